@@ -0,0 +1,296 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	flag "github.com/spf13/pflag"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode-sdk-go/option"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/clipboard"
+	"github.com/aaronmrosenthal/rycode/internal/quick"
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+	"github.com/aaronmrosenthal/rycode/internal/schedule"
+	"github.com/aaronmrosenthal/rycode/internal/splash"
+	"github.com/aaronmrosenthal/rycode/internal/target"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+	"github.com/aaronmrosenthal/rycode/tui"
+)
+
+var Version = "dev"
+
+func main() {
+	version := Version
+	if version != "dev" && !strings.HasPrefix(Version, "v") {
+		version = "v" + Version
+	}
+
+	var model *string = flag.String("model", "", "model to begin with")
+	var prompt *string = flag.String("prompt", "", "prompt to begin with")
+	var agent *string = flag.String("agent", "", "agent to begin with")
+	var sessionID *string = flag.String("session", "", "session ID")
+	var showSplashFlag *bool = flag.Bool("splash", false, "force show splash screen")
+	var noSplashFlag *bool = flag.Bool("no-splash", false, "skip splash screen")
+	var remoteFlag *string = flag.String("remote", "", "work on a remote worktree over SSH, as [user@]host:path")
+	var remoteCommand *string = flag.String("remote-command", remote.DefaultServerCommand, "command that starts the server on the remote machine")
+	var tutorialFlag *bool = flag.Bool("tutorial", false, "start in the tutorial playground, which needs no API key")
+	var noAltScreenFlag *bool = flag.Bool("no-altscreen", false, "run inline, leaving the chat in the terminal's scrollback")
+	flag.Parse()
+
+	// Easter egg: /donut command - infinite cortex animation
+	if len(flag.Args()) > 0 && flag.Args()[0] == "donut" {
+		runDonutMode()
+		return
+	}
+
+	url := os.Getenv("RYCODE_SERVER")
+	if url == "" {
+		url = "http://127.0.0.1:4096"
+	}
+
+	if *remoteFlag == "" {
+		*remoteFlag = os.Getenv("RYCODE_REMOTE")
+	}
+	if *remoteFlag != "" {
+		conn, server, err := connectRemote(*remoteFlag, *remoteCommand)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "rycode:", err)
+			os.Exit(1)
+		}
+		defer conn.Close()
+		defer server.Stop()
+		remote.Attach(conn)
+		url = server.URL
+	}
+
+	stat, err := os.Stdin.Stat()
+	if err != nil {
+		slog.Error("Failed to stat stdin", "error", err)
+		os.Exit(1)
+	}
+
+	// Check if there's data piped to stdin
+	if (stat.Mode() & os.ModeCharDevice) == 0 {
+		stdin, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			slog.Error("Failed to read stdin", "error", err)
+			os.Exit(1)
+		}
+		stdinContent := strings.TrimSpace(string(stdin))
+		if stdinContent != "" {
+			if prompt == nil || *prompt == "" {
+				prompt = &stdinContent
+			} else {
+				combined := *prompt + "\n" + stdinContent
+				prompt = &combined
+			}
+		}
+	}
+
+	httpClient := opencode.NewClient(
+		option.WithBaseURL(url),
+	)
+
+	workspace, err := tui.Connect(context.Background(), httpClient)
+	if err != nil {
+		panic(err)
+	}
+	project, path := workspace.Project, workspace.Path
+	if remote.Active() != nil {
+		localizePaths(path)
+	}
+	if err := useTarget(path.Worktree); err != nil {
+		fmt.Fprintln(os.Stderr, "rycode:", err)
+		os.Exit(1)
+	}
+
+	// Headless scheduler for recurring prompts registered with /schedule
+	if len(flag.Args()) > 0 && flag.Args()[0] == "daemon" {
+		runDaemon(httpClient, path)
+		return
+	}
+
+	// Single prompt box for tmux popups and floating windows
+	if len(flag.Args()) > 0 && flag.Args()[0] == "quick" {
+		question := strings.Join(flag.Args()[1:], " ")
+		if prompt != nil && *prompt != "" {
+			question = strings.TrimSpace(question + "\n" + *prompt)
+		}
+		runQuick(httpClient, project, path, question)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	apiHandler := util.NewAPILogHandler(ctx, httpClient, "tui", slog.LevelDebug)
+	logger := slog.New(apiHandler)
+	slog.SetDefault(logger)
+
+	slog.Debug("TUI launched")
+
+	// The TUI shows its own splash in the provider's colors, so the one
+	// forced with --splash stands in for it
+	if *showSplashFlag {
+		showSplash()
+	}
+
+	program, err := tui.New(ctx, tui.Options{
+		Client:    httpClient,
+		Workspace: &workspace,
+		Version:   version,
+		Model:     *model,
+		Prompt:    *prompt,
+		Agent:     *agent,
+		Session:   *sessionID,
+		Tutorial:  *tutorialFlag,
+		Inline:    *noAltScreenFlag,
+		NoSplash:  *noSplashFlag || *showSplashFlag,
+	})
+	if err != nil {
+		panic(err)
+	}
+	program.Run()
+}
+
+// runDonutMode runs the infinite cortex animation (easter egg)
+func runDonutMode() {
+	model := splash.NewDonutMode()
+	program := tea.NewProgram(model, tea.WithAltScreen())
+	if _, err := program.Run(); err != nil {
+		slog.Error("Donut mode error", "error", err)
+	}
+}
+
+// runDaemon runs scheduled prompts until interrupted
+func runDaemon(client *opencode.Client, path *opencode.Path) {
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	daemon := &schedule.Daemon{
+		Client: client,
+		Store:  schedule.NewStore(path.State),
+	}
+	if err := daemon.Run(ctx); err != nil {
+		slog.Error("Scheduler error", "error", err)
+		os.Exit(1)
+	}
+}
+
+// runQuick runs the quick mode with the model and theme of the TUI
+func runQuick(client *opencode.Client, project *opencode.Project, path *opencode.Path, question string) {
+	util.RootPath = project.Worktree
+	util.CwdPath, _ = os.Getwd()
+	state, err := app.LoadState(filepath.Join(path.State, "tui"))
+	if err != nil {
+		state = app.NewState()
+	}
+	if err := theme.LoadThemesFromDirectories(path.Config, util.RootPath, util.CwdPath); err != nil {
+		slog.Warn("Failed to load themes from directories", "error", err)
+	}
+	if state.Theme != "" {
+		theme.SetTheme(state.Theme)
+	}
+	if err := clipboard.Init(); err != nil {
+		slog.Debug("Failed to initialize clipboard", "error", err)
+	}
+
+	err = quick.Run(context.Background(), quick.Options{
+		Client:    client,
+		Directory: path.Directory,
+		State:     state,
+		Pane:      quick.Pane(),
+		Prompt:    question,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "rycode:", err)
+		os.Exit(1)
+	}
+}
+
+// connectRemote connects to a remote worktree and starts the server there
+func connectRemote(spec, command string) (*remote.Conn, *remote.Server, error) {
+	target, err := remote.ParseTarget(spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	fmt.Fprintf(os.Stderr, "Connecting to %s…\n", target)
+	ctx := context.Background()
+	conn, err := remote.Dial(ctx, target)
+	if err != nil {
+		return nil, nil, err
+	}
+	server, err := conn.StartServer(ctx, command)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	return conn, server, nil
+}
+
+// useTarget runs the project's commands in the container its configuration
+// names, if any
+func useTarget(root string) error {
+	config, err := target.LoadConfig(root)
+	if err != nil {
+		return err
+	}
+	container, err := target.Resolve(context.Background(), root, config)
+	if err != nil {
+		return err
+	}
+	target.Use(container)
+	return nil
+}
+
+// localizePaths keeps the TUI's own configuration and state on this machine
+// when the server runs on a remote one
+func localizePaths(path *opencode.Path) {
+	if dir, err := os.UserConfigDir(); err == nil {
+		path.Config = filepath.Join(dir, "rycode")
+	}
+	state := os.Getenv("XDG_STATE_HOME")
+	if state == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			state = filepath.Join(home, ".local", "state")
+		}
+	}
+	if state != "" {
+		path.State = filepath.Join(state, "rycode")
+	}
+}
+
+// showSplash runs the startup splash before the TUI
+func showSplash() {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Warn("Splash screen crashed, continuing to TUI", "error", r)
+		}
+	}()
+
+	splashModel := splash.New()
+	splashProgram := tea.NewProgram(splashModel, tea.WithAltScreen())
+	if _, err := splashProgram.Run(); err != nil {
+		slog.Warn("Splash screen failed, continuing to TUI", "error", err)
+	}
+
+	// Clear screen after splash for clean transition
+	clearScreen()
+}
+
+// clearScreen clears the terminal screen for clean transition
+func clearScreen() {
+	// ANSI escape code to clear screen and move cursor to top-left
+	os.Stdout.WriteString("\033[2J\033[H")
+}
@@ -20,10 +20,11 @@ type AccessibilitySettings struct {
 	mu sync.RWMutex
 
 	// Visual settings
-	HighContrast      bool
-	ReducedMotion     bool
-	LargeText         bool
-	IncreasedSpacing  bool
+	HighContrast     bool
+	ReducedMotion    bool
+	LargeText        bool
+	IncreasedSpacing bool
+	Zoom             bool // Low-vision zoom: large text, wide spacing and raised contrast
 
 	// Interaction settings
 	KeyboardOnly      bool
@@ -37,12 +38,12 @@ type AccessibilitySettings struct {
 	AnimationSpeed    float64 // 0.5 = half speed, 1.0 = normal, 2.0 = double
 
 	// Color settings
-	ColorBlindMode    string // "", "protanopia", "deuteranopia", "tritanopia"
-	ForceDarkMode     bool
-	ForceLightMode    bool
+	ColorBlindMode string // "", "protanopia", "deuteranopia", "tritanopia"
+	ForceDarkMode  bool
+	ForceLightMode bool
 
 	// Focus indicators
-	EnhancedFocus     bool
+	EnhancedFocus      bool
 	FocusIndicatorSize int // 1 = normal, 2 = large, 3 = extra large
 }
 
@@ -124,6 +125,15 @@ func (s *AccessibilitySettings) EnableScreenReader() {
 	s.ShowKeyboardHints = true
 }
 
+// DisableScreenReader disables screen reader mode and stops speaking
+func (s *AccessibilitySettings) DisableScreenReader() {
+	s.mu.Lock()
+	s.ScreenReaderMode = false
+	s.VerboseLabels = false
+	s.mu.Unlock()
+	StopSpeech()
+}
+
 // IsScreenReaderMode returns whether screen reader mode is enabled
 func (s *AccessibilitySettings) IsScreenReaderMode() bool {
 	s.mu.RLock()
@@ -140,6 +150,14 @@ func (s *AccessibilitySettings) EnableKeyboardOnly() {
 	s.EnhancedFocus = true
 }
 
+// DisableKeyboardOnly disables keyboard-only mode
+func (s *AccessibilitySettings) DisableKeyboardOnly() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.KeyboardOnly = false
+	s.EnhancedFocus = false
+}
+
 // IsKeyboardOnly returns whether keyboard-only mode is enabled
 func (s *AccessibilitySettings) IsKeyboardOnly() bool {
 	s.mu.RLock()
@@ -175,16 +193,16 @@ func (s *AccessibilitySettings) GetFocusIndicatorSize() int {
 
 // HighContrastColors provides color names for high contrast mode
 type HighContrastColors struct {
-	Background     string
-	Foreground     string
-	Primary        string
-	Secondary      string
-	Success        string
-	Warning        string
-	Error          string
-	Info           string
-	Border         string
-	Focus          string
+	Background string
+	Foreground string
+	Primary    string
+	Secondary  string
+	Success    string
+	Warning    string
+	Error      string
+	Info       string
+	Border     string
+	Focus      string
 }
 
 // GetHighContrastColors returns high contrast color palette
@@ -384,9 +402,19 @@ func (q *AnnouncementQueue) Clear() {
 // Global announcement queue
 var globalAnnouncementQueue = NewAnnouncementQueue()
 
-// Announce adds a screen reader announcement
+// Announce adds a screen reader announcement and speaks it. High priority
+// announcements interrupt what is being spoken.
 func Announce(message, priority, typ string) {
+	if !globalSettings.IsScreenReaderMode() {
+		return
+	}
 	globalAnnouncementQueue.Announce(message, priority, typ)
+	switch priority {
+	case "high", "assertive":
+		speaker().Interrupt(message)
+	default:
+		speaker().Speak(message)
+	}
 }
 
 // GetAnnouncements returns pending screen reader announcements
@@ -0,0 +1,77 @@
+package accessibility
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// MouseAction is something done with the mouse or on a touch screen, with
+// the way to do it from the keyboard. Components register theirs, for the
+// keyboard audit to check that none is reachable only with the mouse.
+type MouseAction struct {
+	Component string // Where, as "chat"
+	Name      string // What is done, as "fold tool output"
+	Mouse     string // How with the mouse, as "click the tool call"
+	Command   string // Command doing it from the keyboard, by name
+	Keys      string // Keys doing it outside of commands, as "enter on an empty prompt"
+}
+
+// KeyboardIssue is a mouse action the keyboard can't do, in the form of the
+// issues of the accessibility checker
+type KeyboardIssue struct {
+	Level       string // "error" or "warning"
+	Component   string
+	Description string
+	Fix         string
+}
+
+var (
+	mouseActionsMu sync.RWMutex
+	mouseActions   []MouseAction
+)
+
+// RegisterMouseAction adds an action to those the keyboard audit checks
+func RegisterMouseAction(action MouseAction) {
+	mouseActionsMu.Lock()
+	defer mouseActionsMu.Unlock()
+	mouseActions = append(mouseActions, action)
+}
+
+// MouseActions returns the registered mouse actions, by component and name
+func MouseActions() []MouseAction {
+	mouseActionsMu.RLock()
+	actions := slices.Clone(mouseActions)
+	mouseActionsMu.RUnlock()
+	slices.SortFunc(actions, func(a, b MouseAction) int {
+		return cmp.Or(cmp.Compare(a.Component, b.Component), cmp.Compare(a.Name, b.Name))
+	})
+	return actions
+}
+
+// AuditKeyboard checks that every mouse action can be done from the
+// keyboard. keys returns how a command is run from the keyboard, "" when
+// it isn't: its key binding, or its trigger typed as /trigger.
+func AuditKeyboard(keys func(command string) string) []KeyboardIssue {
+	var issues []KeyboardIssue
+	for _, action := range MouseActions() {
+		switch {
+		case action.Command == "" && action.Keys == "":
+			issues = append(issues, KeyboardIssue{
+				Level:       "error",
+				Component:   action.Component,
+				Description: fmt.Sprintf("%s is reachable only with the mouse (%s)", action.Name, action.Mouse),
+				Fix:         "Add a command or key doing the same",
+			})
+		case action.Command != "" && keys(action.Command) == "" && action.Keys == "":
+			issues = append(issues, KeyboardIssue{
+				Level:       "error",
+				Component:   action.Component,
+				Description: fmt.Sprintf("%s needs the %s command, which has no key binding or trigger", action.Name, action.Command),
+				Fix:         "Bind a key to " + action.Command + " in the keybinds",
+			})
+		}
+	}
+	return issues
+}
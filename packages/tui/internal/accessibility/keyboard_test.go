@@ -0,0 +1,25 @@
+package accessibility
+
+import "testing"
+
+func TestAuditKeyboard(t *testing.T) {
+	saved := mouseActions
+	defer func() { mouseActions = saved }()
+	mouseActions = nil
+
+	RegisterMouseAction(MouseAction{Component: "chat", Name: "Fold tool output", Mouse: "click", Keys: "enter on an empty prompt"})
+	RegisterMouseAction(MouseAction{Component: "chat", Name: "Copy text", Mouse: "drag", Command: "messages_select"})
+	RegisterMouseAction(MouseAction{Component: "chat", Name: "Open menu", Mouse: "long press", Command: "messages_menu"})
+	RegisterMouseAction(MouseAction{Component: "panes", Name: "Resize", Mouse: "drag the border"})
+
+	keys := func(command string) string {
+		if command == "messages_select" {
+			return "<leader>v"
+		}
+		return ""
+	}
+	issues := AuditKeyboard(keys)
+	if len(issues) != 2 || issues[0].Component != "chat" || issues[1].Component != "panes" {
+		t.Fatalf("issues = %+v, want the unbound menu and the mouse-only resize", issues)
+	}
+}
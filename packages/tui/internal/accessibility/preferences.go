@@ -0,0 +1,43 @@
+package accessibility
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// PreferencesFile is the file in the config directory accessibility
+// preferences are kept in, apart from the state as they belong to the user
+// rather than to a project or session
+const PreferencesFile = "accessibility.toml"
+
+// Preferences are the accessibility settings remembered across launches
+type Preferences struct {
+	Zoom bool `toml:"zoom,omitempty"` // Low-vision zoom: wider spacing, a simpler status bar and raised contrast
+}
+
+// LoadPreferences reads the accessibility preferences kept in a config
+// directory, the defaults when none were saved yet
+func LoadPreferences(dir string) (Preferences, error) {
+	var prefs Preferences
+	path := filepath.Join(dir, PreferencesFile)
+	if _, err := toml.DecodeFile(path, &prefs); err != nil && !os.IsNotExist(err) {
+		return Preferences{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return prefs, nil
+}
+
+// SavePreferences writes the accessibility preferences to a config directory
+func SavePreferences(dir string, prefs Preferences) error {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(prefs); err != nil {
+		return fmt.Errorf("failed to encode accessibility preferences: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	return os.WriteFile(filepath.Join(dir, PreferencesFile), buf.Bytes(), 0644)
+}
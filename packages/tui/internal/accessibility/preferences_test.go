@@ -0,0 +1,17 @@
+package accessibility
+
+import "testing"
+
+func TestPreferences(t *testing.T) {
+	dir := t.TempDir()
+	prefs, err := LoadPreferences(dir)
+	if err != nil || prefs.Zoom {
+		t.Fatalf("preferences before any were saved = %+v, %v", prefs, err)
+	}
+	if err := SavePreferences(dir, Preferences{Zoom: true}); err != nil {
+		t.Fatal(err)
+	}
+	if prefs, err = LoadPreferences(dir); err != nil || !prefs.Zoom {
+		t.Errorf("saved zoom was not loaded: %+v, %v", prefs, err)
+	}
+}
@@ -0,0 +1,199 @@
+package accessibility
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	// maxPendingSpeech is the number of announcements waiting to be spoken.
+	// Older ones are dropped, as they describe a state that has moved on.
+	maxPendingSpeech = 3
+	// maxSpokenRunes is the length announcements are cut to
+	maxSpokenRunes = 400
+)
+
+// SpeechBackend is a command that speaks text
+type SpeechBackend struct {
+	Name string   // Executable
+	Args []string // Arguments; the text is written to its standard input
+}
+
+// windowsSpeech speaks standard input with SAPI through System.Speech
+const windowsSpeech = "Add-Type -AssemblyName System.Speech; " +
+	"(New-Object System.Speech.Synthesis.SpeechSynthesizer).Speak([Console]::In.ReadToEnd())"
+
+// speechBackends lists the speech commands of each system, in order of
+// preference
+var speechBackends = map[string][]SpeechBackend{
+	"darwin": {{Name: "say"}},
+	"linux": {
+		{Name: "spd-say", Args: []string{"--wait", "--pipe-mode"}}, // speech-dispatcher, which Orca also speaks through
+		{Name: "espeak-ng", Args: []string{"--stdin"}},
+		{Name: "espeak", Args: []string{"--stdin"}},
+	},
+	"windows": {{Name: "powershell", Args: []string{"-NoProfile", "-NonInteractive", "-Command", windowsSpeech}}},
+}
+
+// DetectSpeechBackend returns the first speech command of goos that
+// lookPath finds, or nil when there is none
+func DetectSpeechBackend(goos string, lookPath func(string) (string, error)) *SpeechBackend {
+	for _, backend := range speechBackends[goos] {
+		if _, err := lookPath(backend.Name); err == nil {
+			return &backend
+		}
+	}
+	return nil
+}
+
+// Speaker speaks announcements one at a time, in the background
+type Speaker struct {
+	mu       sync.Mutex
+	backend  *SpeechBackend
+	pending  []string
+	speaking bool
+	cancel   context.CancelFunc // Cancels what is being spoken
+	run      func(ctx context.Context, backend SpeechBackend, text string) error
+}
+
+// NewSpeaker creates a speaker using backend, which may be nil for a
+// speaker that stays silent
+func NewSpeaker(backend *SpeechBackend) *Speaker {
+	return &Speaker{backend: backend, run: runSpeech}
+}
+
+// Backend returns the name of the speech command, or "" when there is none
+func (s *Speaker) Backend() string {
+	if s.backend == nil {
+		return ""
+	}
+	return s.backend.Name
+}
+
+// Speak queues text to be spoken after what is already queued
+func (s *Speaker) Speak(text string) {
+	s.speak(text, false)
+}
+
+// Interrupt stops what is being spoken, drops what is queued and speaks text
+func (s *Speaker) Interrupt(text string) {
+	s.speak(text, true)
+}
+
+func (s *Speaker) speak(text string, interrupt bool) {
+	text = Speakable(text)
+	if s.backend == nil || text == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if interrupt {
+		s.pending = s.pending[:0]
+		if s.cancel != nil {
+			s.cancel()
+		}
+	}
+	s.pending = append(s.pending, text)
+	if len(s.pending) > maxPendingSpeech {
+		s.pending = s.pending[len(s.pending)-maxPendingSpeech:]
+	}
+	if !s.speaking {
+		s.speaking = true
+		go s.drain()
+	}
+}
+
+// drain speaks the queued announcements until there are none left
+func (s *Speaker) drain() {
+	for {
+		s.mu.Lock()
+		if len(s.pending) == 0 {
+			s.speaking, s.cancel = false, nil
+			s.mu.Unlock()
+			return
+		}
+		text := s.pending[0]
+		s.pending = s.pending[1:]
+		ctx, cancel := context.WithCancel(context.Background())
+		s.cancel = cancel
+		backend := *s.backend
+		s.mu.Unlock()
+
+		s.run(ctx, backend, text)
+		cancel()
+	}
+}
+
+// Stop stops speaking and drops what is queued
+func (s *Speaker) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = s.pending[:0]
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// runSpeech runs backend with text on its standard input
+func runSpeech(ctx context.Context, backend SpeechBackend, text string) error {
+	cmd := exec.CommandContext(ctx, backend.Name, backend.Args...)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout, cmd.Stderr = io.Discard, io.Discard
+	return cmd.Run()
+}
+
+// Speakable makes text fit to be spoken: escape sequences and markdown
+// markers are removed, whitespace is collapsed and long text is cut at a
+// word
+func Speakable(text string) string {
+	text = ansi.Strip(text)
+	text = strings.NewReplacer("`", "", "**", "", "__", "", "#", "").Replace(text)
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= maxSpokenRunes {
+		return text
+	}
+	cut := string([]rune(text)[:maxSpokenRunes])
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return cut + "…"
+}
+
+var (
+	globalSpeaker     *Speaker
+	globalSpeakerOnce sync.Once
+)
+
+// speaker returns the speaker of this system, found on first use
+func speaker() *Speaker {
+	globalSpeakerOnce.Do(func() {
+		globalSpeaker = NewSpeaker(DetectSpeechBackend(runtime.GOOS, exec.LookPath))
+	})
+	return globalSpeaker
+}
+
+// Speak speaks text through the system's speech command, after what is
+// already being spoken. It is silent outside screen reader mode.
+func Speak(text string) {
+	if globalSettings.IsScreenReaderMode() {
+		speaker().Speak(text)
+	}
+}
+
+// SpeechBackendName returns the name of the system's speech command, or ""
+// when none was found
+func SpeechBackendName() string {
+	return speaker().Backend()
+}
+
+// StopSpeech stops speaking and drops the queued announcements
+func StopSpeech() {
+	speaker().Stop()
+}
@@ -0,0 +1,113 @@
+package accessibility
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDetectSpeechBackend(t *testing.T) {
+	only := func(names ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			for _, n := range names {
+				if n == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+	}
+
+	tests := []struct {
+		goos     string
+		lookPath func(string) (string, error)
+		want     string
+	}{
+		{"darwin", only("say"), "say"},
+		{"linux", only("espeak", "spd-say"), "spd-say"},
+		{"linux", only("espeak"), "espeak"},
+		{"windows", only("powershell"), "powershell"},
+		{"linux", only(), ""},
+		{"plan9", only("say"), ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if backend := DetectSpeechBackend(tt.goos, tt.lookPath); backend != nil {
+			got = backend.Name
+		}
+		if got != tt.want {
+			t.Errorf("DetectSpeechBackend(%q) = %q, want %q", tt.goos, got, tt.want)
+		}
+	}
+}
+
+func TestSpeakerSpeaksInOrder(t *testing.T) {
+	var mu sync.Mutex
+	var spoken []string
+	done := make(chan struct{}, 3)
+	s := NewSpeaker(&SpeechBackend{Name: "test"})
+	s.run = func(ctx context.Context, backend SpeechBackend, text string) error {
+		mu.Lock()
+		spoken = append(spoken, text)
+		mu.Unlock()
+		done <- struct{}{}
+		return nil
+	}
+
+	s.Speak("one")
+	s.Speak("two")
+	s.Speak("three")
+	for range 3 {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("announcements weren't spoken")
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(spoken, ",") != "one,two,three" {
+		t.Errorf("spoken = %v, want one, two, three in order", spoken)
+	}
+}
+
+func TestSpeakerInterrupt(t *testing.T) {
+	started := make(chan string, 4)
+	s := NewSpeaker(&SpeechBackend{Name: "test"})
+	s.run = func(ctx context.Context, backend SpeechBackend, text string) error {
+		started <- text
+		if text == "long" {
+			<-ctx.Done() // Speaks until interrupted
+		}
+		return nil
+	}
+
+	s.Speak("long")
+	if got := <-started; got != "long" {
+		t.Fatalf("first spoken = %q, want long", got)
+	}
+	s.Speak("stale")
+	s.Interrupt("urgent")
+	select {
+	case got := <-started:
+		if got != "urgent" {
+			t.Errorf("spoken after interrupting = %q, want urgent", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("interrupting didn't stop what was being spoken")
+	}
+}
+
+func TestSpeakable(t *testing.T) {
+	if got := Speakable("\x1b[1m**Done**\x1b[0m\n\n  with `go test`"); got != "Done with go test" {
+		t.Errorf("Speakable = %q", got)
+	}
+	long := strings.Repeat("word ", 200)
+	got := Speakable(long)
+	if len([]rune(got)) > maxSpokenRunes+1 || !strings.HasSuffix(got, "word…") {
+		t.Errorf("long text cut to %d runes: %q", len([]rune(got)), got[len(got)-10:])
+	}
+}
@@ -0,0 +1,63 @@
+package accessibility
+
+import (
+	"os"
+	"strings"
+)
+
+// DoubleHeightEnv names the environment variable that says whether the
+// terminal draws double-height lines, whatever it's detected as
+const DoubleHeightEnv = "RYCODE_DOUBLE_HEIGHT"
+
+// EnableZoom enables low-vision zoom, which takes large text and increased
+// spacing with it
+func (s *AccessibilitySettings) EnableZoom() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Zoom = true
+	s.LargeText = true
+	s.IncreasedSpacing = true
+}
+
+// DisableZoom disables low-vision zoom
+func (s *AccessibilitySettings) DisableZoom() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Zoom = false
+	s.LargeText = false
+	s.IncreasedSpacing = false
+}
+
+// IsZoom returns whether low-vision zoom is enabled
+func (s *AccessibilitySettings) IsZoom() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Zoom
+}
+
+// DoubleHeightSupported reports whether the terminal draws double-height
+// lines (DECDHL). Most terminals ignore the escape and draw the line twice,
+// so only those known to draw it are trusted.
+func DoubleHeightSupported() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(DoubleHeightEnv))) {
+	case "1", "true", "on":
+		return true
+	case "0", "false", "off":
+		return false
+	}
+	if os.Getenv("TMUX") != "" || strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		return false
+	}
+	for _, env := range []string{"XTERM_VERSION", "KONSOLE_VERSION", "WT_SESSION"} {
+		if os.Getenv(env) != "" {
+			return true
+		}
+	}
+	return strings.Contains(os.Getenv("TERM"), "mlterm")
+}
+
+// DoubleHeight returns a line drawn at double height, as its top and bottom
+// halves. The line must fit half the width of the screen.
+func DoubleHeight(line string) string {
+	return "\x1b#3" + line + "\n\x1b#4" + line
+}
@@ -14,7 +14,7 @@ type Request struct {
 	Body json.RawMessage `json:"body"`
 }
 
-func Start(ctx context.Context, program *tea.Program, client *opencode.Client) {
+func Start(ctx context.Context, send func(tea.Msg), client *opencode.Client) {
 	for {
 		select {
 		case <-ctx.Done():
@@ -25,7 +25,7 @@ func Start(ctx context.Context, program *tea.Program, client *opencode.Client) {
 				log.Printf("Error getting next request: %v", err)
 				continue
 			}
-			program.Send(req)
+			send(req)
 		}
 	}
 }
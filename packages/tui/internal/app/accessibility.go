@@ -0,0 +1,168 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/accessibility"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+)
+
+// ScreenReaderEnv names the environment variable that turns screen reader
+// mode on, or off, at launch whatever was left in the state
+const ScreenReaderEnv = "RYCODE_SCREEN_READER"
+
+// applyScreenReader turns screen reader mode on at launch when it was left
+// on, or when RYCODE_SCREEN_READER asks for it
+func applyScreenReader(state *State) {
+	on := state.ScreenReader
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(ScreenReaderEnv))) {
+	case "1", "true", "on":
+		on = true
+	case "0", "false", "off":
+		on = false
+	}
+	if on {
+		accessibility.GetSettings().EnableScreenReader()
+	}
+}
+
+// ScreenReader reports whether screen reader mode is on, in which replies
+// and state changes are spoken
+func (a *App) ScreenReader() bool {
+	return accessibility.GetSettings().IsScreenReaderMode()
+}
+
+// SetScreenReader turns screen reader mode on or off and remembers it
+func (a *App) SetScreenReader(on bool) tea.Cmd {
+	a.State.ScreenReader = on
+	if !on {
+		accessibility.GetSettings().DisableScreenReader()
+		return tea.Batch(a.SaveState(), toast.NewInfoToast("Screen reader mode off"))
+	}
+	accessibility.GetSettings().EnableScreenReader()
+	backend := accessibility.SpeechBackendName()
+	if backend == "" {
+		return tea.Batch(a.SaveState(), toast.NewWarningToast(
+			"No speech command was found; install speech-dispatcher or espeak-ng to hear announcements",
+			toast.WithTitle("Screen reader mode on"),
+		))
+	}
+	return tea.Batch(a.SaveState(), toast.NewSuccessToast(
+		"Replies and state changes are spoken with "+backend,
+		toast.WithTitle("Screen reader mode on"),
+	))
+}
+
+// applyZoom turns low-vision zoom on at launch when it was left on in the
+// accessibility preferences of the config directory
+func applyZoom(configDir string) {
+	prefs, err := accessibility.LoadPreferences(configDir)
+	if err != nil {
+		slog.Warn("failed to load accessibility preferences", "error", err)
+		return
+	}
+	if prefs.Zoom {
+		accessibility.GetSettings().EnableZoom()
+		theme.SetMinimumContrast(theme.ContrastAAA)
+	}
+}
+
+// Zoom reports whether low-vision zoom is on, which spaces the chat out,
+// simplifies the status bar and raises the contrast of text
+func (a *App) Zoom() bool {
+	return accessibility.GetSettings().IsZoom()
+}
+
+// SetZoom turns low-vision zoom on or off and remembers it in the
+// accessibility preferences
+func (a *App) SetZoom(on bool) tea.Cmd {
+	var save tea.Cmd
+	prefs, err := accessibility.LoadPreferences(a.ConfigDir)
+	if err == nil {
+		prefs.Zoom = on
+		err = accessibility.SavePreferences(a.ConfigDir, prefs)
+	}
+	if err != nil {
+		save = toast.NewErrorToast("Zoom mode won't be remembered: " + err.Error())
+	}
+	if !on {
+		accessibility.GetSettings().DisableZoom()
+		theme.SetMinimumContrast(0)
+		return tea.Batch(save, toast.NewInfoToast("Zoom mode off"))
+	}
+	accessibility.GetSettings().EnableZoom()
+	theme.SetMinimumContrast(theme.ContrastAAA)
+	return tea.Batch(save, toast.NewSuccessToast("Wider spacing, a simpler status bar and raised contrast", toast.WithTitle("Zoom mode on")))
+}
+
+// AnnounceReply speaks a reply of the current session once it is complete,
+// or why it failed
+func (a *App) AnnounceReply(info opencode.AssistantMessage) {
+	if !a.ScreenReader() || info.Time.Completed == 0 || info.Summary || a.announcedReply == info.ID {
+		return
+	}
+	if a.Session == nil || info.SessionID != a.Session.ID {
+		return
+	}
+	a.announcedReply = info.ID
+
+	switch err := info.Error.AsUnion().(type) {
+	case opencode.AssistantMessageErrorMessageOutputLengthError:
+		accessibility.AnnounceError("Reply cut short: output length exceeded")
+		return
+	case opencode.ProviderAuthError:
+		accessibility.AnnounceError("Reply failed: " + err.Data.Message)
+		return
+	case opencode.MessageAbortedError:
+		accessibility.AnnounceWarning("Reply aborted")
+		return
+	case opencode.UnknownError:
+		accessibility.AnnounceError("Reply failed: " + err.Data.Message)
+		return
+	}
+
+	index := slices.IndexFunc(a.Messages, func(m Message) bool { return MessageID(m) == info.ID })
+	text := ""
+	if index > -1 {
+		text = MessageText(a.Messages[index])
+	}
+	if text == "" {
+		accessibility.AnnounceInfo("Reply finished")
+		return
+	}
+	accessibility.Announce("Reply: "+text, "medium", "info")
+}
+
+// AnnouncePermission speaks a permission request, interrupting what is being
+// spoken as the reply waits on it
+func (a *App) AnnouncePermission(permission opencode.Permission) {
+	accessibility.Announce("Permission needed: "+permission.Title, "high", "warning")
+}
+
+// AnnounceSession speaks the session just opened
+func (a *App) AnnounceSession() {
+	if !a.ScreenReader() || a.Session == nil {
+		return
+	}
+	title := a.Session.Title
+	if title == "" {
+		title = "untitled"
+	}
+	accessibility.AnnounceNavigation("", fmt.Sprintf("session %s, %d messages", title, len(a.Messages)+a.OlderMessages()))
+}
+
+// AnnounceToast speaks a toast, with its title
+func (a *App) AnnounceToast(msg toast.ShowToastMsg) {
+	text := msg.Message
+	if msg.Title != nil && *msg.Title != "" {
+		text = *msg.Title + ". " + text
+	}
+	accessibility.AnnounceInfo(text)
+}
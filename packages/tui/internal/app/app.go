@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"log/slog"
@@ -14,12 +15,23 @@ import (
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/aaronmrosenthal/rycode-sdk-go"
 	"github.com/aaronmrosenthal/rycode/internal/auth"
+	"github.com/aaronmrosenthal/rycode/internal/bridge"
 	"github.com/aaronmrosenthal/rycode/internal/clipboard"
+	"github.com/aaronmrosenthal/rycode/internal/clipwatch"
 	"github.com/aaronmrosenthal/rycode/internal/commands"
+	"github.com/aaronmrosenthal/rycode/internal/components/help"
+	"github.com/aaronmrosenthal/rycode/internal/components/textarea"
 	"github.com/aaronmrosenthal/rycode/internal/components/toast"
 	"github.com/aaronmrosenthal/rycode/internal/id"
+	"github.com/aaronmrosenthal/rycode/internal/intelligence"
+	"github.com/aaronmrosenthal/rycode/internal/plugin"
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+	"github.com/aaronmrosenthal/rycode/internal/repoqa"
+	"github.com/aaronmrosenthal/rycode/internal/sessiontemplate"
+	"github.com/aaronmrosenthal/rycode/internal/shells"
 	"github.com/aaronmrosenthal/rycode/internal/styles"
 	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/tutorial"
 	"github.com/aaronmrosenthal/rycode/internal/util"
 )
 
@@ -34,6 +46,7 @@ type App struct {
 	Providers         []opencode.Provider
 	Version           string
 	StatePath         string
+	ConfigDir         string // Global config directory
 	Config            *opencode.Config
 	Client            *opencode.Client
 	State             *State
@@ -49,13 +62,78 @@ type App struct {
 	InitialPrompt     *string
 	InitialAgent      *string
 	InitialSession    *string
+	InitialTutorial   bool                    // Start in the tutorial playground
+	Inline            bool                    // Run in the normal screen rather than the alternate one
+	Plain             bool                    // No colors, and ASCII for borders, marks and emoji
+	SkipSplash        bool                    // Skip the startup splash this run, as when the program showed its own
+	pendingDir        string                  // Directory set with /cd before the session started
+	pendingContext    string                  // Context set picked before the session started
+	shells            *shells.Pool            // Named shells, nil until one is started
+	shellInbox        bool                    // A ShellInboxMsg is on its way
+	shellRequests     map[string]shellRequest // Inbox commands waiting on a permission, by its ID
+	shellAsked        int                     // Permissions asked for inbox commands so far
+	toolRequests      map[string]toolRequest  // Project tool calls waiting on a permission, by its ID
+	toolAsked         int                     // Permissions asked for project tool calls so far
 	compactCancel     context.CancelFunc
 	IsLeaderSequence  bool
 	IsBashMode        bool
+	VimMode           textarea.VimMode // Mode of the prompt's vim keybindings, VimOff unless enabled
 	ScrollSpeed       int
-	AuthBridge        *auth.Bridge // Auth system bridge
-	CurrentCost       float64      // Cached cost from auth system
-	LastCostUpdate    time.Time    // When cost was last fetched
+	AuthBridge        *auth.Bridge              // Auth system bridge
+	Bridge            *bridge.Server            // Editor plugins, nil when the socket couldn't be opened
+	Events            *EventStream              // The server's events, nil when they aren't streamed by the App
+	CurrentCost       float64                   // Cached cost from auth system
+	LastCostUpdate    time.Time                 // When cost was last fetched
+	PreviousResponse  *ResponseSnapshot         // Answer replaced by the last undo/retry
+	ClipboardError    *clipwatch.Match          // Copied error offered to the session, nil when there is none
+	Template          *sessiontemplate.Template // Seeds the next new session, nil for a plain one
+	AppliedRule       *AppliedRule              // Path rule that picked the last prompt's agent or model
+	Tutorial          *tutorial.Tutorial        // Playground shown in place of the session, nil outside the tutorial
+	EmbeddedPlugins   []plugin.Embedded         // Plugins of the program embedding the TUI, started with the installed ones
+	homeRoot          string                    // Worktree the TUI started in
+	homeClient        *opencode.Client          // Client for homeRoot, which Client replaces in another worktree
+	experiments       *intelligence.ExperimentStore
+	appliedEdits      map[string]*appliedEdit  // AI-edited files awaiting a quality measurement
+	editJournal       []JournalEdit            // Edits tool calls made, for undoing them in the worktree
+	partRenders       map[string]partRendering // Plugins' drawings of tool calls, by part ID
+	repoIndex         *repoqa.Cache
+	usageInsights     *intelligence.UsageInsights
+	costLedger        *intelligence.CostLedger
+	budget            *intelligence.PredictiveBudget
+	recordedUsage     map[string]bool   // Assistant messages already counted in usage
+	compactRequested  map[string]bool   // Sessions compacted with /compact, whose summary isn't background spend
+	backgroundWarned  string            // Day the background cap was last reported reached, as YYYY-MM-DD
+	autoTitles        map[string]string // Title of each session to auto-title after its first exchange, by session ID
+	titleSessions     sync.Map          // Throwaway sessions generating titles, by ID
+	window            messageWindow     // Messages of the current session left out of memory
+	announcedReply    string            // Last reply spoken in screen reader mode
+	notifier          notifier          // Focus of the terminal and the events notified
+	voice             voiceInput        // Push-to-talk recording and transcription
+	saver             stateSaver        // Unwritten state changes
+	stream            streamWatchdog    // Notices the event stream stalling mid-response
+	compaction        compaction        // Compacting of the current session, as the server reported it
+	busyOverride      string            // Reply taken for finished with /unbusy
+	sessionSearch     *SessionSearchIndex
+	watches           []*Watch // Watches running while the TUI is open
+	clipboardCancel   context.CancelFunc
+	clipboardChanges  <-chan []byte // Clipboard copies, nil unless the clipboard is watched
+	clipboardErrorSeq int
+	editReview        *EditReview // Hunks of the current permission's edit, once one is selected or rejected
+	compactSuggested  string      // Session compacting was suggested for while its context is full
+	autoCompact       autoCompactWatch
+	tutorialReturn    *tutorialReturn
+	plugins           *plugin.Host
+	hints             *help.ContextHelpProvider
+	tips              *help.ProgressiveTips
+	budgetOverrun     bool // The budget forecast went over since the TUI started
+	history           *PromptHistory
+	pendingBranch     *pendingBranch         // Branch started from a message, until its first prompt is sent
+	savedArtifacts    map[string]bool        // Messages whose artifacts were saved
+	editBases         map[string]*editBase   // Files of the edits waiting for review, by permission ID
+	verifiedRefactors map[string]bool        // Answers whose refactor was verified
+	refactorFollowUps map[string]int         // Unfinished refactors sent back to each session in a row, by session ID
+	sessionRuns       map[string]*sessionRun // Runs followed from events, by session ID
+	runs              map[string]*runTracker // Runs followed against their budget, by session ID
 }
 
 func (a *App) Agent() *opencode.Agent {
@@ -86,7 +164,16 @@ type AgentSelectedMsg struct {
 type SessionClearedMsg struct{}
 type CompactSessionMsg struct{}
 
-// CostUpdatedMsg is sent when cost summary is updated
+// CompactionFailedMsg is sent when the server failed a compaction the TUI
+// asked for
+type CompactionFailedMsg struct {
+	SessionID string
+	Reason    string // Why the auto-compact policy asked for it, "" for /compact
+	Err       error
+}
+
+// CostUpdatedMsg is sent with today's spend, as the auth bridge sums it or
+// as a completed message adds to it
 type CostUpdatedMsg struct {
 	Cost float64
 }
@@ -122,6 +209,10 @@ func New(
 ) (*App, error) {
 	util.RootPath = project.Worktree
 	util.CwdPath, _ = os.Getwd()
+	if remote.Active() != nil {
+		// The local working directory has nothing to do with the remote one
+		util.CwdPath = path.Directory
+	}
 
 	configInfo, err := httpClient.Config.Get(ctx, opencode.ConfigGetParams{})
 	if err != nil {
@@ -143,6 +234,12 @@ func New(
 		appState.AgentModel = make(map[string]AgentModel)
 	}
 
+	applyScreenReader(appState)
+	applyZoom(path.Config)
+	applyColorDepth(appState)
+	applyKeyboardOnly(appState)
+	util.ASCII = asciiOnly(PlainEnv())
+
 	if configInfo.Theme != "" {
 		appState.Theme = configInfo.Theme
 	}
@@ -208,6 +305,7 @@ func New(
 		Agents:         agents,
 		Version:        version,
 		StatePath:      appStatePath,
+		ConfigDir:      path.Config,
 		Config:         configInfo,
 		State:          appState,
 		Client:         httpClient,
@@ -219,8 +317,12 @@ func New(
 		InitialPrompt:  initialPrompt,
 		InitialAgent:   initialAgent,
 		InitialSession: initialSession,
+		Inline:         appState.Inline,
+		Plain:          PlainEnv(),
 		ScrollSpeed:    int(configInfo.Tui.ScrollSpeed),
 		AuthBridge:     auth.NewBridge(project.Worktree),
+		homeRoot:       project.Worktree,
+		homeClient:     httpClient,
 		CurrentCost:    0.0,
 		LastCostUpdate: time.Now(),
 	}
@@ -255,6 +357,7 @@ func (a *App) Key(commandName commands.CommandName) string {
 }
 
 func SetClipboard(text string) tea.Cmd {
+	setOwnCopy(text)
 	var cmds []tea.Cmd
 	cmds = append(cmds, func() tea.Msg {
 		clipboard.Write(clipboard.FmtText, []byte(text))
@@ -385,25 +488,14 @@ func (a *App) CycleRecentModelReverse() (*App, tea.Cmd) {
 
 // CycleAuthenticatedProviders cycles through all authenticated providers
 func (a *App) CycleAuthenticatedProviders(forward bool) (*App, tea.Cmd) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := a.TimeoutContext(TimeoutProvider)
 	defer cancel()
 
-	// Get authenticated CLI providers
-	cliProviders, err := a.AuthBridge.GetCLIProviders(ctx)
+	authenticatedProviders, err := a.authenticatedProviderIDs(ctx)
 	if err != nil {
 		return a, toast.NewErrorToast("Failed to get CLI providers")
 	}
 
-	// Filter to only authenticated providers
-	authenticatedProviders := []string{}
-	for _, cliProv := range cliProviders {
-		authStatus, err := a.AuthBridge.CheckAuthStatus(ctx, cliProv.Provider)
-		if err != nil || !authStatus.IsAuthenticated {
-			continue
-		}
-		authenticatedProviders = append(authenticatedProviders, cliProv.Provider)
-	}
-
 	if len(authenticatedProviders) == 0 {
 		return a, toast.NewInfoToast("No authenticated providers. Press 'd' in /model to auto-detect.")
 	}
@@ -434,45 +526,11 @@ func (a *App) CycleAuthenticatedProviders(forward bool) (*App, tea.Cmd) {
 	// Get next provider ID
 	nextProviderID := authenticatedProviders[nextIndex]
 
-	// Find the actual provider in a.Providers
-	var nextProvider *opencode.Provider
-	var nextModel *opencode.Model
-
-	for i := range a.Providers {
-		if a.Providers[i].ID == nextProviderID {
-			nextProvider = &a.Providers[i]
-			break
-		}
-	}
-
+	nextProvider, nextModel := a.preferredModel(nextProviderID)
 	if nextProvider == nil {
 		return a, toast.NewErrorToast(fmt.Sprintf("Provider %s not found in providers list", nextProviderID))
 	}
 
-	// Try to find the most recently used model for this provider
-	for _, recentModel := range a.State.RecentlyUsedModels {
-		if recentModel.ProviderID == nextProvider.ID {
-			// Find this model in the provider's models
-			for _, model := range nextProvider.Models {
-				if model.ID == recentModel.ModelID {
-					nextModel = &model
-					break
-				}
-			}
-			if nextModel != nil {
-				break
-			}
-		}
-	}
-
-	// If no recent model, use the first available model
-	if nextModel == nil && len(nextProvider.Models) > 0 {
-		for _, model := range nextProvider.Models {
-			nextModel = &model
-			break
-		}
-	}
-
 	if nextModel == nil {
 		return a, toast.NewErrorToast(fmt.Sprintf("No models found for %s", nextProvider.Name))
 	}
@@ -480,6 +538,10 @@ func (a *App) CycleAuthenticatedProviders(forward bool) (*App, tea.Cmd) {
 	// Update app state
 	a.Provider = nextProvider
 	a.Model = nextModel
+	message := fmt.Sprintf("→ %s: %s", nextProvider.Name, nextModel.Name)
+	if agent := a.SwitchProviderAgent(nextProvider.ID); agent != "" {
+		message += " · " + agent + " agent"
+	}
 	a.State.AgentModel[a.Agent().Name] = AgentModel{
 		ProviderID: nextProvider.ID,
 		ModelID:    nextModel.ID,
@@ -488,12 +550,64 @@ func (a *App) CycleAuthenticatedProviders(forward bool) (*App, tea.Cmd) {
 
 	return a, tea.Sequence(
 		a.SaveState(),
-		toast.NewSuccessToast(
-			fmt.Sprintf("→ %s: %s", nextProvider.Name, nextModel.Name),
-		),
+		toast.NewSuccessToast(message),
 	)
 }
 
+// authenticatedProviderIDs returns the CLI providers that are signed in, in
+// the order they are cycled through
+func (a *App) authenticatedProviderIDs(ctx context.Context) ([]string, error) {
+	cliProviders, err := a.AuthBridge.GetCLIProviders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter to only authenticated providers
+	authenticatedProviders := []string{}
+	for _, cliProv := range cliProviders {
+		authStatus, err := a.AuthBridge.CheckAuthStatus(ctx, cliProv.Provider)
+		if err != nil || !authStatus.IsAuthenticated {
+			continue
+		}
+		authenticatedProviders = append(authenticatedProviders, cliProv.Provider)
+	}
+	return authenticatedProviders, nil
+}
+
+// preferredModel returns a provider with its most recently used model, or
+// its first model when none was used. The provider is nil when it isn't
+// available and the model is nil when it has no models.
+func (a *App) preferredModel(providerID string) (*opencode.Provider, *opencode.Model) {
+	var provider *opencode.Provider
+	for i := range a.Providers {
+		if a.Providers[i].ID == providerID {
+			provider = &a.Providers[i]
+			break
+		}
+	}
+	if provider == nil {
+		return nil, nil
+	}
+
+	// Try to find the most recently used model for this provider
+	for _, recentModel := range a.State.RecentlyUsedModels {
+		if recentModel.ProviderID != provider.ID {
+			continue
+		}
+		for _, model := range provider.Models {
+			if model.ID == recentModel.ModelID {
+				return provider, &model
+			}
+		}
+	}
+
+	// If no recent model, use the first available model
+	for _, model := range provider.Models {
+		return provider, &model
+	}
+	return provider, nil
+}
+
 func (a *App) CycleAuthenticatedProvider() (*App, tea.Cmd) {
 	return a.CycleAuthenticatedProviders(true)
 }
@@ -603,7 +717,13 @@ func (a *App) InitializeProvider() tea.Cmd {
 	}
 	if len(providers) == 0 {
 		slog.Error("No providers configured")
-		return nil
+		if a.InitialTutorial {
+			return nil
+		}
+		return toast.NewInfoToast(
+			"No provider is set up yet. Type /tutorial to try RyCode without an API key.",
+			toast.WithTitle("Welcome"),
+		)
 	}
 
 	// Get the HTTP-only response for default model selection
@@ -802,24 +922,11 @@ func getDefaultModel(
 }
 
 func (a *App) IsBusy() bool {
-	if len(a.Messages) == 0 {
-		return false
-	}
 	if a.IsCompacting() {
 		return true
 	}
-	lastMessage := a.Messages[len(a.Messages)-1]
-	if casted, ok := lastMessage.Info.(opencode.AssistantMessage); ok {
-		return casted.Time.Completed == 0
-	}
-	return false
-}
-
-func (a *App) IsCompacting() bool {
-	if time.Since(time.UnixMilli(int64(a.Session.Time.Compacting))) < time.Second*30 {
-		return true
-	}
-	return false
+	message, ok := a.pendingReply()
+	return ok && message.ID != a.busyOverride
 }
 
 func (a *App) HasAnimatingWork() bool {
@@ -841,20 +948,10 @@ func (a *App) HasAnimatingWork() bool {
 	return false
 }
 
-func (a *App) SaveState() tea.Cmd {
-	return func() tea.Msg {
-		err := SaveState(a.StatePath, a.State)
-		if err != nil {
-			slog.Error("Failed to save state", "error", err)
-		}
-		return nil
-	}
-}
-
 // UpdateCost fetches the latest cost from the auth bridge
 func (a *App) UpdateCost() tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		ctx, cancel := a.TimeoutContext(TimeoutStatus)
 		defer cancel()
 
 		summary, err := a.AuthBridge.GetCostSummary(ctx)
@@ -895,33 +992,40 @@ func (a *App) InitializeProject(ctx context.Context) tea.Cmd {
 }
 
 func (a *App) CompactSession(ctx context.Context) tea.Cmd {
+	return a.compactSession(ctx, "")
+}
+
+// compactSession compacts the current session, as /compact asked when no
+// reason is given, or else as the auto-compact policy decided for it
+func (a *App) compactSession(ctx context.Context, reason string) tea.Cmd {
 	if a.compactCancel != nil {
 		a.compactCancel()
 	}
 
 	compactCtx, cancel := context.WithCancel(ctx)
 	a.compactCancel = cancel
+	if reason == "" {
+		if a.compactRequested == nil {
+			a.compactRequested = make(map[string]bool)
+		}
+		a.compactRequested[a.Session.ID] = true
+	}
+	a.CompactionStarted()
+	a.compaction.reason = reason
 
-	go func() {
-		defer func() {
-			a.compactCancel = nil
-		}()
-
-		_, err := a.Client.Session.Summarize(
-			compactCtx,
-			a.Session.ID,
-			opencode.SessionSummarizeParams{
-				ProviderID: opencode.F(a.Provider.ID),
-				ModelID:    opencode.F(a.Model.ID),
-			},
-		)
-		if err != nil {
-			if compactCtx.Err() != context.Canceled {
-				slog.Error("Failed to compact session", "error", err)
-			}
+	sessionID := a.Session.ID
+	params := opencode.SessionSummarizeParams{
+		ProviderID: opencode.F(a.Provider.ID),
+		ModelID:    opencode.F(a.Model.ID),
+	}
+	return func() tea.Msg {
+		_, err := a.Client.Session.Summarize(compactCtx, sessionID, params)
+		if err == nil || compactCtx.Err() == context.Canceled {
+			return nil
 		}
-	}()
-	return nil
+		slog.Error("Failed to compact session", "error", err)
+		return CompactionFailedMsg{SessionID: sessionID, Reason: reason, Err: err}
+	}
 }
 
 func (a *App) MarkProjectInitialized(ctx context.Context) error {
@@ -945,38 +1049,54 @@ func (a *App) CreateSession(ctx context.Context) (*opencode.Session, error) {
 }
 
 func (a *App) SendPrompt(ctx context.Context, prompt Prompt) (*App, tea.Cmd) {
+	if a.Tutorial != nil {
+		return a.sendTutorialPrompt(prompt)
+	}
 	var cmds []tea.Cmd
+	created := false
 	if a.Session.ID == "" {
-		session, err := a.CreateSession(ctx)
+		session, err := a.createPromptSession(ctx, prompt)
 		if err != nil {
 			return a, toast.NewErrorToast(err.Error())
 		}
 		a.Session = session
-		cmds = append(cmds, util.CmdHandler(SessionCreatedMsg{Session: session}))
+		a.adoptPendingDir()
+		a.adoptPendingContext()
+		created = true
+		cmds = append(cmds,
+			util.CmdHandler(SessionCreatedMsg{Session: session}),
+			a.FindDuplicateSession(session.ID, prompt.Text),
+		)
+		if a.Template != nil {
+			prompt = a.seedSession(session.ID, prompt)
+		}
 	}
 
 	messageID := id.Ascending(id.Message)
 	message := prompt.ToMessage(messageID, a.Session.ID)
+	if created {
+		message = a.adoptPendingBranch(message)
+	}
+	providerID, modelID := a.promptModel(messageID)
+	agent := a.Agent().Name
+
+	// Path rules give way to a running experiment's choice of model
+	a.AppliedRule = a.pathRule(prompt)
+	if rule := a.AppliedRule; rule != nil {
+		if rule.Agent != "" {
+			agent = rule.Agent
+		}
+		if rule.Model != nil && a.Experiments().Active == nil {
+			providerID, modelID = rule.ProviderID, rule.Model.ID
+		}
+		slog.Debug("Applied path rule", "rule", rule.Rule.Label(), "share", rule.Share, "agent", agent, "model", modelID)
+	}
 
 	a.Messages = append(a.Messages, message)
 
-	cmds = append(cmds, func() tea.Msg {
-		_, err := a.Client.Session.Prompt(ctx, a.Session.ID, opencode.SessionPromptParams{
-			Model: opencode.F(opencode.SessionPromptParamsModel{
-				ProviderID: opencode.F(a.Provider.ID),
-				ModelID:    opencode.F(a.Model.ID),
-			}),
-			Agent:     opencode.F(a.Agent().Name),
-			MessageID: opencode.F(messageID),
-			Parts:     opencode.F(message.ToSessionChatParams()),
-		})
-		if err != nil {
-			errormsg := fmt.Sprintf("failed to send message: %v", err)
-			slog.Error(errormsg)
-			return toast.NewErrorToast(errormsg)()
-		}
-		return nil
-	})
+	cmds = append(cmds, a.sendPrompt(ctx, a.Session.ID, messageID, message.ToSessionChatParams(), providerID, modelID, agent, nil))
+	cmds = append(cmds, a.RecordInteraction(help.InteractionRequest))
+	cmds = append(cmds, a.ExpectReply())
 
 	// The actual response will come through SSE
 	// For now, just return success
@@ -991,8 +1111,11 @@ func (a *App) SendCommand(ctx context.Context, command string, args string) (*Ap
 			return a, toast.NewErrorToast(err.Error())
 		}
 		a.Session = session
+		a.adoptPendingDir()
+		a.adoptPendingContext()
 		cmds = append(cmds, util.CmdHandler(SessionCreatedMsg{Session: session}))
 	}
+	a.commandStartsRun(a.Session.ID, command)
 
 	cmds = append(cmds, func() tea.Msg {
 		params := opencode.SessionCommandParams{
@@ -1003,6 +1126,9 @@ func (a *App) SendCommand(ctx context.Context, command string, args string) (*Ap
 		if a.Provider != nil && a.Model != nil {
 			params.Model = opencode.F(a.Provider.ID + "/" + a.Model.ID)
 		}
+		if dir := a.toolDirectory(a.Session.ID); dir != "" {
+			params.Directory = opencode.F(dir)
+		}
 		_, err := a.Client.Session.Command(
 			context.Background(),
 			a.Session.ID,
@@ -1014,12 +1140,15 @@ func (a *App) SendCommand(ctx context.Context, command string, args string) (*Ap
 		}
 		return nil
 	})
+	cmds = append(cmds, a.ExpectReply())
 
 	// The actual response will come through SSE
 	// For now, just return success
 	return a, tea.Batch(cmds...)
 }
 
+// SendShell runs a shell command in the session, in the project's execution
+// target
 func (a *App) SendShell(ctx context.Context, command string) (*App, tea.Cmd) {
 	var cmds []tea.Cmd
 	if a.Session.ID == "" {
@@ -1028,17 +1157,23 @@ func (a *App) SendShell(ctx context.Context, command string) (*App, tea.Cmd) {
 			return a, toast.NewErrorToast(err.Error())
 		}
 		a.Session = session
+		a.adoptPendingDir()
+		a.adoptPendingContext()
 		cmds = append(cmds, util.CmdHandler(SessionCreatedMsg{Session: session}))
 	}
 
 	cmds = append(cmds, func() tea.Msg {
+		params := opencode.SessionShellParams{
+			Agent:   opencode.F(a.Agent().Name),
+			Command: opencode.F(a.shellLine(command)),
+		}
+		if dir := a.toolDirectory(a.Session.ID); dir != "" {
+			params.Directory = opencode.F(dir)
+		}
 		_, err := a.Client.Session.Shell(
 			context.Background(),
 			a.Session.ID,
-			opencode.SessionShellParams{
-				Agent:   opencode.F(a.Agent().Name),
-				Command: opencode.F(command),
-			},
+			params,
 		)
 		if err != nil {
 			slog.Error("Failed to submit shell command", "error", err)
@@ -1046,6 +1181,7 @@ func (a *App) SendShell(ctx context.Context, command string) (*App, tea.Cmd) {
 		}
 		return nil
 	})
+	cmds = append(cmds, a.ExpectReply())
 
 	// The actual response will come through SSE
 	// For now, just return success
@@ -1101,7 +1237,7 @@ func (a *App) UpdateSession(ctx context.Context, sessionID string, title string)
 
 // isFirstRun checks if this is the first run (no authenticated providers)
 func (a *App) isFirstRun() bool {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := a.TimeoutContext(TimeoutStatus)
 	defer cancel()
 
 	status, err := a.AuthBridge.GetAuthStatus(ctx)
@@ -1117,7 +1253,7 @@ func (a *App) isFirstRun() bool {
 // autoDetectAllCredentials attempts to auto-detect credentials on first run
 func (a *App) autoDetectAllCredentials() tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := a.TimeoutContext(TimeoutProvider)
 		defer cancel()
 
 		slog.Debug("Auto-detecting credentials on first run")
@@ -1142,7 +1278,7 @@ func (a *App) autoDetectAllCredentials() tea.Cmd {
 // autoDetectAllCredentialsQuiet runs auto-detect silently on every startup
 func (a *App) autoDetectAllCredentialsQuiet() tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := a.TimeoutContext(TimeoutProvider)
 		defer cancel()
 
 		slog.Debug("Auto-detecting credentials")
@@ -1210,7 +1346,7 @@ func (a *App) AnalyzePromptAndRecommendModel(prompt string) tea.Cmd {
 			return nil
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		ctx, cancel := a.TimeoutContext(TimeoutStatus)
 		defer cancel()
 
 		// Get AI recommendations for this task
@@ -1251,10 +1387,19 @@ func (a *App) AnalyzePromptAndRecommendModel(prompt string) tea.Cmd {
 				return nil
 			}
 
+			// A model whose edits the user keeps reworking isn't suggested,
+			// and the share kept is told for one whose edits were measured
+			text := fmt.Sprintf("%s might be better for %s tasks", recommendedModel.Name, taskType)
+			if rate, rated := a.RecommendationEngine().Satisfaction(bestRec.Provider, bestRec.Model); rated >= minRatedEdits {
+				if rate < 0.5 {
+					slog.Debug("Not recommending a model whose edits needed rework", "model", bestModelID, "satisfied", rate)
+					return nil
+				}
+				text += fmt.Sprintf("; you kept %.0f%% of its recent edits", rate*100)
+			}
+
 			// Create toast with action to switch
-			return toast.NewInfoToast(
-				fmt.Sprintf("%s might be better for %s tasks", recommendedModel.Name, taskType),
-			)()
+			return toast.NewInfoToast(text)()
 		}
 
 		return nil
@@ -1405,13 +1550,15 @@ func formatModelName(modelID string) string {
 // formatProviderName formats a provider ID into a human-readable name
 func formatProviderName(providerID string) string {
 	names := map[string]string{
-		"claude":    "Anthropic",
-		"qwen":      "Alibaba",
-		"codex":     "OpenAI",
-		"gemini":    "Google",
-		"anthropic": "Anthropic",
-		"openai":    "OpenAI",
-		"google":    "Google",
+		"claude":         "Anthropic",
+		"qwen":           "Alibaba",
+		"codex":          "OpenAI",
+		"gemini":         "Google",
+		"anthropic":      "Anthropic",
+		"openai":         "OpenAI",
+		"google":         "Google",
+		"azure":          "Azure OpenAI",
+		"amazon-bedrock": "AWS Bedrock",
 	}
 	if name, ok := names[providerID]; ok {
 		return name
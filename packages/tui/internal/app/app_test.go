@@ -1,6 +1,11 @@
 package app
 
 import (
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/aaronmrosenthal/rycode-sdk-go"
@@ -226,3 +231,50 @@ func TestFindProviderByID(t *testing.T) {
 		})
 	}
 }
+
+func TestSaveKeybinds(t *testing.T) {
+	dir := t.TempDir()
+	existing := `{"theme": "tokyonight", "keybinds": {"leader": "ctrl+x", "session_new": "<leader>n"}}`
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(existing), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := saveKeybinds(dir, map[string]string{"session_new": "ctrl+n", "app_exit": "none"})
+	if err != nil || path != filepath.Join(dir, "config.json") {
+		t.Fatalf("saveKeybinds = %q, %v", path, err)
+	}
+	data, _ := os.ReadFile(path)
+	var config struct {
+		Theme    string            `json:"theme"`
+		Keybinds map[string]string `json:"keybinds"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"leader": "ctrl+x", "session_new": "ctrl+n", "app_exit": "none"}
+	if config.Theme != "tokyonight" || !maps.Equal(config.Keybinds, want) {
+		t.Errorf("config = %s", data)
+	}
+
+	jsonc := filepath.Join(t.TempDir(), "opencode.jsonc")
+	existing = "{\n  // theme\n  \"theme\": \"tokyonight\",\n  /* keys */\n  \"keybinds\": {\"leader\": \"ctrl+x\",},\n}\n"
+	if err := os.WriteFile(jsonc, []byte(existing), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path, err = saveKeybinds(filepath.Dir(jsonc), map[string]string{"app_exit": "none"})
+	if err != nil || path != jsonc {
+		t.Fatalf("saveKeybinds = %q, %v", path, err)
+	}
+	data, _ = os.ReadFile(path)
+	if !strings.Contains(string(data), "// theme") || !strings.Contains(string(data), "/* keys */") {
+		t.Errorf("comments were dropped: %s", data)
+	}
+	config.Keybinds = nil
+	if err := json.Unmarshal(stripJSONC(data), &config); err != nil {
+		t.Fatal(err)
+	}
+	want = map[string]string{"leader": "ctrl+x", "app_exit": "none"}
+	if config.Theme != "tokyonight" || !maps.Equal(config.Keybinds, want) {
+		t.Errorf("config = %s", data)
+	}
+}
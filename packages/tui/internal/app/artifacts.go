@@ -0,0 +1,202 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// ArtifactsDir is where the artifacts of each session are saved, in a
+// directory named after it, relative to the project root
+const ArtifactsDir = ".rycode/artifacts"
+
+// artifactMinLines is the fewest lines a code block has to be worth saving;
+// shorter ones are commands to run rather than files
+const artifactMinLines = 3
+
+// artifactExtensions maps the languages of the code blocks saved as
+// artifacts, scripts, configs and queries, to the extension of their files
+var artifactExtensions = map[string]string{
+	"sh": "sh", "bash": "sh", "shell": "sh", "zsh": "sh", "fish": "fish",
+	"powershell": "ps1", "ps1": "ps1", "bat": "bat", "cmd": "bat",
+	"python": "py", "py": "py", "ruby": "rb", "rb": "rb", "perl": "pl",
+	"javascript": "js", "js": "js", "mjs": "mjs", "typescript": "ts", "ts": "ts",
+	"sql": "sql", "psql": "sql", "mysql": "sql", "postgresql": "sql", "sqlite": "sql",
+	"graphql": "graphql", "gql": "graphql",
+	"yaml": "yaml", "yml": "yaml", "json": "json", "jsonc": "json", "toml": "toml",
+	"ini": "ini", "conf": "conf", "env": "env", "dotenv": "env", "xml": "xml",
+	"dockerfile": "dockerfile", "docker": "dockerfile", "makefile": "mk", "make": "mk",
+	"hcl": "tf", "terraform": "tf", "tf": "tf", "nginx": "conf",
+}
+
+// Artifact is a code block of an answer saved to the session's artifacts
+type Artifact struct {
+	Name     string
+	Path     string // Relative to the project root
+	Size     int64
+	Modified time.Time
+}
+
+// ArtifactsSavedMsg is sent when the artifacts of a message were saved
+type ArtifactsSavedMsg struct {
+	Paths []string
+	Err   error
+}
+
+// SessionArtifactsDir returns the directory the artifacts of a session are
+// saved to, relative to the project root
+func SessionArtifactsDir(sessionID string) string {
+	return filepath.Join(ArtifactsDir, sessionID)
+}
+
+// MessageArtifacts returns the code blocks of a message worth keeping as
+// files: scripts, configs and queries of a few lines that none of its tool
+// calls wrote to the worktree. Each is named after the message's time and
+// its place among them.
+func MessageArtifacts(message Message) map[string]string {
+	assistant, ok := message.Info.(opencode.AssistantMessage)
+	if !ok {
+		return nil
+	}
+	var applied []string
+	for _, part := range message.Parts {
+		if tool, ok := part.(opencode.ToolPart); ok {
+			input, _ := tool.State.Input.(map[string]any)
+			for _, key := range []string{"content", "newString"} {
+				if text, ok := input[key].(string); ok {
+					applied = append(applied, text)
+				}
+			}
+		}
+	}
+
+	created := time.UnixMilli(int64(assistant.Time.Created)).Format("20060102-150405")
+	artifacts := make(map[string]string)
+	for _, block := range FencedBlocks(MessageText(message)) {
+		ext, ok := artifactExtensions[block.Lang]
+		code := strings.TrimSpace(block.Code)
+		if !ok || strings.Count(code, "\n")+1 < artifactMinLines {
+			continue
+		}
+		if slices.ContainsFunc(applied, func(text string) bool { return strings.Contains(text, code) }) {
+			continue
+		}
+		name := fmt.Sprintf("%s-%d.%s", created, len(artifacts)+1, ext)
+		artifacts[name] = code + "\n"
+	}
+	return artifacts
+}
+
+// SaveArtifacts saves the artifacts of a completed answer of the session,
+// when they are saved automatically
+func (a *App) SaveArtifacts(message opencode.AssistantMessage) tea.Cmd {
+	if !a.State.SaveArtifacts || message.Time.Completed == 0 || message.SessionID != a.Session.ID || a.savedArtifacts[message.ID] {
+		return nil
+	}
+	index := slices.IndexFunc(a.Messages, func(m Message) bool { return MessageID(m) == message.ID })
+	if index < 0 {
+		return nil
+	}
+	if a.savedArtifacts == nil {
+		a.savedArtifacts = make(map[string]bool)
+	}
+	a.savedArtifacts[message.ID] = true
+	artifacts := MessageArtifacts(a.Messages[index])
+	if len(artifacts) == 0 {
+		return nil
+	}
+	return writeArtifacts(message.SessionID, artifacts)
+}
+
+// SaveSessionArtifacts saves the artifacts of every answer of the session
+func (a *App) SaveSessionArtifacts() tea.Cmd {
+	artifacts := make(map[string]string)
+	for _, message := range a.Messages {
+		for name, code := range MessageArtifacts(message) {
+			artifacts[name] = code
+		}
+		if a.savedArtifacts == nil {
+			a.savedArtifacts = make(map[string]bool)
+		}
+		a.savedArtifacts[MessageID(message)] = true
+	}
+	if len(artifacts) == 0 {
+		return func() tea.Msg {
+			return ArtifactsSavedMsg{Err: errors.New("the session has no scripts, configs or queries to save")}
+		}
+	}
+	return writeArtifacts(a.Session.ID, artifacts)
+}
+
+// writeArtifacts writes the artifacts of a session that aren't saved yet
+func writeArtifacts(sessionID string, artifacts map[string]string) tea.Cmd {
+	return func() tea.Msg {
+		dir := filepath.Join(util.RootPath, SessionArtifactsDir(sessionID))
+		if err := remote.MkdirAll(dir, 0755); err != nil {
+			return ArtifactsSavedMsg{Err: fmt.Errorf("failed to create artifacts directory: %w", err)}
+		}
+		var paths []string
+		for name, code := range artifacts {
+			path := filepath.Join(dir, name)
+			// Saved artifacts are never overwritten, as they may have been edited
+			if _, err := remote.Stat(path); err == nil {
+				continue
+			}
+			if err := remote.WriteFile(path, []byte(code), 0644); err != nil {
+				return ArtifactsSavedMsg{Paths: paths, Err: fmt.Errorf("failed to write %s: %w", name, err)}
+			}
+			paths = append(paths, filepath.Join(SessionArtifactsDir(sessionID), name))
+		}
+		slices.Sort(paths)
+		return ArtifactsSavedMsg{Paths: paths}
+	}
+}
+
+// Artifacts lists the saved artifacts of a session, oldest first
+func (a *App) Artifacts(sessionID string) ([]Artifact, error) {
+	rel := SessionArtifactsDir(sessionID)
+	entries, err := remote.ReadDir(filepath.Join(util.RootPath, rel))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifacts: %w", err)
+	}
+	var artifacts []Artifact
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		artifacts = append(artifacts, Artifact{
+			Name:     entry.Name(),
+			Path:     filepath.Join(rel, entry.Name()),
+			Size:     info.Size(),
+			Modified: info.ModTime(),
+		})
+	}
+	return artifacts, nil
+}
+
+// SetSaveArtifacts turns saving the artifacts of answers on or off
+func (a *App) SetSaveArtifacts(on bool) tea.Cmd {
+	a.State.SaveArtifacts = on
+	return a.SaveState()
+}
+
+// RemoveArtifact deletes a saved artifact
+func (a *App) RemoveArtifact(artifact Artifact) error {
+	if err := remote.Remove(filepath.Join(util.RootPath, artifact.Path)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove %s: %w", artifact.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+func TestMessageArtifacts(t *testing.T) {
+	created := time.Date(2026, 3, 14, 9, 30, 0, 0, time.Local)
+	answer := "Run this migration:\n\n```sql\nALTER TABLE users\n  ADD COLUMN email TEXT;\nCREATE INDEX users_email ON users (email);\n```\n\n" +
+		"Then:\n\n```bash\nmake migrate\n```\n\n" +
+		"And the deploy script I wrote:\n\n```sh\n#!/bin/sh\nset -e\n./deploy\n```\n\n" +
+		"```diff\n-a\n+b\n+c\n```\n"
+	message := Message{
+		Info: opencode.AssistantMessage{ID: "msg_1", Time: opencode.AssistantMessageTime{Created: float64(created.UnixMilli())}},
+		Parts: []opencode.PartUnion{
+			opencode.TextPart{Text: answer},
+			opencode.ToolPart{Tool: "write", State: opencode.ToolPartState{
+				Input: map[string]any{"filePath": "deploy.sh", "content": "#!/bin/sh\nset -e\n./deploy\n"},
+			}},
+		},
+	}
+
+	artifacts := MessageArtifacts(message)
+	want := "ALTER TABLE users\n  ADD COLUMN email TEXT;\nCREATE INDEX users_email ON users (email);\n"
+	if len(artifacts) != 1 || artifacts["20260314-093000-1.sql"] != want {
+		t.Fatalf("artifacts = %q; want only the query, as the command is short, the script written and the diff no artifact", artifacts)
+	}
+
+	root := util.RootPath
+	util.RootPath = t.TempDir()
+	defer func() { util.RootPath = root }()
+	msg := writeArtifacts("ses_1", artifacts)().(ArtifactsSavedMsg)
+	path := filepath.Join(ArtifactsDir, "ses_1", "20260314-093000-1.sql")
+	if msg.Err != nil || len(msg.Paths) != 1 || msg.Paths[0] != path {
+		t.Fatalf("saved = %+v", msg)
+	}
+	if data, err := os.ReadFile(filepath.Join(util.RootPath, path)); err != nil || string(data) != want {
+		t.Errorf("saved artifact = %q, %v", data, err)
+	}
+	if again := writeArtifacts("ses_1", artifacts)().(ArtifactsSavedMsg); again.Err != nil || len(again.Paths) != 0 {
+		t.Errorf("saving again = %+v, want nothing written", again)
+	}
+
+	a := &App{State: &State{}}
+	listed, err := a.Artifacts("ses_1")
+	if err != nil || len(listed) != 1 || listed[0].Path != path || listed[0].Size != int64(len(want)) {
+		t.Fatalf("artifacts listed = %+v, %v", listed, err)
+	}
+	if err := a.RemoveArtifact(listed[0]); err != nil {
+		t.Fatal(err)
+	}
+	if listed, _ := a.Artifacts("ses_1"); len(listed) != 0 {
+		t.Errorf("artifacts after removal = %+v", listed)
+	}
+}
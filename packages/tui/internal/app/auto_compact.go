@@ -0,0 +1,149 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+)
+
+// DefaultAutoCompactThreshold is the share of the context window at which
+// sessions are compacted when the policy sets none
+const DefaultAutoCompactThreshold = 0.85
+
+// autoCompactIdleFloor is the share of the context window an idle session
+// must fill to be compacted, as summarizing a short one gains little
+const autoCompactIdleFloor = 0.3
+
+// autoCompactRetry is how long the policy waits to compact a session again
+// after the server failed to, doubled for each failure in a row up to
+// autoCompactMaxRetry
+const (
+	autoCompactRetry    = 5 * time.Minute
+	autoCompactMaxRetry = 2 * time.Hour
+)
+
+// AutoCompactPolicy decides when sessions are compacted without being
+// asked: when their context fills past a threshold, or when they're left
+// idle. Automatic summaries count as background spend and stop at its cap.
+// Sessions are only compacted once a policy is set with /autocompact.
+type AutoCompactPolicy struct {
+	Off         bool    `toml:"off,omitempty"`
+	Threshold   float64 `toml:"threshold,omitempty"`    // Share of the context window; 0 uses DefaultAutoCompactThreshold
+	IdleMinutes int     `toml:"idle_minutes,omitempty"` // Compact a session idle this long; 0 never does
+}
+
+// String describes the policy, as "at 85% of the context window, or idle 30m"
+func (p AutoCompactPolicy) String() string {
+	if p.Off {
+		return "off"
+	}
+	text := fmt.Sprintf("at %.0f%% of the context window", p.threshold()*100)
+	if p.IdleMinutes > 0 {
+		text += fmt.Sprintf(", or idle %s", time.Duration(p.IdleMinutes)*time.Minute)
+	}
+	return text
+}
+
+func (p AutoCompactPolicy) threshold() float64 {
+	if p.Threshold <= 0 {
+		return DefaultAutoCompactThreshold
+	}
+	return p.Threshold
+}
+
+// AutoCompactPolicy returns the policy sessions are compacted by, off
+// until one is set
+func (a *App) AutoCompactPolicy() AutoCompactPolicy {
+	if a.State.AutoCompact == nil {
+		return AutoCompactPolicy{Off: true}
+	}
+	return *a.State.AutoCompact
+}
+
+// SetAutoCompactPolicy changes the policy sessions are compacted by
+func (a *App) SetAutoCompactPolicy(policy AutoCompactPolicy) tea.Cmd {
+	a.State.AutoCompact = &policy
+	return a.SaveState()
+}
+
+// autoCompactWatch follows the current session for the auto-compact policy
+type autoCompactWatch struct {
+	sessionID string
+	opened    time.Time // When the session was opened, which idle time counts from at the earliest
+	failures  int       // Compactions the server failed in a row
+	retryAt   time.Time // When a failed compaction may be tried again
+}
+
+// AutoCompactFailed backs off compacting a session the server failed to
+// compact, so a failing provider isn't asked again every few minutes
+func (a *App) AutoCompactFailed(sessionID string, now time.Time) {
+	if a.autoCompact.sessionID != sessionID {
+		return
+	}
+	a.autoCompact.failures++
+	delay := autoCompactRetry << (a.autoCompact.failures - 1)
+	if delay > autoCompactMaxRetry || delay <= 0 {
+		delay = autoCompactMaxRetry
+	}
+	a.autoCompact.retryAt = now.Add(delay)
+}
+
+// autoCompactDue returns why the policy compacts the current session now,
+// or "" when it doesn't. A session is compacted once for each stretch of
+// messages; the summary the compaction ends with closes the stretch. Idle
+// time counts from the last answer, or from when the session was opened
+// when that's later, so reopening an old session doesn't compact it.
+func (a *App) autoCompactDue(now time.Time) string {
+	if a.Session == nil || a.Session.ID == "" {
+		return ""
+	}
+	if a.autoCompact.sessionID != a.Session.ID {
+		a.autoCompact = autoCompactWatch{sessionID: a.Session.ID, opened: now}
+	}
+	policy := a.AutoCompactPolicy()
+	if policy.Off || a.IsBusy() || len(a.Messages) == 0 {
+		return ""
+	}
+	last, ok := a.Messages[len(a.Messages)-1].Info.(opencode.AssistantMessage)
+	if ok && last.Summary {
+		a.autoCompact.failures = 0
+	}
+	if !ok || last.Summary || last.Time.Completed == 0 || now.Before(a.autoCompact.retryAt) {
+		return ""
+	}
+	_, share := a.ContextUsage()
+	if share >= policy.threshold() {
+		return fmt.Sprintf("context %.0f%% full", share*100)
+	}
+	if policy.IdleMinutes > 0 && share >= autoCompactIdleFloor {
+		since := time.UnixMilli(int64(last.Time.Completed))
+		if a.autoCompact.opened.After(since) {
+			since = a.autoCompact.opened
+		}
+		idle := now.Sub(since)
+		if idle >= time.Duration(policy.IdleMinutes)*time.Minute {
+			return fmt.Sprintf("idle %s", idle.Truncate(time.Minute))
+		}
+	}
+	return ""
+}
+
+// AutoCompact compacts the current session when the policy says it's due
+// and background spend is under today's cap
+func (a *App) AutoCompact() tea.Cmd {
+	reason := a.autoCompactDue(time.Now())
+	if reason == "" || a.BackgroundSpentToday() >= a.BackgroundCap() {
+		return nil
+	}
+	return tea.Batch(
+		a.compactSession(context.Background(), reason),
+		toast.NewInfoToast(
+			fmt.Sprintf("Summarizing the session, %s. /autocompact changes when this happens.", reason),
+			toast.WithTitle("Auto-compacting"),
+		),
+	)
+}
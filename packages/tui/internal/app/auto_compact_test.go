@@ -0,0 +1,94 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+func TestAutoCompactDue(t *testing.T) {
+	completed := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	reply := func(tokens float64, summary bool) Message {
+		message := opencode.AssistantMessage{ID: "msg_1", Summary: summary}
+		message.Tokens.Input = tokens
+		message.Tokens.Output = 1
+		message.Time.Completed = float64(completed.UnixMilli())
+		return Message{Info: message}
+	}
+	a := &App{
+		State:    &State{},
+		Session:  &opencode.Session{ID: "ses_1"},
+		Model:    &opencode.Model{Limit: opencode.ModelLimit{Context: 1000}},
+		Messages: []Message{reply(899, false)},
+	}
+
+	if reason := a.autoCompactDue(completed); reason != "" {
+		t.Errorf("reason without a policy = %q", reason)
+	}
+	a.State.AutoCompact = &AutoCompactPolicy{}
+	if reason := a.autoCompactDue(completed); reason != "context 90% full" {
+		t.Errorf("reason at 90%% = %q", reason)
+	}
+	a.State.AutoCompact = &AutoCompactPolicy{Threshold: 0.95}
+	if reason := a.autoCompactDue(completed.Add(time.Hour)); reason != "" {
+		t.Errorf("reason under the threshold without an idle limit = %q", reason)
+	}
+	a.State.AutoCompact.IdleMinutes = 30
+	if reason := a.autoCompactDue(completed.Add(10 * time.Minute)); reason != "" {
+		t.Errorf("reason after 10m of 30m idle = %q", reason)
+	}
+	if reason := a.autoCompactDue(completed.Add(45 * time.Minute)); reason != "idle 45m0s" {
+		t.Errorf("reason after 45m idle = %q", reason)
+	}
+
+	a.Messages = []Message{reply(100, false)}
+	if reason := a.autoCompactDue(completed.Add(time.Hour)); reason != "" {
+		t.Errorf("a short idle session is compacted: %q", reason)
+	}
+	a.Messages = []Message{reply(999, true)}
+	if reason := a.autoCompactDue(completed.Add(time.Hour)); reason != "" {
+		t.Errorf("a session ending with its summary is compacted again: %q", reason)
+	}
+	a.Messages = []Message{reply(999, false)}
+	a.State.AutoCompact.Off = true
+	if reason := a.autoCompactDue(completed); reason != "" {
+		t.Errorf("reason with auto-compact off = %q", reason)
+	}
+}
+
+func TestAutoCompactIdleAndRetry(t *testing.T) {
+	completed := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	message := opencode.AssistantMessage{ID: "msg_1"}
+	message.Tokens.Input = 499
+	message.Tokens.Output = 1
+	message.Time.Completed = float64(completed.UnixMilli())
+	a := &App{
+		State:    &State{AutoCompact: &AutoCompactPolicy{IdleMinutes: 30}},
+		Session:  &opencode.Session{ID: "ses_1"},
+		Model:    &opencode.Model{Limit: opencode.ModelLimit{Context: 1000}},
+		Messages: []Message{{Info: message}},
+	}
+
+	// Opened a day after its last answer, the session idles from then
+	opened := completed.Add(24 * time.Hour)
+	if reason := a.autoCompactDue(opened); reason != "" {
+		t.Errorf("a session was compacted as it was opened: %q", reason)
+	}
+	if reason := a.autoCompactDue(opened.Add(31 * time.Minute)); reason != "idle 31m0s" {
+		t.Errorf("reason after 31m open = %q", reason)
+	}
+
+	now := opened.Add(time.Hour)
+	a.AutoCompactFailed("ses_1", now)
+	if reason := a.autoCompactDue(now.Add(4 * time.Minute)); reason != "" {
+		t.Errorf("a failed compaction was retried at once: %q", reason)
+	}
+	if reason := a.autoCompactDue(now.Add(6 * time.Minute)); reason == "" {
+		t.Error("a failed compaction was never retried")
+	}
+	a.AutoCompactFailed("ses_1", now)
+	if reason := a.autoCompactDue(now.Add(6 * time.Minute)); reason != "" {
+		t.Errorf("the retry didn't back off after a second failure: %q", reason)
+	}
+}
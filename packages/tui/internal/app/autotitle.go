@@ -0,0 +1,163 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/intelligence"
+)
+
+// maxTitleExchangeBytes bounds each side of the exchange a title is
+// generated from
+const maxTitleExchangeBytes = 4000
+
+// titleSystemPrompt asks for nothing but the title
+const titleSystemPrompt = `You name coding sessions. Reply with only a title of at most six words, in sentence case, without quotes or final punctuation, that says what the session is about.`
+
+// SessionTitledMsg is sent when a title was generated for a session
+type SessionTitledMsg struct {
+	SessionID string
+	Previous  string // Title the session had when the title was asked for
+	Title     string
+	Err       error
+}
+
+// AutoTitle titles a session once its first exchange has completed, with
+// the cheapest model of the provider that answered. Sessions the prompt
+// didn't start, or that auto-title wasn't allowed for when they started,
+// keep their title.
+func (a *App) AutoTitle(message opencode.AssistantMessage) tea.Cmd {
+	previous, ok := a.autoTitles[message.SessionID]
+	if !ok || message.Time.Completed == 0 {
+		return nil
+	}
+	delete(a.autoTitles, message.SessionID)
+	if !a.BackgroundAllowed(intelligence.BackgroundTitle) || message.SessionID != a.Session.ID {
+		return nil
+	}
+
+	var question, answer []string
+	for _, m := range a.Messages {
+		for _, part := range m.Parts {
+			text, ok := part.(opencode.TextPart)
+			if !ok || text.Synthetic {
+				continue
+			}
+			switch m.Info.(type) {
+			case opencode.UserMessage:
+				question = append(question, text.Text)
+			case opencode.AssistantMessage:
+				answer = append(answer, text.Text)
+			}
+		}
+	}
+	if len(question) == 0 {
+		return nil
+	}
+	prompt := fmt.Sprintf(
+		"Title this session from its first exchange.\n\nUser:\n%s\n\nAssistant:\n%s",
+		truncateExchange(strings.Join(question, "\n\n")),
+		truncateExchange(strings.Join(answer, "\n\n")),
+	)
+	sessionID := message.SessionID
+	model := askModel{
+		providerID: message.ProviderID,
+		modelID:    a.cheapestModel(message.ProviderID, message.ModelID),
+		agent:      a.Agent().Name,
+		// Its spend counts as the auto-title's
+		started: func(id string) { a.titleSessions.Store(id, true) },
+	}
+
+	return func() tea.Msg {
+		ctx, cancel := a.TimeoutContext(TimeoutGenerate)
+		defer cancel()
+		reply, err := a.askThrowaway(ctx, model, "title: "+previous, titleSystemPrompt, prompt)
+		if err != nil {
+			return SessionTitledMsg{SessionID: sessionID, Err: err}
+		}
+		title := cleanTitle(reply)
+		if title == "" {
+			return SessionTitledMsg{SessionID: sessionID, Err: fmt.Errorf("the model replied without a title")}
+		}
+		return SessionTitledMsg{SessionID: sessionID, Previous: previous, Title: title}
+	}
+}
+
+// ApplySessionTitle renames a session to its generated title, unless it was
+// renamed meanwhile
+func (a *App) ApplySessionTitle(msg SessionTitledMsg) tea.Cmd {
+	if msg.Err != nil {
+		slog.Warn("Failed to generate session title", "session", msg.SessionID, "error", msg.Err)
+		return nil
+	}
+	if a.Session.ID == msg.SessionID && a.Session.Title != msg.Previous {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := a.TimeoutContext(TimeoutAPI)
+		defer cancel()
+		// The server reports the new title with a session update
+		a.UpdateSession(ctx, msg.SessionID, msg.Title)
+		return nil
+	}
+}
+
+// expectAutoTitle marks a session just started to be titled after its
+// first exchange, if it still has the title it started with then
+func (a *App) expectAutoTitle(session *opencode.Session) {
+	if a.autoTitles == nil {
+		a.autoTitles = make(map[string]string)
+	}
+	a.autoTitles[session.ID] = session.Title
+}
+
+// cheapestModel returns the model of a provider with the lowest price,
+// or fallback when the provider lists no priced model
+func (a *App) cheapestModel(providerID, fallback string) string {
+	best, bestPrice := fallback, -1.0
+	for _, provider := range a.Providers {
+		if provider.ID != providerID {
+			continue
+		}
+		for id, model := range provider.Models {
+			price := model.Cost.Input + model.Cost.Output
+			if model.Experimental || price <= 0 {
+				continue
+			}
+			if bestPrice < 0 || price < bestPrice || (price == bestPrice && id < best) {
+				best, bestPrice = id, price
+			}
+		}
+	}
+	return best
+}
+
+func truncateExchange(text string) string {
+	text = strings.TrimSpace(text)
+	if len(text) <= maxTitleExchangeBytes {
+		return text
+	}
+	return strings.ToValidUTF8(text[:maxTitleExchangeBytes], "") + "…"
+}
+
+// cleanTitle makes a title of the model's reply: its first line, without
+// quotes, a "Title:" label or final punctuation, cut short
+func cleanTitle(reply string) string {
+	line := ""
+	for candidate := range strings.SplitSeq(reply, "\n") {
+		if candidate = strings.TrimSpace(candidate); candidate != "" {
+			line = candidate
+			break
+		}
+	}
+	line = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(line, "Title:"), "title:"))
+	line = strings.Trim(line, "\"'`*# ")
+	line = strings.TrimRight(line, ".!")
+	if line == "" {
+		return ""
+	}
+	return PromptTitle(line)
+}
@@ -0,0 +1,42 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+func TestCleanTitle(t *testing.T) {
+	cases := map[string]string{
+		"Fix flaky upload retries":                "Fix flaky upload retries",
+		"\n  \"Add dark mode toggle.\"\nBecause…": "Add dark mode toggle",
+		"Title: **Refactor the auth bridge**":     "Refactor the auth bridge",
+		"   ":                                     "",
+		"Migrate the billing service from the legacy REST client to gRPC streaming": "Migrate the billing service from the legacy REST…",
+	}
+	for reply, want := range cases {
+		if got := cleanTitle(reply); got != want {
+			t.Errorf("cleanTitle(%q) = %q, want %q", reply, got, want)
+		}
+	}
+}
+
+func TestCheapestModel(t *testing.T) {
+	a := &App{Providers: []opencode.Provider{
+		{ID: "other", Models: map[string]opencode.Model{
+			"tiny": {Cost: opencode.ModelCost{Input: 0.01, Output: 0.01}},
+		}},
+		{ID: "anthropic", Models: map[string]opencode.Model{
+			"opus":    {Cost: opencode.ModelCost{Input: 15, Output: 75}},
+			"haiku":   {Cost: opencode.ModelCost{Input: 0.8, Output: 4}},
+			"free":    {},
+			"preview": {Cost: opencode.ModelCost{Input: 0.1, Output: 0.1}, Experimental: true},
+		}},
+	}}
+	if got := a.cheapestModel("anthropic", "opus"); got != "haiku" {
+		t.Errorf("cheapestModel = %q, want haiku", got)
+	}
+	if got := a.cheapestModel("missing", "opus"); got != "opus" {
+		t.Errorf("cheapestModel of an unknown provider = %q, want the fallback", got)
+	}
+}
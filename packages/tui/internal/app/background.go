@@ -0,0 +1,126 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/intelligence"
+)
+
+// promptTitleLength is the length of the titles sessions are given from
+// their first prompt, until auto-title names them
+const promptTitleLength = 50
+
+// BackgroundCap returns the background spend allowed per day, in USD
+func (a *App) BackgroundCap() float64 {
+	if a.State.BackgroundCap != nil {
+		return *a.State.BackgroundCap
+	}
+	return intelligence.DefaultBackgroundDailyCap
+}
+
+// SetBackgroundCap sets the background spend allowed per day
+func (a *App) SetBackgroundCap(usd float64) tea.Cmd {
+	a.State.BackgroundCap = &usd
+	return a.SaveState()
+}
+
+// BackgroundEnabled reports whether a background feature is on
+func (a *App) BackgroundEnabled(feature intelligence.BackgroundFeature) bool {
+	return !slices.Contains(a.State.BackgroundOff, string(feature))
+}
+
+// SetBackgroundEnabled turns a background feature on or off
+func (a *App) SetBackgroundEnabled(feature intelligence.BackgroundFeature, on bool) (tea.Cmd, error) {
+	if !feature.Switchable() {
+		return nil, fmt.Errorf("%s runs on the server and can't be turned off here", strings.ToLower(feature.Name()))
+	}
+	a.State.BackgroundOff = slices.DeleteFunc(a.State.BackgroundOff, func(name string) bool {
+		return name == string(feature)
+	})
+	if !on {
+		a.State.BackgroundOff = append(a.State.BackgroundOff, string(feature))
+	}
+	return a.SaveState(), nil
+}
+
+// BackgroundSpentToday returns what background features spent today
+func (a *App) BackgroundSpentToday() float64 {
+	return intelligence.BackgroundSpentToday(a.CostLedger().Records(), time.Now())
+}
+
+// BackgroundAllowed reports whether a background feature may run: it is on
+// and background spend is under today's cap
+func (a *App) BackgroundAllowed(feature intelligence.BackgroundFeature) bool {
+	return a.BackgroundEnabled(feature) && a.BackgroundSpentToday() < a.BackgroundCap()
+}
+
+// backgroundFeatureOf returns the background feature behind a response, or
+// "" for the response to a prompt. Summaries count unless /compact asked for
+// them.
+func (a *App) backgroundFeatureOf(message opencode.AssistantMessage) intelligence.BackgroundFeature {
+	if _, ok := a.titleSessions.Load(message.SessionID); ok {
+		return intelligence.BackgroundTitle
+	}
+	if !message.Summary {
+		return ""
+	}
+	if a.compactRequested[message.SessionID] {
+		delete(a.compactRequested, message.SessionID)
+		return ""
+	}
+	return intelligence.BackgroundCompaction
+}
+
+// checkBackgroundCap warns, once a day, when background spend reaches the
+// cap and the features that can be paused stop running
+func (a *App) checkBackgroundCap() tea.Cmd {
+	now := time.Now()
+	today := now.Format("2006-01-02")
+	spent := a.BackgroundSpentToday()
+	if a.backgroundWarned == today || spent < a.BackgroundCap() {
+		return nil
+	}
+	a.backgroundWarned = today
+	return toast.NewWarningToast(
+		fmt.Sprintf("Background features spent $%.2f today, over the $%.2f cap. Auto-title and model recommendations pause until tomorrow; /background shows the breakdown.", spent, a.BackgroundCap()),
+		toast.WithTitle("Background spend capped"),
+	)
+}
+
+// createPromptSession creates the session a prompt starts, named after the
+// prompt so that the server doesn't generate a title. When auto-title may
+// run, it renames the session after the first exchange.
+func (a *App) createPromptSession(ctx context.Context, prompt Prompt) (*opencode.Session, error) {
+	session, err := a.Client.Session.New(ctx, opencode.SessionNewParams{Title: opencode.F(PromptTitle(prompt.Text))})
+	if err != nil {
+		return nil, err
+	}
+	if a.BackgroundAllowed(intelligence.BackgroundTitle) {
+		a.expectAutoTitle(session)
+	}
+	return session, nil
+}
+
+// PromptTitle names a session after its first prompt: the first line, cut
+// short
+func PromptTitle(text string) string {
+	line := strings.TrimSpace(text)
+	if i := strings.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	line = strings.Join(strings.Fields(line), " ")
+	if runes := []rune(line); len(runes) > promptTitleLength {
+		line = strings.TrimSpace(string(runes[:promptTitleLength-1])) + "…"
+	}
+	if line == "" {
+		return "Untitled"
+	}
+	return line
+}
@@ -0,0 +1,173 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/bench"
+	"github.com/aaronmrosenthal/rycode/internal/intelligence"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// benchCaseTimeout bounds how long a single benchmark case may take
+const benchCaseTimeout = 5 * time.Minute
+
+// BenchmarkFinishedMsg is sent when a benchmark run completes
+type BenchmarkFinishedMsg struct {
+	Report  *bench.Report
+	Results []intelligence.BenchmarkResult
+	Err     error
+}
+
+// BenchmarkHistoryPath returns the file benchmark results are saved to
+func (a *App) BenchmarkHistoryPath() string {
+	return filepath.Join(a.InsightsDir(), "benchmarks.json")
+}
+
+// RunBenchmark runs a benchmark suite against the given models. args is the
+// text typed after /bench: an optional suite path followed by optional
+// "provider/model" references.
+func (a *App) RunBenchmark(args string) tea.Cmd {
+	suitePath := filepath.Join(util.RootPath, bench.DefaultSuitePath)
+	var modelArgs []string
+	for i, field := range strings.Fields(args) {
+		if i == 0 && (strings.HasSuffix(field, ".yaml") || strings.HasSuffix(field, ".yml")) {
+			suitePath = field
+			if !filepath.IsAbs(suitePath) {
+				suitePath = filepath.Join(util.CwdPath, suitePath)
+			}
+			continue
+		}
+		modelArgs = append(modelArgs, field)
+	}
+
+	return func() tea.Msg {
+		suite, err := bench.LoadSuite(suitePath)
+		if err != nil {
+			return BenchmarkFinishedMsg{Err: err}
+		}
+
+		models, err := a.benchmarkModels(suite, modelArgs)
+		if err != nil {
+			return BenchmarkFinishedMsg{Err: err}
+		}
+
+		slog.Info("Running benchmark", "suite", suite.Name, "cases", len(suite.Cases), "models", len(models))
+		report := bench.Run(context.Background(), suite, models, &sessionCompleter{app: a, agent: suite.Agent}, func(done, total int) {
+			slog.Debug("Benchmark progress", "done", done, "total", total)
+		})
+
+		results := report.Summaries()
+		history, err := intelligence.LoadBenchmarkHistory(a.BenchmarkHistoryPath())
+		if err != nil {
+			slog.Warn("Failed to load benchmark history", "error", err)
+		}
+		history.Add(results...)
+		if err := history.Save(); err != nil {
+			slog.Error("Failed to save benchmark results", "error", err)
+		}
+
+		return BenchmarkFinishedMsg{Report: report, Results: results}
+	}
+}
+
+// benchmarkModels resolves the models to benchmark: explicit arguments first,
+// then the suite's list, then the current model
+func (a *App) benchmarkModels(suite *bench.Suite, args []string) ([]bench.ModelRef, error) {
+	refs := args
+	if len(refs) == 0 {
+		refs = suite.Models
+	}
+	if len(refs) == 0 && a.Provider != nil && a.Model != nil {
+		refs = []string{a.Provider.ID + "/" + a.Model.ID}
+	}
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("no models to benchmark")
+	}
+
+	models := make([]bench.ModelRef, 0, len(refs))
+	for _, ref := range refs {
+		model, err := bench.ParseModelRef(ref)
+		if err != nil {
+			return nil, err
+		}
+		if provider, m := findModelByProviderAndModelID(a.Providers, model.ProviderID, model.ModelID); provider == nil || m == nil {
+			return nil, fmt.Errorf("model %s is not available", ref)
+		}
+		models = append(models, model)
+	}
+	return models, nil
+}
+
+// sessionCompleter runs each benchmark case in its own throwaway session so
+// answers are not influenced by earlier cases
+type sessionCompleter struct {
+	app   *App
+	agent string
+}
+
+func (c *sessionCompleter) Complete(ctx context.Context, model bench.ModelRef, benchCase bench.Case) (bench.Completion, error) {
+	ctx, cancel := context.WithTimeout(ctx, benchCaseTimeout)
+	defer cancel()
+
+	session, err := c.app.Client.Session.New(ctx, opencode.SessionNewParams{
+		Title: opencode.F("bench: " + benchCase.Name),
+	})
+	if err != nil {
+		return bench.Completion{}, fmt.Errorf("failed to create benchmark session: %w", err)
+	}
+	defer func() {
+		if _, err := c.app.Client.Session.Delete(context.Background(), session.ID, opencode.SessionDeleteParams{}); err != nil {
+			slog.Debug("Failed to delete benchmark session", "session", session.ID, "error", err)
+		}
+	}()
+
+	agent := c.agent
+	if agent == "" {
+		agent = c.app.Agent().Name
+	}
+	params := opencode.SessionPromptParams{
+		Model: opencode.F(opencode.SessionPromptParamsModel{
+			ProviderID: opencode.F(model.ProviderID),
+			ModelID:    opencode.F(model.ModelID),
+		}),
+		Agent: opencode.F(agent),
+		Parts: opencode.F([]opencode.SessionPromptParamsPartUnion{
+			opencode.TextPartInputParam{
+				Type: opencode.F(opencode.TextPartInputTypeText),
+				Text: opencode.F(benchCase.Prompt),
+			},
+		}),
+	}
+	if benchCase.System != "" {
+		params.System = opencode.F(benchCase.System)
+	}
+
+	start := time.Now()
+	response, err := c.app.Client.Session.Prompt(ctx, session.ID, params)
+	latency := time.Since(start)
+	if err != nil {
+		return bench.Completion{}, err
+	}
+
+	var texts []string
+	for _, part := range response.Parts {
+		if text, ok := part.AsUnion().(opencode.TextPart); ok && !text.Synthetic {
+			texts = append(texts, text.Text)
+		}
+	}
+
+	tokens := response.Info.Tokens
+	return bench.Completion{
+		Text:    strings.Join(texts, "\n"),
+		Cost:    response.Info.Cost,
+		Tokens:  int64(tokens.Input + tokens.Output + tokens.Reasoning),
+		Latency: latency,
+	}, nil
+}
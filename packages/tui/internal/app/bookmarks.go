@@ -0,0 +1,142 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+// bookmarkExcerptLength is the number of characters of a message kept to
+// recognize its bookmark
+const bookmarkExcerptLength = 80
+
+// Bookmark is a message kept to jump back to, in any session
+type Bookmark struct {
+	SessionID    string    `toml:"session_id"`
+	SessionTitle string    `toml:"session_title"`
+	MessageID    string    `toml:"message_id"`
+	Role         string    `toml:"role"`
+	Excerpt      string    `toml:"excerpt"`
+	Created      time.Time `toml:"created"`
+}
+
+// BookmarkOpenedMsg is sent once the session of a bookmark was found
+type BookmarkOpenedMsg struct {
+	Session   *opencode.Session
+	MessageID string
+	Err       error
+}
+
+// SessionPinned reports whether a session is pinned to the top of the
+// session list
+func (a *App) SessionPinned(sessionID string) bool {
+	return slices.Contains(a.State.PinnedSessions, sessionID)
+}
+
+// TogglePinnedSession pins a session, or unpins it when it is pinned, and
+// reports whether it is pinned now
+func (a *App) TogglePinnedSession(sessionID string) (bool, tea.Cmd) {
+	if i := slices.Index(a.State.PinnedSessions, sessionID); i >= 0 {
+		a.State.PinnedSessions = slices.Delete(a.State.PinnedSessions, i, i+1)
+		return false, a.SaveState()
+	}
+	a.State.PinnedSessions = append(a.State.PinnedSessions, sessionID)
+	return true, a.SaveState()
+}
+
+// PinnedFirst orders sessions with the pinned ones first, keeping the order
+// of each group
+func (a *App) PinnedFirst(sessions []opencode.Session) []opencode.Session {
+	sorted := slices.Clone(sessions)
+	slices.SortStableFunc(sorted, func(x, y opencode.Session) int {
+		px, py := a.SessionPinned(x.ID), a.SessionPinned(y.ID)
+		switch {
+		case px && !py:
+			return -1
+		case py && !px:
+			return 1
+		}
+		return 0
+	})
+	return sorted
+}
+
+// Bookmarked reports whether a message is bookmarked
+func (a *App) Bookmarked(messageID string) bool {
+	return slices.ContainsFunc(a.State.Bookmarks, func(b Bookmark) bool {
+		return b.MessageID == messageID
+	})
+}
+
+// ToggleBookmark bookmarks a message of the current session, or removes its
+// bookmark, and reports whether it is bookmarked now
+func (a *App) ToggleBookmark(messageID string) (bool, tea.Cmd, error) {
+	if i := slices.IndexFunc(a.State.Bookmarks, func(b Bookmark) bool { return b.MessageID == messageID }); i >= 0 {
+		a.State.Bookmarks = slices.Delete(a.State.Bookmarks, i, i+1)
+		return false, a.SaveState(), nil
+	}
+	i := slices.IndexFunc(a.Messages, func(m Message) bool { return messageID != "" && MessageID(m) == messageID })
+	if i < 0 {
+		return false, nil, fmt.Errorf("no message to bookmark")
+	}
+	message := a.Messages[i]
+	role := "user"
+	if _, ok := message.Info.(opencode.AssistantMessage); ok {
+		role = "assistant"
+	}
+	excerpt := strings.Join(strings.Fields(MessageText(message)), " ")
+	if runes := []rune(excerpt); len(runes) > bookmarkExcerptLength {
+		excerpt = string(runes[:bookmarkExcerptLength-1]) + "…"
+	}
+	a.State.Bookmarks = append([]Bookmark{{
+		SessionID:    a.Session.ID,
+		SessionTitle: a.Session.Title,
+		MessageID:    messageID,
+		Role:         role,
+		Excerpt:      excerpt,
+		Created:      time.Now(),
+	}}, a.State.Bookmarks...)
+	return true, a.SaveState(), nil
+}
+
+// RemoveBookmark removes the bookmark of a message
+func (a *App) RemoveBookmark(messageID string) tea.Cmd {
+	a.State.Bookmarks = slices.DeleteFunc(a.State.Bookmarks, func(b Bookmark) bool {
+		return b.MessageID == messageID
+	})
+	return a.SaveState()
+}
+
+// OpenBookmark finds the session of a bookmark to switch to it
+func (a *App) OpenBookmark(bookmark Bookmark) tea.Cmd {
+	if a.Session != nil && a.Session.ID == bookmark.SessionID {
+		session := *a.Session
+		return func() tea.Msg {
+			return BookmarkOpenedMsg{Session: &session, MessageID: bookmark.MessageID}
+		}
+	}
+	return func() tea.Msg {
+		ctx, cancel := a.TimeoutContext(TimeoutAPI)
+		defer cancel()
+		session, err := a.Client.Session.Get(ctx, bookmark.SessionID, opencode.SessionGetParams{})
+		if err != nil {
+			return BookmarkOpenedMsg{Err: fmt.Errorf("%q is gone: %w", bookmark.SessionTitle, err)}
+		}
+		return BookmarkOpenedMsg{Session: session, MessageID: bookmark.MessageID}
+	}
+}
+
+// MessageID returns the ID of a message, user or assistant
+func MessageID(message Message) string {
+	switch info := message.Info.(type) {
+	case opencode.UserMessage:
+		return info.ID
+	case opencode.AssistantMessage:
+		return info.ID
+	}
+	return ""
+}
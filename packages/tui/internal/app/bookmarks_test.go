@@ -0,0 +1,43 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+func TestBookmarks(t *testing.T) {
+	a := &App{
+		StatePath: filepath.Join(t.TempDir(), "tui"),
+		State:     NewState(),
+		Session:   &opencode.Session{ID: "ses_1", Title: "fix the build"},
+		Messages: []Message{
+			{Info: opencode.UserMessage{ID: "msg_1"}, Parts: []opencode.PartUnion{opencode.TextPart{Text: "Why does\nthe build fail?"}}},
+			{Info: opencode.AssistantMessage{ID: "msg_2"}, Parts: []opencode.PartUnion{opencode.TextPart{Text: "The go.mod is missing."}}},
+		},
+	}
+
+	if bookmarked, _, err := a.ToggleBookmark("msg_2"); err != nil || !bookmarked {
+		t.Fatalf("bookmarking = %v, %v", bookmarked, err)
+	}
+	a.ToggleBookmark("msg_1")
+	if b := a.State.Bookmarks[0]; b.MessageID != "msg_1" || b.Role != "user" || b.Excerpt != "Why does the build fail?" || b.SessionTitle != "fix the build" {
+		t.Errorf("newest bookmark = %+v", b)
+	}
+	if bookmarked, _, _ := a.ToggleBookmark("msg_1"); bookmarked || a.Bookmarked("msg_1") || !a.Bookmarked("msg_2") {
+		t.Error("toggling a bookmark again didn't remove only it")
+	}
+	if _, _, err := a.ToggleBookmark(""); err == nil {
+		t.Error("bookmarked without a message")
+	}
+
+	a.TogglePinnedSession("ses_3")
+	sessions := a.PinnedFirst([]opencode.Session{{ID: "ses_1"}, {ID: "ses_2"}, {ID: "ses_3"}})
+	if sessions[0].ID != "ses_3" || sessions[1].ID != "ses_1" || sessions[2].ID != "ses_2" {
+		t.Errorf("sessions = %v, want the pinned one first, then in order", sessions)
+	}
+	if pinned, _ := a.TogglePinnedSession("ses_3"); pinned || a.SessionPinned("ses_3") {
+		t.Error("session still pinned")
+	}
+}
@@ -0,0 +1,29 @@
+package app
+
+import (
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/bridge"
+)
+
+// PublishEdit sends the diff of an edit the session completed to the editor
+// plugins subscribed to the bridge
+func (a *App) PublishEdit(part opencode.PartUnion) {
+	tool, ok := part.(opencode.ToolPart)
+	if a.Bridge == nil || !ok || tool.SessionID != a.Session.ID || tool.State.Status != opencode.ToolPartStateStatusCompleted {
+		return
+	}
+	metadata, _ := tool.State.Metadata.(map[string]any)
+	diff, _ := metadata["diff"].(string)
+	if diff == "" {
+		return
+	}
+	input, _ := tool.State.Input.(map[string]any)
+	path, _ := input["filePath"].(string)
+	a.Bridge.PublishDiff(bridge.Diff{
+		SessionID: tool.SessionID,
+		PartID:    tool.ID,
+		Tool:      tool.Tool,
+		Path:      path,
+		Diff:      diff,
+	})
+}
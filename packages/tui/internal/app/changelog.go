@@ -0,0 +1,76 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/changelog"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// ChangelogDraftMsg is sent when release notes have been drafted
+type ChangelogDraftMsg struct {
+	Options changelog.Options
+	Commits int
+	Draft   string
+	Err     error
+}
+
+// ReleaseCreatedMsg is sent when a GitHub release has been created
+type ReleaseCreatedMsg struct {
+	URL string
+	Err error
+}
+
+// DraftChangelog drafts release notes for the range named in the arguments of
+// /changelog. The commits are grouped locally and written up by the model in
+// a throwaway session with editing tools disabled.
+func (a *App) DraftChangelog(args string) tea.Cmd {
+	model := a.currentAskModel()
+
+	return func() tea.Msg {
+		ctx := context.Background()
+		options, err := changelog.ParseArgs(ctx, util.RootPath, args)
+		if err != nil {
+			return ChangelogDraftMsg{Err: err}
+		}
+		commits, err := changelog.Log(ctx, util.RootPath, options.Range)
+		if err != nil {
+			return ChangelogDraftMsg{Options: options, Err: err}
+		}
+		if len(commits) == 0 {
+			return ChangelogDraftMsg{Options: options, Err: fmt.Errorf("no commits in %s", options.Range)}
+		}
+
+		draft, err := a.askThrowaway(ctx, model, "changelog: "+options.Range, "", changelog.Prompt(options, changelog.GroupCommits(commits)))
+		if err != nil {
+			return ChangelogDraftMsg{Options: options, Err: fmt.Errorf("failed to draft release notes: %w", err)}
+		}
+		return ChangelogDraftMsg{
+			Options: options,
+			Commits: len(commits),
+			Draft:   strings.TrimSpace(draft),
+		}
+	}
+}
+
+// WriteChangelog adds release notes to the project's CHANGELOG.md and
+// returns its path
+func (a *App) WriteChangelog(version, notes string) (string, error) {
+	path := filepath.Join(util.RootPath, changelog.DefaultPath)
+	return path, changelog.Prepend(path, version, notes, time.Now())
+}
+
+// CreateRelease publishes release notes as a GitHub release
+func (a *App) CreateRelease(tag, notes string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		url, err := changelog.CreateRelease(ctx, util.RootPath, tag, notes)
+		return ReleaseCreatedMsg{URL: url, Err: err}
+	}
+}
@@ -0,0 +1,172 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// maxArchivedSessions is the number of cleared sessions /unclear can bring
+// back
+const maxArchivedSessions = 20
+
+// editTools are the tools whose filePath input is a file they changed
+var editTools = []string{"edit", "multiedit", "write", "patch"}
+
+// ArchivedSession is a session cleared from the screen. Its messages stay
+// on the server, where /unclear loads them from.
+type ArchivedSession struct {
+	ID         string    `toml:"id"`
+	Title      string    `toml:"title"`
+	Messages   int       `toml:"messages"`
+	ArchivedAt time.Time `toml:"archived_at"`
+}
+
+// ClearCheckedMsg is sent once the files the session changed were checked
+// for uncommitted changes before clearing it
+type ClearCheckedMsg struct {
+	SessionID   string
+	Uncommitted []string // Files the session changed that aren't committed
+	Then        tea.Cmd  // Run once the session is cleared
+}
+
+// CheckClear lists the files the current session changed that have
+// uncommitted changes, so that clearing the session can be confirmed. Once
+// it is cleared, then runs, carrying on with what cleared it; without a
+// session then runs right away.
+func (a *App) CheckClear(then tea.Cmd) tea.Cmd {
+	if a.Session.ID == "" {
+		return then
+	}
+	sessionID := a.Session.ID
+	files := a.sessionEditedFiles()
+	return func() tea.Msg {
+		msg := ClearCheckedMsg{SessionID: sessionID, Then: then}
+		if len(files) == 0 {
+			return msg
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		args := append([]string{"status", "--porcelain", "--untracked-files=all", "--"}, files...)
+		out, err := remote.Command(ctx, util.RootPath, "git", args...).Output()
+		if err != nil {
+			// Outside a repository there is nothing to commit
+			slog.Debug("Failed to check the session's files", "error", err)
+			return msg
+		}
+		for line := range strings.Lines(string(out)) {
+			line = strings.TrimRight(line, "\n")
+			if len(line) < 4 {
+				continue
+			}
+			file := line[3:]
+			if _, renamed, ok := strings.Cut(file, " -> "); ok {
+				file = renamed
+			}
+			msg.Uncommitted = append(msg.Uncommitted, strings.Trim(file, `"`))
+		}
+		return msg
+	}
+}
+
+// sessionEditedFiles lists the files the session's tools changed, relative
+// to the project
+func (a *App) sessionEditedFiles() []string {
+	var files []string
+	add := func(file string) {
+		if file == "" {
+			return
+		}
+		if filepath.IsAbs(file) {
+			rel, err := filepath.Rel(util.RootPath, file)
+			if err != nil || strings.HasPrefix(rel, "..") {
+				return
+			}
+			file = rel
+		}
+		if file = filepath.ToSlash(file); !slices.Contains(files, file) {
+			files = append(files, file)
+		}
+	}
+	for _, message := range a.Messages {
+		for _, part := range message.Parts {
+			switch part := part.(type) {
+			case opencode.ToolPart:
+				if part.State.Status != opencode.ToolPartStateStatusCompleted || !slices.Contains(editTools, part.Tool) {
+					continue
+				}
+				if input, ok := part.State.Input.(map[string]any); ok {
+					file, _ := input["filePath"].(string)
+					add(file)
+				}
+			case opencode.PartPatchPart:
+				for _, file := range part.Files {
+					add(file)
+				}
+			}
+		}
+	}
+	return files
+}
+
+// ArchiveSession records the current session before it is cleared from the
+// screen, for /unclear to bring back
+func (a *App) ArchiveSession() tea.Cmd {
+	if a.Session.ID == "" || len(a.Messages) == 0 || a.Tutorial != nil {
+		return nil
+	}
+	archived := ArchivedSession{
+		ID:         a.Session.ID,
+		Title:      a.Session.Title,
+		Messages:   len(a.Messages),
+		ArchivedAt: time.Now(),
+	}
+	a.State.ArchivedSessions = slices.DeleteFunc(a.State.ArchivedSessions, func(s ArchivedSession) bool {
+		return s.ID == archived.ID
+	})
+	a.State.ArchivedSessions = append([]ArchivedSession{archived}, a.State.ArchivedSessions...)
+	if len(a.State.ArchivedSessions) > maxArchivedSessions {
+		a.State.ArchivedSessions = a.State.ArchivedSessions[:maxArchivedSessions]
+	}
+	return a.SaveState()
+}
+
+// UnclearedMsg is sent when /unclear found the last cleared session
+type UnclearedMsg struct {
+	Session *opencode.Session
+	Err     error
+}
+
+// Unclear brings back the session cleared last. A session deleted since
+// is dropped from the archive.
+func (a *App) Unclear() tea.Cmd {
+	archived := slices.DeleteFunc(slices.Clone(a.State.ArchivedSessions), func(s ArchivedSession) bool {
+		return s.ID == a.Session.ID
+	})
+	if len(archived) == 0 {
+		return func() tea.Msg {
+			return UnclearedMsg{Err: fmt.Errorf("no cleared session to bring back")}
+		}
+	}
+	last := archived[0]
+	a.State.ArchivedSessions = archived[1:]
+	save := a.SaveState()
+	return tea.Batch(save, func() tea.Msg {
+		ctx, cancel := a.TimeoutContext(TimeoutAPI)
+		defer cancel()
+		session, err := a.Client.Session.Get(ctx, last.ID, opencode.SessionGetParams{})
+		if err != nil {
+			return UnclearedMsg{Err: fmt.Errorf("%q is gone: %w", last.Title, err)}
+		}
+		return UnclearedMsg{Session: session}
+	})
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+func TestArchiveSession(t *testing.T) {
+	root := util.RootPath
+	util.RootPath = "/project"
+	defer func() { util.RootPath = root }()
+
+	tool := func(name string, status opencode.ToolPartStateStatus, file string) opencode.ToolPart {
+		return opencode.ToolPart{Tool: name, State: opencode.ToolPartState{
+			Status: status,
+			Input:  map[string]any{"filePath": file},
+		}}
+	}
+	a := &App{
+		StatePath: filepath.Join(t.TempDir(), "tui"),
+		State:     NewState(),
+		Session:   &opencode.Session{ID: "ses_1", Title: "fix the build"},
+		Messages: []Message{{Parts: []opencode.PartUnion{
+			tool("read", opencode.ToolPartStateStatusCompleted, "/project/README.md"),
+			tool("edit", opencode.ToolPartStateStatusCompleted, "/project/main.go"),
+			tool("write", opencode.ToolPartStateStatusError, "/project/broken.go"),
+			tool("write", opencode.ToolPartStateStatusCompleted, "/elsewhere/notes.md"),
+			opencode.PartPatchPart{Files: []string{"main.go", "go.mod"}},
+		}}},
+	}
+
+	if files := a.sessionEditedFiles(); !slices.Equal(files, []string{"main.go", "go.mod"}) {
+		t.Errorf("edited files = %q, want the completed edits in the project", files)
+	}
+
+	a.ArchiveSession()
+	a.Session = &opencode.Session{ID: "ses_2", Title: "write docs"}
+	a.ArchiveSession()
+	a.Session = &opencode.Session{ID: "ses_1", Title: "fix the build"}
+	a.ArchiveSession()
+	var ids []string
+	for _, s := range a.State.ArchivedSessions {
+		ids = append(ids, s.ID)
+	}
+	if !slices.Equal(ids, []string{"ses_1", "ses_2"}) {
+		t.Errorf("archive = %q, want the session cleared last first, once", ids)
+	}
+}
+
+func TestCheckClearCarriesOn(t *testing.T) {
+	type switched struct{}
+	then := func() tea.Msg { return switched{} }
+
+	a := &App{Session: &opencode.Session{}}
+	if msg := a.CheckClear(then)(); msg != (switched{}) {
+		t.Errorf("without a session CheckClear sent %T, want the next step run right away", msg)
+	}
+
+	a.Session = &opencode.Session{ID: "ses_1"}
+	msg, ok := a.CheckClear(then)().(ClearCheckedMsg)
+	if !ok || msg.SessionID != "ses_1" || msg.Then == nil || msg.Then() != (switched{}) {
+		t.Errorf("CheckClear sent %+v, want the check carrying the next step", msg)
+	}
+}
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/clipboard"
+	"github.com/aaronmrosenthal/rycode/internal/clipwatch"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+)
+
+// ClipboardErrorMsg is sent when an error message or a stack trace is copied
+// while the clipboard is watched
+type ClipboardErrorMsg struct {
+	Match   clipwatch.Match
+	changes <-chan []byte
+}
+
+// ClipboardErrorExpiredMsg hides the offer to explain a copied error once it
+// has been shown for a while
+type ClipboardErrorExpiredMsg struct {
+	seq int
+}
+
+// clipboardErrorTimeout is how long the offer to explain a copied error stays
+const clipboardErrorTimeout = time.Minute
+
+// ownCopy is the text RyCode last put on the clipboard, which is not offered
+// back when it contains an error
+var ownCopy struct {
+	sync.Mutex
+	text string
+}
+
+func setOwnCopy(text string) {
+	ownCopy.Lock()
+	defer ownCopy.Unlock()
+	ownCopy.text = strings.TrimSpace(text)
+}
+
+func isOwnCopy(text string) bool {
+	ownCopy.Lock()
+	defer ownCopy.Unlock()
+	return ownCopy.text != "" && ownCopy.text == strings.TrimSpace(text)
+}
+
+// WatchingClipboard reports whether copied errors are offered to the session
+func (a *App) WatchingClipboard() bool {
+	return a.clipboardChanges != nil
+}
+
+// StartClipboardWatch starts watching the system clipboard for copied error
+// messages and stack traces
+func (a *App) StartClipboardWatch() tea.Cmd {
+	if a.clipboardChanges != nil {
+		return nil
+	}
+	if err := clipboard.Init(); err != nil {
+		return toast.NewErrorToast("The clipboard can't be read: "+err.Error(), toast.WithTitle("Clipboard watch"))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.clipboardCancel = cancel
+	a.clipboardChanges = clipboard.Watch(ctx, clipboard.FmtText)
+	return waitClipboardError(a.clipboardChanges)
+}
+
+// StopClipboardWatch stops watching the clipboard and drops a pending offer
+func (a *App) StopClipboardWatch() {
+	if a.clipboardCancel != nil {
+		a.clipboardCancel()
+	}
+	a.clipboardCancel, a.clipboardChanges = nil, nil
+	a.ClipboardError = nil
+}
+
+// waitClipboardError waits for the next copy that looks like an error
+func waitClipboardError(changes <-chan []byte) tea.Cmd {
+	return func() tea.Msg {
+		for text := range changes {
+			if isOwnCopy(string(text)) {
+				continue
+			}
+			if match, ok := clipwatch.Detect(string(text)); ok {
+				return ClipboardErrorMsg{Match: match, changes: changes}
+			}
+		}
+		return nil
+	}
+}
+
+// HandleClipboardError offers to explain a copied error and waits for the
+// next one. Errors from a watch that was stopped in the meantime are dropped.
+func (a *App) HandleClipboardError(msg ClipboardErrorMsg) tea.Cmd {
+	if msg.changes != a.clipboardChanges {
+		return nil
+	}
+	match := msg.Match
+	a.ClipboardError = &match
+	a.clipboardErrorSeq++
+	seq := a.clipboardErrorSeq
+	return tea.Batch(
+		waitClipboardError(msg.changes),
+		tea.Tick(clipboardErrorTimeout, func(time.Time) tea.Msg {
+			return ClipboardErrorExpiredMsg{seq: seq}
+		}),
+	)
+}
+
+// ExpireClipboardError hides an offer that nobody took up
+func (a *App) ExpireClipboardError(msg ClipboardErrorExpiredMsg) {
+	if msg.seq == a.clipboardErrorSeq {
+		a.ClipboardError = nil
+	}
+}
+
+// ExplainClipboardError asks the current session to explain and fix the
+// copied error
+func (a *App) ExplainClipboardError() (*App, tea.Cmd) {
+	if a.ClipboardError == nil {
+		return a, nil
+	}
+	prompt := a.ClipboardError.Prompt()
+	a.ClipboardError = nil
+	return a.SendPrompt(context.Background(), Prompt{Text: prompt})
+}
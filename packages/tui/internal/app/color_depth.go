@@ -0,0 +1,40 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+)
+
+// applyColorDepth draws the themes in the depth left in the state, or in
+// the one the terminal is detected to draw
+func applyColorDepth(state *State) {
+	depth, ok := theme.ParseColorDepth(state.ColorDepth)
+	if !ok {
+		depth = theme.DetectColorDepth(os.Getenv)
+	}
+	theme.SetColorDepth(depth)
+}
+
+// SetColorDepth draws the themes in a color depth and remembers it, or
+// detects the terminal's again with "auto"
+func (a *App) SetColorDepth(arg string) tea.Cmd {
+	arg = strings.ToLower(strings.TrimSpace(arg))
+	if arg == "auto" {
+		a.State.ColorDepth = ""
+		depth := theme.DetectColorDepth(os.Getenv)
+		theme.SetColorDepth(depth)
+		return tea.Batch(a.SaveState(), toast.NewSuccessToast(fmt.Sprintf("This terminal draws %s colors", depth), toast.WithTitle("Color depth detected")))
+	}
+	depth, ok := theme.ParseColorDepth(arg)
+	if !ok {
+		return toast.NewErrorToast("Usage: /colors [auto|truecolor|256|16]")
+	}
+	a.State.ColorDepth = depth.String()
+	theme.SetColorDepth(depth)
+	return tea.Batch(a.SaveState(), toast.NewSuccessToast(fmt.Sprintf("Themes are drawn in %s colors", depth)))
+}
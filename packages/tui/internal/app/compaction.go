@@ -0,0 +1,117 @@
+package app
+
+import (
+	"time"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+// compactingTimeout is how long a session is taken to be compacting without
+// the server saying it finished. Server clocks aren't compared with the
+// local one, so it's counted from when the TUI saw the compaction start.
+const compactingTimeout = 5 * time.Minute
+
+// requestedStamp stands for the server's Time.Compacting of a compaction
+// the TUI requested until the server reports it
+const requestedStamp = -1
+
+// compaction follows the compacting of the current session from the server's
+// lifecycle events, timed with the local monotonic clock
+type compaction struct {
+	sessionID string
+	stamp     float64   // Server's Time.Compacting the compaction was seen with
+	started   time.Time // Local time it was seen, with a monotonic reading
+	active    bool      // Kept false once it ends, so that its stamp doesn't start it again
+	reason    string    // Why the auto-compact policy started it, "" when asked for
+}
+
+// ObserveSession notes the compacting state of the current session from an
+// update of it. A new Time.Compacting starts a compaction; its clearing, or
+// SessionCompacted, ends it.
+func (a *App) ObserveSession(session opencode.Session) {
+	if session.ID != a.Session.ID {
+		return
+	}
+	requested := a.compaction.active && a.compaction.sessionID == session.ID && a.compaction.stamp == requestedStamp
+	switch {
+	case session.Time.Compacting == 0:
+		// The compaction the TUI requested may not have started yet
+		if !requested {
+			a.compaction.active = false
+		}
+	case requested:
+		// The compaction the TUI requested started
+		a.compaction.stamp = session.Time.Compacting
+	case a.compaction.sessionID != session.ID || a.compaction.stamp != session.Time.Compacting:
+		a.compaction = compaction{
+			sessionID: session.ID,
+			stamp:     session.Time.Compacting,
+			started:   time.Now(),
+			active:    true,
+		}
+	}
+}
+
+// CompactionStarted notes a compaction requested by the TUI, before the
+// server reports it
+func (a *App) CompactionStarted() {
+	a.compaction = compaction{sessionID: a.Session.ID, stamp: requestedStamp, started: time.Now(), active: true}
+}
+
+// CompactionEnded notes that the server finished compacting a session, or
+// stopped with an error
+func (a *App) CompactionEnded(sessionID string) {
+	if a.compaction.sessionID == sessionID {
+		a.compaction.active = false
+	}
+}
+
+// IsCompacting reports whether the current session is being compacted
+func (a *App) IsCompacting() bool {
+	_, compacting := a.CompactingFor()
+	return compacting
+}
+
+// CompactingFor returns how long the current session has been compacting
+func (a *App) CompactingFor() (time.Duration, bool) {
+	if !a.compaction.active || a.compaction.sessionID != a.Session.ID {
+		return 0, false
+	}
+	elapsed := time.Since(a.compaction.started)
+	return elapsed, elapsed < compactingTimeout
+}
+
+// CompactionReason returns why the auto-compact policy started compacting
+// the current session, "" when it was asked for or the server started it
+func (a *App) CompactionReason() string {
+	if _, compacting := a.CompactingFor(); !compacting {
+		return ""
+	}
+	return a.compaction.reason
+}
+
+// ClearBusy stops the current session from showing as busy, for when the
+// server never reported a reply or a compaction finished
+func (a *App) ClearBusy() bool {
+	cleared := a.IsCompacting()
+	a.compaction.active = false
+	if message, ok := a.pendingReply(); ok {
+		a.busyOverride = message.ID
+		cleared = true
+	}
+	a.stream.awaiting = false
+	return cleared
+}
+
+// pendingReply returns the last message of the session when it's a reply
+// the server hasn't completed
+func (a *App) pendingReply() (opencode.AssistantMessage, bool) {
+	if len(a.Messages) == 0 {
+		return opencode.AssistantMessage{}, false
+	}
+	message, ok := a.Messages[len(a.Messages)-1].Info.(opencode.AssistantMessage)
+	if !ok || message.Time.Completed != 0 {
+		return opencode.AssistantMessage{}, false
+	}
+	return message, true
+}
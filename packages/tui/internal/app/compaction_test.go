@@ -0,0 +1,57 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+func TestCompactionLifecycle(t *testing.T) {
+	session := opencode.Session{ID: "ses_1"}
+	a := &App{Session: &session}
+
+	a.CompactionStarted()
+	// Updates before the server starts compacting don't end it
+	a.ObserveSession(session)
+	if !a.IsCompacting() || !a.IsBusy() {
+		t.Fatal("a requested compaction isn't shown")
+	}
+	session.Time.Compacting = 1_700_000_000_000
+	a.ObserveSession(session)
+	a.SawEvent(opencode.EventListResponseEventSessionCompacted{
+		Properties: opencode.EventListResponseEventSessionCompactedProperties{SessionID: "ses_1"},
+	})
+	if a.IsCompacting() {
+		t.Fatal("still compacting after the server said it finished")
+	}
+	// A server that leaves the stamp doesn't start it again
+	a.ObserveSession(session)
+	if a.IsCompacting() {
+		t.Error("the finished compaction's stamp started another")
+	}
+	session.Time.Compacting = 1_700_000_060_000
+	a.ObserveSession(session)
+	if !a.IsCompacting() {
+		t.Error("a new compaction isn't shown")
+	}
+	if !a.ClearBusy() || a.IsBusy() {
+		t.Error("ClearBusy didn't clear the compaction")
+	}
+}
+
+func TestClearBusyReply(t *testing.T) {
+	a := &App{Session: &opencode.Session{ID: "ses_1"}, Messages: []Message{
+		{Info: opencode.AssistantMessage{ID: "msg_1"}},
+	}}
+	if !a.IsBusy() {
+		t.Fatal("an uncompleted reply isn't busy")
+	}
+	a.ClearBusy()
+	if a.IsBusy() {
+		t.Error("the cleared reply still keeps the session busy")
+	}
+	a.Messages = append(a.Messages, Message{Info: opencode.AssistantMessage{ID: "msg_2"}})
+	if !a.IsBusy() {
+		t.Error("a later reply isn't busy")
+	}
+}
@@ -0,0 +1,248 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// maxPinnedFileBytes is the size above which a pinned file is referenced
+// rather than included in each prompt
+const maxPinnedFileBytes = 64 * 1024
+
+// Kinds of pinned context
+const (
+	PinnedFile = "file"
+	PinnedDir  = "directory"
+	PinnedURL  = "url"
+)
+
+// PinnedItem is an entry of a context set, as it's included in prompts
+type PinnedItem struct {
+	Value    string // Path relative to the project root, or URL
+	Kind     string
+	Included bool // The file's content goes with each prompt, rather than its path
+	Missing  bool // The file or directory no longer exists
+	Tokens   int  // Estimate of what it adds to each prompt
+}
+
+// ContextSetName returns the name of the context set the session
+// re-includes, or "" when it has none
+func (a *App) ContextSetName() string {
+	if a.Session.ID == "" {
+		return a.pendingContext
+	}
+	return a.State.SessionContexts[a.Session.ID]
+}
+
+// ContextSetNames returns the names of the saved context sets, sorted
+func (a *App) ContextSetNames() []string {
+	var names []string
+	for name := range a.State.ContextSets {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// UseContextSet makes the session re-include a named context set, creating
+// it empty when it doesn't exist. An empty name detaches the session's set,
+// which stays saved.
+func (a *App) UseContextSet(name string) tea.Cmd {
+	if name != "" {
+		if a.State.ContextSets == nil {
+			a.State.ContextSets = make(map[string][]string)
+		}
+		if _, ok := a.State.ContextSets[name]; !ok {
+			a.State.ContextSets[name] = []string{}
+		}
+	}
+	if a.Session.ID == "" {
+		// Kept for the session the next prompt starts
+		a.pendingContext = name
+		return a.SaveState()
+	}
+	a.setSessionContext(a.Session.ID, name)
+	return a.SaveState()
+}
+
+func (a *App) setSessionContext(sessionID, name string) {
+	if name == "" {
+		delete(a.State.SessionContexts, sessionID)
+		return
+	}
+	if a.State.SessionContexts == nil {
+		a.State.SessionContexts = make(map[string]string)
+	}
+	a.State.SessionContexts[sessionID] = name
+}
+
+// adoptPendingContext gives a session just started the context set picked
+// before it
+func (a *App) adoptPendingContext() {
+	if a.pendingContext == "" {
+		return
+	}
+	a.setSessionContext(a.Session.ID, a.pendingContext)
+	a.pendingContext = ""
+	if err := a.saveStateNow(); err != nil {
+		slog.Error("Failed to save state", "error", err)
+	}
+}
+
+// PinContext adds files, directories or URLs to the session's context set,
+// starting a set when the session has none. Paths are relative to the
+// current directory and must be in the project.
+func (a *App) PinContext(values []string) (tea.Cmd, error) {
+	var items []string
+	for _, value := range values {
+		item, err := resolvePinned(value)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	name := a.ContextSetName()
+	var cmd tea.Cmd
+	if name == "" {
+		name = a.newContextSetName()
+		cmd = a.UseContextSet(name)
+	}
+	set := a.State.ContextSets[name]
+	for _, item := range items {
+		if !slices.Contains(set, item) {
+			set = append(set, item)
+		}
+	}
+	a.State.ContextSets[name] = set
+	return tea.Batch(cmd, a.SaveState()), nil
+}
+
+// UnpinContext removes an entry from the session's context set
+func (a *App) UnpinContext(value string) (tea.Cmd, error) {
+	name := a.ContextSetName()
+	set := a.State.ContextSets[name]
+	i := slices.Index(set, value)
+	if i < 0 {
+		// The entry may be given as it was pinned, relative to the current
+		// directory
+		if item, err := resolvePinned(value); err == nil {
+			i = slices.Index(set, item)
+		}
+	}
+	if i < 0 {
+		return nil, fmt.Errorf("%s isn't pinned", value)
+	}
+	a.State.ContextSets[name] = slices.Delete(set, i, i+1)
+	return a.SaveState(), nil
+}
+
+// newContextSetName returns a name no context set has
+func (a *App) newContextSetName() string {
+	name := "pinned"
+	for n := 2; ; n++ {
+		if _, ok := a.State.ContextSets[name]; !ok {
+			return name
+		}
+		name = fmt.Sprintf("pinned-%d", n)
+	}
+}
+
+// resolvePinned returns a URL as it is, and a path relative to the project
+// root, checking that it exists
+func resolvePinned(value string) (string, error) {
+	if strings.HasPrefix(value, "https://") || strings.HasPrefix(value, "http://") {
+		return value, nil
+	}
+	path := value
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(util.CwdPath, path)
+	}
+	rel, err := filepath.Rel(util.RootPath, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%s is outside the project", value)
+	}
+	if _, err := remote.Stat(path); err != nil {
+		return "", fmt.Errorf("no such file or directory: %s", value)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// ContextItems returns the entries of the session's context set, with what
+// each adds to every prompt
+func (a *App) ContextItems() []PinnedItem {
+	items, _ := a.pinnedContext(a.ContextSetName())
+	return items
+}
+
+// pinnedContext reads a context set, returning its entries and the text
+// they add to the system prompt
+func (a *App) pinnedContext(name string) ([]PinnedItem, string) {
+	set := a.State.ContextSets[name]
+	if len(set) == 0 {
+		return nil, ""
+	}
+	var items []PinnedItem
+	var included, referenced []string
+	for _, value := range set {
+		item := PinnedItem{Value: value, Kind: PinnedURL}
+		var text string
+		if strings.HasPrefix(value, "https://") || strings.HasPrefix(value, "http://") {
+			text = "- " + value + " (fetch it when it's relevant)"
+			referenced = append(referenced, text)
+		} else {
+			path := filepath.Join(util.RootPath, filepath.FromSlash(value))
+			info, err := remote.Stat(path)
+			switch {
+			case err != nil:
+				item.Kind, item.Missing = PinnedFile, true
+			case info.IsDir():
+				item.Kind = PinnedDir
+				text = "- " + value + "/ (a directory, list and read its files when they're relevant)"
+				referenced = append(referenced, text)
+			case info.Size() > maxPinnedFileBytes:
+				item.Kind = PinnedFile
+				text = "- " + value + " (read it when it's relevant)"
+				referenced = append(referenced, text)
+			default:
+				item.Kind = PinnedFile
+				content, err := remote.ReadFile(path)
+				if err != nil {
+					if !errors.Is(err, fs.ErrNotExist) {
+						slog.Warn("Failed to read pinned file", "file", value, "error", err)
+					}
+					item.Missing = true
+					break
+				}
+				item.Included = true
+				text = fmt.Sprintf("<file path=%q>\n%s\n</file>", value, strings.TrimRight(string(content), "\n"))
+				included = append(included, text)
+			}
+		}
+		item.Tokens = estimateTokens(text)
+		items = append(items, item)
+	}
+
+	var parts []string
+	if len(included) > 0 {
+		parts = append(parts, fmt.Sprintf("The user pinned these files to the conversation (context set %q). They're current as of this message:\n\n%s", name, strings.Join(included, "\n\n")))
+	}
+	if len(referenced) > 0 {
+		parts = append(parts, fmt.Sprintf("The user also pinned these to the conversation (context set %q). Keep them in mind:\n%s", name, strings.Join(referenced, "\n")))
+	}
+	return items, strings.Join(parts, "\n\n")
+}
+
+// estimateTokens approximates a text's token count at four characters a
+// token
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
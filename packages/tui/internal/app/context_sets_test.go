@@ -0,0 +1,61 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+func TestPinnedContext(t *testing.T) {
+	root := t.TempDir()
+	oldRoot, oldCwd := util.RootPath, util.CwdPath
+	util.RootPath, util.CwdPath = root, filepath.Join(root, "src")
+	t.Cleanup(func() { util.RootPath, util.CwdPath = oldRoot, oldCwd })
+	if err := os.MkdirAll(filepath.Join(root, "src", "lib"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "src", "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := &App{State: NewState(), Session: &opencode.Session{ID: "ses_1"}}
+	if _, err := a.PinContext([]string{"main.go", "lib", "https://example.com/spec"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.PinContext([]string{"../../outside"}); err == nil {
+		t.Error("a path outside the project was pinned")
+	}
+	if name := a.ContextSetName(); name != "pinned" {
+		t.Errorf("context set = %q, want pinned", name)
+	}
+
+	items := a.ContextItems()
+	if len(items) != 3 {
+		t.Fatalf("items = %+v", items)
+	}
+	if items[0].Value != "src/main.go" || !items[0].Included || items[0].Tokens == 0 {
+		t.Errorf("file = %+v", items[0])
+	}
+	if items[1].Kind != PinnedDir || items[2].Kind != PinnedURL {
+		t.Errorf("directory and url = %+v, %+v", items[1], items[2])
+	}
+	system := a.sessionSystem("ses_1")
+	if !strings.Contains(system, "package main") || !strings.Contains(system, "https://example.com/spec") {
+		t.Errorf("system = %q", system)
+	}
+
+	if _, err := a.UnpinContext("main.go"); err != nil {
+		t.Fatal(err)
+	}
+	a.UseContextSet("")
+	if system := a.sessionSystem("ses_1"); system != "" {
+		t.Errorf("system after detaching = %q", system)
+	}
+	if len(a.State.ContextSets["pinned"]) != 2 {
+		t.Errorf("set after unpinning = %v", a.State.ContextSets["pinned"])
+	}
+}
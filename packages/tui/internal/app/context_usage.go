@@ -0,0 +1,64 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+)
+
+// CompactThreshold is the share of the model's context window above which
+// compacting the session is suggested
+const CompactThreshold = 0.8
+
+// ContextTokens returns the tokens in the context of a session: the prompt,
+// cache and completion tokens of its latest answer, or only the summary's
+// once the session has been compacted
+func ContextTokens(messages []Message) float64 {
+	tokens := float64(0)
+	for _, message := range messages {
+		assistant, ok := message.Info.(opencode.AssistantMessage)
+		if !ok || assistant.Tokens.Output <= 0 {
+			continue
+		}
+		usage := assistant.Tokens
+		if assistant.Summary {
+			tokens = usage.Output
+			continue
+		}
+		tokens = usage.Input + usage.Cache.Read + usage.Cache.Write + usage.Output + usage.Reasoning
+	}
+	return tokens
+}
+
+// ContextUsage returns the tokens in the current session's context and the
+// share of the model's context window they fill, which is 0 when the window
+// isn't known
+func (a *App) ContextUsage() (tokens, share float64) {
+	tokens = ContextTokens(a.Messages)
+	if a.Model == nil || a.Model.Limit.Context <= 0 {
+		return tokens, 0
+	}
+	return tokens, tokens / a.Model.Limit.Context
+}
+
+// SuggestCompact suggests compacting the session when its context passes
+// CompactThreshold, once until it drops below it again
+func (a *App) SuggestCompact() tea.Cmd {
+	_, share := a.ContextUsage()
+	if share < CompactThreshold {
+		if a.compactSuggested == a.Session.ID {
+			a.compactSuggested = ""
+		}
+		return nil
+	}
+	if a.Session.ID == "" || a.compactSuggested == a.Session.ID {
+		return nil
+	}
+	a.compactSuggested = a.Session.ID
+	return toast.NewWarningToast(
+		fmt.Sprintf("The context window is %.0f%% full. Run /compact to summarize the session before the model loses track of it.", share*100),
+		toast.WithTitle("Context almost full"),
+	)
+}
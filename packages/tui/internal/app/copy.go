@@ -0,0 +1,151 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// CopyMode is what of a message is copied
+type CopyMode string
+
+const (
+	CopyMarkdown CopyMode = "markdown" // The raw markdown
+	CopyText     CopyMode = "text"     // The text as rendered, without styling
+	CopyCode     CopyMode = "code"     // Only the code blocks, one after another
+)
+
+// copyModeNames name the modes in toasts
+var copyModeNames = map[CopyMode]string{
+	CopyMarkdown: "Markdown",
+	CopyText:     "Text",
+	CopyCode:     "Code",
+}
+
+// MessageText returns the text of a message's parts, without the synthetic
+// ones the server adds
+func MessageText(message Message) string {
+	var texts []string
+	for _, part := range message.Parts {
+		if text, ok := part.(opencode.TextPart); ok && !text.Synthetic && strings.TrimSpace(text.Text) != "" {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// CodeBlock is a fenced code block of markdown
+type CodeBlock struct {
+	Lang string // First word of the info string after the opening fence
+	Code string
+}
+
+// CodeBlocks returns the contents of the fenced code blocks of markdown. A
+// block left open runs to the end.
+func CodeBlocks(markdown string) []string {
+	var blocks []string
+	for _, block := range FencedBlocks(markdown) {
+		blocks = append(blocks, block.Code)
+	}
+	return blocks
+}
+
+// FencedBlocks returns the fenced code blocks of markdown with their
+// language. A block left open runs to the end.
+func FencedBlocks(markdown string) []CodeBlock {
+	var blocks []CodeBlock
+	var block []string
+	fence, lang := "", ""
+	for line := range strings.Lines(markdown) {
+		line = strings.TrimRight(line, "\r\n")
+		trimmed := strings.TrimLeft(line, " ")
+		if fence == "" {
+			for _, marker := range []string{"```", "~~~"} {
+				if strings.HasPrefix(trimmed, marker) {
+					info := strings.TrimLeft(trimmed, marker[:1])
+					fence = trimmed[:len(trimmed)-len(info)]
+					lang = ""
+					if fields := strings.Fields(info); len(fields) > 0 {
+						lang = strings.ToLower(fields[0])
+					}
+					block = nil
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, fence) && strings.TrimSpace(strings.TrimLeft(trimmed, fence[:1])) == "" {
+			blocks = append(blocks, CodeBlock{Lang: lang, Code: strings.Join(block, "\n")})
+			fence = ""
+			continue
+		}
+		block = append(block, line)
+	}
+	if fence != "" {
+		blocks = append(blocks, CodeBlock{Lang: lang, Code: strings.Join(block, "\n")})
+	}
+	return blocks
+}
+
+// RenderedText renders markdown as the messages show it, without styling
+// or trailing spaces
+func RenderedText(markdown string, width int) string {
+	rendered := ansi.Strip(util.ToMarkdown(markdown, width, theme.CurrentTheme().Background()))
+	lines := strings.Split(rendered, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// CopyContent returns what is copied of a message in a mode
+func CopyContent(message Message, mode CopyMode) (string, error) {
+	text := MessageText(message)
+	if text == "" {
+		return "", fmt.Errorf("the message has no text")
+	}
+	switch mode {
+	case CopyText:
+		return RenderedText(text, layout.Current.Container.Width), nil
+	case CopyCode:
+		blocks := CodeBlocks(text)
+		if len(blocks) == 0 {
+			return "", fmt.Errorf("the message has no code blocks")
+		}
+		return strings.Join(blocks, "\n\n"), nil
+	}
+	return text, nil
+}
+
+// CopyMessage copies a message to the clipboard, both natively and with
+// OSC52
+func (a *App) CopyMessage(message Message, mode CopyMode) tea.Cmd {
+	content, err := CopyContent(message, mode)
+	if err != nil {
+		return toast.NewErrorToast("Nothing to copy: " + err.Error())
+	}
+	return tea.Sequence(
+		SetClipboard(content),
+		toast.NewSuccessToast(copyModeNames[mode]+" copied to clipboard"),
+	)
+}
+
+// CopyLastMessage copies the last message that has something to copy in a
+// mode: text, or code blocks when only the code is copied
+func (a *App) CopyLastMessage(mode CopyMode) tea.Cmd {
+	for i := len(a.Messages) - 1; i >= 0; i-- {
+		if _, err := CopyContent(a.Messages[i], mode); err == nil {
+			return a.CopyMessage(a.Messages[i], mode)
+		}
+	}
+	if mode == CopyCode {
+		return toast.NewErrorToast("No message has code blocks to copy")
+	}
+	return toast.NewErrorToast("No message to copy")
+}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+func TestCopyContent(t *testing.T) {
+	answer := "Run the build:\n\n```sh\ngo build ./...\n```\n\nThen, in `main.go`:\n\n" +
+		"  ````go\n  func main() {\n  \t```\n  }\n  ````\n\n~~~\nunterminated"
+	message := Message{Parts: []opencode.PartUnion{
+		opencode.TextPart{Text: "context the server added", Synthetic: true},
+		opencode.TextPart{Text: answer},
+	}}
+
+	blocks := CodeBlocks(answer)
+	want := []string{"go build ./...", "  func main() {\n  \t```\n  }", "unterminated"}
+	if !slices.Equal(blocks, want) {
+		t.Errorf("code blocks = %q, want %q", blocks, want)
+	}
+
+	if content, _ := CopyContent(message, CopyMarkdown); content != answer {
+		t.Errorf("markdown = %q, want the text without synthetic parts", content)
+	}
+	if content, _ := CopyContent(message, CopyCode); content != "go build ./...\n\n  func main() {\n  \t```\n  }\n\nunterminated" {
+		t.Errorf("code = %q", content)
+	}
+	prose := Message{Parts: []opencode.PartUnion{opencode.TextPart{Text: "No code here."}}}
+	if _, err := CopyContent(prose, CopyCode); err == nil {
+		t.Error("copied the code of a message without code blocks")
+	}
+}
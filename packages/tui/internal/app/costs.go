@@ -0,0 +1,109 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/auth"
+	"github.com/aaronmrosenthal/rycode/internal/intelligence"
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// CostSummaryMsg is sent with the auth bridge's cost summary
+type CostSummaryMsg struct {
+	Summary *auth.CostSummary
+	Err     error
+}
+
+// CostsExportedMsg is sent when the cost records have been exported
+type CostsExportedMsg struct {
+	Path string
+	Err  error
+}
+
+// CostsPath returns the file every response's cost is recorded in
+func (a *App) CostsPath() string {
+	return filepath.Join(a.InsightsDir(), "costs.jsonl")
+}
+
+// CostLedger returns the cost of every recorded response, loading it on
+// first use with the retention of the usage
+func (a *App) CostLedger() *intelligence.CostLedger {
+	if a.costLedger == nil {
+		retention := intelligence.DefaultUsageRetentionDays
+		if a.State.UsageRetentionDays != nil {
+			retention = *a.State.UsageRetentionDays
+		}
+		ledger, err := intelligence.LoadCostLedger(a.CostsPath(), retention)
+		if err != nil {
+			slog.Warn("Failed to load costs", "error", err)
+		}
+		a.costLedger = ledger
+	}
+	return a.costLedger
+}
+
+// recordCost adds the cost of a completed response to the ledger
+func (a *App) recordCost(message opencode.AssistantMessage) {
+	title := ""
+	if a.Session != nil && a.Session.ID == message.SessionID {
+		title = a.Session.Title
+	}
+	tokens := message.Tokens
+	err := a.CostLedger().Add(intelligence.CostRecord{
+		Time:            time.UnixMilli(int64(message.Time.Completed)),
+		SessionID:       message.SessionID,
+		SessionTitle:    title,
+		ProviderID:      message.ProviderID,
+		ModelID:         message.ModelID,
+		Cost:            message.Cost,
+		InputTokens:     int64(tokens.Input),
+		OutputTokens:    int64(tokens.Output),
+		ReasoningTokens: int64(tokens.Reasoning),
+		CacheRead:       int64(tokens.Cache.Read),
+		CacheWrite:      int64(tokens.Cache.Write),
+		Feature:         string(a.backgroundFeatureOf(message)),
+	})
+	if err != nil {
+		slog.Warn("Failed to record cost", "error", err)
+	}
+}
+
+// FetchCostSummary asks the auth bridge for today's and the month's cost
+func (a *App) FetchCostSummary() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.TimeoutContext(TimeoutStatus)
+		defer cancel()
+		summary, err := a.AuthBridge.GetCostSummary(ctx)
+		return CostSummaryMsg{Summary: summary, Err: err}
+	}
+}
+
+// ExportCosts writes every cost record as CSV to the export directory
+func (a *App) ExportCosts() tea.Cmd {
+	records := a.CostLedger().Records()
+	return func() tea.Msg {
+		if len(records) == 0 {
+			return CostsExportedMsg{Err: fmt.Errorf("no costs recorded yet")}
+		}
+		dir := filepath.Join(util.RootPath, ExportDir)
+		if err := remote.MkdirAll(dir, 0755); err != nil {
+			return CostsExportedMsg{Err: fmt.Errorf("failed to create export directory: %w", err)}
+		}
+		path := filepath.Join(dir, "costs-"+time.Now().Format("20060102-150405")+".csv")
+		var data bytes.Buffer
+		if err := intelligence.WriteCostCSV(&data, records); err != nil {
+			return CostsExportedMsg{Err: fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)}
+		}
+		if err := remote.WriteFile(path, data.Bytes(), 0644); err != nil {
+			return CostsExportedMsg{Err: fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)}
+		}
+		return CostsExportedMsg{Path: path}
+	}
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+	"github.com/aaronmrosenthal/rycode/internal/transcript"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// DatasetExportedMsg is sent when sessions have been exported as a
+// fine-tuning dataset
+type DatasetExportedMsg struct {
+	Path     string
+	Records  int
+	Skipped  int // Sessions without an answer to learn from
+	Redacted int // Secrets, paths, user names and contacts masked
+	Err      error
+}
+
+// ExportDataset writes sessions as a JSONL dataset of training records for
+// fine-tuning or evals, in the style named first in args. The sessions are
+// the current one, the IDs named after the style, or every session for
+// "all". Every secret, home directory path, user name and contact found is
+// masked, as datasets leave the machine unreviewed.
+func (a *App) ExportDataset(args string) tea.Cmd {
+	fields := strings.Fields(args)
+	style := transcript.DatasetOpenAI
+	if len(fields) > 0 {
+		if parsed, err := transcript.ParseDatasetStyle(fields[0]); err == nil {
+			style, fields = parsed, fields[1:]
+		}
+	}
+	sessionIDs := fields
+	all := len(fields) == 1 && strings.EqualFold(fields[0], "all")
+	if len(sessionIDs) == 0 {
+		if a.Session.ID == "" {
+			return util.CmdHandler(DatasetExportedMsg{Err: fmt.Errorf("no active session to export")})
+		}
+		sessionIDs = []string{a.Session.ID}
+	}
+	return func() tea.Msg {
+		ctx := context.Background()
+		if all {
+			sessions, err := a.ListSessions(ctx)
+			if err != nil {
+				return DatasetExportedMsg{Err: fmt.Errorf("failed to list sessions: %w", err)}
+			}
+			sessionIDs = nil
+			for _, session := range sessions {
+				sessionIDs = append(sessionIDs, session.ID)
+			}
+		}
+
+		var transcripts []*transcript.Transcript
+		skipped, redacted := 0, 0
+		usernames := localUsernames()
+		for _, sessionID := range sessionIDs {
+			t, err := a.sessionTranscript(ctx, sessionID)
+			if err != nil {
+				// Sessions without messages are left out of a dataset of many
+				if all || len(sessionIDs) > 1 {
+					skipped++
+					continue
+				}
+				return DatasetExportedMsg{Err: err}
+			}
+			findings := t.Findings(usernames...)
+			redacted += len(findings)
+			transcripts = append(transcripts, t.Redact(findings))
+		}
+
+		data, left, err := transcript.Dataset(style, transcripts)
+		if err != nil {
+			return DatasetExportedMsg{Err: err}
+		}
+		skipped += left
+		records := len(transcripts) - left
+		if records == 0 {
+			return DatasetExportedMsg{Skipped: skipped, Err: fmt.Errorf("no session has an answer to learn from")}
+		}
+		dir := filepath.Join(util.RootPath, ExportDir)
+		if err := remote.MkdirAll(dir, 0755); err != nil {
+			return DatasetExportedMsg{Err: fmt.Errorf("failed to create export directory: %w", err)}
+		}
+		path := filepath.Join(dir, fmt.Sprintf("dataset-%s-%s.jsonl", style, time.Now().Format("20060102-150405")))
+		if err := remote.WriteFile(path, data, 0644); err != nil {
+			return DatasetExportedMsg{Err: fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)}
+		}
+		return DatasetExportedMsg{Path: path, Records: records, Skipped: skipped, Redacted: redacted}
+	}
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"os"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// ThemeFont turns off text decorations of a theme, for terminals whose
+// fonts render them poorly
+type ThemeFont struct {
+	NoBold   bool `toml:"no_bold,omitempty"`
+	NoItalic bool `toml:"no_italic,omitempty"`
+}
+
+// ASCIIEnv names the environment variable that turns ASCII-only mode on, or
+// off, whatever the locale
+const ASCIIEnv = "RYCODE_ASCII"
+
+// PlainEnv reports whether the environment asks for plain text: NO_COLOR
+// set to anything, or a dumb terminal
+func PlainEnv() bool {
+	return os.Getenv("NO_COLOR") != "" || strings.TrimSpace(os.Getenv("TERM")) == "dumb"
+}
+
+// asciiOnly reports whether only ASCII should be drawn: RYCODE_ASCII asks
+// for it, or the locale's character set isn't UTF-8, or colors are off too
+func asciiOnly(plain bool) bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(ASCIIEnv))) {
+	case "1", "true", "on":
+		return true
+	case "0", "false", "off":
+		return false
+	}
+	return plain || !utf8Locale()
+}
+
+// utf8Locale reports whether the locale's character set is UTF-8, from the
+// first of LC_ALL, LC_CTYPE and LANG that is set. Without any, the C locale
+// is ASCII; Windows consoles have no locale variables and Windows Terminal
+// draws UTF-8.
+func utf8Locale() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			locale = strings.ToLower(locale)
+			return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+		}
+	}
+	return runtime.GOOS == "windows" && os.Getenv("WT_SESSION") != ""
+}
+
+// Font returns the decorations turned off for the current theme
+func (a *App) Font() ThemeFont {
+	return a.State.ThemeFonts[theme.CurrentThemeName()]
+}
+
+// SetFont turns decorations of the current theme off or back on
+func (a *App) SetFont(font ThemeFont) tea.Cmd {
+	name := theme.CurrentThemeName()
+	if font == (ThemeFont{}) {
+		delete(a.State.ThemeFonts, name)
+		return a.SaveState()
+	}
+	if a.State.ThemeFonts == nil {
+		a.State.ThemeFonts = make(map[string]ThemeFont)
+	}
+	a.State.ThemeFonts[name] = font
+	return a.SaveState()
+}
+
+// FilterView takes the decorations the current theme turns off out of a
+// rendered frame, its colors in plain mode, and the symbols that need more
+// than ASCII in ASCII-only mode
+func (a *App) FilterView(view string) string {
+	font := a.Font()
+	view = util.FilterSGR(view, !a.Plain, !font.NoBold, !font.NoItalic)
+	if util.ASCII {
+		view = util.ASCIISymbols(view)
+	}
+	return view
+}
@@ -0,0 +1,28 @@
+package app
+
+import "testing"
+
+func TestASCIIOnly(t *testing.T) {
+	cases := []struct {
+		lcAll, lang, env string
+		plain            bool
+		want             bool
+	}{
+		{lang: "en_US.UTF-8", want: false},
+		{lang: "en_US.utf8", want: false},
+		{lang: "C", want: true},
+		{lcAll: "POSIX", lang: "en_US.UTF-8", want: true},
+		{lang: "en_US.UTF-8", plain: true, want: true},
+		{lang: "C", env: "off", want: false},
+		{lang: "en_US.UTF-8", env: "1", want: true},
+	}
+	for _, c := range cases {
+		t.Setenv("LC_ALL", c.lcAll)
+		t.Setenv("LC_CTYPE", "")
+		t.Setenv("LANG", c.lang)
+		t.Setenv(ASCIIEnv, c.env)
+		if got := asciiOnly(c.plain); got != c.want {
+			t.Errorf("asciiOnly(%v) with LC_ALL=%q LANG=%q %s=%q = %v, want %v", c.plain, c.lcAll, c.lang, ASCIIEnv, c.env, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+// duplicateWindow is how far back sessions are compared with a new one
+const duplicateWindow = 7 * 24 * time.Hour
+
+// duplicateThreshold is the share of a session title's words a new prompt
+// has to share for the two to be taken as the same task
+const duplicateThreshold = 0.75
+
+// maxMergeBytes bounds the conversation carried into the session merged into
+const maxMergeBytes = 60_000
+
+// DuplicateSessionMsg is sent when a session just started looks like the
+// same task as an earlier one
+type DuplicateSessionMsg struct {
+	SessionID string
+	Match     opencode.Session
+}
+
+// SessionMergedMsg is sent when a session has been merged into another
+type SessionMergedMsg struct {
+	From opencode.Session
+	Into opencode.Session
+	Err  error
+}
+
+// FindDuplicateSession looks for a recent session whose title matches the
+// first prompt of a session just started
+func (a *App) FindDuplicateSession(sessionID, prompt string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := a.TimeoutContext(TimeoutAPI)
+		defer cancel()
+		sessions, err := a.ListSessions(ctx)
+		if err != nil {
+			slog.Debug("Failed to list sessions for duplicates", "error", err)
+			return nil
+		}
+		cutoff := time.Now().Add(-duplicateWindow)
+		for _, session := range MergeCandidates(sessions, sessionID) {
+			if time.UnixMilli(int64(session.Time.Updated)).Before(cutoff) {
+				break
+			}
+			if sameTask(session.Title, prompt) {
+				return DuplicateSessionMsg{SessionID: sessionID, Match: session}
+			}
+		}
+		return nil
+	}
+}
+
+// MergeCandidates returns the sessions another can be merged into or linked
+// with: those other than it and its subagents' sessions, most recently
+// updated first
+func MergeCandidates(sessions []opencode.Session, sessionID string) []opencode.Session {
+	var candidates []opencode.Session
+	for _, session := range sessions {
+		if session.ID != sessionID && session.ParentID == "" {
+			candidates = append(candidates, session)
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b opencode.Session) int {
+		switch {
+		case a.Time.Updated > b.Time.Updated:
+			return -1
+		case a.Time.Updated < b.Time.Updated:
+			return 1
+		}
+		return 0
+	})
+	return candidates
+}
+
+// stopWords are left out when comparing a title with a prompt
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true, "into": true,
+	"this": true, "that": true, "are": true, "was": true, "can": true, "you": true,
+	"please": true, "our": true, "its": true, "not": true, "but": true,
+}
+
+// taskWords returns the words of a text that say what it is about,
+// lowercased and without plural s
+func taskWords(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(word) < 3 || stopWords[word] {
+			continue
+		}
+		if len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+			word = word[:len(word)-1]
+		}
+		words[word] = true
+	}
+	return words
+}
+
+// sameTask reports whether a prompt is about what a session's title says.
+// Titles of a single word are too vague to tell.
+func sameTask(title, prompt string) bool {
+	titleWords := taskWords(title)
+	if len(titleWords) < 2 {
+		return false
+	}
+	promptWords := taskWords(prompt)
+	shared := 0
+	for word := range titleWords {
+		if promptWords[word] {
+			shared++
+		}
+	}
+	return float64(shared)/float64(len(titleWords)) >= duplicateThreshold
+}
+
+// RelatedSessions returns the IDs of the sessions linked with a session
+func (a *App) RelatedSessions(sessionID string) []string {
+	return a.State.RelatedSessions[sessionID]
+}
+
+// LinkSessions links two sessions as related, both ways
+func (a *App) LinkSessions(first, second string) tea.Cmd {
+	a.linkSessions(first, second)
+	return a.SaveState()
+}
+
+func (a *App) linkSessions(first, second string) {
+	if first == second {
+		return
+	}
+	if a.State.RelatedSessions == nil {
+		a.State.RelatedSessions = make(map[string][]string)
+	}
+	for _, pair := range [][2]string{{first, second}, {second, first}} {
+		if !slices.Contains(a.State.RelatedSessions[pair[0]], pair[1]) {
+			a.State.RelatedSessions[pair[0]] = append(a.State.RelatedSessions[pair[0]], pair[1])
+		}
+	}
+}
+
+// MergeSession carries the conversation of a session into another, which
+// the agent acknowledges, then deletes it. The server can't move messages
+// between sessions, so the conversation is added to the other session as
+// one message.
+func (a *App) MergeSession(from, into opencode.Session) tea.Cmd {
+	providerID, modelID := a.Provider.ID, a.Model.ID
+	agent := a.Agent().Name
+
+	return func() tea.Msg {
+		ctx := context.Background()
+		t, err := a.sessionTranscript(ctx, from.ID)
+		if err != nil {
+			return SessionMergedMsg{From: from, Into: into, Err: err}
+		}
+		text := fmt.Sprintf(
+			"This task was also worked on in a separate session, %q, which is now merged into this one. Its conversation follows. Take it into account from here on, and reply with one sentence saying what it adds.\n\n%s",
+			t.Title(),
+			t.Conversation(maxMergeBytes),
+		)
+		_, err = a.Client.Session.Prompt(ctx, into.ID, opencode.SessionPromptParams{
+			Model: opencode.F(opencode.SessionPromptParamsModel{
+				ProviderID: opencode.F(providerID),
+				ModelID:    opencode.F(modelID),
+			}),
+			Agent: opencode.F(agent),
+			Parts: opencode.F([]opencode.SessionPromptParamsPartUnion{
+				opencode.TextPartInputParam{
+					Type: opencode.F(opencode.TextPartInputTypeText),
+					Text: opencode.F(text),
+				},
+			}),
+		})
+		if err != nil {
+			return SessionMergedMsg{From: from, Into: into, Err: fmt.Errorf("failed to carry the conversation over: %w", err)}
+		}
+		if err := a.DeleteSession(ctx, from.ID); err != nil {
+			return SessionMergedMsg{From: from, Into: into, Err: fmt.Errorf("merged, but failed to delete the session: %w", err)}
+		}
+		return SessionMergedMsg{From: from, Into: into}
+	}
+}
+
+// ApplySessionMerge gives what the TUI kept about a merged session to the
+// one it was merged into: its cost, links and pin
+func (a *App) ApplySessionMerge(msg SessionMergedMsg) tea.Cmd {
+	if _, err := a.CostLedger().MoveSession(msg.From.ID, msg.Into.ID, msg.Into.Title); err != nil {
+		slog.Warn("Failed to move the merged session's cost", "error", err)
+	}
+	for _, related := range a.State.RelatedSessions[msg.From.ID] {
+		a.linkSessions(msg.Into.ID, related)
+		a.State.RelatedSessions[related] = slices.DeleteFunc(a.State.RelatedSessions[related], func(id string) bool {
+			return id == msg.From.ID
+		})
+	}
+	delete(a.State.RelatedSessions, msg.From.ID)
+	if i := slices.Index(a.State.PinnedSessions, msg.From.ID); i >= 0 {
+		a.State.PinnedSessions = slices.Delete(a.State.PinnedSessions, i, i+1)
+		if !a.SessionPinned(msg.Into.ID) {
+			a.State.PinnedSessions = append(a.State.PinnedSessions, msg.Into.ID)
+		}
+	}
+	delete(a.State.SessionDirs, msg.From.ID)
+	delete(a.State.SessionSystem, msg.From.ID)
+	return a.SaveState()
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+func TestSameTask(t *testing.T) {
+	cases := []struct {
+		title, prompt string
+		want          bool
+	}{
+		{"Fix flaky upload retries", "the upload retry is flaky again, can you fix it", true},
+		{"Add dark mode toggle", "add a toggle for dark mode to the settings page", true},
+		{"Add dark mode toggle", "add a toggle for the sidebar", false},
+		{"Refactor", "refactor the auth bridge", false},
+		{"Untitled", "anything", false},
+	}
+	for _, c := range cases {
+		if got := sameTask(c.title, c.prompt); got != c.want {
+			t.Errorf("sameTask(%q, %q) = %v, want %v", c.title, c.prompt, got, c.want)
+		}
+	}
+}
+
+func TestApplySessionMerge(t *testing.T) {
+	a := &App{State: &State{PinnedSessions: []string{"ses_old"}}}
+	a.linkSessions("ses_old", "ses_other")
+	a.linkSessions("ses_old", "ses_new")
+
+	a.ApplySessionMerge(SessionMergedMsg{
+		From: opencode.Session{ID: "ses_old"},
+		Into: opencode.Session{ID: "ses_new", Title: "Uploads"},
+	})
+	if related := a.RelatedSessions("ses_new"); !slices.Equal(related, []string{"ses_other"}) {
+		t.Errorf("related to the merged session = %q, want ses_other", related)
+	}
+	if related := a.RelatedSessions("ses_other"); !slices.Equal(related, []string{"ses_new"}) {
+		t.Errorf("links to the session merged away weren't moved: %q", related)
+	}
+	if !slices.Equal(a.State.PinnedSessions, []string{"ses_new"}) {
+		t.Errorf("pinned = %q, want the pin moved", a.State.PinnedSessions)
+	}
+}
@@ -0,0 +1,205 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/components/diff"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+	"github.com/aaronmrosenthal/rycode/internal/watch"
+)
+
+// editBase is the file an edit permission changes, as it was when the edit
+// was asked for. The server computed the edit from it and would write it
+// back, losing whatever else changed the file since.
+type editBase struct {
+	path    string
+	content string
+	changed bool           // The file was seen changing, and it was reported
+	watcher *watch.Watcher // Nil in remote mode, where the file is compared when the edit is applied
+}
+
+// EditBaseChangedMsg is sent when the directory of a file an edit waits to
+// change changes
+type EditBaseChangedMsg struct {
+	PermissionID string
+}
+
+// EditConflictResolvedMsg is sent when the changes on disk are kept over an
+// edit touching the same lines, or overwritten by it
+type EditConflictResolvedMsg struct {
+	Conflict  *EditConflict
+	Overwrite bool
+}
+
+// EditConflict is an edit whose file was changed on disk while the edit
+// waited for review
+type EditConflict struct {
+	PermissionID string
+	FilePath     string
+	Base         string         // The file as the edit was asked for
+	Current      string         // The file as it is now
+	Hunks        []diff.Hunk    // The edit's hunks that are to be applied
+	Rejected     []diff.Hunk    // The edit's hunks rejected in review
+	Changes      []diff.Hunk    // What changed in the file since the edit was asked for
+	Rebased      []diff.Hunk    // The hunks moved onto the file as it is now
+	Overlaps     []diff.Overlap // Hunks changing lines that changed since, which can't be moved
+}
+
+// TrackEditBase keeps the file an edit permission changes as it is now, and
+// watches it for changes until the permission is answered
+func (a *App) TrackEditBase(permission opencode.Permission) tea.Cmd {
+	filePath, _ := permission.Metadata["filePath"].(string)
+	if permission.Type != "edit" || filePath == "" {
+		return nil
+	}
+	data, err := remote.ReadFile(filePath)
+	if err != nil {
+		// A file the edit creates has nothing to lose
+		return nil
+	}
+	base := &editBase{path: filePath, content: string(data)}
+	if remote.Active() == nil {
+		// Editors save by replacing the file, so its directory is watched
+		if base.watcher, err = watch.NewWatcher(util.RootPath, []string{filepath.Dir(filePath)}); err != nil {
+			slog.Warn("Failed to watch edited file", "file", filePath, "error", err)
+		}
+	}
+	if a.editBases == nil {
+		a.editBases = make(map[string]*editBase)
+	}
+	a.DropEditBase(permission.ID)
+	a.editBases[permission.ID] = base
+	return waitEditBase(permission.ID, base)
+}
+
+// waitEditBase waits for the next change to the directory of an edit's file
+func waitEditBase(permissionID string, base *editBase) tea.Cmd {
+	if base.watcher == nil {
+		return nil
+	}
+	watcher := base.watcher
+	return func() tea.Msg {
+		if watcher.Wait() {
+			return EditBaseChangedMsg{PermissionID: permissionID}
+		}
+		return nil
+	}
+}
+
+// EditBaseChanged warns, once, that the file of an edit waiting for review
+// was changed on disk, and keeps watching it
+func (a *App) EditBaseChanged(msg EditBaseChangedMsg) tea.Cmd {
+	base := a.editBases[msg.PermissionID]
+	if base == nil {
+		return nil
+	}
+	wait := waitEditBase(msg.PermissionID, base)
+	if base.changed {
+		return wait
+	}
+	data, err := remote.ReadFile(base.path)
+	if err != nil || string(data) == base.content {
+		return wait
+	}
+	base.changed = true
+	slog.Info("File of a pending edit changed on disk", "file", base.path, "permission", msg.PermissionID)
+	return tea.Batch(wait, toast.NewWarningToast(
+		"The edit will be moved onto your changes when it is accepted, or compared with them where they touch the same lines",
+		toast.WithTitle(util.Relative(base.path)+" changed on disk"),
+	))
+}
+
+// EditBaseChangedOnDisk reports whether the file of an edit permission was
+// seen changing while the edit waits
+func (a *App) EditBaseChangedOnDisk(permissionID string) bool {
+	base := a.editBases[permissionID]
+	return base != nil && base.changed
+}
+
+// DropEditBase stops watching the file of an answered edit permission
+func (a *App) DropEditBase(permissionID string) {
+	base := a.editBases[permissionID]
+	if base == nil {
+		return
+	}
+	if base.watcher != nil {
+		base.watcher.Close()
+	}
+	delete(a.editBases, permissionID)
+}
+
+// EditConflict compares the file of an edit under review with the file
+// the edit was computed from, returning nil when it didn't change
+func (a *App) EditConflict(review *EditReview) *EditConflict {
+	base := a.editBases[review.PermissionID]
+	if base == nil {
+		return nil
+	}
+	data, err := remote.ReadFile(base.path)
+	if err != nil || string(data) == base.content {
+		return nil
+	}
+	accepted, rejected := review.Split()
+	conflict := &EditConflict{
+		PermissionID: review.PermissionID,
+		FilePath:     review.FilePath,
+		Base:         base.content,
+		Current:      string(data),
+		Hunks:        accepted,
+		Rejected:     rejected,
+		Changes:      diff.Changes(base.content, string(data)),
+	}
+	conflict.Rebased, conflict.Overlaps = diff.Rebase(accepted, conflict.Changes)
+	return conflict
+}
+
+// ApplyRebasedEdit applies an edit moved onto the file's changes and tells
+// the session the file changed. Its permission is rejected, as the server
+// would write the edit over the changes.
+func (a *App) ApplyRebasedEdit(conflict *EditConflict) (*App, tea.Cmd) {
+	content, err := diff.ApplyHunks(conflict.Current, conflict.Rebased)
+	if err != nil {
+		return a, toast.NewErrorToast(err.Error(), toast.WithTitle("Edit not applied"))
+	}
+	info, err := remote.Stat(conflict.FilePath)
+	if err != nil {
+		return a, toast.NewErrorToast(err.Error(), toast.WithTitle("Edit not applied"))
+	}
+	if err := remote.WriteFile(conflict.FilePath, []byte(content), info.Mode().Perm()); err != nil {
+		return a, toast.NewErrorToast(err.Error(), toast.WithTitle("Edit not applied"))
+	}
+	slog.Info("Applied an edit onto changes made on disk", "file", conflict.FilePath, "hunks", len(conflict.Rebased))
+
+	prompt := fmt.Sprintf(
+		"While your edit to %s waited for review I changed the file myself:\n\n```diff\n%s```\n\nI applied your edit on top of my changes.",
+		conflict.FilePath, diff.FormatHunks(conflict.Changes),
+	)
+	if len(conflict.Rejected) > 0 {
+		prompt += fmt.Sprintf(" These hunks of it were rejected and are not in the file:\n\n```diff\n%s```\n\n", diff.FormatHunks(conflict.Rejected))
+	} else {
+		prompt += " "
+	}
+	prompt += "Read the file again before editing it further."
+	updated, cmd := a.SendPrompt(context.Background(), Prompt{Text: prompt})
+	return updated, tea.Batch(cmd, toast.NewSuccessToast(
+		fmt.Sprintf("%d hunks applied onto the changes on disk", len(conflict.Rebased)),
+		toast.WithTitle("Edit re-baselined"),
+	))
+}
+
+// KeepConflictingChanges tells the session an edit was rejected for the
+// changes made to its file since it was asked for
+func (a *App) KeepConflictingChanges(conflict *EditConflict) (*App, tea.Cmd) {
+	prompt := fmt.Sprintf(
+		"While your edit to %s waited for review I changed the same lines of the file myself, and kept my changes:\n\n```diff\n%s```\n\nYour edit was not applied. Read the file again before editing it.",
+		conflict.FilePath, diff.FormatHunks(conflict.Changes),
+	)
+	return a.SendPrompt(context.Background(), Prompt{Text: prompt})
+}
@@ -0,0 +1,336 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/components/diff"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// maxJournalEdits is the number of edits the journal keeps, across sessions
+const maxJournalEdits = 200
+
+// JournalEdit is a file change a tool call made, with the contents before
+// and after it so that it can be undone and redone in the worktree
+type JournalEdit struct {
+	SessionID string
+	MessageID string
+	PartID    string
+	Tool      string
+	Path      string // Absolute path of the file
+	Before    string
+	After     string
+	Created   bool // The file didn't exist before the edit
+	Undone    bool
+	ByRevert  bool // Undone because its message was reverted
+	Time      time.Time
+}
+
+// Stats counts the lines the edit added and removed
+func (e JournalEdit) Stats() diff.DiffStats {
+	return diff.ChangeStats(e.Before, e.After)
+}
+
+// DisplayPath returns the edit's file relative to the project when it's in
+// it
+func (e JournalEdit) DisplayPath() string {
+	if rel, err := filepath.Rel(util.RootPath, e.Path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return e.Path
+}
+
+// RecordEdit journals a completed edit tool call, reading what it left in
+// the file and reconstructing what was there before from its diff. Edits
+// that can't be reconstructed, as a write over an existing file, aren't
+// journaled.
+func (a *App) RecordEdit(part opencode.PartUnion) {
+	tool, ok := part.(opencode.ToolPart)
+	if !ok || tool.State.Status != opencode.ToolPartStateStatusCompleted || !slices.Contains(editTools, tool.Tool) {
+		return
+	}
+	if slices.ContainsFunc(a.editJournal, func(e JournalEdit) bool { return e.PartID == tool.ID }) {
+		return
+	}
+	input, _ := tool.State.Input.(map[string]any)
+	path, _ := input["filePath"].(string)
+	if path == "" {
+		return
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(util.RootPath, path)
+	}
+	after, err := remote.ReadFile(path)
+	if err != nil {
+		slog.Debug("Failed to read edited file", "file", path, "error", err)
+		return
+	}
+
+	edit := JournalEdit{
+		SessionID: tool.SessionID,
+		MessageID: tool.MessageID,
+		PartID:    tool.ID,
+		Tool:      tool.Tool,
+		Path:      path,
+		After:     string(after),
+		Time:      time.Now(),
+	}
+	metadata, _ := tool.State.Metadata.(map[string]any)
+	if exists, ok := metadata["exists"].(bool); ok && !exists {
+		edit.Created = true
+	} else {
+		diffs := editDiffs(metadata)
+		if len(diffs) == 0 {
+			return
+		}
+		// The diffs of a multiedit follow each other, so they're undone
+		// from the last
+		before := edit.After
+		for _, d := range slices.Backward(diffs) {
+			parsed, err := diff.ParseUnifiedDiff(d)
+			if err == nil {
+				before, err = diff.ApplyHunks(before, diff.ReverseHunks(parsed.Hunks))
+			}
+			if err != nil {
+				slog.Debug("Failed to reconstruct edited file", "file", path, "error", err)
+				return
+			}
+		}
+		edit.Before = before
+	}
+
+	a.editJournal = append(a.editJournal, edit)
+	if len(a.editJournal) > maxJournalEdits {
+		a.editJournal = a.editJournal[len(a.editJournal)-maxJournalEdits:]
+	}
+}
+
+// editDiffs returns the diffs of an edit tool's metadata, in the order they
+// were applied
+func editDiffs(metadata map[string]any) []string {
+	if d, ok := metadata["diff"].(string); ok && d != "" {
+		return []string{d}
+	}
+	results, _ := metadata["results"].([]any)
+	var diffs []string
+	for _, result := range results {
+		result, _ := result.(map[string]any)
+		if d, ok := result["diff"].(string); ok && d != "" {
+			diffs = append(diffs, d)
+		}
+	}
+	return diffs
+}
+
+// JournalEdits returns the journaled edits of the current session, oldest
+// first
+func (a *App) JournalEdits() []JournalEdit {
+	var edits []JournalEdit
+	for _, edit := range a.editJournal {
+		if edit.SessionID == a.Session.ID {
+			edits = append(edits, edit)
+		}
+	}
+	return edits
+}
+
+// JournalPrompt returns the prompt the message of an edit answered, empty
+// when the message isn't loaded
+func (a *App) JournalPrompt(messageID string) string {
+	i := slices.IndexFunc(a.Messages, func(m Message) bool { return MessageID(m) == messageID })
+	for ; i >= 0; i-- {
+		if _, ok := a.Messages[i].Info.(opencode.UserMessage); ok {
+			return MessageText(a.Messages[i])
+		}
+	}
+	return ""
+}
+
+// journalIndex returns the index of an edit in the journal, by tool part
+func (a *App) journalIndex(partID string) int {
+	return slices.IndexFunc(a.editJournal, func(e JournalEdit) bool { return e.PartID == partID })
+}
+
+// errFileChanged is returned when a file no longer holds what an edit left
+// in it, or what was there before it
+var errFileChanged = errors.New("changed since the edit")
+
+// setEditUndone puts the file of the edit at index i back to its content
+// before the edit, or after it when undone is false. A file already in that
+// state is left as it is.
+func (a *App) setEditUndone(i int, undone bool) error {
+	edit := &a.editJournal[i]
+	if edit.Undone == undone {
+		return nil
+	}
+	// from is what the file holds now, to what it must hold; absent when
+	// it doesn't exist
+	from, fromAbsent := edit.After, false
+	to, toAbsent := edit.Before, edit.Created
+	if !undone {
+		from, fromAbsent, to, toAbsent = to, toAbsent, from, fromAbsent
+	}
+
+	content, err := remote.ReadFile(edit.Path)
+	absent := errors.Is(err, fs.ErrNotExist)
+	if err != nil && !absent {
+		return err
+	}
+	switch {
+	case absent == toAbsent && (absent || string(content) == to):
+		// The server's revert, or the user, got there first
+	case absent == fromAbsent && (absent || string(content) == from):
+		if toAbsent {
+			err = remote.Remove(edit.Path)
+		} else {
+			err = remote.WriteFile(edit.Path, []byte(to), 0o644)
+		}
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%s %w", edit.DisplayPath(), errFileChanged)
+	}
+	edit.Undone = undone
+	edit.ByRevert = false
+	return nil
+}
+
+// UndoFileEdits undoes an edit along with the later edits of its file,
+// latest first
+func (a *App) UndoFileEdits(partID string) tea.Cmd {
+	i := a.journalIndex(partID)
+	if i < 0 {
+		return nil
+	}
+	edit := a.editJournal[i]
+	var indices []int
+	for j := len(a.editJournal) - 1; j >= i; j-- {
+		if e := a.editJournal[j]; e.Path == edit.Path && !e.Undone {
+			indices = append(indices, j)
+		}
+	}
+	return a.applyJournal(indices, true, edit.DisplayPath())
+}
+
+// RedoFileEdits redoes an edit along with the earlier undone edits of its
+// file, oldest first
+func (a *App) RedoFileEdits(partID string) tea.Cmd {
+	i := a.journalIndex(partID)
+	if i < 0 {
+		return nil
+	}
+	edit := a.editJournal[i]
+	var indices []int
+	for j := 0; j <= i; j++ {
+		if e := a.editJournal[j]; e.Path == edit.Path && e.Undone {
+			indices = append(indices, j)
+		}
+	}
+	return a.applyJournal(indices, false, edit.DisplayPath())
+}
+
+// UndoMessageEdits undoes the edits a message made, latest first
+func (a *App) UndoMessageEdits(messageID string) tea.Cmd {
+	var indices []int
+	for j := len(a.editJournal) - 1; j >= 0; j-- {
+		if e := a.editJournal[j]; e.MessageID == messageID && !e.Undone {
+			indices = append(indices, j)
+		}
+	}
+	return a.applyJournal(indices, true, "the message")
+}
+
+// RedoMessageEdits redoes the undone edits of a message, oldest first
+func (a *App) RedoMessageEdits(messageID string) tea.Cmd {
+	var indices []int
+	for j, e := range a.editJournal {
+		if e.MessageID == messageID && e.Undone {
+			indices = append(indices, j)
+		}
+	}
+	return a.applyJournal(indices, false, "the message")
+}
+
+// applyJournal undoes or redoes the edits at indices in order, stopping at
+// the first file that can't be restored, and reports the outcome
+func (a *App) applyJournal(indices []int, undo bool, subject string) tea.Cmd {
+	action, done := "redo", "Redid"
+	if undo {
+		action, done = "undo", "Undid"
+	}
+	if len(indices) == 0 {
+		return toast.NewInfoToast("No edits of " + subject + " to " + action)
+	}
+	for n, i := range indices {
+		if err := a.setEditUndone(i, undo); err != nil {
+			title := "Couldn't " + action + " the edit"
+			if n > 0 {
+				title = fmt.Sprintf("%s %d of %d edits", done, n, len(indices))
+			}
+			return toast.NewErrorToast(err.Error(), toast.WithTitle(title))
+		}
+	}
+	return toast.NewSuccessToast(fmt.Sprintf("%s %d %s of %s", done, len(indices), plural(len(indices), "edit"), subject))
+}
+
+// SyncRevertedEdits brings the worktree in line with the session's revert:
+// the edits of the reverted messages are undone, and those the revert had
+// undone before it moved are redone. Files the server's revert restored
+// already are left as they are.
+func (a *App) SyncRevertedEdits() tea.Cmd {
+	reverted := make(map[string]bool)
+	if revertID := a.Session.Revert.MessageID; revertID != "" {
+		from := slices.IndexFunc(a.Messages, func(m Message) bool { return MessageID(m) == revertID })
+		if from >= 0 {
+			for _, m := range a.Messages[from:] {
+				reverted[MessageID(m)] = true
+			}
+		}
+	}
+
+	var failed []string
+	for i := len(a.editJournal) - 1; i >= 0; i-- {
+		edit := a.editJournal[i]
+		if edit.SessionID != a.Session.ID || edit.Undone || !reverted[edit.MessageID] {
+			continue
+		}
+		if err := a.setEditUndone(i, true); err != nil {
+			failed = append(failed, err.Error())
+			continue
+		}
+		a.editJournal[i].ByRevert = true
+	}
+	for i, edit := range a.editJournal {
+		if edit.SessionID != a.Session.ID || !edit.ByRevert || reverted[edit.MessageID] {
+			continue
+		}
+		if err := a.setEditUndone(i, false); err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	slog.Warn("Files kept through the revert", "files", failed)
+	return toast.NewErrorToast(failed[0], toast.WithTitle(fmt.Sprintf("Kept %d changed %s", len(failed), plural(len(failed), "file"))))
+}
+
+// plural returns noun, with an s unless n is 1
+func plural(n int, noun string) string {
+	if n == 1 {
+		return noun
+	}
+	return noun + "s"
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/components/diff"
+)
+
+func TestEditJournal(t *testing.T) {
+	dir := t.TempDir()
+	main := filepath.Join(dir, "main.go")
+	notes := filepath.Join(dir, "notes.md")
+	original := "package main\n\nfunc main() {\n}\n"
+	edited := "package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n"
+	if err := os.WriteFile(main, []byte(edited), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(notes, []byte("todo\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tool := func(id, name, file string, metadata map[string]any) opencode.ToolPart {
+		return opencode.ToolPart{ID: id, SessionID: "ses_1", MessageID: "msg_1", Tool: name, State: opencode.ToolPartState{
+			Status:   opencode.ToolPartStateStatusCompleted,
+			Input:    map[string]any{"filePath": file},
+			Metadata: metadata,
+		}}
+	}
+	a := &App{Session: &opencode.Session{ID: "ses_1"}}
+	a.RecordEdit(tool("prt_1", "edit", main, map[string]any{"diff": diff.GenerateUnifiedDiff(main, main, original, edited, 3)}))
+	a.RecordEdit(tool("prt_2", "write", notes, map[string]any{"exists": false}))
+	a.RecordEdit(tool("prt_3", "write", main, map[string]any{"exists": true}))
+	a.RecordEdit(tool("prt_1", "edit", main, map[string]any{"diff": diff.GenerateUnifiedDiff(main, main, original, edited, 3)}))
+
+	edits := a.JournalEdits()
+	if len(edits) != 2 {
+		t.Fatalf("journaled %d edits, want the edit and the creation once", len(edits))
+	}
+	if edits[0].Before != original {
+		t.Errorf("reconstructed %q before the edit, want %q", edits[0].Before, original)
+	}
+
+	read := func(path string) string {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "<absent>"
+		}
+		return string(content)
+	}
+	a.UndoMessageEdits("msg_1")
+	if got := read(main); got != original {
+		t.Errorf("after undo main.go = %q, want %q", got, original)
+	}
+	if got := read(notes); got != "<absent>" {
+		t.Errorf("after undo notes.md = %q, want the created file removed", got)
+	}
+
+	a.RedoFileEdits("prt_1")
+	if got := read(main); got != edited {
+		t.Errorf("after redo main.go = %q, want %q", got, edited)
+	}
+
+	// A file changed since the edit is kept
+	if err := os.WriteFile(main, []byte("changed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	a.UndoFileEdits("prt_1")
+	if got := read(main); got != "changed\n" {
+		t.Errorf("undo overwrote a changed file with %q", got)
+	}
+	if a.JournalEdits()[0].Undone {
+		t.Error("an edit whose file changed was marked undone")
+	}
+}
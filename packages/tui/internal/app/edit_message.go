@@ -0,0 +1,143 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/id"
+	"github.com/aaronmrosenthal/rycode/internal/transcript"
+)
+
+// MessageBranchedMsg is sent once the conversation before a message has
+// been read for a branch to start from it
+type MessageBranchedMsg struct {
+	From    opencode.Session
+	Message Message // User message the branch resends, edited
+	Carry   string  // Conversation before the message, carried into the branch
+	Err     error
+}
+
+// pendingBranch is a branch started from a message of another session,
+// which becomes a session once its first prompt is sent
+type pendingBranch struct {
+	from  string // ID of the session branched from
+	carry string
+}
+
+// errEditBusy is returned when a message is edited while a reply is on its
+// way, which reverting would cut short
+var errEditBusy = errors.New("wait for the reply to finish, or interrupt it, before editing a message")
+
+// EditMessage reverts the session to just before one of its user messages,
+// which the editor then holds to be edited and resent. Later messages, and
+// the changes their tools made, are reverted with it and dropped once the
+// edited message is sent; until then redo brings them back.
+func (a *App) EditMessage(message Message) (tea.Cmd, error) {
+	user, ok := message.Info.(opencode.UserMessage)
+	if !ok {
+		return nil, errors.New("only your own messages can be edited")
+	}
+	if a.IsBusy() {
+		return nil, errEditBusy
+	}
+	sessionID := a.Session.ID
+	return func() tea.Msg {
+		ctx, cancel := a.TimeoutContext(TimeoutAPI)
+		defer cancel()
+		session, err := a.Client.Session.Revert(ctx, sessionID, opencode.SessionRevertParams{
+			MessageID: opencode.F(user.ID),
+		})
+		if err != nil || session == nil {
+			slog.Error("Failed to revert for an edit", "error", err)
+			return toast.NewErrorToast("Failed to edit the message")()
+		}
+		return MessageRevertedMsg{Session: *session, Message: message}
+	}, nil
+}
+
+// BranchFromMessage starts a new session from one of the current session's
+// user messages, leaving the session as it is. The conversation before the
+// message is carried into the branch, and the message waits in the editor
+// to be edited and sent.
+func (a *App) BranchFromMessage(message Message) (tea.Cmd, error) {
+	user, ok := message.Info.(opencode.UserMessage)
+	if !ok {
+		return nil, errors.New("only your own messages can be branched from")
+	}
+	if a.IsBusy() {
+		return nil, errEditBusy
+	}
+	from := *a.Session
+	return func() tea.Msg {
+		ctx, cancel := a.TimeoutContext(TimeoutAPI)
+		defer cancel()
+		messages, err := a.ListMessages(ctx, from.ID)
+		if err != nil {
+			return MessageBranchedMsg{From: from, Message: message, Err: err}
+		}
+		return MessageBranchedMsg{From: from, Message: message, Carry: conversationBefore(from, messages, user.ID)}
+	}, nil
+}
+
+// conversationBefore returns the conversation of a session up to, but not
+// including, one of its messages
+func conversationBefore(session opencode.Session, messages []Message, messageID string) string {
+	t := transcript.New(session, time.Now())
+	for _, message := range messages {
+		if MessageID(message) == messageID {
+			break
+		}
+		t.Add(message.Info, message.Parts)
+	}
+	return t.Conversation(maxMergeBytes)
+}
+
+// StartBranch leaves the current session for a branch that becomes a
+// session, linked to the one it came from, when its first prompt is sent
+func (a *App) StartBranch(msg MessageBranchedMsg) {
+	a.Session = &opencode.Session{}
+	a.Messages = []Message{}
+	a.AppliedRule = nil
+	a.pendingBranch = &pendingBranch{from: msg.From.ID, carry: msg.Carry}
+}
+
+// DropBranch forgets a branch whose first prompt was never sent
+func (a *App) DropBranch() {
+	a.pendingBranch = nil
+}
+
+// adoptPendingBranch links a session just started to the session it
+// branched from, and adds the conversation carried over to its first
+// message, hidden from the chat as the server's own additions are
+func (a *App) adoptPendingBranch(message Message) Message {
+	branch := a.pendingBranch
+	if branch == nil {
+		return message
+	}
+	a.pendingBranch = nil
+	a.linkSessions(a.Session.ID, branch.from)
+	if err := a.saveStateNow(); err != nil {
+		slog.Error("Failed to save state", "error", err)
+	}
+	if branch.carry == "" {
+		return message
+	}
+	carry := opencode.TextPart{
+		ID:        id.Ascending(id.Part),
+		MessageID: MessageID(message),
+		SessionID: a.Session.ID,
+		Type:      opencode.TextPartTypeText,
+		Text: fmt.Sprintf(
+			"This session branches off an earlier conversation, which follows. Continue from it as if it had happened in this session; the rest of this message is the user's next message in it.\n\n%s",
+			branch.carry,
+		),
+		Synthetic: true,
+	}
+	message.Parts = append(message.Parts, carry)
+	return message
+}
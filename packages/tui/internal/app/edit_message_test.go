@@ -0,0 +1,47 @@
+package app
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+func TestBranchFromMessage(t *testing.T) {
+	text := func(info opencode.MessageUnion, id, text string) Message {
+		return Message{Info: info, Parts: []opencode.PartUnion{opencode.TextPart{ID: id + "_part", Type: opencode.TextPartTypeText, Text: text}}}
+	}
+	from := opencode.Session{ID: "ses_1", Title: "Parser"}
+	messages := []Message{
+		text(opencode.UserMessage{ID: "msg_1", Role: opencode.UserMessageRoleUser}, "msg_1", "Write a parser"),
+		text(opencode.AssistantMessage{ID: "msg_2", Role: opencode.AssistantMessageRoleAssistant}, "msg_2", "Here is a parser"),
+		text(opencode.UserMessage{ID: "msg_3", Role: opencode.UserMessageRoleUser}, "msg_3", "Make it faster"),
+	}
+	carry := conversationBefore(from, messages, "msg_3")
+	if !strings.Contains(carry, "Write a parser") || !strings.Contains(carry, "Here is a parser") || strings.Contains(carry, "Make it faster") {
+		t.Errorf("conversation before msg_3 = %q", carry)
+	}
+
+	a := &App{State: &State{}, StatePath: filepath.Join(t.TempDir(), "state.toml"), Session: &from, Messages: messages}
+	a.StartBranch(MessageBranchedMsg{From: from, Message: messages[2], Carry: carry})
+	if a.Session.ID != "" || len(a.Messages) != 0 {
+		t.Fatal("the branch didn't start empty")
+	}
+
+	a.Session = &opencode.Session{ID: "ses_2"}
+	sent := a.adoptPendingBranch(Prompt{Text: "Make it smaller"}.ToMessage("msg_4", "ses_2"))
+	if len(sent.Parts) != 2 || MessageText(sent) != "Make it smaller" {
+		t.Fatalf("branch's first message = %+v", sent.Parts)
+	}
+	if part := sent.Parts[1].(opencode.TextPart); !part.Synthetic || !strings.Contains(part.Text, "Write a parser") {
+		t.Errorf("carried conversation = %+v", part)
+	}
+	if !slices.Contains(a.State.RelatedSessions["ses_1"], "ses_2") {
+		t.Error("the branch isn't linked to its session")
+	}
+	if again := a.adoptPendingBranch(Prompt{Text: "More"}.ToMessage("msg_5", "ses_2")); len(again.Parts) != 1 {
+		t.Error("the conversation was carried twice")
+	}
+}
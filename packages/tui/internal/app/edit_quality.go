@@ -0,0 +1,165 @@
+package app
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/components/diff"
+	"github.com/aaronmrosenthal/rycode/internal/intelligence"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// defaultEditTrackingWindow is how long applied files are watched for manual
+// changes when the state doesn't configure a window
+const defaultEditTrackingWindow = 10 * time.Minute
+
+// EditWindowElapsedMsg is sent when the tracking window of an applied edit ends
+type EditWindowElapsedMsg struct {
+	File       string
+	Generation int
+}
+
+// appliedEdit is the file content the AI left behind, kept until the
+// tracking window ends
+type appliedEdit struct {
+	providerID string
+	modelID    string
+	content    string
+	appliedAt  time.Time
+	generation int
+}
+
+// EditQualityPath returns the file edit quality signals are saved to
+func (a *App) EditQualityPath() string {
+	return filepath.Join(a.InsightsDir(), "edit_quality.json")
+}
+
+// EditTrackingWindow returns how long applied files are watched. A zero
+// window disables tracking.
+func (a *App) EditTrackingWindow() time.Duration {
+	configured := strings.TrimSpace(a.State.EditTrackingWindow)
+	switch configured {
+	case "":
+		return defaultEditTrackingWindow
+	case "off", "0":
+		return 0
+	}
+	window, err := time.ParseDuration(configured)
+	if err != nil || window < 0 {
+		slog.Warn("Invalid edit tracking window", "window", configured)
+		return defaultEditTrackingWindow
+	}
+	return window
+}
+
+// TrackAppliedEdit snapshots a file the AI just edited and schedules a
+// measurement of the manual changes made to it within the tracking window.
+// A further AI edit to the same file restarts its window.
+func (a *App) TrackAppliedEdit(file string) tea.Cmd {
+	window := a.EditTrackingWindow()
+	if window == 0 {
+		return nil
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(util.RootPath, file)
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		slog.Debug("Failed to snapshot edited file", "file", file, "error", err)
+		return nil
+	}
+
+	providerID, modelID := a.lastResponseModel()
+	if a.appliedEdits == nil {
+		a.appliedEdits = make(map[string]*appliedEdit)
+	}
+	generation := 1
+	if previous, ok := a.appliedEdits[file]; ok {
+		generation = previous.generation + 1
+	}
+	a.appliedEdits[file] = &appliedEdit{
+		providerID: providerID,
+		modelID:    modelID,
+		content:    string(content),
+		appliedAt:  time.Now(),
+		generation: generation,
+	}
+
+	return tea.Tick(window, func(time.Time) tea.Msg {
+		return EditWindowElapsedMsg{File: file, Generation: generation}
+	})
+}
+
+// MeasureAppliedEdit records how much a tracked file was changed by hand
+// since the AI edited it
+func (a *App) MeasureAppliedEdit(msg EditWindowElapsedMsg) {
+	edit, ok := a.appliedEdits[msg.File]
+	if !ok || edit.generation != msg.Generation {
+		// The file was edited by the AI again and has a newer window
+		return
+	}
+	delete(a.appliedEdits, msg.File)
+
+	// A deleted file counts as fully reworked
+	current := ""
+	if content, err := os.ReadFile(msg.File); err == nil {
+		current = string(content)
+	}
+	stats := diff.ChangeStats(edit.content, current)
+
+	signal := intelligence.EditQualitySignal{
+		Provider:     edit.providerID,
+		Model:        edit.modelID,
+		File:         msg.File,
+		AppliedAt:    edit.appliedAt,
+		MeasuredAt:   time.Now(),
+		AppliedLines: strings.Count(edit.content, "\n") + 1,
+		ChangedLines: stats.Modified,
+	}
+	history, err := intelligence.LoadEditQualityHistory(a.EditQualityPath())
+	if err != nil {
+		slog.Warn("Failed to load edit quality history", "error", err)
+	}
+	history.Add(signal)
+	if err := history.Save(); err != nil {
+		slog.Error("Failed to save edit quality signal", "error", err)
+		return
+	}
+	slog.Debug("Recorded edit quality", "file", msg.File, "model", signal.Model, "churn", signal.Churn())
+}
+
+// minRatedEdits is how many measured edits of a model it takes for their
+// rework to count when a model is recommended
+const minRatedEdits = 5
+
+// RecommendationEngine returns a recommendation engine that has learnt from
+// the measured edits, an edit satisfying the user when little of it was
+// reworked
+func (a *App) RecommendationEngine() *intelligence.RecommendationEngine {
+	engine := intelligence.NewRecommendationEngine()
+	history, err := intelligence.LoadEditQualityHistory(a.EditQualityPath())
+	if err != nil {
+		slog.Warn("Failed to load edit quality history", "error", err)
+	}
+	engine.AddUsage(history.Usage()...)
+	return engine
+}
+
+// lastResponseModel returns the model of the latest assistant message, which
+// is the one applying edits while a response streams in
+func (a *App) lastResponseModel() (providerID, modelID string) {
+	for i := len(a.Messages) - 1; i >= 0; i-- {
+		if assistant, ok := a.Messages[i].Info.(opencode.AssistantMessage); ok {
+			return assistant.ProviderID, assistant.ModelID
+		}
+	}
+	if a.Provider != nil && a.Model != nil {
+		return a.Provider.ID, a.Model.ID
+	}
+	return "", ""
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/components/diff"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+)
+
+// EditReview is the review of an edit's hunks while its permission is
+// asked. Hunks are accepted unless rejected one by one.
+type EditReview struct {
+	PermissionID string
+	FilePath     string
+	Hunks        []diff.Hunk
+	Rejected     []bool
+	Current      int // Hunk the review is on
+}
+
+// EditReviewChangedMsg is sent when a hunk is selected, accepted or rejected
+type EditReviewChangedMsg struct{}
+
+// newEditReview starts the review of an edit permission, returning nil for
+// other permissions
+func newEditReview(permission opencode.Permission) *EditReview {
+	if permission.ID == "" || permission.Type != "edit" {
+		return nil
+	}
+	patch, _ := permission.Metadata["diff"].(string)
+	filePath, _ := permission.Metadata["filePath"].(string)
+	if patch == "" || filePath == "" {
+		return nil
+	}
+	result, err := diff.ParseUnifiedDiff(patch)
+	if err != nil || len(result.Hunks) == 0 {
+		return nil
+	}
+	return &EditReview{
+		PermissionID: permission.ID,
+		FilePath:     filePath,
+		Hunks:        result.Hunks,
+		Rejected:     make([]bool, len(result.Hunks)),
+	}
+}
+
+// EditReview returns the review of the current permission, or nil when it
+// doesn't ask to edit a file
+func (a *App) EditReview() *EditReview {
+	if a.editReview != nil && a.editReview.PermissionID == a.CurrentPermission.ID {
+		return a.editReview
+	}
+	return newEditReview(a.CurrentPermission)
+}
+
+// SetEditReview replaces the review of the current permission. Reviews are
+// replaced rather than changed, as messages are rendered in the background.
+func (a *App) SetEditReview(review *EditReview) {
+	a.editReview = review
+}
+
+func (r *EditReview) clone() *EditReview {
+	updated := *r
+	updated.Rejected = append([]bool(nil), r.Rejected...)
+	return &updated
+}
+
+// Move selects the hunk delta hunks away, stopping at the first and last
+func (r *EditReview) Move(delta int) *EditReview {
+	updated := r.clone()
+	updated.Current = max(0, min(len(r.Hunks)-1, r.Current+delta))
+	return updated
+}
+
+// Toggle accepts the selected hunk when it is rejected, or rejects it
+func (r *EditReview) Toggle() *EditReview {
+	updated := r.clone()
+	updated.Rejected[r.Current] = !r.Rejected[r.Current]
+	return updated
+}
+
+// Split returns the accepted and the rejected hunks
+func (r *EditReview) Split() (accepted, rejected []diff.Hunk) {
+	for i, hunk := range r.Hunks {
+		if r.Rejected[i] {
+			rejected = append(rejected, hunk)
+		} else {
+			accepted = append(accepted, hunk)
+		}
+	}
+	return accepted, rejected
+}
+
+// ApplyReviewedEdit applies the accepted hunks of a partly rejected edit to
+// the file and tells the session which hunks were left out. The server
+// applies an edit whole or not at all, so its permission is rejected and
+// the file is written here.
+func (a *App) ApplyReviewedEdit(review *EditReview) (*App, tea.Cmd) {
+	accepted, rejected := review.Split()
+	data, err := remote.ReadFile(review.FilePath)
+	if err != nil {
+		return a, toast.NewErrorToast(err.Error(), toast.WithTitle("Edit not applied"))
+	}
+	content, err := diff.ApplyHunks(string(data), accepted)
+	if err != nil {
+		return a, toast.NewErrorToast(err.Error(), toast.WithTitle("Edit not applied"))
+	}
+	info, err := remote.Stat(review.FilePath)
+	if err != nil {
+		return a, toast.NewErrorToast(err.Error(), toast.WithTitle("Edit not applied"))
+	}
+	if err := remote.WriteFile(review.FilePath, []byte(content), info.Mode().Perm()); err != nil {
+		return a, toast.NewErrorToast(err.Error(), toast.WithTitle("Edit not applied"))
+	}
+	slog.Info("Applied part of an edit", "file", review.FilePath, "accepted", len(accepted), "rejected", len(rejected))
+
+	prompt := fmt.Sprintf(
+		"I applied %d of the %d hunks of your edit to %s myself. These hunks were rejected and are not in the file:\n\n```diff\n%s```\n\nContinue without them.",
+		len(accepted), len(review.Hunks), review.FilePath, diff.FormatHunks(rejected),
+	)
+	updated, cmd := a.SendPrompt(context.Background(), Prompt{Text: prompt})
+	return updated, tea.Batch(cmd, toast.NewSuccessToast(
+		fmt.Sprintf("%d of %d hunks applied", len(accepted), len(review.Hunks)),
+		toast.WithTitle("Edit applied in part"),
+	))
+}
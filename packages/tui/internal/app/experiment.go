@@ -0,0 +1,146 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/aaronmrosenthal/rycode/internal/bench"
+	"github.com/aaronmrosenthal/rycode/internal/intelligence"
+)
+
+// defaultExperimentSplit is the share of prompts routed to arm B when no
+// split is given
+const defaultExperimentSplit = 50
+
+// ExperimentsPath returns the file A/B routing experiments are saved to
+func (a *App) ExperimentsPath() string {
+	return filepath.Join(a.InsightsDir(), "experiments.json")
+}
+
+// Experiments returns the experiment store, loading it on first use
+func (a *App) Experiments() *intelligence.ExperimentStore {
+	if a.experiments == nil {
+		store, err := intelligence.LoadExperimentStore(a.ExperimentsPath())
+		if err != nil {
+			slog.Warn("Failed to load experiments", "error", err)
+		}
+		a.experiments = store
+	}
+	return a.experiments
+}
+
+// StartExperiment starts routing prompts between two models. args is the text
+// typed after "/experiment start": two "provider/model" references and an
+// optional percentage of prompts to route to the second one.
+func (a *App) StartExperiment(args string) (*intelligence.Experiment, error) {
+	fields := strings.Fields(args)
+	if len(fields) < 2 || len(fields) > 3 {
+		return nil, fmt.Errorf("usage: /experiment start <provider/model> <provider/model> [percent]")
+	}
+
+	for _, ref := range fields[:2] {
+		model, err := bench.ParseModelRef(ref)
+		if err != nil {
+			return nil, err
+		}
+		if provider, m := findModelByProviderAndModelID(a.Providers, model.ProviderID, model.ModelID); provider == nil || m == nil {
+			return nil, fmt.Errorf("model %s is not available", ref)
+		}
+	}
+
+	split := defaultExperimentSplit
+	if len(fields) == 3 {
+		parsed, err := strconv.Atoi(strings.TrimSuffix(fields[2], "%"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid split %q", fields[2])
+		}
+		split = parsed
+	}
+
+	experiment, err := intelligence.NewExperiment(fields[0], fields[1], split)
+	if err != nil {
+		return nil, err
+	}
+
+	store := a.Experiments()
+	store.Start(experiment)
+	if err := store.Save(); err != nil {
+		return nil, err
+	}
+	return experiment, nil
+}
+
+// StopExperiment ends the active experiment and returns it, or nil when no
+// experiment was running
+func (a *App) StopExperiment() (*intelligence.Experiment, error) {
+	store := a.Experiments()
+	stopped := store.Stop()
+	if stopped == nil {
+		return nil, nil
+	}
+	return stopped, store.Save()
+}
+
+// RecordExperimentSignal records an outcome for a prompt routed by the active
+// experiment. It reports whether the prompt belonged to the experiment.
+func (a *App) RecordExperimentSignal(messageID string, signal intelligence.ExperimentSignal) bool {
+	experiment := a.Experiments().Active
+	if experiment == nil || !experiment.Record(messageID, signal) {
+		return false
+	}
+	if err := a.Experiments().Save(); err != nil {
+		slog.Error("Failed to save experiment signal", "error", err)
+	}
+	return true
+}
+
+// RecordExperimentFeedback applies explicit feedback to the most recent
+// prompt routed by the active experiment
+func (a *App) RecordExperimentFeedback(positive bool) bool {
+	experiment := a.Experiments().Active
+	if experiment == nil {
+		return false
+	}
+	messageID, ok := experiment.LatestAssignment()
+	if !ok {
+		return false
+	}
+	signal := intelligence.SignalNegative
+	if positive {
+		signal = intelligence.SignalPositive
+	}
+	return a.RecordExperimentSignal(messageID, signal)
+}
+
+// promptModel picks the model a prompt is sent to. While an experiment is
+// active the prompt is randomly assigned to one of its arms; otherwise the
+// current model is used.
+func (a *App) promptModel(messageID string) (providerID, modelID string) {
+	providerID, modelID = a.Provider.ID, a.Model.ID
+
+	experiment := a.Experiments().Active
+	if experiment == nil {
+		return providerID, modelID
+	}
+
+	arm := experiment.Assign(messageID, rand.Float64())
+	if err := a.Experiments().Save(); err != nil {
+		slog.Error("Failed to save experiment assignment", "error", err)
+	}
+
+	model, err := bench.ParseModelRef(experiment.Model(arm))
+	if err != nil {
+		slog.Warn("Invalid experiment arm", "arm", arm, "error", err)
+		return providerID, modelID
+	}
+	if provider, m := findModelByProviderAndModelID(a.Providers, model.ProviderID, model.ModelID); provider == nil || m == nil {
+		slog.Warn("Experiment model is not available", "model", model.String())
+		return providerID, modelID
+	}
+	slog.Debug("Routed prompt by experiment", "arm", arm, "model", model.String())
+	return model.ProviderID, model.ModelID
+}
@@ -0,0 +1,201 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/transcript"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// ExportDir is where exported sessions are written, relative to the project
+// root
+const ExportDir = ".rycode/exports"
+
+// SessionExportedMsg is sent when a session has been exported
+type SessionExportedMsg struct {
+	Paths []string
+	Err   error
+}
+
+// ExportSession renders the full message history of a session, including
+// tool calls and diffs, in a format and writes it to the export directory.
+// It returns the path of the written file.
+func (a *App) ExportSession(ctx context.Context, sessionID string, format transcript.Format) (string, error) {
+	t, err := a.sessionTranscript(ctx, sessionID)
+	if err != nil {
+		return "", err
+	}
+	return writeTranscript(t, format)
+}
+
+// ExportReviewMsg is sent when a transcript about to be exported or shared
+// has findings to review before it leaves the machine
+type ExportReviewMsg struct {
+	Transcript *transcript.Transcript
+	Formats    []transcript.Format // Empty when the session is being shared
+	Findings   []transcript.Finding
+}
+
+// SessionSharedMsg is sent when a session has been shared
+type SessionSharedMsg struct {
+	URL string
+	Err error
+}
+
+// ExportSessionFormats exports a session in each of the named formats, or in
+// every format for "all". Markdown is used when no format is named. When the
+// transcript has secrets, home directory paths or user names in it, an
+// ExportReviewMsg is sent instead so they can be masked first.
+func (a *App) ExportSessionFormats(sessionID, args string) tea.Cmd {
+	return func() tea.Msg {
+		formats, err := parseExportFormats(args)
+		if err != nil {
+			return SessionExportedMsg{Err: err}
+		}
+		// Fetch the history once for every format
+		t, err := a.sessionTranscript(context.Background(), sessionID)
+		if err != nil {
+			return SessionExportedMsg{Err: err}
+		}
+		if findings := t.Findings(localUsernames()...); len(findings) > 0 {
+			return ExportReviewMsg{Transcript: t, Formats: formats, Findings: findings}
+		}
+		return writeTranscripts(t, formats)
+	}
+}
+
+// WriteTranscripts writes a reviewed transcript in each of the formats
+func (a *App) WriteTranscripts(t *transcript.Transcript, formats []transcript.Format) tea.Cmd {
+	return func() tea.Msg {
+		return writeTranscripts(t, formats)
+	}
+}
+
+func writeTranscripts(t *transcript.Transcript, formats []transcript.Format) tea.Msg {
+	var paths []string
+	for _, format := range formats {
+		path, err := writeTranscript(t, format)
+		if err != nil {
+			return SessionExportedMsg{Paths: paths, Err: err}
+		}
+		paths = append(paths, path)
+	}
+	return SessionExportedMsg{Paths: paths}
+}
+
+// ReviewShare checks a session for secrets, home directory paths and user
+// names before sharing it, sending an ExportReviewMsg when it has any.
+// Shared pages are rendered from the server's copy of the session, so they
+// can be reviewed but not masked.
+func (a *App) ReviewShare(sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		t, err := a.sessionTranscript(context.Background(), sessionID)
+		if err != nil {
+			return SessionSharedMsg{Err: err}
+		}
+		if findings := t.Findings(localUsernames()...); len(findings) > 0 {
+			return ExportReviewMsg{Transcript: t, Findings: findings}
+		}
+		return a.shareSession(sessionID)
+	}
+}
+
+// ShareSession shares a session without reviewing it
+func (a *App) ShareSession(sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		return a.shareSession(sessionID)
+	}
+}
+
+func (a *App) shareSession(sessionID string) tea.Msg {
+	response, err := a.Client.Session.Share(context.Background(), sessionID, opencode.SessionShareParams{})
+	if err != nil {
+		slog.Error("Failed to share session", "error", err)
+		return SessionSharedMsg{Err: fmt.Errorf("failed to share session")}
+	}
+	return SessionSharedMsg{URL: response.Share.URL}
+}
+
+// localUsernames returns the name of the user running the TUI, which
+// transcripts are checked for
+func localUsernames() []string {
+	current, err := user.Current()
+	if err != nil {
+		return nil
+	}
+	// Windows names include the domain
+	name := current.Username[strings.LastIndex(current.Username, `\`)+1:]
+	return []string{name}
+}
+
+func parseExportFormats(args string) ([]transcript.Format, error) {
+	fields := strings.Fields(strings.ReplaceAll(args, ",", " "))
+	if len(fields) == 0 {
+		return []transcript.Format{transcript.FormatMarkdown}, nil
+	}
+	var formats []transcript.Format
+	seen := make(map[transcript.Format]bool)
+	for _, field := range fields {
+		if strings.EqualFold(field, "all") {
+			return transcript.Formats, nil
+		}
+		format, err := transcript.ParseFormat(field)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[format] {
+			seen[format] = true
+			formats = append(formats, format)
+		}
+	}
+	return formats, nil
+}
+
+// sessionTranscript reads a session and its messages into a transcript
+func (a *App) sessionTranscript(ctx context.Context, sessionID string) (*transcript.Transcript, error) {
+	if sessionID == "" {
+		return nil, fmt.Errorf("no active session to export")
+	}
+	session, err := a.Client.Session.Get(ctx, sessionID, opencode.SessionGetParams{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+	messages, err := a.ListMessages(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load messages: %w", err)
+	}
+
+	t := transcript.New(*session, time.Now())
+	for _, message := range messages {
+		t.Add(message.Info, message.Parts)
+	}
+	if len(t.Messages) == 0 {
+		return nil, fmt.Errorf("no messages to export")
+	}
+	return t, nil
+}
+
+func writeTranscript(t *transcript.Transcript, format transcript.Format) (string, error) {
+	data, err := t.Render(format)
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(util.RootPath, ExportDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create export directory: %w", err)
+	}
+	path := filepath.Join(dir, t.Filename(format))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return path, nil
+}
@@ -0,0 +1,259 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/id"
+)
+
+// PromptFailedMsg is sent when a prompt failed with an error that another
+// provider may not have, such as a rate limit or a server error, and
+// failover is enabled
+type PromptFailedMsg struct {
+	SessionID  string
+	MessageID  string
+	Parts      []opencode.SessionPromptParamsPartUnion
+	ProviderID string
+	ModelID    string
+	Agent      string
+	Reason     string
+	Tried      []string // Providers that already failed this prompt
+}
+
+// FailoverEntry is an audit record of a prompt moved to another provider
+type FailoverEntry struct {
+	Time           time.Time `json:"time"`
+	SessionID      string    `json:"sessionID"`
+	MessageID      string    `json:"messageID"`      // The failed message
+	RetryMessageID string    `json:"retryMessageID"` // The retried message
+	FromProvider   string    `json:"fromProvider"`
+	FromModel      string    `json:"fromModel"`
+	ToProvider     string    `json:"toProvider"`
+	ToModel        string    `json:"toModel"`
+	Reason         string    `json:"reason"`
+}
+
+// failoverPattern matches provider error messages worth retrying elsewhere:
+// rate limits, overloads and server errors. Status codes only count next to
+// "status", "code" or "http", so numbers elsewhere in a message don't.
+var failoverPattern = regexp.MustCompile(`(?i)` +
+	`\b(?:status(?: code)?|code|http)[\s:=]*(?:429|5\d\d)\b|` +
+	`\brate[ _-]?limit(?:ed|s)?\b|too many requests|quota exceeded|` +
+	`\boverloaded_error\b|\b(?:server|model|api|provider|service)s? (?:is |are )?(?:currently )?overloaded\b|^overloaded\b|` +
+	`internal server error|bad gateway|service unavailable|gateway timeout`)
+
+// FailoverEnabled reports whether failed prompts are retried on the next
+// authenticated provider. Failover is off unless enabled.
+func (a *App) FailoverEnabled() bool {
+	return a.State.ProviderFailover != nil && *a.State.ProviderFailover
+}
+
+// SetFailover turns provider failover on or off
+func (a *App) SetFailover(enabled bool) tea.Cmd {
+	a.State.ProviderFailover = &enabled
+	return a.SaveState()
+}
+
+// sendPrompt sends a prompt to a provider. When failover is enabled and the
+// provider fails with a rate limit or server error, a PromptFailedMsg is
+// returned so that it can be retried elsewhere.
+func (a *App) sendPrompt(
+	ctx context.Context,
+	sessionID, messageID string,
+	parts []opencode.SessionPromptParamsPartUnion,
+	providerID, modelID, agent string,
+	tried []string,
+) tea.Cmd {
+	failover := a.FailoverEnabled()
+	params := opencode.SessionPromptParams{
+		Model: opencode.F(opencode.SessionPromptParamsModel{
+			ProviderID: opencode.F(providerID),
+			ModelID:    opencode.F(modelID),
+		}),
+		Agent:     opencode.F(agent),
+		MessageID: opencode.F(messageID),
+		Parts:     opencode.F(parts),
+	}
+	// Sessions keep the instructions of their template and their directory
+	if system := a.sessionSystem(sessionID); system != "" {
+		params.System = opencode.F(system)
+	}
+	if dir := a.toolDirectory(sessionID); dir != "" {
+		params.Directory = opencode.F(dir)
+	}
+	return func() tea.Msg {
+		response, err := a.Client.Session.Prompt(ctx, sessionID, params)
+
+		var reason string
+		if err != nil {
+			reason = requestFailoverReason(err)
+		} else if response != nil {
+			reason = messageFailoverReason(response.Info)
+		}
+		if failover && reason != "" {
+			return PromptFailedMsg{
+				SessionID:  sessionID,
+				MessageID:  messageID,
+				Parts:      parts,
+				ProviderID: providerID,
+				ModelID:    modelID,
+				Agent:      agent,
+				Reason:     reason,
+				Tried:      append(slices.Clone(tried), providerID),
+			}
+		}
+		if err != nil {
+			errormsg := fmt.Sprintf("failed to send message: %v", err)
+			slog.Error(errormsg)
+			return toast.NewErrorToast(errormsg)()
+		}
+		return nil
+	}
+}
+
+// requestFailoverReason describes a failed request that failover handles,
+// or returns "" when it doesn't
+func requestFailoverReason(err error) string {
+	var apiErr *opencode.Error
+	if errors.As(err, &apiErr) {
+		if apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500 {
+			return fmt.Sprintf("%d %s", apiErr.StatusCode, http.StatusText(apiErr.StatusCode))
+		}
+		return ""
+	}
+	if failoverPattern.MatchString(err.Error()) {
+		return err.Error()
+	}
+	return ""
+}
+
+// messageFailoverReason describes the provider error of an assistant message
+// that failover handles, or returns "" when it doesn't
+func messageFailoverReason(message opencode.AssistantMessage) string {
+	if err, ok := message.Error.AsUnion().(opencode.UnknownError); ok && failoverPattern.MatchString(err.Data.Message) {
+		return err.Data.Message
+	}
+	return ""
+}
+
+// Failover retries a failed prompt on the next authenticated provider, in
+// the order providers are cycled through, and makes it the current
+// provider. Each switch is recorded in the session's audit log.
+func (a *App) Failover(msg PromptFailedMsg) (*App, tea.Cmd) {
+	ctx, cancel := a.TimeoutContext(TimeoutProvider)
+	defer cancel()
+
+	failed := fmt.Sprintf("%s failed: %s", msg.ProviderID, msg.Reason)
+	providers, err := a.authenticatedProviderIDs(ctx)
+	if err != nil {
+		return a, toast.NewErrorToast(failed, toast.WithTitle("Failover unavailable"))
+	}
+
+	// Continue from the failed provider, wrapping around once
+	start := slices.Index(providers, msg.ProviderID) + 1
+	var provider *opencode.Provider
+	var model *opencode.Model
+	for i := range providers {
+		candidate := providers[(start+i)%len(providers)]
+		if slices.Contains(msg.Tried, candidate) {
+			continue
+		}
+		if provider, model = a.preferredModel(candidate); provider != nil && model != nil {
+			break
+		}
+	}
+	if provider == nil || model == nil {
+		return a, toast.NewErrorToast(failed+". No other authenticated provider to retry with.", toast.WithTitle("Failover exhausted"))
+	}
+
+	retryID := id.Ascending(id.Message)
+	entry := FailoverEntry{
+		Time:           time.Now(),
+		SessionID:      msg.SessionID,
+		MessageID:      msg.MessageID,
+		RetryMessageID: retryID,
+		FromProvider:   msg.ProviderID,
+		FromModel:      msg.ModelID,
+		ToProvider:     provider.ID,
+		ToModel:        model.ID,
+		Reason:         msg.Reason,
+	}
+	if err := a.recordFailover(entry); err != nil {
+		slog.Warn("Failed to record failover", "error", err)
+	}
+	slog.Info("Failing over", "from", msg.ProviderID, "to", provider.ID, "reason", msg.Reason)
+
+	a.Provider = provider
+	a.Model = model
+	a.State.AgentModel[a.Agent().Name] = AgentModel{ProviderID: provider.ID, ModelID: model.ID}
+	a.State.UpdateModelUsage(provider.ID, model.ID)
+
+	return a, tea.Batch(
+		a.SaveState(),
+		toast.NewWarningToast(
+			fmt.Sprintf("%s. Retrying with %s: %s", failed, provider.Name, model.Name),
+			toast.WithTitle("Provider failover"),
+		),
+		a.sendPrompt(context.Background(), msg.SessionID, retryID, msg.Parts, provider.ID, model.ID, msg.Agent, msg.Tried),
+	)
+}
+
+// failoverAuditPath returns the audit log of a session's failovers
+func (a *App) failoverAuditPath(sessionID string) string {
+	return filepath.Join(a.InsightsDir(), "failover", sessionID+".jsonl")
+}
+
+func (a *App) recordFailover(entry FailoverEntry) error {
+	path := a.failoverAuditPath(entry.SessionID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// FailoverAudit returns the failovers recorded for a session, oldest first
+func (a *App) FailoverAudit(sessionID string) ([]FailoverEntry, error) {
+	file, err := os.Open(a.failoverAuditPath(sessionID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []FailoverEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry FailoverEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+func TestRequestFailoverReason(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&opencode.Error{StatusCode: 429}, "429 Too Many Requests"},
+		{&opencode.Error{StatusCode: 503}, "503 Service Unavailable"},
+		{&opencode.Error{StatusCode: 400}, ""},
+		{errors.New("provider is overloaded"), "provider is overloaded"},
+		{errors.New("Overloaded"), "Overloaded"},
+		{errors.New("upstream returned status 502"), "upstream returned status 502"},
+		{errors.New("context canceled"), ""},
+		{errors.New("read 512 bytes of 500"), ""},
+		{errors.New("method is overloaded by the embedded type"), ""},
+		{errors.New("invalid tool schema for limit_rate"), ""},
+	}
+	for _, tt := range tests {
+		if got := requestFailoverReason(tt.err); got != tt.want {
+			t.Errorf("requestFailoverReason(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestMessageFailoverReason(t *testing.T) {
+	decode := func(raw string) opencode.AssistantMessage {
+		var message opencode.AssistantMessage
+		if err := json.Unmarshal([]byte(raw), &message); err != nil {
+			t.Fatal(err)
+		}
+		return message
+	}
+
+	limited := decode(`{"id":"msg_1","error":{"name":"UnknownError","data":{"message":"Rate limit reached for requests"}}}`)
+	if got := messageFailoverReason(limited); got != "Rate limit reached for requests" {
+		t.Errorf("rate limit reason = %q", got)
+	}
+	other := decode(`{"id":"msg_2","error":{"name":"UnknownError","data":{"message":"invalid tool schema at line 500"}}}`)
+	if got := messageFailoverReason(other); got != "" {
+		t.Errorf("unrelated error reason = %q, want none", got)
+	}
+	if got := messageFailoverReason(decode(`{"id":"msg_3"}`)); got != "" {
+		t.Errorf("successful message reason = %q, want none", got)
+	}
+}
+
+func TestFailoverAudit(t *testing.T) {
+	a := &App{StatePath: filepath.Join(t.TempDir(), "tui")}
+	entries, err := a.FailoverAudit("ses_1")
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("empty audit = %v, %v", entries, err)
+	}
+
+	for _, to := range []string{"openai", "google"} {
+		err := a.recordFailover(FailoverEntry{Time: time.Now(), SessionID: "ses_1", FromProvider: "anthropic", ToProvider: to, Reason: "429"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err = a.FailoverAudit("ses_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].ToProvider != "openai" || entries[1].ToProvider != "google" {
+		t.Errorf("entries = %+v", entries)
+	}
+	if other, _ := a.FailoverAudit("ses_2"); len(other) != 0 {
+		t.Errorf("another session has entries: %+v", other)
+	}
+}
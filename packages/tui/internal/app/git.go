@@ -0,0 +1,113 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/gitstatus"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// maxCommitConversationBytes bounds the session's conversation sent along
+// with the diff to explain it
+const maxCommitConversationBytes = 30_000
+
+// GitStatusMsg is sent with the status of the project's worktree
+type GitStatusMsg struct {
+	Status gitstatus.Status
+	Err    error
+}
+
+// CommitMessageMsg is sent with a commit message generated for the staged
+// changes
+type CommitMessageMsg struct {
+	Message string
+	Err     error
+}
+
+// GitCommittedMsg is sent when the staged changes have been committed
+type GitCommittedMsg struct {
+	Summary string
+	Err     error
+}
+
+// GitStatus reads the status of the project's worktree
+func (a *App) GitStatus() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		status, err := gitstatus.Read(ctx, util.RootPath)
+		return GitStatusMsg{Status: status, Err: err}
+	}
+}
+
+// StageFiles stages or, with stage false, unstages paths, then reads the
+// status again
+func (a *App) StageFiles(stage bool, paths ...string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		change := gitstatus.Stage
+		if !stage {
+			change = gitstatus.Unstage
+		}
+		if err := change(ctx, util.RootPath, paths...); err != nil {
+			return GitStatusMsg{Err: err}
+		}
+		status, err := gitstatus.Read(ctx, util.RootPath)
+		return GitStatusMsg{Status: status, Err: err}
+	}
+}
+
+// GenerateCommitMessage asks the model for the message of a commit of the
+// staged changes, in a throwaway session where it can't change anything.
+// The current session's conversation, which usually made the changes, is
+// sent along to say why they were made.
+func (a *App) GenerateCommitMessage() tea.Cmd {
+	sessionID := a.Session.ID
+	model := a.currentAskModel()
+
+	return func() tea.Msg {
+		ctx := context.Background()
+		diff, err := gitstatus.StagedDiff(ctx, util.RootPath)
+		if err != nil {
+			return CommitMessageMsg{Err: err}
+		}
+		if strings.TrimSpace(diff) == "" {
+			return CommitMessageMsg{Err: errors.New("nothing is staged")}
+		}
+		conversation := ""
+		if sessionID != "" {
+			if t, err := a.sessionTranscript(ctx, sessionID); err == nil {
+				conversation = t.Conversation(maxCommitConversationBytes)
+			} else {
+				slog.Debug("Failed to read the session for the commit message", "error", err)
+			}
+		}
+		subjects, _ := gitstatus.RecentSubjects(ctx, util.RootPath, 10)
+
+		answer, err := a.askThrowaway(ctx, model, "commit message", gitstatus.MessageSystemPrompt, gitstatus.MessagePrompt(diff, conversation, subjects))
+		if err != nil {
+			return CommitMessageMsg{Err: err}
+		}
+		message := gitstatus.ParseMessage(answer)
+		if message == "" {
+			return CommitMessageMsg{Err: errors.New("the model didn't write a message")}
+		}
+		return CommitMessageMsg{Message: message}
+	}
+}
+
+// CommitStaged commits the staged changes with message
+func (a *App) CommitStaged(message string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		summary, err := gitstatus.Commit(ctx, util.RootPath, message)
+		return GitCommittedMsg{Summary: summary, Err: err}
+	}
+}
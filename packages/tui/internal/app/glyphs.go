@@ -0,0 +1,41 @@
+package app
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// maxRecentGlyphs is the number of symbols the picker remembers
+const maxRecentGlyphs = 16
+
+// UseGlyph moves a symbol inserted with the picker to the front of the
+// recent ones
+func (a *App) UseGlyph(glyph string) tea.Cmd {
+	recent := slices.DeleteFunc(a.State.RecentGlyphs, func(g string) bool { return g == glyph })
+	recent = append([]string{glyph}, recent...)
+	a.State.RecentGlyphs = recent[:min(len(recent), maxRecentGlyphs)]
+	return a.SaveState()
+}
+
+// ForgetGlyph removes a symbol from the recent ones
+func (a *App) ForgetGlyph(glyph string) tea.Cmd {
+	a.State.RecentGlyphs = slices.DeleteFunc(a.State.RecentGlyphs, func(g string) bool { return g == glyph })
+	return a.SaveState()
+}
+
+// GlyphFavorite reports whether a symbol is a favorite of the picker
+func (a *App) GlyphFavorite(glyph string) bool {
+	return slices.Contains(a.State.FavoriteGlyphs, glyph)
+}
+
+// ToggleFavoriteGlyph adds a symbol to the favorites of the picker, or
+// removes it, and reports whether it is a favorite now
+func (a *App) ToggleFavoriteGlyph(glyph string) (bool, tea.Cmd) {
+	if i := slices.Index(a.State.FavoriteGlyphs, glyph); i >= 0 {
+		a.State.FavoriteGlyphs = slices.Delete(a.State.FavoriteGlyphs, i, i+1)
+		return false, a.SaveState()
+	}
+	a.State.FavoriteGlyphs = append(a.State.FavoriteGlyphs, glyph)
+	return true, a.SaveState()
+}
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/commands"
+	"github.com/aaronmrosenthal/rycode/internal/components/help"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+)
+
+// spendingTipThreshold is the month's spending, in dollars, past which the
+// budget forecast is suggested
+const spendingTipThreshold = 5.0
+
+// contextHelp returns the hints, with the dismissals saved in the state
+func (a *App) contextHelp() *help.ContextHelpProvider {
+	if a.hints == nil {
+		a.hints = help.NewContextHelpProvider()
+		for _, ctx := range a.State.DismissedHints {
+			a.hints.DismissHint(help.HelpContext(ctx))
+		}
+	}
+	return a.hints
+}
+
+// progressiveTips returns the tips, carrying on from the counts saved in
+// the state
+func (a *App) progressiveTips() *help.ProgressiveTips {
+	if a.tips == nil {
+		a.tips = help.RestoreProgressiveTips(a.State.TipCounts, a.State.TipsShown)
+	}
+	return a.tips
+}
+
+// hintContexts lists the contexts that apply to the app's state, the most
+// pressing first
+func (a *App) hintContexts() []help.HelpContext {
+	var contexts []help.HelpContext
+	if len(a.Providers) == 0 {
+		contexts = append(contexts, help.ContextNoProviders)
+	}
+	if a.budgetOverrun {
+		contexts = append(contexts, help.ContextBudgetOverrun)
+	}
+	if a.Session.ID == "" || len(a.Messages) == 0 {
+		contexts = append(contexts, help.ContextEmpty)
+	} else {
+		contexts = append(contexts, help.ContextChat)
+	}
+	return contexts
+}
+
+// Hint returns the hint for the app's state, with the shortcut its command
+// is bound to, or nil when there is none. The tutorial shows none, having
+// its own guidance.
+func (a *App) Hint() (help.HelpContext, *help.ContextualHint) {
+	if a.Tutorial != nil {
+		return "", nil
+	}
+	for _, ctx := range a.hintContexts() {
+		if hint := a.contextHelp().GetHint(ctx); hint != nil {
+			return ctx, a.withShortcut(hint)
+		}
+	}
+	return "", nil
+}
+
+// withShortcut replaces the shortcut of a hint with its command's keybind,
+// or its trigger when the command has no keybind
+func (a *App) withShortcut(hint *help.ContextualHint) *help.ContextualHint {
+	command, ok := a.Commands[hint.Command]
+	if hint.Command == "" || !ok {
+		return hint
+	}
+	if key := a.Keybind(hint.Command); key != "" {
+		hint.Shortcut = key
+	} else if command.HasTrigger() {
+		hint.Shortcut = "/" + command.PrimaryTrigger()
+	}
+	return hint
+}
+
+// DismissHint stops showing the current hint, unless it can't be dismissed
+func (a *App) DismissHint() (bool, tea.Cmd) {
+	ctx, hint := a.Hint()
+	if hint == nil || !hint.Dismissable {
+		return false, nil
+	}
+	return true, a.dismissHint(ctx)
+}
+
+func (a *App) dismissHint(ctx help.HelpContext) tea.Cmd {
+	a.contextHelp().DismissHint(ctx)
+	a.State.DismissedHints = a.State.DismissedHints[:0]
+	for _, dismissed := range a.contextHelp().Dismissed() {
+		a.State.DismissedHints = append(a.State.DismissedHints, string(dismissed))
+	}
+	return a.SaveState()
+}
+
+// ResetHints shows the dismissed hints again
+func (a *App) ResetHints() tea.Cmd {
+	a.contextHelp().ResetHints()
+	a.State.DismissedHints = nil
+	return a.SaveState()
+}
+
+// HintFollowed dismisses the current hint once its command was run, as it
+// has served its purpose
+func (a *App) HintFollowed(name commands.CommandName) tea.Cmd {
+	ctx, hint := a.Hint()
+	if hint == nil || !hint.Dismissable || hint.Command != name {
+		return nil
+	}
+	return a.dismissHint(ctx)
+}
+
+// RecordInteraction counts an interaction towards the progressive tips,
+// and shows the tip it makes due
+func (a *App) RecordInteraction(action string) tea.Cmd {
+	if a.Tutorial != nil {
+		return nil
+	}
+	tips := a.progressiveTips()
+	tips.RecordInteraction(action)
+	var cmds []tea.Cmd
+	if tip := tips.GetProgressiveTip(); tip != nil {
+		tip = a.withShortcut(tip)
+		cmds = append(cmds, toast.NewInfoToast(
+			tip.Message+"\n"+tip.Action+": "+tip.Shortcut,
+			toast.WithTitle(tip.Icon+" "+tip.Title),
+		))
+	}
+	a.State.TipCounts = tips.Counts()
+	a.State.TipsShown = tips.Shown()
+	return tea.Batch(append(cmds, a.SaveState())...)
+}
+
+// checkSpending raises the budget hint the first time the forecast goes
+// over the budget, and counts spending past the tip's threshold
+func (a *App) checkSpending() tea.Cmd {
+	forecast := a.Budget().GetForecast()
+	if forecast.WillExceedBudget {
+		a.budgetOverrun = true
+	}
+	if forecast.CurrentSpend < spendingTipThreshold || slices.Contains(a.progressiveTips().Shown(), help.InteractionSpending) {
+		return nil
+	}
+	return a.RecordInteraction(help.InteractionSpending)
+}
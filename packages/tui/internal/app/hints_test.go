@@ -0,0 +1,60 @@
+package app
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/commands"
+	"github.com/aaronmrosenthal/rycode/internal/components/help"
+)
+
+func TestHints(t *testing.T) {
+	newApp := func(state *State, providers ...opencode.Provider) *App {
+		config := &opencode.Config{}
+		config.Keybinds.Leader = "ctrl+x"
+		return &App{
+			StatePath: filepath.Join(t.TempDir(), "tui"),
+			State:     state,
+			Config:    config,
+			Session:   &opencode.Session{},
+			Providers: providers,
+			Commands: commands.CommandRegistry{
+				commands.ModelListCommand: {
+					Name:        commands.ModelListCommand,
+					Keybindings: []commands.Keybinding{{RequiresLeader: true, Key: "m"}},
+				},
+			},
+		}
+	}
+
+	state := NewState()
+	a := newApp(state)
+	if ctx, hint := a.Hint(); ctx != help.ContextNoProviders || hint == nil {
+		t.Fatalf("hint without providers = %q, %+v", ctx, hint)
+	}
+	if dismissed, _ := a.DismissHint(); dismissed {
+		t.Error("dismissed the hint shown until a provider is set up")
+	}
+
+	a = newApp(state, opencode.Provider{ID: "anthropic"})
+	ctx, hint := a.Hint()
+	if ctx != help.ContextEmpty || hint == nil || hint.Shortcut != "ctrl+x m" {
+		t.Fatalf("hint of an empty session = %q, %+v, want the model list's keybind", ctx, hint)
+	}
+	a.HintFollowed(commands.ModelListCommand)
+	if _, hint := a.Hint(); hint != nil {
+		t.Errorf("hint after opening the model list = %+v, want none", hint)
+	}
+	if _, hint := newApp(state, opencode.Provider{ID: "anthropic"}).Hint(); hint != nil {
+		t.Errorf("a dismissed hint came back with the saved state: %+v", hint)
+	}
+
+	for range 10 {
+		a.RecordInteraction(help.InteractionRequest)
+	}
+	if state.TipCounts[help.InteractionRequest] != 10 || !slices.Contains(state.TipsShown, help.InteractionRequest) {
+		t.Errorf("tips = %v, shown %v, want the request tip shown after 10 prompts", state.TipCounts, state.TipsShown)
+	}
+}
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"cmp"
+	"log/slog"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+// maxHomeSessions is the number of recent sessions the home screen lists
+const maxHomeSessions = 5
+
+// HomeDashboard is what the home screen shows around the prompt besides the
+// spend, which is read from the usage insights as it's drawn
+type HomeDashboard struct {
+	Sessions []opencode.Session // Most recently updated first
+	Health   map[string]string  // "healthy", "degraded" or "down", by provider ID
+}
+
+// HomeDashboardMsg carries the home screen's dashboard once it's loaded
+type HomeDashboardMsg struct {
+	Dashboard HomeDashboard
+}
+
+// HomeDashboardEnabled reports whether the home screen shows the spend,
+// recent sessions and provider health, as it does unless the state turns it
+// off
+func (a *App) HomeDashboardEnabled() bool {
+	return a.State.HomeDashboard == nil || *a.State.HomeDashboard
+}
+
+// SetHomeDashboard shows or hides the spend, recent sessions and provider
+// health on the home screen, loading them when they're shown
+func (a *App) SetHomeDashboard(on bool) tea.Cmd {
+	a.State.HomeDashboard = &on
+	return tea.Batch(a.SaveState(), a.LoadHomeDashboard())
+}
+
+// LoadHomeDashboard lists the recent sessions and checks the health of the
+// connected providers for the home screen
+func (a *App) LoadHomeDashboard() tea.Cmd {
+	if !a.HomeDashboardEnabled() || a.Tutorial != nil {
+		return nil
+	}
+	var providers []string
+	for _, provider := range a.Providers {
+		providers = append(providers, provider.ID)
+	}
+	return func() tea.Msg {
+		ctx, cancel := a.TimeoutContext(TimeoutAPI)
+		defer cancel()
+		var dashboard HomeDashboard
+		sessions, err := a.ListSessions(ctx)
+		if err != nil {
+			slog.Debug("Failed to list sessions for the home screen", "error", err)
+		}
+		// Subagent sessions are reached from their parent
+		sessions = slices.DeleteFunc(sessions, func(s opencode.Session) bool { return s.ParentID != "" })
+		slices.SortFunc(sessions, func(x, y opencode.Session) int { return cmp.Compare(y.Time.Updated, x.Time.Updated) })
+		dashboard.Sessions = sessions[:min(len(sessions), maxHomeSessions)]
+
+		if a.AuthBridge != nil {
+			dashboard.Health = make(map[string]string)
+			for _, provider := range providers {
+				health, err := a.AuthBridge.GetProviderHealth(ctx, provider)
+				if err != nil {
+					slog.Debug("Failed to check provider health", "provider", provider, "error", err)
+					continue
+				}
+				dashboard.Health[provider] = health.Status
+			}
+		}
+		return HomeDashboardMsg{Dashboard: dashboard}
+	}
+}
+
+// TodaySpend returns what today's usage cost
+func (a *App) TodaySpend() float64 {
+	return a.UsageInsights().GetCostOn(time.Now())
+}
@@ -0,0 +1,32 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// inlineMinHeight is the fewest lines the TUI takes up when it runs inline:
+// a few lines of the reply being written above the prompt and status bar
+const inlineMinHeight = 14
+
+// ProgramOptions returns the screen options the TUI's program runs with.
+// Inline, it stays out of the alternate screen and leaves the mouse to the
+// terminal, so finished messages land in the scrollback where the terminal,
+// or tmux's copy mode, selects and scrolls them. In keyboard-only mode the
+// mouse is left to the terminal as well.
+func (a *App) ProgramOptions() []tea.ProgramOption {
+	// Focus is reported to hold notifications back while the terminal is
+	// looked at
+	if a.Inline {
+		return []tea.ProgramOption{tea.WithReportFocus()}
+	}
+	if a.KeyboardOnly() {
+		return []tea.ProgramOption{tea.WithAltScreen(), tea.WithReportFocus()}
+	}
+	return []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithReportFocus()}
+}
+
+// InlineHeight returns the lines the TUI takes up below the scrollback in a
+// terminal of a height when it runs inline
+func InlineHeight(terminal int) int {
+	return min(terminal, max(inlineMinHeight, terminal/2))
+}
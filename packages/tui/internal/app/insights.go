@@ -0,0 +1,79 @@
+package app
+
+import (
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/intelligence"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// InsightsDir returns the directory holding persisted insights data, next to
+// the TUI state file
+func (a *App) InsightsDir() string {
+	return filepath.Join(filepath.Dir(a.StatePath), "insights")
+}
+
+// UsagePath returns the file usage analytics are saved to
+func (a *App) UsagePath() string {
+	return filepath.Join(a.InsightsDir(), "usage.json")
+}
+
+// UsageInsights returns the persisted usage analytics, loading them on first
+// use with the retention configured in the state
+func (a *App) UsageInsights() *intelligence.UsageInsights {
+	if a.usageInsights == nil {
+		retention := intelligence.DefaultUsageRetentionDays
+		if a.State.UsageRetentionDays != nil {
+			retention = *a.State.UsageRetentionDays
+		}
+		insights, err := intelligence.LoadUsageInsights(a.UsagePath(), retention)
+		if err != nil {
+			slog.Warn("Failed to load usage insights", "error", err)
+		}
+		a.usageInsights = insights
+	}
+	return a.usageInsights
+}
+
+// Budget returns the budget forecast, fed by the persisted usage
+func (a *App) Budget() *intelligence.PredictiveBudget {
+	if a.budget == nil {
+		budget := intelligence.DefaultBudget
+		a.budget = intelligence.NewPredictiveBudget(budget.MonthlyLimit, budget.DailyLimit, a.UsageInsights())
+		a.budget.SyncFromInsights(time.Now())
+	}
+	return a.budget
+}
+
+// RecordUsage records the cost and tokens of a completed assistant message in
+// the usage insights and the budget forecast. Messages of every session
+// count, including the throwaway ones behind features such as /ask, and
+// each is recorded once. It returns the hints and tips the spending calls
+// for.
+func (a *App) RecordUsage(message opencode.AssistantMessage) tea.Cmd {
+	if message.Time.Completed == 0 || a.recordedUsage[message.ID] {
+		return nil
+	}
+	if a.recordedUsage == nil {
+		a.recordedUsage = make(map[string]bool)
+	}
+	a.recordedUsage[message.ID] = true
+
+	tokens := message.Tokens
+	total := int64(tokens.Input + tokens.Output + tokens.Reasoning + tokens.Cache.Read + tokens.Cache.Write)
+	completed := time.UnixMilli(int64(message.Time.Completed))
+	// Loaded before the usage is added, which it would count again
+	budget := a.Budget()
+	a.UsageInsights().AddUsage(completed, message.Cost, 1, total, message.ModelID, message.ProviderID)
+	budget.RecordDailySpend(completed, message.Cost)
+	a.recordCost(message)
+	return tea.Batch(
+		util.CmdHandler(CostUpdatedMsg{Cost: a.TodaySpend()}),
+		a.checkSpending(),
+		a.checkBackgroundCap(),
+	)
+}
@@ -0,0 +1,200 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/commands"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+)
+
+// keybindConfigFiles are the global config files keybindings are saved to,
+// the first that exists, or else the first, which is created
+var keybindConfigFiles = []string{"opencode.json", "opencode.jsonc", "config.json"}
+
+// SaveKeybinds writes the keybindings of the named commands, as they are in
+// the registry, to the global config. The config's other settings are kept.
+func (a *App) SaveKeybinds(names []commands.CommandName) tea.Cmd {
+	keybinds := make(map[string]string, len(names))
+	for _, name := range names {
+		keybinds[string(name)] = commands.FormatBindings(a.Commands[name].Keybindings)
+	}
+	dir := a.ConfigDir
+	return func() tea.Msg {
+		path, err := saveKeybinds(dir, keybinds)
+		if err != nil {
+			return toast.NewErrorToast(err.Error(), toast.WithTitle("Keybindings not saved"))()
+		}
+		return toast.NewSuccessToast("Saved to "+path, toast.WithTitle("Keybindings"))()
+	}
+}
+
+func saveKeybinds(dir string, keybinds map[string]string) (string, error) {
+	path := filepath.Join(dir, keybindConfigFiles[0])
+	for _, name := range keybindConfigFiles {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			path = filepath.Join(dir, name)
+			break
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		data = []byte("{}")
+	case err != nil:
+		return path, err
+	}
+	data, err = setKeybinds(data, keybinds)
+	if err != nil {
+		return path, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return path, err
+	}
+	return path, os.WriteFile(path, data, 0o644)
+}
+
+// setKeybinds merges keybinds into the "keybinds" object of a JSON or JSONC
+// config. Only that object is rewritten, so comments and the formatting of
+// the rest of the config are kept.
+func setKeybinds(data []byte, keybinds map[string]string) ([]byte, error) {
+	plain := stripJSONC(data)
+	config := map[string]any{}
+	if err := json.Unmarshal(plain, &config); err != nil {
+		return nil, err
+	}
+	existing, _ := config["keybinds"].(map[string]any)
+	if existing == nil {
+		existing = map[string]any{}
+	}
+	for name, binding := range keybinds {
+		existing[name] = binding
+	}
+	value, err := json.MarshalIndent(existing, "  ", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, err := keybindsSpan(plain)
+	if err != nil {
+		return nil, err
+	}
+	var out []byte
+	if start < 0 {
+		// No keybinds yet, add them as the first member
+		open := bytes.IndexByte(plain, '{') + 1
+		member := append([]byte("\n  \"keybinds\": "), value...)
+		if len(config) > 0 {
+			member = append(member, ',')
+		}
+		out = append(out, data[:open]...)
+		out = append(out, member...)
+		out = append(out, data[open:]...)
+	} else {
+		out = append(out, data[:start]...)
+		out = append(out, value...)
+		out = append(out, data[end:]...)
+	}
+	if !bytes.HasSuffix(out, []byte("\n")) {
+		out = append(out, '\n')
+	}
+	return out, nil
+}
+
+// keybindsSpan finds the value of the top level "keybinds" member, or -1
+// if there's none
+func keybindsSpan(plain []byte) (int, int, error) {
+	dec := json.NewDecoder(bytes.NewReader(plain))
+	if _, err := dec.Token(); err != nil {
+		return 0, 0, err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return 0, 0, err
+		}
+		start := int(dec.InputOffset())
+		for start < len(plain) && (plain[start] == ':' || isJSONSpace(plain[start])) {
+			start++
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return 0, 0, err
+		}
+		if key == "keybinds" {
+			return start, int(dec.InputOffset()), nil
+		}
+	}
+	return -1, -1, nil
+}
+
+// stripJSONC blanks out the comments and trailing commas of a JSONC
+// document. Offsets are kept, so spans found in the result hold for the
+// original too.
+func stripJSONC(data []byte) []byte {
+	out := bytes.Clone(data)
+	inString := false
+	for i := 0; i < len(out); i++ {
+		switch {
+		case inString:
+			if out[i] == '\\' {
+				i++
+			} else if out[i] == '"' {
+				inString = false
+			}
+		case out[i] == '"':
+			inString = true
+		case out[i] == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case out[i] == '/' && i+1 < len(out) && out[i+1] == '*':
+			end := bytes.Index(out[i+2:], []byte("*/"))
+			if end < 0 {
+				end = len(out)
+			} else {
+				end += i + 4
+			}
+			for ; i < end; i++ {
+				if out[i] != '\n' {
+					out[i] = ' '
+				}
+			}
+			i--
+		}
+	}
+
+	inString = false
+	for i := 0; i < len(out); i++ {
+		switch {
+		case inString:
+			if out[i] == '\\' {
+				i++
+			} else if out[i] == '"' {
+				inString = false
+			}
+		case out[i] == '"':
+			inString = true
+		case out[i] == ',':
+			next := i + 1
+			for next < len(out) && isJSONSpace(out[next]) {
+				next++
+			}
+			if next < len(out) && (out[next] == '}' || out[next] == ']') {
+				out[i] = ' '
+			}
+		}
+	}
+	return out
+}
+
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/accessibility"
+	"github.com/aaronmrosenthal/rycode/internal/commands"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+)
+
+// applyKeyboardOnly turns keyboard-only mode on at launch when it was left on
+func applyKeyboardOnly(state *State) {
+	if state.KeyboardOnly {
+		accessibility.GetSettings().EnableKeyboardOnly()
+	}
+}
+
+// KeyboardOnly reports whether keyboard-only mode is on, in which the mouse
+// is left to the terminal and everything is done from the keyboard
+func (a *App) KeyboardOnly() bool {
+	return accessibility.GetSettings().IsKeyboardOnly()
+}
+
+// SetKeyboardOnly turns keyboard-only mode on or off and remembers it. The
+// mouse is handed to the terminal, or taken back, at once, and turning the
+// mode on audits what the mouse does for actions the keyboard can't do.
+func (a *App) SetKeyboardOnly(on bool) tea.Cmd {
+	a.State.KeyboardOnly = on
+	// Inline, the mouse is the terminal's either way
+	var mouse tea.Cmd
+	if !on {
+		accessibility.GetSettings().DisableKeyboardOnly()
+		if !a.Inline {
+			mouse = tea.EnableMouseCellMotion
+		}
+		return tea.Batch(a.SaveState(), mouse, toast.NewInfoToast("Keyboard-only mode off"))
+	}
+	accessibility.GetSettings().EnableKeyboardOnly()
+	if !a.Inline {
+		mouse = tea.DisableMouse
+	}
+	report := toast.NewSuccessToast("Everything the mouse does has a key", toast.WithTitle("Keyboard-only mode on"))
+	if issues := a.KeyboardAudit(); len(issues) > 0 {
+		report = toast.NewWarningToast(
+			fmt.Sprintf("%d actions need the mouse; /accessibility keyboard lists them", len(issues)),
+			toast.WithTitle("Keyboard-only mode on"),
+		)
+	}
+	return tea.Batch(a.SaveState(), mouse, report)
+}
+
+// KeyboardAudit returns the mouse actions the keyboard can't do
+func (a *App) KeyboardAudit() []accessibility.KeyboardIssue {
+	return accessibility.AuditKeyboard(a.CommandKeys)
+}
+
+// CommandKeys returns how a command is run from the keyboard: its key
+// binding, or else its trigger, "" when it has neither
+func (a *App) CommandKeys(name string) string {
+	command, ok := a.Commands[commands.CommandName(name)]
+	if !ok {
+		return ""
+	}
+	if keys := a.Keybind(command.Name); keys != "" {
+		return keys
+	}
+	if len(command.Trigger) > 0 {
+		return "/" + command.Trigger[0]
+	}
+	return ""
+}
@@ -0,0 +1,160 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/loopguard"
+)
+
+// LoopDetectedMsg is sent when a run of the session was interrupted for
+// repeating a failing call, or editing a file back and forth
+type LoopDetectedMsg struct {
+	SessionID string
+	Loop      loopguard.Loop
+}
+
+// LoopLimit returns the repetitions that interrupt a run, 0 when runs are
+// never interrupted
+func (a *App) LoopLimit() int {
+	switch {
+	case a.State.LoopLimit == 0:
+		return loopguard.DefaultLimit
+	case a.State.LoopLimit < 0:
+		return 0
+	}
+	return a.State.LoopLimit
+}
+
+// SetLoopLimit sets the repetitions that interrupt a run, 0 to never
+// interrupt them
+func (a *App) SetLoopLimit(limit int) tea.Cmd {
+	a.State.LoopLimit = limit
+	if limit == 0 {
+		a.State.LoopLimit = -1
+	}
+	return a.SaveState()
+}
+
+// sessionRun is the run a session is on, followed from the events of every
+// session whichever one is in view
+type sessionRun struct {
+	prompt      string    // ID of the prompt that started the run, once seen
+	started     time.Time // When the prompt was sent, or the first answer seen was
+	calls       []string  // IDs of the run's tool parts, in the order they were made
+	tools       map[string]opencode.ToolPart
+	answers     map[string]opencode.AssistantMessage // By message ID
+	agent       string                               // Agent of the first answer
+	interrupted bool                                 // Interrupted for a loop; what follows is a run of its own
+}
+
+func newSessionRun() *sessionRun {
+	return &sessionRun{tools: make(map[string]opencode.ToolPart), answers: make(map[string]opencode.AssistantMessage)}
+}
+
+func (a *App) sessionRun(sessionID string) *sessionRun {
+	if a.sessionRuns == nil {
+		a.sessionRuns = make(map[string]*sessionRun)
+	}
+	run := a.sessionRuns[sessionID]
+	if run == nil {
+		run = newSessionRun()
+		a.sessionRuns[sessionID] = run
+	}
+	return run
+}
+
+// FollowRun notes a message of a session. A prompt starts the session's run
+// over; an answer adds to what the run has cost.
+func (a *App) FollowRun(message opencode.MessageUnion) {
+	switch message := message.(type) {
+	case opencode.UserMessage:
+		if a.sessionRun(message.SessionID).prompt != message.ID {
+			run := newSessionRun()
+			run.prompt, run.started = message.ID, time.UnixMilli(int64(message.Time.Created))
+			a.sessionRuns[message.SessionID] = run
+		}
+	case opencode.AssistantMessage:
+		run := a.sessionRun(message.SessionID)
+		if run.started.IsZero() {
+			run.started = time.UnixMilli(int64(message.Time.Created))
+		}
+		if run.agent == "" && !message.Summary {
+			run.agent = message.Mode
+		}
+		run.answers[message.ID] = message
+	}
+}
+
+// answering reports whether an answer of the run is still being written,
+// and whether it is a summary compacting the session
+func (r *sessionRun) answering() (busy, compacting bool) {
+	for _, answer := range r.answers {
+		if answer.Time.Completed == 0 {
+			busy = true
+			compacting = compacting || answer.Summary
+		}
+	}
+	return busy, compacting
+}
+
+// followTool notes a tool call of a session's run
+func (a *App) followTool(tool opencode.ToolPart) *sessionRun {
+	run := a.sessionRun(tool.SessionID)
+	if _, ok := run.tools[tool.ID]; !ok {
+		run.calls = append(run.calls, tool.ID)
+	}
+	run.tools[tool.ID] = tool
+	return run
+}
+
+// GuardLoop interrupts a session's run when a tool call that finished makes
+// it repeat itself past the limit, whichever session is in view. A run is
+// interrupted once; what the agent does after it is told how to go on is a
+// run of its own.
+func (a *App) GuardLoop(tool opencode.ToolPart) tea.Cmd {
+	run := a.followTool(tool)
+	if tool.State.Status != opencode.ToolPartStateStatusCompleted && tool.State.Status != opencode.ToolPartStateStatusError {
+		return nil
+	}
+	if run.interrupted {
+		return nil
+	}
+	loop := loopguard.Detect(run.loopCalls(), a.LoopLimit())
+	if loop == nil {
+		return nil
+	}
+	run.interrupted = true
+	sessionID := tool.SessionID
+	slog.Warn("Interrupted a run repeating itself", "session", sessionID, "kind", loop.Kind, "tool", loop.Tool, "label", loop.Label, "count", loop.Count)
+
+	detected := *loop
+	return tea.Batch(
+		func() tea.Msg {
+			if _, err := a.Client.Session.Abort(context.Background(), sessionID, opencode.SessionAbortParams{}); err != nil {
+				slog.Error("Failed to interrupt a run repeating itself", "session", sessionID, "error", err)
+			}
+			return LoopDetectedMsg{SessionID: sessionID, Loop: detected}
+		},
+		a.Notify(NotifyErrors, "Agent stuck", detected.Summary()),
+	)
+}
+
+// loopCalls returns the finished tool calls of the run, in the order they
+// were made
+func (r *sessionRun) loopCalls() []loopguard.Call {
+	var calls []loopguard.Call
+	for _, id := range r.calls {
+		tool := r.tools[id]
+		failed := tool.State.Status == opencode.ToolPartStateStatusError
+		if !failed && tool.State.Status != opencode.ToolPartStateStatusCompleted {
+			continue
+		}
+		input, _ := tool.State.Input.(map[string]any)
+		calls = append(calls, loopguard.Call{Tool: tool.Tool, Input: input, Failed: failed, Error: tool.State.Error})
+	}
+	return calls
+}
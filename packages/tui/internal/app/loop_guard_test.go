@@ -0,0 +1,48 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/loopguard"
+)
+
+func TestGuardLoopFollowsEverySession(t *testing.T) {
+	a := &App{State: NewState(), Session: &opencode.Session{ID: "ses_viewed"}}
+	failing := func(sessionID string, n int) opencode.ToolPart {
+		return opencode.ToolPart{
+			ID:        fmt.Sprintf("prt_%s_%d", sessionID, n),
+			SessionID: sessionID,
+			Tool:      "bash",
+			State: opencode.ToolPartState{
+				Status: opencode.ToolPartStateStatusError,
+				Input:  map[string]any{"command": "make"},
+				Error:  "exit status 2",
+			},
+		}
+	}
+
+	a.FollowRun(opencode.UserMessage{ID: "msg_1", SessionID: "ses_other"})
+	for n := 1; n < loopguard.DefaultLimit; n++ {
+		if cmd := a.GuardLoop(failing("ses_other", n)); cmd != nil {
+			t.Fatalf("run interrupted after %d failures", n)
+		}
+	}
+	// An update of a call already seen is not another failure
+	if cmd := a.GuardLoop(failing("ses_other", 1)); cmd != nil {
+		t.Fatal("an updated call was counted again")
+	}
+	if cmd := a.GuardLoop(failing("ses_other", loopguard.DefaultLimit)); cmd == nil {
+		t.Fatal("the run of a session out of view was not interrupted")
+	}
+	if cmd := a.GuardLoop(failing("ses_other", loopguard.DefaultLimit+1)); cmd != nil {
+		t.Error("the run was interrupted twice")
+	}
+
+	// A new prompt starts a run of its own
+	a.FollowRun(opencode.UserMessage{ID: "msg_2", SessionID: "ses_other"})
+	if cmd := a.GuardLoop(failing("ses_other", 10)); cmd != nil {
+		t.Error("the calls of the interrupted run were counted in the next one")
+	}
+}
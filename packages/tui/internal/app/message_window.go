@@ -0,0 +1,170 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+const (
+	// MessageWindow is the number of messages of the current session kept in
+	// memory. Earlier ones are fetched again when scrolled up to.
+	MessageWindow = 200
+	// messagePage is the number of earlier messages fetched at once, and
+	// how far past the window messages go before the oldest are dropped
+	messagePage = 50
+	// messageFetches is the number of earlier messages fetched in parallel
+	messageFetches = 8
+	// messageRetry is how long a failed fetch of earlier messages waits to
+	// be tried again, doubled for each failure in a row up to
+	// messageMaxRetry
+	messageRetry    = 2 * time.Second
+	messageMaxRetry = time.Minute
+)
+
+// OlderMessagesLoadedMsg is sent with earlier messages of a session, fetched
+// when scrolled up to
+type OlderMessagesLoadedMsg struct {
+	SessionID string
+	Messages  []Message
+	Err       error
+}
+
+// messageWindow holds the IDs of the messages of a session left out of memory
+type messageWindow struct {
+	sessionID string
+	older     []string // Oldest first
+	loading   bool
+	failures  int       // Fetches failed in a row
+	retryAt   time.Time // When a failed fetch may be tried again
+}
+
+// SetMessages makes messages the current session's, keeping the latest
+// MessageWindow in memory
+func (a *App) SetMessages(sessionID string, messages []Message) {
+	a.window = messageWindow{sessionID: sessionID}
+	if len(messages) > MessageWindow {
+		a.window.older = messageIDs(messages[:len(messages)-MessageWindow])
+		messages = slices.Clone(messages[len(messages)-MessageWindow:])
+	}
+	a.Messages = messages
+}
+
+// OlderMessages returns the number of earlier messages of the current
+// session that aren't in memory
+func (a *App) OlderMessages() int {
+	if a.Session == nil || a.window.sessionID != a.Session.ID {
+		return 0
+	}
+	return len(a.window.older)
+}
+
+// LoadingOlderMessages reports whether earlier messages are being fetched
+func (a *App) LoadingOlderMessages() bool {
+	return a.OlderMessages() > 0 && a.window.loading
+}
+
+// MessagePagedOut reports whether a message of the current session was left
+// out of memory
+func (a *App) MessagePagedOut(messageID string) bool {
+	return a.OlderMessages() > 0 && slices.Contains(a.window.older, messageID)
+}
+
+// TrimMessages drops the oldest messages of the current session once there
+// are a page more than MessageWindow, and reports whether it dropped any.
+// It is called while the latest messages are in view.
+func (a *App) TrimMessages() bool {
+	if a.Session == nil || a.Session.ID == "" || a.Tutorial != nil || a.window.loading || len(a.Messages) <= MessageWindow+messagePage {
+		return false
+	}
+	if a.window.sessionID != a.Session.ID {
+		a.window = messageWindow{sessionID: a.Session.ID}
+	}
+	drop := len(a.Messages) - MessageWindow
+	a.window.older = append(a.window.older, messageIDs(a.Messages[:drop])...)
+	// A copy, so that the dropped messages can be freed
+	a.Messages = slices.Clone(a.Messages[drop:])
+	return true
+}
+
+// LoadOlderMessages fetches the page of messages before the earliest one in
+// memory. After a failed fetch it waits before trying again, as it's asked
+// for on every update while the view is scrolled to the top.
+func (a *App) LoadOlderMessages() tea.Cmd {
+	if a.OlderMessages() == 0 || a.window.loading || time.Now().Before(a.window.retryAt) {
+		return nil
+	}
+	a.window.loading = true
+	sessionID := a.window.sessionID
+	ids := slices.Clone(a.window.older[max(0, len(a.window.older)-messagePage):])
+	return func() tea.Msg {
+		ctx, cancel := a.TimeoutContext(TimeoutAPI)
+		defer cancel()
+		messages := make([]Message, len(ids))
+		errs := make([]error, len(ids))
+		var wg sync.WaitGroup
+		limit := make(chan struct{}, messageFetches)
+		for i, id := range ids {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				limit <- struct{}{}
+				defer func() { <-limit }()
+				response, err := a.Client.Session.Message(ctx, sessionID, id, opencode.SessionMessageParams{})
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				message := Message{Info: response.Info.AsUnion()}
+				for _, part := range response.Parts {
+					message.Parts = append(message.Parts, part.AsUnion())
+				}
+				messages[i] = message
+			}()
+		}
+		wg.Wait()
+		for _, err := range errs {
+			if err != nil {
+				return OlderMessagesLoadedMsg{SessionID: sessionID, Err: fmt.Errorf("failed to load earlier messages: %w", err)}
+			}
+		}
+		return OlderMessagesLoadedMsg{SessionID: sessionID, Messages: messages}
+	}
+}
+
+// PrependOlderMessages puts fetched earlier messages back in front of the
+// current session's
+func (a *App) PrependOlderMessages(msg OlderMessagesLoadedMsg) {
+	if a.window.sessionID != msg.SessionID {
+		return
+	}
+	a.window.loading = false
+	if msg.Err != nil {
+		a.window.failures++
+		delay := min(messageRetry<<(a.window.failures-1), messageMaxRetry)
+		if delay <= 0 {
+			delay = messageMaxRetry
+		}
+		a.window.retryAt = time.Now().Add(delay)
+		return
+	}
+	a.window.failures, a.window.retryAt = 0, time.Time{}
+	if a.Session == nil || a.Session.ID != msg.SessionID {
+		return
+	}
+	a.window.older = a.window.older[:len(a.window.older)-len(msg.Messages)]
+	a.Messages = append(msg.Messages, a.Messages...)
+}
+
+// messageIDs returns the IDs of messages
+func messageIDs(messages []Message) []string {
+	ids := make([]string, len(messages))
+	for i, message := range messages {
+		ids[i] = MessageID(message)
+	}
+	return ids
+}
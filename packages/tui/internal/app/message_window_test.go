@@ -0,0 +1,64 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+func TestMessageWindow(t *testing.T) {
+	messages := func(from, to int) []Message {
+		var list []Message
+		for i := from; i < to; i++ {
+			list = append(list, Message{Info: opencode.UserMessage{ID: fmt.Sprintf("msg_%04d", i)}})
+		}
+		return list
+	}
+	a := &App{Session: &opencode.Session{ID: "ses_1"}}
+
+	a.SetMessages("ses_1", messages(0, MessageWindow+30))
+	if len(a.Messages) != MessageWindow || a.OlderMessages() != 30 {
+		t.Fatalf("kept %d messages and left out %d, want %d and 30", len(a.Messages), a.OlderMessages(), MessageWindow)
+	}
+	if !a.MessagePagedOut("msg_0029") || a.MessagePagedOut("msg_0030") {
+		t.Error("paged out the wrong messages")
+	}
+
+	// Messages are only dropped a page past the window
+	a.Messages = append(a.Messages, messages(MessageWindow+30, MessageWindow+30+messagePage)...)
+	if a.TrimMessages() {
+		t.Error("trimmed at a page past the window")
+	}
+	a.Messages = append(a.Messages, messages(MessageWindow+30+messagePage, MessageWindow+31+messagePage)...)
+	if !a.TrimMessages() || len(a.Messages) != MessageWindow || a.OlderMessages() != 31+messagePage {
+		t.Fatalf("after trimming, kept %d and left out %d", len(a.Messages), a.OlderMessages())
+	}
+
+	// A fetched page goes back in front, and the window stays put while
+	// loading
+	a.window.loading = true
+	if a.TrimMessages() {
+		t.Error("trimmed while earlier messages were loading")
+	}
+	a.PrependOlderMessages(OlderMessagesLoadedMsg{SessionID: "ses_1", Messages: messages(31, 31+messagePage)})
+	if a.OlderMessages() != 31 || MessageID(a.Messages[0]) != "msg_0031" || a.LoadingOlderMessages() {
+		t.Errorf("after loading a page, %d left out and first is %s", a.OlderMessages(), MessageID(a.Messages[0]))
+	}
+
+	// A failed fetch isn't tried again at once
+	a.window.loading = true
+	a.PrependOlderMessages(OlderMessagesLoadedMsg{SessionID: "ses_1", Err: fmt.Errorf("offline")})
+	if a.LoadingOlderMessages() || a.LoadOlderMessages() != nil {
+		t.Error("earlier messages were fetched again right after failing")
+	}
+	if a.OlderMessages() != 31 {
+		t.Errorf("a failed fetch left out %d, want 31", a.OlderMessages())
+	}
+
+	// Another session's window isn't the current one's
+	a.Session = &opencode.Session{ID: "ses_2"}
+	if a.OlderMessages() != 0 || a.LoadOlderMessages() != nil {
+		t.Error("earlier messages of another session")
+	}
+}
@@ -0,0 +1,169 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/splash"
+)
+
+// Events notified while the terminal is unfocused, each turned off with
+// /notify <event> off
+const (
+	NotifyCompletions = "completions" // Replies, and tool calls that ran long, finishing
+	NotifyPermissions = "permissions" // The agent waiting on a permission
+	NotifyErrors      = "errors"      // Replies failing
+)
+
+// NotifyEvents are the events that can be notified
+var NotifyEvents = []string{NotifyCompletions, NotifyPermissions, NotifyErrors}
+
+// notifyToolAfter is how long a tool call runs before its finishing is
+// notified
+const notifyToolAfter = 30 * time.Second
+
+// notifier follows the focus of the terminal, which the TUI learns of from
+// focus reports, and the messages and tool calls already notified
+type notifier struct {
+	unfocused bool
+	protocol  *splash.NotificationProtocol
+	notified  map[string]bool
+}
+
+// SetFocused records whether the terminal is focused. Terminals that don't
+// report focus are taken for focused and never notify.
+func (a *App) SetFocused(focused bool) {
+	a.notifier.unfocused = !focused
+}
+
+// NotifyEnabled reports whether an event is notified
+func (a *App) NotifyEnabled(event string) bool {
+	return !slices.Contains(a.State.NotifyOff, event)
+}
+
+// SetNotify turns the notifications of an event on or off and remembers it
+func (a *App) SetNotify(event string, on bool) (tea.Cmd, error) {
+	if !slices.Contains(NotifyEvents, event) {
+		return nil, fmt.Errorf("unknown event %q, expected one of %s", event, strings.Join(NotifyEvents, ", "))
+	}
+	a.State.NotifyOff = slices.DeleteFunc(a.State.NotifyOff, func(e string) bool { return e == event })
+	if !on {
+		a.State.NotifyOff = append(a.State.NotifyOff, event)
+	}
+	return a.SaveState(), nil
+}
+
+// NotificationProtocol returns how the terminal shows notifications
+func (a *App) NotificationProtocol() splash.NotificationProtocol {
+	if a.notifier.protocol == nil {
+		protocol := splash.DetectNotificationProtocol()
+		a.notifier.protocol = &protocol
+	}
+	return *a.notifier.protocol
+}
+
+// Notify sends a desktop notification of an event while the terminal is
+// unfocused and the event is notified, or nil
+func (a *App) Notify(event, title, body string) tea.Cmd {
+	if !a.notifier.unfocused || !a.NotifyEnabled(event) {
+		return nil
+	}
+	return a.SendNotification(title, body)
+}
+
+// SendNotification sends a desktop notification through the terminal, or
+// rings the bell in terminals that show none
+func (a *App) SendNotification(title, body string) tea.Cmd {
+	return tea.Raw(notification(a.NotificationProtocol(), title, body, os.Getenv("TMUX") != ""))
+}
+
+// NotifyReply notifies a reply of the current session finishing, or failing,
+// once
+func (a *App) NotifyReply(info opencode.AssistantMessage) tea.Cmd {
+	if info.Time.Completed == 0 || info.Summary || a.Session == nil || info.SessionID != a.Session.ID {
+		return nil
+	}
+	if !a.markNotified(info.ID) {
+		return nil
+	}
+	title := a.Session.Title
+	if title == "" {
+		title = "RyCode"
+	}
+	if info.Error.AsUnion() != nil {
+		if _, aborted := info.Error.AsUnion().(opencode.MessageAbortedError); aborted {
+			return nil
+		}
+		return a.Notify(NotifyErrors, title, "Reply failed")
+	}
+	return a.Notify(NotifyCompletions, title, "Reply finished")
+}
+
+// NotifyToolRun notifies a tool call finishing after running long, once
+func (a *App) NotifyToolRun(part opencode.ToolPart) tea.Cmd {
+	run := toolRun(part)
+	if run.Running || part.State.Status == opencode.ToolPartStateStatusPending || run.Elapsed(time.Now()) < notifyToolAfter {
+		return nil
+	}
+	if !a.markNotified(part.ID) {
+		return nil
+	}
+	label := runLabel(run)
+	if run.Failed {
+		return a.Notify(NotifyCompletions, "Tool call failed", label)
+	}
+	return a.Notify(NotifyCompletions, "Tool call finished", label)
+}
+
+// runLabel names a tool run for a notification
+func runLabel(run ToolRun) string {
+	if run.Label == "" {
+		return run.Tool
+	}
+	return run.Tool + ": " + run.Label
+}
+
+// markNotified records a message or part as notified, reporting whether it
+// wasn't already
+func (a *App) markNotified(id string) bool {
+	if a.notifier.notified == nil {
+		a.notifier.notified = make(map[string]bool)
+	}
+	if a.notifier.notified[id] {
+		return false
+	}
+	a.notifier.notified[id] = true
+	return true
+}
+
+// notification returns the escape sequence showing a notification in a
+// terminal, passed through tmux to the terminal outside it
+func notification(protocol splash.NotificationProtocol, title, body string, tmux bool) string {
+	clean := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if r < 0x20 || r == 0x7f {
+				return ' '
+			}
+			return r
+		}, s)
+	}
+	title, body = clean(title), clean(body)
+	var sequence string
+	switch protocol {
+	case splash.NotifyOSC9:
+		sequence = "\x1b]9;" + title + ": " + body + "\x07"
+	case splash.NotifyOSC777:
+		sequence = "\x1b]777;notify;" + strings.ReplaceAll(title, ";", ",") + ";" + body + "\x07"
+	default:
+		return "\a"
+	}
+	if tmux {
+		sequence = "\x1bPtmux;" + strings.ReplaceAll(sequence, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+	return sequence
+}
@@ -0,0 +1,25 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode/internal/splash"
+)
+
+func TestNotification(t *testing.T) {
+	tests := []struct {
+		protocol splash.NotificationProtocol
+		tmux     bool
+		want     string
+	}{
+		{splash.NotifyOSC9, false, "\x1b]9;Fix; tests: Reply finished\x07"},
+		{splash.NotifyOSC777, false, "\x1b]777;notify;Fix, tests;Reply finished\x07"},
+		{splash.NotifyOSC9, true, "\x1bPtmux;\x1b\x1b]9;Fix; tests: Reply finished\x07\x1b\\"},
+		{splash.NotifyBell, true, "\a"},
+	}
+	for _, test := range tests {
+		if got := notification(test.protocol, "Fix; tests", "Reply\nfinished", test.tmux); got != test.want {
+			t.Errorf("notification(%v, tmux %v) = %q, want %q", test.protocol, test.tmux, got, test.want)
+		}
+	}
+}
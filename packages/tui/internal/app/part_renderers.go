@@ -0,0 +1,124 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/plugin"
+)
+
+// PartRenderedMsg carries a plugin's drawing of a tool call
+type PartRenderedMsg struct {
+	PartID string
+	Width  int
+	Text   string
+	Err    error
+}
+
+// partRendering is a plugin's drawing of a tool call, empty while it's asked
+// for or when the plugin left the call to the chat
+type partRendering struct {
+	plugin string
+	width  int
+	text   string
+}
+
+// partRenderer returns the running plugin that draws the calls of a tool
+func (a *App) partRenderer(tool string) (string, plugin.PartRenderer, bool) {
+	for _, l := range a.Plugins() {
+		renderer, ok := l.Plugin.(plugin.PartRenderer)
+		if !l.Running() || !ok {
+			continue
+		}
+		for _, r := range l.Contributions.Renderers {
+			if r.Matches(tool) {
+				return l.Manifest.Name, renderer, true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// RenderParts asks plugins for the drawings of the session's finished tool
+// calls they render, which aren't drawn at the chat's width yet
+func (a *App) RenderParts() tea.Cmd {
+	current := make(map[string]bool)
+	var cmds []tea.Cmd
+	for _, message := range a.Messages {
+		for _, part := range message.Parts {
+			if tool, ok := part.(opencode.ToolPart); ok {
+				current[tool.ID] = true
+				cmds = append(cmds, a.RenderPart(tool))
+			}
+		}
+	}
+	// Drawings of other sessions' calls are dropped
+	for id := range a.partRenders {
+		if !current[id] {
+			delete(a.partRenders, id)
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// RenderPart asks a plugin for the drawing of a tool call once it has
+// finished, when one renders its tool
+func (a *App) RenderPart(part opencode.PartUnion) tea.Cmd {
+	tool, ok := part.(opencode.ToolPart)
+	if !ok || (tool.State.Status != opencode.ToolPartStateStatusCompleted && tool.State.Status != opencode.ToolPartStateStatusError) {
+		return nil
+	}
+	name, renderer, ok := a.partRenderer(tool.Tool)
+	if !ok {
+		return nil
+	}
+	// The drawing fits the content of the tool's block in the chat
+	width := max(20, layout.Current.Viewport.Width-10)
+	if r, ok := a.partRenders[tool.ID]; ok && r.plugin == name && r.width == width {
+		return nil
+	}
+	if a.partRenders == nil {
+		a.partRenders = make(map[string]partRendering)
+	}
+	a.partRenders[tool.ID] = partRendering{plugin: name, width: width}
+
+	request := plugin.Part{
+		Tool:   tool.Tool,
+		Status: string(tool.State.Status),
+		Output: tool.State.Output,
+		Error:  tool.State.Error,
+		Width:  width,
+	}
+	request.Input, _ = tool.State.Input.(map[string]any)
+	request.Metadata, _ = tool.State.Metadata.(map[string]any)
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), pluginTimeout)
+		defer cancel()
+		text, err := renderer.RenderPart(ctx, request)
+		return PartRenderedMsg{PartID: tool.ID, Width: width, Text: text, Err: err}
+	}
+}
+
+// SetPartRendering keeps a plugin's drawing of a tool call, unless the
+// call was asked to be drawn again since
+func (a *App) SetPartRendering(msg PartRenderedMsg) {
+	r, ok := a.partRenders[msg.PartID]
+	if !ok || r.width != msg.Width {
+		return
+	}
+	if msg.Err != nil {
+		slog.Warn("Plugin failed to draw a tool call", "plugin", r.plugin, "part", msg.PartID, "error", msg.Err)
+		return
+	}
+	r.text = msg.Text
+	a.partRenders[msg.PartID] = r
+}
+
+// PartRendering returns a plugin's drawing of a tool call, empty when the
+// chat draws it
+func (a *App) PartRendering(partID string) string {
+	return a.partRenders[partID].text
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/plugin"
+)
+
+type planRenderer struct{}
+
+func (planRenderer) Init(ctx context.Context, env plugin.Env) (plugin.Contributions, error) {
+	return plugin.Contributions{Renderers: []plugin.Renderer{{Tool: "terraform_*"}}}, nil
+}
+
+func (planRenderer) Update(ctx context.Context, event plugin.Event) (*plugin.Action, error) {
+	return nil, nil
+}
+
+func (planRenderer) View(ctx context.Context, widget string) (string, error) {
+	return "", nil
+}
+
+func (planRenderer) RenderPart(ctx context.Context, part plugin.Part) (string, error) {
+	return "plan: " + part.Output, nil
+}
+
+func (planRenderer) Close() error {
+	return nil
+}
+
+func TestRenderPart(t *testing.T) {
+	a := &App{}
+	a.plugins = plugin.NewHost(plugin.Env{})
+	a.plugins.Embed(plugin.Embedded{Name: "terraform", Plugin: planRenderer{}})
+	a.plugins.Load(t.TempDir(), t.TempDir(), nil)
+
+	tool := func(id, name string, status opencode.ToolPartStateStatus) opencode.ToolPart {
+		return opencode.ToolPart{ID: id, Tool: name, State: opencode.ToolPartState{Status: status, Output: "2 to add"}}
+	}
+	if cmd := a.RenderPart(tool("prt_1", "bash", opencode.ToolPartStateStatusCompleted)); cmd != nil {
+		t.Error("a tool no plugin renders was asked for")
+	}
+	if cmd := a.RenderPart(tool("prt_2", "terraform_plan", opencode.ToolPartStateStatusRunning)); cmd != nil {
+		t.Error("a running call was asked for")
+	}
+
+	cmd := a.RenderPart(tool("prt_3", "terraform_plan", opencode.ToolPartStateStatusCompleted))
+	if cmd == nil {
+		t.Fatal("a finished call wasn't asked for")
+	}
+	if again := a.RenderPart(tool("prt_3", "terraform_plan", opencode.ToolPartStateStatusCompleted)); again != nil {
+		t.Error("a call was asked for twice")
+	}
+	a.SetPartRendering(cmd().(PartRenderedMsg))
+	if got := a.PartRendering("prt_3"); got != "plan: 2 to add" {
+		t.Errorf("rendering = %q", got)
+	}
+
+	// Drawings of calls no longer in the session are dropped
+	a.RenderParts()
+	if got := a.PartRendering("prt_3"); got != "" {
+		t.Errorf("rendering after the session changed = %q", got)
+	}
+}
@@ -0,0 +1,146 @@
+package app
+
+import (
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/bench"
+	"github.com/aaronmrosenthal/rycode/internal/pathrules"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// ruleContextMessages is how many of the latest messages the files of the
+// active context are taken from
+const ruleContextMessages = 10
+
+// AppliedRule is a path rule that picked the agent or model of the last
+// prompt in place of the current ones
+type AppliedRule struct {
+	Rule       pathrules.Rule
+	Share      float64 // Share of the context's files the rule matched
+	Agent      string  // Empty when the rule kept the current agent
+	ProviderID string
+	Model      *opencode.Model // nil when the rule kept the current model
+}
+
+// PathRulesEnabled reports whether the project's path rules pick agents and
+// models. They are on unless turned off.
+func (a *App) PathRulesEnabled() bool {
+	return a.State.PathRules == nil || *a.State.PathRules
+}
+
+// SetPathRules turns the project's path rules on or off
+func (a *App) SetPathRules(enabled bool) tea.Cmd {
+	a.State.PathRules = &enabled
+	if !enabled {
+		a.AppliedRule = nil
+	}
+	return a.SaveState()
+}
+
+// PathRules reads the project's path rules
+func (a *App) PathRules() (*pathrules.Config, error) {
+	return pathrules.Load(util.RootPath)
+}
+
+// pathRule picks the rule whose paths dominate the files of a prompt and of
+// the latest messages, returning nil when no rule applies or when it would
+// change nothing. Rules naming an agent or model that isn't available keep
+// the current one.
+func (a *App) pathRule(prompt Prompt) *AppliedRule {
+	if !a.PathRulesEnabled() {
+		return nil
+	}
+	config, err := a.PathRules()
+	if err != nil {
+		slog.Warn("Failed to load path rules", "error", err)
+		return nil
+	}
+	rule, share := config.Select(a.contextFiles(prompt))
+	if rule == nil {
+		return nil
+	}
+
+	applied := &AppliedRule{Rule: *rule, Share: share}
+	if rule.Agent != "" && rule.Agent != a.Agent().Name {
+		for _, agent := range a.Agents {
+			if agent.Name == rule.Agent && agent.Mode != "subagent" {
+				applied.Agent = agent.Name
+			}
+		}
+		if applied.Agent == "" {
+			slog.Warn("Path rule agent is not available", "rule", rule.Label(), "agent", rule.Agent)
+		}
+	}
+	if rule.Model != "" {
+		ref, _ := bench.ParseModelRef(rule.Model)
+		provider, model := findModelByProviderAndModelID(a.Providers, ref.ProviderID, ref.ModelID)
+		switch {
+		case provider == nil || model == nil:
+			slog.Warn("Path rule model is not available", "rule", rule.Label(), "model", rule.Model)
+		case a.Provider == nil || a.Model == nil || provider.ID != a.Provider.ID || model.ID != a.Model.ID:
+			applied.ProviderID = provider.ID
+			applied.Model = model
+		}
+	}
+	if applied.Agent == "" && applied.Model == nil {
+		return nil
+	}
+	return applied
+}
+
+// contextFiles returns the project files a prompt attaches and that the
+// latest messages attached, read or changed, relative to the project root
+func (a *App) contextFiles(prompt Prompt) []string {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(file string) {
+		if file == "" {
+			return
+		}
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(util.CwdPath, file)
+		}
+		rel, err := filepath.Rel(util.RootPath, file)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return
+		}
+		file = filepath.ToSlash(rel)
+		if !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+
+	for _, att := range prompt.Attachments {
+		if source, ok := att.GetFileSource(); ok {
+			add(source.Path)
+		} else if source, ok := att.GetSymbolSource(); ok {
+			add(source.Path)
+		}
+	}
+	for _, message := range a.Messages[max(0, len(a.Messages)-ruleContextMessages):] {
+		for _, part := range message.Parts {
+			switch part := part.(type) {
+			case opencode.FilePart:
+				add(part.Source.Path)
+			case opencode.ToolPart:
+				if input, ok := part.State.Input.(map[string]any); ok {
+					for _, key := range []string{"filePath", "path"} {
+						if file, ok := input[key].(string); ok {
+							add(file)
+						}
+					}
+				}
+			case opencode.PartPatchPart:
+				for _, file := range part.Files {
+					add(file)
+				}
+			}
+		}
+	}
+	return files
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/permissions"
+)
+
+// PermissionRule returns the remembered rule answering a permission request
+// in this project, nil when the user is asked
+func (a *App) PermissionRule(permission opencode.Permission) *permissions.Rule {
+	return permissions.Decide(a.State.PermissionRules, permission, a.historyProject())
+}
+
+// RememberPermission keeps an answer to a permission request as a rule for
+// requests like it in this project
+func (a *App) RememberPermission(permission opencode.Permission, allow bool) tea.Cmd {
+	return a.AddPermissionRule(permissions.Remember(permission, allow, a.historyProject()))
+}
+
+// AddPermissionRule adds a rule before the others, replacing one for the
+// same tool, pattern and project
+func (a *App) AddPermissionRule(rule permissions.Rule) tea.Cmd {
+	rules := []permissions.Rule{rule}
+	for _, r := range a.State.PermissionRules {
+		if r.Tool != rule.Tool || r.Pattern != rule.Pattern || r.Project != rule.Project {
+			rules = append(rules, r)
+		}
+	}
+	a.State.PermissionRules = rules
+	return a.SaveState()
+}
+
+// SetPermissionRule changes a rule, by index
+func (a *App) SetPermissionRule(index int, rule permissions.Rule) tea.Cmd {
+	if index < 0 || index >= len(a.State.PermissionRules) {
+		return nil
+	}
+	a.State.PermissionRules[index] = rule
+	return a.SaveState()
+}
+
+// RemovePermissionRule forgets a rule, by index
+func (a *App) RemovePermissionRule(index int) tea.Cmd {
+	if index < 0 || index >= len(a.State.PermissionRules) {
+		return nil
+	}
+	a.State.PermissionRules = append(a.State.PermissionRules[:index], a.State.PermissionRules[index+1:]...)
+	return a.SaveState()
+}
+
+// PermissionProject is the project rules remembered here hold in
+func (a *App) PermissionProject() string {
+	return a.historyProject()
+}
+
+// RespondPermission answers a permission request. Those asked for the
+// commands of shell inboxes and for project tool calls are answered here.
+func (a *App) RespondPermission(sessionID, permissionID string, response opencode.SessionPermissionRespondParamsResponse) tea.Cmd {
+	if cmd, ok := a.answerShellPermission(permissionID, response); ok {
+		return cmd
+	}
+	if cmd, ok := a.answerToolPermission(permissionID, response); ok {
+		return cmd
+	}
+	return func() tea.Msg {
+		resp, err := a.Client.Session.Permissions.Respond(
+			context.Background(),
+			sessionID,
+			permissionID,
+			opencode.SessionPermissionRespondParams{Response: opencode.F(response)},
+		)
+		if err != nil {
+			slog.Error("Failed to respond to permission request", "error", err)
+			return toast.NewErrorToast("Failed to respond to permission request")()
+		}
+		slog.Debug("Responded to permission request", "response", resp)
+		return nil
+	}
+}
@@ -0,0 +1,166 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/commands"
+	"github.com/aaronmrosenthal/rycode/internal/plugin"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// pluginTimeout bounds a plugin's answer to an event or a widget
+const pluginTimeout = 5 * time.Second
+
+// PluginsLoadedMsg is sent once the installed plugins have started
+type PluginsLoadedMsg struct {
+	Plugins []plugin.Loaded
+}
+
+// PluginChangedMsg is sent when a plugin was turned on or off
+type PluginChangedMsg struct {
+	Plugin plugin.Loaded
+	Err    error
+}
+
+// PluginActionMsg carries what a plugin asked for in answer to an event
+type PluginActionMsg struct {
+	Plugin string
+	Action *plugin.Action
+	Err    error
+}
+
+// LoadPlugins starts the installed plugins that aren't disabled
+func (a *App) LoadPlugins() tea.Cmd {
+	env := plugin.Env{Version: a.Version, Root: util.RootPath, Cwd: util.CwdPath}
+	if a.Bridge != nil {
+		env.Bridge = a.Bridge.Socket()
+	}
+	a.plugins = plugin.NewHost(env)
+	a.plugins.Embed(a.EmbeddedPlugins...)
+	host, disabled := a.plugins, slices.Clone(a.State.DisabledPlugins)
+	return func() tea.Msg {
+		host.Load(a.ConfigDir, util.RootPath, disabled)
+		return PluginsLoadedMsg{Plugins: host.Plugins()}
+	}
+}
+
+// Plugins returns the installed plugins
+func (a *App) Plugins() []plugin.Loaded {
+	if a.plugins == nil {
+		return nil
+	}
+	return a.plugins.Plugins()
+}
+
+// SetPluginEnabled turns a plugin on or off, remembering the choice
+func (a *App) SetPluginEnabled(name string, enabled bool) tea.Cmd {
+	if a.plugins == nil {
+		return nil
+	}
+	a.State.DisabledPlugins = slices.DeleteFunc(a.State.DisabledPlugins, func(n string) bool { return n == name })
+	if !enabled {
+		a.State.DisabledPlugins = append(a.State.DisabledPlugins, name)
+	}
+	host := a.plugins
+	return tea.Batch(a.SaveState(), func() tea.Msg {
+		loaded, err := host.SetEnabled(name, enabled)
+		return PluginChangedMsg{Plugin: loaded, Err: err}
+	})
+}
+
+// AddPluginCommands updates the commands of plugins that started or
+// stopped. A command's trigger is its plugin's name and its own when
+// another command already has it.
+func (a *App) AddPluginCommands(plugins ...plugin.Loaded) {
+	for _, l := range plugins {
+		for name, command := range a.Commands {
+			if command.Plugin == l.Manifest.Name {
+				delete(a.Commands, name)
+			}
+		}
+		if !l.Running() {
+			continue
+		}
+		for _, c := range l.Contributions.Commands {
+			trigger := c.Trigger
+			if trigger == "" {
+				trigger = c.Name
+			}
+			if _, taken := a.Commands.FindByTrigger(trigger); taken {
+				trigger = l.Manifest.Name + "-" + trigger
+			}
+			name := commands.CommandName(l.Manifest.Name + ":" + c.Name)
+			a.Commands[name] = commands.Command{
+				Name:        name,
+				Description: c.Description,
+				Trigger:     []string{trigger},
+				AcceptsArgs: true,
+				Plugin:      l.Manifest.Name,
+			}
+		}
+	}
+}
+
+// RunPluginCommand runs a command added by a plugin
+func (a *App) RunPluginCommand(command commands.Command, args string) tea.Cmd {
+	_, name, _ := strings.Cut(string(command.Name), ":")
+	return a.PluginEvent(command.Plugin, plugin.Event{Type: plugin.EventCommand, Command: name, Args: args})
+}
+
+// PluginEvent tells a plugin of an event, off the UI goroutine
+func (a *App) PluginEvent(name string, event plugin.Event) tea.Cmd {
+	if a.plugins == nil {
+		return nil
+	}
+	l, ok := a.plugins.Get(name)
+	if !ok || !l.Running() {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), pluginTimeout)
+		defer cancel()
+		action, err := l.Plugin.Update(ctx, event)
+		return PluginActionMsg{Plugin: name, Action: action, Err: err}
+	}
+}
+
+// BroadcastPluginEvent tells every running plugin of an event
+func (a *App) BroadcastPluginEvent(event plugin.Event) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, l := range a.Plugins() {
+		cmds = append(cmds, a.PluginEvent(l.Manifest.Name, event))
+	}
+	return tea.Batch(cmds...)
+}
+
+// PluginView draws a plugin's widget. It blocks, so it is called where
+// widgets are polled.
+func (a *App) PluginView(name, widget string) string {
+	if a.plugins == nil {
+		return ""
+	}
+	l, ok := a.plugins.Get(name)
+	if !ok || !l.Running() {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pluginTimeout)
+	defer cancel()
+	text, err := l.Plugin.View(ctx, widget)
+	if err != nil {
+		slog.Debug("Plugin widget failed", "plugin", name, "widget", widget, "error", err)
+		return ""
+	}
+	return text
+}
+
+// ClosePlugins stops the running plugins
+func (a *App) ClosePlugins() {
+	if a.plugins != nil {
+		a.plugins.Close()
+	}
+}
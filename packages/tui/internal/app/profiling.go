@@ -0,0 +1,108 @@
+package app
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/performance"
+)
+
+// ProfileDir returns where CPU and heap profiles are written, next to the
+// TUI state file. Profiles describe the TUI's own process, so they stay on
+// this machine when the project is remote.
+func (a *App) ProfileDir() string {
+	return filepath.Join(filepath.Dir(a.StatePath), "profiles")
+}
+
+// StartProfilingFromEnv serves the pprof endpoints from launch when
+// RYCODE_PPROF is set, to an address or to 1 for the default one
+func (a *App) StartProfilingFromEnv() tea.Cmd {
+	addr := strings.TrimSpace(os.Getenv(performance.PprofEnv))
+	switch strings.ToLower(addr) {
+	case "", "0", "false", "off":
+		return nil
+	case "1", "true", "on":
+		addr = ""
+	}
+	return a.SetPprof(true, addr)
+}
+
+// SetPprof starts serving the pprof endpoints on addr, or the default
+// address when addr is "", or stops serving them. The render profiler
+// records while they are served, so its timings are there to read.
+func (a *App) SetPprof(on bool, addr string) tea.Cmd {
+	if !on {
+		if err := performance.StopPprof(); err != nil {
+			slog.Warn("Failed to stop pprof", "error", err)
+		}
+		return toast.NewInfoToast("pprof endpoint stopped")
+	}
+	listening, err := performance.StartPprof(addr)
+	if err != nil {
+		return toast.NewErrorToast(err.Error(), toast.WithTitle("pprof"))
+	}
+	performance.GetMonitor().Enable()
+	slog.Info("Serving pprof", "addr", listening)
+	return toast.NewSuccessToast(
+		"go tool pprof http://"+listening+"/debug/pprof/profile\nFrame timings: http://"+listening+"/debug/pprof/frames",
+		toast.WithTitle("pprof on "+listening),
+	)
+}
+
+// ToggleCPUProfile starts profiling the CPU, or stops and writes the
+// profile when it is running
+func (a *App) ToggleCPUProfile() tea.Cmd {
+	if performance.CPUProfiling() {
+		path, err := performance.StopCPUProfile()
+		if err != nil {
+			return toast.NewErrorToast(err.Error())
+		}
+		return toast.NewSuccessToast("go tool pprof "+path, toast.WithTitle("CPU profile written"))
+	}
+	if _, err := performance.StartCPUProfile(a.ProfileDir()); err != nil {
+		return toast.NewErrorToast(err.Error())
+	}
+	performance.GetMonitor().Enable()
+	return toast.NewInfoToast("Reproduce the slowdown, then stop the profile to write it", toast.WithTitle("Profiling the CPU"))
+}
+
+// WriteHeapProfile writes the sampled allocations to the profile directory
+func (a *App) WriteHeapProfile() tea.Cmd {
+	return func() tea.Msg {
+		path, err := performance.WriteHeapProfile(a.ProfileDir())
+		if err != nil {
+			return toast.NewErrorToast(err.Error())()
+		}
+		return toast.NewSuccessToast("go tool pprof "+path, toast.WithTitle("Heap profile written"))()
+	}
+}
+
+// SetAllocSampling samples allocations closely for heap profiles, or goes
+// back to the default rate
+func (a *App) SetAllocSampling(on bool) tea.Cmd {
+	performance.SetAllocSampling(on)
+	if on {
+		performance.GetMonitor().Enable()
+		return toast.NewInfoToast("Allocations are sampled every 4 KB until turned off; heap profiles show them")
+	}
+	return toast.NewInfoToast("Allocations are sampled at Go's default rate")
+}
+
+// StopProfiling writes a running CPU profile and stops the pprof endpoint,
+// as the TUI exits
+func (a *App) StopProfiling() {
+	if performance.CPUProfiling() {
+		if path, err := performance.StopCPUProfile(); err != nil {
+			slog.Warn("Failed to write CPU profile", "error", err)
+		} else {
+			slog.Info("CPU profile written", "path", path)
+		}
+	}
+	if err := performance.StopPprof(); err != nil {
+		slog.Warn("Failed to stop pprof", "error", err)
+	}
+}
@@ -0,0 +1,162 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/bridge"
+	"github.com/aaronmrosenthal/rycode/internal/projecttools"
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// projectToolTimeout bounds a run of a project tool called by the agent
+const projectToolTimeout = 10 * time.Minute
+
+// ProjectTools reads the tools the project declares for the agent
+func (a *App) ProjectTools() ([]projecttools.Tool, error) {
+	return projecttools.Load(util.RootPath)
+}
+
+// ProjectToolsRegisteredMsg is sent when the project's tools were
+// registered with the server
+type ProjectToolsRegisteredMsg struct {
+	Changed bool // A tool changed since the server started, which reads them then
+	Err     error
+}
+
+// RegisterProjectTools registers the project's tools with the server.
+// They aren't on a remote worktree, where the modules run on the remote
+// machine and can't reach the TUI's bridge.
+func (a *App) RegisterProjectTools() tea.Cmd {
+	return func() tea.Msg {
+		tools, err := a.ProjectTools()
+		if err != nil {
+			return ProjectToolsRegisteredMsg{Err: err}
+		}
+		if remote.Active() != nil {
+			if len(tools) > 0 {
+				return ProjectToolsRegisteredMsg{Err: errors.New("the agent can't call project tools on a remote worktree; run them with /tools")}
+			}
+			return ProjectToolsRegisteredMsg{}
+		}
+		changed, err := projecttools.Register(util.RootPath, tools)
+		return ProjectToolsRegisteredMsg{Changed: changed, Err: err}
+	}
+}
+
+// toolPermissionPrefix starts the IDs of the permissions asked for project
+// tool calls, which are answered here rather than by the server
+const toolPermissionPrefix = "tool_"
+
+// toolRequest is a project tool call waiting on its permission
+type toolRequest struct {
+	request bridge.RequestMsg
+	tool    projecttools.Tool
+	args    map[string]any
+	command string
+}
+
+// RunProjectTool asks permission for a project tool the agent called
+// through its module, as for its bash calls, with the tool's command. The
+// request goes through the permission rules and prompt as the server's
+// do, and the call is answered with the tool's output once allowed, or
+// with an error when denied.
+func (a *App) RunProjectTool(request bridge.RequestMsg) tea.Cmd {
+	var params bridge.ToolParams
+	if err := request.Decode(&params); err != nil {
+		request.Reply(nil, err)
+		return nil
+	}
+	tools, err := a.ProjectTools()
+	if err != nil {
+		request.Reply(nil, err)
+		return nil
+	}
+	i := slices.IndexFunc(tools, func(t projecttools.Tool) bool { return t.Name == params.Name })
+	if i < 0 {
+		request.Reply(nil, fmt.Errorf("%w: no project tool named %s", bridge.ErrInvalidParams, params.Name))
+		return nil
+	}
+	command, err := tools[i].Render(params.Args)
+	if err != nil {
+		request.Reply(nil, fmt.Errorf("%w: %w", bridge.ErrInvalidParams, err))
+		return nil
+	}
+
+	if a.toolRequests == nil {
+		a.toolRequests = make(map[string]toolRequest)
+	}
+	a.toolAsked++
+	id := fmt.Sprintf("%s%d", toolPermissionPrefix, a.toolAsked)
+	a.toolRequests[id] = toolRequest{request: request, tool: tools[i], args: params.Args, command: command}
+	return util.CmdHandler(opencode.EventListResponseEventPermissionUpdated{
+		Type: opencode.EventListResponseEventPermissionUpdatedTypePermissionUpdated,
+		Properties: opencode.Permission{
+			ID:        id,
+			SessionID: a.Session.ID,
+			Type:      "bash",
+			Title:     command,
+			Metadata:  map[string]any{"command": command, "tool": params.Name},
+			Time:      opencode.PermissionTime{Created: float64(time.Now().UnixMilli())},
+		},
+	})
+}
+
+// ProjectToolPermissionPart returns the bash call a permission asked for a
+// project tool call stands for, to be shown as the server's requests are
+func (a *App) ProjectToolPermissionPart(permission opencode.Permission) (opencode.ToolPart, bool) {
+	request, ok := a.toolRequests[permission.ID]
+	if !ok {
+		return opencode.ToolPart{}, false
+	}
+	return opencode.ToolPart{
+		ID:     permission.ID,
+		Tool:   "bash",
+		CallID: permission.CallID,
+		State: opencode.ToolPartState{
+			Status: opencode.ToolPartStateStatusRunning,
+			Title:  "Run project tool " + request.tool.Name,
+			Input:  map[string]any{"command": request.command, "description": "Run project tool " + request.tool.Name},
+		},
+	}, true
+}
+
+// answerToolPermission runs the project tool a permission was asked for
+// once it's allowed, and answers the call. It reports whether the
+// permission was one of those.
+func (a *App) answerToolPermission(permissionID string, response opencode.SessionPermissionRespondParamsResponse) (tea.Cmd, bool) {
+	request, ok := a.toolRequests[permissionID]
+	if !ok {
+		return nil, false
+	}
+	delete(a.toolRequests, permissionID)
+	if response == opencode.SessionPermissionRespondParamsResponseReject {
+		slog.Info("Rejected project tool call from the agent", "tool", request.tool.Name)
+		request.request.Reply(nil, fmt.Errorf("the user denied running %s", request.tool.Name))
+		return nil, true
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), projectToolTimeout)
+		defer cancel()
+		output, err := request.tool.Run(ctx, util.RootPath, request.args)
+		var exitErr interface{ ExitCode() int }
+		switch {
+		case errors.As(err, &exitErr):
+			// A failing command is a result for the agent to read
+			request.request.Reply(fmt.Sprintf("%s\nExited with status %d", output, exitErr.ExitCode()), nil)
+		case err != nil:
+			request.request.Reply(nil, err)
+		default:
+			request.request.Reply(output, nil)
+		}
+		slog.Info("Ran a project tool for the agent", "tool", request.tool.Name, "error", err)
+		return nil
+	}, true
+}
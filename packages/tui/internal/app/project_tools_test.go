@@ -0,0 +1,61 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/bridge"
+	"github.com/aaronmrosenthal/rycode/internal/permissions"
+	"github.com/aaronmrosenthal/rycode/internal/projecttools"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+func TestRunProjectToolAsksPermission(t *testing.T) {
+	root := t.TempDir()
+	saved := util.RootPath
+	util.RootPath = root
+	t.Cleanup(func() { util.RootPath = saved })
+	config := `{"tools": [{"name": "db:migrate", "description": "Migrate", "command": "npm run migrate -- {{.to}}"}]}`
+	if err := os.WriteFile(filepath.Join(root, projecttools.ConfigPath), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := &App{Session: &opencode.Session{ID: "ses_1"}, State: NewState()}
+	call := bridge.RequestMsg{Method: bridge.MethodTool, Params: []byte(`{"name": "db:migrate", "args": {"to": "v2"}}`)}
+	cmd := a.RunProjectTool(call)
+	if cmd == nil {
+		t.Fatal("the call was answered without asking")
+	}
+	asked, ok := cmd().(opencode.EventListResponseEventPermissionUpdated)
+	if !ok {
+		t.Fatalf("the call asked %T, want a permission request", cmd())
+	}
+	permission := asked.Properties
+	if permission.Type != "bash" || permissions.Subject(permission) != "npm run migrate -- v2" {
+		t.Fatalf("permission = %+v, want a bash request for the tool's command", permission)
+	}
+	if part, ok := a.ProjectToolPermissionPart(permission); !ok || part.Tool != "bash" {
+		t.Errorf("ProjectToolPermissionPart = %+v, %v", part, ok)
+	}
+
+	// Rules answer project tool calls as they do bash calls
+	a.State.PermissionRules = []permissions.Rule{{Tool: "bash", Pattern: "npm run migrate *", Allow: false}}
+	if rule := a.PermissionRule(permission); rule == nil || rule.Allow {
+		t.Errorf("rule = %v, want the deny rule", rule)
+	}
+
+	if cmd := a.RespondPermission(permission.SessionID, permission.ID, opencode.SessionPermissionRespondParamsResponseReject); cmd != nil {
+		t.Error("a denied tool was run")
+	}
+	if _, ok := a.ProjectToolPermissionPart(permission); ok {
+		t.Error("an answered request is still waiting")
+	}
+
+	// Unknown tools are refused without asking
+	call.Params = []byte(`{"name": "deploy"}`)
+	if cmd := a.RunProjectTool(call); cmd != nil {
+		t.Error("a call of an undeclared tool asked permission")
+	}
+}
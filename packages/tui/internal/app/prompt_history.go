@@ -0,0 +1,196 @@
+package app
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/attachment"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// promptHistoryLimit is the number of prompts the history file keeps
+const promptHistoryLimit = 5000
+
+// HistoryEntry is a prompt submitted, with the project it was submitted in
+type HistoryEntry struct {
+	Text        string                   `toml:"text"`
+	Attachments []*attachment.Attachment `toml:"attachments,omitempty"`
+	Project     string                   `toml:"project,omitempty"` // Empty for prompts of any project
+	Time        time.Time                `toml:"time"`
+}
+
+// PromptHistory is every prompt submitted, oldest first. It is kept in a
+// TOML file of all the projects that each prompt is appended to.
+type PromptHistory struct {
+	path    string
+	entries []HistoryEntry
+}
+
+// promptHistoryFile is the TOML document of the history file. Each prompt
+// is appended as a [[prompt]] table.
+type promptHistoryFile struct {
+	Prompts []HistoryEntry `toml:"prompt"`
+}
+
+// LoadPromptHistory reads the history file. One that doesn't exist yet is
+// an empty history; one over the limit is cut down to its newest prompts.
+func LoadPromptHistory(path string) (*PromptHistory, error) {
+	h := &PromptHistory{path: path}
+	var file promptHistoryFile
+	if _, err := toml.DecodeFile(path, &file); err != nil {
+		if os.IsNotExist(err) {
+			return h, nil
+		}
+		return h, fmt.Errorf("failed to decode prompt history %s: %w", path, err)
+	}
+	for _, entry := range file.Prompts {
+		for _, att := range entry.Attachments {
+			att.RestoreSourceType()
+		}
+	}
+	h.entries = file.Prompts
+	if len(h.entries) > promptHistoryLimit {
+		h.entries = h.entries[len(h.entries)-promptHistoryLimit:]
+		if err := h.write(os.O_TRUNC, h.entries); err != nil {
+			return h, err
+		}
+	}
+	return h, nil
+}
+
+// Add appends a prompt to the history and its file
+func (h *PromptHistory) Add(entry HistoryEntry) error {
+	h.entries = append(h.entries, entry)
+	return h.write(os.O_APPEND, []HistoryEntry{entry})
+}
+
+func (h *PromptHistory) write(mode int, entries []HistoryEntry) error {
+	if h.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(h.path, os.O_CREATE|os.O_WRONLY|mode, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open prompt history %s: %w", h.path, err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	if err := toml.NewEncoder(writer).Encode(promptHistoryFile{Prompts: entries}); err != nil {
+		return fmt.Errorf("failed to encode prompt history %s: %w", h.path, err)
+	}
+	return writer.Flush()
+}
+
+// Prompts returns the prompts of a project, or of every project when
+// project is empty, newest first. A prompt submitted again is only listed
+// where it was last submitted.
+func (h *PromptHistory) Prompts(project string) []Prompt {
+	var prompts []Prompt
+	seen := map[string]bool{}
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		entry := h.entries[i]
+		if project != "" && entry.Project != "" && entry.Project != project {
+			continue
+		}
+		if seen[entry.Text] {
+			continue
+		}
+		seen[entry.Text] = true
+		prompts = append(prompts, Prompt{Text: entry.Text, Attachments: entry.Attachments})
+	}
+	return prompts
+}
+
+// SearchPrompts returns the index of the first prompt, from the one at
+// from on, that contains the query, ignoring case, or -1 when none does
+func SearchPrompts(prompts []Prompt, query string, from int) int {
+	query = strings.ToLower(query)
+	for i := max(0, from); i < len(prompts); i++ {
+		if strings.Contains(strings.ToLower(prompts[i].Text), query) {
+			return i
+		}
+	}
+	return -1
+}
+
+// promptHistory returns the history, read from the file next to the state
+// the first time. The prompts the state kept before are carried into it.
+func (a *App) promptHistory() *PromptHistory {
+	if a.history != nil {
+		return a.history
+	}
+	// Without a state file the history is only kept in memory
+	history := &PromptHistory{}
+	if a.StatePath != "" {
+		var err error
+		history, err = LoadPromptHistory(filepath.Join(filepath.Dir(a.StatePath), "prompt_history.toml"))
+		if err != nil {
+			slog.Error("Failed to load prompt history", "error", err)
+		}
+	}
+	a.history = history
+	if len(history.entries) == 0 && len(a.State.MessageHistory) > 0 {
+		for i := len(a.State.MessageHistory) - 1; i >= 0; i-- {
+			prompt := a.State.MessageHistory[i]
+			if err := history.Add(HistoryEntry{Text: prompt.Text, Attachments: prompt.Attachments}); err != nil {
+				slog.Error("Failed to carry prompts into the history", "error", err)
+				return history
+			}
+		}
+		a.State.MessageHistory = nil
+		if a.StatePath != "" {
+			a.saveStateNow()
+		}
+	}
+	return history
+}
+
+// historyProject is the project prompts are scoped to
+func (a *App) historyProject() string {
+	if a.Project.Worktree != "" {
+		return a.Project.Worktree
+	}
+	return util.CwdPath
+}
+
+// History returns the prompts submitted, newest first: those of the
+// project, or of every project with the global history on
+func (a *App) History() []Prompt {
+	if a.State.GlobalHistory {
+		return a.promptHistory().Prompts("")
+	}
+	return a.promptHistory().Prompts(a.historyProject())
+}
+
+// AddToHistory records a prompt submitted in the project
+func (a *App) AddToHistory(prompt Prompt) tea.Cmd {
+	entry := HistoryEntry{
+		Text:        prompt.Text,
+		Attachments: prompt.Attachments,
+		Project:     a.historyProject(),
+		Time:        time.Now(),
+	}
+	if err := a.promptHistory().Add(entry); err != nil {
+		slog.Error("Failed to save prompt history", "error", err)
+		return toast.NewErrorToast("Failed to save the prompt to the history")
+	}
+	return nil
+}
+
+// SetGlobalHistory scopes the history to every project, or to the
+// current one
+func (a *App) SetGlobalHistory(global bool) tea.Cmd {
+	a.State.GlobalHistory = global
+	return a.SaveState()
+}
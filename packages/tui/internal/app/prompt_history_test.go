@@ -0,0 +1,72 @@
+package app
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aaronmrosenthal/rycode/internal/attachment"
+)
+
+func TestPromptHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prompt_history.toml")
+	history, err := LoadPromptHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := &attachment.Attachment{
+		ID:         "a1",
+		Type:       "file",
+		Display:    "@main.go",
+		StartIndex: 8,
+		EndIndex:   16,
+		Source:     &attachment.FileSource{Path: "main.go", Mime: "text/plain"},
+	}
+	for _, entry := range []HistoryEntry{
+		{Text: "fix the build", Project: "/api"},
+		{Text: "explain @main.go", Attachments: []*attachment.Attachment{file}, Project: "/api"},
+		{Text: "write the changelog", Project: "/web"},
+		{Text: "fix the build", Project: "/web"},
+		{Text: "carried from the state"},
+	} {
+		entry.Time = time.Now()
+		if err := history.Add(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history, err = LoadPromptHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	texts := func(prompts []Prompt) []string {
+		var texts []string
+		for _, p := range prompts {
+			texts = append(texts, p.Text)
+		}
+		return texts
+	}
+	api := history.Prompts("/api")
+	if want := []string{"carried from the state", "explain @main.go", "fix the build"}; !slices.Equal(texts(api), want) {
+		t.Errorf("prompts of /api = %q, want %q", texts(api), want)
+	}
+	if source, ok := api[1].Attachments[0].GetFileSource(); !ok || source.Path != "main.go" {
+		t.Errorf("attachment read back = %+v, want its file source", api[1].Attachments[0])
+	}
+	all := history.Prompts("")
+	if want := []string{"carried from the state", "fix the build", "write the changelog", "explain @main.go"}; !slices.Equal(texts(all), want) {
+		t.Errorf("prompts of every project = %q, want %q", texts(all), want)
+	}
+
+	if i := SearchPrompts(all, "BUILD", 0); i != 1 {
+		t.Errorf("search for BUILD = %d, want 1", i)
+	}
+	if i := SearchPrompts(all, "the", 2); i != 2 {
+		t.Errorf("search for the from 2 = %d, want 2", i)
+	}
+	if i := SearchPrompts(all, "deploy", 0); i != -1 {
+		t.Errorf("search for deploy = %d, want -1", i)
+	}
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// ProviderAgent returns the agent paired with a provider, which becomes the
+// current agent when switching to the provider, or "" when none is
+func (a *App) ProviderAgent(providerID string) string {
+	return a.State.ProviderAgents[providerID]
+}
+
+// SetProviderAgent pairs an agent with a provider, or unpairs the provider
+// when the agent is ""
+func (a *App) SetProviderAgent(providerID, agent string) (tea.Cmd, error) {
+	if agent == "" {
+		delete(a.State.ProviderAgents, providerID)
+		return a.SaveState(), nil
+	}
+	if a.primaryAgentIndex(agent) < 0 {
+		return nil, fmt.Errorf("no agent named %q; agents are %s", agent, strings.Join(a.PrimaryAgents(), ", "))
+	}
+	if a.State.ProviderAgents == nil {
+		a.State.ProviderAgents = make(map[string]string)
+	}
+	a.State.ProviderAgents[providerID] = agent
+	return a.SaveState(), nil
+}
+
+// PrimaryAgents returns the names of the agents that can be the current
+// agent, leaving out subagents
+func (a *App) PrimaryAgents() []string {
+	var names []string
+	for _, agent := range a.Agents {
+		if agent.Mode != "subagent" {
+			names = append(names, agent.Name)
+		}
+	}
+	return names
+}
+
+// NextProviderAgent returns the agent after the one paired with a provider,
+// cycling through the primary agents and then no agent
+func (a *App) NextProviderAgent(providerID string) string {
+	names := a.PrimaryAgents()
+	i := slices.Index(names, a.ProviderAgent(providerID))
+	if i+1 >= len(names) {
+		return ""
+	}
+	return names[i+1]
+}
+
+// SwitchProviderAgent makes the agent paired with a provider the current
+// agent, keeping the current model. It returns the agent switched to, or
+// "" when the provider has no agent or its agent is already current.
+func (a *App) SwitchProviderAgent(providerID string) string {
+	name := a.ProviderAgent(providerID)
+	i := a.primaryAgentIndex(name)
+	if name == "" || i < 0 || i == a.AgentIndex {
+		return ""
+	}
+	a.AgentIndex = i
+	a.State.Agent = name
+	a.State.UpdateAgentUsage(name)
+	return name
+}
+
+func (a *App) primaryAgentIndex(name string) int {
+	for i, agent := range a.Agents {
+		if agent.Name == name && agent.Mode != "subagent" {
+			return i
+		}
+	}
+	return -1
+}
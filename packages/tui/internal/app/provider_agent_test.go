@@ -0,0 +1,47 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+func TestProviderAgent(t *testing.T) {
+	a := &App{
+		StatePath: filepath.Join(t.TempDir(), "tui"),
+		State:     NewState(),
+		Agents: []opencode.Agent{
+			{Name: "build", Mode: "primary"},
+			{Name: "general", Mode: "subagent"},
+			{Name: "plan", Mode: "primary"},
+		},
+	}
+
+	if _, err := a.SetProviderAgent("anthropic", "general"); err == nil {
+		t.Error("paired a subagent with a provider")
+	}
+	if _, err := a.SetProviderAgent("anthropic", "plan"); err != nil {
+		t.Fatal(err)
+	}
+	if agent := a.SwitchProviderAgent("anthropic"); agent != "plan" || a.Agent().Name != "plan" {
+		t.Errorf("switched to %q, agent is %q, want plan", agent, a.Agent().Name)
+	}
+	if agent := a.SwitchProviderAgent("anthropic"); agent != "" {
+		t.Errorf("switched again to %q, want the current agent kept", agent)
+	}
+	if agent := a.SwitchProviderAgent("openai"); agent != "" || a.Agent().Name != "plan" {
+		t.Errorf("a provider without an agent switched to %q", agent)
+	}
+
+	// Pairing cycles through the primary agents, then none
+	var cycle []string
+	for range 3 {
+		next := a.NextProviderAgent("openai")
+		cycle = append(cycle, next)
+		a.SetProviderAgent("openai", next)
+	}
+	if cycle[0] != "build" || cycle[1] != "plan" || cycle[2] != "" {
+		t.Errorf("pairing cycle = %q, want build, plan, none", cycle)
+	}
+}
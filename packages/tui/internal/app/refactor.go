@@ -0,0 +1,209 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/docgen"
+	"github.com/aaronmrosenthal/rycode/internal/refactor"
+	"github.com/aaronmrosenthal/rycode/internal/testgen"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// RefactorPlannedMsg is sent when the agent has proposed the files a
+// refactor affects
+type RefactorPlannedMsg struct {
+	Plan *refactor.Plan
+	Err  error
+}
+
+// RefactorBatchDoneMsg is sent when the agent has finished a batch of files
+type RefactorBatchDoneMsg struct {
+	Batch     []int
+	SessionID string
+	Err       error
+}
+
+// RefactorCheckedMsg is sent when the check of a reviewed plan, such as its
+// test run, has finished
+type RefactorCheckedMsg struct {
+	Output string
+	Err    error
+}
+
+// refactorCheckTimeout bounds how long a plan's check may run
+const refactorCheckTimeout = 10 * time.Minute
+
+const refactorPlanningPrompt = `List every file in this repository that must change to make the following refactor:
+
+%s
+
+Do not edit anything. Reply with one repository-relative file path per line and nothing else.`
+
+const refactorBatchPrompt = `Workspace refactor: %s
+
+Apply this refactor to the following files only, editing them in place. Do not modify any other file.
+
+%s`
+
+const instructedBatchPrompt = `%s
+
+Only edit the following files, in place. Do not modify any other file.
+
+%s`
+
+// PlanRefactor asks the agent which files a refactor affects. The question is
+// asked in a throwaway session using the plan agent when one exists, so
+// nothing is edited while planning.
+func (a *App) PlanRefactor(description string) tea.Cmd {
+	model := a.currentAskModel()
+	for _, candidate := range a.Agents {
+		if candidate.Name == "plan" {
+			model.agent = candidate.Name
+			break
+		}
+	}
+	return func() tea.Msg {
+		answer, err := a.askThrowaway(context.Background(), model, "refactor plan: "+description, "", fmt.Sprintf(refactorPlanningPrompt, description))
+		if err != nil {
+			return RefactorPlannedMsg{Err: fmt.Errorf("failed to plan refactor: %w", err)}
+		}
+		files := refactor.ParseFileList(answer, util.RootPath)
+		return RefactorPlannedMsg{Plan: refactor.NewPlan(util.RootPath, description, files)}
+	}
+}
+
+// LoadRefactorPlan loads the plan of an interrupted refactor, if any
+func (a *App) LoadRefactorPlan() (*refactor.Plan, error) {
+	return refactor.LoadPlan(util.RootPath)
+}
+
+// RunRefactorBatch sends a batch of files to the agent in the refactor's own
+// session, creating it on the first batch. The plan must already have been
+// marked with StartBatch.
+func (a *App) RunRefactorBatch(plan *refactor.Plan, batch []int) tea.Cmd {
+	sessionID := plan.SessionID
+	description := plan.Description
+	instructions := plan.Instructions
+	paths := make([]string, 0, len(batch))
+	for _, i := range batch {
+		paths = append(paths, "- "+plan.Files[i].Path)
+	}
+
+	return func() tea.Msg {
+		ctx := context.Background()
+		if sessionID == "" {
+			session, err := a.Client.Session.New(ctx, opencode.SessionNewParams{
+				Title: opencode.F("refactor: " + description),
+			})
+			if err != nil {
+				return RefactorBatchDoneMsg{Batch: batch, Err: fmt.Errorf("failed to create refactor session: %w", err)}
+			}
+			sessionID = session.ID
+		}
+
+		prompt := fmt.Sprintf(refactorBatchPrompt, description, strings.Join(paths, "\n"))
+		if instructions != "" {
+			prompt = fmt.Sprintf(instructedBatchPrompt, instructions, strings.Join(paths, "\n"))
+		}
+		_, err := a.Client.Session.Prompt(ctx, sessionID, opencode.SessionPromptParams{
+			Model: opencode.F(opencode.SessionPromptParamsModel{
+				ProviderID: opencode.F(a.Provider.ID),
+				ModelID:    opencode.F(a.Model.ID),
+			}),
+			Agent: opencode.F(a.Agent().Name),
+			Parts: opencode.F([]opencode.SessionPromptParamsPartUnion{
+				opencode.TextPartInputParam{
+					Type: opencode.F(opencode.TextPartInputTypeText),
+					Text: opencode.F(prompt),
+				},
+			}),
+		})
+		if err != nil {
+			slog.Error("Refactor batch failed", "error", err)
+		}
+		return RefactorBatchDoneMsg{Batch: batch, SessionID: sessionID, Err: err}
+	}
+}
+
+// DocgenPlanPath is where the plan of /docgen is saved, relative to the
+// project root, apart from the plan of a refactor in progress
+const DocgenPlanPath = ".rycode/docgen.json"
+
+// DocgenPlan builds a plan that documents the package or file named in the
+// arguments of /docgen. It is applied and reviewed like a refactor.
+func (a *App) DocgenPlan(args string) (*refactor.Plan, error) {
+	options, err := docgen.ParseArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	target := options.Target
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(util.CwdPath, target)
+	}
+
+	files, err := docgen.CollectFiles(util.RootPath, target, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", options.Target, err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no source files to document in %s", options.Target)
+	}
+
+	plan := refactor.NewPlan(util.RootPath, "Document "+options.Target, files)
+	plan.Title = "Generate Documentation"
+	plan.SetPath(DocgenPlanPath)
+	plan.Instructions = docgen.Instructions(options.Target, files, options)
+	return plan, nil
+}
+
+// RunRefactorCheck runs the check of a reviewed plan
+func (a *App) RunRefactorCheck(plan *refactor.Plan) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), refactorCheckTimeout)
+		defer cancel()
+		output, err := plan.RunCheck(ctx)
+		return RefactorCheckedMsg{Output: output, Err: err}
+	}
+}
+
+// GenTestsPlanPath is where the plan of /gentests is saved, relative to the
+// project root, apart from the plan of a refactor in progress
+const GenTestsPlanPath = ".rycode/gentests.json"
+
+// GenTestsPlan builds a plan that writes tests for the file named in the
+// arguments of /gentests, aimed at the lines an existing coverage report
+// shows as uncovered. The tests are reviewed like a refactor and run once
+// kept.
+func (a *App) GenTestsPlan(args string) (*refactor.Plan, error) {
+	file := strings.TrimSpace(args)
+	if file == "" {
+		return nil, fmt.Errorf("usage: /gentests <file>")
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(util.CwdPath, file)
+	}
+
+	target, err := testgen.Resolve(util.RootPath, file)
+	if err != nil {
+		return nil, err
+	}
+	coverage, err := testgen.LoadCoverage(util.RootPath, target)
+	if err != nil {
+		// Tests can still be written without coverage
+		slog.Warn("Failed to load coverage", "file", target.Path, "error", err)
+	}
+
+	plan := refactor.NewPlan(util.RootPath, "Test "+target.Path, []string{target.TestPath})
+	plan.Title = "Generate Tests"
+	plan.SetPath(GenTestsPlanPath)
+	plan.Instructions = testgen.Instructions(target, coverage)
+	plan.Check = &refactor.Check{Command: target.Command, Dir: target.Dir}
+	return plan, nil
+}
@@ -0,0 +1,276 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/refactor"
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// RefactorVerifiedMsg is sent when the verification pass after a refactor
+// has finished
+type RefactorVerifiedMsg struct {
+	SessionID string
+	Report    refactor.Report
+	Automatic bool // The pass ran on its own after an answer, rather than with /verify
+	Err       error
+}
+
+// maxRefactorFollowUps is how many times in a row an automatic pass sends
+// its findings to the session, so an agent that can't finish a refactor
+// isn't asked again and again
+const maxRefactorFollowUps = 2
+
+// MessageEdits returns the edits the edit tools of a message made
+func MessageEdits(message Message) []refactor.Edit {
+	var edits []refactor.Edit
+	for _, part := range message.Parts {
+		tool, ok := part.(opencode.ToolPart)
+		if !ok || tool.State.Status != opencode.ToolPartStateStatusCompleted {
+			continue
+		}
+		input, _ := tool.State.Input.(map[string]any)
+		path, _ := input["filePath"].(string)
+		switch tool.Tool {
+		case "edit":
+			oldString, _ := input["oldString"].(string)
+			newString, _ := input["newString"].(string)
+			edits = append(edits, refactor.Edit{Path: path, Old: oldString, New: newString})
+		case "multiedit":
+			list, _ := input["edits"].([]any)
+			for _, item := range list {
+				edit, _ := item.(map[string]any)
+				oldString, _ := edit["oldString"].(string)
+				newString, _ := edit["newString"].(string)
+				edits = append(edits, refactor.Edit{Path: path, Old: oldString, New: newString})
+			}
+		}
+	}
+	return edits
+}
+
+// messageDiagnostics returns the errors the language server reported after
+// the last edit of each file of a message, as path:line:column message
+func messageDiagnostics(message Message) []string {
+	latest := make(map[string][]any)
+	for _, part := range message.Parts {
+		tool, ok := part.(opencode.ToolPart)
+		if !ok || (tool.Tool != "edit" && tool.Tool != "multiedit" && tool.Tool != "write") {
+			continue
+		}
+		metadata, _ := tool.State.Metadata.(map[string]any)
+		diagnostics, _ := metadata["diagnostics"].(map[string]any)
+		input, _ := tool.State.Input.(map[string]any)
+		path, _ := input["filePath"].(string)
+		if list, ok := diagnostics[path].([]any); ok {
+			latest[path] = list
+		}
+	}
+	var found []string
+	for path, list := range latest {
+		for _, item := range list {
+			diagnostic, _ := item.(map[string]any)
+			if severity, _ := diagnostic["severity"].(float64); severity != 1 {
+				continue
+			}
+			message, _ := diagnostic["message"].(string)
+			line, column := 0.0, 0.0
+			if r, ok := diagnostic["range"].(map[string]any); ok {
+				if start, ok := r["start"].(map[string]any); ok {
+					line, _ = start["line"].(float64)
+					column, _ = start["character"].(float64)
+				}
+			}
+			found = append(found, fmt.Sprintf("%s:%d:%d %s", util.Relative(path), int(line)+1, int(column)+1, message))
+		}
+	}
+	slices.Sort(found)
+	return found
+}
+
+// VerifyRefactors verifies the refactor of a completed answer of the
+// session, when answers are verified automatically. Only answers that
+// renamed a symbol or changed a signature across files are verified.
+func (a *App) VerifyRefactors(message opencode.AssistantMessage) tea.Cmd {
+	if !a.State.VerifyRefactors || message.Time.Completed == 0 || message.SessionID != a.Session.ID || a.verifiedRefactors[message.ID] {
+		return nil
+	}
+	index := slices.IndexFunc(a.Messages, func(m Message) bool { return MessageID(m) == message.ID })
+	if index < 0 {
+		return nil
+	}
+	if a.verifiedRefactors == nil {
+		a.verifiedRefactors = make(map[string]bool)
+	}
+	a.verifiedRefactors[message.ID] = true
+	change := refactor.Detect(MessageEdits(a.Messages[index]))
+	if !change.CrossFile() {
+		return nil
+	}
+	return a.verifyRefactor(a.Messages[index], change, true)
+}
+
+// VerifyLastRefactor verifies the edits of the session's last answer that
+// edited files, for /verify
+func (a *App) VerifyLastRefactor() tea.Cmd {
+	for i := len(a.Messages) - 1; i >= 0; i-- {
+		if _, ok := a.Messages[i].Info.(opencode.AssistantMessage); !ok {
+			continue
+		}
+		if edits := MessageEdits(a.Messages[i]); len(edits) > 0 {
+			return a.verifyRefactor(a.Messages[i], refactor.Detect(edits), false)
+		}
+	}
+	return func() tea.Msg {
+		return RefactorVerifiedMsg{Err: errors.New("no answer of the session edited files")}
+	}
+}
+
+// verifyRefactor looks for what still refers to the old names of renamed
+// symbols: the references the language server finds to symbols still
+// declared by an old name, and the old names as text. It collects the
+// errors the language server reported in the changed files, and compiles
+// them.
+func (a *App) verifyRefactor(message Message, change refactor.Change, automatic bool) tea.Cmd {
+	sessionID := a.Session.ID
+	diagnostics := messageDiagnostics(message)
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), refactorCheckTimeout)
+		defer cancel()
+		finder := refactor.NewReferenceFinder(util.RootPath)
+		defer finder.Close()
+
+		report := refactor.Report{Change: change, Diagnostics: diagnostics}
+		lines := make(map[string][]string)
+		found := make(map[string]bool) // Leftovers by path:line
+		addLeftover := func(rename refactor.Rename, path string, line int, text string) {
+			key := fmt.Sprintf("%s:%d", path, line)
+			if found[key] {
+				return
+			}
+			found[key] = true
+			report.Leftovers = append(report.Leftovers, refactor.Leftover{Rename: rename, Path: path, Line: line, Text: text})
+		}
+
+		for _, rename := range change.Renames {
+			// Symbols still declared by the old name are located with the
+			// server's workspace symbols, and what refers to them with the
+			// language server's references
+			symbols, err := a.Client.Find.Symbols(ctx, opencode.FindSymbolsParams{Query: opencode.F(rename.Old)})
+			if err != nil {
+				slog.Warn("Failed to find symbols", "symbol", rename.Old, "error", err)
+			} else if symbols != nil {
+				for _, symbol := range *symbols {
+					if symbol.Name != rename.Old && !strings.HasSuffix(symbol.Name, "."+rename.Old) {
+						continue
+					}
+					at := refactor.Location{
+						Path:   strings.TrimPrefix(symbol.Location.Uri, "file://"),
+						Line:   int(symbol.Location.Range.Start.Line),
+						Column: int(symbol.Location.Range.Start.Character),
+					}
+					report.Symbols = append(report.Symbols, fmt.Sprintf("%s in %s:%d", symbol.Name, util.Relative(at.Path), at.Line+1))
+					references, err := finder.References(ctx, rename.Old, at)
+					if err != nil {
+						slog.Warn("Failed to find references", "symbol", symbol.Name, "error", err)
+						continue
+					}
+					for _, reference := range references {
+						addLeftover(rename, util.Relative(reference.Path), reference.Line+1, fileLine(lines, reference.Path, reference.Line))
+					}
+				}
+			}
+
+			// Comments, strings and files the language server doesn't read
+			// are searched as text
+			matches, err := a.Client.Find.Text(ctx, opencode.FindTextParams{Pattern: opencode.F(`\b` + rename.Old + `\b`)})
+			if err != nil {
+				return RefactorVerifiedMsg{SessionID: sessionID, Automatic: automatic, Err: fmt.Errorf("failed to search for %s: %w", rename.Old, err)}
+			}
+			if matches != nil {
+				for _, match := range *matches {
+					addLeftover(rename, util.Relative(match.Path.Text), int(match.LineNumber), match.Lines.Text)
+				}
+			}
+		}
+
+		// Each project the changed files belong to is compiled once
+		var checks []*refactor.Check
+		for _, file := range change.Files {
+			check := refactor.CompileCheck(util.RootPath, file)
+			if check == nil || slices.ContainsFunc(checks, func(c *refactor.Check) bool {
+				return c.Dir == check.Dir && slices.Equal(c.Command, check.Command)
+			}) {
+				continue
+			}
+			checks = append(checks, check)
+		}
+		for _, check := range checks {
+			output, err := check.Run(ctx, util.RootPath)
+			report.Check, report.CheckOutput = check, output
+			if err != nil {
+				report.CheckFailed = true
+				break
+			}
+		}
+		return RefactorVerifiedMsg{SessionID: sessionID, Report: report, Automatic: automatic}
+	}
+}
+
+// fileLine returns a zero-based line of a file, reading each file once
+func fileLine(files map[string][]string, path string, line int) string {
+	lines, ok := files[path]
+	if !ok {
+		if content, err := remote.ReadFile(path); err == nil {
+			lines = strings.Split(string(content), "\n")
+		}
+		files[path] = lines
+	}
+	if line < 0 || line >= len(lines) {
+		return ""
+	}
+	return lines[line]
+}
+
+// ReportRefactor tells the session what the verification pass after its
+// refactor found, so the agent can finish it
+func (a *App) ReportRefactor(msg RefactorVerifiedMsg) (*App, tea.Cmd) {
+	if msg.Err != nil {
+		return a, toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Refactor not verified"))
+	}
+	report := msg.Report
+	slog.Info("Refactor verified", "session", msg.SessionID, "leftovers", len(report.Leftovers), "diagnostics", len(report.Diagnostics), "checkFailed", report.CheckFailed)
+	if report.Clean() {
+		delete(a.refactorFollowUps, msg.SessionID)
+		return a, toast.NewSuccessToast(report.Summary(), toast.WithTitle("Refactor verified"))
+	}
+	if msg.SessionID != a.Session.ID {
+		return a, toast.NewWarningToast(report.Summary(), toast.WithTitle("Refactor unfinished"))
+	}
+	if msg.Automatic {
+		if a.refactorFollowUps[msg.SessionID] >= maxRefactorFollowUps {
+			return a, toast.NewWarningToast(report.Summary()+". Run /verify to send them to the session again.", toast.WithTitle("Refactor still unfinished"))
+		}
+		if a.refactorFollowUps == nil {
+			a.refactorFollowUps = make(map[string]int)
+		}
+		a.refactorFollowUps[msg.SessionID]++
+	}
+	updated, cmd := a.SendPrompt(context.Background(), Prompt{Text: report.Prompt()})
+	return updated, tea.Batch(cmd, toast.NewWarningToast(report.Summary()+", sent to the session", toast.WithTitle("Refactor unfinished")))
+}
+
+// SetVerifyRefactors turns verifying refactors after answers on or off
+func (a *App) SetVerifyRefactors(on bool) tea.Cmd {
+	a.State.VerifyRefactors = on
+	return a.SaveState()
+}
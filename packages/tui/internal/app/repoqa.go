@@ -0,0 +1,125 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/repoqa"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// repoAskDisabledTools keeps the model from changing anything while it
+// answers a question about the repository
+var repoAskDisabledTools = map[string]bool{
+	"edit":      false,
+	"write":     false,
+	"patch":     false,
+	"bash":      false,
+	"todowrite": false,
+}
+
+// askModel is the model and agent a throwaway session is asked with, taken
+// when a command is made, as they change with the current session
+type askModel struct {
+	providerID string
+	modelID    string
+	agent      string
+	started    func(sessionID string) // Called with the session before it's asked, if set
+}
+
+// currentAskModel returns the model and agent of the current session
+func (a *App) currentAskModel() askModel {
+	return askModel{providerID: a.Provider.ID, modelID: a.Model.ID, agent: a.Agent().Name}
+}
+
+// askThrowaway asks a question in a session of its own, deleted once
+// answered, with the tools that change anything disabled. It returns the
+// text of the answer.
+func (a *App) askThrowaway(ctx context.Context, model askModel, title, system, prompt string) (string, error) {
+	session, err := a.Client.Session.New(ctx, opencode.SessionNewParams{
+		Title: opencode.F(title),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create session: %w", err)
+	}
+	if model.started != nil {
+		model.started(session.ID)
+	}
+	defer func() {
+		if _, err := a.Client.Session.Delete(context.Background(), session.ID, opencode.SessionDeleteParams{}); err != nil {
+			slog.Debug("Failed to delete throwaway session", "title", title, "session", session.ID, "error", err)
+		}
+	}()
+
+	params := opencode.SessionPromptParams{
+		Model: opencode.F(opencode.SessionPromptParamsModel{
+			ProviderID: opencode.F(model.providerID),
+			ModelID:    opencode.F(model.modelID),
+		}),
+		Agent: opencode.F(model.agent),
+		Tools: opencode.F(repoAskDisabledTools),
+		Parts: opencode.F([]opencode.SessionPromptParamsPartUnion{
+			opencode.TextPartInputParam{
+				Type: opencode.F(opencode.TextPartInputTypeText),
+				Text: opencode.F(prompt),
+			},
+		}),
+	}
+	if system != "" {
+		params.System = opencode.F(system)
+	}
+	response, err := a.Client.Session.Prompt(ctx, session.ID, params)
+	if err != nil {
+		return "", err
+	}
+
+	var texts []string
+	for _, part := range response.Parts {
+		if text, ok := part.AsUnion().(opencode.TextPart); ok && !text.Synthetic {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n"), nil
+}
+
+// RepoAnswerMsg is sent when a question about the repository was answered
+type RepoAnswerMsg struct {
+	Question  string
+	Answer    string
+	Citations []repoqa.Citation
+	Err       error
+}
+
+// AskRepo answers a question about the repository. Relevant excerpts are
+// retrieved from a local index and sent with a repository outline to a
+// throwaway session in which editing tools are disabled.
+func (a *App) AskRepo(question string) tea.Cmd {
+	if a.repoIndex == nil {
+		a.repoIndex = &repoqa.Cache{}
+	}
+	cache := a.repoIndex
+	model := a.currentAskModel()
+
+	return func() tea.Msg {
+		index, err := cache.Get(util.RootPath)
+		if err != nil {
+			return RepoAnswerMsg{Question: question, Err: fmt.Errorf("failed to index repository: %w", err)}
+		}
+		chunks := index.Search(question, repoqa.DefaultContextChunks)
+		slog.Debug("Retrieved repository context", "files", len(index.Files), "chunks", len(chunks))
+
+		answer, err := a.askThrowaway(context.Background(), model, "ask: "+question, repoqa.SystemPrompt, repoqa.BuildPrompt(question, index, chunks))
+		if err != nil {
+			return RepoAnswerMsg{Question: question, Err: err}
+		}
+		return RepoAnswerMsg{
+			Question:  question,
+			Answer:    answer,
+			Citations: repoqa.ParseCitations(answer, util.RootPath),
+		}
+	}
+}
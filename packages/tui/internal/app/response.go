@@ -0,0 +1,89 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+// ResponseSnapshot captures an assistant answer so that a regenerated
+// response can later be compared against it.
+type ResponseSnapshot struct {
+	UserMessageID string
+	ProviderID    string
+	ModelID       string
+	Text          string
+}
+
+// ModelLabel returns the "provider/model" label of the snapshot
+func (r ResponseSnapshot) ModelLabel() string {
+	if r.ProviderID == "" && r.ModelID == "" {
+		return "unknown model"
+	}
+	return r.ProviderID + "/" + r.ModelID
+}
+
+// ResponseAfter collects the assistant answer that follows the given user
+// message. It returns nil when there is no text answer to capture.
+func ResponseAfter(messages []Message, userMessageID string) *ResponseSnapshot {
+	start := -1
+	for i, message := range messages {
+		if casted, ok := message.Info.(opencode.UserMessage); ok && casted.ID == userMessageID {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		return nil
+	}
+	return collectResponse(messages, start)
+}
+
+// LatestResponse collects the assistant answer to the most recent user message
+func LatestResponse(messages []Message) *ResponseSnapshot {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if _, ok := messages[i].Info.(opencode.UserMessage); ok {
+			return collectResponse(messages, i)
+		}
+	}
+	return nil
+}
+
+func collectResponse(messages []Message, userIndex int) *ResponseSnapshot {
+	userMessage, ok := messages[userIndex].Info.(opencode.UserMessage)
+	if !ok {
+		return nil
+	}
+
+	snapshot := &ResponseSnapshot{UserMessageID: userMessage.ID}
+	var texts []string
+	for i := userIndex + 1; i < len(messages); i++ {
+		casted, ok := messages[i].Info.(opencode.AssistantMessage)
+		if !ok {
+			break
+		}
+		snapshot.ProviderID = casted.ProviderID
+		snapshot.ModelID = casted.ModelID
+		for _, part := range messages[i].Parts {
+			if text, ok := part.(opencode.TextPart); ok && !text.Synthetic {
+				if trimmed := strings.TrimSpace(text.Text); trimmed != "" {
+					texts = append(texts, trimmed)
+				}
+			}
+		}
+	}
+
+	if len(texts) == 0 {
+		return nil
+	}
+	snapshot.Text = strings.Join(texts, "\n\n")
+	return snapshot
+}
+
+// RememberResponse stores the answer to the given user message as the
+// previous response, to be compared with whatever replaces it.
+func (a *App) RememberResponse(userMessageID string) {
+	if snapshot := ResponseAfter(a.Messages, userMessageID); snapshot != nil {
+		a.PreviousResponse = snapshot
+	}
+}
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// AnyLanguage overrides the response language of a session, to answer in
+// the prompt's language
+const AnyLanguage = "any"
+
+// maxLanguageLength bounds the name of a language, which goes in the system
+// prompt as given
+const maxLanguageLength = 40
+
+// ResponseLanguage returns the language the assistant answers the current
+// session in, "" for the prompt's own, and whether the session overrides
+// the setting
+func (a *App) ResponseLanguage() (string, bool) {
+	return a.responseLanguage(a.Session.ID)
+}
+
+func (a *App) responseLanguage(sessionID string) (string, bool) {
+	if language, ok := a.State.SessionLanguages[sessionID]; ok && sessionID != "" {
+		if language == AnyLanguage {
+			return "", true
+		}
+		return language, true
+	}
+	return a.State.ResponseLanguage, false
+}
+
+// SetResponseLanguage sets the language the assistant answers every session
+// in, "" for the prompt's own
+func (a *App) SetResponseLanguage(language string) (tea.Cmd, error) {
+	language, err := checkLanguage(language)
+	if err != nil {
+		return nil, err
+	}
+	if language == AnyLanguage {
+		language = ""
+	}
+	a.State.ResponseLanguage = language
+	return a.SaveState(), nil
+}
+
+// SetSessionLanguage overrides the response language for the current
+// session: a language, AnyLanguage for the prompt's own, or "" to follow
+// the setting again
+func (a *App) SetSessionLanguage(language string) (tea.Cmd, error) {
+	if a.Session.ID == "" {
+		return nil, errors.New("no session to set the language of yet")
+	}
+	language, err := checkLanguage(language)
+	if err != nil {
+		return nil, err
+	}
+	if language == "" {
+		delete(a.State.SessionLanguages, a.Session.ID)
+		return a.SaveState(), nil
+	}
+	if a.State.SessionLanguages == nil {
+		a.State.SessionLanguages = make(map[string]string)
+	}
+	a.State.SessionLanguages[a.Session.ID] = language
+	return a.SaveState(), nil
+}
+
+// checkLanguage tidies the name of a language, as French or pt-BR
+func checkLanguage(language string) (string, error) {
+	language = strings.Join(strings.Fields(language), " ")
+	if strings.EqualFold(language, AnyLanguage) {
+		return AnyLanguage, nil
+	}
+	if len(language) > maxLanguageLength || strings.ContainsAny(language, ".:\"`") {
+		return "", fmt.Errorf("not a language: %q", language)
+	}
+	return language, nil
+}
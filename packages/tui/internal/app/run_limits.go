@@ -0,0 +1,331 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+// Limits of a run, each set with /limits <limit> <value>
+const (
+	RunLimitTime  = "time"  // Wall time since the prompt that started the run
+	RunLimitTools = "tools" // Tool calls made
+	RunLimitCost  = "cost"  // Cost of the run's answers, in USD
+)
+
+// RunLimitNames are the limits of a run, in the order they are listed
+var RunLimitNames = []string{RunLimitTime, RunLimitTools, RunLimitCost}
+
+// RunBudget bounds what a run of the agent, from a prompt until the agent
+// stops, may take. Zero fields don't bound it.
+type RunBudget struct {
+	Time      time.Duration `toml:"time,omitempty"`
+	ToolCalls int           `toml:"tools,omitempty"`
+	Cost      float64       `toml:"cost,omitempty"`
+}
+
+// RunLimits bound the runs of every agent, and of single agents and
+// commands, as in the state file. A command's budget overrides its agent's,
+// which overrides the default, limit by limit:
+//
+//	[run_limits.default]
+//	time = "20m"
+//	[run_limits.agents.build]
+//	tools = 80
+//	[run_limits.commands.review]
+//	cost = 0.5
+type RunLimits struct {
+	Default  RunBudget            `toml:"default,omitempty"`
+	Agents   map[string]RunBudget `toml:"agents,omitempty"`   // By agent name
+	Commands map[string]RunBudget `toml:"commands,omitempty"` // By command name
+}
+
+// RunUsage is what a run took so far
+type RunUsage struct {
+	Elapsed   time.Duration
+	ToolCalls int
+	Cost      float64
+}
+
+// over returns the budget with the limits o sets replacing its own
+func (b RunBudget) over(o RunBudget) RunBudget {
+	if o.Time != 0 {
+		b.Time = o.Time
+	}
+	if o.ToolCalls != 0 {
+		b.ToolCalls = o.ToolCalls
+	}
+	if o.Cost != 0 {
+		b.Cost = o.Cost
+	}
+	return b
+}
+
+// Unbounded reports whether the budget sets no limit
+func (b RunBudget) Unbounded() bool {
+	return b == RunBudget{}
+}
+
+// Exceeded returns the limits a run went over
+func (b RunBudget) Exceeded(u RunUsage) []string {
+	var exceeded []string
+	if b.Time > 0 && u.Elapsed >= b.Time {
+		exceeded = append(exceeded, RunLimitTime)
+	}
+	if b.ToolCalls > 0 && u.ToolCalls >= b.ToolCalls {
+		exceeded = append(exceeded, RunLimitTools)
+	}
+	if b.Cost > 0 && u.Cost >= b.Cost {
+		exceeded = append(exceeded, RunLimitCost)
+	}
+	return exceeded
+}
+
+// Limit describes one limit of the budget, or "none"
+func (b RunBudget) Limit(limit string) string {
+	switch {
+	case limit == RunLimitTime && b.Time > 0:
+		return b.Time.String()
+	case limit == RunLimitTools && b.ToolCalls > 0:
+		return fmt.Sprintf("%d tool calls", b.ToolCalls)
+	case limit == RunLimitCost && b.Cost > 0:
+		return fmt.Sprintf("$%.2f", b.Cost)
+	}
+	return "none"
+}
+
+// Used describes what a run took of one limit
+func (u RunUsage) Used(limit string) string {
+	switch limit {
+	case RunLimitTime:
+		return u.Elapsed.Truncate(time.Second).String()
+	case RunLimitTools:
+		return fmt.Sprintf("%d tool calls", u.ToolCalls)
+	case RunLimitCost:
+		return fmt.Sprintf("$%.2f", u.Cost)
+	}
+	return ""
+}
+
+// RunBudget returns the budget of a run of an agent, started by a command
+// or by a prompt when command is ""
+func (a *App) RunBudget(agent, command string) RunBudget {
+	limits := a.State.RunLimits
+	if limits == nil {
+		return RunBudget{}
+	}
+	budget := limits.Default.over(limits.Agents[agent])
+	if command != "" {
+		budget = budget.over(limits.Commands[command])
+	}
+	return budget
+}
+
+// SetRunLimit sets a limit of the runs of every agent, when scope is "", or
+// of the agent or command named. A value of "off" or 0 removes the limit.
+func (a *App) SetRunLimit(scope, name, limit, value string) (tea.Cmd, error) {
+	if a.State.RunLimits == nil {
+		a.State.RunLimits = &RunLimits{}
+	}
+	limits := a.State.RunLimits
+	var budget RunBudget
+	switch scope {
+	case "":
+		budget = limits.Default
+	case "agent":
+		budget = limits.Agents[name]
+	case "command":
+		budget = limits.Commands[name]
+	default:
+		return nil, fmt.Errorf("unknown scope %q, expected agent or command", scope)
+	}
+
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "off" {
+		value = "0"
+	}
+	var err error
+	switch limit {
+	case RunLimitTime:
+		budget.Time, err = time.ParseDuration(value)
+		if err != nil || budget.Time < 0 {
+			return nil, fmt.Errorf("the time limit is a duration, as 20m or 1h30m")
+		}
+	case RunLimitTools:
+		budget.ToolCalls, err = strconv.Atoi(value)
+		if err != nil || budget.ToolCalls < 0 {
+			return nil, fmt.Errorf("the tools limit is a number of tool calls")
+		}
+	case RunLimitCost:
+		budget.Cost, err = strconv.ParseFloat(strings.TrimPrefix(value, "$"), 64)
+		if err != nil || budget.Cost < 0 {
+			return nil, fmt.Errorf("the cost limit is an amount in USD, as 0.50")
+		}
+	default:
+		return nil, fmt.Errorf("unknown limit %q, expected one of %s", limit, strings.Join(RunLimitNames, ", "))
+	}
+
+	set := func(budgets map[string]RunBudget) map[string]RunBudget {
+		if budget.Unbounded() {
+			delete(budgets, name)
+			return budgets
+		}
+		if budgets == nil {
+			budgets = make(map[string]RunBudget)
+		}
+		budgets[name] = budget
+		return budgets
+	}
+	switch scope {
+	case "":
+		limits.Default = budget
+	case "agent":
+		limits.Agents = set(limits.Agents)
+	case "command":
+		limits.Commands = set(limits.Commands)
+	}
+	return a.SaveState(), nil
+}
+
+// RunPausedMsg is sent when a run of the session was stopped for going over
+// its budget
+type RunPausedMsg struct {
+	SessionID string
+	Agent     string
+	Command   string // Command that started the run, if any
+	Budget    RunBudget
+	Usage     RunUsage
+	Exceeded  []string
+	Doing     string // The last tool call of the run, as the tool log shows it
+}
+
+// RunResumedMsg is sent when a paused run is told to go on, with a fresh
+// budget or without one
+type RunResumedMsg struct {
+	Paused    RunPausedMsg
+	Unbounded bool
+}
+
+// runTracker follows the run of a session against its budget
+type runTracker struct {
+	run           string // ID of the prompt that started the run
+	command       string
+	unbounded     bool
+	paused        bool
+	nextCommand   string // Command whose prompt starts the next run
+	nextUnbounded bool   // The next run resumes one without a budget
+}
+
+func (a *App) runTracker(sessionID string) *runTracker {
+	if a.runs == nil {
+		a.runs = make(map[string]*runTracker)
+	}
+	tracker := a.runs[sessionID]
+	if tracker == nil {
+		tracker = &runTracker{}
+		a.runs[sessionID] = tracker
+	}
+	return tracker
+}
+
+// RunLimitsTickMsg checks the runs of every session against their budget
+type RunLimitsTickMsg struct{}
+
+// runLimitsInterval is how often runs are checked against their budget
+const runLimitsInterval = 5 * time.Second
+
+// WatchRunLimits checks the runs against their budget after a while
+func (a *App) WatchRunLimits() tea.Cmd {
+	return tea.Tick(runLimitsInterval, func(time.Time) tea.Msg { return RunLimitsTickMsg{} })
+}
+
+// CheckRunLimits pauses the runs that went over their budget, aborting
+// them, in every session whichever one is in view
+func (a *App) CheckRunLimits() tea.Cmd {
+	if a.State.RunLimits == nil {
+		return nil
+	}
+	var cmds []tea.Cmd
+	for sessionID, run := range a.sessionRuns {
+		cmds = append(cmds, a.checkRunLimits(sessionID, run))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (a *App) checkRunLimits(sessionID string, run *sessionRun) tea.Cmd {
+	if busy, compacting := run.answering(); !busy || compacting || run.prompt == "" {
+		return nil
+	}
+	tracker := a.runTracker(sessionID)
+	if tracker.run != run.prompt {
+		tracker.run, tracker.command, tracker.unbounded, tracker.paused = run.prompt, tracker.nextCommand, tracker.nextUnbounded, false
+		tracker.nextCommand, tracker.nextUnbounded = "", false
+	}
+	if tracker.paused || tracker.unbounded {
+		return nil
+	}
+
+	usage := RunUsage{Elapsed: time.Since(run.started)}
+	for _, answer := range run.answers {
+		usage.Cost += answer.Cost
+	}
+	doing := ""
+	for _, id := range run.calls {
+		if tool := run.tools[id]; tool.State.Status != opencode.ToolPartStateStatusPending {
+			usage.ToolCalls++
+			call := toolRun(tool)
+			doing = strings.TrimSpace(call.Tool + " " + call.Label)
+		}
+	}
+	agent := run.agent
+	if agent == "" {
+		agent = a.Agent().Name
+	}
+	budget := a.RunBudget(agent, tracker.command)
+	exceeded := budget.Exceeded(usage)
+	if len(exceeded) == 0 {
+		return nil
+	}
+	tracker.paused = true
+	slog.Info("Paused a run over its budget", "session", sessionID, "agent", agent, "command", tracker.command, "exceeded", exceeded)
+
+	paused := RunPausedMsg{
+		SessionID: sessionID,
+		Agent:     agent,
+		Command:   tracker.command,
+		Budget:    budget,
+		Usage:     usage,
+		Exceeded:  exceeded,
+		Doing:     doing,
+	}
+	return tea.Batch(
+		func() tea.Msg {
+			if _, err := a.Client.Session.Abort(context.Background(), sessionID, opencode.SessionAbortParams{}); err != nil {
+				slog.Error("Failed to pause a run over its budget", "session", sessionID, "error", err)
+			}
+			return paused
+		},
+		a.Notify(NotifyCompletions, "Run paused", "Over its "+strings.Join(exceeded, " and ")+" limit"),
+	)
+}
+
+// ResumeRun tells a paused run to go on, where it was stopped. The run it
+// starts has a budget of its own, or none when unbounded.
+func (a *App) ResumeRun(msg RunResumedMsg) (*App, tea.Cmd) {
+	tracker := a.runTracker(msg.Paused.SessionID)
+	tracker.nextCommand, tracker.nextUnbounded = msg.Paused.Command, msg.Unbounded
+	prompt := fmt.Sprintf("You were paused for going over this run's %s limit. Continue where you stopped.", strings.Join(msg.Paused.Exceeded, " and "))
+	return a.SendPrompt(context.Background(), Prompt{Text: prompt})
+}
+
+// commandStartsRun notes that the next run of the session is started by a
+// command, whose budget it gets
+func (a *App) commandStartsRun(sessionID, command string) {
+	a.runTracker(sessionID).nextCommand = command
+}
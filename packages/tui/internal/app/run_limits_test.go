@@ -0,0 +1,91 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+func TestRunLimits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.toml")
+	config := `
+[run_limits.default]
+time = "20m"
+tools = 50
+[run_limits.agents.build]
+tools = 80
+[run_limits.commands.review]
+cost = 0.5
+`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &App{State: state}
+
+	want := RunBudget{Time: 20 * time.Minute, ToolCalls: 80, Cost: 0.5}
+	if got := a.RunBudget("build", "review"); got != want {
+		t.Errorf("budget of /review on build = %+v, want %+v", got, want)
+	}
+	want = RunBudget{Time: 20 * time.Minute, ToolCalls: 50}
+	if got := a.RunBudget("plan", ""); got != want {
+		t.Errorf("budget of plan = %+v, want %+v", got, want)
+	}
+
+	usage := RunUsage{Elapsed: 21 * time.Minute, ToolCalls: 80, Cost: 0.1}
+	if got := a.RunBudget("build", "review").Exceeded(usage); !slices.Equal(got, []string{RunLimitTime, RunLimitTools}) {
+		t.Errorf("exceeded = %v, want time and tools", got)
+	}
+	if got := (RunBudget{}).Exceeded(usage); got != nil {
+		t.Errorf("an unbounded budget was exceeded: %v", got)
+	}
+
+	if _, err := a.SetRunLimit("agent", "build", RunLimitCost, "$1.25"); err != nil {
+		t.Fatal(err)
+	}
+	if got := a.State.RunLimits.Agents["build"]; got.Cost != 1.25 || got.ToolCalls != 80 {
+		t.Errorf("build budget = %+v, want its cost set and its tools kept", got)
+	}
+	for _, bad := range [][2]string{{RunLimitTime, "soon"}, {RunLimitTools, "-1"}, {"tokens", "10"}} {
+		if _, err := a.SetRunLimit("", "", bad[0], bad[1]); err == nil {
+			t.Errorf("%s limit %q was set", bad[0], bad[1])
+		}
+	}
+	if _, err := a.SetRunLimit("command", "review", RunLimitCost, "off"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := a.State.RunLimits.Commands["review"]; ok {
+		t.Errorf("turning off the last limit of /review left %v", a.State.RunLimits.Commands)
+	}
+}
+
+func TestCheckRunLimitsOfEverySession(t *testing.T) {
+	a := &App{State: NewState(), Session: &opencode.Session{ID: "ses_viewed"}}
+	a.State.RunLimits = &RunLimits{Default: RunBudget{Cost: 1}}
+
+	a.FollowRun(opencode.UserMessage{ID: "msg_1", SessionID: "ses_other", Time: opencode.UserMessageTime{Created: float64(time.Now().UnixMilli())}})
+	a.FollowRun(opencode.AssistantMessage{ID: "msg_2", SessionID: "ses_other", Mode: "build", Cost: 0.4})
+	if cmd := a.CheckRunLimits(); cmd != nil {
+		t.Fatal("a run under its budget was paused")
+	}
+	a.FollowRun(opencode.AssistantMessage{ID: "msg_2", SessionID: "ses_other", Mode: "build", Cost: 1.2})
+	if cmd := a.CheckRunLimits(); cmd == nil {
+		t.Fatal("the run of a session out of view was not paused")
+	}
+	if !a.runTracker("ses_other").paused {
+		t.Error("the paused run was not marked paused")
+	}
+
+	a.FollowRun(opencode.AssistantMessage{ID: "msg_2", SessionID: "ses_other", Mode: "build", Cost: 1.2, Time: opencode.AssistantMessageTime{Completed: 1}})
+	a.runTracker("ses_other").paused = false
+	if cmd := a.CheckRunLimits(); cmd != nil {
+		t.Error("a finished run was paused")
+	}
+}
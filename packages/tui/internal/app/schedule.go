@@ -0,0 +1,47 @@
+package app
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/schedule"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// ScheduledRunMsg is sent when a scheduled prompt run started from the TUI
+// has finished
+type ScheduledRunMsg struct {
+	Job    schedule.Job
+	Result schedule.Result
+}
+
+// Schedules returns the store of scheduled prompts, which lives in the state
+// directory shared with the scheduler daemon
+func (a *App) Schedules() *schedule.Store {
+	return schedule.NewStore(filepath.Dir(a.StatePath))
+}
+
+// AddSchedule registers a scheduled prompt from the arguments of /schedule
+// add. It runs with the current model and agent in the current project.
+func (a *App) AddSchedule(args string) (schedule.Job, error) {
+	job, err := schedule.ParseJob(args, time.Now())
+	if err != nil {
+		return job, err
+	}
+	job.ProviderID = a.Provider.ID
+	job.ModelID = a.Model.ID
+	job.Agent = a.Agent().Name
+	job.Directory = util.RootPath
+	return job, a.Schedules().Add(job)
+}
+
+// RunScheduleNow runs a scheduled prompt immediately, delivering and
+// recording its result as the daemon would
+func (a *App) RunScheduleNow(job schedule.Job) tea.Cmd {
+	daemon := &schedule.Daemon{Client: a.Client, Store: a.Schedules()}
+	return func() tea.Msg {
+		return ScheduledRunMsg{Job: job, Result: daemon.RunJob(context.Background(), job)}
+	}
+}
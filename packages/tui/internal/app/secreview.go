@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+	"github.com/aaronmrosenthal/rycode/internal/secreview"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// SecurityReportPath is where exported SARIF reports are written, relative
+// to the project root
+const SecurityReportPath = ".rycode/security-review.sarif"
+
+// maxReviewDiffBytes bounds the diff sent for review
+const maxReviewDiffBytes = 200_000
+
+// SecurityReviewMsg is sent when a security review has finished
+type SecurityReviewMsg struct {
+	Report *secreview.Report
+	Err    error
+}
+
+// SecurityReview reviews the files or diff named in the arguments of
+// /security-review in a throwaway session where the agent can read the code
+// but not change it
+func (a *App) SecurityReview(args string) tea.Cmd {
+	scope, err := secreview.ParseArgs(args)
+	if err != nil {
+		return util.CmdHandler(SecurityReviewMsg{Err: err})
+	}
+	model := a.currentAskModel()
+
+	return func() tea.Msg {
+		ctx := context.Background()
+		diff := ""
+		if scope.Diff {
+			cmd := remote.Command(ctx, util.RootPath, "git", "diff", scope.Ref)
+			out, err := cmd.Output()
+			if err != nil {
+				return SecurityReviewMsg{Err: fmt.Errorf("failed to diff against %s: %w", scope.Ref, err)}
+			}
+			diff = string(out)
+			if strings.TrimSpace(diff) == "" {
+				return SecurityReviewMsg{Err: fmt.Errorf("no changes since %s to review", scope.Ref)}
+			}
+			if len(diff) > maxReviewDiffBytes {
+				diff = diff[:maxReviewDiffBytes] + "\n… diff truncated"
+			}
+		}
+
+		// The same tools as /ask are disabled: the review is read-only
+		answer, err := a.askThrowaway(ctx, model, "security review: "+scope.String(), secreview.SystemPrompt, secreview.BuildPrompt(scope, diff))
+		if err != nil {
+			return SecurityReviewMsg{Err: err}
+		}
+		findings, err := secreview.ParseFindings(answer, util.RootPath)
+		if err != nil {
+			return SecurityReviewMsg{Err: err}
+		}
+		return SecurityReviewMsg{Report: &secreview.Report{
+			Scope:     scope.String(),
+			Model:     model.providerID + "/" + model.modelID,
+			CreatedAt: time.Now(),
+			Findings:  findings,
+		}}
+	}
+}
+
+// ExportSecurityReport writes a review report as SARIF and returns the path
+// written
+func (a *App) ExportSecurityReport(report *secreview.Report) (string, error) {
+	data, err := report.SARIF()
+	if err != nil {
+		return "", fmt.Errorf("failed to encode SARIF: %w", err)
+	}
+	path := filepath.Join(util.RootPath, SecurityReportPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create report directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", SecurityReportPath, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+	"github.com/aaronmrosenthal/rycode/internal/target"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// SessionDir returns the directory the session's shell commands run in,
+// relative to the project root, or "" for the root
+func (a *App) SessionDir() string {
+	if a.Session.ID == "" {
+		return a.pendingDir
+	}
+	return a.State.SessionDirs[a.Session.ID]
+}
+
+// SetSessionDir changes the directory the session's shell commands run in,
+// as cd does: relative to the current one, or to the project root when it
+// starts with a slash. It returns the new directory.
+func (a *App) SetSessionDir(dir string) (string, tea.Cmd, error) {
+	dir, err := resolveSessionDir(util.RootPath, a.SessionDir(), dir)
+	if err != nil {
+		return "", nil, err
+	}
+	if dir != "" {
+		info, err := remote.Stat(filepath.Join(util.RootPath, dir))
+		if err != nil {
+			return "", nil, fmt.Errorf("no such directory: %s", dir)
+		}
+		if !info.IsDir() {
+			return "", nil, fmt.Errorf("not a directory: %s", dir)
+		}
+	}
+	if a.Session.ID == "" {
+		// Kept for the session the next prompt starts
+		a.pendingDir = dir
+		return dir, nil, nil
+	}
+	a.setSessionDir(a.Session.ID, dir)
+	return dir, a.SaveState(), nil
+}
+
+func (a *App) setSessionDir(sessionID, dir string) {
+	if dir == "" {
+		delete(a.State.SessionDirs, sessionID)
+		return
+	}
+	if a.State.SessionDirs == nil {
+		a.State.SessionDirs = make(map[string]string)
+	}
+	a.State.SessionDirs[sessionID] = dir
+}
+
+// adoptPendingDir gives a session just started the directory set before it
+func (a *App) adoptPendingDir() {
+	if a.pendingDir == "" {
+		return
+	}
+	a.setSessionDir(a.Session.ID, a.pendingDir)
+	a.pendingDir = ""
+	if err := a.saveStateNow(); err != nil {
+		slog.Error("Failed to save state", "error", err)
+	}
+}
+
+// resolveSessionDir resolves the argument of cd from the current directory,
+// both relative to root. A path starting with a slash is an absolute path
+// when it is in the project, and relative to root otherwise. Directories
+// outside the project are refused.
+func resolveSessionDir(root, current, dir string) (string, error) {
+	dir = strings.TrimSpace(dir)
+	switch dir {
+	case "", "/", "~":
+		return "", nil
+	}
+	switch {
+	case filepath.IsAbs(dir) && (dir == root || strings.HasPrefix(dir, root+string(filepath.Separator))):
+	case strings.HasPrefix(dir, "/"):
+		dir = filepath.Join(root, dir)
+	default:
+		dir = filepath.Join(root, current, dir)
+	}
+	rel, err := filepath.Rel(root, filepath.Clean(dir))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.New("the directory is outside the project")
+	}
+	if rel == "." {
+		return "", nil
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// shellLine returns the command line the server runs for a shell command
+// of the session, in its directory
+func (a *App) shellLine(command string) string {
+	dir := a.SessionDir()
+	if dir == "" {
+		return target.ShellLine(util.CwdPath, command)
+	}
+	return target.ShellLineAt(filepath.Join(util.RootPath, filepath.FromSlash(dir)), command)
+}
+
+// toolDirectory returns the absolute directory a session's tools run in,
+// or "" to run them at the project root. Prompts, commands and shell
+// commands pass it to the server as their directory.
+func (a *App) toolDirectory(sessionID string) string {
+	dir := a.State.SessionDirs[sessionID]
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(util.RootPath, filepath.FromSlash(dir))
+}
+
+// sessionSystem returns what a session adds to the system prompt: the
+// instructions of the template it started from, the directory its commands
+// run in, the named shells, the project's tools, its pinned context and the
+// language to answer in
+func (a *App) sessionSystem(sessionID string) string {
+	var parts []string
+	if system := a.State.SessionSystem[sessionID]; system != "" {
+		parts = append(parts, system)
+	}
+	if dir := a.State.SessionDirs[sessionID]; dir != "" {
+		parts = append(parts, fmt.Sprintf(
+			"The user is working in the %s directory of the project, which commands run in. Take relative paths the user mentions as relative to it.",
+			dir,
+		))
+	}
+	if note := a.shellsNote(); note != "" {
+		parts = append(parts, note)
+	}
+	if _, pinned := a.pinnedContext(a.State.SessionContexts[sessionID]); pinned != "" {
+		parts = append(parts, pinned)
+	}
+	if language, _ := a.responseLanguage(sessionID); language != "" {
+		parts = append(parts, fmt.Sprintf(
+			"Always respond in %s, whatever language the user writes in. Keep code, identifiers, commands and quoted text as they are.",
+			language,
+		))
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+func TestResolveSessionDir(t *testing.T) {
+	tests := []struct {
+		current, dir string
+		want         string
+	}{
+		{"", "src", "src"},
+		{"src", "lib/", "src/lib"},
+		{"src/lib", "..", "src"},
+		{"src", "/docs", "docs"},
+		{"src", "/project/web", "web"},
+		{"src", "/", ""},
+		{"src", "..", ""},
+		{"src", "", ""},
+	}
+	for _, test := range tests {
+		got, err := resolveSessionDir("/project", test.current, test.dir)
+		if err != nil || got != test.want {
+			t.Errorf("resolveSessionDir(%q, %q) = %q, %v; want %q", test.current, test.dir, got, err, test.want)
+		}
+	}
+	for _, dir := range []string{"../..", "../../other"} {
+		if got, err := resolveSessionDir("/project", "src", dir); err == nil {
+			t.Errorf("resolveSessionDir(%q) = %q, want an error", dir, got)
+		}
+	}
+}
+
+func TestSessionSystem(t *testing.T) {
+	a := &App{State: NewState()}
+	a.State.SessionSystem = map[string]string{"ses_1": "Be brief."}
+	if system := a.sessionSystem("ses_1"); system != "Be brief." {
+		t.Errorf("system without a directory = %q", system)
+	}
+	a.setSessionDir("ses_1", "web")
+	system := a.sessionSystem("ses_1")
+	if !strings.HasPrefix(system, "Be brief.\n\n") || !strings.Contains(system, "the web directory") {
+		t.Errorf("system with a directory = %q", system)
+	}
+	if dir := a.toolDirectory("ses_1"); dir != filepath.Join(util.RootPath, "web") {
+		t.Errorf("tool directory = %q", dir)
+	}
+	a.setSessionDir("ses_1", "")
+	if dir := a.toolDirectory("ses_1"); dir != "" {
+		t.Errorf("tool directory at the root = %q", dir)
+	}
+	if _, ok := a.State.SessionDirs["ses_1"]; ok {
+		t.Error("the root was kept as a directory")
+	}
+
+	a.State.ResponseLanguage = "French"
+	if system := a.sessionSystem("ses_1"); !strings.Contains(system, "respond in French") {
+		t.Errorf("system with a response language = %q", system)
+	}
+	a.State.SessionLanguages = map[string]string{"ses_1": AnyLanguage}
+	if system := a.sessionSystem("ses_1"); system != "Be brief." {
+		t.Errorf("system of a session answered in any language = %q", system)
+	}
+}
@@ -0,0 +1,341 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+// sessionIndexWorkers bounds the sessions whose messages are fetched at once
+const sessionIndexWorkers = 8
+
+// maxSnippetRunes bounds the excerpt shown for a match
+const maxSnippetRunes = 160
+
+// SessionsIndexedMsg is sent when the session search index is up to date
+type SessionsIndexedMsg struct {
+	Sessions int
+	Err      error
+}
+
+// SessionSearchEntry is a searchable piece of a session: its title or the
+// text of one of its messages
+type SessionSearchEntry struct {
+	Session   opencode.Session
+	MessageID string // Empty for the session title
+	Role      string // "user" or "assistant"; empty for the session title
+	Text      string
+	Updated   time.Time
+}
+
+// SessionSearchResult is an entry matching a query
+type SessionSearchResult struct {
+	Entry   SessionSearchEntry
+	Score   int
+	Snippet string // The matching line, shortened around the match
+	Matches []int  // Rune offsets of the matched characters in Snippet
+}
+
+// SessionSearchIndex holds the titles and message text of every session.
+// Sessions are only re-read when they have been updated since they were
+// indexed.
+type SessionSearchIndex struct {
+	mu       sync.RWMutex
+	sessions map[string]indexedSession
+}
+
+type indexedSession struct {
+	updated float64
+	entries []SessionSearchEntry
+}
+
+// SessionSearch returns the session search index, which is empty until
+// IndexSessions has run
+func (a *App) SessionSearch() *SessionSearchIndex {
+	if a.sessionSearch == nil {
+		a.sessionSearch = &SessionSearchIndex{sessions: make(map[string]indexedSession)}
+	}
+	return a.sessionSearch
+}
+
+// IndexSessions brings the session search index up to date
+func (a *App) IndexSessions() tea.Cmd {
+	index := a.SessionSearch()
+	return func() tea.Msg {
+		ctx := context.Background()
+		sessions, err := a.ListSessions(ctx)
+		if err != nil {
+			return SessionsIndexedMsg{Err: fmt.Errorf("failed to list sessions: %w", err)}
+		}
+
+		index.mu.RLock()
+		var stale []opencode.Session
+		for _, session := range sessions {
+			if indexed, ok := index.sessions[session.ID]; !ok || indexed.updated != session.Time.Updated {
+				stale = append(stale, session)
+			}
+		}
+		index.mu.RUnlock()
+
+		fresh := make(map[string]indexedSession, len(stale))
+		var freshMu sync.Mutex
+		var wg sync.WaitGroup
+		workers := make(chan struct{}, sessionIndexWorkers)
+		for _, session := range stale {
+			wg.Add(1)
+			workers <- struct{}{}
+			go func(session opencode.Session) {
+				defer wg.Done()
+				defer func() { <-workers }()
+				messages, err := a.ListMessages(ctx, session.ID)
+				if err != nil {
+					// Index the title alone; the messages are retried next time
+					messages = nil
+				}
+				indexed := indexedSession{entries: sessionEntries(session, messages)}
+				if err == nil {
+					indexed.updated = session.Time.Updated
+				}
+				freshMu.Lock()
+				fresh[session.ID] = indexed
+				freshMu.Unlock()
+			}(session)
+		}
+		wg.Wait()
+
+		index.mu.Lock()
+		current := make(map[string]indexedSession, len(sessions))
+		for _, session := range sessions {
+			if indexed, ok := fresh[session.ID]; ok {
+				current[session.ID] = indexed
+			} else {
+				current[session.ID] = index.sessions[session.ID]
+			}
+		}
+		index.sessions = current
+		index.mu.Unlock()
+		return SessionsIndexedMsg{Sessions: len(sessions)}
+	}
+}
+
+// sessionEntries returns the searchable entries of a session
+func sessionEntries(session opencode.Session, messages []Message) []SessionSearchEntry {
+	updated := time.UnixMilli(int64(session.Time.Updated))
+	entries := []SessionSearchEntry{{Session: session, Text: session.Title, Updated: updated}}
+	for _, message := range messages {
+		var id, role string
+		switch info := message.Info.(type) {
+		case opencode.UserMessage:
+			id, role = info.ID, "user"
+		case opencode.AssistantMessage:
+			id, role = info.ID, "assistant"
+		default:
+			continue
+		}
+		var texts []string
+		for _, part := range message.Parts {
+			if text, ok := part.(opencode.TextPart); ok && !text.Synthetic {
+				texts = append(texts, text.Text)
+			}
+		}
+		if len(texts) == 0 {
+			continue
+		}
+		entries = append(entries, SessionSearchEntry{
+			Session:   session,
+			MessageID: id,
+			Role:      role,
+			Text:      strings.Join(texts, "\n"),
+			Updated:   updated,
+		})
+	}
+	return entries
+}
+
+// Size returns the number of indexed sessions
+func (s *SessionSearchIndex) Size() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.sessions)
+}
+
+// Search returns the best matches for a query, best first. Titles rank above
+// messages with an equally good match, and recent sessions above old ones.
+func (s *SessionSearchIndex) Search(query string, limit int) []SessionSearchResult {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+
+	s.mu.RLock()
+	var results []SessionSearchResult
+	for _, session := range s.sessions {
+		for _, entry := range session.entries {
+			score, snippet, matches, ok := matchEntry(query, entry.Text)
+			if !ok {
+				continue
+			}
+			if entry.MessageID == "" {
+				score += 50
+			}
+			results = append(results, SessionSearchResult{
+				Entry:   entry,
+				Score:   score,
+				Snippet: snippet,
+				Matches: matches,
+			})
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Entry.Updated.After(results[j].Entry.Updated)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// matchEntry finds the best matching line of a text and returns its score,
+// the line shortened around the match and the matched rune offsets in it
+func matchEntry(query, text string) (int, string, []int, bool) {
+	needle := []rune(strings.ToLower(query))
+	bestScore := 0
+	var bestLine []rune
+	var bestMatches []int
+	for _, line := range strings.Split(text, "\n") {
+		runes := []rune(line)
+		score, matches, ok := fuzzyMatch(needle, runes)
+		if ok && (bestLine == nil || score > bestScore) {
+			bestScore, bestLine, bestMatches = score, runes, matches
+		}
+	}
+	if bestLine == nil {
+		return 0, "", nil, false
+	}
+	snippet, matches := trimSnippet(bestLine, bestMatches)
+	return bestScore, snippet, matches, true
+}
+
+// fuzzyMatch matches the lowercased needle against a line. An exact
+// substring match scores highest; otherwise the needle's characters must
+// appear in order within a short span, so that long messages don't match
+// every query. Matches at word starts score higher.
+func fuzzyMatch(needle, line []rune) (int, []int, bool) {
+	if len(needle) == 0 || len(needle) > len(line) {
+		return 0, nil, false
+	}
+	lower := make([]rune, len(line))
+	for i, r := range line {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	if i := indexRunes(lower, needle); i >= 0 {
+		matches := make([]int, len(needle))
+		for j := range needle {
+			matches[j] = i + j
+		}
+		score := 1000 - min(i, 100)
+		if isWordStart(line, i) {
+			score += 100
+		}
+		return score, matches, true
+	}
+
+	// Find the tightest in-order match, starting from each occurrence of
+	// the needle's first character
+	maxSpan := len(needle)*3 + 6
+	var best []int
+	for start := range lower {
+		if lower[start] != needle[0] {
+			continue
+		}
+		matches := []int{start}
+		for i := start + 1; i < len(lower) && len(matches) < len(needle) && i-start < maxSpan; i++ {
+			if lower[i] == needle[len(matches)] {
+				matches = append(matches, i)
+			}
+		}
+		if len(matches) == len(needle) && (best == nil || matches[len(matches)-1]-matches[0] < best[len(best)-1]-best[0]) {
+			best = matches
+		}
+	}
+	if best == nil {
+		return 0, nil, false
+	}
+
+	span := best[len(best)-1] - best[0] + 1
+	score := 500 - (span-len(needle))*10
+	for _, i := range best {
+		if isWordStart(line, i) {
+			score += 15
+		}
+	}
+	return score, best, true
+}
+
+func indexRunes(haystack, needle []rune) int {
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		found := true
+		for j, r := range needle {
+			if haystack[i+j] != r {
+				found = false
+				break
+			}
+		}
+		if found {
+			return i
+		}
+	}
+	return -1
+}
+
+func isWordStart(line []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, cur := line[i-1], line[i]
+	return !unicode.IsLetter(prev) && !unicode.IsDigit(prev) || unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
+
+// trimSnippet shortens a line to maxSnippetRunes around its first match
+func trimSnippet(line []rune, matches []int) (string, []int) {
+	start := 0
+	if len(line) > maxSnippetRunes && len(matches) > 0 {
+		start = max(0, min(matches[0]-maxSnippetRunes/4, len(line)-maxSnippetRunes))
+	}
+	end := min(len(line), start+maxSnippetRunes)
+	prefix, suffix := "", ""
+	if start > 0 {
+		prefix = "…"
+	}
+	if end < len(line) {
+		suffix = "…"
+	}
+	for start < end && unicode.IsSpace(line[start]) {
+		start++
+	}
+	for end > start && unicode.IsSpace(line[end-1]) {
+		end--
+	}
+
+	offset := len([]rune(prefix)) - start
+	shifted := make([]int, 0, len(matches))
+	for _, i := range matches {
+		if i >= start && i < end {
+			shifted = append(shifted, i+offset)
+		}
+	}
+	return prefix + string(line[start:end]) + suffix, shifted
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		query string
+		line  string
+		match bool
+	}{
+		{"auth", "Fix the auth bridge timeout", true},
+		{"abt", "auth bridge timeout", true},
+		{"AUTH", "oauth flow", true},
+		{"abt", "a long line where b and much later the letter t appears", false},
+		{"zzz", "nothing here", false},
+	}
+	for _, tt := range tests {
+		_, matches, ok := fuzzyMatch([]rune(strings.ToLower(tt.query)), []rune(tt.line))
+		if ok != tt.match {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", tt.query, tt.line, ok, tt.match)
+		}
+		if ok && len(matches) != len(tt.query) {
+			t.Errorf("fuzzyMatch(%q, %q) matched %d runes", tt.query, tt.line, len(matches))
+		}
+	}
+
+	exact, _, _ := fuzzyMatch([]rune("bridge"), []rune("the bridge"))
+	scattered, _, _ := fuzzyMatch([]rune("bridge"), []rune("b r i d g e"))
+	if exact <= scattered {
+		t.Errorf("expected substring match to outrank scattered match (%d <= %d)", exact, scattered)
+	}
+}
+
+func TestSessionSearch(t *testing.T) {
+	index := &SessionSearchIndex{sessions: map[string]indexedSession{}}
+	session := opencode.Session{ID: "s1", Title: "Refactor token refresh"}
+	messages := []Message{
+		{
+			Info:  opencode.UserMessage{ID: "m1"},
+			Parts: []opencode.PartUnion{opencode.TextPart{Text: "Why does the token refresh race?"}},
+		},
+		{
+			Info:  opencode.AssistantMessage{ID: "m2"},
+			Parts: []opencode.PartUnion{opencode.TextPart{Text: "intro\n    The refresh lock is released too early."}},
+		},
+	}
+	index.sessions["s1"] = indexedSession{entries: sessionEntries(session, messages)}
+
+	results := index.Search("refresh lock", 10)
+	if len(results) != 1 || results[0].Entry.MessageID != "m2" {
+		t.Fatalf("expected the assistant message, got %+v", results)
+	}
+	result := results[0]
+	if result.Snippet != "The refresh lock is released too early." {
+		t.Errorf("unexpected snippet %q", result.Snippet)
+	}
+	if runes := []rune(result.Snippet); string(runes[result.Matches[0]:result.Matches[0]+7]) != "refresh" {
+		t.Errorf("matches don't point at the query in %q: %v", result.Snippet, result.Matches)
+	}
+
+	results = index.Search("token refresh", 10)
+	if len(results) < 2 || results[0].Entry.MessageID != "" {
+		t.Errorf("expected the title to rank first, got %+v", results)
+	}
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/intelligence"
+	"github.com/aaronmrosenthal/rycode/internal/transcript"
+)
+
+// SessionSummaryMsg is sent with the stats of a session that was left
+type SessionSummaryMsg struct {
+	Session opencode.Session
+	Stats   transcript.Stats
+	Left    time.Time // Zero when the session is still open
+}
+
+// SessionsPath returns the file the stats of left sessions are kept in
+func (a *App) SessionsPath() string {
+	return filepath.Join(a.InsightsDir(), "sessions.jsonl")
+}
+
+// maxRecentSessions is the number of left sessions /summary recent lists
+const maxRecentSessions = 5
+
+// SummarizeSession reads the stats of the current session, as it is being
+// cleared or switched away from when leaving. The whole history is read from
+// the server, since older messages may have been paged out of the chat, so
+// a session is left without reading it when summaries are off.
+func (a *App) SummarizeSession(leaving bool) tea.Cmd {
+	if a.Session.ID == "" || len(a.Messages) == 0 || a.Tutorial != nil {
+		return nil
+	}
+	if leaving && !a.State.SessionSummary {
+		return nil
+	}
+	session := *a.Session
+	return func() tea.Msg {
+		ctx, cancel := a.TimeoutContext(TimeoutAPI)
+		defer cancel()
+		t, err := a.sessionTranscript(ctx, session.ID)
+		if err != nil {
+			slog.Warn("Failed to summarize session", "session", session.ID, "error", err)
+			return nil
+		}
+		msg := SessionSummaryMsg{Session: session, Stats: t.Stats()}
+		if leaving {
+			msg.Left = time.Now()
+		}
+		return msg
+	}
+}
+
+// ShowSessionSummary returns the card showing the stats of a session. The
+// stats of a left session are added to the insights too.
+func (a *App) ShowSessionSummary(msg SessionSummaryMsg) tea.Cmd {
+	stats := msg.Stats
+	if !msg.Left.IsZero() {
+		a.recordSessionSummary(msg)
+	}
+	title := msg.Session.Title
+	if title == "" {
+		title = "Session"
+	}
+	return toast.NewInfoToast(stats.Card(), toast.WithTitle(title), toast.WithDuration(8*time.Second))
+}
+
+func (a *App) recordSessionSummary(msg SessionSummaryMsg) {
+	stats := msg.Stats
+	err := intelligence.AppendSessionRecord(a.SessionsPath(), intelligence.SessionRecord{
+		Time:         msg.Left,
+		SessionID:    msg.Session.ID,
+		SessionTitle: msg.Session.Title,
+		Duration:     stats.Duration,
+		Messages:     stats.Messages,
+		InputTokens:  stats.InputTokens,
+		OutputTokens: stats.OutputTokens,
+		Cost:         stats.Cost,
+		FilesChanged: len(stats.Files),
+		TestRuns:     stats.TestRuns,
+		TestFailures: stats.TestFailures,
+	})
+	if err != nil {
+		slog.Warn("Failed to record session summary", "error", err)
+	}
+}
+
+// RecentSessions returns the card of the sessions left last, newest first,
+// as the insights recorded them
+func (a *App) RecentSessions() tea.Cmd {
+	records, err := intelligence.LoadSessionRecords(a.SessionsPath())
+	if err != nil {
+		return toast.NewErrorToast(err.Error())
+	}
+	if len(records) == 0 {
+		return toast.NewInfoToast("No session was summarized yet; /summary on records them as you leave them")
+	}
+	return toast.NewInfoToast(recentSessionsCard(records), toast.WithTitle("Recent sessions"), toast.WithDuration(10*time.Second))
+}
+
+// recentSessionsCard lists the sessions left last, newest first, under the
+// totals of every recorded session
+func recentSessionsCard(records []intelligence.SessionRecord) string {
+	var duration time.Duration
+	var cost float64
+	for _, record := range records {
+		duration += record.Duration
+		cost += record.Cost
+	}
+	lines := []string{fmt.Sprintf("%d sessions · %s · $%.2f", len(records), transcript.FormatDuration(duration), cost)}
+	recent := slices.Clone(records[max(0, len(records)-maxRecentSessions):])
+	slices.Reverse(recent)
+	for _, record := range recent {
+		title := record.SessionTitle
+		if title == "" {
+			title = record.SessionID
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s · %d messages · $%.2f · %d files",
+			title, transcript.FormatDuration(record.Duration), record.Messages, record.Cost, record.FilesChanged))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SetSessionSummary turns the summary card on or off
+func (a *App) SetSessionSummary(on bool) tea.Cmd {
+	a.State.SessionSummary = on
+	return a.SaveState()
+}
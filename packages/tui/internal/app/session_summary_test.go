@@ -0,0 +1,42 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/intelligence"
+)
+
+func TestSummarizeSessionWhenLeaving(t *testing.T) {
+	a := &App{
+		State:    NewState(),
+		Session:  &opencode.Session{ID: "ses_1"},
+		Messages: []Message{{Info: opencode.UserMessage{ID: "msg_1"}}},
+	}
+	if a.SummarizeSession(true) != nil {
+		t.Error("leaving a session read its history with summaries off")
+	}
+	if a.SummarizeSession(false) == nil {
+		t.Error("/summary didn't read the session's history")
+	}
+	a.State.SessionSummary = true
+	if a.SummarizeSession(true) == nil {
+		t.Error("leaving a session wasn't summarized with summaries on")
+	}
+}
+
+func TestRecentSessionsCard(t *testing.T) {
+	var records []intelligence.SessionRecord
+	for i, title := range []string{"one", "two", "three", "four", "five", "six"} {
+		records = append(records, intelligence.SessionRecord{SessionTitle: title, Duration: time.Minute, Cost: 0.5, Messages: i})
+	}
+	lines := strings.Split(recentSessionsCard(records), "\n")
+	if len(lines) != 1+maxRecentSessions || lines[0] != "6 sessions · 6m · $3.00" {
+		t.Fatalf("card = %q, want the totals and %d sessions", lines, maxRecentSessions)
+	}
+	if !strings.HasPrefix(lines[1], "six: ") || !strings.HasPrefix(lines[len(lines)-1], "two: ") {
+		t.Errorf("sessions = %q, want the newest first", lines[1:])
+	}
+}
@@ -0,0 +1,274 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/shells"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// ShellOutputMsg is sent when a named shell printed something or exited
+type ShellOutputMsg struct {
+	changed <-chan struct{}
+}
+
+// ShellInboxMsg checks the inboxes of the named shells for commands the
+// agent left there
+type ShellInboxMsg struct{}
+
+// shellInboxInterval is how often the inboxes are checked while shells run
+const shellInboxInterval = time.Second
+
+// Shells returns the named shells, in the order they were started
+func (a *App) Shells() []*shells.Shell {
+	if a.shells == nil {
+		return nil
+	}
+	return a.shells.List()
+}
+
+// Shell returns the named shell with a name, or nil
+func (a *App) Shell(name string) *shells.Shell {
+	if a.shells == nil {
+		return nil
+	}
+	return a.shells.Get(name)
+}
+
+// RunInShell runs a command in a named shell, which starts in the session's
+// directory when it isn't running yet. Without a command it only starts
+// the shell.
+func (a *App) RunInShell(name, command string) tea.Cmd {
+	var cmds []tea.Cmd
+	s := a.Shell(name)
+	if s == nil || !s.Running() {
+		dir := a.SessionDir()
+		if s != nil {
+			dir = s.Dir
+		}
+		var err error
+		if s, cmds, err = a.startShell(name, dir); err != nil {
+			return toast.NewErrorToast(err.Error(), toast.WithTitle("Shell"))
+		}
+	}
+	if command != "" {
+		if err := s.Run(command); err != nil {
+			return toast.NewErrorToast(err.Error(), toast.WithTitle("Shell"))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// RestartShell stops a named shell and whatever runs in it, and starts it
+// again in the directory it started in
+func (a *App) RestartShell(name string) tea.Cmd {
+	s := a.Shell(name)
+	if s == nil {
+		return toast.NewErrorToast("No shell named "+name, toast.WithTitle("Shell"))
+	}
+	_, cmds, err := a.startShell(s.Name, s.Dir)
+	if err != nil {
+		return toast.NewErrorToast(err.Error(), toast.WithTitle("Shell"))
+	}
+	return tea.Batch(append(cmds, toast.NewInfoToast("Restarted "+s.Name, toast.WithTitle("Shell")))...)
+}
+
+// CloseShell stops a named shell and whatever runs in it
+func (a *App) CloseShell(name string) tea.Cmd {
+	if a.shells == nil || a.shells.Get(name) == nil {
+		return toast.NewErrorToast("No shell named "+name, toast.WithTitle("Shell"))
+	}
+	if err := a.shells.Close(name); err != nil {
+		slog.Warn("Failed to stop shell", "shell", name, "error", err)
+	}
+	return toast.NewInfoToast("Closed "+name, toast.WithTitle("Shell"))
+}
+
+// StopShells stops the named shells when the TUI exits
+func (a *App) StopShells() {
+	if a.shells != nil {
+		a.shells.CloseAll()
+	}
+}
+
+// startShell starts a named shell in a directory of the project, along with
+// waiting for the shells' output and checking their inboxes
+func (a *App) startShell(name, dir string) (*shells.Shell, []tea.Cmd, error) {
+	var cmds []tea.Cmd
+	if a.shells == nil {
+		// One directory per TUI, as each has its own shells
+		dir := filepath.Join(filepath.Dir(a.StatePath), "shells", strconv.Itoa(os.Getpid()))
+		a.shells = shells.NewPool(dir)
+		cmds = append(cmds, waitShellOutput(a.shells.Changed()))
+	}
+	s, err := a.shells.Start(name, util.RootPath, dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	slog.Info("Shell started", "shell", name, "dir", s.Dir)
+	if !a.shellInbox {
+		a.shellInbox = true
+		cmds = append(cmds, checkShellInboxAfter(shellInboxInterval))
+	}
+	return s, cmds, nil
+}
+
+func waitShellOutput(changed <-chan struct{}) tea.Cmd {
+	return func() tea.Msg {
+		<-changed
+		return ShellOutputMsg{changed: changed}
+	}
+}
+
+// HandleShellOutput reports shells that exited and waits for more output
+func (a *App) HandleShellOutput(msg ShellOutputMsg) tea.Cmd {
+	cmds := []tea.Cmd{waitShellOutput(msg.changed)}
+	for _, s := range a.Shells() {
+		err, exited := s.TakeExit()
+		if !exited {
+			continue
+		}
+		text := s.Name + " exited"
+		if err != nil {
+			text += ": " + err.Error()
+		}
+		cmds = append(cmds, toast.NewWarningToast(text, toast.WithTitle("Shell")))
+	}
+	return tea.Batch(cmds...)
+}
+
+func checkShellInboxAfter(delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(time.Time) tea.Msg { return ShellInboxMsg{} })
+}
+
+// shellPermissionPrefix starts the IDs of the permissions asked for inbox
+// commands, which are answered here rather than by the server
+const shellPermissionPrefix = "shell_"
+
+// shellRequest is an inbox command waiting on its permission
+type shellRequest struct {
+	shell   string
+	command string
+}
+
+// CheckShellInboxes asks permission for the commands the agent left in the
+// shells' inboxes, as for its bash calls, and keeps checking while shells
+// run. The requests go through the permission rules and prompt as the
+// server's do, and the command runs once allowed.
+func (a *App) CheckShellInboxes() tea.Cmd {
+	running := false
+	var cmds []tea.Cmd
+	for _, s := range a.Shells() {
+		if !s.Running() {
+			continue
+		}
+		running = true
+		commands, err := a.shells.TakeInbox(s.Name)
+		if err != nil {
+			slog.Warn("Failed to read shell inbox", "shell", s.Name, "error", err)
+		}
+		for _, command := range commands {
+			cmds = append(cmds, util.CmdHandler(a.shellPermission(s.Name, command)))
+		}
+	}
+	if !running {
+		a.shellInbox = false
+		return tea.Batch(cmds...)
+	}
+	return tea.Batch(append(cmds, checkShellInboxAfter(shellInboxInterval))...)
+}
+
+// shellPermission returns the permission request of an inbox command, as
+// the server sends it for a bash call
+func (a *App) shellPermission(shell, command string) opencode.EventListResponseEventPermissionUpdated {
+	if a.shellRequests == nil {
+		a.shellRequests = make(map[string]shellRequest)
+	}
+	a.shellAsked++
+	id := fmt.Sprintf("%s%d", shellPermissionPrefix, a.shellAsked)
+	a.shellRequests[id] = shellRequest{shell: shell, command: command}
+	return opencode.EventListResponseEventPermissionUpdated{
+		Type: opencode.EventListResponseEventPermissionUpdatedTypePermissionUpdated,
+		Properties: opencode.Permission{
+			ID:        id,
+			SessionID: a.Session.ID,
+			Type:      "bash",
+			Title:     command,
+			Metadata:  map[string]any{"command": command, "shell": shell},
+			Time:      opencode.PermissionTime{Created: float64(time.Now().UnixMilli())},
+		},
+	}
+}
+
+// ShellPermissionPart returns the bash call a permission asked for an inbox
+// command stands for, to be shown as the server's requests are
+func (a *App) ShellPermissionPart(permission opencode.Permission) (opencode.ToolPart, bool) {
+	request, ok := a.shellRequests[permission.ID]
+	if !ok {
+		return opencode.ToolPart{}, false
+	}
+	return opencode.ToolPart{
+		ID:     permission.ID,
+		Tool:   "bash",
+		CallID: permission.CallID,
+		State: opencode.ToolPartState{
+			Status: opencode.ToolPartStateStatusRunning,
+			Title:  "Run in shell " + request.shell,
+			Input:  map[string]any{"command": request.command, "description": "Run in shell " + request.shell},
+		},
+	}, true
+}
+
+// answerShellPermission runs the inbox command a permission was asked for
+// once it's allowed. It reports whether the permission was one of those.
+func (a *App) answerShellPermission(permissionID string, response opencode.SessionPermissionRespondParamsResponse) (tea.Cmd, bool) {
+	request, ok := a.shellRequests[permissionID]
+	if !ok {
+		return nil, false
+	}
+	delete(a.shellRequests, permissionID)
+	if response == opencode.SessionPermissionRespondParamsResponseReject {
+		slog.Info("Rejected command from the agent", "shell", request.shell, "command", request.command)
+		return nil, true
+	}
+	s := a.Shell(request.shell)
+	if s == nil {
+		return toast.NewErrorToast("No shell named "+request.shell, toast.WithTitle("Shell")), true
+	}
+	slog.Info("Running command from the agent", "shell", request.shell, "command", request.command)
+	if err := s.Run(request.command); err != nil {
+		return toast.NewErrorToast(err.Error(), toast.WithTitle("Shell")), true
+	}
+	return nil, true
+}
+
+// shellsNote tells the agent about the named shells and how to use them
+func (a *App) shellsNote() string {
+	list := a.Shells()
+	if len(list) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("The user keeps named shells running, such as dev servers. To run a command in one, append it as a line to its inbox file; the user is asked to allow it as for your bash calls, and its output is appended to its log file. Don't start another copy of what a shell runs.")
+	for _, s := range list {
+		state := "running"
+		if !s.Running() {
+			state = "exited"
+		}
+		dir := s.Dir
+		if dir == "" {
+			dir = "the project root"
+		}
+		fmt.Fprintf(&b, "\n- %s (%s, in %s): inbox %s, log %s", s.Name, state, dir, a.shells.InboxPath(s.Name), a.shells.LogPath(s.Name))
+	}
+	return b.String()
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/permissions"
+)
+
+func TestShellPermission(t *testing.T) {
+	a := &App{Session: &opencode.Session{ID: "ses_1"}, State: NewState()}
+	asked := a.shellPermission("dev", "npm run build")
+	permission := asked.Properties
+	if permission.Type != "bash" || permissions.Subject(permission) != "npm run build" {
+		t.Fatalf("permission = %+v, want a bash request for the command", permission)
+	}
+	if part, ok := a.ShellPermissionPart(permission); !ok || part.Tool != "bash" {
+		t.Errorf("ShellPermissionPart = %+v, %v", part, ok)
+	}
+
+	// Rules answer inbox commands as they do bash calls
+	a.State.PermissionRules = []permissions.Rule{{Tool: "bash", Pattern: "npm run *", Allow: false}}
+	if rule := a.PermissionRule(permission); rule == nil || rule.Allow {
+		t.Errorf("rule = %v, want the deny rule", rule)
+	}
+
+	if cmd := a.RespondPermission(permission.SessionID, permission.ID, opencode.SessionPermissionRespondParamsResponseReject); cmd != nil {
+		t.Error("a rejected command was run")
+	}
+	if _, ok := a.ShellPermissionPart(permission); ok {
+		t.Error("an answered request is still waiting")
+	}
+
+	// The shell may be gone by the time the command is allowed
+	permission = a.shellPermission("gone", "ls").Properties
+	if cmd := a.RespondPermission(permission.SessionID, permission.ID, opencode.SessionPermissionRespondParamsResponseOnce); cmd == nil {
+		t.Error("allowing a command of a closed shell reported nothing")
+	}
+}
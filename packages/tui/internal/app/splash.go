@@ -0,0 +1,72 @@
+package app
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/accessibility"
+	"github.com/aaronmrosenthal/rycode/internal/splash"
+)
+
+// SplashEnabled reports whether the startup splash is shown: it wasn't
+// turned off with /splash or in the splash config, the config's frequency
+// has it shown this time, and the TUI isn't inline, plain or read by a
+// screen reader, which a full-screen animation would only get in the way of
+func (a *App) SplashEnabled() bool {
+	if a.State.NoSplash || a.SkipSplash || a.Inline || a.Plain || a.ScreenReader() {
+		return false
+	}
+	config, err := splash.LoadConfig()
+	if err != nil {
+		config = splash.DefaultConfig()
+	}
+	return config.SplashEnabled && config.Due()
+}
+
+// SplashShown records that the startup splash was shown, so that it isn't
+// again when the frequency is "first"
+func (a *App) SplashShown() {
+	if err := splash.MarkAsShown(); err != nil {
+		slog.Warn("Failed to mark splash as shown", "error", err)
+	}
+}
+
+// SetSplash turns the startup splash on or off
+func (a *App) SetSplash(on bool) tea.Cmd {
+	a.State.NoSplash = !on
+	return a.SaveState()
+}
+
+// ReducedMotion reports whether animations are to be kept still, as asked
+// in the accessibility settings, the splash config or PREFERS_REDUCED_MOTION
+func (a *App) ReducedMotion() bool {
+	if accessibility.GetSettings().IsReducedMotion() || os.Getenv("PREFERS_REDUCED_MOTION") == "1" {
+		return true
+	}
+	config, err := splash.LoadConfig()
+	return err == nil && config.ReducedMotion
+}
+
+// StartupProviderID returns the provider the TUI is expected to start
+// with, before the providers are listed: the provider of --model, of the
+// config's model, of the agent's model or the last one used. It is "" when
+// there's no telling.
+func (a *App) StartupProviderID() string {
+	if a.Provider != nil {
+		return a.Provider.ID
+	}
+	for _, model := range []*string{a.InitialModel, &a.Config.Model} {
+		if model == nil {
+			continue
+		}
+		if provider, _, ok := strings.Cut(*model, "/"); ok && provider != "" {
+			return provider
+		}
+	}
+	if model, ok := a.State.AgentModel[a.State.Agent]; ok {
+		return model.ProviderID
+	}
+	return a.State.Provider
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"math"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// Share of the width the output pane of the split layout takes
+const (
+	DefaultSplitPaneShare = 0.45
+	minSplitPaneShare     = 0.25
+	maxSplitPaneShare     = 0.7
+	splitPaneStep         = 0.05
+)
+
+// SplitPaneShare returns the share of the width the output pane takes
+func (a *App) SplitPaneShare() float64 {
+	if a.State.SplitPaneShare == 0 {
+		return DefaultSplitPaneShare
+	}
+	return min(maxSplitPaneShare, max(minSplitPaneShare, a.State.SplitPaneShare))
+}
+
+// SetSplitPane turns the split layout on or off
+func (a *App) SetSplitPane(on bool) tea.Cmd {
+	a.State.SplitPane = on
+	return a.SaveState()
+}
+
+// ResizeSplitPane widens the output pane by a step, or narrows it when steps
+// is negative. It reports whether the pane's width changed.
+func (a *App) ResizeSplitPane(steps int) (bool, tea.Cmd) {
+	share := a.SplitPaneShare() + float64(steps)*splitPaneStep
+	share = min(maxSplitPaneShare, max(minSplitPaneShare, math.Round(share*100)/100))
+	if share == a.SplitPaneShare() {
+		return false, nil
+	}
+	a.State.SplitPaneShare = share
+	return true, a.SaveState()
+}
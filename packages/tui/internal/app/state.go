@@ -1,13 +1,12 @@
 package app
 
 import (
-	"bufio"
 	"fmt"
-	"log/slog"
 	"os"
 	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/aaronmrosenthal/rycode/internal/permissions"
 )
 
 type ModelUsage struct {
@@ -34,9 +33,63 @@ type State struct {
 	Agent              string                `toml:"agent"`
 	RecentlyUsedModels []ModelUsage          `toml:"recently_used_models"`
 	RecentlyUsedAgents []AgentUsage          `toml:"recently_used_agents"`
-	MessageHistory     []Prompt              `toml:"message_history"`
+	MessageHistory     []Prompt              `toml:"message_history,omitempty"` // Prompts from before the history file, carried into it
 	ShowToolDetails    *bool                 `toml:"show_tool_details"`
 	ShowThinkingBlocks *bool                 `toml:"show_thinking_blocks"`
+	ShowToolLog        bool                  `toml:"show_tool_log,omitempty"`        // Tail the running tool calls in a pane below the chat
+	EditTrackingWindow string                `toml:"edit_tracking_window,omitempty"` // e.g. "10m", or "off"
+	UsageRetentionDays *int                  `toml:"usage_retention_days,omitempty"` // 0 keeps usage forever
+	ProviderFailover   *bool                 `toml:"provider_failover,omitempty"`
+	StatusBar          *StatusBarLayout      `toml:"status_bar,omitempty"` // nil shows the default widgets
+	VimMode            bool                  `toml:"vim_mode,omitempty"`
+	ClipboardWatch     bool                  `toml:"clipboard_watch,omitempty"` // Offer to explain copied errors
+	PathRules          *bool                 `toml:"path_rules,omitempty"`      // nil leaves the project's path rules on
+	SessionSystem      map[string]string     `toml:"session_system,omitempty"`  // Instructions a session's template adds to the system prompt, by session ID
+	SessionDirs        map[string]string     `toml:"session_dirs,omitempty"`    // Directory a session's shell commands run in, relative to the project root, by session ID
+	DisabledPlugins    []string              `toml:"disabled_plugins,omitempty"`
+	DismissedHints     []string              `toml:"dismissed_hints,omitempty"` // Hint contexts not to show again
+	TipCounts          map[string]int        `toml:"tip_counts,omitempty"`      // Interactions counted towards progressive tips
+	TipsShown          []string              `toml:"tips_shown,omitempty"`
+	GlobalHistory      bool                  `toml:"global_history,omitempty"`    // Recall the prompts of every project, not only the current one
+	ArchivedSessions   []ArchivedSession     `toml:"archived_sessions,omitempty"` // Sessions cleared from the screen, newest first
+	ProviderAgents     map[string]string     `toml:"provider_agents,omitempty"`   // Agent switched to with each provider, by provider ID
+	PinnedSessions     []string              `toml:"pinned_sessions,omitempty"`   // Sessions listed first, by ID
+	RelatedSessions    map[string][]string   `toml:"related_sessions,omitempty"`  // Sessions linked as the same task, both ways, by session ID
+	Bookmarks          []Bookmark            `toml:"bookmarks,omitempty"`         // Newest first
+	BackgroundCap      *float64              `toml:"background_cap,omitempty"`    // Daily spend of background features in USD; nil uses the default
+	BackgroundOff      []string              `toml:"background_off,omitempty"`    // Background features turned off
+	ScreenReader       bool                  `toml:"screen_reader,omitempty"`     // Speak replies and state changes
+	KeyboardOnly       bool                  `toml:"keyboard_only,omitempty"`     // Leave the mouse to the terminal, doing everything from the keyboard
+	NotifyOff          []string              `toml:"notify_off,omitempty"`        // Events not notified while the terminal is unfocused, of NotifyEvents
+	AutoCompact        *AutoCompactPolicy    `toml:"auto_compact,omitempty"`      // nil never compacts
+	Timeouts           *Timeouts             `toml:"timeouts,omitempty"`          // Overrides of the default timeout of each TimeoutClass
+	NoSplash           bool                  `toml:"no_splash,omitempty"`         // Skip the provider-branded startup splash
+	SessionSummary     bool                  `toml:"session_summary,omitempty"`   // Show a summary card on leaving a session
+	SplitPane          bool                  `toml:"split_pane,omitempty"`        // Show the newest tool output beside the chat on wide terminals
+	SplitPaneShare     float64               `toml:"split_pane_share,omitempty"`  // Share of the width the output takes, DefaultSplitPaneShare when zero
+	SaveArtifacts      bool                  `toml:"save_artifacts,omitempty"`    // Save the scripts, configs and queries of answers to ArtifactsDir
+	VerifyRefactors    bool                  `toml:"verify_refactors,omitempty"`  // Look for leftovers of answers renaming or changing signatures across files
+	ColorDepth         string                `toml:"color_depth,omitempty"`       // truecolor, 256 or 16; empty detects the terminal's
+	RunLimits          *RunLimits            `toml:"run_limits,omitempty"`        // Budgets of agent runs, nil leaves them unbounded
+	LoopLimit          int                   `toml:"loop_limit,omitempty"`        // Repetitions of a failing call or back-and-forth edit that interrupt a run; 0 uses loopguard.DefaultLimit, negative never interrupts
+	Inline             bool                  `toml:"inline,omitempty"`            // Run in the normal screen, leaving replies in the terminal's scrollback
+	RecentGlyphs       []string              `toml:"recent_glyphs,omitempty"`     // Symbols inserted with the picker, newest first
+	FavoriteGlyphs     []string              `toml:"favorite_glyphs,omitempty"`
+	ThemeFonts         map[string]ThemeFont  `toml:"theme_fonts,omitempty"`       // Decorations turned off, by theme name
+	HomeDashboard      *bool                 `toml:"home_dashboard,omitempty"`    // nil shows the spend, recent sessions and provider health on the home screen
+	ContextSets        map[string][]string   `toml:"context_sets,omitempty"`      // Files, directories and URLs pinned to conversations, by set name
+	SessionContexts    map[string]string     `toml:"session_contexts,omitempty"`  // Context set a session re-includes with each prompt, by session ID
+	ExpandedTools      map[string][]string   `toml:"expanded_tools,omitempty"`    // Folded tool calls expanded to show their output, by message ID
+	ResponseLanguage   string                `toml:"response_language,omitempty"` // Language the assistant answers in, whatever the prompt's
+	SessionLanguages   map[string]string     `toml:"session_languages,omitempty"` // Overrides of the response language, AnyLanguage to answer in the prompt's, by session ID
+	PermissionRules    []permissions.Rule    `toml:"permission_rules,omitempty"`  // Remembered answers to permission requests, newest first
+}
+
+// StatusBarLayout names the widgets on each side of the status bar, in the
+// order they are shown
+type StatusBarLayout struct {
+	Left  []string `toml:"left"`
+	Right []string `toml:"right"`
 }
 
 func NewState() *State {
@@ -123,33 +176,14 @@ func (s *State) RemoveAgentFromRecentlyUsed(agentName string) {
 	}
 }
 
-func (s *State) AddPromptToHistory(prompt Prompt) {
-	s.MessageHistory = append([]Prompt{prompt}, s.MessageHistory...)
-	if len(s.MessageHistory) > 50 {
-		s.MessageHistory = s.MessageHistory[:50]
-	}
-}
-
-// SaveState writes the provided Config struct to the specified TOML file.
-// It will create the file if it doesn't exist, or overwrite it if it does.
+// SaveState writes the state to the specified TOML file, replacing it
+// whole or creating it if it doesn't exist
 func SaveState(filePath string, state *State) error {
-	file, err := os.Create(filePath)
+	data, err := encodeState(state)
 	if err != nil {
-		return fmt.Errorf("failed to create/open config file %s: %w", filePath, err)
+		return err
 	}
-	defer file.Close()
-
-	writer := bufio.NewWriter(file)
-	encoder := toml.NewEncoder(writer)
-	if err := encoder.Encode(state); err != nil {
-		return fmt.Errorf("failed to encode state to TOML file %s: %w", filePath, err)
-	}
-	if err := writer.Flush(); err != nil {
-		return fmt.Errorf("failed to flush writer for state file %s: %w", filePath, err)
-	}
-
-	slog.Debug("State saved to file", "file", filePath)
-	return nil
+	return writeStateFile(filePath, data)
 }
 
 // LoadState loads the state from the specified TOML file.
@@ -170,5 +204,9 @@ func LoadState(filePath string) (*State, error) {
 		}
 	}
 
+	if state.Timeouts != nil {
+		state.Timeouts.validate()
+	}
+
 	return &state, nil
 }
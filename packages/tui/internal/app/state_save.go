@@ -0,0 +1,138 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/BurntSushi/toml"
+)
+
+// stateSaveDelay is how long state changes gather before the state is
+// written, so that cycling through models or agents writes it once
+const stateSaveDelay = 750 * time.Millisecond
+
+// StateFlushMsg writes the state once changes have settled
+type StateFlushMsg struct{}
+
+// stateSaver tracks unwritten state changes and what was last written
+type stateSaver struct {
+	mu        sync.Mutex // Serializes writes and guards written
+	written   []byte     // State last written, to skip writes that change nothing
+	dirty     bool       // The state changed since it was last written
+	scheduled bool       // A StateFlushMsg is on its way
+	changed   time.Time  // Last change
+}
+
+// SaveState marks the state changed. It is written once changes have
+// stopped coming for stateSaveDelay, and only when it differs from what was
+// last written.
+func (a *App) SaveState() tea.Cmd {
+	a.saver.dirty = true
+	a.saver.changed = time.Now()
+	if a.saver.scheduled {
+		return nil
+	}
+	a.saver.scheduled = true
+	return flushStateAfter(stateSaveDelay)
+}
+
+func flushStateAfter(delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(time.Time) tea.Msg { return StateFlushMsg{} })
+}
+
+// FlushState writes the state when changes have settled, or waits for them
+// to. The state is encoded here, in the update loop that changes it, and
+// written in the background.
+func (a *App) FlushState() tea.Cmd {
+	a.saver.scheduled = false
+	if !a.saver.dirty {
+		return nil
+	}
+	if wait := stateSaveDelay - time.Since(a.saver.changed); wait > 0 {
+		a.saver.scheduled = true
+		return flushStateAfter(wait)
+	}
+	a.saver.dirty = false
+	data, err := encodeState(a.State)
+	if err != nil {
+		slog.Error("Failed to save state", "error", err)
+		return nil
+	}
+	return func() tea.Msg {
+		if err := a.writeState(data); err != nil {
+			slog.Error("Failed to save state", "error", err)
+		}
+		return nil
+	}
+}
+
+// FlushStateNow writes unwritten state changes at once, as the TUI exits
+func (a *App) FlushStateNow() {
+	if !a.saver.dirty {
+		return
+	}
+	a.saver.dirty = false
+	if err := a.saveStateNow(); err != nil {
+		slog.Error("Failed to save state", "error", err)
+	}
+}
+
+// saveStateNow encodes and writes the state at once
+func (a *App) saveStateNow() error {
+	data, err := encodeState(a.State)
+	if err != nil {
+		return err
+	}
+	return a.writeState(data)
+}
+
+// writeState writes encoded state unless it is what was last written
+func (a *App) writeState(data []byte) error {
+	a.saver.mu.Lock()
+	defer a.saver.mu.Unlock()
+	if bytes.Equal(data, a.saver.written) {
+		return nil
+	}
+	if err := writeStateFile(a.StatePath, data); err != nil {
+		return err
+	}
+	a.saver.written = data
+	return nil
+}
+
+// encodeState encodes the state as TOML
+func encodeState(state *State) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(state); err != nil {
+		return nil, fmt.Errorf("failed to encode state to TOML: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeStateFile replaces the state file with data through a temporary
+// file, so that an interrupted write leaves the previous state
+func writeStateFile(filePath string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create state file %s: %w", filePath, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state file %s: %w", filePath, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state file %s: %w", filePath, err)
+	}
+	if err := os.Rename(tmp.Name(), filePath); err != nil {
+		return fmt.Errorf("failed to replace state file %s: %w", filePath, err)
+	}
+	slog.Debug("State saved to file", "file", filePath)
+	return nil
+}
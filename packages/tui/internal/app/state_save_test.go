@@ -0,0 +1,74 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveStateDebounces(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tui")
+	a := &App{StatePath: path, State: NewState()}
+
+	// Cycling through agents schedules a single write
+	if a.SaveState() == nil {
+		t.Fatal("first change scheduled no write")
+	}
+	for _, agent := range []string{"plan", "build", "plan"} {
+		a.State.Agent = agent
+		if cmd := a.SaveState(); cmd != nil {
+			t.Fatalf("change to %s scheduled another write", agent)
+		}
+	}
+
+	// Changes still coming push the write back
+	if cmd := a.FlushState(); cmd == nil || !a.saver.dirty {
+		t.Fatal("flush right after a change didn't wait for changes to settle")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("state was written before changes settled")
+	}
+
+	a.saver.changed = time.Now().Add(-stateSaveDelay)
+	write := a.FlushState()
+	if write == nil {
+		t.Fatal("settled changes weren't written")
+	}
+	write()
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Agent != "plan" {
+		t.Errorf("saved agent = %q, want plan", state.Agent)
+	}
+
+	// Nothing changed since, so nothing is written
+	if a.FlushState() != nil {
+		t.Error("a flush without changes wrote the state")
+	}
+}
+
+func TestWriteStateSkipsUnchanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tui")
+	a := &App{StatePath: path, State: NewState()}
+	if err := a.saveStateNow(); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(path)
+	if err := a.saveStateNow(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("unchanged state was written again")
+	}
+
+	a.State.Theme = "nord"
+	if err := a.saveStateNow(); err != nil {
+		t.Fatal(err)
+	}
+	if state, err := LoadState(path); err != nil || state.Theme != "nord" {
+		t.Errorf("changed state wasn't written: %v, %v", state, err)
+	}
+}
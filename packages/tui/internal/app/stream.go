@@ -0,0 +1,373 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+)
+
+const (
+	// StreamStallTimeout is how long a response can go without events
+	// before its stream is taken for stalled, unless the stream timeout
+	// is set
+	StreamStallTimeout = 30 * time.Second
+	// toolStallTimeout replaces StreamStallTimeout while a tool runs, as
+	// tools such as a build or a test run send nothing until they finish
+	toolStallTimeout = 3 * time.Minute
+	// streamWatchTick is how often the stalled banner's elapsed time is
+	// redrawn
+	streamWatchTick = time.Second
+	// reconnectMinDelay and reconnectMaxDelay bound the wait before
+	// subscribing again to a stream that dropped, which doubles with each
+	// attempt
+	reconnectMinDelay = time.Second
+	reconnectMaxDelay = 30 * time.Second
+)
+
+// StreamDroppedMsg is sent when the subscription to the server's events
+// ended without being asked to, as when the server restarts or the laptop
+// sleeps, and before each attempt to subscribe again
+type StreamDroppedMsg struct {
+	Err     error // Nil when the server closed the stream
+	Attempt int   // Attempts to subscribe again so far, from 1
+	Delay   time.Duration
+}
+
+// StreamResumedMsg is sent when the subscription is back after it dropped
+type StreamResumedMsg struct {
+	Down time.Duration
+}
+
+// EventStream feeds the server's events to the TUI, and is restarted when
+// the watchdog finds it stalled
+type EventStream struct {
+	ctx    context.Context
+	client *opencode.Client
+	send   func(tea.Msg)
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	dropped time.Time // When the stream dropped, zero while it is up
+}
+
+// StartEventStream subscribes to the server's events until ctx is done,
+// sending each to the TUI with send
+func StartEventStream(ctx context.Context, client *opencode.Client, send func(tea.Msg)) *EventStream {
+	s := &EventStream{ctx: ctx, client: client, send: send}
+	s.Reconnect()
+	return s
+}
+
+// Reconnect drops the subscription and subscribes again
+func (s *EventStream) Reconnect() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.cancel = cancel
+	go s.run(ctx)
+}
+
+// SetClient subscribes to the events of another client's directory
+func (s *EventStream) SetClient(client *opencode.Client) {
+	s.mu.Lock()
+	s.client = client
+	s.mu.Unlock()
+	s.Reconnect()
+}
+
+func (s *EventStream) run(ctx context.Context) {
+	attempt := 0
+	for {
+		s.mu.Lock()
+		client := s.client
+		s.mu.Unlock()
+		stream := client.Event.ListStreaming(ctx, opencode.EventListParams{})
+		connected := false
+		for stream.Next() {
+			if !connected {
+				connected, attempt = true, 0
+				s.resume()
+			}
+			s.send(stream.Current().AsUnion())
+		}
+		err := stream.Err()
+		stream.Close()
+		// A subscription dropped on purpose isn't retried
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.mu.Lock()
+		if s.dropped.IsZero() {
+			s.dropped = time.Now()
+			slog.Warn("Event stream dropped", "error", err)
+		}
+		s.mu.Unlock()
+		delay := reconnectDelay(attempt)
+		attempt++
+		s.send(StreamDroppedMsg{Err: err, Attempt: attempt, Delay: delay})
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// resume tells the TUI the stream is back, when it had dropped
+func (s *EventStream) resume() {
+	s.mu.Lock()
+	dropped := s.dropped
+	s.dropped = time.Time{}
+	s.mu.Unlock()
+	if dropped.IsZero() {
+		return
+	}
+	down := time.Since(dropped)
+	slog.Info("Event stream resumed", "down", down)
+	s.send(StreamResumedMsg{Down: down})
+}
+
+// reconnectDelay returns the wait before an attempt, from 0, to subscribe
+// again to a dropped stream
+func reconnectDelay(attempt int) time.Duration {
+	return min(reconnectMaxDelay, reconnectMinDelay<<min(attempt, 6))
+}
+
+// StreamWatchMsg checks for a stalled stream while a response is expected
+type StreamWatchMsg struct{}
+
+// StreamReconnectedMsg is sent with the messages of a session reloaded
+// after its stream was reconnected
+type StreamReconnectedMsg struct {
+	SessionID string
+	Session   *opencode.Session // The session as the server has it now, nil when it couldn't be read
+	Messages  []Message
+	Answered  []string // Permissions answered or dropped while the stream was down
+	Err       error
+}
+
+// streamWatchdog notices when the server stops sending events in the middle
+// of a response
+type streamWatchdog struct {
+	lastEvent time.Time
+	awaiting  bool // A prompt was sent and no reply has started yet
+	watching  bool // A StreamWatchMsg is on its way
+	stalled   bool
+	dropped   *StreamDroppedMsg // The last attempt to subscribe again, while the stream is down
+}
+
+// SawEvent notes an event from the server, which shows the stream is alive,
+// and the lifecycle of replies and compactions it reports
+func (a *App) SawEvent(event opencode.EventListResponseUnion) {
+	a.stream.lastEvent = time.Now()
+	a.stream.stalled = false
+	switch event := event.(type) {
+	case opencode.EventListResponseEventMessageUpdated:
+		if _, ok := event.Properties.Info.AsUnion().(opencode.AssistantMessage); ok {
+			a.stream.awaiting = false
+		}
+	case opencode.EventListResponseEventSessionUpdated:
+		a.ObserveSession(event.Properties.Info)
+	case opencode.EventListResponseEventSessionCompacted:
+		a.CompactionEnded(event.Properties.SessionID)
+	case opencode.EventListResponseEventSessionIdle:
+		a.stream.awaiting = false
+		a.CompactionEnded(event.Properties.SessionID)
+	case opencode.EventListResponseEventSessionError:
+		a.stream.awaiting = false
+		a.CompactionEnded(event.Properties.SessionID)
+	}
+}
+
+// ExpectReply starts watching the stream for the reply to a prompt just
+// sent
+func (a *App) ExpectReply() tea.Cmd {
+	a.stream.awaiting = true
+	a.stream.lastEvent = time.Now()
+	return a.WatchStream()
+}
+
+// WatchStream watches for the stream stalling while a response is expected
+func (a *App) WatchStream() tea.Cmd {
+	if a.stream.watching || !a.responding() {
+		return nil
+	}
+	a.stream.watching = true
+	return watchStreamAfter(streamWatchTick)
+}
+
+func watchStreamAfter(delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(time.Time) tea.Msg { return StreamWatchMsg{} })
+}
+
+// CheckStream marks the stream stalled when a response went without events
+// for longer than the stall timeout, and keeps watching while one is
+// expected
+func (a *App) CheckStream() tea.Cmd {
+	a.stream.watching = false
+	if !a.responding() {
+		a.stream.stalled = false
+		return nil
+	}
+	if a.CurrentPermission.ID != "" {
+		// Waiting on the user, not the server
+		a.stream.lastEvent = time.Now()
+	}
+	timeout := a.Timeout(TimeoutStream)
+	if a.runningTool() {
+		timeout = a.Timeout(TimeoutTool)
+	}
+	quiet := time.Since(a.stream.lastEvent)
+	// A dropped stream is retried on its own, and shown on the status bar
+	if quiet >= timeout && !a.stream.stalled && a.stream.dropped == nil {
+		slog.Warn("Event stream stalled", "quiet", quiet, "session", a.Session.ID)
+		a.stream.stalled = true
+	}
+	a.stream.watching = true
+	return watchStreamAfter(streamWatchTick)
+}
+
+// StreamStalled returns how long the stream has gone without events, and
+// whether it is taken for stalled
+func (a *App) StreamStalled() (time.Duration, bool) {
+	if !a.stream.stalled {
+		return 0, false
+	}
+	return time.Since(a.stream.lastEvent), true
+}
+
+// responding reports whether a response is expected from the server
+func (a *App) responding() bool {
+	return a.Session.ID != "" && (a.stream.awaiting || a.IsBusy())
+}
+
+// runningTool reports whether a tool of the last message is running
+func (a *App) runningTool() bool {
+	if len(a.Messages) == 0 {
+		return false
+	}
+	for _, part := range a.Messages[len(a.Messages)-1].Parts {
+		if tool, ok := part.(opencode.ToolPart); ok && tool.State.Status == opencode.ToolPartStateStatusRunning {
+			return true
+		}
+	}
+	return false
+}
+
+// ReconnectStream subscribes to the server's events again and reloads the
+// session's messages, which catches up on the events missed
+func (a *App) ReconnectStream() tea.Cmd {
+	if a.Events != nil {
+		a.Events.Reconnect()
+	}
+	a.stream.stalled = false
+	a.stream.awaiting = false
+	a.stream.lastEvent = time.Now()
+	return a.catchUp()
+}
+
+// StreamDropped notes the stream is down and being subscribed to again,
+// warning of it on the first attempt
+func (a *App) StreamDropped(msg StreamDroppedMsg) tea.Cmd {
+	first := a.stream.dropped == nil
+	a.stream.dropped = &msg
+	a.stream.stalled = false
+	if !first {
+		return nil
+	}
+	return toast.NewWarningToast("Lost the connection to the server, reconnecting…")
+}
+
+// StreamResumed notes the stream is back up, and catches up on what the
+// session missed while it was down
+func (a *App) StreamResumed(msg StreamResumedMsg) tea.Cmd {
+	a.stream.dropped = nil
+	a.stream.stalled = false
+	a.stream.lastEvent = time.Now()
+	return a.catchUp()
+}
+
+// StreamDown returns the last attempt to subscribe again to a dropped
+// stream, and whether the stream is down
+func (a *App) StreamDown() (StreamDroppedMsg, bool) {
+	if a.stream.dropped == nil {
+		return StreamDroppedMsg{}, false
+	}
+	return *a.stream.dropped, true
+}
+
+// catchUp reloads what a dropped or stalled stream missed events of: the
+// session and its messages, the permissions still asked, and the recent
+// sessions when the home screen lists them. The permissions asked for inbox commands
+// and project tool calls are the TUI's own and kept.
+func (a *App) catchUp() tea.Cmd {
+	sessionID := a.Session.ID
+	asked := slices.DeleteFunc(slices.Clone(a.Permissions), func(p opencode.Permission) bool {
+		return strings.HasPrefix(p.ID, shellPermissionPrefix) || strings.HasPrefix(p.ID, toolPermissionPrefix)
+	})
+	reload := func() tea.Msg {
+		ctx, cancel := a.TimeoutContext(TimeoutAPI)
+		defer cancel()
+		msg := StreamReconnectedMsg{SessionID: sessionID, Answered: a.answeredPermissions(ctx, asked)}
+		if sessionID == "" {
+			return msg
+		}
+		if session, err := a.Client.Session.Get(ctx, sessionID, opencode.SessionGetParams{}); err == nil {
+			msg.Session = session
+		}
+		msg.Messages, msg.Err = a.ListMessages(ctx, sessionID)
+		return msg
+	}
+	if sessionID == "" {
+		return tea.Batch(reload, a.LoadHomeDashboard())
+	}
+	return reload
+}
+
+// answeredPermissions returns the IDs of the permissions whose tool call no
+// longer waits on them, as their answer or their call's end came while the
+// stream was down
+func (a *App) answeredPermissions(ctx context.Context, permissions []opencode.Permission) []string {
+	var answered []string
+	for _, permission := range permissions {
+		response, err := a.Client.Session.Message(ctx, permission.SessionID, permission.MessageID, opencode.SessionMessageParams{})
+		if err != nil || response == nil {
+			// Kept, as whether it still waits is unknown
+			continue
+		}
+		waiting := false
+		for _, part := range response.Parts {
+			if tool, ok := part.AsUnion().(opencode.ToolPart); ok && tool.CallID == permission.CallID {
+				waiting = tool.State.Status == opencode.ToolPartStateStatusPending || tool.State.Status == opencode.ToolPartStateStatusRunning
+			}
+		}
+		if !waiting {
+			answered = append(answered, permission.ID)
+		}
+	}
+	return answered
+}
+
+// AbortStalled aborts the response of a stalled stream, then reconnects it
+// to show where the response stopped
+func (a *App) AbortStalled() tea.Cmd {
+	sessionID := a.Session.ID
+	abort := func() tea.Msg {
+		if err := a.Cancel(context.Background(), sessionID); err != nil {
+			return toast.NewErrorToast("Failed to abort the response")()
+		}
+		return nil
+	}
+	return tea.Sequence(abort, a.ReconnectStream())
+}
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+func TestCheckStreamStalls(t *testing.T) {
+	a := &App{
+		Session:  &opencode.Session{ID: "ses_1"},
+		Messages: []Message{{Info: opencode.AssistantMessage{ID: "msg_1"}}},
+	}
+
+	// Responding, with a recent event
+	a.stream.lastEvent = time.Now()
+	if a.CheckStream() == nil {
+		t.Fatal("a response in progress wasn't watched")
+	}
+	if _, stalled := a.StreamStalled(); stalled {
+		t.Error("stalled right after an event")
+	}
+
+	// A running tool is given longer
+	a.stream.lastEvent = time.Now().Add(-StreamStallTimeout)
+	a.Messages[0].Parts = []opencode.PartUnion{opencode.ToolPart{State: opencode.ToolPartState{Status: opencode.ToolPartStateStatusRunning}}}
+	a.CheckStream()
+	if _, stalled := a.StreamStalled(); stalled {
+		t.Error("stalled while a tool runs")
+	}
+
+	a.Messages[0].Parts = nil
+	a.CheckStream()
+	if quiet, stalled := a.StreamStalled(); !stalled || quiet < StreamStallTimeout {
+		t.Errorf("StreamStalled = %s, %v after %s without events", quiet, stalled, StreamStallTimeout)
+	}
+
+	// An event ends the stall
+	a.SawEvent(opencode.EventListResponseEventServerConnected{})
+	if _, stalled := a.StreamStalled(); stalled {
+		t.Error("still stalled after an event")
+	}
+
+	// Nothing is watched once the response completed
+	a.Messages[0].Info = opencode.AssistantMessage{ID: "msg_1", Time: opencode.AssistantMessageTime{Completed: 1}}
+	if a.CheckStream() != nil {
+		t.Error("watched with no response expected")
+	}
+	if a.ExpectReply() == nil {
+		t.Error("a prompt sent wasn't watched for its reply")
+	}
+}
+
+func TestReconnectDelay(t *testing.T) {
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second}
+	for attempt, delay := range want {
+		if got := reconnectDelay(attempt); got != delay {
+			t.Errorf("reconnectDelay(%d) = %s, want %s", attempt, got, delay)
+		}
+	}
+	if got := reconnectDelay(100); got != reconnectMaxDelay {
+		t.Errorf("reconnectDelay(100) = %s", got)
+	}
+}
+
+func TestStreamDroppedIsNotStalled(t *testing.T) {
+	a := &App{
+		Session:  &opencode.Session{ID: "ses_1"},
+		Messages: []Message{{Info: opencode.AssistantMessage{ID: "msg_1"}}},
+	}
+	a.stream.lastEvent = time.Now().Add(-StreamStallTimeout)
+	if a.StreamDropped(StreamDroppedMsg{Attempt: 1, Delay: time.Second}) == nil {
+		t.Error("the first drop wasn't warned of")
+	}
+	if a.StreamDropped(StreamDroppedMsg{Attempt: 2, Delay: 2 * time.Second}) != nil {
+		t.Error("a later attempt was warned of")
+	}
+	a.CheckStream()
+	if _, stalled := a.StreamStalled(); stalled {
+		t.Error("a dropped stream was taken for stalled")
+	}
+	if dropped, down := a.StreamDown(); !down || dropped.Attempt != 2 {
+		t.Errorf("StreamDown = %+v, %v", dropped, down)
+	}
+
+	if a.StreamResumed(StreamResumedMsg{Down: time.Minute}) == nil {
+		t.Error("the session wasn't caught up")
+	}
+	if _, down := a.StreamDown(); down {
+		t.Error("still down after resuming")
+	}
+}
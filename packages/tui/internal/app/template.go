@@ -0,0 +1,132 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/attachment"
+	"github.com/aaronmrosenthal/rycode/internal/bench"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/sessiontemplate"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+	"github.com/google/uuid"
+)
+
+// TemplateSelectedMsg is sent when a template is chosen for the next session
+type TemplateSelectedMsg struct {
+	Template sessiontemplate.Template
+}
+
+// Templates returns the built-in, user and project session templates
+func (a *App) Templates() ([]sessiontemplate.Template, error) {
+	return sessiontemplate.Load(a.ConfigDir, util.RootPath)
+}
+
+// StartTemplate switches to the agent and model of a template, once the
+// current session is cleared. The session itself is created by the next
+// prompt, which gets the template's context files attached.
+func (a *App) StartTemplate(t sessiontemplate.Template) (*App, tea.Cmd) {
+	var cmds []tea.Cmd
+	a.Template = &t
+
+	if t.Agent != "" && t.Agent != a.Agent().Name {
+		found := false
+		for _, agent := range a.Agents {
+			if agent.Name == t.Agent && agent.Mode != "subagent" {
+				found = true
+				break
+			}
+		}
+		if found {
+			cmds = append(cmds, util.CmdHandler(AgentSelectedMsg{AgentName: t.Agent}))
+		} else {
+			slog.Warn("Template agent is not available", "template", t.Name, "agent", t.Agent)
+		}
+	}
+	if t.Model != "" {
+		ref, err := bench.ParseModelRef(t.Model)
+		if err != nil {
+			return a, toast.NewErrorToast(err.Error(), toast.WithTitle(t.Name))
+		}
+		provider, model := findModelByProviderAndModelID(a.Providers, ref.ProviderID, ref.ModelID)
+		if provider == nil || model == nil {
+			return a, toast.NewErrorToast(fmt.Sprintf("Model %s is not available", t.Model), toast.WithTitle(t.Name))
+		}
+		// Sent after the agent switch, which picks the agent's own model
+		cmds = append(cmds, util.CmdHandler(ModelSelectedMsg{Provider: *provider, Model: *model}))
+	}
+
+	message := "Your next prompt starts the session"
+	if t.Description != "" {
+		message = t.Description + ". " + message
+	}
+	cmds = append(cmds, toast.NewInfoToast(message, toast.WithTitle("Template: "+t.Name)))
+	return a, tea.Sequence(cmds...)
+}
+
+// seedSession records the pending template's instructions for a session
+// just created for a prompt and attaches its context files to the prompt
+func (a *App) seedSession(sessionID string, prompt Prompt) Prompt {
+	t := a.Template
+	a.Template = nil
+	if t.System != "" {
+		if a.State.SessionSystem == nil {
+			a.State.SessionSystem = make(map[string]string)
+		}
+		a.State.SessionSystem[sessionID] = t.System
+		if err := a.saveStateNow(); err != nil {
+			slog.Error("Failed to save state", "error", err)
+		}
+	}
+
+	files, err := t.ContextFiles(util.RootPath)
+	if err != nil {
+		slog.Warn("Template context", "template", t.Name, "error", err)
+	}
+	attachments := append([]*attachment.Attachment(nil), prompt.Attachments...)
+	for _, file := range files {
+		path := filepath.Join(util.RootPath, file)
+		attachments = append(attachments, &attachment.Attachment{
+			ID:        uuid.NewString(),
+			Type:      "file",
+			Display:   "@" + file,
+			URL:       "file://" + path,
+			Filename:  file,
+			MediaType: "text/plain",
+			Source: &attachment.FileSource{
+				Path: path,
+				Mime: "text/plain",
+			},
+		})
+	}
+	prompt.Attachments = attachments
+	return prompt
+}
+
+// ImportTemplate copies a shared template file into the project
+func (a *App) ImportTemplate(path string) (sessiontemplate.Template, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return sessiontemplate.Template{}, "", err
+	}
+	t, err := sessiontemplate.Parse(data)
+	if err != nil {
+		return sessiontemplate.Template{}, "", err
+	}
+	saved, err := sessiontemplate.Save(util.RootPath, t)
+	return t, saved, err
+}
+
+// ExportTemplate writes a template to the project as a JSON file, so it can
+// be committed or sent to someone
+func (a *App) ExportTemplate(name string) (string, error) {
+	templates, _ := a.Templates()
+	t, ok := sessiontemplate.Find(templates, name)
+	if !ok {
+		return "", fmt.Errorf("no template named %q", name)
+	}
+	return sessiontemplate.Save(util.RootPath, t)
+}
@@ -0,0 +1,175 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// TimeoutClass groups the operations that wait on the auth bridge, the
+// server or a provider for about as long
+type TimeoutClass string
+
+const (
+	TimeoutStatus   TimeoutClass = "status"   // Quick bridge checks redrawn often, as today's cost
+	TimeoutProvider TimeoutClass = "provider" // Bridge calls that ask the providers, as auto-detecting keys
+	TimeoutAPI      TimeoutClass = "api"      // Server calls reading or changing sessions
+	TimeoutGenerate TimeoutClass = "generate" // Server calls waiting on a model, as a session title
+	TimeoutStream   TimeoutClass = "stream"   // How long a response may go without events before it's taken for stalled
+	TimeoutTool     TimeoutClass = "tool"     // The same while a tool runs
+)
+
+// timeoutLimit is the default timeout of a class and the range it may be
+// set to
+type timeoutLimit struct {
+	class            TimeoutClass
+	fallback, lo, hi time.Duration
+}
+
+// timeoutLimits are the limits of each class, in the order classes are
+// listed
+var timeoutLimits = []timeoutLimit{
+	{TimeoutStatus, 2 * time.Second, 500 * time.Millisecond, time.Minute},
+	{TimeoutProvider, 10 * time.Second, time.Second, 5 * time.Minute},
+	{TimeoutAPI, 10 * time.Second, time.Second, 5 * time.Minute},
+	{TimeoutGenerate, time.Minute, 5 * time.Second, 30 * time.Minute},
+	{TimeoutStream, StreamStallTimeout, 5 * time.Second, time.Hour},
+	{TimeoutTool, toolStallTimeout, 10 * time.Second, 2 * time.Hour},
+}
+
+// TimeoutClasses lists the classes timeouts are set for
+func TimeoutClasses() []TimeoutClass {
+	classes := make([]TimeoutClass, len(timeoutLimits))
+	for i, limit := range timeoutLimits {
+		classes[i] = limit.class
+	}
+	return classes
+}
+
+// Timeouts override the default timeout of each class, for every provider
+// and for single ones, as in the state file:
+//
+//	[timeouts.default]
+//	stream = "45s"
+//	[timeouts.providers.ollama]
+//	generate = "5m"
+type Timeouts struct {
+	Default   map[TimeoutClass]time.Duration            `toml:"default,omitempty"`
+	Providers map[string]map[TimeoutClass]time.Duration `toml:"providers,omitempty"` // By provider ID
+}
+
+// ValidateTimeout checks that a class exists and that a timeout is in its
+// range
+func ValidateTimeout(class TimeoutClass, timeout time.Duration) error {
+	i := slices.IndexFunc(timeoutLimits, func(limit timeoutLimit) bool { return limit.class == class })
+	if i < 0 {
+		names := make([]string, len(timeoutLimits))
+		for i, limit := range timeoutLimits {
+			names[i] = string(limit.class)
+		}
+		return fmt.Errorf("unknown timeout %q, expected one of %s", class, strings.Join(names, ", "))
+	}
+	if limit := timeoutLimits[i]; timeout < limit.lo || timeout > limit.hi {
+		return fmt.Errorf("the %s timeout must be from %s to %s", class, limit.lo, limit.hi)
+	}
+	return nil
+}
+
+// validate drops the timeouts a hand-edited state file got wrong, so that
+// their defaults apply
+func (t *Timeouts) validate() {
+	check := func(scope string, timeouts map[TimeoutClass]time.Duration) {
+		for class, timeout := range timeouts {
+			if err := ValidateTimeout(class, timeout); err != nil {
+				slog.Warn("Ignoring timeout", "scope", scope, "error", err)
+				delete(timeouts, class)
+			}
+		}
+	}
+	check("default", t.Default)
+	for provider, timeouts := range t.Providers {
+		check(provider, timeouts)
+	}
+}
+
+// DefaultTimeout returns a class's timeout when none is set
+func DefaultTimeout(class TimeoutClass) time.Duration {
+	for _, limit := range timeoutLimits {
+		if limit.class == class {
+			return limit.fallback
+		}
+	}
+	return 0
+}
+
+// ProviderTimeout returns a class's timeout for a provider, or for every
+// provider when providerID is ""
+func (a *App) ProviderTimeout(providerID string, class TimeoutClass) time.Duration {
+	if a.State != nil && a.State.Timeouts != nil {
+		if timeout, ok := a.State.Timeouts.Providers[providerID][class]; ok && providerID != "" {
+			return timeout
+		}
+		if timeout, ok := a.State.Timeouts.Default[class]; ok {
+			return timeout
+		}
+	}
+	return DefaultTimeout(class)
+}
+
+// Timeout returns a class's timeout for the current provider
+func (a *App) Timeout(class TimeoutClass) time.Duration {
+	providerID := ""
+	if a.Provider != nil {
+		providerID = a.Provider.ID
+	}
+	return a.ProviderTimeout(providerID, class)
+}
+
+// TimeoutContext returns a context ended after a class's timeout for the
+// current provider
+func (a *App) TimeoutContext(class TimeoutClass) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), a.Timeout(class))
+}
+
+// SetTimeout sets a class's timeout for a provider, or for every provider
+// when providerID is "". A timeout of 0 restores the default.
+func (a *App) SetTimeout(providerID string, class TimeoutClass, timeout time.Duration) (tea.Cmd, error) {
+	if DefaultTimeout(class) == 0 || timeout != 0 {
+		if err := ValidateTimeout(class, timeout); err != nil {
+			return nil, err
+		}
+	}
+	if a.State.Timeouts == nil {
+		a.State.Timeouts = &Timeouts{}
+	}
+	timeouts := a.State.Timeouts.Default
+	if providerID != "" {
+		timeouts = a.State.Timeouts.Providers[providerID]
+	}
+	if timeouts == nil {
+		timeouts = make(map[TimeoutClass]time.Duration)
+	}
+	if timeout == 0 {
+		delete(timeouts, class)
+	} else {
+		timeouts[class] = timeout
+	}
+
+	switch {
+	case providerID == "":
+		a.State.Timeouts.Default = timeouts
+	case len(timeouts) == 0:
+		delete(a.State.Timeouts.Providers, providerID)
+	default:
+		if a.State.Timeouts.Providers == nil {
+			a.State.Timeouts.Providers = make(map[string]map[TimeoutClass]time.Duration)
+		}
+		a.State.Timeouts.Providers[providerID] = timeouts
+	}
+	return a.SaveState(), nil
+}
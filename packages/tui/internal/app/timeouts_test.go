@@ -0,0 +1,55 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+func TestTimeouts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.toml")
+	config := `
+[timeouts.default]
+stream = "45s"
+status = "1ms"
+[timeouts.providers.ollama]
+generate = "5m"
+`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &App{State: state, Provider: &opencode.Provider{ID: "ollama"}}
+
+	if got := a.Timeout(TimeoutStream); got != 45*time.Second {
+		t.Errorf("stream timeout = %s, want 45s", got)
+	}
+	if got := a.Timeout(TimeoutStatus); got != DefaultTimeout(TimeoutStatus) {
+		t.Errorf("status timeout out of range = %s, want the default", got)
+	}
+	if got := a.Timeout(TimeoutGenerate); got != 5*time.Minute {
+		t.Errorf("generate timeout of ollama = %s, want 5m", got)
+	}
+	if got := a.ProviderTimeout("anthropic", TimeoutGenerate); got != time.Minute {
+		t.Errorf("generate timeout of another provider = %s, want 1m", got)
+	}
+
+	if _, err := a.SetTimeout("", TimeoutTool, time.Second); err == nil {
+		t.Error("a tool timeout under its range was set")
+	}
+	if _, err := a.SetTimeout("", "upload", time.Minute); err == nil {
+		t.Error("an unknown class was set")
+	}
+	if _, err := a.SetTimeout("ollama", TimeoutGenerate, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := a.State.Timeouts.Providers["ollama"]; ok || a.Timeout(TimeoutGenerate) != time.Minute {
+		t.Errorf("restoring the last timeout of ollama left %v", a.State.Timeouts.Providers)
+	}
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+	"github.com/aaronmrosenthal/rycode/internal/todos"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// TodosExtractedMsg is sent when the action items of a session have been
+// extracted
+type TodosExtractedMsg struct {
+	Session string // Title of the session
+	Items   []todos.Item
+	Err     error
+}
+
+// TodoIssuesFiledMsg is sent when GitHub issues have been filed for action
+// items. URLs holds the URL of each issue filed, by item title.
+type TodoIssuesFiledMsg struct {
+	URLs map[string]string
+	Err  error
+}
+
+// ExtractTodos asks the model for the action items left open in the current
+// session, in a throwaway session where it can't change anything
+func (a *App) ExtractTodos() tea.Cmd {
+	sessionID := a.Session.ID
+	model := a.currentAskModel()
+
+	return func() tea.Msg {
+		ctx := context.Background()
+		t, err := a.sessionTranscript(ctx, sessionID)
+		if err != nil {
+			return TodosExtractedMsg{Err: err}
+		}
+
+		answer, err := a.askThrowaway(ctx, model, "action items: "+t.Title(), todos.SystemPrompt, todos.BuildPrompt(t))
+		if err != nil {
+			return TodosExtractedMsg{Err: err}
+		}
+		items, err := todos.ParseItems(answer)
+		return TodosExtractedMsg{Session: t.Title(), Items: items, Err: err}
+	}
+}
+
+// ExportTodos appends action items to the project's TODO.md under the
+// session's title and returns the path written. Items already listed are
+// skipped.
+func (a *App) ExportTodos(session string, items []todos.Item) (string, error) {
+	path := filepath.Join(util.RootPath, todos.FileName)
+	content, err := remote.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("failed to read %s: %w", todos.FileName, err)
+	}
+	updated, ok := todos.AppendMarkdown(string(content), session, items)
+	if !ok {
+		return "", fmt.Errorf("every item is already in %s", todos.FileName)
+	}
+	if err := remote.WriteFile(path, []byte(updated), 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", todos.FileName, err)
+	}
+	return path, nil
+}
+
+// FileTodoIssues files a GitHub issue for each action item with the gh CLI,
+// stopping at the first that fails
+func (a *App) FileTodoIssues(session string, items []todos.Item) tea.Cmd {
+	return func() tea.Msg {
+		urls := make(map[string]string)
+		for _, item := range items {
+			cmd := remote.Command(context.Background(), util.RootPath, "gh", "issue", "create",
+				"--title", item.Title,
+				"--body", todos.IssueBody(item, session),
+			)
+			out, err := cmd.CombinedOutput()
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) || strings.Contains(err.Error(), "executable file not found") {
+					err = errors.New("filing issues needs the GitHub CLI (gh)")
+				} else if output := strings.TrimSpace(string(out)); output != "" {
+					err = errors.New(output)
+				}
+				return TodoIssuesFiledMsg{URLs: urls, Err: err}
+			}
+			// gh prints the URL of the new issue last
+			lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+			urls[item.Title] = strings.TrimSpace(lines[len(lines)-1])
+		}
+		return TodoIssuesFiledMsg{URLs: urls}
+	}
+}
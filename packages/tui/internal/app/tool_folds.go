@@ -0,0 +1,45 @@
+package app
+
+import (
+	"maps"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// maxExpandedMessages bounds the messages whose expanded tool calls are
+// remembered. The oldest are forgotten first.
+const maxExpandedMessages = 500
+
+// ToolExpanded reports whether a folded tool call of a message was expanded
+// to show its output
+func (a *App) ToolExpanded(messageID, partID string) bool {
+	return slices.Contains(a.State.ExpandedTools[messageID], partID)
+}
+
+// ToggleTool expands a folded tool call of a message, or folds it again,
+// and saves the choice
+func (a *App) ToggleTool(messageID, partID string) tea.Cmd {
+	expanded := a.State.ExpandedTools[messageID]
+	if i := slices.Index(expanded, partID); i >= 0 {
+		expanded = slices.Delete(expanded, i, i+1)
+	} else {
+		expanded = append(expanded, partID)
+	}
+	if len(expanded) == 0 {
+		delete(a.State.ExpandedTools, messageID)
+	} else {
+		if a.State.ExpandedTools == nil {
+			a.State.ExpandedTools = make(map[string][]string)
+		}
+		a.State.ExpandedTools[messageID] = expanded
+	}
+	// Message IDs sort in the order the messages were sent
+	if len(a.State.ExpandedTools) > maxExpandedMessages {
+		ids := slices.Sorted(maps.Keys(a.State.ExpandedTools))
+		for _, id := range ids[:len(ids)-maxExpandedMessages] {
+			delete(a.State.ExpandedTools, id)
+		}
+	}
+	return a.SaveState()
+}
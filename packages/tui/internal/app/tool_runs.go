@@ -0,0 +1,103 @@
+package app
+
+import (
+	"time"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+// ToolRun is a tool call of the session as the tool log shows it
+type ToolRun struct {
+	PartID  string
+	Tool    string
+	Label   string // The command run, or the file, pattern or URL the tool works on
+	Running bool
+	Failed  bool
+	Exit    *int // Exit code of a finished command
+	Started time.Time
+	Ended   time.Time
+	Output  string // Streamed as the call runs by the tools that report it, as bash
+}
+
+// Elapsed returns how long the call has run, or ran
+func (r ToolRun) Elapsed(now time.Time) time.Duration {
+	if r.Started.IsZero() {
+		return 0
+	}
+	if !r.Running {
+		now = r.Ended
+	}
+	return max(0, now.Sub(r.Started))
+}
+
+// ToolRuns returns the session's running tool calls, oldest first, or the
+// last call to finish when none are running
+func (a *App) ToolRuns() []ToolRun {
+	var running []ToolRun
+	var last *ToolRun
+	for _, message := range a.Messages {
+		for _, part := range message.Parts {
+			tool, ok := part.(opencode.ToolPart)
+			if !ok || tool.State.Status == opencode.ToolPartStateStatusPending {
+				continue
+			}
+			run := toolRun(tool)
+			if run.Running {
+				running = append(running, run)
+			} else {
+				last = &run
+			}
+		}
+	}
+	if len(running) == 0 && last != nil {
+		return []ToolRun{*last}
+	}
+	return running
+}
+
+func toolRun(tool opencode.ToolPart) ToolRun {
+	input, _ := tool.State.Input.(map[string]any)
+	metadata, _ := tool.State.Metadata.(map[string]any)
+	run := ToolRun{
+		PartID:  tool.ID,
+		Tool:    tool.Tool,
+		Running: tool.State.Status == opencode.ToolPartStateStatusRunning,
+		Failed:  tool.State.Status == opencode.ToolPartStateStatusError,
+		Output:  tool.State.Output,
+	}
+	for _, key := range []string{"command", "filePath", "pattern", "url", "path", "description"} {
+		if value, ok := input[key].(string); ok && value != "" {
+			run.Label = value
+			break
+		}
+	}
+	if output, ok := metadata["output"].(string); ok {
+		run.Output = output
+	}
+	if run.Failed && run.Output == "" {
+		run.Output = tool.State.Error
+	}
+	if exit, ok := metadata["exit"].(float64); ok && !run.Running {
+		code := int(exit)
+		run.Exit = &code
+	}
+
+	var start, end float64
+	switch t := tool.State.Time.(type) {
+	case opencode.ToolStateRunningTime:
+		start = t.Start
+	case opencode.ToolStateCompletedTime:
+		start, end = t.Start, t.End
+	case opencode.ToolStateErrorTime:
+		start, end = t.Start, t.End
+	}
+	if start > 0 {
+		run.Started = time.UnixMilli(int64(start))
+	}
+	if end > 0 {
+		run.Ended = time.UnixMilli(int64(end))
+	} else {
+		run.Ended = run.Started
+	}
+	return run
+}
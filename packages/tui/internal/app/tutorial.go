@@ -0,0 +1,215 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/id"
+	"github.com/aaronmrosenthal/rycode/internal/tutorial"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// tutorialLatency is how long the playground takes to answer, so the
+// answer reads like one that was generated
+const tutorialLatency = 900 * time.Millisecond
+
+// TutorialStartMsg starts the tutorial playground
+type TutorialStartMsg struct{}
+
+// TutorialReplyMsg carries the playground's answer to a prompt
+type TutorialReplyMsg struct {
+	MessageID string // Placeholder the answer replaces
+	Reply     tutorial.Reply
+}
+
+// TutorialUpdatedMsg is sent when the playground's messages or steps change
+type TutorialUpdatedMsg struct{}
+
+// tutorialReturn is what the tutorial puts back when it ends
+type tutorialReturn struct {
+	provider *opencode.Provider
+	model    *opencode.Model
+	session  *opencode.Session
+	messages []Message
+}
+
+// StartTutorial sets the current session aside for the playground, where
+// prompts are answered by the tutorial's mock provider instead of the server
+func (a *App) StartTutorial() (*App, tea.Cmd) {
+	if a.Tutorial != nil {
+		return a, toast.NewInfoToast("The tutorial is already running")
+	}
+	a.tutorialReturn = &tutorialReturn{
+		provider: a.Provider,
+		model:    a.Model,
+		session:  a.Session,
+		messages: a.Messages,
+	}
+	a.Tutorial = tutorial.New()
+
+	provider := tutorial.Provider()
+	model := provider.Models[tutorial.FastModel]
+	a.Providers = append(a.Providers, provider)
+	a.Provider, a.Model = &provider, &model
+	a.Session = &opencode.Session{
+		ID:    tutorial.SessionID,
+		Title: "Tutorial",
+		Time:  opencode.SessionTime{Created: float64(time.Now().UnixMilli())},
+	}
+	a.Messages = nil
+	return a, tea.Sequence(
+		util.CmdHandler(TutorialUpdatedMsg{}),
+		toast.NewInfoToast(
+			"Nothing here reaches a real model or costs anything. Type /tutorial end to leave.",
+			toast.WithTitle("Tutorial playground"),
+		),
+	)
+}
+
+// EndTutorial leaves the playground and goes back to the session and model
+// from before it
+func (a *App) EndTutorial() (*App, tea.Cmd) {
+	if a.Tutorial == nil {
+		return a, toast.NewInfoToast("The tutorial isn't running")
+	}
+	previous := a.tutorialReturn
+	a.LeaveTutorial()
+	a.Session, a.Messages = previous.session, previous.messages
+	return a, tea.Sequence(
+		util.CmdHandler(TutorialUpdatedMsg{}),
+		toast.NewInfoToast("Back to your own session", toast.WithTitle("Tutorial ended")),
+	)
+}
+
+// LeaveTutorial drops the playground and its mock provider when another
+// session is opened, putting the model from before it back
+func (a *App) LeaveTutorial() {
+	if a.Tutorial == nil {
+		return
+	}
+	previous := a.tutorialReturn
+	a.Tutorial, a.tutorialReturn = nil, nil
+	a.Providers = slices.DeleteFunc(a.Providers, func(p opencode.Provider) bool {
+		return p.ID == tutorial.ProviderID
+	})
+	a.Provider, a.Model = previous.provider, previous.model
+}
+
+// TutorialAction records something done in the playground and announces the
+// step it completed
+func (a *App) TutorialAction(action tutorial.Action) tea.Cmd {
+	if a.Tutorial == nil || !a.Tutorial.Complete(action) {
+		return nil
+	}
+	done, total := a.Tutorial.Progress()
+	if a.Tutorial.Finished() {
+		return tea.Sequence(
+			util.CmdHandler(TutorialUpdatedMsg{}),
+			toast.NewSuccessToast(
+				"Keep playing, or type /tutorial end to go back to your session.",
+				toast.WithTitle("Tutorial complete"),
+			),
+		)
+	}
+	return tea.Sequence(
+		util.CmdHandler(TutorialUpdatedMsg{}),
+		toast.NewSuccessToast(tutorial.Steps[done-1].Title, toast.WithTitle(fmt.Sprintf("Step %d of %d done", done, total))),
+	)
+}
+
+// CycleTutorialModel switches between the playground's models
+func (a *App) CycleTutorialModel() tea.Cmd {
+	provider := tutorial.Provider()
+	next := tutorial.SmartModel
+	if a.Model != nil && a.Model.ID == tutorial.SmartModel {
+		next = tutorial.FastModel
+	}
+	return tea.Sequence(
+		util.CmdHandler(ModelSelectedMsg{Provider: provider, Model: provider.Models[next]}),
+		toast.NewInfoToast("Switched to "+provider.Models[next].Name),
+	)
+}
+
+// sendTutorialPrompt answers a prompt in the playground. Nothing is sent to
+// the server: the mock provider's answer arrives after a short delay.
+func (a *App) sendTutorialPrompt(prompt Prompt) (*App, tea.Cmd) {
+	a.Messages = append(a.Messages, prompt.ToMessage(id.Ascending(id.Message), tutorial.SessionID))
+
+	messageID := id.Ascending(id.Message)
+	a.Messages = append(a.Messages, Message{Info: a.tutorialAssistant(messageID, tutorial.Reply{}, float64(time.Now().UnixMilli()), 0)})
+	reply := a.Tutorial.Reply(prompt.Text, a.Model.ID)
+	return a, tea.Batch(
+		util.CmdHandler(TutorialUpdatedMsg{}),
+		tea.Tick(tutorialLatency, func(time.Time) tea.Msg {
+			return TutorialReplyMsg{MessageID: messageID, Reply: reply}
+		}),
+	)
+}
+
+// AnswerTutorial puts the playground's answer in place of its placeholder
+func (a *App) AnswerTutorial(msg TutorialReplyMsg) tea.Cmd {
+	if a.Tutorial == nil {
+		return nil
+	}
+	index := slices.IndexFunc(a.Messages, func(m Message) bool {
+		assistant, ok := m.Info.(opencode.AssistantMessage)
+		return ok && assistant.ID == msg.MessageID
+	})
+	if index < 0 {
+		return nil
+	}
+
+	created := a.Messages[index].Info.(opencode.AssistantMessage).Time.Created
+	now := float64(time.Now().UnixMilli())
+	parts := []opencode.PartUnion{opencode.TextPart{
+		ID:        id.Ascending(id.Part),
+		MessageID: msg.MessageID,
+		SessionID: tutorial.SessionID,
+		Type:      opencode.TextPartTypeText,
+		Text:      msg.Reply.Text,
+		Time:      opencode.TextPartTime{Start: now, End: now},
+	}}
+	if edit := msg.Reply.Edit; edit != nil {
+		input := map[string]any{"filePath": edit.FilePath}
+		parts = append(parts, opencode.ToolPart{
+			ID:        id.Ascending(id.Part),
+			CallID:    id.Ascending(id.Part),
+			MessageID: msg.MessageID,
+			SessionID: tutorial.SessionID,
+			Tool:      "edit",
+			Type:      opencode.ToolPartTypeTool,
+			State: opencode.ToolPartState{
+				Status:   opencode.ToolPartStateStatusCompleted,
+				Input:    input,
+				Metadata: map[string]any{"diff": edit.Diff},
+				Title:    edit.FilePath,
+				Time:     opencode.ToolStateCompletedTime{Start: now, End: now},
+			},
+		})
+	}
+	a.Messages[index] = Message{Info: a.tutorialAssistant(msg.MessageID, msg.Reply, created, now), Parts: parts}
+	return tea.Batch(util.CmdHandler(TutorialUpdatedMsg{}), a.TutorialAction(tutorial.ActionPrompt))
+}
+
+// tutorialAssistant is the playground's answer message, still being
+// written while completed is zero
+func (a *App) tutorialAssistant(messageID string, reply tutorial.Reply, created, completed float64) opencode.AssistantMessage {
+	return opencode.AssistantMessage{
+		ID:         messageID,
+		Mode:       a.Agent().Name,
+		ModelID:    a.Model.ID,
+		ProviderID: tutorial.ProviderID,
+		Path:       opencode.AssistantMessagePath{Cwd: util.CwdPath, Root: util.RootPath},
+		Role:       opencode.AssistantMessageRoleAssistant,
+		SessionID:  tutorial.SessionID,
+		Time:       opencode.AssistantMessageTime{Created: created, Completed: completed},
+		Tokens: opencode.AssistantMessageTokens{
+			Input:  float64(reply.InputTokens),
+			Output: float64(reply.OutputTokens),
+		},
+	}
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// LastToolFile returns the file the session's tools last read or changed,
+// relative to the project root when it's in it, at the line the read
+// started from or the first change of the diff
+func (a *App) LastToolFile() (string, int, bool) {
+	for i := len(a.Messages) - 1; i >= 0; i-- {
+		parts := a.Messages[i].Parts
+		for j := len(parts) - 1; j >= 0; j-- {
+			part, ok := parts[j].(opencode.ToolPart)
+			if !ok || part.State.Status != opencode.ToolPartStateStatusCompleted {
+				continue
+			}
+			input, _ := part.State.Input.(map[string]any)
+			path, _ := input["filePath"].(string)
+			if path == "" {
+				continue
+			}
+			if rel, err := filepath.Rel(util.RootPath, path); filepath.IsAbs(path) && err == nil && !strings.HasPrefix(rel, "..") {
+				path = rel
+			}
+			line := 1
+			if offset, ok := input["offset"].(float64); ok && offset > 0 {
+				line = int(offset) + 1
+			}
+			metadata, _ := part.State.Metadata.(map[string]any)
+			if diff, ok := metadata["diff"].(string); ok {
+				line = max(line, firstChangedLine(diff))
+			}
+			return path, line, true
+		}
+	}
+	return "", 0, false
+}
+
+// firstChangedLine returns the line of the new file its diff's first hunk
+// starts at, or 1
+func firstChangedLine(diff string) int {
+	for line := range strings.Lines(diff) {
+		if !strings.HasPrefix(line, "@@") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+			break
+		}
+		start, _, _ := strings.Cut(fields[2][1:], ",")
+		if n, err := strconv.Atoi(start); err == nil && n > 0 {
+			return n
+		}
+		break
+	}
+	return 1
+}
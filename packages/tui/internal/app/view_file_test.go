@@ -0,0 +1,16 @@
+package app
+
+import "testing"
+
+func TestFirstChangedLine(t *testing.T) {
+	cases := map[string]int{
+		"--- a/x.go\n+++ b/x.go\n@@ -10,4 +12,5 @@ func main() {\n-a\n+b\n": 12,
+		"@@ -1 +1 @@\n-a\n+b\n": 1,
+		"not a diff":             1,
+	}
+	for diff, want := range cases {
+		if got := firstChangedLine(diff); got != want {
+			t.Errorf("firstChangedLine(%q) = %d, want %d", diff, got, want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package app
+
+import (
+	"context"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/voice"
+)
+
+const (
+	// voiceTick is how often the recording indicator is redrawn
+	voiceTick = 200 * time.Millisecond
+	// lowVoiceConfidence is the confidence under which a transcript is
+	// flagged for checking
+	lowVoiceConfidence = 0.6
+)
+
+// VoiceTickMsg redraws the recording indicator while the microphone is
+// recorded
+type VoiceTickMsg struct{}
+
+// VoiceTranscribedMsg is sent with the transcript of a recording
+type VoiceTranscribedMsg struct {
+	Transcript voice.Transcript
+	Err        error
+}
+
+// voiceInput is push-to-talk: the microphone being recorded, or a recording
+// being transcribed
+type voiceInput struct {
+	recording    *voice.Recording
+	transcribing bool
+	confidence   float64 // Of the transcript last put in the editor, 0 once sent
+}
+
+// VoiceRecording returns how long the microphone has been recorded, and
+// whether it is
+func (a *App) VoiceRecording() (time.Duration, bool) {
+	if a.voice.recording == nil {
+		return 0, false
+	}
+	return a.voice.recording.Elapsed(), true
+}
+
+// VoiceTranscribing reports whether a recording is being transcribed
+func (a *App) VoiceTranscribing() bool {
+	return a.voice.transcribing
+}
+
+// VoiceConfidence returns the confidence of the transcript last put in the
+// editor, or 0 when it was sent or there is none
+func (a *App) VoiceConfidence() float64 {
+	return a.voice.confidence
+}
+
+// ClearVoiceConfidence forgets the confidence of the last transcript, as
+// the prompt holding it is sent or cleared
+func (a *App) ClearVoiceConfidence() {
+	a.voice.confidence = 0
+}
+
+// ToggleVoice starts recording the microphone, or stops and transcribes the
+// recording when it is running
+func (a *App) ToggleVoice() tea.Cmd {
+	if a.voice.transcribing {
+		return toast.NewInfoToast("Still transcribing the last recording")
+	}
+	if a.voice.recording != nil {
+		return a.stopVoice()
+	}
+	// Fail before recording rather than after
+	if err := voice.Available(); err != nil {
+		return toast.NewErrorToast(err.Error(), toast.WithTitle("Voice input"))
+	}
+	recording, err := voice.Start("")
+	if err != nil {
+		return toast.NewErrorToast(err.Error(), toast.WithTitle("Voice input"))
+	}
+	a.voice.recording = recording
+	a.voice.confidence = 0
+	return tickVoice()
+}
+
+// TickVoice redraws the recording indicator, and stops a recording that ran
+// for voice.MaxRecording
+func (a *App) TickVoice() tea.Cmd {
+	elapsed, recording := a.VoiceRecording()
+	switch {
+	case !recording:
+		return nil
+	case elapsed >= voice.MaxRecording:
+		return a.stopVoice()
+	}
+	return tickVoice()
+}
+
+func tickVoice() tea.Cmd {
+	return tea.Tick(voiceTick, func(time.Time) tea.Msg { return VoiceTickMsg{} })
+}
+
+// stopVoice stops recording and transcribes the recording in the background
+func (a *App) stopVoice() tea.Cmd {
+	recording := a.voice.recording
+	a.voice.recording = nil
+	a.voice.transcribing = true
+	return func() tea.Msg {
+		path, err := recording.Stop()
+		if err != nil {
+			return VoiceTranscribedMsg{Err: err}
+		}
+		defer os.Remove(path)
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		transcript, err := voice.Transcribe(ctx, path)
+		return VoiceTranscribedMsg{Transcript: transcript, Err: err}
+	}
+}
+
+// VoiceTranscribed records the outcome of a transcription and returns the
+// text to put in the editor, if any
+func (a *App) VoiceTranscribed(msg VoiceTranscribedMsg) (string, tea.Cmd) {
+	a.voice.transcribing = false
+	if msg.Err != nil {
+		return "", toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Voice input"))
+	}
+	a.voice.confidence = msg.Transcript.Confidence
+	if msg.Transcript.Confidence > 0 && msg.Transcript.Confidence < lowVoiceConfidence {
+		return msg.Transcript.Text, toast.NewWarningToast("Whisper wasn't sure of this transcript; check it before sending")
+	}
+	return msg.Transcript.Text, nil
+}
+
+// StopVoice stops a recording without transcribing it, as the TUI exits
+func (a *App) StopVoice() {
+	if a.voice.recording == nil {
+		return
+	}
+	if path, err := a.voice.recording.Stop(); err == nil {
+		os.Remove(path)
+	}
+	a.voice.recording = nil
+}
@@ -0,0 +1,240 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+	"github.com/aaronmrosenthal/rycode/internal/watch"
+)
+
+// WatchDueMsg is sent when a watch should run its command, because its
+// interval elapsed or, when Changed is set, one of its paths changed
+type WatchDueMsg struct {
+	ID      string
+	Changed bool
+}
+
+// WatchRunMsg is sent when a watch's command has finished
+type WatchRunMsg struct {
+	ID     string
+	Result watch.Result
+}
+
+// WatchPendingMsg retries sending a fired prompt that had to wait for the
+// session to become idle
+type WatchPendingMsg struct {
+	ID string
+}
+
+// watchBusyRetry is how often a fired prompt checks whether the session is
+// idle again
+const watchBusyRetry = 15 * time.Second
+
+// Watch is a trigger running while the TUI is open
+type Watch struct {
+	Trigger   watch.Trigger
+	Last      *watch.Result // Last completed run, the baseline for the next
+	LastErr   error
+	LastFired time.Time
+	Fired     int
+	Running   bool
+	pending   string // Prompt that fired while the session was busy
+	watcher   *watch.Watcher
+}
+
+// Watches returns the running watches, oldest first
+func (a *App) Watches() []*Watch {
+	return a.watches
+}
+
+func (a *App) findWatch(id string) *Watch {
+	for _, w := range a.watches {
+		if w.Trigger.ID == id {
+			return w
+		}
+	}
+	return nil
+}
+
+// AddWatch starts a watch from the arguments of /watch add. Its command runs
+// right away to record the output later runs are compared with.
+func (a *App) AddWatch(args string) (*Watch, tea.Cmd, error) {
+	trigger, err := watch.Parse(args, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	w := &Watch{Trigger: trigger}
+	if len(trigger.Paths) > 0 {
+		if w.watcher, err = watch.NewWatcher(util.RootPath, trigger.Paths); err != nil {
+			return nil, nil, err
+		}
+	}
+	a.watches = append(a.watches, w)
+	slog.Info("Watch started", "id", trigger.ID, "command", trigger.Command)
+	return w, tea.Batch(a.RunWatch(trigger.ID), watchTick(w), watchChange(w)), nil
+}
+
+// RemoveWatch stops a watch
+func (a *App) RemoveWatch(id string) error {
+	i := slices.IndexFunc(a.watches, func(w *Watch) bool { return w.Trigger.ID == id })
+	if i < 0 {
+		return fmt.Errorf("no watch %q", id)
+	}
+	if watcher := a.watches[i].watcher; watcher != nil {
+		watcher.Close()
+	}
+	a.watches = slices.Delete(a.watches, i, i+1)
+	return nil
+}
+
+// StopWatches stops every watch
+func (a *App) StopWatches() {
+	for _, w := range a.watches {
+		if w.watcher != nil {
+			w.watcher.Close()
+		}
+	}
+	a.watches = nil
+}
+
+// watchTick waits for a watch's next interval
+func watchTick(w *Watch) tea.Cmd {
+	if w.Trigger.Every == 0 {
+		return nil
+	}
+	id := w.Trigger.ID
+	return tea.Tick(w.Trigger.Every, func(time.Time) tea.Msg {
+		return WatchDueMsg{ID: id}
+	})
+}
+
+// watchChange waits for the next change to a watch's paths
+func watchChange(w *Watch) tea.Cmd {
+	if w.watcher == nil {
+		return nil
+	}
+	id, watcher := w.Trigger.ID, w.watcher
+	return func() tea.Msg {
+		if watcher.Wait() {
+			return WatchDueMsg{ID: id, Changed: true}
+		}
+		return nil
+	}
+}
+
+// WatchDue runs a watch that is due and waits for its next run. Watches
+// removed in the meantime are dropped.
+func (a *App) WatchDue(msg WatchDueMsg) tea.Cmd {
+	w := a.findWatch(msg.ID)
+	if w == nil {
+		return nil
+	}
+	if msg.Changed {
+		return tea.Batch(a.RunWatch(msg.ID), watchChange(w))
+	}
+	return tea.Batch(a.RunWatch(msg.ID), watchTick(w))
+}
+
+// RunWatch runs a watch's command now, unless it is still running
+func (a *App) RunWatch(id string) tea.Cmd {
+	w := a.findWatch(id)
+	if w == nil || w.Running {
+		return nil
+	}
+	w.Running = true
+	command := w.Trigger.Command
+	return func() tea.Msg {
+		return WatchRunMsg{ID: id, Result: watch.Run(context.Background(), util.RootPath, command)}
+	}
+}
+
+// HandleWatchRun compares a run with the previous one and, when the watch's
+// condition is met, sends its prompt to the current session
+func (a *App) HandleWatchRun(msg WatchRunMsg) (*App, tea.Cmd) {
+	w := a.findWatch(msg.ID)
+	if w == nil {
+		return a, nil
+	}
+	w.Running = false
+	if msg.Result.Err != nil {
+		slog.Warn("Watch command failed", "id", msg.ID, "error", msg.Result.Err)
+		w.LastErr = msg.Result.Err
+		return a, nil
+	}
+	w.LastErr = nil
+
+	fired, matches := w.Trigger.Evaluate(w.Last, msg.Result)
+	data := watch.Data{
+		Name:     w.Trigger.Name,
+		Command:  w.Trigger.Command,
+		Output:   msg.Result.Output,
+		Matches:  strings.Join(matches, "\n"),
+		ExitCode: msg.Result.ExitCode,
+	}
+	if w.Last != nil {
+		data.Previous = w.Last.Output
+	}
+	result := msg.Result
+	w.Last = &result
+	if !fired {
+		return a, nil
+	}
+
+	prompt, err := w.Trigger.Render(data)
+	if err != nil {
+		return a, toast.NewErrorToast(err.Error(), toast.WithTitle("Watch "+w.Trigger.Name))
+	}
+	w.Fired++
+	w.LastFired = msg.Result.RanAt
+	slog.Info("Watch fired", "id", msg.ID, "fired", w.Fired)
+
+	if a.IsBusy() {
+		// Only the latest prompt is kept while waiting
+		waiting := w.pending != ""
+		w.pending = prompt
+		if waiting {
+			return a, nil
+		}
+		return a, tea.Batch(
+			toast.NewInfoToast("The prompt will be sent when the session is idle", toast.WithTitle("Watch "+w.Trigger.Name)),
+			a.retryWatchPrompt(msg.ID),
+		)
+	}
+	return a.sendWatchPrompt(w, prompt)
+}
+
+func (a *App) retryWatchPrompt(id string) tea.Cmd {
+	return tea.Tick(watchBusyRetry, func(time.Time) tea.Msg {
+		return WatchPendingMsg{ID: id}
+	})
+}
+
+// SendPendingWatchPrompt sends a prompt that fired while the session was
+// busy, or waits longer when it still is
+func (a *App) SendPendingWatchPrompt(msg WatchPendingMsg) (*App, tea.Cmd) {
+	w := a.findWatch(msg.ID)
+	if w == nil || w.pending == "" {
+		return a, nil
+	}
+	if a.IsBusy() {
+		return a, a.retryWatchPrompt(msg.ID)
+	}
+	prompt := w.pending
+	w.pending = ""
+	return a.sendWatchPrompt(w, prompt)
+}
+
+func (a *App) sendWatchPrompt(w *Watch, prompt string) (*App, tea.Cmd) {
+	updated, cmd := a.SendPrompt(context.Background(), Prompt{Text: prompt})
+	return updated, tea.Batch(
+		cmd,
+		toast.NewInfoToast("Sent its prompt to the session", toast.WithTitle("Watch fired: "+w.Trigger.Name)),
+	)
+}
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode-sdk-go/option"
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+	"github.com/aaronmrosenthal/rycode/internal/worktree"
+)
+
+// WorktreesListedMsg is sent with the worktrees and submodules of the
+// project the TUI started in
+type WorktreesListedMsg struct {
+	Main  string // Main worktree
+	Roots []worktree.Root
+	Err   error
+}
+
+// WorktreeSwitchedMsg is sent when the server is ready to work in another
+// worktree or submodule
+type WorktreeSwitchedMsg struct {
+	Dir     string
+	Project opencode.Project
+	Client  *opencode.Client
+	Err     error
+}
+
+// ListWorktrees lists the worktrees and submodules the TUI can switch to
+func (a *App) ListWorktrees() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		roots, err := worktree.List(ctx, a.homeRoot)
+		if err != nil {
+			return WorktreesListedMsg{Err: err}
+		}
+		msg := WorktreesListedMsg{Roots: roots}
+		if len(roots) > 0 {
+			msg.Main = roots[0].Path
+		}
+		return msg
+	}
+}
+
+// SwitchWorktree points the TUI at another worktree or submodule. The server
+// scopes its file tools, sessions and events to the directory each request
+// names, so a client naming dir keeps the agent within it.
+func (a *App) SwitchWorktree(dir string) tea.Cmd {
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(util.RootPath, dir)
+	}
+	dir = filepath.Clean(dir)
+	home := a.homeClient
+	homeRoot := a.homeRoot
+
+	return func() tea.Msg {
+		info, err := remote.Stat(dir)
+		if err != nil || !info.IsDir() {
+			return WorktreeSwitchedMsg{Dir: dir, Err: fmt.Errorf("no such directory: %s", dir)}
+		}
+		client := home
+		if dir != homeRoot {
+			client = opencode.NewClient(append(slices.Clone(home.Options), option.WithQuery("directory", dir))...)
+		}
+		ctx, cancel := a.TimeoutContext(TimeoutAPI)
+		defer cancel()
+		project, err := client.Project.Current(ctx, opencode.ProjectCurrentParams{})
+		if err != nil {
+			return WorktreeSwitchedMsg{Dir: dir, Err: fmt.Errorf("server couldn't open %s: %w", dir, err)}
+		}
+		return WorktreeSwitchedMsg{Dir: dir, Project: *project, Client: client}
+	}
+}
+
+// ApplyWorktree makes the directory switched to the TUI's root and clears
+// the session, which belongs to the previous one
+func (a *App) ApplyWorktree(msg WorktreeSwitchedMsg) tea.Cmd {
+	a.Client = msg.Client
+	a.Project = msg.Project
+	util.RootPath = msg.Dir
+	util.CwdPath = msg.Dir
+	a.pendingDir = ""
+	if a.Events != nil {
+		a.Events.SetClient(msg.Client)
+	}
+	return util.CmdHandler(SessionClearedMsg{})
+}
+
+// Worktree returns the directory the TUI works in, and whether it's another
+// than the one it started in
+func (a *App) Worktree() (string, bool) {
+	return util.RootPath, util.RootPath != a.homeRoot
+}
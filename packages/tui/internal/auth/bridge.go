@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sync"
 	"time"
 )
 
@@ -70,9 +71,15 @@ type RecommendationsResult struct {
 	Recommendations []Recommendation `json:"recommendations"`
 }
 
-// Bridge provides access to the TypeScript authentication system
+// Bridge provides access to the TypeScript authentication system. The
+// credentials of cloud platforms, Azure OpenAI and AWS Bedrock, it detects
+// itself and adds to the answers of the CLI.
 type Bridge struct {
 	cliPath string
+
+	cloudMu sync.Mutex
+	cloud   []CloudCredentials
+	cloudAt time.Time // When cloud was detected
 }
 
 var debugLog *os.File
@@ -133,6 +140,15 @@ func (b *Bridge) runCLI(ctx context.Context, args ...string) ([]byte, error) {
 
 // CheckAuthStatus checks if a provider is authenticated
 func (b *Bridge) CheckAuthStatus(ctx context.Context, provider string) (*AuthStatus, error) {
+	if IsCloudProvider(provider) {
+		c, _ := b.cloudProvider(ctx, provider)
+		status := &AuthStatus{Provider: provider, IsAuthenticated: c.Usable()}
+		if status.IsAuthenticated {
+			status.ModelsCount = len(c.Models)
+		}
+		return status, nil
+	}
+
 	output, err := b.runCLI(ctx, "check", provider)
 	if err != nil {
 		return nil, err
@@ -193,33 +209,50 @@ func (b *Bridge) GetProviderHealth(ctx context.Context, provider string) (*Provi
 
 // ListAuthenticatedProviders lists all authenticated providers
 func (b *Bridge) ListAuthenticatedProviders(ctx context.Context) ([]ProviderInfo, error) {
-	output, err := b.runCLI(ctx, "list")
-	if err != nil {
-		return nil, err
-	}
-
 	var result struct {
 		Providers []ProviderInfo `json:"providers"`
 	}
-	if err := json.Unmarshal(output, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse provider list: %w", err)
+	output, err := b.runCLI(ctx, "list")
+	if err == nil {
+		if err := json.Unmarshal(output, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse provider list: %w", err)
+		}
 	}
 
+	cloud := b.usableCloud(ctx)
+	if err != nil && len(cloud) == 0 {
+		return nil, err
+	}
+	for _, c := range cloud {
+		result.Providers = append(result.Providers, ProviderInfo{ID: c.Provider, Name: cloudNames[c.Provider], ModelsCount: len(c.Models)})
+	}
 	return result.Providers, nil
 }
 
 // AutoDetect attempts to auto-detect credentials
 func (b *Bridge) AutoDetect(ctx context.Context) (*AutoDetectResult, error) {
+	var result AutoDetectResult
 	output, err := b.runCLI(ctx, "auto-detect")
-	if err != nil {
-		return nil, err
+	if err == nil {
+		if err := json.Unmarshal(output, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse auto-detect result: %w", err)
+		}
 	}
 
-	var result AutoDetectResult
-	if err := json.Unmarshal(output, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse auto-detect result: %w", err)
+	cloud := b.usableCloud(ctx)
+	if err != nil && len(cloud) == 0 {
+		return nil, err
+	}
+	for _, c := range cloud {
+		result.Credentials = append(result.Credentials, struct {
+			Provider string `json:"provider"`
+			Count    int    `json:"count"`
+		}{Provider: c.Provider, Count: len(c.Models)})
+		result.Found++
+	}
+	if len(cloud) > 0 {
+		result.Message = fmt.Sprintf("Found credentials for %d provider(s)", result.Found)
 	}
-
 	return &result, nil
 }
 
@@ -289,19 +322,38 @@ type CLIProviderInfo struct {
 	Source   string   `json:"source"`
 }
 
-// GetCLIProviders retrieves available CLI providers with models
+// GetCLIProviders retrieves available CLI providers with models, and the
+// cloud platforms with usable credentials
 func (b *Bridge) GetCLIProviders(ctx context.Context) ([]CLIProviderInfo, error) {
-	output, err := b.runCLI(ctx, "cli-providers")
-	if err != nil {
-		return nil, err
-	}
-
 	var result struct {
 		Providers []CLIProviderInfo `json:"providers"`
 	}
-	if err := json.Unmarshal(output, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse CLI providers: %w", err)
+	output, err := b.runCLI(ctx, "cli-providers")
+	if err == nil {
+		if err := json.Unmarshal(output, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse CLI providers: %w", err)
+		}
 	}
 
+	cloud := b.usableCloud(ctx)
+	if err != nil && len(cloud) == 0 {
+		return nil, err
+	}
+	for _, c := range cloud {
+		result.Providers = append(result.Providers, CLIProviderInfo{Provider: c.Provider, Models: c.Models, Source: c.Source})
+	}
 	return result.Providers, nil
 }
+
+// usableCloud returns the cloud platforms prompts can be sent to
+func (b *Bridge) usableCloud(ctx context.Context) []CloudCredentials {
+	var usable []CloudCredentials
+	for _, c := range b.cloudCredentials(ctx) {
+		if c.Usable() {
+			usable = append(usable, c)
+		} else {
+			logDebug("DEBUG [bridge]: %s credentials from %s unusable: %v", c.Provider, c.Source, c.Err)
+		}
+	}
+	return usable
+}
@@ -0,0 +1,307 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Provider IDs of the cloud platforms whose credentials the bridge detects
+// itself rather than through the auth CLI. They match the server's IDs, so
+// prompts to their models are routed to the same credentials.
+const (
+	ProviderAzure   = "azure"
+	ProviderBedrock = "amazon-bedrock"
+)
+
+// cloudNames are the names of the cloud platforms
+var cloudNames = map[string]string{
+	ProviderAzure:   "Azure OpenAI",
+	ProviderBedrock: "AWS Bedrock",
+}
+
+// cloudTTL is how long detected cloud credentials are reused, as listing
+// Azure deployments takes a request
+const cloudTTL = 5 * time.Minute
+
+// azureAPIVersion is the version of the Azure OpenAI API that lists
+// deployments
+const azureAPIVersion = "2022-12-01"
+
+// bedrockModels are the Bedrock models offered, best first. In regions
+// with cross-region inference they are called through the inference
+// profile of the region's geography.
+var bedrockModels = []string{
+	"anthropic.claude-sonnet-4-5-20250929-v1:0",
+	"anthropic.claude-opus-4-1-20250805-v1:0",
+	"anthropic.claude-sonnet-4-20250514-v1:0",
+	"anthropic.claude-3-7-sonnet-20250219-v1:0",
+	"anthropic.claude-3-5-haiku-20241022-v1:0",
+	"amazon.nova-pro-v1:0",
+	"amazon.nova-lite-v1:0",
+	"amazon.nova-micro-v1:0",
+	"meta.llama3-3-70b-instruct-v1:0",
+	"deepseek.r1-v1:0",
+}
+
+// CloudCredentials are the credentials found for a cloud platform
+type CloudCredentials struct {
+	Provider string
+	Source   string   // Where they were found, such as "environment" or "profile work"
+	Region   string   // AWS region, empty for Azure
+	Models   []string // Azure deployments or Bedrock model IDs
+	Err      error    // Why the credentials can't be used, although found
+}
+
+// Usable reports whether prompts can be sent with the credentials
+func (c CloudCredentials) Usable() bool {
+	return c.Err == nil && len(c.Models) > 0
+}
+
+// cloudCredentials returns the cloud credentials found, detecting them
+// again once they are older than cloudTTL
+func (b *Bridge) cloudCredentials(ctx context.Context) []CloudCredentials {
+	b.cloudMu.Lock()
+	defer b.cloudMu.Unlock()
+	if b.cloudAt.IsZero() || time.Since(b.cloudAt) > cloudTTL {
+		b.cloud = detectCloud(ctx, os.Getenv, http.DefaultClient)
+		b.cloudAt = time.Now()
+	}
+	return b.cloud
+}
+
+// cloudProvider returns the credentials of a cloud platform, if found
+func (b *Bridge) cloudProvider(ctx context.Context, provider string) (CloudCredentials, bool) {
+	for _, c := range b.cloudCredentials(ctx) {
+		if c.Provider == provider {
+			return c, true
+		}
+	}
+	return CloudCredentials{}, false
+}
+
+// IsCloudProvider reports whether the bridge detects a provider's
+// credentials itself
+func IsCloudProvider(provider string) bool {
+	return provider == ProviderAzure || provider == ProviderBedrock
+}
+
+func detectCloud(ctx context.Context, getenv func(string) string, client *http.Client) []CloudCredentials {
+	var found []CloudCredentials
+	if c, ok := detectAzure(ctx, getenv, client); ok {
+		found = append(found, c)
+	}
+	if c, ok := detectBedrock(getenv); ok {
+		found = append(found, c)
+	}
+	return found
+}
+
+// detectAzure finds an Azure OpenAI endpoint and key. Its models are the
+// deployments named in AZURE_OPENAI_DEPLOYMENTS, or else those the
+// endpoint lists.
+func detectAzure(ctx context.Context, getenv func(string) string, client *http.Client) (CloudCredentials, bool) {
+	endpoint := strings.TrimRight(getenv("AZURE_OPENAI_ENDPOINT"), "/")
+	if endpoint == "" && getenv("AZURE_RESOURCE_NAME") != "" {
+		endpoint = "https://" + getenv("AZURE_RESOURCE_NAME") + ".openai.azure.com"
+	}
+	key := firstEnv(getenv, "AZURE_OPENAI_API_KEY", "AZURE_API_KEY")
+	if endpoint == "" && key == "" {
+		return CloudCredentials{}, false
+	}
+
+	c := CloudCredentials{Provider: ProviderAzure, Source: "environment"}
+	switch {
+	case endpoint == "":
+		c.Err = fmt.Errorf("set AZURE_OPENAI_ENDPOINT or AZURE_RESOURCE_NAME")
+		return c, true
+	case key == "":
+		c.Err = fmt.Errorf("set AZURE_OPENAI_API_KEY")
+		return c, true
+	}
+
+	c.Models = splitList(firstEnv(getenv, "AZURE_OPENAI_DEPLOYMENTS", "AZURE_OPENAI_DEPLOYMENT"))
+	if len(c.Models) > 0 {
+		return c, true
+	}
+	deployments, err := listAzureDeployments(ctx, client, endpoint, key)
+	switch {
+	case err != nil:
+		c.Err = fmt.Errorf("listing deployments: %w; set AZURE_OPENAI_DEPLOYMENTS instead", err)
+	case len(deployments) == 0:
+		c.Err = fmt.Errorf("%s has no deployments", endpoint)
+	}
+	c.Models = deployments
+	return c, true
+}
+
+func listAzureDeployments(ctx context.Context, client *http.Client, endpoint, key string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"/openai/deployments?api-version="+azureAPIVersion, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("api-key", key)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+
+	var body struct {
+		Data []struct {
+			ID     string `json:"id"`
+			Status string `json:"status"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	var deployments []string
+	for _, d := range body.Data {
+		if d.Status == "" || d.Status == "succeeded" {
+			deployments = append(deployments, d.ID)
+		}
+	}
+	slices.Sort(deployments)
+	return deployments, nil
+}
+
+// detectBedrock finds AWS credentials the way the AWS SDKs do: a Bedrock
+// API key, keys in the environment, with a session token when they come
+// from STS, a web identity to assume a role with, the container's
+// credentials, or a profile of the shared config files.
+func detectBedrock(getenv func(string) string) (CloudCredentials, bool) {
+	profile := firstEnv(getenv, "AWS_PROFILE", "AWS_DEFAULT_PROFILE")
+	if profile == "" {
+		profile = "default"
+	}
+	home, _ := os.UserHomeDir()
+	config := readINI(envOr(getenv, "AWS_CONFIG_FILE", filepath.Join(home, ".aws", "config")))
+	credentials := readINI(envOr(getenv, "AWS_SHARED_CREDENTIALS_FILE", filepath.Join(home, ".aws", "credentials")))
+	configSection := config["profile "+profile]
+	if profile == "default" && configSection == nil {
+		configSection = config["default"]
+	}
+
+	c := CloudCredentials{Provider: ProviderBedrock}
+	switch {
+	case getenv("AWS_BEARER_TOKEN_BEDROCK") != "":
+		c.Source = "Bedrock API key"
+	case getenv("AWS_ACCESS_KEY_ID") != "" && getenv("AWS_SECRET_ACCESS_KEY") != "":
+		c.Source = "environment"
+		if getenv("AWS_SESSION_TOKEN") != "" {
+			c.Source = "environment (STS session)"
+		}
+	case getenv("AWS_WEB_IDENTITY_TOKEN_FILE") != "" && getenv("AWS_ROLE_ARN") != "":
+		c.Source = "web identity (STS)"
+	case getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI") != "" || getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI") != "":
+		c.Source = "container"
+	case credentials[profile]["aws_access_key_id"] != "" || configSection["aws_access_key_id"] != "":
+		c.Source = "profile " + profile
+	case configSection["role_arn"] != "":
+		c.Source = "profile " + profile + " (assume role)"
+	case configSection["sso_session"] != "" || configSection["sso_start_url"] != "":
+		c.Source = "profile " + profile + " (SSO)"
+	case configSection["credential_process"] != "":
+		c.Source = "profile " + profile + " (credential process)"
+	default:
+		return CloudCredentials{}, false
+	}
+
+	c.Region = firstEnv(getenv, "AWS_REGION", "AWS_DEFAULT_REGION")
+	if c.Region == "" {
+		c.Region = configSection["region"]
+	}
+	if c.Region == "" {
+		c.Err = fmt.Errorf("set AWS_REGION or the region of profile %s", profile)
+		return c, true
+	}
+	c.Models = BedrockModels(c.Region)
+	return c, true
+}
+
+// BedrockModels returns the IDs the Bedrock models are called by in a
+// region, best first
+func BedrockModels(region string) []string {
+	var geography string
+	switch {
+	case strings.HasPrefix(region, "us-"):
+		geography = "us."
+	case strings.HasPrefix(region, "eu-"):
+		geography = "eu."
+	case strings.HasPrefix(region, "ap-"):
+		geography = "apac."
+	}
+	models := make([]string, len(bedrockModels))
+	for i, id := range bedrockModels {
+		models[i] = geography + id
+	}
+	return models
+}
+
+// readINI reads the sections of an AWS config or credentials file. A file
+// that can't be read has none.
+func readINI(path string) map[string]map[string]string {
+	sections := map[string]map[string]string{}
+	f, err := os.Open(path)
+	if err != nil {
+		return sections
+	}
+	defer f.Close()
+
+	var section map[string]string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			name := strings.Join(strings.Fields(line[1:len(line)-1]), " ")
+			section = map[string]string{}
+			sections[name] = section
+		case section != nil:
+			if key, value, ok := strings.Cut(line, "="); ok {
+				section[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			}
+		}
+	}
+	return sections
+}
+
+func firstEnv(getenv func(string) string, names ...string) string {
+	for _, name := range names {
+		if value := getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func envOr(getenv func(string) string, name, fallback string) string {
+	if value := getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func splitList(s string) []string {
+	var items []string
+	for item := range strings.SplitSeq(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
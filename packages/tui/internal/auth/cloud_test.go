@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestDetectCloud(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/deployments" || r.Header.Get("api-key") != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"data": [
+			{"id": "gpt-4o", "status": "succeeded"},
+			{"id": "gpt-4o-mini", "status": "succeeded"},
+			{"id": "o3", "status": "creating"}
+		]}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	config := filepath.Join(dir, "config")
+	os.WriteFile(config, []byte("[profile work]\nsso_session = corp\nregion = eu-west-1\n"), 0o600)
+
+	env := map[string]string{
+		"AZURE_OPENAI_ENDPOINT":       server.URL + "/",
+		"AZURE_OPENAI_API_KEY":        "secret",
+		"AWS_PROFILE":                 "work",
+		"AWS_CONFIG_FILE":             config,
+		"AWS_SHARED_CREDENTIALS_FILE": filepath.Join(dir, "credentials"),
+	}
+	found := detectCloud(context.Background(), func(name string) string { return env[name] }, server.Client())
+	if len(found) != 2 {
+		t.Fatalf("found %+v, want Azure and Bedrock", found)
+	}
+
+	azure := found[0]
+	if !azure.Usable() || !slices.Equal(azure.Models, []string{"gpt-4o", "gpt-4o-mini"}) {
+		t.Errorf("Azure = %+v, want the ready deployments", azure)
+	}
+
+	bedrock := found[1]
+	if !bedrock.Usable() || bedrock.Source != "profile work (SSO)" || bedrock.Region != "eu-west-1" {
+		t.Errorf("Bedrock = %+v, want the SSO profile in eu-west-1", bedrock)
+	}
+	if bedrock.Models[0] != "eu.anthropic.claude-sonnet-4-5-20250929-v1:0" {
+		t.Errorf("best Bedrock model = %q, want the EU inference profile", bedrock.Models[0])
+	}
+
+	env["AZURE_OPENAI_API_KEY"] = "wrong"
+	env["AZURE_OPENAI_DEPLOYMENT"] = "prod-gpt"
+	env["AWS_CONFIG_FILE"] = filepath.Join(dir, "missing")
+	env["AWS_ACCESS_KEY_ID"], env["AWS_SECRET_ACCESS_KEY"] = "AKIA", "secret"
+	found = detectCloud(context.Background(), func(name string) string { return env[name] }, server.Client())
+	if len(found) != 2 || !slices.Equal(found[0].Models, []string{"prod-gpt"}) {
+		t.Errorf("found %+v, want the named deployment without listing", found)
+	}
+	if found[1].Usable() || found[1].Err == nil {
+		t.Errorf("Bedrock without a region = %+v, want an error", found[1])
+	}
+}
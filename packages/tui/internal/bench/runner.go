@@ -0,0 +1,150 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aaronmrosenthal/rycode/internal/intelligence"
+)
+
+// ModelRef identifies a model by provider and model ID
+type ModelRef struct {
+	ProviderID string
+	ModelID    string
+}
+
+func (m ModelRef) String() string {
+	return m.ProviderID + "/" + m.ModelID
+}
+
+// ParseModelRef parses a "provider/model" reference
+func ParseModelRef(ref string) (ModelRef, error) {
+	parts := strings.SplitN(strings.TrimSpace(ref), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return ModelRef{}, fmt.Errorf("invalid model %q, expected provider/model", ref)
+	}
+	return ModelRef{ProviderID: parts[0], ModelID: parts[1]}, nil
+}
+
+// Completion is a model's answer to a benchmark case
+type Completion struct {
+	Text    string
+	Cost    float64
+	Tokens  int64
+	Latency time.Duration
+}
+
+// Completer sends a single benchmark case to a model
+type Completer interface {
+	Complete(ctx context.Context, model ModelRef, c Case) (Completion, error)
+}
+
+// CaseResult is the outcome of one case against one model
+type CaseResult struct {
+	Case       string
+	Model      ModelRef
+	Completion Completion
+	Score      float64
+	Failures   []string
+	Err        error
+}
+
+// Passed reports whether every check of the case passed
+func (r CaseResult) Passed() bool {
+	return r.Err == nil && len(r.Failures) == 0
+}
+
+// Report collects the results of a benchmark run
+type Report struct {
+	Suite   string
+	RunAt   time.Time
+	Models  []ModelRef
+	Results []CaseResult
+}
+
+// Progress is called after each case finishes
+type Progress func(done, total int)
+
+// Run executes every case of the suite against every model, one at a time,
+// so latency measurements are not skewed by concurrent requests
+func Run(ctx context.Context, suite *Suite, models []ModelRef, completer Completer, progress Progress) *Report {
+	report := &Report{
+		Suite:  suite.Name,
+		RunAt:  time.Now(),
+		Models: models,
+	}
+
+	total := len(models) * len(suite.Cases)
+	for _, model := range models {
+		for _, c := range suite.Cases {
+			if ctx.Err() != nil {
+				return report
+			}
+
+			result := CaseResult{Case: c.Name, Model: model}
+			completion, err := completer.Complete(ctx, model, c)
+			if err != nil {
+				result.Err = err
+			} else {
+				result.Completion = completion
+				result.Score, result.Failures = c.Expect.Evaluate(completion.Text, completion.Latency)
+			}
+			report.Results = append(report.Results, result)
+
+			if progress != nil {
+				progress(len(report.Results), total)
+			}
+		}
+	}
+	return report
+}
+
+// Summaries aggregates the report into one scored result per model, ordered
+// from best to worst
+func (r *Report) Summaries() []intelligence.BenchmarkResult {
+	summaries := make([]intelligence.BenchmarkResult, 0, len(r.Models))
+	for _, model := range r.Models {
+		summary := intelligence.BenchmarkResult{
+			Suite:    r.Suite,
+			RunAt:    r.RunAt,
+			Provider: model.ProviderID,
+			Model:    model.ModelID,
+		}
+
+		var totalScore float64
+		var totalLatency time.Duration
+		answered := 0
+		for _, result := range r.Results {
+			if result.Model != model {
+				continue
+			}
+			summary.Cases++
+			if result.Err != nil {
+				summary.Errors++
+				continue
+			}
+			if result.Passed() {
+				summary.Passed++
+			}
+			answered++
+			totalScore += result.Score
+			totalLatency += result.Completion.Latency
+			summary.Cost += result.Completion.Cost
+			summary.Tokens += result.Completion.Tokens
+		}
+
+		if summary.Cases > 0 {
+			// Errored cases count as a zero score
+			summary.Accuracy = totalScore / float64(summary.Cases)
+		}
+		if answered > 0 {
+			summary.AvgLatency = totalLatency / time.Duration(answered)
+		}
+		summaries = append(summaries, summary)
+	}
+
+	intelligence.SortBenchmarkResults(summaries)
+	return summaries
+}
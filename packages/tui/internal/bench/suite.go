@@ -0,0 +1,138 @@
+package bench
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultSuitePath is where a project keeps its benchmark suite, relative to
+// the project root
+var DefaultSuitePath = filepath.Join(".rycode", "bench.yaml")
+
+// Suite is a set of prompts with assertions used to compare models on the
+// user's own workloads
+type Suite struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Models      []string `yaml:"models"` // "provider/model" entries to run by default
+	Agent       string   `yaml:"agent"`
+	Cases       []Case   `yaml:"cases"`
+}
+
+// Case is a single prompt and the checks its answer must pass
+type Case struct {
+	Name   string      `yaml:"name"`
+	Prompt string      `yaml:"prompt"`
+	System string      `yaml:"system"`
+	Expect Expectation `yaml:"expect"`
+}
+
+// Expectation lists the heuristic assertions applied to an answer. Every
+// assertion counts as one check towards the case score.
+type Expectation struct {
+	Contains    []string      `yaml:"contains"`
+	NotContains []string      `yaml:"not_contains"`
+	Matches     []string      `yaml:"matches"` // regular expressions
+	MinLength   int           `yaml:"min_length"`
+	MaxLength   int           `yaml:"max_length"`
+	MaxLatency  time.Duration `yaml:"max_latency"`
+}
+
+// LoadSuite reads and validates a YAML benchmark suite
+func LoadSuite(path string) (*Suite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read benchmark suite %s: %w", path, err)
+	}
+	suite, err := ParseSuite(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid benchmark suite %s: %w", path, err)
+	}
+	if suite.Name == "" {
+		suite.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return suite, nil
+}
+
+// ParseSuite decodes a YAML benchmark suite
+func ParseSuite(data []byte) (*Suite, error) {
+	var suite Suite
+	if err := yaml.Unmarshal(data, &suite); err != nil {
+		return nil, err
+	}
+	if len(suite.Cases) == 0 {
+		return nil, fmt.Errorf("suite has no cases")
+	}
+	for i := range suite.Cases {
+		c := &suite.Cases[i]
+		if strings.TrimSpace(c.Prompt) == "" {
+			return nil, fmt.Errorf("case %d has no prompt", i+1)
+		}
+		if c.Name == "" {
+			c.Name = fmt.Sprintf("case %d", i+1)
+		}
+		for _, pattern := range c.Expect.Matches {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return nil, fmt.Errorf("case %q: invalid pattern %q: %w", c.Name, pattern, err)
+			}
+		}
+	}
+	return &suite, nil
+}
+
+// Evaluate scores an answer against the expectation. It returns the fraction
+// of checks that passed and a description of every failed check. An
+// expectation without checks scores 1.
+func (e Expectation) Evaluate(text string, latency time.Duration) (float64, []string) {
+	var failures []string
+	checks := 0
+	lower := strings.ToLower(text)
+
+	for _, s := range e.Contains {
+		checks++
+		if !strings.Contains(lower, strings.ToLower(s)) {
+			failures = append(failures, fmt.Sprintf("missing %q", s))
+		}
+	}
+	for _, s := range e.NotContains {
+		checks++
+		if strings.Contains(lower, strings.ToLower(s)) {
+			failures = append(failures, fmt.Sprintf("contains %q", s))
+		}
+	}
+	for _, pattern := range e.Matches {
+		checks++
+		if re, err := regexp.Compile(pattern); err != nil || !re.MatchString(text) {
+			failures = append(failures, fmt.Sprintf("no match for /%s/", pattern))
+		}
+	}
+	if e.MinLength > 0 {
+		checks++
+		if len(text) < e.MinLength {
+			failures = append(failures, fmt.Sprintf("shorter than %d chars", e.MinLength))
+		}
+	}
+	if e.MaxLength > 0 {
+		checks++
+		if len(text) > e.MaxLength {
+			failures = append(failures, fmt.Sprintf("longer than %d chars", e.MaxLength))
+		}
+	}
+	if e.MaxLatency > 0 {
+		checks++
+		if latency > e.MaxLatency {
+			failures = append(failures, fmt.Sprintf("slower than %s", e.MaxLatency))
+		}
+	}
+
+	if checks == 0 {
+		return 1, nil
+	}
+	return float64(checks-len(failures)) / float64(checks), failures
+}
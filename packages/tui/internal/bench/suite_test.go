@@ -0,0 +1,112 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+const testSuite = `
+name: go-basics
+models: [anthropic/claude-sonnet-4]
+cases:
+  - name: error wrapping
+    prompt: How do I wrap an error in Go?
+    expect:
+      contains: ["%w", "fmt.Errorf"]
+      not_contains: ["panic"]
+      max_latency: 5s
+  - prompt: Write a haiku
+`
+
+func TestParseSuite(t *testing.T) {
+	suite, err := ParseSuite([]byte(testSuite))
+	if err != nil {
+		t.Fatalf("failed to parse suite: %v", err)
+	}
+	if suite.Name != "go-basics" || len(suite.Cases) != 2 {
+		t.Fatalf("unexpected suite %+v", suite)
+	}
+	if suite.Cases[1].Name != "case 2" {
+		t.Errorf("expected default case name, got %q", suite.Cases[1].Name)
+	}
+	if suite.Cases[0].Expect.MaxLatency != 5*time.Second {
+		t.Errorf("expected max latency of 5s, got %s", suite.Cases[0].Expect.MaxLatency)
+	}
+}
+
+func TestParseSuite_Invalid(t *testing.T) {
+	if _, err := ParseSuite([]byte("name: empty\n")); err == nil {
+		t.Error("expected error for suite without cases")
+	}
+	if _, err := ParseSuite([]byte("cases:\n  - name: x\n")); err == nil {
+		t.Error("expected error for case without prompt")
+	}
+	if _, err := ParseSuite([]byte("cases:\n  - prompt: x\n    expect:\n      matches: ['(']\n")); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestExpectationEvaluate(t *testing.T) {
+	e := Expectation{
+		Contains:    []string{"fmt.Errorf"},
+		NotContains: []string{"panic"},
+		Matches:     []string{`%w`},
+		MaxLatency:  time.Second,
+	}
+
+	score, failures := e.Evaluate("Use fmt.Errorf with %w", 500*time.Millisecond)
+	if score != 1 || len(failures) != 0 {
+		t.Errorf("expected full score, got %v %v", score, failures)
+	}
+
+	score, failures = e.Evaluate("just panic", 2*time.Second)
+	if score != 0 || len(failures) != 4 {
+		t.Errorf("expected zero score with 4 failures, got %v %v", score, failures)
+	}
+}
+
+type fakeCompleter map[string]Completion
+
+func (f fakeCompleter) Complete(ctx context.Context, model ModelRef, c Case) (Completion, error) {
+	completion, ok := f[model.String()]
+	if !ok {
+		return Completion{}, errors.New("model unavailable")
+	}
+	return completion, nil
+}
+
+func TestRunSummaries(t *testing.T) {
+	suite, _ := ParseSuite([]byte(testSuite))
+	good := ModelRef{ProviderID: "a", ModelID: "good"}
+	bad := ModelRef{ProviderID: "b", ModelID: "bad"}
+	missing := ModelRef{ProviderID: "c", ModelID: "missing"}
+
+	completer := fakeCompleter{
+		good.String(): {Text: "fmt.Errorf(\"%w\", err)", Cost: 0.01, Latency: time.Second},
+		bad.String():  {Text: "panic(err)", Cost: 0.002, Latency: 2 * time.Second},
+	}
+
+	calls := 0
+	report := Run(context.Background(), suite, []ModelRef{bad, good, missing}, completer, func(done, total int) {
+		calls++
+		if total != 6 {
+			t.Errorf("expected 6 total cases, got %d", total)
+		}
+	})
+	if calls != 6 {
+		t.Errorf("expected 6 progress callbacks, got %d", calls)
+	}
+
+	summaries := report.Summaries()
+	if len(summaries) != 3 {
+		t.Fatalf("expected 3 summaries, got %d", len(summaries))
+	}
+	if summaries[0].Model != "good" || summaries[0].Passed != 2 || summaries[0].Accuracy != 1 {
+		t.Errorf("expected good model to win with full marks, got %+v", summaries[0])
+	}
+	if summaries[2].Model != "missing" || summaries[2].Errors != 2 || summaries[2].Accuracy != 0 {
+		t.Errorf("expected missing model last with errors, got %+v", summaries[2])
+	}
+}
@@ -0,0 +1,318 @@
+// Package bridge lets editor plugins talk to a running TUI over a unix
+// socket, so a Neovim or VS Code plugin can send the current file or
+// selection to the active session and have the session's edits sent back
+// without implementing a client of the server.
+//
+// The protocol is JSON-RPC 2.0, one message per line. Each TUI listens on
+// its own socket and describes it in a JSON file next to it, in
+// $XDG_RUNTIME_DIR/rycode (or rycode-<uid> in the temporary directory), so
+// plugins find the TUI of the project they edit. Processes started by the
+// TUI find it in $RYCODE_BRIDGE.
+package bridge
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// Methods a plugin calls. Subscribe is answered by the bridge itself; the
+// others are sent to the TUI as a RequestMsg.
+const (
+	MethodStatus    = "status"    // Describes the active session
+	MethodAttach    = "attach"    // Adds a file or selection to the prompt
+	MethodPrompt    = "prompt"    // Sends a prompt, with an optional file or selection
+	MethodSubscribe = "subscribe" // Starts the diff notifications
+	MethodTool      = "tool"      // Runs a project tool for the agent, answering with its output
+)
+
+// NotifyDiff is the notification sent to subscribed plugins when the
+// session edits a file
+const NotifyDiff = "diff"
+
+// EnvSocket holds the socket path in processes started by the TUI
+const EnvSocket = "RYCODE_BRIDGE"
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeFailed         = -32000
+)
+
+var (
+	ErrMethodNotFound = errors.New("method not found")
+	ErrInvalidParams  = errors.New("invalid params")
+)
+
+// Selection is a file, or the lines of a file, sent by an editor. Lines
+// are 1-based and inclusive.
+type Selection struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"startLine,omitempty"`
+	EndLine   int    `json:"endLine,omitempty"`
+	Text      string `json:"text,omitempty"` // The selected text, the whole file is attached when empty
+}
+
+// PromptParams are the params of the prompt method
+type PromptParams struct {
+	Text      string     `json:"text"`
+	Selection *Selection `json:"selection,omitempty"`
+}
+
+// ToolParams are the params of the tool method
+type ToolParams struct {
+	Name string         `json:"name"`
+	Args map[string]any `json:"args"`
+}
+
+// Diff is an edit the session made to a file
+type Diff struct {
+	SessionID string `json:"sessionID"`
+	PartID    string `json:"partID"`
+	Tool      string `json:"tool"`
+	Path      string `json:"path"`
+	Diff      string `json:"diff"`
+}
+
+// Info is the discovery file written next to the socket
+type Info struct {
+	Socket string `json:"socket"`
+	Root   string `json:"root"`
+	PID    int    `json:"pid"`
+}
+
+// RequestMsg is a call from a plugin for the TUI to answer with Reply
+type RequestMsg struct {
+	Method string
+	Params json.RawMessage
+	id     json.RawMessage
+	conn   *conn
+}
+
+// Reply answers the request. Requests sent as notifications, without an
+// ID, are not answered.
+func (r RequestMsg) Reply(result any, err error) {
+	if r.conn == nil || len(r.id) == 0 {
+		return
+	}
+	if err != nil {
+		r.conn.write(message{ID: r.id, Error: rpcError(err)})
+		return
+	}
+	if result == nil {
+		result = true
+	}
+	r.conn.write(message{ID: r.id, Result: result})
+}
+
+// Decode unmarshals the params of the request
+func (r RequestMsg) Decode(params any) error {
+	if len(r.Params) == 0 {
+		return fmt.Errorf("%w: missing", ErrInvalidParams)
+	}
+	if err := json.Unmarshal(r.Params, params); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidParams, err)
+	}
+	return nil
+}
+
+// message is a request, response or notification
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  any             `json:"result,omitempty"`
+	Error   *errorObject    `json:"error,omitempty"`
+}
+
+type errorObject struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func rpcError(err error) *errorObject {
+	code := codeFailed
+	switch {
+	case errors.Is(err, ErrMethodNotFound):
+		code = codeMethodNotFound
+	case errors.Is(err, ErrInvalidParams):
+		code = codeInvalidParams
+	}
+	return &errorObject{Code: code, Message: err.Error()}
+}
+
+// Server accepts plugin connections on a unix socket
+type Server struct {
+	listener net.Listener
+	info     string
+	send     func(tea.Msg)
+
+	mu    sync.Mutex
+	conns map[*conn]bool
+	sent  map[string]bool // Parts whose diff was sent
+}
+
+// Dir returns the directory the sockets and their discovery files are in.
+// The temporary directory is shared by every user, so each has a directory
+// of their own there.
+func Dir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "rycode")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("rycode-%d", os.Getuid()))
+}
+
+// Listen starts the bridge of the TUI working on root. Requests are sent
+// to the TUI with send, typically the program's Send.
+func Listen(root string, send func(tea.Msg)) (*Server, error) {
+	dir := Dir()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	// Another user could have made the directory first, to read the
+	// sockets' discovery files or put sockets of their own in it
+	if err := checkPrivate(dir); err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("bridge-%d", os.Getpid())
+	socket := filepath.Join(dir, name+".sock")
+	os.Remove(socket) // Left behind by a process that crashed with this PID
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		listener: listener,
+		info:     filepath.Join(dir, name+".json"),
+		send:     send,
+		conns:    make(map[*conn]bool),
+		sent:     make(map[string]bool),
+	}
+	data, _ := json.Marshal(Info{Socket: socket, Root: root, PID: os.Getpid()})
+	if err := os.WriteFile(s.info, data, 0o600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	os.Setenv(EnvSocket, socket)
+	go s.accept()
+	return s, nil
+}
+
+// Socket returns the path of the socket
+func (s *Server) Socket() string {
+	return s.listener.Addr().String()
+}
+
+// Close stops the bridge and disconnects the plugins
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	os.Remove(s.info)
+	s.mu.Lock()
+	for c := range s.conns {
+		c.Close()
+	}
+	s.mu.Unlock()
+	return err
+}
+
+// PublishDiff notifies the subscribed plugins of an edit, once per part
+func (s *Server) PublishDiff(d Diff) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sent[d.PartID] {
+		return
+	}
+	s.sent[d.PartID] = true
+	params, _ := json.Marshal(d)
+	for c := range s.conns {
+		if c.subscribed {
+			c.write(message{Method: NotifyDiff, Params: params})
+		}
+	}
+}
+
+func (s *Server) accept() {
+	for {
+		nc, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		c := &conn{Conn: nc}
+		s.mu.Lock()
+		s.conns[c] = true
+		s.mu.Unlock()
+		go s.serve(c)
+	}
+}
+
+// maxMessage bounds a message, which may hold a whole file
+const maxMessage = 16 << 20
+
+func (s *Server) serve(c *conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+		c.Close()
+	}()
+	scanner := bufio.NewScanner(c)
+	scanner.Buffer(make([]byte, 64*1024), maxMessage)
+	for scanner.Scan() {
+		var req message
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			c.write(message{ID: json.RawMessage("null"), Error: &errorObject{Code: codeParseError, Message: err.Error()}})
+			continue
+		}
+		msg := RequestMsg{Method: req.Method, Params: req.Params, id: req.ID, conn: c}
+		if req.Method == MethodSubscribe {
+			s.mu.Lock()
+			c.subscribed = true
+			s.mu.Unlock()
+			msg.Reply(true, nil)
+			continue
+		}
+		s.send(msg)
+	}
+	if err := scanner.Err(); err != nil {
+		slog.Debug("Editor bridge connection closed", "error", err)
+	}
+}
+
+// writeTimeout bounds a write to a plugin
+const writeTimeout = time.Second
+
+// conn is a plugin connection. Writes come from the TUI and the bridge, so
+// they are serialized.
+type conn struct {
+	net.Conn
+	subscribed bool
+	wmu        sync.Mutex
+}
+
+func (c *conn) write(m message) {
+	m.JSONRPC = "2.0"
+	data, err := json.Marshal(m)
+	if err != nil {
+		slog.Error("Failed to encode bridge message", "error", err)
+		return
+	}
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	// A plugin that stops reading mustn't hold up the TUI
+	c.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if _, err := c.Conn.Write(append(data, '\n')); err != nil {
+		slog.Debug("Failed to write to editor bridge", "error", err)
+	}
+}
@@ -0,0 +1,125 @@
+package bridge
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+func TestBridge(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv(EnvSocket, "")
+	// Stands in for the TUI, answering requests as they arrive
+	send := func(msg tea.Msg) {
+		req := msg.(RequestMsg)
+		switch req.Method {
+		case MethodStatus:
+			req.Reply(map[string]string{"sessionID": "ses_1"}, nil)
+		case MethodAttach:
+			var selection Selection
+			req.Reply(nil, req.Decode(&selection))
+		default:
+			req.Reply(nil, ErrMethodNotFound)
+		}
+	}
+	s, err := Listen("/src/app", send)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	data, err := os.ReadFile(strings.TrimSuffix(s.Socket(), ".sock") + ".json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info Info
+	if err := json.Unmarshal(data, &info); err != nil || info.Socket != s.Socket() || info.Root != "/src/app" {
+		t.Errorf("discovery file = %s, %v", data, err)
+	}
+	if os.Getenv(EnvSocket) != s.Socket() {
+		t.Errorf("%s = %q", EnvSocket, os.Getenv(EnvSocket))
+	}
+
+	conn, err := net.Dial("unix", s.Socket())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	lines := bufio.NewScanner(conn)
+	call := func(request string) map[string]any {
+		t.Helper()
+		if _, err := conn.Write([]byte(request + "\n")); err != nil {
+			t.Fatal(err)
+		}
+		if !lines.Scan() {
+			t.Fatalf("no answer to %s: %v", request, lines.Err())
+		}
+		var answer map[string]any
+		if err := json.Unmarshal(lines.Bytes(), &answer); err != nil {
+			t.Fatal(err)
+		}
+		return answer
+	}
+
+	answer := call(`{"jsonrpc":"2.0","id":1,"method":"status"}`)
+	if result, _ := answer["result"].(map[string]any); result["sessionID"] != "ses_1" || answer["id"] != float64(1) {
+		t.Errorf("status answer = %v", answer)
+	}
+	answer = call(`{"jsonrpc":"2.0","id":2,"method":"attach"}`)
+	if rpcErr, _ := answer["error"].(map[string]any); rpcErr["code"] != float64(codeInvalidParams) {
+		t.Errorf("attach without params answer = %v", answer)
+	}
+	answer = call(`{"jsonrpc":"2.0","id":3,"method":"rename"}`)
+	if rpcErr, _ := answer["error"].(map[string]any); rpcErr["code"] != float64(codeMethodNotFound) {
+		t.Errorf("unknown method answer = %v", answer)
+	}
+	answer = call(`not json`)
+	if rpcErr, _ := answer["error"].(map[string]any); rpcErr["code"] != float64(codeParseError) {
+		t.Errorf("parse error answer = %v", answer)
+	}
+
+	if answer := call(`{"jsonrpc":"2.0","id":"s","method":"subscribe"}`); answer["result"] != true {
+		t.Fatalf("subscribe answer = %v", answer)
+	}
+	diff := Diff{SessionID: "ses_1", PartID: "prt_1", Tool: "edit", Path: "/src/app/main.go", Diff: "@@ -1 +1 @@"}
+	s.PublishDiff(diff)
+	s.PublishDiff(diff) // Parts are updated more than once
+	s.PublishDiff(Diff{PartID: "prt_2", Path: "/src/app/go.mod"})
+	for _, want := range []string{"/src/app/main.go", "/src/app/go.mod"} {
+		if !lines.Scan() {
+			t.Fatalf("no notification: %v", lines.Err())
+		}
+		var notification struct {
+			Method string `json:"method"`
+			Params Diff   `json:"params"`
+		}
+		if err := json.Unmarshal(lines.Bytes(), &notification); err != nil || notification.Method != NotifyDiff || notification.Params.Path != want {
+			t.Errorf("notification = %s, want the diff of %s", lines.Bytes(), want)
+		}
+	}
+}
+
+func TestListenRefusesSharedDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("directories aren't checked on Windows")
+	}
+	dir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+	if err := os.Mkdir(filepath.Join(dir, "rycode"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	os.Chmod(filepath.Join(dir, "rycode"), 0o777)
+	if s, err := Listen("/src/app", func(tea.Msg) {}); err == nil {
+		s.Close()
+		t.Error("Listen used a directory others can write to")
+	}
+}
@@ -0,0 +1,26 @@
+//go:build !windows
+
+package bridge
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// checkPrivate returns an error unless dir is a directory, not a link to
+// one, that only the current user can use
+func checkPrivate(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !info.IsDir() || !ok || int(stat.Uid) != os.Getuid() {
+		return fmt.Errorf("%s isn't a directory of the current user", dir)
+	}
+	if info.Mode().Perm() != 0o700 {
+		return fmt.Errorf("%s has mode %o, not 700", dir, info.Mode().Perm())
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package bridge
+
+// checkPrivate accepts any directory: Windows controls access to the
+// temporary directory per user already
+func checkPrivate(dir string) error {
+	return nil
+}
@@ -0,0 +1,260 @@
+// Package changelog collects the commits of a revision range, groups them by
+// kind and writes the release notes drafted from them to CHANGELOG.md.
+package changelog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+)
+
+// DefaultPath is the changelog file, relative to the project root
+const DefaultPath = "CHANGELOG.md"
+
+// maxCommits bounds the commits sent to the model
+const maxCommits = 500
+
+// Commit is a commit of the range
+type Commit struct {
+	Hash     string
+	Author   string
+	Subject  string
+	Body     string
+	Type     string // Conventional commit type, e.g. feat or fix; empty when not conventional
+	Scope    string
+	Breaking bool
+}
+
+// Group is a category of commits in the release notes
+type Group struct {
+	Title   string
+	Commits []Commit
+}
+
+// groupTitles maps conventional commit types to release note categories, in
+// the order the categories are listed
+var groupTitles = []struct {
+	title string
+	types []string
+}{
+	{"Features", []string{"feat", "feature"}},
+	{"Bug Fixes", []string{"fix", "bugfix"}},
+	{"Performance", []string{"perf"}},
+	{"Refactoring", []string{"refactor"}},
+	{"Documentation", []string{"docs", "doc"}},
+	{"Tests", []string{"test", "tests"}},
+	{"Maintenance", []string{"build", "ci", "chore", "deps", "style"}},
+}
+
+// conventionalPattern matches "type(scope)!: subject"
+var conventionalPattern = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?:\s*(.+)$`)
+
+// Options configures a changelog run
+type Options struct {
+	Range   string // Revision range, e.g. v1.2..HEAD
+	Version string // Heading of the release; the range end when it is a tag
+}
+
+// ParseArgs parses the arguments of /changelog: [range] [--version name].
+// Without a range, the commits since the latest tag are used.
+func ParseArgs(ctx context.Context, root, args string) (Options, error) {
+	var options Options
+	fields := strings.Fields(args)
+	for i := 0; i < len(fields); i++ {
+		switch field := fields[i]; {
+		case field == "--version" && i+1 < len(fields):
+			i++
+			options.Version = fields[i]
+		case strings.HasPrefix(field, "--version="):
+			options.Version = strings.TrimPrefix(field, "--version=")
+		case strings.HasPrefix(field, "-"):
+			return options, fmt.Errorf("unknown option %s", field)
+		case options.Range == "":
+			options.Range = field
+		default:
+			return options, fmt.Errorf("usage: /changelog [from..to] [--version name]")
+		}
+	}
+
+	if options.Range == "" {
+		tag, err := git(ctx, root, "describe", "--tags", "--abbrev=0")
+		if err != nil {
+			return options, fmt.Errorf("no tags to start from; give a range such as v1.2..HEAD")
+		}
+		options.Range = strings.TrimSpace(tag) + "..HEAD"
+	}
+	if options.Version == "" {
+		options.Version = "Unreleased"
+		if _, end, ok := strings.Cut(options.Range, ".."); ok {
+			end = strings.TrimPrefix(end, ".")
+			if end != "" && end != "HEAD" {
+				options.Version = end
+			}
+		}
+	}
+	return options, nil
+}
+
+// Log returns the commits of a range, newest first, without merges
+func Log(ctx context.Context, root, revisions string) ([]Commit, error) {
+	output, err := git(ctx, root, "log", "--no-merges", fmt.Sprintf("--max-count=%d", maxCommits),
+		"--format=%H%x1f%an%x1f%s%x1f%b%x1e", revisions)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(output, "\x1e") {
+		fields := strings.Split(strings.TrimSpace(record), "\x1f")
+		if len(fields) < 4 {
+			continue
+		}
+		commit := Commit{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Subject: fields[2],
+			Body:    strings.TrimSpace(fields[3]),
+		}
+		if match := conventionalPattern.FindStringSubmatch(commit.Subject); match != nil {
+			commit.Type = strings.ToLower(match[1])
+			commit.Scope = match[2]
+			commit.Breaking = match[3] == "!"
+			commit.Subject = match[4]
+		}
+		if strings.Contains(commit.Body, "BREAKING CHANGE") {
+			commit.Breaking = true
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// GroupCommits sorts commits into release note categories. Breaking changes
+// come first and commits that aren't conventional end up under Other.
+func GroupCommits(commits []Commit) []Group {
+	var breaking []Commit
+	byTitle := make(map[string][]Commit)
+	for _, commit := range commits {
+		if commit.Breaking {
+			breaking = append(breaking, commit)
+			continue
+		}
+		title := "Other"
+		for _, group := range groupTitles {
+			for _, kind := range group.types {
+				if commit.Type == kind {
+					title = group.title
+				}
+			}
+		}
+		byTitle[title] = append(byTitle[title], commit)
+	}
+
+	var groups []Group
+	if len(breaking) > 0 {
+		groups = append(groups, Group{Title: "Breaking Changes", Commits: breaking})
+	}
+	for _, group := range groupTitles {
+		if commits := byTitle[group.title]; len(commits) > 0 {
+			groups = append(groups, Group{Title: group.title, Commits: commits})
+		}
+	}
+	if other := byTitle["Other"]; len(other) > 0 {
+		groups = append(groups, Group{Title: "Other", Commits: other})
+	}
+	return groups
+}
+
+// Prompt asks the model to write release notes for the grouped commits
+func Prompt(options Options, groups []Group) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Write release notes for %s covering the commits %s, grouped below by kind.\n\n", options.Version, options.Range)
+	b.WriteString("Use Markdown with a ### heading per category, keeping the categories below and dropping empty ones. ")
+	b.WriteString("Write one concise, user-facing bullet per change, merging commits that belong together and omitting purely internal noise. ")
+	b.WriteString("Call out breaking changes and how to migrate. ")
+	b.WriteString("Reply with the release notes only: no top-level heading, preamble or closing remarks.\n")
+	for _, group := range groups {
+		fmt.Fprintf(&b, "\n## %s\n", group.Title)
+		for _, commit := range group.Commits {
+			subject := commit.Subject
+			if commit.Scope != "" {
+				subject = commit.Scope + ": " + subject
+			}
+			fmt.Fprintf(&b, "- %s (%s)\n", subject, commit.Hash[:min(7, len(commit.Hash))])
+			if commit.Body != "" {
+				body := strings.ReplaceAll(commit.Body, "\n", " ")
+				if len(body) > 300 {
+					body = body[:300] + "…"
+				}
+				fmt.Fprintf(&b, "  %s\n", body)
+			}
+		}
+	}
+	return b.String()
+}
+
+// Prepend adds the notes of a release above the previous releases of the
+// changelog at path, creating the file if needed
+func Prepend(path, version, notes string, date time.Time) error {
+	existing, err := remote.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+
+	entry := fmt.Sprintf("## %s - %s\n\n%s\n", version, date.Format("2006-01-02"), strings.TrimSpace(notes))
+	content := string(existing)
+	// New releases go above the previous ones, keeping any title and
+	// introduction above them
+	switch i := strings.Index(content, "\n## "); {
+	case strings.TrimSpace(content) == "":
+		content = "# Changelog\n\n" + entry
+	case strings.HasPrefix(content, "## "):
+		content = entry + "\n" + content
+	case i >= 0:
+		content = content[:i+1] + entry + "\n" + content[i+1:]
+	default:
+		content = strings.TrimRight(content, "\n") + "\n\n" + entry
+	}
+	if err := remote.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// CreateRelease publishes the notes as a GitHub release of tag with the gh
+// CLI and returns the release URL. The tag is created from HEAD by GitHub
+// when it doesn't exist yet.
+func CreateRelease(ctx context.Context, root, tag, notes string) (string, error) {
+	if _, err := exec.LookPath("gh"); err != nil && remote.Active() == nil {
+		return "", fmt.Errorf("creating releases needs the GitHub CLI (gh)")
+	}
+	cmd := remote.Command(ctx, root, "gh", "release", "create", tag, "--title", tag, "--notes-file", "-")
+	cmd.Stdin = strings.NewReader(notes)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("gh release create: %s", strings.TrimSpace(string(output)))
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return lines[len(lines)-1], nil
+}
+
+// git runs a git command in root and returns its output
+func git(ctx context.Context, root string, args ...string) (string, error) {
+	cmd := remote.Command(ctx, root, "git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(output), nil
+}
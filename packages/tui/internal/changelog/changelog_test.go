@@ -0,0 +1,82 @@
+package changelog
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGroupCommits(t *testing.T) {
+	commits := []Commit{
+		{Hash: "a", Type: "fix", Subject: "crash on empty input"},
+		{Hash: "b", Type: "feat", Subject: "add export"},
+		{Hash: "c", Subject: "tweak wording"},
+		{Hash: "d", Type: "feat", Subject: "drop v1 API", Breaking: true},
+		{Hash: "e", Type: "chore", Subject: "bump deps"},
+	}
+	groups := GroupCommits(commits)
+
+	var titles []string
+	for _, group := range groups {
+		titles = append(titles, group.Title)
+	}
+	want := "Breaking Changes,Features,Bug Fixes,Maintenance,Other"
+	if got := strings.Join(titles, ","); got != want {
+		t.Errorf("group titles = %s, want %s", got, want)
+	}
+	if groups[0].Commits[0].Hash != "d" {
+		t.Errorf("breaking group holds %s, want d", groups[0].Commits[0].Hash)
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		args    string
+		range_  string
+		version string
+	}{
+		{"v1.2..HEAD", "v1.2..HEAD", "Unreleased"},
+		{"v1.2..v1.3", "v1.2..v1.3", "v1.3"},
+		{"v1.2...v1.3", "v1.2...v1.3", "v1.3"},
+		{"v1.2..HEAD --version v1.3.0", "v1.2..HEAD", "v1.3.0"},
+		{"--version=v2 v1..HEAD", "v1..HEAD", "v2"},
+	}
+	for _, tt := range tests {
+		options, err := ParseArgs(context.Background(), t.TempDir(), tt.args)
+		if err != nil {
+			t.Fatalf("ParseArgs(%q): %v", tt.args, err)
+		}
+		if options.Range != tt.range_ || options.Version != tt.version {
+			t.Errorf("ParseArgs(%q) = %+v, want range %s version %s", tt.args, options, tt.range_, tt.version)
+		}
+	}
+
+	if _, err := ParseArgs(context.Background(), t.TempDir(), "a..b c..d"); err == nil {
+		t.Error("expected an error for two ranges")
+	}
+}
+
+func TestPrepend(t *testing.T) {
+	date := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), DefaultPath)
+
+	if err := Prepend(path, "v1.0", "### Features\n- First", date); err != nil {
+		t.Fatal(err)
+	}
+	if err := Prepend(path, "v1.1", "### Bug Fixes\n- Second\n", date); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Changelog\n\n" +
+		"## v1.1 - 2026-03-14\n\n### Bug Fixes\n- Second\n\n" +
+		"## v1.0 - 2026-03-14\n\n### Features\n- First\n"
+	if string(content) != want {
+		t.Errorf("changelog =\n%s\nwant\n%s", content, want)
+	}
+}
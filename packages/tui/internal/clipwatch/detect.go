@@ -0,0 +1,125 @@
+// Package clipwatch recognizes error messages and stack traces in copied
+// text, so the TUI can offer to explain them when they are copied
+package clipwatch
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MaxText caps the copied text that is looked at and sent in a prompt;
+// longer copies are rarely a single error
+const MaxText = 32 * 1024
+
+// Match is an error found in copied text
+type Match struct {
+	Kind    string // What was copied, such as "Go panic"
+	Summary string // The line that best describes the error
+	Text    string // The copied text, trimmed
+}
+
+type detector struct {
+	kind    string
+	pattern *regexp.Regexp
+	// summary picks the describing line, the matched line when nil
+	summary func(lines []string, matched int) string
+}
+
+var detectors = []detector{
+	{
+		kind:    "Go panic",
+		pattern: regexp.MustCompile(`^(panic: |fatal error: |goroutine \d+ \[)`),
+		summary: firstPrefixed("panic: ", "fatal error: "),
+	},
+	{
+		kind:    "Python traceback",
+		pattern: regexp.MustCompile(`^Traceback \(most recent call last\):`),
+		summary: lastLine,
+	},
+	{
+		kind:    "Rust panic",
+		pattern: regexp.MustCompile(`^thread '.*' panicked at`),
+	},
+	{
+		kind:    "Java stack trace",
+		pattern: regexp.MustCompile(`^\s+at [\w$.<>]+\([\w$]+\.(java|kt|scala):\d+\)|^Exception in thread "`),
+		summary: firstUnindented,
+	},
+	{
+		kind:    "JavaScript stack trace",
+		pattern: regexp.MustCompile(`^\s+at (.+ \()?(file://)?[^\s()]+:\d+:\d+\)?$`),
+		summary: firstUnindented,
+	},
+	{
+		kind:    "compiler error",
+		pattern: regexp.MustCompile(`^error(\[E\d+\])?: |^[^\s:]+\.\w+(:\d+){1,2}: |^[^\s(]+\.\w+\(\d+,\d+\): error `),
+	},
+	{
+		kind:    "error",
+		pattern: regexp.MustCompile(`^(\w+\.)*\w*(Error|Exception)(: |$)|^(ERROR|FATAL|Fatal|npm ERR!)[: ]`),
+	},
+}
+
+// Detect reports whether text looks like an error message or a stack trace.
+// Everything else people copy, including code that mentions errors, is left
+// alone, so an unclear case is not a match.
+func Detect(text string) (Match, bool) {
+	text = strings.TrimSpace(text)
+	if len(text) < 8 || len(text) > MaxText {
+		return Match{}, false
+	}
+	lines := strings.Split(text, "\n")
+	for _, d := range detectors {
+		for i, line := range lines {
+			if !d.pattern.MatchString(strings.TrimRight(line, "\r")) {
+				continue
+			}
+			summary := strings.TrimSpace(line)
+			if d.summary != nil {
+				summary = d.summary(lines, i)
+			}
+			return Match{Kind: d.kind, Summary: summary, Text: text}, true
+		}
+	}
+	return Match{}, false
+}
+
+// Prompt asks the session to explain an error and fix it
+func (m Match) Prompt() string {
+	return fmt.Sprintf("I just copied this %s. Explain what causes it and fix it in this project.\n\n```\n%s\n```", m.Kind, m.Text)
+}
+
+func firstPrefixed(prefixes ...string) func([]string, int) string {
+	return func(lines []string, matched int) string {
+		for _, line := range lines {
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(line, prefix) {
+					return strings.TrimSpace(line)
+				}
+			}
+		}
+		return strings.TrimSpace(lines[matched])
+	}
+}
+
+// lastLine is the exception of a Python traceback
+func lastLine(lines []string, matched int) string {
+	for i := len(lines) - 1; i > matched; i-- {
+		if line := strings.TrimSpace(lines[i]); line != "" {
+			return line
+		}
+	}
+	return strings.TrimSpace(lines[matched])
+}
+
+// firstUnindented is the exception a Java or JavaScript stack trace is for,
+// the line above its frames
+func firstUnindented(lines []string, matched int) string {
+	for _, line := range lines {
+		if line = strings.TrimRight(line, "\r"); line != "" && line[0] != ' ' && line[0] != '\t' {
+			return strings.TrimSpace(line)
+		}
+	}
+	return strings.TrimSpace(lines[matched])
+}
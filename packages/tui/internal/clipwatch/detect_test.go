@@ -0,0 +1,104 @@
+package clipwatch
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		kind    string
+		summary string
+	}{
+		{
+			name:    "go panic",
+			text:    "panic: runtime error: index out of range [3] with length 3\n\ngoroutine 1 [running]:\nmain.main()\n\t/tmp/main.go:8 +0x1d\nexit status 2",
+			kind:    "Go panic",
+			summary: "panic: runtime error: index out of range [3] with length 3",
+		},
+		{
+			name:    "go stack without the panic line",
+			text:    "goroutine 7 [chan receive]:\nmain.worker()\n\t/tmp/main.go:20",
+			kind:    "Go panic",
+			summary: "goroutine 7 [chan receive]:",
+		},
+		{
+			name:    "python",
+			text:    "Traceback (most recent call last):\n  File \"app.py\", line 3, in <module>\n    main()\nKeyError: 'name'\n",
+			kind:    "Python traceback",
+			summary: "KeyError: 'name'",
+		},
+		{
+			name:    "java",
+			text:    "java.lang.NullPointerException: user is null\n\tat com.example.Service.load(Service.java:42)\n\tat com.example.Main.main(Main.java:7)",
+			kind:    "Java stack trace",
+			summary: "java.lang.NullPointerException: user is null",
+		},
+		{
+			name:    "node",
+			text:    "TypeError: Cannot read properties of undefined (reading 'id')\n    at render (/app/src/view.js:12:18)\n    at /app/src/index.js:4:3",
+			kind:    "JavaScript stack trace",
+			summary: "TypeError: Cannot read properties of undefined (reading 'id')",
+		},
+		{
+			name:    "rust",
+			text:    "thread 'main' panicked at src/main.rs:4:5:\nattempt to divide by zero",
+			kind:    "Rust panic",
+			summary: "thread 'main' panicked at src/main.rs:4:5:",
+		},
+		{
+			name:    "rustc",
+			text:    "error[E0308]: mismatched types\n --> src/main.rs:2:18",
+			kind:    "compiler error",
+			summary: "error[E0308]: mismatched types",
+		},
+		{
+			name:    "go build",
+			text:    "# example\n./main.go:12:2: undefined: foo",
+			kind:    "compiler error",
+			summary: "./main.go:12:2: undefined: foo",
+		},
+		{
+			name:    "typescript",
+			text:    "src/app.ts(3,7): error TS2322: Type 'string' is not assignable to type 'number'.",
+			kind:    "compiler error",
+			summary: "src/app.ts(3,7): error TS2322: Type 'string' is not assignable to type 'number'.",
+		},
+		{
+			name:    "single error line",
+			text:    "ValueError: invalid literal for int() with base 10: 'x'",
+			kind:    "error",
+			summary: "ValueError: invalid literal for int() with base 10: 'x'",
+		},
+		{
+			name:    "npm",
+			text:    "npm ERR! code ERESOLVE\nnpm ERR! ERESOLVE unable to resolve dependency tree",
+			kind:    "error",
+			summary: "npm ERR! code ERESOLVE",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			match, ok := Detect(test.text)
+			if !ok {
+				t.Fatal("not detected")
+			}
+			if match.Kind != test.kind || match.Summary != test.summary {
+				t.Errorf("Detect = %q, %q, want %q, %q", match.Kind, match.Summary, test.kind, test.summary)
+			}
+		})
+	}
+}
+
+func TestDetectIgnores(t *testing.T) {
+	for _, text := range []string{
+		"https://example.com/docs/errors",
+		"git checkout -b fix-error",
+		"if err != nil {\n\treturn fmt.Errorf(\"load: %w\", err)\n}",
+		"The error handling is in errors.go",
+		"Error",
+	} {
+		if match, ok := Detect(text); ok {
+			t.Errorf("Detect(%q) = %q", text, match.Kind)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"strings"
+)
+
+// Categories the shortcuts overlay groups commands by, in the order shown
+const (
+	CategorySessions = "Sessions"
+	CategoryMessages = "Messages"
+	CategoryPrompt   = "Prompt"
+	CategoryModels   = "Models & agents"
+	CategoryFiles    = "Files"
+	CategoryTools    = "Tools"
+	CategoryApp      = "App"
+	CategoryCustom   = "Custom commands"
+	CategoryPlugins  = "Plugins"
+)
+
+// Categories lists the categories in the order they are shown
+var Categories = []string{
+	CategorySessions,
+	CategoryMessages,
+	CategoryPrompt,
+	CategoryModels,
+	CategoryFiles,
+	CategoryTools,
+	CategoryApp,
+	CategoryCustom,
+	CategoryPlugins,
+}
+
+// categoryPrefixes maps the start of a built-in command's name to its
+// category. Built-in commands that match none are tools.
+var categoryPrefixes = []struct {
+	prefix   string
+	category string
+}{
+	{"session_", CategorySessions},
+	{"messages_", CategoryMessages},
+	{"tool_details", CategoryMessages},
+	{"thinking_blocks", CategoryMessages},
+	{"input_", CategoryPrompt},
+	{"editor_open", CategoryPrompt},
+	{"clipboard_watch", CategoryPrompt},
+	{"model_", CategoryModels},
+	{"agent_", CategoryModels},
+	{"switch_agent", CategoryModels},
+	{"path_rules", CategoryModels},
+	{"failover", CategoryModels},
+	{"file_", CategoryFiles},
+	{"app_", CategoryApp},
+	{"theme_list", CategoryApp},
+	{"statusbar", CategoryApp},
+	{"keybinds", CategoryApp},
+	{"vim", CategoryApp},
+	{"tutorial", CategoryApp},
+	{"plugins", CategoryApp},
+	{"hints", CategoryApp},
+	{"home_dashboard", CategoryApp},
+}
+
+// Category returns the category of a command
+func Category(c Command) string {
+	switch {
+	case c.Plugin != "":
+		return CategoryPlugins
+	case c.Custom:
+		return CategoryCustom
+	}
+	for _, p := range categoryPrefixes {
+		if strings.HasPrefix(string(c.Name), p.prefix) {
+			return p.category
+		}
+	}
+	return CategoryTools
+}
+
+// MatchesQuery reports whether every word of a search query appears in the
+// command's name, description, category, triggers or keys, ignoring case
+func (c Command) MatchesQuery(query string) bool {
+	fields := []string{string(c.Name), c.Description, Category(c), c.Plugin}
+	for _, trigger := range c.Trigger {
+		fields = append(fields, "/"+trigger)
+	}
+	for _, binding := range c.Keybindings {
+		fields = append(fields, binding.String())
+	}
+	haystack := strings.ToLower(strings.Join(fields, "\n"))
+	for word := range strings.FieldsSeq(strings.ToLower(query)) {
+		if !strings.Contains(haystack, word) {
+			return false
+		}
+	}
+	return true
+}
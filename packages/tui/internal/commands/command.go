@@ -14,6 +14,12 @@ type ExecuteCommandMsg Command
 type ExecuteCommandsMsg []Command
 type CommandExecutedMsg Command
 
+// ExecuteCommandArgsMsg executes a command with the arguments typed after its trigger
+type ExecuteCommandArgsMsg struct {
+	Command Command
+	Args    string
+}
+
 type Keybinding struct {
 	RequiresLeader bool
 	Key            string
@@ -32,6 +38,8 @@ type Command struct {
 	Keybindings []Keybinding
 	Trigger     []string
 	Custom      bool
+	AcceptsArgs bool   // Built-in command that takes arguments after its trigger
+	Plugin      string // Plugin that runs the command, empty for the others
 }
 
 func (c Command) Keys() []string {
@@ -59,6 +67,16 @@ func (c Command) MatchesTrigger(trigger string) bool {
 
 type CommandRegistry map[CommandName]Command
 
+// FindByTrigger returns the command that responds to the given slash trigger
+func (r CommandRegistry) FindByTrigger(trigger string) (Command, bool) {
+	for _, command := range r {
+		if command.MatchesTrigger(trigger) {
+			return command, true
+		}
+	}
+	return Command{}, false
+}
+
 func (r CommandRegistry) Sorted() []Command {
 	var commands []Command
 	for _, command := range r {
@@ -121,12 +139,17 @@ const (
 	AgentCycleCommand               CommandName = "agent_cycle"
 	AgentCycleReverseCommand        CommandName = "agent_cycle_reverse"
 	AppHelpCommand                  CommandName = "app_help"
+	AppShortcutsCommand             CommandName = "app_shortcuts"
 	SwitchAgentCommand              CommandName = "switch_agent"
 	SwitchAgentReverseCommand       CommandName = "switch_agent_reverse"
 	EditorOpenCommand               CommandName = "editor_open"
 	SessionNewCommand               CommandName = "session_new"
+	SessionUnclearCommand           CommandName = "session_unclear"
+	SessionPinCommand               CommandName = "session_pin"
 	SessionListCommand              CommandName = "session_list"
 	SessionTimelineCommand          CommandName = "session_timeline"
+	SessionSearchCommand            CommandName = "session_search"
+	SessionTemplatesCommand         CommandName = "session_templates"
 	SessionShareCommand             CommandName = "session_share"
 	SessionUnshareCommand           CommandName = "session_unshare"
 	SessionInterruptCommand         CommandName = "session_interrupt"
@@ -136,6 +159,7 @@ const (
 	ThinkingBlocksCommand           CommandName = "thinking_blocks"
 	ModelListCommand                CommandName = "model_list"
 	AgentListCommand                CommandName = "agent_list"
+	AgentPairCommand                CommandName = "agent_pair"
 	ModelCycleRecentCommand         CommandName = "model_cycle_recent"
 	ThemeListCommand                CommandName = "theme_list"
 	FileListCommand                 CommandName = "file_list"
@@ -147,6 +171,8 @@ const (
 	InputPasteCommand               CommandName = "input_paste"
 	InputSubmitCommand              CommandName = "input_submit"
 	InputNewlineCommand             CommandName = "input_newline"
+	InputHistorySearchCommand       CommandName = "input_history_search"
+	InputHistoryScopeCommand        CommandName = "input_history_scope"
 	MessagesPageUpCommand           CommandName = "messages_page_up"
 	MessagesPageDownCommand         CommandName = "messages_page_down"
 	MessagesHalfPageUpCommand       CommandName = "messages_half_page_up"
@@ -157,8 +183,76 @@ const (
 	MessagesLastCommand             CommandName = "messages_last"
 	MessagesLayoutToggleCommand     CommandName = "messages_layout_toggle"
 	MessagesCopyCommand             CommandName = "messages_copy"
+	MessagesCopyTextCommand         CommandName = "messages_copy_text"
+	MessagesCopyCodeCommand         CommandName = "messages_copy_code"
+	MessagesCopyMenuCommand         CommandName = "messages_copy_menu"
+	MessagesSelectCommand           CommandName = "messages_select"
+	MessagesBookmarkCommand         CommandName = "messages_bookmark"
+	MessagesBookmarksCommand        CommandName = "messages_bookmarks"
 	MessagesUndoCommand             CommandName = "messages_undo"
 	MessagesRedoCommand             CommandName = "messages_redo"
+	MessagesDiffCommand             CommandName = "messages_diff"
+	BenchRunCommand                 CommandName = "bench_run"
+	ExperimentCommand               CommandName = "experiment"
+	RefactorCommand                 CommandName = "refactor"
+	RepoAskCommand                  CommandName = "repo_ask"
+	DocgenCommand                   CommandName = "docgen"
+	GenTestsCommand                 CommandName = "gentests"
+	BudgetCommand                   CommandName = "budget"
+	CostCommand                     CommandName = "cost"
+	BackgroundCommand               CommandName = "background"
+	ProfileCommand                  CommandName = "profile"
+	ScreenReaderCommand             CommandName = "screen_reader"
+	VoiceCommand                    CommandName = "voice"
+	SecurityReviewCommand           CommandName = "security_review"
+	ChangelogCommand                CommandName = "changelog"
+	ScheduleCommand                 CommandName = "schedule"
+	FailoverCommand                 CommandName = "failover"
+	WatchCommand                    CommandName = "watch"
+	StatusBarCommand                CommandName = "statusbar"
+	KeybindsCommand                 CommandName = "keybinds"
+	VimCommand                      CommandName = "vim"
+	ClipboardWatchCommand           CommandName = "clipboard_watch"
+	PathRulesCommand                CommandName = "path_rules"
+	TutorialCommand                 CommandName = "tutorial"
+	PluginsCommand                  CommandName = "plugins"
+	HintsCommand                    CommandName = "hints"
+	SessionDirCommand               CommandName = "session_dir"
+	ShellCommand                    CommandName = "shell"
+	TodosCommand                    CommandName = "todos"
+	SessionMergeCommand             CommandName = "session_merge"
+	WorktreeCommand                 CommandName = "worktree"
+	GitCommand                      CommandName = "git"
+	BusyClearCommand                CommandName = "busy_clear"
+	FilePickerCommand               CommandName = "file_picker"
+	GlyphPickerCommand              CommandName = "glyph_picker"
+	FileViewCommand                 CommandName = "file_view"
+	ThemeFontCommand                CommandName = "theme_font"
+	EditJournalCommand              CommandName = "edit_journal"
+	ContextSetCommand               CommandName = "context_set"
+	ProjectToolsCommand             CommandName = "project_tools"
+	ToolLogCommand                  CommandName = "tool_log"
+	ResponseLanguageCommand         CommandName = "response_language"
+	AccessibilityCommand            CommandName = "accessibility"
+	PermissionRulesCommand          CommandName = "permission_rules"
+	MessagesMenuCommand             CommandName = "messages_menu"
+	NotifyCommand                   CommandName = "notify"
+	AutoCompactCommand              CommandName = "auto_compact"
+	TimeoutsCommand                 CommandName = "timeouts"
+	MessagesEditCommand             CommandName = "messages_edit"
+	SplashCommand                   CommandName = "splash"
+	SessionSummaryCommand           CommandName = "session_summary"
+	SplitPaneCommand                CommandName = "split_pane"
+	SplitFocusCommand               CommandName = "split_focus"
+	SplitGrowCommand                CommandName = "split_grow"
+	SplitShrinkCommand              CommandName = "split_shrink"
+	ArtifactsCommand                CommandName = "artifacts"
+	VerifyRefactorCommand           CommandName = "verify_refactor"
+	ColorDepthCommand               CommandName = "color_depth"
+	LoopGuardCommand                CommandName = "loop_guard"
+	RunLimitsCommand                CommandName = "run_limits"
+	SessionDatasetCommand           CommandName = "session_dataset"
+	HomeDashboardCommand            CommandName = "home_dashboard"
 	AppExitCommand                  CommandName = "app_exit"
 )
 
@@ -190,31 +284,57 @@ func parseBindings(bindings ...string) []Keybinding {
 	return parsedBindings
 }
 
-func LoadFromConfig(config *opencode.Config, customCommands []opencode.Command) CommandRegistry {
-	defaults := []Command{
+// defaultCommands returns the built-in commands with their default
+// keybindings
+func defaultCommands() []Command {
+	return []Command{
 		{
 			Name:        AppHelpCommand,
 			Description: "show help",
 			Keybindings: parseBindings("<leader>h"),
 			Trigger:     []string{"help"},
 		},
+		{
+			Name:        AppShortcutsCommand,
+			Description: "show keyboard shortcuts",
+			Keybindings: parseBindings("ctrl+?", "<leader>?"),
+			Trigger:     []string{"shortcuts", "keys"},
+		},
 		{
 			Name:        EditorOpenCommand,
-			Description: "open editor",
-			Keybindings: parseBindings("<leader>e"),
+			Description: "write the prompt in $EDITOR",
+			Keybindings: parseBindings("ctrl+o", "<leader>e"),
 			Trigger:     []string{"editor"},
 		},
 		{
 			Name:        SessionExportCommand,
-			Description: "export conversation",
+			Description: "open conversation in editor, or export to markdown, html or json",
 			Keybindings: parseBindings("<leader>x"),
 			Trigger:     []string{"export"},
+			AcceptsArgs: true,
 		},
 		{
 			Name:        SessionNewCommand,
 			Description: "new session",
 			Keybindings: parseBindings("<leader>n"),
 			Trigger:     []string{"new", "clear"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        SessionUnclearCommand,
+			Description: "bring back the session cleared last",
+			Trigger:     []string{"unclear"},
+		},
+		{
+			Name:        SessionPinCommand,
+			Description: "pin or unpin session",
+			Trigger:     []string{"pin"},
+		},
+		{
+			Name:        SessionTemplatesCommand,
+			Description: "start a session from a template",
+			Trigger:     []string{"templates", "template"},
+			AcceptsArgs: true,
 		},
 		{
 			Name:        SessionListCommand,
@@ -222,6 +342,12 @@ func LoadFromConfig(config *opencode.Config, customCommands []opencode.Command)
 			Keybindings: parseBindings("<leader>l"),
 			Trigger:     []string{"sessions", "resume", "continue"},
 		},
+		{
+			Name:        SessionSearchCommand,
+			Description: "search all sessions",
+			Keybindings: parseBindings("<leader>f"),
+			Trigger:     []string{"search", "find"},
+		},
 		{
 			Name:        SessionTimelineCommand,
 			Description: "show session timeline",
@@ -294,6 +420,12 @@ func LoadFromConfig(config *opencode.Config, customCommands []opencode.Command)
 			Keybindings: parseBindings("<leader>a"),
 			Trigger:     []string{"agents"},
 		},
+		{
+			Name:        AgentPairCommand,
+			Description: "pair an agent with the current provider",
+			Trigger:     []string{"pair"},
+			AcceptsArgs: true,
+		},
 		{
 			Name:        AgentCycleCommand,
 			Description: "next provider",
@@ -336,6 +468,17 @@ func LoadFromConfig(config *opencode.Config, customCommands []opencode.Command)
 			Description: "insert newline",
 			Keybindings: parseBindings("shift+enter", "ctrl+j"),
 		},
+		{
+			Name:        InputHistorySearchCommand,
+			Description: "search prompt history",
+			Keybindings: parseBindings("ctrl+r"),
+		},
+		{
+			Name:        InputHistoryScopeCommand,
+			Description: "recall prompts of this project or, with /history global, of every project",
+			Trigger:     []string{"history"},
+			AcceptsArgs: true,
+		},
 		{
 			Name:        MessagesPageUpCommand,
 			Description: "page up",
@@ -370,9 +513,42 @@ func LoadFromConfig(config *opencode.Config, customCommands []opencode.Command)
 
 		{
 			Name:        MessagesCopyCommand,
-			Description: "copy message",
+			Description: "copy last message as markdown",
 			Keybindings: parseBindings("<leader>y"),
 		},
+		{
+			Name:        MessagesCopyTextCommand,
+			Description: "copy last message as text",
+			Keybindings: parseBindings("<leader>ctrl+y"),
+		},
+		{
+			Name:        MessagesCopyCodeCommand,
+			Description: "copy code blocks of last message",
+			Keybindings: parseBindings("<leader>Y"),
+		},
+		{
+			Name:        MessagesCopyMenuCommand,
+			Description: "copy a message as markdown, text or code",
+			Trigger:     []string{"copy"},
+		},
+		{
+			Name:        MessagesSelectCommand,
+			Description: "select lines of the transcript to copy",
+			Keybindings: parseBindings("<leader>v"),
+			Trigger:     []string{"select"},
+		},
+		{
+			Name:        MessagesBookmarkCommand,
+			Description: "bookmark message in view",
+			Keybindings: parseBindings("<leader>k"),
+			Trigger:     []string{"bookmark"},
+		},
+		{
+			Name:        MessagesBookmarksCommand,
+			Description: "list bookmarks",
+			Keybindings: parseBindings("<leader>K"),
+			Trigger:     []string{"bookmarks"},
+		},
 		{
 			Name:        MessagesUndoCommand,
 			Description: "undo last message",
@@ -385,6 +561,364 @@ func LoadFromConfig(config *opencode.Config, customCommands []opencode.Command)
 			Keybindings: parseBindings("<leader>r"),
 			Trigger:     []string{"redo"},
 		},
+		{
+			Name:        MessagesDiffCommand,
+			Description: "diff vs previous answer",
+			Trigger:     []string{"diff"},
+		},
+		{
+			Name:        BenchRunCommand,
+			Description: "benchmark models on a prompt suite",
+			Trigger:     []string{"bench"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        ExperimentCommand,
+			Description: "A/B test two models",
+			Trigger:     []string{"experiment"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        RefactorCommand,
+			Description: "refactor across the workspace",
+			Trigger:     []string{"refactor"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        RepoAskCommand,
+			Description: "ask about the codebase",
+			Trigger:     []string{"ask"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        DocgenCommand,
+			Description: "generate documentation",
+			Trigger:     []string{"docgen"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        GenTestsCommand,
+			Description: "generate tests for uncovered code",
+			Trigger:     []string{"gentests"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        BudgetCommand,
+			Description: "forecast monthly spending",
+			Trigger:     []string{"budget"},
+		},
+		{
+			Name:        CostCommand,
+			Description: "break down costs by session, provider, model or day",
+			Trigger:     []string{"cost"},
+		},
+		{
+			Name:        BackgroundCommand,
+			Description: "show and limit what automatic features spend",
+			Trigger:     []string{"background", "insights"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        ProfileCommand,
+			Description: "profile rendering and event handling, with pprof",
+			Trigger:     []string{"profile", "perf"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        ScreenReaderCommand,
+			Description: "speak replies and state changes for screen readers",
+			Trigger:     []string{"screen-reader", "a11y"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        VoiceCommand,
+			Description: "push to talk: record, then transcribe into the prompt",
+			Keybindings: parseBindings("<leader>w"),
+			Trigger:     []string{"voice"},
+		},
+		{
+			Name:        SecurityReviewCommand,
+			Description: "review files or changes for security issues",
+			Trigger:     []string{"security-review"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        ChangelogCommand,
+			Description: "draft release notes for a range of commits",
+			Trigger:     []string{"changelog"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        ScheduleCommand,
+			Description: "schedule recurring prompts",
+			Trigger:     []string{"schedule"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        FailoverCommand,
+			Description: "retry failed prompts on another provider",
+			Trigger:     []string{"failover"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        WatchCommand,
+			Description: "run a prompt when a command's output changes",
+			Trigger:     []string{"watch"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        StatusBarCommand,
+			Description: "choose the widgets of the status bar",
+			Trigger:     []string{"statusbar"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        KeybindsCommand,
+			Description: "edit keybindings",
+			Trigger:     []string{"keybinds"},
+		},
+		{
+			Name:        VimCommand,
+			Description: "toggle vim keybindings in the prompt",
+			Trigger:     []string{"vim"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        ClipboardWatchCommand,
+			Description: "offer to explain errors copied to the clipboard",
+			Trigger:     []string{"clipwatch"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        PathRulesCommand,
+			Description: "show or toggle the agent and model rules for project paths",
+			Trigger:     []string{"rules"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        TutorialCommand,
+			Description: "practise in a free playground; /tutorial end leaves it",
+			Trigger:     []string{"tutorial"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        PluginsCommand,
+			Description: "manage plugins",
+			Trigger:     []string{"plugins"},
+		},
+		{
+			Name:        HintsCommand,
+			Description: "show the current hint; /hints dismiss hides it, /hints reset shows dismissed ones",
+			Trigger:     []string{"hints"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        SessionDirCommand,
+			Description: "change the directory the session's shell commands run in",
+			Trigger:     []string{"cd"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        ShellCommand,
+			Description: "run commands in named shells that keep running",
+			Trigger:     []string{"shell"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        TodosCommand,
+			Description: "list the session's open action items",
+			Trigger:     []string{"todos"},
+		},
+		{
+			Name:        SessionMergeCommand,
+			Description: "merge the session into another, or link them as related",
+			Trigger:     []string{"merge"},
+		},
+		{
+			Name:        WorktreeCommand,
+			Description: "switch to another git worktree or submodule",
+			Trigger:     []string{"worktree", "wt"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        GitCommand,
+			Description: "stage changed files and commit them with a generated message",
+			Trigger:     []string{"git"},
+		},
+		{
+			Name:        BusyClearCommand,
+			Description: "stop showing the session as busy when a reply or compaction is stuck",
+			Trigger:     []string{"unbusy"},
+		},
+		{
+			Name:        FilePickerCommand,
+			Description: "browse or search files to reference in the prompt",
+			Keybindings: parseBindings("<leader>o"),
+			Trigger:     []string{"files"},
+		},
+		{
+			Name:        GlyphPickerCommand,
+			Description: "insert a symbol or emoji",
+			Keybindings: parseBindings("ctrl+.", "<leader>."),
+			Trigger:     []string{"symbols", "emoji"},
+		},
+		{
+			Name:        FileViewCommand,
+			Description: "view a file, or the one the session's tools last touched",
+			Trigger:     []string{"view"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        ThemeFontCommand,
+			Description: "turn bold or italic text off or on for the current theme",
+			Trigger:     []string{"font"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        EditJournalCommand,
+			Description: "undo or redo the file edits of the session's tool calls",
+			Trigger:     []string{"edits"},
+		},
+		{
+			Name:        ContextSetCommand,
+			Description: "pin files, directories or URLs to every prompt of the session",
+			Trigger:     []string{"context"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        ProjectToolsCommand,
+			Description: "list or run the tools the project declares for the agent",
+			Trigger:     []string{"tools"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        ToolLogCommand,
+			Description: "toggle the live log of running tool calls",
+			Keybindings: parseBindings("<leader>j"),
+			Trigger:     []string{"toollog"},
+		},
+		{
+			Name:        ResponseLanguageCommand,
+			Description: "make the assistant answer in a language, for every session or this one",
+			Trigger:     []string{"language"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        AccessibilityCommand,
+			Description: "accessibility settings, as low-vision zoom",
+			Trigger:     []string{"accessibility"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        PermissionRulesCommand,
+			Description: "permission rules: remembered answers to the agent's requests",
+			Trigger:     []string{"permissions"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        MessagesMenuCommand,
+			Description: "open the menu of the message in view, as a long press does",
+			Trigger:     []string{"menu"},
+		},
+		{
+			Name:        NotifyCommand,
+			Description: "desktop notifications of replies, permissions and errors while the terminal is unfocused",
+			Trigger:     []string{"notify"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        AutoCompactCommand,
+			Description: "when sessions are summarized without asking: on/off, a context threshold, or after idling",
+			Trigger:     []string{"autocompact"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        TimeoutsCommand,
+			Description: "how long to wait on the auth bridge, the server and streams, for every provider or one",
+			Trigger:     []string{"timeouts"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        MessagesEditCommand,
+			Description: "edit the message in view and resend it, dropping later messages, or in a new branch with /edit branch",
+			Trigger:     []string{"edit"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        SplashCommand,
+			Description: "play the startup splash in the provider's colors, or turn it on or off",
+			Trigger:     []string{"splash"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        SessionSummaryCommand,
+			Description: "show the stats of the session or of recent ones, or turn the summary on leaving a session on or off",
+			Trigger:     []string{"summary"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        SplitPaneCommand,
+			Description: "show the newest diff, file or tool output beside the chat on wide terminals",
+			Trigger:     []string{"split"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        SplitFocusCommand,
+			Description: "move focus between the chat and the output pane",
+			Keybindings: parseBindings("<leader>p"),
+		},
+		{
+			Name:        SplitGrowCommand,
+			Description: "widen the output pane",
+			Keybindings: parseBindings("<leader>["),
+		},
+		{
+			Name:        SplitShrinkCommand,
+			Description: "narrow the output pane",
+			Keybindings: parseBindings("<leader>]"),
+		},
+		{
+			Name:        ArtifactsCommand,
+			Description: "browse the scripts, configs and queries saved from answers, save them, or turn saving them on or off",
+			Trigger:     []string{"artifacts"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        VerifyRefactorCommand,
+			Description: "look for leftovers of the last answer's renames and signature changes, or turn doing it after refactors on or off",
+			Trigger:     []string{"verify"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        ColorDepthCommand,
+			Description: "preview the theme in truecolor, 256 and 16 colors, or set the depth it is drawn in",
+			Trigger:     []string{"colors"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        LoopGuardCommand,
+			Description: "set how many repeated failing calls or back-and-forth edits interrupt a run, or turn it off",
+			Trigger:     []string{"loops"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        RunLimitsCommand,
+			Description: "show or set the wall time, tool calls and cost a run may take, for every agent or one agent or command",
+			Trigger:     []string{"limits"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        SessionDatasetCommand,
+			Description: "export sessions as a redacted jsonl fine-tuning dataset, in openai or anthropic style; the session list picks them with space and d",
+			Trigger:     []string{"dataset"},
+			AcceptsArgs: true,
+		},
+		{
+			Name:        HomeDashboardCommand,
+			Description: "show or hide the spend, recent sessions and provider health on the home screen",
+			Trigger:     []string{"dashboard"},
+			AcceptsArgs: true,
+		},
 		{
 			Name:        AppExitCommand,
 			Description: "exit the app",
@@ -392,6 +926,10 @@ func LoadFromConfig(config *opencode.Config, customCommands []opencode.Command)
 			Trigger:     []string{"exit", "quit", "q"},
 		},
 	}
+}
+
+func LoadFromConfig(config *opencode.Config, customCommands []opencode.Command) CommandRegistry {
+	defaults := defaultCommands()
 	registry := make(CommandRegistry)
 	keybinds := map[string]string{}
 	marshalled, _ := json.Marshal(config.Keybinds)
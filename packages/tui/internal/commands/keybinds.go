@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"slices"
+	"strings"
+)
+
+// String returns the keybinding as written in the config, such as
+// <leader>n or ctrl+x
+func (k Keybinding) String() string {
+	if k.RequiresLeader {
+		return "<leader>" + k.Key
+	}
+	return k.Key
+}
+
+// FormatBindings writes keybindings as in the config, where "none" unbinds
+// a command
+func FormatBindings(bindings []Keybinding) string {
+	if len(bindings) == 0 {
+		return "none"
+	}
+	var formatted []string
+	for _, binding := range bindings {
+		formatted = append(formatted, binding.String())
+	}
+	return strings.Join(formatted, ",")
+}
+
+// DefaultKeybindings returns the keybindings a built-in command has when the
+// config doesn't change them
+func DefaultKeybindings(name CommandName) []Keybinding {
+	for _, command := range defaultCommands() {
+		if command.Name == name {
+			return command.Keybindings
+		}
+	}
+	return nil
+}
+
+// Conflicts returns the commands, other than the named one, that a
+// keybinding already triggers. A key pressed after the leader only
+// conflicts with other leader sequences.
+func (r CommandRegistry) Conflicts(name CommandName, binding Keybinding) []Command {
+	var conflicts []Command
+	for _, command := range r.Sorted() {
+		if command.Name != name && slices.Contains(command.Keybindings, binding) {
+			conflicts = append(conflicts, command)
+		}
+	}
+	return conflicts
+}
+
+// Unbind removes a keybinding from the named command
+func (r CommandRegistry) Unbind(name CommandName, binding Keybinding) {
+	command, ok := r[name]
+	if !ok {
+		return
+	}
+	command.Keybindings = slices.DeleteFunc(slices.Clone(command.Keybindings), func(k Keybinding) bool {
+		return k == binding
+	})
+	r[name] = command
+}
+
+// Rebind replaces the keybindings of the named command
+func (r CommandRegistry) Rebind(name CommandName, bindings []Keybinding) {
+	if command, ok := r[name]; ok {
+		command.Keybindings = bindings
+		r[name] = command
+	}
+}
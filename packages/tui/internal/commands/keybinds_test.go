@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestKeybindConflicts(t *testing.T) {
+	registry := CommandRegistry{
+		SessionNewCommand:   {Name: SessionNewCommand, Keybindings: parseBindings("<leader>n")},
+		ModelListCommand:    {Name: ModelListCommand, Keybindings: parseBindings("<leader>m,ctrl+m")},
+		MessagesCopyCommand: {Name: MessagesCopyCommand, Keybindings: parseBindings("n")},
+	}
+
+	// A key after the leader is a different binding than the key alone
+	leaderN := Keybinding{RequiresLeader: true, Key: "n"}
+	if conflicts := registry.Conflicts(ModelListCommand, leaderN); len(conflicts) != 1 || conflicts[0].Name != SessionNewCommand {
+		t.Errorf("conflicts of <leader>n = %v", conflicts)
+	}
+	if conflicts := registry.Conflicts(SessionNewCommand, leaderN); len(conflicts) != 0 {
+		t.Errorf("a command conflicts with itself: %v", conflicts)
+	}
+	if conflicts := registry.Conflicts(SessionNewCommand, Keybinding{Key: "ctrl+m"}); len(conflicts) != 1 || conflicts[0].Name != ModelListCommand {
+		t.Errorf("conflicts of ctrl+m = %v", conflicts)
+	}
+
+	registry.Unbind(ModelListCommand, Keybinding{Key: "ctrl+m"})
+	if got := FormatBindings(registry[ModelListCommand].Keybindings); got != "<leader>m" {
+		t.Errorf("bindings after unbinding = %q", got)
+	}
+	registry.Rebind(SessionNewCommand, nil)
+	if got := FormatBindings(registry[SessionNewCommand].Keybindings); got != "none" {
+		t.Errorf("bindings after clearing = %q", got)
+	}
+}
+
+func TestDefaultKeybindings(t *testing.T) {
+	if got := DefaultKeybindings(AppExitCommand); !slices.Equal(got, parseBindings("ctrl+c,<leader>q")) {
+		t.Errorf("default bindings of app_exit = %v", got)
+	}
+	if got := DefaultKeybindings("custom"); got != nil {
+		t.Errorf("default bindings of a custom command = %v", got)
+	}
+}
+
+func TestCategory(t *testing.T) {
+	tests := []struct {
+		command Command
+		want    string
+	}{
+		{Command{Name: SessionCompactCommand}, CategorySessions},
+		{Command{Name: SwitchAgentReverseCommand}, CategoryModels},
+		{Command{Name: ToolDetailsCommand}, CategoryMessages},
+		{Command{Name: AppShortcutsCommand}, CategoryApp},
+		{Command{Name: DocgenCommand}, CategoryTools},
+		{Command{Name: "review", Custom: true}, CategoryCustom},
+		{Command{Name: "jira:issue", Plugin: "jira"}, CategoryPlugins},
+	}
+	for _, tt := range tests {
+		if got := Category(tt.command); got != tt.want {
+			t.Errorf("Category(%s) = %q, want %q", tt.command.Name, got, tt.want)
+		}
+	}
+}
+
+func TestMatchesQuery(t *testing.T) {
+	command := Command{
+		Name:        SessionCompactCommand,
+		Description: "compact the session",
+		Keybindings: parseBindings("<leader>c"),
+		Trigger:     []string{"compact", "summarize"},
+	}
+	for query, want := range map[string]bool{
+		"":                 true,
+		"COMPACT":          true,
+		"/summ":            true,
+		"<leader>c":        true,
+		"sessions compact": true,
+		"compact model":    false,
+	} {
+		if got := command.MatchesQuery(query); got != want {
+			t.Errorf("MatchesQuery(%q) = %v, want %v", query, got, want)
+		}
+	}
+}
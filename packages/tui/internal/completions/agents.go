@@ -1,10 +1,10 @@
 package completions
 
 import (
-	"context"
-	"log/slog"
+	"sort"
 	"strings"
 
+	"github.com/lithammer/fuzzysearch/fuzzy"
 	"github.com/aaronmrosenthal/rycode-sdk-go"
 	"github.com/aaronmrosenthal/rycode/internal/app"
 	"github.com/aaronmrosenthal/rycode/internal/styles"
@@ -23,37 +23,52 @@ func (cg *agentsContextGroup) GetEmptyMessage() string {
 	return "no matching agents"
 }
 
+// GetChildEntries lists the subagents that can be mentioned, fuzzy matched
+// against their names and ranked with prefix matches first
 func (cg *agentsContextGroup) GetChildEntries(
 	query string,
 ) ([]CompletionSuggestion, error) {
-	items := make([]CompletionSuggestion, 0)
-
-	query = strings.TrimSpace(query)
-
-	agents, err := cg.app.Client.Agent.List(
-		context.Background(),
-		opencode.AgentListParams{},
-	)
-	if err != nil {
-		slog.Error("Failed to get agent list", "error", err)
-		return items, err
-	}
-	if agents == nil {
-		return items, nil
-	}
-
-	for _, agent := range *agents {
-		if query != "" && !strings.Contains(strings.ToLower(agent.Name), strings.ToLower(query)) {
-			continue
-		}
+	var agents []opencode.Agent
+	var names []string
+	for _, agent := range cg.app.Agents {
 		if agent.Mode == opencode.AgentModePrimary {
 			continue
 		}
+		agents = append(agents, agent)
+		names = append(names, agent.Name)
+	}
+
+	query = strings.TrimSpace(query)
+	if query != "" {
+		matches := fuzzy.RankFindFold(query, names)
+		sort.SliceStable(matches, func(i, j int) bool {
+			iPrefix := strings.HasPrefix(strings.ToLower(matches[i].Target), strings.ToLower(query))
+			jPrefix := strings.HasPrefix(strings.ToLower(matches[j].Target), strings.ToLower(query))
+			if iPrefix != jPrefix {
+				return iPrefix
+			}
+			if matches[i].Distance != matches[j].Distance {
+				return matches[i].Distance < matches[j].Distance
+			}
+			return matches[i].OriginalIndex < matches[j].OriginalIndex
+		})
+		ranked := make([]opencode.Agent, 0, len(matches))
+		for _, match := range matches {
+			ranked = append(ranked, agents[match.OriginalIndex])
+		}
+		agents = ranked
+	}
 
+	items := make([]CompletionSuggestion, 0, len(agents))
+	for _, agent := range agents {
 		displayFunc := func(s styles.Style) string {
 			t := theme.CurrentTheme()
 			muted := s.Foreground(t.TextMuted()).Render
-			return s.Render(agent.Name) + muted(" (agent)")
+			display := s.Render(agent.Name) + muted(" (agent)")
+			if agent.Description != "" {
+				display += muted("  " + strings.Join(strings.Fields(agent.Description), " "))
+			}
+			return display
 		}
 
 		item := CompletionSuggestion{
@@ -0,0 +1,43 @@
+package completions
+
+import (
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+)
+
+func TestAgentsContextGroup(t *testing.T) {
+	a := &app.App{Agents: []opencode.Agent{
+		{Name: "build", Mode: opencode.AgentModePrimary},
+		{Name: "code-reviewer", Mode: opencode.AgentModeSubagent},
+		{Name: "general", Mode: opencode.AgentModeSubagent},
+		{Name: "docs-writer", Mode: opencode.AgentModeAll},
+	}}
+	group := NewAgentsContextGroup(a)
+
+	values := func(query string) []string {
+		items, err := group.GetChildEntries(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var values []string
+		for _, item := range items {
+			values = append(values, item.Value)
+		}
+		return values
+	}
+	if got := values(""); len(got) != 3 {
+		t.Errorf("agents without a query = %v, want the three that aren't primary", got)
+	}
+	if got := values("rev"); len(got) != 1 || got[0] != "code-reviewer" {
+		t.Errorf("agents for rev = %v", got)
+	}
+	// Prefix matches rank first
+	if got := values("gen"); len(got) == 0 || got[0] != "general" {
+		t.Errorf("agents for gen = %v", got)
+	}
+	if got := values("dwr"); len(got) == 0 || got[0] != "docs-writer" {
+		t.Errorf("agents for dwr = %v, want docs-writer first", got)
+	}
+}
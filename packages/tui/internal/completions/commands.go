@@ -38,6 +38,10 @@ func (c *CommandCompletionProvider) getCommandCompletionItem(
 		display := "  /" + cmd.PrimaryTrigger() + s.
 			Foreground(t.TextMuted()).
 			Render(spacer+cmd.Description)
+		if cmd.Custom {
+			// The project's own commands, from its configuration
+			display += s.Foreground(t.Accent()).Render(" (custom)")
+		}
 		return display
 	}
 
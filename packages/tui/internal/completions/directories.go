@@ -0,0 +1,87 @@
+package completions
+
+import (
+	"context"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/lithammer/fuzzysearch/fuzzy"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+)
+
+// directoriesProvider completes the directory of /cd, relative to the
+// directory the session's shell commands run in
+type directoriesProvider struct {
+	app *app.App
+}
+
+func (p *directoriesProvider) GetId() string {
+	return "directories"
+}
+
+func (p *directoriesProvider) GetEmptyMessage() string {
+	return "no matching directories"
+}
+
+// GetChildEntries lists the directories in the one the query's path leads
+// to, matching its last element
+func (p *directoriesProvider) GetChildEntries(query string) ([]CompletionSuggestion, error) {
+	query = strings.TrimSpace(query)
+	parent, name := path.Split(query)
+	dir := path.Join(p.app.SessionDir(), parent)
+	if strings.HasPrefix(parent, "/") {
+		dir = path.Join(".", parent)
+	}
+	nodes, err := p.app.Client.File.List(context.Background(), opencode.FileListParams{
+		Path: opencode.F(dir),
+	})
+	if err != nil || nodes == nil {
+		// The path typed leads nowhere yet
+		slog.Debug("Failed to list directories", "dir", dir, "error", err)
+		nodes = &[]opencode.FileNode{}
+	}
+
+	var names []string
+	for _, node := range *nodes {
+		if node.Type == opencode.FileNodeTypeDirectory && !node.Ignored {
+			names = append(names, node.Name)
+		}
+	}
+	if name != "" {
+		matches := fuzzy.RankFindFold(name, names)
+		sort.Sort(matches)
+		names = names[:0]
+		for _, match := range matches {
+			names = append(names, match.Target)
+		}
+	} else {
+		sort.Strings(names)
+	}
+
+	items := make([]CompletionSuggestion, 0, len(names)+1)
+	for _, name := range names {
+		items = append(items, p.item(parent+name+"/"))
+	}
+	if len(items) == 0 && query != "" {
+		// Such as .., which is no directory in the list but can be
+		// changed to
+		items = append(items, p.item(query))
+	}
+	return items, nil
+}
+
+func (p *directoriesProvider) item(value string) CompletionSuggestion {
+	return CompletionSuggestion{
+		Display:    func(s styles.Style) string { return s.Render(value) },
+		Value:      value,
+		ProviderID: p.GetId(),
+	}
+}
+
+func NewDirectoriesProvider(app *app.App) CompletionProvider {
+	return &directoriesProvider{app: app}
+}
@@ -85,7 +85,14 @@ func (cg *filesContextGroup) GetChildEntries(
 		return items, nil
 	}
 
-	for _, file := range *files {
+	// Files of the directory the session works in come first
+	found := *files
+	if dir := cg.app.SessionDir(); dir != "" && query != "" {
+		sort.SliceStable(found, func(i, j int) bool {
+			return strings.HasPrefix(found[i], dir+"/") && !strings.HasPrefix(found[j], dir+"/")
+		})
+	}
+	for _, file := range found {
 		exists := false
 		for _, existing := range cg.gitFiles {
 			if existing.Value == file {
@@ -0,0 +1,200 @@
+package chat
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// block is a rendered message part, or one that is rendered only once it
+// comes into view when its height is known from an earlier rendering
+type block struct {
+	content string
+	height  int
+	render  func() string // Renders the content, nil once it is rendered
+}
+
+// renderedBlock returns a block of rendered content
+func renderedBlock(content string) block {
+	return block{content: content, height: strings.Count(content, "\n") + 1}
+}
+
+// empty reports whether the block has nothing to show
+func (b block) empty() bool {
+	return b.render == nil && b.content == ""
+}
+
+// rendered returns the block's content, rendering it if it hasn't been
+func (b block) rendered() string {
+	if b.render != nil {
+		return b.render()
+	}
+	return b.content
+}
+
+// cachedBlock returns the block of a message part cached under key. A part
+// that was rendered before at its height is rendered again only when it
+// comes into view, which keeps redraws after a theme change or a session
+// switch to the parts on screen.
+func (m *messagesComponent) cachedBlock(key string, render func() string) block {
+	if content, ok := m.cache.Get(key); ok {
+		return renderedBlock(content)
+	}
+	cache := m.cache
+	cached := func() string {
+		content := render()
+		cache.Set(key, content)
+		return content
+	}
+	if height, ok := cache.Height(key); ok {
+		return block{height: height, render: cached}
+	}
+	return renderedBlock(cached())
+}
+
+// blockList is the message list, used as the viewport's content. Blocks
+// are only rendered and split into lines when they come into view, so
+// redrawing and scrolling a long session costs the same as a short one.
+type blockList struct {
+	blocks []block
+	starts []int // Line each block starts on
+	total  int
+	split  map[int][]string // Lines of the blocks that have been in view
+}
+
+// newBlockList lays out blocks one after another, with a blank line before
+// the first block and after each block
+func newBlockList(blocks []block) *blockList {
+	l := &blockList{
+		blocks: blocks,
+		starts: make([]int, len(blocks)),
+		total:  1,
+		split:  make(map[int][]string),
+	}
+	for i, block := range blocks {
+		l.starts[i] = l.total
+		l.total += block.height + 1
+	}
+	return l
+}
+
+// LineCount returns the number of lines of all blocks
+func (l *blockList) LineCount() int {
+	return l.total
+}
+
+// Lines returns the lines from index from up to, but not including, to
+func (l *blockList) Lines(from, to int) []string {
+	from, to = max(0, from), min(to, l.total)
+	if from >= to {
+		return nil
+	}
+	lines := make([]string, 0, to-from)
+	y := from
+	if y == 0 {
+		lines = append(lines, "")
+		y++
+	}
+	// The last block starting at or before the first line
+	i := sort.Search(len(l.starts), func(i int) bool { return l.starts[i] > y }) - 1
+	for ; i >= 0 && i < len(l.blocks) && y < to; i++ {
+		block := l.blockLines(i)
+		for j := y - l.starts[i]; j < len(block) && y < to; j++ {
+			lines = append(lines, block[j])
+			y++
+		}
+	}
+	return lines
+}
+
+// content returns the rendered content of a block, rendering it first if
+// it hasn't been
+func (l *blockList) content(i int) string {
+	b := &l.blocks[i]
+	if b.render != nil {
+		b.content, b.render = b.rendered(), nil
+	}
+	return b.content
+}
+
+// blockLines returns the lines of a block followed by the blank line after
+// it. A block rendered to another height than the one it was laid out with
+// is cut or padded to it, keeping the blocks after it in place.
+func (l *blockList) blockLines(i int) []string {
+	if lines, ok := l.split[i]; ok {
+		return lines
+	}
+	lines := strings.Split(l.content(i), "\n")
+	height := l.blocks[i].height
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	lines = append(lines[:height], "")
+	l.split[i] = lines
+	return lines
+}
+
+// blockAt returns the block a line belongs to, the blank line after it
+// included, or -1 for the blank line above the first block
+func (l *blockList) blockAt(y int) int {
+	return sort.Search(len(l.starts), func(i int) bool { return l.starts[i] > y }) - 1
+}
+
+// textLine reports whether a line holds text of a block, rather than its
+// border or the blank line between blocks
+func (l *blockList) textLine(y int) bool {
+	i := l.blockAt(y)
+	if i < 0 {
+		return false
+	}
+	return y > l.starts[i] && y < l.starts[i]+l.blocks[i].height-1
+}
+
+// selectText highlights the selected text of the blocks a selection spans
+// and returns it, both plain and with its styling. The selection's lines are
+// counted from the first block, without the blank line above it. The
+// borders of blocks are never selected.
+func (l *blockList) selectText(sel *selection, highlight func(string) string) (clipboard, styled []string) {
+	for i, block := range l.blocks {
+		first := l.starts[i] - 1
+		after := first + block.height
+		if after < sel.startY || first > sel.endY {
+			continue
+		}
+		lines := slices.Clone(l.blockLines(i)[:block.height])
+
+		for index, line := range lines {
+			y := first + index
+			if index == 0 || index == len(lines)-1 || y < sel.startY || y > sel.endY {
+				continue
+			}
+			left := 3
+			if y == sel.startY {
+				left = sel.startX - 2
+			}
+			left = max(3, left)
+
+			width := ansi.StringWidth(line)
+			right := width - 1
+			if y == sel.endY {
+				right = min(sel.endX-2, right)
+			}
+
+			prefix := ansi.Cut(line, 0, left)
+			middle := strings.TrimRight(ansi.Strip(ansi.Cut(line, left, right)), " ")
+			suffix := ansi.Cut(line, left+ansi.StringWidth(middle), width)
+			clipboard = append(clipboard, middle)
+			styled = append(styled, ansi.Cut(line, left, left+ansi.StringWidth(middle))+ansi.ResetStyle)
+			lines[index] = prefix + highlight(ansi.Strip(middle)) + suffix
+		}
+		if after >= sel.startY && after < sel.endY {
+			clipboard = append(clipboard, "")
+			styled = append(styled, "")
+		}
+		l.blocks[i].content = strings.Join(lines, "\n")
+		delete(l.split, i)
+	}
+	return clipboard, styled
+}
@@ -0,0 +1,91 @@
+package chat
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func renderedBlocks(contents ...string) []block {
+	blocks := make([]block, len(contents))
+	for i, content := range contents {
+		blocks[i] = renderedBlock(content)
+	}
+	return blocks
+}
+
+func TestBlockListLines(t *testing.T) {
+	blocks := []string{"a1\na2\na3", "b1", "c1\nc2"}
+	list := newBlockList(renderedBlocks(blocks...))
+	want := strings.Split("\n"+strings.Join(blocks, "\n\n")+"\n", "\n")
+
+	if list.LineCount() != len(want) {
+		t.Fatalf("LineCount = %d, want %d", list.LineCount(), len(want))
+	}
+	for from := 0; from <= len(want); from++ {
+		for to := from; to <= len(want)+2; to++ {
+			got := list.Lines(from, to)
+			if expected := want[from:min(to, len(want))]; !slices.Equal(got, expected) && len(expected) > 0 {
+				t.Errorf("Lines(%d, %d) = %q, want %q", from, to, got, expected)
+			}
+		}
+	}
+	if lines := newBlockList(nil).Lines(0, 10); !slices.Equal(lines, []string{""}) {
+		t.Errorf("empty list lines = %q", lines)
+	}
+}
+
+func TestBlockListSelectText(t *testing.T) {
+	border := "│    │"
+	list := newBlockList(renderedBlocks(
+		border+"\n│  hello world │\n"+border,
+		border+"\n│  second      │\n"+border,
+	))
+	mark := func(s string) string { return "[" + s + "]" }
+	// Lines 1 and 5 hold the text, counted from the first block
+	clipboard, _ := list.selectText(&selection{startX: 0, startY: 1, endX: 100, endY: 5}, mark)
+	if !slices.Equal(clipboard, []string{"hello world", "", "second"}) {
+		t.Errorf("clipboard = %q", clipboard)
+	}
+	if line := list.Lines(2, 3)[0]; !strings.Contains(line, "[hello world]") {
+		t.Errorf("selected line = %q", line)
+	}
+	if line := list.Lines(1, 2)[0]; line != border {
+		t.Errorf("border = %q, want it unselected", line)
+	}
+}
+
+func TestBlockListTextLine(t *testing.T) {
+	// Lines: blank, top border, text, bottom border, blank, top, text, text, bottom, blank
+	list := newBlockList(renderedBlocks("┌\n│ a\n└", "┌\n│ b\n│ c\n└"))
+	var text []int
+	for y := -1; y <= list.LineCount(); y++ {
+		if list.textLine(y) {
+			text = append(text, y)
+		}
+	}
+	if !slices.Equal(text, []int{2, 6, 7}) {
+		t.Errorf("text lines = %v, want [2 6 7]", text)
+	}
+}
+
+func TestBlockListRendersBlocksInView(t *testing.T) {
+	var rendered []string
+	lazy := func(content string) block {
+		return block{height: strings.Count(content, "\n") + 1, render: func() string {
+			rendered = append(rendered, content)
+			return content
+		}}
+	}
+	// A block rendered taller than it was laid out is cut to its height
+	list := newBlockList([]block{lazy("a1\na2"), lazy("b1\nb2\nb3"), {height: 1, render: func() string { return "c1\nc2" }}})
+	if list.LineCount() != 10 || len(rendered) != 0 {
+		t.Fatalf("LineCount = %d and rendered %q, want 10 and nothing", list.LineCount(), rendered)
+	}
+	if lines := list.Lines(4, 6); !slices.Equal(lines, []string{"b1", "b2"}) || !slices.Equal(rendered, []string{"b1\nb2\nb3"}) {
+		t.Errorf("Lines(4, 6) = %q, rendering %q", lines, rendered)
+	}
+	if lines := list.Lines(8, 10); !slices.Equal(lines, []string{"c1", ""}) {
+		t.Errorf("Lines(8, 10) = %q, want the block cut to its height", lines)
+	}
+}
@@ -4,19 +4,26 @@ import (
 	"encoding/hex"
 	"fmt"
 	"hash/fnv"
+	"strings"
 	"sync"
+
+	"github.com/aaronmrosenthal/rycode/internal/util"
 )
 
 // PartCache caches rendered messages to avoid re-rendering
 type PartCache struct {
-	mu    sync.RWMutex
-	cache map[string]string
+	mu      sync.RWMutex
+	cache   map[string]string
+	heights map[string]int                  // Line counts of rendered messages, kept across clears
+	streams map[string]*util.MarkdownStream // Renderers of the parts being streamed, by part ID
 }
 
 // NewPartCache creates a new message cache
 func NewPartCache() *PartCache {
 	return &PartCache{
-		cache: make(map[string]string),
+		cache:   make(map[string]string),
+		heights: make(map[string]int),
+		streams: make(map[string]*util.MarkdownStream),
 	}
 }
 
@@ -43,6 +50,19 @@ func (c *PartCache) Set(key string, content string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.cache[key] = content
+	if content != "" {
+		c.heights[key] = strings.Count(content, "\n") + 1
+	}
+}
+
+// Height returns the line count of a message rendered before, which
+// outlives Clear as the key holds everything the rendering depends on
+func (c *PartCache) Height(key string) (int, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	height, exists := c.heights[key]
+	return height, exists
 }
 
 // Clear removes all entries from the cache
@@ -51,6 +71,26 @@ func (c *PartCache) Clear() {
 	defer c.mu.Unlock()
 
 	c.cache = make(map[string]string)
+	c.streams = make(map[string]*util.MarkdownStream)
+}
+
+// Stream returns the renderer of a part's markdown while it is streamed
+func (c *PartCache) Stream(partID string) *util.MarkdownStream {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stream, ok := c.streams[partID]
+	if !ok {
+		stream = &util.MarkdownStream{}
+		c.streams[partID] = stream
+	}
+	return stream
+}
+
+// EndStream drops the renderer of a part once it is complete
+func (c *PartCache) EndStream(partID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.streams, partID)
 }
 
 // Size returns the number of cached entries
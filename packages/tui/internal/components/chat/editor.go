@@ -4,10 +4,11 @@ import (
 	"encoding/base64"
 	"fmt"
 	"log/slog"
-	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/v2/spinner"
@@ -22,11 +23,16 @@ import (
 	"github.com/aaronmrosenthal/rycode/internal/components/dialog"
 	"github.com/aaronmrosenthal/rycode/internal/components/textarea"
 	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/remote"
 	"github.com/aaronmrosenthal/rycode/internal/styles"
 	"github.com/aaronmrosenthal/rycode/internal/theme"
 	"github.com/aaronmrosenthal/rycode/internal/util"
 )
 
+// compactingStuckHint is how long compacting goes on before the hint
+// suggests /unbusy
+const compactingStuckHint = 2 * time.Minute
+
 type EditorComponent interface {
 	tea.Model
 	tea.ViewModel
@@ -34,6 +40,7 @@ type EditorComponent interface {
 	Cursor() *tea.Cursor
 	Lines() int
 	Value() string
+	ExpandedValue() string
 	Length() int
 	Focused() bool
 	Focus() (tea.Model, tea.Cmd)
@@ -45,9 +52,18 @@ type EditorComponent interface {
 	Newline() (tea.Model, tea.Cmd)
 	SetValue(value string)
 	SetValueWithAttachments(value string)
+	InsertText(text string)
+	InsertAtCursor(text string)
 	SetInterruptKeyInDebounce(inDebounce bool)
 	SetExitKeyInDebounce(inDebounce bool)
 	RestoreFromHistory(index int)
+	SearchHistory()
+	HistorySearching() bool
+	AttachFile(path string)
+	AttachSelection(path string, startLine, endLine int, text string)
+	SetVim(enabled bool)
+	VimKey(msg tea.KeyPressMsg) bool
+	AtWordStart() bool
 }
 
 type editorComponent struct {
@@ -57,8 +73,10 @@ type editorComponent struct {
 	spinner                spinner.Model
 	interruptKeyInDebounce bool
 	exitKeyInDebounce      bool
-	historyIndex           int    // -1 means current (not in history)
-	currentText            string // Store current text when navigating history
+	historyIndex           int          // -1 means current (not in history)
+	currentText            string       // Store current text when navigating history
+	history                []app.Prompt // Prompts navigated, taken when the navigation starts
+	search                 *historySearch
 	pasteCounter           int
 	reverted               bool
 }
@@ -79,19 +97,30 @@ func (m *editorComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.spinner, cmd = m.spinner.Update(msg)
 		return m, cmd
 	case tea.KeyPressMsg:
+		if m.search != nil {
+			return m, m.historySearchKey(msg)
+		}
+		if m.textarea.VimKey(msg) {
+			m.textarea, cmd = m.textarea.Update(msg)
+			m.app.VimMode = m.textarea.VimMode()
+			return m, cmd
+		}
 		// Handle up/down arrows and ctrl+p/ctrl+n for history navigation
 		switch msg.String() {
 		case "up", "ctrl+p":
 			// Only navigate history if cursor is at the first line and column (for arrow keys)
 			// or allow ctrl+p from anywhere
-			if (msg.String() == "ctrl+p" || (m.textarea.Line() == 0 && m.textarea.CursorColumn() == 0)) && len(m.app.State.MessageHistory) > 0 {
+			if m.historyIndex == -1 {
+				m.history = m.app.History()
+			}
+			if (msg.String() == "ctrl+p" || (m.textarea.Line() == 0 && m.textarea.CursorColumn() == 0)) && len(m.history) > 0 {
 				if m.historyIndex == -1 {
 					// Save current text before entering history
 					m.currentText = m.textarea.Value()
 					m.textarea.MoveToBegin()
 				}
 				// Move up in history (older messages)
-				if m.historyIndex < len(m.app.State.MessageHistory)-1 {
+				if m.historyIndex < len(m.history)-1 {
 					m.historyIndex++
 					m.RestoreFromHistory(m.historyIndex)
 					m.textarea.MoveToBegin()
@@ -145,6 +174,14 @@ func (m *editorComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 		}
+	case app.MessageBranchedMsg:
+		prompt, err := msg.Message.ToPrompt()
+		if err != nil {
+			return m, toast.NewErrorToast("Failed to branch from the message")
+		}
+		m.RestoreFromPrompt(*prompt)
+		m.textarea.MoveToEnd()
+		return m, nil
 	case app.SessionUnrevertedMsg:
 		if msg.Session.ID == m.app.Session.ID {
 			if m.reverted {
@@ -162,7 +199,7 @@ func (m *editorComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if !filepath.IsAbs(filePath) {
 				statPath = filepath.Join(util.CwdPath, filePath)
 			}
-			if _, err := os.Stat(statPath); err == nil {
+			if _, err := remote.Stat(statPath); err == nil {
 				attachment := m.createAttachmentFromPath(filePath)
 				if attachment != nil {
 					m.textarea.InsertAttachment(attachment)
@@ -184,7 +221,7 @@ func (m *editorComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		}
-		if _, err := os.Stat(text); err != nil {
+		if _, err := remote.Stat(text); err != nil {
 			slog.Error("Failed to paste file", "error", err)
 			text := string(msg)
 			if m.shouldSummarizePastedText(text) {
@@ -225,7 +262,7 @@ func (m *editorComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.Item.ProviderID {
 		case "commands":
 			command := msg.Item.RawData.(commands.Command)
-			if command.Custom {
+			if command.Custom || command.AcceptsArgs {
 				m.SetValue("/" + command.PrimaryTrigger() + " ")
 				return m, nil
 			}
@@ -237,6 +274,9 @@ func (m *editorComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			commandName := strings.TrimPrefix(msg.Item.Value, "/")
 			cmds = append(cmds, util.CmdHandler(commands.ExecuteCommandMsg(m.app.Commands[commands.CommandName(commandName)])))
 			return m, tea.Batch(cmds...)
+		case "directories":
+			m.SetValue("/" + m.app.Commands[commands.SessionDirCommand].PrimaryTrigger() + " " + msg.Item.Value)
+			return m, nil
 		case "files":
 			atIndex := m.textarea.LastRuneIndex('@')
 			if atIndex == -1 {
@@ -366,6 +406,10 @@ func (m *editorComponent) Content() string {
 		borderForeground = t.Secondary()
 		promptIcon = "$"
 	}
+	if m.search != nil {
+		borderForeground = t.Accent()
+		promptIcon = "⌕"
+	}
 
 	prompt := promptIconStyle.Render(promptIcon) + promptTextStyle.Render(promptText)
 	prompt = styles.NewStyle().PaddingLeft(1).PaddingRight(0).Render(prompt)
@@ -390,26 +434,47 @@ func (m *editorComponent) Content() string {
 		Render(textarea)
 
 	hint := base(m.getSubmitKeyText()) + muted(" send")
-	if m.exitKeyInDebounce {
+	if m.search != nil {
+		hint = m.historySearchHint()
+	} else if m.exitKeyInDebounce {
 		keyText := m.getExitKeyText()
 		hint = base(keyText+" again") + muted(" to exit")
+	} else if elapsed, recording := m.app.VoiceRecording(); recording {
+		recordingStyle := styles.NewStyle().Foreground(t.Error()).Background(t.Background()).Bold(true)
+		hint = recordingStyle.Render("● recording") + muted(fmt.Sprintf(" %ds  ", int(elapsed.Seconds()))) +
+			base(m.app.Keybind(commands.VoiceCommand)) + muted(" stop")
+	} else if m.app.VoiceTranscribing() {
+		hint = muted("transcribing") + " " + m.spinner.View()
 	} else if m.app.IsBusy() {
 		keyText := m.getInterruptKeyText()
-		status := "working"
-		if m.app.IsCompacting() {
-			status = "compacting"
+		status := muted("working")
+		elapsed, compacting := m.app.CompactingFor()
+		if compacting {
+			compactingStyle := styles.NewStyle().Foreground(t.Warning()).Background(t.Background()).Bold(true)
+			label := "◐ compacting"
+			if reason := m.app.CompactionReason(); reason != "" {
+				label = "◐ auto-compacting (" + reason + ")"
+			}
+			status = compactingStyle.Render(label) + muted(fmt.Sprintf(" %ds", int(elapsed.Seconds())))
 		}
 		if m.app.CurrentPermission.ID != "" {
-			status = "waiting for permission"
+			status = muted("waiting for permission")
 		}
 		if m.interruptKeyInDebounce && m.app.CurrentPermission.ID == "" {
-			hint = muted(status) + " " + m.spinner.View() + " " + base(keyText+" again") + muted(" interrupt")
+			hint = status + " " + m.spinner.View() + " " + base(keyText+" again") + muted(" interrupt")
 		} else {
-			hint = muted(status) + " " + m.spinner.View()
+			hint = status + " " + m.spinner.View()
 			if m.app.CurrentPermission.ID == "" {
 				hint += muted("  ") + base(keyText) + muted(" interrupt")
 			}
 		}
+		if compacting && elapsed > compactingStuckHint {
+			hint += muted("  /unbusy if stuck")
+		}
+	}
+
+	if confidence := m.app.VoiceConfidence(); confidence > 0 && m.search == nil && m.Length() > 0 {
+		hint += muted(fmt.Sprintf("  🎤 %.0f%% confident", confidence*100))
 	}
 
 	// Model info removed - it's shown in the status bar to avoid duplication
@@ -480,6 +545,18 @@ func (m *editorComponent) Value() string {
 	return m.textarea.Value()
 }
 
+// ExpandedValue returns the prompt with the pasted text its attachments
+// summarize written out
+func (m *editorComponent) ExpandedValue() string {
+	value := m.Value()
+	for _, att := range m.textarea.GetAttachments() {
+		if source, ok := att.GetTextSource(); ok && strings.HasPrefix(att.Filename, "pasted-text-") {
+			value = strings.Replace(value, att.Display, source.Value, 1)
+		}
+	}
+	return value
+}
+
 func (m *editorComponent) Length() int {
 	return m.textarea.Length()
 }
@@ -535,13 +612,26 @@ func (m *editorComponent) Submit() (tea.Model, tea.Cmd) {
 
 			return m, tea.Batch(cmds...)
 		}
+
+		if command, ok := m.app.Commands.FindByTrigger(commandName); ok && command.AcceptsArgs {
+			args := strings.TrimSpace(strings.TrimPrefix(expandedValue, commandName))
+			cmds = append(
+				cmds,
+				util.CmdHandler(commands.ExecuteCommandArgsMsg{Command: command, Args: args}),
+			)
+
+			updated, cmd := m.Clear()
+			m = updated.(*editorComponent)
+			cmds = append(cmds, cmd)
+
+			return m, tea.Batch(cmds...)
+		}
 	}
 
 	attachments := m.textarea.GetAttachments()
 
 	prompt := app.Prompt{Text: value, Attachments: attachments}
-	m.app.State.AddPromptToHistory(prompt)
-	cmds = append(cmds, m.app.SaveState())
+	cmds = append(cmds, m.app.AddToHistory(prompt))
 
 	updated, cmd := m.Clear()
 	m = updated.(*editorComponent)
@@ -563,12 +653,33 @@ func (m *editorComponent) SubmitBash() (tea.Model, tea.Cmd) {
 
 func (m *editorComponent) Clear() (tea.Model, tea.Cmd) {
 	m.textarea.Reset()
+	m.textarea.VimInsert()
+	m.app.VimMode = m.textarea.VimMode()
 	m.historyIndex = -1
 	m.currentText = ""
+	m.search = nil
 	m.pasteCounter = 0
+	m.app.ClearVoiceConfidence()
 	return m, nil
 }
 
+// SetVim turns the vim keybindings of the prompt on or off
+func (m *editorComponent) SetVim(enabled bool) {
+	m.textarea.SetVim(enabled)
+	m.app.VimMode = m.textarea.VimMode()
+}
+
+// VimKey reports whether the vim keybindings take a key, before the
+// completions and commands do
+func (m *editorComponent) VimKey(msg tea.KeyPressMsg) bool {
+	return m.textarea.VimKey(msg)
+}
+
+// AtWordStart reports whether the cursor is where a mention can begin
+func (m *editorComponent) AtWordStart() bool {
+	return m.textarea.AtWordStart()
+}
+
 func (m *editorComponent) Paste() (tea.Model, tea.Cmd) {
 	imageBytes := clipboard.Read(clipboard.FmtImage)
 	if imageBytes != nil {
@@ -623,10 +734,21 @@ func (m *editorComponent) SetValue(value string) {
 }
 
 func (m *editorComponent) SetValueWithAttachments(value string) {
+	// The prompt's attachments are kept where their text is still found, as
+	// images, which can't be made again from it
+	kept := m.textarea.GetAttachments()
 	m.textarea.Reset()
 
 	i := 0
 	for i < len(value) {
+		if j := slices.IndexFunc(kept, func(att *attachment.Attachment) bool {
+			return att.Display != "" && strings.HasPrefix(value[i:], att.Display)
+		}); j >= 0 {
+			m.textarea.InsertAttachment(kept[j])
+			i += len(kept[j].Display)
+			kept = slices.Delete(kept, j, j+1)
+			continue
+		}
 		r, size := utf8.DecodeRuneInString(value[i:])
 		// Check if filepath and add attachment
 		if r == '@' {
@@ -642,7 +764,7 @@ func (m *editorComponent) SetValueWithAttachments(value string) {
 			if end > start {
 				filePath := value[start:end]
 				slog.Debug("test", "filePath", filePath)
-				if _, err := os.Stat(filepath.Join(util.CwdPath, filePath)); err == nil {
+				if _, err := remote.Stat(filepath.Join(util.CwdPath, filePath)); err == nil {
 					slog.Debug("test", "found", true)
 					attachment := m.createAttachmentFromFile(filePath)
 					if attachment != nil {
@@ -660,6 +782,20 @@ func (m *editorComponent) SetValueWithAttachments(value string) {
 	}
 }
 
+// InsertText types text at the cursor, apart from the word before it, as
+// a transcript of voice input is
+func (m *editorComponent) InsertText(text string) {
+	if value := m.Value(); value != "" && !strings.HasSuffix(value, " ") && !strings.HasSuffix(value, "\n") {
+		text = " " + text
+	}
+	m.textarea.InsertRunesFromUserInput([]rune(text))
+}
+
+// InsertAtCursor types text at the cursor as it is
+func (m *editorComponent) InsertAtCursor(text string) {
+	m.textarea.InsertRunesFromUserInput([]rune(text))
+}
+
 func (m *editorComponent) SetExitKeyInDebounce(inDebounce bool) {
 	m.exitKeyInDebounce = inDebounce
 }
@@ -754,6 +890,10 @@ func updateTextareaStyles(ta textarea.Model) textarea.Model {
 		Foreground(t.Text()).
 		Background(t.Secondary()).
 		Lipgloss()
+	ta.Styles.Selection = styles.NewStyle().
+		Foreground(t.Text()).
+		Background(t.BorderActive()).
+		Lipgloss()
 
 	// Clean, standard terminal cursor - solid block like Claude
 	ta.Styles.Cursor.Shape = tea.CursorBlock
@@ -782,11 +922,13 @@ func NewEditorComponent(app *app.App) EditorComponent {
 
 	ta := textarea.New()
 	ta.Prompt = " "
-	ta.Placeholder = "Ask me anything... (or type / for commands)"
+	ta.Placeholder = "Ask me anything... (@ to mention files or agents, / for commands)"
 	ta.ShowLineNumbers = false
 	ta.CharLimit = -1
-	ta.VirtualCursor = false  // Use REAL cursor for clean UX
+	ta.VirtualCursor = false // Use REAL cursor for clean UX
 	ta = updateTextareaStyles(ta)
+	ta.SetVim(app.State.VimMode)
+	app.VimMode = ta.VimMode()
 
 	m := &editorComponent{
 		app:                    app,
@@ -826,10 +968,10 @@ func (m *editorComponent) RestoreFromPrompt(prompt app.Prompt) {
 
 // RestoreFromHistory restores a message from history at the given index
 func (m *editorComponent) RestoreFromHistory(index int) {
-	if index < 0 || index >= len(m.app.State.MessageHistory) {
+	if index < 0 || index >= len(m.history) {
 		return
 	}
-	entry := m.app.State.MessageHistory[index]
+	entry := m.history[index]
 	m.RestoreFromPrompt(entry)
 }
 
@@ -846,6 +988,34 @@ func getMediaTypeFromExtension(ext string) string {
 	}
 }
 
+// AttachFile adds a file to the prompt, as if it was completed with @
+func (m *editorComponent) AttachFile(path string) {
+	if attachment := m.createAttachmentFromFile(path); attachment != nil {
+		m.textarea.InsertAttachment(attachment)
+		m.textarea.InsertString(" ")
+	}
+}
+
+// AttachSelection adds lines of a file to the prompt. The text is sent in
+// the prompt, as the editor may hold changes that aren't saved.
+func (m *editorComponent) AttachSelection(path string, startLine, endLine int, text string) {
+	display := fmt.Sprintf("@%s:%d-%d", path, startLine, endLine)
+	value := fmt.Sprintf("%s (lines %d-%d):\n```%s\n%s\n```\n", path, startLine, endLine,
+		strings.TrimPrefix(filepath.Ext(path), "."), strings.TrimRight(text, "\n"))
+	m.textarea.InsertAttachment(&attachment.Attachment{
+		ID:        uuid.NewString(),
+		Type:      "text",
+		MediaType: "text/plain",
+		Display:   display,
+		URL:       "data:text/plain;base64," + base64.StdEncoding.EncodeToString([]byte(value)),
+		Filename:  filepath.Base(path),
+		Source: &attachment.TextSource{
+			Value: value,
+		},
+	})
+	m.textarea.InsertString(" ")
+}
+
 func (m *editorComponent) createAttachmentFromFile(filePath string) *attachment.Attachment {
 	ext := strings.ToLower(filepath.Ext(filePath))
 	mediaType := getMediaTypeFromExtension(ext)
@@ -871,7 +1041,7 @@ func (m *editorComponent) createAttachmentFromFile(filePath string) *attachment.
 	}
 
 	// For binary files (images, PDFs), read and encode
-	fileBytes, err := os.ReadFile(filePath)
+	fileBytes, err := remote.ReadFile(absolutePath)
 	if err != nil {
 		slog.Error("Failed to read file", "error", err)
 		return nil
@@ -0,0 +1,142 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+// foldLines is the number of lines of output past which a finished tool
+// call is folded to a summary, until it's expanded
+const foldLines = 12
+
+// toolRef names a tool call drawn in the message list
+type toolRef struct {
+	messageID string
+	partID    string
+}
+
+// toolOutput returns the text a tool call shows in the chat
+func toolOutput(toolCall opencode.ToolPart) string {
+	input, _ := toolCall.State.Input.(map[string]any)
+	metadata, _ := toolCall.State.Metadata.(map[string]any)
+	switch toolCall.Tool {
+	case "bash":
+		if output, ok := metadata["output"].(string); ok {
+			return output
+		}
+	case "write":
+		if content, ok := input["content"].(string); ok {
+			return content
+		}
+	}
+	return toolCall.State.Output
+}
+
+// toolFoldable reports whether a tool call's output is long enough to be
+// folded. Calls asking for permission, edits and plans are always shown.
+func toolFoldable(toolCall opencode.ToolPart, permission opencode.Permission) bool {
+	if permission.ID != "" {
+		return false
+	}
+	if toolCall.State.Status != opencode.ToolPartStateStatusCompleted && toolCall.State.Status != opencode.ToolPartStateStatusError {
+		return false
+	}
+	switch toolCall.Tool {
+	case "edit", "todowrite", "todoread", "task":
+		return false
+	}
+	return countLines(toolOutput(toolCall)) > foldLines
+}
+
+// toolSummary describes a tool call's output in a line: its exit code, its
+// length and how long the call took
+func toolSummary(toolCall opencode.ToolPart) string {
+	var parts []string
+	metadata, _ := toolCall.State.Metadata.(map[string]any)
+	if exit, ok := metadata["exit"].(float64); ok {
+		parts = append(parts, fmt.Sprintf("exit %d", int(exit)))
+	} else if toolCall.State.Status == opencode.ToolPartStateStatusError {
+		parts = append(parts, "failed")
+	}
+	lines := countLines(toolOutput(toolCall))
+	plural := "s"
+	if lines == 1 {
+		plural = ""
+	}
+	parts = append(parts, fmt.Sprintf("%d line%s", lines, plural))
+	if d := toolDuration(toolCall); d > 0 {
+		parts = append(parts, formatToolDuration(d))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// countLines counts the lines of a text, without a final newline's empty
+// line
+func countLines(text string) int {
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return 0
+	}
+	return strings.Count(text, "\n") + 1
+}
+
+// toolDuration returns how long a finished tool call took, 0 when unknown
+func toolDuration(toolCall opencode.ToolPart) time.Duration {
+	var start, end float64
+	switch t := toolCall.State.Time.(type) {
+	case opencode.ToolStateCompletedTime:
+		start, end = t.Start, t.End
+	case opencode.ToolStateErrorTime:
+		start, end = t.Start, t.End
+	}
+	if start == 0 || end < start {
+		return 0
+	}
+	return time.Duration(end-start) * time.Millisecond
+}
+
+// formatToolDuration abbreviates a duration, as 340ms, 2.3s or 1m12s
+func formatToolDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	return d.Round(time.Second).String()
+}
+
+// toggleToolAt expands or folds the tool call drawn at a row of the screen
+func (m *messagesComponent) toggleToolAt(y int) tea.Cmd {
+	line, shown := m.listLine(y)
+	if m.list == nil || !shown {
+		return nil
+	}
+	ref, ok := m.folds[m.list.blockAt(line)]
+	if !ok {
+		return nil
+	}
+	return m.toggleTool(ref)
+}
+
+// ToggleToolInView expands or folds the last foldable tool call in view
+func (m *messagesComponent) ToggleToolInView() (tea.Model, tea.Cmd) {
+	if m.list == nil || m.loading {
+		return m, nil
+	}
+	first := m.list.blockAt(m.viewport.YOffset)
+	for i := m.list.blockAt(m.viewport.YOffset + m.viewport.Height() - 1); i >= max(0, first); i-- {
+		if ref, ok := m.folds[i]; ok {
+			return m, m.toggleTool(ref)
+		}
+	}
+	return m, nil
+}
+
+func (m *messagesComponent) toggleTool(ref toolRef) tea.Cmd {
+	return tea.Batch(m.app.ToggleTool(ref.messageID, ref.partID), m.renderView())
+}
@@ -0,0 +1,48 @@
+package chat
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+func TestToolFolding(t *testing.T) {
+	long := strings.Repeat("line\n", foldLines+1)
+	bash := opencode.ToolPart{
+		Tool: "bash",
+		State: opencode.ToolPartState{
+			Status:   opencode.ToolPartStateStatusCompleted,
+			Input:    map[string]any{"command": "make test"},
+			Metadata: map[string]any{"output": long, "exit": 2.0},
+			Time:     opencode.ToolStateCompletedTime{Start: 1000, End: 3300},
+		},
+	}
+	if !toolFoldable(bash, opencode.Permission{}) {
+		t.Error("long output isn't folded")
+	}
+	if toolFoldable(bash, opencode.Permission{ID: "per_1"}) {
+		t.Error("a call asking for permission is folded")
+	}
+	if summary, want := toolSummary(bash), "exit 2 · 13 lines · 2.3s"; summary != want {
+		t.Errorf("summary = %q, want %q", summary, want)
+	}
+
+	short := bash
+	short.State.Metadata = map[string]any{"output": "ok\n"}
+	if toolFoldable(short, opencode.Permission{}) {
+		t.Error("short output is folded")
+	}
+
+	running := bash
+	running.State.Status = opencode.ToolPartStateStatusRunning
+	if toolFoldable(running, opencode.Permission{}) {
+		t.Error("a running call is folded")
+	}
+
+	edit := bash
+	edit.Tool = "edit"
+	if toolFoldable(edit, opencode.Permission{}) {
+		t.Error("an edit is folded")
+	}
+}
@@ -0,0 +1,110 @@
+package chat
+
+import (
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+)
+
+// historySearch is a reverse incremental search of the prompt history, as
+// ctrl+r in a shell. The prompt that matches is shown in the editor while
+// the query is typed.
+type historySearch struct {
+	query    string
+	match    int        // Index in the history of the prompt shown, -1 before one matched
+	failed   bool       // Nothing matches the query from the prompt shown on
+	original app.Prompt // Prompt being written when the search started
+}
+
+// SearchHistory starts a reverse search of the prompt history, or moves to
+// the next older match when one is under way
+func (m *editorComponent) SearchHistory() {
+	if m.search != nil {
+		m.searchHistory(m.search.match + 1)
+		return
+	}
+	m.history = m.app.History()
+	m.historyIndex = -1
+	m.search = &historySearch{
+		match: -1,
+		original: app.Prompt{
+			Text:        m.textarea.Value(),
+			Attachments: m.textarea.GetAttachments(),
+		},
+	}
+}
+
+// HistorySearching reports whether a search of the prompt history takes
+// the keys
+func (m *editorComponent) HistorySearching() bool {
+	return m.search != nil
+}
+
+// searchHistory shows the first prompt, from the one at from on, that
+// matches the query. When none does the prompt shown is kept, and the
+// search marked as failed.
+func (m *editorComponent) searchHistory(from int) {
+	s := m.search
+	if s.query == "" {
+		s.failed = false
+		return
+	}
+	match := app.SearchPrompts(m.history, s.query, from)
+	s.failed = match < 0
+	if s.failed || match == s.match {
+		return
+	}
+	s.match = match
+	m.RestoreFromPrompt(m.history[match])
+	m.textarea.MoveToEnd()
+}
+
+// historySearchKey handles a key pressed during a search. Enter keeps the
+// prompt found in the editor, esc brings back the one being written.
+func (m *editorComponent) historySearchKey(msg tea.KeyPressMsg) tea.Cmd {
+	s := m.search
+	switch msg.String() {
+	case "ctrl+r":
+		m.SearchHistory()
+	case "backspace":
+		if s.query != "" {
+			_, size := utf8.DecodeLastRuneInString(s.query)
+			s.query = s.query[:len(s.query)-size]
+			m.searchHistory(0)
+		}
+	case "esc", "ctrl+g", "ctrl+c":
+		m.RestoreFromPrompt(s.original)
+		m.textarea.MoveToEnd()
+		m.search = nil
+	case "enter", "tab", "left", "right", "up", "down", "home", "end":
+		m.search = nil
+	default:
+		if msg.Text != "" {
+			s.query += msg.Text
+			m.searchHistory(max(0, s.match))
+		}
+	}
+	return nil
+}
+
+// historySearchHint replaces the hint under the editor during a search
+func (m *editorComponent) historySearchHint() string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Foreground(t.Text()).Background(t.Background()).Render
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.Background()).Render
+
+	label, color := "reverse-i-search", t.Accent()
+	if m.search.failed {
+		label, color = "failing reverse-i-search", t.Warning()
+	}
+	scope := "project"
+	if m.app.State.GlobalHistory {
+		scope = "all projects"
+	}
+	return styles.NewStyle().Foreground(color).Background(t.Background()).Render(label) +
+		muted(" ("+scope+") ") + base(m.search.query) + muted("▏") +
+		muted("   ") + base("ctrl+r") + muted(" older  ") + base("enter") + muted(" accept  ") + base("esc") + muted(" cancel")
+}
@@ -0,0 +1,51 @@
+package chat
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// Running inline, messages are printed to the terminal's scrollback once
+// they are finished and only the ones still changing are drawn above the
+// prompt. Finished messages come first, so they are printed in order.
+
+// settled reports whether a message is finished, so it can be printed to
+// the scrollback: a prompt sent rather than queued behind the reply being
+// written, or a completed reply. Messages from the one a revert starts at
+// are not, as the revert can be undone.
+func settled(message app.Message, revert string, writing string) bool {
+	id := app.MessageID(message)
+	if revert != "" && id >= revert {
+		return false
+	}
+	switch info := message.Info.(type) {
+	case opencode.UserMessage:
+		return info.ID < writing
+	case opencode.AssistantMessage:
+		return info.Time.Completed > 0
+	}
+	return false
+}
+
+// printSettled prints the blocks of finished messages to the scrollback, laid
+// out as the screen lays out the messages
+func printSettled(a *app.App, blocks []string) tea.Cmd {
+	if len(blocks) == 0 {
+		return nil
+	}
+	t := theme.CurrentTheme()
+	printed := styles.NewStyle().
+		Background(t.Background()).
+		Padding(1, 2, 0).
+		Render(strings.Join(blocks, "\n\n"))
+	if theme.CurrentThemeUsesAnsiColors() {
+		printed = util.ConvertRGBToAnsi16Colors(printed)
+	}
+	return tea.Println(a.FilterView(printed))
+}
@@ -0,0 +1,34 @@
+package chat
+
+import (
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+)
+
+func TestSettled(t *testing.T) {
+	prompt := app.Message{Info: opencode.UserMessage{ID: "msg_2"}}
+	writing := app.Message{Info: opencode.AssistantMessage{ID: "msg_3"}}
+	replied := app.Message{Info: opencode.AssistantMessage{ID: "msg_3", Time: opencode.AssistantMessageTime{Completed: 1}}}
+	queued := app.Message{Info: opencode.UserMessage{ID: "msg_4"}}
+
+	cases := []struct {
+		name    string
+		message app.Message
+		revert  string
+		want    bool
+	}{
+		{"sent prompt", prompt, "", true},
+		{"reply being written", writing, "", false},
+		{"finished reply", replied, "", true},
+		{"queued prompt", queued, "", false},
+		{"reverted reply", replied, "msg_2", false},
+		{"prompt before a revert", prompt, "msg_3", true},
+	}
+	for _, c := range cases {
+		if got := settled(c.message, c.revert, "msg_3"); got != c.want {
+			t.Errorf("%s: settled = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
@@ -155,6 +155,21 @@ func renderContentBlock(
 	for _, option := range options {
 		option(renderer)
 	}
+	// Zoom spaces blocks out further, keeping the blocks that are flush flush
+	if app.Zoom() {
+		if renderer.paddingTop > 0 {
+			renderer.paddingTop++
+		}
+		if renderer.paddingBottom > 0 {
+			renderer.paddingBottom++
+		}
+		if renderer.paddingLeft > 0 {
+			renderer.paddingLeft += 2
+		}
+		if renderer.paddingRight > 0 {
+			renderer.paddingRight += 2
+		}
+	}
 
 	borderColor := t.BackgroundPanel()
 	if renderer.borderColor != nil {
@@ -209,6 +224,7 @@ func renderText(
 	app *app.App,
 	message opencode.MessageUnion,
 	text string,
+	stream *util.MarkdownStream, // Renders the text while it streams, nil once complete
 	author string,
 	showToolDetails bool,
 	width int,
@@ -235,7 +251,11 @@ func renderText(
 		if casted.Time.Completed > 0 {
 			ts = time.UnixMilli(int64(casted.Time.Completed))
 		}
-		content = util.ToMarkdown(text, width, backgroundColor)
+		if stream != nil {
+			content = stream.Render(text, width, backgroundColor)
+		} else {
+			content = util.ToMarkdown(text, width, backgroundColor)
+		}
 		if isThinking {
 			// Get provider-specific typing indicator text
 			typingText := "Thinking..."
@@ -459,11 +479,60 @@ func renderText(
 	return ""
 }
 
+// editReview returns the review of an edit permission's hunks, when there
+// is more than one
+func editReview(a *app.App, permission opencode.Permission) *app.EditReview {
+	if permission.ID == "" {
+		return nil
+	}
+	review := a.EditReview()
+	if review == nil || review.PermissionID != permission.ID || len(review.Hunks) < 2 {
+		return nil
+	}
+	return review
+}
+
+// renderEditReview renders the hunks of an edit under review, each below a
+// line saying whether it is accepted. Rejected hunks are folded away unless
+// they are selected.
+func renderEditReview(review *app.EditReview, filename string, width int, backgroundColor compat.AdaptiveColor) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(backgroundColor)
+	var sb strings.Builder
+	for i, hunk := range review.Hunks {
+		marker := "  "
+		if i == review.Current {
+			marker = base.Foreground(t.Warning()).Bold(true).Render("▶ ")
+		}
+		status := base.Foreground(t.Success()).Render("accepted")
+		if review.Rejected[i] {
+			status = base.Foreground(t.Error()).Render("rejected")
+		}
+		line := marker +
+			base.Foreground(t.Text()).Bold(i == review.Current).Render(fmt.Sprintf("Hunk %d/%d ", i+1, len(review.Hunks))) +
+			status +
+			base.Foreground(t.TextMuted()).Render("  "+hunk.Header)
+		sb.WriteString(base.Padding(0, 1).Width(width-2).Render(line) + "\n")
+		if review.Rejected[i] && i != review.Current {
+			continue
+		}
+		if width < 120 {
+			sb.WriteString(diff.RenderUnifiedHunk(filename, hunk, diff.WithWidth(width-2)))
+		} else {
+			sb.WriteString(diff.RenderSideBySideHunk(filename, hunk, diff.WithWidth(width-2)))
+		}
+	}
+	return sb.String()
+}
+
+// renderToolDetails draws a tool call. A long output is folded to a
+// summary line unless the call was expanded.
 func renderToolDetails(
 	app *app.App,
 	toolCall opencode.ToolPart,
 	permission opencode.Permission,
 	width int,
+	expanded bool,
 ) string {
 	measure := util.Measure("chat.renderToolDetails")
 	defer measure("tool", toolCall.Tool)
@@ -503,6 +572,10 @@ func renderToolDetails(
 	baseStyle := styles.NewStyle().Background(backgroundColor).Foreground(t.Text()).Render
 	mutedStyle := styles.NewStyle().Background(backgroundColor).Foreground(t.TextMuted()).Render
 
+	// The hunks of an edit are reviewed one by one while its permission is
+	// asked, when there is more than one
+	review := editReview(app, permission)
+
 	permissionContent := ""
 	if permission.ID != "" {
 		borderColor = t.Warning()
@@ -511,6 +584,13 @@ func renderToolDetails(
 		text := base.Foreground(t.Text()).Bold(true).Render
 		muted := base.Foreground(t.TextMuted()).Render
 		permissionContent = "Permission required to run this tool:\n\n"
+		if app.EditBaseChangedOnDisk(permission.ID) {
+			permissionContent += base.Foreground(t.Warning()).Bold(true).Render("⚠ The file changed on disk since this edit was made.") +
+				muted(" Accepting applies it onto your changes, or compares both where they touch the same lines.") + "\n\n"
+		}
+		if review != nil {
+			permissionContent += text("↑↓") + muted(" hunk   ") + text("space") + muted(" accept/reject hunk   ")
+		}
 		permissionContent += text(
 			"enter ",
 		) + muted(
@@ -519,10 +599,18 @@ func renderToolDetails(
 			"a",
 		) + muted(
 			" accept always   ",
+		) + text(
+			"p",
+		) + muted(
+			" always in project   ",
 		) + text(
 			"esc",
 		) + muted(
-			" reject",
+			" reject   ",
+		) + text(
+			"n",
+		) + muted(
+			" never",
 		)
 
 	}
@@ -558,7 +646,9 @@ func renderToolDetails(
 				if diffField != nil {
 					patch := diffField.(string)
 					var formattedDiff string
-					if width < 120 {
+					if review != nil {
+						formattedDiff = renderEditReview(review, filename, width, backgroundColor)
+					} else if width < 120 {
 						formattedDiff, _ = diff.FormatUnifiedDiff(
 							filename,
 							patch,
@@ -648,9 +738,9 @@ func renderToolDetails(
 				for _, item := range todos.([]any) {
 					todo := item.(map[string]any)
 					content := todo["content"]
-          if content == nil {
-            continue
-          }
+					if content == nil {
+						continue
+					}
 					switch todo["status"] {
 					case "completed":
 						body += fmt.Sprintf("- [x] %s\n", content)
@@ -711,6 +801,16 @@ func renderToolDetails(
 		}
 	}
 
+	// A plugin's drawing replaces the chat's, the error included
+	if rendered := app.PartRendering(toolCall.ID); rendered != "" && permission.ID == "" {
+		lines := strings.Split(rendered, "\n")
+		for i, line := range lines {
+			lines[i] = ansi.Truncate(line, width-6, "…")
+		}
+		body = defaultStyle(strings.Join(lines, "\n"))
+		toolCall.State.Error = ""
+	}
+
 	error := ""
 	if toolCall.State.Status == opencode.ToolPartStateStatusError {
 		error = toolCall.State.Error
@@ -742,6 +842,19 @@ func renderToolDetails(
 
 	title := renderToolTitle(toolCall, width)
 	content := title + "\n\n" + body
+	if toolFoldable(toolCall, permission) {
+		marker, action := "▸ ", " to expand"
+		if expanded {
+			marker, action = "▾ ", " to fold"
+		}
+		fold := defaultStyle(mutedStyle(marker+toolSummary(toolCall)+"  ") +
+			baseStyle("click") + mutedStyle(" or ") + baseStyle("enter") + mutedStyle(action))
+		if expanded {
+			content += "\n\n" + fold
+		} else {
+			content = title + "\n\n" + fold
+		}
+	}
 
 	if permissionContent != "" {
 		content += "\n\n\n" + permissionContent
@@ -14,12 +14,15 @@ import (
 	"github.com/charmbracelet/lipgloss/v2"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/accessibility"
 	"github.com/aaronmrosenthal/rycode/internal/app"
 	"github.com/aaronmrosenthal/rycode/internal/commands"
 	"github.com/aaronmrosenthal/rycode/internal/components/dialog"
 	"github.com/aaronmrosenthal/rycode/internal/components/diff"
 	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/graphics"
 	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/splash"
 	"github.com/aaronmrosenthal/rycode/internal/styles"
 	"github.com/aaronmrosenthal/rycode/internal/theme"
 	"github.com/aaronmrosenthal/rycode/internal/util"
@@ -37,18 +40,30 @@ type MessagesComponent interface {
 	ThinkingBlocksVisible() bool
 	GotoTop() (tea.Model, tea.Cmd)
 	GotoBottom() (tea.Model, tea.Cmd)
-	CopyLastMessage() (tea.Model, tea.Cmd)
 	UndoLastMessage() (tea.Model, tea.Cmd)
 	RedoLastMessage() (tea.Model, tea.Cmd)
 	ScrollToMessage(messageID string) (tea.Model, tea.Cmd)
+	ReserveLines(lines int) (tea.Model, tea.Cmd)
+	ReserveColumns(columns int) (tea.Model, tea.Cmd)
+	MessageInView() string
+	StartSelection() (tea.Model, tea.Cmd)
+	Selecting() bool
+	SelectWord(x, y int) (tea.Model, tea.Cmd)
+	CancelSelection() (tea.Model, tea.Cmd)
+	MessageAt(y int) string
+	ToggleToolInView() (tea.Model, tea.Cmd)
 }
 
 type messagesComponent struct {
 	width, height      int
+	reserved           int // Lines taken from the bottom of the messages by a pane
+	reservedColumns    int // Columns taken from the right of the messages by a pane
 	app                *app.App
 	header             string
 	viewport           viewport.Model
 	clipboard          []string
+	styledClipboard    []string // The selected text with its styling
+	list               *blockList
 	cache              *PartCache
 	loading            bool
 	showToolDetails    bool
@@ -59,8 +74,16 @@ type messagesComponent struct {
 	partCount          int
 	lineCount          int
 	selection          *selection
-	messagePositions   map[string]int // map message ID to line position
+	visual             *visualSelection // Lines selected with the keyboard
+	copyWord           bool             // Copy the word selected by a double tap once it is rendered
+	messagePositions   map[string]int   // map message ID to line position
+	folds              map[int]toolRef  // Foldable tool calls, by block of the list
+	pendingScroll      string           // Message to scroll to once it has been rendered
+	anchorBottom       int              // Lines from the top of the view to the end, kept while earlier messages are added; -1 when none are
 	animating          bool
+	thumbnails         *ThumbnailCache             // nil unless the terminal draws images
+	uploaded           map[int]*graphics.Thumbnail // Images last sent to the terminal, by ID
+	printedThrough     string                      // Last message printed to the scrollback when running inline
 }
 
 type selection struct {
@@ -119,6 +142,10 @@ func (m *messagesComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.renderView(),
 			tea.Tick(90*time.Millisecond, func(t time.Time) tea.Msg { return shimmerTickMsg{} }),
 		)
+	case tea.KeyPressMsg:
+		if m.visual != nil {
+			return m, m.selectionKey(msg)
+		}
 	case tea.MouseClickMsg:
 		slog.Info("mouse", "x", msg.X, "y", msg.Y, "offset", m.viewport.YOffset)
 		y := msg.Y + m.viewport.YOffset
@@ -147,6 +174,13 @@ func (m *messagesComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.MouseReleaseMsg:
 		if m.selection != nil {
+			// A click that selected nothing expands or folds the tool call
+			// under it
+			if m.selection.endY == -1 {
+				m.selection = nil
+				m.clipboard = []string{}
+				return m, tea.Batch(m.renderView(), m.toggleToolAt(msg.Y))
+			}
 			m.selection = nil
 			if len(m.clipboard) > 0 {
 				content := strings.Join(m.clipboard, "\n")
@@ -160,13 +194,14 @@ func (m *messagesComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, m.renderView()
 		}
 	case tea.WindowSizeMsg:
-		effectiveWidth := msg.Width - 4
+		effectiveWidth := msg.Width - 4 - m.reservedColumns
 		// Clear cache on resize since width affects rendering
 		if m.width != effectiveWidth {
 			m.cache.Clear()
+			m.visual = nil
 		}
 		m.width = effectiveWidth
-		m.height = msg.Height - 7
+		m.height = msg.Height - 7 - m.reserved
 		m.viewport.SetWidth(m.width)
 		m.loading = true
 		return m, m.renderView()
@@ -182,6 +217,11 @@ func (m *messagesComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.cache.Clear()
 		m.loading = true
 		return m, m.renderView()
+	case layout.CellSizeMsg:
+		// Thumbnails are scaled for the cell size
+		if m.thumbnails != nil {
+			return m, m.renderView()
+		}
 	case ToggleToolDetailsMsg:
 		m.showToolDetails = !m.showToolDetails
 		m.app.State.ShowToolDetails = &m.showToolDetails
@@ -194,8 +234,10 @@ func (m *messagesComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.tail = true
 		m.loading = true
 		return m, m.renderView()
-	case app.SessionClearedMsg:
+	case app.SessionClearedMsg, app.MessageBranchedMsg:
 		m.cache.Clear()
+		m.visual = nil
+		m.printedThrough = ""
 		m.tail = true
 		m.loading = true
 		return m, m.renderView()
@@ -220,6 +262,8 @@ func (m *messagesComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if currentParent != targetParent {
 			m.cache.Clear()
 		}
+		m.visual = nil
+		m.printedThrough = ""
 
 		m.viewport.GotoBottom()
 	case app.MessageRevertedMsg:
@@ -235,6 +279,20 @@ func (m *messagesComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	case opencode.EventListResponseEventMessageUpdated:
 		if msg.Properties.Info.SessionID == m.app.Session.ID {
+			if m.tail && m.visual == nil && m.app.TrimMessages() {
+				// The rendered parts of the dropped messages go too
+				m.cache.Clear()
+			}
+			cmds = append(cmds, m.renderView())
+		}
+	case app.OlderMessagesLoadedMsg:
+		if msg.SessionID == m.app.Session.ID {
+			if msg.Err == nil {
+				m.anchorBottom = m.viewport.TotalLineCount() - m.viewport.YOffset
+			} else {
+				// A message out of reach isn't scrolled to once it's back
+				m.pendingScroll = ""
+			}
 			cmds = append(cmds, m.renderView())
 		}
 	case opencode.EventListResponseEventSessionError:
@@ -262,13 +320,21 @@ func (m *messagesComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case opencode.EventListResponseEventPermissionReplied:
 		m.tail = true
 		return m, m.renderView()
+	case app.EditReviewChangedMsg, app.PartRenderedMsg:
+		return m, m.renderView()
+	case app.TutorialUpdatedMsg:
+		m.tail = true
+		return m, m.renderView()
 	case renderCompleteMsg:
 		m.partCount = msg.partCount
 		m.lineCount = msg.lineCount
 		m.rendering = false
 		m.clipboard = msg.clipboard
+		m.styledClipboard = msg.styledClipboard
+		m.list = msg.list
 		m.loading = false
 		m.messagePositions = msg.messagePositions
+		m.folds = msg.folds
 		m.tail = m.viewport.AtBottom()
 
 		// Preserve scroll across reflow
@@ -276,15 +342,38 @@ func (m *messagesComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		wasAtBottom := m.viewport.AtBottom()
 		prevYOffset := m.viewport.YOffset
 		m.viewport = msg.viewport
-		if wasAtBottom {
+		if wasAtBottom && m.visual == nil {
 			m.viewport.GotoBottom()
 		} else {
 			m.viewport.YOffset = prevYOffset
 		}
 
 		m.header = msg.header
+		if msg.sessionID == m.app.Session.ID {
+			// The blocks of a render begun before the session was switched
+			// are the old session's
+			m.printedThrough = msg.printedThrough
+			cmds = append(cmds, printSettled(m.app, msg.printed))
+		}
+		if upload := m.upload(msg.images); upload != nil {
+			cmds = append(cmds, upload)
+		}
+		if m.anchorBottom >= 0 {
+			// Earlier messages were added above the ones in view
+			m.viewport.SetYOffset(m.viewport.TotalLineCount() - m.anchorBottom)
+			m.anchorBottom = -1
+		}
+		if position, ok := m.messagePositions[m.pendingScroll]; ok {
+			m.viewport.SetYOffset(position)
+			m.tail = false
+			m.pendingScroll = ""
+		} else if m.app.MessagePagedOut(m.pendingScroll) {
+			cmds = append(cmds, m.app.LoadOlderMessages())
+		}
 		if m.dirty {
 			cmds = append(cmds, m.renderView())
+		} else if m.copyWord {
+			cmds = append(cmds, m.copySelectedWord())
 		}
 
 		// Start shimmer ticks if any assistant/tool is in-flight
@@ -298,17 +387,38 @@ func (m *messagesComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	viewport, cmd := m.viewport.Update(msg)
 	m.viewport = viewport
 	cmds = append(cmds, cmd)
+	cmds = append(cmds, m.loadOlder())
 
 	return m, tea.Batch(cmds...)
 }
 
+// loadOlder fetches the earlier messages of the session left out of memory
+// once the view is scrolled to the top
+func (m *messagesComponent) loadOlder() tea.Cmd {
+	if m.loading || m.app.Inline || !m.viewport.AtTop() || m.app.OlderMessages() == 0 || m.app.LoadingOlderMessages() {
+		return nil
+	}
+	load := m.app.LoadOlderMessages()
+	if load == nil {
+		return nil
+	}
+	return tea.Batch(load, m.renderView())
+}
+
 type renderCompleteMsg struct {
 	viewport         viewport.Model
 	clipboard        []string
+	styledClipboard  []string
+	list             *blockList
 	header           string
 	partCount        int
 	lineCount        int
 	messagePositions map[string]int
+	folds            map[int]toolRef
+	images           []*graphics.Thumbnail // Thumbnails in the rendered messages
+	printed          []string              // Blocks of messages finished since the last render, when running inline
+	printedThrough   string
+	sessionID        string
 }
 
 func (m *messagesComponent) renderView() tea.Cmd {
@@ -324,6 +434,14 @@ func (m *messagesComponent) renderView() tea.Cmd {
 
 	viewport := m.viewport
 	tail := m.tail
+	inline := m.app.Inline
+	printedThrough := m.printedThrough
+	sessionID := m.app.Session.ID
+	var visual *visualSelection
+	if m.visual != nil {
+		selected := *m.visual
+		visual = &selected
+	}
 
 	return func() tea.Msg {
 		header := m.renderHeader()
@@ -331,12 +449,14 @@ func (m *messagesComponent) renderView() tea.Cmd {
 		defer measure()
 
 		t := theme.CurrentTheme()
-		blocks := make([]string, 0)
+		blocks := make([]block, 0)
 		partCount := 0
 		lineCount := 0
 		messagePositions := make(map[string]int) // Track message ID to line position
+		folds := make(map[int]toolRef)           // Foldable tool calls, by block
 
 		orphanedToolCalls := make([]opencode.ToolPart, 0)
+		images := make([]*graphics.Thumbnail, 0)
 
 		width := m.width // always use full width
 
@@ -359,6 +479,20 @@ func (m *messagesComponent) renderView() tea.Cmd {
 			}
 		}
 
+		if older := m.app.OlderMessages(); older > 0 && !inline {
+			notice := fmt.Sprintf("↑ %d earlier messages, scroll up to load them", older)
+			if m.app.LoadingOlderMessages() {
+				notice = "Loading earlier messages…"
+			}
+			content := styles.NewStyle().
+				Foreground(t.TextMuted()).
+				Width(width).
+				Align(lipgloss.Center).
+				Render(notice)
+			lineCount += lipgloss.Height(content) + 1
+			blocks = append(blocks, renderedBlock(content))
+		}
+
 		reverted := false
 		revertedMessageCount := 0
 		revertedToolCount := 0
@@ -372,11 +506,19 @@ func (m *messagesComponent) renderView() tea.Cmd {
 				break
 			}
 		}
+		printed := []string{}
+		settling := inline
 		for _, message := range m.app.Messages {
 			var content string
-			var cached bool
 			error := ""
 
+			if inline && app.MessageID(message) <= printedThrough {
+				continue
+			}
+			settle := settling && settled(message, m.app.Session.Revert.MessageID, lastAssistantMessage)
+			settling = settle
+			firstBlock, firstLine := len(blocks), lineCount
+
 			switch casted := message.Info.(type) {
 			case opencode.UserMessage:
 				// Track the position of this user message
@@ -447,16 +589,31 @@ func (m *messagesComponent) renderView() tea.Cmd {
 							},
 							flexItems...,
 						)
+						// Images are shown below their names where the terminal can
+						// draw them. Their placeholders' colors are the image IDs, so
+						// not with a theme that maps colors to the 16 ANSI ones.
+						if m.thumbnails != nil && !theme.CurrentThemeUsesAnsiColors() {
+							views := []string{}
+							for _, filePart := range fileParts {
+								if thumbnail := m.thumbnails.Get(filePart); thumbnail != nil {
+									images = append(images, thumbnail)
+									views = append(views, thumbnail.View(), " ")
+								}
+							}
+							if len(views) > 0 {
+								files += "\n\n" + lipgloss.JoinHorizontal(lipgloss.Top, views...)
+							}
+						}
 
 						author := m.app.Config.Username
 						isQueued := casted.ID > lastAssistantMessage
 						key := m.cache.GenerateKey(casted.ID, part.Text, width, files, author, isQueued)
-						content, cached = m.cache.Get(key)
-						if !cached {
-							content = renderText(
+						rendered := m.cachedBlock(key, func() string {
+							return renderText(
 								m.app,
 								message.Info,
 								part.Text,
+								nil,
 								author,
 								m.showToolDetails,
 								width,
@@ -467,17 +624,17 @@ func (m *messagesComponent) renderView() tea.Cmd {
 								fileParts,
 								agentParts,
 							)
-							m.cache.Set(key, content)
-						}
-						if content != "" {
+						})
+						if !rendered.empty() {
 							partCount++
-							lineCount += lipgloss.Height(content) + 1
-							blocks = append(blocks, content)
+							lineCount += rendered.height + 1
+							blocks = append(blocks, rendered)
 						}
 					}
 				}
 
 			case opencode.AssistantMessage:
+				messagePositions[casted.ID] = lineCount
 				if casted.ID == m.app.Session.Revert.MessageID {
 					reverted = true
 					revertedMessageCount = 1
@@ -526,14 +683,16 @@ func (m *messagesComponent) renderView() tea.Cmd {
 							}
 						}
 
+						var rendered block
 						if finished {
+							m.cache.EndStream(part.ID)
 							key := m.cache.GenerateKey(casted.ID, part.Text, width, m.showToolDetails, toolCallParts)
-							content, cached = m.cache.Get(key)
-							if !cached {
-								content = renderText(
+							rendered = m.cachedBlock(key, func() string {
+								return renderText(
 									m.app,
 									message.Info,
 									part.Text,
+									nil,
 									casted.ModelID,
 									m.showToolDetails,
 									width,
@@ -545,13 +704,13 @@ func (m *messagesComponent) renderView() tea.Cmd {
 									[]opencode.AgentPart{},
 									toolCallParts...,
 								)
-								m.cache.Set(key, content)
-							}
+							})
 						} else {
-							content = renderText(
+							rendered = renderedBlock(renderText(
 								m.app,
 								message.Info,
 								part.Text,
+								m.cache.Stream(part.ID),
 								casted.ModelID,
 								m.showToolDetails,
 								width,
@@ -562,12 +721,12 @@ func (m *messagesComponent) renderView() tea.Cmd {
 								[]opencode.FilePart{},
 								[]opencode.AgentPart{},
 								toolCallParts...,
-							)
+							))
 						}
-						if content != "" {
+						if !rendered.empty() {
 							partCount++
-							lineCount += lipgloss.Height(content) + 1
-							blocks = append(blocks, content)
+							lineCount += rendered.height + 1
+							blocks = append(blocks, rendered)
 							hasContent = true
 						}
 					case opencode.ToolPart:
@@ -588,36 +747,43 @@ func (m *messagesComponent) renderView() tea.Cmd {
 							continue
 						}
 
+						expanded := m.app.ToolExpanded(casted.ID, part.ID)
+						var rendered block
 						if part.State.Status == opencode.ToolPartStateStatusCompleted || part.State.Status == opencode.ToolPartStateStatusError {
 							key := m.cache.GenerateKey(casted.ID,
 								part.ID,
 								m.showToolDetails,
 								width,
 								permission.ID,
+								m.app.PartRendering(part.ID),
+								expanded,
 							)
-							content, cached = m.cache.Get(key)
-							if !cached {
-								content = renderToolDetails(
+							rendered = m.cachedBlock(key, func() string {
+								return renderToolDetails(
 									m.app,
 									part,
 									permission,
 									width,
+									expanded,
 								)
-								m.cache.Set(key, content)
-							}
+							})
 						} else {
 							// if the tool call isn't finished, don't cache
-							content = renderToolDetails(
+							rendered = renderedBlock(renderToolDetails(
 								m.app,
 								part,
 								permission,
 								width,
-							)
+								expanded,
+							))
+						}
+						if !rendered.empty() && toolFoldable(part, permission) {
+							folds[len(blocks)] = toolRef{messageID: casted.ID, partID: part.ID}
 						}
-						if content != "" {
+						if !rendered.empty() {
 							partCount++
-							lineCount += lipgloss.Height(content) + 1
-							blocks = append(blocks, content)
+							lineCount += rendered.height + 1
+							blocks = append(blocks, rendered)
 							hasContent = true
 						}
 					case opencode.ReasoningPart:
@@ -630,23 +796,35 @@ func (m *messagesComponent) renderView() tea.Cmd {
 						if part.Text != "" {
 							text := part.Text
 							shimmer := part.Time.End == 0 && part.ID == lastStreamingReasoningID
-							content = renderText(
-								m.app,
-								message.Info,
-								text,
-								casted.ModelID,
-								m.showToolDetails,
-								width,
-								"",
-								true,
-								false,
-								shimmer,
-								[]opencode.FilePart{},
-								[]opencode.AgentPart{},
-							)
+							render := func() string {
+								return renderText(
+									m.app,
+									message.Info,
+									text,
+									nil,
+									casted.ModelID,
+									m.showToolDetails,
+									width,
+									"",
+									true,
+									false,
+									shimmer,
+									[]opencode.FilePart{},
+									[]opencode.AgentPart{},
+								)
+							}
+							// Finished thinking doesn't change, so it is cached
+							// like finished text
+							var rendered block
+							if part.Time.End > 0 {
+								key := m.cache.GenerateKey(casted.ID, part.ID, text, width, "reasoning")
+								rendered = m.cachedBlock(key, render)
+							} else {
+								rendered = renderedBlock(render())
+							}
 							partCount++
-							lineCount += lipgloss.Height(content) + 1
-							blocks = append(blocks, content)
+							lineCount += rendered.height + 1
+							blocks = append(blocks, rendered)
 							hasContent = true
 						}
 					}
@@ -669,6 +847,7 @@ func (m *messagesComponent) renderView() tea.Cmd {
 						m.app,
 						message.Info,
 						"Generating...",
+						nil,
 						casted.ModelID,
 						m.showToolDetails,
 						width,
@@ -681,7 +860,7 @@ func (m *messagesComponent) renderView() tea.Cmd {
 					)
 					partCount++
 					lineCount += lipgloss.Height(content) + 1
-					blocks = append(blocks, content)
+					blocks = append(blocks, renderedBlock(content))
 				}
 			}
 
@@ -693,9 +872,24 @@ func (m *messagesComponent) renderView() tea.Cmd {
 					width,
 					WithBorderColor(t.Error()),
 				)
-				blocks = append(blocks, error)
+				blocks = append(blocks, renderedBlock(error))
 				lineCount += lipgloss.Height(error) + 1
 			}
+
+			if settle {
+				// Printed to the scrollback rather than drawn
+				for _, block := range blocks[firstBlock:] {
+					printed = append(printed, block.rendered())
+				}
+				blocks, lineCount = blocks[:firstBlock], firstLine
+				for i := range folds {
+					if i >= firstBlock {
+						delete(folds, i)
+					}
+				}
+				delete(messagePositions, app.MessageID(message))
+				printedThrough = app.MessageID(message)
+			}
 		}
 
 		if revertedMessageCount > 0 || revertedToolCount > 0 {
@@ -763,10 +957,22 @@ func (m *messagesComponent) renderView() tea.Cmd {
 				width,
 				WithBorderColor(t.BackgroundPanel()),
 			)
-			blocks = append(blocks, content)
+			blocks = append(blocks, renderedBlock(content))
 		}
 
-		if m.app.CurrentPermission.ID != "" &&
+		// Commands left in a shell's inbox and project tool calls are asked
+		// for as bash calls
+		part, ok := m.app.ShellPermissionPart(m.app.CurrentPermission)
+		if !ok {
+			part, ok = m.app.ProjectToolPermissionPart(m.app.CurrentPermission)
+		}
+		if ok {
+			if content := renderToolDetails(m.app, part, m.app.CurrentPermission, width, true); content != "" {
+				partCount++
+				lineCount += lipgloss.Height(content) + 1
+				blocks = append(blocks, renderedBlock(content))
+			}
+		} else if m.app.CurrentPermission.ID != "" &&
 			m.app.CurrentPermission.SessionID != m.app.Session.ID {
 			response, err := m.app.Client.Session.Message(
 				context.Background(),
@@ -785,11 +991,12 @@ func (m *messagesComponent) renderView() tea.Cmd {
 								toolPart,
 								m.app.CurrentPermission,
 								width,
+								true,
 							)
 							if content != "" {
 								partCount++
 								lineCount += lipgloss.Height(content) + 1
-								blocks = append(blocks, content)
+								blocks = append(blocks, renderedBlock(content))
 							}
 						}
 					}
@@ -797,54 +1004,20 @@ func (m *messagesComponent) renderView() tea.Cmd {
 			}
 		}
 
-		final := []string{}
-		clipboard := []string{}
-		var selection *selection
+		// Only the blocks in view are laid out into lines, by the viewport
+		list := newBlockList(blocks)
+		clipboard, styled := []string{}, []string{}
+		highlight := styles.NewStyle().
+			Background(t.Accent()).
+			Foreground(t.BackgroundPanel()).
+			Render
 		if m.selection != nil {
-			selection = m.selection.coords(lipgloss.Height(header) + 1)
+			clipboard, styled = list.selectText(m.selection.coords(lipgloss.Height(header)+1), highlight)
+		} else if visual != nil {
+			clipboard, styled = list.selectText(visual.lines(), highlight)
 		}
-		for _, block := range blocks {
-			lines := strings.Split(block, "\n")
-			for index, line := range lines {
-				if selection == nil || index == 0 || index == len(lines)-1 {
-					final = append(final, line)
-					continue
-				}
-				y := len(final)
-				if y >= selection.startY && y <= selection.endY {
-					left := 3
-					if y == selection.startY {
-						left = selection.startX - 2
-					}
-					left = max(3, left)
-
-					width := ansi.StringWidth(line)
-					right := width - 1
-					if y == selection.endY {
-						right = min(selection.endX-2, right)
-					}
-
-					prefix := ansi.Cut(line, 0, left)
-					middle := strings.TrimRight(ansi.Strip(ansi.Cut(line, left, right)), " ")
-					suffix := ansi.Cut(line, left+ansi.StringWidth(middle), width)
-					clipboard = append(clipboard, middle)
-					line = prefix + styles.NewStyle().
-						Background(t.Accent()).
-						Foreground(t.BackgroundPanel()).
-						Render(ansi.Strip(middle)) +
-						suffix
-				}
-				final = append(final, line)
-			}
-			y := len(final)
-			if selection != nil && y >= selection.startY && y < selection.endY {
-				clipboard = append(clipboard, "")
-			}
-			final = append(final, "")
-		}
-		content := "\n" + strings.Join(final, "\n")
 		viewport.SetHeight(m.height - lipgloss.Height(header))
-		viewport.SetContent(content)
+		viewport.SetContentSource(list)
 		if tail {
 			viewport.GotoBottom()
 		}
@@ -852,14 +1025,39 @@ func (m *messagesComponent) renderView() tea.Cmd {
 		return renderCompleteMsg{
 			header:           header,
 			clipboard:        clipboard,
+			styledClipboard:  styled,
+			list:             list,
 			viewport:         viewport,
 			partCount:        partCount,
 			lineCount:        lineCount,
 			messagePositions: messagePositions,
+			folds:            folds,
+			images:           images,
+			printed:          printed,
+			printedThrough:   printedThrough,
+			sessionID:        sessionID,
 		}
 	}
 }
 
+// upload sends the terminal the images it hasn't been sent yet, which it
+// draws in their thumbnails' placeholders
+func (m *messagesComponent) upload(images []*graphics.Thumbnail) tea.Cmd {
+	var sequences strings.Builder
+	for _, image := range images {
+		// A thumbnail scaled again for another cell size replaces the one
+		// uploaded under its ID
+		if m.uploaded[image.ID] != image {
+			m.uploaded[image.ID] = image
+			sequences.WriteString(image.Upload)
+		}
+	}
+	if sequences.Len() == 0 {
+		return nil
+	}
+	return tea.Raw(sequences.String())
+}
+
 func (m *messagesComponent) renderHeader() string {
 	if m.app.Session.ID == "" {
 		return ""
@@ -989,6 +1187,16 @@ func (m *messagesComponent) renderHeader() string {
 		BorderStyle(lipgloss.ThickBorder()).
 		Render(header)
 
+	// Zoom draws the title again at double height, on terminals that can
+	if m.app.Zoom() && accessibility.DoubleHeightSupported() {
+		title := styles.NewStyle().
+			Foreground(t.Text()).
+			Bold(true).
+			PaddingLeft(1).
+			Render(ansi.Truncate(m.app.Session.Title, max(1, m.width/2-2), "…"))
+		header = accessibility.DoubleHeight(title) + "\n" + header
+	}
+
 	return "\n" + header + "\n"
 }
 
@@ -1055,11 +1263,41 @@ func (m *messagesComponent) View() string {
 	}
 
 	viewport := m.viewport.View()
+	if m.visual != nil {
+		lines := strings.Split(viewport, "\n")
+		lines[len(lines)-1] = m.selectionHint()
+		viewport = strings.Join(lines, "\n")
+	}
 	return styles.NewStyle().
 		Background(bgColor).
 		Render(m.header + "\n" + viewport)
 }
 
+// ReserveLines gives lines at the bottom of the messages to a pane, which
+// the messages no longer take
+func (m *messagesComponent) ReserveLines(lines int) (tea.Model, tea.Cmd) {
+	if lines == m.reserved {
+		return m, nil
+	}
+	m.height += m.reserved - lines
+	m.reserved = lines
+	return m, m.renderView()
+}
+
+// ReserveColumns gives columns at the right of the messages to a pane, which
+// the messages no longer take
+func (m *messagesComponent) ReserveColumns(columns int) (tea.Model, tea.Cmd) {
+	if columns == m.reservedColumns {
+		return m, nil
+	}
+	m.width += m.reservedColumns - columns
+	m.reservedColumns = columns
+	m.cache.Clear()
+	m.visual = nil
+	m.viewport.SetWidth(m.width)
+	return m, m.renderView()
+}
+
 func (m *messagesComponent) PageUp() (tea.Model, tea.Cmd) {
 	m.viewport.ViewUp()
 	return m, nil
@@ -1098,26 +1336,6 @@ func (m *messagesComponent) GotoBottom() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m *messagesComponent) CopyLastMessage() (tea.Model, tea.Cmd) {
-	if len(m.app.Messages) == 0 {
-		return m, nil
-	}
-	lastMessage := m.app.Messages[len(m.app.Messages)-1]
-	var lastTextPart *opencode.TextPart
-	for _, part := range lastMessage.Parts {
-		if p, ok := part.(opencode.TextPart); ok {
-			lastTextPart = &p
-		}
-	}
-	if lastTextPart == nil {
-		return m, nil
-	}
-	var cmds []tea.Cmd
-	cmds = append(cmds, app.SetClipboard(lastTextPart.Text))
-	cmds = append(cmds, toast.NewSuccessToast("Message copied to clipboard"))
-	return m, tea.Batch(cmds...)
-}
-
 func (m *messagesComponent) UndoLastMessage() (tea.Model, tea.Cmd) {
 	after := float64(0)
 	var revertedMessage app.Message
@@ -1276,15 +1494,34 @@ func (m *messagesComponent) RedoLastMessage() (tea.Model, tea.Cmd) {
 }
 
 func (m *messagesComponent) ScrollToMessage(messageID string) (tea.Model, tea.Cmd) {
-	if m.messagePositions == nil {
+	position, exists := m.messagePositions[messageID]
+	if m.app.MessagePagedOut(messageID) {
+		// Earlier messages are fetched until the message is among them
+		m.pendingScroll = messageID
+		return m, m.app.LoadOlderMessages()
+	}
+	if m.rendering || m.loading || !exists {
+		// A message of a session that is still loading is scrolled to once
+		// it has been rendered
+		m.pendingScroll = messageID
 		return m, nil
 	}
+	m.viewport.SetYOffset(position)
+	m.tail = false // Stop auto-scrolling to bottom when manually navigating
+	return m, nil
+}
 
-	if position, exists := m.messagePositions[messageID]; exists {
-		m.viewport.SetYOffset(position)
-		m.tail = false // Stop auto-scrolling to bottom when manually navigating
+// MessageInView returns the ID of the message shown in the middle of the
+// view, or "" when no message is shown
+func (m *messagesComponent) MessageInView() string {
+	middle := m.viewport.YOffset + m.viewport.Height()/2
+	id, start := "", -1
+	for messageID, position := range m.messagePositions {
+		if position <= middle && position > start {
+			id, start = messageID, position
+		}
 	}
-	return m, nil
+	return id
 }
 
 func NewMessagesComponent(app *app.App) MessagesComponent {
@@ -1308,6 +1545,11 @@ func NewMessagesComponent(app *app.App) MessagesComponent {
 		showThinkingBlocks = *app.State.ShowThinkingBlocks
 	}
 
+	var thumbnails *ThumbnailCache
+	if splash.DetectTerminalCapabilities().SupportsKittyGraphics {
+		thumbnails = NewThumbnailCache()
+	}
+
 	return &messagesComponent{
 		app:                app,
 		viewport:           vp,
@@ -1315,6 +1557,9 @@ func NewMessagesComponent(app *app.App) MessagesComponent {
 		showThinkingBlocks: showThinkingBlocks,
 		cache:              NewPartCache(),
 		tail:               true,
+		anchorBottom:       -1,
 		messagePositions:   make(map[string]int),
+		thumbnails:         thumbnails,
+		uploaded:           make(map[int]*graphics.Thumbnail),
 	}
 }
@@ -0,0 +1,18 @@
+package chat
+
+import (
+	"github.com/aaronmrosenthal/rycode/internal/accessibility"
+	"github.com/aaronmrosenthal/rycode/internal/commands"
+)
+
+// What the mouse does in the transcript, with the keys doing the same, for
+// the keyboard audit
+func init() {
+	for _, action := range []accessibility.MouseAction{
+		{Component: "chat", Name: "Select and copy text", Mouse: "drag across the transcript", Command: string(commands.MessagesSelectCommand)},
+		{Component: "chat", Name: "Expand or fold tool output", Mouse: "click the tool call", Keys: "enter on an empty prompt, for the tool call in view"},
+		{Component: "chat", Name: "Scroll the transcript", Mouse: "mouse wheel", Command: string(commands.MessagesHalfPageUpCommand)},
+	} {
+		accessibility.RegisterMouseAction(action)
+	}
+}
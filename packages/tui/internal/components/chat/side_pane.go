@@ -0,0 +1,239 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/accessibility"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/commands"
+	"github.com/aaronmrosenthal/rycode/internal/components/diff"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// SidePane shows the newest diff, file or tool output of the session next to
+// the chat, in the split layout of wide terminals. While focused it takes
+// the scrolling keys.
+type SidePane struct {
+	focused bool
+	scroll  int
+	partID  string // Tool call shown, whose change scrolls back to the top
+
+	// The rendered output, kept until the part, its output or the width
+	// change, since diffs and files are costly to highlight
+	key   string
+	title string
+	lines []string
+}
+
+// NewSidePane creates an empty side pane
+func NewSidePane() *SidePane {
+	return &SidePane{}
+}
+
+// Focused reports whether the pane takes the scrolling keys
+func (p *SidePane) Focused() bool {
+	return p.focused
+}
+
+// SetFocused gives the scrolling keys to the pane or back to the chat
+func (p *SidePane) SetFocused(focused bool) {
+	p.focused = focused
+}
+
+// What the mouse does in the pane, with the keys Update takes for it, for
+// the keyboard audit
+func init() {
+	accessibility.RegisterMouseAction(accessibility.MouseAction{
+		Component: "split",
+		Name:      "Scroll the output pane",
+		Mouse:     "mouse wheel over the pane",
+		Command:   string(commands.SplitFocusCommand),
+		Keys:      "↑/↓, page up/down while the pane is focused",
+	})
+}
+
+// Update scrolls the output by the mouse wheel, or by the arrow and page
+// keys, page lines at a time, and takes the focus away on esc. It reports
+// whether the message was for the pane.
+func (p *SidePane) Update(msg tea.Msg, page int) bool {
+	switch msg := msg.(type) {
+	case tea.MouseWheelMsg:
+		switch msg.Button {
+		case tea.MouseWheelUp:
+			p.Scroll(-3)
+		case tea.MouseWheelDown:
+			p.Scroll(3)
+		}
+		return true
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "up":
+			p.Scroll(-1)
+		case "down":
+			p.Scroll(1)
+		case "pgup":
+			p.Scroll(-page)
+		case "pgdown":
+			p.Scroll(page)
+		case "esc":
+			p.SetFocused(false)
+		default:
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// Scroll moves the output by a number of lines, up when negative
+func (p *SidePane) Scroll(lines int) {
+	p.scroll = max(0, p.scroll+lines)
+}
+
+// ScrollTo moves the output to its top, or its end when bottom is set
+func (p *SidePane) ScrollTo(bottom bool) {
+	p.scroll = 0
+	if bottom {
+		p.scroll = len(p.lines)
+	}
+}
+
+// View draws the pane in exactly width columns and height lines: a title
+// line and the output of the session's newest tool call below it
+func (p *SidePane) View(a *app.App, width, height int) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	line := base.Width(width).MaxWidth(width).Render
+	muted := base.Foreground(t.TextMuted()).Render
+
+	part, ok := latestOutput(a.Messages)
+	if !ok {
+		p.partID, p.key, p.title, p.lines = "", "", "", nil
+	} else if key := outputKey(part, width); key != p.key {
+		if part.ID != p.partID {
+			p.partID, p.scroll = part.ID, 0
+		}
+		p.key = key
+		p.title, p.lines = renderOutput(part, width)
+	}
+	body := height - 1
+	p.scroll = min(p.scroll, max(0, len(p.lines)-body))
+
+	title := " " + p.title
+	if p.title == "" {
+		title = " Output"
+	}
+	position := ""
+	if len(p.lines) > body {
+		position = fmt.Sprintf("%d–%d of %d ", p.scroll+1, min(len(p.lines), p.scroll+body), len(p.lines))
+	}
+	if key := a.Keybind(commands.SplitFocusCommand); key != "" {
+		verb := " focus "
+		if p.focused {
+			verb = " back to chat "
+		}
+		position += key + verb
+	}
+	header := styles.NewStyle().Background(t.BackgroundElement()).Foreground(t.Text()).Bold(true)
+	if p.focused {
+		header = header.Background(t.Primary()).Foreground(t.Background())
+	}
+	title = ansi.Truncate(title, max(0, width-lipgloss.Width(position)-1), "…")
+	gap := strings.Repeat(" ", max(1, width-lipgloss.Width(title)-lipgloss.Width(position)))
+	lines := []string{header.Width(width).MaxWidth(width).Render(title + gap + position)}
+
+	if len(p.lines) == 0 {
+		lines = append(lines, line(muted(" The newest diff, file or tool output of the session shows here")))
+	}
+	for _, out := range p.lines[p.scroll:min(len(p.lines), p.scroll+body)] {
+		lines = append(lines, line(out))
+	}
+	for len(lines) < height {
+		lines = append(lines, line(""))
+	}
+	return strings.Join(lines[:height], "\n")
+}
+
+// latestOutput finds the newest tool call of the messages with output to
+// show
+func latestOutput(messages []app.Message) (opencode.ToolPart, bool) {
+	for i := len(messages) - 1; i >= 0; i-- {
+		parts := messages[i].Parts
+		for j := len(parts) - 1; j >= 0; j-- {
+			if part, ok := parts[j].(opencode.ToolPart); ok && paneOutput(part) != "" {
+				return part, true
+			}
+		}
+	}
+	return opencode.ToolPart{}, false
+}
+
+// paneOutput returns what the pane shows of a tool call: the diff of an
+// edit, the file read, or the text the call shows in the chat
+func paneOutput(part opencode.ToolPart) string {
+	metadata, _ := part.State.Metadata.(map[string]any)
+	switch part.Tool {
+	case "edit":
+		if patch, ok := metadata["diff"].(string); ok {
+			return patch
+		}
+	case "read":
+		if preview, ok := metadata["preview"].(string); ok {
+			return preview
+		}
+	}
+	return toolOutput(part)
+}
+
+func outputKey(part opencode.ToolPart, width int) string {
+	return fmt.Sprintf("%s/%s/%d/%d/%s", part.ID, part.State.Status, len(paneOutput(part)), width, theme.CurrentThemeName())
+}
+
+// renderOutput draws the output of a tool call in lines of width columns
+func renderOutput(part opencode.ToolPart, width int) (string, []string) {
+	input, _ := part.State.Input.(map[string]any)
+	filename, _ := input["filePath"].(string)
+	title := part.Tool
+	if filename != "" {
+		title += " " + util.Relative(filename)
+	} else if part.State.Title != "" {
+		title += " " + part.State.Title
+	}
+	if part.State.Status == opencode.ToolPartStateStatusRunning {
+		title += " (running)"
+	}
+
+	output := paneOutput(part)
+	var rendered string
+	switch part.Tool {
+	case "edit":
+		rendered, _ = diff.FormatUnifiedDiff(filename, output, diff.WithWidth(width))
+	case "read", "write":
+		rendered = util.RenderFile(filename, output, width)
+	}
+	if rendered == "" {
+		t := theme.CurrentTheme()
+		text := styles.NewStyle().Background(t.BackgroundPanel()).Foreground(t.Text()).Render
+		var lines []string
+		for out := range strings.SplitSeq(strings.TrimRight(ansi.Strip(output), "\n"), "\n") {
+			// A progress bar redraws its line after a carriage return
+			out = strings.TrimRight(out, "\r")
+			out = out[strings.LastIndex(out, "\r")+1:]
+			out = strings.ReplaceAll(out, "\t", "    ")
+			lines = append(lines, text(" "+ansi.Truncate(out, width-2, "…")))
+		}
+		return title, lines
+	}
+	lines := strings.Split(strings.TrimRight(rendered, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(line, width, "")
+	}
+	return title, lines
+}
@@ -0,0 +1,78 @@
+package chat
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/accessibility"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+)
+
+func TestSidePane(t *testing.T) {
+	bash := opencode.ToolPart{ID: "prt_1", Tool: "bash", State: opencode.ToolPartState{
+		Status:   opencode.ToolPartStateStatusCompleted,
+		Input:    map[string]any{"command": "make test"},
+		Metadata: map[string]any{"output": strings.Repeat("ok\n", 30)},
+	}}
+	todo := opencode.ToolPart{ID: "prt_2", Tool: "todowrite", State: opencode.ToolPartState{
+		Status: opencode.ToolPartStateStatusRunning,
+	}}
+	a := &app.App{Messages: []app.Message{{Parts: []opencode.PartUnion{
+		opencode.TextPart{Text: "Running the tests"},
+		bash,
+		todo,
+	}}}}
+
+	if part, ok := latestOutput(a.Messages); !ok || part.ID != "prt_1" {
+		t.Fatalf("latest output = %s, %v; want the call with output", part.ID, ok)
+	}
+
+	pane := NewSidePane()
+	pane.View(a, 40, 10)
+	pane.Scroll(100)
+	view := pane.View(a, 40, 10)
+	lines := strings.Split(view, "\n")
+	if len(lines) != 10 || lipgloss.Width(view) != 40 {
+		t.Fatalf("view is %d lines of %d columns, want 10 of 40", len(lines), lipgloss.Width(view))
+	}
+	if !strings.Contains(lines[0], "bash") || !strings.Contains(lines[0], "22–30 of 30") {
+		t.Errorf("title = %q, want the tool and the end of its output", lines[0])
+	}
+
+	// A newer call scrolls back to its top
+	a.Messages[0].Parts = append(a.Messages[0].Parts, opencode.ToolPart{ID: "prt_3", Tool: "bash", State: opencode.ToolPartState{
+		Status:   opencode.ToolPartStateStatusRunning,
+		Metadata: map[string]any{"output": strings.Repeat("building\n", 20)},
+	}})
+	if title := strings.Split(pane.View(a, 40, 10), "\n")[0]; !strings.Contains(title, "1–9 of 20") {
+		t.Errorf("title after a newer call = %q", title)
+	}
+}
+
+func TestSidePaneInput(t *testing.T) {
+	pane := NewSidePane()
+	pane.SetFocused(true)
+	pane.Update(tea.MouseWheelMsg{Button: tea.MouseWheelDown}, 10)
+	pane.Update(tea.KeyPressMsg{Code: tea.KeyPgDown}, 10)
+	if pane.scroll != 13 {
+		t.Errorf("scroll = %d, want 13 after a wheel turn and a page", pane.scroll)
+	}
+	if pane.Update(tea.KeyPressMsg{Code: 'a', Text: "a"}, 10) {
+		t.Error("the pane took a key meant for the prompt")
+	}
+	pane.Update(tea.KeyPressMsg{Code: tea.KeyEscape}, 10)
+	if pane.Focused() {
+		t.Error("esc left the pane focused")
+	}
+
+	// The wheel Update handles has its key in the keyboard audit
+	if !slices.ContainsFunc(accessibility.MouseActions(), func(action accessibility.MouseAction) bool {
+		return action.Component == "split" && action.Keys != ""
+	}) {
+		t.Error("the pane's mouse wheel is missing from the keyboard audit")
+	}
+}
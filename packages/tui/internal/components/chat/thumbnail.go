@@ -0,0 +1,69 @@
+package chat
+
+import (
+	"encoding/base64"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/graphics"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+)
+
+// Thumbnails of attached images are at most this many cells
+const (
+	thumbnailColumns = 24
+	thumbnailRows    = 8
+)
+
+// ThumbnailCache keeps the thumbnails of image parts by part ID, so images
+// are decoded once. Parts without a thumbnail are kept as nil.
+type ThumbnailCache struct {
+	mu         sync.Mutex
+	thumbnails map[string]*graphics.Thumbnail
+}
+
+// NewThumbnailCache creates a new thumbnail cache
+func NewThumbnailCache() *ThumbnailCache {
+	return &ThumbnailCache{
+		thumbnails: make(map[string]*graphics.Thumbnail),
+	}
+}
+
+// Get returns the thumbnail of an image part, or nil for other files and
+// images that can't be decoded. Thumbnails are scaled again when the cell
+// size changed.
+func (c *ThumbnailCache) Get(part opencode.FilePart) *graphics.Thumbnail {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if thumbnail, ok := c.thumbnails[part.ID]; ok {
+		if thumbnail == nil || thumbnail.Cell == layout.Current.CellSize() {
+			return thumbnail
+		}
+	}
+	var thumbnail *graphics.Thumbnail
+	if data, ok := imageData(part.URL); ok {
+		var err error
+		thumbnail, err = graphics.NewThumbnail(data, thumbnailColumns, thumbnailRows)
+		if err != nil {
+			slog.Debug("No thumbnail for image", "file", part.Filename, "error", err)
+		}
+	}
+	c.thumbnails[part.ID] = thumbnail
+	return thumbnail
+}
+
+// imageData decodes the data URL pasted and attached images are sent as
+func imageData(url string) ([]byte, bool) {
+	if !strings.HasPrefix(url, "data:image/") {
+		return nil, false
+	}
+	header, encoded, ok := strings.Cut(url, ",")
+	if !ok || !strings.HasSuffix(header, ";base64") {
+		return nil, false
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	return data, err == nil
+}
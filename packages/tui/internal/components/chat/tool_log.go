@@ -0,0 +1,90 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/commands"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+)
+
+// maxToolLogCalls is the number of running calls listed above the output
+// of the newest
+const maxToolLogCalls = 3
+
+// RenderToolLog draws the pane tailing the session's running tool calls in
+// exactly height lines: a line for each call, and the output of the newest
+// as it streams in
+func RenderToolLog(a *app.App, width, height int, now time.Time) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	line := base.Width(width).MaxWidth(width).Render
+	muted := base.Foreground(t.TextMuted()).Render
+	text := base.Foreground(t.Text()).Render
+
+	runs := a.ToolRuns()
+	status := "nothing running"
+	switch running := len(runs); {
+	case running == 0:
+	case runs[0].Running:
+		status = fmt.Sprintf("%d running", running)
+	default:
+		status = "last call"
+	}
+	title := base.Foreground(t.Text()).Bold(true).Render(" Tool log ") + muted(status)
+	if key := a.Keybind(commands.ToolLogCommand); key != "" {
+		hint := text(key) + muted(" hide ")
+		title += muted(strings.Repeat(" ", max(1, width-lipgloss.Width(title)-lipgloss.Width(hint)))) + hint
+	}
+	lines := []string{styles.NewStyle().Background(t.BackgroundElement()).Width(width).MaxWidth(width).Render(title)}
+
+	if len(runs) == 0 {
+		lines = append(lines, line(muted(" Tool calls of the session show here as they run")))
+	}
+	if len(runs) > maxToolLogCalls {
+		lines = append(lines, line(muted(fmt.Sprintf(" +%d more", len(runs)-maxToolLogCalls))))
+		runs = runs[len(runs)-maxToolLogCalls:]
+	}
+	for _, run := range runs {
+		marker := base.Foreground(t.Warning()).Render(" ● ")
+		switch {
+		case run.Running:
+		case run.Failed || (run.Exit != nil && *run.Exit != 0):
+			marker = base.Foreground(t.Error()).Render(" ✗ ")
+		default:
+			marker = base.Foreground(t.Success()).Render(" ✓ ")
+		}
+		detail := "  " + run.Elapsed(now).Round(time.Second).String()
+		if run.Exit != nil {
+			detail += fmt.Sprintf("  exit %d", *run.Exit)
+		}
+		label := ansi.Truncate(run.Label, max(0, width-3-len(run.Tool)-2-ansi.StringWidth(detail)), "…")
+		lines = append(lines, line(marker+text(run.Tool)+muted("  "+label+detail)))
+	}
+
+	if len(runs) > 0 {
+		output := ansi.Strip(runs[len(runs)-1].Output)
+		output = strings.ReplaceAll(strings.TrimRight(output, "\n"), "\t", "    ")
+		if output != "" {
+			tail := strings.Split(output, "\n")
+			tail = tail[max(0, len(tail)-(height-len(lines))):]
+			for _, out := range tail {
+				// A progress bar redraws its line after a carriage return
+				out = strings.TrimRight(out, "\r")
+				out = out[strings.LastIndex(out, "\r")+1:]
+				lines = append(lines, line(text(" "+ansi.Truncate(out, width-2, "…"))))
+			}
+		}
+	}
+
+	lines = lines[:min(len(lines), height)]
+	for len(lines) < height {
+		lines = append(lines, line(""))
+	}
+	return strings.Join(lines, "\n")
+}
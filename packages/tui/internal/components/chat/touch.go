@@ -0,0 +1,120 @@
+package chat
+
+import (
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+)
+
+// listLine returns the line of the message list at a row of the screen,
+// and whether the messages are shown there
+func (m *messagesComponent) listLine(y int) (int, bool) {
+	row := y - lipgloss.Height(m.header)
+	return row + m.viewport.YOffset, row >= 0 && row < m.viewport.Height()
+}
+
+// SelectWord selects the word at a cell of the screen and copies it, as a
+// double tap does on touch screens
+func (m *messagesComponent) SelectWord(x, y int) (tea.Model, tea.Cmd) {
+	if m.list == nil || m.loading {
+		return m, nil
+	}
+	line, shown := m.listLine(y)
+	if !shown || !m.list.textLine(line) {
+		return m, nil
+	}
+	// Columns of the lines are two short of the screen's, as selections
+	// count them
+	start, end, ok := wordBounds(ansi.Strip(m.list.Lines(line, line+1)[0]), x-2)
+	if !ok {
+		return m, nil
+	}
+	m.selection = &selection{
+		startX: start + 2,
+		startY: y + m.viewport.YOffset,
+		endX:   end + 2,
+		endY:   y + m.viewport.YOffset,
+	}
+	m.copyWord = true
+	return m, m.renderView()
+}
+
+// copySelectedWord copies the word SelectWord selected once it is rendered
+func (m *messagesComponent) copySelectedWord() tea.Cmd {
+	m.copyWord = false
+	word := strings.TrimSpace(strings.Join(m.clipboard, ""))
+	if word == "" {
+		return nil
+	}
+	return tea.Sequence(
+		app.SetClipboard(word),
+		toast.NewSuccessToast("Copied \""+ansi.Truncate(word, 40, "…")+"\""),
+	)
+}
+
+// CancelSelection drops the text selected with the mouse, as a drag turns
+// out to be a swipe
+func (m *messagesComponent) CancelSelection() (tea.Model, tea.Cmd) {
+	if m.selection == nil {
+		return m, nil
+	}
+	m.selection = nil
+	m.clipboard = []string{}
+	return m, m.renderView()
+}
+
+// MessageAt returns the ID of the message shown at a row of the screen, or
+// "" when there is none
+func (m *messagesComponent) MessageAt(y int) string {
+	line, shown := m.listLine(y)
+	if !shown {
+		return ""
+	}
+	id, start := "", -1
+	for messageID, position := range m.messagePositions {
+		if position <= line && position > start {
+			id, start = messageID, position
+		}
+	}
+	return id
+}
+
+// wordBounds returns the columns a word of a line spans, from start up to
+// but not including end, when there is one at a column. Words are letters,
+// digits and underscores.
+func wordBounds(line string, column int) (start, end int, ok bool) {
+	type cell struct {
+		start, end int
+		word       bool
+	}
+	var cells []cell
+	x := 0
+	for _, r := range line {
+		width := ansi.StringWidth(string(r))
+		cells = append(cells, cell{x, x + width, unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'})
+		x += width
+	}
+	at := -1
+	for i, c := range cells {
+		if column >= c.start && column < c.end {
+			at = i
+			break
+		}
+	}
+	if at < 0 || !cells[at].word {
+		return 0, 0, false
+	}
+	first, last := at, at
+	for first > 0 && cells[first-1].word {
+		first--
+	}
+	for last < len(cells)-1 && cells[last+1].word {
+		last++
+	}
+	return cells[first].start, cells[last].end, true
+}
@@ -0,0 +1,158 @@
+package chat
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+)
+
+// visualSelection selects whole lines of the transcript with the keyboard,
+// as visual line mode in vim. The mouse can't select text natively while
+// the TUI holds the alternate screen and reports mouse events.
+type visualSelection struct {
+	anchor int // Line the selection started on, in the lines of the message list
+	cursor int // Line the selection is extended to
+}
+
+// lines returns the selection in the lines counted by the block list's
+// selections, which leave out the blank line above the first block
+func (v visualSelection) lines() *selection {
+	return &selection{
+		startX: 0,
+		startY: min(v.anchor, v.cursor) - 1,
+		endX:   math.MaxInt32,
+		endY:   max(v.anchor, v.cursor) - 1,
+	}
+}
+
+// StartSelection starts selecting lines from the last line of text in view
+func (m *messagesComponent) StartSelection() (tea.Model, tea.Cmd) {
+	if m.list == nil || m.loading {
+		return m, nil
+	}
+	cursor := m.viewport.YOffset + m.selectionHeight() - 1
+	cursor = m.textLineFrom(min(cursor, m.list.LineCount()-1), -1)
+	if cursor < 0 {
+		return m, toast.NewInfoToast("Nothing to select yet")
+	}
+	m.visual = &visualSelection{anchor: cursor, cursor: cursor}
+	m.tail = false
+	return m, m.renderView()
+}
+
+// Selecting reports whether lines are being selected, which takes the keys
+func (m *messagesComponent) Selecting() bool {
+	return m.visual != nil
+}
+
+// selectionKey handles a key pressed while lines are selected. y copies the
+// text, Y keeps its colors and styles.
+func (m *messagesComponent) selectionKey(msg tea.KeyPressMsg) tea.Cmd {
+	v := m.visual
+	page := m.selectionHeight()
+	switch msg.String() {
+	case "j", "down":
+		m.moveCursor(v.cursor+1, 1)
+	case "k", "up":
+		m.moveCursor(v.cursor-1, -1)
+	case "ctrl+d":
+		m.moveCursor(v.cursor+page/2, 1)
+	case "ctrl+u":
+		m.moveCursor(v.cursor-page/2, -1)
+	case "pgdown", "ctrl+f":
+		m.moveCursor(v.cursor+page, 1)
+	case "pgup", "ctrl+b":
+		m.moveCursor(v.cursor-page, -1)
+	case "g", "home":
+		m.moveCursor(0, 1)
+	case "G", "end":
+		m.moveCursor(m.list.LineCount()-1, -1)
+	case "o":
+		v.anchor, v.cursor = v.cursor, v.anchor
+		m.moveCursor(v.cursor, 1)
+	case "v", "V", "space":
+		v.anchor = v.cursor
+	case "y", "enter":
+		return m.copySelection(m.clipboard)
+	case "Y":
+		return m.copySelection(m.styledClipboard)
+	case "esc", "q", "ctrl+c":
+		m.visual = nil
+	default:
+		return nil
+	}
+	return m.renderView()
+}
+
+// moveCursor moves the cursor to the first line of text from a line on, in
+// a direction, and scrolls it into view
+func (m *messagesComponent) moveCursor(line, direction int) {
+	line = max(0, min(line, m.list.LineCount()-1))
+	if found := m.textLineFrom(line, direction); found >= 0 {
+		line = found
+	} else if found := m.textLineFrom(line, -direction); found >= 0 {
+		line = found
+	} else {
+		return
+	}
+	m.visual.cursor = line
+	if height := m.selectionHeight(); line < m.viewport.YOffset {
+		m.viewport.SetYOffset(line)
+	} else if line >= m.viewport.YOffset+height {
+		m.viewport.SetYOffset(line - height + 1)
+	}
+}
+
+// textLineFrom returns the first line of text from a line on, in a
+// direction, or -1 when there is none
+func (m *messagesComponent) textLineFrom(line, direction int) int {
+	for ; line >= 0 && line < m.list.LineCount(); line += direction {
+		if m.list.textLine(line) {
+			return line
+		}
+	}
+	return -1
+}
+
+// selectionHeight is the number of lines of messages in view during a
+// selection, above the hint that replaces the last one
+func (m *messagesComponent) selectionHeight() int {
+	return max(1, m.viewport.Height()-1)
+}
+
+// copySelection copies the lines selected and ends the selection
+func (m *messagesComponent) copySelection(lines []string) tea.Cmd {
+	m.visual = nil
+	if len(lines) == 0 {
+		return tea.Sequence(m.renderView(), toast.NewInfoToast("Nothing selected to copy"))
+	}
+	plural := "s"
+	if len(lines) == 1 {
+		plural = ""
+	}
+	return tea.Sequence(
+		m.renderView(),
+		app.SetClipboard(strings.Join(lines, "\n")),
+		toast.NewSuccessToast(fmt.Sprintf("Copied %d line%s to clipboard", len(lines), plural)),
+	)
+}
+
+// selectionHint replaces the last line of the messages during a selection
+func (m *messagesComponent) selectionHint() string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Foreground(t.Text()).Background(t.Background())
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.Background()).Render
+	lines := max(m.visual.anchor, m.visual.cursor) - min(m.visual.anchor, m.visual.cursor) + 1
+	hint := base.Foreground(t.Accent()).Render("-- VISUAL LINE --") +
+		muted(fmt.Sprintf(" %d ", lines)) + muted("   ") +
+		base.Render("j/k") + muted(" extend  ") + base.Render("v") + muted(" restart  ") +
+		base.Render("y") + muted(" copy text  ") + base.Render("Y") + muted(" copy styled  ") +
+		base.Render("esc") + muted(" cancel")
+	return base.Width(m.width).MaxWidth(m.width).Render(hint)
+}
@@ -3,14 +3,14 @@ package dialog
 import (
 	"strings"
 
+	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/aaronmrosenthal/rycode/internal/accessibility"
 	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
 	"github.com/aaronmrosenthal/rycode/internal/layout"
 	"github.com/aaronmrosenthal/rycode/internal/styles"
 	"github.com/aaronmrosenthal/rycode/internal/theme"
-	"github.com/aaronmrosenthal/rycode/internal/typography"
-	tea "github.com/charmbracelet/bubbletea/v2"
-	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/aaronmrosenthal/rycode/internal/util"
 )
 
 // AccessibilityDialog displays accessibility settings
@@ -21,8 +21,7 @@ type AccessibilityDialog interface {
 type accessibilityDialog struct {
 	app      *app.App
 	settings *accessibility.AccessibilitySettings
-	width    int
-	height   int
+	modal    *modal.Modal
 	focused  int // Currently focused setting
 }
 
@@ -31,7 +30,7 @@ type Setting struct {
 	Name        string
 	Description string
 	Enabled     *bool
-	OnToggle    func()
+	OnToggle    func() tea.Cmd // Applies the setting once Enabled is flipped
 }
 
 // NewAccessibilityDialog creates a new accessibility settings dialog
@@ -40,6 +39,10 @@ func NewAccessibilityDialog(app *app.App) AccessibilityDialog {
 		app:      app,
 		settings: accessibility.GetSettings(),
 		focused:  0,
+		modal: modal.New(
+			modal.WithTitle("Accessibility"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
 	}
 }
 
@@ -49,10 +52,6 @@ func (d *accessibilityDialog) Init() tea.Cmd {
 
 func (d *accessibilityDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		d.width = msg.Width
-		d.height = msg.Height
-
 	case tea.KeyPressMsg:
 		settings := d.getSettings()
 		switch msg.String() {
@@ -64,13 +63,14 @@ func (d *accessibilityDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if d.focused < len(settings)-1 {
 				d.focused++
 			}
-		case "enter", " ":
+		case "enter", "space":
 			// Toggle focused setting
 			if d.focused < len(settings) {
 				setting := settings[d.focused]
 				*setting.Enabled = !*setting.Enabled
+				var cmd tea.Cmd
 				if setting.OnToggle != nil {
-					setting.OnToggle()
+					cmd = setting.OnToggle()
 				}
 
 				// Announce change for screen readers
@@ -79,6 +79,7 @@ func (d *accessibilityDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					status = "enabled"
 				}
 				accessibility.AnnounceAction("Toggled "+setting.Name, status)
+				return d, cmd
 			}
 		}
 	}
@@ -86,87 +87,45 @@ func (d *accessibilityDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return d, nil
 }
 
-func (d *accessibilityDialog) View() string {
-	t := theme.CurrentTheme()
-	typo := typography.New()
-
-	var sections []string
-
-	// Header
-	header := styles.NewStyle().
-		Foreground(t.Primary()).
-		Bold(true).
-		Render("♿ Accessibility Settings")
-
-	sections = append(sections, header)
-	sections = append(sections, "")
-
-	// Subtitle
-	subtitle := styles.NewStyle().
-		Foreground(t.TextMuted()).
-		Faint(true).
-		Render("Customize RyCode for your needs")
-
-	sections = append(sections, subtitle)
-	sections = append(sections, "")
-
-	// Settings categories
-	sections = append(sections, typo.Subheading.Render("Visual"))
-	sections = append(sections, "")
-
-	settings := d.getSettings()
-	for i, setting := range settings {
-		card := d.renderSetting(setting, i == d.focused)
-		sections = append(sections, card)
-	}
-
-	sections = append(sections, "")
-
-	// Help footer
-	helpStyle := styles.NewStyle().
-		Foreground(t.TextMuted()).
-		Faint(true)
-
-	help := helpStyle.Render("↑/↓: Navigate  [Space/Enter] Toggle  [ESC] Close")
-	sections = append(sections, help)
-
-	content := strings.Join(sections, "\n")
-
-	// Wrap in bordered box
-	boxStyle := styles.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(t.Border()).
-		Padding(2, 3).
-		Width(d.width - 4)
-
-	return boxStyle.Render(content)
-}
-
 // getSettings returns all available settings
 func (d *accessibilityDialog) getSettings() []Setting {
 	return []Setting{
+		{
+			Name:        "Zoom Mode",
+			Description: "Wider spacing, double-height titles where the terminal can, a simpler status bar and raised contrast",
+			Enabled:     &d.settings.Zoom,
+			OnToggle: func() tea.Cmd {
+				// Switching the theme redraws everything in the raised colors
+				return tea.Batch(
+					d.app.SetZoom(d.settings.Zoom),
+					util.CmdHandler(ThemeSelectedMsg{ThemeName: theme.CurrentThemeName()}),
+				)
+			},
+		},
 		{
 			Name:        "High Contrast Mode",
 			Description: "Increase color contrast for better visibility",
 			Enabled:     &d.settings.HighContrast,
-			OnToggle: func() {
+			OnToggle: func() tea.Cmd {
 				if d.settings.HighContrast {
 					d.settings.EnableHighContrast()
 				} else {
 					d.settings.DisableHighContrast()
 				}
+				return nil
 			},
 		},
 		{
 			Name:        "Reduced Motion",
 			Description: "Minimize animations and motion effects",
 			Enabled:     &d.settings.ReducedMotion,
-			OnToggle: func() {
+			OnToggle: func() tea.Cmd {
 				if d.settings.ReducedMotion {
 					d.settings.EnableReducedMotion()
 				} else {
 					d.settings.DisableReducedMotion()
 				}
+				return nil
 			},
 		},
 		{
@@ -183,20 +142,16 @@ func (d *accessibilityDialog) getSettings() []Setting {
 			Name:        "Screen Reader Mode",
 			Description: "Optimize for screen reader usage with verbose labels",
 			Enabled:     &d.settings.ScreenReaderMode,
-			OnToggle: func() {
-				if d.settings.ScreenReaderMode {
-					d.settings.EnableScreenReader()
-				}
+			OnToggle: func() tea.Cmd {
+				return d.app.SetScreenReader(d.settings.ScreenReaderMode)
 			},
 		},
 		{
 			Name:        "Keyboard-Only Mode",
 			Description: "Enhance keyboard navigation with visual focus indicators",
 			Enabled:     &d.settings.KeyboardOnly,
-			OnToggle: func() {
-				if d.settings.KeyboardOnly {
-					d.settings.EnableKeyboardOnly()
-				}
+			OnToggle: func() tea.Cmd {
+				return d.app.SetKeyboardOnly(d.settings.KeyboardOnly)
 			},
 		},
 		{
@@ -217,65 +172,38 @@ func (d *accessibilityDialog) getSettings() []Setting {
 	}
 }
 
-// renderSetting renders a single setting card
-func (d *accessibilityDialog) renderSetting(setting Setting, focused bool) string {
+func (d *accessibilityDialog) Render(background string) string {
 	t := theme.CurrentTheme()
-
-	// Border color based on focus
-	borderColor := t.Border()
-	if focused {
-		borderColor = t.Primary()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
 	}
 
-	// Toggle indicator
-	toggleStyle := styles.NewStyle().
-		Foreground(t.Background()).
-		Bold(true).
-		Padding(0, 1)
-
-	var toggle string
-	if *setting.Enabled {
-		toggle = toggleStyle.
-			Background(t.Success()).
-			Render("✓ ON")
-	} else {
-		toggle = toggleStyle.
-			Background(t.TextMuted()).
-			Render("○ OFF")
+	var lines []string
+	settings := d.getSettings()
+	for i, setting := range settings {
+		prefix := "  "
+		nameStyle := textStyle
+		if i == d.focused {
+			prefix = "› "
+			nameStyle = nameStyle.Bold(true)
+		}
+		box := mutedStyle.Render("[ ] ")
+		if *setting.Enabled {
+			box = base.Foreground(t.Primary()).Render("[x] ")
+		}
+		lines = append(lines, textStyle.Render(prefix)+box+nameStyle.Render(setting.Name))
 	}
-
-	// Name
-	nameStyle := styles.NewStyle().
-		Foreground(t.Text()).
-		Bold(true)
-
-	name := nameStyle.Render(setting.Name)
-
-	// Description
-	descStyle := styles.NewStyle().
-		Foreground(t.TextMuted()).
-		Faint(true)
-
-	desc := descStyle.Render(setting.Description)
-
-	// Layout
-	line1 := name + "  " + toggle
-	line2 := desc
-
-	content := line1 + "\n" + line2
-
-	// Card style
-	cardStyle := styles.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(borderColor).
-		Padding(0, 2).
-		Width(60)
-
-	return cardStyle.Render(content)
-}
-
-func (d *accessibilityDialog) Render(background string) string {
-	return d.View()
+	lines = append(lines, "", mutedStyle.Render(settings[d.focused].Description), "")
+	lines = append(lines, help("↑/↓", "select", "space", "toggle", "esc", "close"))
+	return d.modal.Render(strings.Join(lines, "\n"), background)
 }
 
 func (d *accessibilityDialog) Close() tea.Cmd {
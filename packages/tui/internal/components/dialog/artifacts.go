@@ -0,0 +1,173 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// maxArtifactRows is the number of artifacts listed at once
+const maxArtifactRows = 12
+
+// ArtifactsDialog browses the scripts, configs and queries saved from the
+// answers of the session, to read one or reference it in the prompt
+type ArtifactsDialog interface {
+	layout.Modal
+}
+
+type artifactsDialog struct {
+	app       *app.App
+	modal     *modal.Modal
+	artifacts []app.Artifact // Newest first
+	selected  int
+	viewer    *fileViewerDialog
+	err       error
+}
+
+func (d *artifactsDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *artifactsDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if d.viewer != nil {
+		if key, ok := msg.(tea.KeyPressMsg); ok && !d.viewer.capturing() {
+			switch key.String() {
+			case "backspace", "left":
+				d.viewer = nil
+				return d, nil
+			case "enter":
+				return d, d.pick(d.viewer.path)
+			}
+		}
+		_, cmd := d.viewer.Update(msg)
+		return d, cmd
+	}
+
+	key, ok := msg.(tea.KeyPressMsg)
+	if !ok || len(d.artifacts) == 0 {
+		return d, nil
+	}
+	artifact := d.artifacts[d.selected]
+	switch key.String() {
+	case "up", "k":
+		d.selected = max(0, d.selected-1)
+	case "down", "j":
+		d.selected = min(len(d.artifacts)-1, d.selected+1)
+	case "tab", "right", "v":
+		d.viewer = newFileViewer(artifact.Path, 1)
+		return d, d.viewer.Init()
+	case "enter":
+		return d, d.pick(artifact.Path)
+	case "x", "delete":
+		if d.err = d.app.RemoveArtifact(artifact); d.err == nil {
+			d.artifacts = append(d.artifacts[:d.selected], d.artifacts[d.selected+1:]...)
+			d.selected = max(0, min(d.selected, len(d.artifacts)-1))
+		}
+	}
+	return d, nil
+}
+
+// pick closes the dialog and references an artifact in the prompt
+func (d *artifactsDialog) pick(path string) tea.Cmd {
+	return tea.Sequence(
+		util.CmdHandler(modal.CloseModalMsg{}),
+		util.CmdHandler(FilePickedMsg{Path: path}),
+	)
+}
+
+func (d *artifactsDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+	width := max(40, min(90, layout.Current.Container.Width-12))
+
+	if d.viewer != nil {
+		return d.modal.Render(d.viewer.View()+"\n\n"+help("enter", "reference in prompt", "backspace", "back"), background)
+	}
+
+	var lines []string
+	if d.err != nil {
+		lines = append(lines, base.Foreground(t.Error()).Width(width).Render(d.err.Error()), "")
+	}
+	if len(d.artifacts) == 0 {
+		hint := "No artifacts saved for this session. /artifacts save keeps the scripts, configs and queries of its answers"
+		if !d.app.State.SaveArtifacts {
+			hint += ", and /artifacts on saves them as they come"
+		}
+		lines = append(lines, mutedStyle.Width(width).Render(hint+"."))
+		return d.modal.Render(strings.Join(lines, "\n"), background)
+	}
+
+	start := max(0, min(d.selected-maxArtifactRows/2, len(d.artifacts)-maxArtifactRows))
+	end := min(len(d.artifacts), start+maxArtifactRows)
+	for i := start; i < end; i++ {
+		artifact := d.artifacts[i]
+		prefix := "  "
+		nameStyle := textStyle
+		if i == d.selected {
+			prefix = "› "
+			nameStyle = nameStyle.Foreground(t.Primary()).Bold(true)
+		}
+		info := fmt.Sprintf("  %s  %s", formatSize(artifact.Size), artifact.Modified.Format("Jan 2 15:04"))
+		name := ansi.Truncate(artifact.Name, width-lipgloss.Width(prefix+info), "…")
+		gap := strings.Repeat(" ", max(0, width-lipgloss.Width(prefix+name+info)))
+		lines = append(lines, textStyle.Render(prefix)+nameStyle.Render(name)+mutedStyle.Render(gap+info))
+	}
+	if end < len(d.artifacts) {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  … %d more", len(d.artifacts)-end)))
+	}
+	lines = append(lines, "", mutedStyle.Render(ansi.Truncate(app.SessionArtifactsDir(d.app.Session.ID), width, "…")))
+	lines = append(lines, "", help("↑/↓", "select", "tab", "view", "enter", "reference in prompt", "x", "delete"))
+	return d.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (d *artifactsDialog) Close() tea.Cmd {
+	return nil
+}
+
+// formatSize shortens a file size, as 12.3 KB
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", size)
+}
+
+// NewArtifactsDialog creates the browser of the session's artifacts, with
+// the newest selected
+func NewArtifactsDialog(a *app.App) ArtifactsDialog {
+	d := &artifactsDialog{
+		app: a,
+		modal: modal.New(
+			modal.WithTitle("Artifacts"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	artifacts, err := a.Artifacts(a.Session.ID)
+	d.err = err
+	for i := len(artifacts) - 1; i >= 0; i-- {
+		d.artifacts = append(d.artifacts, artifacts[i])
+	}
+	return d
+}
@@ -0,0 +1,118 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/intelligence"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+)
+
+// BenchmarkDialog shows the scored comparison table of a benchmark run
+type BenchmarkDialog interface {
+	layout.Modal
+}
+
+type benchmarkDialog struct {
+	modal   *modal.Modal
+	suite   string
+	runAt   time.Time
+	results []intelligence.BenchmarkResult
+	churn   map[string]float64
+}
+
+func (b *benchmarkDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (b *benchmarkDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	return b, nil
+}
+
+func (b *benchmarkDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	mutedStyle := base.Foreground(t.TextMuted())
+	headerStyle := base.Foreground(t.Text()).Bold(true)
+	rowStyle := base.Foreground(t.Text())
+	bestStyle := base.Foreground(t.Success()).Bold(true)
+
+	columns := []string{"Model", "Accuracy", "Passed", "Avg latency", "Cost", "Errors", "Rework"}
+	rows := [][]string{}
+	for _, r := range b.results {
+		model := r.Provider + "/" + r.Model
+		rework := "-"
+		if churn, ok := b.churn[model]; ok {
+			rework = fmt.Sprintf("%.0f%%", churn*100)
+		}
+		rows = append(rows, []string{
+			model,
+			fmt.Sprintf("%.0f%%", r.Accuracy*100),
+			fmt.Sprintf("%d/%d", r.Passed, r.Cases),
+			r.AvgLatency.Round(100 * time.Millisecond).String(),
+			fmt.Sprintf("$%.4f", r.Cost),
+			fmt.Sprintf("%d", r.Errors),
+			rework,
+		})
+	}
+
+	widths := make([]int, len(columns))
+	for i, column := range columns {
+		widths[i] = lipgloss.Width(column)
+		for _, row := range rows {
+			widths[i] = max(widths[i], lipgloss.Width(row[i]))
+		}
+	}
+	formatRow := func(cells []string) string {
+		padded := make([]string, len(cells))
+		for i, cell := range cells {
+			padded[i] = cell + strings.Repeat(" ", widths[i]-lipgloss.Width(cell))
+		}
+		return strings.Join(padded, "  ")
+	}
+
+	lines := []string{
+		mutedStyle.Render(fmt.Sprintf("Suite %s · %s", b.suite, b.runAt.Format("2006-01-02 15:04"))),
+		"",
+		headerStyle.Render(formatRow(columns)),
+	}
+	for i, row := range rows {
+		style := rowStyle
+		if i == 0 && len(rows) > 1 {
+			style = bestStyle
+		}
+		lines = append(lines, style.Render(formatRow(row)))
+	}
+	if len(rows) == 0 {
+		lines = append(lines, mutedStyle.Render("No benchmark results yet. Run /bench <suite.yaml> [provider/model...]"))
+	}
+	lines = append(lines, "", mutedStyle.Render("Accuracy is the mean share of assertions passed; errors count as 0."))
+	lines = append(lines, mutedStyle.Render("Rework is the mean share of applied edits later changed by hand."))
+
+	return b.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (b *benchmarkDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewBenchmarkDialog creates a dialog with the results of a benchmark run.
+// churn holds the mean edit rework per "provider/model", when known.
+func NewBenchmarkDialog(results []intelligence.BenchmarkResult, churn map[string]float64) BenchmarkDialog {
+	d := &benchmarkDialog{
+		results: results,
+		churn:   churn,
+		modal:   modal.New(modal.WithTitle("Model Benchmark")),
+	}
+	if len(results) > 0 {
+		d.suite = results[0].Suite
+		d.runAt = results[0].RunAt
+	}
+	return d
+}
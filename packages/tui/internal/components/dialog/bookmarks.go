@@ -0,0 +1,139 @@
+package dialog
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/commands"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// maxBookmarkRows is the number of bookmarks listed at once
+const maxBookmarkRows = 10
+
+// BookmarksDialog lists the bookmarked messages of every session and jumps
+// to the one selected
+type BookmarksDialog interface {
+	layout.Modal
+}
+
+type bookmarksDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	selected int
+}
+
+func (d *bookmarksDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *bookmarksDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyPressMsg)
+	bookmarks := d.app.State.Bookmarks
+	if !ok || len(bookmarks) == 0 {
+		return d, nil
+	}
+	switch key.String() {
+	case "up", "k":
+		d.selected = max(0, d.selected-1)
+	case "down", "j":
+		d.selected = min(len(bookmarks)-1, d.selected+1)
+	case "enter":
+		return d, tea.Sequence(
+			util.CmdHandler(modal.CloseModalMsg{}),
+			d.app.OpenBookmark(bookmarks[d.selected]),
+		)
+	case "x", "delete", "backspace":
+		cmd := d.app.RemoveBookmark(bookmarks[d.selected].MessageID)
+		d.selected = max(0, min(d.selected, len(d.app.State.Bookmarks)-1))
+		return d, cmd
+	}
+	return d, nil
+}
+
+func (d *bookmarksDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+	width := max(40, min(100, layout.Current.Container.Width-12))
+
+	bookmarks := d.app.State.Bookmarks
+	if len(bookmarks) == 0 {
+		return d.modal.Render(mutedStyle.Width(width).Render("No bookmarks yet. "+d.app.Keybind(commands.MessagesBookmarkCommand)+" bookmarks the message in view."), background)
+	}
+
+	var lines []string
+	start := max(0, min(d.selected-maxBookmarkRows/2, len(bookmarks)-maxBookmarkRows))
+	end := min(len(bookmarks), start+maxBookmarkRows)
+	for i := start; i < end; i++ {
+		bookmark := bookmarks[i]
+		prefix := "  "
+		excerptStyle := textStyle
+		if i == d.selected {
+			prefix = "› "
+			excerptStyle = excerptStyle.Foreground(t.Primary()).Bold(true)
+		}
+		title := bookmark.SessionTitle
+		if title == "" {
+			title = "Untitled"
+		}
+		title = ansi.Truncate(title, max(10, width/4), "…")
+		if d.app.Session != nil && bookmark.SessionID == d.app.Session.ID {
+			title += " ●"
+		}
+		author := "you"
+		if bookmark.Role == "assistant" {
+			author = "ai"
+		}
+		date := "  " + bookmark.Created.Format("Jan 2")
+		label := title + "  " + author + ": "
+		excerpt := ansi.Truncate(bookmark.Excerpt, width-lipgloss.Width(prefix+label+date), "…")
+		gap := strings.Repeat(" ", max(0, width-lipgloss.Width(prefix+label+excerpt+date)))
+		lines = append(lines, textStyle.Render(prefix)+mutedStyle.Render(label)+excerptStyle.Render(excerpt)+mutedStyle.Render(gap+date))
+	}
+	if end < len(bookmarks) {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  … %d more", len(bookmarks)-end)))
+	}
+	lines = append(lines, "", help("↑/↓", "select", "enter", "jump to message", "x", "remove"))
+	return d.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (d *bookmarksDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewBookmarksDialog creates the list of bookmarks, with the newest
+// bookmark of the current session selected
+func NewBookmarksDialog(a *app.App) BookmarksDialog {
+	d := &bookmarksDialog{
+		app: a,
+		modal: modal.New(
+			modal.WithTitle("Bookmarks"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	if a.Session != nil {
+		d.selected = max(0, slices.IndexFunc(a.State.Bookmarks, func(b app.Bookmark) bool {
+			return b.SessionID == a.Session.ID
+		}))
+	}
+	return d
+}
@@ -0,0 +1,46 @@
+package dialog
+
+import (
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/intelligence"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+)
+
+// BudgetDialog shows the month-end spending forecast
+type BudgetDialog interface {
+	layout.Modal
+}
+
+type budgetDialog struct {
+	modal  *modal.Modal
+	budget *intelligence.PredictiveBudget
+}
+
+func (b *budgetDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (b *budgetDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	return b, nil
+}
+
+func (b *budgetDialog) Render(background string) string {
+	return b.modal.Render(b.budget.RenderForecast(layout.Current.Container.Width-12), background)
+}
+
+func (b *budgetDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewBudgetDialog creates a dialog for the budget forecast. The forecast is
+// rendered on every frame, so it follows spending while the dialog is open.
+func NewBudgetDialog(budget *intelligence.PredictiveBudget) BudgetDialog {
+	return &budgetDialog{
+		budget: budget,
+		modal: modal.New(
+			modal.WithTitle("Budget Forecast"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+}
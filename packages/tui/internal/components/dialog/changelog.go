@@ -0,0 +1,184 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/changelog"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/components/textarea"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// ChangelogDialog shows drafted release notes for editing before they are
+// written to CHANGELOG.md or published as a GitHub release
+type ChangelogDialog interface {
+	layout.Modal
+}
+
+type changelogPhase int
+
+const (
+	changelogEditing changelogPhase = iota
+	changelogConfirmRelease
+	changelogReleasing
+)
+
+type changelogDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	options  changelog.Options
+	commits  int
+	editor   textarea.Model
+	phase    changelogPhase
+	released string // URL of the created release
+}
+
+func (c *changelogDialog) Init() tea.Cmd {
+	return c.editor.Focus()
+}
+
+func (c *changelogDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case app.ReleaseCreatedMsg:
+		c.phase = changelogEditing
+		if msg.Err != nil {
+			return c, toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Release failed"))
+		}
+		c.released = msg.URL
+		return c, toast.NewSuccessToast(msg.URL, toast.WithTitle("Release "+c.options.Version+" created"))
+	case tea.KeyPressMsg:
+		switch c.phase {
+		case changelogReleasing:
+			return c, nil
+		case changelogConfirmRelease:
+			switch msg.String() {
+			case "y", "enter":
+				c.phase = changelogReleasing
+				return c, c.app.CreateRelease(c.options.Version, c.notes())
+			case "n":
+				c.phase = changelogEditing
+			}
+			return c, nil
+		}
+
+		switch msg.String() {
+		case "ctrl+s":
+			if c.notes() == "" {
+				return c, toast.NewErrorToast("The release notes are empty")
+			}
+			path, err := c.app.WriteChangelog(c.options.Version, c.notes())
+			if err != nil {
+				return c, toast.NewErrorToast(err.Error(), toast.WithTitle("Changelog"))
+			}
+			return c, tea.Sequence(
+				util.CmdHandler(modal.CloseModalMsg{}),
+				toast.NewSuccessToast("Added "+c.options.Version+" to "+path, toast.WithTitle("Changelog updated")),
+			)
+		case "ctrl+r":
+			switch {
+			case c.notes() == "":
+				return c, toast.NewErrorToast("The release notes are empty")
+			case c.options.Version == "Unreleased":
+				return c, toast.NewErrorToast("Name the release tag with /changelog <range> --version <tag>", toast.WithTitle("No release tag"))
+			case c.released != "":
+				return c, toast.NewInfoToast(c.released, toast.WithTitle("Already released"))
+			}
+			c.phase = changelogConfirmRelease
+			return c, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	c.editor, cmd = c.editor.Update(msg)
+	return c, cmd
+}
+
+// notes returns the edited release notes
+func (c *changelogDialog) notes() string {
+	return strings.TrimSpace(c.editor.Value())
+}
+
+func (c *changelogDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+
+	header := mutedStyle.Render(fmt.Sprintf("%s · %s · %d commits", c.options.Version, c.options.Range, c.commits))
+	var footer string
+	switch c.phase {
+	case changelogConfirmRelease:
+		footer = base.Foreground(t.Warning()).Render(fmt.Sprintf("Create GitHub release %s with these notes? ", c.options.Version)) +
+			help("y", "create", "n", "cancel")
+	case changelogReleasing:
+		footer = mutedStyle.Render("Creating release " + c.options.Version + "…")
+	default:
+		footer = help("ctrl+s", "write "+changelog.DefaultPath, "ctrl+r", "create GitHub release", "esc", "discard")
+	}
+	return c.modal.Render(header+"\n\n"+c.editor.View()+"\n\n"+footer, background)
+}
+
+func (c *changelogDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewChangelogDialog creates a dialog for editing drafted release notes
+func NewChangelogDialog(app *app.App, options changelog.Options, commits int, draft string) ChangelogDialog {
+	width := max(40, layout.Current.Container.Width-12)
+	height := max(8, layout.Current.Viewport.Height-14)
+
+	editor := textarea.New()
+	editor.Prompt = ""
+	editor.ShowLineNumbers = false
+	editor.CharLimit = -1
+	editor.MaxHeight = height
+	editor = changelogEditorStyles(editor)
+	editor.SetWidth(width)
+	editor.SetValue(draft)
+	editor.MoveToBegin()
+	editor.SetHeight(height)
+
+	return &changelogDialog{
+		app:     app,
+		options: options,
+		commits: commits,
+		editor:  editor,
+		modal: modal.New(
+			modal.WithTitle("Release Notes"),
+			modal.WithMaxWidth(width+4),
+		),
+	}
+}
+
+// changelogEditorStyles gives the release notes editor the panel background
+func changelogEditorStyles(ta textarea.Model) textarea.Model {
+	t := theme.CurrentTheme()
+	text := styles.NewStyle().Foreground(t.Text()).Background(t.BackgroundPanel()).Lipgloss()
+	background := styles.NewStyle().Background(t.BackgroundPanel()).Lipgloss()
+	placeholder := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundPanel()).Lipgloss()
+
+	ta.Styles.Focused.Base = text
+	ta.Styles.Focused.Text = text
+	ta.Styles.Focused.CursorLine = background
+	ta.Styles.Focused.Placeholder = placeholder
+	ta.Styles.Blurred.Base = text
+	ta.Styles.Blurred.Text = text
+	ta.Styles.Blurred.CursorLine = background
+	ta.Styles.Blurred.Placeholder = placeholder
+	ta.Styles.Cursor.Color = t.Text()
+	return ta
+}
@@ -0,0 +1,95 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// maxClearFiles is the number of uncommitted files listed before clearing
+const maxClearFiles = 8
+
+// ClearDialog confirms clearing a session that changed files which aren't
+// committed yet
+type ClearDialog interface {
+	layout.Modal
+}
+
+type clearDialog struct {
+	modal *modal.Modal
+	files []string
+	then  tea.Cmd
+}
+
+func (d *clearDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *clearDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyPressMsg); ok {
+		switch msg.String() {
+		case "enter", "y":
+			return d, tea.Sequence(
+				util.CmdHandler(modal.CloseModalMsg{}),
+				util.CmdHandler(app.SessionClearedMsg{}),
+				d.then,
+			)
+		case "n":
+			return d, util.CmdHandler(modal.CloseModalMsg{})
+		}
+	}
+	return d, nil
+}
+
+func (d *clearDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	width := max(40, min(72, layout.Current.Container.Width-12))
+
+	lines := []string{
+		textStyle.Width(width).Render(fmt.Sprintf(
+			"This session changed %d file(s) with uncommitted changes:", len(d.files),
+		)),
+		"",
+	}
+	for _, file := range d.files[:min(len(d.files), maxClearFiles)] {
+		lines = append(lines, base.Foreground(t.Warning()).Render("  "+file))
+	}
+	if len(d.files) > maxClearFiles {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  … %d more", len(d.files)-maxClearFiles)))
+	}
+	lines = append(lines,
+		"",
+		mutedStyle.Width(width).Render("The changes stay on disk, and /unclear brings the conversation back."),
+		"",
+		keyStyle.Render("enter")+mutedStyle.Render(" clear   ")+keyStyle.Render("esc")+mutedStyle.Render(" keep the session"),
+	)
+	return d.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (d *clearDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewClearDialog creates a dialog confirming clearing a session with
+// uncommitted files, which runs then once it is cleared
+func NewClearDialog(files []string, then tea.Cmd) ClearDialog {
+	return &clearDialog{
+		files: files,
+		then:  then,
+		modal: modal.New(
+			modal.WithTitle("Clear session?"),
+			modal.WithMaxWidth(80),
+		),
+	}
+}
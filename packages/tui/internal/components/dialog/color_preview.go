@@ -0,0 +1,199 @@
+package dialog
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/lipgloss/v2/compat"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// maxPreviewRows is the number of theme colors listed at once
+const maxPreviewRows = 14
+
+// previewColors are the theme colors the preview lists, those the eye
+// notices most when a palette draws them badly
+var previewColors = []struct {
+	name  string
+	color func(theme.Theme) compat.AdaptiveColor
+}{
+	{"Background", theme.Theme.Background},
+	{"Panel", theme.Theme.BackgroundPanel},
+	{"Element", theme.Theme.BackgroundElement},
+	{"Border", theme.Theme.Border},
+	{"Text", theme.Theme.Text},
+	{"Muted text", theme.Theme.TextMuted},
+	{"Primary", theme.Theme.Primary},
+	{"Secondary", theme.Theme.Secondary},
+	{"Accent", theme.Theme.Accent},
+	{"Error", theme.Theme.Error},
+	{"Warning", theme.Theme.Warning},
+	{"Success", theme.Theme.Success},
+	{"Info", theme.Theme.Info},
+	{"Diff added", theme.Theme.DiffAdded},
+	{"Diff removed", theme.Theme.DiffRemoved},
+	{"Added line", theme.Theme.DiffAddedBg},
+	{"Removed line", theme.Theme.DiffRemovedBg},
+	{"Heading", theme.Theme.MarkdownHeading},
+	{"Link", theme.Theme.MarkdownLink},
+	{"Code", theme.Theme.MarkdownCode},
+	{"Keyword", theme.Theme.SyntaxKeyword},
+	{"Function", theme.Theme.SyntaxFunction},
+	{"String", theme.Theme.SyntaxString},
+	{"Comment", theme.Theme.SyntaxComment},
+}
+
+// ColorPreviewDialog shows the colors of the current theme as each color
+// depth draws them, side by side, and picks the depth themes are drawn in
+type ColorPreviewDialog interface {
+	layout.Modal
+}
+
+type colorPreviewDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	detected theme.ColorDepth
+	selected int // Index of the depth in theme.ColorDepths
+	offset   int // First color listed
+}
+
+func (d *colorPreviewDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *colorPreviewDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return d, nil
+	}
+	switch key.String() {
+	case "left", "h":
+		d.selected = max(0, d.selected-1)
+	case "right", "l", "tab":
+		d.selected = min(len(theme.ColorDepths)-1, d.selected+1)
+	case "up", "k":
+		d.offset = max(0, d.offset-1)
+	case "down", "j":
+		d.offset = min(max(0, len(previewColors)-maxPreviewRows), d.offset+1)
+	case "enter":
+		return d, tea.Sequence(util.CmdHandler(modal.CloseModalMsg{}), d.app.SetColorDepth(theme.ColorDepths[d.selected].String()))
+	case "a":
+		return d, tea.Sequence(util.CmdHandler(modal.CloseModalMsg{}), d.app.SetColorDepth("auto"))
+	}
+	return d, nil
+}
+
+func (d *colorPreviewDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	source := theme.TrueColorTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+	const nameWidth, columnWidth = 14, 14
+
+	lines := []string{
+		mutedStyle.Render(fmt.Sprintf("This terminal is detected to draw %s colors; themes are drawn in %s.", d.detected, theme.CurrentColorDepth())),
+		"",
+	}
+	header := textStyle.Render(strings.Repeat(" ", nameWidth))
+	for i, depth := range theme.ColorDepths {
+		title := depth.String()
+		style := mutedStyle
+		if i == d.selected {
+			title = "› " + title
+			style = base.Foreground(t.Primary()).Bold(true)
+		}
+		header += style.Render(ansi.Truncate(fmt.Sprintf("%-*s", columnWidth, title), columnWidth, ""))
+	}
+	lines = append(lines, header)
+
+	end := min(len(previewColors), d.offset+maxPreviewRows)
+	for _, preview := range previewColors[d.offset:end] {
+		c := themeSide(preview.color(source))
+		row := textStyle.Render(fmt.Sprintf("%-*s", nameWidth, preview.name))
+		for _, depth := range theme.ColorDepths {
+			reduced := theme.Quantize(c, depth)
+			if depth == theme.Depth16 && preview.name == "Background" {
+				reduced = lipgloss.NoColor{}
+			}
+			row += d.swatch(reduced, base, mutedStyle, columnWidth)
+		}
+		lines = append(lines, row)
+	}
+	if end < len(previewColors) {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("… %d more", len(previewColors)-end)))
+	}
+	lines = append(lines, "", help("←/→", "depth", "enter", "draw in it", "a", "detect", "↑/↓", "scroll", "esc", "close"))
+	return d.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+// swatch draws a color as a block and its value: a hex code, a palette
+// index, or the terminal's own color
+func (d *colorPreviewDialog) swatch(c color.Color, base, mutedStyle styles.Style, width int) string {
+	var label string
+	switch c := c.(type) {
+	case lipgloss.NoColor:
+		return mutedStyle.Render(fmt.Sprintf("%-*s", width, "   terminal"))
+	case ansi.BasicColor:
+		label = fmt.Sprintf("%d", c)
+	case ansi.IndexedColor:
+		label = fmt.Sprintf("%d", c)
+	default:
+		hex, _ := colorful.MakeColor(c)
+		label = hex.Hex()
+	}
+	return base.Foreground(compat.AdaptiveColor{Dark: c, Light: c}).Render("██ ") + mutedStyle.Render(fmt.Sprintf("%-*s", width-3, label))
+}
+
+// themeSide returns the side of an adaptive color the terminal's background
+// draws
+func themeSide(c compat.AdaptiveColor) color.Color {
+	if styles.Terminal.BackgroundIsDark {
+		return c.Dark
+	}
+	return c.Light
+}
+
+func (d *colorPreviewDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewColorPreviewDialog creates the preview of the current theme in each
+// color depth, with the depth it is drawn in selected
+func NewColorPreviewDialog(a *app.App) ColorPreviewDialog {
+	current := theme.CurrentColorDepth()
+	selected := 0
+	for i, depth := range theme.ColorDepths {
+		if depth == current {
+			selected = i
+		}
+	}
+	return &colorPreviewDialog{
+		app:      a,
+		detected: theme.DetectColorDepth(os.Getenv),
+		selected: selected,
+		modal: modal.New(
+			modal.WithTitle("Theme colors"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+}
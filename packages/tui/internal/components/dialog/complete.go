@@ -112,8 +112,9 @@ func (c *completionDialogComponent) getAllCompletions(query string) tea.Cmd {
 					displayValues[i] = item.Display(baseStyle)
 				}
 
+				// Ties keep the provider's order, which ranks by the project
 				matches := fuzzy.RankFindFold(query, displayValues)
-				sort.Sort(matches)
+				sort.Stable(matches)
 
 				// Reorder items for this provider based on fuzzy ranking
 				ranked := make([]completions.CompletionSuggestion, 0, len(matches))
@@ -201,6 +202,19 @@ func (c *completionDialogComponent) View() string {
 	t := theme.CurrentTheme()
 	c.list.SetMaxWidth(c.width)
 
+	// What the popup lists and its keys, so that it's found out about
+	hint := "↑↓ select  tab insert  esc close"
+	switch c.trigger {
+	case "@":
+		hint = "files, agents and symbols  " + hint
+	case "/":
+		hint = "commands  " + hint
+	}
+	hint = styles.NewStyle().
+		Foreground(t.TextMuted()).
+		Background(t.BackgroundElement()).
+		Render(truncate.String(hint, uint(max(0, c.width-4))))
+
 	return styles.NewStyle().
 		Padding(0, 1).
 		Foreground(t.Text()).
@@ -211,7 +225,7 @@ func (c *completionDialogComponent) View() string {
 		BorderForeground(t.Border()).
 		BorderBackground(t.Background()).
 		Width(c.width).
-		Render(c.list.View())
+		Render(c.list.View() + "\n" + hint)
 }
 
 func (c *completionDialogComponent) SetWidth(width int) {
@@ -224,12 +238,13 @@ func (c *completionDialogComponent) IsEmpty() bool {
 
 func (c *completionDialogComponent) complete(item completions.CompletionSuggestion) tea.Cmd {
 	value := c.pseudoSearchTextArea.Value()
-	return tea.Batch(
+	// Closed first, so a selection can open another dialog, as /cd's does
+	return tea.Sequence(
+		c.close(),
 		util.CmdHandler(CompletionSelectedMsg{
 			SearchString: value,
 			Item:         item,
 		}),
-		c.close(),
 	)
 }
 
@@ -0,0 +1,134 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+)
+
+// ContextSetDialog lists the files, directories and URLs pinned to the
+// session, with what each adds to every prompt, to remove them
+type ContextSetDialog interface {
+	layout.Modal
+}
+
+type contextSetDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	items    []app.PinnedItem
+	selected int
+}
+
+func (d *contextSetDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *contextSetDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyPressMsg)
+	if !ok || len(d.items) == 0 {
+		return d, nil
+	}
+	switch keyMsg.String() {
+	case "up", "k":
+		d.selected = max(0, d.selected-1)
+	case "down", "j":
+		d.selected = min(len(d.items)-1, d.selected+1)
+	case "d", "delete", "backspace":
+		cmd, err := d.app.UnpinContext(d.items[d.selected].Value)
+		if err != nil {
+			return d, toast.NewErrorToast(err.Error())
+		}
+		d.items = d.app.ContextItems()
+		d.selected = max(0, min(d.selected, len(d.items)-1))
+		return d, cmd
+	}
+	return d, nil
+}
+
+func (d *contextSetDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+
+	name := d.app.ContextSetName()
+	if len(d.items) == 0 {
+		text := "Nothing pinned to this session"
+		if name != "" {
+			text = fmt.Sprintf("Nothing pinned in %s", name)
+		}
+		return d.modal.Render(mutedStyle.Render(text)+"\n\n"+
+			mutedStyle.Render("Pin files, directories or URLs with /context add <path or url>"), background)
+	}
+
+	total := 0
+	for _, item := range d.items {
+		total += item.Tokens
+	}
+	lines := []string{
+		textStyle.Bold(true).Render(name) + mutedStyle.Render(fmt.Sprintf("  ~%s tokens with each prompt", formatTokens(total))),
+		"",
+	}
+	width := max(40, layout.Current.Container.Width-12)
+	for i, item := range d.items {
+		prefix := "  "
+		valueStyle := textStyle
+		if i == d.selected {
+			prefix = "› "
+			valueStyle = valueStyle.Foreground(t.Primary()).Bold(true)
+		}
+		detail := fmt.Sprintf("  %s  ~%s", item.Kind, formatTokens(item.Tokens))
+		switch {
+		case item.Missing:
+			detail = "  missing"
+			valueStyle = valueStyle.Foreground(t.Error())
+		case item.Kind == app.PinnedFile && !item.Included:
+			detail += "  referenced"
+		}
+		value := ansi.Truncate(item.Value, width-len(prefix)-len(detail), "…")
+		lines = append(lines, textStyle.Render(prefix)+valueStyle.Render(value)+mutedStyle.Render(detail))
+	}
+
+	lines = append(lines, "", help("↑/↓", "select", "d", "unpin"))
+	return d.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (d *contextSetDialog) Close() tea.Cmd {
+	return nil
+}
+
+// formatTokens abbreviates a token count, as 1.2k
+func formatTokens(tokens int) string {
+	if tokens < 1000 {
+		return fmt.Sprintf("%d", tokens)
+	}
+	return fmt.Sprintf("%.1fk", float64(tokens)/1000)
+}
+
+// NewContextSetDialog creates a dialog listing the session's pinned context
+func NewContextSetDialog(app *app.App) ContextSetDialog {
+	return &contextSetDialog{
+		app:   app,
+		items: app.ContextItems(),
+		modal: modal.New(
+			modal.WithTitle("Pinned Context"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+}
@@ -0,0 +1,218 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/auth"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/intelligence"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+)
+
+// maxCostRows is the number of groups listed at once
+const maxCostRows = 12
+
+// CostDialog breaks the recorded spending down by session, provider, model
+// or day, and exports it as CSV
+type CostDialog interface {
+	layout.Modal
+}
+
+type costDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	records  []intelligence.CostRecord
+	grouping int // Index in intelligence.CostGroupings
+	lines    []intelligence.CostLine
+	offset   int
+	summary  *auth.CostSummary // nil until the auth bridge answers, or when it can't
+}
+
+func (d *costDialog) Init() tea.Cmd {
+	return d.app.FetchCostSummary()
+}
+
+func (d *costDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case app.CostSummaryMsg:
+		if msg.Err == nil {
+			d.summary = msg.Summary
+		}
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "tab", "right", "l":
+			d.group((d.grouping + 1) % len(intelligence.CostGroupings))
+		case "shift+tab", "left", "h":
+			d.group((d.grouping + len(intelligence.CostGroupings) - 1) % len(intelligence.CostGroupings))
+		case "s":
+			d.group(0)
+		case "p":
+			d.group(1)
+		case "m":
+			d.group(2)
+		case "d":
+			d.group(3)
+		case "up", "k":
+			d.offset = max(0, d.offset-1)
+		case "down", "j":
+			d.offset = max(0, min(len(d.lines)-maxCostRows, d.offset+1))
+		case "e":
+			return d, d.app.ExportCosts()
+		}
+	}
+	return d, nil
+}
+
+// group adds the records up by the grouping at an index
+func (d *costDialog) group(index int) {
+	d.grouping = index
+	d.lines = intelligence.CostBreakdown(d.records, intelligence.CostGroupings[index])
+	d.offset = 0
+}
+
+// label names a group as the dialog lists it
+func (d *costDialog) label(line intelligence.CostLine) string {
+	switch intelligence.CostGroupings[d.grouping] {
+	case intelligence.CostByProvider:
+		for _, provider := range d.app.Providers {
+			if provider.ID == line.Key {
+				return provider.Name
+			}
+		}
+	case intelligence.CostBySession:
+		if d.app.Session != nil && line.Key == d.app.Session.ID {
+			return line.Label + " ●"
+		}
+	}
+	return line.Label
+}
+
+func (d *costDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+	width := max(50, min(100, layout.Current.Container.Width-12))
+
+	var lines []string
+	lines = append(lines, d.renderSummary(textStyle, mutedStyle), "")
+
+	var tabs []string
+	for i, grouping := range intelligence.CostGroupings {
+		name := " By " + string(grouping) + " "
+		if i == d.grouping {
+			tabs = append(tabs, base.Foreground(t.BackgroundPanel()).Background(t.Primary()).Bold(true).Render(name))
+		} else {
+			tabs = append(tabs, mutedStyle.Render(name))
+		}
+	}
+	lines = append(lines, strings.Join(tabs, mutedStyle.Render(" ")), "")
+
+	if len(d.records) == 0 {
+		lines = append(lines, mutedStyle.Width(width).Render("No costs recorded yet. Every completed response is recorded from now on."))
+	} else {
+		const columns = 34 // Width of the requests, tokens and cost columns
+		row := func(style styles.Style, label, requests, tokens, cost string) string {
+			label = ansi.Truncate(label, width-columns-1, "…")
+			gap := strings.Repeat(" ", max(1, width-columns-lipgloss.Width(label)))
+			return style.Render(label+gap) + mutedStyle.Render(fmt.Sprintf("%8s %12s", requests, tokens)) + style.Render(fmt.Sprintf("%14s", cost))
+		}
+		lines = append(lines, row(mutedStyle, "", "requests", "tokens", "cost"))
+		end := min(len(d.lines), d.offset+maxCostRows)
+		for _, line := range d.lines[d.offset:end] {
+			lines = append(lines, row(textStyle, d.label(line), fmt.Sprint(line.Requests), formatTokenCount(line.Tokens), fmt.Sprintf("$%.4f", line.Cost)))
+		}
+		if end < len(d.lines) {
+			lines = append(lines, mutedStyle.Render(fmt.Sprintf("… %d more", len(d.lines)-end)))
+		}
+		var total float64
+		var requests int
+		var tokens int64
+		for _, record := range d.records {
+			total += record.Cost
+			requests++
+			tokens += record.Tokens()
+		}
+		since := d.records[0].Time.Local().Format("Jan 2, 2006")
+		lines = append(lines, "", row(keyStyle, "Total since "+since, fmt.Sprint(requests), formatTokenCount(tokens), fmt.Sprintf("$%.4f", total)))
+	}
+
+	lines = append(lines, "", help("tab", "group by", "↑/↓", "scroll", "e", "export CSV"))
+	return d.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+// renderSummary renders today's and the month's spending, from the auth
+// bridge when it answers and from the recorded costs otherwise
+func (d *costDialog) renderSummary(textStyle, mutedStyle styles.Style) string {
+	var today, month float64
+	var projection string
+	if d.summary != nil {
+		today, month = d.summary.TodayCost, d.summary.MonthCost
+		if d.summary.Projection > 0 {
+			projection = fmt.Sprintf("$%.2f", d.summary.Projection)
+		}
+	} else {
+		now := time.Now()
+		for _, record := range d.records {
+			local := record.Time.Local()
+			if local.Year() == now.Year() && local.Month() == now.Month() {
+				month += record.Cost
+				if local.Day() == now.Day() {
+					today += record.Cost
+				}
+			}
+		}
+	}
+	summary := mutedStyle.Render("Today ") + textStyle.Render(fmt.Sprintf("$%.2f", today)) +
+		mutedStyle.Render("   This month ") + textStyle.Render(fmt.Sprintf("$%.2f", month))
+	if projection != "" {
+		summary += mutedStyle.Render("   Month-end projection ") + textStyle.Render(projection)
+	}
+	return summary
+}
+
+// formatTokenCount shortens a token count, as 12.3K or 4.5M
+func formatTokenCount(tokens int64) string {
+	switch {
+	case tokens >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(tokens)/1_000_000)
+	case tokens >= 1_000:
+		return fmt.Sprintf("%.1fK", float64(tokens)/1_000)
+	}
+	return fmt.Sprint(tokens)
+}
+
+func (d *costDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewCostDialog creates the cost breakdown of the recorded responses,
+// grouped by session
+func NewCostDialog(a *app.App) CostDialog {
+	d := &costDialog{
+		app:     a,
+		records: a.CostLedger().Records(),
+		modal: modal.New(
+			modal.WithTitle("Cost Breakdown"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	d.group(0)
+	return d
+}
@@ -0,0 +1,177 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/diff"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// maxConflictLines is the number of lines each side of a region shows
+const maxConflictLines = 8
+
+// EditConflictDialog shows where an edit and the changes made to its file
+// on disk since it was asked for touch the same lines, each region as the
+// file was, as it is now and as the edit would make it
+type EditConflictDialog interface {
+	layout.Modal
+}
+
+type editConflictDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	conflict *app.EditConflict
+	selected int // Overlap shown
+}
+
+func (d *editConflictDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *editConflictDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return d, nil
+	}
+	switch key.String() {
+	case "left", "up", "k":
+		d.selected = max(0, d.selected-1)
+	case "right", "down", "j", "tab":
+		d.selected = min(len(d.conflict.Overlaps)-1, d.selected+1)
+	case "y":
+		return d, d.resolve(false)
+	case "o":
+		return d, d.resolve(true)
+	}
+	return d, nil
+}
+
+func (d *editConflictDialog) resolve(overwrite bool) tea.Cmd {
+	return tea.Sequence(
+		util.CmdHandler(modal.CloseModalMsg{}),
+		util.CmdHandler(app.EditConflictResolvedMsg{Conflict: d.conflict, Overwrite: overwrite}),
+	)
+}
+
+func (d *editConflictDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+	width := max(40, min(100, layout.Current.Container.Width-12))
+
+	c := d.conflict
+	overlap := c.Overlaps[d.selected]
+	hunk, change := c.Hunks[overlap.Hunk], c.Changes[overlap.Change]
+	original := oldSide(hunk)
+
+	section := func(title string, lines []string, style func(string) (string, string)) []string {
+		out := []string{textStyle.Bold(true).Render(title)}
+		for i, line := range lines {
+			if i == maxConflictLines {
+				out = append(out, mutedStyle.Render(fmt.Sprintf("  … %d more lines", len(lines)-i)))
+				break
+			}
+			marker, text := style(line)
+			out = append(out, marker+textStyle.Render(ansi.Truncate(strings.ReplaceAll(text, "\t", "    "), width-2, "…")))
+		}
+		return out
+	}
+	plain := func(line string) (string, string) {
+		return mutedStyle.Render("  "), line
+	}
+
+	lines := []string{
+		mutedStyle.Render(fmt.Sprintf("%s changed on disk while the edit waited. Region %d of %d where both changed the same lines:",
+			util.Relative(c.FilePath), d.selected+1, len(c.Overlaps))),
+		"",
+	}
+	lines = append(lines, section("Original", original, plain)...)
+	lines = append(lines, "")
+	lines = append(lines, section("Yours (on disk)", changedLines(change), d.marked)...)
+	lines = append(lines, "")
+	lines = append(lines, section("AI", changedLines(hunk), d.marked)...)
+	lines = append(lines, "")
+	if len(c.Rebased) > 0 {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("%d other hunks of the edit don't touch your changes.", len(c.Rebased))), "")
+	}
+	lines = append(lines, help("←/→", "region", "y", "keep yours, reject edit", "o", "overwrite with AI edit", "esc", "back"))
+	return d.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+// marked splits a line of changedLines into its colored marker and text
+func (d *editConflictDialog) marked(line string) (string, string) {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	switch {
+	case strings.HasPrefix(line, "-"):
+		return base.Foreground(t.DiffRemoved()).Render("- "), line[1:]
+	case strings.HasPrefix(line, "+"):
+		return base.Foreground(t.DiffAdded()).Render("+ "), line[1:]
+	}
+	return base.Render("  "), strings.TrimPrefix(line, " ")
+}
+
+// oldSide returns the lines of the file a hunk covers, before it changes
+// them
+func oldSide(hunk diff.Hunk) []string {
+	var lines []string
+	for _, line := range hunk.Lines {
+		switch line.Kind {
+		case diff.LineContext:
+			lines = append(lines, strings.TrimPrefix(line.Content, " "))
+		case diff.LineRemoved:
+			lines = append(lines, line.Content)
+		}
+	}
+	return lines
+}
+
+// changedLines returns the lines of a hunk marked as in a unified diff
+func changedLines(hunk diff.Hunk) []string {
+	var lines []string
+	for _, line := range hunk.Lines {
+		switch line.Kind {
+		case diff.LineAdded:
+			lines = append(lines, "+"+line.Content)
+		case diff.LineRemoved:
+			lines = append(lines, "-"+line.Content)
+		default:
+			lines = append(lines, " "+strings.TrimPrefix(line.Content, " "))
+		}
+	}
+	return lines
+}
+
+func (d *editConflictDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewEditConflictDialog creates the three-way view of an edit whose file
+// changed on disk in the same lines, which has at least one overlap
+func NewEditConflictDialog(a *app.App, conflict *app.EditConflict) EditConflictDialog {
+	return &editConflictDialog{
+		app:      a,
+		conflict: conflict,
+		modal: modal.New(
+			modal.WithTitle("Edit conflicts with changes on disk"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+}
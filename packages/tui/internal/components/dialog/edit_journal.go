@@ -0,0 +1,143 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+)
+
+// maxJournalRows is the number of lines of edits listed at once
+const maxJournalRows = 14
+
+// EditJournalDialog lists the file edits of the session's tool calls, by
+// message, to undo or redo them in the worktree
+type EditJournalDialog interface {
+	layout.Modal
+}
+
+type editJournalDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	edits    []app.JournalEdit
+	selected int
+}
+
+func (d *editJournalDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *editJournalDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyPressMsg)
+	if !ok || len(d.edits) == 0 {
+		return d, nil
+	}
+	edit := d.edits[d.selected]
+	var cmd tea.Cmd
+	switch keyMsg.String() {
+	case "up", "k":
+		d.selected = max(0, d.selected-1)
+	case "down", "j":
+		d.selected = min(len(d.edits)-1, d.selected+1)
+	case "u":
+		cmd = d.app.UndoFileEdits(edit.PartID)
+	case "r":
+		cmd = d.app.RedoFileEdits(edit.PartID)
+	case "m":
+		cmd = d.app.UndoMessageEdits(edit.MessageID)
+	case "M":
+		cmd = d.app.RedoMessageEdits(edit.MessageID)
+	}
+	d.edits = d.app.JournalEdits()
+	return d, cmd
+}
+
+func (d *editJournalDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+
+	if len(d.edits) == 0 {
+		return d.modal.Render(mutedStyle.Render("No file edits in this session yet"), background)
+	}
+
+	// Each message's edits are listed under its prompt
+	width := max(40, layout.Current.Container.Width-12)
+	var rows []string
+	selectedRow := 0
+	for i, edit := range d.edits {
+		if i == 0 || edit.MessageID != d.edits[i-1].MessageID {
+			prompt := strings.Join(strings.Fields(d.app.JournalPrompt(edit.MessageID)), " ")
+			if prompt == "" {
+				prompt = edit.Time.Format("15:04") + " message"
+			}
+			if i > 0 {
+				rows = append(rows, "")
+			}
+			rows = append(rows, mutedStyle.Bold(true).Render(ansi.Truncate(prompt, width, "…")))
+		}
+		prefix := "  "
+		pathStyle := textStyle
+		if i == d.selected {
+			prefix = "› "
+			pathStyle = pathStyle.Foreground(t.Primary()).Bold(true)
+			selectedRow = len(rows)
+		}
+		stats := edit.Stats()
+		state := ""
+		if edit.Created {
+			state = "  new"
+		}
+		if edit.Undone {
+			pathStyle = pathStyle.Strikethrough(true)
+			state += "  undone"
+		}
+		rows = append(rows, textStyle.Render(prefix)+pathStyle.Render(edit.DisplayPath())+
+			base.Foreground(t.Success()).Render(fmt.Sprintf("  +%d", stats.Added))+
+			base.Foreground(t.Error()).Render(fmt.Sprintf(" -%d", stats.Removed))+
+			mutedStyle.Render(state))
+	}
+	start := max(0, min(selectedRow-maxJournalRows/2, len(rows)-maxJournalRows))
+	end := min(len(rows), start+maxJournalRows)
+	lines := rows[start:end]
+	if end < len(rows) {
+		lines = append(lines, mutedStyle.Render("  …"))
+	}
+
+	lines = append(lines, "", help("↑/↓", "select", "u/r", "undo/redo file", "m/M", "undo/redo message"))
+	return d.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (d *editJournalDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewEditJournalDialog creates a dialog listing the file edits of the
+// current session, selecting the latest
+func NewEditJournalDialog(app *app.App) EditJournalDialog {
+	edits := app.JournalEdits()
+	return &editJournalDialog{
+		app:      app,
+		edits:    edits,
+		selected: max(0, len(edits)-1),
+		modal: modal.New(
+			modal.WithTitle("Edits"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+}
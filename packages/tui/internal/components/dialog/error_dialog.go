@@ -97,6 +97,21 @@ func NewAuthError(provider, errorMsg string) *ErrorDialog {
 			"Check your usage limits",
 		).WithDocsLink("https://x.ai/api")
 
+	case "azure":
+		dialog.WithSuggestions(
+			"Set AZURE_OPENAI_ENDPOINT (or AZURE_RESOURCE_NAME) and AZURE_OPENAI_API_KEY",
+			"Copy the key from Keys and Endpoint of your resource in portal.azure.com",
+			"List your deployments in AZURE_OPENAI_DEPLOYMENTS if they can't be listed",
+		).WithDocsLink("https://learn.microsoft.com/azure/ai-services/openai/")
+
+	case "amazon-bedrock", "bedrock", "aws":
+		dialog.WithSuggestions(
+			"Set AWS_PROFILE, or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY",
+			"Refresh expired STS or SSO credentials with aws sso login",
+			"Set AWS_REGION to a region where Bedrock is available",
+			"Request access to the models in the Bedrock console",
+		).WithDocsLink("https://docs.aws.amazon.com/bedrock/latest/userguide/getting-started.html")
+
 	case "qwen", "alibaba":
 		dialog.WithSuggestions(
 			"Verify your API key at dashscope.aliyun.com",
@@ -0,0 +1,136 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/intelligence"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+)
+
+// ExperimentDialog reports the per-arm outcomes of an A/B routing experiment
+type ExperimentDialog interface {
+	layout.Modal
+}
+
+type experimentDialog struct {
+	modal      *modal.Modal
+	experiment *intelligence.Experiment
+	active     bool
+}
+
+func (e *experimentDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (e *experimentDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	return e, nil
+}
+
+func (e *experimentDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	mutedStyle := base.Foreground(t.TextMuted())
+	headerStyle := base.Foreground(t.Text()).Bold(true)
+	rowStyle := base.Foreground(t.Text())
+	winnerStyle := base.Foreground(t.Success()).Bold(true)
+
+	if e.experiment == nil {
+		lines := []string{
+			mutedStyle.Render("No experiment has been run yet."),
+			"",
+			mutedStyle.Render("/experiment start <provider/model> <provider/model> [percent]"),
+			mutedStyle.Render("/experiment good | bad    rate the last routed answer"),
+			mutedStyle.Render("/experiment stop"),
+		}
+		return e.modal.Render(strings.Join(lines, "\n"), background)
+	}
+
+	winner, decided := e.experiment.Winner()
+	columns := []string{"Arm", "Model", "Prompts", "Accepted", "Retried", "👍", "👎", "Score"}
+	rows := [][]string{}
+	for _, arm := range []intelligence.ExperimentArm{intelligence.ArmA, intelligence.ArmB} {
+		outcome := e.experiment.Outcomes[arm]
+		rows = append(rows, []string{
+			string(arm),
+			e.experiment.Model(arm),
+			fmt.Sprintf("%d", outcome.Prompts),
+			fmt.Sprintf("%d", outcome.Accepts),
+			fmt.Sprintf("%d", outcome.Retries),
+			fmt.Sprintf("%d", outcome.Positive),
+			fmt.Sprintf("%d", outcome.Negative),
+			fmt.Sprintf("%+.2f", outcome.Score()),
+		})
+	}
+
+	widths := make([]int, len(columns))
+	for i, column := range columns {
+		widths[i] = lipgloss.Width(column)
+		for _, row := range rows {
+			widths[i] = max(widths[i], lipgloss.Width(row[i]))
+		}
+	}
+	formatRow := func(cells []string) string {
+		padded := make([]string, len(cells))
+		for i, cell := range cells {
+			padded[i] = cell + strings.Repeat(" ", widths[i]-lipgloss.Width(cell))
+		}
+		return strings.Join(padded, "  ")
+	}
+
+	status := "finished " + e.experiment.EndedAt.Format("2006-01-02 15:04")
+	if e.active {
+		status = "running"
+	}
+	lines := []string{
+		mutedStyle.Render(fmt.Sprintf(
+			"Started %s · %s · %d%% of prompts routed to B",
+			e.experiment.StartedAt.Format("2006-01-02 15:04"),
+			status,
+			e.experiment.SplitPercent,
+		)),
+		"",
+		headerStyle.Render(formatRow(columns)),
+	}
+	for _, row := range rows {
+		style := rowStyle
+		if decided && intelligence.ExperimentArm(row[0]) == winner {
+			style = winnerStyle
+		}
+		lines = append(lines, style.Render(formatRow(row)))
+	}
+
+	lines = append(lines, "")
+	if decided {
+		lines = append(lines, winnerStyle.Render(fmt.Sprintf("Winner: %s", e.experiment.Model(winner))))
+	} else {
+		lines = append(lines, mutedStyle.Render("No winner yet: each arm needs more accepted or retried answers."))
+	}
+	lines = append(lines, mutedStyle.Render("Score weighs accepts and 👍 against retries and 👎, from -1 to +1."))
+
+	return e.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (e *experimentDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewExperimentDialog creates a dialog reporting the active experiment, or the
+// most recently finished one when none is running
+func NewExperimentDialog(store *intelligence.ExperimentStore) ExperimentDialog {
+	d := &experimentDialog{
+		modal: modal.New(modal.WithTitle("Model Experiment")),
+	}
+	if store.Active != nil {
+		d.experiment = store.Active
+		d.active = true
+	} else if len(store.Finished) > 0 {
+		d.experiment = &store.Finished[len(store.Finished)-1]
+	}
+	return d
+}
@@ -0,0 +1,88 @@
+package dialog
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+)
+
+// FailoverDialog shows whether provider failover is enabled and the
+// failovers of the current session
+type FailoverDialog interface {
+	layout.Modal
+}
+
+type failoverDialog struct {
+	modal   *modal.Modal
+	enabled bool
+	entries []app.FailoverEntry
+	err     error
+}
+
+func (f *failoverDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (f *failoverDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	return f, nil
+}
+
+func (f *failoverDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+
+	status := base.Foreground(t.Success()).Render("on")
+	hint := "turn it off with /failover off"
+	if !f.enabled {
+		status = base.Foreground(t.Warning()).Render("off")
+		hint = "turn it on with /failover on"
+	}
+	lines := []string{
+		textStyle.Render("Failover is ") + status + mutedStyle.Render(" · "+hint),
+		mutedStyle.Render("Prompts that hit a rate limit or server error are retried on the next authenticated provider."),
+		"",
+	}
+
+	switch {
+	case f.err != nil:
+		lines = append(lines, base.Foreground(t.Error()).Render(f.err.Error()))
+	case len(f.entries) == 0:
+		lines = append(lines, mutedStyle.Render("No failovers in this session."))
+	default:
+		width := max(40, layout.Current.Container.Width-12)
+		for _, entry := range f.entries {
+			lines = append(lines,
+				textStyle.Render(entry.Time.Format("15:04:05")+"  "+entry.FromProvider+"/"+entry.FromModel+" → "+entry.ToProvider+"/"+entry.ToModel),
+				mutedStyle.Width(width).Render("          "+entry.Reason),
+			)
+		}
+	}
+	return f.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (f *failoverDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewFailoverDialog creates a dialog for the failover status and the audit
+// log of a session
+func NewFailoverDialog(a *app.App, sessionID string) FailoverDialog {
+	f := &failoverDialog{
+		enabled: a.FailoverEnabled(),
+		modal: modal.New(
+			modal.WithTitle("Provider Failover"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	if sessionID != "" {
+		f.entries, f.err = a.FailoverAudit(sessionID)
+	}
+	return f
+}
@@ -0,0 +1,333 @@
+package dialog
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/list"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// numVisibleFiles is the number of files and directories listed at once
+const numVisibleFiles = 14
+
+// FilePickedMsg asks for a file, at a path relative to the project root, to
+// be referenced in the prompt
+type FilePickedMsg struct {
+	Path string
+}
+
+// FilePickerDialog browses the project's files as a tree, or finds them by
+// fuzzy search, to reference one in the prompt or read it in the viewer.
+// Files ignored by git are left out of both.
+type FilePickerDialog interface {
+	layout.Modal
+}
+
+// filesListedMsg is sent with the entries of a directory of the tree
+type filesListedMsg struct {
+	dir   string
+	nodes []opencode.FileNode
+	err   error
+}
+
+// filesFoundMsg is sent with the files matching a query
+type filesFoundMsg struct {
+	query string
+	files []string
+	err   error
+}
+
+type filePickerDialog struct {
+	app          *app.App
+	modal        *modal.Modal
+	searchDialog *SearchDialog
+	children     map[string][]opencode.FileNode // Entries of the directories listed, by path
+	expanded     map[string]bool
+	viewer       *fileViewerDialog
+	err          error
+}
+
+// fileEntry is a file or directory of the tree or of the search results
+type fileEntry struct {
+	path     string // Relative to the project root
+	dir      bool
+	depth    int
+	expanded bool
+	search   bool // A search result, shown with its full path
+}
+
+func (f fileEntry) Render(selected bool, width int, baseStyle styles.Style) string {
+	t := theme.CurrentTheme()
+	base := baseStyle.Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	if selected {
+		textStyle = textStyle.Foreground(t.Primary()).Bold(true)
+	}
+
+	name := filepath.Base(f.path)
+	if f.search {
+		name = f.path
+	}
+	icon := "  "
+	if f.dir {
+		icon = "▸ "
+		if f.expanded {
+			icon = "▾ "
+		}
+		name += "/"
+	}
+	indent := strings.Repeat("  ", f.depth)
+	line := mutedStyle.Render(" "+indent+icon) + textStyle.Render(ansi.Truncate(name, max(10, width-len(indent)-4), "…"))
+	return base.Width(width).Render(line)
+}
+
+func (f fileEntry) Selectable() bool {
+	return true
+}
+
+func (p *filePickerDialog) Init() tea.Cmd {
+	return tea.Batch(p.searchDialog.Init(), p.listDir(""))
+}
+
+// listDir lists the entries of a directory of the tree
+func (p *filePickerDialog) listDir(dir string) tea.Cmd {
+	client := p.app.Client
+	return func() tea.Msg {
+		nodes, err := client.File.List(context.Background(), opencode.FileListParams{Path: opencode.F(dir)})
+		if err != nil {
+			return filesListedMsg{dir: dir, err: err}
+		}
+		return filesListedMsg{dir: dir, nodes: *nodes}
+	}
+}
+
+// find searches the project's files for query
+func (p *filePickerDialog) find(query string) tea.Cmd {
+	client := p.app.Client
+	return func() tea.Msg {
+		files, err := client.Find.Files(context.Background(), opencode.FindFilesParams{Query: opencode.F(query)})
+		if err != nil {
+			return filesFoundMsg{query: query, err: err}
+		}
+		return filesFoundMsg{query: query, files: *files}
+	}
+}
+
+func (p *filePickerDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if p.viewer != nil {
+		if key, ok := msg.(tea.KeyPressMsg); ok && !p.viewer.capturing() {
+			switch key.String() {
+			case "backspace", "left":
+				p.viewer = nil
+				return p, nil
+			case "enter":
+				return p, p.pick(p.viewer.path)
+			}
+		}
+		_, cmd := p.viewer.Update(msg)
+		return p, cmd
+	}
+
+	switch msg := msg.(type) {
+	case filesListedMsg:
+		p.err = msg.err
+		if msg.err == nil {
+			p.children[msg.dir] = msg.nodes
+			if p.searchDialog.GetQuery() == "" {
+				p.showTree(p.selectedPath())
+			}
+		}
+		return p, nil
+	case filesFoundMsg:
+		// Results of a query since changed are dropped
+		if msg.query != p.searchDialog.GetQuery() {
+			return p, nil
+		}
+		p.err = msg.err
+		items := make([]list.Item, 0, len(msg.files))
+		for _, file := range msg.files {
+			if strings.HasSuffix(file, "/") {
+				continue
+			}
+			items = append(items, fileEntry{path: file, search: true})
+		}
+		p.searchDialog.SetItems(items)
+		return p, nil
+	case SearchQueryChangedMsg:
+		if strings.TrimSpace(msg.Query) == "" {
+			p.showTree("")
+			return p, nil
+		}
+		return p, p.find(msg.Query)
+	case SearchSelectionMsg:
+		entry, ok := msg.Item.(fileEntry)
+		if !ok {
+			return p, nil
+		}
+		if entry.dir {
+			return p, p.toggle(entry.path)
+		}
+		return p, p.pick(entry.path)
+	case SearchCancelledMsg:
+		return p, util.CmdHandler(modal.CloseModalMsg{})
+	case tea.KeyPressMsg:
+		entry, ok := p.selected()
+		switch msg.String() {
+		case "tab":
+			// Opens a file in the viewer, or a directory in the tree
+			if !ok {
+				return p, nil
+			}
+			if entry.dir {
+				return p, p.toggle(entry.path)
+			}
+			p.viewer = newFileViewer(entry.path, 1)
+			return p, nil
+		case "right":
+			if ok && entry.dir && !p.expanded[entry.path] {
+				return p, p.toggle(entry.path)
+			}
+		case "left":
+			if ok && entry.dir && p.expanded[entry.path] {
+				return p, p.toggle(entry.path)
+			}
+		}
+	}
+
+	updated, cmd := p.searchDialog.Update(msg)
+	p.searchDialog = updated.(*SearchDialog)
+	return p, cmd
+}
+
+// pick references a file in the prompt and closes the picker
+func (p *filePickerDialog) pick(path string) tea.Cmd {
+	return tea.Sequence(
+		util.CmdHandler(modal.CloseModalMsg{}),
+		util.CmdHandler(FilePickedMsg{Path: path}),
+	)
+}
+
+// toggle expands or collapses a directory of the tree, listing it the first
+// time
+func (p *filePickerDialog) toggle(dir string) tea.Cmd {
+	p.expanded[dir] = !p.expanded[dir]
+	if _, listed := p.children[dir]; p.expanded[dir] && !listed {
+		return p.listDir(dir)
+	}
+	p.showTree(dir)
+	return nil
+}
+
+func (p *filePickerDialog) selected() (fileEntry, bool) {
+	item, idx := p.searchDialog.list.GetSelectedItem()
+	if idx == -1 {
+		return fileEntry{}, false
+	}
+	entry, ok := item.(fileEntry)
+	return entry, ok
+}
+
+func (p *filePickerDialog) selectedPath() string {
+	entry, _ := p.selected()
+	return entry.path
+}
+
+// showTree lists the expanded directories of the tree, keeping the entry
+// at selected in view
+func (p *filePickerDialog) showTree(selected string) {
+	var items []list.Item
+	var walk func(dir string, depth int)
+	walk = func(dir string, depth int) {
+		nodes := slices.Clone(p.children[dir])
+		slices.SortFunc(nodes, func(a, b opencode.FileNode) int {
+			if a.Type != b.Type {
+				if a.Type == opencode.FileNodeTypeDirectory {
+					return -1
+				}
+				return 1
+			}
+			return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		})
+		for _, node := range nodes {
+			if node.Ignored || node.Name == ".git" {
+				continue
+			}
+			dir := node.Type == opencode.FileNodeTypeDirectory
+			items = append(items, fileEntry{
+				path:     node.Path,
+				dir:      dir,
+				depth:    depth,
+				expanded: dir && p.expanded[node.Path],
+			})
+			if dir && p.expanded[node.Path] {
+				walk(node.Path, depth+1)
+			}
+		}
+	}
+	walk("", 0)
+	p.searchDialog.SetItems(items)
+	for i, item := range items {
+		if item.(fileEntry).path == selected {
+			p.searchDialog.list.SetSelectedIndex(i)
+			break
+		}
+	}
+}
+
+func (p *filePickerDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+
+	if p.viewer != nil {
+		return p.modal.Render(p.viewer.View()+"\n\n"+help("enter", "reference in prompt", "backspace", "back"), background)
+	}
+
+	footer := help("enter", "reference in prompt", "tab", "view", "←/→", "collapse/expand")
+	if p.err != nil {
+		footer = base.Foreground(t.Error()).Render(p.err.Error())
+	}
+	return p.modal.Render(p.searchDialog.View()+"\n"+footer, background)
+}
+
+func (p *filePickerDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewFilePickerDialog creates a dialog browsing and searching the project's
+// files
+func NewFilePickerDialog(app *app.App) FilePickerDialog {
+	width := max(60, layout.Current.Container.Width-12)
+	searchDialog := NewSearchDialog("Search files...", numVisibleFiles)
+	searchDialog.SetWidth(width)
+	return &filePickerDialog{
+		app:          app,
+		searchDialog: searchDialog,
+		children:     make(map[string][]opencode.FileNode),
+		expanded:     make(map[string]bool),
+		modal: modal.New(
+			modal.WithTitle("Files"),
+			modal.WithMaxWidth(width+4),
+		),
+	}
+}
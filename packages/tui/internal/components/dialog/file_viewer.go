@@ -0,0 +1,407 @@
+package dialog
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/textinput"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/diff"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+	"github.com/aaronmrosenthal/rycode/internal/viewport"
+)
+
+// maxHighlightBytes bounds the files highlighted; larger ones are shown as
+// plain text
+const maxHighlightBytes = 512 * 1024
+
+// FileViewerDialog shows a read-only file with syntax highlighting, from a
+// cursor line that can be moved, searched to, or jumped to by number. A
+// range of lines can be selected and copied.
+type FileViewerDialog interface {
+	layout.Modal
+}
+
+// viewerPrompt is what the viewer's input line asks for
+type viewerPrompt int
+
+const (
+	viewerNoPrompt viewerPrompt = iota
+	viewerSearch
+	viewerGoTo
+)
+
+type fileViewerDialog struct {
+	modal       *modal.Modal
+	viewport    viewport.Model
+	path        string
+	line        int // Cursor line, 1-based
+	lines       []string
+	highlighted []string // Lines with syntax highlighting, nil when the file isn't highlighted
+	anchor      int      // Line the selected range started on, 0 without a range
+	prompt      viewerPrompt
+	input       textinput.Model
+	query       string
+	matches     []int // Lines matching the query
+	err         error
+}
+
+func (f *fileViewerDialog) Init() tea.Cmd {
+	return f.viewport.Init()
+}
+
+// capturing reports whether the viewer's input line takes the keys, which
+// the dialogs embedding the viewer then leave to it
+func (f *fileViewerDialog) capturing() bool {
+	return f.prompt != viewerNoPrompt
+}
+
+func (f *fileViewerDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		f.resize()
+		return f, nil
+	case tea.KeyPressMsg:
+		if f.capturing() {
+			return f, f.promptKey(msg)
+		}
+		return f, f.key(msg)
+	}
+	var cmd tea.Cmd
+	f.viewport, cmd = f.viewport.Update(msg)
+	return f, cmd
+}
+
+// key moves the cursor, or starts a search, a jump or a selection
+func (f *fileViewerDialog) key(msg tea.KeyPressMsg) tea.Cmd {
+	page := max(1, f.viewport.Height()-1)
+	switch msg.String() {
+	case "up", "k":
+		f.moveTo(f.line - 1)
+	case "down", "j":
+		f.moveTo(f.line + 1)
+	case "pgup", "ctrl+b":
+		f.moveTo(f.line - page)
+	case "pgdown", "ctrl+f", " ":
+		f.moveTo(f.line + page)
+	case "ctrl+u":
+		f.moveTo(f.line - page/2)
+	case "ctrl+d":
+		f.moveTo(f.line + page/2)
+	case "home", "g":
+		f.moveTo(1)
+	case "end", "G":
+		f.moveTo(len(f.lines))
+	case "/":
+		return f.startPrompt(viewerSearch)
+	case ":":
+		return f.startPrompt(viewerGoTo)
+	case "n":
+		f.nextMatch(1)
+	case "N":
+		f.nextMatch(-1)
+	case "v":
+		if f.anchor != 0 {
+			f.anchor = 0
+		} else {
+			f.anchor = f.line
+		}
+		f.renderContent()
+	case "y":
+		return f.copyRange()
+	}
+	return nil
+}
+
+// promptKey edits the input line; enter runs it, and backspace on an empty
+// line leaves it
+func (f *fileViewerDialog) promptKey(msg tea.KeyPressMsg) tea.Cmd {
+	switch msg.String() {
+	case "enter":
+		value := strings.TrimSpace(f.input.Value())
+		prompt := f.prompt
+		f.prompt = viewerNoPrompt
+		f.input.Blur()
+		switch prompt {
+		case viewerSearch:
+			f.search(value)
+		case viewerGoTo:
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return toast.NewInfoToast("Type a line number to go to")
+			}
+			f.moveTo(n)
+		}
+		return nil
+	case "backspace":
+		if f.input.Value() == "" {
+			f.prompt = viewerNoPrompt
+			f.input.Blur()
+			return nil
+		}
+	}
+	var cmd tea.Cmd
+	f.input, cmd = f.input.Update(msg)
+	return cmd
+}
+
+func (f *fileViewerDialog) startPrompt(prompt viewerPrompt) tea.Cmd {
+	f.prompt = prompt
+	f.input.SetValue("")
+	f.input.Placeholder = "line number"
+	if prompt == viewerSearch {
+		f.input.Placeholder = "search"
+		f.input.SetValue(f.query)
+		f.input.CursorEnd()
+	}
+	return f.input.Focus()
+}
+
+// search finds the lines containing query, ignoring case unless it has
+// capitals, and moves to the first one from the cursor
+func (f *fileViewerDialog) search(query string) {
+	f.query = query
+	f.matches = nil
+	if query != "" {
+		fold := strings.ToLower(query) == query
+		for i, line := range f.lines {
+			if fold {
+				line = strings.ToLower(line)
+			}
+			if strings.Contains(line, query) {
+				f.matches = append(f.matches, i+1)
+			}
+		}
+	}
+	f.line--
+	f.nextMatch(1)
+	if len(f.matches) == 0 {
+		f.line++
+		f.renderContent()
+	}
+}
+
+// nextMatch moves to the next line matching the search in the direction
+// given, wrapping around the file
+func (f *fileViewerDialog) nextMatch(direction int) {
+	if len(f.matches) == 0 {
+		return
+	}
+	target := f.matches[0]
+	if direction < 0 {
+		target = f.matches[len(f.matches)-1]
+	}
+	for i := range f.matches {
+		match := f.matches[i]
+		if direction < 0 {
+			match = f.matches[len(f.matches)-1-i]
+		}
+		if (direction > 0 && match > f.line) || (direction < 0 && match < f.line) {
+			target = match
+			break
+		}
+	}
+	f.moveTo(target)
+}
+
+// moveTo moves the cursor to a line, scrolling it into view
+func (f *fileViewerDialog) moveTo(line int) {
+	f.line = max(1, min(line, len(f.lines)))
+	if top := f.viewport.YOffset; f.line-1 < top || f.line-1 >= top+f.viewport.Height() {
+		f.viewport.SetYOffset(max(0, f.line-1-f.viewport.Height()/3))
+	}
+	f.renderContent()
+}
+
+// selection returns the first and last line of the selected range, or the
+// cursor line without one
+func (f *fileViewerDialog) selection() (int, int) {
+	if f.anchor == 0 {
+		return f.line, f.line
+	}
+	return min(f.anchor, f.line), max(f.anchor, f.line)
+}
+
+// copyRange copies the selected lines, or the cursor line, and ends the
+// selection
+func (f *fileViewerDialog) copyRange() tea.Cmd {
+	if len(f.lines) == 0 || f.err != nil {
+		return nil
+	}
+	start, end := f.selection()
+	text := strings.Join(f.lines[start-1:end], "\n")
+	f.anchor = 0
+	f.renderContent()
+	what := fmt.Sprintf("line %d", start)
+	if end > start {
+		what = fmt.Sprintf("lines %d-%d", start, end)
+	}
+	return tea.Sequence(
+		app.SetClipboard(text),
+		toast.NewSuccessToast(fmt.Sprintf("Copied %s of %s", what, filepath.Base(f.path))),
+	)
+}
+
+func (f *fileViewerDialog) resize() {
+	f.viewport.SetWidth(max(40, layout.Current.Container.Width-12))
+	f.viewport.SetHeight(max(8, layout.Current.Viewport.Height-14))
+	f.input.SetWidth(max(20, f.viewport.Width()-4))
+	f.renderContent()
+	f.viewport.SetYOffset(max(0, f.line-1-f.viewport.Height()/3))
+}
+
+// highlight colors the file's syntax, highlighting it as a whole so that
+// tokens spanning lines, as comments and strings, are colored on each
+func (f *fileViewerDialog) highlight() {
+	f.highlighted = nil
+	source := strings.Join(f.lines, "\n")
+	if len(source) > maxHighlightBytes {
+		return
+	}
+	var buf bytes.Buffer
+	if err := diff.SyntaxHighlight(&buf, source, f.path, "terminal16m", theme.CurrentTheme().BackgroundPanel()); err != nil {
+		return
+	}
+	highlighted := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(highlighted) == len(f.lines) {
+		f.highlighted = highlighted
+	}
+}
+
+func (f *fileViewerDialog) renderContent() {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	if f.err != nil {
+		f.viewport.SetContent(base.Foreground(t.Error()).Render(f.err.Error()))
+		return
+	}
+
+	numberWidth := len(strconv.Itoa(len(f.lines)))
+	numberStyle := base.Foreground(t.TextMuted())
+	cursorStyle := base.Foreground(t.Primary()).Bold(true)
+	matchStyle := base.Foreground(t.Warning())
+	textStyle := base.Foreground(t.Text())
+	selectedStyle := styles.NewStyle().Background(t.BackgroundElement()).Foreground(t.Text())
+	width := f.viewport.Width() - numberWidth - 2
+
+	matched := make(map[int]bool, len(f.matches))
+	for _, line := range f.matches {
+		matched[line] = true
+	}
+	start, end := 0, -1
+	if f.anchor != 0 {
+		start, end = f.selection()
+	}
+
+	rendered := make([]string, len(f.lines))
+	for i, line := range f.lines {
+		n := i + 1
+		gutter := numberStyle.Render(fmt.Sprintf(" %*d ", numberWidth, n))
+		switch {
+		case n == f.line:
+			gutter = cursorStyle.Render(fmt.Sprintf("▶%*d ", numberWidth, n))
+		case matched[n]:
+			gutter = matchStyle.Render(fmt.Sprintf(" %*d ", numberWidth, n))
+		}
+		if n >= start && n <= end {
+			rendered[i] = gutter + selectedStyle.MaxHeight(1).Width(width).Render(ansi.Truncate(line, width, "…"))
+			continue
+		}
+		if f.highlighted != nil {
+			line = f.highlighted[i]
+		} else {
+			line = textStyle.Render(line)
+		}
+		rendered[i] = gutter + base.MaxHeight(1).Width(width).Render(ansi.Truncate(line, width, "…"))
+	}
+	f.viewport.SetContent(strings.Join(rendered, "\n"))
+}
+
+func (f *fileViewerDialog) Render(background string) string {
+	return f.modal.Render(f.View(), background)
+}
+
+// View renders the viewer's content without the surrounding modal, so other
+// dialogs can embed it
+func (f *fileViewerDialog) View() string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+
+	status := fmt.Sprintf("%s:%d", f.path, f.line)
+	if start, end := f.selection(); f.anchor != 0 {
+		status += fmt.Sprintf("   lines %d-%d selected", start, end)
+	}
+	if f.query != "" {
+		status += fmt.Sprintf("   %d matching %q", len(f.matches), f.query)
+	}
+	header := mutedStyle.Render(status)
+
+	var footer string
+	switch f.prompt {
+	case viewerSearch:
+		footer = keyStyle.Render("/") + f.input.View()
+	case viewerGoTo:
+		footer = keyStyle.Render(":") + f.input.View()
+	default:
+		var parts []string
+		for _, pair := range [][2]string{{"/", "search"}, {"n/N", "next/prev"}, {":", "go to line"}, {"v", "select"}, {"y", "copy"}} {
+			parts = append(parts, keyStyle.Render(pair[0])+mutedStyle.Render(" "+pair[1]))
+		}
+		footer = strings.Join(parts, mutedStyle.Render("   "))
+	}
+	return strings.Join([]string{header, "", f.viewport.View(), "", footer}, "\n")
+}
+
+func (f *fileViewerDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewFileViewerDialog opens a file relative to the project root at the given
+// 1-based line
+func NewFileViewerDialog(path string, line int) FileViewerDialog {
+	return newFileViewer(path, line)
+}
+
+func newFileViewer(path string, line int) *fileViewerDialog {
+	input := textinput.New()
+	input.Prompt = " "
+	input.CharLimit = -1
+	f := &fileViewerDialog{
+		path:     path,
+		line:     max(1, line),
+		viewport: viewport.New(),
+		input:    input,
+		modal: modal.New(
+			modal.WithTitle(filepath.Base(path)),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	full := path
+	if !filepath.IsAbs(full) {
+		full = filepath.Join(util.RootPath, path)
+	}
+	content, err := remote.ReadFile(full)
+	if err != nil {
+		f.err = fmt.Errorf("failed to open %s: %w", path, err)
+	} else {
+		text := strings.ReplaceAll(strings.TrimSuffix(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n"), "\t", "    ")
+		f.lines = strings.Split(text, "\n")
+		f.line = min(f.line, len(f.lines))
+		f.highlight()
+	}
+	f.resize()
+	return f
+}
@@ -0,0 +1,240 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/gitstatus"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// maxGitRows is the number of changed files listed at once
+const maxGitRows = 12
+
+// GitDialog summarizes the changed files of the project, stages and
+// unstages them, and commits them with a generated message
+type GitDialog interface {
+	layout.Modal
+}
+
+type gitDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	status   gitstatus.Status
+	selected int
+	loading  bool
+	busy     string // What is being waited for, such as "Committing…"
+	message  string // Generated commit message, committed with enter
+	err      error
+}
+
+func (g *gitDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (g *gitDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case app.GitStatusMsg:
+		g.loading = false
+		g.busy = ""
+		g.err = msg.Err
+		if msg.Err == nil {
+			g.status = msg.Status
+			g.selected = min(g.selected, max(0, len(g.status.Files)-1))
+		}
+	case app.CommitMessageMsg:
+		g.busy = ""
+		g.err = msg.Err
+		g.message = msg.Message
+	case app.GitCommittedMsg:
+		g.busy = ""
+		if msg.Err != nil {
+			g.err = msg.Err
+			return g, nil
+		}
+		return g, tea.Batch(
+			toast.NewSuccessToast(msg.Summary, toast.WithTitle("Committed")),
+			util.CmdHandler(modal.CloseModalMsg{}),
+		)
+	case tea.KeyPressMsg:
+		if g.loading || g.busy != "" {
+			return g, nil
+		}
+		files := g.status.Files
+		switch msg.String() {
+		case "up", "k":
+			g.selected = max(0, g.selected-1)
+		case "down", "j":
+			g.selected = min(max(0, len(files)-1), g.selected+1)
+		case "space":
+			if len(files) == 0 {
+				return g, nil
+			}
+			file := files[g.selected]
+			// A file with unstaged changes is staged, one fully staged is
+			// unstaged
+			paths := []string{file.Path}
+			if file.OrigPath != "" {
+				paths = append(paths, file.OrigPath)
+			}
+			g.busy = "Updating the index…"
+			g.message = ""
+			return g, g.app.StageFiles(file.Unstaged(), paths...)
+		case "a":
+			if len(files) == 0 {
+				return g, nil
+			}
+			g.busy = "Staging every change…"
+			g.message = ""
+			return g, g.app.StageFiles(true, ".")
+		case "u":
+			staged, _, _, _ := g.status.Counts()
+			if staged == 0 {
+				return g, nil
+			}
+			g.busy = "Unstaging every change…"
+			g.message = ""
+			return g, g.app.StageFiles(false, ".")
+		case "r":
+			g.loading = true
+			return g, g.app.GitStatus()
+		case "m":
+			if staged, _, _, _ := g.status.Counts(); staged == 0 {
+				g.err = fmt.Errorf("stage changes to write a commit message for")
+				return g, nil
+			}
+			if g.app.Model == nil || g.app.Provider == nil {
+				g.err = fmt.Errorf("select a model to write the commit message")
+				return g, nil
+			}
+			g.busy = "Writing a commit message…"
+			g.err = nil
+			return g, g.app.GenerateCommitMessage()
+		case "enter":
+			if g.message == "" {
+				return g, nil
+			}
+			g.busy = "Committing…"
+			g.err = nil
+			return g, g.app.CommitStaged(g.message)
+		}
+	}
+	return g, nil
+}
+
+func (g *gitDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	stagedStyle := base.Foreground(t.Success())
+	unstagedStyle := base.Foreground(t.Warning())
+	errorStyle := base.Foreground(t.Error())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+
+	if g.loading {
+		return g.modal.Render(mutedStyle.Render("Reading the git status…"), background)
+	}
+	if g.err != nil && len(g.status.Files) == 0 && g.status.Branch == "" {
+		return g.modal.Render(errorStyle.Render(g.err.Error()), background)
+	}
+
+	var lines []string
+	branch := g.status.Branch
+	if branch == "" {
+		branch = "detached HEAD"
+	}
+	header := textStyle.Bold(true).Render(branch)
+	if g.status.Upstream != "" {
+		header += mutedStyle.Render(" → " + g.status.Upstream)
+	}
+	if g.status.Ahead > 0 || g.status.Behind > 0 {
+		header += mutedStyle.Render(fmt.Sprintf("  ↑%d ↓%d", g.status.Ahead, g.status.Behind))
+	}
+	lines = append(lines, header, "")
+
+	files := g.status.Files
+	if len(files) == 0 {
+		lines = append(lines, mutedStyle.Render("Nothing to commit, the worktree is clean."))
+	}
+	width := max(30, layout.Current.Container.Width-24)
+	start := max(0, min(g.selected-maxGitRows/2, len(files)-maxGitRows))
+	end := min(len(files), start+maxGitRows)
+	for i := start; i < end; i++ {
+		file := files[i]
+		prefix := "  "
+		pathStyle := textStyle
+		if i == g.selected {
+			prefix = "› "
+			pathStyle = pathStyle.Bold(true)
+		}
+		code := file.Code()
+		path := file.Path
+		if file.OrigPath != "" {
+			path = file.OrigPath + " → " + file.Path
+		}
+		lines = append(lines, textStyle.Render(prefix)+
+			stagedStyle.Render(code[:1])+unstagedStyle.Render(code[1:])+
+			pathStyle.Render(" "+ansi.Truncate(path, width, "…")))
+	}
+	if end < len(files) {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  … %d more", len(files)-end)))
+	}
+
+	if g.message != "" {
+		lines = append(lines, "", mutedStyle.Render("Commit message:"))
+		wrapped := lipgloss.NewStyle().Width(width).Render(g.message)
+		for line := range strings.SplitSeq(wrapped, "\n") {
+			lines = append(lines, textStyle.Render("  "+line))
+		}
+	}
+
+	lines = append(lines, "")
+	switch {
+	case g.busy != "":
+		lines = append(lines, mutedStyle.Render(g.busy))
+	case g.err != nil:
+		lines = append(lines, errorStyle.Render(g.err.Error()), "")
+		fallthrough
+	default:
+		pairs := []string{"space", "stage/unstage", "a", "stage all", "u", "unstage all", "m", "write message"}
+		if g.message != "" {
+			pairs = append(pairs, "enter", "commit")
+		}
+		lines = append(lines, help(append(pairs, "esc", "close")...))
+	}
+	return g.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (g *gitDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewGitDialog creates a dialog of the project's changed files, which it
+// lists when GitStatus' message arrives
+func NewGitDialog(app *app.App) GitDialog {
+	return &gitDialog{
+		app:     app,
+		loading: true,
+		modal: modal.New(
+			modal.WithTitle("Git"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+}
@@ -0,0 +1,205 @@
+package dialog
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/list"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/glyphs"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// numVisibleGlyphs is the number of symbols listed at once
+const numVisibleGlyphs = 12
+
+// GlyphPickedMsg asks for a symbol to be inserted at the prompt's cursor
+type GlyphPickedMsg struct {
+	Glyph string
+}
+
+// GlyphPickerDialog finds arrows, box drawing, math symbols and emoji by
+// name, to insert one in the prompt. The favorites and the recent symbols
+// are listed first.
+type GlyphPickerDialog interface {
+	layout.Modal
+}
+
+type glyphPickerDialog struct {
+	app          *app.App
+	modal        *modal.Modal
+	searchDialog *SearchDialog
+}
+
+// glyphItem is a symbol of the picker's list
+type glyphItem struct {
+	glyph    glyphs.Glyph
+	favorite bool
+	recent   bool // Listed under Recent, and removable from it
+}
+
+func (g glyphItem) Render(selected bool, width int, baseStyle styles.Style) string {
+	t := theme.CurrentTheme()
+	base := baseStyle.Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	if selected {
+		textStyle = textStyle.Foreground(t.Primary()).Bold(true)
+	}
+
+	star := "  "
+	if g.favorite {
+		star = "★ "
+	}
+	// Emoji are two cells wide, the other symbols one
+	symbol := base.Width(3).Render(g.glyph.Text)
+	line := mutedStyle.Render(" "+star) + symbol + textStyle.Render(" "+g.glyph.Name) + mutedStyle.Render("  "+g.glyph.Category)
+	return base.Width(width).MaxWidth(width).Render(line)
+}
+
+func (g glyphItem) Selectable() bool {
+	return true
+}
+
+func (p *glyphPickerDialog) Init() tea.Cmd {
+	p.showGlyphs("")
+	return p.searchDialog.Init()
+}
+
+// showGlyphs lists the symbols matching query. Without one, the favorites
+// and recent symbols come before the catalog.
+func (p *glyphPickerDialog) showGlyphs(query string) {
+	item := func(g glyphs.Glyph, recent bool) list.Item {
+		return glyphItem{glyph: g, favorite: p.app.GlyphFavorite(g.Text), recent: recent}
+	}
+	var items []list.Item
+	if strings.TrimSpace(query) != "" {
+		for _, g := range glyphs.Search(query) {
+			items = append(items, item(g, false))
+		}
+		p.searchDialog.SetItems(items)
+		return
+	}
+
+	section := func(title string, texts []string, recent bool) {
+		var found []list.Item
+		for _, text := range texts {
+			if g, ok := glyphs.Lookup(text); ok {
+				found = append(found, item(g, recent))
+			}
+		}
+		if len(found) > 0 {
+			items = append(items, list.HeaderItem(title))
+			items = append(items, found...)
+		}
+	}
+	section("Favorites", p.app.State.FavoriteGlyphs, false)
+	section("Recent", p.app.State.RecentGlyphs, true)
+	category := ""
+	for _, g := range glyphs.Catalog {
+		if g.Category != category {
+			category = g.Category
+			items = append(items, list.HeaderItem(category))
+		}
+		items = append(items, item(g, false))
+	}
+	p.searchDialog.SetItems(items)
+}
+
+// reshow lists the symbols again after the favorites or recent ones
+// changed, keeping the selection in place
+func (p *glyphPickerDialog) reshow() {
+	_, idx := p.searchDialog.list.GetSelectedItem()
+	p.showGlyphs(p.searchDialog.GetQuery())
+	if idx > 0 {
+		p.searchDialog.list.SetSelectedIndex(idx)
+	}
+}
+
+func (p *glyphPickerDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case SearchQueryChangedMsg:
+		p.showGlyphs(msg.Query)
+		return p, nil
+	case SearchSelectionMsg:
+		item, ok := msg.Item.(glyphItem)
+		if !ok {
+			return p, nil
+		}
+		return p, tea.Sequence(
+			util.CmdHandler(modal.CloseModalMsg{}),
+			util.CmdHandler(GlyphPickedMsg{Glyph: item.glyph.Text}),
+		)
+	case SearchRemoveItemMsg:
+		item, ok := msg.Item.(glyphItem)
+		if !ok || !item.recent {
+			return p, nil
+		}
+		cmd := p.app.ForgetGlyph(item.glyph.Text)
+		p.reshow()
+		return p, cmd
+	case SearchCancelledMsg:
+		return p, util.CmdHandler(modal.CloseModalMsg{})
+	case tea.KeyPressMsg:
+		if msg.String() == "ctrl+f" {
+			selected, idx := p.searchDialog.list.GetSelectedItem()
+			item, ok := selected.(glyphItem)
+			if idx == -1 || !ok {
+				return p, nil
+			}
+			_, cmd := p.app.ToggleFavoriteGlyph(item.glyph.Text)
+			p.reshow()
+			return p, cmd
+		}
+	}
+
+	updated, cmd := p.searchDialog.Update(msg)
+	p.searchDialog = updated.(*SearchDialog)
+	return p, cmd
+}
+
+func (p *glyphPickerDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+
+	pairs := []string{"enter", "insert", "ctrl+f", "favorite"}
+	if selected, idx := p.searchDialog.list.GetSelectedItem(); idx != -1 {
+		if item, ok := selected.(glyphItem); ok && item.recent {
+			pairs = append(pairs, "ctrl+x", "remove from recent")
+		}
+	}
+	return p.modal.Render(p.searchDialog.View()+"\n"+help(pairs...), background)
+}
+
+func (p *glyphPickerDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewGlyphPickerDialog creates a dialog finding symbols and emoji to insert
+// in the prompt
+func NewGlyphPickerDialog(app *app.App) GlyphPickerDialog {
+	width := 56
+	searchDialog := NewSearchDialog("Search symbols and emoji...", numVisibleGlyphs)
+	searchDialog.SetWidth(width)
+	return &glyphPickerDialog{
+		app:          app,
+		searchDialog: searchDialog,
+		modal: modal.New(
+			modal.WithTitle("Symbols"),
+			modal.WithMaxWidth(width+4),
+		),
+	}
+}
@@ -1,13 +1,17 @@
 package dialog
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
 	"github.com/aaronmrosenthal/rycode/internal/intelligence"
 	"github.com/aaronmrosenthal/rycode/internal/layout"
 	"github.com/aaronmrosenthal/rycode/internal/styles"
 	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/typography"
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/charmbracelet/lipgloss/v2"
 )
@@ -18,62 +22,21 @@ type InsightsDialog interface {
 }
 
 type insightsDialog struct {
-	app     *app.App
+	app      *app.App
 	insights *intelligence.UsageInsights
-	width   int
-	height  int
+	modal    *modal.Modal
+	width    int
 }
 
-// NewInsightsDialog creates a new usage insights dialog
+// NewInsightsDialog creates a new usage insights dialog showing the usage
+// persisted by the app and what background features spent
 func NewInsightsDialog(app *app.App) InsightsDialog {
-	dialog := &insightsDialog{
-		app:     app,
-		insights: intelligence.NewUsageInsights(),
+	return &insightsDialog{
+		app:      app,
+		insights: app.UsageInsights(),
+		modal:    modal.New(modal.WithMaxWidth(layout.Current.Container.Width - 8)),
+		width:    max(50, min(100, layout.Current.Container.Width-12)),
 	}
-
-	// TODO: Load actual usage data from app state
-	// For now, creating sample data for demonstration
-	dialog.loadSampleData()
-
-	return dialog
-}
-
-// loadSampleData creates sample usage data for demonstration
-func (d *insightsDialog) loadSampleData() {
-	// Sample data showing realistic usage patterns
-	// In production, this would load from persistent storage
-
-	// Simulated 7 days of usage
-	// Day 1: Light usage
-	d.insights.AddUsage(d.daysAgo(6), 0.45, 15, 12000, "claude-3-5-haiku", "anthropic")
-	d.insights.AddUsage(d.daysAgo(6), 0.25, 8, 8000, "gpt-3.5-turbo", "openai")
-
-	// Day 2: Moderate usage
-	d.insights.AddUsage(d.daysAgo(5), 1.20, 35, 45000, "claude-3-5-sonnet", "anthropic")
-	d.insights.AddUsage(d.daysAgo(5), 0.60, 20, 18000, "claude-3-5-haiku", "anthropic")
-
-	// Day 3: Heavy usage
-	d.insights.AddUsage(d.daysAgo(4), 2.50, 50, 80000, "claude-3-5-sonnet", "anthropic")
-	d.insights.AddUsage(d.daysAgo(4), 1.10, 40, 35000, "gpt-4-turbo", "openai")
-
-	// Day 4: Light usage
-	d.insights.AddUsage(d.daysAgo(3), 0.35, 12, 10000, "claude-3-5-haiku", "anthropic")
-
-	// Day 5: Moderate usage
-	d.insights.AddUsage(d.daysAgo(2), 1.80, 45, 55000, "claude-3-5-sonnet", "anthropic")
-	d.insights.AddUsage(d.daysAgo(2), 0.40, 15, 12000, "gemini-1.5-flash", "google")
-
-	// Day 6: Heavy usage
-	d.insights.AddUsage(d.daysAgo(1), 3.20, 65, 95000, "claude-3-5-sonnet", "anthropic")
-	d.insights.AddUsage(d.daysAgo(1), 0.80, 25, 22000, "claude-3-5-haiku", "anthropic")
-
-	// Day 7 (today): Moderate usage so far
-	d.insights.AddUsage(d.daysAgo(0), 1.50, 38, 48000, "claude-3-5-sonnet", "anthropic")
-}
-
-// daysAgo returns a time N days in the past
-func (d *insightsDialog) daysAgo(days int) time.Time {
-	return time.Now().AddDate(0, 0, -days)
 }
 
 func (i *insightsDialog) Init() tea.Cmd {
@@ -83,43 +46,88 @@ func (i *insightsDialog) Init() tea.Cmd {
 func (i *insightsDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		i.width = msg.Width
-		i.height = msg.Height
+		i.width = max(50, min(100, msg.Width-12))
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "t":
+			return i, i.toggle(intelligence.BackgroundTitle)
+		case "r":
+			return i, i.toggle(intelligence.BackgroundRecommendations)
+		}
 	}
 	return i, nil
 }
 
+// toggle turns a background feature off when it is on, and on otherwise
+func (i *insightsDialog) toggle(feature intelligence.BackgroundFeature) tea.Cmd {
+	cmd, _ := i.app.SetBackgroundEnabled(feature, !i.app.BackgroundEnabled(feature))
+	return cmd
+}
+
 func (i *insightsDialog) View() string {
 	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
 
 	// Render dashboard
-	dashboard := i.insights.RenderDashboard(i.width - 8)
+	dashboard := i.insights.RenderDashboard(i.width)
 
 	// Add footer with help text
-	footer := styles.NewStyle().
-		Foreground(t.TextMuted()).
-		Faint(true).
-		MarginTop(2).
-		Render("Press ESC to close")
-
-	content := dashboard + "\n" + footer
-
-	// Wrap in bordered box
-	boxStyle := styles.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(t.Border()).
-		Padding(2, 3).
-		Width(i.width - 4)
-
-	return boxStyle.Render(content)
+	footer := keyStyle.Render("t") + mutedStyle.Render(" auto-title   ") +
+		keyStyle.Render("r") + mutedStyle.Render(" recommendations   ") +
+		keyStyle.Render("esc") + mutedStyle.Render(" close")
+
+	return dashboard + "\n\n" + i.renderBackground() + "\n\n" + footer
 }
 
-func (i *insightsDialog) Render(background string) string {
-	// Center the dialog
-	dialog := i.View()
+// renderBackground breaks down what background features spent, with each
+// feature's state and the day's cap
+func (i *insightsDialog) renderBackground() string {
+	t := theme.CurrentTheme()
+	typo := typography.New()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+
+	spend := intelligence.BackgroundSpend(i.app.CostLedger().Records(), time.Now())
+	var today float64
+	for _, line := range spend {
+		today += line.Today
+	}
+	limit := i.app.BackgroundCap()
+	capped := today >= limit
+
+	lines := []string{typo.Subheading.Render("🌙 Background Spend")}
+	status := mutedStyle.Render("Today ") + textStyle.Render(fmt.Sprintf("$%.2f", today)) +
+		mutedStyle.Render(fmt.Sprintf(" of $%.2f cap", limit))
+	if capped {
+		status += base.Foreground(t.Warning()).Render("  reached, paused until tomorrow")
+	}
+	lines = append(lines, status)
+
+	for _, line := range spend {
+		state := "on"
+		switch {
+		case !line.Feature.Switchable():
+			state = "server"
+		case !i.app.BackgroundEnabled(line.Feature):
+			state = "off"
+		case capped:
+			state = "paused"
+		}
+		cost := "not metered"
+		if line.Feature.Metered() {
+			cost = fmt.Sprintf("$%.4f today  $%.4f total  %d runs  %s tokens", line.Today, line.Total, line.Requests, formatTokenCount(line.Tokens))
+		}
+		name := fmt.Sprintf("%-22s", line.Feature.Name())
+		lines = append(lines, textStyle.Render(name)+mutedStyle.Render(fmt.Sprintf("%-8s", state))+mutedStyle.Render(cost))
+	}
+	return lipgloss.NewStyle().MaxWidth(i.width).Render(strings.Join(lines, "\n"))
+}
 
-	// Simple centering
-	return dialog
+func (i *insightsDialog) Render(background string) string {
+	return i.modal.Render(i.View(), background)
 }
 
 func (i *insightsDialog) Close() tea.Cmd {
@@ -0,0 +1,225 @@
+package dialog
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/commands"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// KeybindsDialog lists the commands with their keybindings and rebinds them
+// live, saving the changes to the global config
+type KeybindsDialog interface {
+	layout.Modal
+}
+
+// keybindsMode is what the dialog waits for
+type keybindsMode int
+
+const (
+	keybindsBrowsing   keybindsMode = iota
+	keybindsCapturing               // The next key pressed is the new binding
+	keybindsConfirming              // The new binding conflicts, enter takes it over
+)
+
+// keybindsPageSize is the number of commands shown at once
+const keybindsPageSize = 14
+
+type keybindsDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	commands []commands.Command
+	selected int
+	mode     keybindsMode
+	adding   bool // The captured binding is added rather than replacing the others
+	leader   bool // The leader was pressed while capturing
+	pending  commands.Keybinding
+}
+
+func (k *keybindsDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (k *keybindsDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyPressMsg)
+	if !ok || len(k.commands) == 0 {
+		return k, nil
+	}
+	command := k.commands[k.selected]
+
+	switch k.mode {
+	case keybindsCapturing:
+		if key.String() == k.app.Config.Keybinds.Leader && !k.leader {
+			k.leader = true
+			return k, nil
+		}
+		k.pending = commands.Keybinding{RequiresLeader: k.leader, Key: key.String()}
+		k.leader = false
+		if len(k.app.Commands.Conflicts(command.Name, k.pending)) > 0 {
+			k.mode = keybindsConfirming
+			return k, nil
+		}
+		return k, k.bind(command, k.pending)
+	case keybindsConfirming:
+		if key.String() == "enter" {
+			return k, k.bind(command, k.pending)
+		}
+		k.mode = keybindsBrowsing
+		return k, nil
+	}
+
+	switch key.String() {
+	case "up", "k":
+		k.selected = max(0, k.selected-1)
+	case "down", "j":
+		k.selected = min(len(k.commands)-1, k.selected+1)
+	case "pgup":
+		k.selected = max(0, k.selected-keybindsPageSize)
+	case "pgdown":
+		k.selected = min(len(k.commands)-1, k.selected+keybindsPageSize)
+	case "enter", "a":
+		k.mode = keybindsCapturing
+		k.adding = key.String() == "a"
+	case "backspace", "delete":
+		return k, k.rebind([]commands.CommandName{command.Name}, nil)
+	case "r":
+		return k, k.rebind([]commands.CommandName{command.Name}, commands.DefaultKeybindings(command.Name))
+	}
+	return k, nil
+}
+
+// bind gives the selected command the captured keybinding, taking it away
+// from the commands it conflicts with
+func (k *keybindsDialog) bind(command commands.Command, binding commands.Keybinding) tea.Cmd {
+	k.mode = keybindsBrowsing
+	changed := []commands.CommandName{command.Name}
+	for _, conflict := range k.app.Commands.Conflicts(command.Name, binding) {
+		k.app.Commands.Unbind(conflict.Name, binding)
+		changed = append(changed, conflict.Name)
+	}
+	bindings := []commands.Keybinding{binding}
+	if k.adding && !slices.Contains(command.Keybindings, binding) {
+		bindings = append(slices.Clone(command.Keybindings), binding)
+	}
+	return k.rebind(changed, bindings)
+}
+
+// rebind sets the keybindings of the first command and saves the named ones
+func (k *keybindsDialog) rebind(changed []commands.CommandName, bindings []commands.Keybinding) tea.Cmd {
+	k.app.Commands.Rebind(changed[0], bindings)
+	k.refresh()
+	return k.app.SaveKeybinds(changed)
+}
+
+// refresh reloads the commands from the registry, which the dialog changes.
+// Custom commands, which have no keybindings, are left out.
+func (k *keybindsDialog) refresh() {
+	k.commands = slices.DeleteFunc(k.app.Commands.Sorted(), func(c commands.Command) bool { return c.Custom })
+	k.selected = max(0, min(k.selected, len(k.commands)-1))
+}
+
+func (k *keybindsDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	warningStyle := base.Foreground(t.Warning())
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+
+	nameWidth := 0
+	for _, command := range k.commands {
+		nameWidth = max(nameWidth, len(command.Name))
+	}
+	start := max(0, min(k.selected-keybindsPageSize/2, len(k.commands)-keybindsPageSize))
+	end := min(len(k.commands), start+keybindsPageSize)
+
+	var lines []string
+	for i := start; i < end; i++ {
+		command := k.commands[i]
+		prefix := "  "
+		nameStyle := textStyle
+		if i == k.selected {
+			prefix = "› "
+			nameStyle = nameStyle.Bold(true)
+		}
+		bindings := "—"
+		if len(command.Keybindings) > 0 {
+			bindings = commands.FormatBindings(command.Keybindings)
+		}
+		line := textStyle.Render(prefix) +
+			nameStyle.Render(fmt.Sprintf("%-*s", nameWidth, command.Name)) +
+			keyStyle.Render(fmt.Sprintf("  %-22s", bindings)) +
+			mutedStyle.Render(command.Description)
+		var shared []string
+		for _, binding := range command.Keybindings {
+			for _, conflict := range k.app.Commands.Conflicts(command.Name, binding) {
+				shared = append(shared, string(conflict.Name))
+			}
+		}
+		if len(shared) > 0 {
+			line += warningStyle.Render("  ⚠ shared with " + strings.Join(shared, ", "))
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, mutedStyle.Render(fmt.Sprintf("  %d–%d of %d", start+1, end, len(k.commands))), "")
+
+	leader := k.app.Config.Keybinds.Leader
+	switch k.mode {
+	case keybindsCapturing:
+		prompt := "Press the new key for " + string(k.commands[k.selected].Name)
+		if k.leader {
+			prompt = "Leader pressed, now press the key that follows it"
+		}
+		lines = append(lines,
+			textStyle.Bold(true).Render(prompt),
+			mutedStyle.Render(fmt.Sprintf("Press %s first for a leader sequence. esc closes the editor.", leader)),
+		)
+	case keybindsConfirming:
+		var names []string
+		for _, conflict := range k.app.Commands.Conflicts(k.commands[k.selected].Name, k.pending) {
+			names = append(names, string(conflict.Name))
+		}
+		lines = append(lines,
+			warningStyle.Render(fmt.Sprintf("%s is already bound to %s.", k.pending, strings.Join(names, ", "))),
+			help("enter", "take it over and unbind it there", "any other key", "cancel"),
+		)
+	default:
+		lines = append(lines,
+			help("↑/↓", "select", "enter", "rebind", "a", "add a key", "⌫", "unbind", "r", "reset to default"),
+			"",
+			mutedStyle.Render(fmt.Sprintf("<leader> is %s. Changes apply now and are saved to the global config.", leader)),
+		)
+	}
+	return k.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (k *keybindsDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewKeybindsDialog creates the keybinding editor
+func NewKeybindsDialog(app *app.App) KeybindsDialog {
+	dialog := &keybindsDialog{
+		app: app,
+		modal: modal.New(
+			modal.WithTitle("Keybindings"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	dialog.refresh()
+	return dialog
+}
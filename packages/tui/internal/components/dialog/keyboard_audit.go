@@ -0,0 +1,91 @@
+package dialog
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/accessibility"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+)
+
+// KeyboardAuditDialog reports what the mouse does and the keys doing the
+// same, flagging what only the mouse can do
+type KeyboardAuditDialog interface {
+	layout.Modal
+}
+
+type keyboardAuditDialog struct {
+	app   *app.App
+	modal *modal.Modal
+}
+
+func (d *keyboardAuditDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *keyboardAuditDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	return d, nil
+}
+
+func (d *keyboardAuditDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+
+	issues := d.app.KeyboardAudit()
+	var lines []string
+	if len(issues) == 0 {
+		lines = append(lines, base.Foreground(t.Success()).Render("✓ Everything the mouse does has a key"), "")
+	}
+	for _, issue := range issues {
+		lines = append(lines,
+			base.Foreground(t.Error()).Render("✗ ")+textStyle.Render(issue.Description),
+			mutedStyle.Render("  "+issue.Fix),
+		)
+	}
+	if len(issues) > 0 {
+		lines = append(lines, "")
+	}
+
+	for _, action := range accessibility.MouseActions() {
+		keys := action.Keys
+		if action.Command != "" {
+			keys = d.app.CommandKeys(action.Command)
+		}
+		if keys == "" {
+			continue
+		}
+		lines = append(lines,
+			textStyle.Render(action.Name)+mutedStyle.Render("  "+action.Component),
+			mutedStyle.Render("  "+action.Mouse+" · ")+textStyle.Bold(true).Render(keys),
+		)
+	}
+
+	mode := "off; /accessibility keyboard-only turns it on"
+	if d.app.KeyboardOnly() {
+		mode = "on: the mouse is left to the terminal"
+	}
+	lines = append(lines, "", mutedStyle.Render("Keyboard-only mode is "+mode))
+	return d.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (d *keyboardAuditDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewKeyboardAuditDialog creates a dialog auditing the keyboard equivalents
+// of mouse actions
+func NewKeyboardAuditDialog(app *app.App) KeyboardAuditDialog {
+	return &keyboardAuditDialog{
+		app: app,
+		modal: modal.New(
+			modal.WithTitle("Keyboard Audit"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+}
@@ -0,0 +1,123 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/loopguard"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// maxLoopLines is the number of lines of an error or version shown
+const maxLoopLines = 6
+
+// LoopDialog tells that a run was interrupted for repeating itself, shows
+// where it was stuck and asks how to go on
+type LoopDialog interface {
+	layout.Modal
+}
+
+type loopDialog struct {
+	app   *app.App
+	modal *modal.Modal
+	loop  loopguard.Loop
+}
+
+func (d *loopDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *loopDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return d, nil
+	}
+	switch key.String() {
+	case "enter", "r":
+		return d, tea.Sequence(
+			util.CmdHandler(modal.CloseModalMsg{}),
+			util.CmdHandler(app.SendPrompt{Text: d.loop.Prompt()}),
+		)
+	case "w":
+		return d, tea.Sequence(
+			util.CmdHandler(modal.CloseModalMsg{}),
+			util.CmdHandler(app.SetEditorContentMsg{Text: "You got stuck: " + d.loop.Summary() + ". "}),
+		)
+	}
+	return d, nil
+}
+
+func (d *loopDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+	width := max(40, min(90, layout.Current.Container.Width-12))
+
+	block := func(title, text string) []string {
+		out := []string{textStyle.Bold(true).Render(title)}
+		lines := strings.Split(strings.TrimSpace(text), "\n")
+		for i, line := range lines {
+			if i == maxLoopLines {
+				out = append(out, mutedStyle.Render(fmt.Sprintf("  … %d more lines", len(lines)-i)))
+				break
+			}
+			out = append(out, mutedStyle.Render("  ")+textStyle.Render(ansi.Truncate(strings.ReplaceAll(line, "\t", "    "), width-2, "…")))
+		}
+		return out
+	}
+
+	loop := d.loop
+	lines := []string{
+		base.Foreground(t.Warning()).Width(width).Render(loop.Summary() + ", so the run was interrupted."),
+		"",
+	}
+	switch {
+	case loop.Kind == loopguard.RepeatedFailure && loop.Error != "":
+		lines = append(lines, block("Last error", loop.Error)...)
+		lines = append(lines, "")
+	case loop.Kind == loopguard.Oscillation && len(loop.Versions) == 2:
+		lines = append(lines, block("One version", loop.Versions[0])...)
+		lines = append(lines, "")
+		lines = append(lines, block("The other", loop.Versions[1])...)
+		lines = append(lines, "")
+	}
+	lines = append(lines,
+		mutedStyle.Width(width).Render("How should the agent go on? It can be told what went wrong and asked for another approach, or given your own instructions."),
+		"",
+		help("enter", "ask for another approach", "w", "write instructions", "esc", "leave it stopped"),
+	)
+	return d.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (d *loopDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewLoopDialog creates the notice of a run interrupted for repeating
+// itself
+func NewLoopDialog(a *app.App, loop loopguard.Loop) LoopDialog {
+	return &loopDialog{
+		app:  a,
+		loop: loop,
+		modal: modal.New(
+			modal.WithTitle("Agent stuck"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+}
@@ -0,0 +1,160 @@
+package dialog
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// maxMergeRows is the number of sessions listed at once
+const maxMergeRows = 8
+
+// MergeSessionDialog offers to merge the current session into another, or
+// to link the two as related
+type MergeSessionDialog interface {
+	layout.Modal
+}
+
+type mergeSessionDialog struct {
+	app       *app.App
+	modal     *modal.Modal
+	sessions  []opencode.Session
+	selected  int
+	duplicate bool // Opened because the session looks like the first one
+	merging   bool
+}
+
+func (m *mergeSessionDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (m *mergeSessionDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case app.SessionMergedMsg:
+		m.merging = false
+		return m, util.CmdHandler(modal.CloseModalMsg{})
+	case tea.KeyPressMsg:
+		if m.merging || len(m.sessions) == 0 {
+			return m, nil
+		}
+		target := m.sessions[m.selected]
+		switch msg.String() {
+		case "up", "k":
+			m.selected = max(0, m.selected-1)
+		case "down", "j":
+			m.selected = min(len(m.sessions)-1, m.selected+1)
+		case "m", "enter":
+			if m.app.IsBusy() {
+				return m, toast.NewWarningToast("Wait for the reply to finish before merging")
+			}
+			m.merging = true
+			return m, m.app.MergeSession(*m.app.Session, target)
+		case "l":
+			return m, tea.Batch(
+				m.app.LinkSessions(m.app.Session.ID, target.ID),
+				toast.NewSuccessToast("Linked with "+target.Title, toast.WithTitle("Related sessions")),
+				util.CmdHandler(modal.CloseModalMsg{}),
+			)
+		}
+	}
+	return m, nil
+}
+
+func (m *mergeSessionDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+
+	if len(m.sessions) == 0 {
+		return m.modal.Render(mutedStyle.Render("No other sessions to merge into."), background)
+	}
+
+	var lines []string
+	if m.duplicate {
+		lines = append(lines, textStyle.Render("This looks like the same task as an earlier session."), "")
+	}
+	width := max(40, layout.Current.Container.Width-24)
+	start := max(0, min(m.selected-maxMergeRows/2, len(m.sessions)-maxMergeRows))
+	end := min(len(m.sessions), start+maxMergeRows)
+	related := m.app.RelatedSessions(m.app.Session.ID)
+	for i := start; i < end; i++ {
+		session := m.sessions[i]
+		prefix := "  "
+		titleStyle := textStyle
+		if i == m.selected {
+			prefix = "› "
+			titleStyle = titleStyle.Bold(true)
+		}
+		title := ansi.Truncate(session.Title, width, "…")
+		if slices.Contains(related, session.ID) {
+			title += " ↔"
+		}
+		updated := time.UnixMilli(int64(session.Time.Updated)).Format("Jan 2 15:04")
+		lines = append(lines, textStyle.Render(prefix)+titleStyle.Render(title)+mutedStyle.Render("  "+updated))
+	}
+	if end < len(m.sessions) {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  … %d more", len(m.sessions)-end)))
+	}
+
+	lines = append(lines, "")
+	if m.merging {
+		lines = append(lines, mutedStyle.Render("Carrying this session's conversation over…"))
+	} else {
+		lines = append(lines,
+			mutedStyle.Render("Merging adds this session's conversation to the selected one and deletes this one."),
+			"",
+			help("↑/↓", "select", "m", "merge into it", "l", "link as related", "esc", "keep separate"),
+		)
+	}
+	return m.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (m *mergeSessionDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewMergeSessionDialog creates a dialog merging the current session into
+// one of sessions, or linking the two. A duplicate is listed first.
+func NewMergeSessionDialog(app *app.App, sessions []opencode.Session, duplicate *opencode.Session) MergeSessionDialog {
+	m := &mergeSessionDialog{
+		app:      app,
+		sessions: sessions,
+		modal: modal.New(
+			modal.WithTitle("Merge Session"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	if duplicate != nil {
+		m.duplicate = true
+		m.sessions = slices.DeleteFunc(slices.Clone(sessions), func(session opencode.Session) bool {
+			return session.ID == duplicate.ID
+		})
+		m.sessions = slices.Insert(m.sessions, 0, *duplicate)
+		m.modal = modal.New(
+			modal.WithTitle("Same Task?"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		)
+	}
+	return m
+}
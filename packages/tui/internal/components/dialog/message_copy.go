@@ -0,0 +1,136 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// maxCopyRows is the number of messages listed at once
+const maxCopyRows = 10
+
+// MessageCopyDialog picks a message of the session and copies it as
+// markdown, as rendered text or only its code blocks
+type MessageCopyDialog interface {
+	layout.Modal
+}
+
+type messageCopyDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	messages []app.Message // Messages with text, newest first
+	selected int
+}
+
+func (d *messageCopyDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *messageCopyDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyPressMsg)
+	if !ok || len(d.messages) == 0 {
+		return d, nil
+	}
+	mode := app.CopyMode("")
+	switch key.String() {
+	case "up", "k":
+		d.selected = max(0, d.selected-1)
+	case "down", "j":
+		d.selected = min(len(d.messages)-1, d.selected+1)
+	case "enter", "m":
+		mode = app.CopyMarkdown
+	case "t":
+		mode = app.CopyText
+	case "c":
+		mode = app.CopyCode
+	}
+	if mode == "" {
+		return d, nil
+	}
+	return d, tea.Sequence(
+		util.CmdHandler(modal.CloseModalMsg{}),
+		d.app.CopyMessage(d.messages[d.selected], mode),
+	)
+}
+
+func (d *messageCopyDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+	width := max(40, min(90, layout.Current.Container.Width-12))
+
+	if len(d.messages) == 0 {
+		return d.modal.Render(mutedStyle.Width(width).Render("No message to copy yet."), background)
+	}
+
+	var lines []string
+	start := max(0, min(d.selected-maxCopyRows/2, len(d.messages)-maxCopyRows))
+	end := min(len(d.messages), start+maxCopyRows)
+	for i := start; i < end; i++ {
+		message := d.messages[i]
+		prefix := "  "
+		previewStyle := textStyle
+		if i == d.selected {
+			prefix = "› "
+			previewStyle = previewStyle.Foreground(t.Primary()).Bold(true)
+		}
+		author := "You"
+		if _, ok := message.Info.(opencode.AssistantMessage); ok {
+			author = "AI "
+		}
+		text := app.MessageText(message)
+		info := ""
+		if blocks := len(app.CodeBlocks(text)); blocks > 0 {
+			info = fmt.Sprintf("  %d code", blocks)
+		}
+		preview := strings.Join(strings.Fields(text), " ")
+		preview = ansi.Truncate(preview, width-lipgloss.Width(prefix+author+"  "+info), "…")
+		lines = append(lines, textStyle.Render(prefix)+mutedStyle.Render(author+"  ")+previewStyle.Render(preview)+mutedStyle.Render(info))
+	}
+	if end < len(d.messages) {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  … %d more", len(d.messages)-end)))
+	}
+	lines = append(lines, "", help("↑/↓", "select", "enter/m", "markdown", "t", "text", "c", "code only"))
+	return d.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (d *messageCopyDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewMessageCopyDialog creates the copy menu of the session's messages,
+// with the last one selected
+func NewMessageCopyDialog(a *app.App) MessageCopyDialog {
+	d := &messageCopyDialog{
+		app: a,
+		modal: modal.New(
+			modal.WithTitle("Copy message"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	for i := len(a.Messages) - 1; i >= 0; i-- {
+		if app.MessageText(a.Messages[i]) != "" {
+			d.messages = append(d.messages, a.Messages[i])
+		}
+	}
+	return d
+}
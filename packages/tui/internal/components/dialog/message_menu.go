@@ -0,0 +1,160 @@
+package dialog
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/commands"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// MessageMenuDialog is the context menu of a message, opened by a long
+// press on touch screens or with /menu for the message in view
+type MessageMenuDialog interface {
+	layout.Modal
+}
+
+// messageMenuItem is an action of the menu, picked with its key or with
+// enter when selected
+type messageMenuItem struct {
+	key   string
+	label string
+}
+
+var messageMenuItems = []messageMenuItem{
+	{"m", "Copy as markdown"},
+	{"t", "Copy as text"},
+	{"c", "Copy code blocks"},
+	{"b", "Bookmark"},
+	{"v", "Select lines"},
+}
+
+// userMessageMenuItems are the actions only a user message has
+var userMessageMenuItems = []messageMenuItem{
+	{"e", "Edit and resend"},
+	{"f", "Edit in a new branch"},
+}
+
+type messageMenuDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	message  app.Message
+	selected int
+}
+
+func (d *messageMenuDialog) Init() tea.Cmd {
+	return nil
+}
+
+// items returns the actions of the message
+func (d *messageMenuDialog) items() []messageMenuItem {
+	if _, ok := d.message.Info.(opencode.UserMessage); ok {
+		return append(slices.Clone(messageMenuItems), userMessageMenuItems...)
+	}
+	return messageMenuItems
+}
+
+func (d *messageMenuDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return d, nil
+	}
+	items := d.items()
+	action := key.String()
+	switch action {
+	case "up", "k":
+		d.selected = max(0, d.selected-1)
+		return d, nil
+	case "down", "j":
+		d.selected = min(len(items)-1, d.selected+1)
+		return d, nil
+	case "enter":
+		action = items[d.selected].key
+	}
+	if !slices.ContainsFunc(items, func(item messageMenuItem) bool { return item.key == action }) {
+		return d, nil
+	}
+	var cmd tea.Cmd
+	switch action {
+	case "m":
+		cmd = d.app.CopyMessage(d.message, app.CopyMarkdown)
+	case "t":
+		cmd = d.app.CopyMessage(d.message, app.CopyText)
+	case "c":
+		cmd = d.app.CopyMessage(d.message, app.CopyCode)
+	case "b":
+		bookmarked, save, err := d.app.ToggleBookmark(app.MessageID(d.message))
+		if err != nil {
+			cmd = toast.NewErrorToast(err.Error())
+			break
+		}
+		text := "Bookmark removed"
+		if bookmarked {
+			text = "Message bookmarked"
+		}
+		cmd = tea.Batch(save, toast.NewSuccessToast(text))
+	case "v":
+		cmd = util.CmdHandler(commands.ExecuteCommandMsg(d.app.Commands[commands.MessagesSelectCommand]))
+	case "e", "f":
+		edit := d.app.EditMessage
+		if action == "f" {
+			edit = d.app.BranchFromMessage
+		}
+		var err error
+		if cmd, err = edit(d.message); err != nil {
+			cmd = toast.NewErrorToast(err.Error())
+		}
+	default:
+		return d, nil
+	}
+	return d, tea.Sequence(util.CmdHandler(modal.CloseModalMsg{}), cmd)
+}
+
+func (d *messageMenuDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	width := max(30, min(60, layout.Current.Container.Width-12))
+
+	preview := strings.Join(strings.Fields(app.MessageText(d.message)), " ")
+	lines := []string{mutedStyle.Render(ansi.Truncate(preview, width, "…")), ""}
+	for i, item := range d.items() {
+		label := item.label
+		if item.key == "b" && d.app.Bookmarked(app.MessageID(d.message)) {
+			label = "Remove bookmark"
+		}
+		prefix, style := "  ", textStyle
+		if i == d.selected {
+			prefix, style = "› ", style.Foreground(t.Primary()).Bold(true)
+		}
+		lines = append(lines, textStyle.Render(prefix)+keyStyle.Render(item.key)+mutedStyle.Render("  ")+style.Render(label))
+	}
+	return d.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (d *messageMenuDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewMessageMenuDialog creates the context menu of a message
+func NewMessageMenuDialog(a *app.App, message app.Message) MessageMenuDialog {
+	return &messageMenuDialog{
+		app:     a,
+		message: message,
+		modal: modal.New(
+			modal.WithTitle("Message"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+}
@@ -3,8 +3,10 @@ package dialog
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
 	"github.com/aaronmrosenthal/rycode/internal/layout"
 	"github.com/aaronmrosenthal/rycode/internal/performance"
 	"github.com/aaronmrosenthal/rycode/internal/styles"
@@ -22,28 +24,49 @@ type PerformanceDialog interface {
 type performanceDialog struct {
 	app     *app.App
 	monitor *performance.PerformanceMonitor
+	modal   *modal.Modal
 	width   int
 	height  int
 }
 
-// NewPerformanceDialog creates a new performance monitoring dialog
+// performanceTickMsg refreshes the metrics while the dialog is open
+type performanceTickMsg struct{}
+
+// NewPerformanceDialog creates a new performance monitoring dialog. Opening
+// it turns the render profiler on.
 func NewPerformanceDialog(app *app.App) PerformanceDialog {
+	monitor := performance.GetMonitor()
+	monitor.Enable()
 	return &performanceDialog{
 		app:     app,
-		monitor: performance.GetMonitor(),
+		monitor: monitor,
+		modal:   modal.New(modal.WithMaxWidth(layout.Current.Container.Width - 8)),
+		width:   max(50, min(100, layout.Current.Container.Width-12)),
 	}
 }
 
 func (d *performanceDialog) Init() tea.Cmd {
-	return nil
+	d.monitor.RecordMemorySnapshot()
+	return performanceTick()
+}
+
+// performanceTick schedules the next refresh of the metrics
+func performanceTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return performanceTickMsg{}
+	})
 }
 
 func (d *performanceDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		d.width = msg.Width
+		d.width = max(50, min(100, msg.Width-12))
 		d.height = msg.Height
 
+	case performanceTickMsg:
+		d.monitor.RecordMemorySnapshot()
+		return d, performanceTick()
+
 	case tea.KeyPressMsg:
 		switch msg.String() {
 		case "r":
@@ -59,6 +82,14 @@ func (d *performanceDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "c":
 			// Clear warnings
 			d.monitor.ClearWarnings()
+		case "p":
+			return d, d.app.SetPprof(performance.PprofAddr() == "", "")
+		case "f":
+			return d, d.app.ToggleCPUProfile()
+		case "h":
+			return d, d.app.WriteHeapProfile()
+		case "a":
+			return d, d.app.SetAllocSampling(!performance.AllocSampling())
 		}
 	}
 
@@ -118,24 +149,44 @@ func (d *performanceDialog) View() string {
 		sections = append(sections, "")
 	}
 
+	// Profiling
+	sections = append(sections, typo.Subheading.Render("🔬 Profiling"))
+	sections = append(sections, d.renderProfiling())
+	sections = append(sections, "")
+
 	// Help footer
 	helpStyle := styles.NewStyle().
 		Foreground(t.TextMuted()).
 		Faint(true)
 
-	help := helpStyle.Render("[r] Reset  [t] Toggle  [c] Clear warnings  [ESC] Close")
-	sections = append(sections, help)
+	help := helpStyle.Render("[r] Reset  [t] Toggle  [c] Clear warnings  [p] pprof  [f] CPU profile  [h] Heap profile  [a] Alloc sampling  [ESC] Close")
+	sections = append(sections, lipgloss.NewStyle().Width(d.width).Render(help))
 
-	content := strings.Join(sections, "\n")
+	return strings.Join(sections, "\n")
+}
 
-	// Wrap in bordered box
-	boxStyle := styles.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(t.Border()).
-		Padding(2, 3).
-		Width(d.width - 4)
+// renderProfiling shows what is being profiled, and where to read it
+func (d *performanceDialog) renderProfiling() string {
+	t := theme.CurrentTheme()
+	labelStyle := styles.NewStyle().Foreground(t.TextMuted())
+	onStyle := styles.NewStyle().Foreground(t.Success()).Bold(true)
+	state := func(on bool, detail string) string {
+		if on {
+			return onStyle.Render("on") + labelStyle.Render(detail)
+		}
+		return labelStyle.Render("off")
+	}
 
-	return boxStyle.Render(content)
+	addr := performance.PprofAddr()
+	lines := []string{
+		labelStyle.Render(fmt.Sprintf("%-18s", "pprof endpoint")) + state(addr != "", "  http://"+addr+"/debug/pprof/"),
+		labelStyle.Render(fmt.Sprintf("%-18s", "CPU profile")) + state(performance.CPUProfiling(), "  written to "+d.app.ProfileDir()+" when stopped"),
+		labelStyle.Render(fmt.Sprintf("%-18s", "Alloc sampling")) + state(performance.AllocSampling(), "  every 4 KB"),
+	}
+	if addr == "" {
+		lines = append(lines, labelStyle.Faint(true).Render("Set "+performance.PprofEnv+"=1 to serve pprof from launch"))
+	}
+	return strings.Join(lines, "\n")
 }
 
 // renderHealthScore creates a health score visualization
@@ -381,7 +432,7 @@ func (d *performanceDialog) renderWarnings(warnings []string) string {
 }
 
 func (d *performanceDialog) Render(background string) string {
-	return d.View()
+	return d.modal.Render(d.View(), background)
 }
 
 func (d *performanceDialog) Close() tea.Cmd {
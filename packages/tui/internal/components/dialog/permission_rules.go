@@ -0,0 +1,130 @@
+package dialog
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+)
+
+// PermissionRulesDialog lists the remembered answers to permission
+// requests, to flip, rescope or forget them
+type PermissionRulesDialog interface {
+	layout.Modal
+}
+
+type permissionRulesDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	selected int
+}
+
+func (d *permissionRulesDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *permissionRulesDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyPressMsg)
+	rules := d.app.State.PermissionRules
+	if !ok || len(rules) == 0 {
+		return d, nil
+	}
+	rule := rules[d.selected]
+	switch keyMsg.String() {
+	case "up", "k":
+		d.selected = max(0, d.selected-1)
+	case "down", "j":
+		d.selected = min(len(rules)-1, d.selected+1)
+	case "space":
+		rule.Allow = !rule.Allow
+		return d, d.app.SetPermissionRule(d.selected, rule)
+	case "g":
+		// A rule holds in every project, or only in this one
+		if rule.Project == "" {
+			rule.Project = d.app.PermissionProject()
+		} else {
+			rule.Project = ""
+		}
+		return d, d.app.SetPermissionRule(d.selected, rule)
+	case "d", "delete", "backspace":
+		cmd := d.app.RemovePermissionRule(d.selected)
+		d.selected = max(0, min(d.selected, len(d.app.State.PermissionRules)-1))
+		return d, cmd
+	}
+	return d, nil
+}
+
+func (d *permissionRulesDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+
+	rules := d.app.State.PermissionRules
+	if len(rules) == 0 {
+		return d.modal.Render(mutedStyle.Render("No permission rules")+"\n\n"+
+			mutedStyle.Render("Answer a request with p to always allow it in this project, or n to never allow it,")+"\n"+
+			mutedStyle.Render("or add one with /permissions allow|deny <tool> [pattern]"), background)
+	}
+
+	project := d.app.PermissionProject()
+	width := max(40, layout.Current.Container.Width-12)
+	var lines []string
+	for i, rule := range rules {
+		prefix := "  "
+		patternStyle := textStyle
+		if i == d.selected {
+			prefix = "› "
+			patternStyle = patternStyle.Bold(true)
+		}
+		verb := base.Foreground(t.Error()).Render("deny  ")
+		if rule.Allow {
+			verb = base.Foreground(t.Success()).Render("allow ")
+		}
+		scope := "  every project"
+		switch rule.Project {
+		case "":
+		case project:
+			scope = "  this project"
+		default:
+			scope = "  " + rule.Project
+		}
+		pattern := rule.Pattern
+		if pattern == "" {
+			pattern = "*"
+		}
+		pattern = ansi.Truncate(pattern, width-len(prefix)-6-len(rule.Tool)-1-len(scope), "…")
+		lines = append(lines, textStyle.Render(prefix)+verb+mutedStyle.Render(rule.Tool+" ")+patternStyle.Render(pattern)+mutedStyle.Render(scope))
+	}
+
+	lines = append(lines, "", help("↑/↓", "select", "space", "allow/deny", "g", "this/every project", "d", "forget"))
+	return d.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (d *permissionRulesDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewPermissionRulesDialog creates a dialog listing the permission rules
+func NewPermissionRulesDialog(app *app.App) PermissionRulesDialog {
+	return &permissionRulesDialog{
+		app: app,
+		modal: modal.New(
+			modal.WithTitle("Permission Rules"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+}
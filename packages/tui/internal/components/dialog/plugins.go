@@ -0,0 +1,243 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/plugin"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// maxPluginRows is the number of plugins or plugin items listed at once
+const maxPluginRows = 8
+
+// PluginsDialog lists the installed plugins and turns them on or off
+type PluginsDialog interface {
+	layout.Modal
+}
+
+type pluginsDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	selected int
+	pending  string // Plugin being turned on or off
+}
+
+func (d *pluginsDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *pluginsDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case app.PluginChangedMsg:
+		if msg.Plugin.Manifest.Name == d.pending {
+			d.pending = ""
+		}
+	case tea.KeyPressMsg:
+		plugins := d.app.Plugins()
+		if len(plugins) == 0 {
+			return d, nil
+		}
+		switch msg.String() {
+		case "up", "k":
+			d.selected = max(0, d.selected-1)
+		case "down", "j":
+			d.selected = min(len(plugins)-1, d.selected+1)
+		case "space", "enter":
+			if d.pending != "" {
+				return d, nil
+			}
+			l := plugins[min(d.selected, len(plugins)-1)]
+			d.pending = l.Manifest.Name
+			return d, d.app.SetPluginEnabled(l.Manifest.Name, !l.Enabled)
+		}
+	}
+	return d, nil
+}
+
+func (d *pluginsDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+	width := max(40, min(90, layout.Current.Container.Width-12))
+
+	plugins := d.app.Plugins()
+	if len(plugins) == 0 {
+		return d.modal.Render(mutedStyle.Width(width).Render(
+			"No plugins installed. Add a directory with a "+plugin.ManifestFile+" to plugins/ in the config directory or to .rycode/plugins in the project.",
+		), background)
+	}
+	d.selected = min(d.selected, len(plugins)-1)
+
+	var lines []string
+	start := max(0, min(d.selected-maxPluginRows/2, len(plugins)-maxPluginRows))
+	end := min(len(plugins), start+maxPluginRows)
+	for i := start; i < end; i++ {
+		l := plugins[i]
+		prefix := "  "
+		nameStyle := textStyle
+		if i == d.selected {
+			prefix = "› "
+			nameStyle = nameStyle.Foreground(t.Primary()).Bold(true)
+		}
+		status := mutedStyle.Render("off")
+		switch {
+		case l.Manifest.Name == d.pending:
+			status = mutedStyle.Render("…")
+		case l.Err != nil:
+			status = base.Foreground(t.Error()).Render("failed")
+		case l.Running():
+			status = base.Foreground(t.Success()).Render("on")
+		}
+		lines = append(lines, textStyle.Render(prefix)+nameStyle.Render(l.Manifest.Name)+"  "+status+mutedStyle.Render("  "+l.Manifest.Description))
+	}
+	if end < len(plugins) {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  … %d more", len(plugins)-end)))
+	}
+
+	l := plugins[d.selected]
+	lines = append(lines, "")
+	if l.Manifest.Dir != "" {
+		lines = append(lines, mutedStyle.Render(util.Relative(l.Manifest.Dir)))
+	}
+	if l.Err != nil {
+		lines = append(lines, base.Foreground(t.Error()).Width(width).Render(l.Err.Error()))
+	}
+	var commands []string
+	for _, c := range d.app.Commands.Sorted() {
+		if c.Plugin == l.Manifest.Name {
+			commands = append(commands, "/"+c.PrimaryTrigger())
+		}
+	}
+	if len(commands) > 0 {
+		lines = append(lines, mutedStyle.Render("commands "+strings.Join(commands, ", ")))
+	}
+	var widgets []string
+	for _, w := range l.Contributions.Widgets {
+		widgets = append(widgets, l.Manifest.Name+"."+w.Name)
+	}
+	if len(widgets) > 0 {
+		lines = append(lines, mutedStyle.Render("widgets "+strings.Join(widgets, ", ")))
+	}
+	lines = append(lines, "", help("↑/↓", "select", "space", "turn on/off"))
+	return d.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (d *pluginsDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewPluginsDialog creates a dialog managing the installed plugins
+func NewPluginsDialog(app *app.App) PluginsDialog {
+	return &pluginsDialog{
+		app: app,
+		modal: modal.New(
+			modal.WithTitle("Plugins"),
+			modal.WithMaxWidth(min(94, layout.Current.Container.Width-8)),
+		),
+	}
+}
+
+// PluginDialog shows a list a plugin asked the user to choose from
+type PluginDialog interface {
+	layout.Modal
+}
+
+type pluginDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	plugin   string
+	dialog   plugin.Dialog
+	selected int
+}
+
+func (d *pluginDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *pluginDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyPressMsg)
+	if !ok || len(d.dialog.Items) == 0 {
+		return d, nil
+	}
+	switch key.String() {
+	case "up", "k":
+		d.selected = max(0, d.selected-1)
+	case "down", "j":
+		d.selected = min(len(d.dialog.Items)-1, d.selected+1)
+	case "enter":
+		event := plugin.Event{Type: plugin.EventSelect, Dialog: d.dialog.ID, Item: d.dialog.Items[d.selected].ID}
+		return d, tea.Sequence(
+			util.CmdHandler(modal.CloseModalMsg{}),
+			d.app.PluginEvent(d.plugin, event),
+		)
+	}
+	return d, nil
+}
+
+func (d *pluginDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	width := max(40, min(90, layout.Current.Container.Width-12))
+
+	var lines []string
+	if d.dialog.Body != "" {
+		lines = append(lines, util.TruncateHeight(textStyle.Width(width).Render(d.dialog.Body), 12))
+	}
+	if len(d.dialog.Items) > 0 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		start := max(0, min(d.selected-maxPluginRows/2, len(d.dialog.Items)-maxPluginRows))
+		end := min(len(d.dialog.Items), start+maxPluginRows)
+		for i := start; i < end; i++ {
+			item := d.dialog.Items[i]
+			prefix := "  "
+			labelStyle := textStyle
+			if i == d.selected {
+				prefix = "› "
+				labelStyle = labelStyle.Foreground(t.Primary()).Bold(true)
+			}
+			lines = append(lines, textStyle.Render(prefix)+labelStyle.Render(item.Label)+mutedStyle.Render("  "+item.Description))
+		}
+		if end < len(d.dialog.Items) {
+			lines = append(lines, mutedStyle.Render(fmt.Sprintf("  … %d more", len(d.dialog.Items)-end)))
+		}
+	}
+	lines = append(lines, "", mutedStyle.Render("from the "+d.plugin+" plugin"))
+	return d.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (d *pluginDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewPluginDialog creates a dialog showing what a plugin asked for
+func NewPluginDialog(app *app.App, name string, dialog plugin.Dialog) PluginDialog {
+	return &pluginDialog{
+		app:    app,
+		plugin: name,
+		dialog: dialog,
+		modal: modal.New(
+			modal.WithTitle(dialog.Title),
+			modal.WithMaxWidth(min(94, layout.Current.Container.Width-8)),
+		),
+	}
+}
@@ -0,0 +1,139 @@
+package dialog
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/projecttools"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// ProjectToolsDialog lists the tools the project declares for the agent,
+// to run one by hand
+type ProjectToolsDialog interface {
+	layout.Modal
+}
+
+type projectToolsDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	tools    []projecttools.Tool
+	err      error
+	selected int
+}
+
+func (d *projectToolsDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *projectToolsDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyPressMsg)
+	if !ok || len(d.tools) == 0 {
+		return d, nil
+	}
+	switch keyMsg.String() {
+	case "up", "k":
+		d.selected = max(0, d.selected-1)
+	case "down", "j":
+		d.selected = min(len(d.tools)-1, d.selected+1)
+	case "enter":
+		return d, tea.Sequence(
+			util.CmdHandler(modal.CloseModalMsg{}),
+			util.CmdHandler(app.SetEditorContentMsg{Text: "/tools " + d.tools[d.selected].Name + " "}),
+		)
+	}
+	return d, nil
+}
+
+func (d *projectToolsDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+
+	if d.err != nil {
+		return d.modal.Render(base.Foreground(t.Error()).Render(d.err.Error()), background)
+	}
+	if len(d.tools) == 0 {
+		return d.modal.Render(mutedStyle.Render("This project declares no tools")+"\n\n"+
+			mutedStyle.Render(fmt.Sprintf("Add them to %s as \"tools\": [{\"name\", \"description\", \"command\", \"args\"}]", projecttools.ConfigPath)), background)
+	}
+
+	width := max(40, layout.Current.Container.Width-12)
+	var lines []string
+	for i, tool := range d.tools {
+		prefix := "  "
+		nameStyle := textStyle
+		if i == d.selected {
+			prefix = "› "
+			nameStyle = nameStyle.Foreground(t.Primary()).Bold(true)
+		}
+		line := textStyle.Render(prefix) + nameStyle.Render(tool.Name)
+		if tool.Description != "" {
+			line += mutedStyle.Render("  " + ansi.Truncate(tool.Description, width-len(prefix)-len(tool.Name)-2, "…"))
+		}
+		lines = append(lines, line)
+		if i == d.selected {
+			lines = append(lines, mutedStyle.Render("    "+ansi.Truncate(tool.Command, width-4, "…")))
+			if args := toolArgs(tool); args != "" {
+				lines = append(lines, mutedStyle.Render("    "+ansi.Truncate(args, width-4, "…")))
+			}
+		}
+	}
+
+	lines = append(lines, "", help("↑/↓", "select", "enter", "run"))
+	return d.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (d *projectToolsDialog) Close() tea.Cmd {
+	return nil
+}
+
+// toolArgs summarizes a tool's arguments as name=type, optional ones in
+// brackets
+func toolArgs(tool projecttools.Tool) string {
+	if tool.Args == nil {
+		return ""
+	}
+	var args []string
+	for _, name := range slices.Sorted(maps.Keys(tool.Args.Properties)) {
+		arg := name + "=" + cmp.Or(tool.Args.Properties[name].Type, "value")
+		if !slices.Contains(tool.Args.Required, name) {
+			arg = "[" + arg + "]"
+		}
+		args = append(args, arg)
+	}
+	return strings.Join(args, " ")
+}
+
+// NewProjectToolsDialog creates a dialog listing the project's tools
+func NewProjectToolsDialog(app *app.App) ProjectToolsDialog {
+	tools, err := app.ProjectTools()
+	return &projectToolsDialog{
+		app:   app,
+		tools: tools,
+		err:   err,
+		modal: modal.New(
+			modal.WithTitle("Project Tools"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+}
@@ -1,7 +1,6 @@
 package dialog
 
 import (
-	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -55,17 +54,19 @@ func NewProvidersDialog(app *app.App) ProvidersDialog {
 
 // loadProviders fetches current provider statuses
 func (d *providersDialog) loadProviders() {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := d.app.TimeoutContext(app.TimeoutStatus)
 	defer cancel()
 
 	// Known providers
-	providerIDs := []string{"anthropic", "openai", "google", "xai", "qwen"}
+	providerIDs := []string{"anthropic", "openai", "google", "xai", "qwen", "azure", "amazon-bedrock"}
 	providerNames := map[string]string{
-		"anthropic": "Anthropic (Claude)",
-		"openai":    "OpenAI (GPT)",
-		"google":    "Google (Gemini)",
-		"xai":       "X.AI (Grok)",
-		"qwen":      "Alibaba (Qwen)",
+		"anthropic":      "Anthropic (Claude)",
+		"openai":         "OpenAI (GPT)",
+		"google":         "Google (Gemini)",
+		"xai":            "X.AI (Grok)",
+		"qwen":           "Alibaba (Qwen)",
+		"azure":          "Microsoft (Azure OpenAI)",
+		"amazon-bedrock": "Amazon (AWS Bedrock)",
 	}
 
 	for _, id := range providerIDs {
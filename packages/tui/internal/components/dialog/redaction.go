@@ -0,0 +1,236 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/lipgloss/v2/compat"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/transcript"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// maxRedactionRows is the number of findings listed at once
+const maxRedactionRows = 10
+
+// RedactionDialog lists the secrets, paths and user names found in a
+// transcript before it is exported, masking them one by one or by category.
+// Shared sessions are listed to be reviewed but can't be masked.
+type RedactionDialog interface {
+	layout.Modal
+}
+
+type redactionDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	review   app.ExportReviewMsg
+	masked   []bool
+	selected int
+}
+
+func (r *redactionDialog) Init() tea.Cmd {
+	return nil
+}
+
+// sharing reports whether the review is of a session about to be shared
+func (r *redactionDialog) sharing() bool {
+	return len(r.review.Formats) == 0
+}
+
+func (r *redactionDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return r, nil
+	}
+	switch key.String() {
+	case "up", "k":
+		r.selected = max(0, r.selected-1)
+	case "down", "j":
+		r.selected = min(len(r.review.Findings)-1, r.selected+1)
+	case "space":
+		if !r.sharing() {
+			r.masked[r.selected] = !r.masked[r.selected]
+		}
+	case "s":
+		r.toggleCategory(transcript.CategorySecret)
+	case "p":
+		r.toggleCategory(transcript.CategoryPath)
+	case "u":
+		r.toggleCategory(transcript.CategoryUsername)
+	case "c":
+		r.toggleCategory(transcript.CategoryContact)
+	case "enter":
+		close := util.CmdHandler(modal.CloseModalMsg{})
+		if r.sharing() {
+			return r, tea.Sequence(close, r.app.ShareSession(r.review.Transcript.Session.ID))
+		}
+		var masked []transcript.Finding
+		for i, finding := range r.review.Findings {
+			if r.masked[i] {
+				masked = append(masked, finding)
+			}
+		}
+		redacted := r.review.Transcript.Redact(masked)
+		return r, tea.Sequence(close, r.app.WriteTranscripts(redacted, r.review.Formats))
+	}
+	return r, nil
+}
+
+// toggleCategory masks every finding of a category, or unmasks them when
+// they are all masked already
+func (r *redactionDialog) toggleCategory(category transcript.Category) {
+	if r.sharing() {
+		return
+	}
+	all := true
+	for i, finding := range r.review.Findings {
+		if finding.Category == category && !r.masked[i] {
+			all = false
+		}
+	}
+	for i, finding := range r.review.Findings {
+		if finding.Category == category {
+			r.masked[i] = !all
+		}
+	}
+}
+
+// categoryColor returns the color findings of a category are drawn in
+func categoryColor(t theme.Theme, category transcript.Category) compat.AdaptiveColor {
+	switch category {
+	case transcript.CategorySecret:
+		return t.Error()
+	case transcript.CategoryPath:
+		return t.Warning()
+	case transcript.CategoryContact:
+		return t.Accent()
+	}
+	return t.Info()
+}
+
+func (r *redactionDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+	width := max(40, layout.Current.Container.Width-12)
+
+	counts := make(map[transcript.Category]int)
+	maskedCount := 0
+	for i, finding := range r.review.Findings {
+		counts[finding.Category]++
+		if r.masked[i] {
+			maskedCount++
+		}
+	}
+	var summary []string
+	for _, category := range transcript.Categories {
+		if counts[category] > 0 {
+			summary = append(summary, base.Foreground(categoryColor(t, category)).Render(fmt.Sprintf("%d %s", counts[category], category)))
+		}
+	}
+	lines := []string{strings.Join(summary, mutedStyle.Render(" · "))}
+	if r.sharing() {
+		lines = append(lines, base.Foreground(t.Warning()).Width(width).Render("Shared pages show the session as the server has it, so these can't be masked. Export the session instead to share a redacted copy."))
+	} else {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("%d of %d will be masked", maskedCount, len(r.review.Findings))))
+	}
+	lines = append(lines, "")
+
+	// Keep the selected finding in view when there are many
+	findings := r.review.Findings
+	start := max(0, min(r.selected-maxRedactionRows/2, len(findings)-maxRedactionRows))
+	end := min(len(findings), start+maxRedactionRows)
+	for i := start; i < end; i++ {
+		finding := findings[i]
+		prefix := "  "
+		if i == r.selected {
+			prefix = "› "
+		}
+		if !r.sharing() {
+			if r.masked[i] {
+				prefix += "[x] "
+			} else {
+				prefix += "[ ] "
+			}
+		}
+		category := base.Foreground(categoryColor(t, finding.Category)).Bold(true).Render(fmt.Sprintf("%-9s", finding.Category))
+		text := strings.Join(strings.Fields(finding.Text), " ")
+		text = ansi.Truncate(text, max(10, width/2), "…")
+		textRendered := textStyle.Render(text)
+		if i == r.selected {
+			textRendered = textStyle.Bold(true).Render(text)
+		}
+		messages := "1 message"
+		if len(finding.Messages) > 1 {
+			messages = fmt.Sprintf("%d messages", len(finding.Messages))
+		}
+		lines = append(lines, textStyle.Render(prefix)+category+" "+textRendered+mutedStyle.Render("  "+finding.Kind+" · "+messages))
+	}
+	if end < len(findings) {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  … %d more", len(findings)-end)))
+	}
+
+	// Show where the selected finding appears, highlighted
+	finding := findings[r.selected]
+	message := r.review.Transcript.Messages[finding.Messages[0]]
+	radius := max(10, (width-20)/2)
+	before, after := r.review.Transcript.Context(finding, radius)
+	shown := strings.Join(strings.Fields(finding.Text), " ")
+	if !r.sharing() && r.masked[r.selected] {
+		shown = finding.Category.Mask()
+	}
+	highlight := base.Foreground(t.Background()).Background(categoryColor(t, finding.Category)).Bold(true)
+	lines = append(lines,
+		"",
+		keyStyle.Render(fmt.Sprintf("In the %s message of %s", message.Role, message.Created.Format("15:04:05"))),
+		mutedStyle.Render("…"+before)+highlight.Render(ansi.Truncate(shown, max(10, width-2*radius), "…"))+mutedStyle.Render(after+"…"),
+		"",
+	)
+	if r.sharing() {
+		lines = append(lines, help("↑/↓", "select", "enter", "share anyway", "esc", "cancel"))
+	} else {
+		lines = append(lines, help("↑/↓", "select", "space", "mask", "s/p/u/c", "mask all secrets/paths/users/contacts", "enter", "export"))
+	}
+	return r.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (r *redactionDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewRedactionDialog creates a dialog reviewing the findings of a transcript
+// about to be exported or shared. Every finding starts out masked.
+func NewRedactionDialog(app *app.App, review app.ExportReviewMsg) RedactionDialog {
+	masked := make([]bool, len(review.Findings))
+	for i := range masked {
+		masked[i] = true
+	}
+	title := "Redact Export"
+	if len(review.Formats) == 0 {
+		title = "Review Share"
+	}
+	return &redactionDialog{
+		app:    app,
+		review: review,
+		masked: masked,
+		modal: modal.New(
+			modal.WithTitle(title),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+}
@@ -0,0 +1,447 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/textinput"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/diff"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/refactor"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+	"github.com/aaronmrosenthal/rycode/internal/viewport"
+)
+
+// maxRefactorRows is the number of files listed at once
+const maxRefactorRows = 12
+
+// RefactorDialog guides a workspace-wide refactor: it plans the affected
+// files, lets the user edit the plan, applies it in batches, asks for a
+// review of every changed file and finally runs the plan's check, if any
+type RefactorDialog interface {
+	layout.Modal
+}
+
+type refactorPhase int
+
+const (
+	refactorPlanning refactorPhase = iota
+	refactorEditing
+	refactorRunning
+	refactorReview
+	refactorChecking
+	refactorDone
+)
+
+type refactorDialog struct {
+	app         *app.App
+	modal       *modal.Modal
+	description string
+	plan        *refactor.Plan
+	phase       refactorPhase
+	selected    int
+	adding      bool
+	input       textinput.Model
+	reviewing   int
+	viewport    viewport.Model
+	err         string
+	checked     bool
+	checkErr    error
+}
+
+func (r *refactorDialog) Init() tea.Cmd {
+	if r.plan == nil {
+		return r.app.PlanRefactor(r.description)
+	}
+	if r.plan.Started() {
+		return r.advance()
+	}
+	return nil
+}
+
+func (r *refactorDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		r.resize()
+	case app.RefactorPlannedMsg:
+		if msg.Err != nil {
+			r.err = msg.Err.Error()
+			r.phase = refactorDone
+			return r, nil
+		}
+		r.plan = msg.Plan
+		r.phase = refactorEditing
+		if len(r.plan.Files) == 0 {
+			r.err = "The agent didn't name any existing files. Add them with a."
+		}
+		return r, nil
+	case app.RefactorBatchDoneMsg:
+		if r.plan == nil {
+			return r, nil
+		}
+		if msg.SessionID != "" {
+			r.plan.SessionID = msg.SessionID
+		}
+		r.plan.FinishBatch(msg.Batch, msg.Err)
+		if msg.Err != nil {
+			r.err = msg.Err.Error()
+		}
+		return r, r.advance()
+	case app.RefactorCheckedMsg:
+		r.phase = refactorDone
+		r.checked = true
+		r.checkErr = msg.Err
+		r.viewport.SetContent(msg.Output)
+		r.viewport.GotoBottom()
+		return r, nil
+	case tea.KeyPressMsg:
+		switch r.phase {
+		case refactorEditing:
+			return r, r.updateEditing(msg)
+		case refactorReview:
+			return r, r.updateReview(msg)
+		case refactorDone:
+			switch msg.String() {
+			case "enter":
+				return r, util.CmdHandler(modal.CloseModalMsg{})
+			case "r":
+				if r.checked {
+					r.phase = refactorChecking
+					return r, r.app.RunRefactorCheck(r.plan)
+				}
+			default:
+				var cmd tea.Cmd
+				r.viewport, cmd = r.viewport.Update(msg)
+				return r, cmd
+			}
+		}
+		return r, nil
+	}
+
+	if r.phase == refactorReview || r.checked {
+		var cmd tea.Cmd
+		r.viewport, cmd = r.viewport.Update(msg)
+		return r, cmd
+	}
+	return r, nil
+}
+
+func (r *refactorDialog) updateEditing(msg tea.KeyPressMsg) tea.Cmd {
+	if r.adding {
+		if msg.String() == "enter" {
+			path := r.input.Value()
+			if path != "" && r.plan.AddFile(path) {
+				r.selected = len(r.plan.Files) - 1
+			}
+			r.adding = false
+			r.input.SetValue("")
+			return nil
+		}
+		var cmd tea.Cmd
+		r.input, cmd = r.input.Update(msg)
+		return cmd
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		r.selected = max(0, r.selected-1)
+	case "down", "j":
+		r.selected = min(len(r.plan.Files)-1, r.selected+1)
+	case "d", "x", "delete", "backspace":
+		if r.plan.RemoveFile(r.selected) {
+			r.selected = max(0, min(r.selected, len(r.plan.Files)-1))
+		}
+	case "a":
+		r.adding = true
+		r.err = ""
+		return r.input.Focus()
+	case "enter":
+		if len(r.plan.Files) == 0 {
+			return nil
+		}
+		r.err = ""
+		return r.advance()
+	}
+	return nil
+}
+
+func (r *refactorDialog) updateReview(msg tea.KeyPressMsg) tea.Cmd {
+	switch msg.String() {
+	case "y":
+		r.plan.Accept(r.reviewing)
+	case "n":
+		if err := r.plan.Reject(r.reviewing); err != nil {
+			return toast.NewErrorToast(err.Error())
+		}
+	default:
+		var cmd tea.Cmd
+		r.viewport, cmd = r.viewport.Update(msg)
+		return cmd
+	}
+	return r.advance()
+}
+
+// advance moves the refactor to its next step: reviewing a changed file,
+// starting the next batch, or finishing. The plan is saved after every step
+// so the refactor can be resumed with /refactor.
+func (r *refactorDialog) advance() tea.Cmd {
+	if review := r.plan.AwaitingReview(); len(review) > 0 {
+		r.phase = refactorReview
+		r.reviewing = review[0]
+		r.renderReview()
+		return r.save()
+	}
+
+	if batch := r.plan.NextBatch(); len(batch) > 0 {
+		r.phase = refactorRunning
+		r.plan.StartBatch(batch)
+		return tea.Batch(r.save(), r.app.RunRefactorBatch(r.plan, batch))
+	}
+
+	r.phase = refactorDone
+	if err := r.plan.Remove(); err != nil {
+		return toast.NewErrorToast("Failed to remove refactor plan: " + err.Error())
+	}
+	// Verify the kept changes, unless everything was reverted
+	if r.plan.Check != nil {
+		for _, file := range r.plan.Files {
+			if file.Status == refactor.StatusAccepted {
+				r.phase = refactorChecking
+				return r.app.RunRefactorCheck(r.plan)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *refactorDialog) save() tea.Cmd {
+	if err := r.plan.Save(); err != nil {
+		return toast.NewErrorToast(err.Error(), toast.WithTitle("Refactor not resumable"))
+	}
+	return nil
+}
+
+func (r *refactorDialog) resize() {
+	r.viewport.SetWidth(max(40, layout.Current.Container.Width-12))
+	r.viewport.SetHeight(max(8, layout.Current.Viewport.Height-16))
+	if r.phase == refactorReview {
+		r.renderReview()
+	}
+}
+
+func (r *refactorDialog) renderReview() {
+	t := theme.CurrentTheme()
+	mutedStyle := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundPanel())
+
+	file := r.plan.Files[r.reviewing]
+	unified := diff.GenerateUnifiedDiff(
+		"a/"+file.Path,
+		"b/"+file.Path,
+		file.Original,
+		r.plan.Current(r.reviewing),
+		diff.DefaultContextLines,
+	)
+	formatted, err := diff.FormatUnifiedDiff(file.Path, unified, diff.WithWidth(r.viewport.Width()))
+	if err != nil {
+		r.viewport.SetContent(mutedStyle.Render("Failed to render diff: " + err.Error()))
+		return
+	}
+	r.viewport.SetContent(strings.TrimSuffix(formatted, "\n"))
+	r.viewport.GotoTop()
+}
+
+func (r *refactorDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	errorStyle := base.Foreground(t.Error())
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+
+	description := r.description
+	if r.plan != nil {
+		description = r.plan.Description
+	}
+	lines := []string{textStyle.Render(description), ""}
+
+	switch r.phase {
+	case refactorPlanning:
+		lines = append(lines, mutedStyle.Render("Asking the agent which files are affected…"))
+	case refactorEditing:
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("Proposed plan: %d files", len(r.plan.Files))), "")
+		lines = append(lines, r.renderFiles(true)...)
+		lines = append(lines, "")
+		if r.adding {
+			lines = append(lines, mutedStyle.Render("Add file:")+r.input.View())
+			lines = append(lines, help("enter", "add"))
+		} else {
+			lines = append(lines, help("↑/↓", "select", "d", "remove", "a", "add file", "enter", "start"))
+		}
+	case refactorRunning:
+		lines = append(lines, r.renderProgress(), "")
+		lines = append(lines, r.renderFiles(false)...)
+		lines = append(lines, "", mutedStyle.Render("Applying batch… closing this dialog keeps the plan; run /refactor to resume."))
+	case refactorReview:
+		review := r.plan.AwaitingReview()
+		file := r.plan.Files[r.reviewing]
+		stats := diff.ChangeStats(file.Original, r.plan.Current(r.reviewing))
+		lines = append(lines,
+			r.renderProgress(),
+			"",
+			textStyle.Render(fmt.Sprintf("Review %s", file.Path))+
+				mutedStyle.Render(fmt.Sprintf("  +%d -%d  (%d left in batch)", stats.Added, stats.Removed, len(review)-1)),
+			"",
+			r.viewport.View(),
+			"",
+			help("y", "keep", "n", "revert", "↑/↓", "scroll"),
+		)
+	case refactorChecking:
+		lines = append(lines, r.renderProgress(), "")
+		lines = append(lines, mutedStyle.Render("Running "+strings.Join(r.plan.Check.Command, " ")+"…"))
+	case refactorDone:
+		if r.plan != nil {
+			lines = append(lines, r.renderProgress(), "")
+			counts := make(map[refactor.FileStatus]int)
+			for _, file := range r.plan.Files {
+				counts[file.Status]++
+			}
+			lines = append(lines, textStyle.Render(fmt.Sprintf(
+				"Refactor complete: %d kept, %d reverted, %d unchanged, %d failed",
+				counts[refactor.StatusAccepted],
+				counts[refactor.StatusRejected],
+				counts[refactor.StatusUnchanged],
+				counts[refactor.StatusFailed],
+			)))
+		}
+		if r.checked {
+			command := strings.Join(r.plan.Check.Command, " ")
+			if r.checkErr != nil {
+				lines = append(lines, "", errorStyle.Render("✗ "+command+" failed: "+r.checkErr.Error()))
+			} else {
+				lines = append(lines, "", base.Foreground(t.Success()).Render("✓ "+command+" passed"))
+			}
+			lines = append(lines, "", r.viewport.View(), "", help("enter", "close", "r", "run again", "↑/↓", "scroll"))
+		} else {
+			lines = append(lines, "", help("enter", "close"))
+		}
+	}
+
+	if r.err != "" {
+		lines = append(lines, "", errorStyle.Render(r.err))
+	}
+	return r.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+// renderProgress renders a progress bar over the processed files
+func (r *refactorDialog) renderProgress() string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	done, total := r.plan.Progress()
+	width := 30
+	filled := 0
+	if total > 0 {
+		filled = done * width / total
+	}
+	return base.Foreground(t.Primary()).Render(strings.Repeat("█", filled)) +
+		base.Foreground(t.TextMuted()).Render(strings.Repeat("░", width-filled)) +
+		base.Foreground(t.Text()).Render(fmt.Sprintf(" %d/%d files", done, total))
+}
+
+// renderFiles lists the plan's files with their status, scrolled to keep the
+// selected file, or the batch in progress, in view
+func (r *refactorDialog) renderFiles(selectable bool) []string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+
+	focus := r.selected
+	if !selectable {
+		for i, file := range r.plan.Files {
+			if file.Status == refactor.StatusProcessing {
+				focus = i
+				break
+			}
+		}
+	}
+	start := max(0, min(focus-maxRefactorRows/2, len(r.plan.Files)-maxRefactorRows))
+	end := min(len(r.plan.Files), start+maxRefactorRows)
+
+	var lines []string
+	for i := start; i < end; i++ {
+		file := r.plan.Files[i]
+		icon, color := "○", t.TextMuted()
+		switch file.Status {
+		case refactor.StatusProcessing:
+			icon, color = "◐", t.Warning()
+		case refactor.StatusChanged:
+			icon, color = "●", t.Info()
+		case refactor.StatusAccepted:
+			icon, color = "✓", t.Success()
+		case refactor.StatusRejected:
+			icon, color = "↺", t.TextMuted()
+		case refactor.StatusUnchanged:
+			icon, color = "–", t.TextMuted()
+		case refactor.StatusFailed:
+			icon, color = "✗", t.Error()
+		}
+		prefix := "  "
+		style := base.Foreground(t.Text())
+		if selectable && i == r.selected {
+			prefix = "› "
+			style = style.Bold(true)
+		}
+		lines = append(lines, base.Foreground(color).Render(prefix+icon+" ")+style.Render(file.Path))
+	}
+	if end < len(r.plan.Files) {
+		lines = append(lines, base.Foreground(t.TextMuted()).Render(fmt.Sprintf("  … %d more", len(r.plan.Files)-end)))
+	}
+	return lines
+}
+
+func (r *refactorDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewRefactorDialog creates a refactor dialog. With a nil plan the agent is
+// asked to plan the described refactor; otherwise the saved plan is resumed.
+func NewRefactorDialog(app *app.App, description string, plan *refactor.Plan) RefactorDialog {
+	input := textinput.New()
+	input.Placeholder = "path/to/file"
+	input.Prompt = " "
+	input.CharLimit = -1
+
+	r := &refactorDialog{
+		app:         app,
+		description: description,
+		plan:        plan,
+		input:       input,
+		viewport:    viewport.New(),
+		modal: modal.New(
+			modal.WithTitle("Workspace Refactor"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	if plan != nil {
+		r.phase = refactorEditing
+		if plan.Title != "" {
+			r.modal = modal.New(
+				modal.WithTitle(plan.Title),
+				modal.WithMaxWidth(layout.Current.Container.Width-8),
+			)
+		}
+	}
+	r.resize()
+	return r
+}
@@ -0,0 +1,136 @@
+package dialog
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/repoqa"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+	"github.com/aaronmrosenthal/rycode/internal/viewport"
+)
+
+// RepoAnswerDialog shows an answer about the repository with its citations,
+// which open in a file viewer
+type RepoAnswerDialog interface {
+	layout.Modal
+}
+
+type repoAnswerDialog struct {
+	modal     *modal.Modal
+	viewport  viewport.Model
+	question  string
+	answer    string
+	citations []repoqa.Citation
+	selected  int
+	viewer    *fileViewerDialog
+}
+
+func (r *repoAnswerDialog) Init() tea.Cmd {
+	return r.viewport.Init()
+}
+
+func (r *repoAnswerDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(tea.WindowSizeMsg); ok {
+		r.resize()
+	}
+
+	if r.viewer != nil {
+		if key, ok := msg.(tea.KeyPressMsg); ok && !r.viewer.capturing() && (key.String() == "backspace" || key.String() == "left") {
+			r.viewer = nil
+			return r, nil
+		}
+		_, cmd := r.viewer.Update(msg)
+		return r, cmd
+	}
+
+	if key, ok := msg.(tea.KeyPressMsg); ok && len(r.citations) > 0 {
+		switch key.String() {
+		case "tab":
+			r.selected = (r.selected + 1) % len(r.citations)
+			return r, nil
+		case "shift+tab":
+			r.selected = (r.selected - 1 + len(r.citations)) % len(r.citations)
+			return r, nil
+		case "enter":
+			citation := r.citations[r.selected]
+			r.viewer = newFileViewer(citation.Path, citation.Line)
+			return r, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	r.viewport, cmd = r.viewport.Update(msg)
+	return r, cmd
+}
+
+func (r *repoAnswerDialog) resize() {
+	width := max(40, layout.Current.Container.Width-12)
+	r.viewport.SetWidth(width)
+	r.viewport.SetHeight(max(6, layout.Current.Viewport.Height-16-min(len(r.citations), 6)))
+	r.viewport.SetContent(util.ToMarkdown(r.answer, width, theme.CurrentTheme().BackgroundPanel()))
+}
+
+func (r *repoAnswerDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	linkStyle := base.Foreground(t.Primary())
+	selectedStyle := base.Foreground(t.Primary()).Bold(true).Underline(true)
+
+	if r.viewer != nil {
+		help := keyStyle.Render("backspace") + mutedStyle.Render(" back to answer")
+		return r.modal.Render(r.viewer.View()+"\n\n"+help, background)
+	}
+
+	lines := []string{mutedStyle.Render("Q: " + r.question), "", r.viewport.View(), ""}
+	if len(r.citations) == 0 {
+		lines = append(lines, mutedStyle.Render("No citations in this answer."))
+	} else {
+		// Keep the selected citation in view when there are many
+		start := max(0, min(r.selected-2, len(r.citations)-6))
+		end := min(len(r.citations), start+6)
+		for i := start; i < end; i++ {
+			citation := r.citations[i]
+			style := linkStyle
+			if i == r.selected {
+				style = selectedStyle
+			}
+			// Terminals supporting OSC 8 can open the file directly as well
+			uri := "file://" + filepath.Join(util.RootPath, citation.Path)
+			link := ansi.SetHyperlink(uri) + style.Render(citation.String()) + ansi.ResetHyperlink()
+			lines = append(lines, mutedStyle.Render(fmt.Sprintf("[%d] ", i+1))+link)
+		}
+		lines = append(lines, "", keyStyle.Render("tab")+mutedStyle.Render(" next citation   ")+
+			keyStyle.Render("enter")+mutedStyle.Render(" open   ")+
+			keyStyle.Render("↑/↓")+mutedStyle.Render(" scroll"))
+	}
+	return r.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (r *repoAnswerDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewRepoAnswerDialog creates a dialog for an answer and its citations
+func NewRepoAnswerDialog(question, answer string, citations []repoqa.Citation) RepoAnswerDialog {
+	r := &repoAnswerDialog{
+		question:  question,
+		answer:    answer,
+		citations: citations,
+		viewport:  viewport.New(),
+		modal: modal.New(
+			modal.WithTitle("Ask the Repo"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	r.resize()
+	return r
+}
@@ -0,0 +1,137 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/diff"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/viewport"
+)
+
+// ResponseDiffDialog shows what changed between a regenerated answer and the
+// answer it replaced
+type ResponseDiffDialog interface {
+	layout.Modal
+}
+
+type responseDiffDialog struct {
+	modal       *modal.Modal
+	viewport    viewport.Model
+	previous    app.ResponseSnapshot
+	current     app.ResponseSnapshot
+	sideBySide  bool
+	renderWidth int
+}
+
+func (d *responseDiffDialog) Init() tea.Cmd {
+	return d.viewport.Init()
+}
+
+func (d *responseDiffDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.resize()
+	case tea.KeyPressMsg:
+		if msg.String() == "tab" {
+			d.sideBySide = !d.sideBySide
+			d.renderContent()
+			return d, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	d.viewport, cmd = d.viewport.Update(msg)
+	return d, cmd
+}
+
+func (d *responseDiffDialog) resize() {
+	width := max(40, layout.Current.Container.Width-12)
+	height := max(8, layout.Current.Viewport.Height-12)
+	d.viewport.SetWidth(width)
+	d.viewport.SetHeight(height)
+	d.renderWidth = width
+	d.renderContent()
+}
+
+func (d *responseDiffDialog) renderContent() {
+	t := theme.CurrentTheme()
+	mutedStyle := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundPanel())
+
+	unified := diff.GenerateUnifiedDiff(
+		"previous.md",
+		"current.md",
+		d.previous.Text,
+		d.current.Text,
+		diff.DefaultContextLines,
+	)
+	if unified == "" {
+		d.viewport.SetContent(mutedStyle.Render("The regenerated answer is identical to the previous one."))
+		return
+	}
+
+	var formatted string
+	var err error
+	if d.sideBySide {
+		formatted, err = diff.FormatDiff("response.md", unified, diff.WithWidth(d.renderWidth))
+	} else {
+		formatted, err = diff.FormatUnifiedDiff("response.md", unified, diff.WithWidth(d.renderWidth))
+	}
+	if err != nil {
+		d.viewport.SetContent(mutedStyle.Render("Failed to render diff: " + err.Error()))
+		return
+	}
+	d.viewport.SetContent(strings.TrimSuffix(formatted, "\n"))
+}
+
+func (d *responseDiffDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	keyStyle := styles.NewStyle().
+		Foreground(t.Text()).
+		Background(t.BackgroundPanel()).
+		Bold(true).
+		Render
+	mutedStyle := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundPanel()).Render
+	addedStyle := styles.NewStyle().Foreground(t.DiffAdded()).Background(t.BackgroundPanel()).Render
+	removedStyle := styles.NewStyle().Foreground(t.DiffRemoved()).Background(t.BackgroundPanel()).Render
+
+	stats := diff.ChangeStats(d.previous.Text, d.current.Text)
+	summary := mutedStyle(d.previous.ModelLabel()+" → "+d.current.ModelLabel()+"   ") +
+		addedStyle(fmt.Sprintf("+%d", stats.Added)) +
+		mutedStyle(" ") +
+		removedStyle(fmt.Sprintf("-%d", stats.Removed))
+
+	mode := "side-by-side"
+	if d.sideBySide {
+		mode = "unified"
+	}
+	helpText := keyStyle("↑/↓") + mutedStyle(" scroll   ") + keyStyle("tab") + mutedStyle(" "+mode)
+
+	content := strings.Join([]string{summary, "", d.viewport.View(), "", helpText}, "\n")
+	return d.modal.Render(content, background)
+}
+
+func (d *responseDiffDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewResponseDiffDialog creates a dialog comparing the previous answer with
+// the current one
+func NewResponseDiffDialog(previous, current app.ResponseSnapshot) ResponseDiffDialog {
+	d := &responseDiffDialog{
+		previous: previous,
+		current:  current,
+		viewport: viewport.New(),
+		modal: modal.New(
+			modal.WithTitle("Diff vs Previous Answer"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	d.resize()
+	return d
+}
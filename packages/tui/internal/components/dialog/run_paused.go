@@ -0,0 +1,113 @@
+package dialog
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// RunPausedDialog tells that a run was paused for going over its budget,
+// with what it took, and asks whether it goes on
+type RunPausedDialog interface {
+	layout.Modal
+}
+
+type runPausedDialog struct {
+	app    *app.App
+	modal  *modal.Modal
+	paused app.RunPausedMsg
+}
+
+func (d *runPausedDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *runPausedDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return d, nil
+	}
+	switch key.String() {
+	case "enter", "r":
+		return d, d.resume(false)
+	case "u":
+		return d, d.resume(true)
+	}
+	return d, nil
+}
+
+func (d *runPausedDialog) resume(unbounded bool) tea.Cmd {
+	return tea.Sequence(
+		util.CmdHandler(modal.CloseModalMsg{}),
+		util.CmdHandler(app.RunResumedMsg{Paused: d.paused, Unbounded: unbounded}),
+	)
+}
+
+func (d *runPausedDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+	width := max(40, min(80, layout.Current.Container.Width-12))
+
+	p := d.paused
+	scope := "The " + p.Agent + " agent's run"
+	if p.Command != "" {
+		scope = "The run of /" + p.Command
+	}
+	lines := []string{
+		base.Foreground(t.Warning()).Width(width).Render(scope + " went over its " + strings.Join(p.Exceeded, " and ") + " limit, and was paused."),
+		"",
+	}
+	for _, limit := range app.RunLimitNames {
+		style := textStyle
+		if slices.Contains(p.Exceeded, limit) {
+			style = base.Foreground(t.Warning()).Bold(true)
+		}
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("%-6s", limit))+style.Render(p.Usage.Used(limit))+mutedStyle.Render(" of "+p.Budget.Limit(limit)))
+	}
+	if p.Doing != "" {
+		lines = append(lines, "", mutedStyle.Render("Last ")+textStyle.Render(ansi.Truncate(p.Doing, width-5, "…")))
+	}
+	lines = append(lines,
+		"",
+		mutedStyle.Width(width).Render("Resuming gives the run a fresh budget, or none at all. Staying paused leaves the prompt to your own instructions."),
+		"",
+		help("enter", "resume", "u", "resume without limits", "esc", "stay paused"),
+	)
+	return d.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (d *runPausedDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewRunPausedDialog creates the notice of a run paused for going over its
+// budget
+func NewRunPausedDialog(a *app.App, paused app.RunPausedMsg) RunPausedDialog {
+	return &runPausedDialog{
+		app:    a,
+		paused: paused,
+		modal: modal.New(
+			modal.WithTitle("Run paused"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+}
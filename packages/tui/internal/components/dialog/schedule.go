@@ -0,0 +1,188 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/schedule"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+)
+
+// ScheduleDialog lists the scheduled prompts and runs, pauses or removes
+// them
+type ScheduleDialog interface {
+	layout.Modal
+}
+
+type scheduleDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	jobs     []schedule.Job
+	selected int
+	running  map[string]bool
+	err      error
+}
+
+func (s *scheduleDialog) Init() tea.Cmd {
+	return nil
+}
+
+// reload reads the jobs again, keeping the selection in range
+func (s *scheduleDialog) reload() {
+	s.jobs, s.err = s.app.Schedules().Jobs()
+	s.selected = max(0, min(s.selected, len(s.jobs)-1))
+}
+
+func (s *scheduleDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case app.ScheduledRunMsg:
+		delete(s.running, msg.Job.ID)
+		s.reload()
+		return s, nil
+	case tea.KeyPressMsg:
+		if len(s.jobs) == 0 {
+			return s, nil
+		}
+		job := s.jobs[s.selected]
+		switch msg.String() {
+		case "up", "k":
+			s.selected = max(0, s.selected-1)
+		case "down", "j":
+			s.selected = min(len(s.jobs)-1, s.selected+1)
+		case "r":
+			if s.running[job.ID] {
+				return s, nil
+			}
+			s.running[job.ID] = true
+			return s, tea.Batch(
+				toast.NewInfoToast("Running "+job.Name+"…"),
+				s.app.RunScheduleNow(job),
+			)
+		case "p":
+			if err := s.app.Schedules().Modify(job.ID, func(j *schedule.Job) { j.Paused = !j.Paused }); err != nil {
+				return s, toast.NewErrorToast(err.Error())
+			}
+			s.reload()
+		case "d", "delete":
+			if err := s.app.Schedules().Remove(job.ID); err != nil {
+				return s, toast.NewErrorToast(err.Error())
+			}
+			s.reload()
+			return s, toast.NewInfoToast("Removed " + job.Name)
+		}
+	}
+	return s, nil
+}
+
+func (s *scheduleDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+
+	var lines []string
+	switch {
+	case s.err != nil:
+		lines = append(lines, base.Foreground(t.Error()).Render(s.err.Error()))
+	case len(s.jobs) == 0:
+		lines = append(lines,
+			textStyle.Render("No scheduled prompts."),
+			"",
+			mutedStyle.Render("Add one with /schedule add <when> | <prompt>, for example:"),
+			textStyle.Render("  /schedule add weekdays at 9 | summarize yesterday's commits"),
+		)
+	default:
+		now := time.Now()
+		for i, job := range s.jobs {
+			prefix := "  "
+			nameStyle := textStyle
+			if i == s.selected {
+				prefix = "› "
+				nameStyle = nameStyle.Bold(true)
+			}
+			status := mutedStyle.Render("next " + formatNextRun(job.Next(), now))
+			switch {
+			case s.running[job.ID]:
+				status = base.Foreground(t.Info()).Render("running…")
+			case job.Paused:
+				status = base.Foreground(t.Warning()).Render("paused")
+			case job.LastStatus == "error":
+				status = base.Foreground(t.Error()).Render("failed") + mutedStyle.Render(" · ") + status
+			}
+			lines = append(lines, textStyle.Render(prefix)+nameStyle.Render(job.Name)+
+				mutedStyle.Render(fmt.Sprintf("  %s  [%s]  ", job.Schedule, job.ID))+status)
+		}
+
+		job := s.jobs[s.selected]
+		width := max(40, layout.Current.Container.Width-12)
+		lines = append(lines, "", textStyle.Width(width).Render(job.Prompt), "")
+		var delivery []string
+		if job.KeepSession {
+			delivery = append(delivery, "saved as a session")
+		}
+		if job.Webhook != "" {
+			delivery = append(delivery, "posted to "+job.Webhook)
+		}
+		details := "Results are " + strings.Join(delivery, " and ")
+		if !job.LastRun.IsZero() {
+			details += " · last run " + job.LastRun.Format("Jan 2 15:04")
+		}
+		lines = append(lines, mutedStyle.Render(details))
+		if job.LastStatus == "error" && job.LastError != "" {
+			lines = append(lines, base.Foreground(t.Error()).Width(width).Render(job.LastError))
+		}
+		lines = append(lines, "", help("↑/↓", "select", "r", "run now", "p", "pause/resume", "d", "delete"))
+	}
+	lines = append(lines, "", mutedStyle.Render("Scheduled prompts run while `rycode daemon` is running."))
+	return s.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+// formatNextRun describes when a job runs next relative to now
+func formatNextRun(next, now time.Time) string {
+	switch {
+	case next.IsZero():
+		return "never"
+	case !next.After(now):
+		return "now"
+	case next.Sub(now) < time.Hour:
+		return fmt.Sprintf("in %dm", int(next.Sub(now).Minutes())+1)
+	case next.YearDay() == now.YearDay() && next.Year() == now.Year():
+		return next.Format("15:04")
+	case next.Sub(now) < 7*24*time.Hour:
+		return next.Format("Mon 15:04")
+	}
+	return next.Format("Jan 2 15:04")
+}
+
+func (s *scheduleDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewScheduleDialog creates a dialog listing the scheduled prompts
+func NewScheduleDialog(app *app.App) ScheduleDialog {
+	s := &scheduleDialog{
+		app:     app,
+		running: make(map[string]bool),
+		modal: modal.New(
+			modal.WithTitle("Scheduled Prompts"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	s.reload()
+	return s
+}
@@ -0,0 +1,180 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2/compat"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/secreview"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+)
+
+// maxFindingRows is the number of findings listed at once
+const maxFindingRows = 8
+
+// SecurityReviewDialog is a navigable report of security review findings
+// that opens each finding's location and exports the report to SARIF
+type SecurityReviewDialog interface {
+	layout.Modal
+}
+
+type securityReviewDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	report   *secreview.Report
+	selected int
+	viewer   *fileViewerDialog
+}
+
+func (s *securityReviewDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (s *securityReviewDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if s.viewer != nil {
+		if key, ok := msg.(tea.KeyPressMsg); ok && !s.viewer.capturing() && (key.String() == "backspace" || key.String() == "left") {
+			s.viewer = nil
+			return s, nil
+		}
+		_, cmd := s.viewer.Update(msg)
+		return s, cmd
+	}
+
+	key, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return s, nil
+	}
+	switch key.String() {
+	case "up", "k":
+		s.selected = max(0, s.selected-1)
+	case "down", "j":
+		s.selected = min(len(s.report.Findings)-1, s.selected+1)
+	case "enter":
+		if len(s.report.Findings) > 0 {
+			finding := s.report.Findings[s.selected]
+			s.viewer = newFileViewer(finding.Path, finding.Line)
+		}
+	case "e":
+		path, err := s.app.ExportSecurityReport(s.report)
+		if err != nil {
+			return s, toast.NewErrorToast(err.Error(), toast.WithTitle("SARIF export failed"))
+		}
+		return s, toast.NewSuccessToast("Saved to "+path, toast.WithTitle("SARIF exported"))
+	}
+	return s, nil
+}
+
+// severityColor returns the color findings of a severity are drawn in
+func severityColor(t theme.Theme, severity secreview.Severity) compat.AdaptiveColor {
+	switch severity {
+	case secreview.SeverityCritical, secreview.SeverityHigh:
+		return t.Error()
+	case secreview.SeverityMedium:
+		return t.Warning()
+	case secreview.SeverityLow:
+		return t.Info()
+	}
+	return t.TextMuted()
+}
+
+func (s *securityReviewDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+
+	if s.viewer != nil {
+		return s.modal.Render(s.viewer.View()+"\n\n"+help("backspace", "back to report"), background)
+	}
+
+	lines := []string{mutedStyle.Render(fmt.Sprintf("%s · %s", s.report.Scope, s.report.Model)), ""}
+	if len(s.report.Findings) == 0 {
+		lines = append(lines,
+			base.Foreground(t.Success()).Render("✓ No security issues found"),
+			"",
+			help("e", "export SARIF"),
+		)
+		return s.modal.Render(strings.Join(lines, "\n"), background)
+	}
+
+	counts := s.report.Counts()
+	var summary []string
+	for _, severity := range []secreview.Severity{
+		secreview.SeverityCritical,
+		secreview.SeverityHigh,
+		secreview.SeverityMedium,
+		secreview.SeverityLow,
+		secreview.SeverityInfo,
+	} {
+		if counts[severity] > 0 {
+			summary = append(summary, base.Foreground(severityColor(t, severity)).Render(fmt.Sprintf("%d %s", counts[severity], severity)))
+		}
+	}
+	lines = append(lines, strings.Join(summary, mutedStyle.Render(" · ")), "")
+
+	// Keep the selected finding in view when there are many
+	start := max(0, min(s.selected-maxFindingRows/2, len(s.report.Findings)-maxFindingRows))
+	end := min(len(s.report.Findings), start+maxFindingRows)
+	for i := start; i < end; i++ {
+		finding := s.report.Findings[i]
+		prefix := "  "
+		titleStyle := textStyle
+		if i == s.selected {
+			prefix = "› "
+			titleStyle = titleStyle.Bold(true)
+		}
+		severity := base.Foreground(severityColor(t, finding.Severity)).Bold(true).Render(fmt.Sprintf("%-8s", strings.ToUpper(string(finding.Severity))))
+		lines = append(lines, textStyle.Render(prefix)+severity+" "+titleStyle.Render(finding.Title)+mutedStyle.Render("  "+finding.Location()))
+	}
+	if end < len(s.report.Findings) {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  … %d more", len(s.report.Findings)-end)))
+	}
+
+	finding := s.report.Findings[s.selected]
+	width := max(40, layout.Current.Container.Width-12)
+	detailStyle := textStyle.Width(width)
+	lines = append(lines, "", keyStyle.Render(finding.Title))
+	location := finding.Location()
+	if finding.CWE != "" {
+		location += " · " + finding.CWE
+	}
+	lines = append(lines, mutedStyle.Render(location), "")
+	if finding.Description != "" {
+		lines = append(lines, detailStyle.Render(finding.Description), "")
+	}
+	if finding.Remediation != "" {
+		lines = append(lines, keyStyle.Render("Remediation"), detailStyle.Render(finding.Remediation), "")
+	}
+	lines = append(lines, help("↑/↓", "select", "enter", "open location", "e", "export SARIF"))
+	return s.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (s *securityReviewDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewSecurityReviewDialog creates a dialog for a security review report
+func NewSecurityReviewDialog(app *app.App, report *secreview.Report) SecurityReviewDialog {
+	return &securityReviewDialog{
+		app:    app,
+		report: report,
+		modal: modal.New(
+			modal.WithTitle("Security Review"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+}
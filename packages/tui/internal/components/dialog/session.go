@@ -30,6 +30,9 @@ type sessionItem struct {
 	title              string
 	isDeleteConfirming bool
 	isCurrentSession   bool
+	isPinned           bool
+	isRelated          bool // Linked with other sessions
+	isMarked           bool // Picked for the dataset export
 }
 
 func (s sessionItem) Render(
@@ -49,6 +52,15 @@ func (s sessionItem) Render(
 		} else {
 			text = s.title
 		}
+		if s.isPinned {
+			text = "★ " + text
+		}
+		if s.isMarked {
+			text = "✓ " + text
+		}
+		if s.isRelated {
+			text += " ↔"
+		}
 	}
 
 	truncatedStr := truncate.StringWithTail(text, uint(width-1), "...")
@@ -113,7 +125,8 @@ type sessionDialog struct {
 	deleteConfirmation int // -1 means no confirmation, >= 0 means confirming deletion of session at this index
 	renameMode         bool
 	renameInput        textinput.Model
-	renameIndex        int // index of session being renamed
+	renameIndex        int             // index of session being renamed
+	marked             map[string]bool // Sessions picked for the dataset export, by ID
 }
 
 func (s *sessionDialog) Init() tea.Cmd {
@@ -174,10 +187,49 @@ func (s *sessionDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						util.CmdHandler(app.SessionSelectedMsg(&selectedSession)),
 					)
 				}
+			case "p":
+				if _, idx := s.list.GetSelectedItem(); idx >= 0 && idx < len(s.sessions) {
+					id := s.sessions[idx].ID
+					_, cmd := s.app.TogglePinnedSession(id)
+					s.sessions = s.app.PinnedFirst(s.sessions)
+					s.updateListItems()
+					s.list.SetSelectedIndex(slices.IndexFunc(s.sessions, func(session opencode.Session) bool {
+						return session.ID == id
+					}))
+					return s, cmd
+				}
+			case "space":
+				if _, idx := s.list.GetSelectedItem(); idx >= 0 && idx < len(s.sessions) {
+					id := s.sessions[idx].ID
+					if s.marked[id] {
+						delete(s.marked, id)
+					} else {
+						s.marked[id] = true
+					}
+					s.updateListItems()
+					return s, nil
+				}
+			case "d":
+				// The sessions picked, or else the one selected
+				var ids []string
+				for _, session := range s.sessions {
+					if s.marked[session.ID] {
+						ids = append(ids, session.ID)
+					}
+				}
+				if _, idx := s.list.GetSelectedItem(); len(ids) == 0 && idx >= 0 && idx < len(s.sessions) {
+					ids = append(ids, s.sessions[idx].ID)
+				}
+				if len(ids) > 0 {
+					return s, tea.Sequence(
+						util.CmdHandler(modal.CloseModalMsg{}),
+						s.app.ExportDataset(strings.Join(ids, " ")),
+					)
+				}
 			case "n":
 				return s, tea.Sequence(
 					util.CmdHandler(modal.CloseModalMsg{}),
-					util.CmdHandler(app.SessionClearedMsg{}),
+					s.app.CheckClear(nil),
 				)
 			case "r":
 				if _, idx := s.list.GetSelectedItem(); idx >= 0 && idx < len(s.sessions) {
@@ -255,7 +307,8 @@ func (s *sessionDialog) Render(background string) string {
 		Render
 	mutedStyle := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundPanel()).Render
 
-	leftHelp := keyStyle("n") + mutedStyle(" new   ") + keyStyle("r") + mutedStyle(" rename")
+	leftHelp := keyStyle("n") + mutedStyle(" new   ") + keyStyle("r") + mutedStyle(" rename   ") + keyStyle("p") + mutedStyle(" pin   ") +
+		keyStyle("space") + mutedStyle(" pick   ") + keyStyle("d") + mutedStyle(" dataset")
 	rightHelp := keyStyle("x/del") + mutedStyle(" delete")
 
 	bgColor := t.BackgroundPanel()
@@ -269,10 +322,29 @@ func (s *sessionDialog) Render(background string) string {
 	helpText = styles.NewStyle().PaddingLeft(1).PaddingTop(1).Render(helpText)
 
 	content := strings.Join([]string{listView, helpText}, "\n")
+	if related := s.relatedTitles(); related != "" {
+		content = strings.Join([]string{listView, styles.NewStyle().PaddingLeft(1).Render(mutedStyle("↔ " + related)), helpText}, "\n")
+	}
 
 	return s.modal.Render(content, background)
 }
 
+// relatedTitles lists the titles of the sessions linked with the selected
+// one
+func (s *sessionDialog) relatedTitles() string {
+	_, idx := s.list.GetSelectedItem()
+	if idx < 0 || idx >= len(s.sessions) {
+		return ""
+	}
+	var titles []string
+	for _, id := range s.app.RelatedSessions(s.sessions[idx].ID) {
+		if i := slices.IndexFunc(s.sessions, func(session opencode.Session) bool { return session.ID == id }); i >= 0 {
+			titles = append(titles, s.sessions[i].Title)
+		}
+	}
+	return truncate.StringWithTail(strings.Join(titles, ", "), uint(max(10, layout.Current.Container.Width-16)), "...")
+}
+
 func (s *sessionDialog) setupRenameInput(currentTitle string) {
 	t := theme.CurrentTheme()
 	bgColor := t.BackgroundPanel()
@@ -315,6 +387,9 @@ func (s *sessionDialog) updateListItems() {
 			title:              sess.Title,
 			isDeleteConfirming: s.deleteConfirmation == i,
 			isCurrentSession:   s.app.Session != nil && s.app.Session.ID == sess.ID,
+			isPinned:           s.app.SessionPinned(sess.ID),
+			isRelated:          len(s.app.RelatedSessions(sess.ID)) > 0,
+			isMarked:           s.marked[sess.ID],
 		}
 		items = append(items, item)
 	}
@@ -362,10 +437,15 @@ func NewSessionDialog(app *app.App) SessionDialog {
 			continue
 		}
 		filteredSessions = append(filteredSessions, sess)
+	}
+	filteredSessions = app.PinnedFirst(filteredSessions)
+	for _, sess := range filteredSessions {
 		items = append(items, sessionItem{
 			title:              sess.Title,
 			isDeleteConfirming: false,
 			isCurrentSession:   app.Session != nil && app.Session.ID == sess.ID,
+			isPinned:           app.SessionPinned(sess.ID),
+			isRelated:          len(app.RelatedSessions(sess.ID)) > 0,
 		})
 	}
 
@@ -392,6 +472,7 @@ func NewSessionDialog(app *app.App) SessionDialog {
 		deleteConfirmation: -1,
 		renameMode:         false,
 		renameIndex:        -1,
+		marked:             make(map[string]bool),
 		modal: modal.New(
 			modal.WithTitle("Switch Session"),
 			modal.WithMaxWidth(layout.Current.Container.Width-8),
@@ -0,0 +1,221 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/list"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+const (
+	numVisibleSearchResults = 12
+	maxSessionSearchResults = 100
+)
+
+// SessionSearchDialog searches the titles and messages of every session and
+// jumps to the selected message
+type SessionSearchDialog interface {
+	layout.Modal
+}
+
+type sessionSearchDialog struct {
+	app          *app.App
+	modal        *modal.Modal
+	searchDialog *SearchDialog
+	indexing     bool
+}
+
+// sessionSearchItem is a search result with its matched characters
+// highlighted
+type sessionSearchItem struct {
+	result    app.SessionSearchResult
+	isCurrent bool
+}
+
+func (s sessionSearchItem) Render(selected bool, width int, baseStyle styles.Style) string {
+	t := theme.CurrentTheme()
+	base := baseStyle.Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	matchStyle := base.Foreground(t.Primary()).Bold(true)
+	if selected {
+		textStyle = textStyle.Foreground(t.Primary())
+	}
+
+	entry := s.result.Entry
+	title := entry.Session.Title
+	if title == "" {
+		title = "Untitled"
+	}
+	title = ansi.Truncate(title, max(10, width/3), "…")
+	role := ""
+	switch entry.Role {
+	case "user":
+		role = "you: "
+	case "assistant":
+		role = "ai: "
+	}
+
+	var prefix string
+	if entry.MessageID == "" {
+		prefix = mutedStyle.Render(" ") + highlight(s.result.Snippet, s.result.Matches, textStyle.Bold(selected), matchStyle)
+	} else {
+		prefix = textStyle.Bold(selected).Render(" "+title) + mutedStyle.Render("  "+role)
+		available := max(10, width-ansi.StringWidth(prefix)-14)
+		snippet, matches := fitSnippet(s.result.Snippet, s.result.Matches, available)
+		prefix += highlight(snippet, matches, mutedStyle, matchStyle)
+	}
+	if s.isCurrent {
+		prefix += mutedStyle.Render(" (current)")
+	}
+	date := mutedStyle.Render(entry.Updated.Format("Jan 2"))
+	gap := max(1, width-ansi.StringWidth(prefix)-ansi.StringWidth(date)-1)
+	return base.Width(width).Render(prefix + base.Render(strings.Repeat(" ", gap)) + date)
+}
+
+func (s sessionSearchItem) Selectable() bool {
+	return true
+}
+
+// highlight renders text with the runes at the given offsets emphasized
+func highlight(text string, matches []int, style, matchStyle styles.Style) string {
+	matched := make(map[int]bool, len(matches))
+	for _, i := range matches {
+		matched[i] = true
+	}
+	var b strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		j := i
+		for j < len(runes) && matched[j] == matched[i] {
+			j++
+		}
+		if matched[i] {
+			b.WriteString(matchStyle.Render(string(runes[i:j])))
+		} else {
+			b.WriteString(style.Render(string(runes[i:j])))
+		}
+		i = j
+	}
+	return b.String()
+}
+
+// fitSnippet shortens a snippet to width runes, keeping its first match in
+// view
+func fitSnippet(snippet string, matches []int, width int) (string, []int) {
+	runes := []rune(snippet)
+	if len(runes) <= width {
+		return snippet, matches
+	}
+	start := 0
+	if len(matches) > 0 {
+		start = max(0, min(matches[0]-width/4, len(runes)-width+1))
+	}
+	end := min(len(runes), start+width-1)
+	shifted := make([]int, 0, len(matches))
+	offset := 0
+	prefix := ""
+	if start > 0 {
+		prefix, offset = "…", 1
+	}
+	for _, i := range matches {
+		if i >= start && i < end {
+			shifted = append(shifted, i-start+offset)
+		}
+	}
+	return prefix + string(runes[start:end]) + "…", shifted
+}
+
+func (s *sessionSearchDialog) Init() tea.Cmd {
+	return tea.Batch(s.searchDialog.Init(), s.app.IndexSessions())
+}
+
+func (s *sessionSearchDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case app.SessionsIndexedMsg:
+		s.indexing = false
+		if msg.Err != nil {
+			return s, toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Session search"))
+		}
+		s.searchDialog.SetItems(s.buildResults(s.searchDialog.GetQuery()))
+		return s, nil
+	case SearchQueryChangedMsg:
+		s.searchDialog.SetItems(s.buildResults(msg.Query))
+		return s, nil
+	case SearchSelectionMsg:
+		item, ok := msg.Item.(sessionSearchItem)
+		if !ok {
+			return s, nil
+		}
+		entry := item.result.Entry
+		cmds := []tea.Cmd{util.CmdHandler(modal.CloseModalMsg{})}
+		if !item.isCurrent {
+			session := entry.Session
+			cmds = append(cmds, util.CmdHandler(app.SessionSelectedMsg(&session)))
+		}
+		if entry.MessageID != "" {
+			cmds = append(cmds, util.CmdHandler(ScrollToMessageMsg{MessageID: entry.MessageID}))
+		}
+		return s, tea.Sequence(cmds...)
+	case SearchCancelledMsg:
+		return s, util.CmdHandler(modal.CloseModalMsg{})
+	}
+
+	updated, cmd := s.searchDialog.Update(msg)
+	s.searchDialog = updated.(*SearchDialog)
+	return s, cmd
+}
+
+func (s *sessionSearchDialog) buildResults(query string) []list.Item {
+	results := s.app.SessionSearch().Search(query, maxSessionSearchResults)
+	items := make([]list.Item, 0, len(results))
+	for _, result := range results {
+		items = append(items, sessionSearchItem{
+			result:    result,
+			isCurrent: s.app.Session != nil && result.Entry.Session.ID == s.app.Session.ID,
+		})
+	}
+	return items
+}
+
+func (s *sessionSearchDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	mutedStyle := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundPanel())
+
+	status := fmt.Sprintf("%d sessions indexed", s.app.SessionSearch().Size())
+	if s.indexing {
+		status = "Indexing sessions…"
+	}
+	return s.modal.Render(s.searchDialog.View()+"\n"+mutedStyle.Render(status), background)
+}
+
+func (s *sessionSearchDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewSessionSearchDialog creates a dialog searching every session. The index
+// is refreshed when the dialog opens; sessions unchanged since the last
+// search are not read again.
+func NewSessionSearchDialog(app *app.App) SessionSearchDialog {
+	width := max(60, layout.Current.Container.Width-12)
+	searchDialog := NewSearchDialog("Search sessions and messages...", numVisibleSearchResults)
+	searchDialog.SetWidth(width)
+	return &sessionSearchDialog{
+		app:          app,
+		searchDialog: searchDialog,
+		indexing:     true,
+		modal: modal.New(
+			modal.WithTitle("Search Sessions"),
+			modal.WithMaxWidth(width+4),
+		),
+	}
+}
@@ -0,0 +1,204 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/textinput"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/shells"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// ShellsDialog shows the scrollback of the named shells, one at a time, and
+// runs commands in them
+type ShellsDialog interface {
+	layout.Modal
+}
+
+type shellsDialog struct {
+	app    *app.App
+	modal  *modal.Modal
+	name   string // The shell shown
+	input  textinput.Model
+	scroll int // Lines scrolled back from the bottom
+}
+
+func (s *shellsDialog) Init() tea.Cmd {
+	return s.input.Focus()
+}
+
+// shell returns the shell shown, falling back to the first one when it was
+// closed
+func (s *shellsDialog) shell() *shells.Shell {
+	if shell := s.app.Shell(s.name); shell != nil {
+		return shell
+	}
+	list := s.app.Shells()
+	if len(list) == 0 {
+		return nil
+	}
+	s.name = list[0].Name
+	s.scroll = 0
+	return list[0]
+}
+
+func (s *shellsDialog) height() int {
+	return max(8, layout.Current.Viewport.Height-14)
+}
+
+func (s *shellsDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return s, nil
+	}
+	shell := s.shell()
+	switch keyMsg.String() {
+	case "enter":
+		command := strings.TrimSpace(s.input.Value())
+		if shell == nil || command == "" {
+			return s, nil
+		}
+		s.input.SetValue("")
+		s.scroll = 0
+		return s, s.app.RunInShell(shell.Name, command)
+	case "tab", "shift+tab":
+		list := s.app.Shells()
+		if shell == nil || len(list) < 2 {
+			return s, nil
+		}
+		for i, other := range list {
+			if other == shell {
+				step := 1
+				if keyMsg.String() == "shift+tab" {
+					step = len(list) - 1
+				}
+				s.name = list[(i+step)%len(list)].Name
+				s.scroll = 0
+				break
+			}
+		}
+		return s, nil
+	case "ctrl+r":
+		if shell == nil {
+			return s, nil
+		}
+		s.scroll = 0
+		return s, s.app.RestartShell(shell.Name)
+	case "ctrl+w":
+		if shell == nil {
+			return s, nil
+		}
+		cmd := s.app.CloseShell(shell.Name)
+		if len(s.app.Shells()) == 0 {
+			return s, tea.Batch(cmd, util.CmdHandler(modal.CloseModalMsg{}))
+		}
+		return s, cmd
+	case "pgup":
+		if shell != nil {
+			s.scroll = min(s.scroll+s.height()/2, max(0, len(shell.Lines(0))-s.height()))
+		}
+		return s, nil
+	case "pgdown":
+		s.scroll = max(0, s.scroll-s.height()/2)
+		return s, nil
+	}
+	var cmd tea.Cmd
+	s.input, cmd = s.input.Update(msg)
+	return s, cmd
+}
+
+func (s *shellsDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+
+	shell := s.shell()
+	if shell == nil {
+		return s.modal.Render(mutedStyle.Render("No shells. Start one with /shell <name> [command]."), background)
+	}
+
+	var tabs []string
+	for _, other := range s.app.Shells() {
+		label := " " + other.Name + " "
+		if !other.Running() {
+			label = " " + other.Name + " (exited) "
+		}
+		if other == shell {
+			tabs = append(tabs, base.Foreground(t.Background()).Background(t.Primary()).Bold(true).Render(label))
+		} else {
+			tabs = append(tabs, mutedStyle.Render(label))
+		}
+	}
+	dir := shell.Dir
+	if dir == "" {
+		dir = "project root"
+	}
+	lines := []string{
+		strings.Join(tabs, mutedStyle.Render(" ")),
+		mutedStyle.Render(fmt.Sprintf("in %s, since %s", dir, shell.Started.Format("15:04"))),
+		"",
+	}
+
+	height := s.height()
+	width := max(20, layout.Current.Container.Width-16)
+	scrollback := shell.Lines(0)
+	end := max(0, len(scrollback)-s.scroll)
+	start := max(0, end-height)
+	shown := scrollback[start:end]
+	for range height - len(shown) {
+		lines = append(lines, "")
+	}
+	for _, line := range shown {
+		lines = append(lines, textStyle.Render(ansi.Truncate(line, width, "…")))
+	}
+	if s.scroll > 0 {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("… %d more lines below", len(scrollback)-end)))
+	} else {
+		lines = append(lines, "")
+	}
+
+	lines = append(lines,
+		mutedStyle.Render("$")+s.input.View(),
+		"",
+		help("enter", "run", "tab", "next shell", "pgup/pgdn", "scroll", "ctrl+r", "restart", "ctrl+w", "close shell"),
+	)
+	return s.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (s *shellsDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewShellsDialog creates a dialog showing the named shell, or the first one
+func NewShellsDialog(app *app.App, name string) ShellsDialog {
+	input := textinput.New()
+	input.Placeholder = "command"
+	input.Prompt = " "
+	input.CharLimit = -1
+
+	return &shellsDialog{
+		app:   app,
+		name:  name,
+		input: input,
+		modal: modal.New(
+			modal.WithTitle("Shells"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+}
@@ -1,122 +1,43 @@
 package dialog
 
 import (
+	"fmt"
+	"slices"
 	"strings"
+	"unicode/utf8"
 
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/aaronmrosenthal/rycode/internal/accessibility"
 	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/commands"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
 	"github.com/aaronmrosenthal/rycode/internal/layout"
 	"github.com/aaronmrosenthal/rycode/internal/styles"
 	"github.com/aaronmrosenthal/rycode/internal/theme"
-	tea "github.com/charmbracelet/bubbletea/v2"
-	"github.com/charmbracelet/lipgloss/v2"
 )
 
-// ShortcutCategory represents a group of related shortcuts
-type ShortcutCategory struct {
-	Name      string
-	Icon      string
-	Shortcuts []Shortcut
-}
-
-// Shortcut represents a single keyboard shortcut
-type Shortcut struct {
-	Keys        string
-	Description string
-	IsImportant bool // Highlight as essential shortcut
-}
-
-// ShortcutsDialog displays comprehensive keyboard shortcuts
+// ShortcutsDialog is a full-screen, searchable cheat sheet of the
+// commands, their keybindings and their slash triggers, grouped by
+// category. It is built from the live command registry.
 type ShortcutsDialog interface {
 	layout.Modal
 }
 
 type shortcutsDialog struct {
-	app        *app.App
-	categories []ShortcutCategory
-	width      int
-	height     int
+	app    *app.App
+	modal  *modal.Modal
+	query  string
+	offset int // First line shown
 }
 
-// NewShortcutsDialog creates a new keyboard shortcuts reference
-func NewShortcutsDialog(app *app.App) ShortcutsDialog {
-	dialog := &shortcutsDialog{
-		app: app,
-	}
-
-	dialog.setupCategories()
-
-	return dialog
-}
-
-// setupCategories defines all keyboard shortcuts by category
-func (s *shortcutsDialog) setupCategories() {
-	s.categories = []ShortcutCategory{
-		{
-			Name: "Essential",
-			Icon: "⭐",
-			Shortcuts: []Shortcut{
-				{"Tab", "Cycle through available models", true},
-				{"Ctrl+M", "Open model selector", true},
-				{"Ctrl+?", "Show this shortcuts guide", true},
-				{"Ctrl+C", "Exit RyCode", true},
-				{"ESC", "Close current dialog", true},
-			},
-		},
-		{
-			Name: "Navigation",
-			Icon: "🧭",
-			Shortcuts: []Shortcut{
-				{"↑/↓ or j/k", "Navigate lists", false},
-				{"←/→ or h/l", "Navigate steps", false},
-				{"Enter", "Select/Confirm", false},
-				{"/", "Search/Filter", false},
-				{"Home/End", "Jump to first/last", false},
-			},
-		},
-		{
-			Name: "Models & Providers",
-			Icon: "🤖",
-			Shortcuts: []Shortcut{
-				{"Tab", "Quick model switch", true},
-				{"Ctrl+M", "Model selector dialog", false},
-				{"Ctrl+P", "Provider management", false},
-				{"a", "Authenticate provider (in provider list)", false},
-				{"r", "Refresh provider status", false},
-				{"d", "Auto-detect credentials", false},
-			},
-		},
-		{
-			Name: "Analytics & Insights",
-			Icon: "📊",
-			Shortcuts: []Shortcut{
-				{"Ctrl+I", "Usage insights dashboard", true},
-				{"Ctrl+B", "Budget forecast", true},
-				{"Ctrl+$", "Cost summary", false},
-				{"i", "Toggle AI recommendations (in model selector)", false},
-			},
-		},
-		{
-			Name: "Editing",
-			Icon: "✏️",
-			Shortcuts: []Shortcut{
-				{"Ctrl+A", "Select all", false},
-				{"Ctrl+X", "Cut", false},
-				{"Ctrl+V", "Paste", false},
-				{"Ctrl+Z", "Undo", false},
-				{"Ctrl+Y", "Redo", false},
-			},
-		},
-		{
-			Name: "Advanced",
-			Icon: "🔧",
-			Shortcuts: []Shortcut{
-				{"Ctrl+R", "Refresh all data", false},
-				{"Ctrl+T", "Toggle theme", false},
-				{"Ctrl+L", "Clear screen", false},
-				{"Ctrl+D", "Developer tools", false},
-			},
-		},
-	}
+func init() {
+	accessibility.RegisterMouseAction(accessibility.MouseAction{
+		Component: "shortcuts",
+		Name:      "Scroll the shortcuts",
+		Mouse:     "mouse wheel",
+		Keys:      "↑/↓, page up/down",
+	})
 }
 
 func (s *shortcutsDialog) Init() tea.Cmd {
@@ -125,165 +46,168 @@ func (s *shortcutsDialog) Init() tea.Cmd {
 
 func (s *shortcutsDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		s.width = msg.Width
-		s.height = msg.Height
+	case tea.MouseWheelMsg:
+		switch msg.Button {
+		case tea.MouseWheelUp:
+			s.offset -= 3
+		case tea.MouseWheelDown:
+			s.offset += 3
+		}
+	case tea.KeyPressMsg:
+		page := s.height()
+		switch msg.String() {
+		case "up":
+			s.offset--
+		case "down":
+			s.offset++
+		case "pgup":
+			s.offset -= page
+		case "pgdown":
+			s.offset += page
+		case "home":
+			s.offset = 0
+		case "end":
+			s.offset = len(s.lines())
+		case "backspace":
+			if s.query != "" {
+				_, size := utf8.DecodeLastRuneInString(s.query)
+				s.query = s.query[:len(s.query)-size]
+				s.offset = 0
+			}
+		case "ctrl+u":
+			s.query, s.offset = "", 0
+		default:
+			if msg.Text != "" {
+				s.query += msg.Text
+				s.offset = 0
+			}
+		}
 	}
-
 	return s, nil
 }
 
-func (s *shortcutsDialog) View() string {
-	t := theme.CurrentTheme()
-
-	var sections []string
-
-	// Header
-	header := styles.NewStyle().
-		Foreground(t.Primary()).
-		Bold(true).
-		Render("⌨️  Keyboard Shortcuts")
-
-	sections = append(sections, header)
-	sections = append(sections, "")
-
-	// Subtitle
-	subtitle := styles.NewStyle().
-		Foreground(t.TextMuted()).
-		Faint(true).
-		Render("Master RyCode with these keyboard shortcuts")
-
-	sections = append(sections, subtitle)
-	sections = append(sections, "")
+// height is the number of cheat sheet lines that fit on the screen
+func (s *shortcutsDialog) height() int {
+	// Border, padding, title, search and footer
+	return max(5, layout.Current.Viewport.Height-10)
+}
 
-	// Render categories in two columns
-	leftColumn := []ShortcutCategory{}
-	rightColumn := []ShortcutCategory{}
+// width is the width of the cheat sheet inside the modal
+func (s *shortcutsDialog) width() int {
+	return layout.Current.Container.Width - 12
+}
 
-	for i, cat := range s.categories {
-		if i%2 == 0 {
-			leftColumn = append(leftColumn, cat)
+// keys writes the keybindings of a command as they are pressed
+func (s *shortcutsDialog) keys(command commands.Command) string {
+	var keys []string
+	for _, binding := range command.Keybindings {
+		if binding.RequiresLeader {
+			keys = append(keys, s.app.Config.Keybinds.Leader+" "+binding.Key)
 		} else {
-			rightColumn = append(rightColumn, cat)
+			keys = append(keys, binding.Key)
 		}
 	}
-
-	// Render left column
-	leftContent := s.renderColumn(leftColumn)
-	rightContent := s.renderColumn(rightColumn)
-
-	// Combine columns
-	combined := lipgloss.JoinHorizontal(
-		lipgloss.Top,
-		leftContent,
-		strings.Repeat(" ", 4),
-		rightContent,
-	)
-
-	sections = append(sections, combined)
-	sections = append(sections, "")
-
-	// Footer tip
-	tipStyle := styles.NewStyle().
-		Foreground(t.Info()).
-		Italic(true)
-
-	tip := tipStyle.Render("💡 Tip: Essential shortcuts are marked with ⭐")
-	sections = append(sections, tip)
-	sections = append(sections, "")
-
-	// Help text
-	helpStyle := styles.NewStyle().
-		Foreground(t.TextMuted()).
-		Faint(true)
-
-	help := helpStyle.Render("Press ESC to close this guide")
-	sections = append(sections, help)
-
-	content := strings.Join(sections, "\n")
-
-	// Wrap in bordered box
-	boxStyle := styles.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(t.Border()).
-		Padding(2, 3).
-		Width(s.width - 4)
-
-	return boxStyle.Render(content)
+	return strings.Join(keys, ", ")
 }
 
-// renderColumn renders a column of shortcut categories
-func (s *shortcutsDialog) renderColumn(categories []ShortcutCategory) string {
+// lines renders the commands that match the query, grouped by category
+func (s *shortcutsDialog) lines() []string {
 	t := theme.CurrentTheme()
-
-	var sections []string
-
-	for _, category := range categories {
-		// Category header
-		categoryHeader := styles.NewStyle().
-			Foreground(t.Primary()).
-			Bold(true).
-			Render(category.Icon + " " + category.Name)
-
-		sections = append(sections, categoryHeader)
-		sections = append(sections, "")
-
-		// Shortcuts
-		for _, shortcut := range category.Shortcuts {
-			line := s.renderShortcut(shortcut)
-			sections = append(sections, line)
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Primary()).Bold(true)
+	headingStyle := base.Foreground(t.Accent()).Bold(true)
+
+	groups := map[string][]commands.Command{}
+	keysWidth, triggerWidth := 0, 0
+	for _, command := range s.app.Commands.Sorted() {
+		if !command.MatchesQuery(s.query) {
+			continue
+		}
+		category := commands.Category(command)
+		groups[category] = append(groups[category], command)
+		keysWidth = max(keysWidth, lipgloss.Width(s.keys(command)))
+		if command.HasTrigger() {
+			triggerWidth = max(triggerWidth, lipgloss.Width("/"+command.PrimaryTrigger()))
 		}
-
-		sections = append(sections, "")
 	}
-
-	return strings.Join(sections, "\n")
+	keysWidth = min(keysWidth, 28)
+	triggerWidth = min(triggerWidth, 22)
+	descriptionWidth := max(10, s.width()-keysWidth-triggerWidth-6)
+
+	var lines []string
+	for _, category := range commands.Categories {
+		group := groups[category]
+		if len(group) == 0 {
+			continue
+		}
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, headingStyle.Render(category))
+		for _, command := range group {
+			trigger := ""
+			if command.HasTrigger() {
+				trigger = "/" + command.PrimaryTrigger()
+			}
+			lines = append(lines, "  "+
+				keyStyle.Width(keysWidth).MaxWidth(keysWidth).Render(s.keys(command))+"  "+
+				mutedStyle.Width(triggerWidth).MaxWidth(triggerWidth).Render(trigger)+"  "+
+				textStyle.MaxWidth(descriptionWidth).Render(command.Description))
+		}
+	}
+	return lines
 }
 
-// renderShortcut renders a single shortcut line
-func (s *shortcutsDialog) renderShortcut(shortcut Shortcut) string {
+func (s *shortcutsDialog) Render(background string) string {
 	t := theme.CurrentTheme()
-
-	// Key style
-	keyStyle := styles.NewStyle().
-		Foreground(t.Background()).
-		Background(t.Primary()).
-		Bold(true).
-		Padding(0, 1)
-
-	if !shortcut.IsImportant {
-		// Non-essential shortcuts get muted background
-		keyStyle = keyStyle.
-			Background(t.TextMuted())
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+
+	search := mutedStyle.Render("type to search")
+	if s.query != "" {
+		search = textStyle.Render(s.query) + base.Foreground(t.Primary()).Render("▏")
 	}
-
-	key := keyStyle.Render(shortcut.Keys)
-
-	// Description style
-	descStyle := styles.NewStyle().
-		Foreground(t.Text())
-
-	if !shortcut.IsImportant {
-		descStyle = descStyle.Foreground(t.TextMuted())
+	search = keyStyle.Render("search ") + search
+
+	lines := s.lines()
+	height := s.height()
+	s.offset = max(0, min(s.offset, len(lines)-height))
+	visible := lines[s.offset:min(len(lines), s.offset+height)]
+	if len(lines) == 0 {
+		visible = []string{mutedStyle.Render(fmt.Sprintf("No commands match %q", s.query))}
 	}
-
-	desc := descStyle.Render(shortcut.Description)
-
-	// Star for important shortcuts
-	star := ""
-	if shortcut.IsImportant {
-		star = styles.NewStyle().
-			Foreground(t.Warning()).
-			Render(" ⭐")
+	visible = slices.Clone(visible)
+	for len(visible) < height {
+		visible = append(visible, "")
 	}
 
-	return key + "  " + desc + star
-}
+	position := ""
+	if len(lines) > height {
+		position = mutedStyle.Render(fmt.Sprintf("%d-%d of %d   ", s.offset+1, min(len(lines), s.offset+height), len(lines)))
+	}
+	footer := position +
+		keyStyle.Render("↑/↓ pgup/pgdn") + mutedStyle.Render(" scroll   ") +
+		keyStyle.Render("ctrl+u") + mutedStyle.Render(" clear search")
 
-func (s *shortcutsDialog) Render(background string) string {
-	return s.View()
+	content := strings.Join([]string{search, "", strings.Join(visible, "\n"), "", footer}, "\n")
+	return s.modal.Render(base.Width(s.width()).Render(content), background)
 }
 
 func (s *shortcutsDialog) Close() tea.Cmd {
 	return nil
 }
+
+// NewShortcutsDialog creates the keyboard shortcuts cheat sheet
+func NewShortcutsDialog(app *app.App) ShortcutsDialog {
+	return &shortcutsDialog{
+		app: app,
+		modal: modal.New(
+			modal.WithTitle("Keyboard shortcuts"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+}
@@ -1,7 +1,6 @@
 package dialog
 
 import (
-	"context"
 	"fmt"
 	"log/slog"
 	"sort"
@@ -83,7 +82,7 @@ func (s *SimpleProviderToggle) loadProvidersAsync() tea.Cmd {
 
 func (s *SimpleProviderToggle) loadAuthenticatedProvidersSync() ([]opencode.Provider, error) {
 	// Use timeout context to prevent hanging
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := s.app.TimeoutContext(app.TimeoutProvider)
 	defer cancel()
 
 	slog.Debug("loading authenticated providers")
@@ -102,10 +101,10 @@ func (s *SimpleProviderToggle) loadAuthenticatedProvidersSync() ([]opencode.Prov
 
 	// Check authentication in parallel for faster loading (reduces from ~4-5s to ~1s)
 	type authCheckResult struct {
-		providerID  string
-		models      []string
-		authStatus  *auth.AuthStatus
-		err         error
+		providerID string
+		models     []string
+		authStatus *auth.AuthStatus
+		err        error
 	}
 
 	resultChan := make(chan authCheckResult, len(cliProviders))
@@ -167,13 +166,16 @@ func (s *SimpleProviderToggle) loadAuthenticatedProvidersSync() ([]opencode.Prov
 		slog.Debug("final provider", "index", i, "id", p.ID, "name", p.Name, "models_count", len(p.Models))
 	}
 
-	// Sort providers by priority: Claude, Codex, Gemini, Grok, Qwen
+	// Sort providers by priority: Claude, Codex, Gemini, Grok, Qwen, then
+	// the cloud platforms
 	providerOrder := map[string]int{
-		"claude":  1,
-		"codex":   2,
-		"gemini":  3,
-		"grok":    4,
-		"qwen":    5,
+		"claude":         1,
+		"codex":          2,
+		"gemini":         3,
+		"grok":           4,
+		"qwen":           5,
+		"azure":          6,
+		"amazon-bedrock": 7,
 	}
 	sort.Slice(providers, func(i, j int) bool {
 		orderI, okI := providerOrder[providers[i].ID]
@@ -208,15 +210,17 @@ func formatModelName(modelID string) string {
 // formatProviderName formats a provider ID into a human-readable company name
 func formatProviderName(providerID string) string {
 	names := map[string]string{
-		"anthropic": "Anthropic",
-		"claude":    "Anthropic",
-		"openai":    "OpenAI",
-		"codex":     "OpenAI",
-		"google":    "Google",
-		"gemini":    "Google",
-		"xai":       "xAI",
-		"grok":      "xAI",
-		"qwen":      "Alibaba",
+		"anthropic":      "Anthropic",
+		"claude":         "Anthropic",
+		"openai":         "OpenAI",
+		"codex":          "OpenAI",
+		"google":         "Google",
+		"gemini":         "Google",
+		"xai":            "xAI",
+		"grok":           "xAI",
+		"qwen":           "Alibaba",
+		"azure":          "Microsoft",
+		"amazon-bedrock": "Amazon",
 	}
 	if name, ok := names[providerID]; ok {
 		return name
@@ -227,15 +231,17 @@ func formatProviderName(providerID string) string {
 // getProviderDisplayName returns the short display name for chips/UI
 func getProviderDisplayName(providerID string) string {
 	names := map[string]string{
-		"anthropic": "Claude",
-		"claude":    "Claude",
-		"google":    "Gemini",
-		"gemini":    "Gemini",
-		"openai":    "Codex",
-		"codex":     "Codex",
-		"xai":       "Grok",
-		"grok":      "Grok",
-		"qwen":      "Qwen",
+		"anthropic":      "Claude",
+		"claude":         "Claude",
+		"google":         "Gemini",
+		"gemini":         "Gemini",
+		"openai":         "Codex",
+		"codex":          "Codex",
+		"xai":            "Grok",
+		"grok":           "Grok",
+		"qwen":           "Qwen",
+		"azure":          "Azure",
+		"amazon-bedrock": "Bedrock",
 	}
 	if name, ok := names[providerID]; ok {
 		return name
@@ -377,6 +383,18 @@ func (s *SimpleProviderToggle) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			slog.Debug("modal closing via esc key")
 			return s, util.CmdHandler(modal.CloseModalMsg{})
 
+		case key.Matches(msg, key.NewBinding(key.WithKeys("a"))):
+			// A: pair the next agent with the selected provider, or none
+			if len(s.providers) > 0 && s.selectedIndex < len(s.providers) {
+				providerID := s.providers[s.selectedIndex].ID
+				cmd, err := s.app.SetProviderAgent(providerID, s.app.NextProviderAgent(providerID))
+				if err != nil {
+					slog.Error("failed to pair agent with provider", "provider", providerID, "error", err)
+				}
+				return s, cmd
+			}
+			return s, nil
+
 		case key.Matches(msg, key.NewBinding(key.WithKeys("r"))):
 			// R: retry loading if there was an error
 			if s.loadError != nil {
@@ -467,7 +485,7 @@ func (s *SimpleProviderToggle) View() string {
 	footerStyle := lipgloss.NewStyle().
 		Foreground(t.TextMuted()).
 		Padding(1, 2)
-	footer := "1-5: Quick Select | Tab: Next | Shift+Tab: Previous | Enter: Select | Esc: Cancel"
+	footer := "1-5: Quick Select | Tab: Next | Shift+Tab: Previous | A: Pair Agent | Enter: Select | Esc: Cancel"
 	b.WriteString(footerStyle.Render(footer))
 
 	return b.String()
@@ -642,8 +660,11 @@ func (s *SimpleProviderToggle) renderProviderChip(provider opencode.Provider, is
 			Foreground(color)
 	}
 
-	// Show: [number] Name (modelCount)
+	// Show: [number] Name (modelCount) · paired agent
 	displayName := fmt.Sprintf("[%d] %s (%d)", number, getProviderDisplayName(provider.ID), len(provider.Models))
+	if agent := s.app.ProviderAgent(provider.ID); agent != "" {
+		displayName += " · " + agent
+	}
 	return chipStyle.Render(displayName)
 }
 
@@ -665,6 +686,12 @@ func (s *SimpleProviderToggle) getProviderColor(providerID string) compat.Adapti
 	case "qwen":
 		// Qwen brand: golden orange (from badge) #FFA726
 		return compat.AdaptiveColor{Light: lipgloss.Color("#FFA726"), Dark: lipgloss.Color("#FFA726")}
+	case "azure":
+		// Azure brand: blue #0078D4
+		return compat.AdaptiveColor{Light: lipgloss.Color("#0078D4"), Dark: lipgloss.Color("#0078D4")}
+	case "amazon-bedrock":
+		// AWS brand: orange #FF9900
+		return compat.AdaptiveColor{Light: lipgloss.Color("#FF9900"), Dark: lipgloss.Color("#FF9900")}
 	default:
 		return theme.CurrentTheme().Primary()
 	}
@@ -675,53 +702,61 @@ func (s *SimpleProviderToggle) getDefaultModelForProvider(provider opencode.Prov
 	// Priority order for each provider's models (latest SOTA models as of 2025)
 	priorities := map[string][]string{
 		"claude": {
-			"claude-sonnet-4-5",              // Latest Sonnet 4.5 (main coding model)
-			"claude-opus-4-1",                // Opus 4.1 (complex reasoning)
-			"claude-sonnet-4",                // Sonnet 4
-			"claude-3-7-sonnet",              // Sonnet 3.7
-			"claude-3-5-sonnet-20241022",     // Sonnet 3.5 (dated version)
-			"claude-3-5-haiku-20241022",      // Haiku 3.5 (fast/lightweight)
+			"claude-sonnet-4-5",          // Latest Sonnet 4.5 (main coding model)
+			"claude-opus-4-1",            // Opus 4.1 (complex reasoning)
+			"claude-sonnet-4",            // Sonnet 4
+			"claude-3-7-sonnet",          // Sonnet 3.7
+			"claude-3-5-sonnet-20241022", // Sonnet 3.5 (dated version)
+			"claude-3-5-haiku-20241022",  // Haiku 3.5 (fast/lightweight)
 		},
 		"codex": {
-			"gpt-5",                          // GPT-5 base model
-			"o3",                             // O3 reasoning model
-			"gpt-5-mini",                     // GPT-5 mini
-			"o3-mini",                        // O3 mini
-			"gpt-5-nano",                     // GPT-5 nano
-			"gpt-4-5",                        // GPT-4.5
-			"gpt-4o",                         // GPT-4o
-			"gpt-4o-mini",                    // GPT-4o mini
+			"gpt-5",       // GPT-5 base model
+			"o3",          // O3 reasoning model
+			"gpt-5-mini",  // GPT-5 mini
+			"o3-mini",     // O3 mini
+			"gpt-5-nano",  // GPT-5 nano
+			"gpt-4-5",     // GPT-4.5
+			"gpt-4o",      // GPT-4o
+			"gpt-4o-mini", // GPT-4o mini
 		},
 		"gemini": {
-			"gemini-2.5-pro",                 // Gemini Pro 2.5 (latest)
-			"gemini-2.5-flash",               // Gemini 2.5 Flash
-			"gemini-2.5-flash-lite",          // Gemini 2.5 Flash Lite
-			"gemini-2.5-flash-image",         // Gemini 2.5 Flash Image
-			"gemini-2.5-computer-use",        // Gemini 2.5 Computer Use
-			"gemini-2.5-deep-think",          // Gemini 2.5 Deep Think
-			"gemini-exp-1206",                // Experimental release
-			"gemini-2.0-flash-exp",           // Gemini 2.0 Flash (legacy)
+			"gemini-2.5-pro",          // Gemini Pro 2.5 (latest)
+			"gemini-2.5-flash",        // Gemini 2.5 Flash
+			"gemini-2.5-flash-lite",   // Gemini 2.5 Flash Lite
+			"gemini-2.5-flash-image",  // Gemini 2.5 Flash Image
+			"gemini-2.5-computer-use", // Gemini 2.5 Computer Use
+			"gemini-2.5-deep-think",   // Gemini 2.5 Deep Think
+			"gemini-exp-1206",         // Experimental release
+			"gemini-2.0-flash-exp",    // Gemini 2.0 Flash (legacy)
 		},
 		"grok": {
-			"grok-beta",                      // Grok beta (latest)
-			"grok-2-1212",                    // Grok 2 dated release
+			"grok-beta",   // Grok beta (latest)
+			"grok-2-1212", // Grok 2 dated release
 		},
 		"qwen": {
-			"qwen3-max",                      // Qwen 3 Max (best general purpose)
-			"qwen3-thinking-2507",            // Qwen 3 Thinking (reasoning model)
-			"qwen3-next",                     // Qwen 3 Next
-			"qwen3-omni",                     // Qwen 3 Omni (multimodal)
-			"qwen3-instruct-2507",            // Qwen 3 Instruct
-			"qwen3-235b",                     // Qwen 3 235B
-			"qwen3-32b",                      // Qwen 3 32B
+			"qwen3-max",           // Qwen 3 Max (best general purpose)
+			"qwen3-thinking-2507", // Qwen 3 Thinking (reasoning model)
+			"qwen3-next",          // Qwen 3 Next
+			"qwen3-omni",          // Qwen 3 Omni (multimodal)
+			"qwen3-instruct-2507", // Qwen 3 Instruct
+			"qwen3-235b",          // Qwen 3 235B
+			"qwen3-32b",           // Qwen 3 32B
+		},
+		"amazon-bedrock": {
+			"anthropic.claude-sonnet-4-5-20250929-v1:0", // Claude Sonnet 4.5
+			"anthropic.claude-opus-4-1-20250805-v1:0",   // Claude Opus 4.1
+			"anthropic.claude-sonnet-4-20250514-v1:0",   // Claude Sonnet 4
+			"amazon.nova-pro-v1:0",                      // Nova Pro
 		},
 	}
 
 	// Check if we have priorities for this provider
 	if prefs, ok := priorities[provider.ID]; ok {
 		for _, modelID := range prefs {
-			if model, exists := provider.Models[modelID]; exists {
-				return model
+			for id, model := range provider.Models {
+				if baseModelID(id) == modelID {
+					return model
+				}
 			}
 		}
 	}
@@ -761,11 +796,12 @@ func (s *SimpleProviderToggle) renderProviderModels(provider opencode.Provider,
 
 	// Sort models using the same priority system as getDefaultModelForProvider (latest SOTA models as of 2025)
 	priorities := map[string][]string{
-		"claude":  {"claude-sonnet-4-5", "claude-opus-4-1", "claude-sonnet-4", "claude-3-7-sonnet", "claude-3-5-sonnet-20241022", "claude-3-5-haiku-20241022"},
-		"codex":   {"gpt-5", "o3", "gpt-5-mini", "o3-mini", "gpt-5-nano", "gpt-4-5", "gpt-4o", "gpt-4o-mini"},
-		"gemini":  {"gemini-2.5-pro", "gemini-2.5-flash", "gemini-2.5-flash-lite", "gemini-2.5-flash-image", "gemini-2.5-computer-use", "gemini-2.5-deep-think", "gemini-exp-1206", "gemini-2.0-flash-exp"},
-		"grok":    {"grok-beta", "grok-2-1212"},
-		"qwen":    {"qwen3-max", "qwen3-thinking-2507", "qwen3-next", "qwen3-omni", "qwen3-instruct-2507", "qwen3-235b", "qwen3-32b"},
+		"claude":         {"claude-sonnet-4-5", "claude-opus-4-1", "claude-sonnet-4", "claude-3-7-sonnet", "claude-3-5-sonnet-20241022", "claude-3-5-haiku-20241022"},
+		"codex":          {"gpt-5", "o3", "gpt-5-mini", "o3-mini", "gpt-5-nano", "gpt-4-5", "gpt-4o", "gpt-4o-mini"},
+		"gemini":         {"gemini-2.5-pro", "gemini-2.5-flash", "gemini-2.5-flash-lite", "gemini-2.5-flash-image", "gemini-2.5-computer-use", "gemini-2.5-deep-think", "gemini-exp-1206", "gemini-2.0-flash-exp"},
+		"grok":           {"grok-beta", "grok-2-1212"},
+		"qwen":           {"qwen3-max", "qwen3-thinking-2507", "qwen3-next", "qwen3-omni", "qwen3-instruct-2507", "qwen3-235b", "qwen3-32b"},
+		"amazon-bedrock": {"anthropic.claude-sonnet-4-5-20250929-v1:0", "anthropic.claude-opus-4-1-20250805-v1:0", "anthropic.claude-sonnet-4-20250514-v1:0", "amazon.nova-pro-v1:0"},
 	}
 
 	priorityList := priorities[provider.ID]
@@ -773,10 +809,10 @@ func (s *SimpleProviderToggle) renderProviderModels(provider opencode.Provider,
 		// Find positions in priority list
 		posI, posJ := -1, -1
 		for idx, pID := range priorityList {
-			if pID == modelIDs[i] {
+			if pID == baseModelID(modelIDs[i]) {
 				posI = idx
 			}
-			if pID == modelIDs[j] {
+			if pID == baseModelID(modelIDs[j]) {
 				posJ = idx
 			}
 		}
@@ -842,6 +878,9 @@ func (s *SimpleProviderToggle) CalculateRequiredWidth() int {
 		// Example: "[1] Claude (6)" = 14 chars
 		displayName := getProviderDisplayName(provider.ID)
 		chipText := fmt.Sprintf("[%d] %s (%d)", i+1, displayName, len(provider.Models))
+		if agent := s.app.ProviderAgent(provider.ID); agent != "" {
+			chipText += " · " + agent
+		}
 
 		// Add border (2 chars) + padding (4 chars) = 6 extra chars
 		chipWidth := len(chipText) + 6
@@ -870,3 +909,14 @@ func (s *SimpleProviderToggle) CalculateRequiredWidth() int {
 
 	return totalWidth
 }
+
+// baseModelID strips the geography of a Bedrock cross-region inference
+// profile, such as "us.", from a model ID
+func baseModelID(id string) string {
+	for _, geography := range []string{"us.", "eu.", "apac."} {
+		if rest, ok := strings.CutPrefix(id, geography); ok {
+			return rest
+		}
+	}
+	return id
+}
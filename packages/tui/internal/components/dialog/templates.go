@@ -0,0 +1,137 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/sessiontemplate"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// maxTemplateRows is the number of templates listed at once
+const maxTemplateRows = 8
+
+// TemplatesDialog lists the session templates to start a new session from
+type TemplatesDialog interface {
+	layout.Modal
+}
+
+type templatesDialog struct {
+	app       *app.App
+	modal     *modal.Modal
+	templates []sessiontemplate.Template
+	selected  int
+}
+
+func (d *templatesDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *templatesDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyPressMsg)
+	if !ok || len(d.templates) == 0 {
+		return d, nil
+	}
+	switch key.String() {
+	case "up", "k":
+		d.selected = max(0, d.selected-1)
+	case "down", "j":
+		d.selected = min(len(d.templates)-1, d.selected+1)
+	case "enter":
+		return d, tea.Sequence(
+			util.CmdHandler(modal.CloseModalMsg{}),
+			util.CmdHandler(app.TemplateSelectedMsg{Template: d.templates[d.selected]}),
+		)
+	case "e":
+		path, err := d.app.ExportTemplate(d.templates[d.selected].Name)
+		if err != nil {
+			return d, toast.NewErrorToast(err.Error(), toast.WithTitle("Template not exported"))
+		}
+		d.templates[d.selected].Path = path
+		return d, toast.NewSuccessToast("Saved to "+path, toast.WithTitle("Template exported"))
+	}
+	return d, nil
+}
+
+func (d *templatesDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+	width := max(40, min(90, layout.Current.Container.Width-12))
+
+	if len(d.templates) == 0 {
+		return d.modal.Render(mutedStyle.Render("No templates"), background)
+	}
+
+	var lines []string
+	start := max(0, min(d.selected-maxTemplateRows/2, len(d.templates)-maxTemplateRows))
+	end := min(len(d.templates), start+maxTemplateRows)
+	for i := start; i < end; i++ {
+		template := d.templates[i]
+		prefix := "  "
+		nameStyle := textStyle
+		if i == d.selected {
+			prefix = "› "
+			nameStyle = nameStyle.Foreground(t.Primary()).Bold(true)
+		}
+		lines = append(lines, textStyle.Render(prefix)+nameStyle.Render(template.Name)+mutedStyle.Render("  "+template.Description))
+	}
+	if end < len(d.templates) {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  … %d more", len(d.templates)-end)))
+	}
+
+	template := d.templates[d.selected]
+	source := "built in"
+	if template.Path != "" {
+		source = util.Relative(template.Path)
+	}
+	details := []string{"agent " + valueOr(template.Agent, "current"), "model " + valueOr(template.Model, "current")}
+	if len(template.Context) > 0 {
+		details = append(details, "context "+strings.Join(template.Context, ", "))
+	}
+	lines = append(lines, "", mutedStyle.Render(strings.Join(details, " · ")), mutedStyle.Render(source))
+	if template.System != "" {
+		lines = append(lines, "", util.TruncateHeight(textStyle.Width(width).Render(template.System), 6))
+	}
+	lines = append(lines, "", help("↑/↓", "select", "enter", "start session", "e", "export to project"))
+	return d.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func (d *templatesDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewTemplatesDialog creates a dialog choosing the template of a new session
+func NewTemplatesDialog(app *app.App, templates []sessiontemplate.Template) TemplatesDialog {
+	return &templatesDialog{
+		app:       app,
+		templates: templates,
+		modal: modal.New(
+			modal.WithTitle("New Session from Template"),
+			modal.WithMaxWidth(min(94, layout.Current.Container.Width-8)),
+		),
+	}
+}
@@ -0,0 +1,183 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/todos"
+)
+
+// maxTodoRows is the number of action items listed at once
+const maxTodoRows = 10
+
+// TodosDialog lists the action items extracted from a session and exports
+// the checked ones to TODO.md or GitHub issues
+type TodosDialog interface {
+	layout.Modal
+}
+
+type todosDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	session  string
+	items    []todos.Item
+	checked  []bool
+	selected int
+	filing   bool
+}
+
+func (d *todosDialog) Init() tea.Cmd {
+	return nil
+}
+
+// chosen returns the checked items, or every item when none is checked
+func (d *todosDialog) chosen() []todos.Item {
+	var items []todos.Item
+	for i, item := range d.items {
+		if d.checked[i] {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return d.items
+	}
+	return items
+}
+
+func (d *todosDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case app.TodoIssuesFiledMsg:
+		d.filing = false
+		for i := range d.items {
+			if url, ok := msg.URLs[d.items[i].Title]; ok {
+				d.items[i].Issue = url
+				d.checked[i] = false
+			}
+		}
+		return d, nil
+	case tea.KeyPressMsg:
+		if len(d.items) == 0 {
+			return d, nil
+		}
+		switch msg.String() {
+		case "up", "k":
+			d.selected = max(0, d.selected-1)
+		case "down", "j":
+			d.selected = min(len(d.items)-1, d.selected+1)
+		case "space", "x":
+			d.checked[d.selected] = !d.checked[d.selected]
+		case "a":
+			all := !d.checked[d.selected]
+			for i := range d.checked {
+				d.checked[i] = all
+			}
+		case "d", "delete", "backspace":
+			d.items = append(d.items[:d.selected], d.items[d.selected+1:]...)
+			d.checked = append(d.checked[:d.selected], d.checked[d.selected+1:]...)
+			d.selected = max(0, min(d.selected, len(d.items)-1))
+		case "e":
+			path, err := d.app.ExportTodos(d.session, d.chosen())
+			if err != nil {
+				return d, toast.NewErrorToast(err.Error(), toast.WithTitle("TODO export failed"))
+			}
+			return d, toast.NewSuccessToast("Saved to "+path, toast.WithTitle("TODO list exported"))
+		case "i":
+			if d.filing {
+				return d, nil
+			}
+			var items []todos.Item
+			for _, item := range d.chosen() {
+				if item.Issue == "" {
+					items = append(items, item)
+				}
+			}
+			if len(items) == 0 {
+				return d, toast.NewInfoToast("Every item has an issue already")
+			}
+			d.filing = true
+			return d, d.app.FileTodoIssues(d.session, items)
+		}
+	}
+	return d, nil
+}
+
+func (d *todosDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+
+	lines := []string{mutedStyle.Render(d.session), ""}
+	if len(d.items) == 0 {
+		lines = append(lines, base.Foreground(t.Success()).Render("✓ Nothing left to do"))
+		return d.modal.Render(strings.Join(lines, "\n"), background)
+	}
+
+	start := max(0, min(d.selected-maxTodoRows/2, len(d.items)-maxTodoRows))
+	end := min(len(d.items), start+maxTodoRows)
+	for i := start; i < end; i++ {
+		item := d.items[i]
+		prefix := "  "
+		titleStyle := textStyle
+		if i == d.selected {
+			prefix = "› "
+			titleStyle = titleStyle.Bold(true)
+		}
+		box := mutedStyle.Render("[ ] ")
+		if d.checked[i] {
+			box = base.Foreground(t.Primary()).Render("[x] ")
+		}
+		line := textStyle.Render(prefix) + box + titleStyle.Render(item.Title)
+		if item.Issue != "" {
+			line += base.Foreground(t.Success()).Render("  ✓ " + item.Issue)
+		}
+		lines = append(lines, line)
+	}
+	if end < len(d.items) {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  … %d more", len(d.items)-end)))
+	}
+
+	if detail := d.items[d.selected].Detail; detail != "" {
+		width := max(40, layout.Current.Container.Width-12)
+		lines = append(lines, "", textStyle.Width(width).Render(detail))
+	}
+	lines = append(lines, "")
+	if d.filing {
+		lines = append(lines, mutedStyle.Render("Filing GitHub issues…"), "")
+	}
+	lines = append(lines, help("↑/↓", "select", "space", "check", "d", "remove", "e", "append to "+todos.FileName, "i", "file issues"))
+	return d.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (d *todosDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewTodosDialog creates a dialog for the action items of a session
+func NewTodosDialog(app *app.App, session string, items []todos.Item) TodosDialog {
+	return &todosDialog{
+		app:     app,
+		session: session,
+		items:   items,
+		checked: make([]bool, len(items)),
+		modal: modal.New(
+			modal.WithTitle("Action Items"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+}
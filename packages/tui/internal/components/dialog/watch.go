@@ -0,0 +1,144 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+)
+
+// WatchDialog lists the watches running in this TUI and runs or stops them
+type WatchDialog interface {
+	layout.Modal
+}
+
+type watchDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	selected int
+}
+
+func (w *watchDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (w *watchDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	watches := w.app.Watches()
+	w.selected = max(0, min(w.selected, len(watches)-1))
+	key, ok := msg.(tea.KeyPressMsg)
+	if !ok || len(watches) == 0 {
+		return w, nil
+	}
+	watch := watches[w.selected]
+	switch key.String() {
+	case "up", "k":
+		w.selected = max(0, w.selected-1)
+	case "down", "j":
+		w.selected = min(len(watches)-1, w.selected+1)
+	case "r":
+		return w, w.app.RunWatch(watch.Trigger.ID)
+	case "d", "delete":
+		if err := w.app.RemoveWatch(watch.Trigger.ID); err != nil {
+			return w, toast.NewErrorToast(err.Error())
+		}
+		w.selected = max(0, min(w.selected, len(w.app.Watches())-1))
+		return w, toast.NewInfoToast("Stopped watching " + watch.Trigger.Name)
+	}
+	return w, nil
+}
+
+func (w *watchDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+
+	watches := w.app.Watches()
+	var lines []string
+	if len(watches) == 0 {
+		lines = append(lines,
+			textStyle.Render("Nothing is being watched."),
+			"",
+			mutedStyle.Render("Add a watch with /watch add [options] <command> => <prompt>, for example:"),
+			textStyle.Render("  /watch add --every 2m --match failure gh run list --limit 10 => Triage these failing CI runs: {{.Matches}}"),
+			"",
+			mutedStyle.Render("--every D   run on an interval (default 5m)     --on PATH   run when a file or directory changes"),
+			mutedStyle.Render("--match RE  fire on new matching lines          --fail      fire when the command fails"),
+			mutedStyle.Render("Otherwise a watch fires when the output changes. Prompts can use {{.Output}}, {{.Previous}},"),
+			mutedStyle.Render("{{.Matches}} and {{.ExitCode}}; without them the output is appended."),
+		)
+		return w.modal.Render(strings.Join(lines, "\n"), background)
+	}
+
+	for i, watch := range watches {
+		prefix := "  "
+		nameStyle := textStyle
+		if i == w.selected {
+			prefix = "› "
+			nameStyle = nameStyle.Bold(true)
+		}
+		status := mutedStyle.Render("waiting for its first run")
+		switch {
+		case watch.Running:
+			status = base.Foreground(t.Info()).Render("running…")
+		case watch.LastErr != nil:
+			status = base.Foreground(t.Error()).Render("failed")
+		case watch.Last != nil:
+			status = mutedStyle.Render(fmt.Sprintf("ran %s · exit %d", watch.Last.RanAt.Format("15:04:05"), watch.Last.ExitCode))
+		}
+		if watch.Fired > 0 {
+			status += mutedStyle.Render(fmt.Sprintf(" · fired %d×, last %s", watch.Fired, watch.LastFired.Format("15:04")))
+		}
+		lines = append(lines, textStyle.Render(prefix)+nameStyle.Render(watch.Trigger.Name)+
+			mutedStyle.Render(fmt.Sprintf("  [%s]  ", watch.Trigger.ID))+status)
+	}
+
+	watch := watches[w.selected]
+	width := max(40, layout.Current.Container.Width-12)
+	lines = append(lines,
+		"",
+		textStyle.Width(width).Render("$ "+watch.Trigger.Command),
+		mutedStyle.Width(width).Render(watch.Trigger.Describe()),
+		"",
+		textStyle.Width(width).Render(watch.Trigger.Prompt),
+	)
+	if watch.LastErr != nil {
+		lines = append(lines, "", base.Foreground(t.Error()).Width(width).Render(watch.LastErr.Error()))
+	}
+	lines = append(lines,
+		"",
+		help("↑/↓", "select", "r", "run now", "d", "stop"),
+		"",
+		mutedStyle.Render("Fired prompts are sent to the current session. Watches stop when RyCode exits."),
+	)
+	return w.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (w *watchDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewWatchDialog creates a dialog listing the running watches
+func NewWatchDialog(app *app.App) WatchDialog {
+	return &watchDialog{
+		app: app,
+		modal: modal.New(
+			modal.WithTitle("Watches"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+}
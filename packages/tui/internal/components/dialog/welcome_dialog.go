@@ -5,10 +5,12 @@ import (
 	"strings"
 
 	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
 	"github.com/aaronmrosenthal/rycode/internal/layout"
 	"github.com/aaronmrosenthal/rycode/internal/styles"
 	"github.com/aaronmrosenthal/rycode/internal/theme"
 	"github.com/aaronmrosenthal/rycode/internal/typography"
+	"github.com/aaronmrosenthal/rycode/internal/util"
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/charmbracelet/lipgloss/v2"
 )
@@ -86,6 +88,7 @@ func (w *welcomeDialog) setupSteps() {
 			Bullets: []string{
 				"[A] Auto-detect credentials from environment variables",
 				"[M] Manually enter API key for a specific provider",
+				"[T] Try the tutorial playground first, no API key needed",
 				"",
 				"Auto-detect looks for:",
 				"  • ANTHROPIC_API_KEY",
@@ -95,7 +98,7 @@ func (w *welcomeDialog) setupSteps() {
 				"  • QWEN_API_KEY",
 			},
 			Action:  "Choose your setup method",
-			KeyHint: "Press [A] for auto-detect or [M] for manual",
+			KeyHint: "Press [A] for auto-detect, [M] for manual or [T] for the tutorial",
 		},
 		{
 			Title:       "Keyboard Shortcuts",
@@ -194,6 +197,15 @@ func (w *welcomeDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Open manual auth dialog
 				// Would open auth prompt here
 			}
+
+		case "t":
+			// Tutorial playground (on step 2)
+			if w.currentStep == 2 {
+				return w, tea.Sequence(
+					util.CmdHandler(modal.CloseModalMsg{}),
+					util.CmdHandler(app.TutorialStartMsg{}),
+				)
+			}
 		}
 	}
 
@@ -0,0 +1,172 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/modal"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+	"github.com/aaronmrosenthal/rycode/internal/worktree"
+)
+
+// maxWorktreeRows is the number of worktrees listed at once
+const maxWorktreeRows = 10
+
+// WorktreeDialog switches the TUI between the worktrees and submodules of
+// the project
+type WorktreeDialog interface {
+	layout.Modal
+}
+
+type worktreeDialog struct {
+	app       *app.App
+	modal     *modal.Modal
+	main      string
+	roots     []worktree.Root
+	selected  int
+	loading   bool
+	switching bool
+	err       error
+}
+
+func (w *worktreeDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (w *worktreeDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case app.WorktreesListedMsg:
+		w.loading = false
+		w.main, w.roots, w.err = msg.Main, msg.Roots, msg.Err
+		current, _ := w.app.Worktree()
+		for i, root := range w.roots {
+			if root.Path == current {
+				w.selected = i
+			}
+		}
+	case app.WorktreeSwitchedMsg:
+		w.switching = false
+		if msg.Err != nil {
+			w.err = msg.Err
+			return w, nil
+		}
+		return w, util.CmdHandler(modal.CloseModalMsg{})
+	case tea.KeyPressMsg:
+		if w.loading || w.switching || len(w.roots) == 0 {
+			return w, nil
+		}
+		switch msg.String() {
+		case "up", "k":
+			w.selected = max(0, w.selected-1)
+		case "down", "j":
+			w.selected = min(len(w.roots)-1, w.selected+1)
+		case "enter":
+			root := w.roots[w.selected]
+			if current, _ := w.app.Worktree(); root.Path == current {
+				return w, util.CmdHandler(modal.CloseModalMsg{})
+			}
+			if w.app.IsBusy() {
+				w.err = fmt.Errorf("wait for the reply to finish before switching")
+				return w, nil
+			}
+			w.switching = true
+			w.err = nil
+			return w, w.app.CheckClear(w.app.SwitchWorktree(root.Path))
+		}
+	}
+	return w, nil
+}
+
+func (w *worktreeDialog) Render(background string) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundPanel())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	accentStyle := base.Foreground(t.Primary())
+	errorStyle := base.Foreground(t.Error())
+	keyStyle := base.Foreground(t.Text()).Bold(true)
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, keyStyle.Render(pairs[i])+mutedStyle.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, mutedStyle.Render("   "))
+	}
+
+	var lines []string
+	switch {
+	case w.loading:
+		lines = append(lines, mutedStyle.Render("Looking for worktrees…"))
+	case len(w.roots) == 0 && w.err != nil:
+		lines = append(lines, errorStyle.Render(w.err.Error()))
+	case len(w.roots) == 0:
+		lines = append(lines, mutedStyle.Render("No worktrees or submodules."))
+	default:
+		current, _ := w.app.Worktree()
+		width := max(30, layout.Current.Container.Width-40)
+		start := max(0, min(w.selected-maxWorktreeRows/2, len(w.roots)-maxWorktreeRows))
+		end := min(len(w.roots), start+maxWorktreeRows)
+		for i := start; i < end; i++ {
+			root := w.roots[i]
+			prefix := "  "
+			labelStyle := textStyle
+			if i == w.selected {
+				prefix = "› "
+				labelStyle = labelStyle.Bold(true)
+			}
+			mark := "  "
+			if root.Path == current {
+				mark = "● "
+			}
+			label := ansi.Truncate(root.Label(w.main), width, "…")
+			detail := string(root.Kind)
+			if ref := root.Ref(); ref != "" {
+				detail += " · " + ref
+			}
+			lines = append(lines,
+				textStyle.Render(prefix)+accentStyle.Render(mark)+labelStyle.Render(label)+mutedStyle.Render("  "+detail),
+			)
+		}
+		if end < len(w.roots) {
+			lines = append(lines, mutedStyle.Render(fmt.Sprintf("  … %d more", len(w.roots)-end)))
+		}
+		lines = append(lines, "")
+		switch {
+		case w.switching:
+			lines = append(lines, mutedStyle.Render("Switching…"))
+		case w.err != nil:
+			lines = append(lines, errorStyle.Render(w.err.Error()), "", help("↑/↓", "select", "enter", "switch", "esc", "close"))
+		default:
+			lines = append(lines,
+				mutedStyle.Render("Switching starts a new session, with the agent's files limited to the worktree."),
+				"",
+				help("↑/↓", "select", "enter", "switch", "esc", "close"),
+			)
+		}
+	}
+	return w.modal.Render(strings.Join(lines, "\n"), background)
+}
+
+func (w *worktreeDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewWorktreeDialog creates a dialog switching between the worktrees and
+// submodules of the project, which it lists when ListWorktrees' message
+// arrives
+func NewWorktreeDialog(app *app.App) WorktreeDialog {
+	return &worktreeDialog{
+		app:     app,
+		loading: true,
+		modal: modal.New(
+			modal.WithTitle("Worktrees"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+}
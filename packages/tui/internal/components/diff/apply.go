@@ -0,0 +1,127 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var hunkRangePattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? `)
+
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -(\S+) \+(\S+) @@(.*)$`)
+
+// ApplyHunks applies hunks of a diff of text, in order, leaving out the
+// ones that aren't passed. The hunks are the ones ParseUnifiedDiff returns,
+// whose context lines keep their leading space. Each hunk's context and
+// removed lines must match text where its header places them.
+func ApplyHunks(text string, hunks []Hunk) (string, error) {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var sb strings.Builder
+	write := func(line string) {
+		// A last line without a newline gets one when lines follow it
+		if sb.Len() > 0 && !strings.HasSuffix(sb.String(), "\n") {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(line)
+	}
+
+	next := 0 // Index of the next line of text to copy
+	for i, hunk := range hunks {
+		start, err := hunkStart(hunk.Header)
+		if err != nil {
+			return "", err
+		}
+		if start < next || start > len(lines) {
+			return "", fmt.Errorf("hunk %d is out of place: %s", i+1, hunk.Header)
+		}
+		for _, line := range lines[next:start] {
+			write(line)
+		}
+		at := start
+		for _, line := range hunk.Lines {
+			if line.Kind == LineAdded {
+				write(line.Content + "\n")
+				continue
+			}
+			content := line.Content
+			if line.Kind == LineContext {
+				content = strings.TrimPrefix(content, " ")
+			}
+			if at >= len(lines) || strings.TrimRight(lines[at], "\r\n") != content {
+				return "", fmt.Errorf("hunk %d doesn't match line %d: %s", i+1, at+1, hunk.Header)
+			}
+			if line.Kind == LineContext {
+				write(lines[at])
+			}
+			at++
+		}
+		next = at
+	}
+	for _, line := range lines[next:] {
+		write(line)
+	}
+	return sb.String(), nil
+}
+
+// ReverseHunks returns the hunks undoing hunks: the added lines become
+// removed ones and the ranges of the header are swapped
+func ReverseHunks(hunks []Hunk) []Hunk {
+	reversed := make([]Hunk, 0, len(hunks))
+	for _, hunk := range hunks {
+		header := hunk.Header
+		if match := hunkHeaderPattern.FindStringSubmatch(header); match != nil {
+			header = "@@ -" + match[2] + " +" + match[1] + " @@" + match[3]
+		}
+		lines := make([]DiffLine, len(hunk.Lines))
+		for i, line := range hunk.Lines {
+			switch line.Kind {
+			case LineAdded:
+				line.Kind = LineRemoved
+			case LineRemoved:
+				line.Kind = LineAdded
+			}
+			line.OldLineNo, line.NewLineNo = line.NewLineNo, line.OldLineNo
+			lines[i] = line
+		}
+		reversed = append(reversed, Hunk{Header: header, Lines: lines})
+	}
+	return reversed
+}
+
+// hunkStart returns the index of the first line a hunk covers in the old
+// text. A hunk covering no lines inserts after the line its header names.
+func hunkStart(header string) (int, error) {
+	match := hunkRangePattern.FindStringSubmatch(header)
+	if match == nil {
+		return 0, fmt.Errorf("invalid hunk header: %s", header)
+	}
+	start, _ := strconv.Atoi(match[1])
+	if match[2] == "0" {
+		return start, nil
+	}
+	return max(0, start-1), nil
+}
+
+// FormatHunks returns hunks ParseUnifiedDiff returned in unified diff format
+func FormatHunks(hunks []Hunk) string {
+	var sb strings.Builder
+	for _, hunk := range hunks {
+		sb.WriteString(hunk.Header + "\n")
+		for _, line := range hunk.Lines {
+			switch line.Kind {
+			case LineAdded:
+				sb.WriteString("+" + line.Content + "\n")
+			case LineRemoved:
+				sb.WriteString("-" + line.Content + "\n")
+			default:
+				sb.WriteString(" " + strings.TrimPrefix(line.Content, " ") + "\n")
+			}
+		}
+	}
+	return sb.String()
+}
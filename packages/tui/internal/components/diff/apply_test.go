@@ -0,0 +1,96 @@
+package diff
+
+import "testing"
+
+func TestApplyHunks(t *testing.T) {
+	oldText := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	newText := "one\nTWO\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n"
+	result, err := ParseUnifiedDiff(GenerateUnifiedDiff("a", "b", oldText, newText, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Hunks) != 2 {
+		t.Fatalf("expected 2 hunks, got %d", len(result.Hunks))
+	}
+
+	tests := []struct {
+		name  string
+		hunks []Hunk
+		want  string
+	}{
+		{"all", result.Hunks, newText},
+		{"none", nil, oldText},
+		{"first", result.Hunks[:1], "one\nTWO\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"},
+		{"second", result.Hunks[1:], "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ApplyHunks(oldText, test.hunks)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("ApplyHunks = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestApplyHunks_WithoutFinalNewline(t *testing.T) {
+	result, err := ParseUnifiedDiff("@@ -2,1 +2,2 @@\n b\n+c\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ApplyHunks("a\nb", result.Hunks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "a\nb\nc\n" {
+		t.Errorf("ApplyHunks = %q", got)
+	}
+}
+
+func TestApplyHunks_Mismatch(t *testing.T) {
+	result, err := ParseUnifiedDiff("@@ -1,2 +1,2 @@\n one\n-two\n+TWO\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ApplyHunks("one\nchanged\n", result.Hunks); err == nil {
+		t.Error("a hunk applied to lines it doesn't match")
+	}
+}
+
+func TestFormatHunks(t *testing.T) {
+	patch := "@@ -1,3 +1,3 @@\n one\n-two\n+TWO\n three\n"
+	result, err := ParseUnifiedDiff(patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := FormatHunks(result.Hunks); got != patch {
+		t.Errorf("FormatHunks = %q, want %q", got, patch)
+	}
+}
+
+func TestReverseHunks(t *testing.T) {
+	oldText := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	newText := "one\nTWO\nthree\nfour\nfive\nsix\nseven\nnine\nten\neleven\n"
+	result, err := ParseUnifiedDiff(GenerateUnifiedDiff("a", "b", oldText, newText, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ApplyHunks(newText, ReverseHunks(result.Hunks))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != oldText {
+		t.Errorf("reversed hunks gave %q, want %q", got, oldText)
+	}
+
+	created, err := ParseUnifiedDiff("@@ -0,0 +1,2 @@\n+a\n+b\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ApplyHunks("a\nb\n", ReverseHunks(created.Hunks)); err != nil || got != "" {
+		t.Errorf("reversing a creation gave %q, %v", got, err)
+	}
+}
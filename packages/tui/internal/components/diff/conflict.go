@@ -0,0 +1,143 @@
+package diff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Overlap pairs a hunk with a change made beside it to the same lines
+type Overlap struct {
+	Hunk   int // Index among the hunks rebased
+	Change int // Index among the changes
+}
+
+// Changes returns the hunks turning oldText into newText as
+// ParseUnifiedDiff returns them, with a line of context so that the header
+// of an insertion places it
+func Changes(oldText, newText string) []Hunk {
+	result, err := ParseUnifiedDiff(GenerateUnifiedDiff("a", "b", oldText, newText, 1))
+	if err != nil {
+		return nil
+	}
+	return result.Hunks
+}
+
+// Rebase moves hunks of a text onto the text changes turned it into, each
+// shifted by the lines the changes before it added or removed. Hunks whose
+// lines, context included, a change touches can't be moved, as they would
+// no longer match, and are returned as overlaps instead.
+func Rebase(hunks, changes []Hunk) ([]Hunk, []Overlap) {
+	var rebased []Hunk
+	var overlaps []Overlap
+	for i, hunk := range hunks {
+		lo, hi, ok := span(hunk, true)
+		if !ok {
+			rebased = append(rebased, hunk)
+			continue
+		}
+		shift, overlapping := 0, false
+		for j, change := range changes {
+			clo, chi, ok := span(change, false)
+			if !ok {
+				continue
+			}
+			switch {
+			case chi < lo:
+				shift += netLines(change)
+			case clo <= hi:
+				overlaps = append(overlaps, Overlap{Hunk: i, Change: j})
+				overlapping = true
+			}
+		}
+		if !overlapping {
+			rebased = append(rebased, shiftHunk(hunk, shift))
+		}
+	}
+	return rebased, overlaps
+}
+
+// span returns the lines of the old text a hunk covers, in doubled numbers
+// so that line n is 2n and inserting before it is 2n-1. Context lines are
+// left out unless context is set.
+func span(hunk Hunk, context bool) (lo, hi int, ok bool) {
+	at, err := hunkStart(hunk.Header)
+	if err != nil {
+		return 0, 0, false
+	}
+	for _, line := range hunk.Lines {
+		var pos int
+		switch line.Kind {
+		case LineContext:
+			at++
+			if !context {
+				continue
+			}
+			pos = 2 * at
+		case LineRemoved:
+			at++
+			pos = 2 * at
+		case LineAdded:
+			pos = 2*(at+1) - 1
+		}
+		if !ok || pos < lo {
+			lo = pos
+		}
+		if !ok || pos > hi {
+			hi = pos
+		}
+		ok = true
+	}
+	return lo, hi, ok
+}
+
+// netLines returns the lines a hunk adds less the ones it removes
+func netLines(hunk Hunk) int {
+	n := 0
+	for _, line := range hunk.Lines {
+		switch line.Kind {
+		case LineAdded:
+			n++
+		case LineRemoved:
+			n--
+		}
+	}
+	return n
+}
+
+// shiftHunk moves a hunk by shift lines, in its header and line numbers
+func shiftHunk(hunk Hunk, shift int) Hunk {
+	if shift == 0 {
+		return hunk
+	}
+	match := hunkHeaderPattern.FindStringSubmatch(hunk.Header)
+	if match == nil {
+		return hunk
+	}
+	lines := make([]DiffLine, len(hunk.Lines))
+	for i, line := range hunk.Lines {
+		if line.OldLineNo > 0 {
+			line.OldLineNo += shift
+		}
+		if line.NewLineNo > 0 {
+			line.NewLineNo += shift
+		}
+		lines[i] = line
+	}
+	header := "@@ -" + shiftRange(match[1], shift) + " +" + shiftRange(match[2], shift) + " @@" + match[3]
+	return Hunk{Header: header, Lines: lines}
+}
+
+// shiftRange moves the start of a start,count range of a hunk header
+func shiftRange(r string, shift int) string {
+	start, count, found := strings.Cut(r, ",")
+	n, err := strconv.Atoi(start)
+	if err != nil {
+		return r
+	}
+	n = max(0, n+shift)
+	if !found {
+		return strconv.Itoa(n)
+	}
+	return fmt.Sprintf("%d,%s", n, count)
+}
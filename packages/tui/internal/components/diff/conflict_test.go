@@ -0,0 +1,61 @@
+package diff
+
+import "testing"
+
+func TestRebase(t *testing.T) {
+	base := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\ntwelve\n"
+	ai := "one\ntwo\nthree\nfour\nfive\nsix\nseven\nEIGHT\nnine\nten\neleven\ntwelve\n"
+	result, err := ParseUnifiedDiff(GenerateUnifiedDiff("a", "b", base, ai, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		current  string
+		want     string
+		overlaps int
+	}{
+		{
+			"lines added above",
+			"zero\nhalf\none\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\ntwelve\n",
+			"zero\nhalf\none\ntwo\nthree\nfour\nfive\nsix\nseven\nEIGHT\nnine\nten\neleven\ntwelve\n",
+			0,
+		},
+		{
+			"line removed above",
+			"one\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\ntwelve\n",
+			"one\nthree\nfour\nfive\nsix\nseven\nEIGHT\nnine\nten\neleven\ntwelve\n",
+			0,
+		},
+		{
+			"line added below",
+			"one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\ntwelve\nthirteen\n",
+			"one\ntwo\nthree\nfour\nfive\nsix\nseven\nEIGHT\nnine\nten\neleven\ntwelve\nthirteen\n",
+			0,
+		},
+		{"same line", "one\ntwo\nthree\nfour\nfive\nsix\nseven\nocho\nnine\nten\neleven\ntwelve\n", "", 1},
+		{"context line", "one\ntwo\nthree\nfour\nfive\nsix\nSEVEN\neight\nnine\nten\neleven\ntwelve\n", "", 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rebased, overlaps := Rebase(result.Hunks, Changes(base, test.current))
+			if len(overlaps) != test.overlaps {
+				t.Fatalf("got %d overlaps, want %d", len(overlaps), test.overlaps)
+			}
+			if test.overlaps > 0 {
+				if len(rebased) != 0 {
+					t.Errorf("an overlapping hunk was rebased")
+				}
+				return
+			}
+			got, err := ApplyHunks(test.current, rebased)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("rebased edit = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// DefaultContextLines is the number of unchanged lines kept around each change
+const DefaultContextLines = 3
+
+// GenerateUnifiedDiff builds a unified diff between two texts that can be
+// passed to FormatUnifiedDiff or FormatDiff. It returns an empty string when
+// the texts are identical.
+func GenerateUnifiedDiff(oldName, newName, oldText, newText string, contextLines int) string {
+	lines := diffLines(oldText, newText)
+	hunks := groupHunks(lines, contextLines)
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("--- a/" + oldName + "\n")
+	sb.WriteString("+++ b/" + newName + "\n")
+	for _, h := range hunks {
+		sb.WriteString(h.Header + "\n")
+		for _, line := range h.Lines {
+			switch line.Kind {
+			case LineAdded:
+				sb.WriteString("+")
+			case LineRemoved:
+				sb.WriteString("-")
+			default:
+				sb.WriteString(" ")
+			}
+			sb.WriteString(line.Content + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// ChangeStats counts the lines added and removed between two texts
+func ChangeStats(oldText, newText string) DiffStats {
+	var stats DiffStats
+	for _, line := range diffLines(oldText, newText) {
+		switch line.Kind {
+		case LineAdded:
+			stats.Added++
+		case LineRemoved:
+			stats.Removed++
+		}
+	}
+	stats.Modified = stats.Added + stats.Removed
+	return stats
+}
+
+// diffLines computes a line-level diff with old and new line numbers filled in
+func diffLines(oldText, newText string) []DiffLine {
+	dmp := diffmatchpatch.New()
+	oldChars, newChars, lineArray := dmp.DiffLinesToChars(oldText, newText)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(oldChars, newChars, false), lineArray)
+
+	var lines []DiffLine
+	oldLine, newLine := 1, 1
+	for _, d := range diffs {
+		for _, content := range splitLines(d.Text) {
+			switch d.Type {
+			case diffmatchpatch.DiffInsert:
+				lines = append(lines, DiffLine{NewLineNo: newLine, Kind: LineAdded, Content: content})
+				newLine++
+			case diffmatchpatch.DiffDelete:
+				lines = append(lines, DiffLine{OldLineNo: oldLine, Kind: LineRemoved, Content: content})
+				oldLine++
+			default:
+				lines = append(lines, DiffLine{OldLineNo: oldLine, NewLineNo: newLine, Kind: LineContext, Content: content})
+				oldLine++
+				newLine++
+			}
+		}
+	}
+	return lines
+}
+
+// groupHunks splits diff lines into hunks, keeping contextLines of
+// unchanged lines around each change
+func groupHunks(lines []DiffLine, contextLines int) []Hunk {
+	if contextLines < 0 {
+		contextLines = DefaultContextLines
+	}
+
+	var hunks []Hunk
+	start, end := -1, -1
+	flush := func() {
+		if start < 0 {
+			return
+		}
+		hunkLines := lines[start:end]
+		hunks = append(hunks, Hunk{Header: hunkHeader(hunkLines), Lines: hunkLines})
+		start, end = -1, -1
+	}
+
+	for i, line := range lines {
+		if line.Kind == LineContext {
+			continue
+		}
+		from := max(0, i-contextLines)
+		to := min(len(lines), i+contextLines+1)
+		if start >= 0 && from > end {
+			flush()
+		}
+		if start < 0 {
+			start = from
+		}
+		end = max(end, to)
+	}
+	flush()
+
+	return hunks
+}
+
+func hunkHeader(lines []DiffLine) string {
+	oldStart, newStart := 0, 0
+	oldCount, newCount := 0, 0
+	for _, line := range lines {
+		if line.Kind != LineAdded {
+			if oldStart == 0 {
+				oldStart = line.OldLineNo
+			}
+			oldCount++
+		}
+		if line.Kind != LineRemoved {
+			if newStart == 0 {
+				newStart = line.NewLineNo
+			}
+			newCount++
+		}
+	}
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", oldStart, oldCount, newStart, newCount)
+}
+
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return []string{""}
+	}
+	return strings.Split(text, "\n")
+}
@@ -0,0 +1,40 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateUnifiedDiff_Identical(t *testing.T) {
+	if got := GenerateUnifiedDiff("a", "b", "same\ntext\n", "same\ntext\n", 3); got != "" {
+		t.Errorf("expected empty diff for identical texts, got %q", got)
+	}
+}
+
+func TestGenerateUnifiedDiff_RoundTrip(t *testing.T) {
+	oldText := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	newText := "one\ntwo\nTHREE\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n"
+
+	text := GenerateUnifiedDiff("old", "new", oldText, newText, 1)
+	result, err := ParseUnifiedDiff(text)
+	if err != nil {
+		t.Fatalf("failed to parse generated diff: %v", err)
+	}
+
+	if result.OldFile != "old" || result.NewFile != "new" {
+		t.Errorf("unexpected file names %q / %q", result.OldFile, result.NewFile)
+	}
+	if len(result.Hunks) != 2 {
+		t.Fatalf("expected 2 hunks, got %d:\n%s", len(result.Hunks), text)
+	}
+	if !strings.HasPrefix(result.Hunks[0].Header, "@@ -2,3 +2,3 @@") {
+		t.Errorf("unexpected first hunk header %q", result.Hunks[0].Header)
+	}
+}
+
+func TestChangeStats(t *testing.T) {
+	stats := ChangeStats("a\nb\nc\n", "a\nx\nc\nd\n")
+	if stats.Added != 2 || stats.Removed != 1 || stats.Modified != 3 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
@@ -1,8 +1,10 @@
 package help
 
 import (
+	"slices"
 	"strings"
 
+	"github.com/aaronmrosenthal/rycode/internal/commands"
 	"github.com/aaronmrosenthal/rycode/internal/styles"
 	"github.com/aaronmrosenthal/rycode/internal/theme"
 )
@@ -11,15 +13,17 @@ import (
 type HelpContext string
 
 const (
-	ContextModelSelector   HelpContext = "model_selector"
-	ContextProviderList    HelpContext = "provider_list"
-	ContextInsightsDash    HelpContext = "insights_dashboard"
-	ContextBudgetForecast  HelpContext = "budget_forecast"
-	ContextChat            HelpContext = "chat"
-	ContextEmpty           HelpContext = "empty_state"
-	ContextFirstRun        HelpContext = "first_run"
-	ContextAuthentication  HelpContext = "authentication"
-	ContextModelRecommend  HelpContext = "model_recommendations"
+	ContextModelSelector  HelpContext = "model_selector"
+	ContextProviderList   HelpContext = "provider_list"
+	ContextInsightsDash   HelpContext = "insights_dashboard"
+	ContextBudgetForecast HelpContext = "budget_forecast"
+	ContextChat           HelpContext = "chat"
+	ContextEmpty          HelpContext = "empty_state"
+	ContextFirstRun       HelpContext = "first_run"
+	ContextAuthentication HelpContext = "authentication"
+	ContextModelRecommend HelpContext = "model_recommendations"
+	ContextNoProviders    HelpContext = "no_providers"
+	ContextBudgetOverrun  HelpContext = "budget_overrun"
 )
 
 // ContextualHint represents a helpful tip for a specific context
@@ -27,8 +31,9 @@ type ContextualHint struct {
 	Icon        string
 	Title       string
 	Message     string
-	Action      string // Suggested action
-	Shortcut    string // Keyboard shortcut if applicable
+	Action      string               // Suggested action
+	Shortcut    string               // Keyboard shortcut if applicable
+	Command     commands.CommandName // Command whose keybind is the shortcut, when set
 	Dismissable bool
 }
 
@@ -128,17 +133,19 @@ func (p *ContextHelpProvider) setupHints() {
 			{
 				Icon:        "⌨️",
 				Title:       "Keyboard Shortcuts",
-				Message:     "Press Ctrl+? to see all available keyboard shortcuts",
+				Message:     "Open the searchable list of every keyboard shortcut",
 				Action:      "Master shortcuts to boost productivity",
 				Shortcut:    "Ctrl+?",
+				Command:     commands.AppShortcutsCommand,
 				Dismissable: true,
 			},
 			{
 				Icon:        "🔄",
-				Title:       "Switch Models Anytime",
-				Message:     "Press Tab to quickly switch between models mid-conversation",
+				Title:       "Switch Providers Anytime",
+				Message:     "Cycle to your next provider mid-conversation",
 				Action:      "Try switching to a cheaper model for simple questions",
 				Shortcut:    "Tab",
+				Command:     commands.AgentCycleCommand,
 				Dismissable: true,
 			},
 		},
@@ -147,11 +154,34 @@ func (p *ContextHelpProvider) setupHints() {
 				Icon:        "👋",
 				Title:       "Welcome to RyCode!",
 				Message:     "Start by selecting a model and asking a question",
-				Action:      "Press Ctrl+M to open the model selector",
+				Action:      "Open the model selector",
 				Shortcut:    "Ctrl+M",
+				Command:     commands.ModelListCommand,
+				Dismissable: true,
+			},
+		},
+		ContextNoProviders: {
+			{
+				Icon:        "🔐",
+				Title:       "No Providers Yet",
+				Message:     "Authenticate a provider to start chatting, or practise in the offline tutorial",
+				Action:      "Start the tutorial",
+				Shortcut:    "/tutorial",
+				Command:     commands.TutorialCommand,
 				Dismissable: false,
 			},
 		},
+		ContextBudgetOverrun: {
+			{
+				Icon:        "⚠️",
+				Title:       "Budget Alert",
+				Message:     "You're projected to exceed your budget this month",
+				Action:      "Check the forecast for cost-saving tips",
+				Shortcut:    "/budget",
+				Command:     commands.BudgetCommand,
+				Dismissable: true,
+			},
+		},
 		ContextFirstRun: {
 			{
 				Icon:        "🚀",
@@ -237,6 +267,19 @@ func (p *ContextHelpProvider) ResetHints() {
 	p.dismissedHints = make(map[HelpContext]bool)
 }
 
+// Dismissed returns the contexts whose hints were dismissed, sorted so they
+// can be saved
+func (p *ContextHelpProvider) Dismissed() []HelpContext {
+	var dismissed []HelpContext
+	for ctx, ok := range p.dismissedHints {
+		if ok {
+			dismissed = append(dismissed, ctx)
+		}
+	}
+	slices.Sort(dismissed)
+	return dismissed
+}
+
 // RenderHint creates a beautiful hint card
 func (p *ContextHelpProvider) RenderHint(hint *ContextualHint, width int) string {
 	if hint == nil {
@@ -327,22 +370,36 @@ func (p *ContextHelpProvider) RenderCompactHint(hint *ContextualHint) string {
 	return icon + " " + message + shortcut
 }
 
+// RenderInlineHint creates a single-line hint on the background of base,
+// for the status bar and the home screen
+func RenderInlineHint(hint *ContextualHint, base styles.Style) string {
+	if hint == nil {
+		return ""
+	}
+	t := theme.CurrentTheme()
+	line := base.Foreground(t.Info()).Render(hint.Icon+" ") + base.Foreground(t.TextMuted()).Render(hint.Message)
+	if hint.Shortcut != "" {
+		line += base.Foreground(t.Primary()).Bold(true).Render(" [" + hint.Shortcut + "]")
+	}
+	return line
+}
+
 // GetStatusBarHint returns a context-appropriate hint for the status bar
 func (p *ContextHelpProvider) GetStatusBarHint(ctx HelpContext, viewContext string) string {
 	// Dynamic hints based on current view state
 	hints := map[string]string{
-		"model_selector_empty":       "Press Ctrl+P to authenticate a provider first",
-		"model_selector_loaded":      "Tab: Quick switch | i: Toggle recommendations | Ctrl+?: All shortcuts",
-		"chat_empty":                 "Select a model with Ctrl+M, then start chatting",
-		"chat_active":                "Tab: Switch model | Ctrl+I: Usage insights | Ctrl+B: Budget forecast",
-		"provider_list_none":         "Press 'a' to authenticate or 'd' to auto-detect credentials",
-		"provider_list_partial":      "Press 'a' to add more providers",
-		"insights_no_data":           "Make API calls to see analytics here",
-		"insights_with_data":         "Ctrl+B: Budget forecast | Scroll for optimization tips",
-		"budget_under":               "You're under budget - consider using premium models",
-		"budget_over":                "Budget overrun likely - check recommendations",
-		"welcome_flow":               "Press Enter to continue | ← → to navigate steps",
-		"shortcuts_guide":            "ESC: Close | Scroll to see all categories",
+		"model_selector_empty":  "Press Ctrl+P to authenticate a provider first",
+		"model_selector_loaded": "Tab: Quick switch | i: Toggle recommendations | Ctrl+?: All shortcuts",
+		"chat_empty":            "Select a model with Ctrl+M, then start chatting",
+		"chat_active":           "Tab: Switch model | Ctrl+I: Usage insights | Ctrl+B: Budget forecast",
+		"provider_list_none":    "Press 'a' to authenticate or 'd' to auto-detect credentials",
+		"provider_list_partial": "Press 'a' to add more providers",
+		"insights_no_data":      "Make API calls to see analytics here",
+		"insights_with_data":    "Ctrl+B: Budget forecast | Scroll for optimization tips",
+		"budget_under":          "You're under budget - consider using premium models",
+		"budget_over":           "Budget overrun likely - check recommendations",
+		"welcome_flow":          "Press Enter to continue | ← → to navigate steps",
+		"shortcuts_guide":       "ESC: Close | Scroll to see all categories",
 	}
 
 	t := theme.CurrentTheme()
@@ -416,20 +473,62 @@ func GetTooltip(element string) string {
 	return ""
 }
 
+// Interactions counted by ProgressiveTips. Each is also the ID of the tip
+// it leads to.
+const (
+	InteractionModelSwitch = "model_switch" // The model selector was opened
+	InteractionRequest     = "request"      // A prompt was sent
+	InteractionSpending    = "spending"     // The month's spending passed a threshold
+)
+
 // ProgressiveTips provides tips that appear based on user progression
 type ProgressiveTips struct {
-	tipsShown      map[string]bool
+	tipsShown        map[string]bool
 	interactionCount map[string]int
 }
 
 // NewProgressiveTips creates a new progressive tips tracker
 func NewProgressiveTips() *ProgressiveTips {
 	return &ProgressiveTips{
-		tipsShown:      make(map[string]bool),
+		tipsShown:        make(map[string]bool),
 		interactionCount: make(map[string]int),
 	}
 }
 
+// RestoreProgressiveTips creates a tracker that carries on from saved
+// counts and shown tips
+func RestoreProgressiveTips(counts map[string]int, shown []string) *ProgressiveTips {
+	pt := NewProgressiveTips()
+	for action, count := range counts {
+		pt.interactionCount[action] = count
+	}
+	for _, tipID := range shown {
+		pt.tipsShown[tipID] = true
+	}
+	return pt
+}
+
+// Counts returns the interactions recorded so far
+func (pt *ProgressiveTips) Counts() map[string]int {
+	counts := make(map[string]int, len(pt.interactionCount))
+	for action, count := range pt.interactionCount {
+		counts[action] = count
+	}
+	return counts
+}
+
+// Shown returns the tips shown so far, sorted
+func (pt *ProgressiveTips) Shown() []string {
+	var shown []string
+	for tipID, ok := range pt.tipsShown {
+		if ok {
+			shown = append(shown, tipID)
+		}
+	}
+	slices.Sort(shown)
+	return shown
+}
+
 // RecordInteraction tracks user interactions for tip timing
 func (pt *ProgressiveTips) RecordInteraction(action string) {
 	pt.interactionCount[action]++
@@ -454,41 +553,44 @@ func (pt *ProgressiveTips) MarkTipShown(tipID string) {
 
 // GetProgressiveTip returns a tip based on user progression
 func (pt *ProgressiveTips) GetProgressiveTip() *ContextualHint {
-	// After 5 model switches, suggest Tab shortcut
-	if pt.ShouldShowTip("model_switch", 5) {
-		pt.MarkTipShown("model_switch")
+	// After 5 visits to the model selector, suggest cycling recent models
+	if pt.ShouldShowTip(InteractionModelSwitch, 5) {
+		pt.MarkTipShown(InteractionModelSwitch)
 		return &ContextualHint{
 			Icon:        "⚡",
 			Title:       "Power User Tip",
-			Message:     "You're switching models often - try Tab for instant cycling",
-			Action:      "Press Tab to quickly switch between your favorite models",
-			Shortcut:    "Tab",
+			Message:     "You're switching models often - cycle through your recent ones instead",
+			Action:      "Switch models without opening the selector",
+			Shortcut:    "F2",
+			Command:     commands.ModelCycleRecentCommand,
 			Dismissable: true,
 		}
 	}
 
-	// After 10 requests, suggest insights
-	if pt.ShouldShowTip("request", 10) {
-		pt.MarkTipShown("request")
+	// After 10 requests, suggest the forecast
+	if pt.ShouldShowTip(InteractionRequest, 10) {
+		pt.MarkTipShown(InteractionRequest)
 		return &ContextualHint{
 			Icon:        "📊",
-			Title:       "Check Your Insights",
-			Message:     "You've made 10+ requests - see your usage patterns and savings opportunities",
-			Action:      "Press Ctrl+I to view your analytics dashboard",
-			Shortcut:    "Ctrl+I",
+			Title:       "Check Your Spending",
+			Message:     "You've made 10+ requests - see where your spending is heading",
+			Action:      "Open the budget forecast",
+			Shortcut:    "/budget",
+			Command:     commands.BudgetCommand,
 			Dismissable: true,
 		}
 	}
 
 	// After spending $5, suggest budget forecast
-	if pt.ShouldShowTip("spending", 1) {
-		pt.MarkTipShown("spending")
+	if pt.ShouldShowTip(InteractionSpending, 1) {
+		pt.MarkTipShown(InteractionSpending)
 		return &ContextualHint{
 			Icon:        "🔮",
 			Title:       "Budget Management",
 			Message:     "You're spending regularly - let RyCode predict your month-end costs",
-			Action:      "Press Ctrl+B to see your budget forecast",
-			Shortcut:    "Ctrl+B",
+			Action:      "Open your budget forecast",
+			Shortcut:    "/budget",
+			Command:     commands.BudgetCommand,
 			Dismissable: true,
 		}
 	}
@@ -0,0 +1,69 @@
+// Package sparkline draws small charts with braille dots: each cell holds
+// two values side by side, four dots high.
+package sparkline
+
+import (
+	"strings"
+)
+
+// dotsPerCell is the height of a cell in dots
+const dotsPerCell = 4
+
+// columnDots are the bits of a braille cell's left and right dot columns,
+// from the bottom dot up
+var columnDots = [2][dotsPerCell]rune{
+	{0x40, 0x04, 0x02, 0x01},
+	{0x80, 0x20, 0x10, 0x08},
+}
+
+// Chart draws values as bars rows cells high, two values a cell, scaled to
+// the largest. It returns the rows from the top. Any value above zero shows
+// at least one dot.
+func Chart(values []float64, rows int) []string {
+	rows = max(1, rows)
+	peak := 0.0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+	heights := make([]int, len(values))
+	for i, v := range values {
+		if v <= 0 || peak == 0 {
+			continue
+		}
+		heights[i] = max(1, int(v/peak*float64(rows*dotsPerCell)+0.5))
+	}
+
+	lines := make([]string, rows)
+	for row := range rows {
+		// Dots below this row of cells
+		floor := (rows - 1 - row) * dotsPerCell
+		var b strings.Builder
+		for i := 0; i < len(heights); i += 2 {
+			cell := rune(0x2800)
+			for col := 0; col < 2 && i+col < len(heights); col++ {
+				for dot := 0; dot < min(dotsPerCell, heights[i+col]-floor); dot++ {
+					cell |= columnDots[col][dot]
+				}
+			}
+			b.WriteRune(cell)
+		}
+		lines[row] = b.String()
+	}
+	return lines
+}
+
+// Line draws values as a sparkline one cell high
+func Line(values []float64) string {
+	return Chart(values, 1)[0]
+}
+
+// Bar draws share, from 0 to 1, as a horizontal bar cells wide, to half a
+// cell
+func Bar(share float64, cells int) string {
+	halves := min(cells*2, max(0, int(share*float64(cells*2)+0.5)))
+	bar := strings.Repeat("⣿", halves/2)
+	if halves%2 == 1 {
+		bar += "⡇"
+	}
+	return bar
+}
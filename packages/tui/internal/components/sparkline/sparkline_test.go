@@ -0,0 +1,36 @@
+package sparkline
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestChart(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		rows   int
+		want   []string
+	}{
+		{"empty", nil, 1, []string{""}},
+		{"zeros", []float64{0, 0, 0}, 1, []string{"⠀⠀"}},
+		{"rising", []float64{1, 2, 3, 4}, 1, []string{"⣠⣾"}},
+		{"small values show", []float64{1, 100}, 1, []string{"⣸"}},
+		{"two rows", []float64{8, 4}, 2, []string{"⡇", "⣿"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Chart(test.values, test.rows); !slices.Equal(got, test.want) {
+				t.Errorf("Chart(%v, %d) = %q, want %q", test.values, test.rows, got, test.want)
+			}
+		})
+	}
+}
+
+func TestBar(t *testing.T) {
+	for share, want := range map[float64]string{0: "", 0.25: "⣿", 0.4: "⣿⡇", 1: "⣿⣿⣿⣿", 2: "⣿⣿⣿⣿"} {
+		if got := Bar(share, 4); got != want {
+			t.Errorf("Bar(%v, 4) = %q, want %q", share, got, want)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/aaronmrosenthal/rycode/internal/styles"
 	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
 )
 
 // Spinner frames for different styles
@@ -85,6 +86,10 @@ func New() *Spinner {
 
 // GetProviderSpinnerFrames returns spinner frames based on the current provider theme
 func GetProviderSpinnerFrames(t theme.Theme) []string {
+	if util.ASCII {
+		return Line
+	}
+
 	// Try to get provider-specific spinner from ProviderTheme
 	if providerTheme, ok := t.(*theme.ProviderTheme); ok {
 		// Parse the LoadingSpinner string into individual rune frames
@@ -16,15 +16,17 @@ const (
 // providerBrandColors is a pre-computed map for O(1) color lookups
 // This optimization reduces repeated switch statement overhead
 var providerBrandColors = map[string]RGB{
-	"anthropic": {R: 224, G: 120, B: 86},  // Claude brand: warm orange/peach #E07856
-	"claude":    {R: 224, G: 120, B: 86},  // Claude brand: warm orange/peach #E07856
-	"google":    {R: 139, G: 127, B: 216}, // Gemini brand: blue-to-purple gradient #8B7FD8
-	"gemini":    {R: 139, G: 127, B: 216}, // Gemini brand: blue-to-purple gradient #8B7FD8
-	"openai":    {R: 16, G: 163, B: 127},  // OpenAI/Codex brand: teal/cyan #10A37F
-	"codex":     {R: 16, G: 163, B: 127},  // OpenAI/Codex brand: teal/cyan #10A37F
-	"xai":       {R: 255, G: 68, B: 68},   // Grok/xAI brand: red #FF4444
-	"grok":      {R: 255, G: 68, B: 68},   // Grok/xAI brand: red #FF4444
-	"qwen":      {R: 255, G: 167, B: 38},  // Qwen brand: golden orange #FFA726
+	"anthropic":      {R: 224, G: 120, B: 86},  // Claude brand: warm orange/peach #E07856
+	"claude":         {R: 224, G: 120, B: 86},  // Claude brand: warm orange/peach #E07856
+	"google":         {R: 139, G: 127, B: 216}, // Gemini brand: blue-to-purple gradient #8B7FD8
+	"gemini":         {R: 139, G: 127, B: 216}, // Gemini brand: blue-to-purple gradient #8B7FD8
+	"openai":         {R: 16, G: 163, B: 127},  // OpenAI/Codex brand: teal/cyan #10A37F
+	"codex":          {R: 16, G: 163, B: 127},  // OpenAI/Codex brand: teal/cyan #10A37F
+	"xai":            {R: 255, G: 68, B: 68},   // Grok/xAI brand: red #FF4444
+	"grok":           {R: 255, G: 68, B: 68},   // Grok/xAI brand: red #FF4444
+	"qwen":           {R: 255, G: 167, B: 38},  // Qwen brand: golden orange #FFA726
+	"azure":          {R: 0, G: 120, B: 212},   // Azure brand: blue #0078D4
+	"amazon-bedrock": {R: 255, G: 153, B: 0},   // AWS brand: orange #FF9900
 }
 
 // defaultBrandColor is the fallback color for unknown providers
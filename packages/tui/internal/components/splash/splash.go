@@ -20,6 +20,7 @@ type tickMsg time.Time
 
 const (
 	splashDuration = 4500 * time.Millisecond // Extended for better viewing
+	stillDuration  = 1500 * time.Millisecond // How long the still splash of reduced motion stays
 	tickInterval   = 50 * time.Millisecond
 	matrixChars    = "ﾊﾐﾋｰｳｼﾅﾓﾆｻﾜﾂｵﾘｱﾎﾃﾏｹﾒｴｶｷﾑﾕﾗｾﾈｽﾀﾇﾍ01"
 )
@@ -32,6 +33,8 @@ type Model struct {
 	fadeProgress    float64
 	cortexRenderer  *CortexRenderer
 	showCortex      bool  // Show cortex instead of matrix rain (first install)
+	brand           string // Brand color of the provider as hex, "" for RyCode's own colors
+	reducedMotion   bool   // One still frame, without rain or rotation
 }
 
 type rainColumn struct {
@@ -72,6 +75,7 @@ func New(width, height int) Model {
 	return Model{
 		width:          width,
 		height:         height,
+		startTime:      time.Now(),
 		rainColumns:    columns,
 		logoVisible:    false,
 		fadeProgress:   1.0, // Start fully visible - cortex + Matrix rain immediately
@@ -80,8 +84,31 @@ func New(width, height int) Model {
 	}
 }
 
+// SetProvider colors the cortex and logo with a provider's brand color, or
+// with RyCode's own when providerID is ""
+func (m *Model) SetProvider(providerID string) {
+	if providerID == "" {
+		m.cortexRenderer.ClearBrandColor()
+		m.brand = ""
+		return
+	}
+	color := GetProviderBrandColor(providerID)
+	m.cortexRenderer.SetBrandColor(color)
+	m.brand = color.ToHex()
+}
+
+// SetReducedMotion shows one still frame, logo and all, for a moment
+// instead of the animation
+func (m *Model) SetReducedMotion(on bool) {
+	m.reducedMotion = on
+	m.logoVisible = on
+}
+
 func (m Model) Init() tea.Cmd {
-	m.startTime = time.Now()
+	if m.reducedMotion {
+		m.cortexRenderer.RenderFrame()
+		return tea.Tick(stillDuration, func(time.Time) tea.Msg { return SplashFinishedMsg{} })
+	}
 	return tea.Batch(
 		tickCmd(),
 	)
@@ -176,7 +203,11 @@ func (m Model) View() string {
 	mediumGreen := "#00CC88"   // Medium cyan-green (matches RyCode logo)
 	darkGreen := "#008866"     // Darker green for depth
 
-	for _, col := range m.rainColumns {
+	rain := m.rainColumns
+	if m.reducedMotion {
+		rain = nil
+	}
+	for _, col := range rain {
 		for i, char := range col.chars {
 			y := col.y + i
 			if y >= 0 && y < m.height && col.x < m.width {
@@ -203,8 +234,10 @@ func (m Model) View() string {
 
 	// RENDER 3D CORTEX (if enabled) - positioned ABOVE logo
 	if m.showCortex && m.cortexRenderer != nil {
-		// Render the cortex frame
-		m.cortexRenderer.RenderFrame()
+		// Render the cortex frame, which reduced motion keeps still
+		if !m.reducedMotion {
+			m.cortexRenderer.RenderFrame()
+		}
 
 		// Calculate center position for cortex (upper part of screen)
 		cortexWidth := m.cortexRenderer.Width()
@@ -247,6 +280,10 @@ func (m Model) View() string {
 	}
 
 	tagline := "> Where Code Writes Itself"
+	logoColor := brightCyan
+	if m.brand != "" {
+		logoColor = m.brand
+	}
 
 	// Calculate center position for logo (lower part of screen, below cortex)
 	logoStartY := m.height*2/3
@@ -267,8 +304,9 @@ func (m Model) View() string {
 					if x >= 0 && x < m.width {
 						if char != ' ' {
 							canvas[y][x] = char
-							// Logo glows in Matrix green matching terminal branding
-							colors[y][x] = brightCyan
+							// Logo glows in the provider's color, or in Matrix
+							// green matching terminal branding
+							colors[y][x] = logoColor
 						}
 					}
 				}
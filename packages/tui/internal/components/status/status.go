@@ -1,9 +1,9 @@
 package status
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
@@ -15,6 +15,7 @@ import (
 	"github.com/aaronmrosenthal/rycode/internal/app"
 	"github.com/aaronmrosenthal/rycode/internal/commands"
 	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/remote"
 	"github.com/aaronmrosenthal/rycode/internal/styles"
 	"github.com/aaronmrosenthal/rycode/internal/theme"
 	"github.com/aaronmrosenthal/rycode/internal/util"
@@ -38,10 +39,12 @@ type statusComponent struct {
 	watcher    *fsnotify.Watcher
 	done       chan struct{}
 	lastUpdate time.Time
+	ticking    map[string]bool   // Widgets being redrawn every interval
+	polled     map[string]string // Latest results of the widgets' polls
 }
 
 func (m *statusComponent) Init() tea.Cmd {
-	return m.startGitWatcher()
+	return tea.Batch(m.startGitWatcher(), m.startWidgets())
 }
 
 func (m *statusComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -55,6 +58,18 @@ func (m *statusComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		// Continue watching for changes (persistent watcher)
 		return m, m.watchForGitChanges()
+	case LayoutChangedMsg:
+		return m, m.startWidgets()
+	case app.WorktreeSwitchedMsg:
+		if msg.Err != nil {
+			return m, nil
+		}
+		// Watch the branch of the worktree switched to
+		m.Cleanup()
+		m.cwd = displayPath(util.CwdPath)
+		return m, m.startGitWatcher()
+	case widgetTickMsg:
+		return m, m.handleWidgetTick(msg)
 	}
 	return m, nil
 }
@@ -101,7 +116,7 @@ func (m *statusComponent) logo() string {
 		Render(content)
 }
 
-func (m *statusComponent) collapsePath(path string, maxWidth int) string {
+func collapsePath(path string, maxWidth int) string {
 	if lipgloss.Width(path) <= maxWidth {
 		return path
 	}
@@ -140,6 +155,18 @@ func getProviderBrandColor(providerName string) compat.AdaptiveColor {
 	provider := strings.ToLower(providerName)
 
 	switch {
+	case strings.Contains(provider, "azure"):
+		// Azure OpenAI - Azure blue, before OpenAI as the name contains it
+		return compat.AdaptiveColor{
+			Dark:  lipgloss.Color("#0078D4"), // Azure blue
+			Light: lipgloss.Color("#005A9E"),
+		}
+	case strings.Contains(provider, "bedrock") || strings.Contains(provider, "aws"):
+		// AWS Bedrock - AWS orange
+		return compat.AdaptiveColor{
+			Dark:  lipgloss.Color("#FF9900"), // AWS orange
+			Light: lipgloss.Color("#CC7A00"),
+		}
 	case strings.Contains(provider, "anthropic") || strings.Contains(provider, "claude"):
 		// Anthropic Claude - Orange/Coral
 		return compat.AdaptiveColor{
@@ -179,147 +206,144 @@ func getProviderBrandColor(providerName string) compat.AdaptiveColor {
 	}
 }
 
-func (m *statusComponent) buildModelDisplay() string {
-	t := theme.CurrentTheme()
+// minCwdWidth is the least room given to the left side before its
+// widgets are dropped
+const minCwdWidth = 20
 
-	// Check if we have a model selected
-	if m.app.Model == nil || m.app.Provider == nil {
-		faintStyle := styles.NewStyle().
-			Faint(true).
-			Background(t.BackgroundPanel()).
-			Foreground(t.TextMuted())
-		noModelStyle := styles.NewStyle().
-			Background(t.BackgroundElement()).
-			Padding(0, 1).
-			BorderLeft(true).
-			BorderStyle(lipgloss.ThickBorder()).
-			BorderForeground(t.BackgroundElement()).
-			BorderBackground(t.BackgroundPanel())
-		return faintStyle.Render("  ") + noModelStyle.Render("No model")
+// agentCycleKey returns the key that cycles agents
+func (m *statusComponent) agentCycleKey() string {
+	command := m.app.Commands[commands.AgentCycleCommand]
+	if len(command.Keybindings) == 0 {
+		return ""
 	}
+	kb := command.Keybindings[0]
+	if kb.RequiresLeader {
+		return m.app.Config.Keybinds.Leader + " " + kb.Key
+	}
+	return kb.Key
+}
 
-	// Get provider brand color
-	brandColor := getProviderBrandColor(m.app.Provider.Name)
-
-	// Get cost (from cached value)
-	costStr := fmt.Sprintf("💰 $%.2f", m.app.CurrentCost)
+// renderRight draws the widgets on the right side in a pill of the
+// provider's brand color, dropping widgets from the end until it fits
+func (m *statusComponent) renderRight(names []string, maxWidth int) string {
+	t := theme.CurrentTheme()
 
-	// Check if cost data is stale (>10 seconds old)
-	if time.Since(m.app.LastCostUpdate) > 10*time.Second {
-		costStr = "💰 $--"
+	background := t.BackgroundElement()
+	key := ""
+	if m.app.Model != nil && m.app.Provider != nil {
+		background = getProviderBrandColor(m.app.Provider.Name)
+		key = m.agentCycleKey()
 	}
 
-	// Style definitions with brand color background
-	modelNameStyle := styles.NewStyle().
-		Background(brandColor).
+	style := styles.NewStyle().
+		Background(background).
 		Foreground(compat.AdaptiveColor{
 			Dark:  lipgloss.Color("#FFFFFF"), // White text on dark bg
 			Light: lipgloss.Color("#FFFFFF"), // White text on light bg
-		}).
-		Bold(true).
-		Render
-
-	costStyle := styles.NewStyle().
-		Background(brandColor).
-		Foreground(compat.AdaptiveColor{
-			Dark:  lipgloss.Color("#FFFFFF"),
-			Light: lipgloss.Color("#FFFFFF"),
-		}).
-		Render
-
-	hintStyle := styles.NewStyle().
-		Background(brandColor).
+		})
+	mutedStyle := style.
 		Foreground(compat.AdaptiveColor{
 			Dark:  lipgloss.Color("#E5E5E5"),
 			Light: lipgloss.Color("#F0F0F0"),
 		}).
-		Faint(true).
-		Render
+		Faint(true)
 
-	separatorStyle := styles.NewStyle().
-		Background(brandColor).
-		Foreground(compat.AdaptiveColor{
-			Dark:  lipgloss.Color("#E5E5E5"),
-			Light: lipgloss.Color("#F0F0F0"),
-		}).
-		Faint(true).
-		Render
+	var items []string
+	for _, name := range names {
+		if view := m.renderWidget(name, style, maxWidth); view != "" {
+			items = append(items, view)
+		}
+	}
+	if len(items) == 0 {
+		return ""
+	}
 
-	// Get keybinding for cycling
-	command := m.app.Commands[commands.AgentCycleCommand]
-	kb := command.Keybindings[0]
-	key := kb.Key
-	if kb.RequiresLeader {
-		key = m.app.Config.Keybinds.Leader + " " + kb.Key
-	}
-
-	// Build display: "Model Name | 💰 $0.12 | tab→"
-	modelName := modelNameStyle(m.app.Model.Name)
-	cost := costStyle(costStr)
-	hint := hintStyle(key + "→")
-	separator := separatorStyle(" | ")
-
-	var content string
-	if m.width > 80 {
-		// Full display with all info
-		content = modelName + separator + cost + separator + hint
-	} else if m.width > 60 {
-		// Hide hint, show model and cost
-		content = modelName + separator + cost
-	} else {
-		// Just show model name
-		content = modelName
-	}
-
-	// Add border and padding with brand color
-	faintStyle := styles.NewStyle().
-		Faint(true).
-		Background(t.BackgroundPanel()).
-		Foreground(t.TextMuted())
+	// The hint is dropped first: "Model Name | 💰 $0.12 | tab→"
+	prefix := ""
+	if key != "" {
+		items = append(items, mutedStyle.Render(key+"→"))
+		prefix = styles.NewStyle().
+			Faint(true).
+			Background(t.BackgroundPanel()).
+			Foreground(t.TextMuted()).
+			Render(key + " ")
+	}
 
 	displayStyle := styles.NewStyle().
-		Background(brandColor).
+		Background(background).
 		Padding(0, 1).
 		BorderLeft(true).
 		BorderStyle(lipgloss.ThickBorder()).
-		BorderForeground(brandColor).
+		BorderForeground(background).
 		BorderBackground(t.BackgroundPanel())
 
-	return faintStyle.Render(key+" ") + displayStyle.Render(content)
+	separator := mutedStyle.Render(" | ")
+	for {
+		view := prefix + displayStyle.Render(strings.Join(items, separator))
+		if len(items) == 1 || lipgloss.Width(view) <= maxWidth {
+			return view
+		}
+		items = items[:len(items)-1]
+	}
 }
 
-func (m *statusComponent) View() string {
+// renderLeft draws the widgets on the left side in the room left. The first
+// widget is given the room the others don't need, which the working
+// directory fills by collapsing its path, and the others are dropped from
+// the end when it would get less than minCwdWidth.
+func (m *statusComponent) renderLeft(names []string, room int) string {
 	t := theme.CurrentTheme()
-	logo := m.logo()
-	logoWidth := lipgloss.Width(logo)
-
-	// Build model display instead of agent display
-	modelDisplay := m.buildModelDisplay()
-	modelWidth := lipgloss.Width(modelDisplay)
-
-	availableWidth := m.width - logoWidth - modelWidth
-	branchSuffix := ""
-	if m.branch != "" {
-		branchSuffix = ":" + m.branch
+	style := styles.NewStyle().
+		Foreground(t.TextMuted()).
+		Background(t.BackgroundPanel())
+	separator := style.Faint(true).Render(" · ")
+
+	type item struct{ name, view string }
+	var items []item
+	for _, name := range names {
+		if view := m.renderWidget(name, style, room); view != "" {
+			items = append(items, item{name, view})
+		}
 	}
 
-	maxCwdWidth := availableWidth - lipgloss.Width(branchSuffix)
-	cwdDisplay := m.collapsePath(m.cwd, maxCwdWidth)
-
-	faintStyle := styles.NewStyle().
-		Faint(true).
-		Background(t.BackgroundPanel()).
-		Foreground(t.TextMuted())
-
-	if m.branch != "" && availableWidth > lipgloss.Width(cwdDisplay)+lipgloss.Width(branchSuffix) {
-		cwdDisplay += faintStyle.Render(branchSuffix)
+	for len(items) > 0 {
+		rest := 0
+		for _, item := range items[1:] {
+			rest += lipgloss.Width(separator) + lipgloss.Width(item.view)
+		}
+		if lipgloss.Width(items[0].view)+rest <= room {
+			break
+		}
+		if room-rest >= minCwdWidth || len(items) == 1 {
+			items[0].view = m.renderWidget(items[0].name, style, max(0, room-rest))
+			break
+		}
+		items = items[:len(items)-1]
 	}
 
-	cwd := styles.NewStyle().
+	views := make([]string, len(items))
+	for i, item := range items {
+		views[i] = item.view
+	}
+	return styles.NewStyle().
 		Foreground(t.TextMuted()).
 		Background(t.BackgroundPanel()).
 		Padding(0, 1).
-		Render(cwdDisplay)
+		Render(strings.Join(views, separator))
+}
+
+func (m *statusComponent) View() string {
+	t := theme.CurrentTheme()
+	logo := m.logo() + m.connection()
+	logoWidth := lipgloss.Width(logo)
+
+	widgets := Layout(m.app.State)
+	if m.app.Zoom() {
+		widgets = ZoomLayout
+	}
+	right := m.renderRight(widgets.Right, m.width-logoWidth-minCwdWidth)
+	// The padding around the left side takes two columns
+	left := m.renderLeft(widgets.Left, m.width-logoWidth-lipgloss.Width(right)-2)
 
 	background := t.BackgroundPanel()
 	status := layout.Render(
@@ -331,10 +355,10 @@ func (m *statusComponent) View() string {
 			Width:      m.width,
 		},
 		layout.FlexItem{
-			View: logo + cwd,
+			View: logo + left,
 		},
 		layout.FlexItem{
-			View: modelDisplay,
+			View: right,
 		},
 	)
 
@@ -342,6 +366,26 @@ func (m *statusComponent) View() string {
 	return blank + "\n" + status
 }
 
+// connection warns that the connection to the server is lost, whatever the
+// layout, while the stream is subscribed to again
+func (m *statusComponent) connection() string {
+	dropped, down := m.app.StreamDown()
+	if !down {
+		return ""
+	}
+	t := theme.CurrentTheme()
+	label := "⚠ offline, reconnecting"
+	if dropped.Attempt > 1 {
+		label += fmt.Sprintf(" (attempt %d)", dropped.Attempt)
+	}
+	return styles.NewStyle().
+		Foreground(t.Background()).
+		Background(t.Warning()).
+		Bold(true).
+		Padding(0, 1).
+		Render(label)
+}
+
 func (m *statusComponent) startGitWatcher() tea.Cmd {
 	cmd := util.CmdHandler(
 		GitBranchUpdatedMsg{Branch: getCurrentGitBranch(util.CwdPath)},
@@ -353,7 +397,7 @@ func (m *statusComponent) startGitWatcher() tea.Cmd {
 }
 
 func (m *statusComponent) initWatcher() error {
-	gitDir := filepath.Join(util.CwdPath, ".git")
+	gitDir := getGitDir(util.CwdPath)
 	headFile := filepath.Join(gitDir, "HEAD")
 	if info, err := os.Stat(gitDir); err != nil || !info.IsDir() {
 		return err
@@ -386,15 +430,17 @@ func (m *statusComponent) watchForGitChanges() tea.Cmd {
 	if m.watcher == nil {
 		return nil
 	}
+	// Held so that a watcher replaced by another stops here
+	watcher, done := m.watcher, m.done
 
 	return tea.Cmd(func() tea.Msg {
 		for {
 			select {
-			case event, ok := <-m.watcher.Events:
-				branch := getCurrentGitBranch(util.CwdPath)
+			case event, ok := <-watcher.Events:
 				if !ok {
-					return GitBranchUpdatedMsg{Branch: branch}
+					return nil
 				}
+				branch := getCurrentGitBranch(util.CwdPath)
 				if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) {
 					// Debounce updates to prevent excessive refreshes
 					now := time.Now()
@@ -407,10 +453,10 @@ func (m *statusComponent) watchForGitChanges() tea.Cmd {
 					}
 					return GitBranchUpdatedMsg{Branch: branch}
 				}
-			case <-m.watcher.Errors:
+			case <-watcher.Errors:
 				// Continue watching even on errors
-			case <-m.done:
-				return GitBranchUpdatedMsg{Branch: ""}
+			case <-done:
+				return nil
 			}
 		}
 	})
@@ -421,7 +467,7 @@ func (m *statusComponent) updateWatchedFiles() {
 		return
 	}
 	refFile := getGitRefFile(util.CwdPath)
-	headFile := filepath.Join(util.CwdPath, ".git", "HEAD")
+	headFile := filepath.Join(getGitDir(util.CwdPath), "HEAD")
 	if refFile != headFile && refFile != "" {
 		if _, err := os.Stat(refFile); err == nil {
 			// Try to add the new ref file (ignore error if already watching)
@@ -431,8 +477,7 @@ func (m *statusComponent) updateWatchedFiles() {
 }
 
 func getCurrentGitBranch(cwd string) string {
-	cmd := exec.Command("git", "branch", "--show-current")
-	cmd.Dir = cwd
+	cmd := remote.Command(context.Background(), cwd, "git", "branch", "--show-current")
 	output, err := cmd.Output()
 	if err != nil {
 		return ""
@@ -440,8 +485,27 @@ func getCurrentGitBranch(cwd string) string {
 	return strings.TrimSpace(string(output))
 }
 
+// getGitDir returns the git directory of a worktree, which for linked
+// worktrees and submodules is named by a .git file
+func getGitDir(cwd string) string {
+	gitDir := filepath.Join(cwd, ".git")
+	content, err := os.ReadFile(gitDir)
+	if err != nil {
+		return gitDir
+	}
+	dir, ok := strings.CutPrefix(strings.TrimSpace(string(content)), "gitdir: ")
+	if !ok {
+		return gitDir
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(cwd, dir)
+	}
+	return dir
+}
+
 func getGitRefFile(cwd string) string {
-	headFile := filepath.Join(cwd, ".git", "HEAD")
+	gitDir := getGitDir(cwd)
+	headFile := filepath.Join(gitDir, "HEAD")
 	content, err := os.ReadFile(headFile)
 	if err != nil {
 		return ""
@@ -451,7 +515,7 @@ func getGitRefFile(cwd string) string {
 	if after, ok := strings.CutPrefix(headContent, "ref: "); ok {
 		// HEAD points to a ref file
 		refPath := after
-		return filepath.Join(cwd, ".git", refPath)
+		return filepath.Join(gitDir, refPath)
 	}
 
 	// HEAD contains a direct commit hash
@@ -461,9 +525,11 @@ func getGitRefFile(cwd string) string {
 func (m *statusComponent) Cleanup() {
 	if m.done != nil {
 		close(m.done)
+		m.done = nil
 	}
 	if m.watcher != nil {
 		m.watcher.Close()
+		m.watcher = nil
 	}
 }
 
@@ -471,14 +537,20 @@ func NewStatusCmp(app *app.App) StatusComponent {
 	statusComponent := &statusComponent{
 		app:        app,
 		lastUpdate: time.Now(),
+		ticking:    make(map[string]bool),
+		polled:     make(map[string]string),
 	}
 
-	homePath, err := os.UserHomeDir()
-	cwdPath := util.CwdPath
-	if err == nil && homePath != "" && strings.HasPrefix(cwdPath, homePath) {
-		cwdPath = "~" + cwdPath[len(homePath):]
-	}
-	statusComponent.cwd = cwdPath
+	statusComponent.cwd = displayPath(util.CwdPath)
 
 	return statusComponent
 }
+
+// displayPath shortens a path under the home directory to start with ~
+func displayPath(path string) string {
+	homePath, err := os.UserHomeDir()
+	if err == nil && homePath != "" && strings.HasPrefix(path, homePath) {
+		return "~" + path[len(homePath):]
+	}
+	return path
+}
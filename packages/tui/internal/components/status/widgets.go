@@ -0,0 +1,535 @@
+package status
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/help"
+	"github.com/aaronmrosenthal/rycode/internal/components/sparkline"
+	"github.com/aaronmrosenthal/rycode/internal/components/textarea"
+	"github.com/aaronmrosenthal/rycode/internal/gitstatus"
+	"github.com/aaronmrosenthal/rycode/internal/plugin"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/target"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// Widget is an item of the status bar. The built-in widgets are registered
+// below; other components and plugins add their own with Register.
+type Widget struct {
+	Name        string
+	Description string
+	// Render draws the widget with ctx.Style, or returns "" to hide it
+	Render func(ctx Context) string
+	// Interval redraws the widget periodically, in step with the clock, for
+	// widgets that change without the app receiving a message
+	Interval time.Duration
+	// Poll, when set, runs every Interval off the UI goroutine. Its latest
+	// result is passed to Render as Context.Polled.
+	Poll func(a *app.App) string
+}
+
+// Context is what a widget is drawn from
+type Context struct {
+	App    *app.App
+	Cwd    string
+	Branch string
+	Polled string
+	Width  int          // Room left on the bar
+	Style  styles.Style // Colors of the side of the bar the widget is on
+}
+
+// LayoutChangedMsg tells the status bar that the widgets shown changed, so
+// it starts redrawing the ones with an interval
+type LayoutChangedMsg struct{}
+
+// widgetTickMsg is sent every interval of a widget that is shown
+type widgetTickMsg struct {
+	Name   string
+	Polled string
+}
+
+// DefaultLayout is shown until the layout is changed
+var DefaultLayout = app.StatusBarLayout{
+	Left:  []string{"cwd", "dir", "branch", "target", "language", "vim", "hint"},
+	Right: []string{"context", "model", "cost"},
+}
+
+// ZoomLayout is shown in low-vision zoom, whatever the layout, keeping the
+// directory, the context used and the model
+var ZoomLayout = app.StatusBarLayout{
+	Left:  []string{"cwd"},
+	Right: []string{"context", "model"},
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Widget{}
+)
+
+// Register adds a widget to those that can be shown, replacing any widget
+// with the same name
+func Register(w Widget) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[w.Name] = w
+}
+
+// Widgets returns the registered widgets, sorted by name
+func Widgets() []Widget {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	widgets := make([]Widget, 0, len(registry))
+	for _, w := range registry {
+		widgets = append(widgets, w)
+	}
+	sort.Slice(widgets, func(i, j int) bool { return widgets[i].Name < widgets[j].Name })
+	return widgets
+}
+
+func lookup(name string) (Widget, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	w, ok := registry[name]
+	return w, ok
+}
+
+// pluginWidgetInterval is how often a plugin's widget is redrawn when it
+// doesn't say
+const pluginWidgetInterval = 30 * time.Second
+
+// PluginWidget is a widget drawn by a plugin, named after the plugin. Its
+// text is polled from the plugin every interval.
+func PluginWidget(name string, w plugin.Widget) Widget {
+	interval := time.Duration(w.Interval) * time.Second
+	if interval <= 0 {
+		interval = pluginWidgetInterval
+	}
+	description := w.Description
+	if description == "" {
+		description = "From the " + name + " plugin"
+	}
+	return Widget{
+		Name:        name + "." + w.Name,
+		Description: description,
+		Interval:    interval,
+		Poll: func(a *app.App) string {
+			return a.PluginView(name, w.Name)
+		},
+		Render: func(ctx Context) string {
+			if ctx.Polled == "" {
+				return ""
+			}
+			return ctx.Style.Render(ansi.Truncate(ctx.Polled, ctx.Width, "…"))
+		},
+	}
+}
+
+// Layout returns the widgets shown on each side of the status bar
+func Layout(state *app.State) app.StatusBarLayout {
+	if state == nil || state.StatusBar == nil {
+		return DefaultLayout
+	}
+	return *state.StatusBar
+}
+
+// Configure changes the layout of the status bar as /statusbar args asks,
+// and describes the result. Widget names that are not registered are
+// refused, although the saved layout may name widgets of plugins that
+// are not loaded, which are skipped.
+func Configure(state *app.State, args string) (changed bool, summary string, err error) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 || fields[0] == "list" {
+		return false, describeLayout(Layout(state)), nil
+	}
+	current := Layout(state)
+	next := app.StatusBarLayout{Left: slices.Clone(current.Left), Right: slices.Clone(current.Right)}
+	names := fields[1:]
+
+	switch fields[0] {
+	case "reset":
+		state.StatusBar = nil
+		return true, describeLayout(DefaultLayout), nil
+	case "left", "right":
+		if err := checkWidgets(names); err != nil {
+			return false, "", err
+		}
+		if fields[0] == "left" {
+			next.Left = slices.Clone(names)
+		} else {
+			next.Right = slices.Clone(names)
+		}
+	case "enable":
+		side := "right"
+		if len(names) == 2 && (names[1] == "left" || names[1] == "right") {
+			side, names = names[1], names[:1]
+		}
+		if len(names) != 1 {
+			return false, "", fmt.Errorf("usage: /statusbar enable <widget> [left|right]")
+		}
+		if err := checkWidgets(names); err != nil {
+			return false, "", err
+		}
+		// Enabling a widget that is shown moves it to the end of the side
+		name := names[0]
+		next.Left = slices.DeleteFunc(next.Left, func(n string) bool { return n == name })
+		next.Right = slices.DeleteFunc(next.Right, func(n string) bool { return n == name })
+		if side == "left" {
+			next.Left = append(next.Left, name)
+		} else {
+			next.Right = append(next.Right, name)
+		}
+	case "disable":
+		if len(names) == 0 {
+			return false, "", fmt.Errorf("usage: /statusbar disable <widget>...")
+		}
+		disabled := func(n string) bool { return slices.Contains(names, n) }
+		next.Left = slices.DeleteFunc(next.Left, disabled)
+		next.Right = slices.DeleteFunc(next.Right, disabled)
+	default:
+		return false, "", fmt.Errorf("usage: /statusbar [left|right <widget>...] [enable|disable <widget>] [reset]")
+	}
+	state.StatusBar = &next
+	return true, describeLayout(next), nil
+}
+
+func checkWidgets(names []string) error {
+	for _, name := range names {
+		if _, ok := lookup(name); !ok {
+			return fmt.Errorf("unknown widget %q, available: %s", name, widgetNames())
+		}
+	}
+	return nil
+}
+
+func describeLayout(l app.StatusBarLayout) string {
+	side := func(names []string) string {
+		if len(names) == 0 {
+			return "(none)"
+		}
+		return strings.Join(names, " ")
+	}
+	return fmt.Sprintf("Left: %s\nRight: %s\nAvailable: %s", side(l.Left), side(l.Right), widgetNames())
+}
+
+func widgetNames() string {
+	var names []string
+	for _, w := range Widgets() {
+		names = append(names, w.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// startWidgets starts the redraws of the widgets shown that have an
+// interval and are not redrawing already
+func (m *statusComponent) startWidgets() tea.Cmd {
+	var cmds []tea.Cmd
+	layout := Layout(m.app.State)
+	for _, name := range append(slices.Clone(layout.Left), layout.Right...) {
+		w, ok := lookup(name)
+		if !ok || w.Interval <= 0 || m.ticking[name] {
+			continue
+		}
+		m.ticking[name] = true
+		if w.Poll != nil {
+			a := m.app
+			cmds = append(cmds, func() tea.Msg {
+				return widgetTickMsg{Name: w.Name, Polled: w.Poll(a)}
+			})
+			continue
+		}
+		cmds = append(cmds, m.widgetTick(w))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *statusComponent) widgetTick(w Widget) tea.Cmd {
+	a := m.app
+	return tea.Every(w.Interval, func(time.Time) tea.Msg {
+		msg := widgetTickMsg{Name: w.Name}
+		if w.Poll != nil {
+			msg.Polled = w.Poll(a)
+		}
+		return msg
+	})
+}
+
+// handleWidgetTick keeps the result of a poll and schedules the next redraw,
+// unless the widget is no longer shown
+func (m *statusComponent) handleWidgetTick(msg widgetTickMsg) tea.Cmd {
+	layout := Layout(m.app.State)
+	w, ok := lookup(msg.Name)
+	if !ok || w.Interval <= 0 || (!slices.Contains(layout.Left, msg.Name) && !slices.Contains(layout.Right, msg.Name)) {
+		delete(m.ticking, msg.Name)
+		delete(m.polled, msg.Name)
+		return nil
+	}
+	if w.Poll != nil {
+		m.polled[msg.Name] = msg.Polled
+	}
+	return m.widgetTick(w)
+}
+
+// renderWidget draws a widget, or returns "" when it is not registered
+func (m *statusComponent) renderWidget(name string, style styles.Style, width int) string {
+	w, ok := lookup(name)
+	if !ok || w.Render == nil {
+		return ""
+	}
+	return w.Render(Context{
+		App:    m.app,
+		Cwd:    m.cwd,
+		Branch: m.branch,
+		Polled: m.polled[name],
+		Width:  width,
+		Style:  style,
+	})
+}
+
+// The context gauge is this many cells wide, and turns from green to yellow
+// at contextWarning and to red where compacting is suggested
+const (
+	contextGaugeCells = 8
+	contextWarning    = 0.6
+)
+
+// contextGauge draws the share of the context window used as the filled
+// and empty cells of a gauge
+func contextGauge(share float64, cells int) (filled, empty string) {
+	n := min(cells, max(0, int(share*float64(cells)+0.5)))
+	// Any use shows at least one cell
+	if n == 0 && share > 0 {
+		n = 1
+	}
+	return strings.Repeat("▰", n), strings.Repeat("▱", cells-n)
+}
+
+// spendDays is the number of days the spend sparkline covers, two a cell
+const spendDays = 14
+
+// gitPollInterval is how often the branch widget reads the git status
+const gitPollInterval = 5 * time.Second
+
+func formatTokens(tokens float64) string {
+	var formatted string
+	switch {
+	case tokens >= 1_000_000:
+		formatted = fmt.Sprintf("%.1fM", tokens/1_000_000)
+	case tokens >= 1_000:
+		formatted = fmt.Sprintf("%.1fK", tokens/1_000)
+	default:
+		return fmt.Sprintf("%d", int(tokens))
+	}
+	return strings.Replace(formatted, ".0", "", 1)
+}
+
+func init() {
+	for _, w := range []Widget{
+		{
+			Name:        "cwd",
+			Description: "Working directory",
+			Render: func(ctx Context) string {
+				return ctx.Style.Render(collapsePath(ctx.Cwd, ctx.Width))
+			},
+		},
+		{
+			Name:        "dir",
+			Description: "Directory the session's shell commands run in, set with /cd",
+			Render: func(ctx Context) string {
+				dir := ctx.App.SessionDir()
+				if dir == "" {
+					return ""
+				}
+				return ctx.Style.Render("↳ " + collapsePath(dir, ctx.Width-2))
+			},
+		},
+		{
+			Name:        "branch",
+			Description: "Git branch, with its staged (+), unstaged (~) and untracked (?) files and commits ahead (↑) and behind (↓)",
+			Interval:    gitPollInterval,
+			Poll: func(a *app.App) string {
+				ctx, cancel := context.WithTimeout(context.Background(), gitPollInterval)
+				defer cancel()
+				status, err := gitstatus.Read(ctx, util.RootPath)
+				if err != nil {
+					return ""
+				}
+				return status.Indicators()
+			},
+			Render: func(ctx Context) string {
+				if ctx.Branch == "" {
+					return ""
+				}
+				view := ctx.Style.Faint(true).Render(ctx.Branch)
+				if ctx.Polled != "" {
+					view += ctx.Style.Render(" " + ctx.Polled)
+				}
+				return view
+			},
+		},
+		{
+			Name:        "target",
+			Description: "Container commands run in",
+			Render: func(ctx Context) string {
+				container := target.Active()
+				if container == nil {
+					return ""
+				}
+				return ctx.Style.Render("⬢ " + container.Label())
+			},
+		},
+		{
+			Name:        "language",
+			Description: "Language the assistant is made to answer in",
+			Render: func(ctx Context) string {
+				language, session := ctx.App.ResponseLanguage()
+				if language == "" {
+					return ""
+				}
+				view := ctx.Style.Render("🌐 " + language)
+				if session {
+					view += ctx.Style.Faint(true).Render(" this session")
+				}
+				return view
+			},
+		},
+		{
+			Name:        "vim",
+			Description: "Mode of the prompt's vim keybindings",
+			Render: func(ctx Context) string {
+				if ctx.App.VimMode == textarea.VimOff {
+					return ""
+				}
+				return ctx.Style.Bold(true).Render("-- " + ctx.App.VimMode.String() + " --")
+			},
+		},
+		{
+			Name:        "hint",
+			Description: "Tip for what the app is showing, /hints dismiss hides it",
+			Render: func(ctx Context) string {
+				// The home screen shows the hint itself
+				if ctx.App.Session.ID == "" {
+					return ""
+				}
+				_, hint := ctx.App.Hint()
+				return ansi.Truncate(help.RenderInlineHint(hint, ctx.Style), ctx.Width, "…")
+			},
+		},
+		{
+			Name:        "model",
+			Description: "Current model",
+			Render: func(ctx Context) string {
+				if ctx.App.Model == nil || ctx.App.Provider == nil {
+					return ctx.Style.Render("No model")
+				}
+				rule := ctx.App.AppliedRule
+				if rule == nil {
+					return ctx.Style.Bold(true).Render(ctx.App.Model.Name)
+				}
+				// A path rule overrode the model or agent of the last prompt
+				name := ctx.App.Model.Name
+				if rule.Model != nil {
+					name = rule.Model.Name
+				}
+				override := "⇢ " + ansi.Truncate(rule.Rule.Label(), 24, "…")
+				if rule.Agent != "" {
+					override += " · " + rule.Agent
+				}
+				return ctx.Style.Bold(true).Render(name) + ctx.Style.Faint(true).Render(" "+override)
+			},
+		},
+		{
+			Name:        "cost",
+			Description: "Cost so far today",
+			// Redrawn to show when the cost is stale
+			Interval: 10 * time.Second,
+			Render: func(ctx Context) string {
+				if ctx.App.Model == nil || ctx.App.Provider == nil {
+					return ""
+				}
+				// The cost is cached, and stale after 10 seconds
+				if time.Since(ctx.App.LastCostUpdate) > 10*time.Second {
+					return ctx.Style.Render("💰 $--")
+				}
+				return ctx.Style.Render(fmt.Sprintf("💰 $%.2f", ctx.App.CurrentCost))
+			},
+		},
+		{
+			Name:        "spend",
+			Description: "Sparkline of the daily cost over the last two weeks",
+			Render: func(ctx Context) string {
+				costs := ctx.App.UsageInsights().GetDailyCosts(spendDays)
+				if !slices.ContainsFunc(costs, func(cost float64) bool { return cost > 0 }) {
+					return ""
+				}
+				return ctx.Style.Render(sparkline.Line(costs)) + ctx.Style.Faint(true).Render(fmt.Sprintf(" %dd", spendDays))
+			},
+		},
+		{
+			Name:        "tokens",
+			Description: "Tokens in the session's context",
+			Render: func(ctx Context) string {
+				tokens := app.ContextTokens(ctx.App.Messages)
+				if tokens == 0 {
+					return ""
+				}
+				return ctx.Style.Render(formatTokens(tokens) + " tokens")
+			},
+		},
+		{
+			Name:        "context",
+			Description: "Gauge of the model's context window used",
+			Render: func(ctx Context) string {
+				tokens, share := ctx.App.ContextUsage()
+				if share == 0 {
+					return ""
+				}
+				t := theme.CurrentTheme()
+				color := t.Success()
+				switch {
+				case share >= app.CompactThreshold:
+					color = t.Error()
+				case share >= contextWarning:
+					color = t.Warning()
+				}
+				label := ctx.Style.Foreground(color).Render(fmt.Sprintf(" %d%%", int(share*100)))
+				if ctx.Width < contextGaugeCells+12 {
+					return label
+				}
+				filled, empty := contextGauge(share, contextGaugeCells)
+				gauge := ctx.Style.Foreground(color).Render(filled) + ctx.Style.Faint(true).Render(empty)
+				return gauge + label + ctx.Style.Faint(true).Render(" "+formatTokens(tokens))
+			},
+		},
+		{
+			Name:        "agent",
+			Description: "Current agent",
+			Render: func(ctx Context) string {
+				agent := ctx.App.Agent()
+				if agent == nil {
+					return ""
+				}
+				return ctx.Style.Render(agent.Name)
+			},
+		},
+		{
+			Name:        "time",
+			Description: "Time of day",
+			Interval:    time.Minute,
+			Render: func(ctx Context) string {
+				return ctx.Style.Render(time.Now().Format("15:04"))
+			},
+		},
+	} {
+		Register(w)
+	}
+}
@@ -0,0 +1,82 @@
+package status
+
+import (
+	"slices"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/aaronmrosenthal/rycode/internal/app"
+)
+
+func TestConfigure(t *testing.T) {
+	state := app.NewState()
+	steps := []struct {
+		args        string
+		left, right []string
+	}{
+		{"enable time", []string{"cwd", "dir", "branch", "target", "language", "vim", "hint"}, []string{"context", "model", "cost", "time"}},
+		{"enable model left", []string{"cwd", "dir", "branch", "target", "language", "vim", "hint", "model"}, []string{"context", "cost", "time"}},
+		{"disable dir branch target language vim hint cost", []string{"cwd", "model"}, []string{"context", "time"}},
+		{"right agent tokens context", []string{"cwd", "model"}, []string{"agent", "tokens", "context"}},
+		{"left", nil, []string{"agent", "tokens", "context"}},
+	}
+	for _, step := range steps {
+		changed, _, err := Configure(state, step.args)
+		if err != nil || !changed {
+			t.Fatalf("Configure(%q) = %v, %v", step.args, changed, err)
+		}
+		if !slices.Equal(state.StatusBar.Left, step.left) || !slices.Equal(state.StatusBar.Right, step.right) {
+			t.Errorf("after %q layout = %+v, want left %q right %q", step.args, *state.StatusBar, step.left, step.right)
+		}
+	}
+
+	for _, args := range []string{"enable nope", "right model nope", "enable", "disable", "shuffle"} {
+		if _, _, err := Configure(state, args); err == nil {
+			t.Errorf("Configure(%q) succeeded, want an error", args)
+		}
+	}
+	if changed, _, _ := Configure(state, ""); changed {
+		t.Error("listing the layout changed it")
+	}
+	if _, _, err := Configure(state, "reset"); err != nil || state.StatusBar != nil {
+		t.Errorf("reset left %+v, %v", state.StatusBar, err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register(Widget{Name: "zz-test", Render: func(ctx Context) string { return ctx.Polled }})
+	widgets := Widgets()
+	if last := widgets[len(widgets)-1]; last.Name != "zz-test" {
+		t.Fatalf("last widget = %q, want the registered one", last.Name)
+	}
+	if _, _, err := Configure(app.NewState(), "enable zz-test"); err != nil {
+		t.Errorf("enabling a registered widget: %v", err)
+	}
+}
+
+func TestFormatTokens(t *testing.T) {
+	tests := map[float64]string{999: "999", 1_000: "1K", 12_345: "12.3K", 2_000_000: "2M", 1_250_000: "1.2M"}
+	for tokens, want := range tests {
+		if got := formatTokens(tokens); got != want {
+			t.Errorf("formatTokens(%v) = %q, want %q", tokens, got, want)
+		}
+	}
+}
+
+func TestContextGauge(t *testing.T) {
+	tests := []struct {
+		share         float64
+		filled, empty int
+	}{
+		{0.01, 1, 7},
+		{0.5, 4, 4},
+		{0.8, 6, 2},
+		{1.3, 8, 0},
+	}
+	for _, test := range tests {
+		filled, empty := contextGauge(test.share, 8)
+		if utf8.RuneCountInString(filled) != test.filled || utf8.RuneCountInString(empty) != test.empty {
+			t.Errorf("contextGauge(%v) = %q, %q", test.share, filled, empty)
+		}
+	}
+}
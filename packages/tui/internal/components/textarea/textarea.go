@@ -112,21 +112,28 @@ func (m Model) isAttachmentAtCursor() (*attachment.Attachment, int, int) {
 	return nil, -1, -1
 }
 
-// renderLineWithAttachments renders a line with proper attachment highlighting
+// renderLineWithAttachments renders a line with proper attachment highlighting.
+// The items start at col of the given row, for the visual selection.
 func (m Model) renderLineWithAttachments(
 	items []any,
 	style lipgloss.Style,
+	row, col int,
 ) string {
 	var s strings.Builder
 	currentAttachment, _, _ := m.isAttachmentAtCursor()
 
-	for _, item := range items {
+	for i, item := range items {
+		selected := m.vimSelected(row, col+i)
 		switch val := item.(type) {
 		case rune:
-			s.WriteString(style.Render(string(val)))
+			if selected {
+				s.WriteString(m.Styles.Selection.Render(string(val)))
+			} else {
+				s.WriteString(style.Render(string(val)))
+			}
 		case *attachment.Attachment:
 			// Check if this is the attachment the cursor is currently on
-			if currentAttachment != nil && currentAttachment.ID == val.ID {
+			if selected || currentAttachment != nil && currentAttachment.ID == val.ID {
 				// Cursor is on this attachment, highlight it
 				s.WriteString(m.Styles.SelectedAttachment.Render(val.Display))
 			} else {
@@ -362,6 +369,7 @@ type Styles struct {
 	Cursor             CursorStyle
 	Attachment         lipgloss.Style
 	SelectedAttachment lipgloss.Style
+	Selection          lipgloss.Style // Text selected in the vim visual modes
 }
 
 // StyleState that will be applied to the text area.
@@ -522,6 +530,9 @@ type Model struct {
 
 	// rune sanitizer for input.
 	rsan Sanitizer
+
+	// vim holds the state of the vim keybindings, when enabled.
+	vim vimState
 }
 
 // New creates a new model with default settings.
@@ -596,6 +607,7 @@ func DefaultStyles(isDark bool) Styles {
 	s.SelectedAttachment = lipgloss.NewStyle().
 		Background(lipgloss.Color("11")).
 		Foreground(lipgloss.Color("0"))
+	s.Selection = lipgloss.NewStyle().Reverse(true)
 	s.Cursor = CursorStyle{
 		Color: lipgloss.Color("7"),
 		Shape: tea.CursorBlock,
@@ -905,6 +917,16 @@ func (m Model) CursorColumn() int {
 	return m.col
 }
 
+// AtWordStart reports whether the cursor is at the start of a line or after
+// a space or an attachment, where a word such as a mention begins
+func (m Model) AtWordStart() bool {
+	if m.row >= len(m.value) || m.col == 0 || m.col > len(m.value[m.row]) {
+		return true
+	}
+	r, ok := m.value[m.row][m.col-1].(rune)
+	return !ok || unicode.IsSpace(r)
+}
+
 // LastRuneIndex returns the index of the last occurrence of a rune on the current line,
 // searching backwards from the current cursor position.
 // Returns -1 if the rune is not found before the cursor.
@@ -1583,6 +1605,10 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyPressMsg:
+		if m.VimKey(msg) {
+			m.vimKeyPress(msg)
+			break
+		}
 		switch {
 		case key.Matches(msg, m.KeyMap.DeleteAfterCursor):
 			m.col = clamp(m.col, 0, len(m.value[m.row]))
@@ -1722,6 +1748,7 @@ func (m Model) View() string {
 			style = styles.computedText()
 		}
 
+		offset := 0
 		for wl, wrappedLine := range wrappedLines {
 			prompt := m.promptView(displayLine)
 			prompt = styles.computedPrompt().Render(prompt)
@@ -1766,6 +1793,7 @@ func (m Model) View() string {
 					m.renderLineWithAttachments(
 						wrappedLine[:lineInfo.ColumnOffset],
 						style,
+						l, offset,
 					),
 				)
 
@@ -1786,15 +1814,16 @@ func (m Model) View() string {
 					}
 
 					// Render the part of the line after the cursor
-					s.WriteString(m.renderLineWithAttachments(wrappedLine[lineInfo.ColumnOffset+1:], style))
+					s.WriteString(m.renderLineWithAttachments(wrappedLine[lineInfo.ColumnOffset+1:], style, l, offset+lineInfo.ColumnOffset+1))
 				} else {
 					// Cursor is at the end of the line
 					m.virtualCursor.SetChar(" ")
 					s.WriteString(style.Render(m.virtualCursor.View()))
 				}
 			} else {
-				s.WriteString(m.renderLineWithAttachments(wrappedLine, style))
+				s.WriteString(m.renderLineWithAttachments(wrappedLine, style, l, offset))
 			}
+			offset += len(wrappedLine)
 
 			s.WriteString(style.Render(strings.Repeat(" ", max(0, padding))))
 			s.WriteRune('\n')
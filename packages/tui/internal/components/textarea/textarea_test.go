@@ -73,3 +73,22 @@ func TestRemoveAttachmentAtCursor_NoAttachment_NoChange(t *testing.T) {
 		t.Fatalf("value or cursor unexpectedly changed")
 	}
 }
+
+func TestAtWordStart(t *testing.T) {
+	m := New()
+	if !m.AtWordStart() {
+		t.Error("an empty prompt isn't at a word start")
+	}
+	m.InsertString("mail me at bob")
+	if m.AtWordStart() {
+		t.Error("the middle of a word is at a word start")
+	}
+	m.InsertString(" ")
+	if !m.AtWordStart() {
+		t.Error("after a space isn't at a word start")
+	}
+	m.InsertAttachment(&attachment.Attachment{ID: "1", Display: "@main.go"})
+	if !m.AtWordStart() {
+		t.Error("after an attachment isn't at a word start")
+	}
+}
@@ -0,0 +1,890 @@
+package textarea
+
+import (
+	"slices"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/attachment"
+)
+
+// VimMode is the mode of the vim keybindings, which are off unless enabled
+// with SetVim
+type VimMode int
+
+const (
+	VimOff VimMode = iota
+	VimInsert
+	VimNormal
+	VimVisual
+	VimVisualLine
+)
+
+// String returns the mode as vim shows it, or nothing when the keybindings
+// are off
+func (v VimMode) String() string {
+	switch v {
+	case VimInsert:
+		return "INSERT"
+	case VimNormal:
+		return "NORMAL"
+	case VimVisual:
+		return "VISUAL"
+	case VimVisualLine:
+		return "VISUAL LINE"
+	}
+	return ""
+}
+
+// vimUndoLimit bounds the number of changes u can undo
+const vimUndoLimit = 100
+
+// vimKind is how a motion selects the text an operator works on
+type vimKind int
+
+const (
+	vimExclusive vimKind = iota // Up to the position the motion moves to
+	vimInclusive                // Up to and including it
+	vimLinewise                 // The whole lines in between
+)
+
+type position struct {
+	row, col int
+}
+
+func (p position) before(o position) bool {
+	return p.row < o.row || p.row == o.row && p.col < o.col
+}
+
+// vimRegister holds yanked or deleted text. Linewise text is put on lines of
+// its own.
+type vimRegister struct {
+	lines    [][]any
+	linewise bool
+}
+
+// append adds text to the register, as yanking into an uppercase register
+// does
+func (r vimRegister) append(o vimRegister) vimRegister {
+	lines := slices.Clone(r.lines)
+	if r.linewise || o.linewise {
+		return vimRegister{lines: append(lines, o.lines...), linewise: true}
+	}
+	last := len(lines) - 1
+	lines[last] = append(copyInterfaceSlice(lines[last]), o.lines[0]...)
+	return vimRegister{lines: append(lines, o.lines[1:]...)}
+}
+
+type vimSnapshot struct {
+	value    [][]any
+	row, col int
+}
+
+type vimState struct {
+	mode      VimMode
+	pending   []rune   // Keys of an unfinished command, such as "a2d
+	anchor    position // Where the visual selection started
+	registers map[rune]vimRegister
+	undo      []vimSnapshot
+}
+
+// SetVim turns the vim keybindings on, starting in the insert mode, or off
+func (m *Model) SetVim(enabled bool) {
+	if !enabled {
+		m.vim = vimState{}
+		return
+	}
+	if m.vim.mode == VimOff {
+		m.vim = vimState{registers: make(map[rune]vimRegister)}
+		m.vimCheckpoint()
+		m.vimInsert()
+	}
+}
+
+// VimMode returns the mode of the vim keybindings
+func (m Model) VimMode() VimMode {
+	return m.vim.mode
+}
+
+// VimInsert switches the vim keybindings to the insert mode, as for a new
+// prompt. The registers and the undo history are kept.
+func (m *Model) VimInsert() {
+	if m.vim.mode == VimOff {
+		return
+	}
+	m.vimDropUnchanged()
+	m.vimCheckpoint()
+	m.vimInsert()
+}
+
+// vimInsert enters the insert mode, where the text typed is undone together
+// with the checkpoint before it
+func (m *Model) vimInsert() {
+	m.vim.pending = nil
+	m.vim.mode = VimInsert
+}
+
+// VimKey reports whether the vim keybindings take a key, which Update then
+// handles as a vim command. The insert mode only takes esc; the normal and
+// visual modes take esc, backspace and every key that types text. A lone
+// esc in the normal mode is left to the caller, to interrupt a session.
+func (m Model) VimKey(msg tea.KeyPressMsg) bool {
+	switch m.vim.mode {
+	case VimOff:
+		return false
+	case VimInsert:
+		return msg.String() == "esc"
+	}
+	switch msg.String() {
+	case "esc":
+		return m.vim.mode != VimNormal || len(m.vim.pending) > 0
+	case "backspace":
+		return true
+	}
+	return msg.Text != ""
+}
+
+// vimKeyPress handles a key taken by VimKey
+func (m *Model) vimKeyPress(msg tea.KeyPressMsg) {
+	if m.vim.mode == VimInsert {
+		m.vim.mode = VimNormal
+		m.vimDropUnchanged()
+		m.SetCursorColumn(m.col - 1)
+		return
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.vim.pending = nil
+		m.vim.mode = VimNormal
+		m.vimClamp()
+		return
+	case "backspace":
+		msg.Text = "h"
+	}
+
+	if m.vim.mode == VimNormal && len(m.vim.pending) == 0 && msg.Text == "u" {
+		m.vimUndo()
+		m.vimClamp()
+		return
+	}
+
+	// Every command is a checkpoint, dropped again when nothing changed.
+	// Commands entering the insert mode keep theirs, so the text typed is
+	// undone with the command.
+	m.vimCheckpoint()
+	m.vim.pending = append(m.vim.pending, []rune(msg.Text)...)
+	if m.vimRun(m.vim.pending) {
+		m.vim.pending = nil
+	}
+	if m.vim.mode != VimInsert {
+		m.vimDropUnchanged()
+		m.vimClamp()
+	}
+}
+
+// vimRun runs the command typed so far in the normal or visual mode. It
+// returns false while the command needs more keys.
+func (m *Model) vimRun(keys []rune) bool {
+	register := '"'
+	if keys[0] == '"' {
+		if len(keys) < 2 {
+			return false
+		}
+		register = keys[1]
+		if !vimRegisterName(register) {
+			return true
+		}
+		keys = keys[2:]
+	}
+	count, keys := vimCount(keys)
+	if len(keys) == 0 {
+		return false
+	}
+	if m.vim.mode != VimNormal {
+		return m.vimVisualKey(keys, count, register)
+	}
+
+	// Shorthands for an operator and a motion
+	if alias, ok := map[rune]string{
+		'x': "dl", 'X': "dh", 'D': "d$", 'C': "c$", 's': "cl", 'S': "cc", 'Y': "yy",
+	}[keys[0]]; ok {
+		keys = append([]rune(alias), keys[1:]...)
+	}
+
+	n := max(1, count)
+	switch keys[0] {
+	case 'i':
+		m.vimInsert()
+	case 'a':
+		if len(m.value[m.row]) > 0 {
+			m.SetCursorColumn(m.col + 1)
+		}
+		m.vimInsert()
+	case 'I':
+		m.SetCursorColumn(m.firstNonBlank(m.row))
+		m.vimInsert()
+	case 'A':
+		m.CursorEnd()
+		m.vimInsert()
+	case 'o':
+		m.value = slices.Insert(m.value, m.row+1, []any{})
+		m.row++
+		m.SetCursorColumn(0)
+		m.vimInsert()
+	case 'O':
+		m.value = slices.Insert(m.value, m.row, []any{})
+		m.SetCursorColumn(0)
+		m.vimInsert()
+	case 'v':
+		m.vim.anchor = position{m.row, m.col}
+		m.vim.mode = VimVisual
+	case 'V':
+		m.vim.anchor = position{m.row, m.col}
+		m.vim.mode = VimVisualLine
+	case 'd', 'c', 'y':
+		return m.vimOperator(keys[0], keys[1:], count, register)
+	case 'p', 'P':
+		m.vimPut(register, keys[0] == 'p', n)
+	case 'r':
+		if len(keys) < 2 {
+			return false
+		}
+		line := m.value[m.row]
+		if m.col+n > len(line) {
+			return true
+		}
+		for i := range n {
+			line[m.col+i] = keys[1]
+		}
+		m.SetCursorColumn(m.col + n - 1)
+	case 'J':
+		for range max(1, n-1) {
+			m.vimJoin()
+		}
+	default:
+		to, _, more, ok := m.vimMotion(keys, count)
+		if more {
+			return false
+		}
+		if ok {
+			m.row = to.row
+			m.SetCursorColumn(to.col)
+		}
+	}
+	return true
+}
+
+// vimVisualKey runs a command of the visual modes, where motions move the
+// cursor and operators work on the selection
+func (m *Model) vimVisualKey(keys []rune, count int, register rune) bool {
+	switch keys[0] {
+	case 'v', 'V':
+		mode := VimVisual
+		if keys[0] == 'V' {
+			mode = VimVisualLine
+		}
+		if m.vim.mode == mode {
+			mode = VimNormal
+		}
+		m.vim.mode = mode
+	case 'o':
+		anchor := m.vim.anchor
+		m.vim.anchor = position{m.row, m.col}
+		m.row = anchor.row
+		m.SetCursorColumn(anchor.col)
+	case 'd', 'x', 'c', 's', 'y':
+		from, to := m.vimSelection()
+		kind := vimExclusive
+		if m.vim.mode == VimVisualLine {
+			kind = vimLinewise
+		}
+		op := map[rune]rune{'x': 'd', 's': 'c'}[keys[0]]
+		if op == 0 {
+			op = keys[0]
+		}
+		m.vim.mode = VimNormal
+		m.vimOperate(op, register, from, to, kind)
+	default:
+		to, _, more, ok := m.vimMotion(keys, count)
+		if more {
+			return false
+		}
+		if ok {
+			m.row = to.row
+			m.SetCursorColumn(to.col)
+		}
+	}
+	return true
+}
+
+// vimOperator runs an operator on the text a motion or text object selects,
+// or on whole lines when the operator is doubled, as in dd
+func (m *Model) vimOperator(op rune, keys []rune, count int, register rune) bool {
+	count2, keys := vimCount(keys)
+	if len(keys) == 0 {
+		return false
+	}
+	n := 0
+	if count > 0 || count2 > 0 {
+		n = max(1, count) * max(1, count2)
+	}
+	from := position{m.row, m.col}
+
+	switch keys[0] {
+	case op:
+		to := position{min(m.row+max(1, n)-1, len(m.value)-1), 0}
+		m.vimOperate(op, register, from, to, vimLinewise)
+		return true
+	case 'i', 'a':
+		if len(keys) < 2 {
+			return false
+		}
+		if start, end, ok := m.vimObject(keys[0] == 'a', keys[1]); ok {
+			m.vimOperate(op, register, start, end, vimExclusive)
+		}
+		return true
+	}
+
+	// cw changes up to the end of the word, like ce
+	if op == 'c' && (keys[0] == 'w' || keys[0] == 'W') && m.vimClass(from, keys[0] == 'W') != vimBlank {
+		keys = []rune{keys[0] - 'w' + 'e'}
+	}
+	to, kind, more, ok := m.vimMotion(keys, n)
+	if more {
+		return false
+	}
+	if ok {
+		m.vimOperate(op, register, from, to, kind)
+	}
+	return true
+}
+
+// vimOperate yanks, deletes or changes the text between two positions
+func (m *Model) vimOperate(op rune, register rune, from, to position, kind vimKind) {
+	if kind == vimLinewise {
+		first, last := min(from.row, to.row), max(from.row, to.row)
+		lines := make([][]any, 0, last-first+1)
+		for _, line := range m.value[first : last+1] {
+			lines = append(lines, copyInterfaceSlice(line))
+		}
+		m.vimYank(register, lines, true, op == 'y')
+		switch op {
+		case 'y':
+			m.row = first
+			m.SetCursorColumn(m.col)
+		case 'd':
+			m.value = slices.Delete(m.value, first, last+1)
+			if len(m.value) == 0 {
+				m.value = [][]any{{}}
+			}
+			m.row = min(first, len(m.value)-1)
+			m.SetCursorColumn(m.firstNonBlank(m.row))
+		case 'c':
+			m.value = slices.Replace(m.value, first, last+1, []any{})
+			m.row = first
+			m.SetCursorColumn(0)
+			m.vimInsert()
+		}
+		return
+	}
+
+	if to.before(from) {
+		from, to = to, from
+	}
+	if kind == vimInclusive {
+		to.col = min(to.col+1, len(m.value[to.row]))
+	} else if to.col == 0 && to.row > from.row {
+		// An exclusive motion to the start of a line stops at the end of
+		// the line before, so dw on the last word leaves the next line be
+		to = position{to.row - 1, len(m.value[to.row-1])}
+	}
+	m.vimYank(register, m.vimText(from, to), false, op == 'y')
+	if op != 'y' {
+		m.vimDelete(from, to)
+	}
+	m.row = from.row
+	m.SetCursorColumn(from.col)
+	if op == 'c' {
+		m.vimInsert()
+	}
+}
+
+// vimMotion returns where a motion moves the cursor and how an operator
+// uses it. more reports that the motion needs another key, and ok that it
+// is a motion at all.
+func (m *Model) vimMotion(keys []rune, count int) (to position, kind vimKind, more, ok bool) {
+	n := max(1, count)
+	p := position{m.row, m.col}
+	switch keys[0] {
+	case 'h':
+		return position{p.row, max(0, p.col-n)}, vimExclusive, false, true
+	case 'l':
+		return position{p.row, min(len(m.value[p.row]), p.col+n)}, vimExclusive, false, true
+	case 'j':
+		return position{min(len(m.value)-1, p.row+n), p.col}, vimLinewise, false, true
+	case 'k':
+		return position{max(0, p.row-n), p.col}, vimLinewise, false, true
+	case '0':
+		return position{p.row, 0}, vimExclusive, false, true
+	case '^':
+		return position{p.row, m.firstNonBlank(p.row)}, vimExclusive, false, true
+	case '$':
+		row := min(len(m.value)-1, p.row+n-1)
+		return position{row, max(0, len(m.value[row])-1)}, vimInclusive, false, true
+	case 'w', 'W':
+		for range n {
+			p = m.vimWordForward(p, keys[0] == 'W')
+		}
+		return p, vimExclusive, false, true
+	case 'b', 'B':
+		for range n {
+			p = m.vimWordBackward(p, keys[0] == 'B')
+		}
+		return p, vimExclusive, false, true
+	case 'e', 'E':
+		for range n {
+			p = m.vimWordEnd(p, keys[0] == 'E')
+		}
+		return p, vimInclusive, false, true
+	case 'G', 'g':
+		if keys[0] == 'g' && len(keys) < 2 {
+			return p, vimLinewise, true, false
+		}
+		if keys[0] == 'g' && keys[1] != 'g' {
+			return p, vimLinewise, false, false
+		}
+		row := 0
+		if keys[0] == 'G' {
+			row = len(m.value) - 1
+		}
+		if count > 0 {
+			row = min(count, len(m.value)) - 1
+		}
+		return position{row, m.firstNonBlank(row)}, vimLinewise, false, true
+	case 'f', 'F', 't', 'T':
+		if len(keys) < 2 {
+			return p, vimInclusive, true, false
+		}
+		to, ok := m.vimFind(keys[0], keys[1], n)
+		kind := vimInclusive
+		if keys[0] == 'F' || keys[0] == 'T' {
+			kind = vimExclusive
+		}
+		return to, kind, false, ok
+	}
+	return p, vimExclusive, false, false
+}
+
+// vimFind finds the nth occurrence of a character on the cursor's line, for
+// f, F, t and T
+func (m *Model) vimFind(motion, char rune, n int) (position, bool) {
+	line := m.value[m.row]
+	forward := motion == 'f' || motion == 't'
+	col := m.col
+	for found := 0; found < n; {
+		if forward {
+			col++
+		} else {
+			col--
+		}
+		if col < 0 || col >= len(line) {
+			return position{}, false
+		}
+		if getRuneAt(line, col) == char {
+			found++
+		}
+	}
+	switch motion {
+	case 't':
+		col--
+	case 'T':
+		col++
+	}
+	return position{m.row, col}, true
+}
+
+// Classes of the items of a line for word motions. Every attachment is a
+// word of its own.
+const (
+	vimBlank = iota // Blanks, and the end of a line
+	vimKeyword
+	vimPunctuation
+	vimAttachment
+)
+
+// vimClassAt returns the class of the item at col. WORDs, the big words of
+// W, B and E, only tell blanks apart.
+func vimClassAt(line []any, col int, bigWord bool) int {
+	if col < 0 || col >= len(line) {
+		return vimBlank
+	}
+	switch item := line[col].(type) {
+	case *attachment.Attachment:
+		if bigWord {
+			return vimKeyword
+		}
+		return vimAttachment
+	case rune:
+		switch {
+		case unicode.IsSpace(item):
+			return vimBlank
+		case bigWord, item == '_', unicode.IsLetter(item), unicode.IsDigit(item):
+			return vimKeyword
+		}
+		return vimPunctuation
+	}
+	return vimBlank
+}
+
+func (m *Model) vimClass(p position, bigWord bool) int {
+	return vimClassAt(m.value[p.row], p.col, bigWord)
+}
+
+// vimNext steps to the next position, where the end of a line is a
+// position of its own. It returns false at the end of the text.
+func (m *Model) vimNext(p position) (position, bool) {
+	if p.col < len(m.value[p.row]) {
+		return position{p.row, p.col + 1}, true
+	}
+	if p.row < len(m.value)-1 {
+		return position{p.row + 1, 0}, true
+	}
+	return p, false
+}
+
+// vimPrev steps to the previous position
+func (m *Model) vimPrev(p position) (position, bool) {
+	if p.col > 0 {
+		return position{p.row, p.col - 1}, true
+	}
+	if p.row > 0 {
+		return position{p.row - 1, len(m.value[p.row-1])}, true
+	}
+	return p, false
+}
+
+// vimEmptyLine reports whether p is on an empty line, which word motions
+// stop at
+func (m *Model) vimEmptyLine(p position) bool {
+	return len(m.value[p.row]) == 0
+}
+
+func (m *Model) vimWordForward(p position, bigWord bool) position {
+	start := p
+	ok := true
+	if class := m.vimClass(p, bigWord); class != vimBlank {
+		for ok && m.vimClass(p, bigWord) == class {
+			p, ok = m.vimNext(p)
+		}
+	}
+	for ok && m.vimClass(p, bigWord) == vimBlank && !(m.vimEmptyLine(p) && p.row != start.row) {
+		p, ok = m.vimNext(p)
+	}
+	return p
+}
+
+func (m *Model) vimWordBackward(p position, bigWord bool) position {
+	p, ok := m.vimPrev(p)
+	for ok && m.vimClass(p, bigWord) == vimBlank && !m.vimEmptyLine(p) {
+		p, ok = m.vimPrev(p)
+	}
+	class := m.vimClass(p, bigWord)
+	if class == vimBlank {
+		return p
+	}
+	for {
+		prev, ok := m.vimPrev(p)
+		if !ok || m.vimClass(prev, bigWord) != class {
+			return p
+		}
+		p = prev
+	}
+}
+
+func (m *Model) vimWordEnd(p position, bigWord bool) position {
+	p, ok := m.vimNext(p)
+	for ok && m.vimClass(p, bigWord) == vimBlank {
+		p, ok = m.vimNext(p)
+	}
+	class := m.vimClass(p, bigWord)
+	for ok {
+		next, nextOK := m.vimNext(p)
+		if !nextOK || m.vimClass(next, bigWord) != class {
+			break
+		}
+		p = next
+	}
+	return p
+}
+
+// vimObject returns the text object around the cursor: a word (w), a WORD
+// (W) or a quoted string (", ' or `). The inner object leaves the blanks
+// or quotes around it out.
+func (m *Model) vimObject(around bool, object rune) (from, to position, ok bool) {
+	line := m.value[m.row]
+	if len(line) == 0 {
+		return from, to, false
+	}
+	col := min(m.col, len(line)-1)
+
+	switch object {
+	case 'w', 'W':
+		bigWord := object == 'W'
+		class := vimClassAt(line, col, bigWord)
+		start, end := col, col+1
+		for start > 0 && vimClassAt(line, start-1, bigWord) == class {
+			start--
+		}
+		for end < len(line) && vimClassAt(line, end, bigWord) == class {
+			end++
+		}
+		if around {
+			if class == vimBlank {
+				// The blanks and the word after them
+				next := vimClassAt(line, end, bigWord)
+				for end < len(line) && next != vimBlank && vimClassAt(line, end, bigWord) == next {
+					end++
+				}
+			} else {
+				// The word and the blanks after it, or else before it
+				blanks := end
+				for blanks < len(line) && vimClassAt(line, blanks, bigWord) == vimBlank {
+					blanks++
+				}
+				if blanks > end {
+					end = blanks
+				} else {
+					for start > 0 && vimClassAt(line, start-1, bigWord) == vimBlank {
+						start--
+					}
+				}
+			}
+		}
+		return position{m.row, start}, position{m.row, end}, true
+	case '"', '\'', '`':
+		// Quotes pair up from the start of the line. The first pair that
+		// doesn't end before the cursor is used.
+		var quotes []int
+		for i := range line {
+			if getRuneAt(line, i) == object {
+				quotes = append(quotes, i)
+			}
+		}
+		for i := 0; i+1 < len(quotes); i += 2 {
+			open, close := quotes[i], quotes[i+1]
+			if close < col {
+				continue
+			}
+			if around {
+				return position{m.row, open}, position{m.row, close + 1}, true
+			}
+			return position{m.row, open + 1}, position{m.row, close}, true
+		}
+	}
+	return from, to, false
+}
+
+// vimSelection returns the start and the exclusive end of the visual
+// selection. In the visual line mode only the rows count.
+func (m Model) vimSelection() (from, to position) {
+	from = m.vim.anchor
+	from.row = clamp(from.row, 0, len(m.value)-1)
+	from.col = clamp(from.col, 0, len(m.value[from.row]))
+	to = position{m.row, m.col}
+	if to.before(from) {
+		from, to = to, from
+	}
+	if m.vim.mode != VimVisualLine {
+		to.col = min(to.col+1, len(m.value[to.row]))
+	}
+	return from, to
+}
+
+// vimSelected reports whether the item at row and col is visually selected
+func (m Model) vimSelected(row, col int) bool {
+	switch m.vim.mode {
+	case VimVisual:
+		from, to := m.vimSelection()
+		p := position{row, col}
+		return !p.before(from) && p.before(to)
+	case VimVisualLine:
+		from, to := m.vimSelection()
+		return row >= from.row && row <= to.row
+	}
+	return false
+}
+
+// vimText copies the text from one position up to another
+func (m *Model) vimText(from, to position) [][]any {
+	if from.row == to.row {
+		return [][]any{copyInterfaceSlice(m.value[from.row][from.col:to.col])}
+	}
+	lines := [][]any{copyInterfaceSlice(m.value[from.row][from.col:])}
+	for _, line := range m.value[from.row+1 : to.row] {
+		lines = append(lines, copyInterfaceSlice(line))
+	}
+	return append(lines, copyInterfaceSlice(m.value[to.row][:to.col]))
+}
+
+// vimDelete deletes the text from one position up to another
+func (m *Model) vimDelete(from, to position) {
+	line := append(copyInterfaceSlice(m.value[from.row][:from.col]), m.value[to.row][to.col:]...)
+	m.value = slices.Replace(m.value, from.row, to.row+1, line)
+}
+
+// vimInsertText inserts lines of text at a position and returns the end of
+// the inserted text
+func (m *Model) vimInsertText(p position, lines [][]any) position {
+	tail := copyInterfaceSlice(m.value[p.row][p.col:])
+	inserted := [][]any{append(copyInterfaceSlice(m.value[p.row][:p.col]), lines[0]...)}
+	for _, line := range lines[1:] {
+		inserted = append(inserted, copyInterfaceSlice(line))
+	}
+	last := len(inserted) - 1
+	end := position{p.row + last, len(inserted[last])}
+	inserted[last] = append(inserted[last], tail...)
+	m.value = slices.Replace(m.value, p.row, p.row+1, inserted...)
+	return end
+}
+
+// vimPut puts the text of a register after or before the cursor, count
+// times. Linewise text goes below or above the cursor's line.
+func (m *Model) vimPut(register rune, after bool, count int) {
+	reg, ok := m.vim.registers[unicode.ToLower(register)]
+	if !ok || len(reg.lines) == 0 {
+		return
+	}
+	if reg.linewise {
+		at := m.row
+		if after {
+			at++
+		}
+		var lines [][]any
+		for range count {
+			for _, line := range reg.lines {
+				lines = append(lines, copyInterfaceSlice(line))
+			}
+		}
+		m.value = slices.Insert(m.value, at, lines...)
+		m.row = at
+		m.SetCursorColumn(m.firstNonBlank(at))
+		return
+	}
+
+	p := position{m.row, m.col}
+	if after && len(m.value[m.row]) > 0 {
+		p.col++
+	}
+	for range count {
+		p = m.vimInsertText(p, reg.lines)
+	}
+	// The cursor ends on the last character put
+	m.row = p.row
+	m.SetCursorColumn(p.col - 1)
+}
+
+// vimYank stores text in a register. The unnamed register always gets it,
+// and the 0 register gets what's yanked rather than deleted. An uppercase
+// register appends to the lowercase one, and _ drops the text.
+func (m *Model) vimYank(register rune, lines [][]any, linewise, yank bool) {
+	if register == '_' {
+		return
+	}
+	reg := vimRegister{lines: lines, linewise: linewise}
+	if unicode.IsUpper(register) {
+		register = unicode.ToLower(register)
+		if prev, ok := m.vim.registers[register]; ok {
+			reg = prev.append(reg)
+		}
+	}
+	m.vim.registers['"'] = reg
+	switch {
+	case register != '"':
+		m.vim.registers[register] = reg
+	case yank:
+		m.vim.registers['0'] = reg
+	}
+}
+
+// vimJoin joins the line below to the cursor's line, with a space between
+func (m *Model) vimJoin() {
+	if m.row >= len(m.value)-1 {
+		return
+	}
+	line, next := m.value[m.row], m.value[m.row+1]
+	for len(next) > 0 && isSpaceAt(next, 0) {
+		next = next[1:]
+	}
+	joined := copyInterfaceSlice(line)
+	if len(line) > 0 && len(next) > 0 {
+		joined = append(joined, ' ')
+	}
+	m.value[m.row] = append(joined, next...)
+	m.value = slices.Delete(m.value, m.row+1, m.row+2)
+	m.SetCursorColumn(len(line))
+}
+
+func (m *Model) firstNonBlank(row int) int {
+	for col := range m.value[row] {
+		if !isSpaceAt(m.value[row], col) {
+			return col
+		}
+	}
+	return len(m.value[row])
+}
+
+// vimClamp keeps the cursor on a character, as the normal and visual modes
+// have no position past the end of a line
+func (m *Model) vimClamp() {
+	m.row = clamp(m.row, 0, len(m.value)-1)
+	m.col = clamp(m.col, 0, max(0, len(m.value[m.row])-1))
+}
+
+func (m *Model) vimCheckpoint() {
+	snapshot := vimSnapshot{value: make([][]any, len(m.value)), row: m.row, col: m.col}
+	for i, line := range m.value {
+		snapshot.value[i] = copyInterfaceSlice(line)
+	}
+	m.vim.undo = append(m.vim.undo, snapshot)
+	if len(m.vim.undo) > vimUndoLimit {
+		m.vim.undo = m.vim.undo[1:]
+	}
+}
+
+// vimDropUnchanged drops the last checkpoint when the text is still the same
+func (m *Model) vimDropUnchanged() {
+	n := len(m.vim.undo)
+	if n > 0 && slices.EqualFunc(m.vim.undo[n-1].value, m.value, slices.Equal[[]any]) {
+		m.vim.undo = m.vim.undo[:n-1]
+	}
+}
+
+func (m *Model) vimUndo() {
+	n := len(m.vim.undo)
+	if n == 0 {
+		return
+	}
+	snapshot := m.vim.undo[n-1]
+	m.vim.undo = m.vim.undo[:n-1]
+	m.value = snapshot.value
+	m.row = snapshot.row
+	m.SetCursorColumn(snapshot.col)
+}
+
+// vimRegisterName reports whether r names a register: a letter, a digit,
+// " for the unnamed register or _ to drop the text
+func vimRegisterName(r rune) bool {
+	return r == '"' || r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+// vimCount splits the count off the front of a command. A leading 0 is the
+// motion to the start of the line rather than a count.
+func vimCount(keys []rune) (int, []rune) {
+	count := 0
+	for len(keys) > 0 && (keys[0] >= '1' && keys[0] <= '9' || count > 0 && keys[0] == '0') {
+		count = min(count*10+int(keys[0]-'0'), maxLines)
+		keys = keys[1:]
+	}
+	return count, keys
+}
@@ -0,0 +1,153 @@
+package textarea
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// vimKeys sends keys to a focused textarea, one rune at a time. <esc> and
+// <bs> stand for those keys.
+func vimKeys(m Model, keys ...string) Model {
+	for _, k := range keys {
+		var msgs []tea.KeyPressMsg
+		switch k {
+		case "<esc>":
+			msgs = []tea.KeyPressMsg{{Code: tea.KeyEscape}}
+		case "<bs>":
+			msgs = []tea.KeyPressMsg{{Code: tea.KeyBackspace}}
+		default:
+			for _, r := range k {
+				msgs = append(msgs, tea.KeyPressMsg{Code: r, Text: string(r)})
+			}
+		}
+		for _, msg := range msgs {
+			m, _ = m.Update(msg)
+		}
+	}
+	return m
+}
+
+func newVim(value string) Model {
+	m := New()
+	m.Focus()
+	m.SetVim(true)
+	m.SetValue(value)
+	return vimKeys(m, "<esc>", "gg0")
+}
+
+func TestVimEditing(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		keys  []string
+		want  string
+		mode  VimMode
+	}{
+		{"dw", "one two three", []string{"w", "dw"}, "one three", VimNormal},
+		{"dw on the last word keeps the next line", "one two\nthree", []string{"w", "dw"}, "one \nthree", VimNormal},
+		{"count", "one two three", []string{"2dw"}, "three", VimNormal},
+		{"dd", "one\ntwo\nthree", []string{"j", "dd"}, "one\nthree", VimNormal},
+		{"dd with a count", "one\ntwo\nthree", []string{"2dd"}, "three", VimNormal},
+		{"d$", "one two three", []string{"w", "D"}, "one ", VimNormal},
+		{"d0", "one two three", []string{"$", "d0"}, "e", VimNormal},
+		{"de", "one two", []string{"de"}, " two", VimNormal},
+		{"db", "one two", []string{"$", "db"}, "one o", VimNormal},
+		{"ciw", "say hello world", []string{"w", "ciw", "bye"}, "say bye world", VimInsert},
+		{"cw stops at the end of the word", "one two", []string{"cw", "1"}, "1 two", VimInsert},
+		{"daw", "one two three", []string{"w", "daw"}, "one three", VimNormal},
+		{"ci quote", `say "hello world" now`, []string{`ci"`, "x"}, `say "x" now`, VimInsert},
+		{"dt", "call(a, b)", []string{"dt("}, "(a, b)", VimNormal},
+		{"x", "abc", []string{"x"}, "bc", VimNormal},
+		{"X at the start does nothing", "abc", []string{"X"}, "abc", VimNormal},
+		{"r", "abc", []string{"rz"}, "zbc", VimNormal},
+		{"yy p", "one\ntwo", []string{"yyp"}, "one\none\ntwo", VimNormal},
+		{"yw P", "one two", []string{"yw", "$", "P"}, "one twone o", VimNormal},
+		{"dd p moves a line", "one\ntwo", []string{"ddp"}, "two\none", VimNormal},
+		{"named register", "one two", []string{`"ayw`, "w", "dw", `"ap`}, "one one ", VimNormal},
+		{"black hole register", "one two", []string{"yw", "w", `"_dw`, "p"}, "one one ", VimNormal},
+		{"uppercase register appends", "one two", []string{`"ayl`, "w", `"Ayl`, "0", `"aP`}, "otone two", VimNormal},
+		{"o", "one", []string{"o", "two"}, "one\ntwo", VimInsert},
+		{"A", "one", []string{"A", "!"}, "one!", VimInsert},
+		{"J", "one\n  two", []string{"J"}, "one two", VimNormal},
+		{"visual delete", "one two three", []string{"w", "ve", "d"}, "one  three", VimNormal},
+		{"visual line yank", "one\ntwo", []string{"Vy", "j", "p"}, "one\ntwo\none", VimNormal},
+		{"visual change", "one two", []string{"v$", "c", "x"}, "x", VimInsert},
+		{"undo", "one two", []string{"dw", "x", "u", "u"}, "one two", VimNormal},
+		{"undo an insert", "one", []string{"A", " two", "<esc>", "u"}, "one", VimNormal},
+		{"esc cancels a command", "one", []string{"d", "<esc>", "x"}, "ne", VimNormal},
+		{"backspace moves left", "one", []string{"$", "<bs>", "x"}, "oe", VimNormal},
+		{"G and gg", "one\ntwo\nthree", []string{"G", "dd", "gg", "dd"}, "two", VimNormal},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := vimKeys(newVim(test.value), test.keys...)
+			if got := m.Value(); got != test.want {
+				t.Errorf("value = %q, want %q", got, test.want)
+			}
+			if m.VimMode() != test.mode {
+				t.Errorf("mode = %s, want %s", m.VimMode(), test.mode)
+			}
+		})
+	}
+}
+
+func TestVimMotions(t *testing.T) {
+	m := newVim("foo.bar baz\n\nqux")
+	for _, step := range []struct {
+		keys     string
+		row, col int
+	}{
+		{"w", 0, 3},
+		{"w", 0, 4},
+		{"W", 0, 8},
+		{"W", 1, 0}, // An empty line is a word
+		{"w", 2, 0},
+		{"b", 1, 0},
+		{"b", 0, 8},
+		{"B", 0, 0},
+		{"E", 0, 6},
+		{"e", 0, 10},
+		{"$", 0, 10},
+		{"0", 0, 0},
+		{"fa", 0, 5},
+		{"2l", 0, 7},
+		{"j", 1, 0},
+		{"G", 2, 0},
+		{"$l", 2, 2}, // The cursor stays on the last character
+	} {
+		m = vimKeys(m, step.keys)
+		if m.Line() != step.row || m.CursorColumn() != step.col {
+			t.Fatalf("after %s the cursor is at %d:%d, want %d:%d", step.keys, m.Line(), m.CursorColumn(), step.row, step.col)
+		}
+	}
+}
+
+func TestVimKey(t *testing.T) {
+	m := New()
+	esc := tea.KeyPressMsg{Code: tea.KeyEscape}
+	letter := tea.KeyPressMsg{Code: 'd', Text: "d"}
+	if m.VimKey(esc) {
+		t.Error("keys are taken with vim off")
+	}
+	m.SetVim(true)
+	if !m.VimKey(esc) || m.VimKey(letter) {
+		t.Error("the insert mode takes esc only")
+	}
+	m.Focus()
+	m, _ = m.Update(esc)
+	if m.VimMode() != VimNormal || !m.VimKey(letter) {
+		t.Error("the normal mode takes text keys")
+	}
+	if m.VimKey(esc) {
+		t.Error("a lone esc in the normal mode is left to interrupt the session")
+	}
+	m, _ = m.Update(letter)
+	if !m.VimKey(esc) {
+		t.Error("esc cancels a pending command")
+	}
+	m.VimInsert()
+	if m.VimMode() != VimInsert {
+		t.Errorf("mode after VimInsert = %s", m.VimMode())
+	}
+}
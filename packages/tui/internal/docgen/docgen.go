@@ -0,0 +1,197 @@
+// Package docgen selects the files of a package or module to document and
+// builds the instructions the agent follows to write their documentation.
+package docgen
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Style is a documentation comment convention
+type Style string
+
+const (
+	StyleAuto      Style = "auto" // Chosen per file from its language
+	StyleGodoc     Style = "godoc"
+	StyleJSDoc     Style = "jsdoc"
+	StyleDocstring Style = "docstring"
+	StyleRustdoc   Style = "rustdoc"
+)
+
+// styleGuides describes each style to the model
+var styleGuides = map[Style]string{
+	StyleGodoc:     "Go: godoc comments directly above every exported identifier and a package comment, each starting with the identifier's name, in full sentences.",
+	StyleJSDoc:     "JavaScript/TypeScript: JSDoc /** */ blocks above exported functions, classes and types, with @param and @returns tags.",
+	StyleDocstring: "Python: PEP 257 docstrings for modules, public classes and functions, with Args/Returns sections.",
+	StyleRustdoc:   "Rust: /// doc comments on public items and //! module docs, with # Examples where useful.",
+}
+
+// extensionStyles maps source file extensions to their native style
+var extensionStyles = map[string]Style{
+	".go":  StyleGodoc,
+	".js":  StyleJSDoc,
+	".jsx": StyleJSDoc,
+	".ts":  StyleJSDoc,
+	".tsx": StyleJSDoc,
+	".mjs": StyleJSDoc,
+	".py":  StyleDocstring,
+	".rs":  StyleRustdoc,
+}
+
+// skippedDirs are never documented
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"dist":         true,
+	"build":        true,
+	"target":       true,
+	"__pycache__":  true,
+}
+
+// maxFiles bounds the number of files collected for one run
+const maxFiles = 200
+
+// Options configures a documentation run
+type Options struct {
+	Target    string // Directory or file to document, relative to the working directory
+	Style     Style
+	NoReadme  bool // Leave README files alone
+	Recursive bool // Include subdirectories
+}
+
+// ParseArgs parses the arguments of /docgen:
+// <path> [--style godoc|jsdoc|docstring|rustdoc] [--no-readme] [-r]
+func ParseArgs(args string) (Options, error) {
+	options := Options{Style: StyleAuto}
+	fields := strings.Fields(args)
+	for i := 0; i < len(fields); i++ {
+		switch field := fields[i]; {
+		case field == "--style" && i+1 < len(fields):
+			i++
+			options.Style = Style(strings.ToLower(fields[i]))
+		case strings.HasPrefix(field, "--style="):
+			options.Style = Style(strings.ToLower(strings.TrimPrefix(field, "--style=")))
+		case field == "--no-readme":
+			options.NoReadme = true
+		case field == "-r" || field == "--recursive":
+			options.Recursive = true
+		case strings.HasPrefix(field, "-"):
+			return options, fmt.Errorf("unknown option %s", field)
+		case options.Target == "":
+			options.Target = field
+		default:
+			return options, fmt.Errorf("only one path can be documented at a time")
+		}
+	}
+	if options.Target == "" {
+		return options, fmt.Errorf("usage: /docgen <path> [--style godoc|jsdoc|docstring|rustdoc] [--no-readme] [-r]")
+	}
+	if _, ok := styleGuides[options.Style]; !ok && options.Style != StyleAuto {
+		return options, fmt.Errorf("unknown style %q", options.Style)
+	}
+	return options, nil
+}
+
+// CollectFiles returns the source files, and README when enabled, to
+// document under target, relative to root. Tests and generated files are
+// skipped.
+func CollectFiles(root, target string, options Options) ([]string, error) {
+	info, err := os.Stat(target)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		rel, err := filepath.Rel(root, target)
+		if err != nil {
+			return nil, err
+		}
+		return []string{rel}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(target, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != target && (!options.Recursive || skippedDirs[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if len(files) >= maxFiles {
+			return filepath.SkipAll
+		}
+		if !documentable(d.Name(), options) {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Offer a README for the target even when it has none yet; it can be
+	// dropped from the plan before starting
+	if !options.NoReadme {
+		readme, _ := filepath.Rel(root, filepath.Join(target, "README.md"))
+		if !slices.Contains(files, readme) {
+			files = append(files, readme)
+		}
+	}
+	return files, nil
+}
+
+func documentable(name string, options Options) bool {
+	if strings.EqualFold(name, "README.md") {
+		return !options.NoReadme
+	}
+	if _, ok := extensionStyles[filepath.Ext(name)]; !ok {
+		return false
+	}
+	lower := strings.ToLower(name)
+	for _, marker := range []string{"_test.", ".test.", ".spec.", ".pb.", "_gen.", ".gen.", ".d.ts"} {
+		if strings.Contains(lower, marker) {
+			return false
+		}
+	}
+	return !strings.HasPrefix(lower, "test_")
+}
+
+// Instructions tells the agent how to document the collected files
+func Instructions(target string, files []string, options Options) string {
+	styles := map[Style]bool{}
+	if options.Style != StyleAuto {
+		styles[options.Style] = true
+	} else {
+		for _, file := range files {
+			if style, ok := extensionStyles[filepath.Ext(file)]; ok {
+				styles[style] = true
+			}
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Generate or update the documentation of %s.\n\n", target)
+	b.WriteString("Add missing doc comments and fix outdated ones so they match what the code actually does. ")
+	b.WriteString("Only change comments and documentation: do not alter code, signatures or formatting.\n\n")
+	b.WriteString("Style:\n")
+	for _, style := range []Style{StyleGodoc, StyleJSDoc, StyleDocstring, StyleRustdoc} {
+		if styles[style] {
+			b.WriteString("- " + styleGuides[style] + "\n")
+		}
+	}
+	if !options.NoReadme {
+		b.WriteString("\nFor README files, update the sections describing the package's purpose and public API; keep other sections as they are.\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,164 @@
+// Package gesture recognizes touch gestures in the mouse events a terminal
+// reports. Mobile terminals such as Termux and Blink report a finger as the
+// left mouse button: a press, drags while it moves, and a release.
+package gesture
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// Env names the environment variable turning gestures on (1) or off (0)
+// whatever the terminal
+const Env = "RYCODE_GESTURES"
+
+const (
+	// SwipeColumns is how far a swipe travels sideways
+	SwipeColumns = 8
+	// SwipeTime is how quick a swipe is; slower drags select text
+	SwipeTime = 700 * time.Millisecond
+	// LongPressTime is how long a finger is held still for a long press
+	LongPressTime = 500 * time.Millisecond
+	// DoubleTapTime is how soon a second tap follows the first
+	DoubleTapTime = 350 * time.Millisecond
+)
+
+// Kind is the kind of a gesture
+type Kind int
+
+const (
+	None Kind = iota
+	Tap
+	DoubleTap
+	LongPress
+	SwipeLeft
+	SwipeRight
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Tap:
+		return "tap"
+	case DoubleTap:
+		return "double tap"
+	case LongPress:
+		return "long press"
+	case SwipeLeft:
+		return "swipe left"
+	case SwipeRight:
+		return "swipe right"
+	}
+	return "none"
+}
+
+// Event is a recognized gesture, at the cell it started on
+type Event struct {
+	Kind Kind
+	X, Y int
+}
+
+// Recognizer turns the presses, drags and releases of one pointer into
+// gestures
+type Recognizer struct {
+	pressed     bool
+	press       int // Counts presses, so a hold check only applies to its own
+	x, y        int // Where the press started
+	at          time.Time
+	moved       bool // Moved off the cell it was pressed on
+	longPressed bool // Reported as a long press, so its release is no tap
+
+	lastTap      time.Time
+	lastX, lastY int
+}
+
+// New creates a recognizer
+func New() *Recognizer {
+	return &Recognizer{}
+}
+
+// Enabled reports whether gestures are recognized in this terminal: in
+// Termux and Blink, or as Env says
+func Enabled() bool {
+	switch os.Getenv(Env) {
+	case "1", "true", "on":
+		return true
+	case "0", "false", "off":
+		return false
+	}
+	return os.Getenv("TERMUX_VERSION") != "" ||
+		strings.Contains(os.Getenv("PREFIX"), "com.termux") ||
+		os.Getenv("LC_TERMINAL") == "Blink" ||
+		strings.Contains(strings.ToLower(os.Getenv("TERM_PROGRAM")), "blink")
+}
+
+// Press starts tracking a press. It returns the press's number for Hold,
+// which is checked LongPressTime later.
+func (r *Recognizer) Press(x, y int, at time.Time) int {
+	r.press++
+	r.pressed = true
+	r.x, r.y, r.at = x, y, at
+	r.moved = false
+	r.longPressed = false
+	return r.press
+}
+
+// Move follows a drag of the press
+func (r *Recognizer) Move(x, y int, at time.Time) {
+	if !r.pressed {
+		return
+	}
+	if abs(x-r.x) > 1 || abs(y-r.y) > 1 {
+		r.moved = true
+	}
+}
+
+// Hold reports a long press when a press is still held where it started
+// LongPressTime after it
+func (r *Recognizer) Hold(press int, at time.Time) Event {
+	if !r.pressed || press != r.press || r.moved || r.longPressed || at.Sub(r.at) < LongPressTime {
+		return Event{}
+	}
+	r.longPressed = true
+	r.lastTap = time.Time{}
+	return Event{Kind: LongPress, X: r.x, Y: r.y}
+}
+
+// Release ends the press and returns the gesture it made: a quick, mostly
+// sideways drag is a swipe, a press that stayed put a tap, or a double tap
+// right after another
+func (r *Recognizer) Release(x, y int, at time.Time) Event {
+	if !r.pressed {
+		return Event{}
+	}
+	r.pressed = false
+	if r.longPressed {
+		return Event{}
+	}
+	dx, dy := x-r.x, y-r.y
+	if abs(dx) >= SwipeColumns && abs(dy)*2 <= abs(dx) && at.Sub(r.at) <= SwipeTime {
+		r.lastTap = time.Time{}
+		if dx < 0 {
+			return Event{Kind: SwipeLeft, X: r.x, Y: r.y}
+		}
+		return Event{Kind: SwipeRight, X: r.x, Y: r.y}
+	}
+	if r.moved || abs(dx) > 1 || abs(dy) > 1 {
+		// A drag, which selects text
+		r.lastTap = time.Time{}
+		return Event{}
+	}
+	if !r.lastTap.IsZero() && at.Sub(r.lastTap) <= DoubleTapTime && abs(r.x-r.lastX) <= 1 && r.y == r.lastY {
+		r.lastTap = time.Time{}
+		return Event{Kind: DoubleTap, X: r.x, Y: r.y}
+	}
+	r.lastTap, r.lastX, r.lastY = at, r.x, r.y
+	return Event{Kind: Tap, X: r.x, Y: r.y}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,56 @@
+package gesture
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecognizer(t *testing.T) {
+	start := time.Now()
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+
+	r := New()
+	r.Press(40, 10, at(0))
+	r.Move(30, 10, at(100))
+	r.Move(20, 11, at(200))
+	if e := r.Release(20, 11, at(250)); e.Kind != SwipeLeft || e.X != 40 {
+		t.Errorf("quick drag left = %v at %d, want a swipe left at 40", e.Kind, e.X)
+	}
+
+	r.Press(20, 10, at(1000))
+	r.Move(40, 10, at(1500))
+	if e := r.Release(40, 10, at(2000)); e.Kind != None {
+		t.Errorf("slow drag = %v, want none so it selects text", e.Kind)
+	}
+
+	r.Press(20, 10, at(3000))
+	if e := r.Release(20, 10, at(3080)); e.Kind != Tap {
+		t.Errorf("first tap = %v, want tap", e.Kind)
+	}
+	r.Press(21, 10, at(3200))
+	if e := r.Release(21, 10, at(3260)); e.Kind != DoubleTap {
+		t.Errorf("second tap = %v, want double tap", e.Kind)
+	}
+
+	press := r.Press(20, 10, at(5000))
+	if e := r.Hold(press, at(5000+int(LongPressTime/time.Millisecond))); e.Kind != LongPress {
+		t.Errorf("held press = %v, want long press", e.Kind)
+	}
+	if e := r.Release(20, 10, at(6000)); e.Kind != None {
+		t.Errorf("release of a long press = %v, want none", e.Kind)
+	}
+
+	// A hold check of an earlier press, or of one that moved, is no long
+	// press
+	press = r.Press(20, 10, at(7000))
+	r.Release(20, 10, at(7050))
+	r.Press(20, 10, at(7100))
+	if e := r.Hold(press, at(7600)); e.Kind != None {
+		t.Errorf("hold of an earlier press = %v, want none", e.Kind)
+	}
+	press = r.Press(20, 10, at(8000))
+	r.Move(20, 14, at(8100))
+	if e := r.Hold(press, at(8600)); e.Kind != None {
+		t.Errorf("hold of a moved press = %v, want none", e.Kind)
+	}
+}
@@ -0,0 +1,229 @@
+// Package gitstatus reads the state of a git worktree beyond its branch: the
+// files changed, staged and untracked, and how far the branch is from its
+// upstream. It also stages, unstages and commits for the /git dialog.
+package gitstatus
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+)
+
+// File is a changed file of a worktree. Index and Worktree are the X and Y
+// letters of git status: M modified, A added, D deleted, R renamed, ? for
+// untracked files and ' ' when unchanged.
+type File struct {
+	Path     string
+	OrigPath string // Path before a rename
+	Index    byte
+	Worktree byte
+	Conflict bool
+}
+
+// Staged reports whether the file has changes in the index
+func (f File) Staged() bool {
+	return !f.Conflict && f.Index != ' ' && f.Index != '?'
+}
+
+// Unstaged reports whether the file has changes not in the index, including
+// being untracked
+func (f File) Unstaged() bool {
+	return f.Conflict || f.Worktree != ' '
+}
+
+// Untracked reports whether git doesn't track the file
+func (f File) Untracked() bool {
+	return f.Index == '?'
+}
+
+// Code returns the two letters git status --short shows for the file
+func (f File) Code() string {
+	if f.Conflict {
+		return "UU"
+	}
+	return string([]byte{f.Index, f.Worktree})
+}
+
+// Status is the state of a worktree
+type Status struct {
+	Branch   string // Empty when detached
+	Upstream string
+	Ahead    int
+	Behind   int
+	Files    []File
+}
+
+// Counts returns the number of files with staged and unstaged changes, of
+// untracked files and of conflicts
+func (s Status) Counts() (staged, unstaged, untracked, conflicts int) {
+	for _, f := range s.Files {
+		switch {
+		case f.Conflict:
+			conflicts++
+		case f.Untracked():
+			untracked++
+		default:
+			if f.Staged() {
+				staged++
+			}
+			if f.Unstaged() {
+				unstaged++
+			}
+		}
+	}
+	return staged, unstaged, untracked, conflicts
+}
+
+// Indicators summarizes the status for the status bar, such as "+2 ~1 ?3 ↑1",
+// or returns "" for a clean worktree in step with its upstream
+func (s Status) Indicators() string {
+	staged, unstaged, untracked, conflicts := s.Counts()
+	var parts []string
+	for _, part := range []struct {
+		symbol string
+		n      int
+	}{
+		{"!", conflicts},
+		{"+", staged},
+		{"~", unstaged},
+		{"?", untracked},
+		{"↑", s.Ahead},
+		{"↓", s.Behind},
+	} {
+		if part.n > 0 {
+			parts = append(parts, part.symbol+strconv.Itoa(part.n))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Read returns the status of the worktree at dir. Optional locks are skipped
+// so that polling doesn't get in the way of git commands run meanwhile.
+func Read(ctx context.Context, dir string) (Status, error) {
+	out, err := git(ctx, dir, "--no-optional-locks", "status", "--porcelain=v2", "--branch", "-z", "--untracked-files=all")
+	if err != nil {
+		return Status{}, err
+	}
+	return Parse(out), nil
+}
+
+// Parse parses the output of git status --porcelain=v2 --branch -z
+func Parse(output string) Status {
+	var s Status
+	entries := strings.Split(output, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if entry == "" {
+			continue
+		}
+		switch entry[0] {
+		case '#':
+			fields := strings.Fields(entry)
+			if len(fields) < 3 {
+				continue
+			}
+			switch fields[1] {
+			case "branch.head":
+				if fields[2] != "(detached)" {
+					s.Branch = fields[2]
+				}
+			case "branch.upstream":
+				s.Upstream = fields[2]
+			case "branch.ab":
+				if len(fields) == 4 {
+					s.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[2], "+"))
+					s.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[3], "-"))
+				}
+			}
+		case '1', '2', 'u':
+			// The path is the last field, and may contain spaces
+			n := map[byte]int{'1': 9, '2': 10, 'u': 11}[entry[0]]
+			fields := strings.SplitN(entry, " ", n)
+			if len(fields) < n || len(fields[1]) != 2 {
+				continue
+			}
+			f := File{
+				Path:     fields[n-1],
+				Index:    unchanged(fields[1][0]),
+				Worktree: unchanged(fields[1][1]),
+				Conflict: entry[0] == 'u',
+			}
+			if entry[0] == '2' && i+1 < len(entries) {
+				// The path renamed from is the next entry
+				i++
+				f.OrigPath = entries[i]
+			}
+			s.Files = append(s.Files, f)
+		case '?':
+			s.Files = append(s.Files, File{Path: strings.TrimPrefix(entry, "? "), Index: '?', Worktree: '?'})
+		}
+	}
+	return s
+}
+
+// unchanged turns the dot porcelain v2 uses for an unchanged side into the
+// space of the short format
+func unchanged(code byte) byte {
+	if code == '.' {
+		return ' '
+	}
+	return code
+}
+
+// Stage adds the changes of paths to the index
+func Stage(ctx context.Context, dir string, paths ...string) error {
+	_, err := git(ctx, dir, append([]string{"add", "--all", "--"}, paths...)...)
+	return err
+}
+
+// Unstage removes the changes of paths from the index, keeping them in the
+// worktree
+func Unstage(ctx context.Context, dir string, paths ...string) error {
+	_, err := git(ctx, dir, append([]string{"restore", "--staged", "--"}, paths...)...)
+	return err
+}
+
+// StagedDiff returns the diff of the index against HEAD
+func StagedDiff(ctx context.Context, dir string) (string, error) {
+	return git(ctx, dir, "diff", "--cached", "--no-color", "--no-ext-diff")
+}
+
+// RecentSubjects returns the subject lines of the last n commits, one per
+// line
+func RecentSubjects(ctx context.Context, dir string, n int) (string, error) {
+	return git(ctx, dir, "log", "-n", strconv.Itoa(n), "--format=%s")
+}
+
+// Commit commits the index with message and returns the one-line summary git
+// prints, such as "[main 1a2b3c4] Fix the upload retries"
+func Commit(ctx context.Context, dir, message string) (string, error) {
+	cmd := remote.Command(ctx, dir, "git", "commit", "--file", "-")
+	cmd.Stdin = strings.NewReader(message)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git commit: %s", strings.TrimSpace(string(output)))
+	}
+	summary, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	return summary, nil
+}
+
+// git runs a git command in dir and returns its output
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := remote.Command(ctx, dir, "git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		name := args[0]
+		if strings.HasPrefix(name, "-") && len(args) > 1 {
+			name = args[1]
+		}
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", name, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", name, err)
+	}
+	return string(output), nil
+}
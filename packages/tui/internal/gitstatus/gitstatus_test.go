@@ -0,0 +1,49 @@
+package gitstatus
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	output := strings.Join([]string{
+		"# branch.oid 1a2b3c4d5e6f",
+		"# branch.head main",
+		"# branch.upstream origin/main",
+		"# branch.ab +2 -1",
+		"1 M. N... 100644 100644 100644 aaa bbb internal/app/app.go",
+		"1 .M N... 100644 100644 100644 aaa bbb docs/read me.md",
+		"2 R. N... 100644 100644 100644 aaa bbb R100 internal/new.go",
+		"internal/old.go",
+		"u UU N... 100644 100644 100644 100644 aaa bbb ccc go.sum",
+		"? notes.txt",
+		"",
+	}, "\x00")
+
+	s := Parse(output)
+	if s.Branch != "main" || s.Upstream != "origin/main" || s.Ahead != 2 || s.Behind != 1 {
+		t.Errorf("branch = %q %q +%d -%d", s.Branch, s.Upstream, s.Ahead, s.Behind)
+	}
+	if len(s.Files) != 5 {
+		t.Fatalf("expected 5 files, got %+v", s.Files)
+	}
+	if f := s.Files[1]; f.Path != "docs/read me.md" || f.Staged() || !f.Unstaged() || f.Code() != " M" {
+		t.Errorf("unstaged file = %+v", f)
+	}
+	if f := s.Files[2]; f.Path != "internal/new.go" || f.OrigPath != "internal/old.go" || !f.Staged() {
+		t.Errorf("renamed file = %+v", f)
+	}
+	if f := s.Files[4]; !f.Untracked() || f.Staged() || f.Path != "notes.txt" {
+		t.Errorf("untracked file = %+v", f)
+	}
+	if got, want := s.Indicators(), "!1 +2 ~1 ?1 ↑2 ↓1"; got != want {
+		t.Errorf("Indicators() = %q, want %q", got, want)
+	}
+}
+
+func TestParseClean(t *testing.T) {
+	s := Parse("# branch.oid 1a2b3c4\x00# branch.head (detached)\x00")
+	if s.Branch != "" || len(s.Files) != 0 || s.Indicators() != "" {
+		t.Errorf("clean detached worktree = %+v, %q", s, s.Indicators())
+	}
+}
@@ -0,0 +1,47 @@
+package gitstatus
+
+import (
+	"regexp"
+	"strings"
+)
+
+// maxDiffBytes bounds the staged diff sent to generate a commit message
+const maxDiffBytes = 60_000
+
+// MessageSystemPrompt has the agent only write the message
+const MessageSystemPrompt = `You write git commit messages. Do not modify files or run commands.
+Write a subject line in the imperative mood under 72 characters, without a trailing period. When the change needs explaining, add a blank line and a body of short paragraphs wrapped at 72 columns saying what changed and why.
+Follow the style of the recent commits when they are given.`
+
+// MessagePrompt asks for the message of a commit of diff. The conversation
+// of the session that made the changes, when given, says why they were made.
+func MessagePrompt(diff, conversation, recentSubjects string) string {
+	if len(diff) > maxDiffBytes {
+		diff = diff[:maxDiffBytes] + "\n… (diff truncated)"
+	}
+	var b strings.Builder
+	if conversation != "" {
+		b.WriteString("These changes were made in this conversation with a coding agent:\n\n")
+		b.WriteString(conversation)
+		b.WriteString("\n\n")
+	}
+	if recentSubjects = strings.TrimSpace(recentSubjects); recentSubjects != "" {
+		b.WriteString("Recent commits:\n")
+		b.WriteString(recentSubjects)
+		b.WriteString("\n\n")
+	}
+	b.WriteString("Write the commit message for this staged diff:\n\n```diff\n")
+	b.WriteString(diff)
+	b.WriteString("\n```\n\nReply with the commit message alone, in a ```text block.")
+	return b.String()
+}
+
+var messageBlockPattern = regexp.MustCompile("(?s)```[a-z]*\\n(.*?)```")
+
+// ParseMessage extracts the commit message from the agent's answer
+func ParseMessage(answer string) string {
+	if match := messageBlockPattern.FindStringSubmatch(answer); match != nil {
+		answer = match[1]
+	}
+	return strings.TrimSpace(answer)
+}
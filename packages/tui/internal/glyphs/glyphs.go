@@ -0,0 +1,195 @@
+// Package glyphs is the catalog of the symbol picker: arrows, box drawing,
+// math and other symbols, and emoji, found by name.
+package glyphs
+
+import (
+	"slices"
+	"strings"
+)
+
+// Glyph is a symbol of the picker
+type Glyph struct {
+	Text     string
+	Name     string
+	Category string
+	Keywords []string // Other words it's found by
+}
+
+// Categories in the order the picker lists them
+const (
+	Arrows   = "Arrows"
+	Box      = "Box drawing"
+	Math     = "Math"
+	Marks    = "Marks"
+	Currency = "Currency"
+	Emoji    = "Emoji"
+)
+
+// Catalog is every glyph of the picker
+var Catalog = []Glyph{
+	{"→", "rightwards arrow", Arrows, []string{"right", "to", "implies"}},
+	{"←", "leftwards arrow", Arrows, []string{"left", "from"}},
+	{"↑", "upwards arrow", Arrows, []string{"up"}},
+	{"↓", "downwards arrow", Arrows, []string{"down"}},
+	{"↔", "left right arrow", Arrows, []string{"both", "bidirectional"}},
+	{"↕", "up down arrow", Arrows, []string{"vertical"}},
+	{"⇒", "rightwards double arrow", Arrows, []string{"implies", "then"}},
+	{"⇐", "leftwards double arrow", Arrows, nil},
+	{"⇔", "left right double arrow", Arrows, []string{"iff", "equivalent"}},
+	{"↗", "north east arrow", Arrows, []string{"up", "right", "increase"}},
+	{"↘", "south east arrow", Arrows, []string{"down", "right", "decrease"}},
+	{"↩", "leftwards arrow with hook", Arrows, []string{"return", "enter", "undo"}},
+	{"↪", "rightwards arrow with hook", Arrows, []string{"redo"}},
+	{"⟶", "long rightwards arrow", Arrows, nil},
+	{"↻", "clockwise open circle arrow", Arrows, []string{"refresh", "reload", "retry"}},
+	{"⤴", "arrow pointing right then curving up", Arrows, nil},
+
+	{"─", "light horizontal", Box, []string{"line", "dash"}},
+	{"│", "light vertical", Box, []string{"line", "pipe"}},
+	{"┌", "light down and right", Box, []string{"corner", "top", "left"}},
+	{"┐", "light down and left", Box, []string{"corner", "top", "right"}},
+	{"└", "light up and right", Box, []string{"corner", "bottom", "left", "tree"}},
+	{"┘", "light up and left", Box, []string{"corner", "bottom", "right"}},
+	{"├", "light vertical and right", Box, []string{"tee", "tree", "branch"}},
+	{"┤", "light vertical and left", Box, []string{"tee"}},
+	{"┬", "light down and horizontal", Box, []string{"tee"}},
+	{"┴", "light up and horizontal", Box, []string{"tee"}},
+	{"┼", "light vertical and horizontal", Box, []string{"cross"}},
+	{"═", "double horizontal", Box, []string{"line"}},
+	{"║", "double vertical", Box, []string{"line"}},
+	{"╔", "double down and right", Box, []string{"corner"}},
+	{"╗", "double down and left", Box, []string{"corner"}},
+	{"╚", "double up and right", Box, []string{"corner"}},
+	{"╝", "double up and left", Box, []string{"corner"}},
+	{"╭", "light arc down and right", Box, []string{"rounded", "corner"}},
+	{"╮", "light arc down and left", Box, []string{"rounded", "corner"}},
+	{"╰", "light arc up and right", Box, []string{"rounded", "corner"}},
+	{"╯", "light arc up and left", Box, []string{"rounded", "corner"}},
+	{"█", "full block", Box, []string{"bar", "solid"}},
+	{"░", "light shade", Box, []string{"bar"}},
+	{"▒", "medium shade", Box, []string{"bar"}},
+
+	{"×", "multiplication sign", Math, []string{"times", "multiply", "by"}},
+	{"÷", "division sign", Math, []string{"divide"}},
+	{"±", "plus minus sign", Math, []string{"plusminus"}},
+	{"≈", "almost equal to", Math, []string{"approximately", "about"}},
+	{"≠", "not equal to", Math, []string{"unequal", "neq"}},
+	{"≤", "less than or equal to", Math, []string{"leq"}},
+	{"≥", "greater than or equal to", Math, []string{"geq"}},
+	{"∞", "infinity", Math, nil},
+	{"√", "square root", Math, []string{"sqrt"}},
+	{"∑", "n-ary summation", Math, []string{"sum", "sigma"}},
+	{"∆", "increment", Math, []string{"delta", "change", "diff"}},
+	{"π", "greek small letter pi", Math, nil},
+	{"λ", "greek small letter lambda", Math, []string{"function"}},
+	{"µ", "micro sign", Math, []string{"mu", "micro"}},
+	{"°", "degree sign", Math, []string{"degrees", "temperature"}},
+	{"∈", "element of", Math, []string{"in", "member"}},
+	{"∀", "for all", Math, []string{"every"}},
+	{"∃", "there exists", Math, []string{"exists"}},
+	{"∅", "empty set", Math, []string{"none", "nil"}},
+	{"∧", "logical and", Math, []string{"and"}},
+	{"∨", "logical or", Math, []string{"or"}},
+	{"¬", "not sign", Math, []string{"not", "negation"}},
+
+	{"✓", "check mark", Marks, []string{"done", "yes", "ok", "tick"}},
+	{"✗", "ballot x", Marks, []string{"no", "fail", "cross", "wrong"}},
+	{"•", "bullet", Marks, []string{"dot", "list"}},
+	{"·", "middle dot", Marks, []string{"dot", "separator"}},
+	{"…", "horizontal ellipsis", Marks, []string{"dots", "more"}},
+	{"—", "em dash", Marks, []string{"dash"}},
+	{"–", "en dash", Marks, []string{"dash", "range"}},
+	{"§", "section sign", Marks, []string{"section"}},
+	{"¶", "pilcrow sign", Marks, []string{"paragraph"}},
+	{"†", "dagger", Marks, []string{"footnote"}},
+	{"©", "copyright sign", Marks, nil},
+	{"®", "registered sign", Marks, nil},
+	{"™", "trade mark sign", Marks, []string{"trademark"}},
+	{"★", "black star", Marks, []string{"star", "favorite"}},
+	{"☆", "white star", Marks, []string{"star"}},
+	{"●", "black circle", Marks, []string{"dot", "filled"}},
+	{"○", "white circle", Marks, []string{"empty"}},
+	{"◆", "black diamond", Marks, nil},
+	{"▶", "black right-pointing triangle", Marks, []string{"play", "run"}},
+	{"⚠", "warning sign", Marks, []string{"warning", "caution"}},
+	{"⌘", "place of interest sign", Marks, []string{"command", "cmd", "mac"}},
+	{"⌥", "option key", Marks, []string{"option", "alt", "mac"}},
+	{"⇧", "upwards white arrow", Marks, []string{"shift"}},
+	{"⌃", "up arrowhead", Marks, []string{"control", "ctrl"}},
+	{"⏎", "return symbol", Marks, []string{"enter", "return"}},
+	{"⌫", "erase to the left", Marks, []string{"backspace", "delete"}},
+
+	{"€", "euro sign", Currency, nil},
+	{"£", "pound sign", Currency, nil},
+	{"¥", "yen sign", Currency, nil},
+	{"₹", "indian rupee sign", Currency, []string{"rupee"}},
+	{"₿", "bitcoin sign", Currency, nil},
+
+	{"👍", "thumbs up", Emoji, []string{"yes", "ok", "approve", "+1"}},
+	{"👎", "thumbs down", Emoji, []string{"no", "-1"}},
+	{"🎉", "party popper", Emoji, []string{"tada", "celebrate", "release"}},
+	{"🚀", "rocket", Emoji, []string{"launch", "ship", "deploy", "fast"}},
+	{"🐛", "bug", Emoji, []string{"bug", "issue"}},
+	{"🔥", "fire", Emoji, []string{"hot", "remove"}},
+	{"✨", "sparkles", Emoji, []string{"new", "feature"}},
+	{"⚡", "high voltage", Emoji, []string{"fast", "performance", "zap"}},
+	{"🔧", "wrench", Emoji, []string{"fix", "config", "tool"}},
+	{"🔒", "locked", Emoji, []string{"security", "lock"}},
+	{"📝", "memo", Emoji, []string{"docs", "note", "write"}},
+	{"📦", "package", Emoji, []string{"dependency", "build"}},
+	{"♻", "recycling symbol", Emoji, []string{"refactor", "recycle"}},
+	{"✅", "check mark button", Emoji, []string{"done", "tests", "pass"}},
+	{"❌", "cross mark", Emoji, []string{"fail", "error", "wrong"}},
+	{"❓", "question mark", Emoji, []string{"question", "help"}},
+	{"💡", "light bulb", Emoji, []string{"idea", "tip"}},
+	{"🤔", "thinking face", Emoji, []string{"hmm", "think"}},
+	{"😀", "grinning face", Emoji, []string{"smile", "happy"}},
+	{"😅", "grinning face with sweat", Emoji, []string{"phew", "oops"}},
+	{"🙏", "folded hands", Emoji, []string{"please", "thanks"}},
+	{"👀", "eyes", Emoji, []string{"look", "review"}},
+	{"💥", "collision", Emoji, []string{"breaking", "crash", "boom"}},
+	{"🚧", "construction", Emoji, []string{"wip", "work in progress"}},
+	{"🧪", "test tube", Emoji, []string{"test", "experiment"}},
+	{"📈", "chart increasing", Emoji, []string{"growth", "metrics"}},
+	{"⏱", "stopwatch", Emoji, []string{"time", "timer", "benchmark"}},
+	{"🗑", "wastebasket", Emoji, []string{"delete", "trash", "remove"}},
+	{"❤", "red heart", Emoji, []string{"love", "heart"}},
+}
+
+// Lookup returns the glyph of the catalog with the given text
+func Lookup(text string) (Glyph, bool) {
+	i := slices.IndexFunc(Catalog, func(g Glyph) bool { return g.Text == text })
+	if i < 0 {
+		return Glyph{}, false
+	}
+	return Catalog[i], true
+}
+
+// Search returns the glyphs whose name, keywords or category contain every
+// word of query, those whose name starts with the query first. An empty
+// query returns the whole catalog.
+func Search(query string) []Glyph {
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return slices.Clone(Catalog)
+	}
+	var prefixed, rest []Glyph
+	for _, g := range Catalog {
+		text := strings.ToLower(g.Name + " " + strings.Join(g.Keywords, " ") + " " + g.Category)
+		matched := true
+		for _, word := range words {
+			if !strings.Contains(text, word) {
+				matched = false
+				break
+			}
+		}
+		switch {
+		case !matched:
+		case strings.HasPrefix(g.Name, words[0]) || slices.Contains(g.Keywords, words[0]):
+			prefixed = append(prefixed, g)
+		default:
+			rest = append(rest, g)
+		}
+	}
+	return append(prefixed, rest...)
+}
@@ -0,0 +1,30 @@
+package glyphs
+
+import (
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	results := Search("right arrow")
+	if len(results) == 0 || results[0].Text != "→" {
+		t.Fatalf("Search(right arrow) = %+v, want → first", results)
+	}
+	for _, g := range Search("done") {
+		if g.Text == "→" {
+			t.Error("a glyph matched without containing the query")
+		}
+	}
+	if got := Search("  "); len(got) != len(Catalog) {
+		t.Errorf("an empty query returned %d glyphs, want the catalog", len(got))
+	}
+}
+
+func TestCatalogUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, g := range Catalog {
+		if seen[g.Text] {
+			t.Errorf("%s is in the catalog twice", g.Text)
+		}
+		seen[g.Text] = true
+	}
+}
@@ -0,0 +1,143 @@
+// Package graphics draws images in the terminal with the kitty graphics
+// protocol's Unicode placeholders. An image is uploaded once and then drawn
+// by placeholder characters, which are ordinary text cells that scroll and
+// redraw with the rest of the view.
+//
+// Sixel images are drawn over the cells at the cursor instead, which the
+// renderer overwrites on its next frame, so there is no Sixel variant.
+package graphics
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/ansi/kitty"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+)
+
+// Thumbnail is an image scaled down to a few cells
+type Thumbnail struct {
+	ID      int // Image ID, also encoded in the placeholders' color
+	Columns int
+	Rows    int
+	// Cell is the cell size in pixels the image was scaled for, which the
+	// terminal reports; with another, the image is stretched
+	Cell layout.Dimensions
+	// Upload is the escape sequence uploading the image, which is written to
+	// the terminal once before the thumbnail is shown
+	Upload string
+}
+
+// ID returns the image ID of an image's data. IDs are 24 bits, the most the
+// placeholders' color can carry.
+func ID(data []byte) int {
+	h := fnv.New32a()
+	h.Write(data)
+	return max(1, int(h.Sum32()&0xffffff))
+}
+
+// NewThumbnail decodes a PNG, JPEG or GIF image and scales it to fit
+// in maxColumns by maxRows cells, keeping its aspect ratio
+func NewThumbnail(data []byte, maxColumns, maxRows int) (*Thumbnail, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return nil, fmt.Errorf("empty image")
+	}
+	cell := layout.Current.CellSize()
+	columns, rows := Fit(bounds.Dx(), bounds.Dy(), maxColumns, maxRows)
+
+	scaled := scale(img, columns*cell.Width, rows*cell.Height)
+
+	t := &Thumbnail{ID: ID(data), Columns: columns, Rows: rows, Cell: cell}
+	var upload strings.Builder
+	err = ansi.EncodeKittyGraphics(&upload, scaled, &kitty.Options{
+		Action:           kitty.TransmitAndPut,
+		Quite:            2,
+		ID:               t.ID,
+		Format:           kitty.PNG,
+		VirtualPlacement: true,
+		Columns:          columns,
+		Rows:             rows,
+		Chunk:            true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	t.Upload = upload.String()
+	if os.Getenv("TMUX") != "" {
+		// Needs allow-passthrough, which is how tmux lets images through
+		t.Upload = ansi.TmuxPassthrough(t.Upload)
+	}
+	return t, nil
+}
+
+// Fit returns the cells an image of width by height pixels covers when it is
+// scaled to fit in maxColumns by maxRows cells of the terminal's cell size
+func Fit(width, height, maxColumns, maxRows int) (columns, rows int) {
+	cell := layout.Current.CellSize()
+	// Scale to the width of maxColumns, rounding rows to the nearest
+	columns = maxColumns
+	rows = (2*columns*cell.Width*height + width*cell.Height) / (2 * width * cell.Height)
+	if rows > maxRows {
+		rows = maxRows
+		columns = (2*rows*cell.Height*width + height*cell.Width) / (2 * height * cell.Width)
+	}
+	return max(1, min(columns, maxColumns)), max(1, rows)
+}
+
+// scale shrinks an image to width by height pixels, averaging the pixels
+// each one covers. Images smaller than that are stretched.
+func scale(img image.Image, width, height int) *image.RGBA {
+	bounds := img.Bounds()
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+		for x := range width {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r, g, b, a, n = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa), n+1
+				}
+			}
+			scaled.Set(x, y, color.RGBA64{uint16(r / n), uint16(g / n), uint16(b / n), uint16(a / n)})
+		}
+	}
+	return scaled
+}
+
+// View returns the placeholder cells the terminal draws the thumbnail in.
+// Each cell carries its row and column as diacritics, and the foreground
+// color carries the image ID.
+func (t *Thumbnail) View() string {
+	foreground := fmt.Sprintf("\x1b[38;2;%d;%d;%dm", t.ID>>16&0xff, t.ID>>8&0xff, t.ID&0xff)
+	lines := make([]string, t.Rows)
+	for row := range t.Rows {
+		var line strings.Builder
+		line.WriteString(foreground)
+		for column := range t.Columns {
+			line.WriteRune(kitty.Placeholder)
+			line.WriteRune(kitty.Diacritic(row))
+			line.WriteRune(kitty.Diacritic(column))
+		}
+		line.WriteString("\x1b[39m")
+		lines[row] = line.String()
+	}
+	return strings.Join(lines, "\n")
+}
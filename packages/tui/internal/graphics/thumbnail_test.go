@@ -0,0 +1,66 @@
+package graphics
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+)
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		width, height int
+		columns, rows int
+	}{
+		{800, 400, 24, 6}, // Wide, limited by the columns
+		{400, 800, 8, 8},  // Tall, limited by the rows
+		{100, 100, 16, 8}, // Square, twice as many columns as rows
+		{2000, 10, 24, 1}, // A sliver still takes a row
+	}
+	for _, test := range tests {
+		columns, rows := Fit(test.width, test.height, 24, 8)
+		if columns != test.columns || rows != test.rows {
+			t.Errorf("Fit(%d, %d) = %d, %d, want %d, %d", test.width, test.height, columns, rows, test.columns, test.rows)
+		}
+	}
+
+	// A terminal reporting square cells gets a square image in as many
+	// columns as rows
+	previous := layout.Current
+	defer func() { layout.Current = previous }()
+	layout.Current = &layout.LayoutInfo{Cell: layout.Dimensions{Width: 12, Height: 12}}
+	if columns, rows := Fit(100, 100, 24, 8); columns != 8 || rows != 8 {
+		t.Errorf("Fit(100, 100) with square cells = %d, %d, want 8, 8", columns, rows)
+	}
+}
+
+func TestNewThumbnail(t *testing.T) {
+	t.Setenv("TMUX", "")
+	var data bytes.Buffer
+	if err := png.Encode(&data, image.NewRGBA(image.Rect(0, 0, 64, 32))); err != nil {
+		t.Fatal(err)
+	}
+	thumbnail, err := NewThumbnail(data.Bytes(), 24, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if thumbnail.ID != ID(data.Bytes()) || thumbnail.Columns != 24 || thumbnail.Rows != 6 {
+		t.Errorf("thumbnail = %d, %dx%d", thumbnail.ID, thumbnail.Columns, thumbnail.Rows)
+	}
+	if !strings.HasPrefix(thumbnail.Upload, "\x1b_G") || !strings.Contains(thumbnail.Upload, ",U=1,") || !strings.Contains(thumbnail.Upload, ",a=T") {
+		t.Errorf("upload starts with %q", thumbnail.Upload[:min(40, len(thumbnail.Upload))])
+	}
+
+	view := thumbnail.View()
+	if lipgloss.Width(view) != 24 || lipgloss.Height(view) != 6 {
+		t.Errorf("view is %dx%d cells", lipgloss.Width(view), lipgloss.Height(view))
+	}
+
+	if _, err := NewThumbnail([]byte("not an image"), 24, 8); err == nil {
+		t.Error("NewThumbnail decoded text")
+	}
+}
@@ -0,0 +1,113 @@
+package intelligence
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BackgroundFeature is a feature that runs without being asked to, and may
+// spend tokens doing so
+type BackgroundFeature string
+
+const (
+	BackgroundTitle           BackgroundFeature = "title"           // Session titles a cheap model generates from the first exchange
+	BackgroundRecommendations BackgroundFeature = "recommendations" // Model recommendations after each prompt
+	BackgroundCompaction      BackgroundFeature = "compaction"      // Summaries the server makes when the context fills up
+)
+
+// BackgroundFeatures lists the background features in the order they are
+// shown
+var BackgroundFeatures = []BackgroundFeature{BackgroundTitle, BackgroundRecommendations, BackgroundCompaction}
+
+// DefaultBackgroundDailyCap is the background spend allowed per day, in USD,
+// unless configured otherwise
+const DefaultBackgroundDailyCap = 0.50
+
+// Name returns the name a feature is shown with
+func (f BackgroundFeature) Name() string {
+	switch f {
+	case BackgroundTitle:
+		return "Auto-title"
+	case BackgroundRecommendations:
+		return "Model recommendations"
+	case BackgroundCompaction:
+		return "Auto-compaction"
+	}
+	return string(f)
+}
+
+// Metered reports whether the responses of a feature come with their cost.
+// Recommendations come from the auth bridge, without a message to read
+// it from.
+func (f BackgroundFeature) Metered() bool {
+	return f == BackgroundCompaction || f == BackgroundTitle
+}
+
+// Switchable reports whether a feature can be turned off from here.
+// Compaction runs on the server when a prompt no longer fits, so it can only
+// be watched.
+func (f BackgroundFeature) Switchable() bool {
+	return f != BackgroundCompaction
+}
+
+// ParseBackgroundFeature finds a feature by name, as typed in a command
+func ParseBackgroundFeature(name string) (BackgroundFeature, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, f := range BackgroundFeatures {
+		if string(f) == name || strings.TrimSuffix(string(f), "s") == name {
+			return f, nil
+		}
+	}
+	names := make([]string, len(BackgroundFeatures))
+	for i, f := range BackgroundFeatures {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("no background feature named %q; features are %s", name, strings.Join(names, ", "))
+}
+
+// BackgroundLine is the spend of one background feature
+type BackgroundLine struct {
+	Feature  BackgroundFeature
+	Today    float64
+	Total    float64 // Since the oldest record kept
+	Requests int
+	Tokens   int64
+}
+
+// BackgroundSpend adds up the spend of every background feature, in the
+// order of BackgroundFeatures. Today is the local day of now.
+func BackgroundSpend(records []CostRecord, now time.Time) []BackgroundLine {
+	lines := make([]BackgroundLine, len(BackgroundFeatures))
+	for i, f := range BackgroundFeatures {
+		lines[i].Feature = f
+	}
+	today := now.Local().Format("2006-01-02")
+	for _, record := range records {
+		if record.Feature == "" {
+			continue
+		}
+		for i := range lines {
+			if string(lines[i].Feature) != record.Feature {
+				continue
+			}
+			lines[i].Total += record.Cost
+			lines[i].Requests++
+			lines[i].Tokens += record.Tokens()
+			if record.Time.Local().Format("2006-01-02") == today {
+				lines[i].Today += record.Cost
+			}
+		}
+	}
+	return lines
+}
+
+// BackgroundSpentToday returns the spend of every background feature on the
+// local day of now
+func BackgroundSpentToday(records []CostRecord, now time.Time) float64 {
+	var total float64
+	for _, line := range BackgroundSpend(records, now) {
+		total += line.Today
+	}
+	return total
+}
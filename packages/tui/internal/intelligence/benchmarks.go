@@ -0,0 +1,109 @@
+package intelligence
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// BenchmarkResult is the scored outcome of running a benchmark suite
+// against one model
+type BenchmarkResult struct {
+	Suite      string        `json:"suite"`
+	RunAt      time.Time     `json:"run_at"`
+	Provider   string        `json:"provider"`
+	Model      string        `json:"model"`
+	Cases      int           `json:"cases"`
+	Passed     int           `json:"passed"`
+	Errors     int           `json:"errors"`
+	Accuracy   float64       `json:"accuracy"` // Mean assertion score, 0-1
+	AvgLatency time.Duration `json:"avg_latency"`
+	Cost       float64       `json:"cost"`
+	Tokens     int64         `json:"tokens"`
+}
+
+// BenchmarkHistory stores benchmark results in the insights store so model
+// choices can be based on past runs
+type BenchmarkHistory struct {
+	path    string
+	Results []BenchmarkResult `json:"results"`
+}
+
+// maxBenchmarkResults bounds the number of results kept on disk
+const maxBenchmarkResults = 500
+
+// LoadBenchmarkHistory loads benchmark results from the given file. A
+// missing file yields an empty history.
+func LoadBenchmarkHistory(path string) (*BenchmarkHistory, error) {
+	history := &BenchmarkHistory{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return history, nil
+		}
+		return history, fmt.Errorf("failed to read benchmark history %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, history); err != nil {
+		return history, fmt.Errorf("failed to decode benchmark history %s: %w", path, err)
+	}
+	return history, nil
+}
+
+// Add appends results, dropping the oldest ones beyond the retention limit
+func (h *BenchmarkHistory) Add(results ...BenchmarkResult) {
+	h.Results = append(h.Results, results...)
+	if len(h.Results) > maxBenchmarkResults {
+		h.Results = h.Results[len(h.Results)-maxBenchmarkResults:]
+	}
+}
+
+// Save writes the history back to disk
+func (h *BenchmarkHistory) Save() error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return fmt.Errorf("failed to create insights directory: %w", err)
+	}
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode benchmark history: %w", err)
+	}
+	if err := os.WriteFile(h.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write benchmark history %s: %w", h.path, err)
+	}
+	return nil
+}
+
+// LatestRun returns the results of the most recent run, optionally limited
+// to a suite, ordered by accuracy
+func (h *BenchmarkHistory) LatestRun(suite string) []BenchmarkResult {
+	var latest time.Time
+	for _, r := range h.Results {
+		if (suite == "" || r.Suite == suite) && r.RunAt.After(latest) {
+			latest = r.RunAt
+		}
+	}
+
+	var run []BenchmarkResult
+	for _, r := range h.Results {
+		if r.RunAt.Equal(latest) && (suite == "" || r.Suite == suite) {
+			run = append(run, r)
+		}
+	}
+	SortBenchmarkResults(run)
+	return run
+}
+
+// SortBenchmarkResults orders results by accuracy, then latency, then cost
+func SortBenchmarkResults(results []BenchmarkResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Accuracy != results[j].Accuracy {
+			return results[i].Accuracy > results[j].Accuracy
+		}
+		if results[i].AvgLatency != results[j].AvgLatency {
+			return results[i].AvgLatency < results[j].AvgLatency
+		}
+		return results[i].Cost < results[j].Cost
+	})
+}
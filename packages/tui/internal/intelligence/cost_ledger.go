@@ -0,0 +1,271 @@
+package intelligence
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// CostRecord is the cost of one completed response. Unlike the daily usage,
+// records keep the session, provider and model each cost belongs to.
+type CostRecord struct {
+	Time            time.Time `json:"time"`
+	SessionID       string    `json:"sessionID"`
+	SessionTitle    string    `json:"sessionTitle,omitempty"` // Title when the cost was recorded
+	ProviderID      string    `json:"providerID"`
+	ModelID         string    `json:"modelID"`
+	Cost            float64   `json:"cost"`
+	InputTokens     int64     `json:"input"`
+	OutputTokens    int64     `json:"output"`
+	ReasoningTokens int64     `json:"reasoning,omitempty"`
+	CacheRead       int64     `json:"cacheRead,omitempty"`
+	CacheWrite      int64     `json:"cacheWrite,omitempty"`
+	Feature         string    `json:"feature,omitempty"` // Background feature behind the response; empty for prompts
+}
+
+// Tokens returns all the tokens of a record
+func (r CostRecord) Tokens() int64 {
+	return r.InputTokens + r.OutputTokens + r.ReasoningTokens + r.CacheRead + r.CacheWrite
+}
+
+// CostLedger keeps every cost record, appending each to a JSON lines file
+type CostLedger struct {
+	path      string // Empty keeps the records in memory
+	retention time.Duration
+	records   []CostRecord
+}
+
+// LoadCostLedger loads the records of path and appends every later record
+// there. Records older than retentionDays are dropped; zero keeps them all.
+// A missing file yields an empty ledger.
+func LoadCostLedger(path string, retentionDays int) (*CostLedger, error) {
+	l := &CostLedger{path: path, retention: time.Duration(retentionDays) * 24 * time.Hour}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return l, nil
+		}
+		l.path = ""
+		return l, fmt.Errorf("failed to read costs %s: %w", path, err)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var record CostRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			// A line cut short by a crash is skipped rather than losing the rest
+			continue
+		}
+		l.records = append(l.records, record)
+	}
+	if l.prune(time.Now()) > 0 {
+		return l, l.rewrite()
+	}
+	return l, nil
+}
+
+// Add records a cost, appending it to the ledger's file
+func (l *CostLedger) Add(record CostRecord) error {
+	l.records = append(l.records, record)
+	if l.path == "" {
+		return nil
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode cost: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create insights directory: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write costs %s: %w", l.path, err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write costs %s: %w", l.path, err)
+	}
+	return nil
+}
+
+// Records returns the records, oldest first
+func (l *CostLedger) Records() []CostRecord {
+	return l.records
+}
+
+// MoveSession gives the records of one session to another, as when the
+// first was merged into the second, and returns the number moved
+func (l *CostLedger) MoveSession(from, to, title string) (int, error) {
+	moved := 0
+	for i := range l.records {
+		if l.records[i].SessionID == from {
+			l.records[i].SessionID = to
+			l.records[i].SessionTitle = title
+			moved++
+		}
+	}
+	if moved == 0 || l.path == "" {
+		return moved, nil
+	}
+	return moved, l.rewrite()
+}
+
+// prune drops the records older than the retention window and returns the
+// number dropped
+func (l *CostLedger) prune(now time.Time) int {
+	if l.retention <= 0 {
+		return 0
+	}
+	cutoff := now.Add(-l.retention)
+	kept := l.records[:0]
+	for _, record := range l.records {
+		if !record.Time.Before(cutoff) {
+			kept = append(kept, record)
+		}
+	}
+	pruned := len(l.records) - len(kept)
+	l.records = kept
+	return pruned
+}
+
+// rewrite writes the whole ledger to its file, atomically
+func (l *CostLedger) rewrite() error {
+	var buf bytes.Buffer
+	for _, record := range l.records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to encode cost: %w", err)
+		}
+		buf.Write(append(line, '\n'))
+	}
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write costs %s: %w", l.path, err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return fmt.Errorf("failed to write costs %s: %w", l.path, err)
+	}
+	return nil
+}
+
+// CostGrouping is what costs are added up by
+type CostGrouping string
+
+const (
+	CostBySession  CostGrouping = "session"
+	CostByProvider CostGrouping = "provider"
+	CostByModel    CostGrouping = "model"
+	CostByDay      CostGrouping = "day"
+)
+
+// CostGroupings lists the groupings in the order they are shown
+var CostGroupings = []CostGrouping{CostBySession, CostByProvider, CostByModel, CostByDay}
+
+// CostLine is the total of the records of a group
+type CostLine struct {
+	Key      string // Session ID, provider ID, provider/model or YYYY-MM-DD
+	Label    string
+	Cost     float64
+	Requests int
+	Tokens   int64
+	Last     time.Time // Time of the group's latest record
+}
+
+// CostBreakdown adds up records by a grouping. Days are listed newest
+// first; other groups by cost, highest first.
+func CostBreakdown(records []CostRecord, by CostGrouping) []CostLine {
+	lines := make(map[string]*CostLine)
+	var keys []string
+	for _, record := range records {
+		var key, label string
+		switch by {
+		case CostBySession:
+			key, label = record.SessionID, record.SessionTitle
+			if label == "" {
+				label = record.SessionID
+			}
+		case CostByProvider:
+			key, label = record.ProviderID, record.ProviderID
+		case CostByModel:
+			key, label = record.ProviderID+"/"+record.ModelID, record.ModelID
+		case CostByDay:
+			key = record.Time.Local().Format("2006-01-02")
+			label = record.Time.Local().Format("Mon Jan 2")
+		}
+		line, ok := lines[key]
+		if !ok {
+			line = &CostLine{Key: key, Label: label}
+			lines[key] = line
+			keys = append(keys, key)
+		}
+		if by == CostBySession && record.SessionTitle != "" {
+			// The latest title wins, as sessions are renamed
+			line.Label = record.SessionTitle
+		}
+		line.Cost += record.Cost
+		line.Requests++
+		line.Tokens += record.Tokens()
+		if record.Time.After(line.Last) {
+			line.Last = record.Time
+		}
+	}
+
+	breakdown := make([]CostLine, 0, len(keys))
+	for _, key := range keys {
+		breakdown = append(breakdown, *lines[key])
+	}
+	sort.SliceStable(breakdown, func(i, j int) bool {
+		if by == CostByDay {
+			return breakdown[i].Key > breakdown[j].Key
+		}
+		return breakdown[i].Cost > breakdown[j].Cost
+	})
+	return breakdown
+}
+
+// costCSVHeader names the columns of the CSV export
+var costCSVHeader = []string{
+	"time", "session_id", "session", "provider", "model",
+	"input_tokens", "output_tokens", "reasoning_tokens", "cache_read_tokens", "cache_write_tokens", "cost_usd", "feature",
+}
+
+// WriteCostCSV writes records as CSV, one row per response, for expense
+// reports and spreadsheets
+func WriteCostCSV(w io.Writer, records []CostRecord) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(costCSVHeader); err != nil {
+		return err
+	}
+	for _, r := range records {
+		row := []string{
+			r.Time.Format(time.RFC3339),
+			r.SessionID,
+			r.SessionTitle,
+			r.ProviderID,
+			r.ModelID,
+			strconv.FormatInt(r.InputTokens, 10),
+			strconv.FormatInt(r.OutputTokens, 10),
+			strconv.FormatInt(r.ReasoningTokens, 10),
+			strconv.FormatInt(r.CacheRead, 10),
+			strconv.FormatInt(r.CacheWrite, 10),
+			strconv.FormatFloat(r.Cost, 'f', 6, 64),
+		}
+		if err := out.Write(row); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
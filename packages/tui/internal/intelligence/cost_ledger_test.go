@@ -0,0 +1,99 @@
+package intelligence
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCostLedger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "insights", "costs.jsonl")
+	now := time.Now()
+	ledger, err := LoadCostLedger(path, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records := []CostRecord{
+		{Time: now.AddDate(0, 0, -40), SessionID: "ses_0", ProviderID: "openai", ModelID: "gpt-5", Cost: 9},
+		{Time: now.Add(-2 * time.Hour), SessionID: "ses_1", SessionTitle: "fix", ProviderID: "anthropic", ModelID: "sonnet", Cost: 0.5, InputTokens: 100},
+		{Time: now.Add(-time.Hour), SessionID: "ses_2", SessionTitle: "docs", ProviderID: "openai", ModelID: "gpt-5", Cost: 1, OutputTokens: 50},
+		{Time: now, SessionID: "ses_1", SessionTitle: "fix the build", ProviderID: "anthropic", ModelID: "haiku", Cost: 0.25, CacheRead: 10},
+	}
+	for _, record := range records {
+		if err := ledger.Add(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The record past the retention is dropped when the ledger is loaded again
+	ledger, err = LoadCostLedger(path, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ledger.Records()) != 3 {
+		t.Fatalf("loaded %d records, want 3", len(ledger.Records()))
+	}
+	if data, _ := os.ReadFile(path); strings.Count(string(data), "\n") != 3 {
+		t.Errorf("file has %d records after pruning, want 3", strings.Count(string(data), "\n"))
+	}
+
+	sessions := CostBreakdown(ledger.Records(), CostBySession)
+	if len(sessions) != 2 || sessions[0].Key != "ses_2" || sessions[1].Label != "fix the build" || sessions[1].Requests != 2 || sessions[1].Tokens != 110 {
+		t.Errorf("by session = %+v", sessions)
+	}
+	providers := CostBreakdown(ledger.Records(), CostByProvider)
+	if len(providers) != 2 || providers[0].Key != "openai" || providers[1].Cost != 0.75 {
+		t.Errorf("by provider = %+v", providers)
+	}
+	if models := CostBreakdown(ledger.Records(), CostByModel); len(models) != 3 {
+		t.Errorf("by model = %+v", models)
+	}
+
+	var csv bytes.Buffer
+	if err := WriteCostCSV(&csv, ledger.Records()); err != nil {
+		t.Fatal(err)
+	}
+	rows := strings.Split(strings.TrimSpace(csv.String()), "\n")
+	if len(rows) != 4 || !strings.HasPrefix(rows[0], "time,session_id") || !strings.HasSuffix(rows[3], ",0.250000") {
+		t.Errorf("csv = %q", csv.String())
+	}
+
+	// Merging a session into another combines their cost
+	if moved, err := ledger.MoveSession("ses_1", "ses_2", "docs"); err != nil || moved != 2 {
+		t.Fatalf("MoveSession = %d, %v", moved, err)
+	}
+	ledger, _ = LoadCostLedger(path, 30)
+	if sessions := CostBreakdown(ledger.Records(), CostBySession); len(sessions) != 1 || sessions[0].Cost != 1.75 {
+		t.Errorf("by session after the merge = %+v", sessions)
+	}
+}
+
+func TestBackgroundSpend(t *testing.T) {
+	now := time.Now()
+	records := []CostRecord{
+		{Time: now, Cost: 1},
+		{Time: now, Cost: 0.25, InputTokens: 100, Feature: string(BackgroundCompaction)},
+		{Time: now.AddDate(0, 0, -2), Cost: 0.5, Feature: string(BackgroundCompaction)},
+	}
+	spend := BackgroundSpend(records, now)
+	if len(spend) != len(BackgroundFeatures) {
+		t.Fatalf("got %d features, want %d", len(spend), len(BackgroundFeatures))
+	}
+	compaction := spend[2]
+	if compaction.Feature != BackgroundCompaction || compaction.Today != 0.25 || compaction.Total != 0.75 || compaction.Requests != 2 || compaction.Tokens != 100 {
+		t.Errorf("compaction = %+v", compaction)
+	}
+	if got := BackgroundSpentToday(records, now); got != 0.25 {
+		t.Errorf("spent today = %v, want 0.25, leaving out prompts", got)
+	}
+
+	if f, err := ParseBackgroundFeature("Recommendation"); err != nil || f != BackgroundRecommendations {
+		t.Errorf("ParseBackgroundFeature(Recommendation) = %q, %v", f, err)
+	}
+	if _, err := ParseBackgroundFeature("ghost"); err == nil {
+		t.Error("ParseBackgroundFeature(ghost) found a feature")
+	}
+}
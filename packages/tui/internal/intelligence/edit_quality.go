@@ -0,0 +1,114 @@
+package intelligence
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// maxEditQualitySignals bounds the number of signals kept on disk
+const maxEditQualitySignals = 1000
+
+// satisfiedChurn is the highest share of reworked lines still counted as a
+// satisfying edit
+const satisfiedChurn = 0.2
+
+// EditQualitySignal measures how much a file was changed by hand after the AI
+// applied an edit to it. Heavy rework is an implicit sign of a poor answer.
+type EditQualitySignal struct {
+	Provider     string    `json:"provider"`
+	Model        string    `json:"model"`
+	File         string    `json:"file"`
+	AppliedAt    time.Time `json:"applied_at"`
+	MeasuredAt   time.Time `json:"measured_at"`
+	AppliedLines int       `json:"applied_lines"` // Lines in the file right after the edit
+	ChangedLines int       `json:"changed_lines"` // Lines added or removed by hand since
+}
+
+// Churn returns the share of the applied file that was reworked, from 0 to 1
+func (s EditQualitySignal) Churn() float64 {
+	if s.ChangedLines == 0 {
+		return 0
+	}
+	return min(float64(s.ChangedLines)/float64(max(s.AppliedLines, 1)), 1)
+}
+
+// EditQualityHistory is the persisted list of edit quality signals
+type EditQualityHistory struct {
+	path    string
+	Signals []EditQualitySignal `json:"signals"`
+}
+
+// LoadEditQualityHistory loads signals from the given file. A missing file
+// yields an empty history.
+func LoadEditQualityHistory(path string) (*EditQualityHistory, error) {
+	history := &EditQualityHistory{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return history, nil
+		}
+		return history, fmt.Errorf("failed to read edit quality history %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, history); err != nil {
+		return history, fmt.Errorf("failed to decode edit quality history %s: %w", path, err)
+	}
+	return history, nil
+}
+
+// Add appends signals, dropping the oldest beyond the history limit
+func (h *EditQualityHistory) Add(signals ...EditQualitySignal) {
+	h.Signals = append(h.Signals, signals...)
+	if len(h.Signals) > maxEditQualitySignals {
+		h.Signals = h.Signals[len(h.Signals)-maxEditQualitySignals:]
+	}
+}
+
+// Save writes the history back to disk
+func (h *EditQualityHistory) Save() error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return fmt.Errorf("failed to create insights directory: %w", err)
+	}
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode edit quality history: %w", err)
+	}
+	if err := os.WriteFile(h.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write edit quality history %s: %w", h.path, err)
+	}
+	return nil
+}
+
+// ChurnByModel returns the mean churn per "provider/model"
+func (h *EditQualityHistory) ChurnByModel() map[string]float64 {
+	totals := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, signal := range h.Signals {
+		key := signal.Provider + "/" + signal.Model
+		totals[key] += signal.Churn()
+		counts[key]++
+	}
+	churn := make(map[string]float64, len(totals))
+	for key, total := range totals {
+		churn[key] = total / float64(counts[key])
+	}
+	return churn
+}
+
+// Usage converts the signals into usage records for the recommendation
+// engine. An edit counts as satisfying when little of it was reworked.
+func (h *EditQualityHistory) Usage() []ModelUsage {
+	usage := make([]ModelUsage, 0, len(h.Signals))
+	for _, signal := range h.Signals {
+		usage = append(usage, ModelUsage{
+			Provider:  signal.Provider,
+			Model:     signal.Model,
+			UsedAt:    signal.AppliedAt,
+			TaskType:  "edit",
+			Satisfied: signal.Churn() <= satisfiedChurn,
+		})
+	}
+	return usage
+}
@@ -0,0 +1,34 @@
+package intelligence
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEditQualityFeedsRecommendations(t *testing.T) {
+	history := &EditQualityHistory{}
+	for _, changed := range []int{0, 1, 8, 9} {
+		history.Add(EditQualitySignal{Provider: "anthropic", Model: "claude-3-5-haiku-20241022", AppliedLines: 10, ChangedLines: changed})
+	}
+	engine := NewRecommendationEngine()
+	engine.AddUsage(history.Usage()...)
+
+	rate, used := engine.Satisfaction("Anthropic", "claude-3-5-haiku-20241022")
+	if used != 4 || rate != 0.5 {
+		t.Fatalf("Satisfaction = %v of %d, want 0.5 of 4", rate, used)
+	}
+
+	history.Add(EditQualitySignal{Provider: "anthropic", Model: "claude-3-5-haiku-20241022", AppliedLines: 10, ChangedLines: 10})
+	engine = NewRecommendationEngine()
+	engine.AddUsage(history.Usage()...)
+	noted := false
+	for _, rec := range engine.GetRecommendations(TaskContext{Priority: "cost", TimeOfDay: time.Now()}) {
+		if rec.Model == "claude-3-5-haiku-20241022" {
+			noted = strings.Contains(rec.Reasoning, "needed rework")
+		}
+	}
+	if !noted {
+		t.Error("the rework of a model's edits isn't noted in its recommendation")
+	}
+}
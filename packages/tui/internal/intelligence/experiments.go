@@ -0,0 +1,267 @@
+package intelligence
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ExperimentArm identifies one side of an A/B routing experiment
+type ExperimentArm string
+
+const (
+	ArmA ExperimentArm = "A"
+	ArmB ExperimentArm = "B"
+)
+
+// ExperimentSignal is an outcome signal observed for a routed prompt
+type ExperimentSignal string
+
+const (
+	SignalAccept   ExperimentSignal = "accept"   // The user moved on without retrying
+	SignalRetry    ExperimentSignal = "retry"    // The user undid the answer
+	SignalPositive ExperimentSignal = "positive" // Explicit positive feedback
+	SignalNegative ExperimentSignal = "negative" // Explicit negative feedback
+)
+
+// minResolvedPerArm is the number of resolved prompts each arm needs before a
+// winner is declared
+const minResolvedPerArm = 5
+
+// maxTrackedAssignments bounds the prompt assignments kept per experiment
+const maxTrackedAssignments = 1000
+
+// ArmOutcome counts the signals recorded for an arm
+type ArmOutcome struct {
+	Prompts  int `json:"prompts"`
+	Accepts  int `json:"accepts"`
+	Retries  int `json:"retries"`
+	Positive int `json:"positive"`
+	Negative int `json:"negative"`
+}
+
+// Resolved returns the number of prompts that received an accept or retry
+func (o ArmOutcome) Resolved() int {
+	return o.Accepts + o.Retries
+}
+
+// Score rates the arm between -1 and 1: accepts and positive feedback count
+// for it, retries and negative feedback against it
+func (o ArmOutcome) Score() float64 {
+	signals := o.Accepts + o.Retries + o.Positive + o.Negative
+	if signals == 0 {
+		return 0
+	}
+	return float64(o.Accepts+o.Positive-o.Retries-o.Negative) / float64(signals)
+}
+
+// Assignment records which arm answered a prompt and whether it was resolved
+type Assignment struct {
+	Arm      ExperimentArm `json:"arm"`
+	Resolved bool          `json:"resolved"`
+	Order    int           `json:"order"`
+}
+
+// Experiment randomly routes a share of prompts between two models and
+// tracks outcome signals per arm
+type Experiment struct {
+	Name         string                       `json:"name"`
+	ArmA         string                       `json:"arm_a"`         // "provider/model"
+	ArmB         string                       `json:"arm_b"`         // "provider/model"
+	SplitPercent int                          `json:"split_percent"` // Share of prompts routed to arm B
+	StartedAt    time.Time                    `json:"started_at"`
+	EndedAt      time.Time                    `json:"ended_at,omitempty"`
+	Outcomes     map[ExperimentArm]ArmOutcome `json:"outcomes"`
+	Assignments  map[string]Assignment        `json:"assignments"` // user message ID -> assignment
+	nextOrder    int
+}
+
+// NewExperiment creates an experiment between two "provider/model" arms
+func NewExperiment(armA, armB string, splitPercent int) (*Experiment, error) {
+	if armA == "" || armB == "" {
+		return nil, fmt.Errorf("an experiment needs two models")
+	}
+	if armA == armB {
+		return nil, fmt.Errorf("both arms use %s", armA)
+	}
+	if splitPercent <= 0 || splitPercent >= 100 {
+		return nil, fmt.Errorf("split must be between 1 and 99 percent, got %d", splitPercent)
+	}
+	return &Experiment{
+		Name:         armA + " vs " + armB,
+		ArmA:         armA,
+		ArmB:         armB,
+		SplitPercent: splitPercent,
+		StartedAt:    time.Now(),
+		Outcomes:     map[ExperimentArm]ArmOutcome{ArmA: {}, ArmB: {}},
+		Assignments:  make(map[string]Assignment),
+	}, nil
+}
+
+// Model returns the "provider/model" reference of an arm
+func (e *Experiment) Model(arm ExperimentArm) string {
+	if arm == ArmB {
+		return e.ArmB
+	}
+	return e.ArmA
+}
+
+// Assign picks an arm for a prompt. roll is a uniform random number in
+// [0, 1) so callers control the randomness.
+func (e *Experiment) Assign(messageID string, roll float64) ExperimentArm {
+	arm := ArmA
+	if roll*100 < float64(e.SplitPercent) {
+		arm = ArmB
+	}
+
+	// Resolve the previous prompt: asking something new means it was accepted
+	e.resolvePending(SignalAccept)
+
+	e.nextOrder++
+	e.Assignments[messageID] = Assignment{Arm: arm, Order: e.nextOrder}
+	outcome := e.Outcomes[arm]
+	outcome.Prompts++
+	e.Outcomes[arm] = outcome
+	e.pruneAssignments()
+	return arm
+}
+
+// Record applies a signal to the prompt with the given user message ID.
+// Accept and retry resolve a prompt and are counted at most once per prompt.
+func (e *Experiment) Record(messageID string, signal ExperimentSignal) bool {
+	assignment, ok := e.Assignments[messageID]
+	if !ok {
+		return false
+	}
+	if (signal == SignalAccept || signal == SignalRetry) && assignment.Resolved {
+		return false
+	}
+
+	outcome := e.Outcomes[assignment.Arm]
+	switch signal {
+	case SignalAccept:
+		outcome.Accepts++
+		assignment.Resolved = true
+	case SignalRetry:
+		outcome.Retries++
+		assignment.Resolved = true
+	case SignalPositive:
+		outcome.Positive++
+	case SignalNegative:
+		outcome.Negative++
+	}
+	e.Outcomes[assignment.Arm] = outcome
+	e.Assignments[messageID] = assignment
+	return true
+}
+
+// LatestAssignment returns the user message ID of the most recent routed prompt
+func (e *Experiment) LatestAssignment() (string, bool) {
+	latestID, latestOrder := "", 0
+	for id, assignment := range e.Assignments {
+		if assignment.Order > latestOrder {
+			latestID, latestOrder = id, assignment.Order
+		}
+	}
+	return latestID, latestID != ""
+}
+
+// Winner returns the arm with the better score once both arms have enough
+// resolved prompts. ok is false while the experiment is inconclusive.
+func (e *Experiment) Winner() (arm ExperimentArm, ok bool) {
+	a, b := e.Outcomes[ArmA], e.Outcomes[ArmB]
+	if a.Resolved() < minResolvedPerArm || b.Resolved() < minResolvedPerArm {
+		return "", false
+	}
+	switch {
+	case a.Score() > b.Score():
+		return ArmA, true
+	case b.Score() > a.Score():
+		return ArmB, true
+	}
+	return "", false
+}
+
+func (e *Experiment) resolvePending(signal ExperimentSignal) {
+	if id, ok := e.LatestAssignment(); ok {
+		e.Record(id, signal)
+	}
+}
+
+func (e *Experiment) pruneAssignments() {
+	if len(e.Assignments) <= maxTrackedAssignments {
+		return
+	}
+	cutoff := e.nextOrder - maxTrackedAssignments
+	for id, assignment := range e.Assignments {
+		if assignment.Order <= cutoff {
+			delete(e.Assignments, id)
+		}
+	}
+}
+
+// ExperimentStore persists the active experiment and finished ones in the
+// insights store
+type ExperimentStore struct {
+	path     string
+	Active   *Experiment  `json:"active,omitempty"`
+	Finished []Experiment `json:"finished"`
+}
+
+// LoadExperimentStore loads experiments from the given file. A missing file
+// yields an empty store.
+func LoadExperimentStore(path string) (*ExperimentStore, error) {
+	store := &ExperimentStore{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return store, fmt.Errorf("failed to read experiments %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return store, fmt.Errorf("failed to decode experiments %s: %w", path, err)
+	}
+	if store.Active != nil {
+		for _, assignment := range store.Active.Assignments {
+			store.Active.nextOrder = max(store.Active.nextOrder, assignment.Order)
+		}
+	}
+	return store, nil
+}
+
+// Start replaces any active experiment with a new one
+func (s *ExperimentStore) Start(experiment *Experiment) {
+	s.Stop()
+	s.Active = experiment
+}
+
+// Stop ends the active experiment and moves it to the finished list
+func (s *ExperimentStore) Stop() *Experiment {
+	if s.Active == nil {
+		return nil
+	}
+	stopped := s.Active
+	stopped.EndedAt = time.Now()
+	stopped.Assignments = nil
+	s.Finished = append(s.Finished, *stopped)
+	s.Active = nil
+	return stopped
+}
+
+// Save writes the store back to disk
+func (s *ExperimentStore) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create insights directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode experiments: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write experiments %s: %w", s.path, err)
+	}
+	return nil
+}
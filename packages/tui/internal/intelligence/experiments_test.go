@@ -0,0 +1,75 @@
+package intelligence
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestExperimentRouting(t *testing.T) {
+	e, err := NewExperiment("a/one", "b/two", 30)
+	if err != nil {
+		t.Fatalf("failed to create experiment: %v", err)
+	}
+	if arm := e.Assign("m1", 0.1); arm != ArmB {
+		t.Errorf("expected roll below split to route to B, got %s", arm)
+	}
+	if arm := e.Assign("m2", 0.5); arm != ArmA {
+		t.Errorf("expected roll above split to route to A, got %s", arm)
+	}
+
+	// m1 was accepted implicitly when m2 was sent; a late retry is ignored
+	if e.Record("m1", SignalRetry) {
+		t.Error("expected retry of an accepted prompt to be ignored")
+	}
+	e.Record("m2", SignalRetry)
+	e.Record("m2", SignalNegative)
+
+	a, b := e.Outcomes[ArmA], e.Outcomes[ArmB]
+	if b.Accepts != 1 || a.Retries != 1 || a.Negative != 1 {
+		t.Errorf("unexpected outcomes A=%+v B=%+v", a, b)
+	}
+	if _, ok := e.Winner(); ok {
+		t.Error("expected no winner with too few resolved prompts")
+	}
+}
+
+func TestExperimentWinner(t *testing.T) {
+	e, _ := NewExperiment("a/one", "b/two", 50)
+	e.Outcomes[ArmA] = ArmOutcome{Prompts: 6, Accepts: 2, Retries: 4}
+	e.Outcomes[ArmB] = ArmOutcome{Prompts: 6, Accepts: 5, Retries: 1}
+	if arm, ok := e.Winner(); !ok || arm != ArmB {
+		t.Errorf("expected B to win, got %s %v", arm, ok)
+	}
+}
+
+func TestExperimentStorePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "insights", "experiments.json")
+	store, err := LoadExperimentStore(path)
+	if err != nil {
+		t.Fatalf("failed to load empty store: %v", err)
+	}
+	e, _ := NewExperiment("a/one", "b/two", 50)
+	store.Start(e)
+	e.Assign("m1", 0.9)
+	e.Assign("m2", 0.9)
+	if err := store.Save(); err != nil {
+		t.Fatalf("failed to save store: %v", err)
+	}
+
+	loaded, err := LoadExperimentStore(path)
+	if err != nil || loaded.Active == nil {
+		t.Fatalf("failed to reload store: %v", err)
+	}
+	if id, _ := loaded.Active.LatestAssignment(); id != "m2" {
+		t.Errorf("expected latest assignment m2, got %q", id)
+	}
+	loaded.Active.Assign("m3", 0.9)
+	if id, _ := loaded.Active.LatestAssignment(); id != "m3" {
+		t.Errorf("expected assignment order to continue after reload, got %q", id)
+	}
+
+	loaded.Stop()
+	if loaded.Active != nil || len(loaded.Finished) != 1 {
+		t.Errorf("expected experiment to move to finished, got %+v", loaded)
+	}
+}
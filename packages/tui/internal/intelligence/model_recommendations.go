@@ -2,6 +2,7 @@ package intelligence
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/aaronmrosenthal/rycode/internal/styles"
@@ -54,6 +55,31 @@ func NewRecommendationEngine() *RecommendationEngine {
 	}
 }
 
+// AddUsage feeds observed usage, such as edit quality signals, into the
+// engine's history
+func (r *RecommendationEngine) AddUsage(usage ...ModelUsage) {
+	r.usageHistory = append(r.usageHistory, usage...)
+}
+
+// Satisfaction returns the share of a model's recorded uses that satisfied
+// the user, and how many uses were recorded. Providers are compared without
+// case, as their IDs and display names differ in it.
+func (r *RecommendationEngine) Satisfaction(provider, model string) (float64, int) {
+	used, satisfied := 0, 0
+	for _, usage := range r.usageHistory {
+		if strings.EqualFold(usage.Provider, provider) && usage.Model == model {
+			used++
+			if usage.Satisfied {
+				satisfied++
+			}
+		}
+	}
+	if used == 0 {
+		return 0, 0
+	}
+	return float64(satisfied) / float64(used), used
+}
+
 // GetRecommendations returns top 3 model recommendations for a task
 func (r *RecommendationEngine) GetRecommendations(ctx TaskContext) []ModelRecommendation {
 	recommendations := []ModelRecommendation{}
@@ -339,6 +365,19 @@ func (r *RecommendationEngine) adjustForUserPreferences(recs []ModelRecommendati
 		}
 	}
 
+	// Weigh recorded history by satisfaction rate so long histories don't
+	// outweigh the base score
+	for i, rec := range recs {
+		rate, used := r.Satisfaction(rec.Provider, rec.Model)
+		if used == 0 {
+			continue
+		}
+		recs[i].Score += (rate - 0.5) * 10
+		if rate < 0.5 {
+			recs[i].Reasoning += " (Its recent edits often needed rework)"
+		}
+	}
+
 	// Time-of-day preferences
 	hour := ctx.TimeOfDay.Hour()
 	if hour >= 9 && hour <= 17 {
@@ -39,7 +39,8 @@ type PredictiveBudget struct {
 	monthlyLimit      float64
 	dailyLimit        float64
 	currentMonthSpend float64
-	dailySpendHistory []float64
+	dailySpendHistory []float64 // Spend of each day of the month, from its first
+	month             time.Time // First day of the month the spend is of
 	usageInsights     *UsageInsights
 }
 
@@ -53,12 +54,42 @@ func NewPredictiveBudget(monthlyLimit, dailyLimit float64, insights *UsageInsigh
 	}
 }
 
-// RecordDailySpend records spending for a day
-func (p *PredictiveBudget) RecordDailySpend(amount float64) {
-	p.dailySpendHistory = append(p.dailySpendHistory, amount)
+// RecordDailySpend adds spending to the day it was made on. Spending of a
+// new month starts the month's history over, and that of a month gone is
+// left out.
+func (p *PredictiveBudget) RecordDailySpend(day time.Time, amount float64) {
+	month := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	switch {
+	case p.month.IsZero() || month.After(p.month):
+		p.month = month
+		p.dailySpendHistory = nil
+		p.currentMonthSpend = 0
+	case month.Before(p.month):
+		return
+	}
+	for len(p.dailySpendHistory) < day.Day() {
+		p.dailySpendHistory = append(p.dailySpendHistory, 0)
+	}
+	p.dailySpendHistory[day.Day()-1] += amount
 	p.currentMonthSpend += amount
 }
 
+// SyncFromInsights replaces the recorded spend with the current month's
+// daily costs from the usage insights, one entry per day up to now, so the
+// forecast follows real spending
+func (p *PredictiveBudget) SyncFromInsights(now time.Time) {
+	if p.usageInsights == nil {
+		return
+	}
+	p.month = time.Time{}
+	p.RecordDailySpend(now, 0)
+	for _, day := range p.usageInsights.dailyData {
+		if date := day.Date.In(now.Location()); !date.After(now) {
+			p.RecordDailySpend(date, day.Cost)
+		}
+	}
+}
+
 // GetForecast generates a budget forecast for the current month
 func (p *PredictiveBudget) GetForecast() BudgetForecast {
 	now := time.Now()
@@ -0,0 +1,42 @@
+package intelligence
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPredictiveBudgetSyncFromInsights(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.Local)
+	insights := NewUsageInsights()
+	insights.AddUsage(time.Date(2026, 2, 27, 9, 0, 0, 0, time.Local), 5, 1, 100, "m", "p")
+	insights.AddUsage(time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local), 1.5, 1, 100, "m", "p")
+	insights.AddUsage(time.Date(2026, 3, 2, 11, 0, 0, 0, time.Local), 0.5, 1, 100, "m", "p")
+	insights.AddUsage(time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local), 3, 1, 100, "m", "p")
+
+	budget := NewPredictiveBudget(50, 10, insights)
+	budget.SyncFromInsights(now)
+
+	if budget.currentMonthSpend != 5 {
+		t.Errorf("expected only this month's spend of $5, got $%.2f", budget.currentMonthSpend)
+	}
+	if len(budget.dailySpendHistory) != 10 {
+		t.Fatalf("expected one entry per day of the month so far, got %d", len(budget.dailySpendHistory))
+	}
+	if budget.dailySpendHistory[1] != 2 || budget.dailySpendHistory[9] != 3 || budget.dailySpendHistory[5] != 0 {
+		t.Errorf("unexpected daily history %v", budget.dailySpendHistory)
+	}
+}
+
+func TestPredictiveBudgetRecordDailySpend(t *testing.T) {
+	budget := NewPredictiveBudget(50, 10, nil)
+	budget.RecordDailySpend(time.Date(2026, 3, 3, 9, 0, 0, 0, time.Local), 1)
+	budget.RecordDailySpend(time.Date(2026, 3, 3, 18, 0, 0, 0, time.Local), 0.5)
+	budget.RecordDailySpend(time.Date(2026, 2, 28, 9, 0, 0, 0, time.Local), 4)
+	if budget.currentMonthSpend != 1.5 || len(budget.dailySpendHistory) != 3 || budget.dailySpendHistory[2] != 1.5 {
+		t.Errorf("spend $%.2f over %v, want $1.50 on the 3rd and last month's left out", budget.currentMonthSpend, budget.dailySpendHistory)
+	}
+	budget.RecordDailySpend(time.Date(2026, 4, 1, 9, 0, 0, 0, time.Local), 2)
+	if budget.currentMonthSpend != 2 || len(budget.dailySpendHistory) != 1 {
+		t.Errorf("spend $%.2f over %v, want the new month started over", budget.currentMonthSpend, budget.dailySpendHistory)
+	}
+}
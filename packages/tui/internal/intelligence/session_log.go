@@ -0,0 +1,73 @@
+package intelligence
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SessionRecord sums up a session as it was left
+type SessionRecord struct {
+	Time         time.Time     `json:"time"` // When the session was left
+	SessionID    string        `json:"sessionID"`
+	SessionTitle string        `json:"sessionTitle,omitempty"`
+	Duration     time.Duration `json:"duration"`
+	Messages     int           `json:"messages"`
+	InputTokens  int64         `json:"input"`
+	OutputTokens int64         `json:"output"`
+	Cost         float64       `json:"cost"`
+	FilesChanged int           `json:"filesChanged"`
+	TestRuns     int           `json:"testRuns"`
+	TestFailures int           `json:"testFailures,omitempty"`
+}
+
+// AppendSessionRecord appends a record to a JSON lines file
+func AppendSessionRecord(path string, record SessionRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create insights directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write sessions %s: %w", path, err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write sessions %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadSessionRecords reads the records of a JSON lines file, oldest first.
+// A missing file has none.
+func LoadSessionRecords(path string) ([]SessionRecord, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read sessions %s: %w", path, err)
+	}
+	var records []SessionRecord
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var record SessionRecord
+		// A line cut short by a crash is skipped rather than losing the rest
+		if err := json.Unmarshal(line, &record); err == nil {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
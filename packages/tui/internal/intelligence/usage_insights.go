@@ -2,11 +2,13 @@ package intelligence
 
 import (
 	"fmt"
+	"log/slog"
 	"math"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/aaronmrosenthal/rycode/internal/components/sparkline"
 	"github.com/aaronmrosenthal/rycode/internal/styles"
 	"github.com/aaronmrosenthal/rycode/internal/theme"
 	"github.com/aaronmrosenthal/rycode/internal/typography"
@@ -14,12 +16,12 @@ import (
 
 // UsageData represents usage statistics for a time period
 type UsageData struct {
-	Date      time.Time
-	Cost      float64
-	Requests  int
-	Tokens    int64
-	Models    map[string]int // Model ID -> usage count
-	Providers map[string]int // Provider ID -> usage count
+	Date      time.Time      `json:"date"`
+	Cost      float64        `json:"cost"`
+	Requests  int            `json:"requests"`
+	Tokens    int64          `json:"tokens"`
+	Models    map[string]int `json:"models"`    // Model ID -> usage count
+	Providers map[string]int `json:"providers"` // Provider ID -> usage count
 }
 
 // UsageInsights provides analytics and visualization of usage patterns
@@ -27,6 +29,8 @@ type UsageInsights struct {
 	dailyData   []UsageData
 	weeklyData  []UsageData
 	monthlyData []UsageData
+	path        string        // Where usage is persisted; empty keeps it in memory
+	retention   time.Duration // Daily data older than this is pruned; zero keeps everything
 }
 
 // NewUsageInsights creates a new usage insights analyzer
@@ -71,6 +75,13 @@ func (u *UsageInsights) AddUsage(date time.Time, cost float64, requests int, tok
 	sort.Slice(u.dailyData, func(i, j int) bool {
 		return u.dailyData[i].Date.Before(u.dailyData[j].Date)
 	})
+
+	if u.path != "" {
+		u.Prune(time.Now())
+		if err := u.Save(); err != nil {
+			slog.Warn("Failed to persist usage", "error", err)
+		}
+	}
 }
 
 // GetDailyCosts returns costs for the last N days
@@ -92,6 +103,17 @@ func (u *UsageInsights) GetDailyCosts(days int) []float64 {
 	return costs
 }
 
+// GetCostOn returns the cost of the usage on the day of date
+func (u *UsageInsights) GetCostOn(date time.Time) float64 {
+	dateKey := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	for _, day := range u.dailyData {
+		if day.Date.Equal(dateKey) {
+			return day.Cost
+		}
+	}
+	return 0
+}
+
 // GetTopModels returns the most-used models
 func (u *UsageInsights) GetTopModels(limit int) []struct {
 	Model string
@@ -182,10 +204,10 @@ func (u *UsageInsights) RenderDashboard(width int) string {
 	sections = append(sections, summary)
 	sections = append(sections, "")
 
-	// Cost trend chart (last 7 days)
-	costChart := u.renderCostTrendChart(7, width-4)
+	// Cost trend chart (last 30 days)
+	costChart := u.renderCostTrendChart(30, width-4)
 	if costChart != "" {
-		chartTitle := typo.Subheading.Render("💰 Cost Trend (Last 7 Days)")
+		chartTitle := typo.Subheading.Render("💰 Cost Trend (Last 30 Days)")
 		sections = append(sections, chartTitle)
 		sections = append(sections, costChart)
 		sections = append(sections, "")
@@ -263,7 +285,12 @@ func (u *UsageInsights) renderSummaryStats() string {
 	return line1 + "\n" + line2
 }
 
-// renderCostTrendChart creates an ASCII bar chart of daily costs
+// costTrendRows is the height of the cost trend chart, in cells of four
+// dots
+const costTrendRows = 3
+
+// renderCostTrendChart creates a braille chart of daily costs, two days a
+// cell
 func (u *UsageInsights) renderCostTrendChart(days, width int) string {
 	costs := u.GetDailyCosts(days)
 
@@ -280,60 +307,32 @@ func (u *UsageInsights) renderCostTrendChart(days, width int) string {
 	}
 
 	t := theme.CurrentTheme()
-
-	// Chart height
-	height := 10
-
-	var lines []string
-
-	// Render from top to bottom
-	for row := height; row >= 0; row-- {
-		threshold := (float64(row) / float64(height)) * maxCost
-
-		line := ""
-		for _, cost := range costs {
-			if cost >= threshold {
-				// Bar character
-				bar := styles.NewStyle().
-					Foreground(t.Success()).
-					Render("█")
-				line += bar
-			} else {
-				line += " "
-			}
-			line += " "
-		}
-
-		// Add y-axis label
-		if row == height {
-			label := styles.NewStyle().
-				Foreground(t.TextMuted()).
-				Faint(true).
-				Render(fmt.Sprintf("$%.2f", maxCost))
-			line = label + " " + line
-		} else if row == 0 {
-			label := styles.NewStyle().
-				Foreground(t.TextMuted()).
-				Faint(true).
-				Render("$0.00")
-			line = label + " " + line
-		} else {
-			line = "       " + line
+	labelStyle := styles.NewStyle().
+		Foreground(t.TextMuted()).
+		Faint(true)
+	barStyle := styles.NewStyle().Foreground(t.Success())
+
+	// Y-axis labels on the top and bottom rows
+	top := fmt.Sprintf("$%.2f", maxCost)
+	labelWidth := len(top)
+	rows := sparkline.Chart(costs, costTrendRows)
+	lines := make([]string, 0, len(rows)+1)
+	for i, row := range rows {
+		label := ""
+		switch i {
+		case 0:
+			label = top
+		case len(rows) - 1:
+			label = "$0"
 		}
-
-		lines = append(lines, line)
+		lines = append(lines, labelStyle.Render(fmt.Sprintf("%*s", labelWidth, label))+" "+barStyle.Render(row))
 	}
 
-	// X-axis labels (day numbers)
-	xAxis := "       "
-	for i := 0; i < days; i++ {
-		label := styles.NewStyle().
-			Foreground(t.TextMuted()).
-			Faint(true).
-			Render(fmt.Sprintf("%d", i+1))
-		xAxis += label + " "
-	}
-	lines = append(lines, xAxis)
+	// X-axis from the first day to today
+	chartWidth := (days + 1) / 2
+	start := fmt.Sprintf("%dd ago", days-1)
+	gap := max(1, chartWidth-len(start)-len("today"))
+	lines = append(lines, strings.Repeat(" ", labelWidth+1)+labelStyle.Render(start+strings.Repeat(" ", gap)+"today"))
 
 	return strings.Join(lines, "\n")
 }
@@ -383,9 +382,8 @@ func (u *UsageInsights) renderTopModels(limit int) string {
 
 		nameWithRank := modelStyle.Render(rank + modelName)
 
-		// Bar graph
-		barWidth := int((float64(m.Count) / float64(maxCount)) * 20)
-		bar := strings.Repeat("█", barWidth)
+		// Bar graph, to half a cell
+		bar := sparkline.Bar(float64(m.Count)/float64(maxCount), 20)
 		barRendered := styles.NewStyle().
 			Foreground(t.Primary()).
 			Render(bar)
@@ -413,35 +411,22 @@ func (u *UsageInsights) renderPeakUsageTimes() string {
 
 	t := theme.CurrentTheme()
 
-	// Create 24-hour bar chart
-	hours := make([]bool, 24)
+	// Create 24-hour sparkline, two hours a cell
+	hours := make([]float64, 24)
 	for _, hour := range peakHours {
 		if hour >= 0 && hour < 24 {
-			hours[hour] = true
-		}
-	}
-
-	// Render chart
-	chart := ""
-	for hour := 0; hour < 24; hour++ {
-		if hours[hour] {
-			bar := styles.NewStyle().
-				Foreground(t.Success()).
-				Render("█")
-			chart += bar
-		} else {
-			chart += styles.NewStyle().
-				Foreground(t.TextMuted()).
-				Faint(true).
-				Render("░")
+			hours[hour] = 1
 		}
 	}
+	chart := styles.NewStyle().
+		Foreground(t.Success()).
+		Render(sparkline.Line(hours))
 
 	// Add time labels
 	labels := styles.NewStyle().
 		Foreground(t.TextMuted()).
 		Faint(true).
-		Render("0am                  12pm                  11pm")
+		Render("0h    12h  23h")
 
 	return chart + "\n" + labels
 }
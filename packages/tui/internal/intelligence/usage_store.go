@@ -0,0 +1,180 @@
+package intelligence
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// usageFileVersion is the current version of the persisted usage format
+const usageFileVersion = 1
+
+// DefaultUsageRetentionDays is how long daily usage is kept when no
+// retention is configured
+const DefaultUsageRetentionDays = 90
+
+// usageFile is the persisted form of UsageInsights
+type usageFile struct {
+	Version int         `json:"version"`
+	Daily   []UsageData `json:"daily"`
+}
+
+// legacyUsageData is the untagged in-memory UsageData as it was serialized
+// before usage was versioned: a bare JSON array with Go field names
+type legacyUsageData struct {
+	Date      time.Time
+	Cost      float64
+	Requests  int
+	Tokens    int64
+	Models    map[string]int
+	Providers map[string]int
+}
+
+// LoadUsageInsights loads usage from path and persists every later AddUsage
+// there. Daily data older than retentionDays is pruned; zero keeps it all. A
+// missing file yields empty insights.
+func LoadUsageInsights(path string, retentionDays int) (*UsageInsights, error) {
+	u := NewUsageInsights()
+	return u, u.EnablePersistence(path, retentionDays)
+}
+
+// EnablePersistence attaches the insights to a file. Usage already recorded
+// in memory is merged with the usage stored in the file, so nothing recorded
+// before persistence was enabled is lost.
+func (u *UsageInsights) EnablePersistence(path string, retentionDays int) error {
+	// An unreadable file is left untouched rather than overwritten, and usage
+	// stays in memory only
+	stored, migrated, err := readUsageFile(path)
+	if err != nil {
+		return err
+	}
+	u.path = path
+	u.retention = time.Duration(retentionDays) * 24 * time.Hour
+	inMemory := len(u.dailyData) > 0
+	u.dailyData = mergeUsage(stored, u.dailyData)
+	pruned := u.Prune(time.Now())
+
+	if migrated || inMemory || pruned > 0 {
+		return u.Save()
+	}
+	return nil
+}
+
+// Prune drops daily data older than the retention window and returns the
+// number of days removed
+func (u *UsageInsights) Prune(now time.Time) int {
+	if u.retention <= 0 {
+		return 0
+	}
+	cutoff := now.Add(-u.retention)
+	kept := u.dailyData[:0]
+	for _, day := range u.dailyData {
+		if !day.Date.Before(cutoff) {
+			kept = append(kept, day)
+		}
+	}
+	pruned := len(u.dailyData) - len(kept)
+	u.dailyData = kept
+	return pruned
+}
+
+// Save writes the insights to their file
+func (u *UsageInsights) Save() error {
+	if u.path == "" {
+		return fmt.Errorf("usage insights are not persistent")
+	}
+	if err := os.MkdirAll(filepath.Dir(u.path), 0755); err != nil {
+		return fmt.Errorf("failed to create insights directory: %w", err)
+	}
+	data, err := json.MarshalIndent(usageFile{Version: usageFileVersion, Daily: u.dailyData}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode usage: %w", err)
+	}
+
+	// Write atomically so a crash can't leave a truncated history behind
+	tmp := u.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write usage %s: %w", u.path, err)
+	}
+	if err := os.Rename(tmp, u.path); err != nil {
+		return fmt.Errorf("failed to write usage %s: %w", u.path, err)
+	}
+	return nil
+}
+
+// readUsageFile reads persisted usage, migrating the legacy format. migrated
+// reports whether the file must be rewritten in the current format.
+func readUsageFile(path string) (daily []UsageData, migrated bool, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to read usage %s: %w", path, err)
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var legacy []legacyUsageData
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return nil, false, fmt.Errorf("failed to migrate usage %s: %w", path, err)
+		}
+		daily = make([]UsageData, 0, len(legacy))
+		for _, day := range legacy {
+			daily = append(daily, UsageData(day))
+		}
+		return daily, true, nil
+	}
+
+	var file usageFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, false, fmt.Errorf("failed to decode usage %s: %w", path, err)
+	}
+	if file.Version > usageFileVersion {
+		return nil, false, fmt.Errorf("usage %s was written by a newer version (v%d)", path, file.Version)
+	}
+	return file.Daily, file.Version < usageFileVersion, nil
+}
+
+// mergeUsage combines daily data, summing entries for the same day
+func mergeUsage(sets ...[]UsageData) []UsageData {
+	byDay := make(map[string]*UsageData)
+	var days []string
+	for _, set := range sets {
+		for _, day := range set {
+			key := day.Date.Format("2006-01-02")
+			entry, ok := byDay[key]
+			if !ok {
+				entry = &UsageData{
+					Date:      day.Date,
+					Models:    make(map[string]int),
+					Providers: make(map[string]int),
+				}
+				byDay[key] = entry
+				days = append(days, key)
+			}
+			entry.Cost += day.Cost
+			entry.Requests += day.Requests
+			entry.Tokens += day.Tokens
+			for model, count := range day.Models {
+				entry.Models[model] += count
+			}
+			for provider, count := range day.Providers {
+				entry.Providers[provider] += count
+			}
+		}
+	}
+
+	merged := make([]UsageData, 0, len(days))
+	for _, key := range days {
+		merged = append(merged, *byDay[key])
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Date.Before(merged[j].Date)
+	})
+	return merged
+}
@@ -0,0 +1,74 @@
+package intelligence
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUsageInsightsPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+	u, err := LoadUsageInsights(path, 0)
+	if err != nil {
+		t.Fatalf("failed to load missing usage: %v", err)
+	}
+	today := time.Now()
+	u.AddUsage(today, 0.5, 1, 100, "claude", "anthropic")
+	u.AddUsage(today, 0.25, 1, 50, "gpt", "openai")
+
+	reloaded, err := LoadUsageInsights(path, 0)
+	if err != nil {
+		t.Fatalf("failed to reload usage: %v", err)
+	}
+	if len(reloaded.dailyData) != 1 {
+		t.Fatalf("expected 1 day, got %d", len(reloaded.dailyData))
+	}
+	day := reloaded.dailyData[0]
+	if day.Requests != 2 || day.Cost != 0.75 || day.Models["claude"] != 1 {
+		t.Errorf("unexpected reloaded day %+v", day)
+	}
+}
+
+func TestUsageInsightsMigratesAndPrunes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+	old := time.Now().AddDate(0, 0, -40).Format(time.RFC3339)
+	recent := time.Now().AddDate(0, 0, -1).Format(time.RFC3339)
+	legacy := `[
+		{"Date":"` + old + `","Cost":1,"Requests":1,"Tokens":10,"Models":{"a":1},"Providers":{"p":1}},
+		{"Date":"` + recent + `","Cost":2,"Requests":3,"Tokens":30,"Models":{"a":3},"Providers":{"p":3}}
+	]`
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := LoadUsageInsights(path, 30)
+	if err != nil {
+		t.Fatalf("failed to migrate usage: %v", err)
+	}
+	if len(u.dailyData) != 1 || u.dailyData[0].Requests != 3 {
+		t.Fatalf("expected only the recent day to survive, got %+v", u.dailyData)
+	}
+
+	// The file is rewritten in the current format
+	file, _, err := readUsageFile(path)
+	if err != nil || len(file) != 1 {
+		t.Errorf("expected migrated file with 1 day, got %d days (%v)", len(file), err)
+	}
+}
+
+func TestUsageInsightsKeepsUnreadableFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	u, err := LoadUsageInsights(path, 0)
+	if err == nil {
+		t.Fatal("expected an error for a corrupt file")
+	}
+	u.AddUsage(time.Now(), 1, 1, 1, "m", "p")
+	data, _ := os.ReadFile(path)
+	if string(data) != "{not json" {
+		t.Error("expected the corrupt file to be left untouched")
+	}
+}
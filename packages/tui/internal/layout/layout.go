@@ -10,9 +10,15 @@ func init() {
 	Current = &LayoutInfo{
 		Viewport:  Dimensions{Width: 80, Height: 25},
 		Container: Dimensions{Width: 80, Height: 25},
+		Terminal:  Dimensions{Width: 80, Height: 27},
 	}
 }
 
+// SplitMinWidth is the narrowest terminal the chat is split beside the
+// newest diff, file or tool output in: the width the responsive breakpoints
+// stop treating as a phone
+const SplitMinWidth = 121
+
 type LayoutSize string
 
 type Dimensions struct {
@@ -23,6 +29,12 @@ type Dimensions struct {
 type LayoutInfo struct {
 	Viewport  Dimensions
 	Container Dimensions
+	// Terminal is the whole terminal in cells, status bar included
+	Terminal Dimensions
+	// Pixels and Cell are the terminal's and a cell's size in pixels, as the
+	// terminal reported them; zero until it does
+	Pixels Dimensions
+	Cell   Dimensions
 }
 
 type Modal interface {
@@ -0,0 +1,120 @@
+package layout
+
+import (
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// DefaultCell is the cell size assumed until the terminal reports its own,
+// and for terminals that never do
+var DefaultCell = Dimensions{Width: 10, Height: 20}
+
+// pixelQueryTimeout is how long the terminal has to answer a pixel size
+// query before the sizes are estimated from the cell count
+const pixelQueryTimeout = time.Second
+
+// XTWINOPS reports answering the queries QueryPixels sends
+const (
+	windowPixelsReport = 4 // CSI 4 ; height ; width t, answering CSI 14 t
+	cellPixelsReport   = 6 // CSI 6 ; height ; width t, answering CSI 16 t
+)
+
+// pixelQueries counts the queries sent, so that a timeout only applies to
+// the latest
+var pixelQueries int
+
+// PixelQueryTimeoutMsg ends the wait for the terminal to answer a query
+type PixelQueryTimeoutMsg struct {
+	query int
+}
+
+// QueryPixels asks the terminal for its size and its cells' size in
+// pixels. The answers arrive as XTWINOPS reports for HandlePixelReport;
+// terminals that don't know the queries ignore them, and the timeout falls
+// back to estimates.
+func QueryPixels() tea.Cmd {
+	pixelQueries++
+	query := pixelQueries
+	return tea.Batch(
+		tea.Raw(ansi.WindowOp(ansi.RequestCellSizeWinOp)),
+		tea.Raw(ansi.WindowOp(ansi.RequestWindowSizeWinOp)),
+		tea.Tick(pixelQueryTimeout, func(time.Time) tea.Msg {
+			return PixelQueryTimeoutMsg{query: query}
+		}),
+	)
+}
+
+// CellSizeMsg is sent when a cell's size in pixels changed, for views
+// drawing images to redraw them
+type CellSizeMsg struct {
+	Cell Dimensions
+}
+
+// HandlePixelReport records an XTWINOPS report of the terminal's or a cell's
+// size in pixels. It returns a CellSizeMsg when that changed the cell size.
+func HandlePixelReport(op int, args []int) tea.Cmd {
+	if len(args) < 2 || args[0] <= 0 || args[1] <= 0 {
+		return nil
+	}
+	previous := Current.CellSize()
+	size := Dimensions{Width: args[1], Height: args[0]}
+	switch op {
+	case windowPixelsReport:
+		Current.Pixels = size
+	case cellPixelsReport:
+		Current.Cell = size
+	default:
+		return nil
+	}
+	if cell := Current.CellSize(); cell != previous {
+		return func() tea.Msg { return CellSizeMsg{Cell: cell} }
+	}
+	return nil
+}
+
+// HandlePixelTimeout falls back to estimated pixel sizes when the terminal
+// didn't answer the latest query
+func HandlePixelTimeout(msg PixelQueryTimeoutMsg) {
+	if msg.query != pixelQueries || Current.Pixels.Width > 0 || Current.Cell.Width > 0 {
+		return
+	}
+	slog.Debug("Terminal didn't report its pixel size, estimating it",
+		"cell", Current.CellSize(), "pixels", Current.PixelSize())
+}
+
+// CellSize returns a cell's size in pixels: as the terminal reported it,
+// derived from the terminal's pixel size, or DefaultCell
+func (l *LayoutInfo) CellSize() Dimensions {
+	if l.Cell.Width > 0 && l.Cell.Height > 0 {
+		return l.Cell
+	}
+	if l.Pixels.Width > 0 && l.Terminal.Width > 0 && l.Terminal.Height > 0 {
+		return Dimensions{
+			Width:  max(1, l.Pixels.Width/l.Terminal.Width),
+			Height: max(1, l.Pixels.Height/l.Terminal.Height),
+		}
+	}
+	return DefaultCell
+}
+
+// PixelSize returns the terminal's size in pixels: as the terminal reported
+// it, or its cells times their size
+func (l *LayoutInfo) PixelSize() Dimensions {
+	if l.Pixels.Width > 0 && l.Pixels.Height > 0 {
+		return l.Pixels
+	}
+	cell := l.CellSize()
+	return Dimensions{
+		Width:  l.Terminal.Width * cell.Width,
+		Height: l.Terminal.Height * cell.Height,
+	}
+}
+
+// Cells returns how many columns and rows cover pixels by pixels
+func (l *LayoutInfo) Cells(width, height int) (columns, rows int) {
+	cell := l.CellSize()
+	return (width + cell.Width - 1) / cell.Width, (height + cell.Height - 1) / cell.Height
+}
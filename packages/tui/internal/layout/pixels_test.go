@@ -0,0 +1,39 @@
+package layout
+
+import "testing"
+
+func TestPixelReports(t *testing.T) {
+	previous := Current
+	defer func() { Current = previous }()
+	Current = &LayoutInfo{Terminal: Dimensions{Width: 100, Height: 40}}
+
+	if cell := Current.CellSize(); cell != DefaultCell {
+		t.Errorf("CellSize before any report = %v, want %v", cell, DefaultCell)
+	}
+
+	// CSI 4 ; 720 ; 900 t: the cell size follows from the terminal's
+	if HandlePixelReport(windowPixelsReport, []int{720, 900}) == nil {
+		t.Error("the terminal's pixel size didn't change the cell size")
+	}
+	if cell := Current.CellSize(); cell != (Dimensions{Width: 9, Height: 18}) {
+		t.Errorf("CellSize from the terminal's size = %v, want 9x18", cell)
+	}
+
+	// CSI 6 ; 18 ; 9 t: the same cell size, reported
+	if HandlePixelReport(cellPixelsReport, []int{18, 9}) != nil {
+		t.Error("reporting the same cell size changed it")
+	}
+	if HandlePixelReport(cellPixelsReport, []int{20, 10}) == nil {
+		t.Error("a reported cell size didn't change it")
+	}
+	if size := Current.PixelSize(); size != (Dimensions{Width: 900, Height: 720}) {
+		t.Errorf("PixelSize = %v, want the reported 900x720", size)
+	}
+
+	if HandlePixelReport(8, []int{40, 100}) != nil || HandlePixelReport(cellPixelsReport, nil) != nil {
+		t.Error("an unrelated report was taken for a pixel size")
+	}
+	if columns, rows := Current.Cells(48, 48); columns != 5 || rows != 3 {
+		t.Errorf("Cells(48, 48) = %d, %d, want 5, 3", columns, rows)
+	}
+}
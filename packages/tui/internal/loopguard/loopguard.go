@@ -0,0 +1,183 @@
+// Package loopguard finds the ways an agent run gets stuck: calling a tool
+// that fails again and again, or editing a file back and forth
+// between the same versions.
+package loopguard
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DefaultLimit is the number of repetitions that interrupt a run when no
+// other limit is set
+const DefaultLimit = 3
+
+// Kind is a way of being stuck
+type Kind string
+
+const (
+	RepeatedFailure Kind = "repeated_failure" // The same call failed again and again
+	Oscillation     Kind = "oscillation"      // A file was edited back and forth
+)
+
+// Call is a finished tool call of a run, in the order the run made them
+type Call struct {
+	Tool   string
+	Input  map[string]any
+	Failed bool
+	Error  string // Error or output of a failed call
+}
+
+// Loop is where a run got stuck
+type Loop struct {
+	Kind     Kind
+	Tool     string
+	Label    string   // The command run, or the file the calls work on
+	Count    int      // Failures of the call, or edits going back and forth
+	Error    string   // Last error of a repeated failure
+	Versions []string // The two versions of an oscillating text, older first
+}
+
+// Summary describes the loop in a line
+func (l Loop) Summary() string {
+	if l.Kind == Oscillation {
+		return fmt.Sprintf("%s was edited back and forth between the same two versions %d times", l.Label, l.Count)
+	}
+	return fmt.Sprintf("%s on %s failed %d times", l.Tool, l.Label, l.Count)
+}
+
+// Prompt tells the agent it was stopped and why, asking for another
+// approach
+func (l Loop) Prompt() string {
+	var sb strings.Builder
+	sb.WriteString("You were interrupted because you got stuck: " + l.Summary() + ".\n")
+	switch l.Kind {
+	case RepeatedFailure:
+		if l.Error != "" {
+			fmt.Fprintf(&sb, "\nThe last error was:\n\n```\n%s\n```\n", strings.TrimSpace(l.Error))
+		}
+		sb.WriteString("\nDon't run it again as it is. Find out why it fails, or try a different approach.")
+	case Oscillation:
+		if len(l.Versions) < 2 {
+			sb.WriteString("\nDecide which version of the file is right, and why, before writing it again.")
+			break
+		}
+		fmt.Fprintf(&sb, "\nYou kept switching between\n\n```\n%s\n```\n\nand\n\n```\n%s\n```\n", l.Versions[0], l.Versions[1])
+		sb.WriteString("\nDecide which one is right, and why, before editing the file again.")
+	}
+	return sb.String()
+}
+
+// Detect returns the first loop of a run's calls repeated at least limit
+// times, or nil. Calls are the same when their tool and input are; edits
+// go back and forth when they swap the same two texts of a file.
+func Detect(calls []Call, limit int) *Loop {
+	if limit <= 0 {
+		return nil
+	}
+	failures := make(map[string]*Loop)
+	swaps := make(map[string]*Loop)
+	writes := make(map[string][]string) // Hashes of the contents written to each file
+	for _, call := range calls {
+		path, _ := call.Input["filePath"].(string)
+		if call.Failed {
+			key := call.Tool + "\x00" + canonical(call.Input)
+			loop := failures[key]
+			if loop == nil {
+				loop = &Loop{Kind: RepeatedFailure, Tool: call.Tool, Label: label(call)}
+				failures[key] = loop
+			}
+			loop.Count++
+			loop.Error = call.Error
+			if loop.Count >= limit {
+				return loop
+			}
+			continue
+		}
+		switch call.Tool {
+		case "edit", "multiedit":
+			for _, edit := range edits(call) {
+				if edit[0] == edit[1] {
+					continue
+				}
+				pair := edit
+				if pair[0] > pair[1] {
+					pair[0], pair[1] = pair[1], pair[0]
+				}
+				key := path + "\x00" + pair[0] + "\x00" + pair[1]
+				loop := swaps[key]
+				if loop == nil {
+					loop = &Loop{Kind: Oscillation, Tool: call.Tool, Label: path, Versions: []string{edit[0], edit[1]}}
+					swaps[key] = loop
+				}
+				loop.Count++
+				if loop.Count >= limit {
+					return loop
+				}
+			}
+		case "write":
+			content, _ := call.Input["content"].(string)
+			hash := hashOf(content)
+			history := writes[path]
+			// Writing back the content before the last one swaps the two
+			if n := len(history); n >= 2 && history[n-2] == hash && history[n-1] != hash {
+				key := path + "\x00write\x00" + min(hash, history[n-1]) + max(hash, history[n-1])
+				loop := swaps[key]
+				if loop == nil {
+					// The write being undone is the first of the swaps
+					loop = &Loop{Kind: Oscillation, Tool: call.Tool, Label: path, Count: 1}
+					swaps[key] = loop
+				}
+				loop.Count++
+				if loop.Count >= limit {
+					return loop
+				}
+			}
+			writes[path] = append(history, hash)
+		}
+	}
+	return nil
+}
+
+// edits returns the old and new texts of an edit call
+func edits(call Call) [][2]string {
+	text := func(m map[string]any, key string) string {
+		s, _ := m[key].(string)
+		return s
+	}
+	if call.Tool == "edit" {
+		return [][2]string{{text(call.Input, "oldString"), text(call.Input, "newString")}}
+	}
+	var pairs [][2]string
+	list, _ := call.Input["edits"].([]any)
+	for _, item := range list {
+		edit, _ := item.(map[string]any)
+		pairs = append(pairs, [2]string{text(edit, "oldString"), text(edit, "newString")})
+	}
+	return pairs
+}
+
+// label returns what a call works on, as the tool log shows it
+func label(call Call) string {
+	for _, key := range []string{"command", "filePath", "pattern", "url", "path"} {
+		if value, ok := call.Input[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return call.Tool
+}
+
+// canonical encodes a call's input the same way whatever the order of its
+// keys, as json sorts them
+func canonical(input map[string]any) string {
+	data, _ := json.Marshal(input)
+	return string(data)
+}
+
+func hashOf(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,58 @@
+package loopguard
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	failing := Call{Tool: "bash", Input: map[string]any{"command": "go test ./...", "description": "Run tests"}, Failed: true, Error: "FAIL"}
+	reordered := Call{Tool: "bash", Input: map[string]any{"description": "Run tests", "command": "go test ./..."}, Failed: true, Error: "FAIL again"}
+	read := Call{Tool: "read", Input: map[string]any{"filePath": "main.go"}}
+	edit := func(oldString, newString string) Call {
+		return Call{Tool: "edit", Input: map[string]any{"filePath": "main.go", "oldString": oldString, "newString": newString}}
+	}
+	write := func(content string) Call {
+		return Call{Tool: "write", Input: map[string]any{"filePath": "out.txt", "content": content}}
+	}
+
+	tests := []struct {
+		name  string
+		calls []Call
+		want  Kind
+		count int
+	}{
+		{"repeated failure", []Call{failing, read, failing, read, reordered}, RepeatedFailure, 3},
+		{"failures under the limit", []Call{failing, read, failing}, "", 0},
+		{"different failures", []Call{failing, {Tool: "bash", Input: map[string]any{"command": "go vet"}, Failed: true}, failing}, "", 0},
+		{"edits back and forth", []Call{edit("a := 1", "a := 2"), read, edit("a := 2", "a := 1"), edit("a := 1", "a := 2")}, Oscillation, 3},
+		{"edits moving on", []Call{edit("a := 1", "a := 2"), edit("a := 2", "a := 3"), edit("a := 3", "a := 4")}, "", 0},
+		{"writes back and forth", []Call{write("x"), write("y"), write("x"), write("y")}, Oscillation, 3},
+		{"writes moving on", []Call{write("x"), write("y"), write("z"), write("x")}, "", 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			loop := Detect(test.calls, 3)
+			if test.want == "" {
+				if loop != nil {
+					t.Fatalf("Detect = %+v, want none", loop)
+				}
+				return
+			}
+			if loop == nil || loop.Kind != test.want || loop.Count != test.count {
+				t.Fatalf("Detect = %+v, want %s %d times", loop, test.want, test.count)
+			}
+		})
+	}
+}
+
+func TestDetectLastError(t *testing.T) {
+	calls := []Call{
+		{Tool: "bash", Input: map[string]any{"command": "make"}, Failed: true, Error: "first"},
+		{Tool: "bash", Input: map[string]any{"command": "make"}, Failed: true, Error: "second"},
+	}
+	loop := Detect(calls, 2)
+	if loop == nil || loop.Error != "second" || loop.Label != "make" {
+		t.Fatalf("Detect = %+v", loop)
+	}
+	if Detect(calls, 0) != nil {
+		t.Error("a limit of 0 detects loops")
+	}
+}
@@ -0,0 +1,145 @@
+// Package pathrules picks the agent and model of a prompt from the files it
+// is about. A project maps path globs to the agent and model that suit
+// them, such as a cheap model for docs/** and the strongest one for
+// internal/crypto/**, and a rule applies when most of the files in the
+// session's recent context match its globs.
+package pathrules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+)
+
+// ConfigPath is where a project keeps its rules, relative to the project
+// root
+const ConfigPath = ".rycode/rules.json"
+
+// DefaultThreshold is the share of the context's files a rule must match
+// when the config doesn't set one
+const DefaultThreshold = 0.6
+
+// Config is a project's rules
+type Config struct {
+	Rules     []Rule  `json:"rules"`
+	Threshold float64 `json:"threshold,omitempty"` // Share of the context's files a rule must match, from 0 to 1
+}
+
+// Rule maps path globs to a preferred agent and model. Globs are relative
+// to the project root and use / as separator; * matches within a path
+// segment and ** matches any number of segments.
+type Rule struct {
+	Paths []string `json:"paths"`
+	Agent string   `json:"agent,omitempty"`
+	Model string   `json:"model,omitempty"` // As provider/model
+}
+
+// Label names the rule by its globs
+func (r Rule) Label() string {
+	return strings.Join(r.Paths, ", ")
+}
+
+// Matches reports whether a path, relative to the project root, matches
+// any of the rule's globs
+func (r Rule) Matches(file string) bool {
+	for _, glob := range r.Paths {
+		if Match(glob, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// Load reads the rules of the project at root. It returns nil when the
+// project has none.
+func Load(root string) (*Config, error) {
+	data, err := remote.ReadFile(filepath.Join(root, ConfigPath))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("%s: %w", ConfigPath, err)
+	}
+	for i, rule := range config.Rules {
+		if len(rule.Paths) == 0 {
+			return nil, fmt.Errorf("%s: rule %d has no paths", ConfigPath, i+1)
+		}
+		if rule.Agent == "" && rule.Model == "" {
+			return nil, fmt.Errorf("%s: rule %s sets neither an agent nor a model", ConfigPath, rule.Label())
+		}
+		if rule.Model != "" && !strings.Contains(rule.Model, "/") {
+			return nil, fmt.Errorf("%s: invalid model %q; use provider/model", ConfigPath, rule.Model)
+		}
+	}
+	if config.Threshold <= 0 || config.Threshold > 1 {
+		config.Threshold = DefaultThreshold
+	}
+	return &config, nil
+}
+
+// Select returns the rule matching the largest share of files, when that
+// share reaches the threshold, along with the share. Earlier rules win ties.
+func (c *Config) Select(files []string) (*Rule, float64) {
+	if c == nil || len(files) == 0 {
+		return nil, 0
+	}
+	var best *Rule
+	var bestShare float64
+	for i := range c.Rules {
+		matched := 0
+		for _, file := range files {
+			if c.Rules[i].Matches(file) {
+				matched++
+			}
+		}
+		share := float64(matched) / float64(len(files))
+		if share > bestShare {
+			best, bestShare = &c.Rules[i], share
+		}
+	}
+	if bestShare < c.Threshold {
+		return nil, bestShare
+	}
+	return best, bestShare
+}
+
+// Match reports whether a slash-separated path matches a glob, where **
+// matches any number of path segments, including none
+func Match(glob, file string) bool {
+	return matchSegments(strings.Split(strings.Trim(glob, "/"), "/"), strings.Split(path.Clean(file), "/"))
+}
+
+func matchSegments(globs, segments []string) bool {
+	for len(globs) > 0 {
+		if globs[0] == "**" {
+			globs = globs[1:]
+			if len(globs) == 0 {
+				return true
+			}
+			for i := range segments {
+				if matchSegments(globs, segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(globs[0], segments[0]); !ok {
+			return false
+		}
+		globs, segments = globs[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
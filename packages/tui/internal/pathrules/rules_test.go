@@ -0,0 +1,72 @@
+package pathrules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		glob, file string
+		want       bool
+	}{
+		{"docs/**", "docs/guide/intro.md", true},
+		{"docs/**", "docs", true},
+		{"docs/**", "src/docs/a.md", false},
+		{"**/*.md", "README.md", true},
+		{"**/*.md", "docs/a/b.md", true},
+		{"internal/crypto/**", "internal/crypto/aes/gcm.go", true},
+		{"internal/*/util.go", "internal/app/util.go", true},
+		{"internal/*/util.go", "internal/app/sub/util.go", false},
+		{"**/testdata/**", "pkg/x/testdata/in.txt", true},
+		{"cmd/main.go", "cmd/main.go", true},
+	}
+	for _, test := range tests {
+		if got := Match(test.glob, test.file); got != test.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", test.glob, test.file, got, test.want)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	config := &Config{
+		Threshold: 0.6,
+		Rules: []Rule{
+			{Paths: []string{"docs/**", "**/*.md"}, Model: "anthropic/claude-haiku"},
+			{Paths: []string{"internal/crypto/**"}, Model: "anthropic/claude-opus"},
+		},
+	}
+
+	rule, share := config.Select([]string{"docs/a.md", "README.md", "main.go"})
+	if rule != &config.Rules[0] || share < 0.66 || share > 0.67 {
+		t.Errorf("Select = %v, %v, want the docs rule", rule, share)
+	}
+	if rule, _ := config.Select([]string{"internal/crypto/a.go", "docs/a.md"}); rule != nil {
+		t.Errorf("Select = %v, want no rule below the threshold", rule)
+	}
+	if rule, _ := config.Select(nil); rule != nil {
+		t.Errorf("Select = %v, want no rule without files", rule)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+	if config, err := Load(root); config != nil || err != nil {
+		t.Fatalf("Load = %v, %v, want nothing without a config", config, err)
+	}
+	os.MkdirAll(filepath.Join(root, ".rycode"), 0755)
+	os.WriteFile(filepath.Join(root, ConfigPath), []byte(`{"rules": [{"paths": ["docs/**"], "agent": "docs"}]}`), 0644)
+	config, err := Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Threshold != DefaultThreshold || config.Rules[0].Agent != "docs" {
+		t.Errorf("config = %+v", config)
+	}
+
+	os.WriteFile(filepath.Join(root, ConfigPath), []byte(`{"rules": [{"paths": ["docs/**"]}]}`), 0644)
+	if _, err := Load(root); err == nil {
+		t.Error("a rule without an agent or model loaded")
+	}
+}
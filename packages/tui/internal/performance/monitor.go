@@ -392,8 +392,12 @@ func (pm *PerformanceMonitor) GetSummary() string {
 	)
 }
 
-// Global performance monitor instance
-var globalMonitor = NewPerformanceMonitor()
+// Global performance monitor instance, off until profiling is turned on
+var globalMonitor = func() *PerformanceMonitor {
+	pm := NewPerformanceMonitor()
+	pm.enabled = false
+	return pm
+}()
 
 // Global accessor functions
 func StartFrame() {
@@ -419,3 +423,8 @@ func RecordMemorySnapshot() {
 func GetMonitor() *PerformanceMonitor {
 	return globalMonitor
 }
+
+// Enabled reports whether the global monitor is recording
+func Enabled() bool {
+	return globalMonitor.IsEnabled()
+}
@@ -0,0 +1,213 @@
+package performance
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"runtime"
+	rpprof "runtime/pprof"
+	"sync"
+	"time"
+)
+
+// DefaultPprofAddr is where the pprof endpoint listens unless told otherwise.
+// It only listens on the loopback interface.
+const DefaultPprofAddr = "localhost:6060"
+
+// PprofEnv names the environment variable that starts the pprof endpoint at
+// launch, set to an address or to "1" for DefaultPprofAddr
+const PprofEnv = "RYCODE_PPROF"
+
+// allocSampleRate is the allocation sampling rate while allocations are
+// sampled closely: every 4 KB allocated instead of Go's default 512 KB
+const allocSampleRate = 4 * 1024
+
+// profiler holds the profiling switched on from the TUI
+type profiler struct {
+	mu          sync.Mutex
+	server      *http.Server
+	addr        string
+	cpuFile     *os.File
+	defaultRate int // runtime.MemProfileRate before allocations were sampled closely, 0 when they aren't
+}
+
+var globalProfiler = &profiler{}
+
+// StartPprof serves the pprof endpoints on addr, under /debug/pprof/, and
+// returns the address listened on. Nothing is served on other interfaces
+// unless addr names one.
+func StartPprof(addr string) (string, error) {
+	p := globalProfiler
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.server != nil {
+		return p.addr, nil
+	}
+	if addr == "" {
+		addr = DefaultPprofAddr
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	// Importing net/http/pprof registers its handlers on
+	// http.DefaultServeMux, which nothing serves. This server uses a mux of
+	// its own, so it serves these endpoints and nothing else registered
+	// there.
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/pprof/frames", serveFrames)
+
+	p.server = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	p.addr = listener.Addr().String()
+	go p.server.Serve(listener)
+	return p.addr, nil
+}
+
+// StopPprof stops serving the pprof endpoints
+func StopPprof() error {
+	p := globalProfiler
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.server == nil {
+		return nil
+	}
+	err := p.server.Close()
+	p.server, p.addr = nil, ""
+	return err
+}
+
+// PprofAddr returns the address the pprof endpoints are served on, or ""
+// when they aren't
+func PprofAddr() string {
+	p := globalProfiler
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.addr
+}
+
+// serveFrames serves the render profiler's summary, so that frame timings
+// can be read next to the pprof profiles
+func serveFrames(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, globalMonitor.GetSummary())
+	for name, m := range globalMonitor.GetComponentMetrics() {
+		fmt.Fprintf(w, "%s\t%.2fms avg\t%.2fms last\t%d\n", name,
+			float64(m.AverageRenderTime.Microseconds())/1000.0,
+			float64(m.RenderTime.Microseconds())/1000.0,
+			m.RenderCount)
+	}
+	for _, warning := range globalMonitor.GetWarnings() {
+		fmt.Fprintln(w, "warning:", warning)
+	}
+}
+
+// StartCPUProfile starts sampling the CPU into a new file of dir and
+// returns its path
+func StartCPUProfile(dir string) (string, error) {
+	p := globalProfiler
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cpuFile != nil {
+		return "", errors.New("the CPU is already being profiled")
+	}
+	f, err := createProfile(dir, "cpu")
+	if err != nil {
+		return "", err
+	}
+	if err := rpprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to start CPU profile: %w", err)
+	}
+	p.cpuFile = f
+	return f.Name(), nil
+}
+
+// StopCPUProfile stops sampling the CPU and returns the profile's path
+func StopCPUProfile() (string, error) {
+	p := globalProfiler
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cpuFile == nil {
+		return "", errors.New("the CPU isn't being profiled")
+	}
+	rpprof.StopCPUProfile()
+	path := p.cpuFile.Name()
+	err := p.cpuFile.Close()
+	p.cpuFile = nil
+	if err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return path, nil
+}
+
+// CPUProfiling reports whether the CPU is being profiled
+func CPUProfiling() bool {
+	p := globalProfiler
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.cpuFile != nil
+}
+
+// WriteHeapProfile writes the allocations sampled so far to a new file of
+// dir and returns its path
+func WriteHeapProfile(dir string) (string, error) {
+	f, err := createProfile(dir, "heap")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	runtime.GC() // Up to date statistics
+	if err := rpprof.WriteHeapProfile(f); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", filepath.Base(f.Name()), err)
+	}
+	return f.Name(), nil
+}
+
+// SetAllocSampling samples allocations closely, for heap profiles that
+// catch the small allocations of rendering, or goes back to Go's default
+// rate. Only allocations made afterwards are affected.
+func SetAllocSampling(on bool) {
+	p := globalProfiler
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch {
+	case on && p.defaultRate == 0:
+		p.defaultRate = runtime.MemProfileRate
+		runtime.MemProfileRate = allocSampleRate
+	case !on && p.defaultRate != 0:
+		runtime.MemProfileRate = p.defaultRate
+		p.defaultRate = 0
+	}
+}
+
+// AllocSampling reports whether allocations are sampled closely
+func AllocSampling() bool {
+	p := globalProfiler
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.defaultRate != 0
+}
+
+// createProfile creates a timestamped profile file in dir
+func createProfile(dir, kind string) (*os.File, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	path := filepath.Join(dir, kind+"-"+time.Now().Format("20060102-150405")+".pprof")
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", filepath.Base(path), err)
+	}
+	return f, nil
+}
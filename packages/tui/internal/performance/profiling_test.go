@@ -0,0 +1,78 @@
+package performance
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPprofEndpoint(t *testing.T) {
+	addr, err := StartPprof("localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer StopPprof()
+	if PprofAddr() != addr {
+		t.Errorf("PprofAddr() = %q, want %q", PprofAddr(), addr)
+	}
+
+	for _, path := range []string{"/debug/pprof/", "/debug/pprof/frames"} {
+		resp, err := http.Get("http://" + addr + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s: %s", path, resp.Status)
+		}
+		if path == "/debug/pprof/frames" && !strings.Contains(string(body), "FPS") {
+			t.Errorf("frames = %q, want the render profiler's summary", body)
+		}
+	}
+
+	if err := StopPprof(); err != nil {
+		t.Fatal(err)
+	}
+	if PprofAddr() != "" {
+		t.Error("pprof still served after stopping")
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	dir := t.TempDir()
+	cpu, err := StartCPUProfile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := StartCPUProfile(dir); err == nil {
+		t.Error("started a second CPU profile")
+	}
+	if path, err := StopCPUProfile(); err != nil || path != cpu {
+		t.Fatalf("StopCPUProfile() = %q, %v, want %q", path, err, cpu)
+	}
+	if CPUProfiling() {
+		t.Error("CPU still profiled after stopping")
+	}
+
+	SetAllocSampling(true)
+	if !AllocSampling() {
+		t.Error("allocations not sampled after turning sampling on")
+	}
+	SetAllocSampling(false)
+	if AllocSampling() {
+		t.Error("allocations sampled after turning sampling off")
+	}
+
+	heap, err := WriteHeapProfile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{cpu, heap} {
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("profile %s is missing or empty", path)
+		}
+	}
+}
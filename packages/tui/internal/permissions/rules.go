@@ -0,0 +1,212 @@
+// Package permissions remembers answers to the agent's permission requests
+// as rules, such as always allowing go test in a project or never allowing
+// a POST with curl, so that a request a rule covers is answered without
+// asking again. Rules match a request's tool and the command, path or URL
+// it asks for.
+package permissions
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode-sdk-go/shared"
+)
+
+// Rule answers the permission requests it matches
+type Rule struct {
+	Tool    string `toml:"tool"`              // Permission type, as bash, edit or webfetch; * for any
+	Pattern string `toml:"pattern,omitempty"` // Glob of the command, path or URL asked for, * matching any text; empty for any
+	Allow   bool   `toml:"allow"`
+	Project string `toml:"project,omitempty"` // Worktree the rule holds in; empty for every project
+}
+
+// String describes the rule, as "allow bash go test *"
+func (r Rule) String() string {
+	verb := "deny"
+	if r.Allow {
+		verb = "allow"
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s", verb, r.Tool, r.Pattern))
+}
+
+// Matches reports whether the rule covers a request made in a project. A
+// compound shell command, as go test x; rm -rf ~, is allowed by a rule
+// matching each of its commands, and denied by one matching any of them.
+// A command that redirects its output to a file, as go test x > ~/.bashrc,
+// is never allowed by a rule, since the pattern says nothing of the file.
+func (r Rule) Matches(permission opencode.Permission, project string) bool {
+	if r.Project != "" && r.Project != project {
+		return false
+	}
+	if r.Tool != "*" && r.Tool != permission.Type {
+		return false
+	}
+	if r.Pattern == "" {
+		return true
+	}
+	subject := Subject(permission)
+	if permission.Type != "bash" {
+		return Match(r.Pattern, subject)
+	}
+	commands := Commands(subject)
+	if len(commands) == 0 {
+		return false
+	}
+	for _, command := range commands {
+		if r.Allow && RedirectsOutput(command) {
+			return false
+		}
+		if Match(r.Pattern, command) != r.Allow {
+			return !r.Allow
+		}
+	}
+	return r.Allow
+}
+
+// commandSeparators end a command of a compound shell command, and start
+// the commands substituted into one
+var commandSeparators = []string{"&&", "||", "$(", "<(", ">(", ";", "|", "&", "`", "(", ")", "\n"}
+
+// Commands splits a shell command line into the commands it runs: those
+// joined by ;, &&, || and |, run in the background, on lines of their own,
+// or substituted with $( ) or backticks. Quotes aren't parsed, so a
+// separator inside them splits as well, which only makes a rule less
+// likely to allow the command.
+func Commands(line string) []string {
+	var commands []string
+	start := 0
+	for i := 0; i < len(line); {
+		separator := ""
+		for _, s := range commandSeparators {
+			if strings.HasPrefix(line[i:], s) {
+				separator = s
+				break
+			}
+		}
+		// The & of redirections, as 2>&1 and &>, runs nothing
+		if separator == "&" && (i > 0 && (line[i-1] == '>' || line[i-1] == '<') || strings.HasPrefix(line[i+1:], ">")) {
+			separator = ""
+		}
+		if separator == "" {
+			i++
+			continue
+		}
+		if command := strings.TrimSpace(line[start:i]); command != "" {
+			commands = append(commands, command)
+		}
+		i += len(separator)
+		start = i
+	}
+	if command := strings.TrimSpace(line[start:]); command != "" {
+		commands = append(commands, command)
+	}
+	return commands
+}
+
+// RedirectsOutput reports whether a command writes its output to a file,
+// with >, >>, >|, &> or a numbered redirection as 2>. Duplicating a
+// descriptor, as 2>&1, and writing to /dev/null don't count. Quotes aren't
+// parsed, so a > inside them counts as well.
+func RedirectsOutput(command string) bool {
+	for i := 0; i < len(command); i++ {
+		if command[i] != '>' {
+			continue
+		}
+		target := strings.TrimLeft(command[i+1:], ">|")
+		if rest, ok := strings.CutPrefix(target, "&"); ok {
+			// >&2 and >&- duplicate or close a descriptor, >&file writes it
+			if strings.TrimLeft(rest, "0123456789-") != rest {
+				continue
+			}
+			target = rest
+		}
+		if word := strings.Fields(target); len(word) == 0 || word[0] != "/dev/null" {
+			return true
+		}
+	}
+	return false
+}
+
+// Decide returns the rule answering a request made in a project, nil when
+// none does and the user is asked. A rule denying the request wins over
+// one allowing it.
+func Decide(rules []Rule, permission opencode.Permission, project string) *Rule {
+	var allowed *Rule
+	for i, rule := range rules {
+		if !rule.Matches(permission, project) {
+			continue
+		}
+		if !rule.Allow {
+			return &rules[i]
+		}
+		if allowed == nil {
+			allowed = &rules[i]
+		}
+	}
+	return allowed
+}
+
+// Subject returns what a request asks for: the command to run, the file to
+// change or the URL to fetch, or its title for other tools
+func Subject(permission opencode.Permission) string {
+	for _, key := range []string{"command", "filePath", "url"} {
+		if value, ok := permission.Metadata[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return permission.Title
+}
+
+// Remember returns the rule remembering an answer to a request, for requests
+// like it in a project. The pattern is the one the server gives the request,
+// as "go test *" for go test ./..., or else what it asks for exactly.
+func Remember(permission opencode.Permission, allow bool, project string) Rule {
+	pattern := ""
+	switch p := permission.Pattern.(type) {
+	case shared.UnionString:
+		pattern = string(p)
+	case opencode.PermissionPatternArray:
+		if len(p) == 1 {
+			pattern = p[0]
+		}
+	}
+	if pattern == "" {
+		pattern = Subject(permission)
+	}
+	return Rule{Tool: permission.Type, Pattern: pattern, Allow: allow, Project: project}
+}
+
+// Parse reads a rule typed as "allow|deny <tool> [pattern]"
+func Parse(text string) (Rule, error) {
+	fields := strings.Fields(text)
+	if len(fields) < 2 || (fields[0] != "allow" && fields[0] != "deny") {
+		return Rule{}, fmt.Errorf("expected allow|deny <tool> [pattern], got %q", text)
+	}
+	return Rule{
+		Tool:    fields[1],
+		Pattern: strings.Join(fields[2:], " "),
+		Allow:   fields[0] == "allow",
+	}, nil
+}
+
+// Match reports whether text matches a glob, in which * matches any text,
+// separators included, and ? any one character
+func Match(glob, text string) bool {
+	var b strings.Builder
+	b.WriteString(`(?s)^`)
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteString(`.*`)
+		case '?':
+			b.WriteString(`.`)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString(`$`)
+	matched, _ := regexp.MatchString(b.String(), text)
+	return matched
+}
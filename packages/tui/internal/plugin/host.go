@@ -0,0 +1,184 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// initTimeout bounds the start of a plugin
+const initTimeout = 5 * time.Second
+
+// Loaded is an installed plugin and, while it runs, what it contributes
+type Loaded struct {
+	Manifest      Manifest
+	Enabled       bool
+	Plugin        Plugin // nil unless the plugin is running
+	Contributions Contributions
+	Err           error // Why the plugin isn't running although enabled
+}
+
+// Running reports whether the plugin started
+func (l Loaded) Running() bool {
+	return l.Plugin != nil
+}
+
+// Host runs the plugins of a TUI
+type Host struct {
+	env      Env
+	embedded map[string]Embedded // By name, set before Load
+
+	mu      sync.Mutex
+	plugins []Loaded // Sorted by name
+}
+
+// NewHost creates a host that describes the TUI to its plugins with env
+func NewHost(env Env) *Host {
+	return &Host{env: env}
+}
+
+// Embed adds plugins that run in the TUI's process. Load starts them with
+// the installed plugins, which they replace when they share a name.
+func (h *Host) Embed(plugins ...Embedded) {
+	if h.embedded == nil {
+		h.embedded = make(map[string]Embedded)
+	}
+	for _, e := range plugins {
+		h.embedded[e.Name] = e
+	}
+}
+
+// Load discovers the installed plugins and starts those that aren't
+// disabled, along with the embedded ones. It blocks until they have started
+// or failed to.
+func (h *Host) Load(configDir, root string, disabled []string) {
+	manifests, invalid := Discover(configDir, root)
+	for name, e := range h.embedded {
+		manifests = slices.DeleteFunc(manifests, func(m Manifest) bool { return m.Name == name })
+		delete(invalid, name)
+		manifests = append(manifests, Manifest{Name: name, Description: e.Description})
+	}
+	plugins := make([]Loaded, len(manifests), len(manifests)+len(invalid))
+	var wg sync.WaitGroup
+	for i, manifest := range manifests {
+		plugins[i] = Loaded{Manifest: manifest}
+		if slices.Contains(disabled, manifest.Name) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			plugins[i] = h.start(manifest)
+		}()
+	}
+	wg.Wait()
+	for name, err := range invalid {
+		plugins = append(plugins, Loaded{Manifest: Manifest{Name: name}, Enabled: !slices.Contains(disabled, name), Err: err})
+	}
+	slices.SortFunc(plugins, func(a, b Loaded) int {
+		return strings.Compare(a.Manifest.Name, b.Manifest.Name)
+	})
+
+	h.mu.Lock()
+	old := h.plugins
+	h.plugins = plugins
+	h.mu.Unlock()
+	for _, l := range old {
+		if l.Plugin != nil {
+			l.Plugin.Close()
+		}
+	}
+}
+
+func (h *Host) start(manifest Manifest) Loaded {
+	loaded := Loaded{Manifest: manifest, Enabled: true}
+	var p Plugin
+	if e, ok := h.embedded[manifest.Name]; ok {
+		p = e.Plugin
+	} else {
+		process, err := Start(manifest)
+		if err != nil {
+			loaded.Err = err
+			return loaded
+		}
+		p = process
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), initTimeout)
+	defer cancel()
+	contributions, err := p.Init(ctx, h.env)
+	if err != nil {
+		p.Close()
+		loaded.Err = err
+		return loaded
+	}
+	loaded.Plugin, loaded.Contributions = p, contributions
+	return loaded
+}
+
+// Plugins returns the installed plugins, sorted by name
+func (h *Host) Plugins() []Loaded {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Clone(h.plugins)
+}
+
+// Get returns an installed plugin
+func (h *Host) Get(name string) (Loaded, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, l := range h.plugins {
+		if l.Manifest.Name == name {
+			return l, true
+		}
+	}
+	return Loaded{}, false
+}
+
+// SetEnabled starts or stops a plugin, returning it as it is afterwards
+func (h *Host) SetEnabled(name string, enabled bool) (Loaded, error) {
+	current, ok := h.Get(name)
+	if !ok {
+		return Loaded{}, fmt.Errorf("no plugin named %q", name)
+	}
+	if current.Plugin != nil {
+		current.Plugin.Close()
+	}
+	updated := Loaded{Manifest: current.Manifest, Enabled: enabled}
+	switch {
+	case enabled && current.Manifest.Command == nil && h.embedded[name].Plugin == nil:
+		updated.Err = current.Err // An invalid manifest doesn't start
+	case enabled:
+		updated = h.start(current.Manifest)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i := range h.plugins {
+		if h.plugins[i].Manifest.Name == name {
+			h.plugins[i] = updated
+		}
+	}
+	return updated, updated.Err
+}
+
+// Close stops every plugin
+func (h *Host) Close() {
+	h.mu.Lock()
+	plugins := h.plugins
+	h.plugins = nil
+	h.mu.Unlock()
+	var wg sync.WaitGroup
+	for _, l := range plugins {
+		if l.Plugin != nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				l.Plugin.Close()
+			}()
+		}
+	}
+	wg.Wait()
+}
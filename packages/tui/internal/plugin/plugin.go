@@ -0,0 +1,264 @@
+// Package plugin extends the TUI with slash commands, dialogs and status
+// bar widgets that live outside of it. A plugin implements Plugin: it is
+// initialized once and declares what it contributes, Update is called for
+// its commands, the choices made in its dialogs and events of the app, and
+// View draws its widgets. A plugin that also implements PartRenderer draws
+// the tool calls of the tools it declares renderers for, in place of the
+// chat's default drawing.
+//
+// Third parties write plugins as programs in any language, described by a
+// plugin.json manifest in a directory under plugins/ of the config
+// directory or of the project's .rycode directory. The TUI starts the
+// program and speaks JSON-RPC 2.0 with it over its stdin and stdout, one
+// message per line; see Process.
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ManifestFile is the file describing a plugin in its directory
+const ManifestFile = "plugin.json"
+
+// Plugin is an extension of the TUI. Its methods are called off the UI
+// goroutine, possibly concurrently.
+type Plugin interface {
+	// Init starts the plugin and returns what it adds to the TUI
+	Init(ctx context.Context, env Env) (Contributions, error)
+	// Update tells the plugin of an event and returns what the TUI should
+	// do about it, or nil
+	Update(ctx context.Context, event Event) (*Action, error)
+	// View draws one of the plugin's widgets, or returns "" to hide it
+	View(ctx context.Context, widget string) (string, error)
+	// Close stops the plugin
+	Close() error
+}
+
+// Env describes the TUI to a plugin that is starting
+type Env struct {
+	Version string `json:"version"`
+	Root    string `json:"root"`
+	Cwd     string `json:"cwd"`
+	Bridge  string `json:"bridge,omitempty"` // Socket of the editor bridge, for plugins that send prompts of their own
+}
+
+// Contributions are the commands and widgets a plugin adds
+type Contributions struct {
+	Commands  []Command  `json:"commands,omitempty"`
+	Widgets   []Widget   `json:"widgets,omitempty"`
+	Renderers []Renderer `json:"renderers,omitempty"`
+}
+
+// Renderer declares the tool calls a plugin draws
+type Renderer struct {
+	Tool        string `json:"tool"` // Name of the tool, or a pattern as terraform_*
+	Description string `json:"description,omitempty"`
+}
+
+// Matches reports whether the renderer draws the calls of a tool
+func (r Renderer) Matches(tool string) bool {
+	ok, _ := path.Match(r.Tool, tool)
+	return ok
+}
+
+// PartRenderer is implemented by plugins that declare renderers. Drawings
+// are asked for once a call has finished and kept until the chat's width
+// changes.
+type PartRenderer interface {
+	// RenderPart draws a tool call, or returns "" to leave it to the chat
+	RenderPart(ctx context.Context, part Part) (string, error)
+}
+
+// Part is a finished tool call a plugin draws
+type Part struct {
+	Tool     string         `json:"tool"`
+	Status   string         `json:"status"` // completed or error
+	Input    map[string]any `json:"input,omitempty"`
+	Output   string         `json:"output,omitempty"`
+	Error    string         `json:"error,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+	Width    int            `json:"width"` // Columns the drawing may take, longer lines are cut
+}
+
+// Command is a slash command added by a plugin
+type Command struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Trigger     string `json:"trigger,omitempty"` // Typed after the slash, the name when empty
+}
+
+// Widget is a status bar widget added by a plugin
+type Widget struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Interval    int    `json:"interval,omitempty"` // Seconds between redraws, 30 when unset
+}
+
+// Event types
+const (
+	EventCommand = "command" // One of the plugin's commands was run
+	EventSelect  = "select"  // An item of one of the plugin's dialogs was chosen
+	EventSession = "session" // Another session was opened
+)
+
+// Event is something the plugin is told of in Update
+type Event struct {
+	Type      string `json:"type"`
+	Command   string `json:"command,omitempty"`
+	Args      string `json:"args,omitempty"`
+	Dialog    string `json:"dialog,omitempty"`
+	Item      string `json:"item,omitempty"`
+	SessionID string `json:"sessionID,omitempty"`
+}
+
+// Action is what a plugin asks of the TUI in answer to an event. Every
+// field is optional.
+type Action struct {
+	Toast  *Toast  `json:"toast,omitempty"`
+	Dialog *Dialog `json:"dialog,omitempty"`
+	Prompt string  `json:"prompt,omitempty"` // Sent to the session
+	Insert string  `json:"insert,omitempty"` // Put in the prompt for the user to finish
+}
+
+// Toast is a notification shown by a plugin
+type Toast struct {
+	Kind    string `json:"kind,omitempty"` // info, success, warning or error
+	Title   string `json:"title,omitempty"`
+	Message string `json:"message"`
+}
+
+// Dialog is a list a plugin asks the user to choose from. Choosing an item
+// sends the plugin a select event with the dialog's and the item's IDs.
+type Dialog struct {
+	ID    string       `json:"id"`
+	Title string       `json:"title"`
+	Body  string       `json:"body,omitempty"`
+	Items []DialogItem `json:"items,omitempty"`
+}
+
+// DialogItem is an item of a plugin's dialog
+type DialogItem struct {
+	ID          string `json:"id"`
+	Label       string `json:"label"`
+	Description string `json:"description,omitempty"`
+}
+
+// Embedded is a plugin that runs in the TUI's process, added by a Go
+// program that embeds the TUI rather than installed with a manifest
+type Embedded struct {
+	Name        string
+	Description string
+	Plugin      Plugin
+}
+
+// Manifest describes a plugin installed in a directory
+type Manifest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Command     []string `json:"command"` // Program and arguments, relative to the plugin's directory
+	Dir         string   `json:"-"`
+}
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Discover reads the manifests of the plugins installed for the user in
+// configDir/plugins and for the project in root/.rycode/plugins. A project
+// plugin replaces a user plugin of the same name. Plugins with an invalid
+// manifest are returned with an error each, so they can be listed.
+func Discover(configDir, root string) ([]Manifest, map[string]error) {
+	byName := make(map[string]Manifest)
+	invalid := make(map[string]error)
+	for _, dir := range []string{filepath.Join(configDir, "plugins"), filepath.Join(root, ".rycode", "plugins")} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			manifest, err := ReadManifest(filepath.Join(dir, entry.Name()))
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				invalid[entry.Name()] = err
+				continue
+			}
+			delete(invalid, manifest.Name)
+			byName[manifest.Name] = manifest
+		}
+	}
+
+	manifests := make([]Manifest, 0, len(byName))
+	for _, manifest := range byName {
+		manifests = append(manifests, manifest)
+	}
+	slices.SortFunc(manifests, func(a, b Manifest) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return manifests, invalid
+}
+
+// ReadManifest reads the manifest of the plugin in dir
+func ReadManifest(dir string) (Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return Manifest{}, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("%s: %w", ManifestFile, err)
+	}
+	if !validName.MatchString(manifest.Name) {
+		return Manifest{}, fmt.Errorf("%s: invalid name %q; use lowercase letters, digits, - and _", ManifestFile, manifest.Name)
+	}
+	if len(manifest.Command) == 0 {
+		return Manifest{}, fmt.Errorf("%s: no command", ManifestFile)
+	}
+	manifest.Dir = dir
+	return manifest, nil
+}
+
+// Validate checks what a plugin contributes, so that its commands and
+// widgets can be named after it without clashing
+func (c Contributions) Validate() error {
+	seen := make(map[string]bool)
+	for _, command := range c.Commands {
+		if !validName.MatchString(command.Name) {
+			return fmt.Errorf("invalid command name %q", command.Name)
+		}
+		if command.Trigger != "" && strings.ContainsAny(command.Trigger, " /") {
+			return fmt.Errorf("invalid trigger %q of command %s", command.Trigger, command.Name)
+		}
+		if seen["command "+command.Name] {
+			return fmt.Errorf("command %s declared twice", command.Name)
+		}
+		seen["command "+command.Name] = true
+	}
+	for _, widget := range c.Widgets {
+		if !validName.MatchString(widget.Name) {
+			return fmt.Errorf("invalid widget name %q", widget.Name)
+		}
+		if seen["widget "+widget.Name] {
+			return fmt.Errorf("widget %s declared twice", widget.Name)
+		}
+		seen["widget "+widget.Name] = true
+	}
+	for _, renderer := range c.Renderers {
+		if _, err := path.Match(renderer.Tool, ""); renderer.Tool == "" || err != nil {
+			return fmt.Errorf("invalid renderer tool %q", renderer.Tool)
+		}
+	}
+	return nil
+}
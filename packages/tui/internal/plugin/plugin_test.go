@@ -0,0 +1,168 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeManifest(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	configDir, root := t.TempDir(), t.TempDir()
+	writeManifest(t, filepath.Join(configDir, "plugins", "jira"), `{"name": "jira", "command": ["jira-plugin"]}`)
+	writeManifest(t, filepath.Join(configDir, "plugins", "clock"), `{"name": "clock", "command": ["./clock"]}`)
+	writeManifest(t, filepath.Join(root, ".rycode", "plugins", "jira"), `{"name": "jira", "description": "project", "command": ["./jira"]}`)
+	writeManifest(t, filepath.Join(root, ".rycode", "plugins", "broken"), `{"name": "Broken!", "command": ["x"]}`)
+	os.MkdirAll(filepath.Join(root, ".rycode", "plugins", "empty"), 0o755)
+
+	manifests, invalid := Discover(configDir, root)
+	if len(manifests) != 2 || manifests[0].Name != "clock" || manifests[1].Name != "jira" {
+		t.Fatalf("manifests = %+v, want clock and jira", manifests)
+	}
+	if manifests[1].Description != "project" {
+		t.Errorf("jira = %+v, want the project's manifest", manifests[1])
+	}
+	if len(invalid) != 1 || invalid["broken"] == nil {
+		t.Errorf("invalid = %v, want broken only", invalid)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name          string
+		contributions Contributions
+		valid         bool
+	}{
+		{"valid", Contributions{Commands: []Command{{Name: "issue", Trigger: "jira"}}, Widgets: []Widget{{Name: "issue"}}}, true},
+		{"bad command name", Contributions{Commands: []Command{{Name: "Issue"}}}, false},
+		{"trigger with space", Contributions{Commands: []Command{{Name: "issue", Trigger: "a b"}}}, false},
+		{"duplicate widget", Contributions{Widgets: []Widget{{Name: "w"}, {Name: "w"}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.contributions.Validate(); (err == nil) != tt.valid {
+				t.Errorf("Validate() = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+// TestHelperPlugin is the plugin process started by TestProcess
+func TestHelperPlugin(t *testing.T) {
+	if os.Getenv("RYCODE_TEST_PLUGIN") != "1" {
+		t.Skip("started by TestProcess")
+	}
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var req struct {
+			ID     *int64          `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		json.Unmarshal(scanner.Bytes(), &req)
+		var result any
+		switch req.Method {
+		case MethodInitialize:
+			result = Contributions{Commands: []Command{{Name: "echo"}}, Widgets: []Widget{{Name: "name"}}}
+		case MethodUpdate:
+			var event Event
+			json.Unmarshal(req.Params, &event)
+			result = Action{Toast: &Toast{Message: event.Command + " " + event.Args}}
+		case MethodView:
+			result = os.Getenv(EnvPlugin)
+		case MethodShutdown:
+			os.Exit(0)
+		}
+		data, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+		fmt.Println(string(data))
+	}
+	os.Exit(0)
+}
+
+func TestProcess(t *testing.T) {
+	t.Setenv("RYCODE_TEST_PLUGIN", "1")
+	p, err := Start(Manifest{Name: "helper", Command: []string{os.Args[0], "-test.run=^TestHelperPlugin$"}, Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	contributions, err := p.Init(ctx, Env{Version: "test"})
+	if err != nil || len(contributions.Commands) != 1 || contributions.Commands[0].Name != "echo" {
+		t.Fatalf("Init() = %+v, %v", contributions, err)
+	}
+	action, err := p.Update(ctx, Event{Type: EventCommand, Command: "echo", Args: "hi"})
+	if err != nil || action == nil || action.Toast == nil || action.Toast.Message != "echo hi" {
+		t.Fatalf("Update() = %+v, %v", action, err)
+	}
+	if text, err := p.View(ctx, "name"); err != nil || text != "helper" {
+		t.Fatalf("View() = %q, %v, want the plugin's name", text, err)
+	}
+
+	p.Close()
+	if _, err := p.View(ctx, "name"); err == nil {
+		t.Error("View() after Close succeeded")
+	}
+}
+
+type fakePlugin struct {
+	inits  int
+	closed bool
+}
+
+func (p *fakePlugin) Init(ctx context.Context, env Env) (Contributions, error) {
+	p.inits++
+	return Contributions{Commands: []Command{{Name: "deploy"}}}, nil
+}
+
+func (p *fakePlugin) Update(ctx context.Context, event Event) (*Action, error) {
+	return &Action{Prompt: "deploy " + event.Args}, nil
+}
+
+func (p *fakePlugin) View(ctx context.Context, widget string) (string, error) {
+	return "", nil
+}
+
+func (p *fakePlugin) Close() error {
+	p.closed = true
+	return nil
+}
+
+func TestEmbedded(t *testing.T) {
+	configDir, root := t.TempDir(), t.TempDir()
+	writeManifest(t, filepath.Join(configDir, "plugins", "deploy"), `{"name": "deploy", "command": ["./deploy"]}`)
+	fake := &fakePlugin{}
+	host := NewHost(Env{})
+	host.Embed(Embedded{Name: "deploy", Description: "embedded", Plugin: fake})
+	host.Load(configDir, root, nil)
+
+	l, ok := host.Get("deploy")
+	if !ok || !l.Running() || l.Manifest.Description != "embedded" || len(l.Contributions.Commands) != 1 {
+		t.Fatalf("embedded plugin = %+v", l)
+	}
+	if plugins := host.Plugins(); len(plugins) != 1 {
+		t.Errorf("plugins = %+v, want the embedded one replacing the installed one", plugins)
+	}
+
+	if _, err := host.SetEnabled("deploy", false); err != nil || !fake.closed {
+		t.Fatalf("disabling = %v, closed %v", err, fake.closed)
+	}
+	if l, err := host.SetEnabled("deploy", true); err != nil || !l.Running() || fake.inits != 2 {
+		t.Errorf("enabling = %+v, %v after %d inits", l, err, fake.inits)
+	}
+}
@@ -0,0 +1,238 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Methods the TUI calls on a plugin process
+const (
+	MethodInitialize = "initialize" // Params are an Env, the result Contributions
+	MethodUpdate     = "update"     // Params are an Event, the result an Action or null
+	MethodView       = "view"       // Params are {"widget": name}, the result a string
+	MethodRender     = "render"     // Params are a Part, the result a string
+	MethodShutdown   = "shutdown"   // Notification sent before the process is stopped
+)
+
+// EnvPlugin holds the plugin's name in its process
+const EnvPlugin = "RYCODE_PLUGIN"
+
+// maxMessage bounds a message from a plugin
+const maxMessage = 4 << 20
+
+// shutdownTimeout is how long a plugin has to exit once asked to
+const shutdownTimeout = 2 * time.Second
+
+// ErrExited is returned by the calls to a plugin process that has exited
+var ErrExited = errors.New("plugin exited")
+
+// Process is a plugin running as a separate program. The program reads
+// requests from its stdin and writes responses to its stdout, as JSON-RPC
+// 2.0 messages of one line each; what it writes to stderr is logged.
+type Process struct {
+	manifest Manifest
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+
+	wmu     sync.Mutex
+	mu      sync.Mutex
+	nextID  int64
+	pending map[int64]chan message
+	done    chan struct{}
+	err     error         // Why the process exited, set before done is closed
+	logged  chan struct{} // Closed once stderr is drained
+}
+
+// message is a request, response or notification
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int64          `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  any             `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// Start runs the program of a plugin in its directory
+func Start(manifest Manifest) (*Process, error) {
+	name := manifest.Command[0]
+	if !filepath.IsAbs(name) && (strings.ContainsRune(name, filepath.Separator) || strings.HasPrefix(name, ".")) {
+		name = filepath.Join(manifest.Dir, name)
+	}
+	cmd := exec.Command(name, manifest.Command[1:]...)
+	cmd.Dir = manifest.Dir
+	cmd.Env = append(os.Environ(), EnvPlugin+"="+manifest.Name)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	p := &Process{
+		manifest: manifest,
+		cmd:      cmd,
+		stdin:    stdin,
+		pending:  make(map[int64]chan message),
+		done:     make(chan struct{}),
+		logged:   make(chan struct{}),
+	}
+	go p.log(stderr)
+	go p.read(stdout)
+	return p, nil
+}
+
+func (p *Process) Init(ctx context.Context, env Env) (Contributions, error) {
+	var contributions Contributions
+	if err := p.call(ctx, MethodInitialize, env, &contributions); err != nil {
+		return Contributions{}, err
+	}
+	return contributions, contributions.Validate()
+}
+
+func (p *Process) Update(ctx context.Context, event Event) (*Action, error) {
+	var action *Action
+	err := p.call(ctx, MethodUpdate, event, &action)
+	return action, err
+}
+
+func (p *Process) View(ctx context.Context, widget string) (string, error) {
+	var text string
+	err := p.call(ctx, MethodView, map[string]string{"widget": widget}, &text)
+	return text, err
+}
+
+func (p *Process) RenderPart(ctx context.Context, part Part) (string, error) {
+	var text string
+	err := p.call(ctx, MethodRender, part, &text)
+	return text, err
+}
+
+// Close asks the plugin to exit and kills it when it doesn't
+func (p *Process) Close() error {
+	p.write(message{Method: MethodShutdown})
+	p.stdin.Close()
+	select {
+	case <-p.done:
+	case <-time.After(shutdownTimeout):
+		p.cmd.Process.Kill()
+		<-p.done
+	}
+	return nil
+}
+
+func (p *Process) call(ctx context.Context, method string, params, result any) error {
+	p.mu.Lock()
+	select {
+	case <-p.done:
+		p.mu.Unlock()
+		return p.err
+	default:
+	}
+	p.nextID++
+	id := p.nextID
+	response := make(chan message, 1)
+	p.pending[id] = response
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		delete(p.pending, id)
+		p.mu.Unlock()
+	}()
+
+	if err := p.write(message{ID: &id, Method: method, Params: params}); err != nil {
+		return err
+	}
+	select {
+	case m := <-response:
+		if m.Error != nil {
+			return fmt.Errorf("%s: %s", p.manifest.Name, m.Error.Message)
+		}
+		if len(m.Result) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(m.Result, result); err != nil {
+			return fmt.Errorf("%s: invalid %s result: %w", p.manifest.Name, method, err)
+		}
+		return nil
+	case <-p.done:
+		return p.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *Process) write(m message) error {
+	m.JSONRPC = "2.0"
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	p.wmu.Lock()
+	defer p.wmu.Unlock()
+	_, err = p.stdin.Write(append(data, '\n'))
+	return err
+}
+
+// read hands the responses of the plugin to the calls waiting for them,
+// until the plugin exits
+func (p *Process) read(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), maxMessage)
+	for scanner.Scan() {
+		var m message
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil || m.ID == nil {
+			slog.Debug("Ignored plugin output", "plugin", p.manifest.Name, "line", scanner.Text())
+			continue
+		}
+		p.mu.Lock()
+		response, ok := p.pending[*m.ID]
+		p.mu.Unlock()
+		if ok {
+			response <- m
+		}
+	}
+
+	<-p.logged
+	err := p.cmd.Wait()
+	if err == nil {
+		err = ErrExited
+	} else {
+		err = fmt.Errorf("%w: %v", ErrExited, err)
+	}
+	p.mu.Lock()
+	p.err = fmt.Errorf("%s: %w", p.manifest.Name, err)
+	close(p.done)
+	p.mu.Unlock()
+}
+
+func (p *Process) log(stderr io.Reader) {
+	defer close(p.logged)
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		slog.Info("Plugin", "plugin", p.manifest.Name, "message", scanner.Text())
+	}
+}
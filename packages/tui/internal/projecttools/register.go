@@ -0,0 +1,200 @@
+package projecttools
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+)
+
+// ToolDir is where the server loads the project's own tools from, relative
+// to the project root
+const ToolDir = ".opencode/tool"
+
+// generatedHeader starts the modules Register writes, which are replaced
+// or removed as the declared tools change while others are left alone
+const generatedHeader = "// Generated by rycode from " + ConfigPath + "; edits are overwritten.\n"
+
+// ID returns the name the server registers a tool under, as model APIs
+// take no colons in tool names
+func (t Tool) ID() string {
+	return strings.ReplaceAll(t.Name, ":", "_")
+}
+
+// Register writes a module for each tool to the server's tool directory of
+// the project at root and removes the modules of tools no longer declared.
+// A module hands its call to the TUI over the editor bridge, where the
+// arguments are checked and the command rendered and run by Render, so a
+// call runs exactly as /tools would. It reports whether a module changed,
+// as the server reads them when it starts.
+func Register(root string, tools []Tool) (bool, error) {
+	dir := filepath.Join(root, ToolDir)
+	entries, err := remote.ReadDir(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, err
+	}
+	if len(tools) == 0 && len(entries) == 0 {
+		return false, nil
+	}
+
+	changed := false
+	wanted := make(map[string]bool)
+	for _, tool := range tools {
+		name := tool.ID() + ".ts"
+		wanted[name] = true
+		path := filepath.Join(dir, name)
+		module := tool.module()
+		if existing, err := remote.ReadFile(path); err == nil && bytes.Equal(existing, module) {
+			continue
+		} else if err == nil && !bytes.HasPrefix(existing, []byte(generatedHeader)) {
+			return changed, fmt.Errorf("%s exists and was not generated from %s", filepath.Join(ToolDir, name), ConfigPath)
+		}
+		if !changed {
+			if err := remote.MkdirAll(dir, 0755); err != nil {
+				return false, err
+			}
+		}
+		if err := remote.WriteFile(path, module, 0644); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+	for _, entry := range entries {
+		if wanted[entry.Name()] || !strings.HasSuffix(entry.Name(), ".ts") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if existing, err := remote.ReadFile(path); err != nil || !bytes.HasPrefix(existing, []byte(generatedHeader)) {
+			continue
+		}
+		if err := remote.Remove(path); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// module returns the source of the tool's module: its description and
+// arguments as the plugin API declares them, and a call to the TUI of the
+// project to run it
+func (t Tool) module() []byte {
+	var args []string
+	if t.Args != nil {
+		names := make([]string, 0, len(t.Args.Properties))
+		for name := range t.Args.Properties {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			zod := t.Args.Properties[name].zod()
+			if !slices.Contains(t.Args.Required, name) {
+				zod += ".optional()"
+			}
+			args = append(args, fmt.Sprintf("    %s: %s,", quote(name), zod))
+		}
+	}
+	description := t.Description
+	if description == "" {
+		description = "Runs " + t.Name
+	}
+	var b strings.Builder
+	b.WriteString(generatedHeader)
+	b.WriteString(moduleImports)
+	fmt.Fprintf(&b, "\nexport default tool({\n  description: %s,\n  args: {\n", quote(description))
+	for _, arg := range args {
+		b.WriteString(arg + "\n")
+	}
+	fmt.Fprintf(&b, "  },\n  execute: (args) => callTUI(%s, args),\n})\n", quote(t.Name))
+	b.WriteString(moduleBridge)
+	return []byte(b.String())
+}
+
+// zod returns the schema as the zod expression the plugin API takes
+func (s *Schema) zod() string {
+	var expr string
+	switch s.Type {
+	case "string":
+		expr = "tool.schema.string()"
+	case "number":
+		expr = "tool.schema.number()"
+	case "integer":
+		expr = "tool.schema.number().int()"
+	case "boolean":
+		expr = "tool.schema.boolean()"
+	default:
+		expr = "tool.schema.any()"
+	}
+	if len(s.Enum) > 0 && (s.Type == "" || s.Type == "string") {
+		values := make([]string, len(s.Enum))
+		for i, value := range s.Enum {
+			values[i] = quote(fmt.Sprint(value))
+		}
+		expr = "tool.schema.enum([" + strings.Join(values, ", ") + "])"
+	}
+	if s.Description != "" {
+		expr += ".describe(" + quote(s.Description) + ")"
+	}
+	return expr
+}
+
+// quote returns s as a string literal of the module
+func quote(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+const moduleImports = `import { tool } from "@rycode-ai/plugin"
+import { readdirSync, readFileSync } from "node:fs"
+import { connect } from "node:net"
+import { tmpdir } from "node:os"
+import { join, resolve } from "node:path"
+`
+
+// moduleBridge finds the TUI working on the project in the discovery files
+// of the editor bridge and makes the call over its socket
+const moduleBridge = `
+const root = resolve(import.meta.dirname, "..", "..")
+
+function bridgeSocket(): string {
+  if (process.env.RYCODE_BRIDGE) return process.env.RYCODE_BRIDGE
+  const dir = process.env.XDG_RUNTIME_DIR
+    ? join(process.env.XDG_RUNTIME_DIR, "rycode")
+    : join(tmpdir(), "rycode-" + (process.getuid?.() ?? -1))
+  for (const file of readdirSync(dir).filter((f) => f.endsWith(".json"))) {
+    try {
+      const info = JSON.parse(readFileSync(join(dir, file), "utf8"))
+      if (resolve(info.root) !== root) continue
+      process.kill(info.pid, 0)
+      return info.socket
+    } catch {}
+  }
+  throw new Error("No rycode TUI is open on " + root + " to run project tools")
+}
+
+function callTUI(name: string, args: Record<string, unknown>): Promise<string> {
+  return new Promise((done, fail) => {
+    const socket = connect(bridgeSocket())
+    let buffer = ""
+    socket.on("connect", () => {
+      socket.write(JSON.stringify({ jsonrpc: "2.0", id: 1, method: "tool", params: { name, args } }) + "\n")
+    })
+    socket.on("data", (data) => {
+      buffer += data.toString()
+      const end = buffer.indexOf("\n")
+      if (end < 0) return
+      socket.end()
+      const reply = JSON.parse(buffer.slice(0, end))
+      if (reply.error) fail(new Error(reply.error.message))
+      else done(String(reply.result))
+    })
+    socket.on("error", fail)
+  })
+}
+`
@@ -0,0 +1,89 @@
+package projecttools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	root := t.TempDir()
+	migrate := Tool{
+		Name:        "db:migrate",
+		Description: "Migrate the database",
+		Command:     "npm run db:migrate -- --to {{.version}}",
+		Args: &Schema{
+			Properties: map[string]*Schema{
+				"version": {Type: "string", Description: "Target version"},
+				"env":     {Enum: []any{"dev", "prod"}},
+			},
+			Required: []string{"version"},
+		},
+	}
+	if err := migrate.parse(); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(root, ToolDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// A tool written by hand is left alone, a generated one no longer
+	// declared is removed
+	for name, content := range map[string]string{"lint.ts": "export default {}\n", "gen_proto.ts": generatedHeader} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	changed, err := Register(root, []Tool{migrate})
+	if err != nil || !changed {
+		t.Fatalf("Register = %v, %v", changed, err)
+	}
+	module, err := os.ReadFile(filepath.Join(dir, "db_migrate.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`description: "Migrate the database"`,
+		`"env": tool.schema.enum(["dev", "prod"]).optional(),`,
+		`"version": tool.schema.string().describe("Target version"),`,
+		`callTUI("db:migrate", args)`,
+	} {
+		if !strings.Contains(string(module), want) {
+			t.Errorf("module lacks %s:\n%s", want, module)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "gen_proto.ts")); !os.IsNotExist(err) {
+		t.Errorf("the module of a tool no longer declared was kept: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "lint.ts")); err != nil {
+		t.Errorf("a module written by hand was removed: %v", err)
+	}
+
+	if changed, err := Register(root, []Tool{migrate}); err != nil || changed {
+		t.Errorf("registering the same tools again = %v, %v", changed, err)
+	}
+	lint := Tool{Name: "lint", Command: "make lint"}
+	if _, err := Register(root, []Tool{lint}); err == nil {
+		t.Error("a module written by hand was replaced")
+	}
+}
+
+func TestRun(t *testing.T) {
+	tool := Tool{Name: "greet", Command: "echo hello {{.name}}", Args: &Schema{
+		Properties: map[string]*Schema{"name": {Type: "string"}},
+		Required:   []string{"name"},
+	}}
+	if err := tool.parse(); err != nil {
+		t.Fatal(err)
+	}
+	output, err := tool.Run(context.Background(), t.TempDir(), map[string]any{"name": "a b; exit 1"})
+	if err != nil || output != "hello a b; exit 1\n" {
+		t.Errorf("Run = %q, %v", output, err)
+	}
+	if _, err := tool.Run(context.Background(), t.TempDir(), map[string]any{}); err == nil {
+		t.Error("a call without a required argument was run")
+	}
+}
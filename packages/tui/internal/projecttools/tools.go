@@ -0,0 +1,246 @@
+// Package projecttools reads the tools a project declares for the agent in
+// .rycode.json, such as db:migrate or gen:proto: a description, a shell
+// command template and a JSON schema of the arguments. They are registered
+// with the server as modules of its tool directory, which call back the TUI
+// to run them.
+package projecttools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+	"github.com/aaronmrosenthal/rycode/internal/target"
+)
+
+// ConfigPath is the project configuration declaring the tools, relative to
+// the project root. Its other settings are left to the server.
+const ConfigPath = ".rycode.json"
+
+// Tool is a project script the agent can call
+type Tool struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Command     string  `json:"command"`        // Shell command as a text/template, {{.arg}} standing for each argument
+	Args        *Schema `json:"args,omitempty"` // Schema of the arguments, an object of named properties
+
+	template *template.Template
+}
+
+// Schema is the subset of JSON schema the arguments of a tool are checked
+// against
+type Schema struct {
+	Type        string             `json:"type,omitempty"` // string, number, integer, boolean or object
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Enum        []any              `json:"enum,omitempty"`
+}
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9:_-]*$`)
+
+// Load reads the tools of the project at root, nil when it declares none
+func Load(root string) ([]Tool, error) {
+	data, err := remote.ReadFile(filepath.Join(root, ConfigPath))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var config struct {
+		Tools []Tool `json:"tools"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("%s: %w", ConfigPath, err)
+	}
+	seen := make(map[string]bool)
+	for i := range config.Tools {
+		tool := &config.Tools[i]
+		if err := tool.parse(); err != nil {
+			return nil, fmt.Errorf("%s: %w", ConfigPath, err)
+		}
+		// db:migrate and db_migrate are registered under the same ID
+		if seen[tool.ID()] {
+			return nil, fmt.Errorf("%s: tool %s declared twice", ConfigPath, tool.Name)
+		}
+		seen[tool.ID()] = true
+	}
+	return config.Tools, nil
+}
+
+// parse checks a tool's declaration and parses its command
+func (t *Tool) parse() error {
+	if !validName.MatchString(t.Name) {
+		return fmt.Errorf("invalid tool name %q", t.Name)
+	}
+	if strings.TrimSpace(t.Command) == "" {
+		return fmt.Errorf("tool %s has no command", t.Name)
+	}
+	if t.Args != nil && t.Args.Type != "" && t.Args.Type != "object" {
+		return fmt.Errorf("the args of tool %s must be an object", t.Name)
+	}
+	tmpl, err := template.New(t.Name).Option("missingkey=zero").Parse(t.Command)
+	if err != nil {
+		return fmt.Errorf("command of tool %s: %w", t.Name, err)
+	}
+	t.template = tmpl
+	return nil
+}
+
+// Render checks arguments against the tool's schema and returns its command
+// with them, each quoted for the shell
+func (t Tool) Render(args map[string]any) (string, error) {
+	if t.Args != nil {
+		if err := t.Args.Validate("", args); err != nil {
+			return "", fmt.Errorf("%s: %w", t.Name, err)
+		}
+	}
+	quoted := make(map[string]string, len(args))
+	for name, value := range args {
+		var text string
+		switch value := value.(type) {
+		case bool:
+			// False is left empty, for {{if .flag}} to test
+			if !value {
+				continue
+			}
+			text = "true"
+		case string:
+			text = value
+		case map[string]any, []any:
+			data, _ := json.Marshal(value)
+			text = string(data)
+		default:
+			text = fmt.Sprint(value)
+		}
+		quoted[name] = remote.Quote(text)
+	}
+	var b strings.Builder
+	if err := t.template.Execute(&b, quoted); err != nil {
+		return "", fmt.Errorf("%s: %w", t.Name, err)
+	}
+	return b.String(), nil
+}
+
+// maxOutput bounds the output of a run handed back to the agent, which
+// keeps its end
+const maxOutput = 50 << 10
+
+// Run checks arguments against the tool's schema, renders its command with
+// them and runs it in the execution target of the project at root,
+// returning its combined output. The error is non-nil when the command
+// fails.
+func (t Tool) Run(ctx context.Context, root string, args map[string]any) (string, error) {
+	command, err := t.Render(args)
+	if err != nil {
+		return "", err
+	}
+	output, err := target.Shell(ctx, root, command).CombinedOutput()
+	if len(output) > maxOutput {
+		output = append([]byte("…\n"), output[len(output)-maxOutput:]...)
+	}
+	return string(output), err
+}
+
+// ParseArgs reads arguments typed as name=value, converting the values to
+// the types of the tool's schema
+func (t Tool) ParseArgs(fields []string) (map[string]any, error) {
+	args := make(map[string]any)
+	for _, field := range fields {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("expected name=value, got %q", field)
+		}
+		var property *Schema
+		if t.Args != nil {
+			property = t.Args.Properties[name]
+		}
+		args[name] = value
+		if property == nil {
+			continue
+		}
+		switch property.Type {
+		case "number", "integer":
+			if n, err := strconv.ParseFloat(value, 64); err == nil {
+				args[name] = n
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(value); err == nil {
+				args[name] = b
+			}
+		}
+	}
+	return args, nil
+}
+
+// Validate checks a value against the schema, naming it by path in errors
+func (s *Schema) Validate(path string, value any) error {
+	name := path
+	if name == "" {
+		name = "args"
+	}
+	switch s.Type {
+	case "", "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			if s.Type == "" {
+				break
+			}
+			return fmt.Errorf("%s must be an object", name)
+		}
+		for _, required := range s.Required {
+			if _, ok := object[required]; !ok {
+				return fmt.Errorf("missing %s", join(path, required))
+			}
+		}
+		for key, v := range object {
+			property, ok := s.Properties[key]
+			if !ok {
+				if len(s.Properties) > 0 {
+					return fmt.Errorf("unknown argument %s", join(path, key))
+				}
+				continue
+			}
+			if err := property.Validate(join(path, key), v); err != nil {
+				return err
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s must be a string", name)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("%s must be a number", name)
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != float64(int64(n)) {
+			return fmt.Errorf("%s must be an integer", name)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s must be true or false", name)
+		}
+	}
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e any) bool { return fmt.Sprint(e) == fmt.Sprint(value) }) {
+		return fmt.Errorf("%s must be one of %v", name, s.Enum)
+	}
+	return nil
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
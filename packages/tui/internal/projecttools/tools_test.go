@@ -0,0 +1,79 @@
+package projecttools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+	if tools, err := Load(root); err != nil || tools != nil {
+		t.Fatalf("tools without a config = %v, %v", tools, err)
+	}
+
+	config := `{
+		"theme": "ignored",
+		"tools": [{
+			"name": "db:migrate",
+			"description": "Migrate the database",
+			"command": "npm run db:migrate -- --to {{.version}}{{if .dry}} --dry-run{{end}}",
+			"args": {
+				"type": "object",
+				"properties": {
+					"version": {"type": "string"},
+					"dry": {"type": "boolean"}
+				},
+				"required": ["version"]
+			}
+		}]
+	}`
+	if err := os.WriteFile(filepath.Join(root, ConfigPath), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tools, err := Load(root)
+	if err != nil || len(tools) != 1 {
+		t.Fatalf("tools = %v, %v", tools, err)
+	}
+	tool := tools[0]
+
+	args, err := tool.ParseArgs([]string{"version=2024 01", "dry=true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	command, err := tool.Render(args)
+	if want := "npm run db:migrate -- --to '2024 01' --dry-run"; err != nil || command != want {
+		t.Errorf("command = %q, %v; want %q", command, err, want)
+	}
+	if command, _ := tool.Render(map[string]any{"version": "1", "dry": false}); strings.Contains(command, "--dry-run") {
+		t.Errorf("command with a false flag = %q", command)
+	}
+	if _, err := tool.Render(map[string]any{"dry": true}); err == nil || !strings.Contains(err.Error(), "missing version") {
+		t.Errorf("rendering without a required argument = %v", err)
+	}
+	if _, err := tool.Render(map[string]any{"version": "1", "force": true}); err == nil {
+		t.Error("an unknown argument was accepted")
+	}
+	if _, err := tool.Render(map[string]any{"version": 3.0}); err == nil {
+		t.Error("a number was accepted for a string")
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	for _, config := range []string{
+		`{"tools": [{"name": "Gen Proto", "command": "make proto"}]}`,
+		`{"tools": [{"name": "gen:proto"}]}`,
+		`{"tools": [{"name": "gen:proto", "command": "make {{.target"}]}`,
+		`{"tools": [{"name": "gen", "command": "make"}, {"name": "gen", "command": "make all"}]}`,
+		`{"tools": [{"name": "gen:all", "command": "make"}, {"name": "gen_all", "command": "make all"}]}`,
+	} {
+		root := t.TempDir()
+		if err := os.WriteFile(filepath.Join(root, ConfigPath), []byte(config), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if tools, err := Load(root); err == nil {
+			t.Errorf("Load(%s) = %v, want an error", config, tools)
+		}
+	}
+}
@@ -0,0 +1,423 @@
+// Package quick is the "rycode quick" mode: a single prompt box for a quick
+// question, without the rest of the TUI, made for tmux display-popup and
+// floating windows. The answer can be typed into the pane the popup covers.
+//
+// A tmux binding opens it over the current pane:
+//
+//	bind-key R display-popup -E -w 80% -h 60% "rycode quick"
+package quick
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/spinner"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/components/textarea"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/util"
+)
+
+// readOnlyTools keeps quick questions from changing files
+var readOnlyTools = map[string]bool{
+	"edit":      false,
+	"write":     false,
+	"patch":     false,
+	"todowrite": false,
+}
+
+// Options configure the quick mode
+type Options struct {
+	Client    *opencode.Client
+	Directory string
+	State     *app.State // The TUI's state, for the model and agent it uses
+	Pane      string     // tmux pane answers are inserted into, empty outside tmux
+	Prompt    string     // Asked right away when set
+}
+
+type phase int
+
+const (
+	asking phase = iota
+	waiting
+	answered
+)
+
+type sessionMsg struct {
+	id  string
+	err error
+}
+
+type answerMsg struct {
+	text string
+	err  error
+}
+
+// noticeMsg is shown in the help line, such as the result of a copy
+type noticeMsg string
+
+// Model is the quick mode's program
+type Model struct {
+	opts     Options
+	input    textarea.Model
+	spinner  spinner.Model
+	phase    phase
+	session  string
+	question string
+	messages map[string]bool   // Assistant messages of the session
+	parts    []string          // Text parts of the answer, in order
+	texts    map[string]string // Streamed text by part
+	markdown util.MarkdownStream
+	tool     string // Tool the answer waits on
+	answer   string
+	err      error
+	notice   string
+	scroll   int
+	width    int
+	height   int
+}
+
+// New creates the quick mode
+func New(opts Options) *Model {
+	t := theme.CurrentTheme()
+	input := textarea.New()
+	input.Prompt = ""
+	input.Placeholder = "Ask a quick question"
+	input.ShowLineNumbers = false
+	input.CharLimit = -1
+	input.SetHeight(3)
+	input.Styles.Focused.Base = styles.NewStyle().Foreground(t.Text()).Lipgloss()
+	input.Styles.Focused.Text = styles.NewStyle().Foreground(t.Text()).Lipgloss()
+	input.Styles.Focused.Placeholder = styles.NewStyle().Foreground(t.TextMuted()).Lipgloss()
+	input.Focus()
+	if opts.Prompt != "" {
+		input.SetValue(opts.Prompt)
+	}
+	return &Model{
+		opts:     opts,
+		input:    input,
+		spinner:  spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(styles.NewStyle().Foreground(t.Primary()).Lipgloss())),
+		messages: make(map[string]bool),
+		texts:    make(map[string]string),
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	if m.opts.Prompt != "" {
+		return m.ask()
+	}
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.input.SetWidth(max(10, m.width-4))
+	case tea.KeyPressMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		return m, m.key(msg)
+	case spinner.TickMsg:
+		if m.phase == waiting {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+	case sessionMsg:
+		if msg.err != nil {
+			m.finish("", msg.err)
+			return m, nil
+		}
+		m.session = msg.id
+		return m, m.prompt()
+	case answerMsg:
+		if m.phase == waiting {
+			m.finish(msg.text, msg.err)
+		}
+	case noticeMsg:
+		m.notice = string(msg)
+	case opencode.EventListResponseEventMessageUpdated:
+		if assistant, ok := msg.Properties.Info.AsUnion().(opencode.AssistantMessage); ok && assistant.SessionID == m.session {
+			m.messages[assistant.ID] = true
+		}
+	case opencode.EventListResponseEventMessagePartUpdated:
+		part := msg.Properties.Part
+		if m.phase != waiting || part.SessionID != m.session || !m.messages[part.MessageID] {
+			break
+		}
+		switch part.Type {
+		case opencode.PartTypeText:
+			if part.Synthetic {
+				break
+			}
+			if _, ok := m.texts[part.ID]; !ok {
+				m.parts = append(m.parts, part.ID)
+			}
+			m.texts[part.ID] = part.Text
+			m.tool = ""
+		case opencode.PartTypeTool:
+			m.tool = part.Tool
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) key(msg tea.KeyPressMsg) tea.Cmd {
+	switch m.phase {
+	case asking:
+		switch msg.String() {
+		case "esc":
+			return tea.Quit
+		case "enter":
+			return m.ask()
+		case "shift+enter", "ctrl+j":
+			m.input.Newline()
+			return nil
+		}
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return cmd
+	case waiting:
+		if msg.String() == "esc" {
+			m.phase = asking
+			m.input.SetValue(m.question)
+			client, session := m.opts.Client, m.session
+			return func() tea.Msg {
+				if session != "" {
+					client.Session.Abort(context.Background(), session, opencode.SessionAbortParams{})
+				}
+				return nil
+			}
+		}
+		return nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		return tea.Quit
+	case "enter", "n":
+		m.phase = asking
+		m.input.Reset()
+		return nil
+	case "i", "a":
+		text := m.answer
+		if msg.String() == "i" {
+			text = Code(m.answer)
+		}
+		pane := m.opts.Pane
+		return func() tea.Msg {
+			if err := Insert(pane, text); err != nil {
+				return noticeMsg("Not inserted: " + err.Error())
+			}
+			return tea.QuitMsg{}
+		}
+	case "y":
+		return tea.Batch(app.SetClipboard(m.answer), func() tea.Msg { return noticeMsg("Copied") })
+	case "up", "k":
+		m.scroll = max(0, m.scroll-1)
+	case "down", "j":
+		m.scroll++
+	case "pgup":
+		m.scroll = max(0, m.scroll-m.answerHeight())
+	case "pgdown", "space":
+		m.scroll += m.answerHeight()
+	}
+	return nil
+}
+
+// ask sends the question in the prompt box, creating the session for the
+// first one. Follow-up questions go to the same session.
+func (m *Model) ask() tea.Cmd {
+	question := strings.TrimSpace(m.input.Value())
+	if question == "" {
+		return nil
+	}
+	m.phase = waiting
+	m.question = question
+	m.parts, m.texts = nil, make(map[string]string)
+	m.tool, m.answer, m.err, m.notice, m.scroll = "", "", nil, "", 0
+
+	if m.session != "" {
+		return tea.Batch(m.prompt(), m.spinner.Tick)
+	}
+	client, directory := m.opts.Client, m.opts.Directory
+	return tea.Batch(m.spinner.Tick, func() tea.Msg {
+		session, err := client.Session.New(context.Background(), opencode.SessionNewParams{
+			Directory: opencode.F(directory),
+		})
+		if err != nil {
+			return sessionMsg{err: fmt.Errorf("failed to create session: %w", err)}
+		}
+		return sessionMsg{id: session.ID}
+	})
+}
+
+// prompt sends the question with the model and agent the TUI last used
+func (m *Model) prompt() tea.Cmd {
+	params := opencode.SessionPromptParams{
+		Directory: opencode.F(m.opts.Directory),
+		Tools:     opencode.F(readOnlyTools),
+		Parts: opencode.F([]opencode.SessionPromptParamsPartUnion{
+			opencode.TextPartInputParam{
+				Type: opencode.F(opencode.TextPartInputTypeText),
+				Text: opencode.F(m.question),
+			},
+		}),
+	}
+	if state := m.opts.State; state != nil {
+		provider, model := state.Provider, state.Model
+		if agentModel, ok := state.AgentModel[state.Agent]; ok {
+			provider, model = agentModel.ProviderID, agentModel.ModelID
+		}
+		if provider != "" && model != "" {
+			params.Model = opencode.F(opencode.SessionPromptParamsModel{
+				ProviderID: opencode.F(provider),
+				ModelID:    opencode.F(model),
+			})
+		}
+		if state.Agent != "" {
+			params.Agent = opencode.F(state.Agent)
+		}
+	}
+	client, session := m.opts.Client, m.session
+	return func() tea.Msg {
+		response, err := client.Session.Prompt(context.Background(), session, params)
+		if err != nil {
+			return answerMsg{err: err}
+		}
+		var texts []string
+		for _, part := range response.Parts {
+			if text, ok := part.AsUnion().(opencode.TextPart); ok && !text.Synthetic {
+				texts = append(texts, text.Text)
+			}
+		}
+		return answerMsg{text: strings.TrimSpace(strings.Join(texts, "\n"))}
+	}
+}
+
+func (m *Model) finish(answer string, err error) {
+	m.phase = answered
+	m.answer, m.err = answer, err
+	m.tool = ""
+}
+
+// streamed returns the answer received so far
+func (m *Model) streamed() string {
+	if m.phase == answered {
+		return m.answer
+	}
+	var texts []string
+	for _, id := range m.parts {
+		texts = append(texts, m.texts[id])
+	}
+	return strings.TrimSpace(strings.Join(texts, "\n"))
+}
+
+// answerHeight is the number of lines the answer is shown in
+func (m *Model) answerHeight() int {
+	return max(1, m.height-m.boxHeight()-1)
+}
+
+func (m *Model) boxHeight() int {
+	if m.phase == asking {
+		return m.input.Height() + 2
+	}
+	return 3
+}
+
+func (m *Model) View() string {
+	t := theme.CurrentTheme()
+	width := max(20, m.width)
+	muted := styles.NewStyle().Foreground(t.TextMuted())
+	key := styles.NewStyle().Foreground(t.Text()).Bold(true)
+	box := styles.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Primary()).
+		Width(width - 2)
+
+	var top string
+	if m.phase == asking {
+		top = box.Render(m.input.View())
+	} else {
+		top = box.BorderForeground(t.BorderSubtle()).Render(muted.Render(truncate(m.question, width-4)))
+	}
+
+	var body string
+	switch {
+	case m.err != nil:
+		body = styles.NewStyle().Foreground(t.Error()).Width(width).Render(m.err.Error())
+	case m.phase == waiting && m.streamed() == "":
+		status := "thinking"
+		if m.tool != "" {
+			status = "running " + m.tool
+		}
+		body = m.spinner.View() + " " + muted.Render(status)
+	case m.phase != asking:
+		rendered := m.markdown.Render(m.streamed(), width, t.Background())
+		lines := strings.Split(rendered, "\n")
+		height := m.answerHeight()
+		if m.phase == waiting {
+			// Follow the answer as it streams
+			m.scroll = len(lines)
+		}
+		m.scroll = min(m.scroll, max(0, len(lines)-height))
+		body = strings.Join(lines[m.scroll:min(len(lines), m.scroll+height)], "\n")
+	}
+	body = lipgloss.NewStyle().Height(m.answerHeight()).MaxHeight(m.answerHeight()).Render(body)
+
+	help := func(pairs ...string) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			parts = append(parts, key.Render(pairs[i])+muted.Render(" "+pairs[i+1]))
+		}
+		return strings.Join(parts, muted.Render("  "))
+	}
+	var footer string
+	switch m.phase {
+	case asking:
+		footer = help("enter", "ask", "ctrl+j", "newline", "esc", "quit")
+	case waiting:
+		footer = help("esc", "cancel")
+	default:
+		pairs := []string{"enter", "follow up", "y", "copy"}
+		if m.opts.Pane != "" {
+			pairs = append([]string{"i", "insert code", "a", "insert answer"}, pairs...)
+		}
+		footer = help(append(pairs, "esc", "quit")...)
+	}
+	if m.notice != "" {
+		footer = muted.Render(m.notice) + "  " + footer
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, top, body, footer)
+}
+
+// Run runs the quick mode until it is quit, feeding it the server's events
+func Run(ctx context.Context, opts Options) error {
+	program := tea.NewProgram(New(opts), tea.WithAltScreen())
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		stream := opts.Client.Event.ListStreaming(ctx, opencode.EventListParams{})
+		for stream.Next() {
+			program.Send(stream.Current().AsUnion())
+		}
+	}()
+	_, err := program.Run()
+	return err
+}
+
+func truncate(s string, width int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if width <= 1 || len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
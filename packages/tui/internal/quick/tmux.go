@@ -0,0 +1,82 @@
+package quick
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// EnvPane names the tmux pane answers are inserted into, overriding the
+// pane found by Pane
+const EnvPane = "RYCODE_PANE"
+
+// ErrNoPane is returned when inserting outside tmux
+var ErrNoPane = errors.New("not running in tmux")
+
+// runTmux runs a tmux command with stdin as its input
+var runTmux = func(stdin string, args ...string) (string, error) {
+	cmd := exec.Command("tmux", args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	output, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		err = errors.New(strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return strings.TrimSpace(string(output)), err
+}
+
+// Pane returns the tmux pane answers are inserted into, or "" outside tmux.
+// Under display-popup that is the client's active pane, the one the popup
+// covers. Run in a pane of its own, it is the pane that was active before.
+func Pane() string {
+	if pane := os.Getenv(EnvPane); pane != "" {
+		return pane
+	}
+	if os.Getenv("TMUX") == "" {
+		return ""
+	}
+	pane, err := runTmux("", "display-message", "-p", "#{pane_id}")
+	if err != nil {
+		return ""
+	}
+	if pane == os.Getenv("TMUX_PANE") {
+		pane, err = runTmux("", "display-message", "-p", "-t", "{last}", "#{pane_id}")
+		if err != nil {
+			return ""
+		}
+	}
+	return pane
+}
+
+// Insert types text into a tmux pane without running it. A single line is
+// sent with send-keys; several lines are pasted with bracketed paste, so a
+// shell doesn't run them line by line.
+func Insert(pane, text string) error {
+	if pane == "" {
+		return ErrNoPane
+	}
+	text = strings.TrimRight(text, "\n")
+	if !strings.Contains(text, "\n") {
+		_, err := runTmux("", "send-keys", "-t", pane, "-l", "--", text)
+		return err
+	}
+	const buffer = "rycode-quick"
+	if _, err := runTmux(text, "load-buffer", "-b", buffer, "-"); err != nil {
+		return err
+	}
+	_, err := runTmux("", "paste-buffer", "-p", "-d", "-b", buffer, "-t", pane)
+	return err
+}
+
+var codeBlockPattern = regexp.MustCompile("(?s)```[^\n]*\n(.*?)```")
+
+// Code returns the first code block of an answer, which is what a quick
+// question is usually after, or else the whole answer
+func Code(answer string) string {
+	if match := codeBlockPattern.FindStringSubmatch(answer); match != nil {
+		return strings.TrimRight(match[1], "\n")
+	}
+	return strings.TrimSpace(answer)
+}
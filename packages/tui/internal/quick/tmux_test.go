@@ -0,0 +1,55 @@
+package quick
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCode(t *testing.T) {
+	answer := "Use find:\n\n```bash\nfind . -name '*.go' -mtime -1\n```\n\nOr ```fd```."
+	if got := Code(answer); got != "find . -name '*.go' -mtime -1" {
+		t.Errorf("Code = %q", got)
+	}
+	if got := Code("  Just run `make`.\n"); got != "Just run `make`." {
+		t.Errorf("Code without a block = %q", got)
+	}
+}
+
+func TestInsert(t *testing.T) {
+	type call struct {
+		stdin string
+		args  string
+	}
+	var calls []call
+	t.Cleanup(func(run func(string, ...string) (string, error)) func() {
+		return func() { runTmux = run }
+	}(runTmux))
+	runTmux = func(stdin string, args ...string) (string, error) {
+		calls = append(calls, call{stdin, strings.Join(args, " ")})
+		return "", nil
+	}
+
+	if err := Insert("", "ls"); err != ErrNoPane {
+		t.Errorf("Insert outside tmux = %v", err)
+	}
+
+	if err := Insert("%3", "ls -la\n"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []call{{"", "send-keys -t %3 -l -- ls -la"}}; !slices.Equal(calls, want) {
+		t.Errorf("single line calls = %q, want %q", calls, want)
+	}
+
+	calls = nil
+	if err := Insert("%3", "cd /tmp\nls"); err != nil {
+		t.Fatal(err)
+	}
+	want := []call{
+		{"cd /tmp\nls", "load-buffer -b rycode-quick -"},
+		{"", "paste-buffer -p -d -b rycode-quick -t %3"},
+	}
+	if !slices.Equal(calls, want) {
+		t.Errorf("multi-line calls = %q, want %q", calls, want)
+	}
+}
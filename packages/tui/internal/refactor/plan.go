@@ -0,0 +1,379 @@
+// Package refactor plans and tracks workspace-wide refactors that are applied
+// file by file in batches and reviewed as they land.
+package refactor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aaronmrosenthal/rycode/internal/target"
+)
+
+// DefaultPlanPath is where the plan of the current refactor is saved,
+// relative to the project root, so an interrupted refactor can be resumed
+const DefaultPlanPath = ".rycode/refactor.json"
+
+// DefaultBatchSize is the number of files sent to the agent at once
+const DefaultBatchSize = 5
+
+// FileStatus is the progress of a single file in a refactor
+type FileStatus string
+
+const (
+	StatusPending    FileStatus = "pending"    // Not processed yet
+	StatusProcessing FileStatus = "processing" // In the batch currently being applied
+	StatusChanged    FileStatus = "changed"    // Changed by the agent, awaiting review
+	StatusUnchanged  FileStatus = "unchanged"  // The agent left the file as is
+	StatusAccepted   FileStatus = "accepted"
+	StatusRejected   FileStatus = "rejected" // Restored to its original content
+	StatusFailed     FileStatus = "failed"
+)
+
+// File is one file of a refactor plan
+type File struct {
+	Path     string     `json:"path"`
+	Status   FileStatus `json:"status"`
+	Original string     `json:"original,omitempty"` // Content before the agent touched it
+	Missing  bool       `json:"missing,omitempty"`  // The file didn't exist before the agent ran
+	Error    string     `json:"error,omitempty"`
+}
+
+// Plan is a refactor description with the set of files it affects
+type Plan struct {
+	root         string
+	path         string    // Where the plan is saved, relative to root; DefaultPlanPath when empty
+	Title        string    `json:"title,omitempty"`
+	Description  string    `json:"description"`
+	Instructions string    `json:"instructions,omitempty"` // Sent to the agent instead of the description when set
+	Check        *Check    `json:"check,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	BatchSize    int       `json:"batch_size"`
+	SessionID    string    `json:"session_id,omitempty"`
+	Files        []File    `json:"files"`
+}
+
+// Check is a command, such as a test run, that verifies the kept changes
+// once every file has been reviewed
+type Check struct {
+	Command []string `json:"command"`
+	Dir     string   `json:"dir,omitempty"` // Relative to the project root
+}
+
+// maxCheckOutputLines bounds the check output kept for display
+const maxCheckOutputLines = 200
+
+// RunCheck runs the plan's check in the project's execution target and
+// returns the tail of its combined output. The error is non-nil when the
+// check fails.
+func (p *Plan) RunCheck(ctx context.Context) (string, error) {
+	if p.Check == nil || len(p.Check.Command) == 0 {
+		return "", fmt.Errorf("the plan has no check")
+	}
+	return p.Check.Run(ctx, p.root)
+}
+
+// Run runs the check in the execution target of the project at root, like
+// RunCheck
+func (c *Check) Run(ctx context.Context, root string) (string, error) {
+	cmd := target.Command(ctx, filepath.Join(root, c.Dir), c.Command[0], c.Command[1:]...) //nolint:gosec
+	output, err := cmd.CombinedOutput()
+
+	lines := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
+	if len(lines) > maxCheckOutputLines {
+		lines = append([]string{fmt.Sprintf("… %d lines omitted", len(lines)-maxCheckOutputLines)}, lines[len(lines)-maxCheckOutputLines:]...)
+	}
+	return strings.Join(lines, "\n"), err
+}
+
+// NewPlan creates a plan for the given files of the project at root
+func NewPlan(root, description string, files []string) *Plan {
+	plan := &Plan{
+		root:        root,
+		Description: description,
+		CreatedAt:   time.Now(),
+		BatchSize:   DefaultBatchSize,
+	}
+	for _, file := range files {
+		plan.AddFile(file)
+	}
+	return plan
+}
+
+// LoadPlan loads the saved plan of the project at root. Files that were
+// being processed when the refactor was interrupted are marked changed or
+// pending depending on whether the agent got to them.
+func LoadPlan(root string) (*Plan, error) {
+	return LoadPlanAt(root, DefaultPlanPath)
+}
+
+// LoadPlanAt loads a plan saved at path, relative to root, like LoadPlan
+func LoadPlanAt(root, planPath string) (*Plan, error) {
+	path := filepath.Join(root, planPath)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{root: root, path: planPath}
+	if err := json.Unmarshal(data, plan); err != nil {
+		return nil, fmt.Errorf("failed to decode refactor plan %s: %w", path, err)
+	}
+	if plan.BatchSize <= 0 {
+		plan.BatchSize = DefaultBatchSize
+	}
+	for i := range plan.Files {
+		if plan.Files[i].Status != StatusProcessing {
+			continue
+		}
+		if plan.changed(i) {
+			plan.Files[i].Status = StatusChanged
+		} else {
+			plan.Files[i].Status = StatusPending
+		}
+	}
+	return plan, nil
+}
+
+// SetPath sets where the plan is saved, relative to the project root, so
+// that plans applied like a refactor don't replace the refactor's own
+func (p *Plan) SetPath(path string) {
+	p.path = path
+}
+
+// file returns the absolute path the plan is saved at
+func (p *Plan) file() string {
+	if p.path == "" {
+		return filepath.Join(p.root, DefaultPlanPath)
+	}
+	return filepath.Join(p.root, p.path)
+}
+
+// Save writes the plan to disk
+func (p *Plan) Save() error {
+	path := p.file()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create plan directory: %w", err)
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode refactor plan: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write refactor plan %s: %w", path, err)
+	}
+	return nil
+}
+
+// Remove deletes the saved plan once the refactor is complete
+func (p *Plan) Remove() error {
+	if err := os.Remove(p.file()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// AddFile adds a file to the plan unless it is already part of it
+func (p *Plan) AddFile(path string) bool {
+	path = filepath.Clean(strings.TrimSpace(path))
+	if path == "." || path == "" {
+		return false
+	}
+	for _, file := range p.Files {
+		if file.Path == path {
+			return false
+		}
+	}
+	p.Files = append(p.Files, File{Path: path, Status: StatusPending})
+	return true
+}
+
+// RemoveFile drops a pending file from the plan
+func (p *Plan) RemoveFile(index int) bool {
+	if index < 0 || index >= len(p.Files) || p.Files[index].Status != StatusPending {
+		return false
+	}
+	p.Files = append(p.Files[:index], p.Files[index+1:]...)
+	return true
+}
+
+// Started reports whether any file has been processed
+func (p *Plan) Started() bool {
+	for _, file := range p.Files {
+		if file.Status != StatusPending {
+			return true
+		}
+	}
+	return false
+}
+
+// NextBatch returns the indices of the next pending files to process
+func (p *Plan) NextBatch() []int {
+	var batch []int
+	for i, file := range p.Files {
+		if file.Status == StatusPending {
+			batch = append(batch, i)
+			if len(batch) == p.BatchSize {
+				break
+			}
+		}
+	}
+	return batch
+}
+
+// AwaitingReview returns the indices of changed files not reviewed yet
+func (p *Plan) AwaitingReview() []int {
+	var review []int
+	for i, file := range p.Files {
+		if file.Status == StatusChanged {
+			review = append(review, i)
+		}
+	}
+	return review
+}
+
+// Progress returns how many files are fully processed out of the total
+func (p *Plan) Progress() (done, total int) {
+	for _, file := range p.Files {
+		switch file.Status {
+		case StatusPending, StatusProcessing, StatusChanged:
+		default:
+			done++
+		}
+	}
+	return done, len(p.Files)
+}
+
+// Finished reports whether every file has been processed and reviewed
+func (p *Plan) Finished() bool {
+	done, total := p.Progress()
+	return done == total
+}
+
+// StartBatch snapshots the files of a batch and marks them as processing.
+// The snapshot is taken before the agent runs so changes can be reviewed and
+// reverted.
+func (p *Plan) StartBatch(batch []int) {
+	for _, i := range batch {
+		content, err := os.ReadFile(p.absPath(i))
+		if err != nil && !os.IsNotExist(err) {
+			p.Files[i].Status = StatusFailed
+			p.Files[i].Error = err.Error()
+			continue
+		}
+		p.Files[i].Original = string(content)
+		p.Files[i].Missing = os.IsNotExist(err)
+		p.Files[i].Status = StatusProcessing
+		p.Files[i].Error = ""
+	}
+}
+
+// FinishBatch marks the processed files of a batch as changed or unchanged,
+// or failed when the agent returned an error
+func (p *Plan) FinishBatch(batch []int, batchErr error) {
+	for _, i := range batch {
+		if p.Files[i].Status != StatusProcessing {
+			continue
+		}
+		status := StatusUnchanged
+		if p.changed(i) {
+			status = StatusChanged
+		} else if batchErr != nil {
+			status = StatusFailed
+			p.Files[i].Error = batchErr.Error()
+		}
+		p.Files[i].Status = status
+		if status != StatusChanged {
+			p.Files[i].Original = ""
+		}
+	}
+}
+
+// Current returns the current content of a file
+func (p *Plan) Current(index int) string {
+	content, _ := os.ReadFile(p.absPath(index))
+	return string(content)
+}
+
+// Accept keeps the agent's changes to a file
+func (p *Plan) Accept(index int) {
+	p.Files[index].Status = StatusAccepted
+	p.Files[index].Original = ""
+}
+
+// Reject restores a file to its content before the agent changed it, or
+// removes it if the agent created it
+func (p *Plan) Reject(index int) error {
+	file := &p.Files[index]
+	if file.Missing {
+		if err := os.Remove(p.absPath(index)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", file.Path, err)
+		}
+	} else if err := os.WriteFile(p.absPath(index), []byte(file.Original), 0644); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", file.Path, err)
+	}
+	file.Status = StatusRejected
+	file.Original = ""
+	return nil
+}
+
+func (p *Plan) changed(index int) bool {
+	return p.Current(index) != p.Files[index].Original
+}
+
+func (p *Plan) absPath(index int) string {
+	path := p.Files[index].Path
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(p.root, path)
+}
+
+// listItemPattern strips list markers such as "-", "*" or "1." from a line
+var listItemPattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+`)
+
+// ParseFileList extracts existing file paths from a model's answer, one per
+// line. Bullets, backticks and trailing ":line" suffixes are ignored, as is
+// anything that isn't a file under root.
+func ParseFileList(answer, root string) []string {
+	var files []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(answer, "\n") {
+		line = listItemPattern.ReplaceAllString(line, "")
+		line = strings.Trim(strings.TrimSpace(line), "`'\"")
+		if fields := strings.Fields(line); len(fields) > 0 {
+			line = strings.Trim(fields[0], "`'\",")
+		}
+		if i := strings.Index(line, ":"); i > 0 {
+			line = line[:i]
+		}
+		if line == "" {
+			continue
+		}
+
+		path := line
+		if filepath.IsAbs(path) {
+			if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+				path = rel
+			}
+		}
+		path = filepath.Clean(path)
+		if seen[path] {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(root, path))
+		if filepath.IsAbs(path) {
+			info, err = os.Stat(path)
+		}
+		if err != nil || info.IsDir() {
+			continue
+		}
+		seen[path] = true
+		files = append(files, path)
+	}
+	return files
+}
@@ -0,0 +1,125 @@
+package refactor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFile(t *testing.T, root, path, content string) {
+	t.Helper()
+	full := filepath.Join(root, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseFileList(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "a.go", "")
+	writeFile(t, root, "pkg/b.go", "")
+
+	answer := "Here are the files:\n- `a.go`\n2. pkg/b.go:12\n* missing.go\n- pkg\n" + filepath.Join(root, "a.go")
+	got := ParseFileList(answer, root)
+	want := []string{"a.go", filepath.Join("pkg", "b.go")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestPlanBatchesAndReview(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a.go", "b.go", "c.go"} {
+		writeFile(t, root, name, "package x\n")
+	}
+	plan := NewPlan(root, "rename x", []string{"a.go", "b.go", "c.go", "a.go"})
+	plan.BatchSize = 2
+	if len(plan.Files) != 3 {
+		t.Fatalf("expected duplicate file to be ignored, got %d files", len(plan.Files))
+	}
+
+	batch := plan.NextBatch()
+	if !reflect.DeepEqual(batch, []int{0, 1}) {
+		t.Fatalf("unexpected first batch %v", batch)
+	}
+	plan.StartBatch(batch)
+	writeFile(t, root, "a.go", "package y\n")
+	plan.FinishBatch(batch, nil)
+
+	if plan.Files[0].Status != StatusChanged || plan.Files[1].Status != StatusUnchanged {
+		t.Fatalf("unexpected statuses %v %v", plan.Files[0].Status, plan.Files[1].Status)
+	}
+	if err := plan.Reject(0); err != nil {
+		t.Fatal(err)
+	}
+	if content := plan.Current(0); content != "package x\n" {
+		t.Errorf("expected rejected file to be restored, got %q", content)
+	}
+	if done, total := plan.Progress(); done != 2 || total != 3 {
+		t.Errorf("expected 2/3 done, got %d/%d", done, total)
+	}
+
+	batch = plan.NextBatch()
+	plan.StartBatch(batch)
+	plan.FinishBatch(batch, errors.New("timeout"))
+	if plan.Files[2].Status != StatusFailed || !plan.Finished() {
+		t.Errorf("expected failed batch to finish the plan, got %+v", plan.Files[2])
+	}
+}
+
+func TestLoadPlanRecoversInterruptedBatch(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "a.go", "one\n")
+	writeFile(t, root, "b.go", "two\n")
+	plan := NewPlan(root, "rewrite", []string{"a.go", "b.go"})
+	plan.StartBatch(plan.NextBatch())
+	if err := plan.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The agent got to a.go before the TUI was closed
+	writeFile(t, root, "a.go", "ONE\n")
+
+	loaded, err := LoadPlan(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Files[0].Status != StatusChanged || loaded.Files[1].Status != StatusPending {
+		t.Errorf("unexpected recovered statuses %v %v", loaded.Files[0].Status, loaded.Files[1].Status)
+	}
+
+	if err := loaded.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPlan(root); !os.IsNotExist(err) {
+		t.Errorf("expected removed plan to be gone, got %v", err)
+	}
+}
+
+func TestPlansAtOtherPathsKeepTheRefactor(t *testing.T) {
+	root := t.TempDir()
+	refactoring := NewPlan(root, "rewrite", []string{"a.go"})
+	if err := refactoring.Save(); err != nil {
+		t.Fatal(err)
+	}
+	docs := NewPlan(root, "Document a.go", []string{"a.go"})
+	docs.SetPath(".rycode/docgen.json")
+	if err := docs.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := docs.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadPlan(root)
+	if err != nil {
+		t.Fatalf("the refactor's plan is gone: %v", err)
+	}
+	if loaded.Description != "rewrite" {
+		t.Errorf("the refactor's plan was replaced by %q", loaded.Description)
+	}
+}
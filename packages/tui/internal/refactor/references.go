@@ -0,0 +1,347 @@
+package refactor
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+)
+
+// Location is a place in a file, with the zero-based line and UTF-16
+// column language servers count in
+type Location struct {
+	Path   string
+	Line   int
+	Column int
+}
+
+// ReferenceServer is the language server that finds references in the
+// files of a language
+type ReferenceServer struct {
+	Command    []string
+	Dir        string // Relative to the project root
+	LanguageID string
+}
+
+// ReferenceServerFor returns the language server for a file, run where the
+// nearest manifest of the file's language is, or nil for languages without
+// one
+func ReferenceServerFor(root, file string) *ReferenceServer {
+	rel, err := filepath.Rel(root, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = file
+	}
+	switch ext := filepath.Ext(rel); ext {
+	case ".go":
+		if dir, ok := findManifest(root, rel, "go.mod"); ok {
+			return &ReferenceServer{Command: []string{"gopls"}, Dir: dir, LanguageID: "go"}
+		}
+	case ".rs":
+		if dir, ok := findManifest(root, rel, "Cargo.toml"); ok {
+			return &ReferenceServer{Command: []string{"rust-analyzer"}, Dir: dir, LanguageID: "rust"}
+		}
+	case ".ts", ".tsx", ".mts", ".cts":
+		if dir, ok := findManifest(root, rel, "tsconfig.json"); ok {
+			language := "typescript"
+			if ext == ".tsx" {
+				language = "typescriptreact"
+			}
+			return &ReferenceServer{Command: []string{"typescript-language-server", "--stdio"}, Dir: dir, LanguageID: language}
+		}
+	case ".py":
+		return &ReferenceServer{Command: []string{"pyright-langserver", "--stdio"}, Dir: ".", LanguageID: "python"}
+	}
+	return nil
+}
+
+// ReferenceFinder finds references through the language servers of the
+// project, each started on first use and kept until Close
+type ReferenceFinder struct {
+	root    string
+	clients map[string]*lspClient
+	opened  map[string]bool // Files the servers were sent, by URI
+}
+
+// NewReferenceFinder creates a finder for the project at root
+func NewReferenceFinder(root string) *ReferenceFinder {
+	return &ReferenceFinder{root: root, clients: make(map[string]*lspClient), opened: make(map[string]bool)}
+}
+
+// References returns the places that refer to the symbol whose declaration
+// starts at a location, found by the language server of its file
+func (f *ReferenceFinder) References(ctx context.Context, name string, at Location) ([]Location, error) {
+	server := ReferenceServerFor(f.root, at.Path)
+	if server == nil {
+		return nil, fmt.Errorf("no language server finds references in %s", filepath.Base(at.Path))
+	}
+	content, err := remote.ReadFile(at.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	key := server.Dir + "\x00" + strings.Join(server.Command, " ")
+	client := f.clients[key]
+	if client == nil {
+		client, err = startLSPClient(ctx, filepath.Join(f.root, server.Dir), server.Command)
+		if err != nil {
+			return nil, err
+		}
+		f.clients[key] = client
+	}
+
+	uri := fileURI(at.Path)
+	if !f.opened[uri] {
+		if err := client.notify("textDocument/didOpen", map[string]any{
+			"textDocument": map[string]any{"uri": uri, "languageId": server.LanguageID, "version": 1, "text": string(content)},
+		}); err != nil {
+			return nil, err
+		}
+		f.opened[uri] = true
+	}
+	// Declarations are located by where they start, which is often a
+	// keyword before the name
+	at = nameLocation(string(content), name, at)
+
+	var result []struct {
+		URI   string `json:"uri"`
+		Range struct {
+			Start struct {
+				Line      int `json:"line"`
+				Character int `json:"character"`
+			} `json:"start"`
+		} `json:"range"`
+	}
+	err = client.call(ctx, "textDocument/references", map[string]any{
+		"textDocument": map[string]any{"uri": uri},
+		"position":     map[string]any{"line": at.Line, "character": at.Column},
+		"context":      map[string]any{"includeDeclaration": false},
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	locations := make([]Location, 0, len(result))
+	for _, r := range result {
+		locations = append(locations, Location{Path: uriPath(r.URI), Line: r.Range.Start.Line, Column: r.Range.Start.Character})
+	}
+	return locations, nil
+}
+
+// Close shuts the language servers down
+func (f *ReferenceFinder) Close() {
+	for key, client := range f.clients {
+		client.close()
+		delete(f.clients, key)
+	}
+	clear(f.opened)
+}
+
+// nameLocation finds a name at or after a location, within a few lines
+func nameLocation(content, name string, at Location) Location {
+	pattern := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b`)
+	lines := strings.Split(content, "\n")
+	for line := at.Line; line < len(lines) && line <= at.Line+5; line++ {
+		text := lines[line]
+		from := 0
+		if line == at.Line {
+			from = byteOffset(text, at.Column)
+		}
+		if match := pattern.FindStringIndex(text[from:]); match != nil {
+			return Location{Path: at.Path, Line: line, Column: len(utf16.Encode([]rune(text[:from+match[0]])))}
+		}
+	}
+	return at
+}
+
+// byteOffset converts a UTF-16 column of a line to a byte offset
+func byteOffset(text string, column int) int {
+	units := 0
+	for i, r := range text {
+		if units >= column {
+			return i
+		}
+		units += len(utf16.Encode([]rune{r}))
+	}
+	return len(text)
+}
+
+func fileURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+func uriPath(uri string) string {
+	if u, err := url.Parse(uri); err == nil && u.Scheme == "file" {
+		return filepath.FromSlash(u.Path)
+	}
+	return strings.TrimPrefix(uri, "file://")
+}
+
+// lspClient speaks the language server protocol to a server, as much of it
+// as finding references takes
+type lspClient struct {
+	cmd    *exec.Cmd
+	in     io.WriteCloser
+	out    *bufio.Reader
+	nextID int
+}
+
+func startLSPClient(ctx context.Context, dir string, command []string) (*lspClient, error) {
+	cmd := remote.Command(ctx, dir, command[0], command[1:]...) //nolint:gosec
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", command[0], err)
+	}
+	client := newLSPClient(out, in)
+	client.cmd = cmd
+
+	uri := fileURI(dir)
+	err = client.call(ctx, "initialize", map[string]any{
+		"processId":        nil,
+		"rootUri":          uri,
+		"capabilities":     map[string]any{},
+		"workspaceFolders": []map[string]any{{"uri": uri, "name": filepath.Base(dir)}},
+	}, nil)
+	if err == nil {
+		err = client.notify("initialized", map[string]any{})
+	}
+	if err != nil {
+		client.close()
+		return nil, fmt.Errorf("%s failed to start: %w", command[0], err)
+	}
+	return client, nil
+}
+
+func newLSPClient(r io.Reader, w io.WriteCloser) *lspClient {
+	return &lspClient{in: w, out: bufio.NewReader(r)}
+}
+
+type lspMessage struct {
+	ID     *json.RawMessage `json:"id,omitempty"`
+	Method string           `json:"method,omitempty"`
+	Params json.RawMessage  `json:"params,omitempty"`
+	Result json.RawMessage  `json:"result,omitempty"`
+	Error  *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// call sends a request and waits for its response, answering what the
+// server asks in the meantime
+func (c *lspClient) call(ctx context.Context, method string, params, result any) error {
+	c.nextID++
+	id := c.nextID
+	if err := c.write(map[string]any{"jsonrpc": "2.0", "id": id, "method": method, "params": params}); err != nil {
+		return err
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		message, err := c.read()
+		if err != nil {
+			return err
+		}
+		switch {
+		case message.ID != nil && message.Method != "":
+			if err := c.answer(message); err != nil {
+				return err
+			}
+		case message.ID != nil && string(*message.ID) == strconv.Itoa(id):
+			if message.Error != nil {
+				return fmt.Errorf("%s: %s", method, message.Error.Message)
+			}
+			if result == nil || len(message.Result) == 0 {
+				return nil
+			}
+			return json.Unmarshal(message.Result, result)
+		}
+	}
+}
+
+// answer replies to a request of the server. Configuration is asked for
+// item by item, and everything else is acknowledged.
+func (c *lspClient) answer(request lspMessage) error {
+	var result any
+	if request.Method == "workspace/configuration" {
+		var params struct {
+			Items []json.RawMessage `json:"items"`
+		}
+		json.Unmarshal(request.Params, &params)
+		result = make([]any, len(params.Items))
+	}
+	return c.write(map[string]any{"jsonrpc": "2.0", "id": request.ID, "result": result})
+}
+
+func (c *lspClient) notify(method string, params any) error {
+	return c.write(map[string]any{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+func (c *lspClient) write(message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.in, "Content-Length: %d\r\n\r\n%s", len(data), data)
+	return err
+}
+
+func (c *lspClient) read() (lspMessage, error) {
+	var message lspMessage
+	header, err := textproto.NewReader(c.out).ReadMIMEHeader()
+	if err != nil {
+		return message, err
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return message, errors.New("language server sent a message without a length")
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(c.out, data); err != nil {
+		return message, err
+	}
+	return message, json.Unmarshal(data, &message)
+}
+
+// lspExitGrace is how long a server has to exit once asked to
+const lspExitGrace = 2 * time.Second
+
+// close asks the server to shut down and exit, and kills it when it doesn't
+func (c *lspClient) close() {
+	if c.cmd == nil {
+		c.in.Close()
+		return
+	}
+	c.write(map[string]any{"jsonrpc": "2.0", "id": -1, "method": "shutdown"})
+	c.notify("exit", nil)
+	c.in.Close()
+	done := make(chan struct{})
+	go func() {
+		c.cmd.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(lspExitGrace):
+		c.cmd.Process.Kill()
+		<-done
+	}
+}
@@ -0,0 +1,60 @@
+package refactor
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"testing"
+)
+
+func TestReferenceServerFor(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "web/tsconfig.json", "{}\n")
+	writeFile(t, root, "web/src/app.tsx", "export {}\n")
+
+	server := ReferenceServerFor(root, filepath.Join(root, "web", "src", "app.tsx"))
+	if server == nil || server.Command[0] != "typescript-language-server" || server.Dir != "web" || server.LanguageID != "typescriptreact" {
+		t.Errorf("server = %+v", server)
+	}
+	if server := ReferenceServerFor(root, filepath.Join(root, "main.go")); server != nil {
+		t.Errorf("a Go file outside a module has server %+v", server)
+	}
+}
+
+func TestNameLocation(t *testing.T) {
+	content := "package config\n\n// Load reads it\nfunc (c *Config) LoadConfig(path string) error {\n"
+	got := nameLocation(content, "LoadConfig", Location{Path: "c.go", Line: 3})
+	if got != (Location{Path: "c.go", Line: 3, Column: 17}) {
+		t.Errorf("nameLocation = %+v", got)
+	}
+}
+
+func TestLSPClientCall(t *testing.T) {
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	client := newLSPClient(clientIn, clientOut)
+	server := newLSPClient(serverIn, serverOut)
+
+	go func() {
+		request, _ := server.read()
+		// The server asks for its configuration before it answers
+		server.write(map[string]any{"jsonrpc": "2.0", "id": "cfg", "method": "workspace/configuration", "params": map[string]any{"items": []any{map[string]any{}, map[string]any{}}}})
+		answer, _ := server.read()
+		var config []any
+		json.Unmarshal(answer.Result, &config)
+		server.write(map[string]any{"jsonrpc": "2.0", "method": "window/logMessage", "params": map[string]any{}})
+		server.write(map[string]any{"jsonrpc": "2.0", "id": request.ID, "result": []map[string]any{{"uri": "file:///p/b.go", "configItems": len(config)}}})
+	}()
+
+	var result []struct {
+		URI         string `json:"uri"`
+		ConfigItems int    `json:"configItems"`
+	}
+	if err := client.call(context.Background(), "textDocument/references", map[string]any{}, &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 1 || uriPath(result[0].URI) != filepath.FromSlash("/p/b.go") || result[0].ConfigItems != 2 {
+		t.Errorf("result = %+v", result)
+	}
+}
@@ -0,0 +1,244 @@
+package refactor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Edit is a change an agent's edit tool made to a file
+type Edit struct {
+	Path string
+	Old  string
+	New  string
+}
+
+// Rename is an identifier edits replaced with another one
+type Rename struct {
+	Old   string
+	New   string
+	Files []string
+}
+
+// Change is what a set of edits did that can leave references behind: the
+// identifiers they renamed and the functions whose signature they changed
+type Change struct {
+	Files      []string
+	Renames    []Rename
+	Signatures []string // Functions whose declaration changed, by name
+}
+
+// CrossFile reports whether the edits renamed a symbol or changed a
+// signature while touching more than one file, which is when references are
+// likely left behind
+func (c Change) CrossFile() bool {
+	return len(c.Files) > 1 && (len(c.Renames) > 0 || len(c.Signatures) > 0)
+}
+
+// minRenameLength keeps short names, which are mostly locals, out of
+// renames, as searching the project for them finds unrelated code
+const minRenameLength = 4
+
+var identifierPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// declarationPattern matches a function declaration in the languages the
+// agent usually edits, capturing the function's name
+var declarationPattern = regexp.MustCompile(`\b(?:func|def|fn|function)\s+(?:\([^)]*\)\s*)?([A-Za-z_][A-Za-z0-9_]*)\s*[(<\[]`)
+
+// Detect finds the renames and signature changes of edits. An identifier is
+// renamed when an edit replaces it, in the same place, with one that
+// wasn't there before.
+func Detect(edits []Edit) Change {
+	var change Change
+	for _, edit := range edits {
+		if !slices.Contains(change.Files, edit.Path) {
+			change.Files = append(change.Files, edit.Path)
+		}
+		for _, pair := range renamedIdentifiers(edit.Old, edit.New) {
+			i := slices.IndexFunc(change.Renames, func(r Rename) bool { return r.Old == pair[0] && r.New == pair[1] })
+			if i < 0 {
+				change.Renames = append(change.Renames, Rename{Old: pair[0], New: pair[1]})
+				i = len(change.Renames) - 1
+			}
+			if !slices.Contains(change.Renames[i].Files, edit.Path) {
+				change.Renames[i].Files = append(change.Renames[i].Files, edit.Path)
+			}
+		}
+		for _, name := range changedSignatures(edit.Old, edit.New) {
+			if !slices.Contains(change.Signatures, name) && !slices.ContainsFunc(change.Renames, func(r Rename) bool { return r.Old == name }) {
+				change.Signatures = append(change.Signatures, name)
+			}
+		}
+	}
+	return change
+}
+
+// renamedIdentifiers pairs the identifiers old has and new lacks with the
+// ones new has and old lacks, at the same position of the two
+func renamedIdentifiers(oldText, newText string) [][2]string {
+	oldIDs := identifierPattern.FindAllString(oldText, -1)
+	newIDs := identifierPattern.FindAllString(newText, -1)
+	if len(oldIDs) != len(newIDs) {
+		return nil
+	}
+	var pairs [][2]string
+	for i := range oldIDs {
+		from, to := oldIDs[i], newIDs[i]
+		if from == to || len(from) < minRenameLength || slices.Contains(newIDs, from) || slices.Contains(oldIDs, to) {
+			continue
+		}
+		if !slices.Contains(pairs, [2]string{from, to}) {
+			pairs = append(pairs, [2]string{from, to})
+		}
+	}
+	return pairs
+}
+
+// changedSignatures returns the functions declared in both texts whose
+// declaration line differs
+func changedSignatures(oldText, newText string) []string {
+	declarations := func(text string) map[string]string {
+		found := make(map[string]string)
+		for line := range strings.SplitSeq(text, "\n") {
+			if match := declarationPattern.FindStringSubmatch(line); match != nil {
+				found[match[1]] = strings.TrimSpace(line)
+			}
+		}
+		return found
+	}
+	before, after := declarations(oldText), declarations(newText)
+	var names []string
+	for name, line := range before {
+		if changed, ok := after[name]; ok && changed != line {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// CompileCheck returns the command that compiles the project of a changed
+// file without running it, or nil for languages without one. The command
+// runs where the nearest manifest of the file's language is.
+func CompileCheck(root, file string) *Check {
+	rel, err := filepath.Rel(root, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = file
+	}
+	switch filepath.Ext(rel) {
+	case ".go":
+		if dir, ok := findManifest(root, rel, "go.mod"); ok {
+			return &Check{Command: []string{"go", "build", "./..."}, Dir: dir}
+		}
+	case ".rs":
+		if dir, ok := findManifest(root, rel, "Cargo.toml"); ok {
+			return &Check{Command: []string{"cargo", "check", "--quiet"}, Dir: dir}
+		}
+	case ".ts", ".tsx", ".mts", ".cts":
+		if dir, ok := findManifest(root, rel, "tsconfig.json"); ok {
+			return &Check{Command: []string{"npx", "tsc", "--noEmit", "-p", "."}, Dir: dir}
+		}
+	case ".py":
+		return &Check{Command: []string{"python", "-m", "py_compile", rel}, Dir: "."}
+	}
+	return nil
+}
+
+// findManifest finds the directory nearest to a file, up to the project
+// root, that has a manifest file
+func findManifest(root, rel, manifest string) (string, bool) {
+	dir := filepath.Dir(rel)
+	for {
+		if _, err := os.Stat(filepath.Join(root, dir, manifest)); err == nil {
+			return dir, true
+		}
+		if dir == "." || dir == string(filepath.Separator) {
+			return "", false
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// Leftover is a place that still refers to a renamed symbol
+type Leftover struct {
+	Rename Rename
+	Path   string
+	Line   int
+	Text   string
+}
+
+// Report is what a verification pass after a refactor found
+type Report struct {
+	Change      Change
+	Leftovers   []Leftover
+	Symbols     []string // Symbols the language server still knows by an old name, as name in path
+	Diagnostics []string // Errors the language server reported in the changed files
+	Check       *Check
+	CheckOutput string
+	CheckFailed bool
+}
+
+// Clean reports whether the pass found nothing left to fix
+func (r Report) Clean() bool {
+	return len(r.Leftovers) == 0 && len(r.Symbols) == 0 && len(r.Diagnostics) == 0 && !r.CheckFailed
+}
+
+// maxReportedLeftovers bounds the leftovers listed in a prompt
+const maxReportedLeftovers = 30
+
+// Prompt asks the agent to finish a refactor the pass found unfinished
+func (r Report) Prompt() string {
+	var sb strings.Builder
+	sb.WriteString("A verification pass after your refactor found it unfinished. Fix what is listed below, then check again.\n")
+	if len(r.Leftovers) > 0 {
+		sb.WriteString("\nThese places still refer to renamed symbols:\n")
+		for i, leftover := range r.Leftovers {
+			if i == maxReportedLeftovers {
+				fmt.Fprintf(&sb, "- … and %d more\n", len(r.Leftovers)-i)
+				break
+			}
+			fmt.Fprintf(&sb, "- %s:%d (%s → %s): %s\n", leftover.Path, leftover.Line, leftover.Rename.Old, leftover.Rename.New, strings.TrimSpace(leftover.Text))
+		}
+	}
+	if len(r.Symbols) > 0 {
+		sb.WriteString("\nThe language server still finds symbols by their old names:\n")
+		for _, symbol := range r.Symbols {
+			sb.WriteString("- " + symbol + "\n")
+		}
+	}
+	if len(r.Diagnostics) > 0 {
+		sb.WriteString("\nThe language server reported these errors in the changed files:\n")
+		for _, diagnostic := range r.Diagnostics {
+			sb.WriteString("- " + diagnostic + "\n")
+		}
+	}
+	if r.CheckFailed {
+		fmt.Fprintf(&sb, "\n`%s` failed:\n\n```\n%s\n```\n", strings.Join(r.Check.Command, " "), r.CheckOutput)
+	}
+	return sb.String()
+}
+
+// Summary describes the pass in a line
+func (r Report) Summary() string {
+	if r.Clean() {
+		summary := fmt.Sprintf("%d renames and %d signature changes across %d files have no leftovers", len(r.Change.Renames), len(r.Change.Signatures), len(r.Change.Files))
+		if r.Check != nil {
+			summary += ", and " + strings.Join(r.Check.Command, " ") + " passes"
+		}
+		return summary
+	}
+	var found []string
+	if n := len(r.Leftovers) + len(r.Symbols); n > 0 {
+		found = append(found, fmt.Sprintf("%d leftover references", n))
+	}
+	if n := len(r.Diagnostics); n > 0 {
+		found = append(found, fmt.Sprintf("%d errors", n))
+	}
+	if r.CheckFailed {
+		found = append(found, strings.Join(r.Check.Command, " ")+" failing")
+	}
+	return "Found " + strings.Join(found, ", ")
+}
@@ -0,0 +1,67 @@
+package refactor
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	change := Detect([]Edit{
+		{Path: "a.go", Old: "func LoadConfig(path string) error {", New: "func ReadConfig(path string) error {"},
+		{Path: "b.go", Old: "err := LoadConfig(p)", New: "err := ReadConfig(p)"},
+		{Path: "b.go", Old: "x := 1", New: "y := 1"},
+		{Path: "c.go", Old: "func Save(path string) error {", New: "func Save(ctx context.Context, path string) error {"},
+	})
+	if !reflect.DeepEqual(change.Files, []string{"a.go", "b.go", "c.go"}) {
+		t.Errorf("files = %v", change.Files)
+	}
+	want := []Rename{{Old: "LoadConfig", New: "ReadConfig", Files: []string{"a.go", "b.go"}}}
+	if !reflect.DeepEqual(change.Renames, want) {
+		t.Errorf("renames = %+v, want %+v", change.Renames, want)
+	}
+	if !reflect.DeepEqual(change.Signatures, []string{"Save"}) {
+		t.Errorf("signatures = %v", change.Signatures)
+	}
+	if !change.CrossFile() {
+		t.Error("a rename across files is not cross-file")
+	}
+
+	if Detect([]Edit{{Path: "a.go", Old: "return nil", New: "return err"}}).CrossFile() {
+		t.Error("an edit of one file is cross-file")
+	}
+}
+
+func TestCompileCheck(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "tools/go.mod", "module tools\n")
+	writeFile(t, root, "tools/cmd/main.go", "package main\n")
+
+	check := CompileCheck(root, filepath.Join(root, "tools", "cmd", "main.go"))
+	if check == nil || strings.Join(check.Command, " ") != "go build ./..." || check.Dir != "tools" {
+		t.Errorf("check = %+v", check)
+	}
+	if check := CompileCheck(root, filepath.Join(root, "README.md")); check != nil {
+		t.Errorf("a markdown file has check %+v", check)
+	}
+}
+
+func TestReportPrompt(t *testing.T) {
+	rename := Rename{Old: "LoadConfig", New: "ReadConfig"}
+	report := Report{
+		Leftovers:   []Leftover{{Rename: rename, Path: "d.go", Line: 12, Text: "\tLoadConfig(p)"}},
+		Check:       &Check{Command: []string{"go", "build", "./..."}},
+		CheckOutput: "d.go:12: undefined: LoadConfig",
+		CheckFailed: true,
+	}
+	if report.Clean() {
+		t.Fatal("a report with leftovers is clean")
+	}
+	prompt := report.Prompt()
+	for _, want := range []string{"d.go:12 (LoadConfig → ReadConfig): LoadConfig(p)", "`go build ./...` failed", "undefined: LoadConfig"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt lacks %q:\n%s", want, prompt)
+		}
+	}
+}
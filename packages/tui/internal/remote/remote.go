@@ -0,0 +1,312 @@
+// Package remote attaches the TUI to a worktree on another machine over SSH.
+// The server runs on the remote machine with its port forwarded to a local
+// one, so tools operate on the remote files, while the TUI reads worktree
+// files and runs git through the same multiplexed SSH connection.
+package remote
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Target is a directory on a machine reachable over SSH
+type Target struct {
+	User string
+	Host string
+	Port int    // Zero for the port from the SSH config
+	Dir  string // Empty for the remote home directory
+}
+
+// ParseTarget parses ssh://[user@]host[:port][/path] or the scp-like
+// [user@]host:path. Hosts may be aliases from the SSH config.
+func ParseTarget(s string) (Target, error) {
+	var t Target
+	s = strings.TrimSpace(s)
+	if rest, ok := strings.CutPrefix(s, "ssh://"); ok {
+		hostport, dir, _ := strings.Cut(rest, "/")
+		if dir != "" {
+			t.Dir = "/" + dir
+		}
+		if i := strings.LastIndex(hostport, ":"); i >= 0 {
+			port, err := strconv.Atoi(hostport[i+1:])
+			if err != nil || port <= 0 || port > 65535 {
+				return Target{}, fmt.Errorf("invalid port in %q", s)
+			}
+			t.Port = port
+			hostport = hostport[:i]
+		}
+		s = hostport
+	} else if host, dir, ok := strings.Cut(s, ":"); ok {
+		s, t.Dir = host, dir
+	}
+	if user, host, ok := strings.Cut(s, "@"); ok {
+		t.User, s = user, host
+	}
+	t.Host = s
+	if t.Host == "" || strings.ContainsAny(t.Host, " /") {
+		return Target{}, fmt.Errorf("invalid remote %q, expected [user@]host:path or ssh://[user@]host[:port]/path", s)
+	}
+	return t, nil
+}
+
+// Destination is the host argument passed to ssh
+func (t Target) Destination() string {
+	if t.User != "" {
+		return t.User + "@" + t.Host
+	}
+	return t.Host
+}
+
+func (t Target) String() string {
+	return t.Destination() + ":" + t.Dir
+}
+
+// Conn is a multiplexed SSH connection to a target. Commands reuse the
+// master connection, so they don't authenticate again.
+type Conn struct {
+	Target
+	controlDir string
+}
+
+// Dial opens the master connection to a target and resolves its directory.
+// ssh prompts for passwords and host keys on the terminal as usual.
+func Dial(ctx context.Context, target Target) (*Conn, error) {
+	// Control socket paths are limited to about 100 bytes, so they can't
+	// live in a deep temporary directory
+	dir, err := os.MkdirTemp("/tmp", "rycode-ssh-")
+	if err != nil {
+		dir, err = os.MkdirTemp("", "rycode-ssh-")
+		if err != nil {
+			return nil, err
+		}
+	}
+	c := &Conn{Target: target, controlDir: dir}
+
+	master := exec.CommandContext(ctx, "ssh", c.args("-o", "ControlMaster=yes", "-o", "ControlPersist=yes", "-f", "-N")...)
+	master.Stdin, master.Stdout, master.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := master.Run(); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("ssh %s: %w", target.Destination(), err)
+	}
+
+	resolved, err := c.output(ctx, "cd "+c.dirArg()+" && pwd")
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("%s: %w", target, err)
+	}
+	c.Dir = strings.TrimSpace(resolved)
+	return c, nil
+}
+
+// args are the ssh arguments for a command on the master connection
+func (c *Conn) args(extra ...string) []string {
+	args := []string{"-o", "ControlPath=" + filepath.Join(c.controlDir, "control"), "-o", "ServerAliveInterval=30"}
+	if c.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(c.Port))
+	}
+	args = append(args, extra...)
+	return append(args, c.Destination())
+}
+
+// dirArg is the target directory quoted for the remote shell, with ~ left
+// for the shell to expand
+func (c *Conn) dirArg() string {
+	switch {
+	case c.Dir == "" || c.Dir == "~":
+		return "~"
+	case strings.HasPrefix(c.Dir, "~/"):
+		return "~/" + Quote(c.Dir[2:])
+	}
+	return Quote(c.Dir)
+}
+
+func (c *Conn) output(ctx context.Context, script string) (string, error) {
+	cmd := exec.CommandContext(ctx, "ssh", append(c.args("-T"), "--", script)...)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return string(output), nil
+}
+
+// Command prepares a command that runs in dir on the remote machine, or in
+// the target directory when dir is empty
+func (c *Conn) Command(ctx context.Context, dir, name string, args ...string) *exec.Cmd {
+	cd := c.dirArg()
+	if dir != "" {
+		cd = Quote(dir)
+	}
+	script := "cd " + cd + " && exec " + Quote(name)
+	for _, arg := range args {
+		script += " " + Quote(arg)
+	}
+	return exec.CommandContext(ctx, "ssh", append(c.args("-T"), "--", script)...)
+}
+
+// ReadFile reads a remote file
+func (c *Conn) ReadFile(path string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	output, err := c.output(ctx, "cat -- "+Quote(path))
+	if err != nil {
+		if strings.Contains(err.Error(), "No such file") {
+			err = fs.ErrNotExist
+		}
+		return nil, &fs.PathError{Op: "read", Path: path, Err: err}
+	}
+	return []byte(output), nil
+}
+
+// WriteFile writes a remote file, creating it with the remote umask
+func (c *Conn) WriteFile(path string, data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "ssh", append(c.args("-T"), "--", "cat > "+Quote(path))...)
+	cmd.Stdin = bytes.NewReader(data)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if len(output) > 0 {
+			err = fmt.Errorf("%s", strings.TrimSpace(string(output)))
+		}
+		return &fs.PathError{Op: "write", Path: path, Err: err}
+	}
+	return nil
+}
+
+// Remove deletes a remote file
+func (c *Conn) Remove(path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := c.output(ctx, "rm -- "+Quote(path)); err != nil {
+		if strings.Contains(err.Error(), "No such file") {
+			err = fs.ErrNotExist
+		}
+		return &fs.PathError{Op: "remove", Path: path, Err: err}
+	}
+	return nil
+}
+
+// MkdirAll creates a remote directory with its parents
+func (c *Conn) MkdirAll(path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := c.output(ctx, "mkdir -p -- "+Quote(path)); err != nil {
+		return &fs.PathError{Op: "mkdir", Path: path, Err: err}
+	}
+	return nil
+}
+
+// ReadDir lists a remote directory, sorted by name, following symlinks
+func (c *Conn) ReadDir(path string) ([]fs.DirEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	// A line per entry of size, mtime, hex mode and name, as Stat reads them
+	script := "cd -- " + Quote(path) + ` && for f in * .[!.]*; do [ -e "$f" ] || continue; ` +
+		`stat -L -c '%s %Y %f %n' -- "$f" 2>/dev/null || stat -L -f '%z %m %Xp %N' -- "$f"; done`
+	output, err := c.output(ctx, script)
+	if err != nil {
+		if strings.Contains(err.Error(), "No such file") {
+			err = fs.ErrNotExist
+		}
+		return nil, &fs.PathError{Op: "readdir", Path: path, Err: err}
+	}
+	return parseDirEntries(output), nil
+}
+
+// parseDirEntries reads the lines ReadDir lists
+func parseDirEntries(output string) []fs.DirEntry {
+	var entries []fs.DirEntry
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.SplitN(line, " ", 4)
+		if len(fields) < 4 {
+			continue
+		}
+		size, _ := strconv.ParseInt(fields[0], 10, 64)
+		mtime, _ := strconv.ParseInt(fields[1], 10, 64)
+		mode, _ := strconv.ParseUint(fields[2], 16, 32)
+		info := fileInfo{name: fields[3], size: size, modTime: time.Unix(mtime, 0), mode: unixMode(uint32(mode))}
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return entries
+}
+
+// Stat describes a remote file, following symlinks
+func (c *Conn) Stat(path string) (fs.FileInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// GNU stat first, then BSD stat, both printing size, mtime and hex mode
+	p := Quote(path)
+	output, err := c.output(ctx, "stat -L -c '%s %Y %f' -- "+p+" 2>/dev/null || stat -L -f '%z %m %Xp' -- "+p)
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: path, Err: fs.ErrNotExist}
+	}
+	var size, mtime int64
+	var mode uint32
+	if _, err := fmt.Sscanf(strings.TrimSpace(output), "%d %d %x", &size, &mtime, &mode); err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: path, Err: err}
+	}
+	return fileInfo{name: filepath.Base(path), size: size, modTime: time.Unix(mtime, 0), mode: unixMode(mode)}, nil
+}
+
+// Close ends the master connection
+func (c *Conn) Close() error {
+	err := exec.Command("ssh", c.args("-O", "exit")...).Run()
+	os.RemoveAll(c.controlDir)
+	return err
+}
+
+// Quote quotes s for a POSIX shell
+func Quote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:@,+", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+type fileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	mode    fs.FileMode
+}
+
+func (f fileInfo) Name() string       { return f.name }
+func (f fileInfo) Size() int64        { return f.size }
+func (f fileInfo) Mode() fs.FileMode  { return f.mode }
+func (f fileInfo) ModTime() time.Time { return f.modTime }
+func (f fileInfo) IsDir() bool        { return f.mode.IsDir() }
+func (f fileInfo) Sys() any           { return nil }
+
+// unixMode converts a st_mode to a FileMode
+func unixMode(mode uint32) fs.FileMode {
+	m := fs.FileMode(mode & 0o777)
+	switch mode & 0o170000 {
+	case 0o040000:
+		m |= fs.ModeDir
+	case 0o120000:
+		m |= fs.ModeSymlink
+	case 0o010000:
+		m |= fs.ModeNamedPipe
+	case 0o140000:
+		m |= fs.ModeSocket
+	case 0o020000:
+		m |= fs.ModeDevice | fs.ModeCharDevice
+	case 0o060000:
+		m |= fs.ModeDevice
+	}
+	return m
+}
@@ -0,0 +1,76 @@
+package remote
+
+import (
+	"context"
+	"io/fs"
+	"slices"
+	"testing"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := map[string]Target{
+		"devbox":                       {Host: "devbox"},
+		"devbox:~/src/app":             {Host: "devbox", Dir: "~/src/app"},
+		"me@10.0.0.5:/srv/app":         {User: "me", Host: "10.0.0.5", Dir: "/srv/app"},
+		"ssh://me@devbox:2222/srv/app": {User: "me", Host: "devbox", Port: 2222, Dir: "/srv/app"},
+		"ssh://devbox":                 {Host: "devbox"},
+	}
+	for spec, want := range tests {
+		got, err := ParseTarget(spec)
+		if err != nil || got != want {
+			t.Errorf("ParseTarget(%q) = %+v, %v; want %+v", spec, got, err, want)
+		}
+	}
+	for _, spec := range []string{"", ":/srv", "ssh://devbox:http/app", "me@:/srv"} {
+		if _, err := ParseTarget(spec); err == nil {
+			t.Errorf("ParseTarget(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestCommand(t *testing.T) {
+	c := &Conn{Target: Target{User: "me", Host: "devbox", Port: 2222, Dir: "/srv/my app"}, controlDir: "/tmp/rycode-ssh-x"}
+	cmd := c.Command(context.Background(), "", "git", "log", "--format=%s", "it's")
+	want := []string{
+		"ssh", "-o", "ControlPath=/tmp/rycode-ssh-x/control", "-o", "ServerAliveInterval=30", "-p", "2222", "-T", "me@devbox",
+		"--", `cd '/srv/my app' && exec git log '--format=%s' 'it'\''s'`,
+	}
+	if !slices.Equal(cmd.Args, want) {
+		t.Errorf("Args = %q\nwant   %q", cmd.Args, want)
+	}
+
+	c.Dir = "~/src"
+	if got := c.Command(context.Background(), "", "pwd").Args; got[len(got)-1] != "cd ~/src && exec pwd" {
+		t.Errorf("home directory script = %q", got[len(got)-1])
+	}
+}
+
+func TestUnixMode(t *testing.T) {
+	if mode := unixMode(0o40755); !mode.IsDir() || mode.Perm() != 0o755 {
+		t.Errorf("directory mode = %s", mode)
+	}
+	if mode := unixMode(0o100644); !mode.IsRegular() || mode.Perm() != 0o644 {
+		t.Errorf("file mode = %s", mode)
+	}
+	if mode := unixMode(0o120777); mode&fs.ModeSymlink == 0 {
+		t.Errorf("symlink mode = %s", mode)
+	}
+}
+
+func TestParseDirEntries(t *testing.T) {
+	output := "12 1700000000 81a4 notes.md\n0 1700000000 41ed scripts\n7 1700000000 81a4 my file.txt\n"
+	entries := parseDirEntries(output)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if !slices.Equal(names, []string{"my file.txt", "notes.md", "scripts"}) {
+		t.Fatalf("names = %q", names)
+	}
+	if !entries[2].IsDir() || entries[1].IsDir() {
+		t.Errorf("scripts should be the only directory")
+	}
+	if info, err := entries[1].Info(); err != nil || info.Size() != 12 || info.Mode().Perm() != 0o644 {
+		t.Errorf("notes.md = %v, %v", info, err)
+	}
+}
@@ -0,0 +1,120 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultServerCommand starts the server on the remote machine. The
+// hostname and port options are appended to it.
+const DefaultServerCommand = "rycode serve"
+
+// serverStartTimeout bounds how long the remote server may take to answer
+const serverStartTimeout = time.Minute
+
+// Server is a server running in the remote worktree, reachable on a local
+// port forwarded over the connection
+type Server struct {
+	URL string
+	cmd *exec.Cmd
+}
+
+// StartServer runs the server command in the target directory, listening
+// on the remote loopback, and forwards a local port to it
+func (c *Conn) StartServer(ctx context.Context, command string) (*Server, error) {
+	port, err := freePort()
+	if err != nil {
+		return nil, err
+	}
+	script := fmt.Sprintf("cd %s && exec %s --hostname 127.0.0.1 --port %d", c.dirArg(), command, port)
+	forward := fmt.Sprintf("127.0.0.1:%d:127.0.0.1:%d", port, port)
+	// A terminal ties the server to the session, so it is hung up when
+	// the session ends
+	cmd := exec.Command("ssh", append(c.args("-tt", "-o", "ExitOnForwardFailure=yes", "-L", forward), "--", script)...)
+	output := &tail{}
+	cmd.Stdout, cmd.Stderr = output, output
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	s := &Server{URL: "http://127.0.0.1:" + strconv.Itoa(port), cmd: cmd}
+	ctx, cancel := context.WithTimeout(ctx, serverStartTimeout)
+	defer cancel()
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-exited:
+			return nil, fmt.Errorf("remote server exited: %v: %s", err, strings.TrimSpace(output.String()))
+		case <-ctx.Done():
+			s.Stop()
+			return nil, fmt.Errorf("remote server didn't answer within %s", serverStartTimeout)
+		case <-ticker.C:
+			if s.ready(ctx) {
+				return s, nil
+			}
+		}
+	}
+}
+
+func (s *Server) ready(ctx context.Context) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+"/path", nil)
+	if err != nil {
+		return false
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// Stop ends the remote server along with the port forward
+func (s *Server) Stop() error {
+	if s.cmd.Process == nil {
+		return nil
+	}
+	// ssh closes the session, which hangs up the remote server
+	if err := s.cmd.Process.Signal(os.Interrupt); err != nil {
+		return s.cmd.Process.Kill()
+	}
+	return nil
+}
+
+// freePort picks a local port that is free, which the remote server
+// listens on too
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// tail keeps the end of the server's output to explain why it exited
+type tail struct {
+	b []byte
+}
+
+func (t *tail) Write(p []byte) (int, error) {
+	t.b = append(t.b, p...)
+	if len(t.b) > 4096 {
+		t.b = t.b[len(t.b)-4096:]
+	}
+	return len(p), nil
+}
+
+func (t *tail) String() string {
+	return string(t.b)
+}
@@ -0,0 +1,82 @@
+package remote
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"os/exec"
+)
+
+// active is the connection to the remote worktree, nil when the worktree
+// is local
+var active *Conn
+
+// Attach makes the worktree functions below operate on a remote worktree
+func Attach(c *Conn) {
+	active = c
+}
+
+// Active returns the connection to the remote worktree, or nil when the
+// worktree is local
+func Active() *Conn {
+	return active
+}
+
+// ReadFile reads a file of the worktree
+func ReadFile(path string) ([]byte, error) {
+	if active == nil {
+		return os.ReadFile(path)
+	}
+	return active.ReadFile(path)
+}
+
+// WriteFile writes a file of the worktree
+func WriteFile(path string, data []byte, perm fs.FileMode) error {
+	if active == nil {
+		return os.WriteFile(path, data, perm)
+	}
+	return active.WriteFile(path, data)
+}
+
+// Remove deletes a file of the worktree
+func Remove(path string) error {
+	if active == nil {
+		return os.Remove(path)
+	}
+	return active.Remove(path)
+}
+
+// MkdirAll creates a directory of the worktree with its parents
+func MkdirAll(path string, perm fs.FileMode) error {
+	if active == nil {
+		return os.MkdirAll(path, perm)
+	}
+	return active.MkdirAll(path)
+}
+
+// ReadDir lists a directory of the worktree, sorted by name
+func ReadDir(path string) ([]fs.DirEntry, error) {
+	if active == nil {
+		return os.ReadDir(path)
+	}
+	return active.ReadDir(path)
+}
+
+// Stat describes a file of the worktree
+func Stat(path string) (fs.FileInfo, error) {
+	if active == nil {
+		return os.Stat(path)
+	}
+	return active.Stat(path)
+}
+
+// Command prepares a command, such as git, that runs in a directory of the
+// worktree
+func Command(ctx context.Context, dir, name string, args ...string) *exec.Cmd {
+	if active == nil {
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Dir = dir
+		return cmd
+	}
+	return active.Command(ctx, dir, name, args...)
+}
@@ -0,0 +1,78 @@
+package repoqa
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultContextChunks is the number of retrieved chunks sent with a question
+const DefaultContextChunks = 12
+
+// repoMapBytes bounds the size of the repository outline sent with a question
+const repoMapBytes = 8000
+
+// SystemPrompt instructs the model to answer from the retrieved context only
+// and to cite its sources
+const SystemPrompt = `You answer questions about a code repository. Do not modify any files.
+Base your answer on the provided excerpts; you may read further files to confirm details.
+Cite every claim inline as path:line (for example internal/app/app.go:42), using paths relative to the repository root.
+If the excerpts don't answer the question, say so.`
+
+// Citation is a file and line referenced by an answer
+type Citation struct {
+	Path string
+	Line int
+}
+
+func (c Citation) String() string {
+	return fmt.Sprintf("%s:%d", c.Path, c.Line)
+}
+
+// BuildPrompt assembles the question with the repository outline and the
+// retrieved excerpts, each labelled with its line range
+func BuildPrompt(question string, index *Index, chunks []Chunk) string {
+	var b strings.Builder
+	b.WriteString("Repository outline:\n")
+	b.WriteString(index.RepoMap(repoMapBytes))
+	b.WriteString("\nRelevant excerpts:\n")
+	for _, chunk := range chunks {
+		fmt.Fprintf(&b, "\n--- %s:%d-%d ---\n", chunk.Path, chunk.StartLine, chunk.EndLine)
+		lines := strings.Split(chunk.Text, "\n")
+		for i, line := range lines {
+			fmt.Fprintf(&b, "%d: %s\n", chunk.StartLine+i, line)
+		}
+	}
+	b.WriteString("\nQuestion: ")
+	b.WriteString(question)
+	return b.String()
+}
+
+// citationPattern matches path:line and path:line-line references
+var citationPattern = regexp.MustCompile(`([A-Za-z0-9_.\-/]+\.[A-Za-z0-9]+):(\d+)(?:-\d+)?`)
+
+// ParseCitations extracts the distinct citations of an answer that point to
+// existing files under root, in order of appearance
+func ParseCitations(answer, root string) []Citation {
+	var citations []Citation
+	seen := make(map[Citation]bool)
+	for _, match := range citationPattern.FindAllStringSubmatch(answer, -1) {
+		line, err := strconv.Atoi(match[2])
+		if err != nil || line <= 0 {
+			continue
+		}
+		citation := Citation{Path: strings.TrimPrefix(match[1], "./"), Line: line}
+		if seen[citation] {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(root, citation.Path)); err != nil || info.IsDir() {
+			continue
+		}
+		seen[citation] = true
+		citations = append(citations, citation)
+	}
+	return citations
+}
@@ -0,0 +1,294 @@
+// Package repoqa retrieves the parts of a repository relevant to a question so
+// the model can answer it with citations, without editing anything.
+package repoqa
+
+import (
+	"bytes"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+const (
+	chunkLines   = 40      // Lines per indexed chunk
+	chunkOverlap = 10      // Lines shared by consecutive chunks
+	maxFileSize  = 256_000 // Larger files are skipped
+	maxFiles     = 5000    // Files indexed at most
+	cacheTTL     = 5 * time.Minute
+)
+
+// BM25 tuning parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// skippedDirs are directories never indexed
+var skippedDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"vendor":       true,
+	"dist":         true,
+	"build":        true,
+	"target":       true,
+	"__pycache__":  true,
+	".rycode":      true,
+	".opencode":    true,
+}
+
+// Chunk is a window of lines from a file
+type Chunk struct {
+	Path      string // Relative to the repository root
+	StartLine int    // 1-based, inclusive
+	EndLine   int    // 1-based, inclusive
+	Text      string
+	terms     map[string]int
+	length    int
+}
+
+// Index is a lexical BM25 index over line chunks of a repository
+type Index struct {
+	Root    string
+	Files   []string
+	chunks  []Chunk
+	docFreq map[string]int
+	avgLen  float64
+	symbols map[string][]string
+	BuiltAt time.Time
+}
+
+// BuildIndex walks the repository at root and indexes its text files
+func BuildIndex(root string) (*Index, error) {
+	index := &Index{
+		Root:    root,
+		docFreq: make(map[string]int),
+		symbols: make(map[string][]string),
+		BuiltAt: time.Now(),
+	}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != root && (skippedDirs[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if len(index.Files) >= maxFiles {
+			return filepath.SkipAll
+		}
+		info, err := d.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Size() > maxFileSize {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil || bytes.IndexByte(content, 0) >= 0 {
+			// Unreadable or binary
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		index.addFile(filepath.ToSlash(rel), string(content))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0
+	for _, chunk := range index.chunks {
+		total += chunk.length
+	}
+	if len(index.chunks) > 0 {
+		index.avgLen = float64(total) / float64(len(index.chunks))
+	}
+	return index, nil
+}
+
+func (i *Index) addFile(path, content string) {
+	i.Files = append(i.Files, path)
+	lines := strings.Split(content, "\n")
+	i.symbols[path] = declarations(lines)
+
+	// The path itself is a strong signal, so it is indexed with every chunk
+	pathTerms := tokenize(path)
+	for start := 0; start < len(lines); start += chunkLines - chunkOverlap {
+		end := min(start+chunkLines, len(lines))
+		text := strings.Join(lines[start:end], "\n")
+		chunk := Chunk{
+			Path:      path,
+			StartLine: start + 1,
+			EndLine:   end,
+			Text:      text,
+			terms:     make(map[string]int),
+		}
+		for _, term := range append(tokenize(text), pathTerms...) {
+			chunk.terms[term]++
+			chunk.length++
+		}
+		for term := range chunk.terms {
+			i.docFreq[term]++
+		}
+		i.chunks = append(i.chunks, chunk)
+		if end == len(lines) {
+			break
+		}
+	}
+}
+
+// Search returns the limit chunks most relevant to the query, best first
+func (i *Index) Search(query string, limit int) []Chunk {
+	terms := tokenize(query)
+	if len(terms) == 0 || len(i.chunks) == 0 {
+		return nil
+	}
+
+	type scored struct {
+		chunk int
+		score float64
+	}
+	var results []scored
+	n := float64(len(i.chunks))
+	for c, chunk := range i.chunks {
+		score := 0.0
+		for _, term := range terms {
+			tf := float64(chunk.terms[term])
+			if tf == 0 {
+				continue
+			}
+			df := float64(i.docFreq[term])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*float64(chunk.length)/i.avgLen))
+		}
+		if score > 0 {
+			results = append(results, scored{chunk: c, score: score})
+		}
+	}
+	sort.SliceStable(results, func(a, b int) bool {
+		return results[a].score > results[b].score
+	})
+
+	chunks := make([]Chunk, 0, limit)
+	for _, result := range results {
+		if len(chunks) == limit {
+			break
+		}
+		chunks = append(chunks, i.chunks[result.chunk])
+	}
+	return chunks
+}
+
+// RepoMap renders an outline of the repository: each file with its top-level
+// declarations, truncated to maxBytes
+func (i *Index) RepoMap(maxBytes int) string {
+	var b strings.Builder
+	for _, path := range i.Files {
+		line := path
+		if symbols := i.symbols[path]; len(symbols) > 0 {
+			line += ": " + strings.Join(symbols, ", ")
+		}
+		if b.Len()+len(line)+1 > maxBytes {
+			b.WriteString("…\n")
+			break
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// declarationPattern matches common top-level declarations in Go, JS/TS,
+// Python and Rust
+var declarationPattern = regexp.MustCompile(
+	`^(?:func(?: \([^)]*\))?|type|class|def|fn|pub fn|interface|(?:export )?(?:default )?(?:async )?function|export (?:const|class|interface|type))\s+([A-Za-z_][A-Za-z0-9_]*)`,
+)
+
+// maxDeclarations bounds the declarations listed per file in the repo map
+const maxDeclarations = 12
+
+func declarations(lines []string) []string {
+	var names []string
+	for _, line := range lines {
+		if match := declarationPattern.FindStringSubmatch(line); match != nil {
+			names = append(names, match[1])
+			if len(names) == maxDeclarations {
+				break
+			}
+		}
+	}
+	return names
+}
+
+// tokenize splits text into lowercase terms, breaking identifiers on
+// camelCase and snake_case boundaries and keeping the whole identifier too
+func tokenize(text string) []string {
+	var terms []string
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	for _, word := range words {
+		parts := splitIdentifier(word)
+		if len(parts) > 1 {
+			terms = append(terms, strings.ToLower(word))
+		}
+		for _, part := range parts {
+			if len(part) >= 2 {
+				terms = append(terms, strings.ToLower(part))
+			}
+		}
+	}
+	return terms
+}
+
+func splitIdentifier(word string) []string {
+	var parts []string
+	for _, piece := range strings.Split(word, "_") {
+		start := 0
+		runes := []rune(piece)
+		for i := 1; i < len(runes); i++ {
+			if unicode.IsUpper(runes[i]) && (unicode.IsLower(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				parts = append(parts, string(runes[start:i]))
+				start = i
+			}
+		}
+		if start < len(runes) {
+			parts = append(parts, string(runes[start:]))
+		}
+	}
+	return parts
+}
+
+// Cache keeps the index of a repository for a few minutes so consecutive
+// questions don't rebuild it
+type Cache struct {
+	mu    sync.Mutex
+	index *Index
+}
+
+// Get returns the cached index of root, rebuilding it when stale
+func (c *Cache) Get(root string) (*Index, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.index != nil && c.index.Root == root && time.Since(c.index.BuiltAt) < cacheTTL {
+		return c.index, nil
+	}
+	index, err := BuildIndex(root)
+	if err != nil {
+		return nil, err
+	}
+	c.index = index
+	return index, nil
+}
@@ -0,0 +1,72 @@
+package repoqa
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for path, content := range files {
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestSearchRanksRelevantChunk(t *testing.T) {
+	root := writeRepo(t, map[string]string{
+		"auth/token.go":         "package auth\n\nfunc RefreshToken(session string) error {\n\treturn nil\n}\n",
+		"ui/theme.go":           "package ui\n\ntype Theme struct{}\n\nfunc LoadTheme() Theme { return Theme{} }\n",
+		"node_modules/x/tok.js": "function refreshToken() {}\n",
+		".git/config":           "[core]\n",
+	})
+
+	index, err := BuildIndex(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(index.Files) != 2 {
+		t.Fatalf("expected skipped directories to be ignored, got %v", index.Files)
+	}
+
+	results := index.Search("how is the session token refreshed?", 5)
+	if len(results) == 0 || results[0].Path != "auth/token.go" {
+		t.Fatalf("expected auth/token.go first, got %+v", results)
+	}
+	if results[0].StartLine != 1 {
+		t.Errorf("expected chunk to start at line 1, got %d", results[0].StartLine)
+	}
+
+	repoMap := index.RepoMap(1000)
+	if !strings.Contains(repoMap, "auth/token.go: RefreshToken") || !strings.Contains(repoMap, "ui/theme.go: Theme, LoadTheme") {
+		t.Errorf("unexpected repo map:\n%s", repoMap)
+	}
+}
+
+func TestTokenizeSplitsIdentifiers(t *testing.T) {
+	got := tokenize("parseHTTPRequest snake_case")
+	want := []string{"parsehttprequest", "parse", "http", "request", "snake_case", "snake", "case"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestParseCitations(t *testing.T) {
+	root := writeRepo(t, map[string]string{"auth/token.go": "package auth\n"})
+	answer := "Tokens refresh in auth/token.go:3 (see also ./auth/token.go:3-5 and missing.go:9, auth/token.go:0)."
+	got := ParseCitations(answer, root)
+	want := []Citation{{Path: "auth/token.go", Line: 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
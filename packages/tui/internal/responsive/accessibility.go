@@ -2,9 +2,13 @@ package responsive
 
 import (
 	"fmt"
+	"image/color"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/lipgloss/v2/compat"
+	"github.com/aaronmrosenthal/rycode/internal/accessibility"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
 	"github.com/aaronmrosenthal/rycode/internal/theme"
 )
 
@@ -58,12 +62,12 @@ func NewAccessibilityConfig() *AccessibilityConfig {
 // AccessibilityManager manages accessibility features
 type AccessibilityManager struct {
 	config     *AccessibilityConfig
-	theme      *theme.Theme
+	theme      theme.Theme
 	announcements []string
 }
 
 // NewAccessibilityManager creates an accessibility manager
-func NewAccessibilityManager(config *AccessibilityConfig, theme *theme.Theme) *AccessibilityManager {
+func NewAccessibilityManager(config *AccessibilityConfig, theme theme.Theme) *AccessibilityManager {
 	return &AccessibilityManager{
 		config:     config,
 		theme:      theme,
@@ -83,6 +87,10 @@ func (am *AccessibilityManager) Announce(message string) {
 	if len(am.announcements) > 10 {
 		am.announcements = am.announcements[1:]
 	}
+
+	if am.config.ScreenReaderMode {
+		accessibility.Speak(message)
+	}
 }
 
 // GetAnnouncements returns pending announcements
@@ -124,42 +132,47 @@ func (am *AccessibilityManager) GetFocusStyle(focused bool) lipgloss.Style {
 	// Standard focus
 	return style.
 		BorderStyle(lipgloss.ThickBorder()).
-		BorderForeground(am.theme.AccentPrimary)
+		BorderForeground(am.theme.Primary())
 }
 
 // AdaptThemeForAccessibility modifies theme for accessibility
-func (am *AccessibilityManager) AdaptThemeForAccessibility(baseTheme *theme.Theme) *theme.Theme {
-	adapted := *baseTheme // Copy
-
-	// High contrast mode
-	if am.config.HighContrast {
-		adapted.BackgroundPrimary = lipgloss.Color("0")   // Black
-		adapted.BackgroundSecondary = lipgloss.Color("8") // Dark gray
-		adapted.TextPrimary = lipgloss.Color("15")        // White
-		adapted.TextSecondary = lipgloss.Color("15")
-		adapted.Border = lipgloss.Color("15")
-		adapted.AccentPrimary = lipgloss.Color("11")   // Bright yellow
-		adapted.AccentSecondary = lipgloss.Color("14") // Bright cyan
-	}
+func (am *AccessibilityManager) AdaptThemeForAccessibility(baseTheme theme.Theme) theme.Theme {
+	return theme.Recolor(baseTheme, func(adapted *theme.BaseTheme) {
+		// High contrast mode
+		if am.config.HighContrast {
+			adapted.BackgroundColor = ansiColor("0")      // Black
+			adapted.BackgroundPanelColor = ansiColor("8") // Dark gray
+			adapted.TextColor = ansiColor("15")           // White
+			adapted.TextMutedColor = ansiColor("15")
+			adapted.BorderColor = ansiColor("15")
+			adapted.PrimaryColor = ansiColor("11")   // Bright yellow
+			adapted.SecondaryColor = ansiColor("14") // Bright cyan
+		}
 
-	// Color blind adaptations
-	switch am.config.ColorBlindMode {
-	case ColorBlindProtanopia, ColorBlindDeuteranopia:
-		// Red-green blindness: use blue/yellow instead
-		adapted.Success = lipgloss.Color("12")  // Bright blue
-		adapted.Error = lipgloss.Color("11")    // Bright yellow
-		adapted.Warning = lipgloss.Color("14")  // Bright cyan
-		adapted.Info = lipgloss.Color("13")     // Bright magenta
-
-	case ColorBlindTritanopia:
-		// Blue-yellow blindness: use red/cyan
-		adapted.Success = lipgloss.Color("10")  // Bright green
-		adapted.Error = lipgloss.Color("9")     // Bright red
-		adapted.Warning = lipgloss.Color("13")  // Bright magenta
-		adapted.Info = lipgloss.Color("14")     // Bright cyan
-	}
+		// Color blind adaptations
+		switch am.config.ColorBlindMode {
+		case ColorBlindProtanopia, ColorBlindDeuteranopia:
+			// Red-green blindness: use blue/yellow instead
+			adapted.SuccessColor = ansiColor("12") // Bright blue
+			adapted.ErrorColor = ansiColor("11")   // Bright yellow
+			adapted.WarningColor = ansiColor("14") // Bright cyan
+			adapted.InfoColor = ansiColor("13")    // Bright magenta
+
+		case ColorBlindTritanopia:
+			// Blue-yellow blindness: use red/cyan
+			adapted.SuccessColor = ansiColor("10") // Bright green
+			adapted.ErrorColor = ansiColor("9")    // Bright red
+			adapted.WarningColor = ansiColor("13") // Bright magenta
+			adapted.InfoColor = ansiColor("14")    // Bright cyan
+		}
+	})
+}
 
-	return &adapted
+// ansiColor is an ANSI color of the terminal's palette, on dark and light
+// backgrounds alike
+func ansiColor(number string) compat.AdaptiveColor {
+	c := lipgloss.Color(number)
+	return compat.AdaptiveColor{Dark: c, Light: c}
 }
 
 // ARIALabel generates ARIA-like labels for screen readers
@@ -172,7 +185,7 @@ type ARIALabel struct {
 }
 
 // RenderARIALabel renders an accessible label
-func RenderARIALabel(label ARIALabel, theme *theme.Theme) string {
+func RenderARIALabel(label ARIALabel, theme theme.Theme) string {
 	if label.Label == "" {
 		return ""
 	}
@@ -200,7 +213,7 @@ func RenderARIALabel(label ARIALabel, theme *theme.Theme) string {
 	text := strings.Join(parts, " ")
 
 	style := lipgloss.NewStyle().
-		Foreground(theme.TextPrimary)
+		Foreground(theme.Text())
 
 	// Heading levels get bold
 	if label.Level > 0 {
@@ -237,14 +250,14 @@ func (sl *SkipLink) Hide() {
 }
 
 // Render renders the skip link
-func (sl *SkipLink) Render(theme *theme.Theme) string {
+func (sl *SkipLink) Render(theme theme.Theme) string {
 	if !sl.visible {
 		return ""
 	}
 
 	style := lipgloss.NewStyle().
-		Background(theme.AccentPrimary).
-		Foreground(theme.BackgroundPrimary).
+		Background(theme.Primary()).
+		Foreground(theme.Background()).
 		Padding(0, 2).
 		Bold(true)
 
@@ -271,15 +284,18 @@ func NewAccessibilityChecker() *AccessibilityChecker {
 	}
 }
 
-// CheckTouchTarget validates touch target size
+// CheckTouchTarget validates the size of a touch target of width by height
+// cells, in pixels of the terminal's cell size
 func (ac *AccessibilityChecker) CheckTouchTarget(id string, width, height int) {
-	if width < MinTouchTargetSize || height < MinTouchTargetSize {
+	cell := layout.Current.CellSize()
+	widthPixels, heightPixels := width*cell.Width, height*cell.Height
+	if widthPixels < MinTouchTargetSize || heightPixels < MinTouchTargetSize {
 		ac.issues = append(ac.issues, AccessibilityIssue{
 			Level:     "error",
 			Component: id,
 			Description: fmt.Sprintf(
-				"Touch target too small: %dx%d (minimum: %dx%d)",
-				width, height,
+				"Touch target too small: %dx%dpx (minimum: %dx%dpx)",
+				widthPixels, heightPixels,
 				MinTouchTargetSize, MinTouchTargetSize,
 			),
 			Fix: "Increase target size to at least 48x48 pixels",
@@ -287,26 +303,24 @@ func (ac *AccessibilityChecker) CheckTouchTarget(id string, width, height int) {
 	}
 }
 
-// CheckContrast validates color contrast
-func (ac *AccessibilityChecker) CheckContrast(foreground, background lipgloss.Color) {
-	// Simplified contrast check (real implementation would calculate actual contrast ratio)
-	// WCAG AA requires 4.5:1 for normal text, 3:1 for large text
-
-	// This is a placeholder - proper implementation would:
-	// 1. Convert colors to RGB
-	// 2. Calculate relative luminance
-	// 3. Calculate contrast ratio
-	// 4. Compare to WCAG standards
-
-	// For now, just check if they're the same
-	if foreground == background {
-		ac.issues = append(ac.issues, AccessibilityIssue{
-			Level:       "error",
-			Component:   "color",
-			Description: "Foreground and background colors are identical",
-			Fix:         "Use contrasting colors (WCAG AA: 4.5:1 minimum)",
-		})
+// CheckContrast validates color contrast against WCAG AA (4.5:1 for normal
+// text), for truecolor terminals and for the 256-color fallback
+func (ac *AccessibilityChecker) CheckContrast(foreground, background color.Color) {
+	ratio := theme.ContrastRatio(foreground, background)
+	palette := ""
+	if ratio >= theme.ContrastAA {
+		ratio = theme.ContrastRatio256(foreground, background)
+		palette = " in 256 colors"
+	}
+	if ratio >= theme.ContrastAA {
+		return
 	}
+	ac.issues = append(ac.issues, AccessibilityIssue{
+		Level:       "error",
+		Component:   "color",
+		Description: fmt.Sprintf("Contrast is %.2f:1%s", ratio, palette),
+		Fix:         "Use contrasting colors (WCAG AA: 4.5:1 minimum)",
+	})
 }
 
 // CheckKeyboardAccess validates keyboard accessibility
@@ -339,17 +353,17 @@ func (ac *AccessibilityChecker) GetIssues() []AccessibilityIssue {
 }
 
 // Report generates accessibility report
-func (ac *AccessibilityChecker) Report(theme *theme.Theme) string {
+func (ac *AccessibilityChecker) Report(theme theme.Theme) string {
 	if len(ac.issues) == 0 {
 		successStyle := lipgloss.NewStyle().
-			Foreground(theme.Success).
+			Foreground(theme.Success()).
 			Bold(true)
 
 		return successStyle.Render("✅ No accessibility issues found!")
 	}
 
 	titleStyle := lipgloss.NewStyle().
-		Foreground(theme.Warning).
+		Foreground(theme.Warning()).
 		Bold(true).
 		MarginBottom(1)
 
@@ -364,24 +378,24 @@ func (ac *AccessibilityChecker) Report(theme *theme.Theme) string {
 
 		switch issue.Level {
 		case "error":
-			levelStyle = levelStyle.Foreground(theme.Error)
+			levelStyle = levelStyle.Foreground(theme.Error())
 		case "warning":
-			levelStyle = levelStyle.Foreground(theme.Warning)
+			levelStyle = levelStyle.Foreground(theme.Warning())
 		case "info":
-			levelStyle = levelStyle.Foreground(theme.Info)
+			levelStyle = levelStyle.Foreground(theme.Info())
 		}
 
 		level := levelStyle.Render(strings.ToUpper(issue.Level))
 		component := lipgloss.NewStyle().
-			Foreground(theme.AccentSecondary).
+			Foreground(theme.Secondary()).
 			Render(issue.Component)
 
 		desc := lipgloss.NewStyle().
-			Foreground(theme.TextPrimary).
+			Foreground(theme.Text()).
 			Render(issue.Description)
 
 		fix := lipgloss.NewStyle().
-			Foreground(theme.TextDim).
+			Foreground(theme.TextMuted()).
 			Render("→ " + issue.Fix)
 
 		issueText := fmt.Sprintf(
@@ -427,13 +441,13 @@ func (lr *LiveRegion) GetUpdate() (string, bool) {
 }
 
 // Render renders the live region
-func (lr *LiveRegion) Render(theme *theme.Theme) string {
+func (lr *LiveRegion) Render(theme theme.Theme) string {
 	if lr.content == "" {
 		return ""
 	}
 
 	style := lipgloss.NewStyle().
-		Foreground(theme.Info).
+		Foreground(theme.Info()).
 		Padding(1)
 
 	prefix := "[Live] "
@@ -445,9 +459,9 @@ func (lr *LiveRegion) Render(theme *theme.Theme) string {
 }
 
 // AccessibilitySettings renders accessibility settings UI
-func AccessibilitySettings(config *AccessibilityConfig, theme *theme.Theme, width int) string {
+func AccessibilitySettings(config *AccessibilityConfig, theme theme.Theme, width int) string {
 	titleStyle := lipgloss.NewStyle().
-		Foreground(theme.AccentPrimary).
+		Foreground(theme.Primary()).
 		Bold(true).
 		Width(width - 4).
 		Align(lipgloss.Center).
@@ -472,15 +486,15 @@ func AccessibilitySettings(config *AccessibilityConfig, theme *theme.Theme, widt
 	items := []string{}
 	for _, setting := range settings {
 		keyStyle := lipgloss.NewStyle().
-			Foreground(theme.TextDim).
+			Foreground(theme.TextMuted()).
 			Render(setting.key)
 
 		labelStyle := lipgloss.NewStyle().
-			Foreground(theme.TextPrimary).
+			Foreground(theme.Text()).
 			Render(setting.label)
 
 		valueStyle := lipgloss.NewStyle().
-			Foreground(theme.AccentSecondary).
+			Foreground(theme.Secondary()).
 			Bold(true).
 			Render(setting.value)
 
@@ -495,7 +509,7 @@ func AccessibilitySettings(config *AccessibilityConfig, theme *theme.Theme, widt
 	}
 
 	hintStyle := lipgloss.NewStyle().
-		Foreground(theme.TextDim).
+		Foreground(theme.TextMuted()).
 		Width(width - 4).
 		Align(lipgloss.Center).
 		MarginTop(1)
@@ -512,9 +526,9 @@ func AccessibilitySettings(config *AccessibilityConfig, theme *theme.Theme, widt
 	containerStyle := lipgloss.NewStyle().
 		Width(width - 2).
 		Padding(1, 2).
-		Background(theme.BackgroundSecondary).
+		Background(theme.BackgroundPanel()).
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(theme.Border)
+		BorderForeground(theme.Border())
 
 	return containerStyle.Render(content)
 }
@@ -236,11 +236,8 @@ func (vm *ViewportManager) getBreakpoint() Breakpoint {
 	width := vm.width
 	height := vm.height
 
-	// Determine orientation
-	orientation := OrientationPortrait
-	if width > height {
-		orientation = OrientationLandscape
-	}
+	// Determine orientation, by pixels rather than cells
+	orientation := orientation(width, height)
 
 	// Match breakpoint
 	switch {
@@ -5,8 +5,6 @@ import (
 	"math"
 	"strconv"
 	"strings"
-
-	"github.com/charmbracelet/lipgloss/v2"
 )
 
 // RGB represents an RGB color
@@ -60,9 +58,8 @@ func MeetsWCAG_AAA(ratio float64, isLargeText bool) bool {
 	return ratio >= 7.0
 }
 
-// ColorFromLipgloss converts lipgloss.Color to RGB
-func ColorFromLipgloss(c lipgloss.Color) (RGB, error) {
-	s := string(c)
+// ColorFromLipgloss converts a color as lipgloss.Color takes it to RGB
+func ColorFromLipgloss(s string) (RGB, error) {
 
 	// Handle different color formats
 	if strings.HasPrefix(s, "#") {
@@ -193,7 +190,7 @@ func NewContrastChecker() *ContrastChecker {
 }
 
 // Check checks contrast between two colors
-func (cc *ContrastChecker) Check(component string, fg, bg lipgloss.Color, isLargeText bool) error {
+func (cc *ContrastChecker) Check(component string, fg, bg string, isLargeText bool) error {
 	fgRGB, err := ColorFromLipgloss(fg)
 	if err != nil {
 		return err
@@ -349,13 +346,13 @@ func (c RGB) ToHex() string {
 	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
 }
 
-// ToLipgloss converts RGB to lipgloss.Color
-func (c RGB) ToLipgloss() lipgloss.Color {
-	return lipgloss.Color(c.ToHex())
+// ToLipgloss converts RGB to a color as lipgloss.Color takes it
+func (c RGB) ToLipgloss() string {
+	return c.ToHex()
 }
 
 // ContrastMatrix generates a contrast matrix for a color palette
-func ContrastMatrix(colors map[string]lipgloss.Color) map[string]map[string]float64 {
+func ContrastMatrix(colors map[string]string) map[string]map[string]float64 {
 	matrix := make(map[string]map[string]float64)
 
 	for name1, color1 := range colors {
@@ -381,7 +378,7 @@ func ContrastMatrix(colors map[string]lipgloss.Color) map[string]map[string]floa
 }
 
 // FindAccessiblePairs finds color pairs that meet WCAG standards
-func FindAccessiblePairs(colors map[string]lipgloss.Color, standard string) []ColorPair {
+func FindAccessiblePairs(colors map[string]string, standard string) []ColorPair {
 	pairs := []ColorPair{}
 
 	for name1, color1 := range colors {
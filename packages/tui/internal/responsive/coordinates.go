@@ -2,7 +2,6 @@ package responsive
 
 import (
 	"strings"
-	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/aaronmrosenthal/rycode/internal/theme"
@@ -113,10 +112,6 @@ func (lm *LayoutMeasurer) MeasureText(rendered string) *CoordinateMapper {
 	y := 0
 
 	for _, line := range lines {
-		// Strip ANSI codes for accurate width measurement
-		cleanLine := stripANSI(line)
-		width := visualWidth(cleanLine)
-
 		// Look for element markers in format: <!--id:element-name-->
 		// These would be embedded in the render output
 		lm.scanLineForMarkers(line, y)
@@ -470,7 +465,7 @@ func (pf *PositionedFocusable) HandleKey(key string) tea.Cmd {
 	return pf.inner.HandleKey(key)
 }
 
-func (pf *PositionedFocusable) Render(theme *theme.Theme) string {
+func (pf *PositionedFocusable) Render(theme theme.Theme) string {
 	content := pf.inner.Render(theme)
 
 	// Update position in mapper
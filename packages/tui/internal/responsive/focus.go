@@ -13,7 +13,7 @@ type FocusableElement interface {
 	Focus()
 	Blur()
 	HandleKey(key string) tea.Cmd
-	Render(theme *theme.Theme) string
+	Render(theme theme.Theme) string
 }
 
 // FocusZone represents a group of focusable elements
@@ -324,37 +324,37 @@ func (fm *FocusManager) getZoneOrder() []FocusZone {
 }
 
 // FocusRing renders a visual focus indicator
-func FocusRing(focused bool, keyboardMode bool, theme *theme.Theme) lipgloss.Style {
+func FocusRing(focused bool, keyboardMode bool, theme theme.Theme) lipgloss.Style {
 	style := lipgloss.NewStyle()
 
 	if focused && keyboardMode {
 		// Show prominent focus ring for keyboard navigation
 		style = style.
 			BorderStyle(lipgloss.ThickBorder()).
-			BorderForeground(theme.AccentPrimary)
+			BorderForeground(theme.Primary())
 	} else if focused {
 		// Subtle indicator for mouse/touch
 		style = style.
 			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(theme.AccentSecondary)
+			BorderForeground(theme.Secondary())
 	} else {
 		// No focus
 		style = style.
 			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(theme.Border)
+			BorderForeground(theme.Border())
 	}
 
 	return style
 }
 
 // FocusIndicator renders a simple focus indicator
-func FocusIndicator(focused bool, theme *theme.Theme) string {
+func FocusIndicator(focused bool, theme theme.Theme) string {
 	if !focused {
 		return "  "
 	}
 
 	return lipgloss.NewStyle().
-		Foreground(theme.AccentPrimary).
+		Foreground(theme.Primary()).
 		Bold(true).
 		Render("▶ ")
 }
@@ -428,9 +428,9 @@ func ZoneKeyboardHints(zone FocusZone) []KeyboardHint {
 }
 
 // RenderKeyboardHelp renders keyboard help overlay
-func RenderKeyboardHelp(theme *theme.Theme, width int) string {
+func RenderKeyboardHelp(theme theme.Theme, width int) string {
 	titleStyle := lipgloss.NewStyle().
-		Foreground(theme.AccentPrimary).
+		Foreground(theme.Primary()).
 		Bold(true).
 		Width(width - 4).
 		Align(lipgloss.Center).
@@ -443,7 +443,7 @@ func RenderKeyboardHelp(theme *theme.Theme, width int) string {
 
 	// Instructions
 	instructionStyle := lipgloss.NewStyle().
-		Foreground(theme.Info).
+		Foreground(theme.Info()).
 		Width(width - 4).
 		Align(lipgloss.Center).
 		MarginTop(1)
@@ -460,16 +460,16 @@ func RenderKeyboardHelp(theme *theme.Theme, width int) string {
 	containerStyle := lipgloss.NewStyle().
 		Width(width - 2).
 		Padding(1, 2).
-		Background(theme.BackgroundSecondary).
+		Background(theme.BackgroundPanel()).
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(theme.AccentPrimary)
+		BorderForeground(theme.Primary())
 
 	return containerStyle.Render(content)
 }
 
-func renderHintSection(title string, hints []KeyboardHint, theme *theme.Theme, width int) string {
+func renderHintSection(title string, hints []KeyboardHint, theme theme.Theme, width int) string {
 	titleStyle := lipgloss.NewStyle().
-		Foreground(theme.AccentSecondary).
+		Foreground(theme.Secondary()).
 		Bold(true).
 		MarginBottom(1)
 
@@ -478,13 +478,13 @@ func renderHintSection(title string, hints []KeyboardHint, theme *theme.Theme, w
 	hintLines := []string{}
 	for _, hint := range hints {
 		keyStyle := lipgloss.NewStyle().
-			Foreground(theme.AccentPrimary).
-			Background(theme.BackgroundPrimary).
+			Foreground(theme.Primary()).
+			Background(theme.Background()).
 			Padding(0, 1).
 			Bold(true)
 
 		descStyle := lipgloss.NewStyle().
-			Foreground(theme.TextPrimary).
+			Foreground(theme.Text()).
 			MarginLeft(2)
 
 		line := lipgloss.JoinHorizontal(
@@ -18,7 +18,7 @@ func (m *mockFocusable) IsFocused() bool                          { return m.foc
 func (m *mockFocusable) Focus()                                   { m.focused = true }
 func (m *mockFocusable) Blur()                                    { m.focused = false }
 func (m *mockFocusable) HandleKey(key string) tea.Cmd             { return nil }
-func (m *mockFocusable) Render(theme *theme.Theme) string         { return m.id }
+func (m *mockFocusable) Render(theme theme.Theme) string          { return m.id }
 
 func TestFocusManager_Navigation(t *testing.T) {
 	fm := NewFocusManager()
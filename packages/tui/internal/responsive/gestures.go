@@ -398,8 +398,42 @@ func GestureUpdate(msg tea.Msg, gr *GestureRecognizer, context GestureContext) (
 		}
 	}
 
-	// TODO: Handle actual mouse/touch events when available
-	// This would require extended terminal capabilities
+	// Touch screens report a finger as the left mouse button
+	var gesture *Gesture
+	switch msg := msg.(type) {
+	case tea.MouseClickMsg:
+		if msg.Button == tea.MouseLeft {
+			gr.StartTracking(msg.X, msg.Y)
+		}
+	case tea.MouseMotionMsg:
+		gr.UpdateTracking(msg.X, msg.Y)
+	case tea.MouseReleaseMsg:
+		gr.UpdateTracking(msg.X, msg.Y)
+		gesture = gr.EndTracking()
+	}
+	if gesture == nil {
+		return nil, nil
+	}
+	return &GestureMsg{
+		Gesture: *gesture,
+		Action:  MapGestureToAction(*gesture, context),
+	}, nil
+}
 
-	return nil, nil
+// HandleMouseEvent follows a finger through the SGR mouse events parsed by
+// ParseMouseEvent, and returns the gesture it made once it is lifted
+func (gr *GestureRecognizer) HandleMouseEvent(event *MouseEvent) *Gesture {
+	if event == nil || event.Button != 0 {
+		return nil
+	}
+	switch event.Action {
+	case MousePress:
+		gr.StartTracking(event.X, event.Y)
+	case MouseDrag:
+		gr.UpdateTracking(event.X, event.Y)
+	case MouseRelease:
+		gr.UpdateTracking(event.X, event.Y)
+		return gr.EndTracking()
+	}
+	return nil
 }
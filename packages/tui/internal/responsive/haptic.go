@@ -209,13 +209,13 @@ func (he *HapticEngine) createVisual(event HapticEvent) string {
 }
 
 // RenderVisualFeedback renders visual haptic feedback in terminal
-func RenderVisualFeedback(msg HapticMsg, theme *theme.Theme, x, y int) string {
+func RenderVisualFeedback(msg HapticMsg, theme theme.Theme, x, y int) string {
 	if msg.Visual == "" {
 		return ""
 	}
 
 	style := lipgloss.NewStyle().
-		Foreground(theme.AccentPrimary).
+		Foreground(theme.Primary()).
 		Bold(true)
 
 	// Position the visual indicator
@@ -231,14 +231,14 @@ func RenderVisualFeedback(msg HapticMsg, theme *theme.Theme, x, y int) string {
 }
 
 // renderIntensityBar renders a visual intensity indicator
-func renderIntensityBar(intensity int, theme *theme.Theme) string {
+func renderIntensityBar(intensity int, theme theme.Theme) string {
 	bar := ""
 	for i := 0; i < intensity; i++ {
 		bar += "▂"
 	}
 
 	style := lipgloss.NewStyle().
-		Foreground(theme.AccentPrimary)
+		Foreground(theme.Primary())
 
 	return style.Render(bar)
 }
@@ -249,11 +249,11 @@ type HapticOverlay struct {
 	message   string
 	startTime time.Time
 	duration  time.Duration
-	theme     *theme.Theme
+	theme     theme.Theme
 }
 
 // NewHapticOverlay creates a haptic feedback overlay
-func NewHapticOverlay(theme *theme.Theme) *HapticOverlay {
+func NewHapticOverlay(theme theme.Theme) *HapticOverlay {
 	return &HapticOverlay{
 		theme:    theme,
 		duration: 300 * time.Millisecond,
@@ -291,11 +291,11 @@ func (ho *HapticOverlay) Render(width, height int) string {
 
 	// Center overlay
 	style := lipgloss.NewStyle().
-		Foreground(ho.theme.AccentPrimary).
-		Background(ho.theme.BackgroundSecondary).
+		Foreground(ho.theme.Primary()).
+		Background(ho.theme.BackgroundPanel()).
 		Padding(1, 3).
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(ho.theme.AccentPrimary).
+		BorderForeground(ho.theme.Primary()).
 		Bold(true)
 
 	overlay := style.Render(ho.message)
@@ -20,12 +20,12 @@ type Message struct {
 
 // PhoneLayout renders mobile-optimized layout
 type PhoneLayout struct {
-	theme  *theme.Theme
+	theme  theme.Theme
 	config LayoutConfig
 }
 
 // NewPhoneLayout creates a phone-optimized layout
-func NewPhoneLayout(theme *theme.Theme, config LayoutConfig) *PhoneLayout {
+func NewPhoneLayout(theme theme.Theme, config LayoutConfig) *PhoneLayout {
 	return &PhoneLayout{
 		theme:  theme,
 		config: config,
@@ -44,16 +44,16 @@ func (pl *PhoneLayout) RenderMessage(msg Message, isActive bool) string {
 	if msg.Role == "user" {
 		bubbleStyle = bubbleStyle.
 			Align(lipgloss.Right).
-			Background(pl.theme.AccentPrimary).
-			Foreground(pl.theme.BackgroundPrimary).
+			Background(pl.theme.Primary()).
+			Foreground(pl.theme.Background()).
 			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(pl.theme.AccentPrimary)
+			BorderForeground(pl.theme.Primary())
 
 		content := bubbleStyle.Render(msg.Content)
 
 		// Add timestamp below (right-aligned)
 		timeStyle := lipgloss.NewStyle().
-			Foreground(pl.theme.TextDim).
+			Foreground(pl.theme.TextMuted()).
 			Align(lipgloss.Right).
 			Width(pl.config.Width - 4)
 
@@ -65,21 +65,21 @@ func (pl *PhoneLayout) RenderMessage(msg Message, isActive bool) string {
 	// AI messages: left-aligned, secondary background
 	bubbleStyle = bubbleStyle.
 		Align(lipgloss.Left).
-		Background(pl.theme.BackgroundSecondary).
-		Foreground(pl.theme.TextPrimary).
+		Background(pl.theme.BackgroundPanel()).
+		Foreground(pl.theme.Text()).
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(pl.theme.Border)
+		BorderForeground(pl.theme.Border())
 
 	if isActive {
 		bubbleStyle = bubbleStyle.
-			BorderForeground(pl.theme.AccentPrimary).
+			BorderForeground(pl.theme.Primary()).
 			BorderStyle(lipgloss.ThickBorder())
 	}
 
 	// AI indicator with icon
 	aiIcon := getAIIcon(msg.AI)
 	aiLabel := lipgloss.NewStyle().
-		Foreground(pl.theme.AccentSecondary).
+		Foreground(pl.theme.Secondary()).
 		Bold(true).
 		Render(aiIcon + " " + msg.AI)
 
@@ -103,7 +103,7 @@ func (pl *PhoneLayout) RenderMessage(msg Message, isActive bool) string {
 	// Reaction emoji (if present)
 	if msg.Reaction != "" {
 		reactionStyle := lipgloss.NewStyle().
-			Foreground(pl.theme.AccentPrimary).
+			Foreground(pl.theme.Primary()).
 			MarginLeft(2)
 
 		bubble = lipgloss.JoinHorizontal(
@@ -115,7 +115,7 @@ func (pl *PhoneLayout) RenderMessage(msg Message, isActive bool) string {
 
 	// Timestamp
 	timeStyle := lipgloss.NewStyle().
-		Foreground(pl.theme.TextDim).
+		Foreground(pl.theme.TextMuted()).
 		MarginLeft(2)
 
 	timestamp := timeStyle.Render(msg.Timestamp)
@@ -128,23 +128,23 @@ func (pl *PhoneLayout) RenderInput(value string, placeholder string) string {
 	inputStyle := lipgloss.NewStyle().
 		Width(pl.config.Width - 4).
 		Padding(1, 2).
-		Background(pl.theme.BackgroundSecondary).
-		Foreground(pl.theme.TextPrimary).
+		Background(pl.theme.BackgroundPanel()).
+		Foreground(pl.theme.Text()).
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(pl.theme.AccentPrimary)
+		BorderForeground(pl.theme.Primary())
 
 	// Show placeholder if empty
 	displayValue := value
 	if displayValue == "" {
 		displayValue = lipgloss.NewStyle().
-			Foreground(pl.theme.TextDim).
+			Foreground(pl.theme.TextMuted()).
 			Render(placeholder)
 	}
 
 	// Voice button (phone-specific)
 	voiceButton := lipgloss.NewStyle().
-		Foreground(pl.theme.AccentSecondary).
-		Background(pl.theme.BackgroundSecondary).
+		Foreground(pl.theme.Secondary()).
+		Background(pl.theme.BackgroundPanel()).
 		Padding(0, 1).
 		Bold(true).
 		Render("🎤")
@@ -162,13 +162,13 @@ func (pl *PhoneLayout) RenderInput(value string, placeholder string) string {
 func (pl *PhoneLayout) RenderQuickActions() string {
 	buttonStyle := lipgloss.NewStyle().
 		Padding(0, 2).
-		Background(pl.theme.BackgroundSecondary).
-		Foreground(pl.theme.TextPrimary).
+		Background(pl.theme.BackgroundPanel()).
+		Foreground(pl.theme.Text()).
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(pl.theme.Border)
+		BorderForeground(pl.theme.Border())
 
 	actions := []string{
-		buttonStyle.Copy().Background(pl.theme.AccentPrimary).Render("💬 Chat"),
+		buttonStyle.Copy().Background(pl.theme.Primary()).Render("💬 Chat"),
 		buttonStyle.Render("📜 History"),
 		buttonStyle.Render("⚙️ Settings"),
 		buttonStyle.Render("🤖 AI"),
@@ -182,12 +182,12 @@ func (pl *PhoneLayout) RenderQuickActions() string {
 
 // TabletLayout renders tablet-optimized layout
 type TabletLayout struct {
-	theme  *theme.Theme
+	theme  theme.Theme
 	config LayoutConfig
 }
 
 // NewTabletLayout creates a tablet-optimized layout
-func NewTabletLayout(theme *theme.Theme, config LayoutConfig) *TabletLayout {
+func NewTabletLayout(theme theme.Theme, config LayoutConfig) *TabletLayout {
 	return &TabletLayout{
 		theme:  theme,
 		config: config,
@@ -205,7 +205,7 @@ func (tl *TabletLayout) RenderSplitView(messages []Message, preview string) stri
 		Width(leftWidth).
 		Height(tl.config.Height).
 		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(tl.theme.Border).
+		BorderForeground(tl.theme.Border()).
 		BorderRight(true).
 		Padding(1)
 
@@ -233,11 +233,11 @@ func (tl *TabletLayout) RenderSplitView(messages []Message, preview string) stri
 func (tl *TabletLayout) renderCompactMessage(msg Message) string {
 	// More compact than phone, less verbose than desktop
 	roleStyle := lipgloss.NewStyle().
-		Foreground(tl.theme.AccentSecondary).
+		Foreground(tl.theme.Secondary()).
 		Bold(true)
 
 	contentStyle := lipgloss.NewStyle().
-		Foreground(tl.theme.TextPrimary).
+		Foreground(tl.theme.Text()).
 		Width(tl.config.Width*50/100 - 4)
 
 	role := roleStyle.Render(getAIIcon(msg.AI) + " ")
@@ -246,20 +246,20 @@ func (tl *TabletLayout) renderCompactMessage(msg Message) string {
 	return lipgloss.NewStyle().
 		MarginBottom(1).
 		Padding(1).
-		Background(tl.theme.BackgroundSecondary).
+		Background(tl.theme.BackgroundPanel()).
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(tl.theme.Border).
+		BorderForeground(tl.theme.Border()).
 		Render(lipgloss.JoinVertical(lipgloss.Left, role, content))
 }
 
 // DesktopLayout renders traditional desktop layout
 type DesktopLayout struct {
-	theme  *theme.Theme
+	theme  theme.Theme
 	config LayoutConfig
 }
 
 // NewDesktopLayout creates a desktop layout
-func NewDesktopLayout(theme *theme.Theme, config LayoutConfig) *DesktopLayout {
+func NewDesktopLayout(theme theme.Theme, config LayoutConfig) *DesktopLayout {
 	return &DesktopLayout{
 		theme:  theme,
 		config: config,
@@ -276,7 +276,7 @@ func (dl *DesktopLayout) RenderThreeColumn(sidebar string, messages string, cont
 		Width(sidebarWidth).
 		Height(dl.config.Height).
 		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(dl.theme.Border).
+		BorderForeground(dl.theme.Border()).
 		BorderRight(true).
 		Padding(1)
 
@@ -284,7 +284,7 @@ func (dl *DesktopLayout) RenderThreeColumn(sidebar string, messages string, cont
 		Width(messagesWidth).
 		Height(dl.config.Height).
 		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(dl.theme.Border).
+		BorderForeground(dl.theme.Border()).
 		BorderRight(true).
 		Padding(1)
 
@@ -316,14 +316,14 @@ func getAIIcon(ai string) string {
 }
 
 // ThumbZoneIndicator shows the thumb-reachable zone on phones
-func ThumbZoneIndicator(config LayoutConfig, theme *theme.Theme) string {
+func ThumbZoneIndicator(config LayoutConfig, theme theme.Theme) string {
 	if config.Device != DevicePhone {
 		return ""
 	}
 
 	// Show visual indicator of thumb-friendly zone
 	style := lipgloss.NewStyle().
-		Foreground(theme.Success).
+		Foreground(theme.Success()).
 		Faint(true)
 
 	if config.InputPosition == InputTop {
@@ -334,9 +334,9 @@ func ThumbZoneIndicator(config LayoutConfig, theme *theme.Theme) string {
 }
 
 // SwipeIndicator shows swipe gesture hints
-func SwipeIndicator(direction GestureType, theme *theme.Theme) string {
+func SwipeIndicator(direction GestureType, theme theme.Theme) string {
 	style := lipgloss.NewStyle().
-		Foreground(theme.AccentPrimary).
+		Foreground(theme.Primary()).
 		Bold(true)
 
 	switch direction {
@@ -354,18 +354,18 @@ func SwipeIndicator(direction GestureType, theme *theme.Theme) string {
 }
 
 // VoiceInputButton renders voice input button for phone
-func VoiceInputButton(active bool, theme *theme.Theme) string {
+func VoiceInputButton(active bool, theme theme.Theme) string {
 	style := lipgloss.NewStyle().
 		Padding(1, 3).
-		Background(theme.AccentPrimary).
-		Foreground(theme.BackgroundPrimary).
+		Background(theme.Primary()).
+		Foreground(theme.Background()).
 		BorderStyle(lipgloss.RoundedBorder()).
 		Bold(true)
 
 	if active {
 		// Pulsing effect when recording
-		style = style.
-			Background(theme.Error).
+		return style.
+			Background(theme.Error()).
 			Render("🎤 Recording...")
 	}
 
@@ -373,9 +373,9 @@ func VoiceInputButton(active bool, theme *theme.Theme) string {
 }
 
 // AIProviderPicker renders AI provider picker
-func AIProviderPicker(current string, theme *theme.Theme, width int) string {
+func AIProviderPicker(current string, theme theme.Theme, width int) string {
 	titleStyle := lipgloss.NewStyle().
-		Foreground(theme.AccentPrimary).
+		Foreground(theme.Primary()).
 		Bold(true).
 		Width(width).
 		Align(lipgloss.Center).
@@ -399,24 +399,24 @@ func AIProviderPicker(current string, theme *theme.Theme, width int) string {
 			Width(width - 4).
 			Padding(1, 2).
 			MarginBottom(1).
-			Background(theme.BackgroundSecondary).
+			Background(theme.BackgroundPanel()).
 			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(theme.Border)
+			BorderForeground(theme.Border())
 
 		if strings.ToLower(current) == p.name {
 			buttonStyle = buttonStyle.
-				Background(theme.AccentPrimary).
-				Foreground(theme.BackgroundPrimary).
-				BorderForeground(theme.AccentPrimary)
+				Background(theme.Primary()).
+				Foreground(theme.Background()).
+				BorderForeground(theme.Primary())
 		}
 
 		label := lipgloss.NewStyle().Bold(true).Render(p.icon + " " + p.name)
 		desc := lipgloss.NewStyle().
-			Foreground(theme.TextDim).
+			Foreground(theme.TextMuted()).
 			Render(p.desc)
 
 		numberStyle := lipgloss.NewStyle().
-			Foreground(theme.TextDim).
+			Foreground(theme.TextMuted()).
 			Render(string(rune('1' + i)))
 
 		item := lipgloss.JoinVertical(
@@ -429,7 +429,7 @@ func AIProviderPicker(current string, theme *theme.Theme, width int) string {
 	}
 
 	hint := lipgloss.NewStyle().
-		Foreground(theme.TextDim).
+		Foreground(theme.TextMuted()).
 		Width(width).
 		Align(lipgloss.Center).
 		MarginTop(1).
@@ -1,6 +1,7 @@
 package responsive
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -8,6 +9,8 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode/internal/accessibility"
+	"github.com/aaronmrosenthal/rycode/internal/voice"
 )
 
 // Platform provides platform-specific implementations
@@ -211,7 +214,7 @@ type MacOSPlatform struct {
 func NewMacOSPlatform() *MacOSPlatform {
 	return &MacOSPlatform{
 		haptic:        &AudioHaptic{},
-		voice:         &MacOSVoice{},
+		voice:         &WhisperVoice{},
 		storage:       NewFileStorage("macos"),
 		accessibility: &MacOSAccessibility{},
 	}
@@ -234,26 +237,51 @@ func (h *AudioHaptic) Trigger(event HapticEvent) error {
 
 func (h *AudioHaptic) IsAvailable() bool { return true }
 
-type MacOSVoice struct{}
+// WhisperVoice records the microphone with the system's audio tools and
+// transcribes it with a local whisper.cpp
+type WhisperVoice struct {
+	recording *voice.Recording
+}
 
-func (h *MacOSVoice) StartRecording() tea.Cmd {
-	// Could use macOS `say` command or NSSpeechRecognizer
+func (h *WhisperVoice) StartRecording() tea.Cmd {
+	recording, err := voice.Start("")
+	if err != nil {
+		return func() tea.Msg {
+			return VoiceErrorMsg{Error: err.Error()}
+		}
+	}
+	h.recording = recording
 	return func() tea.Msg {
 		return VoiceStartMsg{}
 	}
 }
 
-func (h *MacOSVoice) StopRecording() (string, float64, error) {
-	return "", 0, fmt.Errorf("voice not implemented for macOS")
+func (h *WhisperVoice) StopRecording() (string, float64, error) {
+	if h.recording == nil {
+		return "", 0, fmt.Errorf("not recording")
+	}
+	path, err := h.recording.Stop()
+	h.recording = nil
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(path)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	transcript, err := voice.Transcribe(ctx, path)
+	if err != nil {
+		return "", 0, err
+	}
+	return transcript.Text, transcript.Confidence, nil
 }
 
-func (h *MacOSVoice) IsAvailable() bool { return false }
+func (h *WhisperVoice) IsAvailable() bool { return voice.Available() == nil }
 
 type MacOSAccessibility struct{}
 
 func (a *MacOSAccessibility) AnnounceForScreenReader(message string) {
-	// Use macOS `say` command
-	// exec.Command("say", message).Run()
+	// Spoken with `say`
+	accessibility.Speak(message)
 }
 
 func (a *MacOSAccessibility) IsScreenReaderActive() bool {
@@ -286,7 +314,7 @@ type LinuxPlatform struct {
 func NewLinuxPlatform() *LinuxPlatform {
 	return &LinuxPlatform{
 		haptic:        &AudioHaptic{},
-		voice:         &GenericVoice{},
+		voice:         &WhisperVoice{},
 		storage:       NewFileStorage("linux"),
 		accessibility: &LinuxAccessibility{},
 	}
@@ -301,7 +329,8 @@ func (p *LinuxPlatform) Accessibility() AccessibilityProvider { return p.accessi
 type LinuxAccessibility struct{}
 
 func (a *LinuxAccessibility) AnnounceForScreenReader(message string) {
-	// Use espeak or speech-dispatcher
+	// Spoken with speech-dispatcher, or espeak without it
+	accessibility.Speak(message)
 }
 
 func (a *LinuxAccessibility) IsScreenReaderActive() bool {
@@ -326,7 +355,7 @@ type WindowsPlatform struct {
 func NewWindowsPlatform() *WindowsPlatform {
 	return &WindowsPlatform{
 		haptic:        &AudioHaptic{},
-		voice:         &GenericVoice{},
+		voice:         &WhisperVoice{},
 		storage:       NewFileStorage("windows"),
 		accessibility: &WindowsAccessibility{},
 	}
@@ -341,7 +370,8 @@ func (p *WindowsPlatform) Accessibility() AccessibilityProvider { return p.acces
 type WindowsAccessibility struct{}
 
 func (a *WindowsAccessibility) AnnounceForScreenReader(message string) {
-	// Use Windows SAPI
+	// Spoken with SAPI
+	accessibility.Speak(message)
 }
 
 func (a *WindowsAccessibility) IsScreenReaderActive() bool {
@@ -7,15 +7,18 @@ import (
 	"strings"
 
 	"golang.org/x/term"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
 )
 
 // TerminalCapabilities represents detected terminal features
 type TerminalCapabilities struct {
 	// Dimensions
-	Width        int
-	Height       int
-	WidthPixels  int // If available
-	HeightPixels int // If available
+	Width          int
+	Height         int
+	WidthPixels    int  // Reported by the terminal, or estimated
+	HeightPixels   int  // Reported by the terminal, or estimated
+	PixelsReported bool // The terminal reported its pixel or cell size
 
 	// Colors
 	SupportsTrueColor bool
@@ -28,10 +31,10 @@ type TerminalCapabilities struct {
 	SupportsPixelMouse    bool // Pixel-based mouse coordinates
 
 	// Advanced features
-	SupportsAltScreen   bool
-	SupportsBracketPaste bool
+	SupportsAltScreen     bool
+	SupportsBracketPaste  bool
 	SupportsKittyGraphics bool
-	SupportsSixel        bool
+	SupportsSixel         bool
 
 	// Terminal info
 	TerminalType    string
@@ -41,10 +44,10 @@ type TerminalCapabilities struct {
 	IsScreen        bool
 
 	// Platform detection
-	Platform    string // "ios", "android", "macos", "linux", "windows"
-	IsPhone     bool
-	IsTablet    bool
-	IsMobile    bool
+	Platform      string // "ios", "android", "macos", "linux", "windows"
+	IsPhone       bool
+	IsTablet      bool
+	IsMobile      bool
 	IsTouchDevice bool
 }
 
@@ -97,45 +100,59 @@ func (tc *TerminalCapabilities) detectDimensions() {
 	tc.detectPixelDimensions()
 }
 
-// detectPixelDimensions attempts to get pixel dimensions
+// detectPixelDimensions sets pixel dimensions from the cell size: the one
+// the terminal reported to layout.QueryPixels (CSI 16 t), or an estimate
+// until it has
 func (tc *TerminalCapabilities) detectPixelDimensions() {
-	// kitty graphics protocol - query terminal size in pixels
-	// CSI 14 t - Reports window size in pixels
-	// Response: CSI 4 ; height ; width t
-
-	// For now, estimate based on common font sizes
-	// Most terminals use ~7-9 pixels per char width, ~14-18 per char height
-	avgCharWidth := 8
-	avgCharHeight := 16
+	cell := layout.Current.CellSize()
+	tc.WidthPixels = tc.Width * cell.Width
+	tc.HeightPixels = tc.Height * cell.Height
+	tc.PixelsReported = false
+}
 
-	tc.WidthPixels = tc.Width * avgCharWidth
-	tc.HeightPixels = tc.Height * avgCharHeight
+// UpdateDimensions takes the terminal's size from the layout, as the TUI
+// sets it on each WindowSizeMsg and as the terminal answers its pixel size
+// queries (CSI 14 t and CSI 16 t)
+func (tc *TerminalCapabilities) UpdateDimensions(info *layout.LayoutInfo) {
+	tc.Width = info.Terminal.Width
+	tc.Height = info.Terminal.Height
+	pixels := info.PixelSize()
+	tc.WidthPixels = pixels.Width
+	tc.HeightPixels = pixels.Height
+	tc.PixelsReported = info.Pixels.Width > 0 || info.Cell.Width > 0
+}
 
-	// TODO: Actually query terminal for real pixel dimensions
-	// This requires sending escape sequences and reading responses
+// orientation is the orientation of columns by rows cells, by their size in
+// pixels: cells are about twice as tall as wide, so 100x50 is square
+func orientation(columns, rows int) Orientation {
+	cell := layout.Current.CellSize()
+	if columns*cell.Width > rows*cell.Height {
+		return OrientationLandscape
+	}
+	return OrientationPortrait
 }
 
 // detectColorSupport detects color capabilities
 func (tc *TerminalCapabilities) detectColorSupport() {
-	colorterm := os.Getenv("COLORTERM")
-
-	// True color detection
-	tc.SupportsTrueColor = colorterm == "truecolor" || colorterm == "24bit" ||
-		strings.Contains(tc.TerminalType, "256color") ||
-		tc.TerminalProgram == "iTerm.app" ||
-		tc.TerminalProgram == "WezTerm" ||
-		tc.TerminalProgram == "Alacritty" ||
-		tc.TerminalProgram == "kitty"
-
-	// 256 color support
-	tc.Supports256Color = tc.SupportsTrueColor ||
-		strings.Contains(tc.TerminalType, "256color") ||
-		colorterm == "256"
+	depth := theme.DetectColorDepth(os.Getenv)
+	tc.SupportsTrueColor = depth == theme.DepthTrueColor
+	tc.Supports256Color = depth != theme.Depth16
 
 	// 16 color support (almost all terminals)
 	tc.Supports16Color = tc.TerminalType != "" && tc.TerminalType != "dumb"
 }
 
+// ColorDepth returns the depth the themes are drawn in on this terminal
+func (tc *TerminalCapabilities) ColorDepth() theme.ColorDepth {
+	switch {
+	case tc.SupportsTrueColor:
+		return theme.DepthTrueColor
+	case tc.Supports256Color:
+		return theme.Depth256
+	}
+	return theme.Depth16
+}
+
 // detectMouseSupport detects mouse capabilities
 func (tc *TerminalCapabilities) detectMouseSupport() {
 	// Most modern terminals support mouse tracking
@@ -160,7 +177,6 @@ func (tc *TerminalCapabilities) detectMouseSupport() {
 func (tc *TerminalCapabilities) detectPlatform() {
 	// Check environment variables for iOS/Android terminal apps
 	termProgram := strings.ToLower(tc.TerminalProgram)
-	termType := strings.ToLower(tc.TerminalType)
 
 	// iOS terminal apps
 	if strings.Contains(termProgram, "blink") ||
@@ -222,7 +238,7 @@ func (tc *TerminalCapabilities) detectAdvancedFeatures() {
 	// Sixel graphics
 	tc.SupportsSixel = tc.TerminalProgram == "WezTerm" ||
 		tc.TerminalProgram == "mlterm" ||
-		strings.Contains(termType, "sixel")
+		strings.Contains(strings.ToLower(tc.TerminalType), "sixel")
 }
 
 // detectTerminalProgram detects which terminal program is running
@@ -302,10 +318,13 @@ func (tc *TerminalCapabilities) GetDeviceType() DeviceType {
 
 // GetOrientation estimates orientation based on dimensions
 func (tc *TerminalCapabilities) GetOrientation() Orientation {
-	if tc.Width > tc.Height {
-		return OrientationLandscape
+	if tc.WidthPixels > 0 && tc.HeightPixels > 0 {
+		if tc.WidthPixels > tc.HeightPixels {
+			return OrientationLandscape
+		}
+		return OrientationPortrait
 	}
-	return OrientationPortrait
+	return orientation(tc.Width, tc.Height)
 }
 
 // ShouldUseTouch returns whether touch interactions should be primary
@@ -371,6 +390,14 @@ func (tc *TerminalCapabilities) String() string {
 	)
 }
 
+// pixelSource describes where pixel dimensions came from
+func pixelSource(reported bool) string {
+	if reported {
+		return "reported"
+	}
+	return "estimated"
+}
+
 // DebugReport generates detailed debug information
 func (tc *TerminalCapabilities) DebugReport() string {
 	lines := []string{
@@ -385,7 +412,7 @@ func (tc *TerminalCapabilities) DebugReport() string {
 		"",
 		"Dimensions:",
 		fmt.Sprintf("  Size: %dx%d characters", tc.Width, tc.Height),
-		fmt.Sprintf("  Pixels: %dx%d (estimated)", tc.WidthPixels, tc.HeightPixels),
+		fmt.Sprintf("  Pixels: %dx%d (%s)", tc.WidthPixels, tc.HeightPixels, pixelSource(tc.PixelsReported)),
 		fmt.Sprintf("  Orientation: %s", tc.GetOrientation()),
 		"",
 		"Colors:",
@@ -495,10 +522,16 @@ func ParseMouseEvent(seq string) (*MouseEvent, error) {
 		Button: button & 3, // Lower 2 bits
 	}
 
-	// Determine action
-	if isPress {
+	// Determine action. Motion (bit 5) is a drag while a button is held,
+	// and a move without one (button bits 3)
+	switch {
+	case button&32 != 0 && button&3 == 3:
+		event.Action = MouseMove
+	case button&32 != 0:
+		event.Action = MouseDrag
+	case isPress:
 		event.Action = MousePress
-	} else {
+	default:
 		event.Action = MouseRelease
 	}
 
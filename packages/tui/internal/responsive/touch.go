@@ -5,6 +5,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/aaronmrosenthal/rycode/internal/layout"
 	"github.com/aaronmrosenthal/rycode/internal/theme"
 )
 
@@ -17,7 +18,7 @@ type TouchZone struct {
 	Height   int
 	Action   func() tea.Cmd
 	Enabled  bool
-	MinSize  int // Minimum touch target size (44px iOS, 48px Android)
+	MinSize  int // Minimum touch target size in pixels (44px iOS, 48px Android)
 	Priority int // Higher priority zones are checked first
 }
 
@@ -28,12 +29,12 @@ type TouchTarget struct {
 	icon      string
 	focused   bool
 	pressed   bool
-	theme     *theme.Theme
+	theme     theme.Theme
 	touchSize int // Actual touch target size
 }
 
 // NewTouchTarget creates a touch-optimized target
-func NewTouchTarget(id, label, icon string, action func() tea.Cmd, theme *theme.Theme) *TouchTarget {
+func NewTouchTarget(id, label, icon string, action func() tea.Cmd, theme theme.Theme) *TouchTarget {
 	return &TouchTarget{
 		zone: TouchZone{
 			ID:       id,
@@ -56,12 +57,13 @@ func (tt *TouchTarget) SetPosition(x, y, width, height int) {
 	tt.zone.Width = width
 	tt.zone.Height = height
 
-	// Ensure minimum touch target size
-	if tt.zone.Width < tt.zone.MinSize {
-		tt.zone.Width = tt.zone.MinSize
+	// Ensure minimum touch target size, in the cells covering it
+	columns, rows := layout.Current.Cells(tt.zone.MinSize, tt.zone.MinSize)
+	if tt.zone.Width < columns {
+		tt.zone.Width = columns
 	}
-	if tt.zone.Height < tt.zone.MinSize {
-		tt.zone.Height = tt.zone.MinSize
+	if tt.zone.Height < rows {
+		tt.zone.Height = rows
 	}
 }
 
@@ -84,7 +86,7 @@ func (tt *TouchTarget) Tap() tea.Cmd {
 	// Visual feedback + action
 	return tea.Sequence(
 		NewHapticEngine(true).Trigger(HapticSelection),
-		tt.zone.Action,
+		tt.zone.Action(),
 		func() tea.Msg {
 			time.Sleep(100 * time.Millisecond)
 			return TouchReleaseMsg{ID: tt.zone.ID}
@@ -96,23 +98,23 @@ func (tt *TouchTarget) Tap() tea.Cmd {
 func (tt *TouchTarget) Render() string {
 	style := lipgloss.NewStyle().
 		Padding(1, 3).
-		Background(tt.theme.BackgroundSecondary).
-		Foreground(tt.theme.TextPrimary).
+		Background(tt.theme.BackgroundPanel()).
+		Foreground(tt.theme.Text()).
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(tt.theme.Border)
+		BorderForeground(tt.theme.Border())
 
 	// Pressed state
 	if tt.pressed {
 		style = style.
-			Background(tt.theme.AccentPrimary).
-			Foreground(tt.theme.BackgroundPrimary).
-			BorderForeground(tt.theme.AccentPrimary)
+			Background(tt.theme.Primary()).
+			Foreground(tt.theme.Background()).
+			BorderForeground(tt.theme.Primary())
 	}
 
 	// Focused state
 	if tt.focused {
 		style = style.
-			BorderForeground(tt.theme.AccentPrimary).
+			BorderForeground(tt.theme.Primary()).
 			BorderStyle(lipgloss.ThickBorder())
 	}
 
@@ -206,14 +208,14 @@ func (tm *TouchManager) HandleTouch(x, y int) tea.Cmd {
 		// Double tap action could be different
 		return tea.Sequence(
 			NewHapticEngine(true).Trigger(HapticMedium),
-			zone.Action,
+			zone.Action(),
 		)
 	}
 
 	// Single tap
 	return tea.Sequence(
 		NewHapticEngine(true).Trigger(HapticSelection),
-		zone.Action,
+		zone.Action(),
 	)
 }
 
@@ -235,11 +237,11 @@ type TouchGrid struct {
 	startY        int
 	targetWidth   int
 	targetHeight  int
-	theme         *theme.Theme
+	theme         theme.Theme
 }
 
 // NewTouchGrid creates a grid layout for touch targets
-func NewTouchGrid(cols, rows int, theme *theme.Theme) *TouchGrid {
+func NewTouchGrid(cols, rows int, theme theme.Theme) *TouchGrid {
 	return &TouchGrid{
 		cols:         cols,
 		rows:         rows,
@@ -297,7 +299,7 @@ func PhoneTouchButtons(actions []struct {
 	Icon  string
 	Label string
 	Action func() tea.Cmd
-}, theme *theme.Theme, width int) string {
+}, theme theme.Theme, width int) string {
 	// Calculate button size based on screen width
 	numButtons := len(actions)
 	if numButtons == 0 {
@@ -323,10 +325,10 @@ func PhoneTouchButtons(actions []struct {
 			Height(3).
 			Padding(1).
 			Align(lipgloss.Center, lipgloss.Center).
-			Background(theme.BackgroundSecondary).
-			Foreground(theme.TextPrimary).
+			Background(theme.BackgroundPanel()).
+			Foreground(theme.Text()).
 			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(theme.Border)
+			BorderForeground(theme.Border())
 
 		content := action.Icon
 		if buttonWidth > 15 && action.Label != "" {
@@ -347,7 +349,7 @@ func renderVerticalButtons(actions []struct {
 	Icon  string
 	Label string
 	Action func() tea.Cmd
-}, theme *theme.Theme, width int) string {
+}, theme theme.Theme, width int) string {
 	buttons := []string{}
 
 	for _, action := range actions {
@@ -355,10 +357,10 @@ func renderVerticalButtons(actions []struct {
 			Width(width - 4).
 			Height(3).
 			Padding(1, 2).
-			Background(theme.BackgroundSecondary).
-			Foreground(theme.TextPrimary).
+			Background(theme.BackgroundPanel()).
+			Foreground(theme.Text()).
 			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(theme.Border).
+			BorderForeground(theme.Border()).
 			MarginBottom(1)
 
 		content := lipgloss.JoinHorizontal(
@@ -375,7 +377,7 @@ func renderVerticalButtons(actions []struct {
 }
 
 // TouchDebugOverlay shows touch zone boundaries (for debugging)
-func TouchDebugOverlay(zones []*TouchZone, theme *theme.Theme, width, height int) string {
+func TouchDebugOverlay(zones []*TouchZone, theme theme.Theme, width, height int) string {
 	// Draw touch zones as colored rectangles
 	canvas := make([][]string, height)
 	for i := range canvas {
@@ -386,8 +388,8 @@ func TouchDebugOverlay(zones []*TouchZone, theme *theme.Theme, width, height int
 	}
 
 	debugStyle := lipgloss.NewStyle().
-		Foreground(theme.Error).
-		Background(theme.BackgroundSecondary)
+		Foreground(theme.Error()).
+		Background(theme.BackgroundPanel())
 
 	// Draw each zone
 	for _, zone := range zones {
@@ -402,7 +404,7 @@ func TouchDebugOverlay(zones []*TouchZone, theme *theme.Theme, width, height int
 		// Draw zone label at top-left
 		if zone.Y >= 0 && zone.Y < height && zone.X >= 0 && zone.X < width {
 			labelStyle := lipgloss.NewStyle().
-				Foreground(theme.Warning).
+				Foreground(theme.Warning()).
 				Bold(true)
 
 			label := labelStyle.Render(zone.ID)
@@ -430,11 +432,11 @@ type TouchFeedbackOverlay struct {
 	y        int
 	startTime time.Time
 	duration time.Duration
-	theme    *theme.Theme
+	theme    theme.Theme
 }
 
 // NewTouchFeedbackOverlay creates a touch feedback overlay
-func NewTouchFeedbackOverlay(theme *theme.Theme) *TouchFeedbackOverlay {
+func NewTouchFeedbackOverlay(theme theme.Theme) *TouchFeedbackOverlay {
 	return &TouchFeedbackOverlay{
 		theme:    theme,
 		duration: 200 * time.Millisecond,
@@ -477,7 +479,7 @@ func (tfo *TouchFeedbackOverlay) Render(width, height int) string {
 	size := int(progress * 5)
 
 	rippleStyle := lipgloss.NewStyle().
-		Foreground(tfo.theme.AccentPrimary).
+		Foreground(tfo.theme.Primary()).
 		Faint(true)
 
 	ripple := ""
@@ -485,12 +487,12 @@ func (tfo *TouchFeedbackOverlay) Render(width, height int) string {
 		ripple += "◯"
 	}
 
-	// Position at touch point
+	// Position at touch point, as a share of the area
 	positioned := lipgloss.Place(
 		width,
 		height,
-		tfo.x,
-		tfo.y,
+		lipgloss.Position(float64(tfo.x)/float64(max(width, 1))),
+		lipgloss.Position(float64(tfo.y)/float64(max(height, 1))),
 		rippleStyle.Render(ripple),
 	)
 
@@ -507,23 +509,32 @@ const (
 	RecommendedTouchSize = 48 // Use 48dp for consistency
 )
 
-// ValidateTouchTarget checks if touch target meets accessibility guidelines
+// MinTouchTargetCells returns the columns and rows covering the minimum
+// touch target size with the terminal's cell size
+func MinTouchTargetCells() (columns, rows int) {
+	return layout.Current.Cells(MinTouchTargetSize, MinTouchTargetSize)
+}
+
+// ValidateTouchTarget checks if a touch target of width by height cells
+// meets accessibility guidelines
 func ValidateTouchTarget(width, height int) bool {
-	return width >= MinTouchTargetSize && height >= MinTouchTargetSize
+	columns, rows := MinTouchTargetCells()
+	return width >= columns && height >= rows
 }
 
 // ExpandTouchTarget expands a target to minimum size if needed
 func ExpandTouchTarget(zone *TouchZone) {
-	if zone.Width < MinTouchTargetSize {
+	columns, rows := MinTouchTargetCells()
+	if zone.Width < columns {
 		// Center expansion
-		diff := MinTouchTargetSize - zone.Width
+		diff := columns - zone.Width
 		zone.X -= diff / 2
-		zone.Width = MinTouchTargetSize
+		zone.Width = columns
 	}
 
-	if zone.Height < MinTouchTargetSize {
-		diff := MinTouchTargetSize - zone.Height
+	if zone.Height < rows {
+		diff := rows - zone.Height
 		zone.Y -= diff / 2
-		zone.Height = MinTouchTargetSize
+		zone.Height = rows
 	}
 }
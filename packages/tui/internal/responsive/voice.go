@@ -31,6 +31,7 @@ type VoiceInput struct {
 	errorMessage  string
 	waveform      []int // Visual waveform
 	enabled       bool
+	provider      VoiceProvider
 }
 
 // NewVoiceInput creates a new voice input manager
@@ -39,9 +40,15 @@ func NewVoiceInput() *VoiceInput {
 		state:    VoiceIdle,
 		waveform: make([]int, 20),
 		enabled:  true,
+		provider: &WhisperVoice{},
 	}
 }
 
+// SetProvider sets the platform's voice provider
+func (vi *VoiceInput) SetProvider(provider VoiceProvider) {
+	vi.provider = provider
+}
+
 // VoiceStartMsg signals voice recording start
 type VoiceStartMsg struct{}
 
@@ -72,9 +79,7 @@ func (vi *VoiceInput) Start() tea.Cmd {
 	vi.errorMessage = ""
 
 	return tea.Batch(
-		func() tea.Msg {
-			return VoiceStartMsg{}
-		},
+		vi.provider.StartRecording(),
 		vi.animateWaveform(),
 	)
 }
@@ -85,17 +90,24 @@ func (vi *VoiceInput) Stop() tea.Cmd {
 	vi.isRecording = false
 	vi.duration = time.Since(vi.startTime)
 
-	return func() tea.Msg {
-		return VoiceStopMsg{}
-	}
+	provider := vi.provider
+	return tea.Batch(
+		func() tea.Msg {
+			return VoiceStopMsg{}
+		},
+		func() tea.Msg {
+			text, confidence, err := provider.StopRecording()
+			if err != nil {
+				return VoiceErrorMsg{Error: err.Error()}
+			}
+			return VoiceTranscriptMsg{Text: text, Confidence: confidence}
+		},
+	)
 }
 
 // Update handles voice input updates
 func (vi *VoiceInput) Update(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
-	case VoiceStartMsg:
-		return vi.simulateRecording()
-
 	case VoiceTranscriptMsg:
 		vi.state = VoiceIdle
 		vi.transcript = msg.Text
@@ -137,23 +149,6 @@ func (vi *VoiceInput) animateWaveform() tea.Cmd {
 	})
 }
 
-// simulateRecording simulates voice recording process
-func (vi *VoiceInput) simulateRecording() tea.Cmd {
-	// In real implementation, this would:
-	// 1. Initialize Web Speech API or native speech recognition
-	// 2. Start capturing microphone input
-	// 3. Send audio to speech-to-text service
-	// 4. Return transcribed text
-
-	return tea.Tick(3*time.Second, func(t time.Time) tea.Msg {
-		// Simulate successful transcription
-		return VoiceTranscriptMsg{
-			Text:       "fix the bug in auth.go",
-			Confidence: 0.92,
-		}
-	})
-}
-
 // GetTranscript returns the transcribed text
 func (vi *VoiceInput) GetTranscript() string {
 	return vi.transcript
@@ -179,7 +174,7 @@ func (vi *VoiceInput) Reset() {
 }
 
 // Render renders voice input UI
-func (vi *VoiceInput) Render(theme *theme.Theme, width int) string {
+func (vi *VoiceInput) Render(theme theme.Theme, width int) string {
 	switch vi.state {
 	case VoiceListening:
 		return vi.renderListening(theme, width)
@@ -193,17 +188,17 @@ func (vi *VoiceInput) Render(theme *theme.Theme, width int) string {
 }
 
 // renderListening renders listening state
-func (vi *VoiceInput) renderListening(theme *theme.Theme, width int) string {
+func (vi *VoiceInput) renderListening(theme theme.Theme, width int) string {
 	containerStyle := lipgloss.NewStyle().
 		Width(width).
 		Padding(2).
-		Background(theme.BackgroundSecondary).
+		Background(theme.BackgroundPanel()).
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(theme.AccentPrimary)
+		BorderForeground(theme.Primary())
 
 	// Title
 	titleStyle := lipgloss.NewStyle().
-		Foreground(theme.AccentPrimary).
+		Foreground(theme.Primary()).
 		Bold(true).
 		Width(width - 4).
 		Align(lipgloss.Center)
@@ -216,7 +211,7 @@ func (vi *VoiceInput) renderListening(theme *theme.Theme, width int) string {
 	// Duration
 	duration := time.Since(vi.startTime)
 	durationStyle := lipgloss.NewStyle().
-		Foreground(theme.TextDim).
+		Foreground(theme.TextMuted()).
 		Width(width - 4).
 		Align(lipgloss.Center)
 
@@ -224,7 +219,7 @@ func (vi *VoiceInput) renderListening(theme *theme.Theme, width int) string {
 
 	// Hint
 	hintStyle := lipgloss.NewStyle().
-		Foreground(theme.TextDim).
+		Foreground(theme.TextMuted()).
 		Width(width - 4).
 		Align(lipgloss.Center).
 		MarginTop(1)
@@ -245,7 +240,7 @@ func (vi *VoiceInput) renderListening(theme *theme.Theme, width int) string {
 }
 
 // renderWaveform renders audio waveform visualization
-func (vi *VoiceInput) renderWaveform(theme *theme.Theme, width int) string {
+func (vi *VoiceInput) renderWaveform(theme theme.Theme, width int) string {
 	bars := []string{}
 
 	for _, level := range vi.waveform {
@@ -260,7 +255,7 @@ func (vi *VoiceInput) renderWaveform(theme *theme.Theme, width int) string {
 }
 
 // renderBar renders a single waveform bar
-func (vi *VoiceInput) renderBar(level int, theme *theme.Theme) string {
+func (vi *VoiceInput) renderBar(level int, theme theme.Theme) string {
 	chars := []string{"▁", "▂", "▃", "▄", "▅", "▆"}
 
 	if level >= len(chars) {
@@ -268,23 +263,23 @@ func (vi *VoiceInput) renderBar(level int, theme *theme.Theme) string {
 	}
 
 	style := lipgloss.NewStyle().
-		Foreground(theme.AccentPrimary)
+		Foreground(theme.Primary())
 
 	return style.Render(chars[level])
 }
 
 // renderProcessing renders processing state
-func (vi *VoiceInput) renderProcessing(theme *theme.Theme, width int) string {
+func (vi *VoiceInput) renderProcessing(theme theme.Theme, width int) string {
 	style := lipgloss.NewStyle().
 		Width(width).
 		Padding(2).
-		Background(theme.BackgroundSecondary).
+		Background(theme.BackgroundPanel()).
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(theme.Info).
+		BorderForeground(theme.Info()).
 		Align(lipgloss.Center)
 
 	content := lipgloss.NewStyle().
-		Foreground(theme.Info).
+		Foreground(theme.Info()).
 		Bold(true).
 		Render("⏳ Processing...")
 
@@ -292,22 +287,22 @@ func (vi *VoiceInput) renderProcessing(theme *theme.Theme, width int) string {
 }
 
 // renderError renders error state
-func (vi *VoiceInput) renderError(theme *theme.Theme, width int) string {
+func (vi *VoiceInput) renderError(theme theme.Theme, width int) string {
 	style := lipgloss.NewStyle().
 		Width(width).
 		Padding(2).
-		Background(theme.BackgroundSecondary).
+		Background(theme.BackgroundPanel()).
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(theme.Error).
+		BorderForeground(theme.Error()).
 		Align(lipgloss.Center)
 
 	title := lipgloss.NewStyle().
-		Foreground(theme.Error).
+		Foreground(theme.Error()).
 		Bold(true).
 		Render("❌ Error")
 
 	message := lipgloss.NewStyle().
-		Foreground(theme.TextDim).
+		Foreground(theme.TextMuted()).
 		MarginTop(1).
 		Render(vi.errorMessage)
 
@@ -411,9 +406,9 @@ func (vqc *VoiceQuickCommands) GetSuggestions() []string {
 }
 
 // VoiceHelpOverlay renders voice help overlay
-func VoiceHelpOverlay(theme *theme.Theme, width int) string {
+func VoiceHelpOverlay(theme theme.Theme, width int) string {
 	titleStyle := lipgloss.NewStyle().
-		Foreground(theme.AccentPrimary).
+		Foreground(theme.Primary()).
 		Bold(true).
 		Width(width - 4).
 		Align(lipgloss.Center).
@@ -450,13 +445,13 @@ func VoiceHelpOverlay(theme *theme.Theme, width int) string {
 	sections := []string{}
 	for _, cmd := range commands {
 		categoryStyle := lipgloss.NewStyle().
-			Foreground(theme.AccentSecondary).
+			Foreground(theme.Secondary()).
 			Bold(true)
 
 		category := categoryStyle.Render(cmd.category)
 
 		exampleStyle := lipgloss.NewStyle().
-			Foreground(theme.TextDim).
+			Foreground(theme.TextMuted()).
 			MarginLeft(2)
 
 		examples := []string{}
@@ -474,7 +469,7 @@ func VoiceHelpOverlay(theme *theme.Theme, width int) string {
 	}
 
 	hintStyle := lipgloss.NewStyle().
-		Foreground(theme.Info).
+		Foreground(theme.Info()).
 		Width(width - 4).
 		Align(lipgloss.Center).
 		MarginTop(1)
@@ -491,9 +486,9 @@ func VoiceHelpOverlay(theme *theme.Theme, width int) string {
 	containerStyle := lipgloss.NewStyle().
 		Width(width - 2).
 		Padding(1, 2).
-		Background(theme.BackgroundSecondary).
+		Background(theme.BackgroundPanel()).
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(theme.Border)
+		BorderForeground(theme.Border())
 
 	return containerStyle.Render(content)
 }
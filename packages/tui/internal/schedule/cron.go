@@ -0,0 +1,265 @@
+package schedule
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule computes when a job runs next
+type Schedule interface {
+	// Next returns the first run strictly after t, or the zero time when
+	// there is none
+	Next(t time.Time) time.Time
+}
+
+// cronSchedule is a standard five-field cron expression: minute, hour, day
+// of month, month and day of week
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64 // Bit sets of the allowed values
+	domAny, dowAny                bool
+}
+
+// everySchedule runs at a fixed interval
+type everySchedule struct {
+	interval time.Duration
+}
+
+func (e everySchedule) Next(t time.Time) time.Time {
+	return t.Add(e.interval)
+}
+
+var aliases = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// Parse parses a schedule: a five-field cron expression, one of the
+// @hourly/@daily/@weekly/@monthly/@yearly aliases, "@every 30m", or a
+// phrase such as "daily at 9", "weekdays at 9:30" or "every monday at 8am"
+func Parse(expr string) (Schedule, error) {
+	normalized, err := Normalize(expr)
+	if err != nil {
+		return nil, err
+	}
+	if rest, ok := strings.CutPrefix(normalized, "@every "); ok {
+		interval, err := time.ParseDuration(rest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval %q", rest)
+		}
+		if interval < time.Minute {
+			return nil, fmt.Errorf("interval %s is shorter than a minute", interval)
+		}
+		return everySchedule{interval: interval}, nil
+	}
+	return parseCron(normalized)
+}
+
+var (
+	everyPattern = regexp.MustCompile(`^(?:@?every)\s+(\d+)\s*(m|min|mins|minutes?|h|hrs?|hours?)$`)
+	atPattern    = regexp.MustCompile(`^(?:every\s+)?(day|daily|morning|evening|night|weekday|weekdays|weekend|weekends|` +
+		`monday|tuesday|wednesday|thursday|friday|saturday|sunday)s?\s+at\s+(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+)
+
+// Normalize rewrites a schedule to a cron expression or "@every <duration>"
+func Normalize(expr string) (string, error) {
+	expr = strings.Join(strings.Fields(strings.ToLower(expr)), " ")
+	if expr == "" {
+		return "", fmt.Errorf("empty schedule")
+	}
+	if cron, ok := aliases[expr]; ok {
+		return cron, nil
+	}
+	if cron, ok := aliases["@"+expr]; ok {
+		return cron, nil
+	}
+	if rest, ok := strings.CutPrefix(expr, "@every "); ok {
+		if _, err := time.ParseDuration(rest); err == nil {
+			return expr, nil
+		}
+	}
+	if match := everyPattern.FindStringSubmatch(expr); match != nil {
+		unit := "m"
+		if strings.HasPrefix(match[2], "h") {
+			unit = "h"
+		}
+		return "@every " + match[1] + unit, nil
+	}
+	if match := atPattern.FindStringSubmatch(expr); match != nil {
+		hour, _ := strconv.Atoi(match[2])
+		minute := 0
+		if match[3] != "" {
+			minute, _ = strconv.Atoi(match[3])
+		}
+		if match[4] != "" && (hour < 1 || hour > 12) || hour > 23 || minute > 59 {
+			return "", fmt.Errorf("invalid time in %q", expr)
+		}
+		switch {
+		case match[4] == "am" && hour == 12:
+			hour = 0
+		case match[4] == "pm" && hour < 12:
+			hour += 12
+		}
+		days := "*"
+		switch match[1] {
+		case "weekday", "weekdays":
+			days = "1-5"
+		case "weekend", "weekends":
+			days = "0,6"
+		case "day", "daily", "morning", "evening", "night":
+		default:
+			days = strconv.Itoa(dayNames[match[1][:3]])
+		}
+		return fmt.Sprintf("%d %d * * %s", minute, hour, days), nil
+	}
+	if len(strings.Fields(expr)) == 5 {
+		if _, err := parseCron(expr); err != nil {
+			return "", err
+		}
+		return expr, nil
+	}
+	return "", fmt.Errorf("unrecognized schedule %q; use cron syntax such as \"0 9 * * *\" or a phrase such as \"daily at 9\"", expr)
+}
+
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields", expr)
+	}
+	var s cronSchedule
+	var err error
+	if s.minute, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("minute: %w", err)
+	}
+	if s.hour, err = parseField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("hour: %w", err)
+	}
+	if s.dom, err = parseField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("day of month: %w", err)
+	}
+	if s.month, err = parseField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, fmt.Errorf("month: %w", err)
+	}
+	if s.dow, err = parseField(fields[4], 0, 7, dayNames); err != nil {
+		return nil, fmt.Errorf("day of week: %w", err)
+	}
+	// Sunday is both 0 and 7
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domAny = fields[2] == "*" || fields[2] == "?"
+	s.dowAny = fields[4] == "*" || fields[4] == "?"
+	return &s, nil
+}
+
+// parseField parses a comma-separated list of values, ranges and steps into
+// a bit set
+func parseField(field string, low, high int, names map[string]int) (uint64, error) {
+	value := func(s string) (int, error) {
+		if n, ok := names[s]; ok {
+			return n, nil
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < low || n > high {
+			return 0, fmt.Errorf("%q is not between %d and %d", s, low, high)
+		}
+		return n, nil
+	}
+
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = n
+		}
+
+		start, end := low, high
+		switch {
+		case rangePart == "*" || rangePart == "?":
+		case strings.Contains(rangePart, "-"):
+			from, to, _ := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = value(from); err != nil {
+				return 0, err
+			}
+			if end, err = value(to); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			n, err := value(rangePart)
+			if err != nil {
+				return 0, err
+			}
+			start = n
+			if !hasStep {
+				end = n
+			}
+		}
+		for i := start; i <= end; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, nil
+}
+
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// Five years covers every valid expression, including 29 February
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		year, month, day := t.Date()
+		switch {
+		case s.month&(1<<uint(month)) == 0:
+			t = time.Date(year, month+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(year, month, day+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(year, month, day, t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches applies the cron rule that a day matches when either the day
+// of month or the day of week does, unless one of them is unrestricted
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	}
+	return dom || dow
+}
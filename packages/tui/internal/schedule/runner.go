@@ -0,0 +1,253 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+// runTimeout bounds a single run of a job
+const runTimeout = 15 * time.Minute
+
+// pollInterval is how often the daemon looks for due jobs
+const pollInterval = 30 * time.Second
+
+// maxWebhookText bounds the text field of webhook payloads, which chat
+// services display as the message
+const maxWebhookText = 3000
+
+// disabledTools keeps unattended runs from changing files; shell commands
+// stay available so that jobs can inspect the repository
+var disabledTools = map[string]bool{
+	"edit":      false,
+	"write":     false,
+	"patch":     false,
+	"todowrite": false,
+}
+
+// Result is the outcome of a run
+type Result struct {
+	JobID     string
+	RanAt     time.Time
+	SessionID string // Set when the session was kept
+	Output    string
+	Cost      float64
+	Err       error
+}
+
+// Execute runs a job's prompt in a new session. The session is deleted
+// afterwards unless the job keeps its sessions.
+func Execute(ctx context.Context, client *opencode.Client, job Job, now time.Time) Result {
+	result := Result{JobID: job.ID, RanAt: now}
+	ctx, cancel := context.WithTimeout(ctx, runTimeout)
+	defer cancel()
+
+	session, err := client.Session.New(ctx, opencode.SessionNewParams{
+		Directory: opencode.F(job.Directory),
+		Title:     opencode.F(fmt.Sprintf("⏰ %s · %s", job.Name, now.Format("Jan 2 15:04"))),
+	})
+	if err != nil {
+		result.Err = fmt.Errorf("failed to create session: %w", err)
+		return result
+	}
+	if job.KeepSession {
+		result.SessionID = session.ID
+	} else {
+		defer func() {
+			if _, err := client.Session.Delete(context.Background(), session.ID, opencode.SessionDeleteParams{}); err != nil {
+				slog.Debug("Failed to delete scheduled session", "session", session.ID, "error", err)
+			}
+		}()
+	}
+
+	params := opencode.SessionPromptParams{
+		Directory: opencode.F(job.Directory),
+		Tools:     opencode.F(disabledTools),
+		Parts: opencode.F([]opencode.SessionPromptParamsPartUnion{
+			opencode.TextPartInputParam{
+				Type: opencode.F(opencode.TextPartInputTypeText),
+				Text: opencode.F(job.Prompt),
+			},
+		}),
+	}
+	if job.ProviderID != "" && job.ModelID != "" {
+		params.Model = opencode.F(opencode.SessionPromptParamsModel{
+			ProviderID: opencode.F(job.ProviderID),
+			ModelID:    opencode.F(job.ModelID),
+		})
+	}
+	if job.Agent != "" {
+		params.Agent = opencode.F(job.Agent)
+	}
+	response, err := client.Session.Prompt(ctx, session.ID, params)
+	if err != nil {
+		result.Err = fmt.Errorf("prompt failed: %w", err)
+		return result
+	}
+
+	var texts []string
+	for _, part := range response.Parts {
+		if text, ok := part.AsUnion().(opencode.TextPart); ok && !text.Synthetic {
+			texts = append(texts, text.Text)
+		}
+	}
+	result.Output = strings.TrimSpace(strings.Join(texts, "\n"))
+	result.Cost = response.Info.Cost
+	return result
+}
+
+// webhookPayload is posted to a job's webhook. Text summarizes the result
+// for chat services such as Slack or Discord, which display that field.
+type webhookPayload struct {
+	Text     string    `json:"text"`
+	Content  string    `json:"content"` // Discord's name for Text
+	Job      string    `json:"job"`
+	JobID    string    `json:"jobID"`
+	Schedule string    `json:"schedule"`
+	Status   string    `json:"status"`
+	RanAt    time.Time `json:"ranAt"`
+	Session  string    `json:"session,omitempty"`
+	Output   string    `json:"output,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Notify posts a result to the job's webhook
+func Notify(ctx context.Context, client *http.Client, job Job, result Result) error {
+	payload := webhookPayload{
+		Job:      job.Name,
+		JobID:    job.ID,
+		Schedule: job.Schedule,
+		Status:   "ok",
+		RanAt:    result.RanAt,
+		Session:  result.SessionID,
+		Output:   result.Output,
+	}
+	text := fmt.Sprintf("*%s*\n%s", job.Name, result.Output)
+	if result.Err != nil {
+		payload.Status = "error"
+		payload.Error = result.Err.Error()
+		text = fmt.Sprintf("*%s* failed: %s", job.Name, result.Err)
+	}
+	if runes := []rune(text); len(runes) > maxWebhookText {
+		text = string(runes[:maxWebhookText]) + "…"
+	}
+	payload.Text, payload.Content = text, text
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.Webhook, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Daemon runs due jobs until its context is cancelled
+type Daemon struct {
+	Client *opencode.Client
+	Store  *Store
+	HTTP   *http.Client
+	Now    func() time.Time
+}
+
+// Run polls the store for due jobs and runs them one at a time
+func (d *Daemon) Run(ctx context.Context) error {
+	slog.Info("Scheduler started", "jobs", d.Store.Path())
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		d.RunDue(ctx)
+		select {
+		case <-ctx.Done():
+			slog.Info("Scheduler stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue runs the jobs that are due
+func (d *Daemon) RunDue(ctx context.Context) {
+	jobs, err := d.Store.Jobs()
+	if err != nil {
+		slog.Error("Failed to load scheduled prompts", "error", err)
+		return
+	}
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		if job.Due(d.now()) {
+			d.RunJob(ctx, job)
+		}
+	}
+}
+
+// RunJob runs a job now, delivers its result and records it in the store
+func (d *Daemon) RunJob(ctx context.Context, job Job) Result {
+	now := d.now()
+	// Record the run first so that a crash mid-run doesn't repeat it
+	if err := d.Store.Modify(job.ID, func(j *Job) { j.LastRun = now }); err != nil {
+		slog.Error("Failed to record scheduled run", "job", job.ID, "error", err)
+		return Result{JobID: job.ID, RanAt: now, Err: err}
+	}
+
+	slog.Info("Running scheduled prompt", "job", job.ID, "name", job.Name)
+	result := Execute(ctx, d.Client, job, now)
+	if result.Err != nil {
+		slog.Error("Scheduled prompt failed", "job", job.ID, "error", result.Err)
+	}
+	if job.Webhook != "" {
+		client := d.HTTP
+		if client == nil {
+			client = http.DefaultClient
+		}
+		if err := Notify(ctx, client, job, result); err != nil {
+			slog.Error("Failed to deliver scheduled result", "job", job.ID, "error", err)
+			if result.Err == nil {
+				result.Err = err
+			}
+		}
+	}
+
+	err := d.Store.Modify(job.ID, func(j *Job) {
+		j.LastStatus, j.LastError = "ok", ""
+		if result.Err != nil {
+			j.LastStatus, j.LastError = "error", result.Err.Error()
+		}
+		if result.SessionID != "" {
+			j.LastSession = result.SessionID
+		}
+	})
+	if err != nil {
+		slog.Warn("Failed to record scheduled result", "job", job.ID, "error", err)
+	}
+	return result
+}
+
+func (d *Daemon) now() time.Time {
+	if d.Now != nil {
+		return d.Now()
+	}
+	return time.Now()
+}
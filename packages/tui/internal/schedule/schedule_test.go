@@ -0,0 +1,136 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"0 9 * * *":           "0 9 * * *",
+		"@daily":              "0 0 * * *",
+		"hourly":              "0 * * * *",
+		"every 30 minutes":    "@every 30m",
+		"@every 2h":           "@every 2h",
+		"daily at 9":          "0 9 * * *",
+		"every morning at 9":  "0 9 * * *",
+		"weekdays at 9:30":    "30 9 * * 1-5",
+		"every Monday at 2pm": "0 14 * * 1",
+		"every day at 12am":   "0 0 * * *",
+		"weekends at 10:15am": "15 10 * * 0,6",
+	}
+	for expr, want := range tests {
+		got, err := Normalize(expr)
+		if err != nil || got != want {
+			t.Errorf("Normalize(%q) = %q, %v; want %q", expr, got, err, want)
+		}
+	}
+
+	for _, expr := range []string{"", "sometimes", "61 * * * *", "daily at 25", "daily at 13pm", "* * *"} {
+		if _, err := Normalize(expr); err == nil {
+			t.Errorf("Normalize(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	// A Wednesday
+	from := time.Date(2026, 3, 11, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"0 9 * * *", time.Date(2026, 3, 12, 9, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 11, 9, 45, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2026, 3, 12, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * sat", time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Day of month or day of week when both are restricted
+		{"0 12 15 * fri", time.Date(2026, 3, 13, 12, 0, 0, 0, time.UTC)},
+		{"@every 90m", time.Date(2026, 3, 11, 11, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		schedule, err := Parse(tt.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.expr, err)
+		}
+		if got := schedule.Next(from); !got.Equal(tt.want) {
+			t.Errorf("%q: Next = %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestDue(t *testing.T) {
+	created := time.Date(2026, 3, 11, 8, 0, 0, 0, time.UTC)
+	job := Job{Schedule: "0 9 * * *", Created: created}
+	if job.Due(created.Add(59 * time.Minute)) {
+		t.Error("due before its first run")
+	}
+	if !job.Due(created.Add(3 * 24 * time.Hour)) {
+		t.Error("a missed run is not made up")
+	}
+
+	job.LastRun = time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC)
+	if job.Due(time.Date(2026, 3, 11, 20, 0, 0, 0, time.UTC)) {
+		t.Error("due again on the day it ran")
+	}
+	job.Paused = true
+	if job.Due(created.Add(3 * 24 * time.Hour)) {
+		t.Error("paused job is due")
+	}
+}
+
+func TestParseJob(t *testing.T) {
+	now := time.Now()
+	job, err := ParseJob("weekdays at 9 --webhook https://example.com/hook | summarize yesterday's commits", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Schedule != "0 9 * * 1-5" || job.Webhook != "https://example.com/hook" || !job.KeepSession {
+		t.Errorf("job = %+v", job)
+	}
+	if job.Prompt != "summarize yesterday's commits" || job.Name != job.Prompt {
+		t.Errorf("prompt = %q, name = %q", job.Prompt, job.Name)
+	}
+
+	for _, args := range []string{
+		"daily at 9",
+		"daily at 9 |  ",
+		"daily at 9 --no-session | hello",
+		"daily at 9 --webhook ftp://x | hello",
+	} {
+		if _, err := ParseJob(args, now); err == nil {
+			t.Errorf("ParseJob(%q) succeeded, want an error", args)
+		}
+	}
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore(t.TempDir())
+	jobs, err := store.Jobs()
+	if err != nil || len(jobs) != 0 {
+		t.Fatalf("empty store: %v, %v", jobs, err)
+	}
+
+	job, err := NewJob("digest", "@daily", "summarize", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Add(job); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Modify(job.ID, func(j *Job) { j.Paused = true }); err != nil {
+		t.Fatal(err)
+	}
+	jobs, _ = store.Jobs()
+	if len(jobs) != 1 || !jobs[0].Paused {
+		t.Fatalf("jobs = %+v", jobs)
+	}
+	if err := store.Remove(job.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Remove(job.ID); err == nil {
+		t.Error("removing a missing job succeeded")
+	}
+}
@@ -0,0 +1,243 @@
+// Package schedule runs recurring headless prompts. Jobs are kept in a JSON
+// file in the state directory, edited from the TUI and run by the scheduler
+// daemon, which saves each result as a session and/or posts it to a webhook.
+package schedule
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileName is the name of the jobs file in the state directory
+const FileName = "schedules.json"
+
+// Job is a recurring prompt
+type Job struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Schedule    string    `json:"schedule"` // Cron expression or "@every <duration>"
+	Prompt      string    `json:"prompt"`
+	ProviderID  string    `json:"providerID,omitempty"`
+	ModelID     string    `json:"modelID,omitempty"`
+	Agent       string    `json:"agent,omitempty"`
+	Directory   string    `json:"directory,omitempty"` // Project the job was created in
+	KeepSession bool      `json:"keepSession"`         // Save each run as a session
+	Webhook     string    `json:"webhook,omitempty"`   // URL each result is posted to
+	Paused      bool      `json:"paused,omitempty"`
+	Created     time.Time `json:"created"`
+
+	LastRun     time.Time `json:"lastRun,omitempty"`
+	LastStatus  string    `json:"lastStatus,omitempty"` // "ok" or "error"
+	LastError   string    `json:"lastError,omitempty"`
+	LastSession string    `json:"lastSession,omitempty"`
+}
+
+// Next returns when the job runs next after its last run, or the zero time
+// when it is paused or its schedule is invalid
+func (j Job) Next() time.Time {
+	if j.Paused {
+		return time.Time{}
+	}
+	schedule, err := Parse(j.Schedule)
+	if err != nil {
+		return time.Time{}
+	}
+	from := j.LastRun
+	if from.IsZero() {
+		from = j.Created
+	}
+	return schedule.Next(from)
+}
+
+// Due reports whether the job should run at now. A run missed while the
+// daemon wasn't running is made up once, not once per missed occurrence.
+func (j Job) Due(now time.Time) bool {
+	next := j.Next()
+	return !next.IsZero() && !next.After(now)
+}
+
+// NewJob creates a job, checking its schedule
+func NewJob(name, schedule, prompt string, now time.Time) (Job, error) {
+	normalized, err := Normalize(schedule)
+	if err != nil {
+		return Job{}, err
+	}
+	if _, err := Parse(normalized); err != nil {
+		return Job{}, err
+	}
+	if strings.TrimSpace(prompt) == "" {
+		return Job{}, fmt.Errorf("the prompt is empty")
+	}
+	if name == "" {
+		name = prompt
+		if runes := []rune(name); len(runes) > 40 {
+			name = string(runes[:40]) + "…"
+		}
+	}
+	return Job{
+		ID:          newID(),
+		Name:        name,
+		Schedule:    normalized,
+		Prompt:      prompt,
+		KeepSession: true,
+		Created:     now,
+	}, nil
+}
+
+// ParseJob parses the arguments of /schedule add:
+//
+//	<schedule> [--webhook URL] [--no-session] [--name NAME] | <prompt>
+//
+// for example "daily at 9 | summarize yesterday's commits". Results are
+// saved as sessions unless --no-session is given, which needs a webhook.
+func ParseJob(args string, now time.Time) (Job, error) {
+	spec, prompt, ok := strings.Cut(args, "|")
+	if !ok {
+		return Job{}, fmt.Errorf("usage: /schedule add <when> [--webhook URL] [--no-session] | <prompt>")
+	}
+
+	var when []string
+	var name, webhook string
+	keepSession := true
+	fields := strings.Fields(spec)
+	for i := 0; i < len(fields); i++ {
+		switch field := fields[i]; {
+		case field == "--webhook" && i+1 < len(fields):
+			i++
+			webhook = fields[i]
+		case strings.HasPrefix(field, "--webhook="):
+			webhook = strings.TrimPrefix(field, "--webhook=")
+		case field == "--name" && i+1 < len(fields):
+			i++
+			name = fields[i]
+		case field == "--no-session":
+			keepSession = false
+		case strings.HasPrefix(field, "--"):
+			return Job{}, fmt.Errorf("unknown option %s", field)
+		default:
+			when = append(when, strings.Trim(field, `"'`))
+		}
+	}
+	if webhook != "" && !strings.HasPrefix(webhook, "https://") && !strings.HasPrefix(webhook, "http://") {
+		return Job{}, fmt.Errorf("the webhook must be an http(s) URL")
+	}
+	if !keepSession && webhook == "" {
+		return Job{}, fmt.Errorf("--no-session needs a --webhook to deliver results to")
+	}
+
+	job, err := NewJob(name, strings.Join(when, " "), strings.TrimSpace(prompt), now)
+	if err != nil {
+		return Job{}, err
+	}
+	job.Webhook = webhook
+	job.KeepSession = keepSession
+	return job, nil
+}
+
+func newID() string {
+	b := make([]byte, 3)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Store is the jobs file. Every operation reads the file again so that the
+// TUI and the daemon see each other's changes.
+type Store struct {
+	path string
+}
+
+// NewStore returns the jobs store of a state directory
+func NewStore(stateDir string) *Store {
+	return &Store{path: filepath.Join(stateDir, FileName)}
+}
+
+// Path returns the jobs file path
+func (s *Store) Path() string {
+	return s.path
+}
+
+type jobsFile struct {
+	Jobs []Job `json:"jobs"`
+}
+
+// Jobs returns the registered jobs
+func (s *Store) Jobs() ([]Job, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read schedules: %w", err)
+	}
+	var file jobsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", s.path, err)
+	}
+	return file.Jobs, nil
+}
+
+// Update applies fn to the jobs and saves the result
+func (s *Store) Update(fn func(jobs []Job) ([]Job, error)) error {
+	jobs, err := s.Jobs()
+	if err != nil {
+		return err
+	}
+	jobs, err = fn(jobs)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(jobsFile{Jobs: jobs}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode schedules: %w", err)
+	}
+	// Write atomically; the daemon may read the file at any time
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write schedules: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write schedules: %w", err)
+	}
+	return nil
+}
+
+// Add registers a job
+func (s *Store) Add(job Job) error {
+	return s.Update(func(jobs []Job) ([]Job, error) {
+		return append(jobs, job), nil
+	})
+}
+
+// Remove deletes the job with an ID
+func (s *Store) Remove(id string) error {
+	return s.Update(func(jobs []Job) ([]Job, error) {
+		for i, job := range jobs {
+			if job.ID == id {
+				return append(jobs[:i], jobs[i+1:]...), nil
+			}
+		}
+		return nil, fmt.Errorf("no scheduled prompt with ID %s", id)
+	})
+}
+
+// Modify changes the job with an ID
+func (s *Store) Modify(id string, fn func(job *Job)) error {
+	return s.Update(func(jobs []Job) ([]Job, error) {
+		for i := range jobs {
+			if jobs[i].ID == id {
+				fn(&jobs[i])
+				return jobs, nil
+			}
+		}
+		return nil, fmt.Errorf("no scheduled prompt with ID %s", id)
+	})
+}
@@ -0,0 +1,192 @@
+// Package secreview builds read-only security reviews of files or diffs and
+// parses the agent's findings into a report that can be exported to SARIF.
+package secreview
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Severity is how serious a finding is
+type Severity string
+
+const (
+	SeverityCritical Severity = "critical"
+	SeverityHigh     Severity = "high"
+	SeverityMedium   Severity = "medium"
+	SeverityLow      Severity = "low"
+	SeverityInfo     Severity = "info"
+)
+
+// severityRanks orders severities from most to least serious
+var severityRanks = map[Severity]int{
+	SeverityCritical: 0,
+	SeverityHigh:     1,
+	SeverityMedium:   2,
+	SeverityLow:      3,
+	SeverityInfo:     4,
+}
+
+// Finding is one security issue found by the review
+type Finding struct {
+	Severity    Severity `json:"severity"`
+	Title       string   `json:"title"`
+	Path        string   `json:"path"` // Relative to the project root
+	Line        int      `json:"line,omitempty"`
+	EndLine     int      `json:"end_line,omitempty"`
+	CWE         string   `json:"cwe,omitempty"` // e.g. CWE-89
+	Description string   `json:"description"`
+	Remediation string   `json:"remediation"`
+}
+
+// Location returns path:line, or the path alone when the line is unknown
+func (f Finding) Location() string {
+	if f.Line <= 0 {
+		return f.Path
+	}
+	if f.EndLine > f.Line {
+		return fmt.Sprintf("%s:%d-%d", f.Path, f.Line, f.EndLine)
+	}
+	return fmt.Sprintf("%s:%d", f.Path, f.Line)
+}
+
+// Scope is what a review covers: the given paths, or a diff
+type Scope struct {
+	Paths []string
+	Diff  bool
+	Ref   string // Revision the diff is taken against
+}
+
+// String describes the scope for titles and reports
+func (s Scope) String() string {
+	if s.Diff {
+		return "changes since " + s.Ref
+	}
+	return strings.Join(s.Paths, ", ")
+}
+
+// ParseArgs parses the arguments of /security-review: paths to review, or
+// --diff [ref] to review uncommitted changes or those since ref. Without
+// arguments the uncommitted changes are reviewed.
+func ParseArgs(args string) (Scope, error) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return Scope{Diff: true, Ref: "HEAD"}, nil
+	}
+	if fields[0] == "--diff" {
+		switch len(fields) {
+		case 1:
+			return Scope{Diff: true, Ref: "HEAD"}, nil
+		case 2:
+			return Scope{Diff: true, Ref: fields[1]}, nil
+		}
+		return Scope{}, fmt.Errorf("usage: /security-review --diff [ref]")
+	}
+	for _, field := range fields {
+		if strings.HasPrefix(field, "-") {
+			return Scope{}, fmt.Errorf("unknown option %s", field)
+		}
+	}
+	return Scope{Paths: fields}, nil
+}
+
+// SystemPrompt puts the agent in a read-only security review mode
+const SystemPrompt = `You are performing a security review. This is a read-only analysis: do not modify files or run commands.
+Look for exploitable weaknesses such as injection, broken authentication or authorization, unsafe deserialization, path traversal, secrets in code, insecure cryptography, SSRF, race conditions and missing input validation.
+Report only issues you can point to in the code, with the exact file and line. Do not report style issues.`
+
+const findingsFormat = "Reply with a single ```json block and nothing else, in this shape:\n" +
+	"```json\n" +
+	`{"findings": [{"severity": "critical|high|medium|low|info", "title": "short name", "path": "repository/relative/path", "line": 12, "end_line": 14, "cwe": "CWE-89", "description": "what is wrong and how it can be exploited", "remediation": "how to fix it"}]}` +
+	"\n```\n" +
+	`Use {"findings": []} when nothing is found.`
+
+// BuildPrompt asks for a review of the scope. diff is the diff under review
+// when the scope is a diff.
+func BuildPrompt(scope Scope, diff string) string {
+	var b strings.Builder
+	if scope.Diff {
+		fmt.Fprintf(&b, "Review the following changes (%s) for security issues. Read the surrounding code where needed, but only report issues introduced or touched by the changes.\n\n", scope)
+		b.WriteString("```diff\n" + diff + "\n```\n\n")
+	} else {
+		b.WriteString("Review the following files and directories for security issues. Read them with your tools:\n")
+		for _, path := range scope.Paths {
+			b.WriteString("- " + path + "\n")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(findingsFormat)
+	return b.String()
+}
+
+// Report is the outcome of a security review
+type Report struct {
+	Scope     string    `json:"scope"`
+	Model     string    `json:"model"`
+	CreatedAt time.Time `json:"created_at"`
+	Findings  []Finding `json:"findings"`
+}
+
+// Counts returns the number of findings of each severity
+func (r *Report) Counts() map[Severity]int {
+	counts := make(map[Severity]int)
+	for _, finding := range r.Findings {
+		counts[finding.Severity]++
+	}
+	return counts
+}
+
+var jsonBlockPattern = regexp.MustCompile("(?s)```(?:json)?\\s*(\\{.*?\\})\\s*```")
+
+// ParseFindings extracts the findings from the agent's answer, normalizing
+// severities and paths and sorting them by severity then location
+func ParseFindings(answer, root string) ([]Finding, error) {
+	payload := ""
+	if match := jsonBlockPattern.FindStringSubmatch(answer); match != nil {
+		payload = match[1]
+	} else if start, end := strings.Index(answer, "{"), strings.LastIndex(answer, "}"); start >= 0 && end > start {
+		payload = answer[start : end+1]
+	}
+	if payload == "" {
+		return nil, fmt.Errorf("the review didn't contain any findings")
+	}
+
+	var parsed struct {
+		Findings []Finding `json:"findings"`
+	}
+	if err := json.Unmarshal([]byte(payload), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse findings: %w", err)
+	}
+
+	findings := parsed.Findings
+	for i := range findings {
+		finding := &findings[i]
+		finding.Severity = Severity(strings.ToLower(strings.TrimSpace(string(finding.Severity))))
+		if _, ok := severityRanks[finding.Severity]; !ok {
+			finding.Severity = SeverityMedium
+		}
+		if filepath.IsAbs(finding.Path) {
+			if rel, err := filepath.Rel(root, finding.Path); err == nil && !strings.HasPrefix(rel, "..") {
+				finding.Path = rel
+			}
+		}
+		finding.Path = filepath.ToSlash(strings.TrimPrefix(finding.Path, "./"))
+		finding.CWE = strings.ToUpper(strings.TrimSpace(finding.CWE))
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if severityRanks[a.Severity] != severityRanks[b.Severity] {
+			return severityRanks[a.Severity] < severityRanks[b.Severity]
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Line < b.Line
+	})
+	return findings, nil
+}
@@ -0,0 +1,81 @@
+package secreview
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	scope, err := ParseArgs("")
+	if err != nil || !scope.Diff || scope.Ref != "HEAD" {
+		t.Errorf("expected uncommitted changes by default, got %+v (%v)", scope, err)
+	}
+	scope, err = ParseArgs("--diff main")
+	if err != nil || !scope.Diff || scope.Ref != "main" {
+		t.Errorf("expected diff against main, got %+v (%v)", scope, err)
+	}
+	scope, err = ParseArgs("internal/auth cmd/main.go")
+	if err != nil || scope.Diff || len(scope.Paths) != 2 {
+		t.Errorf("expected two paths, got %+v (%v)", scope, err)
+	}
+	if _, err := ParseArgs("--bogus"); err == nil {
+		t.Error("expected an error for an unknown option")
+	}
+}
+
+func TestParseFindings(t *testing.T) {
+	answer := "Here is the review.\n```json\n" + `{"findings": [
+		{"severity": "Low", "title": "Verbose errors", "path": "./api/handler.go", "line": 40},
+		{"severity": "critical", "title": "SQL injection", "path": "/repo/db/query.go", "line": 12, "end_line": 14, "cwe": "cwe-89"},
+		{"severity": "unknown", "title": "Odd", "path": "a.go"}
+	]}` + "\n```"
+
+	findings, err := ParseFindings(answer, "/repo")
+	if err != nil {
+		t.Fatalf("failed to parse findings: %v", err)
+	}
+	if len(findings) != 3 {
+		t.Fatalf("expected 3 findings, got %d", len(findings))
+	}
+	first := findings[0]
+	if first.Severity != SeverityCritical || first.Path != "db/query.go" || first.CWE != "CWE-89" {
+		t.Errorf("unexpected first finding %+v", first)
+	}
+	if first.Location() != "db/query.go:12-14" {
+		t.Errorf("unexpected location %s", first.Location())
+	}
+	if findings[1].Severity != SeverityMedium || findings[2].Path != "api/handler.go" {
+		t.Errorf("unexpected order or normalization: %+v", findings)
+	}
+
+	if _, err := ParseFindings("nothing to see", "/repo"); err == nil {
+		t.Error("expected an error without findings")
+	}
+}
+
+func TestSARIF(t *testing.T) {
+	report := &Report{Findings: []Finding{
+		{Severity: SeverityHigh, Title: "SQL injection", Path: "db/query.go", Line: 12, CWE: "CWE-89", Remediation: "Use placeholders"},
+		{Severity: SeverityLow, Title: "Verbose errors", Path: "api/handler.go"},
+	}}
+	data, err := report.SARIF()
+	if err != nil {
+		t.Fatalf("failed to encode SARIF: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("invalid SARIF: %v", err)
+	}
+	run := log.Runs[0]
+	if log.Version != "2.1.0" || len(run.Tool.Driver.Rules) != 2 || len(run.Results) != 2 {
+		t.Fatalf("unexpected SARIF log %+v", log)
+	}
+	result := run.Results[0]
+	if result.RuleID != "CWE-89" || result.Level != "error" || result.Locations[0].PhysicalLocation.Region.StartLine != 12 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if run.Results[1].RuleID != "verbose-errors" || run.Results[1].Locations[0].PhysicalLocation.Region != nil {
+		t.Errorf("unexpected result %+v", run.Results[1])
+	}
+}
@@ -0,0 +1,140 @@
+package secreview
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// sarifSchema is the schema of the SARIF version written
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine,omitempty"`
+}
+
+// sarifLevels maps severities to SARIF result levels
+var sarifLevels = map[Severity]string{
+	SeverityCritical: "error",
+	SeverityHigh:     "error",
+	SeverityMedium:   "warning",
+	SeverityLow:      "note",
+	SeverityInfo:     "note",
+}
+
+var ruleIDPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ruleID identifies the kind of a finding: its CWE when known, otherwise its
+// title
+func ruleID(finding Finding) string {
+	if finding.CWE != "" {
+		return finding.CWE
+	}
+	id := strings.Trim(ruleIDPattern.ReplaceAllString(strings.ToLower(finding.Title), "-"), "-")
+	if id == "" {
+		return "security-issue"
+	}
+	return id
+}
+
+// SARIF encodes the report as a SARIF 2.1.0 log, as read by code scanning
+// tools
+func (r *Report) SARIF() ([]byte, error) {
+	driver := sarifDriver{
+		Name:           "RyCode Security Review",
+		InformationURI: "https://github.com/aaronmrosenthal/RyCode",
+		Rules:          []sarifRule{},
+	}
+	seenRules := make(map[string]bool)
+	results := make([]sarifResult, 0, len(r.Findings))
+	for _, finding := range r.Findings {
+		id := ruleID(finding)
+		if !seenRules[id] {
+			seenRules[id] = true
+			driver.Rules = append(driver.Rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: finding.Title}})
+		}
+
+		message := finding.Title
+		if finding.Description != "" {
+			message += ": " + finding.Description
+		}
+		result := sarifResult{
+			RuleID:     id,
+			Level:      sarifLevels[finding.Severity],
+			Message:    sarifMessage{Text: message},
+			Properties: map[string]string{"severity": string(finding.Severity)},
+		}
+		if finding.Remediation != "" {
+			result.Properties["remediation"] = finding.Remediation
+		}
+		if finding.Path != "" {
+			location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: finding.Path}}
+			if finding.Line > 0 {
+				location.Region = &sarifRegion{StartLine: finding.Line}
+				if finding.EndLine > finding.Line {
+					location.Region.EndLine = finding.EndLine
+				}
+			}
+			result.Locations = []sarifLocation{{PhysicalLocation: location}}
+		}
+		results = append(results, result)
+	}
+
+	return json.MarshalIndent(sarifLog{
+		Version: "2.1.0",
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}, "", "  ")
+}
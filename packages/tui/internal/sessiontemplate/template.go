@@ -0,0 +1,189 @@
+// Package sessiontemplate loads session templates, which pre-seed a new
+// session with an agent, a model, instructions added to the system prompt
+// and files attached to its first prompt. Templates are JSON files, so they
+// can be shared by committing them to a project or sending them around.
+package sessiontemplate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ProjectDir is where a project's templates are kept, relative to the
+// project root
+const ProjectDir = ".rycode/templates"
+
+// MaxContextFiles is the most files a template attaches, so a broad pattern
+// can't attach a whole repository
+const MaxContextFiles = 20
+
+// Template pre-seeds a new session
+type Template struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Agent       string   `json:"agent,omitempty"`
+	Model       string   `json:"model,omitempty"`   // As provider/model
+	System      string   `json:"system,omitempty"`  // Added to the system prompt of every prompt
+	Context     []string `json:"context,omitempty"` // Files and glob patterns attached to the first prompt, relative to the project root
+
+	Path string `json:"-"` // File the template was loaded from, empty when built in
+}
+
+// Builtin are the templates available in every project. Project and user
+// templates of the same name replace them.
+var Builtin = []Template{
+	{
+		Name:        "incident-response",
+		Description: "Investigate a production incident",
+		Agent:       "build",
+		System: "You are helping respond to a production incident. Prioritise mitigation over root cause: " +
+			"first establish the impact and a safe way to stop it, then investigate. Prefer reversible changes, " +
+			"say which commands are safe to run in production before running them, and keep a timestamped " +
+			"timeline of findings and actions that can go into the postmortem.",
+	},
+	{
+		Name:        "code-review",
+		Description: "Review the changes on the current branch",
+		Agent:       "plan",
+		System: "You are reviewing code changes, not writing them. Read the diff against the base branch and " +
+			"report bugs, security issues, missing tests and unclear code, most severe first, each with the file " +
+			"and line. Don't edit files. Leave out style nits a formatter would fix.",
+	},
+}
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Parse reads a template from JSON, checking its name
+func Parse(data []byte) (Template, error) {
+	var t Template
+	if err := json.Unmarshal(data, &t); err != nil {
+		return Template{}, fmt.Errorf("invalid template: %w", err)
+	}
+	t.Name = strings.ToLower(strings.TrimSpace(t.Name))
+	if !namePattern.MatchString(t.Name) {
+		return Template{}, fmt.Errorf("invalid template name %q; use lowercase letters, digits, - and _", t.Name)
+	}
+	if t.Model != "" && !strings.Contains(t.Model, "/") {
+		return Template{}, fmt.Errorf("invalid model %q; use provider/model", t.Model)
+	}
+	return t, nil
+}
+
+// JSON returns the template as a JSON file
+func (t Template) JSON() []byte {
+	data, _ := json.MarshalIndent(t, "", "  ")
+	return append(data, '\n')
+}
+
+// Load returns the built-in templates and those in the user's config
+// directory and the project, sorted by name. Later directories replace
+// templates of the same name. Files that can't be read are skipped and
+// reported in the error.
+func Load(configDir, root string) ([]Template, error) {
+	templates := make(map[string]Template)
+	for _, t := range Builtin {
+		templates[t.Name] = t
+	}
+	var errs []error
+	for _, dir := range []string{filepath.Join(configDir, "templates"), filepath.Join(root, ProjectDir)} {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			data, err := os.ReadFile(path)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			t, err := Parse(data)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", path, err))
+				continue
+			}
+			t.Path = path
+			templates[t.Name] = t
+		}
+	}
+
+	var sorted []Template
+	for _, t := range templates {
+		sorted = append(sorted, t)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted, errors.Join(errs...)
+}
+
+// Find returns the template of a name
+func Find(templates []Template, name string) (Template, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, t := range templates {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Template{}, false
+}
+
+// Save writes a template to the project, where it is shared with everyone
+// working on it, and returns the path of the file
+func Save(root string, t Template) (string, error) {
+	dir := filepath.Join(root, ProjectDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create template directory: %w", err)
+	}
+	path := filepath.Join(dir, t.Name+".json")
+	if err := os.WriteFile(path, t.JSON(), 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return path, nil
+}
+
+// ContextFiles expands the template's context patterns into the files they
+// match under root, relative to it. Patterns reaching outside root are an
+// error.
+func (t Template) ContextFiles(root string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	for _, pattern := range t.Context {
+		pattern = filepath.Clean(filepath.FromSlash(pattern))
+		if filepath.IsAbs(pattern) || pattern == ".." || strings.HasPrefix(pattern, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("context %q is outside the project", pattern)
+		}
+		matches, err := filepath.Glob(filepath.Join(root, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid context pattern %q: %w", pattern, err)
+		}
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil || info.IsDir() {
+				continue
+			}
+			rel, err := filepath.Rel(root, match)
+			if err != nil || seen[rel] {
+				continue
+			}
+			if len(files) == MaxContextFiles {
+				return files, fmt.Errorf("context matches more than %d files", MaxContextFiles)
+			}
+			seen[rel] = true
+			files = append(files, filepath.ToSlash(rel))
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,69 @@
+package sessiontemplate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	template, err := Parse([]byte(`{"name": " Triage ", "model": "anthropic/claude-sonnet-4", "context": ["docs/*.md"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if template.Name != "triage" || template.Model != "anthropic/claude-sonnet-4" {
+		t.Errorf("template = %+v", template)
+	}
+	for _, data := range []string{`{"name": "a b"}`, `{"name": "x", "model": "sonnet"}`, `{`} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("Parse(%s) succeeded", data)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	configDir, root := t.TempDir(), t.TempDir()
+	if err := os.MkdirAll(filepath.Join(configDir, "templates"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(configDir, "templates", "mine.json"), []byte(`{"name": "mine", "system": "user"}`), 0644)
+	os.WriteFile(filepath.Join(configDir, "templates", "broken.json"), []byte(`{`), 0644)
+	if _, err := Save(root, Template{Name: "code-review", System: "project"}); err != nil {
+		t.Fatal(err)
+	}
+
+	templates, err := Load(configDir, root)
+	if err == nil || !strings.Contains(err.Error(), "broken.json") {
+		t.Errorf("err = %v, want the broken template reported", err)
+	}
+	var names []string
+	for _, template := range templates {
+		names = append(names, template.Name)
+	}
+	if got := strings.Join(names, ","); got != "code-review,incident-response,mine" {
+		t.Errorf("templates = %s", got)
+	}
+	if review, _ := Find(templates, "code-review"); review.System != "project" || review.Path == "" {
+		t.Errorf("project template didn't replace the built-in one: %+v", review)
+	}
+}
+
+func TestContextFiles(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "docs", "runbooks"), 0755)
+	for _, name := range []string{"docs/a.md", "docs/b.md", "docs/runbooks/db.md", "README.md"} {
+		os.WriteFile(filepath.Join(root, name), []byte("x"), 0644)
+	}
+
+	files, err := Template{Context: []string{"docs/*", "README.md", "docs/a.md"}}.ContextFiles(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(files, ","); got != "docs/a.md,docs/b.md,README.md" {
+		t.Errorf("files = %s", got)
+	}
+	if _, err := (Template{Context: []string{"../secrets"}}).ContextFiles(root); err == nil {
+		t.Error("a pattern outside the project was expanded")
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+
+package shells
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// startGroup puts the shell in a process group of its own, with the
+// commands it starts
+func startGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killGroup kills the shell and the commands it started
+func killGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
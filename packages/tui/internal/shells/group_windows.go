@@ -0,0 +1,13 @@
+//go:build windows
+
+package shells
+
+import "os/exec"
+
+func startGroup(cmd *exec.Cmd) {}
+
+// killGroup kills the shell. Commands it started are left to exit with
+// their input closed.
+func killGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
@@ -0,0 +1,329 @@
+// Package shells keeps named shells running next to the session, such as a
+// dev server in one and tests in another. Commands are fed to a shell's
+// standard input and its output is kept as scrollback. The output is also
+// appended to a log file, and commands appended to an inbox file are handed
+// out to be run, so the agent can use the shells with its file tools too.
+package shells
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/aaronmrosenthal/rycode/internal/target"
+)
+
+// ScrollbackLines is how many lines of output a shell keeps
+const ScrollbackLines = 2000
+
+// maxLogBytes is the size a shell's log grows to before it is rotated, the
+// output before it kept in the log's .1 file
+var maxLogBytes int64 = 1 << 20
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// Shell is a named shell and what it printed
+type Shell struct {
+	Name    string
+	Dir     string // Where it started, relative to the project root
+	Started time.Time
+
+	kill    func() error
+	stdin   io.WriteCloser
+	log     *os.File
+	logPath string
+	logSize int64
+
+	mu       sync.Mutex
+	lines    []string // Scrollback, oldest first
+	partial  string   // Output after the last newline
+	exited   bool
+	exitErr  error
+	reported bool // The exit was taken with TakeExit
+}
+
+// Run sends a command line to the shell
+func (s *Shell) Run(command string) error {
+	s.mu.Lock()
+	exited := s.exited
+	s.mu.Unlock()
+	if exited {
+		return fmt.Errorf("shell %s has exited", s.Name)
+	}
+	s.write("$ " + command + "\n")
+	_, err := io.WriteString(s.stdin, command+"\n")
+	return err
+}
+
+// Lines returns the last n lines of the scrollback, all of them when n is
+// 0, including a line still being printed
+func (s *Shell) Lines(n int) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lines := s.lines
+	if s.partial != "" {
+		lines = append(slices.Clip(lines), s.partial)
+	}
+	if n > 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return slices.Clone(lines)
+}
+
+// Running reports whether the shell is still running
+func (s *Shell) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.exited
+}
+
+// TakeExit returns how the shell exited, once, after it has
+func (s *Shell) TakeExit() (error, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.exited || s.reported {
+		return nil, false
+	}
+	s.reported = true
+	return s.exitErr, true
+}
+
+// write adds output to the scrollback and the log. A carriage return
+// starts its line over, as progress bars expect.
+func (s *Shell) write(text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.log != nil && s.logSize > 0 && s.logSize+int64(len(text)) > maxLogBytes {
+		s.rotateLog()
+	}
+	if s.log != nil {
+		n, _ := s.log.WriteString(text)
+		s.logSize += int64(n)
+	}
+	text = s.partial + text
+	for {
+		line, rest, ok := strings.Cut(text, "\n")
+		if !ok {
+			break
+		}
+		s.lines = append(s.lines, clean(line))
+		text = rest
+	}
+	s.partial = clean(text)
+	if over := len(s.lines) - ScrollbackLines; over > 0 {
+		s.lines = slices.Delete(s.lines, 0, over)
+	}
+}
+
+// rotateLog moves the log aside, replacing the one moved before, and starts
+// a new one
+func (s *Shell) rotateLog() {
+	s.log.Close()
+	s.log, s.logSize = nil, 0
+	os.Rename(s.logPath, s.logPath+".1")
+	if log, err := os.Create(s.logPath); err == nil {
+		s.log = log
+	}
+}
+
+func clean(line string) string {
+	line = strings.TrimSuffix(line, "\r")
+	if i := strings.LastIndex(line, "\r"); i >= 0 {
+		line = line[i+1:]
+	}
+	return ansi.Strip(line)
+}
+
+// Pool holds the named shells of the TUI
+type Pool struct {
+	dir     string // Holds the logs and inboxes
+	changed chan struct{}
+
+	mu     sync.Mutex
+	shells []*Shell // In the order they were started
+}
+
+// NewPool creates a pool keeping the shells' logs and inboxes in dir
+func NewPool(dir string) *Pool {
+	return &Pool{dir: dir, changed: make(chan struct{}, 1)}
+}
+
+// Changed receives when a shell printed something or exited. Changes that
+// come while one is pending are merged into it.
+func (p *Pool) Changed() <-chan struct{} {
+	return p.changed
+}
+
+func (p *Pool) notify() {
+	select {
+	case p.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Get returns the shell with a name, or nil
+func (p *Pool) Get(name string) *Shell {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, s := range p.shells {
+		if strings.EqualFold(s.Name, name) {
+			return s
+		}
+	}
+	return nil
+}
+
+// List returns the shells in the order they were started
+func (p *Pool) List() []*Shell {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.shells)
+}
+
+// LogPath returns the file a shell's output is appended to
+func (p *Pool) LogPath(name string) string {
+	return filepath.Join(p.dir, name+".log")
+}
+
+// InboxPath returns the file whose lines are run in a shell
+func (p *Pool) InboxPath(name string) string {
+	return filepath.Join(p.dir, name+".in")
+}
+
+// Start starts a shell in a directory of the project at root, replacing a
+// shell of the same name
+func (p *Pool) Start(name, root, dir string) (*Shell, error) {
+	if !validName.MatchString(name) {
+		return nil, fmt.Errorf("shell names are letters, digits, - and _, not %q", name)
+	}
+	p.Close(name)
+	if err := os.MkdirAll(p.dir, 0o755); err != nil {
+		return nil, err
+	}
+	log, err := os.Create(p.LogPath(name))
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := target.Interactive(context.Background(), filepath.Join(root, filepath.FromSlash(dir)))
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		log.Close()
+		return nil, err
+	}
+	output, input := io.Pipe()
+	cmd.Stdout, cmd.Stderr = input, input
+	startGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		log.Close()
+		return nil, err
+	}
+
+	s := &Shell{
+		Name:    name,
+		Dir:     dir,
+		Started: time.Now(),
+		kill:    func() error { return killGroup(cmd) },
+		stdin:   stdin,
+		log:     log,
+		logPath: p.LogPath(name),
+	}
+	go p.read(s, output)
+	go func() {
+		err := cmd.Wait()
+		input.Close()
+		s.mu.Lock()
+		s.exited, s.exitErr = true, err
+		s.mu.Unlock()
+		p.notify()
+	}()
+
+	p.mu.Lock()
+	p.shells = append(p.shells, s)
+	p.mu.Unlock()
+	return s, nil
+}
+
+func (p *Pool) read(s *Shell, output io.Reader) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := output.Read(buf)
+		if n > 0 {
+			s.write(string(buf[:n]))
+			p.notify()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Close stops a shell, and whatever runs in it, and forgets it
+func (p *Pool) Close(name string) error {
+	p.mu.Lock()
+	i := slices.IndexFunc(p.shells, func(s *Shell) bool { return strings.EqualFold(s.Name, name) })
+	if i < 0 {
+		p.mu.Unlock()
+		return fmt.Errorf("no shell named %s", name)
+	}
+	s := p.shells[i]
+	p.shells = slices.Delete(p.shells, i, i+1)
+	p.mu.Unlock()
+
+	s.stdin.Close()
+	var err error
+	if s.Running() {
+		err = s.kill()
+	}
+	s.mu.Lock()
+	if s.log != nil {
+		s.log.Close()
+		s.log = nil
+	}
+	s.mu.Unlock()
+	os.Remove(p.InboxPath(s.Name))
+	return err
+}
+
+// CloseAll stops every shell and removes their files
+func (p *Pool) CloseAll() {
+	for _, s := range p.List() {
+		p.Close(s.Name)
+	}
+	os.RemoveAll(p.dir)
+}
+
+// TakeInbox returns the command lines left in a shell's inbox and empties
+// it
+func (p *Pool) TakeInbox(name string) ([]string, error) {
+	inbox := p.InboxPath(name)
+	taken := inbox + ".taken"
+	// Moved aside first, so lines appended meanwhile land in a new inbox
+	if err := os.Rename(inbox, taken); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	data, err := os.ReadFile(taken)
+	os.Remove(taken)
+	if err != nil {
+		return nil, err
+	}
+	var commands []string
+	for line := range strings.SplitSeq(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			commands = append(commands, line)
+		}
+	}
+	return commands, nil
+}
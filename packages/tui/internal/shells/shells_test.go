@@ -0,0 +1,116 @@
+package shells
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	s := &Shell{}
+	s.write("one\ntw")
+	s.write("o\n\x1b[32mgreen\x1b[0m\n10%\r50%\r100%")
+	if got, want := s.Lines(0), []string{"one", "two", "green", "100%"}; !slices.Equal(got, want) {
+		t.Errorf("Lines = %q, want %q", got, want)
+	}
+	if got := s.Lines(2); !slices.Equal(got, []string{"green", "100%"}) {
+		t.Errorf("Lines(2) = %q", got)
+	}
+
+	s.write(strings.Repeat("line\n", ScrollbackLines+10))
+	if got := len(s.Lines(0)); got != ScrollbackLines {
+		t.Errorf("scrollback holds %d lines, want %d", got, ScrollbackLines)
+	}
+}
+
+func TestLogRotation(t *testing.T) {
+	defer func(max int64) { maxLogBytes = max }(maxLogBytes)
+	maxLogBytes = 10
+
+	path := filepath.Join(t.TempDir(), "dev.log")
+	log, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Shell{log: log, logPath: path}
+	s.write("first 8\n")
+	s.write("second\n")
+	s.write("third\n")
+	s.log.Close()
+
+	if data, _ := os.ReadFile(path); string(data) != "third\n" {
+		t.Errorf("log = %q, want the output since the last rotation", data)
+	}
+	if data, _ := os.ReadFile(path + ".1"); string(data) != "second\n" {
+		t.Errorf("rotated log = %q, want the output before it", data)
+	}
+}
+
+func TestTakeInbox(t *testing.T) {
+	p := NewPool(t.TempDir())
+	if commands, err := p.TakeInbox("tests"); commands != nil || err != nil {
+		t.Fatalf("empty inbox = %q, %v", commands, err)
+	}
+	os.WriteFile(p.InboxPath("tests"), []byte("go test ./...\n\n  make lint \n"), 0o644)
+	commands, err := p.TakeInbox("tests")
+	if err != nil || !slices.Equal(commands, []string{"go test ./...", "make lint"}) {
+		t.Errorf("TakeInbox = %q, %v", commands, err)
+	}
+	if commands, _ := p.TakeInbox("tests"); commands != nil {
+		t.Errorf("inbox taken twice: %q", commands)
+	}
+}
+
+func TestPool(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("runs sh")
+	}
+	root := t.TempDir()
+	p := NewPool(t.TempDir())
+	defer p.CloseAll()
+
+	if _, err := p.Start("bad name", root, ""); err == nil {
+		t.Error("started a shell with a space in its name")
+	}
+	s, err := p.Start("tests", root, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Run("echo hello from $(basename $PWD)"); err != nil {
+		t.Fatal(err)
+	}
+	want := "hello from " + filepath.Base(root)
+	deadline := time.After(5 * time.Second)
+	for !slices.Contains(s.Lines(0), want) {
+		select {
+		case <-p.Changed():
+		case <-deadline:
+			t.Fatalf("output = %q, want %q", s.Lines(0), want)
+		}
+	}
+	if log, _ := os.ReadFile(p.LogPath("tests")); !strings.Contains(string(log), want) {
+		t.Errorf("log = %q", log)
+	}
+
+	s.Run("exit 3")
+	for s.Running() {
+		select {
+		case <-p.Changed():
+		case <-deadline:
+			t.Fatal("the shell didn't exit")
+		}
+	}
+	if err, ok := s.TakeExit(); !ok || err == nil {
+		t.Errorf("TakeExit = %v, %v; want the exit status", err, ok)
+	}
+	if _, ok := s.TakeExit(); ok {
+		t.Error("the exit was taken twice")
+	}
+	if err := p.Close("tests"); err != nil || p.Get("tests") != nil {
+		t.Errorf("Close = %v", err)
+	}
+}
@@ -129,6 +129,12 @@ func ShouldShowSplash(config *Config) bool {
 		return false
 	}
 
+	return config.Due()
+}
+
+// Due reports whether the splash frequency has it shown this time. The
+// first run always shows it.
+func (config *Config) Due() bool {
 	// First run always shows (unless explicitly disabled)
 	if IsFirstRun() {
 		return true
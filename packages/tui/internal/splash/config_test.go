@@ -154,6 +154,27 @@ func TestIsFirstRun(t *testing.T) {
 	}
 }
 
+func TestConfigDue(t *testing.T) {
+	markerPath := filepath.Join(t.TempDir(), ".splash_shown")
+	originalGetMarkerPath := getMarkerPath
+	getMarkerPath = func() string { return markerPath }
+	defer func() { getMarkerPath = originalGetMarkerPath }()
+
+	// The first run shows the splash whatever the frequency
+	if !(&Config{SplashFrequency: "never"}).Due() {
+		t.Error("Splash should be due on the first run")
+	}
+
+	if err := MarkAsShown(); err != nil {
+		t.Fatalf("Failed to mark as shown: %v", err)
+	}
+	for frequency, want := range map[string]bool{"always": true, "first": false, "never": false, "": false} {
+		if got := (&Config{SplashFrequency: frequency}).Due(); got != want {
+			t.Errorf("Due() with frequency %q = %v, want %v", frequency, got, want)
+		}
+	}
+}
+
 func TestDisableSplashPermanently(t *testing.T) {
 	// Create temporary directory
 	tmpDir := t.TempDir()
@@ -16,6 +16,31 @@ type TerminalCapabilities struct {
 	Unicode     bool
 	TooSmall    bool
 	Performance string // "fast", "medium", "slow"
+	// SupportsKittyGraphics is set for terminals that draw kitty graphics
+	// protocol images with Unicode placeholders
+	SupportsKittyGraphics bool
+	SupportsSixel         bool
+	Notifications         NotificationProtocol
+}
+
+// NotificationProtocol is the escape sequence a terminal shows desktop
+// notifications with
+type NotificationProtocol int
+
+const (
+	NotifyBell   NotificationProtocol = iota // The bell, for terminals that show no notifications
+	NotifyOSC9                               // OSC 9, as iTerm2, WezTerm, kitty and Ghostty show
+	NotifyOSC777                             // OSC 777, as urxvt, foot and VTE terminals show
+)
+
+func (p NotificationProtocol) String() string {
+	switch p {
+	case NotifyOSC9:
+		return "OSC 9"
+	case NotifyOSC777:
+		return "OSC 777"
+	}
+	return "the bell"
 }
 
 // DetectTerminalCapabilities detects what the terminal can handle
@@ -48,6 +73,11 @@ func DetectTerminalCapabilities() TerminalCapabilities {
 	// Estimate performance (conservative)
 	caps.Performance = EstimatePerformance()
 
+	// Detect image support
+	caps.SupportsKittyGraphics = SupportsKittyGraphics()
+	caps.SupportsSixel = SupportsSixel()
+	caps.Notifications = DetectNotificationProtocol()
+
 	return caps
 }
 
@@ -89,6 +119,47 @@ func SupportsUnicode() bool {
 	return true
 }
 
+// SupportsKittyGraphics checks if the terminal draws kitty graphics images
+// placed with Unicode placeholders, which kitty and Ghostty do. Inside tmux
+// the terminal is known from the environment tmux was started in.
+func SupportsKittyGraphics() bool {
+	term := os.Getenv("TERM")
+	termProgram := strings.ToLower(os.Getenv("TERM_PROGRAM"))
+	return term == "xterm-kitty" || term == "xterm-ghostty" ||
+		os.Getenv("KITTY_WINDOW_ID") != "" ||
+		os.Getenv("GHOSTTY_RESOURCES_DIR") != "" ||
+		termProgram == "ghostty"
+}
+
+// SupportsSixel checks if the terminal is one known to draw Sixel images
+func SupportsSixel() bool {
+	switch strings.ToLower(os.Getenv("TERM_PROGRAM")) {
+	case "wezterm", "mlterm", "contour", "iterm.app":
+		return true
+	}
+	term := os.Getenv("TERM")
+	return strings.HasPrefix(term, "foot") || strings.HasPrefix(term, "mlterm") ||
+		strings.Contains(term, "sixel")
+}
+
+// DetectNotificationProtocol checks how the terminal shows desktop
+// notifications. Inside tmux the terminal is known from the environment
+// tmux was started in.
+func DetectNotificationProtocol() NotificationProtocol {
+	switch strings.ToLower(os.Getenv("TERM_PROGRAM")) {
+	case "iterm.app", "wezterm", "ghostty":
+		return NotifyOSC9
+	}
+	term := os.Getenv("TERM")
+	switch {
+	case term == "xterm-kitty" || term == "xterm-ghostty" || os.Getenv("KITTY_WINDOW_ID") != "":
+		return NotifyOSC9
+	case strings.HasPrefix(term, "foot") || strings.HasPrefix(term, "rxvt") || os.Getenv("VTE_VERSION") != "":
+		return NotifyOSC777
+	}
+	return NotifyBell
+}
+
 // EstimatePerformance estimates terminal rendering performance
 func EstimatePerformance() string {
 	// Check if running in remote session (likely slower)
@@ -108,6 +108,30 @@ func TestSupportsUnicode(t *testing.T) {
 	}
 }
 
+func TestSupportsKittyGraphics(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected bool
+	}{
+		{"kitty", map[string]string{"TERM": "xterm-kitty"}, true},
+		{"ghostty", map[string]string{"TERM_PROGRAM": "ghostty"}, true},
+		{"kitty under tmux", map[string]string{"TERM": "tmux-256color", "KITTY_WINDOW_ID": "1"}, true},
+		{"xterm", map[string]string{"TERM": "xterm-256color"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"TERM", "TERM_PROGRAM", "KITTY_WINDOW_ID", "GHOSTTY_RESOURCES_DIR"} {
+				t.Setenv(key, tt.env[key])
+			}
+			if result := SupportsKittyGraphics(); result != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
 func TestDetectTerminalCapabilities(t *testing.T) {
 	// Just ensure it doesn't panic and returns reasonable values
 	caps := DetectTerminalCapabilities()
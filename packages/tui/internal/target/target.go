@@ -0,0 +1,305 @@
+// Package target runs the commands of a project on the host, or inside a
+// Docker container or devcontainer when the project is configured to use
+// one. The project's files stay where they are and are expected to be
+// mounted into the container.
+package target
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+)
+
+// ConfigPath is where a project chooses its execution target, relative to
+// the project root
+const ConfigPath = ".rycode/target.json"
+
+// Kinds of execution targets
+const (
+	KindHost         = "host"
+	KindDocker       = "docker"
+	KindDevcontainer = "devcontainer"
+)
+
+// Config is the execution target of a project
+type Config struct {
+	Kind      string `json:"type"`
+	Container string `json:"container,omitempty"` // Name or ID of the container, for docker
+	Workdir   string `json:"workdir,omitempty"`   // The project root in the container, found from its mounts when empty
+	User      string `json:"user,omitempty"`
+	Shell     string `json:"shell,omitempty"` // Runs shell commands, sh when empty
+}
+
+// Container is a running container that commands run in
+type Container struct {
+	Kind    string
+	ID      string
+	Name    string
+	Root    string // The project root on the host
+	Workdir string // The project root in the container
+	User    string
+	Shell   string
+}
+
+// LoadConfig reads the execution target of the project at root. It returns
+// nil when the project has none.
+func LoadConfig(root string) (*Config, error) {
+	data, err := remote.ReadFile(filepath.Join(root, ConfigPath))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("%s: %w", ConfigPath, err)
+	}
+	return &config, nil
+}
+
+// Resolve finds the running container of a configuration. It returns nil
+// when commands run on the host.
+func Resolve(ctx context.Context, root string, config *Config) (*Container, error) {
+	if config == nil {
+		return nil, nil
+	}
+	c := &Container{
+		Kind:    config.Kind,
+		ID:      config.Container,
+		Root:    root,
+		Workdir: config.Workdir,
+		User:    config.User,
+		Shell:   config.Shell,
+	}
+	switch config.Kind {
+	case "", KindHost:
+		return nil, nil
+	case KindDocker:
+		if c.ID == "" {
+			return nil, fmt.Errorf("%s: a docker target needs a container", ConfigPath)
+		}
+	case KindDevcontainer:
+		if c.ID == "" {
+			id, err := devcontainerID(ctx, root)
+			if err != nil {
+				return nil, err
+			}
+			c.ID = id
+		}
+		if c.Workdir == "" {
+			c.Workdir = workspaceFolder(root)
+		}
+	default:
+		return nil, fmt.Errorf("%s: unknown target type %q, want %s, %s or %s", ConfigPath, config.Kind, KindHost, KindDocker, KindDevcontainer)
+	}
+	if c.Shell == "" {
+		c.Shell = "sh"
+	}
+
+	info, err := inspect(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !info.State.Running {
+		return nil, fmt.Errorf("container %s isn't running", c.ID)
+	}
+	c.Name = strings.TrimPrefix(info.Name, "/")
+	if c.Workdir == "" {
+		for _, mount := range info.Mounts {
+			if rel, err := filepath.Rel(mount.Source, root); err == nil && !strings.HasPrefix(rel, "..") {
+				c.Workdir = path.Join(mount.Destination, filepath.ToSlash(rel))
+				break
+			}
+		}
+	}
+	if c.Workdir == "" {
+		return nil, fmt.Errorf("%s isn't mounted in container %s, set its workdir in %s", root, c.Name, ConfigPath)
+	}
+	return c, nil
+}
+
+// containerInfo is the part of docker inspect's output used here
+type containerInfo struct {
+	Name  string
+	State struct {
+		Running bool
+	}
+	Mounts []struct {
+		Source      string
+		Destination string
+	}
+}
+
+func inspect(ctx context.Context, id string) (*containerInfo, error) {
+	output, err := remote.Command(ctx, "", "docker", "inspect", "--type", "container", id).Output()
+	if err != nil {
+		return nil, fmt.Errorf("container %s: %w", id, commandError(err))
+	}
+	var infos []containerInfo
+	if err := json.Unmarshal(output, &infos); err != nil || len(infos) == 0 {
+		return nil, fmt.Errorf("container %s: unexpected docker inspect output", id)
+	}
+	return &infos[0], nil
+}
+
+// devcontainerID finds the container the devcontainer CLI, or an editor,
+// started for the project at root
+func devcontainerID(ctx context.Context, root string) (string, error) {
+	output, err := remote.Command(ctx, "", "docker", "ps", "--quiet",
+		"--filter", "label=devcontainer.local_folder="+root).Output()
+	if err != nil {
+		return "", fmt.Errorf("finding the devcontainer: %w", commandError(err))
+	}
+	id, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	if id == "" {
+		return "", fmt.Errorf("no devcontainer is running for %s, start it with `devcontainer up`", root)
+	}
+	return id, nil
+}
+
+// workspaceFolderPattern finds the workspace folder in devcontainer.json,
+// which allows comments and so can't be read as JSON
+var workspaceFolderPattern = regexp.MustCompile(`"workspaceFolder"\s*:\s*"([^"]+)"`)
+
+// workspaceFolder returns where the devcontainer mounts the project, or ""
+// when devcontainer.json doesn't say and the mounts have to tell
+func workspaceFolder(root string) string {
+	for _, name := range []string{".devcontainer/devcontainer.json", ".devcontainer.json"} {
+		data, err := remote.ReadFile(filepath.Join(root, name))
+		if err != nil {
+			continue
+		}
+		if match := workspaceFolderPattern.FindSubmatch(data); match != nil {
+			folder := string(match[1])
+			return strings.ReplaceAll(folder, "${localWorkspaceFolderBasename}", filepath.Base(root))
+		}
+		return ""
+	}
+	return ""
+}
+
+// commandError adds what a command printed to the error it failed with
+func commandError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
+
+// Label describes the container for the status bar
+func (c *Container) Label() string {
+	return c.Kind + ":" + c.Name
+}
+
+// Dir returns the directory in the container of a directory of the project
+func (c *Container) Dir(dir string) string {
+	if dir == "" {
+		return c.Workdir
+	}
+	rel, err := filepath.Rel(c.Root, dir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return c.Workdir
+	}
+	return path.Join(c.Workdir, filepath.ToSlash(rel))
+}
+
+// execArgs returns the arguments of docker that run a command in dir
+func (c *Container) execArgs(dir string) []string {
+	args := []string{"exec", "-i", "-w", c.Dir(dir)}
+	if c.User != "" {
+		args = append(args, "-u", c.User)
+	}
+	return append(args, c.ID)
+}
+
+// Command prepares a command that runs in a directory of the project, inside
+// the container
+func (c *Container) Command(ctx context.Context, dir, name string, args ...string) *exec.Cmd {
+	return remote.Command(ctx, "", "docker", append(append(c.execArgs(dir), name), args...)...)
+}
+
+// ShellLine returns a command line for a shell on the host that runs line
+// in the container
+func (c *Container) ShellLine(dir, line string) string {
+	words := append(c.execArgs(dir), c.Shell, "-c", line)
+	for i, word := range words {
+		words[i] = remote.Quote(word)
+	}
+	return "docker " + strings.Join(words, " ")
+}
+
+// active is the container commands run in, nil when they run on the host
+var active *Container
+
+// Use makes the functions below run commands in a container, or on the host
+// when c is nil
+func Use(c *Container) {
+	active = c
+}
+
+// Active returns the container commands run in, or nil
+func Active() *Container {
+	return active
+}
+
+// Command prepares a command that runs in a directory of the project
+func Command(ctx context.Context, dir, name string, args ...string) *exec.Cmd {
+	if active == nil {
+		return remote.Command(ctx, dir, name, args...)
+	}
+	return active.Command(ctx, dir, name, args...)
+}
+
+// Shell prepares a shell command line that runs in a directory of the
+// project
+func Shell(ctx context.Context, dir, line string) *exec.Cmd {
+	switch {
+	case active != nil:
+		return active.Command(ctx, dir, active.Shell, "-c", line)
+	case runtime.GOOS == "windows" && remote.Active() == nil:
+		return Command(ctx, dir, "cmd", "/C", line)
+	}
+	return Command(ctx, dir, "sh", "-c", line)
+}
+
+// Interactive prepares a shell that runs in a directory of the project and
+// reads its commands from standard input
+func Interactive(ctx context.Context, dir string) *exec.Cmd {
+	switch {
+	case active != nil:
+		return active.Command(ctx, dir, active.Shell)
+	case runtime.GOOS == "windows" && remote.Active() == nil:
+		return Command(ctx, dir, "cmd", "/Q")
+	}
+	return Command(ctx, dir, "sh")
+}
+
+// ShellLine returns a command line that the server, which runs its shell
+// on the host, runs in the execution target
+func ShellLine(dir, line string) string {
+	if active == nil {
+		return line
+	}
+	return active.ShellLine(dir, line)
+}
+
+// ShellLineAt returns a command line like ShellLine's that runs line in a
+// directory of the project, rather than where the server's shell starts
+func ShellLineAt(dir, line string) string {
+	if active == nil {
+		return "cd " + remote.Quote(dir) + " && " + line
+	}
+	return active.ShellLine(dir, line)
+}
@@ -0,0 +1,94 @@
+package target
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestContainerCommand(t *testing.T) {
+	c := &Container{Kind: KindDocker, ID: "web", Name: "web", Root: "/home/me/app", Workdir: "/workspace", User: "node", Shell: "sh"}
+
+	for dir, want := range map[string]string{
+		"":                      "/workspace",
+		"/home/me/app":          "/workspace",
+		"/home/me/app/pkg/api":  "/workspace/pkg/api",
+		"/home/me/elsewhere":    "/workspace",
+		"/home/me/application2": "/workspace",
+	} {
+		if got := c.Dir(dir); got != want {
+			t.Errorf("Dir(%q) = %q, want %q", dir, got, want)
+		}
+	}
+
+	cmd := c.Command(context.Background(), "/home/me/app/pkg", "go", "test", "./...")
+	want := []string{"docker", "exec", "-i", "-w", "/workspace/pkg", "-u", "node", "web", "go", "test", "./..."}
+	if !slices.Equal(cmd.Args, want) {
+		t.Errorf("Args = %q, want %q", cmd.Args, want)
+	}
+
+	line := c.ShellLine("/home/me/app", "ls -la | grep 'x'")
+	if want := `docker exec -i -w /workspace -u node web sh -c 'ls -la | grep '\''x'\'''`; line != want {
+		t.Errorf("ShellLine = %s, want %s", line, want)
+	}
+}
+
+func TestShellLineOnHost(t *testing.T) {
+	Use(nil)
+	if line := ShellLine("/tmp", "echo hi"); line != "echo hi" {
+		t.Errorf("ShellLine on the host = %q", line)
+	}
+	if line := ShellLineAt("/tmp/my dir", "echo hi"); line != "cd '/tmp/my dir' && echo hi" {
+		t.Errorf("ShellLineAt on the host = %q", line)
+	}
+}
+
+func TestResolveHost(t *testing.T) {
+	for _, config := range []*Config{nil, {}, {Kind: KindHost}} {
+		if c, err := Resolve(context.Background(), "/app", config); c != nil || err != nil {
+			t.Errorf("Resolve(%+v) = %+v, %v; want the host", config, c, err)
+		}
+	}
+	if _, err := Resolve(context.Background(), "/app", &Config{Kind: "vm"}); err == nil {
+		t.Error("an unknown target type was accepted")
+	}
+	if _, err := Resolve(context.Background(), "/app", &Config{Kind: KindDocker}); err == nil {
+		t.Error("a docker target without a container was accepted")
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	root := t.TempDir()
+	if config, err := LoadConfig(root); config != nil || err != nil {
+		t.Fatalf("LoadConfig without a file = %+v, %v", config, err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, ".rycode"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ConfigPath), []byte(`{"type": "docker", "container": "web"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadConfig(root)
+	if err != nil || config.Kind != KindDocker || config.Container != "web" {
+		t.Errorf("LoadConfig = %+v, %v", config, err)
+	}
+}
+
+func TestWorkspaceFolder(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "shop")
+	if err := os.MkdirAll(filepath.Join(root, ".devcontainer"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if folder := workspaceFolder(root); folder != "" {
+		t.Errorf("workspaceFolder without devcontainer.json = %q", folder)
+	}
+	config := "{\n  // Comments are allowed\n  \"workspaceFolder\": \"/workspaces/${localWorkspaceFolderBasename}\",\n}"
+	if err := os.WriteFile(filepath.Join(root, ".devcontainer", "devcontainer.json"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if folder := workspaceFolder(root); folder != "/workspaces/shop" {
+		t.Errorf("workspaceFolder = %q", folder)
+	}
+}
@@ -0,0 +1,329 @@
+// Package testgen locates the tests and coverage of a source file and builds
+// the instructions the agent follows to write tests for its uncovered code.
+package testgen
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Language is a language tests can be generated for
+type Language string
+
+const (
+	LanguageGo         Language = "go"
+	LanguagePython     Language = "python"
+	LanguageTypeScript Language = "typescript" // Also covers JavaScript
+)
+
+// goProfiles are the usual names of go test -coverprofile output
+var goProfiles = []string{"coverage.out", "cover.out", "coverage.txt", "c.out"}
+
+// pythonReports are the usual names of coverage.py JSON reports
+var pythonReports = []string{"coverage.json", ".coverage.json"}
+
+// Target is a source file to test and where its tests live
+type Target struct {
+	Path     string // Source file, relative to the project root
+	TestPath string // Test file, relative to the project root
+	Language Language
+	Command  []string // Runs the tests
+	Dir      string   // Directory the command runs in, relative to the project root
+}
+
+// Resolve finds the test file and test command for a source file. file is
+// absolute or relative to the project root.
+func Resolve(root, file string) (Target, error) {
+	abs := file
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(root, file)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return Target{}, err
+	}
+	if info.IsDir() {
+		return Target{}, fmt.Errorf("%s is a directory; name a source file", file)
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return Target{}, err
+	}
+
+	dir := filepath.Dir(rel)
+	name := filepath.Base(rel)
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+
+	switch ext {
+	case ".go":
+		if strings.HasSuffix(stem, "_test") {
+			return Target{}, fmt.Errorf("%s is already a test file", file)
+		}
+		return Target{
+			Path:     rel,
+			TestPath: filepath.Join(dir, stem+"_test.go"),
+			Language: LanguageGo,
+			Command:  []string{"go", "test", "-cover", "."},
+			Dir:      dir,
+		}, nil
+	case ".py":
+		if strings.HasPrefix(stem, "test_") {
+			return Target{}, fmt.Errorf("%s is already a test file", file)
+		}
+		// Follow the project's tests/ directory when it has one
+		testDir := dir
+		if info, err := os.Stat(filepath.Join(root, "tests")); err == nil && info.IsDir() {
+			testDir = "tests"
+		}
+		testPath := filepath.Join(testDir, "test_"+stem+".py")
+		return Target{
+			Path:     rel,
+			TestPath: testPath,
+			Language: LanguagePython,
+			Command:  []string{"python", "-m", "pytest", testPath},
+			Dir:      ".",
+		}, nil
+	case ".ts", ".tsx", ".js", ".jsx", ".mjs":
+		if strings.HasSuffix(stem, ".test") || strings.HasSuffix(stem, ".spec") {
+			return Target{}, fmt.Errorf("%s is already a test file", file)
+		}
+		testPath := filepath.Join(dir, stem+".test"+ext)
+		return Target{
+			Path:     rel,
+			TestPath: testPath,
+			Language: LanguageTypeScript,
+			Command:  []string{"bun", "test", "./" + filepath.ToSlash(testPath)},
+			Dir:      ".",
+		}, nil
+	}
+	return Target{}, fmt.Errorf("generating tests for %s files is not supported", ext)
+}
+
+// LineRange is an inclusive range of source lines
+type LineRange struct {
+	Start int
+	End   int
+}
+
+func (r LineRange) String() string {
+	if r.Start == r.End {
+		return strconv.Itoa(r.Start)
+	}
+	return fmt.Sprintf("%d-%d", r.Start, r.End)
+}
+
+// Coverage is the coverage of one file taken from an existing report
+type Coverage struct {
+	Report    string  // Report the coverage was read from, relative to the project root
+	Percent   float64 // Share of the file's statements that are covered
+	Uncovered []LineRange
+}
+
+// LoadCoverage reads the coverage of the target from the first coverage
+// report found between its directory and the project root. It returns nil
+// when no report covers the file.
+func LoadCoverage(root string, target Target) (*Coverage, error) {
+	var names []string
+	var parse func(report, file string) (*Coverage, error)
+	switch target.Language {
+	case LanguageGo:
+		names, parse = goProfiles, parseGoProfile
+	case LanguagePython:
+		names, parse = pythonReports, parsePythonReport
+	default:
+		return nil, nil
+	}
+
+	dir := filepath.Dir(target.Path)
+	for {
+		for _, name := range names {
+			report := filepath.Join(root, dir, name)
+			if _, err := os.Stat(report); err != nil {
+				continue
+			}
+			coverage, err := parse(report, target.Path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read coverage report %s: %w", filepath.Join(dir, name), err)
+			}
+			if coverage != nil {
+				coverage.Report = filepath.Join(dir, name)
+				return coverage, nil
+			}
+		}
+		if dir == "." || dir == "" {
+			return nil, nil
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// parseGoProfile reads a go test -coverprofile file. Profiles name files by
+// import path, so they are matched to the target by their trailing path
+// components.
+func parseGoProfile(report, file string) (*Coverage, error) {
+	f, err := os.Open(report)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var total, covered int
+	var uncovered []LineRange
+	found := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "mode:") || line == "" {
+			continue
+		}
+		// name.go:startLine.startCol,endLine.endCol statements count
+		colon := strings.LastIndex(line, ":")
+		if colon < 0 || !sameFile(line[:colon], file) {
+			continue
+		}
+		fields := strings.Fields(line[colon+1:])
+		if len(fields) != 3 {
+			continue
+		}
+		bounds := strings.Split(fields[0], ",")
+		if len(bounds) != 2 {
+			continue
+		}
+		start, _ := strconv.Atoi(strings.Split(bounds[0], ".")[0])
+		end, _ := strconv.Atoi(strings.Split(bounds[1], ".")[0])
+		statements, _ := strconv.Atoi(fields[1])
+		count, _ := strconv.Atoi(fields[2])
+
+		found = true
+		total += statements
+		if count > 0 {
+			covered += statements
+		} else if start > 0 {
+			uncovered = append(uncovered, LineRange{Start: start, End: max(start, end)})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+
+	coverage := &Coverage{Uncovered: mergeRanges(uncovered)}
+	if total > 0 {
+		coverage.Percent = float64(covered) / float64(total) * 100
+	}
+	return coverage, nil
+}
+
+// pythonReport is the part of a coverage.py JSON report that is used
+type pythonReport struct {
+	Files map[string]struct {
+		MissingLines []int `json:"missing_lines"`
+		Summary      struct {
+			PercentCovered float64 `json:"percent_covered"`
+		} `json:"summary"`
+	} `json:"files"`
+}
+
+// parsePythonReport reads a coverage.py JSON report (coverage json)
+func parsePythonReport(report, file string) (*Coverage, error) {
+	data, err := os.ReadFile(report)
+	if err != nil {
+		return nil, err
+	}
+	var parsed pythonReport
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, err
+	}
+	for name, entry := range parsed.Files {
+		if !sameFile(name, file) {
+			continue
+		}
+		var ranges []LineRange
+		for _, line := range entry.MissingLines {
+			ranges = append(ranges, LineRange{Start: line, End: line})
+		}
+		return &Coverage{
+			Percent:   entry.Summary.PercentCovered,
+			Uncovered: mergeRanges(ranges),
+		}, nil
+	}
+	return nil, nil
+}
+
+// sameFile reports whether a file named in a coverage report is the target.
+// Reports name files relative to different roots, so the names must share
+// their trailing components: all of the shorter one, or at least two.
+func sameFile(reported, target string) bool {
+	a := strings.Split(filepath.ToSlash(filepath.Clean(reported)), "/")
+	b := strings.Split(filepath.ToSlash(filepath.Clean(target)), "/")
+	matched := 0
+	for matched < len(a) && matched < len(b) && a[len(a)-1-matched] == b[len(b)-1-matched] {
+		matched++
+	}
+	return matched > 0 && (matched == min(len(a), len(b)) || matched >= 2)
+}
+
+// mergeRanges sorts ranges and joins overlapping or adjacent ones
+func mergeRanges(ranges []LineRange) []LineRange {
+	if len(ranges) == 0 {
+		return nil
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+	merged := []LineRange{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r.Start <= last.End+1 {
+			last.End = max(last.End, r.End)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// maxListedRanges bounds the uncovered ranges spelled out to the agent
+const maxListedRanges = 40
+
+// Instructions tells the agent how to write tests for the target, focusing
+// on its uncovered lines when coverage is known
+func Instructions(target Target, coverage *Coverage) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Write tests for %s in %s.\n\n", target.Path, target.TestPath)
+	b.WriteString("If the test file already exists, add to it and keep the existing tests. ")
+	b.WriteString("Follow the conventions of the project's other tests: framework, naming, helpers and layout. ")
+	b.WriteString("Only edit the test file: do not change the code under test.\n\n")
+
+	switch {
+	case coverage == nil:
+		b.WriteString("No coverage report was found, so cover the file's public behavior, edge cases and error paths.\n")
+	case len(coverage.Uncovered) == 0:
+		fmt.Fprintf(&b, "According to %s the file is fully covered; add tests only for edge cases the existing tests miss.\n", coverage.Report)
+	default:
+		fmt.Fprintf(&b, "According to %s the file is %.1f%% covered. Target these uncovered lines first:\n", coverage.Report, coverage.Percent)
+		ranges := coverage.Uncovered
+		if len(ranges) > maxListedRanges {
+			ranges = ranges[:maxListedRanges]
+		}
+		names := make([]string, 0, len(ranges))
+		for _, r := range ranges {
+			names = append(names, r.String())
+		}
+		b.WriteString(strings.Join(names, ", "))
+		if len(coverage.Uncovered) > maxListedRanges {
+			fmt.Fprintf(&b, " and %d more ranges", len(coverage.Uncovered)-maxListedRanges)
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "\nThe tests are run with `%s`; make sure they pass.\n", strings.Join(target.Command, " "))
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package testgen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "pkg", "calc.go"), "package pkg\n")
+	writeFile(t, filepath.Join(root, "src", "app.ts"), "export {}\n")
+	writeFile(t, filepath.Join(root, "pkg", "calc_test.go"), "package pkg\n")
+
+	target, err := Resolve(root, "pkg/calc.go")
+	if err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+	if target.TestPath != filepath.Join("pkg", "calc_test.go") || target.Dir != "pkg" || target.Language != LanguageGo {
+		t.Errorf("unexpected go target %+v", target)
+	}
+
+	target, err = Resolve(root, filepath.Join(root, "src", "app.ts"))
+	if err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+	if target.TestPath != filepath.Join("src", "app.test.ts") {
+		t.Errorf("unexpected test path %s", target.TestPath)
+	}
+
+	if _, err := Resolve(root, "pkg/calc_test.go"); err == nil {
+		t.Error("expected an error for a test file")
+	}
+}
+
+func TestLoadGoCoverage(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "pkg", "calc.go"), "package pkg\n")
+	writeFile(t, filepath.Join(root, "coverage.out"), strings.Join([]string{
+		"mode: set",
+		"example.com/mod/pkg/calc.go:3.20,5.2 2 1",
+		"example.com/mod/pkg/calc.go:7.20,9.2 1 0",
+		"example.com/mod/pkg/calc.go:9.2,12.3 1 0",
+		"example.com/mod/other/calc.go:1.1,2.2 5 0",
+	}, "\n"))
+
+	target, _ := Resolve(root, "pkg/calc.go")
+	coverage, err := LoadCoverage(root, target)
+	if err != nil || coverage == nil {
+		t.Fatalf("expected coverage, got %v (%v)", coverage, err)
+	}
+	if coverage.Percent != 50 {
+		t.Errorf("expected 50%% coverage, got %.1f", coverage.Percent)
+	}
+	if len(coverage.Uncovered) != 1 || coverage.Uncovered[0] != (LineRange{Start: 7, End: 12}) {
+		t.Errorf("unexpected uncovered ranges %v", coverage.Uncovered)
+	}
+
+	instructions := Instructions(target, coverage)
+	if !strings.Contains(instructions, "7-12") || !strings.Contains(instructions, "go test") {
+		t.Errorf("instructions miss coverage or command:\n%s", instructions)
+	}
+}
+
+func TestLoadPythonCoverage(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "app", "util.py"), "x = 1\n")
+	writeFile(t, filepath.Join(root, "coverage.json"),
+		`{"files": {"app/util.py": {"missing_lines": [4, 5, 9], "summary": {"percent_covered": 62.5}}}}`)
+
+	target, _ := Resolve(root, "app/util.py")
+	coverage, err := LoadCoverage(root, target)
+	if err != nil || coverage == nil {
+		t.Fatalf("expected coverage, got %v (%v)", coverage, err)
+	}
+	if len(coverage.Uncovered) != 2 || coverage.Uncovered[0].String() != "4-5" {
+		t.Errorf("unexpected uncovered ranges %v", coverage.Uncovered)
+	}
+}
+
+func TestLoadCoverageMissingReport(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "main.go"), "package main\n")
+	target, _ := Resolve(root, "main.go")
+	if coverage, err := LoadCoverage(root, target); coverage != nil || err != nil {
+		t.Errorf("expected no coverage, got %v (%v)", coverage, err)
+	}
+}
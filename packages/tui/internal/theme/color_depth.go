@@ -0,0 +1,205 @@
+package theme
+
+import (
+	"image/color"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/lipgloss/v2/compat"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// ColorDepth is how many colors the terminal draws. Themes are truecolor,
+// and on terminals with fewer colors each is drawn as the palette color
+// nearest to it to the eye, rather than the terminal's nearest by value.
+type ColorDepth int
+
+const (
+	DepthTrueColor ColorDepth = iota
+	Depth256
+	Depth16
+)
+
+// ColorDepths are the depths in the order /colors cycles through them
+var ColorDepths = []ColorDepth{DepthTrueColor, Depth256, Depth16}
+
+func (d ColorDepth) String() string {
+	switch d {
+	case Depth256:
+		return "256"
+	case Depth16:
+		return "16"
+	}
+	return "truecolor"
+}
+
+// ParseColorDepth reads a depth as String writes it
+func ParseColorDepth(s string) (ColorDepth, bool) {
+	for _, depth := range ColorDepths {
+		if strings.EqualFold(s, depth.String()) {
+			return depth, true
+		}
+	}
+	return DepthTrueColor, false
+}
+
+// truecolorTerminals are the TERM_PROGRAM values of terminals that draw
+// truecolor without saying so in COLORTERM
+var truecolorTerminals = []string{"iTerm.app", "WezTerm", "vscode", "ghostty", "Hyper", "Tabby"}
+
+// DetectColorDepth finds the colors a terminal draws from its environment,
+// as getenv returns it. COLORTERM is trusted first, then the terminal's
+// name; a TERM ending in 256color only promises 256 colors.
+func DetectColorDepth(getenv func(string) string) ColorDepth {
+	term := getenv("TERM")
+	switch colorterm := strings.ToLower(getenv("COLORTERM")); {
+	case colorterm == "truecolor" || colorterm == "24bit":
+		return DepthTrueColor
+	case slices.Contains(truecolorTerminals, getenv("TERM_PROGRAM")), getenv("WT_SESSION") != "":
+		return DepthTrueColor
+	case strings.HasSuffix(term, "-direct"), strings.HasPrefix(term, "xterm-kitty"), strings.HasPrefix(term, "alacritty"),
+		strings.HasPrefix(term, "foot"), strings.HasPrefix(term, "wezterm"), strings.HasPrefix(term, "xterm-ghostty"):
+		return DepthTrueColor
+	case strings.Contains(term, "256color"), colorterm == "256", getenv("TERM_PROGRAM") == "Apple_Terminal":
+		return Depth256
+	}
+	return Depth16
+}
+
+// colorDepth reduces every color of the current theme to the terminal's
+// palette. The reduced theme is kept until the theme changes, as
+// CurrentTheme is asked for on every draw.
+var colorDepth struct {
+	sync.Mutex
+	depth   ColorDepth
+	base    Theme
+	reduced Theme
+}
+
+// SetColorDepth draws the themes in a terminal's palette, or in their own
+// colors with DepthTrueColor
+func SetColorDepth(depth ColorDepth) {
+	colorDepth.Lock()
+	defer colorDepth.Unlock()
+	colorDepth.depth = depth
+	colorDepth.base, colorDepth.reduced = nil, nil
+}
+
+// CurrentColorDepth returns the depth the themes are drawn in
+func CurrentColorDepth() ColorDepth {
+	colorDepth.Lock()
+	defer colorDepth.Unlock()
+	return colorDepth.depth
+}
+
+// withColorDepth returns base reduced to the color depth, or base in
+// truecolor
+func withColorDepth(base Theme) Theme {
+	colorDepth.Lock()
+	defer colorDepth.Unlock()
+	if colorDepth.depth == DepthTrueColor || base == nil {
+		return base
+	}
+	if colorDepth.reduced != nil && reflect.TypeOf(base).Comparable() && colorDepth.base == base {
+		return colorDepth.reduced
+	}
+	colorDepth.base = base
+	colorDepth.reduced = Reduce(base, colorDepth.depth)
+	return colorDepth.reduced
+}
+
+// Quantize returns the color of a depth's palette nearest to c in CIELAB,
+// which weighs differences as the eye does. In 256 colors the 16 system
+// colors are left out, as terminal themes redefine them. Colors left to
+// the terminal, and palette colors that fit the depth, are kept.
+func Quantize(c color.Color, depth ColorDepth) color.Color {
+	if depth == DepthTrueColor || !checkable(c) {
+		return c
+	}
+	switch c := c.(type) {
+	case ansi.BasicColor:
+		return c
+	case ansi.IndexedColor:
+		if depth == Depth256 {
+			return c
+		}
+		if c < 16 {
+			return ansi.BasicColor(c)
+		}
+	}
+	target, ok := colorful.MakeColor(c)
+	if !ok {
+		return c
+	}
+	first, last := 16, 255
+	if depth == Depth16 {
+		first, last = 0, 15
+	}
+	best, bestDistance := first, -1.0
+	for i := first; i <= last; i++ {
+		distance := target.DistanceLab(paletteColor(i))
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = i, distance
+		}
+	}
+	if depth == Depth16 {
+		return ansi.BasicColor(best)
+	}
+	return ansi.IndexedColor(best)
+}
+
+var palette struct {
+	once   sync.Once
+	colors [256]colorful.Color
+}
+
+// paletteColor returns color i of the xterm palette
+func paletteColor(i int) colorful.Color {
+	palette.once.Do(func() {
+		for n := range palette.colors {
+			palette.colors[n], _ = colorful.MakeColor(ansi.IndexedColor(n))
+		}
+	})
+	return palette.colors[i]
+}
+
+// Reduce returns a theme with every color of base quantized to a depth. In
+// 16 colors the theme's background is left to the terminal, as a color of
+// the palette would rarely match it.
+func Reduce(base Theme, depth ColorDepth) Theme {
+	q := func(c compat.AdaptiveColor) compat.AdaptiveColor {
+		return compat.AdaptiveColor{Dark: Quantize(c.Dark, depth), Light: Quantize(c.Light, depth)}
+	}
+	background := q(base.Background())
+	if depth == Depth16 {
+		background = compat.AdaptiveColor{Dark: lipgloss.NoColor{}, Light: lipgloss.NoColor{}}
+	}
+	colors := colorsOf(base)
+	for _, c := range []*compat.AdaptiveColor{
+		&colors.BackgroundPanelColor, &colors.BackgroundElementColor,
+		&colors.BorderSubtleColor, &colors.BorderColor, &colors.BorderActiveColor,
+		&colors.PrimaryColor, &colors.SecondaryColor, &colors.AccentColor,
+		&colors.TextMutedColor, &colors.TextColor,
+		&colors.ErrorColor, &colors.WarningColor, &colors.SuccessColor, &colors.InfoColor,
+		&colors.DiffAddedColor, &colors.DiffRemovedColor, &colors.DiffContextColor,
+		&colors.DiffHunkHeaderColor, &colors.DiffHighlightAddedColor, &colors.DiffHighlightRemovedColor,
+		&colors.DiffAddedBgColor, &colors.DiffRemovedBgColor, &colors.DiffContextBgColor,
+		&colors.DiffLineNumberColor, &colors.DiffAddedLineNumberBgColor, &colors.DiffRemovedLineNumberBgColor,
+		&colors.MarkdownTextColor, &colors.MarkdownHeadingColor, &colors.MarkdownLinkColor,
+		&colors.MarkdownLinkTextColor, &colors.MarkdownCodeColor, &colors.MarkdownBlockQuoteColor,
+		&colors.MarkdownEmphColor, &colors.MarkdownStrongColor, &colors.MarkdownHorizontalRuleColor,
+		&colors.MarkdownListItemColor, &colors.MarkdownListEnumerationColor, &colors.MarkdownImageColor,
+		&colors.MarkdownImageTextColor, &colors.MarkdownCodeBlockColor,
+		&colors.SyntaxCommentColor, &colors.SyntaxKeywordColor, &colors.SyntaxFunctionColor,
+		&colors.SyntaxVariableColor, &colors.SyntaxStringColor, &colors.SyntaxNumberColor,
+		&colors.SyntaxTypeColor, &colors.SyntaxOperatorColor, &colors.SyntaxPunctuationColor,
+	} {
+		*c = q(*c)
+	}
+	colors.BackgroundColor = background
+	return recolor(base, colors)
+}
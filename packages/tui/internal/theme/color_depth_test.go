@@ -0,0 +1,85 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestDetectColorDepth(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want ColorDepth
+	}{
+		{map[string]string{"COLORTERM": "truecolor", "TERM": "xterm-256color"}, DepthTrueColor},
+		{map[string]string{"TERM": "xterm-256color"}, Depth256},
+		{map[string]string{"TERM": "screen-256color", "TERM_PROGRAM": "WezTerm"}, DepthTrueColor},
+		{map[string]string{"TERM": "xterm-kitty"}, DepthTrueColor},
+		{map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "Apple_Terminal"}, Depth256},
+		{map[string]string{"TERM": "xterm"}, Depth16},
+		{map[string]string{"TERM": "linux"}, Depth16},
+	}
+	for _, test := range tests {
+		if got := DetectColorDepth(func(key string) string { return test.env[key] }); got != test.want {
+			t.Errorf("DetectColorDepth(%v) = %s, want %s", test.env, got, test.want)
+		}
+	}
+}
+
+func TestQuantize(t *testing.T) {
+	tests := []struct {
+		name  string
+		color any
+		depth ColorDepth
+		want  any
+	}{
+		{"truecolor kept", lipgloss.Color("#fab283"), DepthTrueColor, lipgloss.Color("#fab283")},
+		{"palette color in 256", lipgloss.Color("#5f87af"), Depth256, ansi.IndexedColor(67)},
+		{"gray in 256", lipgloss.Color("#1e1e1e"), Depth256, ansi.IndexedColor(234)},
+		{"red in 16", lipgloss.Color("#ff1010"), Depth16, ansi.BasicColor(9)},
+		{"indexed in 16", ansi.IndexedColor(1), Depth16, ansi.BasicColor(1)},
+		{"no color", lipgloss.NoColor{}, Depth16, lipgloss.NoColor{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := test.color.(interface {
+				RGBA() (uint32, uint32, uint32, uint32)
+			})
+			if got := Quantize(c, test.depth); got != test.want {
+				t.Errorf("Quantize = %#v, want %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestReduceLeavesBackgroundToTerminal(t *testing.T) {
+	base := &recoloredTheme{name: "test", BaseTheme: BaseTheme{}}
+	base.BackgroundColor.Dark, base.BackgroundColor.Light = lipgloss.Color("#0a0a0a"), lipgloss.Color("#ffffff")
+	base.TextColor.Dark, base.TextColor.Light = lipgloss.Color("#eeeeee"), lipgloss.Color("#1a1a1a")
+
+	reduced := Reduce(base, Depth16)
+	if _, ok := reduced.Background().Dark.(lipgloss.NoColor); !ok {
+		t.Errorf("background in 16 colors = %#v", reduced.Background().Dark)
+	}
+	if _, ok := reduced.Text().Dark.(ansi.BasicColor); !ok {
+		t.Errorf("text in 16 colors = %#v", reduced.Text().Dark)
+	}
+	if reduced.Name() != "test" {
+		t.Errorf("name = %q", reduced.Name())
+	}
+}
+
+func TestReduceKeepsProviderTheme(t *testing.T) {
+	base := NewGeminiTheme()
+	reduced, ok := Reduce(base, Depth256).(*ProviderTheme)
+	if !ok {
+		t.Fatalf("reduced %s is no longer a provider theme", base.Name())
+	}
+	if reduced.LoadingSpinner != base.LoadingSpinner || reduced.ProviderID != base.ProviderID {
+		t.Errorf("reduced theme lost its provider details: %+v", reduced)
+	}
+	if _, ok := reduced.Primary().Dark.(ansi.IndexedColor); !ok {
+		t.Errorf("primary in 256 colors = %#v", reduced.Primary().Dark)
+	}
+}
@@ -0,0 +1,113 @@
+package theme
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/lipgloss/v2/compat"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// WCAG 2 contrast minimums
+const (
+	ContrastAA      = 4.5 // Normal text, level AA
+	ContrastAALarge = 3.0 // Large or bold text, level AA
+	ContrastAAA     = 7.0 // Normal text, level AAA
+)
+
+// RelativeLuminance returns the WCAG relative luminance of a color, from 0
+// for black to 1 for white. ANSI colors are taken at their xterm values.
+func RelativeLuminance(c color.Color) float64 {
+	r, g, b, _ := c.RGBA()
+	return 0.2126*linearize(r) + 0.7152*linearize(g) + 0.0722*linearize(b)
+}
+
+// linearize converts a 16-bit sRGB component to linear light
+func linearize(component uint32) float64 {
+	v := float64(component) / 0xffff
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// ContrastRatio returns the WCAG contrast ratio of two colors, from 1 for
+// the same luminance to 21 for black on white
+func ContrastRatio(fg, bg color.Color) float64 {
+	l1, l2 := RelativeLuminance(fg), RelativeLuminance(bg)
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+// ContrastRatio256 returns the contrast ratio of two colors once a terminal
+// without truecolor has fallen back to the nearest of its 256 colors
+func ContrastRatio256(fg, bg color.Color) float64 {
+	return ContrastRatio(ansi.Convert256(fg), ansi.Convert256(bg))
+}
+
+// ContrastIssue is a text and background pair of a theme under WCAG AA
+type ContrastIssue struct {
+	Foreground string  // Name of the text color, as in theme files
+	Background string  // Name of the background color
+	Variant    string  // "dark" or "light"
+	Ratio      float64 // Contrast of the pair
+	Fallback   bool    // The pair only fails once reduced to 256 colors
+}
+
+func (i ContrastIssue) String() string {
+	palette := ""
+	if i.Fallback {
+		palette = " in 256 colors"
+	}
+	return fmt.Sprintf("%s on %s (%s) is %.2f:1%s, under the %.1f:1 of WCAG AA", i.Foreground, i.Background, i.Variant, i.Ratio, palette, ContrastAA)
+}
+
+// CheckContrast checks the text colors of a theme against its backgrounds,
+// in both variants and in the 256-color fallback, and returns the pairs
+// under WCAG AA. Backgrounds of "none" are the terminal's own and can't be
+// checked.
+func CheckContrast(t Theme) []ContrastIssue {
+	type named struct {
+		name  string
+		color compat.AdaptiveColor
+	}
+	texts := []named{{"text", t.Text()}, {"textMuted", t.TextMuted()}, {"markdownText", t.MarkdownText()}}
+	backgrounds := []named{{"background", t.Background()}, {"backgroundPanel", t.BackgroundPanel()}, {"backgroundElement", t.BackgroundElement()}}
+
+	var issues []ContrastIssue
+	for _, text := range texts {
+		for _, background := range backgrounds {
+			for _, variant := range []string{"dark", "light"} {
+				fg, bg := text.color.Dark, background.color.Dark
+				if variant == "light" {
+					fg, bg = text.color.Light, background.color.Light
+				}
+				if !checkable(fg) || !checkable(bg) {
+					continue
+				}
+				issue := ContrastIssue{Foreground: text.name, Background: background.name, Variant: variant}
+				if issue.Ratio = ContrastRatio(fg, bg); issue.Ratio < ContrastAA {
+					issues = append(issues, issue)
+				} else if issue.Ratio = ContrastRatio256(fg, bg); issue.Ratio < ContrastAA {
+					issue.Fallback = true
+					issues = append(issues, issue)
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// checkable reports whether a theme color is a color, rather than the
+// terminal's default
+func checkable(c color.Color) bool {
+	if c == nil {
+		return false
+	}
+	_, none := c.(lipgloss.NoColor)
+	return !none
+}
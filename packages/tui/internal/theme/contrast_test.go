@@ -0,0 +1,104 @@
+package theme
+
+import (
+	"math"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/lipgloss/v2/compat"
+)
+
+func TestContrastRatio(t *testing.T) {
+	tests := []struct {
+		fg, bg string
+		want   float64
+	}{
+		{"#000000", "#ffffff", 21},
+		{"#ffffff", "#ffffff", 1},
+		{"#777777", "#ffffff", 4.48},
+		{"15", "0", 21}, // ANSI bright white on black
+		{"231", "16", 21},
+	}
+	for _, tt := range tests {
+		got := ContrastRatio(lipgloss.Color(tt.fg), lipgloss.Color(tt.bg))
+		if math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("ContrastRatio(%s, %s) = %.2f, want %.2f", tt.fg, tt.bg, got, tt.want)
+		}
+	}
+}
+
+func TestCheckContrast(t *testing.T) {
+	same := func(hex string) compat.AdaptiveColor {
+		return compat.AdaptiveColor{Dark: lipgloss.Color(hex), Light: lipgloss.Color(hex)}
+	}
+	none := compat.AdaptiveColor{Dark: lipgloss.NoColor{}, Light: lipgloss.NoColor{}}
+	theme := &LoadedTheme{name: "test", BaseTheme: BaseTheme{
+		TextColor:              same("#eeeeee"),
+		TextMutedColor:         same("#5f5f5f"),
+		MarkdownTextColor:      same("#eeeeee"),
+		BackgroundColor:        same("#111111"),
+		BackgroundPanelColor:   same("#111111"),
+		BackgroundElementColor: none,
+	}}
+	issues := CheckContrast(theme)
+	// Muted text fails on both checked backgrounds, in both variants; the
+	// terminal's own background is skipped
+	if len(issues) != 4 {
+		t.Fatalf("got %d issues, want 4: %v", len(issues), issues)
+	}
+	for _, issue := range issues {
+		if issue.Foreground != "textMuted" || issue.Background == "backgroundElement" || issue.Fallback {
+			t.Errorf("unexpected issue %v", issue)
+		}
+	}
+
+	// A truecolor pair that passes can fail once reduced to 256 colors
+	fg, bg := lipgloss.Color("#009128"), lipgloss.Color("#000000")
+	theme.TextMutedColor = compat.AdaptiveColor{Dark: fg, Light: fg}
+	theme.BackgroundColor = compat.AdaptiveColor{Dark: bg, Light: bg}
+	theme.BackgroundPanelColor = none
+	issues = CheckContrast(theme)
+	if len(issues) != 2 || !issues[0].Fallback {
+		t.Errorf("got %v, want the 256-color fallback of both variants", issues)
+	}
+}
+
+func TestMinimumContrast(t *testing.T) {
+	same := func(hex string) compat.AdaptiveColor {
+		return compat.AdaptiveColor{Dark: lipgloss.Color(hex), Light: lipgloss.Color(hex)}
+	}
+	theme := &LoadedTheme{name: "test", BaseTheme: BaseTheme{
+		TextColor:              same("#eeeeee"),
+		TextMutedColor:         same("#5f5f5f"),
+		BackgroundColor:        same("#111111"),
+		BackgroundPanelColor:   same("#222222"),
+		BackgroundElementColor: compat.AdaptiveColor{Dark: lipgloss.NoColor{}, Light: lipgloss.NoColor{}},
+	}}
+	SetMinimumContrast(ContrastAAA)
+	defer SetMinimumContrast(0)
+	raised := withMinimumContrast(theme)
+	for _, bg := range []string{"#111111", "#222222"} {
+		if ratio := ContrastRatio(raised.TextMuted().Dark, lipgloss.Color(bg)); ratio < ContrastAAA {
+			t.Errorf("raised muted text on %s is %.2f:1", bg, ratio)
+		}
+	}
+	if raised.Text().Dark != theme.Text().Dark {
+		t.Errorf("text that passes was changed to %v", raised.Text().Dark)
+	}
+	if withMinimumContrast(theme) != raised {
+		t.Error("the raised theme was not kept")
+	}
+}
+
+func TestMinimumContrastKeepsProviderTheme(t *testing.T) {
+	SetMinimumContrast(ContrastAAA)
+	defer SetMinimumContrast(0)
+	base := NewClaudeTheme()
+	raised, ok := withMinimumContrast(base).(*ProviderTheme)
+	if !ok {
+		t.Fatalf("raised %s is no longer a provider theme", base.Name())
+	}
+	if raised.LoadingSpinner != base.LoadingSpinner || raised.Name() != base.Name() {
+		t.Errorf("raised theme lost its provider details: %+v", raised)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"image/color"
+	"log/slog"
 	"os"
 	"path"
 	"path/filepath"
@@ -118,6 +119,7 @@ func loadThemesFromDirectory(dir string) error {
 			fmt.Printf("Warning: Failed to parse theme %s: %v\n", filePath, err)
 			continue
 		}
+		warnContrast(filePath, theme)
 
 		RegisterTheme(themeName, theme)
 	}
@@ -125,6 +127,19 @@ func loadThemesFromDirectory(dir string) error {
 	return nil
 }
 
+// warnContrast logs the text and background pairs of a theme under WCAG AA
+func warnContrast(file string, theme Theme) {
+	issues := CheckContrast(theme)
+	if len(issues) == 0 {
+		return
+	}
+	pairs := make([]string, len(issues))
+	for i, issue := range issues {
+		pairs[i] = issue.String()
+	}
+	slog.Warn("Theme text is hard to read", "theme", file, "pairs", strings.Join(pairs, "; "))
+}
+
 func parseJSONTheme(name string, data []byte) (Theme, error) {
 	var jsonTheme JSONTheme
 	if err := json.Unmarshal(data, &jsonTheme); err != nil {
@@ -19,7 +19,7 @@ import (
 type Manager struct {
 	themes               map[string]Theme
 	currentName          string
-	currentUsesAnsiCache bool // Cache whether current theme uses ANSI colors
+	currentUsesAnsiCache bool          // Cache whether current theme uses ANSI colors
 	providerThemes       *ThemeManager // Dynamic provider-specific themes
 	mu                   sync.RWMutex
 }
@@ -46,6 +46,19 @@ func RegisterTheme(name string, theme Theme) {
 	}
 }
 
+// UnregisterTheme removes a theme from the registry. If it was the active
+// theme, no theme is active until another is set.
+func UnregisterTheme(name string) {
+	globalManager.mu.Lock()
+	defer globalManager.mu.Unlock()
+
+	delete(globalManager.themes, name)
+	if globalManager.currentName == name {
+		globalManager.currentName = ""
+		globalManager.currentUsesAnsiCache = false
+	}
+}
+
 // SetTheme changes the active theme to the one with the specified name.
 // Returns an error if the theme doesn't exist.
 func SetTheme(name string) error {
@@ -68,13 +81,19 @@ func SetTheme(name string) error {
 // If a provider theme is active, it returns the provider-specific theme.
 // Otherwise, it returns the registered theme.
 func CurrentTheme() Theme {
+	return withColorDepth(TrueColorTheme())
+}
+
+// TrueColorTheme returns the currently active theme in its own colors,
+// before they are reduced to the terminal's color depth
+func TrueColorTheme() Theme {
 	globalManager.mu.RLock()
 	defer globalManager.mu.RUnlock()
 
 	// Check if provider themes are active
 	if globalManager.providerThemes != nil {
 		if providerTheme := globalManager.providerThemes.Current(); providerTheme != nil {
-			return providerTheme
+			return withMinimumContrast(providerTheme)
 		}
 	}
 
@@ -83,7 +102,7 @@ func CurrentTheme() Theme {
 		return nil
 	}
 
-	return globalManager.themes[globalManager.currentName]
+	return withMinimumContrast(globalManager.themes[globalManager.currentName])
 }
 
 // CurrentThemeName returns the name of the currently active theme.
@@ -0,0 +1,137 @@
+package theme
+
+import (
+	"image/color"
+	"reflect"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss/v2/compat"
+)
+
+// minimumContrast raises the text colors of the current theme to a contrast
+// ratio against its backgrounds, for low vision. The raised theme is kept
+// until the theme changes, as CurrentTheme is asked for on every draw.
+var minimumContrast struct {
+	sync.Mutex
+	ratio  float64
+	base   Theme
+	raised Theme
+}
+
+// SetMinimumContrast raises the text colors of every theme to at least a
+// contrast ratio against its backgrounds, as ContrastAAA, or draws the
+// themes' own colors again with 0
+func SetMinimumContrast(ratio float64) {
+	minimumContrast.Lock()
+	defer minimumContrast.Unlock()
+	minimumContrast.ratio = ratio
+	minimumContrast.base, minimumContrast.raised = nil, nil
+}
+
+// MinimumContrast returns the contrast ratio text is raised to, 0 when the
+// themes' own colors are drawn
+func MinimumContrast() float64 {
+	minimumContrast.Lock()
+	defer minimumContrast.Unlock()
+	return minimumContrast.ratio
+}
+
+// withMinimumContrast returns a theme with the text colors of base raised to
+// the minimum contrast, or base when there is none
+func withMinimumContrast(base Theme) Theme {
+	minimumContrast.Lock()
+	defer minimumContrast.Unlock()
+	if minimumContrast.ratio == 0 || base == nil {
+		return base
+	}
+	if minimumContrast.raised != nil && reflect.TypeOf(base).Comparable() && minimumContrast.base == base {
+		return minimumContrast.raised
+	}
+	minimumContrast.base = base
+	minimumContrast.raised = newContrastTheme(base, minimumContrast.ratio)
+	return minimumContrast.raised
+}
+
+// newContrastTheme returns base with its text colors raised to a contrast
+// ratio against its backgrounds
+func newContrastTheme(base Theme, ratio float64) Theme {
+	backgrounds := []compat.AdaptiveColor{base.Background(), base.BackgroundPanel(), base.BackgroundElement()}
+	raise := func(c *compat.AdaptiveColor) {
+		var dark, light []color.Color
+		for _, background := range backgrounds {
+			dark = append(dark, background.Dark)
+			light = append(light, background.Light)
+		}
+		*c = compat.AdaptiveColor{
+			Dark:  raiseContrast(c.Dark, dark, ratio),
+			Light: raiseContrast(c.Light, light, ratio),
+		}
+	}
+	colors := colorsOf(base)
+	for _, c := range []*compat.AdaptiveColor{
+		&colors.PrimaryColor, &colors.SecondaryColor, &colors.AccentColor,
+		&colors.TextMutedColor, &colors.TextColor,
+		&colors.ErrorColor, &colors.WarningColor, &colors.SuccessColor, &colors.InfoColor,
+		&colors.MarkdownTextColor, &colors.MarkdownHeadingColor, &colors.MarkdownLinkColor,
+		&colors.MarkdownLinkTextColor, &colors.MarkdownCodeColor, &colors.MarkdownBlockQuoteColor,
+		&colors.MarkdownEmphColor, &colors.MarkdownStrongColor, &colors.MarkdownListItemColor,
+		&colors.MarkdownListEnumerationColor,
+		&colors.SyntaxCommentColor, &colors.SyntaxKeywordColor, &colors.SyntaxFunctionColor,
+		&colors.SyntaxVariableColor, &colors.SyntaxStringColor, &colors.SyntaxNumberColor,
+		&colors.SyntaxTypeColor, &colors.SyntaxOperatorColor, &colors.SyntaxPunctuationColor,
+	} {
+		raise(c)
+	}
+	return recolor(base, colors)
+}
+
+// raiseContrast blends a color towards white on dark backgrounds, or black
+// on light ones, until it has the contrast ratio against each. Colors left
+// to the terminal are kept, as their contrast can't be known.
+func raiseContrast(fg color.Color, backgrounds []color.Color, ratio float64) color.Color {
+	if !checkable(fg) {
+		return fg
+	}
+	var checked []color.Color
+	lightest := 0.0
+	for _, background := range backgrounds {
+		if checkable(background) {
+			checked = append(checked, background)
+			lightest = max(lightest, RelativeLuminance(background))
+		}
+	}
+	if len(checked) == 0 {
+		return fg
+	}
+	target := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	if lightest > 0.18 {
+		target = color.RGBA{A: 0xff}
+	}
+	for step := 0; step <= 20; step++ {
+		c := fg
+		if step > 0 {
+			c = blend(fg, target, float64(step)/20)
+		}
+		enough := true
+		for _, background := range checked {
+			if ContrastRatio(c, background) < ratio {
+				enough = false
+				break
+			}
+		}
+		if enough {
+			return c
+		}
+	}
+	return target
+}
+
+// blend mixes a share of to into from
+func blend(from, to color.Color, share float64) color.Color {
+	r1, g1, b1, _ := from.RGBA()
+	r2, g2, b2, _ := to.RGBA()
+	mix := func(a, b uint32) uint8 {
+		return uint8((float64(a)*(1-share) + float64(b)*share) / 0x101)
+	}
+	return color.RGBA{R: mix(r1, r2), G: mix(g1, g2), B: mix(b1, b2), A: 0xff}
+}
@@ -22,9 +22,9 @@ type ProviderTheme struct {
 
 // TypingIndicatorStyle defines how the "thinking" indicator appears
 type TypingIndicatorStyle struct {
-	Text      string // "Thinking..." or "Processing..."
-	Animation string // "dots", "gradient", "pulse", "wave"
-	UseGradient bool // Use gradient animation for Gemini
+	Text        string // "Thinking..." or "Processing..."
+	Animation   string // "dots", "gradient", "pulse", "wave"
+	UseGradient bool   // Use gradient animation for Gemini
 }
 
 // Name returns the theme name (implements Theme interface)
@@ -66,16 +66,16 @@ func NewClaudeTheme() *ProviderTheme {
 			InfoColor:    adaptiveColor("#D4754C", "#D4754C"), // Use primary
 
 			// Diff colors
-			DiffAddedColor:            adaptiveColor("#6FA86F", "#E6F4E6"),
-			DiffRemovedColor:          adaptiveColor("#D47C7C", "#FFEAEA"),
-			DiffContextColor:          adaptiveColor("#9C8373", "#6B5E52"),
-			DiffHunkHeaderColor:       adaptiveColor("#D4754C", "#D4754C"),
-			DiffHighlightAddedColor:   adaptiveColor("#4A8A4A", "#2D6B2D"),
-			DiffHighlightRemovedColor: adaptiveColor("#B84444", "#8B2222"),
-			DiffAddedBgColor:          adaptiveColor("#2A3A2A", "#E6F4E6"),
-			DiffRemovedBgColor:        adaptiveColor("#3A2A2A", "#FFEAEA"),
-			DiffContextBgColor:        adaptiveColor("#1A1816", "#F5F2EE"),
-			DiffLineNumberColor:       adaptiveColor("#9C8373", "#6B5E52"),
+			DiffAddedColor:               adaptiveColor("#6FA86F", "#E6F4E6"),
+			DiffRemovedColor:             adaptiveColor("#D47C7C", "#FFEAEA"),
+			DiffContextColor:             adaptiveColor("#9C8373", "#6B5E52"),
+			DiffHunkHeaderColor:          adaptiveColor("#D4754C", "#D4754C"),
+			DiffHighlightAddedColor:      adaptiveColor("#4A8A4A", "#2D6B2D"),
+			DiffHighlightRemovedColor:    adaptiveColor("#B84444", "#8B2222"),
+			DiffAddedBgColor:             adaptiveColor("#2A3A2A", "#E6F4E6"),
+			DiffRemovedBgColor:           adaptiveColor("#3A2A2A", "#FFEAEA"),
+			DiffContextBgColor:           adaptiveColor("#1A1816", "#F5F2EE"),
+			DiffLineNumberColor:          adaptiveColor("#9C8373", "#6B5E52"),
 			DiffAddedLineNumberBgColor:   adaptiveColor("#2A3A2A", "#C8E6C8"),
 			DiffRemovedLineNumberBgColor: adaptiveColor("#3A2A2A", "#FFCCCC"),
 
@@ -120,7 +120,7 @@ func NewClaudeTheme() *ProviderTheme {
     ░                                    ░            `,
 
 		WelcomeMessage: "Welcome to Claude! I'm here to help you build amazing things.",
-		LoadingSpinner:  "⣾⣽⣻⢿⡿⣟⣯⣷", // Braille spinner
+		LoadingSpinner: "⣾⣽⣻⢿⡿⣟⣯⣷", // Braille spinner
 
 		TypingIndicator: TypingIndicatorStyle{
 			Text:      "Thinking",
@@ -163,16 +163,16 @@ func NewGeminiTheme() *ProviderTheme {
 			InfoColor:    adaptiveColor("#4285F4", "#1A73E8"),
 
 			// Diff colors
-			DiffAddedColor:            adaptiveColor("#34A853", "#E6F4EA"),
-			DiffRemovedColor:          adaptiveColor("#EA4335", "#FCE8E6"),
-			DiffContextColor:          adaptiveColor("#9AA0A6", "#5F6368"),
-			DiffHunkHeaderColor:       adaptiveColor("#4285F4", "#4285F4"),
-			DiffHighlightAddedColor:   adaptiveColor("#1E8E3E", "#137333"),
-			DiffHighlightRemovedColor: adaptiveColor("#C5221F", "#A50E0E"),
-			DiffAddedBgColor:          adaptiveColor("#1A2A1A", "#E6F4EA"),
-			DiffRemovedBgColor:        adaptiveColor("#2A1A1A", "#FCE8E6"),
-			DiffContextBgColor:        adaptiveColor("#0D0D0D", "#FFFFFF"),
-			DiffLineNumberColor:       adaptiveColor("#9AA0A6", "#5F6368"),
+			DiffAddedColor:               adaptiveColor("#34A853", "#E6F4EA"),
+			DiffRemovedColor:             adaptiveColor("#EA4335", "#FCE8E6"),
+			DiffContextColor:             adaptiveColor("#9AA0A6", "#5F6368"),
+			DiffHunkHeaderColor:          adaptiveColor("#4285F4", "#4285F4"),
+			DiffHighlightAddedColor:      adaptiveColor("#1E8E3E", "#137333"),
+			DiffHighlightRemovedColor:    adaptiveColor("#C5221F", "#A50E0E"),
+			DiffAddedBgColor:             adaptiveColor("#1A2A1A", "#E6F4EA"),
+			DiffRemovedBgColor:           adaptiveColor("#2A1A1A", "#FCE8E6"),
+			DiffContextBgColor:           adaptiveColor("#0D0D0D", "#FFFFFF"),
+			DiffLineNumberColor:          adaptiveColor("#9AA0A6", "#5F6368"),
 			DiffAddedLineNumberBgColor:   adaptiveColor("#1A2A1A", "#CEEAD6"),
 			DiffRemovedLineNumberBgColor: adaptiveColor("#2A1A1A", "#F9DEDC"),
 
@@ -213,7 +213,7 @@ func NewGeminiTheme() *ProviderTheme {
      ╚═════╝ ╚══════╝╚═╝     ╚═╝╚═╝╚═╝  ╚═══╝╚═╝`,
 
 		WelcomeMessage: "Welcome to Gemini! Let's explore possibilities together.",
-		LoadingSpinner:  "◐◓◑◒", // Circle spinner
+		LoadingSpinner: "◐◓◑◒", // Circle spinner
 
 		TypingIndicator: TypingIndicatorStyle{
 			Text:        "Thinking",
@@ -257,16 +257,16 @@ func NewCodexTheme() *ProviderTheme {
 			InfoColor:    adaptiveColor("#3B82F6", "#2563EB"),
 
 			// Diff colors
-			DiffAddedColor:            adaptiveColor("#10A37F", "#D1FAE5"),
-			DiffRemovedColor:          adaptiveColor("#EF4444", "#FEE2E2"),
-			DiffContextColor:          adaptiveColor("#8E8E8E", "#6E6E80"),
-			DiffHunkHeaderColor:       adaptiveColor("#10A37F", "#10A37F"),
-			DiffHighlightAddedColor:   adaptiveColor("#0D8569", "#059669"),
-			DiffHighlightRemovedColor: adaptiveColor("#DC2626", "#B91C1C"),
-			DiffAddedBgColor:          adaptiveColor("#1A2D28", "#D1FAE5"),
-			DiffRemovedBgColor:        adaptiveColor("#2D1A1A", "#FEE2E2"),
-			DiffContextBgColor:        adaptiveColor("#0E0E0E", "#FFFFFF"),
-			DiffLineNumberColor:       adaptiveColor("#8E8E8E", "#6E6E80"),
+			DiffAddedColor:               adaptiveColor("#10A37F", "#D1FAE5"),
+			DiffRemovedColor:             adaptiveColor("#EF4444", "#FEE2E2"),
+			DiffContextColor:             adaptiveColor("#8E8E8E", "#6E6E80"),
+			DiffHunkHeaderColor:          adaptiveColor("#10A37F", "#10A37F"),
+			DiffHighlightAddedColor:      adaptiveColor("#0D8569", "#059669"),
+			DiffHighlightRemovedColor:    adaptiveColor("#DC2626", "#B91C1C"),
+			DiffAddedBgColor:             adaptiveColor("#1A2D28", "#D1FAE5"),
+			DiffRemovedBgColor:           adaptiveColor("#2D1A1A", "#FEE2E2"),
+			DiffContextBgColor:           adaptiveColor("#0E0E0E", "#FFFFFF"),
+			DiffLineNumberColor:          adaptiveColor("#8E8E8E", "#6E6E80"),
 			DiffAddedLineNumberBgColor:   adaptiveColor("#1A2D28", "#A7F3D0"),
 			DiffRemovedLineNumberBgColor: adaptiveColor("#2D1A1A", "#FECACA"),
 
@@ -307,7 +307,7 @@ func NewCodexTheme() *ProviderTheme {
      ╚═════╝ ╚═════╝ ╚═════╝ ╚══════╝╚═╝  ╚═╝`,
 
 		WelcomeMessage: "Welcome to Codex. Let's build something extraordinary.",
-		LoadingSpinner:  "⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏", // Line spinner
+		LoadingSpinner: "⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏", // Line spinner
 
 		TypingIndicator: TypingIndicatorStyle{
 			Text:      "Processing",
@@ -350,16 +350,16 @@ func NewQwenTheme() *ProviderTheme {
 			InfoColor:    adaptiveColor("#1890FF", "#096DD9"),
 
 			// Diff colors
-			DiffAddedColor:            adaptiveColor("#52C41A", "#F6FFED"),
-			DiffRemovedColor:          adaptiveColor("#FF4D4F", "#FFF1F0"),
-			DiffContextColor:          adaptiveColor("#A0947C", "#6B5E52"),
-			DiffHunkHeaderColor:       adaptiveColor("#FF6A00", "#FF6A00"),
-			DiffHighlightAddedColor:   adaptiveColor("#389E0D", "#237804"),
-			DiffHighlightRemovedColor: adaptiveColor("#CF1322", "#A8071A"),
-			DiffAddedBgColor:          adaptiveColor("#1A2A1A", "#F6FFED"),
-			DiffRemovedBgColor:        adaptiveColor("#2A1A1A", "#FFF1F0"),
-			DiffContextBgColor:        adaptiveColor("#161410", "#FFFBF5"),
-			DiffLineNumberColor:       adaptiveColor("#A0947C", "#6B5E52"),
+			DiffAddedColor:               adaptiveColor("#52C41A", "#F6FFED"),
+			DiffRemovedColor:             adaptiveColor("#FF4D4F", "#FFF1F0"),
+			DiffContextColor:             adaptiveColor("#A0947C", "#6B5E52"),
+			DiffHunkHeaderColor:          adaptiveColor("#FF6A00", "#FF6A00"),
+			DiffHighlightAddedColor:      adaptiveColor("#389E0D", "#237804"),
+			DiffHighlightRemovedColor:    adaptiveColor("#CF1322", "#A8071A"),
+			DiffAddedBgColor:             adaptiveColor("#1A2A1A", "#F6FFED"),
+			DiffRemovedBgColor:           adaptiveColor("#2A1A1A", "#FFF1F0"),
+			DiffContextBgColor:           adaptiveColor("#161410", "#FFFBF5"),
+			DiffLineNumberColor:          adaptiveColor("#A0947C", "#6B5E52"),
 			DiffAddedLineNumberBgColor:   adaptiveColor("#1A2A1A", "#D9F7BE"),
 			DiffRemovedLineNumberBgColor: adaptiveColor("#2A1A1A", "#FFCCC7"),
 
@@ -400,7 +400,7 @@ func NewQwenTheme() *ProviderTheme {
      ╚══▀▀═╝  ╚══╝╚══╝ ╚══════╝╚═╝  ╚═══╝`,
 
 		WelcomeMessage: "Welcome to Qwen! Ready to innovate together.",
-		LoadingSpinner:  "⣾⣽⣻⢿⡿⣟⣯⣷", // Braille spinner
+		LoadingSpinner: "⣾⣽⣻⢿⡿⣟⣯⣷", // Braille spinner
 
 		TypingIndicator: TypingIndicatorStyle{
 			Text:      "Thinking",
@@ -409,6 +409,192 @@ func NewQwenTheme() *ProviderTheme {
 	}
 }
 
+// NewAzureTheme creates the Azure OpenAI (Microsoft) provider theme
+// Based on Azure's clean Fluent aesthetic
+func NewAzureTheme() *ProviderTheme {
+	return &ProviderTheme{
+		ProviderID:   "azure",
+		ProviderName: "Azure OpenAI",
+
+		BaseTheme: BaseTheme{
+			// Primary accents - Azure blue
+			PrimaryColor:   adaptiveColor("#0078D4", "#0078D4"), // Azure blue
+			SecondaryColor: adaptiveColor("#005A9E", "#005A9E"), // Darker blue
+			AccentColor:    adaptiveColor("#50E6FF", "#0F6CBD"), // Azure cyan
+
+			// Background - cool dark tones
+			BackgroundColor:        adaptiveColor("#0F1419", "#FAFAFA"),
+			BackgroundPanelColor:   adaptiveColor("#1B2229", "#FFFFFF"),
+			BackgroundElementColor: adaptiveColor("#252E38", "#F0F4F8"),
+
+			// Borders - blue accent
+			BorderSubtleColor: adaptiveColor("#2F3B47", "#C7D7E8"),
+			BorderColor:       adaptiveColor("#0078D4", "#0078D4"), // Azure blue
+			BorderActiveColor: adaptiveColor("#50E6FF", "#005A9E"), // Cyan on focus
+
+			// Text - neutral with cool tint
+			TextColor:      adaptiveColor("#E1E8F0", "#1B2229"), // Cool off-white / dark
+			TextMutedColor: adaptiveColor("#8A9BAE", "#5C6B7A"), // Cool gray
+
+			// Status colors - Fluent palette
+			ErrorColor:   adaptiveColor("#F1707B", "#C50F1F"),
+			WarningColor: adaptiveColor("#FCE100", "#BC4B09"),
+			SuccessColor: adaptiveColor("#6CCB5F", "#107C10"),
+			InfoColor:    adaptiveColor("#0078D4", "#0078D4"), // Use primary
+
+			// Diff colors
+			DiffAddedColor:               adaptiveColor("#6CCB5F", "#E9F5E9"),
+			DiffRemovedColor:             adaptiveColor("#F1707B", "#FDE7E9"),
+			DiffContextColor:             adaptiveColor("#8A9BAE", "#5C6B7A"),
+			DiffHunkHeaderColor:          adaptiveColor("#0078D4", "#0078D4"),
+			DiffHighlightAddedColor:      adaptiveColor("#107C10", "#0B5A0B"),
+			DiffHighlightRemovedColor:    adaptiveColor("#C50F1F", "#8E0B16"),
+			DiffAddedBgColor:             adaptiveColor("#1A2D1E", "#E9F5E9"),
+			DiffRemovedBgColor:           adaptiveColor("#2D1A1E", "#FDE7E9"),
+			DiffContextBgColor:           adaptiveColor("#0F1419", "#FAFAFA"),
+			DiffLineNumberColor:          adaptiveColor("#8A9BAE", "#5C6B7A"),
+			DiffAddedLineNumberBgColor:   adaptiveColor("#1A2D1E", "#CDEBCD"),
+			DiffRemovedLineNumberBgColor: adaptiveColor("#2D1A1E", "#F9CDD1"),
+
+			// Markdown colors
+			MarkdownTextColor:            adaptiveColor("#E1E8F0", "#1B2229"),
+			MarkdownHeadingColor:         adaptiveColor("#50E6FF", "#005A9E"),
+			MarkdownLinkColor:            adaptiveColor("#0078D4", "#0078D4"),
+			MarkdownLinkTextColor:        adaptiveColor("#50E6FF", "#0F6CBD"),
+			MarkdownCodeColor:            adaptiveColor("#FCE100", "#BC4B09"),
+			MarkdownBlockQuoteColor:      adaptiveColor("#8A9BAE", "#5C6B7A"),
+			MarkdownEmphColor:            adaptiveColor("#E1E8F0", "#1B2229"),
+			MarkdownStrongColor:          adaptiveColor("#50E6FF", "#005A9E"),
+			MarkdownHorizontalRuleColor:  adaptiveColor("#2F3B47", "#C7D7E8"),
+			MarkdownListItemColor:        adaptiveColor("#0078D4", "#0078D4"),
+			MarkdownListEnumerationColor: adaptiveColor("#8A9BAE", "#5C6B7A"),
+			MarkdownImageColor:           adaptiveColor("#50E6FF", "#0F6CBD"),
+			MarkdownImageTextColor:       adaptiveColor("#8A9BAE", "#5C6B7A"),
+			MarkdownCodeBlockColor:       adaptiveColor("#FCE100", "#BC4B09"),
+
+			// Syntax highlighting - Fluent colors
+			SyntaxCommentColor:     adaptiveColor("#8A9BAE", "#5C6B7A"),
+			SyntaxKeywordColor:     adaptiveColor("#0078D4", "#005A9E"),
+			SyntaxFunctionColor:    adaptiveColor("#50E6FF", "#0F6CBD"),
+			SyntaxVariableColor:    adaptiveColor("#E1E8F0", "#1B2229"),
+			SyntaxStringColor:      adaptiveColor("#6CCB5F", "#107C10"),
+			SyntaxNumberColor:      adaptiveColor("#FCE100", "#BC4B09"),
+			SyntaxTypeColor:        adaptiveColor("#B4A0FF", "#5C2E91"),
+			SyntaxOperatorColor:    adaptiveColor("#50E6FF", "#0078D4"),
+			SyntaxPunctuationColor: adaptiveColor("#8A9BAE", "#5C6B7A"),
+		},
+
+		LogoASCII: `
+     █████╗ ███████╗██╗   ██╗██████╗ ███████╗
+    ██╔══██╗╚══███╔╝██║   ██║██╔══██╗██╔════╝
+    ███████║  ███╔╝ ██║   ██║██████╔╝█████╗
+    ██╔══██║ ███╔╝  ██║   ██║██╔══██╗██╔══╝
+    ██║  ██║███████╗╚██████╔╝██║  ██║███████╗
+    ╚═╝  ╚═╝╚══════╝ ╚═════╝ ╚═╝  ╚═╝╚══════╝`,
+
+		WelcomeMessage: "Welcome to Azure OpenAI! Your deployments are ready.",
+		LoadingSpinner: "⣾⣽⣻⢿⡿⣟⣯⣷", // Braille spinner
+
+		TypingIndicator: TypingIndicatorStyle{
+			Text:      "Thinking",
+			Animation: "pulse",
+		},
+	}
+}
+
+// NewBedrockTheme creates the AWS Bedrock (Amazon) provider theme
+// Based on the AWS console's squid ink and orange aesthetic
+func NewBedrockTheme() *ProviderTheme {
+	return &ProviderTheme{
+		ProviderID:   "amazon-bedrock",
+		ProviderName: "AWS Bedrock",
+
+		BaseTheme: BaseTheme{
+			// Primary accents - AWS orange
+			PrimaryColor:   adaptiveColor("#FF9900", "#EC7211"), // AWS orange
+			SecondaryColor: adaptiveColor("#EC7211", "#C45500"), // Darker orange
+			AccentColor:    adaptiveColor("#FFB84D", "#FF9900"), // Lighter orange
+
+			// Background - squid ink
+			BackgroundColor:        adaptiveColor("#161E2D", "#FAFAFA"), // Deep squid ink
+			BackgroundPanelColor:   adaptiveColor("#232F3E", "#FFFFFF"), // Squid ink
+			BackgroundElementColor: adaptiveColor("#2F3D4F", "#F2F3F3"),
+
+			// Borders - orange accent
+			BorderSubtleColor: adaptiveColor("#3B4A5C", "#D5DBDB"),
+			BorderColor:       adaptiveColor("#FF9900", "#EC7211"), // Orange
+			BorderActiveColor: adaptiveColor("#FFB84D", "#C45500"), // Bright orange
+
+			// Text - neutral
+			TextColor:      adaptiveColor("#EAEDED", "#16191F"), // Off-white / dark
+			TextMutedColor: adaptiveColor("#95A5A6", "#545B64"), // Gray
+
+			// Status colors - AWS console palette
+			ErrorColor:   adaptiveColor("#FF5D64", "#D13212"),
+			WarningColor: adaptiveColor("#FFB84D", "#B7740F"),
+			SuccessColor: adaptiveColor("#6ACC8F", "#1D8102"),
+			InfoColor:    adaptiveColor("#44B9D6", "#0073BB"),
+
+			// Diff colors
+			DiffAddedColor:               adaptiveColor("#6ACC8F", "#F2F8F0"),
+			DiffRemovedColor:             adaptiveColor("#FF5D64", "#FDF3F1"),
+			DiffContextColor:             adaptiveColor("#95A5A6", "#545B64"),
+			DiffHunkHeaderColor:          adaptiveColor("#FF9900", "#EC7211"),
+			DiffHighlightAddedColor:      adaptiveColor("#1D8102", "#156200"),
+			DiffHighlightRemovedColor:    adaptiveColor("#D13212", "#A0250D"),
+			DiffAddedBgColor:             adaptiveColor("#1C3328", "#F2F8F0"),
+			DiffRemovedBgColor:           adaptiveColor("#3A2228", "#FDF3F1"),
+			DiffContextBgColor:           adaptiveColor("#161E2D", "#FAFAFA"),
+			DiffLineNumberColor:          adaptiveColor("#95A5A6", "#545B64"),
+			DiffAddedLineNumberBgColor:   adaptiveColor("#1C3328", "#D3EBCB"),
+			DiffRemovedLineNumberBgColor: adaptiveColor("#3A2228", "#F9D3CB"),
+
+			// Markdown colors
+			MarkdownTextColor:            adaptiveColor("#EAEDED", "#16191F"),
+			MarkdownHeadingColor:         adaptiveColor("#FF9900", "#EC7211"),
+			MarkdownLinkColor:            adaptiveColor("#44B9D6", "#0073BB"),
+			MarkdownLinkTextColor:        adaptiveColor("#FFB84D", "#EC7211"),
+			MarkdownCodeColor:            adaptiveColor("#FFB84D", "#B7740F"),
+			MarkdownBlockQuoteColor:      adaptiveColor("#95A5A6", "#545B64"),
+			MarkdownEmphColor:            adaptiveColor("#EAEDED", "#16191F"),
+			MarkdownStrongColor:          adaptiveColor("#FF9900", "#EC7211"),
+			MarkdownHorizontalRuleColor:  adaptiveColor("#3B4A5C", "#D5DBDB"),
+			MarkdownListItemColor:        adaptiveColor("#FF9900", "#EC7211"),
+			MarkdownListEnumerationColor: adaptiveColor("#95A5A6", "#545B64"),
+			MarkdownImageColor:           adaptiveColor("#44B9D6", "#0073BB"),
+			MarkdownImageTextColor:       adaptiveColor("#95A5A6", "#545B64"),
+			MarkdownCodeBlockColor:       adaptiveColor("#FFB84D", "#B7740F"),
+
+			// Syntax highlighting - console colors
+			SyntaxCommentColor:     adaptiveColor("#95A5A6", "#545B64"),
+			SyntaxKeywordColor:     adaptiveColor("#FF9900", "#C45500"),
+			SyntaxFunctionColor:    adaptiveColor("#44B9D6", "#0073BB"),
+			SyntaxVariableColor:    adaptiveColor("#EAEDED", "#16191F"),
+			SyntaxStringColor:      adaptiveColor("#6ACC8F", "#1D8102"),
+			SyntaxNumberColor:      adaptiveColor("#FF5D64", "#D13212"),
+			SyntaxTypeColor:        adaptiveColor("#FFB84D", "#EC7211"),
+			SyntaxOperatorColor:    adaptiveColor("#FF9900", "#C45500"),
+			SyntaxPunctuationColor: adaptiveColor("#95A5A6", "#545B64"),
+		},
+
+		LogoASCII: `
+    ██████╗ ███████╗██████╗ ██████╗  ██████╗  ██████╗██╗  ██╗
+    ██╔══██╗██╔════╝██╔══██╗██╔══██╗██╔═══██╗██╔════╝██║ ██╔╝
+    ██████╔╝█████╗  ██║  ██║██████╔╝██║   ██║██║     █████╔╝
+    ██╔══██╗██╔══╝  ██║  ██║██╔══██╗██║   ██║██║     ██╔═██╗
+    ██████╔╝███████╗██████╔╝██║  ██║╚██████╔╝╚██████╗██║  ██╗
+    ╚═════╝ ╚══════╝╚═════╝ ╚═╝  ╚═╝ ╚═════╝  ╚═════╝╚═╝  ╚═╝`,
+
+		WelcomeMessage: "Welcome to AWS Bedrock! Foundation models, your account.",
+		LoadingSpinner: "⣾⣽⣻⢿⡿⣟⣯⣷", // Braille spinner
+
+		TypingIndicator: TypingIndicatorStyle{
+			Text:      "Thinking",
+			Animation: "dots",
+		},
+	}
+}
+
 // adaptiveColor creates an AdaptiveColor from light/dark hex values
 func adaptiveColor(dark, light string) compat.AdaptiveColor {
 	return compat.AdaptiveColor{
@@ -0,0 +1,84 @@
+package theme
+
+// colorsOf returns every color of a theme
+func colorsOf(t Theme) BaseTheme {
+	return BaseTheme{
+		BackgroundColor:              t.Background(),
+		BackgroundPanelColor:         t.BackgroundPanel(),
+		BackgroundElementColor:       t.BackgroundElement(),
+		BorderSubtleColor:            t.BorderSubtle(),
+		BorderColor:                  t.Border(),
+		BorderActiveColor:            t.BorderActive(),
+		PrimaryColor:                 t.Primary(),
+		SecondaryColor:               t.Secondary(),
+		AccentColor:                  t.Accent(),
+		TextMutedColor:               t.TextMuted(),
+		TextColor:                    t.Text(),
+		ErrorColor:                   t.Error(),
+		WarningColor:                 t.Warning(),
+		SuccessColor:                 t.Success(),
+		InfoColor:                    t.Info(),
+		DiffAddedColor:               t.DiffAdded(),
+		DiffRemovedColor:             t.DiffRemoved(),
+		DiffContextColor:             t.DiffContext(),
+		DiffHunkHeaderColor:          t.DiffHunkHeader(),
+		DiffHighlightAddedColor:      t.DiffHighlightAdded(),
+		DiffHighlightRemovedColor:    t.DiffHighlightRemoved(),
+		DiffAddedBgColor:             t.DiffAddedBg(),
+		DiffRemovedBgColor:           t.DiffRemovedBg(),
+		DiffContextBgColor:           t.DiffContextBg(),
+		DiffLineNumberColor:          t.DiffLineNumber(),
+		DiffAddedLineNumberBgColor:   t.DiffAddedLineNumberBg(),
+		DiffRemovedLineNumberBgColor: t.DiffRemovedLineNumberBg(),
+		MarkdownTextColor:            t.MarkdownText(),
+		MarkdownHeadingColor:         t.MarkdownHeading(),
+		MarkdownLinkColor:            t.MarkdownLink(),
+		MarkdownLinkTextColor:        t.MarkdownLinkText(),
+		MarkdownCodeColor:            t.MarkdownCode(),
+		MarkdownBlockQuoteColor:      t.MarkdownBlockQuote(),
+		MarkdownEmphColor:            t.MarkdownEmph(),
+		MarkdownStrongColor:          t.MarkdownStrong(),
+		MarkdownHorizontalRuleColor:  t.MarkdownHorizontalRule(),
+		MarkdownListItemColor:        t.MarkdownListItem(),
+		MarkdownListEnumerationColor: t.MarkdownListEnumeration(),
+		MarkdownImageColor:           t.MarkdownImage(),
+		MarkdownImageTextColor:       t.MarkdownImageText(),
+		MarkdownCodeBlockColor:       t.MarkdownCodeBlock(),
+		SyntaxCommentColor:           t.SyntaxComment(),
+		SyntaxKeywordColor:           t.SyntaxKeyword(),
+		SyntaxFunctionColor:          t.SyntaxFunction(),
+		SyntaxVariableColor:          t.SyntaxVariable(),
+		SyntaxStringColor:            t.SyntaxString(),
+		SyntaxNumberColor:            t.SyntaxNumber(),
+		SyntaxTypeColor:              t.SyntaxType(),
+		SyntaxOperatorColor:          t.SyntaxOperator(),
+		SyntaxPunctuationColor:       t.SyntaxPunctuation(),
+	}
+}
+
+// recolor returns base drawn in other colors. A provider theme stays one,
+// keeping its spinner, welcome message and typing indicator.
+func recolor(base Theme, colors BaseTheme) Theme {
+	if provider, ok := base.(*ProviderTheme); ok {
+		recolored := *provider
+		recolored.BaseTheme = colors
+		return &recolored
+	}
+	return &recoloredTheme{BaseTheme: colors, name: base.Name()}
+}
+
+// recoloredTheme is a theme drawn in other colors than its own
+type recoloredTheme struct {
+	BaseTheme
+	name string
+}
+
+func (t *recoloredTheme) Name() string { return t.name }
+
+// Recolor returns base with the colors change sets, as when a theme is
+// adapted for high contrast or color blindness
+func Recolor(base Theme, change func(colors *BaseTheme)) Theme {
+	colors := colorsOf(base)
+	change(&colors)
+	return recolor(base, colors)
+}
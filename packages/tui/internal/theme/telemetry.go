@@ -22,8 +22,8 @@ type ThemeTelemetry struct {
 	SlowestSwitch     time.Duration
 
 	// Usage patterns
-	TabCycles         atomic.Uint64 // User pressed Tab
-	ModalSelections   atomic.Uint64 // User selected from modal
+	TabCycles            atomic.Uint64 // User pressed Tab
+	ModalSelections      atomic.Uint64 // User selected from modal
 	ProgrammaticSwitches atomic.Uint64 // Code-triggered switches
 
 	mu sync.RWMutex
@@ -42,7 +42,7 @@ func init() {
 	}
 
 	// Initialize counters for known providers
-	for _, provider := range []string{"claude", "gemini", "codex", "qwen"} {
+	for _, provider := range []string{"claude", "gemini", "codex", "qwen", "azure", "amazon-bedrock"} {
 		globalTelemetry.SwitchesByTheme[provider] = &atomic.Uint64{}
 	}
 }
@@ -34,6 +34,8 @@ func NewThemeManager() *ThemeManager {
 	tm.registerTheme(NewGeminiTheme())
 	tm.registerTheme(NewCodexTheme())
 	tm.registerTheme(NewQwenTheme())
+	tm.registerTheme(NewAzureTheme())
+	tm.registerTheme(NewBedrockTheme())
 
 	// Set Claude as default
 	tm.current = tm.themes["claude"]
@@ -0,0 +1,116 @@
+// Package todos extracts the action items left open in a session, such as
+// "we should also…" or unchecked tasks, and writes them to a TODO.md list
+// or GitHub issues.
+package todos
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aaronmrosenthal/rycode/internal/transcript"
+)
+
+// FileName is the list items are exported to, at the project root
+const FileName = "TODO.md"
+
+// maxConversationBytes bounds the conversation sent for extraction. The
+// most recent messages are kept.
+const maxConversationBytes = 120_000
+
+// Item is an action item of a session
+type Item struct {
+	Title  string `json:"title"`
+	Detail string `json:"detail,omitempty"` // Context, such as the files involved
+	Issue  string `json:"-"`                // URL of the issue filed for it
+}
+
+// SystemPrompt has the agent only read the conversation
+const SystemPrompt = `You extract action items from a conversation between a user and a coding agent. Do not modify files or run commands.
+An action item is work that was agreed on, suggested or promised but not done by the end of the conversation: "we should also…", "later we can…", "TODO", unchecked task list entries, known bugs left unfixed.
+Leave out work that was completed later in the conversation.`
+
+const itemsFormat = "Reply with a single ```json block and nothing else, in this shape:\n" +
+	"```json\n" +
+	`{"items": [{"title": "imperative summary under 80 characters", "detail": "one or two sentences of context, naming files where relevant"}]}` +
+	"\n```\n" +
+	`Use {"items": []} when nothing is left to do.`
+
+// BuildPrompt asks for the action items of a transcript. Only the text of
+// the messages is sent, not tool calls.
+func BuildPrompt(t *transcript.Transcript) string {
+	return "List the action items left open in this conversation:\n\n" +
+		t.Conversation(maxConversationBytes) + "\n\n" + itemsFormat
+}
+
+var jsonBlockPattern = regexp.MustCompile("(?s)```(?:json)?\\s*(\\{.*?\\})\\s*```")
+
+// ParseItems extracts the action items from the agent's answer, dropping
+// empty and repeated ones
+func ParseItems(answer string) ([]Item, error) {
+	payload := ""
+	if match := jsonBlockPattern.FindStringSubmatch(answer); match != nil {
+		payload = match[1]
+	} else if start, end := strings.Index(answer, "{"), strings.LastIndex(answer, "}"); start >= 0 && end > start {
+		payload = answer[start : end+1]
+	}
+	if payload == "" {
+		return nil, fmt.Errorf("the answer didn't contain any action items")
+	}
+
+	var parsed struct {
+		Items []Item `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(payload), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse action items: %w", err)
+	}
+
+	var items []Item
+	seen := make(map[string]bool)
+	for _, item := range parsed.Items {
+		item.Title = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(item.Title), "- [ ]"))
+		item.Detail = strings.TrimSpace(item.Detail)
+		key := strings.ToLower(item.Title)
+		if item.Title == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// AppendMarkdown adds the items as unchecked tasks under a heading to the
+// content of a TODO.md, which may be empty. Items already in it are left
+// out; ok is false when none are left.
+func AppendMarkdown(content, heading string, items []Item) (string, bool) {
+	var b strings.Builder
+	for _, item := range items {
+		if strings.Contains(content, "] "+item.Title) {
+			continue
+		}
+		b.WriteString("- [ ] " + item.Title)
+		if item.Detail != "" {
+			b.WriteString(" — " + item.Detail)
+		}
+		b.WriteString("\n")
+	}
+	if b.Len() == 0 {
+		return content, false
+	}
+
+	if strings.TrimSpace(content) == "" {
+		content = "# TODO\n"
+	}
+	return strings.TrimRight(content, "\n") + "\n\n## " + heading + "\n\n" + b.String(), true
+}
+
+// IssueBody is the body of the GitHub issue filed for an item
+func IssueBody(item Item, session string) string {
+	body := item.Detail
+	if body != "" {
+		body += "\n\n"
+	}
+	return body + "Found in the session \"" + session + "\"."
+}
@@ -0,0 +1,65 @@
+package todos
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode/internal/transcript"
+)
+
+func TestParseItems(t *testing.T) {
+	answer := "Sure.\n```json\n" + `{"items": [
+		{"title": "Add retries to the uploader", "detail": "See upload.go."},
+		{"title": " - [ ] Document the flag "},
+		{"title": "add retries to the uploader"},
+		{"title": ""}
+	]}` + "\n```"
+
+	items, err := ParseItems(answer)
+	if err != nil {
+		t.Fatalf("failed to parse items: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %+v", items)
+	}
+	if items[0].Detail != "See upload.go." || items[1].Title != "Document the flag" {
+		t.Errorf("unexpected items %+v", items)
+	}
+	if _, err := ParseItems("nothing to see"); err == nil {
+		t.Error("expected an error for an answer without items")
+	}
+}
+
+func TestAppendMarkdown(t *testing.T) {
+	items := []Item{{Title: "Add retries", Detail: "See upload.go."}, {Title: "Document the flag"}}
+	content, ok := AppendMarkdown("", "Uploads", items)
+	want := "# TODO\n\n## Uploads\n\n- [ ] Add retries — See upload.go.\n- [ ] Document the flag\n"
+	if !ok || content != want {
+		t.Errorf("AppendMarkdown = %q, want %q", content, want)
+	}
+
+	content, ok = AppendMarkdown(strings.Replace(content, "[ ] Add", "[x] Add", 1), "Later", append(items, Item{Title: "Drop v1"}))
+	if !ok || !strings.HasSuffix(content, "## Later\n\n- [ ] Drop v1\n") {
+		t.Errorf("expected only the new item to be appended, got %q", content)
+	}
+	if _, ok := AppendMarkdown(content, "Again", items); ok {
+		t.Error("expected nothing to append when every item is listed")
+	}
+}
+
+func TestBuildPrompt(t *testing.T) {
+	tr := &transcript.Transcript{Messages: []transcript.Message{
+		{Role: "user", Parts: []transcript.Part{{Type: "text", Text: "Fix the uploader"}}},
+		{Role: "assistant", Parts: []transcript.Part{
+			{Type: "tool", Tool: "edit", Output: "secret output"},
+			{Type: "text", Text: "Done. We should also add retries."},
+		}},
+	}}
+	prompt := BuildPrompt(tr)
+	if !strings.Contains(prompt, "[user]\nFix the uploader\n\n[assistant]\nDone. We should also add retries.") {
+		t.Errorf("expected the conversation in order, got %q", prompt)
+	}
+	if strings.Contains(prompt, "secret output") {
+		t.Error("expected tool output to be left out")
+	}
+}
@@ -0,0 +1,238 @@
+package transcript
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DatasetStyle is the chat format the records of a fine-tuning dataset are
+// written in
+type DatasetStyle string
+
+const (
+	DatasetOpenAI    DatasetStyle = "openai"    // {"messages": [...]} with tool_calls and tool messages
+	DatasetAnthropic DatasetStyle = "anthropic" // {"messages": [...]} with tool_use and tool_result blocks
+)
+
+// DatasetStyles lists the supported dataset styles
+var DatasetStyles = []DatasetStyle{DatasetOpenAI, DatasetAnthropic}
+
+// ParseDatasetStyle parses a dataset style name, accepting common aliases
+func ParseDatasetStyle(name string) (DatasetStyle, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "openai", "oai", "gpt":
+		return DatasetOpenAI, nil
+	case "anthropic", "claude":
+		return DatasetAnthropic, nil
+	}
+	return "", fmt.Errorf("unknown dataset style %q; use openai or anthropic", name)
+}
+
+// step is a user prompt, or an assistant message with the tool calls it
+// made, as a dataset record has them
+type step struct {
+	role  string
+	text  string
+	calls []Part
+}
+
+// steps maps the transcript's messages to the steps of a record. Reasoning,
+// patches and calls that never finished are left out, and the record is cut
+// after the last answer that ends with text, so it doesn't end mid-run.
+func (t *Transcript) steps() []step {
+	var steps []step
+	for _, message := range t.Messages {
+		s := step{role: message.Role}
+		var texts []string
+		for _, part := range message.Parts {
+			switch part.Type {
+			case "text":
+				texts = append(texts, strings.TrimSpace(part.Text))
+			case "file":
+				if message.Role == "user" {
+					texts = append(texts, "[attached "+part.Filename+"]")
+				}
+			case "tool":
+				if message.Role == "assistant" && (part.Status == "completed" || part.Status == "error") {
+					s.calls = append(s.calls, part)
+				}
+			}
+		}
+		s.text = strings.Join(texts, "\n\n")
+		if s.text == "" && len(s.calls) == 0 {
+			continue
+		}
+		// A record starts with a prompt
+		if len(steps) == 0 && s.role != "user" {
+			continue
+		}
+		steps = append(steps, s)
+	}
+	end := len(steps)
+	for end > 0 && (steps[end-1].role != "assistant" || steps[end-1].text == "" || len(steps[end-1].calls) > 0) {
+		end--
+	}
+	return steps[:end]
+}
+
+type openAIRecord struct {
+	Messages []openAIMessage `json:"messages"`
+}
+
+type openAIMessage struct {
+	Role       string           `json:"role"`
+	Content    string           `json:"content,omitempty"`
+	ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+}
+
+type openAIToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type anthropicRecord struct {
+	Messages []anthropicMessage `json:"messages"`
+}
+
+type anthropicMessage struct {
+	Role    string           `json:"role"`
+	Content []anthropicBlock `json:"content"`
+}
+
+type anthropicBlock struct {
+	Type      string `json:"type"`
+	Text      string `json:"text,omitempty"`
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Input     any    `json:"input,omitempty"`
+	ToolUseID string `json:"tool_use_id,omitempty"`
+	Content   string `json:"content,omitempty"`
+	IsError   bool   `json:"is_error,omitempty"`
+}
+
+// Record returns the transcript as a training record of a dataset, a
+// conversation in the chat format of the style, or nil when it has no
+// answer to learn from. Prompts map to user messages and answers to
+// assistant ones; tool calls are the style's own, followed by their results.
+func (t *Transcript) Record(style DatasetStyle) (any, error) {
+	if style != DatasetOpenAI && style != DatasetAnthropic {
+		return nil, fmt.Errorf("unknown dataset style %q", style)
+	}
+	steps := t.steps()
+	if len(steps) == 0 {
+		return nil, nil
+	}
+	if style == DatasetAnthropic {
+		return anthropicRecordOf(steps), nil
+	}
+	return openAIRecordOf(steps), nil
+}
+
+func openAIRecordOf(steps []step) openAIRecord {
+	var record openAIRecord
+	calls := 0
+	for _, s := range steps {
+		last := len(record.Messages) - 1
+		// Consecutive texts of a role are a single message
+		if len(s.calls) == 0 && last >= 0 && record.Messages[last].Role == s.role && len(record.Messages[last].ToolCalls) == 0 {
+			record.Messages[last].Content += "\n\n" + s.text
+			continue
+		}
+		message := openAIMessage{Role: s.role, Content: s.text}
+		var results []openAIMessage
+		for _, part := range s.calls {
+			calls++
+			call := openAIToolCall{ID: fmt.Sprintf("call_%d", calls), Type: "function"}
+			call.Function.Name = part.Tool
+			call.Function.Arguments = string(toolInput(part))
+			message.ToolCalls = append(message.ToolCalls, call)
+			results = append(results, openAIMessage{Role: "tool", Content: toolResult(part), ToolCallID: call.ID})
+		}
+		record.Messages = append(record.Messages, message)
+		record.Messages = append(record.Messages, results...)
+	}
+	return record
+}
+
+func anthropicRecordOf(steps []step) anthropicRecord {
+	var record anthropicRecord
+	// add appends blocks to the last message when it is of the role, as
+	// roles alternate
+	add := func(role string, blocks ...anthropicBlock) {
+		if last := len(record.Messages) - 1; last >= 0 && record.Messages[last].Role == role {
+			record.Messages[last].Content = append(record.Messages[last].Content, blocks...)
+			return
+		}
+		record.Messages = append(record.Messages, anthropicMessage{Role: role, Content: blocks})
+	}
+	calls := 0
+	for _, s := range steps {
+		var blocks, results []anthropicBlock
+		if s.text != "" {
+			blocks = append(blocks, anthropicBlock{Type: "text", Text: s.text})
+		}
+		for _, part := range s.calls {
+			calls++
+			id := fmt.Sprintf("toolu_%d", calls)
+			blocks = append(blocks, anthropicBlock{Type: "tool_use", ID: id, Name: part.Tool, Input: json.RawMessage(toolInput(part))})
+			results = append(results, anthropicBlock{Type: "tool_result", ToolUseID: id, Content: toolResult(part), IsError: part.Status == "error"})
+		}
+		add(s.role, blocks...)
+		if len(results) > 0 {
+			add("user", results...)
+		}
+	}
+	return record
+}
+
+// toolInput encodes a call's input as a JSON object
+func toolInput(part Part) []byte {
+	if part.Input != nil {
+		if data, err := json.Marshal(part.Input); err == nil && bytes.HasPrefix(data, []byte("{")) {
+			return data
+		}
+	}
+	return []byte("{}")
+}
+
+// toolResult returns what a call gave back to the assistant
+func toolResult(part Part) string {
+	if part.Status == "error" {
+		return "Error: " + part.Error
+	}
+	if strings.TrimSpace(part.Output) == "" {
+		return "(no output)"
+	}
+	return part.Output
+}
+
+// Dataset writes the records of the transcripts in a style as JSON lines,
+// one per transcript with an answer to learn from. It returns the lines and
+// the number of transcripts left out.
+func Dataset(style DatasetStyle, transcripts []*Transcript) ([]byte, int, error) {
+	var buf bytes.Buffer
+	skipped := 0
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	for _, t := range transcripts {
+		record, err := t.Record(style)
+		if err != nil {
+			return nil, 0, err
+		}
+		if record == nil {
+			skipped++
+			continue
+		}
+		if err := encoder.Encode(record); err != nil {
+			return nil, 0, fmt.Errorf("failed to encode %s: %w", t.Title(), err)
+		}
+	}
+	return buf.Bytes(), skipped, nil
+}
@@ -0,0 +1,143 @@
+package transcript
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+func datasetTranscript() *Transcript {
+	tr := testTranscript()
+	tr.Add(opencode.AssistantMessage{ID: "msg_3"}, []opencode.PartUnion{
+		opencode.ToolPart{Tool: "bash", State: opencode.ToolPartState{
+			Status: opencode.ToolPartStateStatusError,
+			Input:  map[string]any{"command": "go test"},
+			Error:  "exit status 1",
+		}},
+	})
+	tr.Add(opencode.AssistantMessage{ID: "msg_4"}, []opencode.PartUnion{
+		opencode.TextPart{Text: "Tests fail before the fix too."},
+	})
+	// A prompt left unanswered is cut from the record
+	tr.Add(opencode.UserMessage{ID: "msg_5"}, []opencode.PartUnion{
+		opencode.TextPart{Text: "And now?"},
+	})
+	return tr
+}
+
+func TestRecordOpenAI(t *testing.T) {
+	record, err := datasetTranscript().Record(DatasetOpenAI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(record)
+	var got struct {
+		Messages []struct {
+			Role       string `json:"role"`
+			Content    string `json:"content"`
+			ToolCallID string `json:"tool_call_id"`
+			ToolCalls  []struct {
+				ID       string `json:"id"`
+				Function struct {
+					Name      string `json:"name"`
+					Arguments string `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	var roles []string
+	for _, message := range got.Messages {
+		roles = append(roles, message.Role)
+	}
+	if strings.Join(roles, ",") != "user,assistant,tool,assistant,tool,assistant" {
+		t.Fatalf("roles = %v", roles)
+	}
+	call := got.Messages[1].ToolCalls[0]
+	if call.Function.Name != "edit" || call.Function.Arguments != `{"filePath":"auth.go"}` || got.Messages[2].ToolCallID != call.ID {
+		t.Errorf("tool call = %+v, result %+v", call, got.Messages[2])
+	}
+	if got.Messages[1].Content != "Fixed with ```code``` fences" {
+		t.Errorf("answer = %q", got.Messages[1].Content)
+	}
+	if got.Messages[4].Content != "Error: exit status 1" {
+		t.Errorf("failed call result = %q", got.Messages[4].Content)
+	}
+}
+
+func TestRecordAnthropic(t *testing.T) {
+	record, err := datasetTranscript().Record(DatasetAnthropic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := record.(anthropicRecord)
+	var got []string
+	for _, message := range r.Messages {
+		var blocks []string
+		for _, block := range message.Content {
+			blocks = append(blocks, block.Type)
+		}
+		got = append(got, message.Role+":"+strings.Join(blocks, "+"))
+	}
+	// Roles alternate, tool results coming back as the user
+	want := "user:text,assistant:text+tool_use,user:tool_result,assistant:tool_use,user:tool_result,assistant:text"
+	if strings.Join(got, ",") != want {
+		t.Errorf("messages = %s, want %s", strings.Join(got, ","), want)
+	}
+	if result := r.Messages[4].Content[0]; !result.IsError || result.ToolUseID != r.Messages[3].Content[0].ID {
+		t.Errorf("failed call result = %+v", result)
+	}
+}
+
+func TestDataset(t *testing.T) {
+	// The run of this one ends with a tool call, not an answer
+	unfinished := testTranscript()
+
+	data, skipped, err := Dataset(DatasetOpenAI, []*Transcript{datasetTranscript(), unfinished, datasetTranscript()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 2 || skipped != 1 {
+		t.Errorf("dataset has %d lines and skipped %d, want 2 and 1", len(lines), skipped)
+	}
+	for _, line := range lines {
+		if !json.Valid([]byte(line)) {
+			t.Errorf("invalid line %s", line)
+		}
+	}
+	if _, _, err := Dataset("csv", []*Transcript{testTranscript()}); err == nil {
+		t.Error("an unknown style was written")
+	}
+}
+
+func TestContactFindings(t *testing.T) {
+	tr := testTranscript()
+	tr.Add(opencode.UserMessage{ID: "msg_3"}, []opencode.PartUnion{
+		opencode.TextPart{Text: "Mail jane.doe@acme.io or call +1 415-555-0134, not git@github.com or ops@example.com"},
+	})
+	var got []string
+	for _, finding := range tr.Findings() {
+		got = append(got, string(finding.Category)+":"+finding.Text)
+	}
+	want := "contact:jane.doe@acme.io,contact:+1 415-555-0134"
+	if strings.Join(got, ",") != want {
+		t.Errorf("findings = %s, want %s", strings.Join(got, ","), want)
+	}
+
+	tr = testTranscript()
+	tr.Add(opencode.UserMessage{ID: "msg_3"}, []opencode.PartUnion{
+		opencode.TextPart{Text: "Build 202-555-0147 of v1.2.3 failed; phone: (415) 555-0199"},
+	})
+	got = nil
+	for _, finding := range tr.Findings() {
+		got = append(got, finding.Text)
+	}
+	if strings.Join(got, ",") != "(415) 555-0199" {
+		t.Errorf("findings = %q, want only the number called a phone", got)
+	}
+}
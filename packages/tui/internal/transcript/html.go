@@ -0,0 +1,103 @@
+package transcript
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+var htmlTemplate = template.Must(template.New("transcript").Funcs(template.FuncMap{
+	"role":      roleTitle,
+	"input":     inputJSON,
+	"diffClass": diffClass,
+	"lines":     func(s string) []string { return strings.Split(strings.TrimRight(s, "\n"), "\n") },
+	"summary":   func(t *Transcript) []string { return t.Stats().Lines() },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font: 15px/1.55 -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; max-width: 920px; margin: 2rem auto; padding: 0 1rem; color: #1f2328; background: #fff; }
+header { border-bottom: 1px solid #d0d7de; margin-bottom: 1.5rem; }
+header p { color: #656d76; margin: .25rem 0 1rem; }
+.message { border: 1px solid #d0d7de; border-radius: 8px; margin: 1rem 0; padding: .75rem 1rem; }
+.message.user { background: #f6f8fa; }
+.meta { color: #656d76; font-size: 13px; margin-bottom: .5rem; }
+.meta strong { color: #1f2328; }
+.text { white-space: pre-wrap; overflow-wrap: anywhere; }
+details { color: #656d76; margin: .5rem 0; }
+.tool { border-left: 3px solid #8250df; padding-left: .75rem; margin: .75rem 0; }
+.tool .name { font-weight: 600; }
+.tool .status { color: #656d76; font-size: 13px; }
+pre { background: #f6f8fa; border-radius: 6px; padding: .6rem .8rem; overflow-x: auto; font: 12.5px/1.45 ui-monospace, SFMono-Regular, Menlo, monospace; }
+pre.diff { padding: .6rem 0; }
+pre.diff span { display: block; padding: 0 .8rem; }
+.add { background: #e6ffec; }
+.del { background: #ffebe9; }
+.hunk { color: #0969da; }
+.error { color: #cf222e; }
+ul.files { margin: .25rem 0; }
+@media (prefers-color-scheme: dark) {
+  body { color: #e6edf3; background: #0d1117; }
+  header, .message { border-color: #30363d; }
+  .message.user, pre { background: #161b22; }
+  .meta strong { color: #e6edf3; }
+  .add { background: #12261e; }
+  .del { background: #2d1214; }
+}
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}</h1>
+<p>Session <code>{{.Session.ID}}</code> · started {{.Session.Created.Format "2006-01-02 15:04"}} · exported {{.Exported.Format "2006-01-02 15:04"}}{{with .Cost}} · {{printf "$%.4f" .}}{{end}}</p>
+</header>
+{{range .Messages}}<section class="message {{.Role}}" id="{{.ID}}">
+<div class="meta"><strong>{{role .}}</strong> · {{.Created.Format "2006-01-02 15:04:05"}}</div>
+{{range .Parts}}{{if eq .Type "text"}}<div class="text">{{.Text}}</div>
+{{else if eq .Type "reasoning"}}<details><summary>Thinking</summary><div class="text">{{.Text}}</div></details>
+{{else if eq .Type "file"}}<p>📎 {{if .URL}}<a href="{{.URL}}">{{.Filename}}</a>{{else}}{{.Filename}}{{end}}</p>
+{{else if eq .Type "patch"}}<p>Changed files:</p><ul class="files">{{range .Files}}<li><code>{{.}}</code></li>{{end}}</ul>
+{{else if eq .Type "tool"}}<div class="tool">
+<div><span class="name">{{.Tool}}</span>{{with .Title}} — {{.}}{{end}}{{if and .Status (ne .Status "completed")}} <span class="status">({{.Status}})</span>{{end}}</div>
+{{with input .Input}}<details><summary>Input</summary><pre>{{.}}</pre></details>{{end}}
+{{if .Diff}}<pre class="diff">{{range lines .Diff}}<span class="{{diffClass .}}">{{.}}</span>{{end}}</pre>
+{{else if .Output}}<details><summary>Output</summary><pre>{{.Output}}</pre></details>{{end}}
+{{with .Error}}<p class="error">{{.}}</p>{{end}}
+</div>
+{{end}}{{end}}{{with .Error}}<p class="error">{{.}}</p>{{end}}
+</section>
+{{end}}<footer class="message">
+<div class="meta"><strong>Summary</strong></div>
+<ul class="files">{{range summary .}}<li>{{.}}</li>{{end}}</ul>
+</footer>
+</body>
+</html>
+`))
+
+// HTML renders the transcript as a self-contained HTML page
+func (t *Transcript) HTML() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, t); err != nil {
+		return nil, fmt.Errorf("failed to render HTML: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// diffClass returns the CSS class of a unified diff line
+func diffClass(line string) string {
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		return ""
+	case strings.HasPrefix(line, "+"):
+		return "add"
+	case strings.HasPrefix(line, "-"):
+		return "del"
+	case strings.HasPrefix(line, "@@"):
+		return "hunk"
+	}
+	return ""
+}
@@ -0,0 +1,123 @@
+package transcript
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Markdown renders the transcript as Markdown
+func (t *Transcript) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", t.Title())
+	fmt.Fprintf(&b, "- Session: `%s`\n", t.Session.ID)
+	fmt.Fprintf(&b, "- Started: %s\n", t.Session.Created.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&b, "- Exported: %s\n", t.Exported.Format("2006-01-02 15:04:05"))
+	if cost := t.Cost(); cost > 0 {
+		fmt.Fprintf(&b, "- Cost: $%.4f\n", cost)
+	}
+
+	for _, message := range t.Messages {
+		fmt.Fprintf(&b, "\n---\n\n## %s\n\n", roleTitle(message))
+		fmt.Fprintf(&b, "*%s*\n\n", message.Created.Format("2006-01-02 15:04:05"))
+		for _, part := range message.Parts {
+			writeMarkdownPart(&b, part)
+		}
+		if message.Error != "" {
+			fmt.Fprintf(&b, "> **Error:** %s\n\n", message.Error)
+		}
+	}
+
+	b.WriteString("\n---\n\n## Summary\n\n")
+	for _, line := range t.Stats().Lines() {
+		fmt.Fprintf(&b, "- %s\n", line)
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// Conversation renders the text of the messages, without tool calls, as
+// "[role]" headed paragraphs. When it would be longer than maxBytes, the
+// earliest messages are left out.
+func (t *Transcript) Conversation(maxBytes int) string {
+	var messages []string
+	size := 0
+	for i := len(t.Messages) - 1; i >= 0; i-- {
+		message := t.Messages[i]
+		var texts []string
+		for _, part := range message.Parts {
+			if part.Type == "text" && strings.TrimSpace(part.Text) != "" {
+				texts = append(texts, strings.TrimSpace(part.Text))
+			}
+		}
+		if len(texts) == 0 {
+			continue
+		}
+		text := fmt.Sprintf("[%s]\n%s", message.Role, strings.Join(texts, "\n\n"))
+		if size+len(text) > maxBytes {
+			messages = append(messages, "[earlier messages left out]")
+			break
+		}
+		size += len(text)
+		messages = append(messages, text)
+	}
+	slices.Reverse(messages)
+	return strings.Join(messages, "\n\n")
+}
+
+func writeMarkdownPart(b *strings.Builder, part Part) {
+	switch part.Type {
+	case "text":
+		b.WriteString(strings.TrimSpace(part.Text) + "\n\n")
+	case "reasoning":
+		b.WriteString("<details>\n<summary>Thinking</summary>\n\n")
+		b.WriteString(strings.TrimSpace(part.Text) + "\n\n</details>\n\n")
+	case "file":
+		if part.URL != "" {
+			fmt.Fprintf(b, "📎 [%s](%s)\n\n", part.Filename, part.URL)
+		} else {
+			fmt.Fprintf(b, "📎 %s\n\n", part.Filename)
+		}
+	case "patch":
+		b.WriteString("Changed files:\n\n")
+		for _, file := range part.Files {
+			fmt.Fprintf(b, "- `%s`\n", file)
+		}
+		b.WriteString("\n")
+	case "tool":
+		heading := "**Tool: " + part.Tool + "**"
+		if part.Title != "" {
+			heading += " — " + part.Title
+		}
+		if part.Status != "" && part.Status != "completed" {
+			heading += " (" + part.Status + ")"
+		}
+		b.WriteString(heading + "\n\n")
+		if input := inputJSON(part.Input); input != "" {
+			writeFence(b, "json", input)
+		}
+		if part.Diff != "" {
+			writeFence(b, "diff", part.Diff)
+		} else if part.Output != "" {
+			writeFence(b, "", part.Output)
+		}
+		if part.Error != "" {
+			fmt.Fprintf(b, "> **Error:** %s\n\n", part.Error)
+		}
+	}
+}
+
+// writeFence writes a fenced code block, using a fence longer than any run
+// of backticks in the content so that it can't end the block early
+func writeFence(b *strings.Builder, language, content string) {
+	longest, run := 0, 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+	fmt.Fprintf(b, "%s%s\n%s\n%s\n\n", fence, language, strings.TrimRight(content, "\n"), fence)
+}
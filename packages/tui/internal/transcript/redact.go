@@ -0,0 +1,275 @@
+package transcript
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Category is a kind of sensitive text a transcript is checked for
+type Category string
+
+const (
+	CategorySecret   Category = "secret"
+	CategoryPath     Category = "path"
+	CategoryUsername Category = "username"
+	CategoryContact  Category = "contact"
+)
+
+// Categories lists the categories in the order findings are listed
+var Categories = []Category{CategorySecret, CategoryPath, CategoryUsername, CategoryContact}
+
+// Mask returns the text masked findings of a category are replaced with
+func (c Category) Mask() string {
+	return "[" + string(c) + " redacted]"
+}
+
+// Finding is sensitive text found in a transcript. Text that appears more
+// than once is a single finding, listing every message it appears in.
+type Finding struct {
+	Category Category
+	Kind     string // What the text looks like, such as "GitHub token"
+	Text     string
+	Messages []int // Indexes of the messages the text appears in
+}
+
+type detector struct {
+	category Category
+	kind     string
+	pattern  *regexp.Regexp // The first group, if any, is the sensitive text
+}
+
+var detectors = []detector{
+	{CategorySecret, "private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)},
+	{CategorySecret, "AWS access key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{CategorySecret, "GitHub token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{CategorySecret, "API key", regexp.MustCompile(`\bsk-(?:ant-|proj-)?[A-Za-z0-9_-]{20,}`)},
+	{CategorySecret, "Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{CategorySecret, "Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{CategorySecret, "JSON web token", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{CategorySecret, "bearer token", regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9._~+/=-]{20,})`)},
+	{CategorySecret, "credential", regexp.MustCompile(`(?i)\b(?:password|passwd|secret|token|api[_-]?key|access[_-]?key|client[_-]?secret)["']?\s*[:=]\s*["']?([^\s"'<>,;]{8,})`)},
+	{CategorySecret, "credentials in URL", regexp.MustCompile(`\b[a-z][a-z0-9+.-]*://[^\s/:@]+:([^\s/@]+)@`)},
+	{CategoryPath, "home directory path", regexp.MustCompile(`(?:/home/|/Users/|[A-Za-z]:\\Users\\)[^\s"'` + "`" + `<>()\[\]{},;]+`)},
+	{CategoryContact, "email address", regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}\b`)},
+	// Bare digits are as often versions, IDs or sizes, so a number is a
+	// phone's only with a country code or a word saying so
+	{CategoryContact, "phone number", regexp.MustCompile(`\+\d{1,3}[ .-]?(?:\(\d{3}\)|\d{3})[ .-]?\d{3}[ .-]?\d{4}\b`)},
+	{CategoryContact, "phone number", regexp.MustCompile(`(?i)\b(?:tel(?:ephone)?|phone|mobile|cell)\b[^\d\n+]{0,12}((?:\(\d{3}\)|\b\d{3})[ .-]\d{3}[ .-]\d{4})\b`)},
+}
+
+// ignored reports whether text a detector of a kind matched is not
+// sensitive after all, by kind
+var ignored = map[string]func(string) bool{
+	"email address": placeholderEmail,
+}
+
+// placeholderEmail reports whether an address is no one's: the user of git
+// remotes, or one of the domains reserved for examples
+func placeholderEmail(address string) bool {
+	local, domain, _ := strings.Cut(strings.ToLower(address), "@")
+	if local == "git" || strings.HasSuffix(domain, "noreply.github.com") {
+		return true
+	}
+	for _, reserved := range []string{"example.com", "example.org", "example.net", ".example", ".test", ".invalid", ".localhost"} {
+		if domain == reserved || strings.HasSuffix(domain, reserved) {
+			return true
+		}
+	}
+	return false
+}
+
+// homeUserPattern matches the user name in a home directory path
+var homeUserPattern = regexp.MustCompile(`^(?:/home/|/Users/|[A-Za-z]:\\Users\\)([^/\\]+)`)
+
+// Findings checks the transcript for secrets, home directory paths, user
+// names, email addresses and phone numbers. The user names of home directories are found, as are the names
+// passed in, where they appear as whole words. Findings are ordered by
+// category, then by where they first appear.
+func (t *Transcript) Findings(usernames ...string) []Finding {
+	var findings []Finding
+	index := make(map[string]int)
+	add := func(category Category, kind, text string, message int) {
+		if i, ok := index[text]; ok {
+			if messages := findings[i].Messages; messages[len(messages)-1] != message {
+				findings[i].Messages = append(messages, message)
+			}
+			return
+		}
+		index[text] = len(findings)
+		findings = append(findings, Finding{Category: category, Kind: kind, Text: text, Messages: []int{message}})
+	}
+
+	names := make(map[string]bool)
+	for _, name := range usernames {
+		// Short names would match ordinary words
+		if len(name) >= 3 {
+			names[name] = true
+		}
+	}
+	for i, message := range t.Messages {
+		for _, text := range message.texts() {
+			for _, d := range detectors {
+				for _, match := range d.pattern.FindAllStringSubmatch(text, -1) {
+					found := match[0]
+					if len(match) > 1 && match[1] != "" {
+						found = match[1]
+					}
+					if ignore := ignored[d.kind]; ignore != nil && ignore(found) {
+						continue
+					}
+					add(d.category, d.kind, found, i)
+					if d.category == CategoryPath {
+						if user := homeUserPattern.FindStringSubmatch(found); user != nil {
+							names[user[1]] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	// User names are looked for once the home directories are known
+	var sorted []string
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	for _, name := range sorted {
+		pattern := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b`)
+		for i, message := range t.Messages {
+			for _, text := range message.texts() {
+				if pattern.MatchString(text) {
+					add(CategoryUsername, "user name", name, i)
+					break
+				}
+			}
+		}
+	}
+
+	order := make(map[Category]int)
+	for i, category := range Categories {
+		order[category] = i
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Category != findings[j].Category {
+			return order[findings[i].Category] < order[findings[j].Category]
+		}
+		return findings[i].Messages[0] < findings[j].Messages[0]
+	})
+	return findings
+}
+
+// texts returns every text of a message that is exported
+func (m Message) texts() []string {
+	texts := []string{m.Error}
+	for _, part := range m.Parts {
+		texts = append(texts, part.Text, part.Filename, part.URL, part.Title, part.Output, part.Error, part.Diff)
+		texts = append(texts, part.Files...)
+		texts = inputTexts(part.Input, texts)
+	}
+	return texts
+}
+
+func inputTexts(input any, texts []string) []string {
+	switch input := input.(type) {
+	case string:
+		texts = append(texts, input)
+	case map[string]any:
+		for _, value := range input {
+			texts = inputTexts(value, texts)
+		}
+	case []any:
+		for _, value := range input {
+			texts = inputTexts(value, texts)
+		}
+	}
+	return texts
+}
+
+// Redact returns a copy of the transcript with the text of the findings
+// replaced by their category's mask. Longer findings are masked first, so a
+// path is masked whole before the user name in it.
+func (t *Transcript) Redact(findings []Finding) *Transcript {
+	if len(findings) == 0 {
+		return t
+	}
+	masked := append([]Finding(nil), findings...)
+	sort.SliceStable(masked, func(i, j int) bool {
+		return len(masked[i].Text) > len(masked[j].Text)
+	})
+	var pairs []string
+	for _, finding := range masked {
+		pairs = append(pairs, finding.Text, finding.Category.Mask())
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	redacted := *t
+	redacted.Session.Title = replacer.Replace(t.Session.Title)
+	redacted.Messages = make([]Message, len(t.Messages))
+	for i, message := range t.Messages {
+		message.Error = replacer.Replace(message.Error)
+		parts := make([]Part, len(message.Parts))
+		for j, part := range message.Parts {
+			part.Text = replacer.Replace(part.Text)
+			part.Filename = replacer.Replace(part.Filename)
+			part.URL = replacer.Replace(part.URL)
+			part.Title = replacer.Replace(part.Title)
+			part.Output = replacer.Replace(part.Output)
+			part.Error = replacer.Replace(part.Error)
+			part.Diff = replacer.Replace(part.Diff)
+			if part.Files != nil {
+				files := make([]string, len(part.Files))
+				for k, file := range part.Files {
+					files[k] = replacer.Replace(file)
+				}
+				part.Files = files
+			}
+			part.Input = redactInput(part.Input, replacer)
+			parts[j] = part
+		}
+		message.Parts = parts
+		redacted.Messages[i] = message
+	}
+	return &redacted
+}
+
+func redactInput(input any, replacer *strings.Replacer) any {
+	switch input := input.(type) {
+	case string:
+		return replacer.Replace(input)
+	case map[string]any:
+		redacted := make(map[string]any, len(input))
+		for key, value := range input {
+			redacted[key] = redactInput(value, replacer)
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(input))
+		for i, value := range input {
+			redacted[i] = redactInput(value, replacer)
+		}
+		return redacted
+	}
+	return input
+}
+
+// Context returns the text around the first appearance of a finding, at
+// most radius characters on each side and on a single line
+func (t *Transcript) Context(finding Finding, radius int) (before, after string) {
+	if len(finding.Messages) == 0 || finding.Messages[0] >= len(t.Messages) {
+		return "", ""
+	}
+	for _, text := range t.Messages[finding.Messages[0]].texts() {
+		at := strings.Index(text, finding.Text)
+		if at < 0 {
+			continue
+		}
+		runes := []rune(text[:at])
+		before = string(runes[max(0, len(runes)-radius):])
+		runes = []rune(text[at+len(finding.Text):])
+		after = string(runes[:min(len(runes), radius)])
+		flatten := strings.NewReplacer("\r", " ", "\n", " ", "\t", " ")
+		return flatten.Replace(before), flatten.Replace(after)
+	}
+	return "", ""
+}
@@ -0,0 +1,141 @@
+package transcript
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+)
+
+// Stats sums up a transcript: how long the session ran and what it used,
+// changed and tested
+type Stats struct {
+	Duration     time.Duration `json:"duration"`
+	Messages     int           `json:"messages"`
+	InputTokens  int64         `json:"inputTokens"`  // Input tokens, cache reads and writes included
+	OutputTokens int64         `json:"outputTokens"` // Output tokens, reasoning included
+	Cost         float64       `json:"cost"`
+	Files        []string      `json:"files,omitempty"` // Files the session's tools changed
+	TestRuns     int           `json:"testRuns"`
+	TestFailures int           `json:"testFailures"`
+}
+
+// editTools are the tools whose filePath input is a file they changed
+var editTools = []string{"edit", "multiedit", "write", "patch"}
+
+// testCommand matches shell commands that run a test suite
+var testCommand = regexp.MustCompile(`(^|[\s;&|(])((go|cargo|dotnet|mix|swift|deno|bun|mvn|gradle|\./gradlew|make) test|(npm|pnpm|yarn)( run)? test|pytest|py\.test|jest|vitest|rspec|phpunit|ctest|tox)\b`)
+
+// Stats sums up the transcript
+func (t *Transcript) Stats() Stats {
+	var s Stats
+	s.Messages = len(t.Messages)
+	var first, last time.Time
+	for _, message := range t.Messages {
+		// Messages still being written may have no time yet
+		if created := message.Created; created.UnixMilli() > 0 {
+			if first.IsZero() || created.Before(first) {
+				first = created
+			}
+			if created.After(last) {
+				last = created
+			}
+		}
+		s.Cost += message.Cost
+		if tokens := message.Tokens; tokens != nil {
+			s.InputTokens += tokens.Input + tokens.CacheRead + tokens.CacheWrite
+			s.OutputTokens += tokens.Output + tokens.Reasoning
+		}
+		for _, part := range message.Parts {
+			switch part.Type {
+			case "patch":
+				for _, file := range part.Files {
+					s.addFile(file)
+				}
+			case "tool":
+				input, _ := part.Input.(map[string]any)
+				if slices.Contains(editTools, part.Tool) && part.Status == "completed" {
+					file, _ := input["filePath"].(string)
+					s.addFile(file)
+				}
+				if command, _ := input["command"].(string); part.Tool == "bash" && testCommand.MatchString(command) {
+					s.TestRuns++
+					if part.Status == "error" {
+						s.TestFailures++
+					}
+				}
+			}
+		}
+	}
+	if t.Session.Updated.After(last) {
+		last = t.Session.Updated
+	}
+	if !first.IsZero() && last.After(first) {
+		s.Duration = last.Sub(first)
+	}
+	return s
+}
+
+func (s *Stats) addFile(file string) {
+	if file != "" && !slices.Contains(s.Files, file) {
+		s.Files = append(s.Files, file)
+	}
+}
+
+// Lines describes the stats, a line for each
+func (s Stats) Lines() []string {
+	lines := []string{
+		fmt.Sprintf("Duration: %s", FormatDuration(s.Duration)),
+		fmt.Sprintf("Messages: %d", s.Messages),
+		fmt.Sprintf("Tokens: %s in, %s out", FormatTokens(s.InputTokens), FormatTokens(s.OutputTokens)),
+		fmt.Sprintf("Cost: $%.4f", s.Cost),
+		fmt.Sprintf("Files changed: %d", len(s.Files)),
+	}
+	tests := fmt.Sprintf("Tests run: %d", s.TestRuns)
+	if s.TestFailures > 0 {
+		tests += fmt.Sprintf(", %d failed", s.TestFailures)
+	}
+	return append(lines, tests)
+}
+
+// Card describes the stats in two short lines, as 12m · 8 messages · $0.42
+func (s Stats) Card() string {
+	card := fmt.Sprintf("%s · %d messages · %s in / %s out · $%.2f\n%d files changed · %d test runs",
+		FormatDuration(s.Duration), s.Messages, FormatTokens(s.InputTokens), FormatTokens(s.OutputTokens), s.Cost,
+		len(s.Files), s.TestRuns)
+	if s.TestFailures > 0 {
+		card += fmt.Sprintf(" (%d failed)", s.TestFailures)
+	}
+	return card
+}
+
+// FormatDuration shortens a duration to its two largest units, as 1h5m or
+// 45s
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	var large, small int
+	var units string
+	switch {
+	case d >= time.Hour:
+		large, small, units = int(d.Hours()), int(d.Minutes())%60, "hm"
+	case d >= time.Minute:
+		large, small, units = int(d.Minutes()), int(d.Seconds())%60, "ms"
+	default:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	if small == 0 {
+		return fmt.Sprintf("%d%c", large, units[0])
+	}
+	return fmt.Sprintf("%d%c%d%c", large, units[0], small, units[1])
+}
+
+// FormatTokens shortens a token count, as 12.3K or 4.5M
+func FormatTokens(tokens int64) string {
+	switch {
+	case tokens >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(tokens)/1_000_000)
+	case tokens >= 1_000:
+		return fmt.Sprintf("%.1fK", float64(tokens)/1_000)
+	}
+	return fmt.Sprint(tokens)
+}
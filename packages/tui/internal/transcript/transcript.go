@@ -0,0 +1,272 @@
+// Package transcript renders the message history of a session, including its
+// tool calls and diffs, as Markdown, HTML or JSON for sharing outside the TUI,
+// or as records of a fine-tuning dataset.
+package transcript
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+)
+
+// Format is an export file format
+type Format string
+
+const (
+	FormatMarkdown Format = "md"
+	FormatHTML     Format = "html"
+	FormatJSON     Format = "json"
+)
+
+// Formats lists the supported formats
+var Formats = []Format{FormatMarkdown, FormatHTML, FormatJSON}
+
+// ParseFormat parses a format name, accepting common aliases
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), ".")) {
+	case "", "md", "markdown":
+		return FormatMarkdown, nil
+	case "html", "htm":
+		return FormatHTML, nil
+	case "json":
+		return FormatJSON, nil
+	}
+	return "", fmt.Errorf("unknown export format %q; use md, html or json", name)
+}
+
+// Transcript is the exported history of a session
+type Transcript struct {
+	Session  Session   `json:"session"`
+	Exported time.Time `json:"exported"`
+	Messages []Message `json:"messages"`
+}
+
+// Session describes the exported session
+type Session struct {
+	ID      string    `json:"id"`
+	Title   string    `json:"title"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+// Message is a user or assistant message of the transcript
+type Message struct {
+	ID       string    `json:"id"`
+	Role     string    `json:"role"`
+	Created  time.Time `json:"created"`
+	Provider string    `json:"provider,omitempty"`
+	Model    string    `json:"model,omitempty"`
+	Cost     float64   `json:"cost,omitempty"`
+	Tokens   *Tokens   `json:"tokens,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	Parts    []Part    `json:"parts"`
+}
+
+// Tokens is the token usage of an assistant message
+type Tokens struct {
+	Input      int64 `json:"input"`
+	Output     int64 `json:"output"`
+	Reasoning  int64 `json:"reasoning,omitempty"`
+	CacheRead  int64 `json:"cacheRead,omitempty"`
+	CacheWrite int64 `json:"cacheWrite,omitempty"`
+}
+
+// Part is a piece of a message. Which fields are set depends on Type: text
+// and reasoning parts have Text, file parts have Filename, tool parts have
+// the Tool fields and patch parts list the changed Files.
+type Part struct {
+	Type     string   `json:"type"`
+	Text     string   `json:"text,omitempty"`
+	Filename string   `json:"filename,omitempty"`
+	Mime     string   `json:"mime,omitempty"`
+	URL      string   `json:"url,omitempty"`
+	Tool     string   `json:"tool,omitempty"`
+	Status   string   `json:"status,omitempty"`
+	Title    string   `json:"title,omitempty"`
+	Input    any      `json:"input,omitempty"`
+	Output   string   `json:"output,omitempty"`
+	Error    string   `json:"error,omitempty"`
+	Diff     string   `json:"diff,omitempty"`
+	Files    []string `json:"files,omitempty"`
+}
+
+// New starts a transcript of a session
+func New(session opencode.Session, exported time.Time) *Transcript {
+	return &Transcript{
+		Session: Session{
+			ID:      session.ID,
+			Title:   session.Title,
+			Created: time.UnixMilli(int64(session.Time.Created)),
+			Updated: time.UnixMilli(int64(session.Time.Updated)),
+		},
+		Exported: exported,
+	}
+}
+
+// Add appends a message and its parts. Messages that aren't from the user
+// or the assistant and parts without content, such as step markers and
+// synthetic text, are left out.
+func (t *Transcript) Add(info opencode.MessageUnion, parts []opencode.PartUnion) {
+	var message Message
+	switch info := info.(type) {
+	case opencode.UserMessage:
+		message = Message{ID: info.ID, Role: "user", Created: time.UnixMilli(int64(info.Time.Created))}
+	case opencode.AssistantMessage:
+		message = Message{
+			ID:       info.ID,
+			Role:     "assistant",
+			Created:  time.UnixMilli(int64(info.Time.Created)),
+			Provider: info.ProviderID,
+			Model:    info.ModelID,
+			Cost:     info.Cost,
+			Tokens: &Tokens{
+				Input:      int64(info.Tokens.Input),
+				Output:     int64(info.Tokens.Output),
+				Reasoning:  int64(info.Tokens.Reasoning),
+				CacheRead:  int64(info.Tokens.Cache.Read),
+				CacheWrite: int64(info.Tokens.Cache.Write),
+			},
+			Error: assistantError(info.Error),
+		}
+	default:
+		return
+	}
+
+	for _, part := range parts {
+		switch p := part.(type) {
+		case opencode.TextPart:
+			if !p.Synthetic && strings.TrimSpace(p.Text) != "" {
+				message.Parts = append(message.Parts, Part{Type: "text", Text: p.Text})
+			}
+		case opencode.ReasoningPart:
+			if strings.TrimSpace(p.Text) != "" {
+				message.Parts = append(message.Parts, Part{Type: "reasoning", Text: p.Text})
+			}
+		case opencode.FilePart:
+			exported := Part{Type: "file", Filename: p.Filename, Mime: p.Mime}
+			// Inline data would bloat the export; only links are kept
+			if !strings.HasPrefix(p.URL, "data:") {
+				exported.URL = p.URL
+			}
+			message.Parts = append(message.Parts, exported)
+		case opencode.ToolPart:
+			message.Parts = append(message.Parts, toolPart(p))
+		case opencode.PartPatchPart:
+			if len(p.Files) > 0 {
+				message.Parts = append(message.Parts, Part{Type: "patch", Files: p.Files})
+			}
+		}
+	}
+	if len(message.Parts) > 0 || message.Error != "" {
+		t.Messages = append(t.Messages, message)
+	}
+}
+
+func toolPart(p opencode.ToolPart) Part {
+	exported := Part{
+		Type:   "tool",
+		Tool:   p.Tool,
+		Status: string(p.State.Status),
+		Title:  p.State.Title,
+		Input:  p.State.Input,
+		Output: p.State.Output,
+		Error:  p.State.Error,
+	}
+	if metadata, ok := p.State.Metadata.(map[string]any); ok {
+		if diff, ok := metadata["diff"].(string); ok {
+			exported.Diff = diff
+		}
+	}
+	if input, ok := p.State.Input.(map[string]any); ok && len(input) == 0 {
+		exported.Input = nil
+	}
+	return exported
+}
+
+func assistantError(err opencode.AssistantMessageError) string {
+	switch err := err.AsUnion().(type) {
+	case opencode.AssistantMessageErrorMessageOutputLengthError:
+		return "Message output length exceeded"
+	case opencode.ProviderAuthError:
+		return err.Data.Message
+	case opencode.MessageAbortedError:
+		return "Request was aborted"
+	case opencode.UnknownError:
+		return err.Data.Message
+	}
+	return ""
+}
+
+// Render renders the transcript in a format
+func (t *Transcript) Render(format Format) ([]byte, error) {
+	switch format {
+	case FormatMarkdown:
+		return []byte(t.Markdown()), nil
+	case FormatHTML:
+		return t.HTML()
+	case FormatJSON:
+		return json.MarshalIndent(struct {
+			*Transcript
+			Stats Stats `json:"stats"`
+		}{t, t.Stats()}, "", "  ")
+	}
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
+
+// Cost returns the total cost of the assistant messages
+func (t *Transcript) Cost() float64 {
+	var cost float64
+	for _, message := range t.Messages {
+		cost += message.Cost
+	}
+	return cost
+}
+
+// Title returns the session title, or a placeholder for untitled sessions
+func (t *Transcript) Title() string {
+	if strings.TrimSpace(t.Session.Title) == "" {
+		return "Untitled session"
+	}
+	return t.Session.Title
+}
+
+var nonSlugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Filename returns a file name for the transcript in a format, made from the
+// session title and the export time
+func (t *Transcript) Filename(format Format) string {
+	slug := strings.Trim(nonSlugPattern.ReplaceAllString(strings.ToLower(t.Session.Title), "-"), "-")
+	if len(slug) > 48 {
+		slug = strings.TrimRight(slug[:48], "-")
+	}
+	if slug == "" {
+		slug = "session"
+	}
+	return fmt.Sprintf("%s-%s.%s", slug, t.Exported.Format("20060102-150405"), format)
+}
+
+// inputJSON formats a tool input for display
+func inputJSON(input any) string {
+	if input == nil {
+		return ""
+	}
+	data, err := json.MarshalIndent(input, "", "  ")
+	if err != nil {
+		return fmt.Sprint(input)
+	}
+	return string(data)
+}
+
+func roleTitle(message Message) string {
+	if message.Role == "user" {
+		return "User"
+	}
+	if message.Model != "" {
+		return "Assistant (" + message.Model + ")"
+	}
+	return "Assistant"
+}
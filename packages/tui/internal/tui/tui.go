@@ -1,13 +1,17 @@
 package tui
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
+	"image/color"
 	"log/slog"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,23 +19,42 @@ import (
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/charmbracelet/lipgloss/v2"
 	"github.com/charmbracelet/lipgloss/v2/compat"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/input"
 
 	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/accessibility"
 	"github.com/aaronmrosenthal/rycode/internal/api"
 	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/bench"
+	"github.com/aaronmrosenthal/rycode/internal/bridge"
 	"github.com/aaronmrosenthal/rycode/internal/commands"
 	"github.com/aaronmrosenthal/rycode/internal/completions"
 	"github.com/aaronmrosenthal/rycode/internal/components/chat"
 	cmdcomp "github.com/aaronmrosenthal/rycode/internal/components/commands"
 	"github.com/aaronmrosenthal/rycode/internal/components/debugger"
 	"github.com/aaronmrosenthal/rycode/internal/components/dialog"
+	"github.com/aaronmrosenthal/rycode/internal/components/help"
 	"github.com/aaronmrosenthal/rycode/internal/components/modal"
 	"github.com/aaronmrosenthal/rycode/internal/components/splash"
 	"github.com/aaronmrosenthal/rycode/internal/components/status"
 	"github.com/aaronmrosenthal/rycode/internal/components/toast"
+	"github.com/aaronmrosenthal/rycode/internal/gesture"
+	"github.com/aaronmrosenthal/rycode/internal/intelligence"
 	"github.com/aaronmrosenthal/rycode/internal/layout"
+	"github.com/aaronmrosenthal/rycode/internal/loopguard"
+	"github.com/aaronmrosenthal/rycode/internal/pathrules"
+	"github.com/aaronmrosenthal/rycode/internal/performance"
+	"github.com/aaronmrosenthal/rycode/internal/permissions"
+	"github.com/aaronmrosenthal/rycode/internal/plugin"
+	"github.com/aaronmrosenthal/rycode/internal/projecttools"
+	"github.com/aaronmrosenthal/rycode/internal/refactor"
+	"github.com/aaronmrosenthal/rycode/internal/schedule"
+	"github.com/aaronmrosenthal/rycode/internal/sessiontemplate"
 	"github.com/aaronmrosenthal/rycode/internal/styles"
 	"github.com/aaronmrosenthal/rycode/internal/theme"
+	"github.com/aaronmrosenthal/rycode/internal/transcript"
+	"github.com/aaronmrosenthal/rycode/internal/tutorial"
 	"github.com/aaronmrosenthal/rycode/internal/util"
 )
 
@@ -41,6 +64,11 @@ type InterruptDebounceTimeoutMsg struct{}
 // ExitDebounceTimeoutMsg is sent when the exit key debounce timeout expires
 type ExitDebounceTimeoutMsg struct{}
 
+// GestureHoldMsg checks whether a touch is held long enough for a long press
+type GestureHoldMsg struct {
+	press int
+}
+
 // CostTickMsg is sent every 5 seconds to trigger cost update
 type CostTickMsg time.Time
 
@@ -75,6 +103,7 @@ type Model struct {
 	tea.Model
 	tea.CursorModel
 	width, height        int
+	terminalHeight       int // Height of the whole terminal, of which an inline TUI takes part
 	app                  *app.App
 	modal                layout.Modal
 	status               status.StatusComponent
@@ -91,14 +120,35 @@ type Model struct {
 	interruptKeyState    InterruptKeyState
 	exitKeyState         ExitKeyState
 	messagesRight        bool
+	gestures             *gesture.Recognizer // Touch gestures, nil unless the terminal is on a touch screen
 	splashScreen         *splash.Model
 	showSplash           bool
+	dashboard            *app.HomeDashboard // Recent sessions and provider health of the home screen, nil until loaded
 	debugger             debugger.Model
 	// Provider switch inline cortex animation
 	providerSwitchCortex *splash.CortexRenderer
 	showProviderSwitch   bool
 	switchStartTime      time.Time
 	switchOpacity        float64
+	toolLogTicks         int            // Generation of the tool log's clock, so that one runs at a time
+	sidePane             *chat.SidePane // Newest tool output beside the chat, shown in the split layout
+	backgroundKnown      bool           // The terminal reported its background, so later reports are changes to follow
+	backgroundPolls      int            // Generation of the background polling, so that one runs at a time
+}
+
+// backgroundPollMsg asks the terminal for its background color again
+type backgroundPollMsg struct {
+	generation int
+}
+
+// backgroundPollInterval is how often the background color is asked for
+// while the system theme follows it, catching the system switching between
+// dark and light mode
+const backgroundPollInterval = 5 * time.Second
+
+// toolLogTickMsg redraws the tool log's elapsed times
+type toolLogTickMsg struct {
+	generation int
 }
 
 func (a Model) Init() tea.Cmd {
@@ -111,9 +161,19 @@ func (a Model) Init() tea.Cmd {
 	// https://github.com/charmbracelet/bubbletea/issues/1440
 	// https://github.com/aaronmrosenthal/rycode/issues/127
 	if !util.IsWsl() {
-		cmds = append(cmds, tea.RequestBackgroundColor)
+		cmds = append(cmds, tea.RequestBackgroundColor, a.pollBackground())
+	}
+	initProvider := a.app.InitializeProvider()
+	if a.app.InitialTutorial {
+		// Started after the provider is picked, so the pick doesn't replace
+		// the playground's model
+		initProvider = tea.Sequence(initProvider, util.CmdHandler(app.TutorialStartMsg{}))
 	}
-	cmds = append(cmds, a.app.InitializeProvider())
+	cmds = append(cmds, initProvider)
+	if a.app.Session.ID == "" {
+		cmds = append(cmds, a.app.LoadHomeDashboard())
+	}
+	cmds = append(cmds, a.app.LoadPlugins())
 	cmds = append(cmds, a.editor.Init())
 	cmds = append(cmds, a.messages.Init())
 	cmds = append(cmds, a.status.Init())
@@ -121,10 +181,22 @@ func (a Model) Init() tea.Cmd {
 	cmds = append(cmds, a.toastManager.Init())
 	cmds = append(cmds, a.debugger.Init())
 
-	// Initialize splash screen
+	// Initialize splash screen, or go on as if it had finished
 	if a.showSplash && a.splashScreen != nil {
 		cmds = append(cmds, a.splashScreen.Init())
+	} else {
+		cmds = append(cmds, util.CmdHandler(splash.SplashFinishedMsg{}))
+	}
+
+	if a.app.State.ClipboardWatch {
+		cmds = append(cmds, a.app.StartClipboardWatch())
+	}
+	if a.app.State.ShowToolLog {
+		cmds = append(cmds, a.tickToolLog())
 	}
+	cmds = append(cmds, a.app.StartProfilingFromEnv())
+	cmds = append(cmds, a.app.WatchRunLimits())
+	cmds = append(cmds, a.app.RegisterProjectTools())
 
 	// Start background cost update ticker
 	cmds = append(cmds, tickEvery5Seconds())
@@ -142,21 +214,73 @@ func (a Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
 
+	if performance.Enabled() {
+		name := fmt.Sprintf("update %T", msg)
+		defer performance.EndComponentRender(name, performance.StartComponentRender(name))
+	}
+
+	// Any event shows the stream is alive
+	if event, ok := msg.(opencode.EventListResponseUnion); ok {
+		a.app.SawEvent(event)
+	}
+
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		a.terminalHeight = size.Height
+		if a.app.Inline {
+			// The components lay out the part of the screen below the
+			// scrollback
+			size.Height = app.InlineHeight(size.Height)
+			msg = size
+		}
+	}
+
+	// Keyboard-only mode leaves the mouse to the terminal; what it still
+	// reports, as before the terminal was told, is dropped
+	if _, ok := msg.(tea.MouseMsg); ok && a.app.KeyboardOnly() {
+		return a, nil
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyPressMsg:
 		keyString := msg.String()
 
 		if a.app.CurrentPermission.ID != "" {
-			if keyString == "enter" || keyString == "esc" || keyString == "a" {
-				sessionID := a.app.CurrentPermission.SessionID
-				permissionID := a.app.CurrentPermission.ID
-				a.editor.Focus()
-				a.app.Permissions = a.app.Permissions[1:]
-				if len(a.app.Permissions) > 0 {
-					a.app.CurrentPermission = a.app.Permissions[0]
-				} else {
-					a.app.CurrentPermission = opencode.Permission{}
+			// The hunks of an edit are selected with up and down, and
+			// rejected or accepted again with space
+			review := a.app.EditReview()
+			if review != nil && len(review.Hunks) > 1 {
+				switch keyString {
+				case "up", "k":
+					a.app.SetEditReview(review.Move(-1))
+					return a, util.CmdHandler(app.EditReviewChangedMsg{})
+				case "down", "j":
+					a.app.SetEditReview(review.Move(1))
+					return a, util.CmdHandler(app.EditReviewChangedMsg{})
+				case "space":
+					a.app.SetEditReview(review.Toggle())
+					return a, util.CmdHandler(app.EditReviewChangedMsg{})
+				}
+			}
+			if keyString == "enter" || keyString == "esc" || keyString == "a" || keyString == "p" || keyString == "n" {
+				// An edit whose file changed on disk since it was asked for
+				// is moved onto the changes, or compared with them where
+				// both touch the same lines
+				var conflict *app.EditConflict
+				if review != nil && keyString != "esc" && keyString != "n" {
+					if conflict = a.app.EditConflict(review); conflict != nil && len(conflict.Hunks) == 0 {
+						conflict = nil
+					}
+					if conflict != nil && len(conflict.Overlaps) > 0 {
+						a.modal = dialog.NewEditConflictDialog(a.app, conflict)
+						return a, nil
+					}
 				}
+
+				permission := a.app.CurrentPermission
+				sessionID := permission.SessionID
+				permissionID := permission.ID
+				a.editor.Focus()
+				a.removePermission(permissionID)
 				response := opencode.SessionPermissionRespondParamsResponseOnce
 				switch keyString {
 				case "enter":
@@ -167,23 +291,61 @@ func (a Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					response = opencode.SessionPermissionRespondParamsResponseReject
 				}
 
-				return a, func() tea.Msg {
-					resp, err := a.app.Client.Session.Permissions.Respond(
-						context.Background(),
-						sessionID,
-						permissionID,
-						opencode.SessionPermissionRespondParams{Response: opencode.F(response)},
-					)
-					if err != nil {
-						slog.Error("Failed to respond to permission request", "error", err)
-						return toast.NewErrorToast("Failed to respond to permission request")()
+				// The answer is remembered for requests like it in the
+				// project, with p to allow and n to deny
+				var remember tea.Cmd
+				switch keyString {
+				case "p":
+					remember = a.app.RememberPermission(permission, true)
+				case "n":
+					response = opencode.SessionPermissionRespondParamsResponseReject
+					remember = a.app.RememberPermission(permission, false)
+				}
+
+				// An edit with rejected hunks is rejected, and the hunks that
+				// were accepted are applied here
+				var partial tea.Cmd
+				if conflict != nil {
+					response = opencode.SessionPermissionRespondParamsResponseReject
+					a.app, partial = a.app.ApplyRebasedEdit(conflict)
+				} else if review != nil && keyString != "esc" && keyString != "n" {
+					accepted, rejected := review.Split()
+					if len(rejected) > 0 {
+						response = opencode.SessionPermissionRespondParamsResponseReject
+					}
+					if len(rejected) > 0 && len(accepted) > 0 {
+						a.app, partial = a.app.ApplyReviewedEdit(review)
 					}
-					slog.Debug("Responded to permission request", "response", resp)
-					return nil
 				}
+				a.app.SetEditReview(nil)
+				a.app.DropEditBase(permissionID)
+
+				return a, tea.Batch(tea.Sequence(a.app.RespondPermission(sessionID, permissionID, response), partial), remember)
 			}
 		}
 
+		// Selecting lines of the transcript takes every key until they are
+		// copied or the selection cancelled
+		if a.modal == nil && a.messages.Selecting() {
+			updated, cmd := a.messages.Update(msg)
+			a.messages = updated.(chat.MessagesComponent)
+			return a, cmd
+		}
+
+		// The output pane takes the scrolling keys while focused, the others
+		// still going to the prompt
+		if a.modal == nil && a.sidePane.Focused() && a.splitColumns() > 0 && a.sidePane.Update(msg, max(1, a.height/2)) {
+			return a, nil
+		}
+
+		// A search of the prompt history takes every key until the prompt
+		// found is accepted or the search cancelled
+		if a.modal == nil && a.editor.HistorySearching() {
+			updated, cmd := a.editor.Update(msg)
+			a.editor = updated.(chat.EditorComponent)
+			return a, cmd
+		}
+
 		if a.app.IsBashMode {
 			if keyString == "backspace" && a.editor.Length() == 0 {
 				a.app.IsBashMode = false
@@ -236,6 +398,40 @@ func (a Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// A stalled stream is reconnected with enter, or its response
+		// aborted with esc, while the prompt is empty
+		if _, stalled := a.app.StreamStalled(); stalled && !a.showCompletionDialog && a.editor.Value() == "" {
+			switch keyString {
+			case "enter":
+				return a, a.app.ReconnectStream()
+			case "esc":
+				return a, a.app.AbortStalled()
+			}
+		}
+
+		// A copied error is explained with enter, or dismissed with esc,
+		// while the prompt is empty. Esc carries on, so that it still
+		// interrupts the agent or leaves the vim insert mode.
+		if a.app.ClipboardError != nil && !a.showCompletionDialog && a.editor.Value() == "" {
+			switch keyString {
+			case "enter":
+				updated, cmd := a.app.ExplainClipboardError()
+				a.app = updated
+				return a, cmd
+			case "esc":
+				a.app.ClipboardError = nil
+			}
+		}
+
+		// Vim keybindings take their keys before the completions and
+		// commands, so that / or ! is a command in the normal mode and esc
+		// leaves the insert mode
+		if !a.showCompletionDialog && a.editor.VimKey(msg) {
+			updated, cmd := a.editor.Update(msg)
+			a.editor = updated.(chat.EditorComponent)
+			return a, cmd
+		}
+
 		// 3. Handle completions trigger
 		if keyString == "/" &&
 			!a.showCompletionDialog &&
@@ -256,10 +452,12 @@ func (a Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, tea.Sequence(cmds...)
 		}
 
-		// Handle file completions trigger
+		// Handle file completions trigger, where a word begins so that
+		// addresses aren't taken for mentions
 		if keyString == "@" &&
 			!a.showCompletionDialog &&
-			!a.app.IsBashMode {
+			!a.app.IsBashMode &&
+			a.editor.AtWordStart() {
 			a.showCompletionDialog = true
 
 			updated, cmd := a.editor.Update(msg)
@@ -386,17 +584,49 @@ func (a Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 			return a, tea.Batch(cmds...)
 		}
+		if columns := a.splitColumns(); columns > 0 && msg.X >= a.width-1-columns {
+			a.sidePane.Update(msg, 0)
+			return a, nil
+		}
 
 		updated, cmd := a.messages.Update(msg)
 		a.messages = updated.(chat.MessagesComponent)
 		cmds = append(cmds, cmd)
 		return a, tea.Batch(cmds...)
+	case tea.MouseClickMsg:
+		cmds = append(cmds, a.gesturePress(msg))
+	case tea.MouseMotionMsg:
+		if a.gestures != nil {
+			a.gestures.Move(msg.X, msg.Y, time.Now())
+		}
+	case tea.MouseReleaseMsg:
+		if a.gestures != nil {
+			event := a.gestures.Release(msg.X, msg.Y, time.Now())
+			if updated, cmd, handled := a.gesture(event); handled {
+				return updated, cmd
+			}
+		}
+	case GestureHoldMsg:
+		if a.gestures != nil {
+			updated, cmd, _ := a.gesture(a.gestures.Hold(msg.press, time.Now()))
+			return updated, cmd
+		}
+	case tea.FocusMsg:
+		a.app.SetFocused(true)
+		// The system may have switched to dark or light mode meanwhile
+		cmds = append(cmds, followBackground())
+	case backgroundPollMsg:
+		// Another theme ends the polling, which starts again with the
+		// system theme
+		if msg.generation == a.backgroundPolls && theme.CurrentThemeName() == "system" {
+			cmds = append(cmds, tea.RequestBackgroundColor, a.pollBackground())
+		}
+	case tea.BlurMsg:
+		a.app.SetFocused(false)
 	case tea.BackgroundColorMsg:
-		styles.Terminal = &styles.TerminalInfo{
-			Background:       msg.Color,
-			BackgroundIsDark: msg.IsDark(),
+		if !a.followBackgroundColor(msg) {
+			return a, nil
 		}
-		slog.Debug("Background color", "color", msg.String(), "isDark", msg.IsDark())
 		return a, func() tea.Msg {
 			theme.UpdateSystemTheme(
 				styles.Terminal.Background,
@@ -422,6 +652,11 @@ func (a Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case commands.ExecuteCommandMsg:
 		updated, cmd := a.executeCommand(commands.Command(msg))
 		return updated, cmd
+	case commands.CommandExecutedMsg:
+		cmds = append(cmds, a.app.HintFollowed(msg.Name))
+	case commands.ExecuteCommandArgsMsg:
+		updated, cmd := a.executeCommandArgs(msg.Command, msg.Args)
+		return updated, cmd
 	case commands.ExecuteCommandsMsg:
 		for _, command := range msg {
 			updated, cmd := a.executeCommand(command)
@@ -436,7 +671,7 @@ func (a Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Analyze prompt and recommend better model if available
 		// This is a proactive feature that runs in the background
-		if a.app.AuthBridge != nil {
+		if a.app.AuthBridge != nil && a.app.Tutorial == nil && a.app.BackgroundAllowed(intelligence.BackgroundRecommendations) {
 			cmds = append(cmds, a.app.AnalyzePromptAndRecommendModel(msg.Text))
 		}
 
@@ -499,11 +734,121 @@ func (a Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		updated, cmd := a.editor.Focus()
 		a.editor = updated.(chat.EditorComponent)
 		cmds = append(cmds, cmd)
+	case app.ClearCheckedMsg:
+		if msg.SessionID != a.app.Session.ID {
+			return a, nil
+		}
+		if len(msg.Uncommitted) > 0 {
+			a.modal = dialog.NewClearDialog(msg.Uncommitted, msg.Then)
+			return a, nil
+		}
+		return a, tea.Sequence(util.CmdHandler(app.SessionClearedMsg{}), msg.Then)
+	case app.BookmarkOpenedMsg:
+		if msg.Err != nil {
+			return a, toast.NewErrorToast("Failed to open the bookmark: " + msg.Err.Error())
+		}
+		var open []tea.Cmd
+		if msg.Session.ID != a.app.Session.ID {
+			open = append(open, util.CmdHandler(app.SessionSelectedMsg(msg.Session)))
+		}
+		open = append(open, util.CmdHandler(dialog.ScrollToMessageMsg{MessageID: msg.MessageID}))
+		return a, tea.Sequence(open...)
+	case app.UnclearedMsg:
+		if msg.Err != nil {
+			return a, toast.NewErrorToast("Failed to bring back the session: " + msg.Err.Error())
+		}
+		return a, tea.Batch(
+			util.CmdHandler(app.SessionSelectedMsg(msg.Session)),
+			toast.NewSuccessToast("Brought back "+msg.Session.Title),
+		)
 	case app.SessionClearedMsg:
+		if a.app.Session.ID != "" && len(a.app.Messages) > 0 && a.app.Tutorial == nil {
+			cmds = append(cmds, a.app.ArchiveSession(), toast.NewInfoToast("Session cleared, /unclear brings it back"))
+		}
+		cmds = append(cmds, a.app.SummarizeSession(true))
+		a.app.LeaveTutorial()
+		a.app.DropBranch()
 		a.app.Session = &opencode.Session{}
 		a.app.Messages = []app.Message{}
+		a.app.AppliedRule = nil
+		cmds = append(cmds, a.app.LoadHomeDashboard())
+	case app.MessageBranchedMsg:
+		if msg.Err != nil {
+			return a, toast.NewErrorToast("Failed to branch: " + msg.Err.Error())
+		}
+		if msg.From.ID != a.app.Session.ID {
+			return a, nil
+		}
+		a.app.StartBranch(msg)
+		cmds = append(cmds,
+			a.app.LoadHomeDashboard(),
+			toast.NewInfoToast("Edit the message and send it to start the branch. "+msg.From.Title+" stays as it was.", toast.WithTitle("Branch")),
+		)
+	case app.HomeDashboardMsg:
+		a.dashboard = &msg.Dashboard
+	case app.SessionSummaryMsg:
+		return a, a.app.ShowSessionSummary(msg)
+	case app.LoopDetectedMsg:
+		if msg.SessionID != a.app.Session.ID {
+			return a, toast.NewWarningToast(msg.Loop.Summary(), toast.WithTitle("Agent stuck, run interrupted"))
+		}
+		a.modal = dialog.NewLoopDialog(a.app, msg.Loop)
+		return a, nil
+	case app.RefactorVerifiedMsg:
+		var cmd tea.Cmd
+		a.app, cmd = a.app.ReportRefactor(msg)
+		return a, cmd
+	case app.EditBaseChangedMsg:
+		return a, tea.Batch(a.app.EditBaseChanged(msg), util.CmdHandler(app.EditReviewChangedMsg{}))
+	case app.EditConflictResolvedMsg:
+		index := slices.IndexFunc(a.app.Permissions, func(p opencode.Permission) bool {
+			return p.ID == msg.Conflict.PermissionID
+		})
+		if index < 0 {
+			return a, toast.NewWarningToast("The edit was already answered")
+		}
+		sessionID := a.app.Permissions[index].SessionID
+		a.editor.Focus()
+		a.removePermission(msg.Conflict.PermissionID)
+		a.app.SetEditReview(nil)
+		a.app.DropEditBase(msg.Conflict.PermissionID)
+		if msg.Overwrite {
+			return a, a.app.RespondPermission(sessionID, msg.Conflict.PermissionID, opencode.SessionPermissionRespondParamsResponseOnce)
+		}
+		var keep tea.Cmd
+		a.app, keep = a.app.KeepConflictingChanges(msg.Conflict)
+		return a, tea.Sequence(a.app.RespondPermission(sessionID, msg.Conflict.PermissionID, opencode.SessionPermissionRespondParamsResponseReject), keep)
+	case app.ArtifactsSavedMsg:
+		if msg.Err != nil {
+			return a, toast.NewErrorToast("Artifacts not saved: " + msg.Err.Error())
+		}
+		if len(msg.Paths) == 1 {
+			return a, toast.NewInfoToast("Saved " + msg.Paths[0])
+		}
+		if len(msg.Paths) > 1 {
+			return a, toast.NewInfoToast(fmt.Sprintf("Saved %d artifacts to %s", len(msg.Paths), filepath.Dir(msg.Paths[0])))
+		}
 	case dialog.CompletionDialogCloseMsg:
 		a.showCompletionDialog = false
+	case dialog.FilePickedMsg:
+		path := msg.Path
+		if rel, err := filepath.Rel(util.CwdPath, filepath.Join(util.RootPath, path)); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+		a.editor.AttachFile(path)
+		updated, cmd := a.editor.Focus()
+		a.editor = updated.(chat.EditorComponent)
+		return a, cmd
+	case dialog.GlyphPickedMsg:
+		a.editor.InsertAtCursor(msg.Glyph)
+		updated, cmd := a.editor.Focus()
+		a.editor = updated.(chat.EditorComponent)
+		return a, tea.Batch(cmd, a.app.UseGlyph(msg.Glyph))
+	case dialog.CompletionSelectedMsg:
+		// The directory of /cd is completed next
+		if command, ok := msg.Item.RawData.(commands.Command); ok && command.Name == commands.SessionDirCommand {
+			cmds = append(cmds, a.completeDirectories())
+		}
 	case opencode.EventListResponseEventInstallationUpdated:
 		return a, toast.NewSuccessToast(
 			"opencode updated to "+msg.Properties.Version+", restart to apply.",
@@ -526,6 +871,11 @@ func (a Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.Properties.Info.ID == a.app.Session.ID {
 			a.app.Session = &msg.Properties.Info
 		}
+	case opencode.EventListResponseEventFileEdited:
+		// Watch AI-applied files for manual rework as an implicit quality signal
+		cmds = append(cmds, a.app.TrackAppliedEdit(msg.Properties.File))
+	case app.EditWindowElapsedMsg:
+		a.app.MeasureAppliedEdit(msg)
 	case opencode.EventListResponseEventMessagePartUpdated:
 		slog.Debug("message part updated", "message", msg.Properties.Part.MessageID, "part", msg.Properties.Part.ID)
 		if msg.Properties.Part.SessionID == a.app.Session.ID {
@@ -565,6 +915,16 @@ func (a Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				a.app.Messages[messageIndex] = message
 			}
+			a.app.PublishEdit(msg.Properties.Part.AsUnion())
+			a.app.RecordEdit(msg.Properties.Part.AsUnion())
+			cmds = append(cmds, a.app.RenderPart(msg.Properties.Part.AsUnion()))
+			if tool, ok := msg.Properties.Part.AsUnion().(opencode.ToolPart); ok {
+				cmds = append(cmds, a.app.NotifyToolRun(tool))
+			}
+		}
+		// Runs of every session are guarded, not only the one in view
+		if tool, ok := msg.Properties.Part.AsUnion().(opencode.ToolPart); ok {
+			cmds = append(cmds, a.app.GuardLoop(tool))
 		}
 	case opencode.EventListResponseEventMessagePartRemoved:
 		slog.Debug("message part removed", "session", msg.Properties.SessionID, "message", msg.Properties.MessageID, "part", msg.Properties.PartID)
@@ -621,6 +981,11 @@ func (a Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 	case opencode.EventListResponseEventMessageUpdated:
+		assistant, isAssistant := msg.Properties.Info.AsUnion().(opencode.AssistantMessage)
+		a.app.FollowRun(msg.Properties.Info.AsUnion())
+		if isAssistant {
+			cmds = append(cmds, a.app.RecordUsage(assistant), a.app.AutoTitle(assistant), a.app.SaveArtifacts(assistant), a.app.VerifyRefactors(assistant))
+		}
 		if msg.Properties.Info.SessionID == a.app.Session.ID {
 			matchIndex := slices.IndexFunc(a.app.Messages, func(m app.Message) bool {
 				switch casted := m.Info.(type) {
@@ -640,7 +1005,7 @@ func (a Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-			if matchIndex == -1 {
+			if matchIndex == -1 && !a.app.MessagePagedOut(msg.Properties.Info.ID) {
 				// Extract the new message ID
 				var newMessageID string
 				switch casted := msg.Properties.Info.AsUnion().(type) {
@@ -676,26 +1041,35 @@ func (a Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Insert at the correct position
 				a.app.Messages = append(a.app.Messages[:insertIndex], append([]app.Message{newMessage}, a.app.Messages[insertIndex:]...)...)
 			}
+			cmds = append(cmds, a.app.SuggestCompact())
+			cmds = append(cmds, a.app.WatchStream())
+			if isAssistant {
+				a.app.AnnounceReply(assistant)
+				cmds = append(cmds, a.app.NotifyReply(assistant))
+			}
 		}
 	case opencode.EventListResponseEventPermissionUpdated:
 		slog.Debug("permission updated", "session", msg.Properties.SessionID, "permission", msg.Properties.ID)
+		// Requests a remembered rule covers are answered without asking
+		if rule := a.app.PermissionRule(msg.Properties); rule != nil {
+			slog.Debug("permission answered by rule", "permission", msg.Properties.ID, "rule", rule.String())
+			if rule.Allow {
+				return a, a.app.RespondPermission(msg.Properties.SessionID, msg.Properties.ID, opencode.SessionPermissionRespondParamsResponseOnce)
+			}
+			return a, tea.Batch(
+				a.app.RespondPermission(msg.Properties.SessionID, msg.Properties.ID, opencode.SessionPermissionRespondParamsResponseReject),
+				toast.NewWarningToast(msg.Properties.Title, toast.WithTitle("Denied by rule: "+rule.String())),
+			)
+		}
+		a.app.AnnouncePermission(msg.Properties)
+		cmds = append(cmds, a.app.Notify(app.NotifyPermissions, "Permission needed", msg.Properties.Title))
 		a.app.Permissions = append(a.app.Permissions, msg.Properties)
 		a.app.CurrentPermission = a.app.Permissions[0]
 		a.editor.Blur()
+		cmds = append(cmds, a.app.TrackEditBase(msg.Properties))
 	case opencode.EventListResponseEventPermissionReplied:
-		index := slices.IndexFunc(a.app.Permissions, func(p opencode.Permission) bool {
-			return p.ID == msg.Properties.PermissionID
-		})
-		if index > -1 {
-			a.app.Permissions = append(a.app.Permissions[:index], a.app.Permissions[index+1:]...)
-		}
-		if a.app.CurrentPermission.ID == msg.Properties.PermissionID {
-			if len(a.app.Permissions) > 0 {
-				a.app.CurrentPermission = a.app.Permissions[0]
-			} else {
-				a.app.CurrentPermission = opencode.Permission{}
-			}
-		}
+		a.removePermission(msg.Properties.PermissionID)
+		a.app.DropEditBase(msg.Properties.PermissionID)
 	case opencode.EventListResponseEventSessionError:
 		switch err := msg.Properties.Error.AsUnion().(type) {
 		case nil:
@@ -722,11 +1096,32 @@ func (a Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			Container: layout.Dimensions{
 				Width: container,
 			},
+			Terminal: layout.Dimensions{
+				Width:  msg.Width,
+				Height: a.terminalHeight,
+			},
+			// Resizing the window keeps the font, but ask again in case it
+			// was the font that changed
+			Cell: layout.Current.Cell,
 		}
+		cmds = append(cmds, layout.QueryPixels(), followBackground(), a.app.RenderParts())
+		updated, cmd := a.messages.ReserveLines(a.toolLogHeight())
+		a.messages = updated.(chat.MessagesComponent)
+		cmds = append(cmds, cmd, a.reserveSplit())
 	case app.SessionSelectedMsg:
+		if msg.ID != a.app.Session.ID {
+			cmds = append(cmds, a.app.SummarizeSession(true))
+		}
+		// A template only seeds a session it starts, as a branch only
+		// starts from the session it was made in
+		a.app.Template = nil
+		a.app.AppliedRule = nil
+		a.app.DropBranch()
+		a.app.LeaveTutorial()
 		updated, cmd := a.messages.Update(msg)
 		a.messages = updated.(chat.MessagesComponent)
 		cmds = append(cmds, cmd)
+		cmds = append(cmds, a.app.BroadcastPluginEvent(plugin.Event{Type: plugin.EventSession, SessionID: msg.ID}))
 
 		messages, err := a.app.ListMessages(context.Background(), msg.ID)
 		if err != nil {
@@ -734,11 +1129,92 @@ func (a Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, toast.NewErrorToast("Failed to open session")
 		}
 		a.app.Session = msg
-		a.app.Messages = messages
+		a.app.ObserveSession(*msg)
+		a.app.SetMessages(msg.ID, messages)
+		a.app.AnnounceSession()
 		cmds = append(cmds, util.CmdHandler(app.SessionLoadedMsg{}))
+		cmds = append(cmds, a.app.WatchStream(), a.app.RenderParts())
 		return a, tea.Batch(cmds...)
 	case app.SessionCreatedMsg:
 		a.app.Session = msg.Session
+	case app.StateFlushMsg:
+		return a, a.app.FlushState()
+	case toolLogTickMsg:
+		if msg.generation != a.toolLogTicks || !a.app.State.ShowToolLog {
+			return a, nil
+		}
+		return a, a.tickToolLog()
+	case app.StreamWatchMsg:
+		return a, a.app.CheckStream()
+	case app.ProjectToolsRegisteredMsg:
+		if msg.Err != nil {
+			slog.Warn("Failed to register the project's tools", "error", msg.Err)
+			return a, toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Project tools"))
+		}
+		if msg.Changed {
+			return a, toast.NewInfoToast("Restart rycode for the agent to see the changes", toast.WithTitle("Project tools registered"))
+		}
+		return a, nil
+	case app.RunLimitsTickMsg:
+		return a, tea.Batch(a.app.CheckRunLimits(), a.app.WatchRunLimits())
+	case app.RunPausedMsg:
+		if msg.SessionID != a.app.Session.ID {
+			return a, toast.NewWarningToast("Over its "+strings.Join(msg.Exceeded, " and ")+" limit", toast.WithTitle("Run paused"))
+		}
+		a.modal = dialog.NewRunPausedDialog(a.app, msg)
+		return a, nil
+	case app.RunResumedMsg:
+		var cmd tea.Cmd
+		a.app, cmd = a.app.ResumeRun(msg)
+		return a, cmd
+	case app.StreamDroppedMsg:
+		return a, a.app.StreamDropped(msg)
+	case app.StreamResumedMsg:
+		return a, a.app.StreamResumed(msg)
+	case app.StreamReconnectedMsg:
+		for _, id := range msg.Answered {
+			a.removePermission(id)
+			a.app.DropEditBase(id)
+		}
+		if msg.Err != nil {
+			slog.Error("Failed to reload messages", "error", msg.Err)
+			return a, toast.NewErrorToast("Reconnected, but failed to reload the session")
+		}
+		if msg.SessionID != a.app.Session.ID {
+			return a, nil
+		}
+		if msg.SessionID == "" {
+			return a, toast.NewSuccessToast("Reconnected")
+		}
+		if msg.Session != nil {
+			a.app.Session = msg.Session
+		}
+		a.app.SetMessages(msg.SessionID, msg.Messages)
+		return a, tea.Batch(
+			util.CmdHandler(app.SessionLoadedMsg{}),
+			a.app.WatchStream(),
+			toast.NewSuccessToast("Reconnected"),
+		)
+	case input.WindowOpEvent:
+		// The terminal answering layout.QueryPixels
+		return a, layout.HandlePixelReport(msg.Op, msg.Args)
+	case layout.PixelQueryTimeoutMsg:
+		layout.HandlePixelTimeout(msg)
+		return a, nil
+	case app.VoiceTickMsg:
+		return a, a.app.TickVoice()
+	case app.VoiceTranscribedMsg:
+		text, cmd := a.app.VoiceTranscribed(msg)
+		if text != "" {
+			a.editor.InsertText(text)
+		}
+		return a, cmd
+	case app.OlderMessagesLoadedMsg:
+		a.app.PrependOlderMessages(msg)
+		cmds = append(cmds, a.app.RenderParts())
+		if msg.Err != nil {
+			cmds = append(cmds, toast.NewErrorToast(msg.Err.Error()))
+		}
 	case dialog.ScrollToMessageMsg:
 		updated, cmd := a.messages.ScrollToMessage(msg.MessageID)
 		a.messages = updated.(chat.MessagesComponent)
@@ -773,12 +1249,38 @@ func (a Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		cmds = append(cmds, cmd)
 	case app.MessageRevertedMsg:
+		// Keep the reverted answer around so a retry can be diffed against it
+		if userMsg, ok := msg.Message.Info.(opencode.UserMessage); ok {
+			a.app.RememberResponse(userMsg.ID)
+			a.app.RecordExperimentSignal(userMsg.ID, intelligence.SignalRetry)
+		}
+		if msg.Session.ID == a.app.Session.ID {
+			a.app.Session = &msg.Session
+			cmds = append(cmds, a.app.SyncRevertedEdits())
+		}
+	case app.SessionUnrevertedMsg:
 		if msg.Session.ID == a.app.Session.ID {
 			a.app.Session = &msg.Session
+			cmds = append(cmds, a.app.SyncRevertedEdits())
 		}
 	case app.ModelSelectedMsg:
+		switched := a.app.Provider == nil || a.app.Provider.ID != msg.Provider.ID
 		a.app.Provider = &msg.Provider
 		a.app.Model = &msg.Model
+		if a.showSplash && a.splashScreen != nil {
+			a.splashScreen.SetProvider(msg.Provider.ID)
+		}
+		if msg.Provider.ID == tutorial.ProviderID {
+			// The playground's models are never remembered
+			cmds = append(cmds, a.app.TutorialAction(tutorial.ActionSwitchModel))
+			break
+		}
+		// A new provider brings the agent paired with it
+		if switched {
+			if agent := a.app.SwitchProviderAgent(msg.Provider.ID); agent != "" {
+				cmds = append(cmds, toast.NewInfoToast("Switched to the "+agent+" agent paired with "+msg.Provider.Name))
+			}
+		}
 		a.app.State.AgentModel[a.app.Agent().Name] = app.AgentModel{
 			ProviderID: msg.Provider.ID,
 			ModelID:    msg.Model.ID,
@@ -802,14 +1304,219 @@ func (a Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				"color", fmt.Sprintf("#%02X%02X%02X", brandColor.R, brandColor.G, brandColor.B))
 			cmds = append(cmds, a.tickProviderSwitch())
 		}
+	case app.BenchmarkFinishedMsg:
+		if msg.Err != nil {
+			slog.Error("Benchmark failed", "error", msg.Err)
+			return a, toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Benchmark failed"))
+		}
+		quality, err := intelligence.LoadEditQualityHistory(a.app.EditQualityPath())
+		if err != nil {
+			slog.Warn("Failed to load edit quality history", "error", err)
+		}
+		a.modal = dialog.NewBenchmarkDialog(msg.Results, quality.ChurnByModel())
+		if msg.Report != nil {
+			cmds = append(cmds, toast.NewSuccessToast("Benchmark results saved to insights"))
+		}
+	case app.RepoAnswerMsg:
+		if msg.Err != nil {
+			slog.Error("Repository question failed", "error", msg.Err)
+			return a, toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Ask failed"))
+		}
+		a.modal = dialog.NewRepoAnswerDialog(msg.Question, msg.Answer, msg.Citations)
+	case app.SecurityReviewMsg:
+		if msg.Err != nil {
+			slog.Error("Security review failed", "error", msg.Err)
+			return a, toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Security review failed"))
+		}
+		a.modal = dialog.NewSecurityReviewDialog(a.app, msg.Report)
+	case app.DuplicateSessionMsg:
+		if msg.SessionID == a.app.Session.ID && a.modal == nil {
+			a.modal = dialog.NewMergeSessionDialog(a.app, nil, &msg.Match)
+		}
+	case app.SessionMergedMsg:
+		if msg.Err != nil {
+			slog.Error("Session merge failed", "error", msg.Err)
+			cmds = append(cmds, toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Merge failed")))
+			break
+		}
+		cmds = append(cmds,
+			a.app.ApplySessionMerge(msg),
+			toast.NewSuccessToast("Merged into "+msg.Into.Title, toast.WithTitle("Sessions merged")),
+		)
+		if msg.From.ID == a.app.Session.ID {
+			cmds = append(cmds, util.CmdHandler(app.SessionSelectedMsg(&msg.Into)))
+		}
+	case app.SessionTitledMsg:
+		cmds = append(cmds, a.app.ApplySessionTitle(msg))
+	case app.GitCommittedMsg:
+		// The dialog reports the commit unless it was closed meanwhile
+		if a.modal == nil {
+			if msg.Err != nil {
+				cmds = append(cmds, toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Commit failed")))
+			} else {
+				cmds = append(cmds, toast.NewSuccessToast(msg.Summary, toast.WithTitle("Committed")))
+			}
+		}
+	case app.WorktreeSwitchedMsg:
+		if msg.Err != nil {
+			if a.modal == nil {
+				cmds = append(cmds, toast.NewErrorToast(msg.Err.Error()))
+			}
+			break
+		}
+		cmds = append(cmds,
+			a.app.ApplyWorktree(msg),
+			toast.NewSuccessToast("Working in "+msg.Dir, toast.WithTitle("Worktree switched")),
+		)
+	case app.TodosExtractedMsg:
+		if msg.Err != nil {
+			slog.Error("Action item extraction failed", "error", msg.Err)
+			return a, toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Action items failed"))
+		}
+		a.modal = dialog.NewTodosDialog(a.app, msg.Session, msg.Items)
+	case app.TodoIssuesFiledMsg:
+		if msg.Err != nil {
+			slog.Error("Filing issues failed", "error", msg.Err)
+			cmds = append(cmds, toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Filing issues failed")))
+		} else {
+			cmds = append(cmds, toast.NewSuccessToast(fmt.Sprintf("Filed %d GitHub issues", len(msg.URLs))))
+		}
+	case app.ChangelogDraftMsg:
+		if msg.Err != nil {
+			slog.Error("Changelog draft failed", "error", msg.Err)
+			return a, toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Changelog failed"))
+		}
+		changelogDialog := dialog.NewChangelogDialog(a.app, msg.Options, msg.Commits, msg.Draft)
+		a.modal = changelogDialog
+		cmds = append(cmds, changelogDialog.Init())
+	case app.PromptFailedMsg:
+		updated, cmd := a.app.Failover(msg)
+		a.app = updated
+		cmds = append(cmds, cmd)
+	case app.ClipboardErrorMsg:
+		cmds = append(cmds, a.app.HandleClipboardError(msg))
+	case app.ClipboardErrorExpiredMsg:
+		a.app.ExpireClipboardError(msg)
+	case app.ShellOutputMsg:
+		cmds = append(cmds, a.app.HandleShellOutput(msg))
+	case app.ShellInboxMsg:
+		cmds = append(cmds, a.app.CheckShellInboxes())
+	case app.WatchDueMsg:
+		cmds = append(cmds, a.app.WatchDue(msg))
+	case bridge.RequestMsg:
+		cmds = append(cmds, a.bridgeRequest(msg))
+	case app.WatchRunMsg:
+		updated, cmd := a.app.HandleWatchRun(msg)
+		a.app = updated
+		cmds = append(cmds, cmd)
+	case app.WatchPendingMsg:
+		updated, cmd := a.app.SendPendingWatchPrompt(msg)
+		a.app = updated
+		cmds = append(cmds, cmd)
+	case app.ScheduledRunMsg:
+		if msg.Result.Err != nil {
+			cmds = append(cmds, toast.NewErrorToast(msg.Result.Err.Error(), toast.WithTitle(msg.Job.Name+" failed")))
+		} else if msg.Result.SessionID != "" {
+			cmds = append(cmds, toast.NewSuccessToast("Result saved as a session", toast.WithTitle(msg.Job.Name)))
+		} else {
+			cmds = append(cmds, toast.NewSuccessToast("Result posted to the webhook", toast.WithTitle(msg.Job.Name)))
+		}
+	case app.SessionExportedMsg:
+		if msg.Err != nil {
+			slog.Error("Session export failed", "error", msg.Err)
+			return a, toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Export failed"))
+		}
+		cmds = append(cmds, toast.NewSuccessToast(strings.Join(msg.Paths, "\n"), toast.WithTitle("Session exported")))
+	case app.CompactionFailedMsg:
+		a.app.CompactionEnded(msg.SessionID)
+		if msg.Reason != "" {
+			a.app.AutoCompactFailed(msg.SessionID, time.Now())
+		}
+		return a, toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Compaction failed"))
+	case app.DatasetExportedMsg:
+		if msg.Err != nil {
+			slog.Error("Dataset export failed", "error", msg.Err)
+			return a, toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Export failed"))
+		}
+		summary := fmt.Sprintf("%d records, %d findings masked", msg.Records, msg.Redacted)
+		if msg.Skipped > 0 {
+			summary += fmt.Sprintf(", %d sessions without answers left out", msg.Skipped)
+		}
+		cmds = append(cmds, toast.NewSuccessToast(msg.Path+"\n"+summary, toast.WithTitle("Dataset exported")))
+	case app.CostsExportedMsg:
+		if msg.Err != nil {
+			return a, toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Export failed"))
+		}
+		cmds = append(cmds, toast.NewSuccessToast(msg.Path, toast.WithTitle("Costs exported")))
+	case app.PluginsLoadedMsg:
+		cmds = append(cmds, a.addPlugins(msg.Plugins...), a.app.RenderParts())
+		var failed []string
+		for _, l := range msg.Plugins {
+			if l.Enabled && l.Err != nil {
+				failed = append(failed, l.Err.Error())
+			}
+		}
+		if len(failed) > 0 {
+			cmds = append(cmds, toast.NewWarningToast(strings.Join(failed, "\n"), toast.WithTitle("Plugins failed to start")))
+		}
+	case app.PluginChangedMsg:
+		cmds = append(cmds, a.addPlugins(msg.Plugin), a.app.RenderParts())
+		switch {
+		case msg.Err != nil:
+			cmds = append(cmds, toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Plugin failed to start")))
+		case msg.Plugin.Running():
+			cmds = append(cmds, toast.NewSuccessToast(msg.Plugin.Manifest.Name+" is on"))
+		default:
+			cmds = append(cmds, toast.NewInfoToast(msg.Plugin.Manifest.Name+" is off"))
+		}
+	case app.PluginActionMsg:
+		cmds = append(cmds, a.pluginAction(msg))
+	case app.PartRenderedMsg:
+		a.app.SetPartRendering(msg)
+	case app.TutorialStartMsg:
+		updated, cmd := a.app.StartTutorial()
+		a.app = updated
+		cmds = append(cmds, cmd)
+	case app.TutorialReplyMsg:
+		cmds = append(cmds, a.app.AnswerTutorial(msg))
+	case app.TemplateSelectedMsg:
+		// The template starts a session of its own, after the current one
+		// is cleared
+		if a.app.Session.ID != "" {
+			return a, a.app.CheckClear(util.CmdHandler(msg))
+		}
+		updated, cmd := a.app.StartTemplate(msg.Template)
+		a.app = updated
+		cmds = append(cmds, cmd)
+	case app.ExportReviewMsg:
+		a.modal = dialog.NewRedactionDialog(a.app, msg)
+	case app.SessionSharedMsg:
+		if msg.Err != nil {
+			return a, toast.NewErrorToast("Failed to share session")
+		}
+		cmds = append(cmds, app.SetClipboard(msg.URL))
+		cmds = append(cmds, toast.NewSuccessToast("Share URL copied to clipboard!"))
+	case app.ReleaseCreatedMsg:
+		// The release dialog reports the result itself while it is open
+		if a.modal == nil {
+			if msg.Err != nil {
+				return a, toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Release failed"))
+			}
+			return a, toast.NewSuccessToast(msg.URL, toast.WithTitle("Release created"))
+		}
 	case app.AgentSelectedMsg:
 		updated, cmd := a.app.SwitchToAgent(msg.AgentName)
 		a.app = updated
 		cmds = append(cmds, cmd)
 	case dialog.ThemeSelectedMsg:
+		if msg.ThemeName == "system" && a.app.State.Theme != "system" {
+			a.backgroundPolls++
+			cmds = append(cmds, a.pollBackground())
+		}
 		a.app.State.Theme = msg.ThemeName
 		cmds = append(cmds, a.app.SaveState())
 	case toast.ShowToastMsg:
+		a.app.AnnounceToast(msg)
 		tm, cmd := a.toastManager.Update(msg)
 		a.toastManager = tm
 		cmds = append(cmds, cmd)
@@ -845,6 +1552,7 @@ func (a Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Update cost in background and schedule next tick
 		return a, tea.Batch(
 			a.app.UpdateCost(),
+			a.app.AutoCompact(),
 			tickEvery5Seconds(),
 		)
 	case app.CostUpdatedMsg:
@@ -1028,12 +1736,14 @@ func (a Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (a Model) View() (string, *tea.Cursor) {
+	performance.StartFrame()
+	defer performance.EndFrame()
 	t := theme.CurrentTheme()
 
 	// Show splash screen if active
 	if a.showSplash && a.splashScreen != nil {
 		splashView := a.splashScreen.View()
-		return splashView + "\n" + a.status.View(), nil
+		return a.app.FilterView(splashView + "\n" + a.status.View()), nil
 	}
 
 	// Show debugger if active
@@ -1061,7 +1771,7 @@ func (a Model) View() (string, *tea.Cursor) {
 			mainLayout = util.ConvertRGBToAnsi16Colors(mainLayout)
 		}
 
-		return mainLayout + "\n" + a.status.View(), nil
+		return a.app.FilterView(mainLayout + "\n" + a.status.View()), nil
 	}
 
 	var mainLayout string
@@ -1106,11 +1816,19 @@ func (a Model) View() (string, *tea.Cursor) {
 	cursor.Position.X += editorX
 	cursor.Position.Y += editorY
 
-	return mainLayout + "\n" + a.status.View(), cursor
+	return a.app.FilterView(mainLayout + "\n" + a.status.View()), cursor
 }
 
 func (a Model) Cleanup() {
 	a.status.Cleanup()
+	a.app.StopWatches()
+	a.app.StopClipboardWatch()
+	a.app.StopShells()
+	a.app.ClosePlugins()
+	a.app.StopProfiling()
+	a.app.StopVoice()
+	a.app.FlushStateNow()
+	accessibility.StopSpeech()
 }
 
 func (a Model) home() (string, int, int) {
@@ -1200,6 +1918,9 @@ _  _, _/_  /_/ /_/_____/ /___  / /_/ / /_/ / /  __/
 	topContent = append(topContent, taglineCentered)
 	topContent = append(topContent, "")
 	topContent = append(topContent, cmds)
+	if hint := a.homeHint(effectiveWidth); hint != "" {
+		topContent = append(topContent, "", hint)
+	}
 
 	// Calculate editor dimensions
 	editorView := a.editor.View()
@@ -1212,9 +1933,16 @@ _  _, _/_  /_/ /_/_____/ /___  / /_/ / /_/ / /  __/
 	editorY := a.height - editorHeight - 2
 	editorX := max(0, (effectiveWidth-editorWidth)/2)
 
+	// The dashboard is left out when it doesn't fit above the prompt
+	if dashboard := a.homeDashboard(effectiveWidth); dashboard != "" {
+		if lipgloss.Height(strings.Join(topContent, "\n"))+1+lipgloss.Height(dashboard) <= editorY-2 {
+			topContent = append(topContent, "", dashboard)
+		}
+	}
+
 	// Place top content
 	topRendered := lipgloss.PlaceVertical(
-		editorY - 2, // Leave space before editor
+		editorY-2, // Leave space before editor
 		lipgloss.Top,
 		strings.Join(topContent, "\n"),
 		styles.WhitespaceStyle(t.Background()),
@@ -1261,6 +1989,13 @@ _  _, _/_  /_/ /_/_____/ /___  / /_/ / /_/ / /  __/
 			overlay,
 			mainLayout,
 		)
+	} else if chip := a.clipboardChip(editorWidth); chip != "" {
+		mainLayout = layout.PlaceOverlay(
+			editorX+editorWidth-lipgloss.Width(chip),
+			editorY,
+			chip,
+			mainLayout,
+		)
 	}
 
 	return mainLayout, editorX, editorY + editorYDelta
@@ -1282,7 +2017,21 @@ func (a Model) chat() (string, int, int) {
 		styles.WhitespaceStyle(t.Background()),
 	)
 
+	if columns := a.splitColumns(); columns > 0 {
+		height := lipgloss.Height(messagesView)
+		messagesView = lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			lipgloss.PlaceHorizontal(effectiveWidth-columns, lipgloss.Left, messagesView, styles.WhitespaceStyle(t.Background())),
+			styles.NewStyle().Background(t.Background()).Width(1).Height(height).Render(""),
+			a.sidePane.View(a.app, columns-1, height),
+		)
+	}
+
 	mainLayout := messagesView + "\n" + editorView
+	if height := a.toolLogHeight(); height > 0 {
+		toolLog := chat.RenderToolLog(a.app, effectiveWidth, height, time.Now())
+		mainLayout = messagesView + "\n" + toolLog + "\n" + editorView
+	}
 	editorX := max(0, (effectiveWidth-editorWidth)/2)
 	editorY := a.height - editorHeight
 
@@ -1313,6 +2062,27 @@ func (a Model) chat() (string, int, int) {
 			overlay,
 			mainLayout,
 		)
+	} else if banner := a.stalledBanner(editorWidth); banner != "" {
+		mainLayout = layout.PlaceOverlay(
+			editorX,
+			a.height-editorHeight-lipgloss.Height(banner),
+			banner,
+			mainLayout,
+		)
+	} else if chip := a.clipboardChip(editorWidth); chip != "" {
+		mainLayout = layout.PlaceOverlay(
+			editorX+editorWidth-lipgloss.Width(chip),
+			a.height-editorHeight,
+			chip,
+			mainLayout,
+		)
+	} else if card := a.tutorialCard(editorWidth); card != "" {
+		mainLayout = layout.PlaceOverlay(
+			editorX,
+			a.height-editorHeight-lipgloss.Height(card),
+			card,
+			mainLayout,
+		)
 	}
 
 	return mainLayout, editorX, editorY
@@ -1325,14 +2095,21 @@ func (a Model) executeCommand(command commands.Command) (tea.Model, tea.Cmd) {
 		f.Close()
 	}
 
-	var cmd tea.Cmd
 	cmds := []tea.Cmd{
 		util.CmdHandler(commands.CommandExecutedMsg(command)),
 	}
+	if command.Plugin != "" {
+		cmds = append(cmds, a.app.RunPluginCommand(command, ""))
+		return a, tea.Batch(cmds...)
+	}
 	switch command.Name {
 	case commands.AppHelpCommand:
 		helpDialog := dialog.NewHelpDialog(a.app)
 		a.modal = helpDialog
+		cmds = append(cmds, a.app.TutorialAction(tutorial.ActionHelp))
+	case commands.AppShortcutsCommand:
+		a.modal = dialog.NewShortcutsDialog(a.app)
+		cmds = append(cmds, a.app.TutorialAction(tutorial.ActionHelp))
 	case commands.AgentCycleCommand:
 		// Tab: Cycle to next provider with inline cortex animation
 		updated, cmd := a.app.CycleAuthenticatedProvider()
@@ -1361,57 +2138,46 @@ func (a Model) executeCommand(command commands.Command) (tea.Model, tea.Cmd) {
 			// status.Warn("Agent is working, please wait...")
 			return a, nil
 		}
-		editor := os.Getenv("EDITOR")
-		if editor == "" {
-			return a, toast.NewErrorToast("No EDITOR set, can't open editor")
-		}
-
-		value := a.editor.Value()
-		updated, cmd := a.editor.Clear()
-		a.editor = updated.(chat.EditorComponent)
-		cmds = append(cmds, cmd)
-
-		tmpfile, err := os.CreateTemp("", "msg_*.md")
-		tmpfile.WriteString(value)
-		if err != nil {
-			slog.Error("Failed to create temp file", "error", err)
-			return a, toast.NewErrorToast("Something went wrong, couldn't open editor")
-		}
-		tmpfile.Close()
-		parts := strings.Fields(editor)
-		c := exec.Command(parts[0], append(parts[1:], tmpfile.Name())...) //nolint:gosec
-		c.Stdin = os.Stdin
-		c.Stdout = os.Stdout
-		c.Stderr = os.Stderr
-		cmd = tea.ExecProcess(c, func(err error) tea.Msg {
-			if err != nil {
-				slog.Error("Failed to open editor", "error", err)
-				return nil
-			}
-			content, err := os.ReadFile(tmpfile.Name())
-			if err != nil {
-				slog.Error("Failed to read file", "error", err)
-				return nil
-			}
-			if len(content) == 0 {
-				slog.Warn("Message is empty")
-				return nil
-			}
-			os.Remove(tmpfile.Name())
-			return app.SetEditorContentMsg{
-				Text: string(content),
-			}
-		})
-		cmds = append(cmds, cmd)
+		cmds = append(cmds, a.openEditor())
 	case commands.SessionNewCommand:
 		if a.app.Session.ID == "" {
 			return a, nil
 		}
-		cmds = append(cmds, util.CmdHandler(app.SessionClearedMsg{}))
-
-	case commands.SessionListCommand:
+		cmds = append(cmds, a.app.CheckClear(nil))
+	case commands.SessionUnclearCommand:
+		cmds = append(cmds, a.app.Unclear())
+	case commands.SessionPinCommand:
+		if a.app.Session.ID == "" {
+			return a, toast.NewInfoToast("No session to pin")
+		}
+		pinned, cmd := a.app.TogglePinnedSession(a.app.Session.ID)
+		message := "Session unpinned"
+		if pinned {
+			message = "Session pinned to the top of the session list"
+		}
+		cmds = append(cmds, cmd, toast.NewSuccessToast(message))
+
+	case commands.SessionTemplatesCommand:
+		cmds = append(cmds, a.templates(""))
+	case commands.PathRulesCommand:
+		cmds = append(cmds, a.pathRules(""))
+	case commands.TutorialCommand:
+		cmds = append(cmds, a.tutorial(""))
+	case commands.PluginsCommand:
+		a.modal = dialog.NewPluginsDialog(a.app)
+	case commands.HintsCommand:
+		cmds = append(cmds, a.hints(""))
+	case commands.SessionDirCommand:
+		cmds = append(cmds, a.changeDir(""))
+	case commands.ShellCommand:
+		cmds = append(cmds, a.shell(""))
+	case commands.SessionListCommand:
 		sessionDialog := dialog.NewSessionDialog(a.app)
 		a.modal = sessionDialog
+	case commands.SessionSearchCommand:
+		searchDialog := dialog.NewSessionSearchDialog(a.app)
+		a.modal = searchDialog
+		cmds = append(cmds, searchDialog.Init())
 	case commands.SessionTimelineCommand:
 		if a.app.Session.ID == "" {
 			return a, toast.NewErrorToast("No active session")
@@ -1422,18 +2188,7 @@ func (a Model) executeCommand(command commands.Command) (tea.Model, tea.Cmd) {
 		if a.app.Session.ID == "" {
 			return a, nil
 		}
-		response, err := a.app.Client.Session.Share(
-			context.Background(),
-			a.app.Session.ID,
-			opencode.SessionShareParams{},
-		)
-		if err != nil {
-			slog.Error("Failed to share session", "error", err)
-			return a, toast.NewErrorToast("Failed to share session")
-		}
-		shareUrl := response.Share.URL
-		cmds = append(cmds, app.SetClipboard(shareUrl))
-		cmds = append(cmds, toast.NewSuccessToast("Share URL copied to clipboard!"))
+		cmds = append(cmds, a.app.ReviewShare(a.app.Session.ID))
 	case commands.SessionUnshareCommand:
 		if a.app.Session.ID == "" {
 			return a, nil
@@ -1460,7 +2215,7 @@ func (a Model) executeCommand(command commands.Command) (tea.Model, tea.Cmd) {
 			return a, nil
 		}
 		// TODO: block until compaction is complete
-		a.app.CompactSession(context.Background())
+		cmds = append(cmds, a.app.CompactSession(context.Background()))
 	case commands.SessionChildCycleCommand:
 		if a.app.Session.ID == "" {
 			return a, nil
@@ -1594,56 +2349,7 @@ func (a Model) executeCommand(command commands.Command) (tea.Model, tea.Cmd) {
 			return app.SessionSelectedMsg(nextSession)
 		})
 	case commands.SessionExportCommand:
-		if a.app.Session.ID == "" {
-			return a, toast.NewErrorToast("No active session to export.")
-		}
-
-		// Use current conversation history
-		messages := a.app.Messages
-		if len(messages) == 0 {
-			return a, toast.NewInfoToast("No messages to export.")
-		}
-
-		// Format to Markdown
-		markdownContent := formatConversationToMarkdown(messages)
-
-		// Check if EDITOR is set
-		editor := os.Getenv("EDITOR")
-		if editor == "" {
-			return a, toast.NewErrorToast("No EDITOR set, can't open editor")
-		}
-
-		// Create and write to temp file
-		tmpfile, err := os.CreateTemp("", "conversation-*.md")
-		if err != nil {
-			slog.Error("Failed to create temp file", "error", err)
-			return a, toast.NewErrorToast("Failed to create temporary file.")
-		}
-
-		_, err = tmpfile.WriteString(markdownContent)
-		if err != nil {
-			slog.Error("Failed to write to temp file", "error", err)
-			tmpfile.Close()
-			os.Remove(tmpfile.Name())
-			return a, toast.NewErrorToast("Failed to write conversation to file.")
-		}
-		tmpfile.Close()
-
-		// Open in editor
-		parts := strings.Fields(editor)
-		c := exec.Command(parts[0], append(parts[1:], tmpfile.Name())...) //nolint:gosec
-		c.Stdin = os.Stdin
-		c.Stdout = os.Stdout
-		c.Stderr = os.Stderr
-		cmd = tea.ExecProcess(c, func(err error) tea.Msg {
-			if err != nil {
-				slog.Error("Failed to open editor for conversation", "error", err)
-			}
-			// Clean up the file after editor closes
-			os.Remove(tmpfile.Name())
-			return nil
-		})
-		cmds = append(cmds, cmd)
+		cmds = append(cmds, a.openExportInEditor())
 	case commands.ToolDetailsCommand:
 		message := "Tool details are now visible"
 		if a.messages.ToolDetailsVisible() {
@@ -1651,6 +2357,7 @@ func (a Model) executeCommand(command commands.Command) (tea.Model, tea.Cmd) {
 		}
 		cmds = append(cmds, util.CmdHandler(chat.ToggleToolDetailsMsg{}))
 		cmds = append(cmds, toast.NewInfoToast(message))
+		cmds = append(cmds, a.app.TutorialAction(tutorial.ActionToolDetails))
 	case commands.ThinkingBlocksCommand:
 		message := "Thinking blocks are now visible"
 		if a.messages.ThinkingBlocksVisible() {
@@ -1659,6 +2366,12 @@ func (a Model) executeCommand(command commands.Command) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, util.CmdHandler(chat.ToggleThinkingBlocksMsg{}))
 		cmds = append(cmds, toast.NewInfoToast(message))
 	case commands.ModelListCommand:
+		if a.app.Tutorial != nil {
+			// The dialog lists real providers; the playground only has its own models
+			cmds = append(cmds, a.app.CycleTutorialModel())
+			break
+		}
+		cmds = append(cmds, a.app.RecordInteraction(help.InteractionModelSwitch))
 		// DEBUG: Log model list command execution
 		if f, err := os.OpenFile("/tmp/rycode-debug.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644); err == nil {
 			fmt.Fprintf(f, "DEBUG: ModelListCommand executed - creating dialog\n")
@@ -1680,16 +2393,26 @@ func (a Model) executeCommand(command commands.Command) (tea.Model, tea.Cmd) {
 		a.modal = agentDialog
 	case commands.ModelCycleRecentCommand:
 		slog.Debug("ModelCycleRecentCommand triggered")
+		if a.app.Tutorial != nil {
+			cmds = append(cmds, a.app.CycleTutorialModel())
+			break
+		}
 		updated, cmd := a.app.CycleRecentModel()
 		a.app = updated
 		cmds = append(cmds, cmd)
 	case commands.ModelCycleRecentReverseCommand:
+		if a.app.Tutorial != nil {
+			cmds = append(cmds, a.app.CycleTutorialModel())
+			break
+		}
 		updated, cmd := a.app.CycleRecentModelReverse()
 		a.app = updated
 		cmds = append(cmds, cmd)
 	case commands.ThemeListCommand:
 		themeDialog := dialog.NewThemeDialog()
 		a.modal = themeDialog
+	case commands.KeybindsCommand:
+		a.modal = dialog.NewKeybindsDialog(a.app)
 	case commands.ProjectInitCommand:
 		cmds = append(cmds, a.app.InitializeProject(context.Background()))
 	case commands.InputClearCommand:
@@ -1704,6 +2427,13 @@ func (a Model) executeCommand(command commands.Command) (tea.Model, tea.Cmd) {
 		a.editor = updated.(chat.EditorComponent)
 		cmds = append(cmds, cmd)
 	case commands.InputSubmitCommand:
+		// Enter with nothing typed expands or folds the tool output in view
+		if strings.TrimSpace(a.editor.Value()) == "" && !a.app.IsBashMode && a.app.Session.ID != "" {
+			updated, cmd := a.messages.ToggleToolInView()
+			a.messages = updated.(chat.MessagesComponent)
+			cmds = append(cmds, cmd)
+			break
+		}
 		updated, cmd := a.editor.Submit()
 		a.editor = updated.(chat.EditorComponent)
 		cmds = append(cmds, cmd)
@@ -1711,6 +2441,12 @@ func (a Model) executeCommand(command commands.Command) (tea.Model, tea.Cmd) {
 		updated, cmd := a.editor.Newline()
 		a.editor = updated.(chat.EditorComponent)
 		cmds = append(cmds, cmd)
+	case commands.InputHistorySearchCommand:
+		if a.modal == nil {
+			a.editor.SearchHistory()
+		}
+	case commands.InputHistoryScopeCommand:
+		cmds = append(cmds, a.historyScope(""))
 	case commands.MessagesFirstCommand:
 		updated, cmd := a.messages.GotoTop()
 		a.messages = updated.(chat.MessagesComponent)
@@ -1736,7 +2472,27 @@ func (a Model) executeCommand(command commands.Command) (tea.Model, tea.Cmd) {
 		a.messages = updated.(chat.MessagesComponent)
 		cmds = append(cmds, cmd)
 	case commands.MessagesCopyCommand:
-		updated, cmd := a.messages.CopyLastMessage()
+		cmds = append(cmds, a.app.CopyLastMessage(app.CopyMarkdown))
+	case commands.MessagesCopyTextCommand:
+		cmds = append(cmds, a.app.CopyLastMessage(app.CopyText))
+	case commands.MessagesCopyCodeCommand:
+		cmds = append(cmds, a.app.CopyLastMessage(app.CopyCode))
+	case commands.MessagesCopyMenuCommand:
+		a.modal = dialog.NewMessageCopyDialog(a.app)
+	case commands.MessagesBookmarkCommand:
+		bookmarked, cmd, err := a.app.ToggleBookmark(a.messages.MessageInView())
+		if err != nil {
+			return a, toast.NewInfoToast("No message in view to bookmark")
+		}
+		message := "Bookmark removed"
+		if bookmarked {
+			message = "Message bookmarked"
+		}
+		cmds = append(cmds, cmd, toast.NewSuccessToast(message))
+	case commands.MessagesBookmarksCommand:
+		a.modal = dialog.NewBookmarksDialog(a.app)
+	case commands.MessagesSelectCommand:
+		updated, cmd := a.messages.StartSelection()
 		a.messages = updated.(chat.MessagesComponent)
 		cmds = append(cmds, cmd)
 	case commands.MessagesUndoCommand:
@@ -1747,99 +2503,2276 @@ func (a Model) executeCommand(command commands.Command) (tea.Model, tea.Cmd) {
 		updated, cmd := a.messages.RedoLastMessage()
 		a.messages = updated.(chat.MessagesComponent)
 		cmds = append(cmds, cmd)
+	case commands.MessagesDiffCommand:
+		if a.app.PreviousResponse == nil {
+			return a, toast.NewInfoToast("No previous answer to compare. Undo and resend a prompt first.")
+		}
+		current := app.LatestResponse(a.app.Messages)
+		if current == nil || current.UserMessageID == a.app.PreviousResponse.UserMessageID {
+			return a, toast.NewInfoToast("Waiting for a regenerated answer to compare")
+		}
+		a.modal = dialog.NewResponseDiffDialog(*a.app.PreviousResponse, *current)
+	case commands.BenchRunCommand:
+		cmds = append(cmds, a.runBenchmark(""))
+	case commands.ExperimentCommand:
+		cmds = append(cmds, a.experiment(""))
+	case commands.RefactorCommand:
+		cmds = append(cmds, a.refactor(""))
+	case commands.RepoAskCommand:
+		cmds = append(cmds, a.askRepo(""))
+	case commands.DocgenCommand:
+		cmds = append(cmds, a.docgen(""))
+	case commands.GenTestsCommand:
+		cmds = append(cmds, a.genTests(""))
+	case commands.BudgetCommand:
+		a.modal = dialog.NewBudgetDialog(a.app.Budget())
+	case commands.CostCommand:
+		costDialog := dialog.NewCostDialog(a.app)
+		a.modal = costDialog
+		cmds = append(cmds, costDialog.Init())
+	case commands.BackgroundCommand:
+		cmds = append(cmds, a.background(""))
+	case commands.ProfileCommand:
+		cmds = append(cmds, a.profile(""))
+	case commands.ScreenReaderCommand:
+		cmds = append(cmds, a.screenReader(""))
+	case commands.VoiceCommand:
+		cmds = append(cmds, a.app.ToggleVoice())
+	case commands.SecurityReviewCommand:
+		cmds = append(cmds, a.securityReview(""))
+	case commands.TodosCommand:
+		cmds = append(cmds, a.todos())
+	case commands.SessionMergeCommand:
+		cmds = append(cmds, a.mergeSession())
+	case commands.WorktreeCommand:
+		cmds = append(cmds, a.worktree(""))
+	case commands.FileViewCommand:
+		cmds = append(cmds, a.viewFile(""))
+	case commands.ThemeFontCommand:
+		cmds = append(cmds, a.themeFont(""))
+	case commands.BusyClearCommand:
+		if a.app.ClearBusy() {
+			cmds = append(cmds, toast.NewInfoToast("The session is no longer shown as busy"))
+		} else {
+			cmds = append(cmds, toast.NewInfoToast("The session isn't busy"))
+		}
+	case commands.FilePickerCommand:
+		filePicker := dialog.NewFilePickerDialog(a.app)
+		a.modal = filePicker
+		cmds = append(cmds, filePicker.Init())
+	case commands.GlyphPickerCommand:
+		glyphPicker := dialog.NewGlyphPickerDialog(a.app)
+		a.modal = glyphPicker
+		cmds = append(cmds, glyphPicker.Init())
+	case commands.EditJournalCommand:
+		a.modal = dialog.NewEditJournalDialog(a.app)
+	case commands.ContextSetCommand:
+		cmds = append(cmds, a.contextSet(""))
+	case commands.ProjectToolsCommand:
+		cmds = append(cmds, a.projectTools(""))
+	case commands.ResponseLanguageCommand:
+		cmds = append(cmds, a.responseLanguage(""))
+	case commands.AccessibilityCommand:
+		cmds = append(cmds, a.accessibilitySettings(""))
+	case commands.PermissionRulesCommand:
+		cmds = append(cmds, a.permissionRules(""))
+	case commands.NotifyCommand:
+		cmds = append(cmds, a.notify(""))
+	case commands.AutoCompactCommand:
+		cmds = append(cmds, a.autoCompact(""))
+	case commands.TimeoutsCommand:
+		cmds = append(cmds, a.timeouts(""))
+	case commands.MessagesEditCommand:
+		cmds = append(cmds, a.editMessage(""))
+	case commands.SplashCommand:
+		cmds = append(cmds, a.splash(""))
+	case commands.SessionSummaryCommand:
+		cmds = append(cmds, a.sessionSummary(""))
+	case commands.SplitPaneCommand:
+		cmds = append(cmds, a.splitPane(""))
+	case commands.ArtifactsCommand:
+		cmds = append(cmds, a.artifacts(""))
+	case commands.VerifyRefactorCommand:
+		cmds = append(cmds, a.verifyRefactor(""))
+	case commands.ColorDepthCommand:
+		cmds = append(cmds, a.colorDepth(""))
+	case commands.LoopGuardCommand:
+		cmds = append(cmds, a.loopGuard(""))
+	case commands.RunLimitsCommand:
+		cmds = append(cmds, a.runLimits(""))
+	case commands.SessionDatasetCommand:
+		cmds = append(cmds, a.app.ExportDataset(""))
+	case commands.HomeDashboardCommand:
+		cmds = append(cmds, a.homeDashboardToggle(""))
+	case commands.SplitFocusCommand:
+		if a.splitColumns() == 0 {
+			cmds = append(cmds, toast.NewInfoToast(a.splitUnavailable()))
+			break
+		}
+		a.sidePane.SetFocused(!a.sidePane.Focused())
+	case commands.SplitGrowCommand, commands.SplitShrinkCommand:
+		if a.splitColumns() == 0 {
+			cmds = append(cmds, toast.NewInfoToast(a.splitUnavailable()))
+			break
+		}
+		steps := 1
+		if command.Name == commands.SplitShrinkCommand {
+			steps = -1
+		}
+		if changed, save := a.app.ResizeSplitPane(steps); changed {
+			cmds = append(cmds, save, a.reserveSplit())
+		}
+	case commands.MessagesMenuCommand:
+		id := a.messages.MessageInView()
+		index := slices.IndexFunc(a.app.Messages, func(m app.Message) bool { return id != "" && app.MessageID(m) == id })
+		if index < 0 {
+			return a, toast.NewInfoToast("No message in view")
+		}
+		a.modal = dialog.NewMessageMenuDialog(a.app, a.app.Messages[index])
+	case commands.ToolLogCommand:
+		a.app.State.ShowToolLog = !a.app.State.ShowToolLog
+		updated, cmd := a.messages.ReserveLines(a.toolLogHeight())
+		a.messages = updated.(chat.MessagesComponent)
+		cmds = append(cmds, cmd, a.app.SaveState())
+		if a.app.State.ShowToolLog {
+			a.toolLogTicks++
+			cmds = append(cmds, a.tickToolLog())
+		}
+	case commands.GitCommand:
+		gitDialog := dialog.NewGitDialog(a.app)
+		a.modal = gitDialog
+		cmds = append(cmds, a.app.GitStatus())
+	case commands.ChangelogCommand:
+		cmds = append(cmds, a.draftChangelog(""))
+	case commands.ScheduleCommand:
+		cmds = append(cmds, a.schedule(""))
+	case commands.FailoverCommand:
+		cmds = append(cmds, a.failover(""))
+	case commands.AgentPairCommand:
+		cmds = append(cmds, a.pairAgent(""))
+	case commands.WatchCommand:
+		cmds = append(cmds, a.watch(""))
+	case commands.StatusBarCommand:
+		cmds = append(cmds, a.statusBar(""))
+	case commands.VimCommand:
+		cmds = append(cmds, a.vim(""))
+	case commands.ClipboardWatchCommand:
+		cmds = append(cmds, a.clipboardWatch(""))
 	case commands.AppExitCommand:
 		return a, tea.Quit
 	}
 	return a, tea.Batch(cmds...)
 }
 
-func NewModel(app *app.App) tea.Model {
-	commandProvider := completions.NewCommandCompletionProvider(app)
-	fileProvider := completions.NewFileContextGroup(app)
-	symbolsProvider := completions.NewSymbolsContextGroup(app)
-	agentsProvider := completions.NewAgentsContextGroup(app)
+// executeCommandArgs runs a command typed with arguments after its trigger
+func (a Model) executeCommandArgs(command commands.Command, args string) (tea.Model, tea.Cmd) {
+	if command.Plugin != "" {
+		return a, a.app.RunPluginCommand(command, args)
+	}
+	switch command.Name {
+	case commands.BenchRunCommand:
+		return a, a.runBenchmark(args)
+	case commands.ExperimentCommand:
+		cmd := a.experiment(args)
+		return a, cmd
+	case commands.RefactorCommand:
+		cmd := a.refactor(args)
+		return a, cmd
+	case commands.RepoAskCommand:
+		return a, a.askRepo(args)
+	case commands.DocgenCommand:
+		cmd := a.docgen(args)
+		return a, cmd
+	case commands.GenTestsCommand:
+		cmd := a.genTests(args)
+		return a, cmd
+	case commands.SecurityReviewCommand:
+		return a, a.securityReview(args)
+	case commands.ChangelogCommand:
+		return a, a.draftChangelog(args)
+	case commands.SessionExportCommand:
+		return a, a.exportSession(args)
+	case commands.ScheduleCommand:
+		cmd := a.schedule(args)
+		return a, cmd
+	case commands.BackgroundCommand:
+		cmd := a.background(args)
+		return a, cmd
+	case commands.ProfileCommand:
+		cmd := a.profile(args)
+		return a, cmd
+	case commands.ScreenReaderCommand:
+		cmd := a.screenReader(args)
+		return a, cmd
+	case commands.FailoverCommand:
+		cmd := a.failover(args)
+		return a, cmd
+	case commands.AgentPairCommand:
+		cmd := a.pairAgent(args)
+		return a, cmd
+	case commands.WatchCommand:
+		cmd := a.watch(args)
+		return a, cmd
+	case commands.StatusBarCommand:
+		cmd := a.statusBar(args)
+		return a, cmd
+	case commands.VimCommand:
+		cmd := a.vim(args)
+		return a, cmd
+	case commands.ClipboardWatchCommand:
+		cmd := a.clipboardWatch(args)
+		return a, cmd
+	case commands.PathRulesCommand:
+		cmd := a.pathRules(args)
+		return a, cmd
+	case commands.TutorialCommand:
+		cmd := a.tutorial(args)
+		return a, cmd
+	case commands.HintsCommand:
+		cmd := a.hints(args)
+		return a, cmd
+	case commands.SessionDirCommand:
+		cmd := a.changeDir(args)
+		return a, cmd
+	case commands.ShellCommand:
+		cmd := a.shell(args)
+		return a, cmd
+	case commands.WorktreeCommand:
+		cmd := a.worktree(args)
+		return a, cmd
+	case commands.FileViewCommand:
+		cmd := a.viewFile(args)
+		return a, cmd
+	case commands.ThemeFontCommand:
+		return a, a.themeFont(args)
+	case commands.ContextSetCommand:
+		cmd := a.contextSet(args)
+		return a, cmd
+	case commands.ProjectToolsCommand:
+		cmd := a.projectTools(args)
+		return a, cmd
+	case commands.ResponseLanguageCommand:
+		cmd := a.responseLanguage(args)
+		return a, cmd
+	case commands.AccessibilityCommand:
+		cmd := a.accessibilitySettings(args)
+		return a, cmd
+	case commands.PermissionRulesCommand:
+		cmd := a.permissionRules(args)
+		return a, cmd
+	case commands.NotifyCommand:
+		cmd := a.notify(args)
+		return a, cmd
+	case commands.AutoCompactCommand:
+		cmd := a.autoCompact(args)
+		return a, cmd
+	case commands.TimeoutsCommand:
+		cmd := a.timeouts(args)
+		return a, cmd
+	case commands.MessagesEditCommand:
+		cmd := a.editMessage(args)
+		return a, cmd
+	case commands.SplashCommand:
+		cmd := a.splash(args)
+		return a, cmd
+	case commands.SessionSummaryCommand:
+		cmd := a.sessionSummary(args)
+		return a, cmd
+	case commands.SplitPaneCommand:
+		cmd := a.splitPane(args)
+		return a, cmd
+	case commands.ArtifactsCommand:
+		cmd := a.artifacts(args)
+		return a, cmd
+	case commands.VerifyRefactorCommand:
+		cmd := a.verifyRefactor(args)
+		return a, cmd
+	case commands.ColorDepthCommand:
+		cmd := a.colorDepth(args)
+		return a, cmd
+	case commands.LoopGuardCommand:
+		cmd := a.loopGuard(args)
+		return a, cmd
+	case commands.RunLimitsCommand:
+		cmd := a.runLimits(args)
+		return a, cmd
+	case commands.SessionDatasetCommand:
+		return a, a.app.ExportDataset(args)
+	case commands.HomeDashboardCommand:
+		cmd := a.homeDashboardToggle(args)
+		return a, cmd
+	case commands.InputHistoryScopeCommand:
+		cmd := a.historyScope(args)
+		return a, cmd
+	case commands.SessionNewCommand:
+		if args != "" {
+			cmd := a.templates(args)
+			return a, cmd
+		}
+	case commands.SessionTemplatesCommand:
+		cmd := a.templates(args)
+		return a, cmd
+	}
+	return a.executeCommand(command)
+}
 
-	messages := chat.NewMessagesComponent(app)
-	editor := chat.NewEditorComponent(app)
-	completions := dialog.NewCompletionDialogComponent("/", commandProvider)
+// runBenchmark starts a benchmark run, or shows the latest saved results when
+// no suite is given and the project has no default suite
+func (a Model) runBenchmark(args string) tea.Cmd {
+	if strings.TrimSpace(args) == "" {
+		if _, err := os.Stat(filepath.Join(util.RootPath, bench.DefaultSuitePath)); err != nil {
+			history, err := intelligence.LoadBenchmarkHistory(a.app.BenchmarkHistoryPath())
+			if err != nil {
+				return toast.NewErrorToast("Failed to load benchmark results")
+			}
+			return util.CmdHandler(app.BenchmarkFinishedMsg{Results: history.LatestRun("")})
+		}
+	}
+	return tea.Batch(
+		toast.NewInfoToast("Running benchmark suite…"),
+		a.app.RunBenchmark(args),
+	)
+}
 
-	var leaderBinding *key.Binding
-	if app.Config.Keybinds.Leader != "" {
-		binding := key.NewBinding(key.WithKeys(app.Config.Keybinds.Leader))
-		leaderBinding = &binding
+// experiment handles /experiment: start and stop A/B routing, rate the last
+// routed answer, or show the report when called without a subcommand
+func (a *Model) experiment(args string) tea.Cmd {
+	subcommand, rest, _ := strings.Cut(strings.TrimSpace(args), " ")
+	switch subcommand {
+	case "start":
+		experiment, err := a.app.StartExperiment(rest)
+		if err != nil {
+			return toast.NewErrorToast(err.Error(), toast.WithTitle("Experiment not started"))
+		}
+		return toast.NewSuccessToast(
+			fmt.Sprintf("Routing %d%% of prompts to %s", experiment.SplitPercent, experiment.ArmB),
+			toast.WithTitle(experiment.Name),
+		)
+	case "stop":
+		stopped, err := a.app.StopExperiment()
+		if err != nil {
+			return toast.NewErrorToast("Failed to save experiment: " + err.Error())
+		}
+		if stopped == nil {
+			return toast.NewInfoToast("No experiment is running")
+		}
+		a.modal = dialog.NewExperimentDialog(a.app.Experiments())
+		return nil
+	case "good", "bad":
+		if !a.app.RecordExperimentFeedback(subcommand == "good") {
+			return toast.NewInfoToast("No experiment answer to rate")
+		}
+		return toast.NewSuccessToast("Feedback recorded")
+	case "":
+		a.modal = dialog.NewExperimentDialog(a.app.Experiments())
+		return nil
 	}
+	return toast.NewErrorToast("Usage: /experiment [start <a> <b> [percent] | stop | good | bad]")
+}
 
-	// Initialize splash screen with default dimensions (will be updated on first WindowSizeMsg)
-	splashModel := splash.New(80, 24)
+// refactor opens the workspace refactor dialog: it plans a new refactor when
+// given a description and resumes the saved one otherwise
+func (a *Model) refactor(args string) tea.Cmd {
+	description := strings.TrimSpace(args)
+	var plan *refactor.Plan
+	if description == "" {
+		saved, err := a.app.LoadRefactorPlan()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return toast.NewInfoToast("Describe the change: /refactor <description>")
+			}
+			return toast.NewErrorToast(err.Error(), toast.WithTitle("Failed to resume refactor"))
+		}
+		plan = saved
+	}
 
-	// Initialize inline cortex renderer for provider switching (compact size)
-	providerSwitchCortex := splash.NewCortexRenderer(40, 12)
+	refactorDialog := dialog.NewRefactorDialog(a.app, description, plan)
+	a.modal = refactorDialog
+	return refactorDialog.Init()
+}
 
-	model := &Model{
-		status:               status.NewStatusCmp(app),
-		app:                  app,
-		editor:               editor,
-		messages:             messages,
-		completions:          completions,
-		commandProvider:      commandProvider,
-		fileProvider:         fileProvider,
-		symbolsProvider:      symbolsProvider,
-		agentsProvider:       agentsProvider,
-		leaderBinding:        leaderBinding,
-		showCompletionDialog: false,
-		toastManager:         toast.NewToastManager(),
-		interruptKeyState:    InterruptKeyIdle,
-		exitKeyState:         ExitKeyIdle,
-		splashScreen:         &splashModel,
-		showSplash:           true,                          // Enable splash screen on startup
-		debugger:             debugger.New(80, 24, app.Client), // Will be updated on first WindowSizeMsg
-		providerSwitchCortex: providerSwitchCortex,
-		showProviderSwitch:   false,
-		switchOpacity:        0.0,
+// docgen plans documentation for a package and opens it in the refactor
+// review flow
+func (a *Model) docgen(args string) tea.Cmd {
+	plan, err := a.app.DocgenPlan(args)
+	if err != nil {
+		return toast.NewErrorToast(err.Error(), toast.WithTitle("Docgen"))
 	}
-
-	return model
+	docgenDialog := dialog.NewRefactorDialog(a.app, plan.Description, plan)
+	a.modal = docgenDialog
+	return docgenDialog.Init()
 }
 
-func formatConversationToMarkdown(messages []app.Message) string {
-	var builder strings.Builder
+// genTests plans tests for a file's uncovered code and opens them in the
+// refactor review flow, which runs them once kept
+func (a *Model) genTests(args string) tea.Cmd {
+	plan, err := a.app.GenTestsPlan(args)
+	if err != nil {
+		return toast.NewErrorToast(err.Error(), toast.WithTitle("Generate tests"))
+	}
+	genTestsDialog := dialog.NewRefactorDialog(a.app, plan.Description, plan)
+	a.modal = genTestsDialog
+	return genTestsDialog.Init()
+}
 
-	builder.WriteString("# Conversation History\n\n")
+// securityReview reviews files, or uncommitted changes by default, for
+// security issues without editing them
+func (a Model) securityReview(args string) tea.Cmd {
+	return tea.Batch(
+		toast.NewInfoToast("Reviewing for security issues…"),
+		a.app.SecurityReview(args),
+	)
+}
 
-	for _, msg := range messages {
-		builder.WriteString("---\n\n")
+// mergeSession offers to merge the current session into another, or to
+// link the two as related
+func (a *Model) mergeSession() tea.Cmd {
+	if a.app.Session.ID == "" {
+		return toast.NewErrorToast("No active session to merge.")
+	}
+	sessions, err := a.app.ListSessions(context.Background())
+	if err != nil {
+		return toast.NewErrorToast("Failed to list sessions: " + err.Error())
+	}
+	a.modal = dialog.NewMergeSessionDialog(a.app, app.MergeCandidates(sessions, a.app.Session.ID), nil)
+	return nil
+}
 
-		var role string
-		var timestamp time.Time
+// worktree lists the worktrees and submodules to switch to, or switches to
+// the one at the path in args
+func (a *Model) worktree(args string) tea.Cmd {
+	if a.app.IsBusy() {
+		return toast.NewWarningToast("Wait for the reply to finish before switching worktrees")
+	}
+	if dir := strings.TrimSpace(args); dir != "" {
+		return a.app.CheckClear(a.app.SwitchWorktree(dir))
+	}
+	worktreeDialog := dialog.NewWorktreeDialog(a.app)
+	a.modal = worktreeDialog
+	return a.app.ListWorktrees()
+}
 
-		switch info := msg.Info.(type) {
-		case opencode.UserMessage:
-			role = "User"
-			timestamp = time.UnixMilli(int64(info.Time.Created))
-		case opencode.AssistantMessage:
-			role = "Assistant"
-			timestamp = time.UnixMilli(int64(info.Time.Created))
-		default:
-			continue
+// viewFile opens a file at path[:line] in the viewer, or without one the
+// file the session's tools last read or changed
+func (a *Model) viewFile(args string) tea.Cmd {
+	path, line := strings.TrimSpace(args), 1
+	if path == "" {
+		var ok bool
+		if path, line, ok = a.app.LastToolFile(); !ok {
+			return toast.NewInfoToast("No file in the session's tools to view; try /view <path>")
+		}
+	} else {
+		if before, after, ok := strings.Cut(path, ":"); ok {
+			if n, err := strconv.Atoi(after); err == nil {
+				path, line = before, n
+			}
+		}
+		// Paths typed are relative to where the TUI was started
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(util.CwdPath, path)
 		}
+		if rel, err := filepath.Rel(util.RootPath, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+	viewer := dialog.NewFileViewerDialog(path, line)
+	a.modal = viewer
+	return viewer.Init()
+}
 
-		builder.WriteString(
-			fmt.Sprintf("**%s** (*%s*)\n\n", role, timestamp.Format("2006-01-02 15:04:05")),
+// themeFont turns bold or italic text off or on for the current theme, or
+// says which are on
+func (a *Model) themeFont(args string) tea.Cmd {
+	font := a.app.Font()
+	onOff := func(off bool) string {
+		if off {
+			return "off"
+		}
+		return "on"
+	}
+	fields := strings.Fields(strings.ToLower(args))
+	if len(fields) == 0 {
+		return toast.NewInfoToast(
+			fmt.Sprintf("Bold %s, italic %s; /font bold|italic [on|off] changes them", onOff(font.NoBold), onOff(font.NoItalic)),
+			toast.WithTitle("Font of "+theme.CurrentThemeName()),
 		)
+	}
+	var setting *bool
+	switch fields[0] {
+	case "bold":
+		setting = &font.NoBold
+	case "italic", "italics":
+		setting = &font.NoItalic
+	default:
+		return toast.NewErrorToast("Usage: /font bold|italic [on|off]")
+	}
+	switch {
+	case len(fields) == 1:
+		*setting = !*setting
+	case fields[1] == "on":
+		*setting = false
+	case fields[1] == "off":
+		*setting = true
+	default:
+		return toast.NewErrorToast("Usage: /font bold|italic [on|off]")
+	}
+	return tea.Batch(
+		a.app.SetFont(font),
+		toast.NewSuccessToast(fmt.Sprintf("%s %s for %s", strings.ToUpper(fields[0][:1])+fields[0][1:], onOff(*setting), theme.CurrentThemeName())),
+	)
+}
+
+// openEditor hands the prompt to $VISUAL or $EDITOR, with its pasted text
+// written out, and reads it back into the prompt once the editor exits. The
+// prompt is left as it was when the editor fails or the file is emptied.
+func (a *Model) openEditor() tea.Cmd {
+	editor := cmp.Or(os.Getenv("VISUAL"), os.Getenv("EDITOR"))
+	if editor == "" {
+		return toast.NewErrorToast("Set $EDITOR to write prompts in your editor")
+	}
+	tmpfile, err := os.CreateTemp("", "msg_*.md")
+	if err != nil {
+		slog.Error("Failed to create temp file", "error", err)
+		return toast.NewErrorToast("Something went wrong, couldn't open editor")
+	}
+	_, err = tmpfile.WriteString(a.editor.ExpandedValue())
+	tmpfile.Close()
+	if err != nil {
+		os.Remove(tmpfile.Name())
+		slog.Error("Failed to write temp file", "error", err)
+		return toast.NewErrorToast("Something went wrong, couldn't open editor")
+	}
 
-		for _, part := range msg.Parts {
-			switch p := part.(type) {
-			case opencode.TextPart:
-				builder.WriteString(p.Text + "\n\n")
-			case opencode.FilePart:
-				builder.WriteString(fmt.Sprintf("[File: %s]\n\n", p.Filename))
-			case opencode.ToolPart:
-				builder.WriteString(fmt.Sprintf("[Tool: %s]\n\n", p.Tool))
+	parts := strings.Fields(editor)
+	c := exec.Command(parts[0], append(parts[1:], tmpfile.Name())...) //nolint:gosec
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		defer os.Remove(tmpfile.Name())
+		if err != nil {
+			slog.Error("Failed to open editor", "error", err)
+			return toast.NewErrorToast("The editor exited with an error, the prompt is unchanged")()
+		}
+		content, err := os.ReadFile(tmpfile.Name())
+		if err != nil {
+			slog.Error("Failed to read file", "error", err)
+			return nil
+		}
+		// Editors end the file with a newline the prompt doesn't need
+		text := strings.TrimRight(string(content), "\r\n")
+		if strings.TrimSpace(text) == "" {
+			return nil
+		}
+		return app.SetEditorContentMsg{Text: text}
+	})
+}
+
+// contextSet opens the session's pinned context, or changes it: "add"
+// pins files, directories or URLs, "remove" unpins one, "use" switches to a
+// named set and "off" stops re-including it
+func (a *Model) contextSet(args string) tea.Cmd {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		a.modal = dialog.NewContextSetDialog(a.app)
+		return nil
+	}
+	const usage = "Usage: /context [add <path or url>... | remove <item> | use <name> | off]"
+	switch fields[0] {
+	case "add":
+		if len(fields) == 1 {
+			return toast.NewErrorToast(usage)
+		}
+		save, err := a.app.PinContext(fields[1:])
+		if err != nil {
+			return toast.NewErrorToast(err.Error())
+		}
+		return tea.Batch(save, toast.NewSuccessToast("Pinned to the context set "+a.app.ContextSetName()))
+	case "remove", "rm":
+		if len(fields) != 2 {
+			return toast.NewErrorToast(usage)
+		}
+		save, err := a.app.UnpinContext(fields[1])
+		if err != nil {
+			return toast.NewErrorToast(err.Error())
+		}
+		return tea.Batch(save, toast.NewSuccessToast("Unpinned "+fields[1]))
+	case "use":
+		if len(fields) != 2 {
+			names := a.app.ContextSetNames()
+			if len(names) == 0 {
+				return toast.NewErrorToast(usage)
 			}
+			return toast.NewInfoToast("Context sets: " + strings.Join(names, ", "))
+		}
+		return tea.Batch(a.app.UseContextSet(fields[1]), toast.NewSuccessToast("Using the context set "+fields[1]))
+	case "off":
+		if a.app.ContextSetName() == "" {
+			return toast.NewInfoToast("No context set is in use")
+		}
+		return tea.Batch(a.app.UseContextSet(""), toast.NewSuccessToast("Pinned context is no longer sent; /context use brings it back"))
+	}
+	return toast.NewErrorToast(usage)
+}
+
+// projectTools lists the project's tools, or puts the command of one with
+// the name=value arguments given in the editor in bash mode, to be run
+// under the same permissions as the agent's
+func (a *Model) projectTools(args string) tea.Cmd {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		a.modal = dialog.NewProjectToolsDialog(a.app)
+		return nil
+	}
+	tools, err := a.app.ProjectTools()
+	if err != nil {
+		return toast.NewErrorToast(err.Error())
+	}
+	i := slices.IndexFunc(tools, func(t projecttools.Tool) bool { return t.Name == fields[0] })
+	if i < 0 {
+		return toast.NewErrorToast("No project tool named " + fields[0])
+	}
+	toolArgs, err := tools[i].ParseArgs(fields[1:])
+	if err != nil {
+		return toast.NewErrorToast(err.Error())
+	}
+	command, err := tools[i].Render(toolArgs)
+	if err != nil {
+		return toast.NewErrorToast(err.Error())
+	}
+	a.app.IsBashMode = true
+	return util.CmdHandler(app.SetEditorContentMsg{Text: command})
+}
+
+// responseLanguage shows or sets the language the assistant answers in:
+// "<language>" or "off" for every session, "session <language>", "session
+// off" or "session default" for this one
+func (a *Model) responseLanguage(args string) tea.Cmd {
+	const usage = "Usage: /language [<language> | off | session <language> | session off | session default]"
+	args = strings.TrimSpace(args)
+	if args == "" {
+		language, session := a.app.ResponseLanguage()
+		switch {
+		case language == "" && session:
+			return toast.NewInfoToast("This session is answered in the language of each prompt")
+		case language == "":
+			return toast.NewInfoToast("Answers are in the language of each prompt. " + usage)
+		case session:
+			return toast.NewInfoToast("This session is answered in " + language)
+		}
+		return toast.NewInfoToast("Answers are in " + language)
+	}
+
+	if rest, ok := strings.CutPrefix(args, "session"); ok && (rest == "" || rest[0] == ' ') {
+		language := strings.TrimSpace(rest)
+		switch language {
+		case "":
+			return toast.NewErrorToast(usage)
+		case "off":
+			language = app.AnyLanguage
+		case "default":
+			language = ""
 		}
+		save, err := a.app.SetSessionLanguage(language)
+		if err != nil {
+			return toast.NewErrorToast(err.Error())
+		}
+		current, _ := a.app.ResponseLanguage()
+		if current == "" {
+			return tea.Batch(save, toast.NewSuccessToast("This session is answered in the language of each prompt"))
+		}
+		return tea.Batch(save, toast.NewSuccessToast("This session is answered in "+current))
+	}
+
+	if args == "off" {
+		args = ""
+	}
+	save, err := a.app.SetResponseLanguage(args)
+	if err != nil {
+		return toast.NewErrorToast(err.Error())
+	}
+	if a.app.State.ResponseLanguage == "" {
+		return tea.Batch(save, toast.NewSuccessToast("Answers are in the language of each prompt"))
+	}
+	return tea.Batch(save, toast.NewSuccessToast("Answers are in "+a.app.State.ResponseLanguage))
+}
+
+// toolLogHeight is the number of lines the tool log takes below the chat, 0
+// while it's hidden
+func (a Model) toolLogHeight() int {
+	if !a.app.State.ShowToolLog {
+		return 0
+	}
+	return max(6, min(16, a.height/3))
+}
+
+// tickToolLog redraws the tool log each second while it's shown, for the
+// elapsed times of the calls
+func (a Model) tickToolLog() tea.Cmd {
+	generation := a.toolLogTicks
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return toolLogTickMsg{generation: generation}
+	})
+}
+
+// todos extracts the action items left open in the current session
+func (a Model) todos() tea.Cmd {
+	if a.app.Session.ID == "" {
+		return toast.NewErrorToast("No active session to extract action items from.")
+	}
+	return tea.Batch(
+		toast.NewInfoToast("Looking for action items…"),
+		a.app.ExtractTodos(),
+	)
+}
+
+// exportSession writes the current session to files in the formats named
+// in args
+func (a Model) exportSession(args string) tea.Cmd {
+	if a.app.Session.ID == "" {
+		return toast.NewErrorToast("No active session to export.")
+	}
+	return a.app.ExportSessionFormats(a.app.Session.ID, args)
+}
+
+// openExportInEditor opens the current conversation as Markdown in $EDITOR
+func (a Model) openExportInEditor() tea.Cmd {
+	if a.app.Session.ID == "" {
+		return toast.NewErrorToast("No active session to export.")
+	}
+	if len(a.app.Messages) == 0 {
+		return toast.NewInfoToast("No messages to export.")
+	}
+
+	t := transcript.New(*a.app.Session, time.Now())
+	for _, message := range a.app.Messages {
+		t.Add(message.Info, message.Parts)
+	}
+	markdown, err := t.Render(transcript.FormatMarkdown)
+	if err != nil {
+		return toast.NewErrorToast(err.Error(), toast.WithTitle("Export failed"))
+	}
+
+	// Check if EDITOR is set
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		return toast.NewErrorToast("No EDITOR set, can't open editor")
 	}
 
-	return builder.String()
+	// Create and write to temp file
+	tmpfile, err := os.CreateTemp("", "conversation-*.md")
+	if err != nil {
+		slog.Error("Failed to create temp file", "error", err)
+		return toast.NewErrorToast("Failed to create temporary file.")
+	}
+	if _, err := tmpfile.Write(markdown); err != nil {
+		slog.Error("Failed to write to temp file", "error", err)
+		tmpfile.Close()
+		os.Remove(tmpfile.Name())
+		return toast.NewErrorToast("Failed to write conversation to file.")
+	}
+	tmpfile.Close()
+
+	// Open in editor
+	parts := strings.Fields(editor)
+	c := exec.Command(parts[0], append(parts[1:], tmpfile.Name())...) //nolint:gosec
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		if err != nil {
+			slog.Error("Failed to open editor for conversation", "error", err)
+		}
+		// Clean up the file after editor closes
+		os.Remove(tmpfile.Name())
+		return nil
+	})
+}
+
+// failover turns provider failover on or off, or shows its status and the
+// failovers of the current session
+func (a *Model) failover(args string) tea.Cmd {
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+		a.modal = dialog.NewFailoverDialog(a.app, a.app.Session.ID)
+		return nil
+	case "on":
+		return tea.Batch(a.app.SetFailover(true), toast.NewSuccessToast("Rate-limited and failed prompts will be retried on the next authenticated provider", toast.WithTitle("Failover on")))
+	case "off":
+		return tea.Batch(a.app.SetFailover(false), toast.NewInfoToast("Failover off"))
+	}
+	return toast.NewErrorToast("Usage: /failover [on|off]")
+}
+
+// background shows what automatic features spent, turns them on or off, or
+// sets their daily cap
+func (a *Model) background(args string) tea.Cmd {
+	const usage = "Usage: /background [on|off <feature>] [cap <usd>]"
+	fields := strings.Fields(strings.ToLower(args))
+	if len(fields) == 0 {
+		a.modal = dialog.NewInsightsDialog(a.app)
+		return nil
+	}
+	if len(fields) != 2 {
+		return toast.NewErrorToast(usage)
+	}
+	switch fields[0] {
+	case "on", "off":
+		feature, err := intelligence.ParseBackgroundFeature(fields[1])
+		if err != nil {
+			return toast.NewErrorToast(err.Error())
+		}
+		cmd, err := a.app.SetBackgroundEnabled(feature, fields[0] == "on")
+		if err != nil {
+			return toast.NewErrorToast(err.Error())
+		}
+		return tea.Batch(cmd, toast.NewInfoToast(feature.Name()+" "+fields[0]))
+	case "cap":
+		usd, err := strconv.ParseFloat(strings.TrimPrefix(fields[1], "$"), 64)
+		if err != nil || usd < 0 {
+			return toast.NewErrorToast(usage)
+		}
+		return tea.Batch(a.app.SetBackgroundCap(usd), toast.NewSuccessToast(fmt.Sprintf("Automatic features pause once they spend $%.2f in a day", usd)))
+	}
+	return toast.NewErrorToast(usage)
+}
+
+// profile opens the render profiler, or switches the pprof endpoint, the
+// CPU profile or allocation sampling
+func (a *Model) profile(args string) tea.Cmd {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		d := dialog.NewPerformanceDialog(a.app)
+		a.modal = d
+		return d.Init()
+	}
+	switch strings.ToLower(fields[0]) {
+	case "pprof":
+		if len(fields) > 1 && strings.EqualFold(fields[1], "off") {
+			return a.app.SetPprof(false, "")
+		}
+		addr := ""
+		if len(fields) > 1 {
+			addr = fields[1]
+		}
+		return a.app.SetPprof(true, addr)
+	case "cpu":
+		return a.app.ToggleCPUProfile()
+	case "heap":
+		return a.app.WriteHeapProfile()
+	case "allocs":
+		return a.app.SetAllocSampling(len(fields) < 2 || !strings.EqualFold(fields[1], "off"))
+	}
+	return toast.NewErrorToast("Usage: /profile [pprof [addr|off]|cpu|heap|allocs [on|off]]")
+}
+
+// screenReader switches screen reader mode, or turns it on or off
+func (a *Model) screenReader(args string) tea.Cmd {
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+		return a.app.SetScreenReader(!a.app.ScreenReader())
+	case "on":
+		return a.app.SetScreenReader(true)
+	case "off":
+		return a.app.SetScreenReader(false)
+	}
+	return toast.NewErrorToast("Usage: /screen-reader [on|off]")
+}
+
+// accessibilitySettings opens the accessibility settings, the keyboard
+// audit with /accessibility keyboard, or turns a mode on or off as
+// /accessibility zoom|keyboard-only [on|off] asks
+func (a *Model) accessibilitySettings(args string) tea.Cmd {
+	const usage = "Usage: /accessibility [keyboard | zoom [on|off] | keyboard-only [on|off]]"
+	fields := strings.Fields(strings.ToLower(args))
+	switch {
+	case len(fields) == 0:
+		a.modal = dialog.NewAccessibilityDialog(a.app)
+		return nil
+	case len(fields) == 1 && fields[0] == "keyboard":
+		a.modal = dialog.NewKeyboardAuditDialog(a.app)
+		return nil
+	case len(fields) > 2:
+		return toast.NewErrorToast(usage)
+	}
+	var on bool
+	switch fields[0] {
+	case "zoom":
+		on = !a.app.Zoom()
+	case "keyboard-only":
+		on = !a.app.KeyboardOnly()
+	default:
+		return toast.NewErrorToast(usage)
+	}
+	if len(fields) == 2 {
+		switch fields[1] {
+		case "on":
+			on = true
+		case "off":
+			on = false
+		default:
+			return toast.NewErrorToast(usage)
+		}
+	}
+	if fields[0] == "keyboard-only" {
+		return a.app.SetKeyboardOnly(on)
+	}
+	// Switching the theme redraws everything in the raised colors
+	return tea.Batch(
+		a.app.SetZoom(on),
+		util.CmdHandler(dialog.ThemeSelectedMsg{ThemeName: theme.CurrentThemeName()}),
+	)
+}
+
+// permissionRules opens the permission rules, or adds one as
+// /permissions allow|deny <tool> [pattern] asks, holding in this project
+// unless "global" follows the verb
+func (a *Model) permissionRules(args string) tea.Cmd {
+	if strings.TrimSpace(args) == "" {
+		a.modal = dialog.NewPermissionRulesDialog(a.app)
+		return nil
+	}
+	fields := strings.Fields(args)
+	project := a.app.PermissionProject()
+	if len(fields) > 1 && fields[1] == "global" {
+		fields = slices.Delete(fields, 1, 2)
+		project = ""
+	}
+	rule, err := permissions.Parse(strings.Join(fields, " "))
+	if err != nil {
+		return toast.NewErrorToast("Usage: /permissions [allow|deny [global] <tool> [pattern]]")
+	}
+	rule.Project = project
+	return tea.Batch(a.app.AddPermissionRule(rule), toast.NewSuccessToast(rule.String(), toast.WithTitle("Permission rule added")))
+}
+
+// notify describes the notifications, turns those of an event or of every
+// event on or off as /notify [event] on|off asks, or sends one with
+// /notify test
+func (a *Model) notify(args string) tea.Cmd {
+	const usage = "Usage: /notify [test | [completions|permissions|errors] on|off]"
+	fields := strings.Fields(strings.ToLower(args))
+	switch len(fields) {
+	case 0:
+		var states []string
+		for _, event := range app.NotifyEvents {
+			state := "off"
+			if a.app.NotifyEnabled(event) {
+				state = "on"
+			}
+			states = append(states, event+" "+state)
+		}
+		via := a.app.NotificationProtocol().String()
+		return toast.NewInfoToast(strings.Join(states, ", ")+"\nSent while the terminal is unfocused, with "+via, toast.WithTitle("Notifications"))
+	case 1:
+		if fields[0] == "test" {
+			return a.app.SendNotification("RyCode", "Notifications work")
+		}
+	}
+	state := fields[len(fields)-1]
+	if (state != "on" && state != "off") || len(fields) > 2 {
+		return toast.NewErrorToast(usage)
+	}
+	events := app.NotifyEvents
+	if len(fields) == 2 {
+		events = fields[:1]
+	}
+	var cmds []tea.Cmd
+	for _, event := range events {
+		cmd, err := a.app.SetNotify(event, state == "on")
+		if err != nil {
+			return toast.NewErrorToast(err.Error())
+		}
+		cmds = append(cmds, cmd)
+	}
+	cmds = append(cmds, toast.NewSuccessToast(strings.Join(events, ", ")+" notifications "+state))
+	return tea.Batch(cmds...)
+}
+
+// autoCompact shows or changes when sessions are compacted without being
+// asked
+func (a *Model) autoCompact(args string) tea.Cmd {
+	const usage = "Usage: /autocompact [on | off | at <percent> | idle <minutes>|off]"
+	policy := a.app.AutoCompactPolicy()
+	fields := strings.Fields(strings.ToLower(args))
+	switch {
+	case len(fields) == 0:
+		return toast.NewInfoToast("Auto-compact "+policy.String(), toast.WithTitle("Auto-compact"))
+	case len(fields) == 1 && (fields[0] == "on" || fields[0] == "off"):
+		policy.Off = fields[0] == "off"
+	case len(fields) == 2 && fields[0] == "at":
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(fields[1], "%"), 64)
+		if err != nil || percent < 10 || percent > 100 {
+			return toast.NewErrorToast("The threshold is a percent of the context window, from 10 to 100")
+		}
+		policy.Off = false
+		policy.Threshold = percent / 100
+	case len(fields) == 2 && fields[0] == "idle":
+		minutes := 0
+		if fields[1] != "off" {
+			d, err := time.ParseDuration(fields[1])
+			if err != nil {
+				n, nerr := strconv.Atoi(fields[1])
+				d, err = time.Duration(n)*time.Minute, nerr
+			}
+			if err != nil || d < time.Minute {
+				return toast.NewErrorToast("Idle time is in minutes, as 30 or 1h, or off")
+			}
+			minutes = int(d / time.Minute)
+		}
+		policy.Off = false
+		policy.IdleMinutes = minutes
+	default:
+		return toast.NewErrorToast(usage)
+	}
+	return tea.Batch(
+		a.app.SetAutoCompactPolicy(policy),
+		toast.NewSuccessToast("Auto-compact "+policy.String()),
+	)
+}
+
+// timeouts shows the timeouts, or sets one for every provider or one
+func (a *Model) timeouts(args string) tea.Cmd {
+	const usage = "Usage: /timeouts [provider] <class> <duration|default>"
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		var lines []string
+		for _, class := range app.TimeoutClasses() {
+			timeout := a.app.ProviderTimeout("", class)
+			lines = append(lines, fmt.Sprintf("%-8s %s", class, timeout))
+			if a.app.Provider != nil {
+				if scoped := a.app.ProviderTimeout(a.app.Provider.ID, class); scoped != timeout {
+					lines = append(lines, fmt.Sprintf("%-8s %s on %s", "", scoped, a.app.Provider.Name))
+				}
+			}
+		}
+		return toast.NewInfoToast(strings.Join(lines, "\n"), toast.WithTitle("Timeouts"))
+	}
+	if len(fields) != 2 && len(fields) != 3 {
+		return toast.NewErrorToast(usage)
+	}
+	providerID, scope := "", "every provider"
+	if len(fields) == 3 {
+		providerID, fields = fields[0], fields[1:]
+		i := slices.IndexFunc(a.app.Providers, func(p opencode.Provider) bool { return p.ID == providerID })
+		if i < 0 {
+			return toast.NewErrorToast("Unknown provider " + providerID)
+		}
+		scope = a.app.Providers[i].Name
+	}
+	class := app.TimeoutClass(strings.ToLower(fields[0]))
+	var timeout time.Duration
+	if value := strings.ToLower(fields[1]); value != "default" {
+		var err error
+		if timeout, err = time.ParseDuration(value); err != nil {
+			return toast.NewErrorToast("Timeouts are durations, as 30s or 2m, or default")
+		}
+	}
+	cmd, err := a.app.SetTimeout(providerID, class, timeout)
+	if err != nil {
+		return toast.NewErrorToast(err.Error())
+	}
+	return tea.Batch(cmd, toast.NewSuccessToast(fmt.Sprintf("The %s timeout is %s for %s", class, a.app.ProviderTimeout(providerID, class), scope)))
+}
+
+// editMessage edits the user message in view, or the one the reply in view
+// answers, in place or in a new branch. Without a message in view the last
+// one is edited.
+func (a *Model) editMessage(args string) tea.Cmd {
+	branch := false
+	switch strings.TrimSpace(args) {
+	case "":
+	case "branch":
+		branch = true
+	default:
+		return toast.NewErrorToast("Usage: /edit [branch]")
+	}
+	index := len(a.app.Messages) - 1
+	if id := a.messages.MessageInView(); id != "" {
+		index = slices.IndexFunc(a.app.Messages, func(m app.Message) bool { return app.MessageID(m) == id })
+	}
+	for ; index >= 0; index-- {
+		if _, ok := a.app.Messages[index].Info.(opencode.UserMessage); ok {
+			break
+		}
+	}
+	if index < 0 {
+		return toast.NewInfoToast("No message to edit")
+	}
+	edit := a.app.EditMessage
+	if branch {
+		edit = a.app.BranchFromMessage
+	}
+	cmd, err := edit(a.app.Messages[index])
+	if err != nil {
+		return toast.NewErrorToast(err.Error())
+	}
+	return cmd
+}
+
+// splash plays the startup splash in the current provider's colors, or
+// turns it on or off
+func (a *Model) splash(args string) tea.Cmd {
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+		screen := splash.New(a.width, a.height)
+		screen.SetProvider(a.app.StartupProviderID())
+		screen.SetReducedMotion(a.app.ReducedMotion())
+		a.splashScreen, a.showSplash = &screen, true
+		return screen.Init()
+	case "on":
+		return tea.Batch(a.app.SetSplash(true), toast.NewSuccessToast("The splash plays on startup"))
+	case "off":
+		return tea.Batch(a.app.SetSplash(false), toast.NewSuccessToast("The splash is skipped on startup"))
+	}
+	return toast.NewErrorToast("Usage: /splash [on|off]")
+}
+
+// sessionSummary shows the stats of the current session or of the sessions
+// left last, or turns the summary shown on leaving a session on or off
+func (a *Model) sessionSummary(args string) tea.Cmd {
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+		if cmd := a.app.SummarizeSession(false); cmd != nil {
+			return cmd
+		}
+		return toast.NewInfoToast("No session to summarize")
+	case "recent":
+		return a.app.RecentSessions()
+	case "on":
+		return tea.Batch(a.app.SetSessionSummary(true), toast.NewSuccessToast("Sessions are summarized when you leave them"))
+	case "off":
+		return tea.Batch(a.app.SetSessionSummary(false), toast.NewSuccessToast("Sessions are left without a summary"))
+	}
+	return toast.NewErrorToast("Usage: /summary [recent|on|off]")
+}
+
+// splitColumns is the number of columns the output pane takes beside the
+// chat, the gap between them included, 0 when the chat isn't split
+func (a Model) splitColumns() int {
+	if !a.app.State.SplitPane || a.width < layout.SplitMinWidth {
+		return 0
+	}
+	return int(float64(a.width-4)*a.app.SplitPaneShare()) + 1
+}
+
+// reserveSplit takes the output pane's columns from the messages, or gives
+// them back when the chat isn't split
+func (a *Model) reserveSplit() tea.Cmd {
+	columns := a.splitColumns()
+	if columns == 0 {
+		a.sidePane.SetFocused(false)
+	}
+	updated, cmd := a.messages.ReserveColumns(columns)
+	a.messages = updated.(chat.MessagesComponent)
+	return cmd
+}
+
+// splitUnavailable tells why the chat isn't split
+func (a Model) splitUnavailable() string {
+	if !a.app.State.SplitPane {
+		return "Turn on the split layout with /split"
+	}
+	return fmt.Sprintf("The output shows beside the chat once the terminal is %d columns wide", layout.SplitMinWidth)
+}
+
+// splitPane turns the split layout on or off, or toggles it
+func (a *Model) splitPane(args string) tea.Cmd {
+	on := !a.app.State.SplitPane
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+	case "on":
+		on = true
+	case "off":
+		on = false
+	default:
+		return toast.NewErrorToast("Usage: /split [on|off]")
+	}
+	save := a.app.SetSplitPane(on)
+	reserve := a.reserveSplit()
+	notice := toast.NewSuccessToast("The chat takes the whole width")
+	if on && a.splitColumns() == 0 {
+		notice = toast.NewInfoToast(a.splitUnavailable())
+	} else if on {
+		notice = toast.NewSuccessToast("The newest output shows beside the chat")
+	}
+	return tea.Batch(save, reserve, notice)
+}
+
+// artifacts opens the browser of the session's saved artifacts, saves them,
+// or turns saving them as answers come on or off
+func (a *Model) artifacts(args string) tea.Cmd {
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+		if a.app.Session.ID == "" {
+			return toast.NewInfoToast("No session to browse the artifacts of")
+		}
+		a.modal = dialog.NewArtifactsDialog(a.app)
+		return nil
+	case "save":
+		if a.app.Session.ID == "" {
+			return toast.NewInfoToast("No session to save the artifacts of")
+		}
+		return a.app.SaveSessionArtifacts()
+	case "on":
+		return tea.Batch(a.app.SetSaveArtifacts(true), toast.NewSuccessToast("Scripts, configs and queries of answers are saved to "+app.ArtifactsDir))
+	case "off":
+		return tea.Batch(a.app.SetSaveArtifacts(false), toast.NewSuccessToast("Artifacts are no longer saved"))
+	}
+	return toast.NewErrorToast("Usage: /artifacts [save|on|off]")
+}
+
+// verifyRefactor looks for what the last refactor of the session left
+// behind, or turns doing it after refactors on or off
+func (a *Model) verifyRefactor(args string) tea.Cmd {
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+		if a.app.Session.ID == "" {
+			return toast.NewInfoToast("No session to verify")
+		}
+		return tea.Batch(a.app.VerifyLastRefactor(), toast.NewInfoToast("Looking for leftovers of the last refactor…"))
+	case "on":
+		return tea.Batch(a.app.SetVerifyRefactors(true), toast.NewSuccessToast("Answers renaming symbols or changing signatures across files are verified"))
+	case "off":
+		return tea.Batch(a.app.SetVerifyRefactors(false), toast.NewSuccessToast("Refactors are no longer verified"))
+	}
+	return toast.NewErrorToast("Usage: /verify [on|off]")
+}
+
+// colorDepth previews the theme in each color depth, or sets the depth it
+// is drawn in
+func (a *Model) colorDepth(args string) tea.Cmd {
+	if strings.TrimSpace(args) == "" {
+		a.modal = dialog.NewColorPreviewDialog(a.app)
+		return nil
+	}
+	return a.app.SetColorDepth(args)
+}
+
+// loopGuard tells how many repetitions interrupt a run, or sets it
+func (a *Model) loopGuard(args string) tea.Cmd {
+	args = strings.ToLower(strings.TrimSpace(args))
+	switch args {
+	case "":
+		if limit := a.app.LoopLimit(); limit > 0 {
+			return toast.NewInfoToast(fmt.Sprintf("Runs are interrupted after %d repeated failing calls or back-and-forth edits", limit))
+		}
+		return toast.NewInfoToast("Runs repeating themselves are not interrupted")
+	case "off":
+		return tea.Batch(a.app.SetLoopLimit(0), toast.NewSuccessToast("Runs repeating themselves are no longer interrupted"))
+	case "on":
+		args = strconv.Itoa(loopguard.DefaultLimit)
+	}
+	limit, err := strconv.Atoi(args)
+	if err != nil || limit < 2 {
+		return toast.NewErrorToast("Usage: /loops [on|off|<repetitions, at least 2>]")
+	}
+	return tea.Batch(a.app.SetLoopLimit(limit), toast.NewSuccessToast(fmt.Sprintf("Runs are interrupted after %d repetitions", limit)))
+}
+
+// runLimits shows the budget of the current agent's runs, or sets a limit
+// of every run or of one agent's or command's
+func (a *Model) runLimits(args string) tea.Cmd {
+	const usage = "Usage: /limits [agent <name>|command <name>] <time|tools|cost> <value|off>"
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		agent := a.app.Agent().Name
+		budget := a.app.RunBudget(agent, "")
+		if budget.Unbounded() {
+			return toast.NewInfoToast("Runs of the "+agent+" agent are unbounded. "+usage, toast.WithTitle("Run limits"))
+		}
+		var lines []string
+		for _, limit := range app.RunLimitNames {
+			lines = append(lines, fmt.Sprintf("%-6s %s", limit, budget.Limit(limit)))
+		}
+		return toast.NewInfoToast(strings.Join(lines, "\n"), toast.WithTitle("Run limits of "+agent))
+	}
+	scope, name, target := "", "", "every run"
+	if fields[0] == "agent" || fields[0] == "command" {
+		if len(fields) < 2 {
+			return toast.NewErrorToast(usage)
+		}
+		scope, name = fields[0], strings.TrimPrefix(fields[1], "/")
+		target = fmt.Sprintf("runs of the %s %s", name, scope)
+		if scope == "command" {
+			target = "runs of /" + name
+		}
+		fields = fields[2:]
+	}
+	if len(fields) != 2 {
+		return toast.NewErrorToast(usage)
+	}
+	limit := strings.ToLower(fields[0])
+	cmd, err := a.app.SetRunLimit(scope, name, limit, fields[1])
+	if err != nil {
+		return toast.NewErrorToast(err.Error())
+	}
+	budget := a.app.State.RunLimits.Default
+	switch scope {
+	case "agent":
+		budget = a.app.State.RunLimits.Agents[name]
+	case "command":
+		budget = a.app.State.RunLimits.Commands[name]
+	}
+	return tea.Batch(cmd, toast.NewSuccessToast(fmt.Sprintf("The %s limit of %s is %s", limit, target, budget.Limit(limit))))
+}
+
+// followBackground asks the terminal for its background color again, which
+// changes when the system switches between dark and light mode. WSL is left
+// out, where the terminal doesn't answer.
+func followBackground() tea.Cmd {
+	if util.IsWsl() {
+		return nil
+	}
+	return tea.RequestBackgroundColor
+}
+
+// pollBackground asks for the background color after
+// backgroundPollInterval while the system theme follows it, nil with any
+// other theme
+func (a Model) pollBackground() tea.Cmd {
+	if util.IsWsl() || theme.CurrentThemeName() != "system" {
+		return nil
+	}
+	generation := a.backgroundPolls
+	return tea.Tick(backgroundPollInterval, func(time.Time) tea.Msg {
+		return backgroundPollMsg{generation: generation}
+	})
+}
+
+// followBackgroundColor takes the background color the terminal reported
+// for the terminal's, and reports whether it changed. The background is
+// asked for again and again to follow it, and only a change redraws.
+func (a *Model) followBackgroundColor(msg tea.BackgroundColorMsg) bool {
+	if a.backgroundKnown && sameColor(styles.Terminal.Background, msg.Color) {
+		return false
+	}
+	if a.backgroundKnown {
+		slog.Info("Terminal background changed", "color", msg.String(), "isDark", msg.IsDark())
+	}
+	a.backgroundKnown = true
+	compat.HasDarkBackground = msg.IsDark()
+	styles.Terminal = &styles.TerminalInfo{
+		Background:       msg.Color,
+		BackgroundIsDark: msg.IsDark(),
+	}
+	slog.Debug("Background color", "color", msg.String(), "isDark", msg.IsDark())
+	return true
+}
+
+// sameColor reports whether two colors are the same
+func sameColor(a, b color.Color) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	r1, g1, b1, a1 := a.RGBA()
+	r2, g2, b2, a2 := b.RGBA()
+	return r1 == r2 && g1 == g2 && b1 == b2 && a1 == a2
+}
+
+// removePermission drops an answered permission, asking the next one
+func (a *Model) removePermission(id string) {
+	a.app.Permissions = slices.DeleteFunc(a.app.Permissions, func(p opencode.Permission) bool {
+		return p.ID == id
+	})
+	if a.app.CurrentPermission.ID == id {
+		if len(a.app.Permissions) > 0 {
+			a.app.CurrentPermission = a.app.Permissions[0]
+		} else {
+			a.app.CurrentPermission = opencode.Permission{}
+		}
+	}
+}
+
+// pairAgent pairs an agent with the current provider, so that switching to
+// the provider switches to the agent, or lists the pairings
+func (a *Model) pairAgent(args string) tea.Cmd {
+	if a.app.Provider == nil {
+		return toast.NewErrorToast("Select a provider first")
+	}
+	provider := a.app.Provider
+	switch name := strings.TrimSpace(args); name {
+	case "":
+		var pairs []string
+		for _, p := range a.app.Providers {
+			if agent := a.app.ProviderAgent(p.ID); agent != "" {
+				pairs = append(pairs, p.Name+" → "+agent)
+			}
+		}
+		if len(pairs) == 0 {
+			return toast.NewInfoToast("No agents are paired with providers; /pair <agent> pairs one with " + provider.Name)
+		}
+		return toast.NewInfoToast(strings.Join(pairs, "\n"), toast.WithTitle("Provider agents"))
+	case "off", "none":
+		cmd, _ := a.app.SetProviderAgent(provider.ID, "")
+		return tea.Batch(cmd, toast.NewInfoToast(provider.Name+" keeps the current agent"))
+	default:
+		cmd, err := a.app.SetProviderAgent(provider.ID, name)
+		if err != nil {
+			return toast.NewErrorToast(err.Error())
+		}
+		return tea.Batch(cmd, toast.NewSuccessToast("Switching to "+provider.Name+" switches to the "+name+" agent"))
+	}
+}
+
+// bridgeRequest answers a call from an editor plugin
+func (a *Model) bridgeRequest(msg bridge.RequestMsg) tea.Cmd {
+	switch msg.Method {
+	case bridge.MethodStatus:
+		status := map[string]any{
+			"root": util.RootPath,
+			"cwd":  util.CwdPath,
+			"busy": a.app.IsBusy(),
+		}
+		if a.app.Session.ID != "" {
+			status["sessionID"] = a.app.Session.ID
+			status["title"] = a.app.Session.Title
+		}
+		if a.app.Model != nil {
+			status["model"] = a.app.Model.Name
+		}
+		msg.Reply(status, nil)
+	case bridge.MethodAttach:
+		var selection bridge.Selection
+		if err := msg.Decode(&selection); err != nil {
+			msg.Reply(nil, err)
+			return nil
+		}
+		msg.Reply(nil, a.attachFromEditor(selection))
+	case bridge.MethodPrompt:
+		var params bridge.PromptParams
+		if err := msg.Decode(&params); err != nil {
+			msg.Reply(nil, err)
+			return nil
+		}
+		if strings.TrimSpace(params.Text) == "" {
+			msg.Reply(nil, fmt.Errorf("%w: the prompt is empty", bridge.ErrInvalidParams))
+			return nil
+		}
+		if params.Selection != nil {
+			if err := checkEditorSelection(*params.Selection); err != nil {
+				msg.Reply(nil, err)
+				return nil
+			}
+		}
+		existing := a.editor.Value()
+		if existing != "" && !strings.HasSuffix(existing, " ") {
+			existing += " "
+		}
+		a.editor.SetValueWithAttachments(existing + params.Text)
+		// The selection follows the text rather than being read back from it,
+		// which would drop an attachment whose label the text doesn't hold
+		if params.Selection != nil {
+			if !strings.HasSuffix(params.Text, " ") {
+				a.editor.InsertAtCursor(" ")
+			}
+			a.attachFromEditor(*params.Selection)
+		}
+		updated, cmd := a.editor.Submit()
+		a.editor = updated.(chat.EditorComponent)
+		msg.Reply(nil, nil)
+		return cmd
+	case bridge.MethodTool:
+		return a.app.RunProjectTool(msg)
+	default:
+		msg.Reply(nil, fmt.Errorf("%w: %s", bridge.ErrMethodNotFound, msg.Method))
+	}
+	return nil
+}
+
+// attachFromEditor adds a file or selection sent by an editor plugin to the
+// prompt, with its path relative to the working directory when inside it
+func (a *Model) attachFromEditor(selection bridge.Selection) error {
+	if err := checkEditorSelection(selection); err != nil {
+		return err
+	}
+	path := selection.Path
+	if rel, err := filepath.Rel(util.CwdPath, path); err == nil && !strings.HasPrefix(rel, "..") {
+		path = rel
+	}
+	if selection.Text == "" {
+		a.editor.AttachFile(path)
+		return nil
+	}
+	a.editor.AttachSelection(path, selection.StartLine, selection.EndLine, selection.Text)
+	return nil
+}
+
+// checkEditorSelection reports what is missing from a file or selection
+// sent by an editor plugin
+func checkEditorSelection(selection bridge.Selection) error {
+	if selection.Path == "" {
+		return fmt.Errorf("%w: the path is empty", bridge.ErrInvalidParams)
+	}
+	if selection.Text != "" && (selection.StartLine < 1 || selection.EndLine < selection.StartLine) {
+		return fmt.Errorf("%w: a selection needs its startLine and endLine", bridge.ErrInvalidParams)
+	}
+	return nil
+}
+
+// statusBar shows the layout of the status bar, or changes and saves it
+func (a *Model) statusBar(args string) tea.Cmd {
+	changed, summary, err := status.Configure(a.app.State, args)
+	if err != nil {
+		return toast.NewErrorToast(err.Error(), toast.WithTitle("Status bar"))
+	}
+	if !changed {
+		return toast.NewInfoToast(summary, toast.WithTitle("Status bar"))
+	}
+	return tea.Batch(
+		a.app.SaveState(),
+		util.CmdHandler(status.LayoutChangedMsg{}),
+		toast.NewSuccessToast(summary, toast.WithTitle("Status bar")),
+	)
+}
+
+// vim turns the vim keybindings of the prompt on or off, toggling them
+// without an argument
+func (a *Model) vim(args string) tea.Cmd {
+	enabled := !a.app.State.VimMode
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+	case "on":
+		enabled = true
+	case "off":
+		enabled = false
+	default:
+		return toast.NewErrorToast("Usage: /vim [on|off]")
+	}
+	a.app.State.VimMode = enabled
+	a.editor.SetVim(enabled)
+	if enabled {
+		return tea.Batch(a.app.SaveState(), toast.NewSuccessToast("esc switches to the normal mode", toast.WithTitle("Vim keybindings on")))
+	}
+	return tea.Batch(a.app.SaveState(), toast.NewInfoToast("Vim keybindings off"))
+}
+
+// templates lists the session templates, starts a new session from the one
+// named in args, or imports or exports a template file
+func (a *Model) templates(args string) tea.Cmd {
+	subcommand, rest, _ := strings.Cut(strings.TrimSpace(args), " ")
+	rest = strings.TrimSpace(rest)
+	switch subcommand {
+	case "import":
+		if rest == "" {
+			return toast.NewErrorToast("Usage: /template import <file>")
+		}
+		template, path, err := a.app.ImportTemplate(rest)
+		if err != nil {
+			return toast.NewErrorToast(err.Error(), toast.WithTitle("Template not imported"))
+		}
+		return toast.NewSuccessToast("Saved to "+path, toast.WithTitle("Imported "+template.Name))
+	case "export":
+		if rest == "" {
+			return toast.NewErrorToast("Usage: /template export <name>")
+		}
+		path, err := a.app.ExportTemplate(rest)
+		if err != nil {
+			return toast.NewErrorToast(err.Error(), toast.WithTitle("Template not exported"))
+		}
+		return toast.NewSuccessToast("Saved to "+path, toast.WithTitle("Template exported"))
+	}
+
+	templates, err := a.app.Templates()
+	if err != nil {
+		// Broken template files are skipped; the rest are still usable
+		slog.Warn("Failed to load some templates", "error", err)
+	}
+	if subcommand == "" {
+		a.modal = dialog.NewTemplatesDialog(a.app, templates)
+		return nil
+	}
+	template, ok := sessiontemplate.Find(templates, args)
+	if !ok {
+		return toast.NewErrorToast(fmt.Sprintf("No template named %q", strings.TrimSpace(args)))
+	}
+	return util.CmdHandler(app.TemplateSelectedMsg{Template: template})
+}
+
+// clipboardWatch turns the offer to explain copied errors on or off,
+// toggling it without an argument
+func (a *Model) clipboardWatch(args string) tea.Cmd {
+	enabled := !a.app.State.ClipboardWatch
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+	case "on":
+		enabled = true
+	case "off":
+		enabled = false
+	default:
+		return toast.NewErrorToast("Usage: /clipwatch [on|off]")
+	}
+	a.app.State.ClipboardWatch = enabled
+	if !enabled {
+		a.app.StopClipboardWatch()
+		return tea.Batch(a.app.SaveState(), toast.NewInfoToast("Clipboard watch off"))
+	}
+	return tea.Batch(
+		a.app.SaveState(),
+		a.app.StartClipboardWatch(),
+		toast.NewSuccessToast("Copy an error or a stack trace to have it explained", toast.WithTitle("Clipboard watch on")),
+	)
+}
+
+// pathRules turns the project's path rules on or off, or lists them
+func (a *Model) pathRules(args string) tea.Cmd {
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "on":
+		return tea.Batch(a.app.SetPathRules(true), toast.NewSuccessToast("Agents and models follow the project's path rules", toast.WithTitle("Path rules on")))
+	case "off":
+		return tea.Batch(a.app.SetPathRules(false), toast.NewInfoToast("Path rules off"))
+	case "":
+	default:
+		return toast.NewErrorToast("Usage: /rules [on|off]")
+	}
+
+	config, err := a.app.PathRules()
+	if err != nil {
+		return toast.NewErrorToast(err.Error(), toast.WithTitle("Invalid path rules"))
+	}
+	if config == nil || len(config.Rules) == 0 {
+		return toast.NewInfoToast("Map paths to agents and models in "+pathrules.ConfigPath, toast.WithTitle("No path rules"))
+	}
+	var lines []string
+	for _, rule := range config.Rules {
+		var picks []string
+		if rule.Agent != "" {
+			picks = append(picks, rule.Agent)
+		}
+		if rule.Model != "" {
+			picks = append(picks, rule.Model)
+		}
+		lines = append(lines, rule.Label()+" → "+strings.Join(picks, ", "))
+	}
+	title := "Path rules"
+	if !a.app.PathRulesEnabled() {
+		title += " (off)"
+	} else if applied := a.app.AppliedRule; applied != nil {
+		lines = append(lines, fmt.Sprintf("Applied %s to %.0f%% of the context", applied.Rule.Label(), applied.Share*100))
+	}
+	return toast.NewInfoToast(strings.Join(lines, "\n"), toast.WithTitle(title))
+}
+
+// addPlugins adds the commands and widgets of plugins that started, and
+// removes the commands of those that stopped. The widgets of a stopped
+// plugin stay registered but draw nothing.
+func (a *Model) addPlugins(plugins ...plugin.Loaded) tea.Cmd {
+	a.app.AddPluginCommands(plugins...)
+	for _, l := range plugins {
+		for _, w := range l.Contributions.Widgets {
+			status.Register(status.PluginWidget(l.Manifest.Name, w))
+		}
+	}
+	return util.CmdHandler(status.LayoutChangedMsg{})
+}
+
+// pluginAction does what a plugin asked for in answer to an event
+func (a *Model) pluginAction(msg app.PluginActionMsg) tea.Cmd {
+	if msg.Err != nil {
+		return toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Plugin "+msg.Plugin))
+	}
+	action := msg.Action
+	if action == nil {
+		return nil
+	}
+	var cmds []tea.Cmd
+	if t := action.Toast; t != nil {
+		title := t.Title
+		if title == "" {
+			title = msg.Plugin
+		}
+		options := []toast.ToastOption{toast.WithTitle(title)}
+		switch t.Kind {
+		case "success":
+			cmds = append(cmds, toast.NewSuccessToast(t.Message, options...))
+		case "warning":
+			cmds = append(cmds, toast.NewWarningToast(t.Message, options...))
+		case "error":
+			cmds = append(cmds, toast.NewErrorToast(t.Message, options...))
+		default:
+			cmds = append(cmds, toast.NewInfoToast(t.Message, options...))
+		}
+	}
+	if action.Dialog != nil {
+		a.modal = dialog.NewPluginDialog(a.app, msg.Plugin, *action.Dialog)
+	}
+	if action.Insert != "" {
+		a.editor.SetValue(action.Insert)
+	}
+	if action.Prompt != "" {
+		cmds = append(cmds, util.CmdHandler(app.SendPrompt{Text: action.Prompt}))
+	}
+	return tea.Batch(cmds...)
+}
+
+// tutorial starts the tutorial playground, or leaves it with end
+func (a *Model) tutorial(args string) tea.Cmd {
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "", "start":
+		return util.CmdHandler(app.TutorialStartMsg{})
+	case "end", "stop", "exit":
+		updated, cmd := a.app.EndTutorial()
+		a.app = updated
+		return cmd
+	default:
+		return toast.NewErrorToast("Usage: /tutorial [end]")
+	}
+}
+
+// hints shows the current hint, dismisses it, or shows the dismissed hints
+// again
+func (a *Model) hints(args string) tea.Cmd {
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+		_, hint := a.app.Hint()
+		if hint == nil {
+			return toast.NewInfoToast("No hint right now")
+		}
+		return toast.NewInfoToast(
+			hint.Message+"\n"+hint.Action+": "+hint.Shortcut,
+			toast.WithTitle(hint.Icon+" "+hint.Title),
+		)
+	case "dismiss", "hide":
+		dismissed, cmd := a.app.DismissHint()
+		if !dismissed {
+			return toast.NewInfoToast("This hint stays until it no longer applies")
+		}
+		return cmd
+	case "reset":
+		return tea.Batch(a.app.ResetHints(), toast.NewSuccessToast("Dismissed hints will show again"))
+	default:
+		return toast.NewErrorToast("Usage: /hints [dismiss|reset]")
+	}
+}
+
+// completeDirectories opens the completions of the directory of /cd
+func (a *Model) completeDirectories() tea.Cmd {
+	trigger := "/" + a.app.Commands[commands.SessionDirCommand].PrimaryTrigger() + " "
+	a.showCompletionDialog = true
+	a.completions = dialog.NewCompletionDialogComponent(trigger, completions.NewDirectoriesProvider(a.app))
+	// A key focuses the dialog, as the key of / or @ does theirs
+	updated, cmd := a.completions.Update(tea.KeyPressMsg{})
+	a.completions = updated.(dialog.CompletionDialog)
+	return cmd
+}
+
+// changeDir changes the directory the session's shell commands run in,
+// or shows it without a directory
+func (a *Model) changeDir(args string) tea.Cmd {
+	if strings.TrimSpace(args) == "" {
+		dir := a.app.SessionDir()
+		if dir == "" {
+			return toast.NewInfoToast("Shell commands run at the project root")
+		}
+		return toast.NewInfoToast("Shell commands run in " + dir + "; /cd / goes back to the root")
+	}
+	dir, save, err := a.app.SetSessionDir(args)
+	if err != nil {
+		return toast.NewErrorToast(err.Error())
+	}
+	if dir == "" {
+		return tea.Batch(save, toast.NewSuccessToast("Shell commands run at the project root"))
+	}
+	return tea.Batch(save, toast.NewSuccessToast("Shell commands run in "+dir))
+}
+
+// shell opens the panel of the named shells. With a name it starts that
+// shell, running the rest of the arguments in it; "restart" and "close"
+// followed by a name restart or close it.
+func (a *Model) shell(args string) tea.Cmd {
+	name, command, _ := strings.Cut(strings.TrimSpace(args), " ")
+	command = strings.TrimSpace(command)
+	switch name {
+	case "":
+		if len(a.app.Shells()) == 0 {
+			return toast.NewInfoToast("Start a shell with /shell <name> [command], such as /shell server npm run dev")
+		}
+	case "restart", "close":
+		if command == "" {
+			return toast.NewErrorToast("Usage: /shell " + name + " <name>")
+		}
+		if name == "close" {
+			return a.app.CloseShell(command)
+		}
+		return a.app.RestartShell(command)
+	default:
+		cmd := a.app.RunInShell(name, command)
+		if a.app.Shell(name) == nil {
+			// It failed to start; the toast says why
+			return cmd
+		}
+		shellsDialog := dialog.NewShellsDialog(a.app, name)
+		a.modal = shellsDialog
+		return tea.Batch(cmd, shellsDialog.Init())
+	}
+	shellsDialog := dialog.NewShellsDialog(a.app, name)
+	a.modal = shellsDialog
+	return shellsDialog.Init()
+}
+
+// historyScope scopes the prompt history to the project, or to every
+// project with "global"
+func (a *Model) historyScope(args string) tea.Cmd {
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+		if a.app.State.GlobalHistory {
+			return toast.NewInfoToast("Prompts of every project are recalled; /history project recalls only this project's")
+		}
+		return toast.NewInfoToast("Prompts of this project are recalled; /history global recalls every project's")
+	case "global", "all":
+		return tea.Batch(a.app.SetGlobalHistory(true), toast.NewSuccessToast("Prompt history covers every project"))
+	case "project", "local":
+		return tea.Batch(a.app.SetGlobalHistory(false), toast.NewSuccessToast("Prompt history covers this project"))
+	default:
+		return toast.NewErrorToast("Usage: /history [global|project]")
+	}
+}
+
+// homeHint renders the current hint under the commands of the home screen
+func (a Model) homeHint(width int) string {
+	t := theme.CurrentTheme()
+	_, hint := a.app.Hint()
+	if hint == nil {
+		return ""
+	}
+	line := help.RenderInlineHint(hint, styles.NewStyle().Background(t.Background()))
+	return lipgloss.PlaceHorizontal(
+		width,
+		lipgloss.Center,
+		ansi.Truncate(line, width, "…"),
+		styles.WhitespaceStyle(t.Background()),
+	)
+}
+
+// homeDashboardToggle shows or hides the dashboard of the home screen,
+// toggling it without an argument
+func (a *Model) homeDashboardToggle(args string) tea.Cmd {
+	enabled := !a.app.HomeDashboardEnabled()
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+	case "on":
+		enabled = true
+	case "off":
+		enabled = false
+	default:
+		return toast.NewErrorToast("Usage: /dashboard [on|off]")
+	}
+	if !enabled {
+		a.dashboard = nil
+		return tea.Batch(a.app.SetHomeDashboard(false), toast.NewInfoToast("The home screen dashboard is hidden"))
+	}
+	return tea.Batch(a.app.SetHomeDashboard(true), toast.NewSuccessToast("The home screen shows the spend, recent sessions and provider health"))
+}
+
+// homeDashboard renders today's spend, the month's forecast, the recent
+// sessions and the providers' health under the commands of the home screen
+func (a Model) homeDashboard(width int) string {
+	if !a.app.HomeDashboardEnabled() || a.app.Tutorial != nil {
+		return ""
+	}
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.Background())
+	textStyle := base.Foreground(t.Text())
+	mutedStyle := base.Foreground(t.TextMuted())
+	labelStyle := mutedStyle.Width(10)
+	available := width
+	width = min(width, 64)
+
+	var lines []string
+	forecast := a.app.Budget().GetForecast()
+	spend := labelStyle.Render("Spend") + textStyle.Render(fmt.Sprintf("$%.2f today", a.app.TodaySpend())) +
+		mutedStyle.Render(fmt.Sprintf(" · $%.2f this month", forecast.CurrentSpend))
+	lines = append(lines, spend)
+	if forecast.CurrentSpend > 0 {
+		status := base.Foreground(t.Success()).Render("✓ on budget")
+		if forecast.WillExceedBudget {
+			status = base.Foreground(t.Warning()).Render(fmt.Sprintf("⚠ $%.2f over budget", forecast.ExcessAmount))
+		}
+		lines = append(lines, labelStyle.Render("Forecast")+textStyle.Render(fmt.Sprintf("$%.2f by month end ", forecast.ProjectedMonthEnd))+status)
+	}
+
+	if a.dashboard != nil && len(a.dashboard.Sessions) > 0 {
+		for i, session := range a.dashboard.Sessions {
+			label := ""
+			if i == 0 {
+				label = "Recent"
+			}
+			age := homeAge(time.UnixMilli(int64(session.Time.Updated)))
+			title := ansi.Truncate(session.Title, max(10, width-10-len(age)-2), "…")
+			lines = append(lines, labelStyle.Render(label)+textStyle.Render(title)+mutedStyle.Render("  "+age))
+		}
+	}
+
+	var providers []string
+	for _, provider := range a.app.Providers {
+		dot := mutedStyle.Render("·")
+		if a.dashboard != nil {
+			switch a.dashboard.Health[provider.ID] {
+			case "healthy":
+				dot = base.Foreground(t.Success()).Render("●")
+			case "degraded":
+				dot = base.Foreground(t.Warning()).Render("◐")
+			case "down":
+				dot = base.Foreground(t.Error()).Render("○")
+			}
+		}
+		providers = append(providers, dot+textStyle.Render(" "+provider.Name))
+	}
+	if len(providers) > 0 {
+		lines = append(lines, labelStyle.Render("Providers")+ansi.Truncate(strings.Join(providers, base.Render("  ")), width-10, "…"))
+	}
+
+	return lipgloss.PlaceHorizontal(
+		available,
+		lipgloss.Center,
+		base.Width(width).Render(strings.Join(lines, "\n")),
+		styles.WhitespaceStyle(t.Background()),
+	)
+}
+
+// homeAge tells how long ago a session of the home screen was updated
+func homeAge(updated time.Time) string {
+	elapsed := time.Since(updated)
+	switch {
+	case elapsed < time.Minute:
+		return "just now"
+	case elapsed < time.Hour:
+		return fmt.Sprintf("%dm ago", int(elapsed.Minutes()))
+	case elapsed < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(elapsed.Hours()))
+	case elapsed < 48*time.Hour:
+		return "yesterday"
+	}
+	return updated.Format("Jan 2")
+}
+
+// tutorialCard renders the current tutorial step, shown above the prompt
+// while the playground is open
+func (a Model) tutorialCard(width int) string {
+	if a.app.Tutorial == nil || a.modal != nil {
+		return ""
+	}
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundElement())
+	done, total := a.app.Tutorial.Progress()
+	step, ok := a.app.Tutorial.Step()
+	if !ok {
+		return base.Padding(0, 1).Width(width).Render(
+			base.Foreground(t.Success()).Bold(true).Render("✓ Tutorial complete  ") +
+				base.Foreground(t.TextMuted()).Render("/tutorial end goes back to your session"),
+		)
+	}
+	title := base.Foreground(t.Primary()).Bold(true).Render(fmt.Sprintf("Tutorial %d/%d · %s", done+1, total, step.Title))
+	if step.Command != "" {
+		if key := a.app.Keybind(step.Command); key != "" {
+			title += base.Foreground(t.TextMuted()).Render("  press ") + base.Foreground(t.Text()).Bold(true).Render(key)
+		}
+	}
+	hint := base.Foreground(t.TextMuted()).Width(width - 2).Render(step.Hint)
+	return base.Padding(0, 1).Width(width).Render(title + "\n" + hint)
+}
+
+// clipboardChip renders the offer to explain a copied error, shown above
+// the prompt while it is empty
+func (a Model) clipboardChip(width int) string {
+	match := a.app.ClipboardError
+	if match == nil || a.editor.Value() != "" || a.modal != nil {
+		return ""
+	}
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundElement())
+	hint := base.Foreground(t.Text()).Bold(true).Render("enter") +
+		base.Foreground(t.TextMuted()).Render(" explain  ") +
+		base.Foreground(t.Text()).Bold(true).Render("esc") +
+		base.Foreground(t.TextMuted()).Render(" dismiss")
+	label := "Copied " + match.Kind + ": "
+	room := width - lipgloss.Width(hint) - len(label) - 6
+	summary := []rune(match.Summary)
+	if room < 8 {
+		summary = nil
+	} else if len(summary) > room {
+		summary = append(summary[:room-1], '…')
+	}
+	return base.Padding(0, 1).Render(
+		base.Foreground(t.Warning()).Render("⚠ "+label) +
+			base.Foreground(t.TextMuted()).Render(string(summary)+"  ") +
+			hint,
+	)
+}
+
+// gesturePress starts following a touch, which is checked for a long press
+// once it has been held for long enough
+func (a Model) gesturePress(msg tea.MouseClickMsg) tea.Cmd {
+	if a.gestures == nil || a.modal != nil || msg.Button != tea.MouseLeft {
+		return nil
+	}
+	press := a.gestures.Press(msg.X, msg.Y, time.Now())
+	return tea.Tick(gesture.LongPressTime, func(time.Time) tea.Msg { return GestureHoldMsg{press: press} })
+}
+
+// What touch gestures do, with the commands doing the same, for the
+// keyboard audit
+func init() {
+	for _, action := range []accessibility.MouseAction{
+		{Component: "chat", Name: "Switch sessions", Mouse: "swipe left or right", Command: string(commands.SessionListCommand)},
+		{Component: "chat", Name: "Open a message's menu", Mouse: "long press the message", Command: string(commands.MessagesMenuCommand)},
+		{Component: "chat", Name: "Copy a word", Mouse: "double tap the word", Command: string(commands.MessagesSelectCommand)},
+	} {
+		accessibility.RegisterMouseAction(action)
+	}
+}
+
+// gesture acts on a touch gesture: swipes switch sessions, a long press
+// opens the menu of the message pressed, and a double tap copies the word
+// tapped. Taps and drags are left to the messages, which select text.
+func (a Model) gesture(event gesture.Event) (tea.Model, tea.Cmd, bool) {
+	if a.modal != nil || a.app.Session.ID == "" {
+		return a, nil, false
+	}
+	switch event.Kind {
+	case gesture.SwipeLeft, gesture.SwipeRight:
+		// The drag selected text on its way
+		updated, cmd := a.messages.CancelSelection()
+		a.messages = updated.(chat.MessagesComponent)
+		step := 1
+		if event.Kind == gesture.SwipeRight {
+			step = -1
+		}
+		return a, tea.Batch(cmd, a.switchSession(step)), true
+	case gesture.LongPress:
+		id := a.messages.MessageAt(event.Y)
+		index := slices.IndexFunc(a.app.Messages, func(m app.Message) bool { return id != "" && app.MessageID(m) == id })
+		if index < 0 {
+			return a, nil, true
+		}
+		updated, cmd := a.messages.CancelSelection()
+		a.messages = updated.(chat.MessagesComponent)
+		a.modal = dialog.NewMessageMenuDialog(a.app, a.app.Messages[index])
+		return a, cmd, true
+	case gesture.DoubleTap:
+		updated, cmd := a.messages.SelectWord(event.X, event.Y)
+		a.messages = updated.(chat.MessagesComponent)
+		return a, cmd, true
+	}
+	return a, nil, false
+}
+
+// switchSession opens the session step places after the current one, in
+// the order of the session list
+func (a Model) switchSession(step int) tea.Cmd {
+	current := a.app.Session.ID
+	return func() tea.Msg {
+		sessions, err := a.app.ListSessions(context.Background())
+		if err != nil {
+			slog.Error("Failed to list sessions", "error", err)
+			return toast.NewErrorToast("Failed to list sessions")()
+		}
+		var top []opencode.Session
+		for _, session := range sessions {
+			if session.ParentID == "" {
+				top = append(top, session)
+			}
+		}
+		top = a.app.PinnedFirst(top)
+		index := slices.IndexFunc(top, func(s opencode.Session) bool { return s.ID == current }) + step
+		if index < 0 || index >= len(top) {
+			return toast.NewInfoToast("No more sessions this way")()
+		}
+		return app.SessionSelectedMsg(&top[index])
+	}
+}
+
+// stalledBanner renders the notice of a stalled stream, shown above the
+// editor with its reconnect and abort actions
+func (a Model) stalledBanner(width int) string {
+	quiet, stalled := a.app.StreamStalled()
+	if !stalled || a.modal != nil {
+		return ""
+	}
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundElement())
+	label := fmt.Sprintf("⚠ Stream stalled, no events for %s  ", quiet.Truncate(time.Second))
+	hint := base.Foreground(t.Text()).Bold(true).Render("enter") +
+		base.Foreground(t.TextMuted()).Render(" reconnect  ") +
+		base.Foreground(t.Text()).Bold(true).Render("esc") +
+		base.Foreground(t.TextMuted()).Render(" abort")
+	if a.editor.Value() != "" {
+		hint = base.Foreground(t.TextMuted()).Render("clear the prompt to reconnect or abort")
+	}
+	return base.Padding(0, 1).Width(width).Render(
+		base.Foreground(t.Warning()).Render(label) + hint,
+	)
+}
+
+// watch manages the watches of this TUI: without arguments it lists them,
+// add starts one, and run and remove act on a single watch
+func (a *Model) watch(args string) tea.Cmd {
+	args = strings.TrimSpace(args)
+	action, rest, _ := strings.Cut(args, " ")
+	switch {
+	case args == "" || action == "list":
+		a.modal = dialog.NewWatchDialog(a.app)
+		return nil
+	case action == "add" || strings.Contains(args, "=>"):
+		if action == "add" {
+			args = rest
+		}
+		w, cmd, err := a.app.AddWatch(args)
+		if err != nil {
+			return toast.NewErrorToast(err.Error(), toast.WithTitle("Watch"))
+		}
+		return tea.Batch(cmd, toast.NewSuccessToast(
+			fmt.Sprintf("%s [%s]. Watches stop when RyCode exits.", w.Trigger.Describe(), w.Trigger.ID),
+			toast.WithTitle("Watching "+w.Trigger.Name),
+		))
+	case action == "run":
+		if cmd := a.app.RunWatch(strings.TrimSpace(rest)); cmd != nil {
+			return cmd
+		}
+	case action == "remove" || action == "delete" || action == "stop":
+		if err := a.app.RemoveWatch(strings.TrimSpace(rest)); err != nil {
+			return toast.NewErrorToast(err.Error(), toast.WithTitle("Watch"))
+		}
+		return toast.NewInfoToast("Stopped watching")
+	}
+	return toast.NewErrorToast("Usage: /watch [add|run|remove] [id]", toast.WithTitle("Watch"))
+}
+
+// schedule manages scheduled prompts: without arguments it lists them, and
+// add, run, pause, resume and remove act on a single prompt
+func (a *Model) schedule(args string) tea.Cmd {
+	args = strings.TrimSpace(args)
+	action, rest, _ := strings.Cut(args, " ")
+	switch {
+	case args == "" || action == "list":
+		a.modal = dialog.NewScheduleDialog(a.app)
+		return nil
+	case action == "add" || strings.Contains(args, "|"):
+		if action == "add" {
+			args = rest
+		}
+		job, err := a.app.AddSchedule(args)
+		if err != nil {
+			return toast.NewErrorToast(err.Error(), toast.WithTitle("Schedule"))
+		}
+		return toast.NewSuccessToast(
+			fmt.Sprintf("Next run %s. Scheduled prompts run while `rycode daemon` is running.", job.Next().Format("Mon Jan 2 15:04")),
+			toast.WithTitle("Scheduled "+job.Name),
+		)
+	}
+
+	id := strings.TrimSpace(rest)
+	jobs, err := a.app.Schedules().Jobs()
+	if err != nil {
+		return toast.NewErrorToast(err.Error(), toast.WithTitle("Schedule"))
+	}
+	var job *schedule.Job
+	for i := range jobs {
+		if jobs[i].ID == id {
+			job = &jobs[i]
+		}
+	}
+	if job == nil {
+		return toast.NewErrorToast("Usage: /schedule [add|run|pause|resume|remove] [id]", toast.WithTitle("Schedule"))
+	}
+	store := a.app.Schedules()
+	switch action {
+	case "run":
+		return tea.Batch(toast.NewInfoToast("Running "+job.Name+"…"), a.app.RunScheduleNow(*job))
+	case "pause", "resume":
+		paused := action == "pause"
+		if err := store.Modify(job.ID, func(j *schedule.Job) { j.Paused = paused }); err != nil {
+			return toast.NewErrorToast(err.Error())
+		}
+		if paused {
+			return toast.NewInfoToast("Paused " + job.Name)
+		}
+		return toast.NewInfoToast("Resumed " + job.Name)
+	case "remove", "delete":
+		if err := store.Remove(job.ID); err != nil {
+			return toast.NewErrorToast(err.Error())
+		}
+		return toast.NewInfoToast("Removed " + job.Name)
+	}
+	return toast.NewErrorToast("Usage: /schedule [add|run|pause|resume|remove] [id]", toast.WithTitle("Schedule"))
+}
+
+// draftChangelog drafts release notes for a range of commits
+func (a Model) draftChangelog(args string) tea.Cmd {
+	return tea.Batch(
+		toast.NewInfoToast("Drafting release notes…"),
+		a.app.DraftChangelog(args),
+	)
+}
+
+// askRepo answers a question about the codebase without editing it
+func (a Model) askRepo(args string) tea.Cmd {
+	question := strings.TrimSpace(args)
+	if question == "" {
+		return toast.NewInfoToast("Ask a question: /ask <question>")
+	}
+	return tea.Batch(
+		toast.NewInfoToast("Searching the repository…"),
+		a.app.AskRepo(question),
+	)
+}
+
+func NewModel(app *app.App) tea.Model {
+	commandProvider := completions.NewCommandCompletionProvider(app)
+	fileProvider := completions.NewFileContextGroup(app)
+	symbolsProvider := completions.NewSymbolsContextGroup(app)
+	agentsProvider := completions.NewAgentsContextGroup(app)
+
+	messages := chat.NewMessagesComponent(app)
+	editor := chat.NewEditorComponent(app)
+	completions := dialog.NewCompletionDialogComponent("/", commandProvider)
+
+	var leaderBinding *key.Binding
+	if app.Config.Keybinds.Leader != "" {
+		binding := key.NewBinding(key.WithKeys(app.Config.Keybinds.Leader))
+		leaderBinding = &binding
+	}
+
+	// Initialize splash screen with default dimensions (will be updated on first WindowSizeMsg),
+	// in the colors of the provider the TUI is likely to start with
+	splashModel := splash.New(80, 24)
+	splashModel.SetProvider(app.StartupProviderID())
+	splashModel.SetReducedMotion(app.ReducedMotion())
+
+	// Initialize inline cortex renderer for provider switching (compact size)
+	providerSwitchCortex := splash.NewCortexRenderer(40, 12)
+
+	model := &Model{
+		status:               status.NewStatusCmp(app),
+		app:                  app,
+		editor:               editor,
+		messages:             messages,
+		completions:          completions,
+		commandProvider:      commandProvider,
+		fileProvider:         fileProvider,
+		symbolsProvider:      symbolsProvider,
+		agentsProvider:       agentsProvider,
+		leaderBinding:        leaderBinding,
+		showCompletionDialog: false,
+		toastManager:         toast.NewToastManager(),
+		interruptKeyState:    InterruptKeyIdle,
+		exitKeyState:         ExitKeyIdle,
+		splashScreen:         &splashModel,
+		showSplash:           app.SplashEnabled(),
+		debugger:             debugger.New(80, 24, app.Client), // Will be updated on first WindowSizeMsg
+		providerSwitchCortex: providerSwitchCortex,
+		showProviderSwitch:   false,
+		switchOpacity:        0.0,
+		sidePane:             chat.NewSidePane(),
+	}
+	if gesture.Enabled() {
+		model.gestures = gesture.New()
+	}
+	if model.showSplash {
+		app.SplashShown()
+	}
+
+	return model
 }
 
 // tickProviderSwitch returns a tick command for the provider switch cortex animation
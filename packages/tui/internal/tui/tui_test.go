@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"image/color"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2/compat"
+	"github.com/aaronmrosenthal/rycode/internal/styles"
+	"github.com/aaronmrosenthal/rycode/internal/theme"
+)
+
+func TestFollowBackgroundColor(t *testing.T) {
+	saved, savedDark := styles.Terminal, compat.HasDarkBackground
+	t.Cleanup(func() {
+		styles.Terminal = saved
+		compat.HasDarkBackground = savedDark
+	})
+
+	var a Model
+	dark := tea.BackgroundColorMsg{Color: color.RGBA{R: 0x1e, G: 0x1e, B: 0x2e, A: 0xff}}
+	if !a.followBackgroundColor(dark) || !compat.HasDarkBackground || !styles.Terminal.BackgroundIsDark {
+		t.Fatal("the first report of a dark background wasn't taken")
+	}
+	if a.followBackgroundColor(dark) {
+		t.Error("reporting the same background again redraws")
+	}
+	light := tea.BackgroundColorMsg{Color: color.RGBA{R: 0xfa, G: 0xfa, B: 0xfa, A: 0xff}}
+	if !a.followBackgroundColor(light) || compat.HasDarkBackground {
+		t.Error("the switch to a light background wasn't followed")
+	}
+}
+
+func TestPollBackgroundOnlyWithSystemTheme(t *testing.T) {
+	saved, savedSystem := theme.CurrentThemeName(), theme.GetTheme("system")
+	t.Cleanup(func() {
+		theme.UnregisterTheme("test-fixed")
+		if savedSystem != nil {
+			theme.RegisterTheme("system", savedSystem)
+		} else {
+			theme.UnregisterTheme("system")
+		}
+		if saved != "" {
+			theme.SetTheme(saved)
+		}
+	})
+
+	theme.UpdateSystemTheme(color.Black, true)
+	theme.RegisterTheme("test-fixed", theme.GetTheme("system"))
+	var a Model
+	if err := theme.SetTheme("test-fixed"); err != nil {
+		t.Fatal(err)
+	}
+	if a.pollBackground() != nil {
+		t.Error("the background is polled with a theme that doesn't follow it")
+	}
+	if err := theme.SetTheme("system"); err != nil {
+		t.Fatal(err)
+	}
+	if a.pollBackground() == nil {
+		t.Error("the system theme doesn't poll the background")
+	}
+}
@@ -0,0 +1,204 @@
+// Package tutorial is the onboarding playground. Prompts in it are answered
+// by a scripted provider that runs entirely offline, so new users can
+// practise sending prompts, reviewing diffs, switching models and using
+// keybinds without an API key and without spending anything.
+package tutorial
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode/internal/commands"
+)
+
+// ProviderID identifies the playground's mock provider
+const ProviderID = "tutorial"
+
+// SessionID identifies the playground session, which the server never sees
+const SessionID = "tutorial"
+
+// SampleFile is the file the playground pretends to edit
+const SampleFile = "greet.go"
+
+// Playground models
+const (
+	FastModel  = "playground-fast"
+	SmartModel = "playground-smart"
+)
+
+// Provider returns the mock provider. Its models cost nothing.
+func Provider() opencode.Provider {
+	return opencode.Provider{
+		ID:   ProviderID,
+		Name: "Playground",
+		Models: map[string]opencode.Model{
+			FastModel: {
+				ID:       FastModel,
+				Name:     "Playground Fast",
+				Limit:    opencode.ModelLimit{Context: 32000, Output: 4096},
+				ToolCall: true,
+			},
+			SmartModel: {
+				ID:        SmartModel,
+				Name:      "Playground Smart",
+				Limit:     opencode.ModelLimit{Context: 200000, Output: 8192},
+				Reasoning: true,
+				ToolCall:  true,
+			},
+		},
+	}
+}
+
+// Action is something the user does that a step waits for
+type Action string
+
+const (
+	ActionPrompt      Action = "prompt"
+	ActionToolDetails Action = "tool_details"
+	ActionSwitchModel Action = "switch_model"
+	ActionHelp        Action = "help"
+)
+
+// Step is one lesson of the tutorial
+type Step struct {
+	Title   string
+	Hint    string
+	Action  Action
+	Command commands.CommandName // Command whose keybind the hint refers to, if any
+}
+
+// Steps are the lessons in the order they are taught
+var Steps = []Step{
+	{
+		Title:  "Send a prompt",
+		Hint:   `Type a request such as "add a greeting" and press enter. The playground answers for free.`,
+		Action: ActionPrompt,
+	},
+	{
+		Title:   "Review the diff",
+		Hint:    "The reply edited " + SampleFile + " and its diff is in the chat. Diffs are tool details; hide them and show them again.",
+		Action:  ActionToolDetails,
+		Command: commands.ToolDetailsCommand,
+	},
+	{
+		Title:   "Switch models",
+		Hint:    "Change to the other playground model, then send another prompt to compare the answers.",
+		Action:  ActionSwitchModel,
+		Command: commands.ModelListCommand,
+	},
+	{
+		Title:   "Use a keybind",
+		Hint:    "Almost everything has a keybind. Open the list of them.",
+		Action:  ActionHelp,
+		Command: commands.AppShortcutsCommand,
+	},
+}
+
+// Tutorial tracks a user's way through the steps
+type Tutorial struct {
+	step    int
+	replies int
+}
+
+// New starts the tutorial at its first step
+func New() *Tutorial {
+	return &Tutorial{}
+}
+
+// Step returns the current step, or false once every step is done
+func (t *Tutorial) Step() (Step, bool) {
+	if t.step >= len(Steps) {
+		return Step{}, false
+	}
+	return Steps[t.step], true
+}
+
+// Progress returns how many steps are done and how many there are
+func (t *Tutorial) Progress() (int, int) {
+	return t.step, len(Steps)
+}
+
+// Finished reports whether every step is done
+func (t *Tutorial) Finished() bool {
+	return t.step >= len(Steps)
+}
+
+// Complete records an action, moving on when it is what the current step
+// waits for. It reports whether the tutorial moved on.
+func (t *Tutorial) Complete(action Action) bool {
+	step, ok := t.Step()
+	if !ok || step.Action != action {
+		return false
+	}
+	t.step++
+	return true
+}
+
+// Edit is a file change in a reply
+type Edit struct {
+	FilePath string
+	Diff     string // Unified diff
+}
+
+// Reply is the mock provider's answer to a prompt
+type Reply struct {
+	Text         string
+	Edit         *Edit // nil when the reply changes nothing
+	InputTokens  int
+	OutputTokens int
+}
+
+// Reply answers a prompt. The first answer edits the sample file, so there
+// is a diff to review; later ones describe how the model would go about the
+// request, at the length of the model that answers.
+func (t *Tutorial) Reply(prompt, modelID string) Reply {
+	t.replies++
+	prompt = strings.TrimSpace(prompt)
+
+	var reply Reply
+	switch {
+	case t.replies == 1:
+		reply.Text = fmt.Sprintf("Here is a first pass at %q. I changed `%s` to add a greeting; its diff is below.\n\n"+
+			"Nothing left your machine: this is the tutorial playground, and every answer in it is scripted.", prompt, SampleFile)
+		reply.Edit = &Edit{FilePath: SampleFile, Diff: sampleDiff}
+	case modelID == SmartModel:
+		reply.Text = fmt.Sprintf("Playground Smart here. For %q I would:\n\n"+
+			"1. Read the files involved and the tests that cover them\n"+
+			"2. Make the smallest change that does the job\n"+
+			"3. Run the tests and show you the diff before anything is applied\n\n"+
+			"Real models answer at different lengths, speeds and prices; the model widget in the status bar shows which one is answering.", prompt)
+	default:
+		reply.Text = fmt.Sprintf("Playground Fast here: short answers for %q. Switch to Playground Smart for a more thorough one.", prompt)
+	}
+	reply.InputTokens = estimateTokens(prompt) + 1200
+	reply.OutputTokens = estimateTokens(reply.Text)
+	if reply.Edit != nil {
+		reply.OutputTokens += estimateTokens(reply.Edit.Diff)
+	}
+	return reply
+}
+
+// estimateTokens approximates a text's token count at four characters a
+// token
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+const sampleDiff = `--- a/greet.go
++++ b/greet.go
+@@ -1,7 +1,11 @@
+ package main
+
+-import "fmt"
++import (
++	"fmt"
++	"os"
++)
+
+ func main() {
+-	fmt.Println("hello")
++	name := os.Getenv("USER")
++	fmt.Printf("Hello, %s! Welcome to RyCode.\n", name)
+ }
+`
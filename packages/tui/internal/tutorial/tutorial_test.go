@@ -0,0 +1,56 @@
+package tutorial
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aaronmrosenthal/rycode/internal/components/diff"
+)
+
+func TestComplete(t *testing.T) {
+	tut := New()
+	if tut.Complete(ActionHelp) {
+		t.Fatal("completed a step out of order")
+	}
+	for i, step := range Steps {
+		if done, total := tut.Progress(); done != i || total != len(Steps) {
+			t.Fatalf("progress = %d/%d, want %d/%d", done, total, i, len(Steps))
+		}
+		if !tut.Complete(step.Action) {
+			t.Fatalf("step %q not completed by %s", step.Title, step.Action)
+		}
+	}
+	if !tut.Finished() {
+		t.Fatal("not finished after every step")
+	}
+	if _, ok := tut.Step(); ok {
+		t.Fatal("step after the last one")
+	}
+	if tut.Complete(ActionPrompt) {
+		t.Fatal("completed a step after finishing")
+	}
+}
+
+func TestReply(t *testing.T) {
+	tut := New()
+	first := tut.Reply("add a greeting", FastModel)
+	if first.Edit == nil || first.Edit.FilePath != SampleFile {
+		t.Fatalf("first reply edit = %+v, want one to %s", first.Edit, SampleFile)
+	}
+	parsed, err := diff.ParseUnifiedDiff(first.Edit.Diff)
+	if err != nil || len(parsed.Hunks) != 1 {
+		t.Fatalf("sample diff: %d hunks, error %v", len(parsed.Hunks), err)
+	}
+	if first.InputTokens == 0 || first.OutputTokens == 0 {
+		t.Errorf("first reply tokens = %d/%d, want both set", first.InputTokens, first.OutputTokens)
+	}
+
+	fast := tut.Reply("explain it", FastModel)
+	smart := tut.Reply("explain it", SmartModel)
+	if fast.Edit != nil || smart.Edit != nil {
+		t.Error("later replies edited a file")
+	}
+	if !strings.Contains(smart.Text, "Playground Smart") || len(smart.Text) <= len(fast.Text) {
+		t.Errorf("smart reply %q should be longer than fast reply %q", smart.Text, fast.Text)
+	}
+}
@@ -0,0 +1,132 @@
+package util
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss/v2/compat"
+)
+
+// MarkdownStream renders markdown that is still being streamed. The blocks
+// that are complete are rendered once and kept; only the block being
+// written is rendered again as chunks arrive, so a long answer costs the
+// same to update as a short one and its settled blocks never change.
+type MarkdownStream struct {
+	width      int
+	background compat.AdaptiveColor
+	settled    string   // Text of the blocks rendered into done
+	done       []string // Rendered blocks
+}
+
+// Render renders the text streamed so far. Text that doesn't continue what
+// was rendered before, or a new width or background, starts over.
+func (s *MarkdownStream) Render(text string, width int, background compat.AdaptiveColor) string {
+	if width != s.width || background != s.background || !strings.HasPrefix(text, s.settled) {
+		*s = MarkdownStream{width: width, background: background}
+	}
+	for {
+		end := nextBlockBoundary(text, len(s.settled))
+		if end < 0 {
+			break
+		}
+		if block := text[len(s.settled):end]; strings.TrimSpace(block) != "" {
+			s.done = append(s.done, ToMarkdown(block, width, background))
+		}
+		s.settled = text[:end]
+	}
+
+	blocks := s.done
+	if tail := text[len(s.settled):]; strings.TrimSpace(tail) != "" {
+		blocks = append(blocks[:len(blocks):len(blocks)], ToMarkdown(closeFence(tail), width, background))
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// nextBlockBoundary returns where the first top-level block after from
+// starts, once the line starting it is complete, or -1 when the block from
+// from may still go on. Blocks are split at blank lines outside code
+// fences, but not before an indented line, or before a list item in a
+// list, which both continue the block.
+func nextBlockBoundary(text string, from int) int {
+	var fence string
+	blank, list := false, false
+	for pos := from; pos < len(text); {
+		end := strings.IndexByte(text[pos:], '\n')
+		if end < 0 {
+			// The last line is still being written
+			return -1
+		}
+		line := text[pos : pos+end]
+		next := pos + end + 1
+
+		if fence != "" {
+			if isFenceClose(line, fence) {
+				fence = ""
+			}
+			blank = false
+			pos = next
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			blank = true
+			pos = next
+			continue
+		}
+		item := isListItem(line)
+		if blank && pos > from && !(line[0] == ' ' || line[0] == '\t' || list && item) {
+			return pos
+		}
+		list = list || item
+		blank = false
+		fence = fenceOpen(line)
+		pos = next
+	}
+	return -1
+}
+
+// isListItem reports whether a line starts an item of a list
+func isListItem(line string) bool {
+	switch {
+	case strings.HasPrefix(line, "- "), strings.HasPrefix(line, "* "), strings.HasPrefix(line, "+ "):
+		return true
+	}
+	digits := len(line) - len(strings.TrimLeft(line, "0123456789"))
+	return digits > 0 && digits < len(line) && (line[digits] == '.' || line[digits] == ')')
+}
+
+// fenceOpen returns the fence a line opens a code block with, or "" when
+// it doesn't open one
+func fenceOpen(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return ""
+	}
+	for _, marker := range []string{"```", "~~~"} {
+		if strings.HasPrefix(trimmed, marker) {
+			return trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, marker[:1]))]
+		}
+	}
+	return ""
+}
+
+func isFenceClose(line, fence string) bool {
+	trimmed := strings.TrimLeft(line, " ")
+	return strings.HasPrefix(trimmed, fence) && strings.TrimSpace(strings.TrimLeft(trimmed, fence[:1])) == ""
+}
+
+// closeFence closes the code block the text leaves open, so that it is
+// highlighted as code while it streams rather than changing once it closes
+func closeFence(text string) string {
+	var fence string
+	for line := range strings.Lines(text) {
+		line = strings.TrimRight(line, "\n")
+		if fence == "" {
+			fence = fenceOpen(line)
+		} else if isFenceClose(line, fence) {
+			fence = ""
+		}
+	}
+	if fence == "" {
+		return text
+	}
+	return strings.TrimRight(text, "\n") + "\n" + fence
+}
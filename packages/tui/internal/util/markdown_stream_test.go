@@ -0,0 +1,46 @@
+package util
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNextBlockBoundary(t *testing.T) {
+	text := "# Plan\n\n- one\n\n- two\n\n  nested\n\n```go\nfunc main() {\n\n}\n```\n\nDone.\nAnd"
+	var starts []int
+	for from := 0; ; {
+		end := nextBlockBoundary(text, from)
+		if end < 0 {
+			break
+		}
+		starts = append(starts, end)
+		from = end
+	}
+	var blocks []string
+	prev := 0
+	for _, start := range starts {
+		blocks = append(blocks, text[prev:start])
+		prev = start
+	}
+	want := []string{"# Plan\n\n", "- one\n\n- two\n\n  nested\n\n", "```go\nfunc main() {\n\n}\n```\n\n"}
+	if !slices.Equal(blocks, want) {
+		t.Errorf("blocks = %q, want %q", blocks, want)
+	}
+	if end := nextBlockBoundary("Intro\n\n``", 0); end != -1 {
+		t.Errorf("split before the line starting the next block was complete, at %d", end)
+	}
+}
+
+func TestCloseFence(t *testing.T) {
+	tests := map[string]string{
+		"```go\nfunc main() {\n":    "```go\nfunc main() {\n```",
+		"~~~~\ncode\n~~~\nmore":     "~~~~\ncode\n~~~\nmore\n~~~~",
+		"```\ncode\n```\n":          "```\ncode\n```\n",
+		"Text with ``` in the line": "Text with ``` in the line",
+	}
+	for text, want := range tests {
+		if got := closeFence(text); got != want {
+			t.Errorf("closeFence(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package util
+
+import (
+	"math/bits"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ASCII is set when the TUI draws only ASCII: the locale isn't UTF-8, the
+// font lacks the symbols, or colors are off too
+var ASCII bool
+
+var sgrRE = regexp.MustCompile(`\x1b\[([0-9;:]*)m`)
+
+// FilterSGR drops colors, bold or italics from the styles of s, keeping
+// the other decorations and the resets
+func FilterSGR(s string, colors, bold, italic bool) string {
+	if colors && bold && italic {
+		return s
+	}
+	return sgrRE.ReplaceAllStringFunc(s, func(seq string) string {
+		params := sgrRE.FindStringSubmatch(seq)[1]
+		if params == "" {
+			return seq
+		}
+		fields := strings.Split(params, ";")
+		kept := make([]string, 0, len(fields))
+		for i := 0; i < len(fields); i++ {
+			field := fields[i]
+			code, _, _ := strings.Cut(field, ":")
+			switch code {
+			case "38", "48", "58":
+				// Extended colors take their arguments as following
+				// parameters, unless they're given as subparameters
+				start := i
+				if !strings.Contains(field, ":") && i+1 < len(fields) {
+					switch fields[i+1] {
+					case "5":
+						i += 2
+					case "2":
+						i += 4
+					}
+					i = min(i, len(fields)-1)
+				}
+				if colors {
+					kept = append(kept, fields[start:i+1]...)
+				}
+				continue
+			case "1":
+				if !bold {
+					continue
+				}
+			case "3":
+				if !italic {
+					continue
+				}
+			}
+			if !colors && isColorCode(code) {
+				continue
+			}
+			kept = append(kept, field)
+		}
+		if len(kept) == 0 {
+			// An empty sequence would reset the styles
+			return ""
+		}
+		return "\x1b[" + strings.Join(kept, ";") + "m"
+	})
+}
+
+// isColorCode reports whether an SGR parameter sets a basic color
+func isColorCode(code string) bool {
+	switch len(code) {
+	case 2:
+		return (code[0] == '3' || code[0] == '4' || code[0] == '9') && code[1] >= '0' && code[1] <= '9' && code != "98" && code != "99"
+	case 3:
+		return code[:2] == "10" && code[2] >= '0' && code[2] <= '7'
+	}
+	return false
+}
+
+// asciiSymbols are the ASCII stand-ins of the symbols the TUI draws with,
+// each as wide as the symbol it replaces
+var asciiSymbols = map[rune]string{
+	'─': "-", '━': "-", '═': "-", '╌': "-", '┄': "-", '╴': "-", '╶': "-", '▔': "-", '▁': "_",
+	'│': "|", '┃': "|", '║': "|", '╎': "|", '┆': "|", '╵': "|", '╷': "|", '▏': "|", '▕': "|", '▎': "|", '▍': "|", '▌': "|", '▐': "|",
+	'█': "#", '▓': "#", '▒': ":", '░': ".", '▀': "-", '▄': "_",
+	'▂': "_", '▃': ".", '▅': "-", '▆': "=", '▇': "=", '▰': "#", '▱': "-", '■': "#", '□': "-",
+	'→': ">", '←': "<", '↑': "^", '↓': "v", '↔': "-", '↕': "|", '⇒': ">", '⇐': "<", '↩': "<", '↪': ">", '↻': "@", '⟶': ">",
+	'▶': ">", '▸': ">", '►': ">", '›': ">", '»': ">", '◀': "<", '◂': "<", '‹': "<", '«': "<",
+	'▾': "v", '▼': "v", '▴': "^", '▲': "^",
+	'✓': "+", '✔': "+", '✗': "x", '✘': "x", '✕': "x", '×': "x",
+	'•': "*", '●': "*", '◆': "*", '★': "*", '☆': "*", '○': "o", '◯': "o", '◐': "o", '◑': "o", '◇': "o", '◉': "@",
+	'…': ".", '·': ".", '⋯': ".", '—': "-", '–': "-",
+	'⚠': "!", 'ℹ': "i", '⎿': "`",
+}
+
+// ASCIISymbols replaces the box drawing, arrows, marks and emoji of s with
+// ASCII of the same width, for terminals that can't show them. Other text,
+// as letters of other scripts, is kept.
+func ASCIISymbols(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	var state byte
+	for len(s) > 0 {
+		cluster, width, n, newState := ansi.DecodeSequence(s, state, nil)
+		state = newState
+		s = s[n:]
+		if width == 0 {
+			// Escape sequences are copied as they are
+			b.WriteString(cluster)
+			continue
+		}
+		r, _ := utf8.DecodeRuneInString(cluster)
+		switch {
+		case r < 0x80:
+			b.WriteString(cluster)
+		case asciiSymbols[r] != "":
+			b.WriteString(strings.Repeat(asciiSymbols[r], max(1, width)))
+		case r >= 0x2500 && r <= 0x257f:
+			// Corners, tees and crossings of box drawing
+			b.WriteString("+")
+		case r >= 0x2800 && r <= 0x28ff:
+			// Braille spinners and charts, by how many of the dots are set
+			b.WriteString(brailleDensity[bits.OnesCount(uint(r-0x2800))])
+		case r >= 0x25a0 && r <= 0x25ff:
+			// Other geometric shapes, as the quarters of circle spinners
+			b.WriteString("o")
+		case isEmoji(r):
+			b.WriteString("*" + strings.Repeat(" ", max(0, width-1)))
+		default:
+			b.WriteString(cluster)
+		}
+	}
+	return b.String()
+}
+
+// brailleDensity stands for a braille pattern by its number of dots
+var brailleDensity = [9]string{" ", ".", ".", ":", ":", "+", "+", "#", "#"}
+
+// isEmoji reports whether a rune is an emoji or pictographic symbol
+func isEmoji(r rune) bool {
+	return (r >= 0x1f000 && r <= 0x1faff) || (r >= 0x2600 && r <= 0x27bf) || (r >= 0x2b00 && r <= 0x2bff) || (r >= 0x2190 && r <= 0x23ff)
+}
@@ -0,0 +1,36 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestFilterSGR(t *testing.T) {
+	styled := "\x1b[1;38;2;10;20;30;48;5;236mbold\x1b[m \x1b[3;31mitalic\x1b[0m"
+	cases := []struct {
+		colors, bold, italic bool
+		want                 string
+	}{
+		{false, true, true, "\x1b[1mbold\x1b[m \x1b[3mitalic\x1b[0m"},
+		{true, false, true, "\x1b[38;2;10;20;30;48;5;236mbold\x1b[m \x1b[3;31mitalic\x1b[0m"},
+		{false, false, false, "bold\x1b[m italic\x1b[0m"},
+	}
+	for _, c := range cases {
+		if got := FilterSGR(styled, c.colors, c.bold, c.italic); got != c.want {
+			t.Errorf("FilterSGR(%v, %v, %v) = %q, want %q", c.colors, c.bold, c.italic, got, c.want)
+		}
+	}
+}
+
+func TestASCIISymbols(t *testing.T) {
+	in := "╭──╮ ✓ done → next 🚀 héllo 日本\n\x1b[1m│x│\x1b[0m ▰▰▱ ⠁⣿"
+	got := ASCIISymbols(in)
+	want := "+--+ + done > next *  héllo 日本\n\x1b[1m|x|\x1b[0m ##- .#"
+	if got != want {
+		t.Errorf("ASCIISymbols() = %q, want %q", got, want)
+	}
+	if ansi.StringWidth(got) != ansi.StringWidth(in) {
+		t.Errorf("the width changed from %d to %d", ansi.StringWidth(in), ansi.StringWidth(got))
+	}
+}
@@ -114,6 +114,7 @@ type Model struct {
 
 	initialized      bool
 	lines            []string
+	source           LineSource // Set instead of lines for virtual content
 	longestLineWidth int
 
 	// HighlightStyle highlights the ranges set with [SetHighligths].
@@ -133,6 +134,15 @@ type Model struct {
 	hiIdx      int
 }
 
+// LineSource provides the lines of virtual content, which are only
+// requested while they are in view. See [Model.SetContentSource].
+type LineSource interface {
+	// LineCount returns the number of lines of the content
+	LineCount() int
+	// Lines returns the lines from index from up to, but not including, to
+	Lines(from, to int) []string
+}
+
 // GutterFunc can be implemented and set into [Model.LeftGutterFunc].
 //
 // Example implementation showing line numbers:
@@ -237,6 +247,22 @@ func (m Model) HorizontalScrollPercent() float64 {
 	return math.Max(0.0, math.Min(1.0, v))
 }
 
+// SetContentSource sets virtual content: only the lines in view are taken
+// from the source, so long content isn't split into lines or measured as a
+// whole. Lines must not be wider than the viewport, and [Model.SoftWrap] and
+// highlights aren't supported.
+func (m *Model) SetContentSource(source LineSource) {
+	m.source = source
+	m.lines = nil
+	m.longestLineWidth = 0
+	m.ClearHighlights()
+
+	if m.YOffset > m.maxYOffset() {
+		m.GotoBottom()
+	}
+	m.memo.Invalidate()
+}
+
 // SetContent set the pager's text content.
 // Line endings will be normalized to '\n'.
 func (m *Model) SetContent(s string) {
@@ -251,6 +277,7 @@ func (m *Model) SetContent(s string) {
 func (m *Model) SetContentLines(lines []string) {
 	// if there's no content, set content to actual nil instead of one empty
 	// line.
+	m.source = nil
 	m.lines = lines
 	if len(m.lines) == 1 && ansi.StringWidth(m.lines[0]) == 0 {
 		m.lines = nil
@@ -267,12 +294,19 @@ func (m *Model) SetContentLines(lines []string) {
 // GetContent returns the entire content as a single string.
 // Line endings are normalized to '\n'.
 func (m Model) GetContent() string {
+	if m.source != nil {
+		return strings.Join(m.source.Lines(0, m.source.LineCount()), "\n")
+	}
 	return strings.Join(m.lines, "\n")
 }
 
 // calculateLine taking soft wrapping into account, returns the total viewable
 // lines and the real-line index for the given yoffset.
 func (m Model) calculateLine(yoffset int) (total, idx int) {
+	if m.source != nil {
+		total = m.source.LineCount()
+		return total, min(max(yoffset, 0), total)
+	}
 	if !m.SoftWrap {
 		for i, line := range m.lines {
 			adjust := max(1, lipgloss.Height(line))
@@ -351,7 +385,11 @@ func (m Model) visibleLines() (lines []string) {
 	maxHeight := m.maxHeight()
 	maxWidth := m.maxWidth()
 
-	if m.lineCount() > 0 {
+	if m.source != nil {
+		top := max(0, m.YOffset)
+		lines = m.source.Lines(top, top+maxHeight)
+		lines = m.styleLines(lines, top)
+	} else if m.lineCount() > 0 {
 		pos := m.lineToIndex(m.YOffset)
 		top := max(0, pos)
 		bottom := clamp(pos+maxHeight, top, len(m.lines))
@@ -543,7 +581,7 @@ func (m *Model) HalfViewUp() {
 
 // LineDown moves the view down by the given number of lines.
 func (m *Model) LineDown(n int) {
-	if m.AtBottom() || n == 0 || len(m.lines) == 0 {
+	if m.AtBottom() || n == 0 || m.empty() {
 		return
 	}
 
@@ -558,7 +596,7 @@ func (m *Model) LineDown(n int) {
 // LineUp moves the view down by the given number of lines. Returns the new
 // lines to show.
 func (m *Model) LineUp(n int) {
-	if m.AtTop() || n == 0 || len(m.lines) == 0 {
+	if m.AtTop() || n == 0 || m.empty() {
 		return
 	}
 
@@ -569,6 +607,14 @@ func (m *Model) LineUp(n int) {
 	m.memo.Invalidate()
 }
 
+// empty reports whether there is no content
+func (m Model) empty() bool {
+	if m.source != nil {
+		return m.source.LineCount() == 0
+	}
+	return len(m.lines) == 0
+}
+
 // TotalLineCount returns the total number of lines (both hidden and visible) within the viewport.
 func (m Model) TotalLineCount() int {
 	return m.lineCount()
@@ -645,7 +691,7 @@ func (m *Model) ResetIndent() {
 // [Model.HighlightNext] and [Model.HighlightPrevious] to navigate.
 // Use [Model.ClearHighlights] to remove all highlights.
 func (m *Model) SetHighlights(matches [][]int) {
-	if len(matches) == 0 || len(m.lines) == 0 {
+	if len(matches) == 0 || len(m.lines) == 0 || m.source != nil {
 		return
 	}
 	m.highlights = parseMatches(m.GetContent(), matches)
@@ -0,0 +1,201 @@
+// Package voice records the microphone with the system's audio tools and
+// transcribes the recording with a local whisper.cpp
+package voice
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// MaxRecording is how long a recording runs before it stops by itself
+const MaxRecording = 2 * time.Minute
+
+// Recorder is a command that records the default microphone to a 16 kHz
+// mono WAV file, the format whisper.cpp reads
+type Recorder struct {
+	Name string
+	Args func(path string) []string
+}
+
+// recorders lists the recording commands of each system, in order of
+// preference
+var recorders = map[string][]Recorder{
+	"darwin": {
+		{Name: "rec", Args: soxArgs},
+		{Name: "ffmpeg", Args: ffmpegArgs("avfoundation", ":0")},
+	},
+	"linux": {
+		{Name: "arecord", Args: func(path string) []string {
+			return []string{"-q", "-f", "S16_LE", "-r", "16000", "-c", "1", "-t", "wav", path}
+		}},
+		{Name: "rec", Args: soxArgs},
+		{Name: "ffmpeg", Args: ffmpegArgs("pulse", "default")},
+	},
+	"windows": {
+		{Name: "sox", Args: func(path string) []string {
+			return append([]string{"-q", "-t", "waveaudio", "default"}, soxOutput(path)...)
+		}},
+	},
+}
+
+func soxArgs(path string) []string {
+	return append([]string{"-q"}, soxOutput(path)...)
+}
+
+func soxOutput(path string) []string {
+	return []string{"-r", "16000", "-c", "1", "-b", "16", path}
+}
+
+func ffmpegArgs(format, input string) func(string) []string {
+	return func(path string) []string {
+		return []string{"-loglevel", "error", "-nostdin", "-f", format, "-i", input, "-ar", "16000", "-ac", "1", "-c:a", "pcm_s16le", "-y", path}
+	}
+}
+
+// DetectRecorder returns the first recording command of goos that lookPath
+// finds, or nil when there is none
+func DetectRecorder(goos string, lookPath func(string) (string, error)) *Recorder {
+	for _, recorder := range recorders[goos] {
+		if _, err := lookPath(recorder.Name); err == nil {
+			return &recorder
+		}
+	}
+	return nil
+}
+
+// ErrNoRecorder is returned when no recording command is installed
+var ErrNoRecorder = errors.New("no audio recorder was found; install sox, or alsa-utils or ffmpeg on Linux")
+
+var errNothingRecorded = errors.New("nothing was recorded; check the microphone")
+
+// Recording is the microphone being recorded
+type Recording struct {
+	path    string
+	cmd     *exec.Cmd
+	started time.Time
+	done    chan struct{}
+	err     error // Set when the recorder exits, before done is closed
+	once    sync.Once
+	timer   *time.Timer
+}
+
+// Start starts recording the microphone to a new WAV file of dir, or of the
+// temporary directory when dir is ""
+func Start(dir string) (*Recording, error) {
+	recorder := DetectRecorder(runtime.GOOS, exec.LookPath)
+	if recorder == nil {
+		return nil, ErrNoRecorder
+	}
+	f, err := os.CreateTemp(dir, "rycode-voice-*.wav")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording: %w", err)
+	}
+	f.Close()
+
+	cmd := exec.Command(recorder.Name, recorder.Args(f.Name())...)
+	cmd.Stdout, cmd.Stderr = io.Discard, io.Discard
+	if err := cmd.Start(); err != nil {
+		os.Remove(f.Name())
+		return nil, fmt.Errorf("failed to start %s: %w", recorder.Name, err)
+	}
+	r := &Recording{path: f.Name(), cmd: cmd, started: time.Now(), done: make(chan struct{})}
+	go func() {
+		r.err = cmd.Wait()
+		close(r.done)
+	}()
+	r.timer = time.AfterFunc(MaxRecording, r.interrupt)
+	return r, nil
+}
+
+// Elapsed returns how long the microphone has been recorded
+func (r *Recording) Elapsed() time.Duration {
+	return time.Since(r.started)
+}
+
+// Stop stops recording and returns the WAV file's path. The caller removes
+// the file.
+func (r *Recording) Stop() (string, error) {
+	r.timer.Stop()
+	// A recorder that exited by itself couldn't record
+	var failed error
+	select {
+	case <-r.done:
+		failed = r.err
+	default:
+	}
+	r.interrupt()
+	select {
+	case <-r.done:
+	case <-time.After(3 * time.Second):
+		r.cmd.Process.Kill()
+		<-r.done
+	}
+	// Killed recorders leave the sizes of the header unwritten
+	if err := fixWAVSizes(r.path); err != nil {
+		os.Remove(r.path)
+		if errors.Is(err, errNothingRecorded) && failed != nil {
+			return "", fmt.Errorf("%s failed: %w", filepath.Base(r.cmd.Path), failed)
+		}
+		return "", err
+	}
+	return r.path, nil
+}
+
+// interrupt asks the recorder to finish the file, which it does on an
+// interrupt; Windows has no interrupt to send, so the recorder is killed
+func (r *Recording) interrupt() {
+	r.once.Do(func() {
+		if runtime.GOOS == "windows" || r.cmd.Process.Signal(os.Interrupt) != nil {
+			r.cmd.Process.Kill()
+		}
+	})
+}
+
+// fixWAVSizes sets the sizes of the RIFF and data chunks of a WAV file from
+// the file's length
+func fixWAVSizes(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read recording: %w", err)
+	}
+	size := info.Size()
+
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(f, header); err != nil || string(header[:4]) != "RIFF" || string(header[8:]) != "WAVE" {
+		return errNothingRecorded
+	}
+	if _, err := f.WriteAt(binary.LittleEndian.AppendUint32(nil, uint32(size-8)), 4); err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	// Walk the chunks to the data one
+	chunk := make([]byte, 8)
+	for offset := int64(12); offset+8 <= size; {
+		if _, err := f.ReadAt(chunk, offset); err != nil {
+			break
+		}
+		if string(chunk[:4]) == "data" {
+			if size-offset-8 <= 0 {
+				return errNothingRecorded
+			}
+			if _, err := f.WriteAt(binary.LittleEndian.AppendUint32(nil, uint32(size-offset-8)), offset+4); err != nil {
+				return fmt.Errorf("failed to write recording: %w", err)
+			}
+			return nil
+		}
+		offset += 8 + int64(binary.LittleEndian.Uint32(chunk[4:]))
+	}
+	return errNothingRecorded
+}
@@ -0,0 +1,98 @@
+package voice
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectRecorder(t *testing.T) {
+	only := func(names ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			for _, n := range names {
+				if n == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+	}
+	if r := DetectRecorder("linux", only("ffmpeg", "arecord")); r == nil || r.Name != "arecord" {
+		t.Errorf("linux with arecord and ffmpeg = %v, want arecord", r)
+	}
+	if r := DetectRecorder("darwin", only("ffmpeg")); r == nil || r.Name != "ffmpeg" {
+		t.Errorf("darwin with ffmpeg = %v, want ffmpeg", r)
+	}
+	if r := DetectRecorder("linux", only()); r != nil {
+		t.Errorf("linux without recorders = %v, want nil", r)
+	}
+}
+
+func TestFixWAVSizes(t *testing.T) {
+	// A header as left by a killed recorder: zero sizes, a LIST chunk
+	// before the data
+	var wav []byte
+	wav = append(wav, "RIFF\x00\x00\x00\x00WAVE"...)
+	wav = append(wav, "fmt "...)
+	wav = binary.LittleEndian.AppendUint32(wav, 16)
+	wav = append(wav, make([]byte, 16)...)
+	wav = append(wav, "LIST"...)
+	wav = binary.LittleEndian.AppendUint32(wav, 4)
+	wav = append(wav, "INFO"...)
+	dataOffset := len(wav)
+	wav = append(wav, "data\x00\x00\x00\x00"...)
+	wav = append(wav, make([]byte, 320)...)
+
+	path := filepath.Join(t.TempDir(), "rec.wav")
+	if err := os.WriteFile(path, wav, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := fixWAVSizes(path); err != nil {
+		t.Fatalf("fixWAVSizes: %v", err)
+	}
+	fixed, _ := os.ReadFile(path)
+	if got := binary.LittleEndian.Uint32(fixed[4:]); got != uint32(len(wav)-8) {
+		t.Errorf("RIFF size = %d, want %d", got, len(wav)-8)
+	}
+	if got := binary.LittleEndian.Uint32(fixed[dataOffset+4:]); got != 320 {
+		t.Errorf("data size = %d, want 320", got)
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.wav")
+	os.WriteFile(empty, wav[:dataOffset+8], 0644)
+	if err := fixWAVSizes(empty); !errors.Is(err, errNothingRecorded) {
+		t.Errorf("header without samples: err = %v, want errNothingRecorded", err)
+	}
+}
+
+func TestParseWhisperJSON(t *testing.T) {
+	data := []byte(`{"transcription": [
+		{"text": " Fix the bug", "tokens": [
+			{"text": "[_BEG_]", "p": 0.1},
+			{"text": " Fix", "p": 0.9},
+			{"text": " the", "p": 1.0},
+			{"text": " bug", "p": 0.8}
+		]},
+		{"text": " in auth.go.", "tokens": [
+			{"text": " in", "p": 0.9},
+			{"text": " auth.go.", "p": 0.9},
+			{"text": "[_TT_150]", "p": 0.2}
+		]}
+	]}`)
+	transcript, err := parseWhisperJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transcript.Text != "Fix the bug in auth.go." {
+		t.Errorf("Text = %q", transcript.Text)
+	}
+	if transcript.Confidence < 0.899 || transcript.Confidence > 0.901 {
+		t.Errorf("Confidence = %f, want 0.9", transcript.Confidence)
+	}
+
+	if _, err := parseWhisperJSON([]byte(`{"transcription": [{"text": " [BLANK_AUDIO]"}]}`)); err == nil {
+		t.Error("blank audio was transcribed")
+	}
+}
@@ -0,0 +1,164 @@
+package voice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	// WhisperEnv names the environment variable pointing at the whisper.cpp
+	// binary, when it isn't on the PATH as whisper-cli
+	WhisperEnv = "RYCODE_WHISPER"
+	// WhisperModelEnv names the environment variable pointing at the ggml
+	// model whisper.cpp transcribes with
+	WhisperModelEnv = "RYCODE_WHISPER_MODEL"
+)
+
+// whisperBinaries are the names whisper.cpp is installed under: whisper-cli
+// by current builds and Homebrew, whisper-cpp by some packages
+var whisperBinaries = []string{"whisper-cli", "whisper-cpp"}
+
+// Transcript is the text of a recording
+type Transcript struct {
+	Text       string
+	Confidence float64 // Mean probability of the transcribed tokens, from 0 to 1
+}
+
+// Whisper finds the whisper.cpp binary and model, from the environment or
+// the usual places
+func Whisper() (binary, model string, err error) {
+	binary = os.Getenv(WhisperEnv)
+	if binary == "" {
+		for _, name := range whisperBinaries {
+			if path, err := exec.LookPath(name); err == nil {
+				binary = path
+				break
+			}
+		}
+	}
+	if binary == "" {
+		return "", "", fmt.Errorf("whisper.cpp wasn't found; install it, or set %s to its whisper-cli", WhisperEnv)
+	}
+
+	model = os.Getenv(WhisperModelEnv)
+	if model == "" {
+		model = findModel()
+	}
+	if model == "" {
+		return "", "", fmt.Errorf("no whisper model was found; download a ggml model such as ggml-base.en.bin to %s, or set %s to one", modelDir(), WhisperModelEnv)
+	}
+	if _, err := os.Stat(model); err != nil {
+		return "", "", fmt.Errorf("whisper model %s: %w", model, err)
+	}
+	return binary, model, nil
+}
+
+// modelDir is where models are looked for when WhisperModelEnv isn't set
+func modelDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "whisper.cpp")
+}
+
+// findModel returns the first ggml model of the model directory, or ""
+func findModel() string {
+	matches, _ := filepath.Glob(filepath.Join(modelDir(), "ggml-*.bin"))
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[0]
+}
+
+// Available reports why voice input can't work on this system, or nil
+// when a recorder, whisper.cpp and a model were all found
+func Available() error {
+	if DetectRecorder(runtime.GOOS, exec.LookPath) == nil {
+		return ErrNoRecorder
+	}
+	_, _, err := Whisper()
+	return err
+}
+
+// Transcribe transcribes a 16 kHz WAV recording with whisper.cpp
+func Transcribe(ctx context.Context, wav string) (Transcript, error) {
+	binary, model, err := Whisper()
+	if err != nil {
+		return Transcript{}, err
+	}
+	// whisper.cpp writes its JSON next to the output prefix it is given
+	prefix := strings.TrimSuffix(wav, filepath.Ext(wav))
+	defer os.Remove(prefix + ".json")
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, "-m", model, "-f", wav, "-np", "-nt", "-ojf", "-of", prefix)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := lastLine(stderr.String()); msg != "" {
+			return Transcript{}, fmt.Errorf("whisper.cpp failed: %s", msg)
+		}
+		return Transcript{}, fmt.Errorf("whisper.cpp failed: %w", err)
+	}
+	data, err := os.ReadFile(prefix + ".json")
+	if err != nil {
+		return Transcript{}, fmt.Errorf("failed to read transcript: %w", err)
+	}
+	return parseWhisperJSON(data)
+}
+
+// whisperOutput is the part of whisper.cpp's full JSON output that is read
+type whisperOutput struct {
+	Transcription []struct {
+		Text   string `json:"text"`
+		Tokens []struct {
+			Text string  `json:"text"`
+			P    float64 `json:"p"`
+		} `json:"tokens"`
+	} `json:"transcription"`
+}
+
+// parseWhisperJSON reads the text of whisper.cpp's full JSON output, with
+// the mean probability of its tokens as the confidence. Special tokens such
+// as [_BEG_] and timestamps don't count.
+func parseWhisperJSON(data []byte) (Transcript, error) {
+	var output whisperOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return Transcript{}, fmt.Errorf("failed to parse transcript: %w", err)
+	}
+	var text strings.Builder
+	var sum float64
+	var tokens int
+	for _, segment := range output.Transcription {
+		text.WriteString(segment.Text)
+		for _, token := range segment.Tokens {
+			if strings.HasPrefix(token.Text, "[_") || strings.HasPrefix(token.Text, "<|") {
+				continue
+			}
+			sum += token.P
+			tokens++
+		}
+	}
+	transcript := Transcript{Text: strings.Join(strings.Fields(text.String()), " ")}
+	if transcript.Text == "" || transcript.Text == "[BLANK_AUDIO]" {
+		return Transcript{}, errors.New("no speech was heard")
+	}
+	if tokens > 0 {
+		transcript.Confidence = sum / float64(tokens)
+	}
+	return transcript, nil
+}
+
+// lastLine returns the last non-empty line of s
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,115 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/aaronmrosenthal/rycode/internal/target"
+	"github.com/fsnotify/fsnotify"
+)
+
+// Timeout bounds a single run of a trigger's command
+const Timeout = 2 * time.Minute
+
+// maxOutput caps the output kept from a run, which ends up in a prompt
+const maxOutput = 16 * 1024
+
+// Run runs a trigger's command through the shell in dir, in the project's
+// execution target
+func Run(ctx context.Context, dir, command string) Result {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+
+	output, err := target.Shell(ctx, dir, command).CombinedOutput()
+
+	result := Result{Output: string(output), RanAt: time.Now()}
+	if len(result.Output) > maxOutput {
+		result.Output = result.Output[:maxOutput] + "\n… (output truncated)"
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		result.Err = fmt.Errorf("timed out after %s", Timeout)
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		result.Err = err
+	}
+	return result
+}
+
+// debounce is how long changes are collected before the command runs, so a
+// burst of writes runs it once
+const debounce = 500 * time.Millisecond
+
+// Watcher reports changes to a trigger's paths
+type Watcher struct {
+	watcher *fsnotify.Watcher
+}
+
+// NewWatcher watches paths, relative to dir. Directories are watched
+// without their subdirectories.
+func NewWatcher(dir string, paths []string) (*Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		if err := watcher.Add(path); err != nil {
+			watcher.Close()
+			return nil, fmt.Errorf("cannot watch %s: %w", path, err)
+		}
+	}
+	return &Watcher{watcher: watcher}, nil
+}
+
+// Wait blocks until a watched path changes, and returns false when the
+// watcher is closed
+func (w *Watcher) Wait() bool {
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return false
+			}
+			if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+				continue
+			}
+			w.drain()
+			return true
+		case _, ok := <-w.watcher.Errors:
+			if !ok {
+				return false
+			}
+		}
+	}
+}
+
+// drain discards the events that follow a change until the paths are quiet
+func (w *Watcher) drain() {
+	timer := time.NewTimer(debounce)
+	defer timer.Stop()
+	for {
+		select {
+		case _, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			timer.Reset(debounce)
+		case <-timer.C:
+			return
+		}
+	}
+}
+
+// Close stops watching
+func (w *Watcher) Close() error {
+	return w.watcher.Close()
+}
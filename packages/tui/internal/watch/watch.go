@@ -0,0 +1,240 @@
+// Package watch fires prompts from shell commands. A trigger runs a command
+// on an interval or when files change and, when its output meets the
+// trigger's condition, renders a prompt template with that output.
+package watch
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultInterval is how often a trigger without an interval or paths runs
+const DefaultInterval = 5 * time.Minute
+
+// MinInterval is the shortest interval a trigger may run on
+const MinInterval = 10 * time.Second
+
+// Condition decides when a trigger fires
+type Condition string
+
+const (
+	// Changed fires when the output differs from the previous run
+	Changed Condition = "changed"
+	// Match fires when lines matching the pattern appear that weren't in
+	// the previous run's output
+	Match Condition = "match"
+	// Fail fires when the command starts failing, or fails with a different
+	// output than before
+	Fail Condition = "fail"
+)
+
+// Trigger is a command watched for a condition and the prompt it fires
+type Trigger struct {
+	ID        string
+	Name      string
+	Command   string
+	Every     time.Duration // Zero when the trigger only runs on file changes
+	Paths     []string      // Files or directories whose changes run the command
+	Condition Condition
+	Pattern   *regexp.Regexp // Lines to look for with the Match condition
+	Prompt    string         // text/template rendered with a Data
+	Created   time.Time
+}
+
+// Result is the outcome of running a trigger's command
+type Result struct {
+	Output   string
+	ExitCode int
+	RanAt    time.Time
+	Err      error // The command couldn't be started or timed out
+}
+
+// Data is what a prompt template is rendered with
+type Data struct {
+	Name     string
+	Command  string
+	Output   string
+	Previous string // Output of the previous run
+	Matches  string // New matching lines, with the Match condition
+	ExitCode int
+}
+
+const usage = "usage: /watch add [--every 2m] [--on PATH] [--match REGEX | --fail] [--name N] <command> => <prompt>"
+
+// Parse creates a trigger from the arguments of /watch add. Options come
+// before the command, and "=>" separates the command from the prompt
+// template so that commands can use pipes.
+func Parse(args string, now time.Time) (Trigger, error) {
+	spec, prompt, ok := strings.Cut(args, "=>")
+	prompt = strings.TrimSpace(prompt)
+	if !ok || prompt == "" {
+		return Trigger{}, fmt.Errorf(usage)
+	}
+
+	trigger := Trigger{ID: newID(), Condition: Changed, Prompt: prompt, Created: now}
+	rest := strings.TrimSpace(spec)
+	for strings.HasPrefix(rest, "--") {
+		option, value, remaining := nextOption(rest)
+		rest = remaining
+		if value == "" && option != "--fail" {
+			return Trigger{}, fmt.Errorf("%s needs a value", option)
+		}
+		switch option {
+		case "--every":
+			every, err := time.ParseDuration(value)
+			if err != nil {
+				return Trigger{}, fmt.Errorf("invalid interval %q", value)
+			}
+			if every < MinInterval {
+				return Trigger{}, fmt.Errorf("the interval must be at least %s", MinInterval)
+			}
+			trigger.Every = every
+		case "--on":
+			trigger.Paths = append(trigger.Paths, value)
+		case "--match":
+			pattern, err := regexp.Compile(value)
+			if err != nil {
+				return Trigger{}, fmt.Errorf("invalid pattern: %w", err)
+			}
+			trigger.Condition = Match
+			trigger.Pattern = pattern
+		case "--fail":
+			trigger.Condition = Fail
+		case "--name":
+			trigger.Name = value
+		default:
+			return Trigger{}, fmt.Errorf("unknown option %s", option)
+		}
+	}
+	trigger.Command = rest
+	if trigger.Command == "" {
+		return Trigger{}, fmt.Errorf(usage)
+	}
+	if _, err := trigger.Render(Data{}); err != nil {
+		return Trigger{}, fmt.Errorf("invalid prompt template: %w", err)
+	}
+	if trigger.Every == 0 && len(trigger.Paths) == 0 {
+		trigger.Every = DefaultInterval
+	}
+	if trigger.Name == "" {
+		trigger.Name = trigger.Command
+		if runes := []rune(trigger.Name); len(runes) > 40 {
+			trigger.Name = string(runes[:40]) + "…"
+		}
+	}
+	return trigger, nil
+}
+
+// nextOption splits the leading option off s. Flags without a value
+// return an empty value; values may be quoted.
+func nextOption(s string) (option, value, rest string) {
+	option, rest, _ = strings.Cut(s, " ")
+	if name, v, ok := strings.Cut(option, "="); ok {
+		return name, strings.Trim(v, `"'`), strings.TrimSpace(rest)
+	}
+	if option == "--fail" {
+		return option, "", strings.TrimSpace(rest)
+	}
+	rest = strings.TrimSpace(rest)
+	if rest != "" && (rest[0] == '"' || rest[0] == '\'') {
+		if end := strings.IndexByte(rest[1:], rest[0]); end >= 0 {
+			return option, rest[1 : end+1], strings.TrimSpace(rest[end+2:])
+		}
+	}
+	value, rest, _ = strings.Cut(rest, " ")
+	return option, value, strings.TrimSpace(rest)
+}
+
+func newID() string {
+	b := make([]byte, 3)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Describe summarizes when the trigger runs and fires
+func (t Trigger) Describe() string {
+	var when []string
+	if t.Every > 0 {
+		when = append(when, "every "+t.Every.String())
+	}
+	if len(t.Paths) > 0 {
+		when = append(when, "when "+strings.Join(t.Paths, ", ")+" change")
+	}
+	fires := "when the output changes"
+	switch t.Condition {
+	case Match:
+		fires = "when new lines match " + t.Pattern.String()
+	case Fail:
+		fires = "when the command fails"
+	}
+	return "runs " + strings.Join(when, " and ") + ", fires " + fires
+}
+
+// Evaluate compares a run with the previous one, which is nil for the first
+// run, and reports whether the trigger fires. For the Match condition it
+// also returns the matching lines that are new.
+func (t Trigger) Evaluate(previous *Result, current Result) (bool, []string) {
+	if current.Err != nil {
+		return false, nil
+	}
+	switch t.Condition {
+	case Match:
+		var seen []string
+		if previous != nil {
+			seen = t.matches(previous.Output)
+		}
+		var fresh []string
+		for _, line := range t.matches(current.Output) {
+			if !slices.Contains(seen, line) && !slices.Contains(fresh, line) {
+				fresh = append(fresh, line)
+			}
+		}
+		return len(fresh) > 0, fresh
+	case Fail:
+		if current.ExitCode == 0 {
+			return false, nil
+		}
+		return previous == nil || previous.ExitCode == 0 || previous.Output != current.Output, nil
+	default:
+		// The first run is the baseline changes are compared with
+		return previous != nil && previous.Err == nil && previous.Output != current.Output, nil
+	}
+}
+
+func (t Trigger) matches(output string) []string {
+	var lines []string
+	for line := range strings.SplitSeq(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" && t.Pattern.MatchString(line) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// Render fills in the prompt template. A prompt that doesn't use the
+// template gets the output appended.
+func (t Trigger) Render(data Data) (string, error) {
+	if !strings.Contains(t.Prompt, "{{") {
+		output := data.Output
+		if data.Matches != "" {
+			output = data.Matches
+		}
+		return fmt.Sprintf("%s\n\nOutput of `%s`:\n```\n%s\n```", t.Prompt, t.Command, strings.TrimSpace(output)), nil
+	}
+	tmpl, err := template.New("prompt").Option("missingkey=error").Parse(t.Prompt)
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
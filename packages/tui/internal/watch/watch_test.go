@@ -0,0 +1,102 @@
+package watch
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	trigger, err := Parse(`--every 2m --match "fail(ed|ure)" gh run list --limit 10 | grep -v skipped => Triage: {{.Matches}}`, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trigger.Every != 2*time.Minute || trigger.Condition != Match || trigger.Pattern.String() != "fail(ed|ure)" {
+		t.Errorf("trigger = %+v", trigger)
+	}
+	if trigger.Command != "gh run list --limit 10 | grep -v skipped" || trigger.Prompt != "Triage: {{.Matches}}" {
+		t.Errorf("command = %q, prompt = %q", trigger.Command, trigger.Prompt)
+	}
+
+	trigger, err = Parse("--on go.mod --fail go build ./... => Fix the build", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trigger.Every != 0 || len(trigger.Paths) != 1 || trigger.Condition != Fail {
+		t.Errorf("trigger = %+v", trigger)
+	}
+	if trigger, _ := Parse("date => hello", time.Now()); trigger.Every != DefaultInterval || trigger.Condition != Changed {
+		t.Errorf("defaults = %+v", trigger)
+	}
+
+	for _, args := range []string{
+		"date",
+		"date =>  ",
+		"--every 1s date => hi",
+		"--match ( date => hi",
+		"--bogus date => hi",
+		"--every => hi",
+		"date => {{.Output",
+		"date => {{.Outptu}}",
+	} {
+		if _, err := Parse(args, time.Now()); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", args)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	changed, _ := Parse("date => hi", time.Now())
+	if fired, _ := changed.Evaluate(nil, Result{Output: "a"}); fired {
+		t.Error("changed fired on its first run")
+	}
+	if fired, _ := changed.Evaluate(&Result{Output: "a"}, Result{Output: "a"}); fired {
+		t.Error("changed fired without a change")
+	}
+	if fired, _ := changed.Evaluate(&Result{Output: "a"}, Result{Output: "b"}); !fired {
+		t.Error("changed didn't fire on a change")
+	}
+
+	match, _ := Parse("--match failure gh run list => hi", time.Now())
+	fired, lines := match.Evaluate(nil, Result{Output: "ci failure\nlint ok"})
+	if !fired || len(lines) != 1 || lines[0] != "ci failure" {
+		t.Errorf("first run = %v, %q", fired, lines)
+	}
+	fired, lines = match.Evaluate(&Result{Output: "ci failure"}, Result{Output: "ci failure\ne2e failure"})
+	if !fired || len(lines) != 1 || lines[0] != "e2e failure" {
+		t.Errorf("new failure = %v, %q", fired, lines)
+	}
+	if fired, _ := match.Evaluate(&Result{Output: "ci failure"}, Result{Output: "ci failure"}); fired {
+		t.Error("match fired on lines it already saw")
+	}
+
+	fail, _ := Parse("--fail make test => hi", time.Now())
+	if fired, _ := fail.Evaluate(&Result{ExitCode: 0}, Result{ExitCode: 1, Output: "x"}); !fired {
+		t.Error("fail didn't fire when the command started failing")
+	}
+	if fired, _ := fail.Evaluate(&Result{ExitCode: 1, Output: "x"}, Result{ExitCode: 1, Output: "x"}); fired {
+		t.Error("fail fired again for the same failure")
+	}
+}
+
+func TestRender(t *testing.T) {
+	trigger, _ := Parse("--match fail gh run list => {{.Name}} found:\n{{.Matches}}", time.Now())
+	prompt, err := trigger.Render(Data{Name: "ci", Matches: "build failed"})
+	if err != nil || prompt != "ci found:\nbuild failed" {
+		t.Errorf("Render = %q, %v", prompt, err)
+	}
+
+	plain, _ := Parse("make test => Why did this change?", time.Now())
+	prompt, _ = plain.Render(Data{Output: "ok 3 tests\n"})
+	if !strings.HasPrefix(prompt, "Why did this change?") || !strings.Contains(prompt, "```\nok 3 tests\n```") {
+		t.Errorf("Render without a template = %q", prompt)
+	}
+}
+
+func TestRun(t *testing.T) {
+	result := Run(context.Background(), t.TempDir(), "echo out; exit 3")
+	if result.Err != nil || result.ExitCode != 3 || strings.TrimSpace(result.Output) != "out" {
+		t.Errorf("Run = %+v", result)
+	}
+}
@@ -0,0 +1,131 @@
+// Package worktree finds the git worktrees and submodules of a project, any
+// of which the TUI can work in instead of the directory it started in.
+package worktree
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/aaronmrosenthal/rycode/internal/remote"
+)
+
+// Kind is what a root is to the project
+type Kind string
+
+const (
+	KindWorktree  Kind = "worktree"
+	KindSubmodule Kind = "submodule"
+)
+
+// Root is a directory of the project the TUI can work in
+type Root struct {
+	Path   string // Absolute
+	Kind   Kind
+	Branch string // Checked out branch; empty when detached
+	Head   string // Checked out commit, abbreviated
+	Main   bool   // The main worktree
+}
+
+// Label describes a root relative to the main worktree
+func (r Root) Label(main string) string {
+	if r.Main {
+		return filepath.Base(r.Path)
+	}
+	if rel, err := filepath.Rel(main, r.Path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return r.Path
+}
+
+// Ref returns the branch of a root, or its commit when detached
+func (r Root) Ref() string {
+	if r.Branch != "" {
+		return r.Branch
+	}
+	return r.Head
+}
+
+// List returns the worktrees of the repository at dir, the main one first,
+// then the initialized submodules of the main worktree
+func List(ctx context.Context, dir string) ([]Root, error) {
+	out, err := remote.Command(ctx, dir, "git", "worktree", "list", "--porcelain").Output()
+	if err != nil {
+		return nil, fmt.Errorf("not a git repository: %s", dir)
+	}
+	roots := ParseWorktrees(string(out))
+	if len(roots) == 0 {
+		return nil, nil
+	}
+	main := roots[0].Path
+	out, err = remote.Command(ctx, main, "git", "submodule", "status", "--recursive").Output()
+	if err == nil {
+		roots = append(roots, ParseSubmodules(string(out), main)...)
+	}
+	return roots, nil
+}
+
+// ParseWorktrees parses the output of git worktree list --porcelain. Bare
+// and prunable worktrees are left out, having no files to work on.
+func ParseWorktrees(output string) []Root {
+	var roots []Root
+	for block := range strings.SplitSeq(strings.TrimSpace(output), "\n\n") {
+		root := Root{Kind: KindWorktree, Main: len(roots) == 0}
+		skip := false
+		for line := range strings.SplitSeq(block, "\n") {
+			key, value, _ := strings.Cut(strings.TrimSpace(line), " ")
+			switch key {
+			case "worktree":
+				root.Path = value
+			case "HEAD":
+				root.Head = abbreviate(value)
+			case "branch":
+				root.Branch = strings.TrimPrefix(value, "refs/heads/")
+			case "bare", "prunable":
+				skip = true
+			}
+		}
+		if root.Path == "" || skip {
+			continue
+		}
+		roots = append(roots, root)
+	}
+	return roots
+}
+
+// ParseSubmodules parses the output of git submodule status, run in root.
+// Submodules that aren't initialized are left out.
+func ParseSubmodules(output, root string) []Root {
+	var roots []Root
+	for line := range strings.SplitSeq(output, "\n") {
+		if line == "" || line[0] == '-' {
+			continue
+		}
+		fields := strings.Fields(line[1:])
+		if len(fields) < 2 {
+			continue
+		}
+		submodule := Root{
+			Path: filepath.Join(root, filepath.FromSlash(fields[1])),
+			Kind: KindSubmodule,
+			Head: abbreviate(fields[0]),
+		}
+		if len(fields) > 2 {
+			// Such as (heads/main) or (v1.2.0-3-gabc)
+			ref := strings.Trim(fields[2], "()")
+			if branch, ok := strings.CutPrefix(ref, "heads/"); ok {
+				submodule.Branch = branch
+			}
+		}
+		roots = append(roots, submodule)
+	}
+	return roots
+}
+
+func abbreviate(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
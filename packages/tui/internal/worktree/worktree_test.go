@@ -0,0 +1,51 @@
+package worktree
+
+import (
+	"testing"
+)
+
+func TestParseWorktrees(t *testing.T) {
+	output := `worktree /src/app
+HEAD 1a2b3c4d5e6f7a8b9c0d
+branch refs/heads/main
+
+worktree /src/app-review
+HEAD 0f9e8d7c6b5a
+detached
+
+worktree /src/app.git
+bare
+
+worktree /src/app-gone
+HEAD 123456789
+branch refs/heads/old
+prunable gitdir file points to non-existent location
+`
+	roots := ParseWorktrees(output)
+	if len(roots) != 2 {
+		t.Fatalf("expected 2 worktrees, got %+v", roots)
+	}
+	if !roots[0].Main || roots[0].Branch != "main" || roots[0].Head != "1a2b3c4" {
+		t.Errorf("main worktree = %+v", roots[0])
+	}
+	if roots[1].Main || roots[1].Ref() != "0f9e8d7" || roots[1].Label("/src/app") != "/src/app-review" {
+		t.Errorf("detached worktree = %+v", roots[1])
+	}
+}
+
+func TestParseSubmodules(t *testing.T) {
+	output := ` 1234567890abcdef libs/ui (heads/main)
+-abcdef1234567890 libs/unused
++fedcba0987654321 vendor/sdk (v1.2.0-3-gfedcba0)
+`
+	roots := ParseSubmodules(output, "/src/app")
+	if len(roots) != 2 {
+		t.Fatalf("expected the 2 initialized submodules, got %+v", roots)
+	}
+	if roots[0].Path != "/src/app/libs/ui" || roots[0].Branch != "main" || roots[0].Kind != KindSubmodule {
+		t.Errorf("first submodule = %+v", roots[0])
+	}
+	if roots[1].Branch != "" || roots[1].Ref() != "fedcba0" || roots[1].Label("/src/app") != "vendor/sdk" {
+		t.Errorf("detached submodule = %+v", roots[1])
+	}
+}
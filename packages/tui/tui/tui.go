@@ -0,0 +1,277 @@
+// Package tui runs the RyCode TUI from other Go programs, either on a screen
+// of its own with Run or as a model inside a Bubble Tea program of theirs.
+// The TUI talks to a RyCode server, which Options point it at, and can be
+// given plugins by the program embedding it.
+package tui
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/aaronmrosenthal/rycode-sdk-go"
+	"github.com/aaronmrosenthal/rycode-sdk-go/option"
+	"github.com/aaronmrosenthal/rycode/internal/api"
+	"github.com/aaronmrosenthal/rycode/internal/app"
+	"github.com/aaronmrosenthal/rycode/internal/bridge"
+	"github.com/aaronmrosenthal/rycode/internal/clipboard"
+	"github.com/aaronmrosenthal/rycode/internal/plugin"
+	internaltui "github.com/aaronmrosenthal/rycode/internal/tui"
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultServerURL is the server the TUI talks to when Options name none
+const DefaultServerURL = "http://127.0.0.1:4096"
+
+// Plugins embedded in the TUI implement Plugin, as the installed ones do
+// over JSON-RPC, and PartRenderer to draw tool calls; see Options.Plugins
+type (
+	Plugin        = plugin.Plugin
+	PartRenderer  = plugin.PartRenderer
+	Embedded      = plugin.Embedded
+	Env           = plugin.Env
+	Contributions = plugin.Contributions
+	Command       = plugin.Command
+	Widget        = plugin.Widget
+	Renderer      = plugin.Renderer
+	Part          = plugin.Part
+	Event         = plugin.Event
+	Action        = plugin.Action
+	Toast         = plugin.Toast
+	Dialog        = plugin.Dialog
+	DialogItem    = plugin.DialogItem
+)
+
+// Event types
+const (
+	EventCommand = plugin.EventCommand
+	EventSelect  = plugin.EventSelect
+	EventSession = plugin.EventSession
+)
+
+// RenderFunc draws a finished tool call, or returns "" to leave it to the
+// chat
+type RenderFunc func(ctx context.Context, part Part) (string, error)
+
+// RenderPlugin returns a plugin that draws the calls of the tools matching
+// a pattern, as terraform_*, with render
+func RenderPlugin(name, tool string, render RenderFunc) Embedded {
+	return Embedded{
+		Name:        name,
+		Description: "draws " + tool + " calls",
+		Plugin:      &renderPlugin{tool: tool, render: render},
+	}
+}
+
+type renderPlugin struct {
+	tool   string
+	render RenderFunc
+}
+
+func (p *renderPlugin) Init(ctx context.Context, env Env) (Contributions, error) {
+	contributions := Contributions{Renderers: []Renderer{{Tool: p.tool}}}
+	return contributions, contributions.Validate()
+}
+
+func (p *renderPlugin) Update(ctx context.Context, event Event) (*Action, error) {
+	return nil, nil
+}
+
+func (p *renderPlugin) View(ctx context.Context, widget string) (string, error) {
+	return "", nil
+}
+
+func (p *renderPlugin) RenderPart(ctx context.Context, part Part) (string, error) {
+	return p.render(ctx, part)
+}
+
+func (p *renderPlugin) Close() error {
+	return nil
+}
+
+// Workspace is what the server tells of the project the TUI works on
+type Workspace struct {
+	Project *opencode.Project
+	Path    *opencode.Path
+	Agents  []opencode.Agent
+}
+
+// Connect reads the project, directories and agents of the server a client
+// talks to
+func Connect(ctx context.Context, client *opencode.Client) (Workspace, error) {
+	var ws Workspace
+	batch, ctx := errgroup.WithContext(ctx)
+	batch.Go(func() error {
+		project, err := client.Project.Current(ctx, opencode.ProjectCurrentParams{})
+		ws.Project = project
+		return err
+	})
+	batch.Go(func() error {
+		agents, err := client.Agent.List(ctx, opencode.AgentListParams{})
+		if err == nil {
+			ws.Agents = *agents
+		}
+		return err
+	})
+	batch.Go(func() error {
+		path, err := client.Path.Get(ctx, opencode.PathGetParams{})
+		ws.Path = path
+		return err
+	})
+	return ws, batch.Wait()
+}
+
+// Options configure a TUI. Every field is optional.
+type Options struct {
+	Client    *opencode.Client // Talks to ServerURL when nil
+	ServerURL string           // DefaultServerURL when empty
+	Workspace *Workspace       // Read from the server with Connect when nil
+	Version   string           // "dev" when empty
+
+	ConfigDir string // Where themes, keybinds and plugins are read, instead of the server's config directory
+	StateDir  string // Where the TUI keeps its state, instead of the server's state directory
+
+	Model    string // Model to begin with, as provider/model
+	Prompt   string // Sent once the TUI starts
+	Agent    string // Agent to begin with
+	Session  string // ID of the session to open
+	Tutorial bool   // Start in the tutorial playground
+	Inline   bool   // Run in the normal screen, leaving the chat in the terminal's scrollback
+	NoSplash bool   // Skip the startup splash, as when the program showed one of its own
+
+	Plugins []Embedded // Started with the installed plugins, replacing those of the same name
+}
+
+// TUI is a RyCode TUI, ready to be run on its own screen or as a model of
+// another program
+type TUI struct {
+	app    *app.App
+	model  *internaltui.Model
+	client *opencode.Client
+	root   string
+	ctx    context.Context
+	cancel context.CancelFunc
+	bridge *bridge.Server
+}
+
+// New connects to the server and creates a TUI for its project. The TUI
+// stops talking to the server once ctx is done or it's closed.
+func New(ctx context.Context, opts Options) (*TUI, error) {
+	client := opts.Client
+	if client == nil {
+		client = opencode.NewClient(option.WithBaseURL(cmp.Or(opts.ServerURL, DefaultServerURL)))
+	}
+	var ws Workspace
+	if opts.Workspace != nil {
+		ws = *opts.Workspace
+	} else {
+		var err error
+		if ws, err = Connect(ctx, client); err != nil {
+			return nil, err
+		}
+	}
+	if ws.Project == nil || ws.Path == nil {
+		return nil, errors.New("the workspace has no project or directories")
+	}
+	// The server's directories are copied so that overriding them leaves
+	// the workspace as it was
+	path := *ws.Path
+	path.Config = cmp.Or(opts.ConfigDir, path.Config)
+	path.State = cmp.Or(opts.StateDir, path.State)
+
+	go func() {
+		if err := clipboard.Init(); err != nil {
+			slog.Error("Failed to initialize clipboard", "error", err)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(ctx)
+	a, err := app.New(ctx, cmp.Or(opts.Version, "dev"), ws.Project, &path, ws.Agents, client,
+		&opts.Model, &opts.Prompt, &opts.Agent, &opts.Session)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	a.InitialTutorial = opts.Tutorial
+	a.Inline = a.Inline || opts.Inline
+	a.SkipSplash = opts.NoSplash
+	a.EmbeddedPlugins = opts.Plugins
+
+	return &TUI{
+		app:    a,
+		model:  internaltui.NewModel(a).(*internaltui.Model),
+		client: client,
+		root:   path.Worktree,
+		ctx:    ctx,
+		cancel: cancel,
+	}, nil
+}
+
+// Model returns the TUI's Bubble Tea model. A program that embeds it must
+// call Start with its Send, pass the model every message and give it the
+// size it may draw in as a tea.WindowSizeMsg. Exiting the TUI returns
+// tea.Quit, which the program may catch to close it instead.
+func (t *TUI) Model() tea.Model {
+	return t.model
+}
+
+// ProgramOptions returns the options of a program running the TUI on a
+// screen of its own
+func (t *TUI) ProgramOptions() []tea.ProgramOption {
+	return t.app.ProgramOptions()
+}
+
+// Start streams the server's events to the TUI through send, the Send of
+// the program running it, and opens the socket editor plugins connect to
+func (t *TUI) Start(send func(tea.Msg)) {
+	if server, err := bridge.Listen(t.root, send); err != nil {
+		slog.Warn("Editor bridge unavailable", "error", err)
+	} else {
+		t.bridge = server
+		t.app.Bridge = server
+	}
+	t.app.Events = app.StartEventStream(t.ctx, t.client, send)
+	go api.Start(t.ctx, send, t.client)
+}
+
+// Run runs the TUI on a screen of its own until it exits or the process is
+// interrupted, and closes it
+func (t *TUI) Run() error {
+	defer t.Close()
+	program := tea.NewProgram(t.model, t.ProgramOptions()...)
+	t.Start(program.Send)
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(sigChan)
+	go func() {
+		select {
+		case sig := <-sigChan:
+			// Close cleans up once the program has quit
+			slog.Info("Received signal, shutting down gracefully", "signal", sig)
+			program.Quit()
+		case <-t.ctx.Done():
+		}
+	}()
+
+	result, err := program.Run()
+	if err != nil {
+		slog.Error("TUI error", "error", err)
+	}
+	slog.Info("TUI exited", "result", result)
+	return err
+}
+
+// Close stops the TUI's widgets, plugins and connections to the server
+func (t *TUI) Close() {
+	t.model.Cleanup()
+	if t.bridge != nil {
+		t.bridge.Close()
+	}
+	t.cancel()
+}